@@ -166,6 +166,8 @@ const toContainsHover = (token: MetaPredicate): string => {
             return `**Built-in predicate**. Search only inside repositories that contain **file content** matching the regular expression \`${parameters}\`.`
         case 'contains.commit.after':
             return `**Built-in predicate**. Search only inside repositories that have been committed to since \`${parameters}\`.`
+        case 'has.meta':
+            return `**Built-in predicate**. Search only inside repositories that have the metadata \`${parameters}\`, given as \`key:value\` or \`key\`.`
     }
     return ''
 }
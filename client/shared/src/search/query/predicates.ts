@@ -26,6 +26,10 @@ export const PREDICATES: Access[] = [
                     },
                 ],
             },
+            {
+                name: 'has',
+                fields: [{ name: 'meta' }],
+            },
         ],
     },
     {
@@ -164,6 +168,11 @@ export const predicateCompletion = (field: string): Completion[] => {
                 insertText: 'contains.commit.after(${1:1 month ago})',
                 asSnippet: true,
             },
+            {
+                label: 'has.meta(...)',
+                insertText: 'has.meta(${1:key}:${2:value})',
+                asSnippet: true,
+            },
         ]
     }
     return []
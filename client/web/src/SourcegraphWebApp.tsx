@@ -409,6 +409,7 @@ export class SourcegraphWebApp extends React.Component<SourcegraphWebAppProps, S
             const statusText = window.pageError.statusText
             const errorMessage = window.pageError.error
             const errorID = window.pageError.errorID
+            const trace = window.pageError.trace
 
             let subtitle: JSX.Element | undefined
             if (errorID) {
@@ -425,6 +426,43 @@ export class SourcegraphWebApp extends React.Component<SourcegraphWebAppProps, S
             } else {
                 subtitle = <div className="app__error">{subtitle}</div>
             }
+            const help = window.errorHelp
+            if (help) {
+                subtitle = (
+                    <>
+                        {subtitle}
+                        {help.troubleshooting && <p className="mt-3">{help.troubleshooting}</p>}
+                        <p className="mt-3">
+                            Need help?{' '}
+                            <a href={help.supportURL} target="_blank" rel="noopener noreferrer">
+                                Contact support
+                            </a>{' '}
+                            and include the error ID: <strong>{errorID}</strong>
+                        </p>
+                    </>
+                )
+            }
+            if (trace) {
+                subtitle = (
+                    <>
+                        {subtitle}
+                        <details className="app__error-trace mt-3 text-left">
+                            <summary>Request timing</summary>
+                            <table className="table table-sm mt-2">
+                                <tbody>
+                                    {trace.steps.map((step, index) => (
+                                        <tr key={index}>
+                                            <td>{step.name}</td>
+                                            <td>{step.duration}</td>
+                                        </tr>
+                                    ))}
+                                </tbody>
+                            </table>
+                            {trace.url && <a href={trace.url}>View trace</a>}
+                        </details>
+                    </>
+                )
+            }
             return <HeroPage icon={ServerIcon} title={`${statusCode}: ${statusText}`} subtitle={subtitle} />
         }
 
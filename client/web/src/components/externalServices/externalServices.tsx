@@ -11,8 +11,10 @@ import React from 'react'
 import { PhabricatorIcon } from '@sourcegraph/shared/src/components/icons'
 
 import awsCodeCommitSchemaJSON from '../../../../../schema/aws_codecommit.schema.json'
+import azureDevOpsSchemaJSON from '../../../../../schema/azuredevops.schema.json'
 import bitbucketCloudSchemaJSON from '../../../../../schema/bitbucket_cloud.schema.json'
 import bitbucketServerSchemaJSON from '../../../../../schema/bitbucket_server.schema.json'
+import giteaSchemaJSON from '../../../../../schema/gitea.schema.json'
 import githubSchemaJSON from '../../../../../schema/github.schema.json'
 import gitlabSchemaJSON from '../../../../../schema/gitlab.schema.json'
 import gitoliteSchemaJSON from '../../../../../schema/gitolite.schema.json'
@@ -263,6 +265,19 @@ const githubEditorActions = (isEnterprise: boolean): EditorAction[] => [
             return { edits, selectText: '<access token>' }
         },
     },
+    {
+        id: 'setGitHubApp',
+        label: 'Use a GitHub App instead of a token',
+        run: (config: string) => {
+            const value = {
+                appID: 0,
+                installationID: 0,
+                privateKey: '<private key>',
+            }
+            const edits = setProperty(config, ['gitHubApp'], value, defaultFormattingOptions)
+            return { edits, selectText: '<private key>' }
+        },
+    },
     {
         id: 'addOrgRepo',
         label: 'Add repositories in an organization',
@@ -727,6 +742,140 @@ const BITBUCKET_CLOUD: AddExternalServiceOptions = {
         </div>
     ),
 }
+const GITEA: AddExternalServiceOptions = {
+    kind: ExternalServiceKind.GITEA,
+    title: 'Gitea',
+    icon: GitIcon,
+    jsonSchema: giteaSchemaJSON,
+    defaultDisplayName: 'Gitea',
+    defaultConfig: `{
+  "url": "https://gitea.example.com",
+  "token": "<access token>",
+  "orgs": []
+}`,
+    editorActions: [
+        {
+            id: 'setAccessToken',
+            label: 'Set access token',
+            run: (config: string) => {
+                const value = '<access token>'
+                const edits = setProperty(config, ['token'], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'addOrgRepositories',
+            label: 'Add repositories belonging to organization',
+            run: (config: string) => {
+                const value = '<organization name>'
+                const edits = setProperty(config, ['orgs', -1], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'addRepository',
+            label: 'Add a single repository',
+            run: (config: string) => {
+                const value = '<owner>/<repository>'
+                const edits = setProperty(config, ['repos', -1], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'enforcePermissions',
+            label: 'Enforce permissions',
+            run: (config: string) => {
+                const value = { identityProvider: { type: 'username' } }
+                const edits = setProperty(config, ['authorization'], value, defaultFormattingOptions)
+                return { edits, selectText: '"authorization"' }
+            },
+        },
+    ],
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    Set <Field>url</Field> to the URL of your Gitea instance.
+                </li>
+                <li>
+                    Create a Gitea access token (in <b>Settings &gt; Applications</b>) for a user with read access to
+                    the repositories to mirror, and set it to be the value of the <Field>token</Field> field in the
+                    configuration below. To enforce Gitea repository permissions, the user must be a site admin.
+                </li>
+                <li>
+                    Set the <Field>orgs</Field> field to the list of organizations whose repositories Sourcegraph
+                    should index, and/or the <Field>repos</Field> field to a list of individual repositories.
+                </li>
+            </ol>
+        </div>
+    ),
+}
+const AZURE_DEVOPS: AddExternalServiceOptions = {
+    kind: ExternalServiceKind.AZUREDEVOPS,
+    title: 'Azure DevOps Services',
+    icon: GitIcon,
+    jsonSchema: azureDevOpsSchemaJSON,
+    defaultDisplayName: 'Azure DevOps',
+    defaultConfig: `{
+  "url": "https://dev.azure.com",
+  "username": "<username>",
+  "token": "<personal access token>",
+  "orgs": []
+}`,
+    editorActions: [
+        {
+            id: 'setAccessToken',
+            label: 'Set personal access token',
+            run: (config: string) => {
+                const value = '<personal access token>'
+                const edits = setProperty(config, ['token'], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'addOrgRepositories',
+            label: 'Add repositories belonging to organization',
+            run: (config: string) => {
+                const value = '<organization name>'
+                const edits = setProperty(config, ['orgs', -1], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'addProjectRepositories',
+            label: 'Add repositories belonging to project',
+            run: (config: string) => {
+                const value = '<organization name>/<project name>'
+                const edits = setProperty(config, ['projects', -1], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'excludeRepo',
+            label: 'Exclude a repository',
+            run: (config: string) => {
+                const value = { name: '<org>/<project>/<repository>' }
+                const edits = setProperty(config, ['exclude', -1], value, defaultFormattingOptions)
+                return { edits, selectText: '<org>/<project>/<repository>' }
+            },
+        },
+    ],
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    Create an Azure DevOps personal access token (in <b>User settings &gt; Personal access tokens</b>)
+                    with the <b>Code (Read)</b> scope, and set it to be the value of the <Field>token</Field> field in
+                    the configuration below. Set <Field>username</Field> to the name of the user who owns the token.
+                </li>
+                <li>
+                    Set the <Field>orgs</Field> field to the list of organizations whose repositories Sourcegraph
+                    should index, and/or the <Field>projects</Field> field to a list of individual projects.
+                </li>
+            </ol>
+        </div>
+    ),
+}
 const BITBUCKET_SERVER: AddExternalServiceOptions = {
     kind: ExternalServiceKind.BITBUCKETSERVER,
     title: 'Bitbucket Server',
@@ -1218,6 +1367,8 @@ export const codeHostExternalServices: Record<string, AddExternalServiceOptions>
     gitlab: GITLAB_SELF_MANAGED,
     bitbucket: BITBUCKET_CLOUD,
     bitbucketserver: BITBUCKET_SERVER,
+    gitea: GITEA,
+    azuredevops: AZURE_DEVOPS,
     aws_codecommit: AWS_CODE_COMMIT,
     srcservegit: SRC_SERVE_GIT,
     gitolite: GITOLITE,
@@ -1239,11 +1390,13 @@ export const defaultExternalServices: Record<ExternalServiceKind, AddExternalSer
     [ExternalServiceKind.GITHUB]: GITHUB_DOTCOM,
     [ExternalServiceKind.BITBUCKETCLOUD]: BITBUCKET_CLOUD,
     [ExternalServiceKind.BITBUCKETSERVER]: BITBUCKET_SERVER,
+    [ExternalServiceKind.GITEA]: GITEA,
     [ExternalServiceKind.GITLAB]: GITLAB_DOTCOM,
     [ExternalServiceKind.GITOLITE]: GITOLITE,
     [ExternalServiceKind.PHABRICATOR]: PHABRICATOR_SERVICE,
     [ExternalServiceKind.OTHER]: GENERIC_GIT,
     [ExternalServiceKind.AWSCODECOMMIT]: AWS_CODE_COMMIT,
+    [ExternalServiceKind.AZUREDEVOPS]: AZURE_DEVOPS,
     [ExternalServiceKind.PERFORCE]: PERFORCE,
     [ExternalServiceKind.JVMPACKAGES]: JVM_PACKAGES,
 }
@@ -56,9 +56,26 @@ const helpTexts: Record<ExternalServiceKind, JSX.Element> = {
             with <code>write</code> permissions on the project and repository level.
         </>
     ),
+    [ExternalServiceKind.AZUREDEVOPS]: (
+        <>
+            <a href={HELP_TEXT_LINK_URL} rel="noreferrer noopener" target="_blank">
+                Create a new personal access token
+            </a>{' '}
+            with the <code>Code (read, write &amp; status)</code> scope.
+        </>
+    ),
+    [ExternalServiceKind.BITBUCKETCLOUD]: (
+        <>
+            <a href={HELP_TEXT_LINK_URL} rel="noreferrer noopener" target="_blank">
+                Create a new app password
+            </a>{' '}
+            with <code>account:read</code>, <code>repository:write</code>, and <code>pullrequest:write</code>{' '}
+            permissions.
+        </>
+    ),
 
     // These are just for type completeness and serve as placeholders for a bright future.
-    [ExternalServiceKind.BITBUCKETCLOUD]: <span>Unsupported</span>,
+    [ExternalServiceKind.GITEA]: <span>Unsupported</span>,
     [ExternalServiceKind.GITOLITE]: <span>Unsupported</span>,
     [ExternalServiceKind.JVMPACKAGES]: <span>Unsupported</span>,
     [ExternalServiceKind.PERFORCE]: <span>Unsupported</span>,
@@ -69,6 +86,9 @@ const helpTexts: Record<ExternalServiceKind, JSX.Element> = {
 
 type Step = 'add-token' | 'get-ssh-key'
 
+/** Code hosts whose credentials can only be used together with the username of their owner. */
+const requiresUsername = new Set<ExternalServiceKind>([ExternalServiceKind.BITBUCKETCLOUD])
+
 export const AddCredentialModal: React.FunctionComponent<AddCredentialModalProps> = ({
     onCancel,
     afterCreate,
@@ -82,6 +102,7 @@ export const AddCredentialModal: React.FunctionComponent<AddCredentialModalProps
     const labelId = 'addCredential'
     const [isLoading, setIsLoading] = useState<boolean | Error>(false)
     const [credential, setCredential] = useState<string>('')
+    const [username, setUsername] = useState<string>('')
     const [sshPublicKey, setSSHPublicKey] = useState<string>()
     const [step, setStep] = useState<Step>(initialStep)
 
@@ -89,6 +110,12 @@ export const AddCredentialModal: React.FunctionComponent<AddCredentialModalProps
         setCredential(event.target.value)
     }, [])
 
+    const onChangeUsername = useCallback<React.ChangeEventHandler<HTMLInputElement>>(event => {
+        setUsername(event.target.value)
+    }, [])
+
+    const needsUsername = requiresUsername.has(externalServiceKind)
+
     const onSubmit = useCallback<React.FormEventHandler>(
         async event => {
             event.preventDefault()
@@ -96,6 +123,7 @@ export const AddCredentialModal: React.FunctionComponent<AddCredentialModalProps
             try {
                 const createdCredential = await createBatchChangesCredential({
                     user: userID,
+                    username: needsUsername ? username : null,
                     credential,
                     externalServiceKind,
                     externalServiceURL,
@@ -113,6 +141,8 @@ export const AddCredentialModal: React.FunctionComponent<AddCredentialModalProps
         [
             afterCreate,
             userID,
+            needsUsername,
+            username,
             credential,
             externalServiceKind,
             externalServiceURL,
@@ -164,8 +194,25 @@ export const AddCredentialModal: React.FunctionComponent<AddCredentialModalProps
                     <>
                         {isErrorLike(isLoading) && <ErrorAlert error={isLoading} />}
                         <Form onSubmit={onSubmit}>
+                            {needsUsername && (
+                                <div className="form-group">
+                                    <label htmlFor="username">Username</label>
+                                    <input
+                                        id="username"
+                                        name="username"
+                                        type="text"
+                                        autoComplete="off"
+                                        className="form-control test-add-credential-modal-username-input"
+                                        required={true}
+                                        spellCheck="false"
+                                        minLength={1}
+                                        value={username}
+                                        onChange={onChangeUsername}
+                                    />
+                                </div>
+                            )}
                             <div className="form-group">
-                                <label htmlFor="token">Personal access token</label>
+                                <label htmlFor="token">{needsUsername ? 'App password' : 'Personal access token'}</label>
                                 <input
                                     id="token"
                                     name="token"
@@ -191,7 +238,11 @@ export const AddCredentialModal: React.FunctionComponent<AddCredentialModalProps
                                 </button>
                                 <button
                                     type="submit"
-                                    disabled={isLoading === true || credential.length === 0}
+                                    disabled={
+                                        isLoading === true ||
+                                        credential.length === 0 ||
+                                        (needsUsername && username.length === 0)
+                                    }
                                     className="btn btn-primary test-add-credential-modal-submit"
                                 >
                                     {isLoading === true && <LoadingSpinner className="icon-inline" />}
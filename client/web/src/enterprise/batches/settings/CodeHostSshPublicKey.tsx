@@ -11,8 +11,12 @@ const configInstructionLinks: Record<ExternalServiceKind, string> = {
     [ExternalServiceKind.GITLAB]: 'https://docs.gitlab.com/ee/ssh/#add-an-ssh-key-to-your-gitlab-account',
     [ExternalServiceKind.BITBUCKETSERVER]:
         'https://confluence.atlassian.com/bitbucketserver/ssh-user-keys-for-personal-use-776639793.html',
+    [ExternalServiceKind.AZUREDEVOPS]:
+        'https://docs.microsoft.com/en-us/azure/devops/repos/git/use-ssh-keys-to-authenticate#step-2-add-the-public-key-to-azure-devops-servicestfs',
     [ExternalServiceKind.AWSCODECOMMIT]: 'unsupported',
-    [ExternalServiceKind.BITBUCKETCLOUD]: 'unsupported',
+    [ExternalServiceKind.BITBUCKETCLOUD]:
+        'https://support.atlassian.com/bitbucket-cloud/docs/set-up-an-ssh-key/#Step-4.-Add-the-public-key-to-your-Account-settings',
+    [ExternalServiceKind.GITEA]: 'unsupported',
     [ExternalServiceKind.GITOLITE]: 'unsupported',
     [ExternalServiceKind.JVMPACKAGES]: 'unsupported',
     [ExternalServiceKind.OTHER]: 'unsupported',
@@ -50,6 +50,7 @@ const localMockRequest: MockedResponse<CodeIntelligenceConfigurationPoliciesResu
                     retentionEnabled: true,
                     retentionDurationHours: 8064,
                     retainIntermediateCommits: true,
+                    retainMostRecentUploads: null,
                     indexingEnabled: true,
                     indexCommitMaxAgeHours: 40320,
                     indexIntermediateCommits: true,
@@ -64,6 +65,7 @@ const localMockRequest: MockedResponse<CodeIntelligenceConfigurationPoliciesResu
                     retentionEnabled: true,
                     retentionDurationHours: 8064,
                     retainIntermediateCommits: true,
+                    retainMostRecentUploads: null,
                     indexingEnabled: true,
                     indexCommitMaxAgeHours: 40320,
                     indexIntermediateCommits: true,
@@ -90,6 +92,7 @@ const globalMockRequest: MockedResponse<CodeIntelligenceConfigurationPoliciesRes
                     retentionEnabled: true,
                     retentionDurationHours: 168,
                     retainIntermediateCommits: false,
+                    retainMostRecentUploads: null,
                     indexingEnabled: false,
                     indexCommitMaxAgeHours: 672,
                     indexIntermediateCommits: false,
@@ -104,6 +107,7 @@ const globalMockRequest: MockedResponse<CodeIntelligenceConfigurationPoliciesRes
                     retentionEnabled: true,
                     retentionDurationHours: 2016,
                     retainIntermediateCommits: false,
+                    retainMostRecentUploads: null,
                     indexingEnabled: false,
                     indexCommitMaxAgeHours: 4032,
                     indexIntermediateCommits: false,
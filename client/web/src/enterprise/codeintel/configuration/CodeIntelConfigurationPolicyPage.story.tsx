@@ -25,6 +25,7 @@ const policy: CodeIntelligenceConfigurationPolicyFields = {
     retentionEnabled: true,
     retentionDurationHours: 168,
     retainIntermediateCommits: true,
+    retainMostRecentUploads: null,
     indexingEnabled: true,
     indexCommitMaxAgeHours: 672,
     indexIntermediateCommits: true,
@@ -157,6 +157,7 @@ function validatePolicy(policy: CodeIntelligenceConfigurationPolicyFields): bool
         ![GitObjectType.GIT_COMMIT, GitObjectType.GIT_TAG, GitObjectType.GIT_TREE].includes(policy.type) ||
         // Numeric validation (optional)
         (policy.retentionDurationHours && policy.retentionDurationHours <= 0) ||
+        (policy.retainMostRecentUploads && policy.retainMostRecentUploads <= 0) ||
         (policy.indexCommitMaxAgeHours && policy.indexCommitMaxAgeHours <= 0)
 
     return !invalid
@@ -175,6 +176,7 @@ function comparePolicies(
         a.retentionEnabled === b.retentionEnabled &&
         a.retentionDurationHours === b.retentionDurationHours &&
         a.retainIntermediateCommits === b.retainIntermediateCommits &&
+        a.retainMostRecentUploads === b.retainMostRecentUploads &&
         a.indexingEnabled === b.indexingEnabled &&
         a.indexCommitMaxAgeHours === b.indexCommitMaxAgeHours &&
         a.indexIntermediateCommits === b.indexIntermediateCommits
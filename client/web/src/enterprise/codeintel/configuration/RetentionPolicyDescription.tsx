@@ -17,6 +17,13 @@ export const RetentionPolicyDescription: FunctionComponent<{ policy: CodeIntelli
                 {policy.retentionDurationHours && (
                     <> for at least {formatDurationValue(policy.retentionDurationHours)} after upload</>
                 )}
+                {policy.retainMostRecentUploads && (
+                    <>
+                        {' '}
+                        and the {policy.retainMostRecentUploads} most recent uploads for each root and indexer
+                        regardless of their age
+                    </>
+                )}
                 .
             </span>
         </>
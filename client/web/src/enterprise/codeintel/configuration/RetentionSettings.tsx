@@ -39,6 +39,29 @@ export const RetentionSettings: FunctionComponent<RetentionSettingsProps> = ({ p
             />
         </div>
 
+        <div className="form-group">
+            <label htmlFor="retain-most-recent-uploads">Most recent uploads</label>
+
+            <input
+                id="retain-most-recent-uploads"
+                type="number"
+                className="form-control"
+                value={policy.retainMostRecentUploads ?? ''}
+                min="1"
+                placeholder="None"
+                disabled={!policy.retentionEnabled}
+                onChange={event =>
+                    setPolicy({
+                        ...policy,
+                        retainMostRecentUploads: event.target.value ? parseInt(event.target.value, 10) : null,
+                    })
+                }
+            />
+            <small className="form-text text-muted">
+                Retain this many of the most recent uploads for each root and indexer regardless of their age.
+            </small>
+        </div>
+
         {policy.type === GitObjectType.GIT_TREE && (
             <div className="form-group">
                 <Toggle
@@ -28,6 +28,7 @@ const defaultCodeIntelligenceConfigurationPolicyFieldsFragment = gql`
         retentionEnabled
         retentionDurationHours
         retainIntermediateCommits
+        retainMostRecentUploads
         indexingEnabled
         indexCommitMaxAgeHours
         indexIntermediateCommits
@@ -158,6 +159,7 @@ const emptyPolicy: CodeIntelligenceConfigurationPolicyFields = {
     retentionEnabled: false,
     retentionDurationHours: null,
     retainIntermediateCommits: false,
+    retainMostRecentUploads: null,
     indexingEnabled: false,
     indexCommitMaxAgeHours: null,
     indexIntermediateCommits: false,
@@ -282,6 +284,7 @@ const CREATE_POLICY_CONFIGURATION = gql`
         $retentionEnabled: Boolean!
         $retentionDurationHours: Int
         $retainIntermediateCommits: Boolean!
+        $retainMostRecentUploads: Int
         $indexingEnabled: Boolean!
         $indexCommitMaxAgeHours: Int
         $indexIntermediateCommits: Boolean!
@@ -294,6 +297,7 @@ const CREATE_POLICY_CONFIGURATION = gql`
             retentionEnabled: $retentionEnabled
             retentionDurationHours: $retentionDurationHours
             retainIntermediateCommits: $retainIntermediateCommits
+            retainMostRecentUploads: $retainMostRecentUploads
             indexingEnabled: $indexingEnabled
             indexCommitMaxAgeHours: $indexCommitMaxAgeHours
             indexIntermediateCommits: $indexIntermediateCommits
@@ -312,6 +316,7 @@ const UPDATE_POLICY_CONFIGURATION = gql`
         $retentionEnabled: Boolean!
         $retentionDurationHours: Int
         $retainIntermediateCommits: Boolean!
+        $retainMostRecentUploads: Int
         $indexingEnabled: Boolean!
         $indexCommitMaxAgeHours: Int
         $indexIntermediateCommits: Boolean!
@@ -324,6 +329,7 @@ const UPDATE_POLICY_CONFIGURATION = gql`
             retentionEnabled: $retentionEnabled
             retentionDurationHours: $retentionDurationHours
             retainIntermediateCommits: $retainIntermediateCommits
+            retainMostRecentUploads: $retainMostRecentUploads
             indexingEnabled: $indexingEnabled
             indexCommitMaxAgeHours: $indexCommitMaxAgeHours
             indexIntermediateCommits: $indexIntermediateCommits
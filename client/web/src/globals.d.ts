@@ -3,10 +3,24 @@ interface PageError {
     statusText: string
     error: string
     errorID: string
+    /** The breakdown of the time spent serving the page. Only set for site admins. */
+    trace?: PageErrorTrace
+}
+
+interface PageErrorTrace {
+    url?: string
+    steps: { name: string; duration: string }[]
+}
+
+/** The help shown on error pages, as configured in the "ui.errorPages" site configuration. */
+interface ErrorHelp {
+    supportURL: string
+    troubleshooting?: string
 }
 
 interface Window {
     pageError?: PageError
+    errorHelp?: ErrorHelp
     context: import('./jscontext').SourcegraphContext
 }
 
@@ -58,6 +58,12 @@ export interface SourcegraphContext extends Pick<Required<SiteConfiguration>, 'e
      */
     emailEnabled: boolean
 
+    /**
+     * The message shown to users while the site is in maintenance mode and read-only. Unset if the
+     * site is not in maintenance mode.
+     */
+    maintenanceModeMessage?: string
+
     /**
      * A subset of the site configuration. Not all fields are set.
      */
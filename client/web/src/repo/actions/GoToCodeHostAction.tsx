@@ -270,6 +270,8 @@ export function serviceKindDisplayNameAndIcon(
             return { displayName: 'Phabricator', icon: PhabricatorIcon }
         case ExternalServiceKind.AWSCODECOMMIT:
             return { displayName: 'AWS CodeCommit' }
+        case ExternalServiceKind.AZUREDEVOPS:
+            return { displayName: 'Azure DevOps' }
         default:
             return { displayName: upperFirst(toLower(serviceKind)) }
     }
@@ -4,6 +4,10 @@ import { SectionID } from '../../search/results/sidebar/SearchSidebar'
 
 /**
  * Schema for temporary settings.
+ *
+ * Keep in sync with internal/temporarysettings/temporary_settings.schema.json, which the
+ * backend validates temporary settings against. Keys that are removed from it are deleted
+ * from the stored settings.
  */
 export interface TemporarySettingsSchema {
     'search.collapsedSidebarSections': { [key in SectionID]?: boolean }
@@ -34,6 +34,11 @@ export const accessTokenFragment = gql`
         creator {
             username
         }
+        rateLimit {
+            limit
+            remaining
+            resetAfterSeconds
+        }
     }
 `
 
@@ -130,6 +135,15 @@ export const AccessTokenNode: React.FunctionComponent<AccessTokenNodeProps> = ({
                                 by <Link to={userURL(node.creator.username)}>{node.creator.username}</Link>
                             </>
                         )}
+                        {node.rateLimit && (
+                            <>
+                                <br />
+                                API rate limit: {node.rateLimit.remaining} of {node.rateLimit.limit} remaining
+                                {node.rateLimit.remaining < node.rateLimit.limit && (
+                                    <>, fully available again in {node.rateLimit.resetAfterSeconds}s</>
+                                )}
+                            </>
+                        )}
                     </small>
                 </div>
                 <div>
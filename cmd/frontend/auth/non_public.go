@@ -69,7 +69,16 @@ var (
 		router.SignOut:            {},
 		router.ResetPasswordInit:  {},
 		router.ResetPasswordCode:  {},
+		router.SignInLinkInit:     {},
+		router.SignInLink:         {},
 		router.CheckUsernameTaken: {},
+
+		// Security keys are the second factor of users who are not yet signed in, who can be required
+		// to register one before signing in.
+		router.SecurityKeySignInInit:   {},
+		router.SecurityKeySignIn:       {},
+		router.SecurityKeyRegisterInit: {},
+		router.SecurityKeyRegister:     {},
 	}
 	anonymousAccessibleUIRoutes = map[string]struct{}{
 		uirouter.RouteSignIn:             {},
@@ -121,9 +130,12 @@ func AllowAnonymousRequest(req *http.Request) bool {
 
 	// Authentication is performed in the webhook handler itself.
 	for _, prefix := range []string{
+		"/.api/webhooks",
 		"/.api/github-webhooks",
 		"/.api/gitlab-webhooks",
 		"/.api/bitbucket-server-webhooks",
+		"/.api/bitbucket-cloud-webhooks",
+		"/.api/azure-devops-webhooks",
 	} {
 		if strings.HasPrefix(req.URL.Path, prefix) {
 			return true
@@ -8,6 +8,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 )
 
 var ErrNoAccessExternalService = errors.New("the authenticated user does not have access to this external service")
@@ -15,13 +16,24 @@ var ErrNoAccessExternalService = errors.New("the authenticated user does not hav
 // CheckExternalServiceAccess checks whether the current user is allowed to
 // access the supplied external service.
 func CheckExternalServiceAccess(ctx context.Context, db dbutil.DB, namespaceUserID, namespaceOrgID int32) error {
+	return checkExternalServiceAccess(ctx, db, namespaceUserID, namespaceOrgID, types.OrgRoleMember)
+}
+
+// CheckExternalServiceWriteAccess checks whether the current user is allowed to
+// modify the supplied external service. Only the admins of an organization can
+// modify the external services owned by the organization.
+func CheckExternalServiceWriteAccess(ctx context.Context, db dbutil.DB, namespaceUserID, namespaceOrgID int32) error {
+	return checkExternalServiceAccess(ctx, db, namespaceUserID, namespaceOrgID, types.OrgRoleAdmin)
+}
+
+func checkExternalServiceAccess(ctx context.Context, db dbutil.DB, namespaceUserID, namespaceOrgID int32, requiredOrgRole types.OrgRole) error {
 	// Fast path that doesn't need to hit DB as we can get id from context
 	a := actor.FromContext(ctx)
 	if namespaceUserID > 0 && a.IsAuthenticated() && namespaceUserID == a.UID {
 		return nil
 	}
 
-	if namespaceOrgID > 0 && CheckOrgAccess(ctx, db, namespaceOrgID) == nil {
+	if namespaceOrgID > 0 && checkOrgAccess(ctx, db, namespaceOrgID, requiredOrgRole, false) == nil {
 		return nil
 	}
 
@@ -9,6 +9,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 )
 
 var ErrNotAuthenticated = errors.New("not authenticated")
@@ -19,7 +20,7 @@ var ErrNotAuthenticated = errors.New("not authenticated")
 // It is used when an action on a user can be performed by site admins and the
 // organization's members, but nobody else.
 func CheckOrgAccessOrSiteAdmin(ctx context.Context, db dbutil.DB, orgID int32) error {
-	return checkOrgAccess(ctx, db, orgID, true)
+	return checkOrgAccess(ctx, db, orgID, types.OrgRoleMember, true)
 }
 
 // CheckOrgAccess returns an error if the user is not a member of the
@@ -28,12 +29,21 @@ func CheckOrgAccessOrSiteAdmin(ctx context.Context, db dbutil.DB, orgID int32) e
 // It is used when an action on a user can be performed by the organization's
 // members, but nobody else.
 func CheckOrgAccess(ctx context.Context, db dbutil.DB, orgID int32) error {
-	return checkOrgAccess(ctx, db, orgID, false)
+	return checkOrgAccess(ctx, db, orgID, types.OrgRoleMember, false)
+}
+
+// CheckOrgAdminOrSiteAdmin returns an error if the user is NEITHER (1) a site
+// admin NOR (2) an admin of the organization with the specified ID.
+//
+// It is used when an action manages the organization, its settings or its code
+// host connections.
+func CheckOrgAdminOrSiteAdmin(ctx context.Context, db dbutil.DB, orgID int32) error {
+	return checkOrgAccess(ctx, db, orgID, types.OrgRoleAdmin, true)
 }
 
 // checkOrgAccess is a helper method used above which allows optionally allowing
 // site admins to access all organisations.
-func checkOrgAccess(ctx context.Context, db dbutil.DB, orgID int32, allowAdmin bool) error {
+func checkOrgAccess(ctx context.Context, db dbutil.DB, orgID int32, requiredRole types.OrgRole, allowAdmin bool) error {
 	if actor.FromContext(ctx).IsInternal() {
 		return nil
 	}
@@ -47,23 +57,27 @@ func checkOrgAccess(ctx context.Context, db dbutil.DB, orgID int32, allowAdmin b
 	if currentUser.SiteAdmin && allowAdmin {
 		return nil
 	}
-	return checkUserIsOrgMember(ctx, db, currentUser.ID, orgID)
+	return CheckUserOrgAccess(ctx, db, currentUser.ID, orgID, requiredRole)
 }
 
-var ErrNotAnOrgMember = errors.New("current user is not an org member")
+var (
+	ErrNotAnOrgMember = errors.New("current user is not an org member")
+	ErrNotAnOrgAdmin  = errors.New("current user is not an org admin")
+)
 
-func checkUserIsOrgMember(ctx context.Context, db dbutil.DB, userID, orgID int32) error {
-	resp, err := database.OrgMembers(db).GetByOrgIDAndUserID(ctx, orgID, userID)
-	if err != nil {
-		if errcode.IsNotFound(err) {
-			return ErrNotAnOrgMember
-		}
-		return err
-	}
-	// Be robust in case GetByOrgIDAndUserID changes so that lack of membership returns
-	// a nil error.
-	if resp == nil {
+// CheckUserOrgAccess returns ErrNotAnOrgMember if the user is not a member of
+// the organization, and ErrNotAnOrgAdmin if the user is a member without the
+// required role.
+func CheckUserOrgAccess(ctx context.Context, db dbutil.DB, userID, orgID int32, requiredRole types.OrgRole) error {
+	err := database.OrgMembers(db).CheckOrgAccess(ctx, userID, orgID, requiredRole)
+	switch {
+	case err == nil:
+		return nil
+	case errcode.IsNotFound(err):
 		return ErrNotAnOrgMember
+	case errors.Is(err, database.ErrInsufficientOrgRole):
+		return ErrNotAnOrgAdmin
+	default:
+		return err
 	}
-	return nil
 }
@@ -72,11 +72,21 @@ func (s *repos) GetByName(ctx context.Context, name api.RepoName) (_ *types.Repo
 	ctx, done := trace(ctx, "Repos", "GetByName", name, &err)
 	defer done()
 
-	switch repo, err := s.store.GetByName(ctx, name); {
-	case err == nil:
-		return repo, nil
-	case !errcode.IsNotFound(err):
-		return nil, err
+	repo, err := s.store.GetByName(ctx, name)
+	if err == nil || !errcode.IsNotFound(err) {
+		return repo, err
+	}
+
+	// Renamed repositories are still found by their old name after the code host stops
+	// reporting the rename.
+	switch newName, rerr := database.RedirectsWith(s.store).Resolve(ctx, database.RedirectKindRepo, string(name)); {
+	case rerr == nil:
+		return s.store.GetByName(ctx, api.RepoName(newName))
+	case !errors.Is(rerr, database.ErrRedirectNotFound):
+		return nil, rerr
+	}
+
+	switch {
 	case envvar.SourcegraphDotComMode():
 		// Automatically add repositories on Sourcegraph.com.
 		newName, err := s.Add(ctx, name)
@@ -2,9 +2,11 @@ package graphqlbackend
 
 import (
 	"context"
+	"math"
 
 	"github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/throttled/throttled/v2"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/internal/database"
@@ -67,3 +69,23 @@ func (r *accessTokenResolver) CreatedAt() DateTime { return DateTime{Time: r.acc
 func (r *accessTokenResolver) LastUsedAt() *DateTime {
 	return DateTimeOrNil(r.accessToken.LastUsedAt)
 }
+
+func (r *accessTokenResolver) RateLimit() (*accessTokenRateLimitResolver, error) {
+	result, err := GetAccessTokenRateLimit(r.accessToken.ID)
+	if err != nil || result == nil {
+		return nil, err
+	}
+	return &accessTokenRateLimitResolver{result: *result}, nil
+}
+
+type accessTokenRateLimitResolver struct {
+	result throttled.RateLimitResult
+}
+
+func (r *accessTokenRateLimitResolver) Limit() int32 { return int32(r.result.Limit) }
+
+func (r *accessTokenRateLimitResolver) Remaining() int32 { return int32(r.result.Remaining) }
+
+func (r *accessTokenRateLimitResolver) ResetAfterSeconds() int32 {
+	return int32(math.Ceil(r.result.ResetAfter.Seconds()))
+}
@@ -20,6 +20,8 @@ type AuthzResolver interface {
 	// Helpers
 	RepositoryPermissionsInfo(ctx context.Context, repoID graphql.ID) (PermissionsInfoResolver, error)
 	UserPermissionsInfo(ctx context.Context, userID graphql.ID) (PermissionsInfoResolver, error)
+	RepositoryPermissionsSyncState(ctx context.Context, repoID graphql.ID) (PermissionsSyncStateResolver, error)
+	UserPermissionsSyncState(ctx context.Context, userID graphql.ID) (PermissionsSyncStateResolver, error)
 }
 
 type RepositoryIDArgs struct {
@@ -54,3 +56,12 @@ type PermissionsInfoResolver interface {
 	SyncedAt() *DateTime
 	UpdatedAt() DateTime
 }
+
+type PermissionsSyncStateResolver interface {
+	State() string
+	QueuedAt() *DateTime
+	StartedAt() *DateTime
+	FinishedAt() *DateTime
+	LastSyncedAt() *DateTime
+	LastError() *string
+}
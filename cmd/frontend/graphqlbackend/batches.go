@@ -154,6 +154,7 @@ type CreateBatchChangesCredentialArgs struct {
 	ExternalServiceKind string
 	ExternalServiceURL  string
 	User                *graphql.ID
+	Username            *string
 	Credential          string
 }
 
@@ -161,6 +162,10 @@ type DeleteBatchChangesCredentialArgs struct {
 	BatchChangesCredential graphql.ID
 }
 
+type CheckBatchChangesCredentialArgs struct {
+	BatchChangesCredential graphql.ID
+}
+
 type ListBatchChangesCodeHostsArgs struct {
 	First  int32
 	After  *string
@@ -246,6 +251,7 @@ type BatchChangesResolver interface {
 	DeleteBatchChange(ctx context.Context, args *DeleteBatchChangeArgs) (*EmptyResponse, error)
 	CreateBatchChangesCredential(ctx context.Context, args *CreateBatchChangesCredentialArgs) (BatchChangesCredentialResolver, error)
 	DeleteBatchChangesCredential(ctx context.Context, args *DeleteBatchChangesCredentialArgs) (*EmptyResponse, error)
+	CheckBatchChangesCredential(ctx context.Context, args *CheckBatchChangesCredentialArgs) (BatchChangesCredentialResolver, error)
 
 	CreateChangesetSpec(ctx context.Context, args *CreateChangesetSpecArgs) (ChangesetSpecResolver, error)
 	SyncChangeset(ctx context.Context, args *SyncChangesetArgs) (*EmptyResponse, error)
@@ -282,6 +288,7 @@ type BulkOperationResolver interface {
 	State() string
 	Progress() float64
 	Errors(ctx context.Context) ([]ChangesetJobErrorResolver, error)
+	Results(ctx context.Context) ([]ChangesetJobResultResolver, error)
 	Initiator(ctx context.Context) (*UserResolver, error)
 	ChangesetCount() int32
 	CreatedAt() DateTime
@@ -293,6 +300,13 @@ type ChangesetJobErrorResolver interface {
 	Error() *string
 }
 
+type ChangesetJobResultResolver interface {
+	Changeset() ChangesetResolver
+	State() string
+	Error() *string
+	FinishedAt() *DateTime
+}
+
 type BatchSpecResolver interface {
 	ID() graphql.ID
 
@@ -512,6 +526,8 @@ type BatchChangesCredentialResolver interface {
 	SSHPublicKey(ctx context.Context) (*string, error)
 	CreatedAt() DateTime
 	IsSiteCredential() bool
+	CheckedAt() *DateTime
+	CheckFailureMessage() *string
 }
 
 type ChangesetCountsArgs struct {
@@ -586,6 +602,8 @@ type BatchChangeResolver interface {
 	Changesets(ctx context.Context, args *ListChangesetsArgs) (ChangesetsConnectionResolver, error)
 	ChangesetCountsOverTime(ctx context.Context, args *ChangesetCountsArgs) ([]ChangesetCountsResolver, error)
 	ClosedAt() *DateTime
+	AutoRebase() bool
+	AutoArchiveAfterDays() *int32
 	DiffStat(ctx context.Context) (*DiffStat, error)
 	CurrentSpec(ctx context.Context) (BatchSpecResolver, error)
 	BulkOperations(ctx context.Context, args *ListBatchChangeBulkOperationArgs) (BulkOperationConnectionResolver, error)
@@ -776,11 +794,13 @@ type BatchSpecWorkspaceResolver interface {
 
 	ChangesetSpecs(ctx context.Context) (*[]ChangesetSpecResolver, error)
 	PlaceInQueue() *int32
+	Artifacts(ctx context.Context) ([]ExecutorJobArtifactResolver, error)
 }
 
 type BatchSpecWorkspaceStagesResolver interface {
 	Setup() []ExecutionLogEntryResolver
 	SrcExec() ExecutionLogEntryResolver
+	Steps() []ExecutionLogEntryResolver
 	Teardown() []ExecutionLogEntryResolver
 }
 
@@ -21,6 +21,8 @@ type CodeIntelResolver interface {
 	DeleteLSIFIndex(ctx context.Context, args *struct{ ID graphql.ID }) (*EmptyResponse, error)
 	CommitGraph(ctx context.Context, id graphql.ID) (CodeIntelligenceCommitGraphResolver, error)
 	QueueAutoIndexJobsForRepo(ctx context.Context, args *QueueAutoIndexJobsForRepoArgs) ([]LSIFIndexResolver, error)
+	AutoIndexJobsDryRun(ctx context.Context, args *AutoIndexJobsDryRunArgs) (AutoIndexJobsDryRunResolver, error)
+	RequestIndexing(ctx context.Context, args *RequestIndexingArgs) (LSIFIndexRequestResolver, error)
 	GitBlobLSIFData(ctx context.Context, args *GitBlobLSIFDataArgs) (GitBlobLSIFDataResolver, error)
 	CodeIntelligenceConfigurationPolicies(ctx context.Context, args *CodeIntelligenceConfigurationPoliciesArgs) ([]CodeIntelligenceConfigurationPolicyResolver, error)
 	CreateCodeIntelligenceConfigurationPolicy(ctx context.Context, args *CreateCodeIntelligenceConfigurationPolicyArgs) (CodeIntelligenceConfigurationPolicyResolver, error)
@@ -130,6 +132,50 @@ type QueueAutoIndexJobsForRepoArgs struct {
 	Configuration *string
 }
 
+type AutoIndexJobsDryRunArgs struct {
+	Repository    graphql.ID
+	Rev           *string
+	Configuration *string
+}
+
+type AutoIndexJobsDryRunResolver interface {
+	Commit() string
+	AlreadyQueued() bool
+	ConfigurationSource() string
+	Jobs() []AutoIndexJobDescriptionResolver
+}
+
+type AutoIndexJobDescriptionResolver interface {
+	Root() string
+	Indexer() string
+	IndexerArgs() []string
+	Outfile() *string
+	DockerSteps() []AutoIndexJobDockerStepResolver
+	LocalSteps() []string
+}
+
+type AutoIndexJobDockerStepResolver interface {
+	Root() string
+	Image() string
+	Commands() []string
+}
+
+type RequestIndexingArgs struct {
+	Repository    graphql.ID
+	Rev           *string
+	Configuration *string
+}
+
+type LSIFIndexRequestResolver interface {
+	ID() graphql.ID
+	InputRev() string
+	InputCommit() string
+	RequestedAt() DateTime
+	State(ctx context.Context) (string, error)
+	Failure(ctx context.Context) (*string, error)
+	Indexes(ctx context.Context) ([]LSIFIndexResolver, error)
+}
+
 type GitTreeLSIFDataResolver interface {
 	Diagnostics(ctx context.Context, args *LSIFDiagnosticsArgs) (DiagnosticConnectionResolver, error)
 	DocumentationPage(ctx context.Context, args *LSIFDocumentationPageArgs) (DocumentationPageResolver, error)
@@ -241,6 +287,7 @@ type CodeIntelConfigurationPolicy struct {
 	RetentionEnabled          bool
 	RetentionDurationHours    *int32
 	RetainIntermediateCommits bool
+	RetainMostRecentUploads   *int32
 	IndexingEnabled           bool
 	IndexCommitMaxAgeHours    *int32
 	IndexIntermediateCommits  bool
@@ -293,6 +340,7 @@ type CodeIntelligenceConfigurationPolicyResolver interface {
 	RetentionEnabled() bool
 	RetentionDurationHours() *int32
 	RetainIntermediateCommits() bool
+	RetainMostRecentUploads() *int32
 	IndexingEnabled() bool
 	IndexCommitMaxAgeHours() *int32
 	IndexIntermediateCommits() bool
@@ -0,0 +1,61 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/codeowners"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func (r *RepositoryResolver) FileOwners(ctx context.Context, args *struct{ Path string }) ([]string, error) {
+	owners, err := codeowners.NewService(r.db).FileOwners(ctx, types.RepoName{ID: r.IDInt32(), Name: r.RepoName()}, args.Path)
+	if err != nil {
+		return nil, err
+	}
+	if owners == nil {
+		owners = []string{}
+	}
+	return owners, nil
+}
+
+func (r *GitTreeEntryResolver) Owners(ctx context.Context) ([]string, error) {
+	return r.Repository().FileOwners(ctx, &struct{ Path string }{Path: r.Path()})
+}
+
+func (r *schemaResolver) UploadCodeownersManifest(ctx context.Context, args *struct {
+	Repository graphql.ID
+	Contents   string
+}) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins may upload ownership manifests.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+	repo, err := r.repositoryByID(ctx, args.Repository)
+	if err != nil {
+		return nil, err
+	}
+	if err := codeowners.NewService(r.db).UploadManifest(ctx, repo.IDInt32(), args.Contents); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+func (r *schemaResolver) DeleteCodeownersManifest(ctx context.Context, args *struct {
+	Repository graphql.ID
+}) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins may delete ownership manifests.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+	repo, err := r.repositoryByID(ctx, args.Repository)
+	if err != nil {
+		return nil, err
+	}
+	if err := codeowners.NewService(r.db).DeleteManifest(ctx, repo.IDInt32()); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
@@ -23,7 +23,7 @@ type ComputeResolver interface {
 // A dummy type to express the union of compute results. This how its done by the GQL library we use.
 // https://github.com/graph-gophers/graphql-go/blob/af5bb93e114f0cd4cc095dd8eae0b67070ae8f20/example/starwars/starwars.go#L485-L487
 //
-// union ComputeResult = ComputeMatchContext | ComputeText
+// union ComputeResult = ComputeMatchContext | ComputeText | ComputeTimeSeries
 type computeResultResolver struct {
 	result interface{}
 }
@@ -120,6 +120,33 @@ func (r *computeTextResolver) Path() *string                   { return nil }
 func (r *computeTextResolver) Kind() *string                   { return nil }
 func (r *computeTextResolver) Value() string                   { return r.t.Value }
 
+// ComputeTimeSeries GQL result resolver definitions.
+
+type computeTimeSeriesResolver struct {
+	series *compute.TimeSeries
+}
+
+func (r *computeTimeSeriesResolver) Points() []*computeTimeSeriesPointResolver {
+	points := make([]*computeTimeSeriesPointResolver, 0, len(r.series.Points))
+	for _, point := range r.series.Points {
+		points = append(points, &computeTimeSeriesPointResolver{point: point})
+	}
+	return points
+}
+
+func (r *computeTimeSeriesResolver) SampledRepositories() int32 {
+	return int32(r.series.SampledRepositories)
+}
+
+func (r *computeTimeSeriesResolver) Complete() bool { return r.series.Complete }
+
+type computeTimeSeriesPointResolver struct {
+	point compute.TimeSeriesPoint
+}
+
+func (r *computeTimeSeriesPointResolver) DateTime() DateTime { return DateTime{Time: r.point.Time} }
+func (r *computeTimeSeriesPointResolver) Value() int32       { return int32(r.point.Value) }
+
 // Definitions required by https://github.com/graph-gophers/graphql-go to resolve
 // a union type in GraphQL.
 
@@ -133,6 +160,11 @@ func (r *computeResultResolver) ToComputeText() (*computeTextResolver, bool) {
 	return res, ok
 }
 
+func (r *computeResultResolver) ToComputeTimeSeries() (*computeTimeSeriesResolver, bool) {
+	res, ok := r.result.(*computeTimeSeriesResolver)
+	return res, ok
+}
+
 func toComputeMatchContextResolver(fm *result.FileMatch, mc *compute.MatchContext, repository *RepositoryResolver) *computeMatchContextResolver {
 	var computeMatches []*computeMatchResolver
 	for _, m := range mc.Matches {
@@ -189,6 +221,11 @@ func toResultResolverList(pattern *regexp.Regexp, matches []result.Match, db dbu
 	return computeResult
 }
 
+// ComputeOverCommits counts the matches of a compute query over the commits of the
+// repositories it matches. It reuses the commit walk of code insights, so it is set
+// by the enterprise code insights initialization and is nil otherwise.
+var ComputeOverCommits func(ctx context.Context, db dbutil.DB, query *compute.Query) (*compute.TimeSeries, error)
+
 // NewComputeImplementer is a function that abstracts away the need to have a
 // handle on (*schemaResolver) Compute.
 func NewComputeImplementer(ctx context.Context, db dbutil.DB, args *ComputeArgs) ([]*computeResultResolver, error) {
@@ -196,6 +233,16 @@ func NewComputeImplementer(ctx context.Context, db dbutil.DB, args *ComputeArgs)
 	if err != nil {
 		return nil, err
 	}
+	if _, ok := query.Command.(*compute.CountOverCommits); ok {
+		if ComputeOverCommits == nil {
+			return nil, errors.New("counting matches over commits requires code insights")
+		}
+		series, err := ComputeOverCommits(ctx, db, query)
+		if err != nil {
+			return nil, err
+		}
+		return []*computeResultResolver{{result: &computeTimeSeriesResolver{series: series}}}, nil
+	}
 	patternType := "regexp"
 	job, err := NewSearchImplementer(ctx, db, &SearchArgs{Query: args.Query, PatternType: &patternType})
 	if err != nil {
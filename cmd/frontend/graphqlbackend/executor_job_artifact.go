@@ -0,0 +1,41 @@
+package graphqlbackend
+
+import (
+	"github.com/sourcegraph/sourcegraph/internal/database"
+)
+
+type ExecutorJobArtifactResolver interface {
+	Name() string
+	Size() int32
+	CreatedAt() DateTime
+	ExpiresAt() DateTime
+	DownloadURL() *string
+}
+
+// NewExecutorJobArtifactResolver returns a resolver for the given artifact. The download URL
+// is signed by the caller, as the route serving it is only available in enterprise.
+func NewExecutorJobArtifactResolver(artifact database.ExecutorJobArtifact, downloadURL *string) *executorJobArtifactResolver {
+	return &executorJobArtifactResolver{
+		artifact:    artifact,
+		downloadURL: downloadURL,
+	}
+}
+
+type executorJobArtifactResolver struct {
+	artifact    database.ExecutorJobArtifact
+	downloadURL *string
+}
+
+var _ ExecutorJobArtifactResolver = &executorJobArtifactResolver{}
+
+func (r *executorJobArtifactResolver) Name() string         { return r.artifact.Name }
+func (r *executorJobArtifactResolver) Size() int32          { return int32(r.artifact.Size) }
+func (r *executorJobArtifactResolver) DownloadURL() *string { return r.downloadURL }
+
+func (r *executorJobArtifactResolver) CreatedAt() DateTime {
+	return DateTime{Time: r.artifact.CreatedAt}
+}
+
+func (r *executorJobArtifactResolver) ExpiresAt() DateTime {
+	return DateTime{Time: r.artifact.ExpiresAt}
+}
@@ -0,0 +1,106 @@
+package graphqlbackend
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+)
+
+// ExecutorQueueMetrics are the aggregated metrics of an executor queue.
+type ExecutorQueueMetrics struct {
+	QueueName string
+	// Depth is the number of jobs waiting to be dequeued.
+	Depth int
+	// DequeueLatencyP50, DequeueLatencyP90 and DequeueLatencyP99 are percentiles of the
+	// time recently dequeued jobs spent in the queue.
+	DequeueLatencyP50 time.Duration
+	DequeueLatencyP90 time.Duration
+	DequeueLatencyP99 time.Duration
+	// Images count the recently finished jobs per image they ran.
+	Images []ExecutorImageMetrics
+}
+
+// ExecutorImageMetrics counts the finished jobs of a queue that ran an image.
+type ExecutorImageMetrics struct {
+	Image     string
+	Completed int
+	Failed    int
+}
+
+// GetExecutorQueueMetrics is called to obtain the metrics of the executor queues for the
+// GraphQL resolver of the executorQueueMetrics query.
+//
+// It is overridden in non-OSS builds, which register the executor queues.
+var GetExecutorQueueMetrics = func(ctx context.Context) ([]ExecutorQueueMetrics, error) {
+	return nil, nil // OSS builds have no executor queues
+}
+
+func (r *schemaResolver) ExecutorQueueMetrics(ctx context.Context) ([]*executorQueueMetricsResolver, error) {
+	// 🚨 SECURITY: Only site admins may view the metrics of executor queues.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	metrics, err := GetExecutorQueueMetrics(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*executorQueueMetricsResolver, 0, len(metrics))
+	for _, m := range metrics {
+		resolvers = append(resolvers, &executorQueueMetricsResolver{metrics: m})
+	}
+	return resolvers, nil
+}
+
+type executorQueueMetricsResolver struct {
+	metrics ExecutorQueueMetrics
+}
+
+func (r *executorQueueMetricsResolver) QueueName() string { return r.metrics.QueueName }
+func (r *executorQueueMetricsResolver) Depth() int32      { return int32(r.metrics.Depth) }
+
+func (r *executorQueueMetricsResolver) DequeueLatency() *executorQueueDequeueLatencyResolver {
+	return &executorQueueDequeueLatencyResolver{metrics: r.metrics}
+}
+
+func (r *executorQueueMetricsResolver) Images() []*executorImageMetricsResolver {
+	resolvers := make([]*executorImageMetricsResolver, 0, len(r.metrics.Images))
+	for _, m := range r.metrics.Images {
+		resolvers = append(resolvers, &executorImageMetricsResolver{metrics: m})
+	}
+	return resolvers
+}
+
+type executorQueueDequeueLatencyResolver struct {
+	metrics ExecutorQueueMetrics
+}
+
+func (r *executorQueueDequeueLatencyResolver) P50() float64 {
+	return r.metrics.DequeueLatencyP50.Seconds()
+}
+
+func (r *executorQueueDequeueLatencyResolver) P90() float64 {
+	return r.metrics.DequeueLatencyP90.Seconds()
+}
+
+func (r *executorQueueDequeueLatencyResolver) P99() float64 {
+	return r.metrics.DequeueLatencyP99.Seconds()
+}
+
+type executorImageMetricsResolver struct {
+	metrics ExecutorImageMetrics
+}
+
+func (r *executorImageMetricsResolver) Image() string    { return r.metrics.Image }
+func (r *executorImageMetricsResolver) Completed() int32 { return int32(r.metrics.Completed) }
+func (r *executorImageMetricsResolver) Failed() int32    { return int32(r.metrics.Failed) }
+
+func (r *executorImageMetricsResolver) FailureRate() float64 {
+	total := r.metrics.Completed + r.metrics.Failed
+	if total == 0 {
+		return 0
+	}
+	return float64(r.metrics.Failed) / float64(total)
+}
@@ -0,0 +1,101 @@
+package graphqlbackend
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+type executorConnectionResolver struct {
+	db  dbutil.DB
+	opt database.ExecutorListOptions
+
+	once      sync.Once
+	executors []database.Executor
+	err       error
+}
+
+func (r *schemaResolver) Executors(ctx context.Context, args *struct {
+	First  int32
+	Active bool
+	Queue  *string
+}) (*executorConnectionResolver, error) {
+	// 🚨 SECURITY: Only site admins may view executors.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	opt := database.ExecutorListOptions{
+		ActiveOnly:  args.Active,
+		LimitOffset: &database.LimitOffset{Limit: int(args.First)},
+	}
+	if args.Queue != nil {
+		opt.QueueName = *args.Queue
+	}
+	return &executorConnectionResolver{db: r.db, opt: opt}, nil
+}
+
+func (r *executorConnectionResolver) compute(ctx context.Context) ([]database.Executor, error) {
+	r.once.Do(func() {
+		r.executors, r.err = database.Executors(r.db).List(ctx, r.opt)
+	})
+	return r.executors, r.err
+}
+
+func (r *executorConnectionResolver) Nodes(ctx context.Context) ([]*executorResolver, error) {
+	executors, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	resolvers := make([]*executorResolver, 0, len(executors))
+	for _, executor := range executors {
+		resolvers = append(resolvers, &executorResolver{executor: executor, now: now})
+	}
+	return resolvers, nil
+}
+
+func (r *executorConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	opt := r.opt
+	opt.LimitOffset = nil
+	count, err := database.Executors(r.db).Count(ctx, opt)
+	return int32(count), err
+}
+
+type executorResolver struct {
+	executor database.Executor
+	now      time.Time
+}
+
+func (r *executorResolver) Name() string            { return r.executor.Name }
+func (r *executorResolver) Hostname() string        { return r.executor.Hostname }
+func (r *executorResolver) QueueName() string       { return r.executor.QueueName }
+func (r *executorResolver) OS() string              { return r.executor.OS }
+func (r *executorResolver) Architecture() string    { return r.executor.Architecture }
+func (r *executorResolver) ExecutorVersion() string { return r.executor.ExecutorVersion }
+func (r *executorResolver) NumCPUs() int32          { return int32(r.executor.NumCPUs) }
+func (r *executorResolver) LoadAverage() float64    { return r.executor.LoadAverage }
+func (r *executorResolver) FirstSeenAt() DateTime   { return DateTime{r.executor.FirstSeenAt} }
+func (r *executorResolver) LastSeenAt() DateTime    { return DateTime{r.executor.LastSeenAt} }
+func (r *executorResolver) Active() bool            { return r.executor.Active(r.now) }
+
+func (r *executorResolver) JobIDs() []int32 {
+	ids := make([]int32, 0, len(r.executor.JobIDs))
+	for _, id := range r.executor.JobIDs {
+		ids = append(ids, int32(id))
+	}
+	return ids
+}
+
+func (r *executorResolver) MemoryTotalBytes() BigInt {
+	return BigInt{Int: r.executor.MemoryTotalBytes}
+}
+
+func (r *executorResolver) MemoryAvailableBytes() BigInt {
+	return BigInt{Int: r.executor.MemoryAvailableBytes}
+}
@@ -2,7 +2,9 @@ package graphqlbackend
 
 import (
 	"context"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/graph-gophers/graphql-go"
@@ -126,6 +128,14 @@ func (r *externalServiceResolver) WebhookURL() (*string, error) {
 			if len(c.Webhooks) > 0 {
 				r.webhookURL = u
 			}
+		case *schema.BitbucketCloudConnection:
+			if c.WebhookSecret != "" {
+				r.webhookURL = u
+			}
+		case *schema.AzureDevOpsConnection:
+			if c.WebhookSecret != "" {
+				r.webhookURL = u
+			}
 		}
 	})
 	if r.webhookURL == "" {
@@ -170,6 +180,10 @@ func (r *externalServiceResolver) NextSyncAt() *DateTime {
 	return &DateTime{Time: r.externalService.NextSyncAt}
 }
 
+func (r *externalServiceResolver) TokenShared() bool {
+	return r.externalService.TokenShared
+}
+
 var scopeCache = rcache.New("extsvc_token_scope")
 
 func (r *externalServiceResolver) GrantedScopes(ctx context.Context) (*[]string, error) {
@@ -185,3 +199,95 @@ func (r *externalServiceResolver) GrantedScopes(ctx context.Context) (*[]string,
 	}
 	return &scopes, nil
 }
+
+func (r *externalServiceResolver) SyncStatistics(ctx context.Context, args *struct{ Hours int32 }) (*externalServiceSyncStatisticsResolver, error) {
+	if args.Hours <= 0 {
+		return nil, errors.New("hours must be positive")
+	}
+	stats, err := database.ExternalServices(r.db).GetSyncStatistics(ctx, r.externalService.ID, time.Duration(args.Hours)*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	return &externalServiceSyncStatisticsResolver{stats: stats}, nil
+}
+
+func (r *externalServiceResolver) NextSync(ctx context.Context) (*externalServiceNextSyncResolver, error) {
+	infos, err := database.ExternalServices(r.db).NextSyncInfo(ctx, []int64{r.externalService.ID})
+	if err != nil {
+		return nil, err
+	}
+	if len(infos) == 0 {
+		return nil, errors.Newf("external service %d not found", r.externalService.ID)
+	}
+	return &externalServiceNextSyncResolver{info: infos[0]}, nil
+}
+
+type externalServiceNextSyncResolver struct {
+	info *types.ExternalServiceNextSyncInfo
+}
+
+func (r *externalServiceNextSyncResolver) NextSyncAt() *DateTime {
+	if r.info.NextSyncAt.IsZero() {
+		return nil
+	}
+	return &DateTime{Time: r.info.NextSyncAt}
+}
+
+func (r *externalServiceNextSyncResolver) SyncJobState() *string {
+	if r.info.SyncJobState == "" {
+		return nil
+	}
+	state := strings.ToUpper(r.info.SyncJobState)
+	return &state
+}
+
+func (r *externalServiceNextSyncResolver) BackoffReason() *string {
+	if r.info.BackoffReason == "" {
+		return nil
+	}
+	reason := strings.ToUpper(string(r.info.BackoffReason))
+	return &reason
+}
+
+type externalServiceSyncStatisticsResolver struct {
+	stats *types.ExternalServiceSyncStatistics
+}
+
+func (r *externalServiceSyncStatisticsResolver) Syncs() int32 {
+	return int32(r.stats.Syncs)
+}
+
+func (r *externalServiceSyncStatisticsResolver) ErroredSyncs() int32 {
+	return int32(r.stats.ErroredSyncs)
+}
+
+func (r *externalServiceSyncStatisticsResolver) AverageDurationSeconds() float64 {
+	return r.stats.AverageDuration.Seconds()
+}
+
+func (r *externalServiceSyncStatisticsResolver) MaxDurationSeconds() float64 {
+	return r.stats.MaxDuration.Seconds()
+}
+
+func (r *externalServiceSyncStatisticsResolver) ReposAdded() int32 {
+	return int32(r.stats.ReposAdded)
+}
+
+func (r *externalServiceSyncStatisticsResolver) ReposRemoved() int32 {
+	return int32(r.stats.ReposRemoved)
+}
+
+func (r *externalServiceSyncStatisticsResolver) ReposErrored() int32 {
+	return int32(r.stats.ReposErrored)
+}
+
+func (r *externalServiceSyncStatisticsResolver) APIRequests() int32 {
+	return int32(r.stats.APIRequests)
+}
+
+func (r *externalServiceSyncStatisticsResolver) LastErroredAt() *DateTime {
+	if r.stats.LastErroredAt.IsZero() {
+		return nil
+	}
+	return &DateTime{Time: r.stats.LastErroredAt}
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
 	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 )
@@ -73,7 +74,9 @@ func (r *schemaResolver) AddExternalService(ctx context.Context, args *addExtern
 			if err = backend.CheckOrgExternalServices(ctx, r.db, namespaceOrgID); err != nil {
 				return nil, err
 			}
-			if backend.CheckOrgAccess(ctx, r.db, namespaceOrgID) != nil {
+			if err := backend.CheckUserOrgAccess(ctx, r.db, actor.FromContext(ctx).UID, namespaceOrgID, types.OrgRoleAdmin); err == backend.ErrNotAnOrgAdmin {
+				return nil, errors.New("only admins of the organization can add code host connections")
+			} else if err != nil {
 				return nil, errors.New("the authenticated user does not belong to the organization requested")
 			}
 		}
@@ -132,7 +135,7 @@ func (r *schemaResolver) UpdateExternalService(ctx context.Context, args *update
 	}
 
 	// 🚨 SECURITY: check access to external service
-	if err := backend.CheckExternalServiceAccess(ctx, r.db, es.NamespaceUserID, es.NamespaceOrgID); err != nil {
+	if err := backend.CheckExternalServiceWriteAccess(ctx, r.db, es.NamespaceUserID, es.NamespaceOrgID); err != nil {
 		return nil, err
 	}
 
@@ -225,7 +228,7 @@ func (r *schemaResolver) DeleteExternalService(ctx context.Context, args *delete
 	}
 
 	// 🚨 SECURITY: check external service access
-	if err := backend.CheckExternalServiceAccess(ctx, r.db, es.NamespaceUserID, es.NamespaceOrgID); err != nil {
+	if err := backend.CheckExternalServiceWriteAccess(ctx, r.db, es.NamespaceUserID, es.NamespaceOrgID); err != nil {
 		return nil, err
 	}
 
@@ -246,10 +249,127 @@ func (r *schemaResolver) DeleteExternalService(ctx context.Context, args *delete
 	return &EmptyResponse{}, nil
 }
 
+type confirmExternalServiceRepoDeletionArgs struct {
+	ExternalService graphql.ID
+}
+
+func (r *schemaResolver) ConfirmExternalServiceRepoDeletion(ctx context.Context, args *confirmExternalServiceRepoDeletionArgs) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins may allow syncs to delete repositories above
+	// the deletion threshold.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	id, err := unmarshalExternalServiceID(args.ExternalService)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := database.ExternalServices(r.db).ConfirmRepoDeletion(ctx, id); err != nil {
+		return nil, err
+	}
+
+	es, err := database.ExternalServices(r.db).GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Kick off the confirmed sync in the background, it may take a while.
+	go func() {
+		if err := syncExternalService(context.Background(), es, syncExternalServiceTimeout, r.repoupdaterClient); err != nil {
+			log15.Warn("Performing sync after confirming repo deletion", "err", err)
+		}
+	}()
+
+	return &EmptyResponse{}, nil
+}
+
+type syncExternalServiceRepositoryArgs struct {
+	ExternalService graphql.ID
+	Repository      string
+}
+
+func (r *schemaResolver) SyncExternalServiceRepository(ctx context.Context, args *syncExternalServiceRepositoryArgs) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins may trigger syncs of single repositories.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	id, err := unmarshalExternalServiceID(args.ExternalService)
+	if err != nil {
+		return nil, err
+	}
+
+	if args.Repository == "" {
+		return nil, errors.New("repository must not be empty")
+	}
+
+	// Make sure the external service exists before enqueueing a sync for it.
+	if _, err := database.ExternalServices(r.db).GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	if err := repoupdater.DefaultClient.SyncExternalServiceRepo(ctx, id, args.Repository); err != nil {
+		return nil, err
+	}
+
+	return &EmptyResponse{}, nil
+}
+
+type setExternalServiceTokenSharedArgs struct {
+	ExternalService graphql.ID
+	Shared          bool
+}
+
+func (r *schemaResolver) SetExternalServiceTokenShared(ctx context.Context, args *setExternalServiceTokenSharedArgs) (*EmptyResponse, error) {
+	id, err := unmarshalExternalServiceID(args.ExternalService)
+	if err != nil {
+		return nil, err
+	}
+
+	es, err := database.ExternalServices(r.db).GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if es.NamespaceOrgID == 0 {
+		return nil, errors.New("only the token of an organization external service can be shared")
+	}
+
+	// 🚨 SECURITY: Sharing the token grants every member of the organization
+	// read access to the repositories of the external service, so only admins
+	// of the organization (or site admins) may change it.
+	if err := backend.CheckOrgAdminOrSiteAdmin(ctx, r.db, es.NamespaceOrgID); err != nil {
+		return nil, err
+	}
+
+	if err := database.ExternalServices(r.db).SetTokenShared(ctx, id, args.Shared); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
 type ExternalServicesArgs struct {
 	Namespace *graphql.ID
 	graphqlutil.ConnectionArgs
-	After *string
+	After      *string
+	Query      *string
+	OrderBy    string
+	Descending *bool
+}
+
+func toDBExternalServiceOrderBy(ob string) database.ExternalServiceOrderBy {
+	switch ob {
+	case "EXTERNAL_SERVICE_DISPLAY_NAME":
+		return database.ExternalServiceOrderByDisplayName
+	case "EXTERNAL_SERVICE_KIND":
+		return database.ExternalServiceOrderByKind
+	case "EXTERNAL_SERVICE_CREATED_AT":
+		return database.ExternalServiceOrderByCreatedAt
+	case "EXTERNAL_SERVICE_LAST_SYNC_AT":
+		return database.ExternalServiceOrderByLastSyncAt
+	default:
+		return database.ExternalServiceOrderByID
+	}
 }
 
 func (r *schemaResolver) ExternalServices(ctx context.Context, args *ExternalServicesArgs) (*externalServiceConnectionResolver, error) {
@@ -272,8 +392,16 @@ func (r *schemaResolver) ExternalServices(ctx context.Context, args *ExternalSer
 		}
 	}
 
+	orderBy := toDBExternalServiceOrderBy(args.OrderBy)
+
 	var afterID int64
 	if args.After != nil {
+		// The cursor is the ID of the last external service of the previous page,
+		// so it can only be used when sorting by ID.
+		if orderBy != database.ExternalServiceOrderByID {
+			return nil, errors.New("after is only supported when sorting by ID")
+		}
+
 		var err error
 		afterID, err = unmarshalExternalServiceID(graphql.ID(*args.After))
 		if err != nil {
@@ -281,10 +409,20 @@ func (r *schemaResolver) ExternalServices(ctx context.Context, args *ExternalSer
 		}
 	}
 
+	direction := "ASC"
+	if (args.Descending == nil && orderBy == database.ExternalServiceOrderByID) || (args.Descending != nil && *args.Descending) {
+		direction = "DESC"
+	}
+
 	opt := database.ExternalServicesListOptions{
-		NamespaceUserID: namespaceUserID,
-		NamespaceOrgID:  namespaceOrgID,
-		AfterID:         afterID,
+		NamespaceUserID:  namespaceUserID,
+		NamespaceOrgID:   namespaceOrgID,
+		AfterID:          afterID,
+		OrderBy:          orderBy,
+		OrderByDirection: direction,
+	}
+	if args.Query != nil {
+		opt.Query = *args.Query
 	}
 	args.ConnectionArgs.Set(&opt.LimitOffset)
 	return &externalServiceConnectionResolver{db: r.db, opt: opt}, nil
@@ -352,6 +490,10 @@ func (r *externalServiceConnectionResolver) PageInfo(ctx context.Context) (*grap
 	}
 
 	if count > len(externalServices) {
+		// Cursors are only supported when sorting by ID.
+		if r.opt.OrderBy != "" && r.opt.OrderBy != database.ExternalServiceOrderByID {
+			return graphqlutil.HasNextPage(true), nil
+		}
 		endCursorID := externalServices[len(externalServices)-1].ID
 		return graphqlutil.NextPageCursor(string(marshalExternalServiceID(endCursorID))), nil
 	}
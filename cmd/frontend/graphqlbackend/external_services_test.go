@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cockroachdb/errors"
 	"github.com/google/go-cmp/cmp"
 	gqlerrors "github.com/graph-gophers/graphql-go/errors"
 
@@ -285,6 +286,7 @@ func TestAddExternalService(t *testing.T) {
 					ID:     1,
 					OrgID:  42,
 					UserID: 10,
+					Role:   types.OrgRoleAdmin,
 				}, nil
 			}
 			database.Mocks.ExternalServices.Create = func(ctx context.Context, confGet func() *conf.Unified, externalService *types.ExternalService) error {
@@ -496,6 +498,7 @@ func TestUpdateExternalService(t *testing.T) {
 				return &types.OrgMembership{
 					OrgID:  orgID,
 					UserID: 1,
+					Role:   types.OrgRoleAdmin,
 				}, nil
 			}
 			database.Mocks.ExternalServices.GetByID = func(id int64) (*types.ExternalService, error) {
@@ -745,6 +748,7 @@ func TestDeleteExternalService(t *testing.T) {
 				return &types.OrgMembership{
 					OrgID:  orgID,
 					UserID: 1,
+					Role:   types.OrgRoleAdmin,
 				}, nil
 			}
 			database.Mocks.ExternalServices.GetByID = func(id int64) (*types.ExternalService, error) {
@@ -773,6 +777,35 @@ func TestDeleteExternalService(t *testing.T) {
 				t.Fatal("!calledDelete")
 			}
 		})
+
+		t.Run("has matching org namespace but is not an org admin", func(t *testing.T) {
+			orgID := int32(1)
+			database.Mocks.OrgMembers.GetByOrgIDAndUserID = func(ctx context.Context, orgID, userID int32) (*types.OrgMembership, error) {
+				return &types.OrgMembership{
+					OrgID:  orgID,
+					UserID: 1,
+					Role:   types.OrgRoleMember,
+				}, nil
+			}
+			database.Mocks.ExternalServices.GetByID = func(id int64) (*types.ExternalService, error) {
+				return &types.ExternalService{
+					ID:             id,
+					NamespaceOrgID: orgID,
+				}, nil
+			}
+			defer func() {
+				database.Mocks.OrgMembers = database.MockOrgMembers{}
+				database.Mocks.ExternalServices = database.MockExternalServices{}
+			}()
+
+			ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+			_, err := newSchemaResolver(db).DeleteExternalService(ctx, &deleteExternalServiceArgs{
+				ExternalService: "RXh0ZXJuYWxTZXJ2aWNlOjQ=",
+			})
+			if err != backend.ErrNoAccessExternalService {
+				t.Errorf("err: want %q but got %v", backend.ErrNoAccessExternalService, err)
+			}
+		})
 	})
 
 	database.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
@@ -815,6 +848,284 @@ func TestDeleteExternalService(t *testing.T) {
 	})
 }
 
+func TestConfirmExternalServiceRepoDeletion(t *testing.T) {
+	db := new(dbtesting.MockDB)
+
+	t.Run("authenticated as non-admin", func(t *testing.T) {
+		database.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+			return &types.User{ID: 1}, nil
+		}
+		defer func() {
+			database.Mocks.Users = database.MockUsers{}
+		}()
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		result, err := newSchemaResolver(db).ConfirmExternalServiceRepoDeletion(ctx, &confirmExternalServiceRepoDeletionArgs{
+			ExternalService: "RXh0ZXJuYWxTZXJ2aWNlOjQ=",
+		})
+		if want := backend.ErrMustBeSiteAdmin; err != want {
+			t.Errorf("err: want %q but got %v", want, err)
+		}
+		if result != nil {
+			t.Errorf("result: want nil but got %v", result)
+		}
+	})
+
+	database.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{SiteAdmin: true}, nil
+	}
+	var confirmed int64
+	database.Mocks.ExternalServices.ConfirmRepoDeletion = func(ctx context.Context, id int64) error {
+		confirmed = id
+		return nil
+	}
+	database.Mocks.ExternalServices.GetByID = func(id int64) (*types.ExternalService, error) {
+		return &types.ExternalService{ID: id}, nil
+	}
+	t.Cleanup(func() {
+		database.Mocks.Users = database.MockUsers{}
+		database.Mocks.ExternalServices = database.MockExternalServices{}
+	})
+
+	RunTests(t, []*Test{
+		{
+			Schema: mustParseGraphQLSchema(t),
+			Query: `
+			mutation {
+				confirmExternalServiceRepoDeletion(externalService: "RXh0ZXJuYWxTZXJ2aWNlOjQ=") {
+					alwaysNil
+				}
+			}
+		`,
+			ExpectedResult: `
+			{
+				"confirmExternalServiceRepoDeletion": {
+					"alwaysNil": null
+				}
+			}
+		`,
+			Context: actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+		},
+	})
+
+	if confirmed != 4 {
+		t.Errorf("want external service 4 to be confirmed, got %d", confirmed)
+	}
+}
+
+func TestSyncExternalServiceRepository(t *testing.T) {
+	db := new(dbtesting.MockDB)
+
+	t.Run("authenticated as non-admin", func(t *testing.T) {
+		database.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+			return &types.User{ID: 1}, nil
+		}
+		defer func() {
+			database.Mocks.Users = database.MockUsers{}
+		}()
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		result, err := newSchemaResolver(db).SyncExternalServiceRepository(ctx, &syncExternalServiceRepositoryArgs{
+			ExternalService: "RXh0ZXJuYWxTZXJ2aWNlOjQ=",
+			Repository:      "sourcegraph/sourcegraph",
+		})
+		if want := backend.ErrMustBeSiteAdmin; err != want {
+			t.Errorf("err: want %q but got %v", want, err)
+		}
+		if result != nil {
+			t.Errorf("result: want nil but got %v", result)
+		}
+	})
+
+	database.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{SiteAdmin: true}, nil
+	}
+	database.Mocks.ExternalServices.GetByID = func(id int64) (*types.ExternalService, error) {
+		return &types.ExternalService{ID: id}, nil
+	}
+	var (
+		syncedID   int64
+		syncedRepo string
+	)
+	repoupdater.MockSyncExternalServiceRepo = func(_ context.Context, id int64, repo string) error {
+		syncedID, syncedRepo = id, repo
+		return nil
+	}
+	t.Cleanup(func() {
+		database.Mocks.Users = database.MockUsers{}
+		database.Mocks.ExternalServices = database.MockExternalServices{}
+		repoupdater.MockSyncExternalServiceRepo = nil
+	})
+
+	RunTests(t, []*Test{
+		{
+			Schema: mustParseGraphQLSchema(t),
+			Query: `
+			mutation {
+				syncExternalServiceRepository(externalService: "RXh0ZXJuYWxTZXJ2aWNlOjQ=", repository: "sourcegraph/sourcegraph") {
+					alwaysNil
+				}
+			}
+		`,
+			ExpectedResult: `
+			{
+				"syncExternalServiceRepository": {
+					"alwaysNil": null
+				}
+			}
+		`,
+			Context: actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+		},
+	})
+
+	if syncedID != 4 || syncedRepo != "sourcegraph/sourcegraph" {
+		t.Errorf("want sync of sourcegraph/sourcegraph in external service 4, got %q in %d", syncedRepo, syncedID)
+	}
+}
+
+func TestSetExternalServiceTokenShared(t *testing.T) {
+	db := new(dbtesting.MockDB)
+
+	database.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{ID: 1}, nil
+	}
+	database.Mocks.ExternalServices.GetByID = func(id int64) (*types.ExternalService, error) {
+		return &types.ExternalService{
+			ID:             id,
+			NamespaceOrgID: 42,
+		}, nil
+	}
+	var (
+		sharedID int64
+		shared   bool
+	)
+	database.Mocks.ExternalServices.SetTokenShared = func(_ context.Context, id int64, s bool) error {
+		sharedID, shared = id, s
+		return nil
+	}
+	t.Cleanup(func() {
+		database.Mocks.Users = database.MockUsers{}
+		database.Mocks.OrgMembers = database.MockOrgMembers{}
+		database.Mocks.ExternalServices = database.MockExternalServices{}
+	})
+
+	t.Run("not a member of the organization", func(t *testing.T) {
+		database.Mocks.OrgMembers.GetByOrgIDAndUserID = func(ctx context.Context, orgID, userID int32) (*types.OrgMembership, error) {
+			return nil, nil
+		}
+		defer func() {
+			database.Mocks.OrgMembers = database.MockOrgMembers{}
+		}()
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		result, err := newSchemaResolver(db).SetExternalServiceTokenShared(ctx, &setExternalServiceTokenSharedArgs{
+			ExternalService: "RXh0ZXJuYWxTZXJ2aWNlOjQ=",
+			Shared:          true,
+		})
+		if want := backend.ErrNotAnOrgMember; err != want {
+			t.Errorf("err: want %q but got %v", want, err)
+		}
+		if result != nil {
+			t.Errorf("result: want nil but got %v", result)
+		}
+	})
+
+	t.Run("member but not an admin of the organization", func(t *testing.T) {
+		database.Mocks.OrgMembers.GetByOrgIDAndUserID = func(ctx context.Context, orgID, userID int32) (*types.OrgMembership, error) {
+			return &types.OrgMembership{
+				OrgID:  orgID,
+				UserID: userID,
+				Role:   types.OrgRoleMember,
+			}, nil
+		}
+		defer func() {
+			database.Mocks.OrgMembers = database.MockOrgMembers{}
+		}()
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		result, err := newSchemaResolver(db).SetExternalServiceTokenShared(ctx, &setExternalServiceTokenSharedArgs{
+			ExternalService: "RXh0ZXJuYWxTZXJ2aWNlOjQ=",
+			Shared:          true,
+		})
+		if want := backend.ErrNotAnOrgAdmin; err != want {
+			t.Errorf("err: want %q but got %v", want, err)
+		}
+		if result != nil {
+			t.Errorf("result: want nil but got %v", result)
+		}
+		if sharedID != 0 {
+			t.Errorf("want token not to be shared, got external service %d shared", sharedID)
+		}
+	})
+
+	t.Run("user external service", func(t *testing.T) {
+		database.Mocks.ExternalServices.GetByID = func(id int64) (*types.ExternalService, error) {
+			return &types.ExternalService{
+				ID:              id,
+				NamespaceUserID: 1,
+			}, nil
+		}
+		defer func() {
+			database.Mocks.ExternalServices.GetByID = func(id int64) (*types.ExternalService, error) {
+				return &types.ExternalService{
+					ID:             id,
+					NamespaceOrgID: 42,
+				}, nil
+			}
+		}()
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		result, err := newSchemaResolver(db).SetExternalServiceTokenShared(ctx, &setExternalServiceTokenSharedArgs{
+			ExternalService: "RXh0ZXJuYWxTZXJ2aWNlOjQ=",
+			Shared:          true,
+		})
+		if err == nil {
+			t.Error("want error for a user external service, got nil")
+		}
+		if result != nil {
+			t.Errorf("result: want nil but got %v", result)
+		}
+	})
+
+	var checkedOrgID int32
+	database.Mocks.OrgMembers.GetByOrgIDAndUserID = func(ctx context.Context, orgID, userID int32) (*types.OrgMembership, error) {
+		checkedOrgID = orgID
+		return &types.OrgMembership{
+			OrgID:  orgID,
+			UserID: userID,
+			Role:   types.OrgRoleAdmin,
+		}, nil
+	}
+
+	RunTests(t, []*Test{
+		{
+			Schema: mustParseGraphQLSchema(t),
+			Query: `
+			mutation {
+				setExternalServiceTokenShared(externalService: "RXh0ZXJuYWxTZXJ2aWNlOjQ=", shared: true) {
+					alwaysNil
+				}
+			}
+		`,
+			ExpectedResult: `
+			{
+				"setExternalServiceTokenShared": {
+					"alwaysNil": null
+				}
+			}
+		`,
+			Context: actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+		},
+	})
+
+	if checkedOrgID != 42 {
+		t.Errorf("want admin role in org 42 to be checked, got %d", checkedOrgID)
+	}
+	if sharedID != 4 || !shared {
+		t.Errorf("want token of external service 4 to be shared, got %d shared=%v", sharedID, shared)
+	}
+}
+
 func TestExternalServices(t *testing.T) {
 	db := new(dbtesting.MockDB)
 
@@ -948,6 +1259,16 @@ func TestExternalServices(t *testing.T) {
 			}, nil
 		}
 
+		if opt.Query == "github" {
+			if opt.OrderBy != database.ExternalServiceOrderByDisplayName || opt.OrderByDirection != "ASC" {
+				t.Errorf("unexpected order: %s %s", opt.OrderBy, opt.OrderByDirection)
+			}
+			return []*types.ExternalService{
+				{ID: 2},
+				{ID: 1},
+			}, nil
+		}
+
 		ess := []*types.ExternalService{
 			{ID: 1},
 			{ID: 2},
@@ -967,6 +1288,27 @@ func TestExternalServices(t *testing.T) {
 	database.Mocks.ExternalServices.GetLastSyncError = func(id int64) (string, error) {
 		return "Oops", nil
 	}
+	database.Mocks.ExternalServices.GetSyncStatistics = func(id int64, window time.Duration) (*types.ExternalServiceSyncStatistics, error) {
+		if window != 24*time.Hour {
+			t.Errorf("unexpected window: %s", window)
+		}
+		return &types.ExternalServiceSyncStatistics{
+			Syncs:           int(id) + 1,
+			ErroredSyncs:    1,
+			AverageDuration: 90 * time.Second,
+			MaxDuration:     2 * time.Minute,
+			ReposAdded:      3,
+			APIRequests:     42,
+		}, nil
+	}
+	database.Mocks.ExternalServices.NextSyncInfo = func(ctx context.Context, ids []int64) ([]*types.ExternalServiceNextSyncInfo, error) {
+		return []*types.ExternalServiceNextSyncInfo{{
+			ExternalServiceID: ids[0],
+			NextSyncAt:        time.Date(2021, 11, 2, 12, 0, 0, 0, time.UTC),
+			SyncJobState:      "queued",
+			BackoffReason:     types.ExternalServiceSyncBackoffRateLimited,
+		}}, nil
+	}
 	defer func() {
 		database.Mocks.Users = database.MockUsers{}
 		database.Mocks.ExternalServices = database.MockExternalServices{}
@@ -994,6 +1336,47 @@ func TestExternalServices(t *testing.T) {
 			}
 		`,
 		},
+		// Search and sort external services
+		{
+			Schema: mustParseGraphQLSchema(t),
+			Query: `
+			{
+				externalServices(query: "github", orderBy: EXTERNAL_SERVICE_DISPLAY_NAME) {
+					nodes {
+						id
+					}
+				}
+			}
+		`,
+			ExpectedResult: `
+			{
+				"externalServices": {
+					"nodes": [{"id":"RXh0ZXJuYWxTZXJ2aWNlOjI="}, {"id":"RXh0ZXJuYWxTZXJ2aWNlOjE="}]
+				}
+			}
+		`,
+		},
+		// Cursors are only supported when sorting by ID
+		{
+			Schema: mustParseGraphQLSchema(t),
+			Query: `
+			{
+				externalServices(after: "RXh0ZXJuYWxTZXJ2aWNlOjE=", orderBy: EXTERNAL_SERVICE_KIND) {
+					nodes {
+						id
+					}
+				}
+			}
+		`,
+			ExpectedErrors: []*gqlerrors.QueryError{
+				{
+					Path:          []interface{}{"externalServices"},
+					Message:       "after is only supported when sorting by ID",
+					ResolverError: errors.New("after is only supported when sorting by ID"),
+				},
+			},
+			ExpectedResult: `null`,
+		},
 		// Not allowed to read someone else's external service
 		{
 			Schema: mustParseGraphQLSchema(t),
@@ -1039,6 +1422,76 @@ func TestExternalServices(t *testing.T) {
 			}
 		`,
 		},
+		// SyncStatistics included
+		{
+			Schema: mustParseGraphQLSchema(t),
+			Query: `
+			{
+				externalServices(first: 1) {
+					nodes {
+						syncStatistics {
+							syncs
+							erroredSyncs
+							averageDurationSeconds
+							maxDurationSeconds
+							reposAdded
+							reposRemoved
+							apiRequests
+							lastErroredAt
+						}
+					}
+				}
+			}
+		`,
+			ExpectedResult: `
+			{
+				"externalServices": {
+					"nodes": [
+						{"syncStatistics": {
+							"syncs": 2,
+							"erroredSyncs": 1,
+							"averageDurationSeconds": 90,
+							"maxDurationSeconds": 120,
+							"reposAdded": 3,
+							"reposRemoved": 0,
+							"apiRequests": 42,
+							"lastErroredAt": null
+						}}
+					]
+				}
+			}
+		`,
+		},
+		// NextSync included
+		{
+			Schema: mustParseGraphQLSchema(t),
+			Query: `
+			{
+				externalServices(first: 1) {
+					nodes {
+						nextSync {
+							nextSyncAt
+							syncJobState
+							backoffReason
+						}
+					}
+				}
+			}
+		`,
+			ExpectedResult: `
+			{
+				"externalServices": {
+					"nodes": [
+						{"nextSync": {
+							"nextSyncAt": "2021-11-02T12:00:00Z",
+							"syncJobState": "QUEUED",
+							"backoffReason": "RATE_LIMITED"
+						}}
+					]
+				}
+			}
+		`,
+		},
 		// Pagination
 		{
 			Schema: mustParseGraphQLSchema(t),
@@ -1150,6 +1603,22 @@ func TestExternalServices_PageInfo(t *testing.T) {
 			},
 			wantPageInfo: graphqlutil.NextPageCursor(string(marshalExternalServiceID(1))),
 		},
+		{
+			name: "same number of results as the limit, and has more, sorted by display name",
+			opt: database.ExternalServicesListOptions{
+				OrderBy: database.ExternalServiceOrderByDisplayName,
+				LimitOffset: &database.LimitOffset{
+					Limit: 1,
+				},
+			},
+			mockList: func(opt database.ExternalServicesListOptions) ([]*types.ExternalService, error) {
+				return []*types.ExternalService{{ID: 1}}, nil
+			},
+			mockCount: func(ctx context.Context, opt database.ExternalServicesListOptions) (int, error) {
+				return 2, nil
+			},
+			wantPageInfo: graphqlutil.HasNextPage(true),
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -22,14 +22,21 @@ type InsightsResolver interface {
 	CreateInsightsDashboard(ctx context.Context, args *CreateInsightsDashboardArgs) (InsightsDashboardPayloadResolver, error)
 	UpdateInsightsDashboard(ctx context.Context, args *UpdateInsightsDashboardArgs) (InsightsDashboardPayloadResolver, error)
 	DeleteInsightsDashboard(ctx context.Context, args *DeleteInsightsDashboardArgs) (*EmptyResponse, error)
+	DuplicateInsightsDashboard(ctx context.Context, args *DuplicateInsightsDashboardArgs) (InsightsDashboardPayloadResolver, error)
 	RemoveInsightViewFromDashboard(ctx context.Context, args *RemoveInsightViewFromDashboardArgs) (InsightsDashboardPayloadResolver, error)
 	AddInsightViewToDashboard(ctx context.Context, args *AddInsightViewToDashboardArgs) (InsightsDashboardPayloadResolver, error)
 
 	CreateLineChartSearchInsight(ctx context.Context, args *CreateLineChartSearchInsightArgs) (CreateInsightResultResolver, error)
+	SearchInsightLivePreview(ctx context.Context, args *SearchInsightLivePreviewArgs) (SearchInsightLivePreviewSeriesResolver, error)
 
 	// Admin Management
 	UpdateInsightSeries(ctx context.Context, args *UpdateInsightSeriesArgs) (InsightSeriesMetadataPayloadResolver, error)
 	InsightSeriesQueryStatus(ctx context.Context) ([]InsightSeriesQueryStatusResolver, error)
+
+	// Alerts
+	InsightSeriesAlerts(ctx context.Context, args *InsightSeriesAlertsArgs) ([]InsightSeriesAlertResolver, error)
+	CreateInsightSeriesAlert(ctx context.Context, args *CreateInsightSeriesAlertArgs) (InsightSeriesAlertResolver, error)
+	DeleteInsightSeriesAlert(ctx context.Context, args *DeleteInsightSeriesAlertArgs) (*EmptyResponse, error)
 }
 
 type InsightsArgs struct {
@@ -47,6 +54,8 @@ type InsightStatusResolver interface {
 	CompletedJobs() int32
 	FailedJobs() int32
 	BackfillQueuedAt() *DateTime
+	BackfillEstimatedCost() *int32
+	BackfillSpentCost() int32
 }
 
 type InsightsPointsArgs struct {
@@ -134,6 +143,17 @@ type DeleteInsightsDashboardArgs struct {
 	Id graphql.ID
 }
 
+type DuplicateInsightsDashboardArgs struct {
+	Id    graphql.ID
+	Input DuplicateInsightsDashboardInput
+}
+
+type DuplicateInsightsDashboardInput struct {
+	Title           *string
+	Grants          *InsightsPermissionGrants
+	RepositoryScope *RepositoryScopeInput
+}
+
 type InsightViewConnectionResolver interface {
 	Nodes(ctx context.Context) ([]InsightViewResolver, error)
 	PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error)
@@ -168,6 +188,9 @@ type SearchInsightDataSeriesDefinitionResolver interface {
 	Query(ctx context.Context) (string, error)
 	RepositoryScope(ctx context.Context) (InsightRepositoryScopeResolver, error)
 	TimeScope(ctx context.Context) (InsightTimeScope, error)
+	GeneratedFromCaptureGroups(ctx context.Context) (bool, error)
+	GenerationMethod(ctx context.Context) (string, error)
+	TopRepositoriesLimit(ctx context.Context) (*int32, error)
 }
 
 type InsightPresentation interface {
@@ -185,6 +208,7 @@ type InsightIntervalTimeScope interface {
 
 type InsightRepositoryScopeResolver interface {
 	Repositories(ctx context.Context) ([]string, error)
+	RepositoryQuery(ctx context.Context) (*string, error)
 }
 
 type InsightsDashboardPayloadResolver interface {
@@ -239,6 +263,40 @@ type InsightSeriesQueryStatusResolver interface {
 	Queued(ctx context.Context) (int32, error)
 }
 
+type InsightSeriesAlertsArgs struct {
+	SeriesId string
+}
+
+type CreateInsightSeriesAlertArgs struct {
+	Input CreateInsightSeriesAlertInput
+}
+
+type CreateInsightSeriesAlertInput struct {
+	SeriesId             string
+	Threshold            float64
+	Direction            string
+	EvaluationWindowDays *int32
+	Email                *bool
+	WebhookURL           *string
+}
+
+type DeleteInsightSeriesAlertArgs struct {
+	Id graphql.ID
+}
+
+type InsightSeriesAlertResolver interface {
+	ID() graphql.ID
+	SeriesId() string
+	Threshold() float64
+	Direction() string
+	EvaluationWindowDays() int32
+	Email() bool
+	WebhookURL() *string
+	Triggered() bool
+	LastEvaluatedAt() *DateTime
+	LastTriggeredAt() *DateTime
+}
+
 type InsightViewFiltersResolver interface {
 	IncludeRepoRegex(ctx context.Context) (*string, error)
 	ExcludeRepoRegex(ctx context.Context) (*string, error)
@@ -258,6 +316,10 @@ type LineChartSearchInsightDataSeriesInput struct {
 	TimeScope       TimeScopeInput
 	RepositoryScope RepositoryScopeInput
 	Options         LineChartDataSeriesOptionsInput
+
+	GeneratedFromCaptureGroups *bool
+	GenerationMethod           *string
+	TopRepositoriesLimit       *int32
 }
 
 type LineChartDataSeriesOptionsInput struct {
@@ -266,7 +328,8 @@ type LineChartDataSeriesOptionsInput struct {
 }
 
 type RepositoryScopeInput struct {
-	Repositories []string
+	Repositories    []string
+	RepositoryQuery *string
 }
 
 type TimeScopeInput struct {
@@ -282,6 +345,24 @@ type LineChartOptionsInput struct {
 	Title *string
 }
 
+type SearchInsightLivePreviewArgs struct {
+	Input SearchInsightLivePreviewInput
+}
+
+type SearchInsightLivePreviewInput struct {
+	Query           string
+	Label           string
+	TimeScope       TimeScopeInput
+	RepositoryScope RepositoryScopeInput
+}
+
+type SearchInsightLivePreviewSeriesResolver interface {
+	Label() string
+	Points() []InsightsDataPointResolver
+	SampledRepositories() int32
+	Complete() bool
+}
+
 type CreateInsightResultResolver interface {
 	View(ctx context.Context) (InsightViewResolver, error)
 }
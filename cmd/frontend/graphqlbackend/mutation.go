@@ -0,0 +1,32 @@
+package graphqlbackend
+
+import (
+	"github.com/cockroachdb/errors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// IsMutation reports whether the operation with the given name of a GraphQL request is a mutation.
+// If the name is empty, it reports whether any operation of the request is a mutation.
+func IsMutation(query, operationName string) (bool, error) {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: query,
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "parsing query")
+	}
+
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if operationName != "" && (op.Name == nil || op.Name.Value != operationName) {
+			continue
+		}
+		if op.Operation == ast.OperationTypeMutation {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,43 @@
+package graphqlbackend
+
+import "testing"
+
+func TestIsMutation(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		operationName string
+		want          bool
+	}{
+		{name: "query", query: `query Foo { currentUser { username } }`, want: false},
+		{name: "shorthand query", query: `{ currentUser { username } }`, want: false},
+		{name: "mutation", query: `mutation Foo { logUserEvent(event: "foo", userCookieID: "bar") { alwaysNil } }`, want: true},
+		{
+			name:          "named query next to mutation",
+			query:         `query Foo { currentUser { username } } mutation Bar { logUserEvent(event: "foo", userCookieID: "bar") { alwaysNil } }`,
+			operationName: "Foo",
+			want:          false,
+		},
+		{
+			name:          "named mutation next to query",
+			query:         `query Foo { currentUser { username } } mutation Bar { logUserEvent(event: "foo", userCookieID: "bar") { alwaysNil } }`,
+			operationName: "Bar",
+			want:          true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := IsMutation(test.query, test.operationName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+
+	if _, err := IsMutation(`mutation {`, ""); err == nil {
+		t.Error("want error for invalid query")
+	}
+}
@@ -211,6 +211,11 @@ func (r *NodeResolver) ToLSIFIndex() (LSIFIndexResolver, bool) {
 	return n, ok
 }
 
+func (r *NodeResolver) ToLSIFIndexRequest() (LSIFIndexRequestResolver, bool) {
+	n, ok := r.Node.(LSIFIndexRequestResolver)
+	return n, ok
+}
+
 func (r *NodeResolver) ToCodeIntelligenceConfigurationPolicy() (CodeIntelligenceConfigurationPolicyResolver, bool) {
 	n, ok := r.Node.(CodeIntelligenceConfigurationPolicyResolver)
 	return n, ok
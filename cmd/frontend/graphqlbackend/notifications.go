@@ -0,0 +1,158 @@
+package graphqlbackend
+
+import (
+	"context"
+	"sync"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+func marshalNotificationID(id int64) graphql.ID { return relay.MarshalID("Notification", id) }
+
+func unmarshalNotificationID(id graphql.ID) (notificationID int64, err error) {
+	err = relay.UnmarshalSpec(id, &notificationID)
+	return
+}
+
+func (r *UserResolver) Notifications(ctx context.Context, args *struct {
+	graphqlutil.ConnectionArgs
+	UnreadOnly bool
+}) (*notificationConnectionResolver, error) {
+	// 🚨 SECURITY: Only the user can list their notifications.
+	if err := backend.CheckSameUser(ctx, r.user.ID); err != nil {
+		return nil, err
+	}
+
+	opt := database.NotificationsListOptions{UserID: r.user.ID, UnreadOnly: args.UnreadOnly}
+	args.ConnectionArgs.Set(&opt.LimitOffset)
+	return &notificationConnectionResolver{db: r.db, opt: opt}, nil
+}
+
+func (r *schemaResolver) MarkNotificationsRead(ctx context.Context, args *struct {
+	Notifications []graphql.ID
+}) (*EmptyResponse, error) {
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		return nil, backend.ErrNotAuthenticated
+	}
+
+	ids := make([]int64, 0, len(args.Notifications))
+	for _, id := range args.Notifications {
+		notificationID, err := unmarshalNotificationID(id)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, notificationID)
+	}
+
+	// 🚨 SECURITY: Only the notifications of the current user are marked as read.
+	if err := database.Notifications(r.db).MarkRead(ctx, a.UID, ids); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+func (r *schemaResolver) MarkAllNotificationsRead(ctx context.Context) (*EmptyResponse, error) {
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		return nil, backend.ErrNotAuthenticated
+	}
+	if err := database.Notifications(r.db).MarkAllRead(ctx, a.UID); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+// notificationConnectionResolver resolves a list of notifications.
+//
+// 🚨 SECURITY: When instantiating a notificationConnectionResolver value, the caller
+// MUST check permissions.
+type notificationConnectionResolver struct {
+	opt database.NotificationsListOptions
+
+	// cache results because they are used by multiple fields
+	once          sync.Once
+	notifications []*database.Notification
+	err           error
+	db            dbutil.DB
+}
+
+func (r *notificationConnectionResolver) compute(ctx context.Context) ([]*database.Notification, error) {
+	r.once.Do(func() {
+		opt2 := r.opt
+		if opt2.LimitOffset != nil {
+			tmp := *opt2.LimitOffset
+			opt2.LimitOffset = &tmp
+			opt2.Limit++ // so we can detect if there is a next page
+		}
+
+		r.notifications, r.err = database.Notifications(r.db).List(ctx, opt2)
+	})
+	return r.notifications, r.err
+}
+
+func (r *notificationConnectionResolver) Nodes(ctx context.Context) ([]*notificationResolver, error) {
+	notifications, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if r.opt.LimitOffset != nil && len(notifications) > r.opt.LimitOffset.Limit {
+		notifications = notifications[:r.opt.LimitOffset.Limit]
+	}
+
+	l := make([]*notificationResolver, 0, len(notifications))
+	for _, n := range notifications {
+		l = append(l, &notificationResolver{notification: n})
+	}
+	return l, nil
+}
+
+func (r *notificationConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	count, err := database.Notifications(r.db).Count(ctx, r.opt)
+	return int32(count), err
+}
+
+func (r *notificationConnectionResolver) UnreadCount(ctx context.Context) (int32, error) {
+	count, err := database.Notifications(r.db).Count(ctx, database.NotificationsListOptions{UserID: r.opt.UserID, UnreadOnly: true})
+	return int32(count), err
+}
+
+func (r *notificationConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	notifications, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return graphqlutil.HasNextPage(r.opt.LimitOffset != nil && len(notifications) > r.opt.Limit), nil
+}
+
+type notificationResolver struct {
+	notification *database.Notification
+}
+
+func (r *notificationResolver) ID() graphql.ID { return marshalNotificationID(r.notification.ID) }
+
+func (r *notificationResolver) Kind() string { return string(r.notification.Kind) }
+
+func (r *notificationResolver) Title() string { return r.notification.Title }
+
+func (r *notificationResolver) Body() string { return r.notification.Body }
+
+func (r *notificationResolver) URL() *string {
+	if r.notification.URL == "" {
+		return nil
+	}
+	return &r.notification.URL
+}
+
+func (r *notificationResolver) CreatedAt() DateTime { return DateTime{Time: r.notification.CreatedAt} }
+
+func (r *notificationResolver) ReadAt() *DateTime { return DateTimeOrNil(r.notification.ReadAt) }
+
+func (r *notificationResolver) ExpiresAt() DateTime { return DateTime{Time: r.notification.ExpiresAt} }
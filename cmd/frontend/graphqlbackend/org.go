@@ -2,6 +2,7 @@ package graphqlbackend
 
 import (
 	"context"
+	"strings"
 
 	"github.com/cockroachdb/errors"
 	"github.com/graph-gophers/graphql-go"
@@ -150,7 +151,7 @@ func (o *OrgResolver) ViewerPendingInvitation(ctx context.Context) (*organizatio
 }
 
 func (o *OrgResolver) ViewerCanAdminister(ctx context.Context) (bool, error) {
-	if err := backend.CheckOrgAccessOrSiteAdmin(ctx, o.db, o.org.ID); err == backend.ErrNotAuthenticated || err == backend.ErrNotAnOrgMember {
+	if err := backend.CheckOrgAdminOrSiteAdmin(ctx, o.db, o.org.ID); err == backend.ErrNotAuthenticated || err == backend.ErrNotAnOrgMember || err == backend.ErrNotAnOrgAdmin {
 		return false, nil
 	} else if err != nil {
 		return false, err
@@ -197,8 +198,8 @@ func (r *schemaResolver) CreateOrganization(ctx context.Context, args *struct {
 		return nil, err
 	}
 
-	// Add the current user as the first member of the new org.
-	_, err = database.OrgMembers(r.db).Create(ctx, newOrg.ID, a.UID)
+	// Add the current user as the first member and admin of the new org.
+	_, err = database.OrgMembers(r.db).CreateWithRole(ctx, newOrg.ID, a.UID, types.OrgRoleAdmin)
 	if err != nil {
 		return nil, err
 	}
@@ -215,9 +216,9 @@ func (r *schemaResolver) UpdateOrganization(ctx context.Context, args *struct {
 		return nil, err
 	}
 
-	// 🚨 SECURITY: Check that the current user is a member
+	// 🚨 SECURITY: Check that the current user is an admin
 	// of the org that is being modified.
-	if err := backend.CheckOrgAccessOrSiteAdmin(ctx, r.db, orgID); err != nil {
+	if err := backend.CheckOrgAdminOrSiteAdmin(ctx, r.db, orgID); err != nil {
 		return nil, err
 	}
 
@@ -242,16 +243,44 @@ func (r *schemaResolver) RemoveUserFromOrganization(ctx context.Context, args *s
 		return nil, err
 	}
 
-	// 🚨 SECURITY: Check that the current user is a member of the org that is being modified, or a
-	// site admin.
-	if err := backend.CheckOrgAccessOrSiteAdmin(ctx, r.db, orgID); err != nil {
-		return nil, err
+	// 🚨 SECURITY: Check that the current user is an admin of the org that is being modified, or a
+	// site admin. Members may always leave the org.
+	if a := actor.FromContext(ctx); !a.IsAuthenticated() || a.UID != userID {
+		if err := backend.CheckOrgAdminOrSiteAdmin(ctx, r.db, orgID); err != nil {
+			return nil, err
+		}
 	}
 
 	log15.Info("removing user from org", "user", userID, "org", orgID)
 	return nil, database.OrgMembers(r.db).Remove(ctx, orgID, userID)
 }
 
+func (r *schemaResolver) SetOrganizationMemberRole(ctx context.Context, args *struct {
+	Organization graphql.ID
+	User         graphql.ID
+	Role         string
+}) (*EmptyResponse, error) {
+	orgID, err := UnmarshalOrgID(args.Organization)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := UnmarshalUserID(args.User)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Only org admins and site admins may change the roles of members.
+	if err := backend.CheckOrgAdminOrSiteAdmin(ctx, r.db, orgID); err != nil {
+		return nil, err
+	}
+
+	role := types.OrgRole(strings.ToLower(args.Role))
+	if err := database.OrgMembers(r.db).SetRole(ctx, orgID, userID, role); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
 func (r *schemaResolver) AddUserToOrganization(ctx context.Context, args *struct {
 	Organization graphql.ID
 	Username     string
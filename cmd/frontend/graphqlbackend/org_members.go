@@ -2,6 +2,7 @@ package graphqlbackend
 
 import (
 	"context"
+	"strings"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
@@ -52,6 +53,13 @@ func (r *organizationMembershipResolver) User(ctx context.Context) (*UserResolve
 	return UserByIDInt32(ctx, r.db, r.membership.UserID)
 }
 
+func (r *organizationMembershipResolver) Role() string {
+	if r.membership.Role == "" {
+		return strings.ToUpper(string(types.OrgRoleMember))
+	}
+	return strings.ToUpper(string(r.membership.Role))
+}
+
 func (r *organizationMembershipResolver) CreatedAt() DateTime {
 	return DateTime{Time: r.membership.CreatedAt}
 }
@@ -366,6 +366,8 @@ type LimiterArgs struct {
 	Anonymous     bool
 	RequestName   string
 	RequestSource trace.SourceType
+	// AccessTokenID is the ID of the access token the request was authenticated with, if any.
+	AccessTokenID int64
 }
 
 type Limiter interface {
@@ -497,6 +499,19 @@ func (w *RateLimitWatcher) updateFromConfig(rlc *schema.ApiRatelimit) {
 		return
 	}
 
+	var tokenLimiter *throttled.GCRARateLimiter
+	if rlc.PerAccessToken > 0 {
+		tokenQuota := throttled.RateQuota{
+			MaxRate:  throttled.PerHour(rlc.PerAccessToken),
+			MaxBurst: int(float64(rlc.PerAccessToken) * maxBurstPercentage),
+		}
+		tokenLimiter, err = throttled.NewGCRARateLimiter(w.store, tokenQuota)
+		if err != nil {
+			log15.Warn("error creating access token rate limiter", "error", err)
+			return
+		}
+	}
+
 	overrides := make(map[string]limiter)
 	for _, o := range rlc.Overrides {
 		switch l := o.Limit.(type) {
@@ -540,18 +555,47 @@ func (w *RateLimitWatcher) updateFromConfig(rlc *schema.ApiRatelimit) {
 
 	// Store the new limiter
 	w.rl.Store(&RateLimiter{
-		enabled:     true,
-		ipLimiter:   ipLimiter,
-		userLimiter: userLimiter,
-		overrides:   overrides,
+		enabled:        true,
+		applyToGraphQL: rlc.ApplyToGraphQL,
+		ipLimiter:      ipLimiter,
+		userLimiter:    userLimiter,
+		tokenLimiter:   tokenLimiter,
+		overrides:      overrides,
 	})
 }
 
+// getForGraphQL returns the current rate limiter if it is enabled and applies to the GraphQL API.
+func (w *RateLimitWatcher) getForGraphQL() (*RateLimiter, bool) {
+	if l, ok := w.rl.Load().(*RateLimiter); ok && l.enabled && l.applyToGraphQL {
+		return l, true
+	}
+	return nil, false
+}
+
+// AccessTokenUsage returns the rate limit state of the given access token without consuming any
+// of its limit. If access tokens are not rate limited separately from their users, nil is
+// returned.
+func (w *RateLimitWatcher) AccessTokenUsage(accessTokenID int64) (*throttled.RateLimitResult, error) {
+	l, ok := w.rl.Load().(*RateLimiter)
+	if !ok || !l.enabled || l.tokenLimiter == nil {
+		return nil, nil
+	}
+	_, result, err := l.tokenLimiter.RateLimit(accessTokenRateLimitKey(accessTokenID), 0)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
 type RateLimiter struct {
-	enabled     bool
-	ipLimiter   *throttled.GCRARateLimiter
-	userLimiter *throttled.GCRARateLimiter
-	overrides   map[string]limiter
+	enabled bool
+	// applyToGraphQL is whether the limits apply to the GraphQL API, which site admins have to
+	// opt in to.
+	applyToGraphQL bool
+	ipLimiter      *throttled.GCRARateLimiter
+	userLimiter    *throttled.GCRARateLimiter
+	tokenLimiter   *throttled.GCRARateLimiter
+	overrides      map[string]limiter
 }
 
 func (rl *RateLimiter) RateLimit(uid string, cost int, args LimiterArgs) (bool, throttled.RateLimitResult, error) {
@@ -561,9 +605,50 @@ func (rl *RateLimiter) RateLimit(uid string, cost int, args LimiterArgs) (bool,
 	if args.IsIP {
 		return rl.ipLimiter.RateLimit(uid, cost)
 	}
+	if args.AccessTokenID != 0 && rl.tokenLimiter != nil {
+		// Requests authenticated with an access token count towards the limits of both the
+		// token and its user, so that creating more tokens does not raise the limit of a user.
+		tokenKey := accessTokenRateLimitKey(args.AccessTokenID)
+
+		// Check both limits before charging either, so that a request rejected by one of
+		// them doesn't use up the other.
+		for _, b := range []struct {
+			limiter *throttled.GCRARateLimiter
+			key     string
+		}{{rl.tokenLimiter, tokenKey}, {rl.userLimiter, uid}} {
+			_, result, err := b.limiter.RateLimit(b.key, 0)
+			if err != nil {
+				return false, result, err
+			}
+			if result.Remaining < cost {
+				// Rejected requests don't charge the bucket, but report when to retry.
+				return b.limiter.RateLimit(b.key, cost)
+			}
+		}
+
+		limited, tokenResult, err := rl.tokenLimiter.RateLimit(tokenKey, cost)
+		if err != nil || limited {
+			return limited, tokenResult, err
+		}
+		limited, userResult, err := rl.userLimiter.RateLimit(uid, cost)
+		if err != nil || limited {
+			return limited, userResult, err
+		}
+		// Report the most restrictive of the two limits.
+		if userResult.Remaining < tokenResult.Remaining {
+			return false, userResult, nil
+		}
+		return false, tokenResult, nil
+	}
 	return rl.userLimiter.RateLimit(uid, cost)
 }
 
+// accessTokenRateLimitKey returns the key of the rate limit of an access token, which must not
+// collide with the keys of users and IPs.
+func accessTokenRateLimitKey(accessTokenID int64) string {
+	return "token:" + strconv.FormatInt(accessTokenID, 10)
+}
+
 type limiter interface {
 	RateLimit(string, int) (bool, throttled.RateLimitResult, error)
 }
@@ -577,3 +662,42 @@ type fixedLimiter struct {
 func (f *fixedLimiter) RateLimit(string, int) (bool, throttled.RateLimitResult, error) {
 	return f.limited, f.result, nil
 }
+
+// APILimitWatcher applies the limits of the api.ratelimit site configuration to GraphQL requests
+// if they are enabled and api.ratelimit.applyToGraphQL is set, and otherwise the limit of
+// anonymous requests of the rateLimitAnonymous experimental feature.
+type APILimitWatcher struct {
+	api   *RateLimitWatcher
+	basic *BasicLimitWatcher
+}
+
+// NewAPILimitWatcher creates a new limiter that applies the limits of the given watchers.
+func NewAPILimitWatcher(api *RateLimitWatcher, basic *BasicLimitWatcher) *APILimitWatcher {
+	return &APILimitWatcher{api: api, basic: basic}
+}
+
+// Get returns the current rate limiter. If rate limiting is currently disabled (nil, false) is
+// returned.
+func (w *APILimitWatcher) Get() (Limiter, bool) {
+	if l, ok := w.api.getForGraphQL(); ok {
+		return l, true
+	}
+	return w.basic.Get()
+}
+
+// AccessTokenUsage returns the rate limit state of the given access token without consuming any
+// of its limit, or nil if access tokens are not rate limited.
+func (w *APILimitWatcher) AccessTokenUsage(accessTokenID int64) (*throttled.RateLimitResult, error) {
+	if _, ok := w.api.getForGraphQL(); !ok {
+		return nil, nil
+	}
+	return w.api.AccessTokenUsage(accessTokenID)
+}
+
+// GetAccessTokenRateLimit is called to obtain the rate limit state of an access token for the
+// GraphQL resolver of AccessToken.rateLimit, or nil if access tokens are not rate limited.
+//
+// It is set when the frontend starts to the rate limiter of the GraphQL endpoint.
+var GetAccessTokenRateLimit = func(accessTokenID int64) (*throttled.RateLimitResult, error) {
+	return nil, nil
+}
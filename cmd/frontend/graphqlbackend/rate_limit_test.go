@@ -423,9 +423,10 @@ func TestRatelimitFromConfig(t *testing.T) {
 		name   string
 		config *schema.ApiRatelimit
 
-		uid  string
-		isIP bool
-		cost int
+		uid           string
+		isIP          bool
+		accessTokenID int64
+		cost          int
 
 		enabled bool
 
@@ -494,6 +495,71 @@ func TestRatelimitFromConfig(t *testing.T) {
 				RetryAfter: -1,
 			},
 		},
+		{
+			name: "Per access token",
+			config: &schema.ApiRatelimit{
+				Enabled:        true,
+				PerIP:          5000,
+				PerUser:        5000,
+				PerAccessToken: 500,
+			},
+			enabled: true,
+
+			uid:           "test",
+			accessTokenID: 1,
+			cost:          1,
+
+			wantLimited: false,
+			wantResult: throttled.RateLimitResult{
+				Limit:      101,
+				Remaining:  100,
+				ResetAfter: 7200 * time.Millisecond,
+				RetryAfter: -1,
+			},
+		},
+		{
+			name: "Per access token with lower user limit",
+			config: &schema.ApiRatelimit{
+				Enabled:        true,
+				PerIP:          5000,
+				PerUser:        50,
+				PerAccessToken: 500,
+			},
+			enabled: true,
+
+			uid:           "test",
+			accessTokenID: 1,
+			cost:          1,
+
+			wantLimited: false,
+			wantResult: throttled.RateLimitResult{
+				Limit:      11,
+				Remaining:  10,
+				ResetAfter: 72 * time.Second,
+				RetryAfter: -1,
+			},
+		},
+		{
+			name: "Access token without per access token limit",
+			config: &schema.ApiRatelimit{
+				Enabled: true,
+				PerIP:   5000,
+				PerUser: 5000,
+			},
+			enabled: true,
+
+			uid:           "test",
+			accessTokenID: 1,
+			cost:          1,
+
+			wantLimited: false,
+			wantResult: throttled.RateLimitResult{
+				Limit:      1001,
+				Remaining:  1000,
+				ResetAfter: 720 * time.Millisecond,
+				RetryAfter: -1,
+			},
+		},
 		{
 			name: "With blocked override",
 			config: &schema.ApiRatelimit{
@@ -569,7 +635,7 @@ func TestRatelimitFromConfig(t *testing.T) {
 			if !tc.enabled {
 				return
 			}
-			limited, result, err := rl.RateLimit(tc.uid, tc.cost, LimiterArgs{IsIP: tc.isIP})
+			limited, result, err := rl.RateLimit(tc.uid, tc.cost, LimiterArgs{IsIP: tc.isIP, AccessTokenID: tc.accessTokenID})
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -583,6 +649,119 @@ func TestRatelimitFromConfig(t *testing.T) {
 	}
 }
 
+func TestAccessTokenUsage(t *testing.T) {
+	store, err := memstore.New(1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rlw := &RateLimitWatcher{store: store}
+
+	rlw.updateFromConfig(&schema.ApiRatelimit{Enabled: true, PerIP: 5000, PerUser: 5000})
+	if usage, err := rlw.AccessTokenUsage(1); err != nil || usage != nil {
+		t.Fatalf("want no usage without per access token limit, got %v (error %v)", usage, err)
+	}
+
+	rlw.updateFromConfig(&schema.ApiRatelimit{Enabled: true, PerIP: 5000, PerUser: 5000, PerAccessToken: 500})
+	rl, _ := rlw.Get()
+	if _, _, err := rl.RateLimit("test", 10, LimiterArgs{AccessTokenID: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reading the usage must not consume any of the limit.
+	for i := 0; i < 2; i++ {
+		usage, err := rlw.AccessTokenUsage(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if usage == nil || usage.Limit != 101 || usage.Remaining != 91 {
+			t.Fatalf("unexpected usage: %+v", usage)
+		}
+	}
+}
+
+func TestAccessTokenChargesUser(t *testing.T) {
+	store, err := memstore.New(1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rlw := &RateLimitWatcher{store: store}
+	rlw.updateFromConfig(&schema.ApiRatelimit{Enabled: true, PerIP: 5000, PerUser: 500, PerAccessToken: 5000})
+	rl, _ := rlw.Get()
+
+	limited, _, err := rl.RateLimit("test", 100, LimiterArgs{AccessTokenID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limited {
+		t.Fatal("want first request not to be limited")
+	}
+
+	// The first request used up the limit of the user, so requests with other tokens of the
+	// same user are limited too.
+	limited, _, err = rl.RateLimit("test", 10, LimiterArgs{AccessTokenID: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !limited {
+		t.Fatal("want request with another access token of the user to be limited")
+	}
+}
+
+func TestAccessTokenLimitedDoesNotChargeUser(t *testing.T) {
+	store, err := memstore.New(1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rlw := &RateLimitWatcher{store: store}
+	rlw.updateFromConfig(&schema.ApiRatelimit{Enabled: true, PerIP: 5000, PerUser: 1000, PerAccessToken: 500})
+	rl, _ := rlw.Get()
+
+	// Requests exceeding the limit of the token must not use up the limit of its user.
+	for i := 0; i < 10; i++ {
+		limited, _, err := rl.RateLimit("test", 60, LimiterArgs{AccessTokenID: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := i > 0; limited != want {
+			t.Fatalf("request %d: want limited %v, got %v", i, want, limited)
+		}
+	}
+
+	limited, _, err := rl.RateLimit("test", 100, LimiterArgs{AccessTokenID: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limited {
+		t.Fatal("want request with another access token of the user not to be limited")
+	}
+}
+
+func TestAPILimitWatcher(t *testing.T) {
+	store, err := memstore.New(1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rlw := &RateLimitWatcher{store: store}
+	w := NewAPILimitWatcher(rlw, &BasicLimitWatcher{store: store})
+
+	// The limits of api.ratelimit only apply to the GraphQL API if site admins opt in.
+	rlw.updateFromConfig(&schema.ApiRatelimit{Enabled: true, PerIP: 5000, PerUser: 5000, PerAccessToken: 500})
+	if _, enabled := w.Get(); enabled {
+		t.Fatal("want rate limiting disabled without applyToGraphQL")
+	}
+	if usage, err := w.AccessTokenUsage(1); err != nil || usage != nil {
+		t.Fatalf("want no usage without applyToGraphQL, got %v (error %v)", usage, err)
+	}
+
+	rlw.updateFromConfig(&schema.ApiRatelimit{Enabled: true, ApplyToGraphQL: true, PerIP: 5000, PerUser: 5000, PerAccessToken: 500})
+	if _, enabled := w.Get(); !enabled {
+		t.Fatal("want rate limiting enabled with applyToGraphQL")
+	}
+	if usage, err := w.AccessTokenUsage(1); err != nil || usage == nil {
+		t.Fatalf("want usage with applyToGraphQL, got %v (error %v)", usage, err)
+	}
+}
+
 func TestBasicLimiterEnabled(t *testing.T) {
 	tests := []struct {
 		limit       int
@@ -376,6 +376,10 @@ func (r *RepositoryResolver) PermissionsInfo(ctx context.Context) (PermissionsIn
 	return EnterpriseResolvers.authzResolver.RepositoryPermissionsInfo(ctx, r.ID())
 }
 
+func (r *RepositoryResolver) PermissionsSyncState(ctx context.Context) (PermissionsSyncStateResolver, error) {
+	return EnterpriseResolvers.authzResolver.RepositoryPermissionsSyncState(ctx, r.ID())
+}
+
 func (r *schemaResolver) AddPhabricatorRepo(ctx context.Context, args *struct {
 	Callsign string
 	Name     *string
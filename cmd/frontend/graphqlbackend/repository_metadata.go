@@ -0,0 +1,170 @@
+package graphqlbackend
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/graph-gophers/graphql-go"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+)
+
+type keyValuePairResolver struct {
+	kvp database.RepoKVP
+}
+
+func (r *keyValuePairResolver) Key() string    { return r.kvp.Key }
+func (r *keyValuePairResolver) Value() *string { return r.kvp.Value }
+
+func (r *RepositoryResolver) KeyValuePairs(ctx context.Context) ([]*keyValuePairResolver, error) {
+	kvps, err := database.RepoKVPs(r.db).List(ctx, r.IDInt32())
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*keyValuePairResolver, 0, len(kvps))
+	for _, kvp := range kvps {
+		resolvers = append(resolvers, &keyValuePairResolver{kvp: kvp})
+	}
+	return resolvers, nil
+}
+
+type repoKeyValuePairArgs struct {
+	Repo  graphql.ID
+	Key   string
+	Value *string
+}
+
+func (r *schemaResolver) AddRepoKeyValuePair(ctx context.Context, args *repoKeyValuePairArgs) (*EmptyResponse, error) {
+	repoID, err := r.checkRepoKeyValuePairArgs(ctx, args.Repo, args.Key)
+	if err != nil {
+		return nil, err
+	}
+	if err := database.RepoKVPs(r.db).Create(ctx, database.RepoKVP{RepoID: repoID, Key: args.Key, Value: args.Value}); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+func (r *schemaResolver) UpdateRepoKeyValuePair(ctx context.Context, args *repoKeyValuePairArgs) (*EmptyResponse, error) {
+	repoID, err := r.checkRepoKeyValuePairArgs(ctx, args.Repo, args.Key)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := database.RepoKVPs(r.db).Update(ctx, database.RepoKVP{RepoID: repoID, Key: args.Key, Value: args.Value}); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+func (r *schemaResolver) DeleteRepoKeyValuePair(ctx context.Context, args *struct {
+	Repo graphql.ID
+	Key  string
+}) (*EmptyResponse, error) {
+	repoID, err := r.checkRepoKeyValuePairArgs(ctx, args.Repo, args.Key)
+	if err != nil {
+		return nil, err
+	}
+	if err := database.RepoKVPs(r.db).Delete(ctx, repoID, args.Key); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+// maxBulkRepoKeyValuePairRepos is the maximum number of repositories a key-value pair
+// can be added to or deleted from in one bulk mutation.
+const maxBulkRepoKeyValuePairRepos = 1000
+
+func (r *schemaResolver) BulkAddRepoKeyValuePair(ctx context.Context, args *struct {
+	Repos []graphql.ID
+	Key   string
+	Value *string
+}) (*EmptyResponse, error) {
+	repoIDs, err := r.checkBulkRepoKeyValuePairArgs(ctx, args.Repos, args.Key)
+	if err != nil {
+		return nil, err
+	}
+	if err := database.RepoKVPs(r.db).BulkUpsert(ctx, repoIDs, args.Key, args.Value); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+func (r *schemaResolver) BulkDeleteRepoKeyValuePair(ctx context.Context, args *struct {
+	Repos []graphql.ID
+	Key   string
+}) (*EmptyResponse, error) {
+	repoIDs, err := r.checkBulkRepoKeyValuePairArgs(ctx, args.Repos, args.Key)
+	if err != nil {
+		return nil, err
+	}
+	if err := database.RepoKVPs(r.db).BulkDelete(ctx, repoIDs, args.Key); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+// checkRepoKeyValuePairArgs checks that the current user may modify the metadata of the
+// repository and that the key is valid, and returns the ID of the repository.
+func (r *schemaResolver) checkRepoKeyValuePairArgs(ctx context.Context, repo graphql.ID, key string) (api.RepoID, error) {
+	repoIDs, err := r.checkBulkRepoKeyValuePairArgs(ctx, []graphql.ID{repo}, key)
+	if err != nil {
+		return 0, err
+	}
+	return repoIDs[0], nil
+}
+
+func (r *schemaResolver) checkBulkRepoKeyValuePairArgs(ctx context.Context, repos []graphql.ID, key string) ([]api.RepoID, error) {
+	// 🚨 SECURITY: Only site admins may modify the metadata of repositories.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+	if err := validateRepoKVPKey(key); err != nil {
+		return nil, err
+	}
+	if len(repos) > maxBulkRepoKeyValuePairRepos {
+		return nil, errors.Errorf("too many repositories, please specify %d or fewer", maxBulkRepoKeyValuePairRepos)
+	}
+	if len(repos) == 0 {
+		return nil, nil
+	}
+
+	repoIDs := make([]api.RepoID, 0, len(repos))
+	for _, id := range repos {
+		repoID, err := UnmarshalRepositoryID(id)
+		if err != nil {
+			return nil, err
+		}
+		repoIDs = append(repoIDs, repoID)
+	}
+
+	// 🚨 SECURITY: Listing the repositories checks that the current user can see them.
+	visible, err := database.Repos(r.db).ListRepoNames(ctx, database.ReposListOptions{IDs: repoIDs})
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[api.RepoID]struct{}, len(visible))
+	for _, repo := range visible {
+		found[repo.ID] = struct{}{}
+	}
+	for _, id := range repoIDs {
+		if _, ok := found[id]; !ok {
+			return nil, &database.RepoNotFoundErr{ID: id}
+		}
+	}
+	return repoIDs, nil
+}
+
+// validateRepoKVPKey returns an error if the key cannot be matched by the repo:has.meta()
+// predicate, which separates the key from the value with a colon.
+func validateRepoKVPKey(key string) error {
+	if strings.TrimSpace(key) == "" {
+		return errors.New("key must not be empty")
+	}
+	if strings.ContainsAny(key, ":()") {
+		return errors.Errorf("key %q must not contain ':', '(' or ')'", key)
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	otlog "github.com/opentracing/opentracing-go/log"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/endpoint"
@@ -33,6 +34,11 @@ type SearchArgs struct {
 	PatternType *string
 	Query       string
 
+	// RepositoriesFirst and RepositoriesAfter select a page of the repositories to search. See
+	// search.RepoPagination.
+	RepositoriesFirst *int32
+	RepositoriesAfter *string
+
 	// Stream if non-nil will stream all SearchEvents.
 	//
 	// This is how our streaming and our batch interface co-exist. When this
@@ -96,6 +102,22 @@ func NewSearchImplementer(ctx context.Context, db dbutil.DB, args *SearchArgs) (
 	}
 	tr.LazyPrintf("parsing done")
 
+	var repoPagination *search.RepoPagination
+	if args.RepositoriesFirst != nil {
+		if maxRepos := search.SearchLimits(conf.Get()).MaxRepos; *args.RepositoriesFirst < 1 || int(*args.RepositoriesFirst) > maxRepos {
+			return nil, errors.Errorf("repositoriesFirst must be between 1 and %d", maxRepos)
+		}
+		if len(plan) > 1 {
+			return nil, errors.New("repositoriesFirst is not supported for queries with or expressions")
+		}
+		repoPagination = &search.RepoPagination{Limit: int(*args.RepositoriesFirst)}
+		if args.RepositoriesAfter != nil {
+			repoPagination.Start = api.RepoName(*args.RepositoriesAfter)
+		}
+	} else if args.RepositoriesAfter != nil {
+		return nil, errors.New("repositoriesAfter requires repositoriesFirst")
+	}
+
 	defaultLimit := defaultMaxSearchResults
 	if args.Stream != nil {
 		defaultLimit = defaultMaxSearchResultsStreaming
@@ -116,7 +138,8 @@ func NewSearchImplementer(ctx context.Context, db dbutil.DB, args *SearchArgs) (
 			DefaultLimit:  defaultLimit,
 		},
 
-		stream: args.Stream,
+		stream:         args.Stream,
+		repoPagination: repoPagination,
 
 		zoekt:        search.Indexed(),
 		searcherURLs: search.SearcherURLs(),
@@ -197,6 +220,11 @@ type searchResolver struct {
 	// stream if non-nil will send all search events we receive down it.
 	stream streaming.Sender
 
+	// repoPagination if non-nil only searches a page of the repositories. nextRepo is the first
+	// repository of the next page, if any.
+	repoPagination *search.RepoPagination
+	nextRepo       api.RepoName
+
 	// Cached resolveRepositories results. We use a pointer to the mutex so that we
 	// can copy the resolver, while sharing the mutex. If we didn't use a pointer,
 	// the mutex would lead to unexpected behaviour.
@@ -27,6 +27,7 @@ type SearchContextsResolver interface {
 	CreateSearchContext(ctx context.Context, args CreateSearchContextArgs) (SearchContextResolver, error)
 	UpdateSearchContext(ctx context.Context, args UpdateSearchContextArgs) (SearchContextResolver, error)
 	DeleteSearchContext(ctx context.Context, args DeleteSearchContextArgs) (*EmptyResponse, error)
+	SetSearchContextHidden(ctx context.Context, args SetSearchContextHiddenArgs) (*EmptyResponse, error)
 
 	NodeResolvers() map[string]NodeByIDFunc
 	SearchContextsToResolvers(searchContexts []*types.SearchContext) []SearchContextResolver
@@ -38,6 +39,8 @@ type SearchContextResolver interface {
 	Description(ctx context.Context) string
 	Public(ctx context.Context) bool
 	AutoDefined(ctx context.Context) bool
+	Managed(ctx context.Context) bool
+	Hidden(ctx context.Context) bool
 	Spec() string
 	UpdatedAt(ctx context.Context) DateTime
 	Namespace(ctx context.Context) (*NamespaceResolver, error)
@@ -89,6 +92,11 @@ type DeleteSearchContextArgs struct {
 	ID graphql.ID
 }
 
+type SetSearchContextHiddenArgs struct {
+	ID     graphql.ID
+	Hidden bool
+}
+
 type SearchContextBySpecArgs struct {
 	Spec string
 }
@@ -98,10 +106,11 @@ type IsSearchContextAvailableArgs struct {
 }
 
 type ListSearchContextsArgs struct {
-	First      int32
-	After      *string
-	Query      *string
-	Namespaces []*graphql.ID
-	OrderBy    SearchContextsOrderBy
-	Descending bool
+	First         int32
+	After         *string
+	Query         *string
+	Namespaces    []*graphql.ID
+	OrderBy       SearchContextsOrderBy
+	Descending    bool
+	ExcludeHidden bool
 }
@@ -0,0 +1,110 @@
+package graphqlbackend
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+)
+
+// rankingReferenceCountsFeatureFlag enables ranking search results by the
+// reference counts of RankingSignals, on top of their default order.
+const rankingReferenceCountsFeatureFlag = "search-ranking-reference-counts"
+
+// RankingSignalProvider provides signals to rank search results by.
+type RankingSignalProvider interface {
+	// ReferenceCounts returns the number of references to the code of the given
+	// repositories, keyed by repository and then by the root directory of the
+	// referenced code within the repository. The root of the whole repository is
+	// the empty string, other roots end in a slash.
+	ReferenceCounts(ctx context.Context, repos []api.RepoID) (map[api.RepoID]map[string]int, error)
+}
+
+// RankingSignals is the provider of the signals to rank search results by. It's
+// set by the enterprise frontend, as the signals stem from code intelligence
+// data.
+var RankingSignals RankingSignalProvider
+
+// rankResults reorders the given results so that results with more references
+// come first. Results with the same number of references keep their order.
+// Ranking is best effort, so results keep their order if the signals can't be
+// fetched.
+func rankResults(ctx context.Context, provider RankingSignalProvider, results []result.Match) {
+	if provider == nil || len(results) < 2 {
+		return
+	}
+
+	seen := map[api.RepoID]struct{}{}
+	var repos []api.RepoID
+	for _, m := range results {
+		id, ok := rankingRepoID(m)
+		if !ok {
+			continue
+		}
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			repos = append(repos, id)
+		}
+	}
+	if len(repos) == 0 {
+		return
+	}
+
+	counts, err := provider.ReferenceCounts(ctx, repos)
+	if err != nil {
+		log15.Warn("Fetching reference counts to rank search results", "error", err)
+		return
+	}
+
+	scores := make([]int, len(results))
+	for i, m := range results {
+		scores[i] = referenceCount(m, counts)
+	}
+
+	// Sort the indexes rather than the results, so that scores stay
+	// associated with their results.
+	order := make([]int, len(results))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return scores[order[i]] > scores[order[j]] })
+
+	ranked := make([]result.Match, len(results))
+	for i, j := range order {
+		ranked[i] = results[j]
+	}
+	copy(results, ranked)
+}
+
+func rankingRepoID(m result.Match) (api.RepoID, bool) {
+	switch r := m.(type) {
+	case *result.RepoMatch:
+		return r.ID, true
+	case *result.FileMatch:
+		return r.Repo.ID, true
+	}
+	return 0, false
+}
+
+// referenceCount returns the number of references to the code of a match. A
+// file match counts the references to all roots containing the file, a
+// repository match counts the references to the whole repository.
+func referenceCount(m result.Match, counts map[api.RepoID]map[string]int) (count int) {
+	switch r := m.(type) {
+	case *result.RepoMatch:
+		for _, n := range counts[r.ID] {
+			count += n
+		}
+	case *result.FileMatch:
+		for root, n := range counts[r.Repo.ID] {
+			if strings.HasPrefix(r.Path, root) {
+				count += n
+			}
+		}
+	}
+	return count
+}
@@ -0,0 +1,89 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+type fakeRankingSignalProvider struct {
+	counts map[api.RepoID]map[string]int
+	err    error
+}
+
+func (p *fakeRankingSignalProvider) ReferenceCounts(_ context.Context, _ []api.RepoID) (map[api.RepoID]map[string]int, error) {
+	return p.counts, p.err
+}
+
+func TestRankResults(t *testing.T) {
+	fileMatch := func(repoID api.RepoID, path string) result.Match {
+		return &result.FileMatch{File: result.File{
+			Repo: types.RepoName{ID: repoID, Name: api.RepoName(fmt.Sprintf("repo%d", repoID))},
+			Path: path,
+		}}
+	}
+	repoMatch := func(repoID api.RepoID) result.Match {
+		return &result.RepoMatch{ID: repoID, Name: api.RepoName(fmt.Sprintf("repo%d", repoID))}
+	}
+	key := func(m result.Match) string {
+		if fm, ok := m.(*result.FileMatch); ok {
+			return string(fm.Repo.Name) + "/" + fm.Path
+		}
+		return string(m.(*result.RepoMatch).Name)
+	}
+
+	results := func() []result.Match {
+		return []result.Match{
+			repoMatch(1),
+			fileMatch(1, "README.md"),
+			fileMatch(1, "lib/util.go"),
+			fileMatch(2, "main.go"),
+			fileMatch(3, "lib/util.go"),
+		}
+	}
+
+	tests := []struct {
+		name     string
+		provider RankingSignalProvider
+		want     []string
+	}{
+		{
+			name: "no provider",
+			want: []string{"repo1", "repo1/README.md", "repo1/lib/util.go", "repo2/main.go", "repo3/lib/util.go"},
+		},
+		{
+			name:     "provider error",
+			provider: &fakeRankingSignalProvider{err: errors.New("boom")},
+			want:     []string{"repo1", "repo1/README.md", "repo1/lib/util.go", "repo2/main.go", "repo3/lib/util.go"},
+		},
+		{
+			name: "ranked by reference counts",
+			provider: &fakeRankingSignalProvider{counts: map[api.RepoID]map[string]int{
+				1: {"": 1, "lib/": 5},
+				3: {"lib/": 3},
+			}},
+			want: []string{"repo1", "repo1/lib/util.go", "repo3/lib/util.go", "repo1/README.md", "repo2/main.go"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matches := results()
+			rankResults(context.Background(), test.provider, matches)
+
+			var have []string
+			for _, m := range matches {
+				have = append(have, key(m))
+			}
+			if diff := cmp.Diff(test.want, have); diff != "" {
+				t.Fatalf("Mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -115,6 +115,10 @@ type SearchResultsResolver struct {
 	// cache for user settings. Ideally this should be set just once in the code path
 	// by an upstream resolver
 	UserSettings *schema.Settings
+
+	// nextRepo is the first repository of the next page of a search over a page of the
+	// repositories, if any.
+	nextRepo api.RepoName
 }
 
 type SearchResults struct {
@@ -196,6 +200,14 @@ func (sr *SearchResultsResolver) ElapsedMilliseconds() int32 {
 	return int32(sr.elapsed.Milliseconds())
 }
 
+func (sr *SearchResultsResolver) NextRepositoriesCursor() *string {
+	if sr.nextRepo == "" {
+		return nil
+	}
+	next := string(sr.nextRepo)
+	return &next
+}
+
 func (sr *SearchResultsResolver) DynamicFilters(ctx context.Context) []*searchFilterResolver {
 	tr, ctx := trace.New(ctx, "DynamicFilters", "", trace.Tag{Key: "resolver", Value: "SearchResultsResolver"})
 	defer func() {
@@ -521,10 +533,12 @@ func (r *searchResolver) toRepoOptions(q query.Q, opts resolveRepositoriesOpts)
 		NoArchived:        archived == query.No,
 		Visibility:        visibility,
 		CommitAfter:       commitAfter,
+		HasKVPs:           q.RepoHasKVPs(),
 		Query:             q,
 		Ranked:            true,
 		Limit:             opts.limit,
 		CacheLookup:       CacheLookup,
+		Pagination:        r.repoPagination,
 	}
 }
 
@@ -547,7 +561,8 @@ func withMode(args search.TextParameters, st query.SearchType) search.TextParame
 				return n.Negated
 			case
 				query.FieldRepoGroup,
-				query.FieldRepoHasFile:
+				query.FieldRepoHasFile,
+				query.FieldRepoHasKVP:
 				return false
 			default:
 				return true
@@ -620,6 +635,10 @@ func (r *searchResolver) toSearchInputs(q query.Q) (*search.TextParameters, []ru
 	}
 	args = withResultTypes(args, forceResultTypes)
 	args = withMode(args, r.PatternType)
+	if r.repoPagination != nil && args.Mode == search.ZoektGlobalSearch {
+		// A global search searches all indexed repositories, not only the page of repositories.
+		args.Mode = search.DefaultMode
+	}
 
 	var jobs []run.Job
 	{
@@ -1000,11 +1019,15 @@ func (r *searchResolver) resultsToResolver(results *SearchResults) *SearchResult
 	if results == nil {
 		results = &SearchResults{}
 	}
+	r.reposMu.Lock()
+	nextRepo := r.nextRepo
+	r.reposMu.Unlock()
 	return &SearchResultsResolver{
 		SearchResults: results,
 		limit:         r.MaxResults(),
 		db:            r.db,
 		UserSettings:  r.UserSettings,
+		nextRepo:      nextRepo,
 	}
 }
 
@@ -1058,6 +1081,11 @@ func (r *searchResolver) resultsRecursive(ctx context.Context, plan query.Plan)
 			r.stream = nil
 			defer func() { r.stream = orig }()
 
+			// Subqueries search all repositories, not only the page of the query.
+			origPagination := r.repoPagination
+			r.repoPagination = nil
+			defer func() { r.repoPagination = origPagination }()
+
 			r.invalidateRepoCache = true
 			plan, err := pred.Plan(q)
 			if err != nil {
@@ -1094,7 +1122,7 @@ func (r *searchResolver) resultsRecursive(ctx context.Context, plan query.Plan)
 	}
 
 	if sr != nil {
-		r.sortResults(sr.Matches)
+		r.sortResults(ctx, sr.Matches)
 	}
 	return sr, err
 }
@@ -1571,6 +1599,11 @@ func (r *searchResolver) doResults(ctx context.Context, args *search.TextParamet
 		return nil, err
 	}
 	args.Repos = resolved.RepoRevs
+	if args.RepoOptions.Pagination != nil {
+		r.reposMu.Lock()
+		r.nextRepo = resolved.Next
+		r.reposMu.Unlock()
+	}
 
 	tr.LazyPrintf("searching %d repos, %d missing", len(args.Repos), len(resolved.MissingRepoRevs))
 	if len(args.Repos) == 0 {
@@ -1747,7 +1780,7 @@ func (r *searchResolver) toSearchResults(ctx context.Context, agg *run.Aggregato
 	}
 	alert, err := ao.Done(&common)
 
-	r.sortResults(matches)
+	r.sortResults(ctx, matches)
 
 	return &SearchResults{
 		Matches: matches,
@@ -1844,12 +1877,15 @@ func compareSearchResults(left, right result.Match, exactFilePatterns map[string
 	return arepo < brepo
 }
 
-func (r *searchResolver) sortResults(results []result.Match) {
+func (r *searchResolver) sortResults(ctx context.Context, results []result.Match) {
 	var exactPatterns map[string]struct{}
 	if getBoolPtr(r.UserSettings.SearchGlobbing, false) {
 		exactPatterns = r.getExactFilePatterns()
 	}
 	sort.Slice(results, func(i, j int) bool { return compareSearchResults(results[i], results[j], exactPatterns) })
+	if featureflag.FromContext(ctx).GetBoolOr(rankingReferenceCountsFeatureFlag, false) {
+		rankResults(ctx, RankingSignals, results)
+	}
 }
 
 // getExactFilePatterns returns the set of file patterns without glob syntax.
@@ -507,6 +507,7 @@ func (r *searchResolver) showSearchContextSuggestions(ctx context.Context) ([]Se
 		database.ListSearchContextsOptions{
 			Name:              parsedSearchContextSpec.SearchContextName,
 			NamespaceName:     parsedSearchContextSpec.NamespaceName,
+			ExcludeHidden:     true,
 			OrderBy:           database.SearchContextsOrderBySpec,
 			OrderByDescending: true,
 		},
@@ -0,0 +1,161 @@
+package graphqlbackend
+
+import (
+	"context"
+	"sync"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+func (r *siteResolver) SecurityEventLogs(ctx context.Context, args *struct {
+	graphqlutil.ConnectionArgs
+	User   *graphql.ID
+	Names  *[]string
+	Source *string
+	Since  *DateTime
+	Until  *DateTime
+}) (*securityEventLogConnectionResolver, error) {
+	// 🚨 SECURITY: Only site admins can view the audit log.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	var opt database.SecurityEventLogsListOptions
+	args.ConnectionArgs.Set(&opt.LimitOffset)
+	if args.User != nil {
+		userID, err := UnmarshalUserID(*args.User)
+		if err != nil {
+			return nil, err
+		}
+		opt.UserID = userID
+	}
+	if args.Names != nil {
+		for _, name := range *args.Names {
+			opt.Names = append(opt.Names, database.SecurityEventName(name))
+		}
+	}
+	if args.Source != nil {
+		opt.Source = *args.Source
+	}
+	if args.Since != nil {
+		opt.Since = &args.Since.Time
+	}
+	if args.Until != nil {
+		opt.Until = &args.Until.Time
+	}
+	return &securityEventLogConnectionResolver{db: r.db, opt: opt}, nil
+}
+
+// securityEventLogConnectionResolver resolves a list of security events.
+//
+// 🚨 SECURITY: When instantiating a securityEventLogConnectionResolver value, the caller MUST
+// check permissions.
+type securityEventLogConnectionResolver struct {
+	db  dbutil.DB
+	opt database.SecurityEventLogsListOptions
+
+	// cache results because they are used by multiple fields
+	once   sync.Once
+	events []*database.SecurityEvent
+	err    error
+}
+
+func (r *securityEventLogConnectionResolver) compute(ctx context.Context) ([]*database.SecurityEvent, error) {
+	r.once.Do(func() {
+		opt2 := r.opt
+		if opt2.LimitOffset != nil {
+			tmp := *opt2.LimitOffset
+			opt2.LimitOffset = &tmp
+			opt2.Limit++ // so we can detect if there is a next page
+		}
+
+		r.events, r.err = database.SecurityEventLogs(r.db).List(ctx, opt2)
+	})
+	return r.events, r.err
+}
+
+func (r *securityEventLogConnectionResolver) Nodes(ctx context.Context) ([]*securityEventLogResolver, error) {
+	events, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if r.opt.LimitOffset != nil && len(events) > r.opt.LimitOffset.Limit {
+		events = events[:r.opt.LimitOffset.Limit]
+	}
+
+	l := make([]*securityEventLogResolver, 0, len(events))
+	for _, event := range events {
+		l = append(l, &securityEventLogResolver{db: r.db, event: event})
+	}
+	return l, nil
+}
+
+func (r *securityEventLogConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	count, err := database.SecurityEventLogs(r.db).Count(ctx, r.opt)
+	return int32(count), err
+}
+
+func (r *securityEventLogConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	events, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return graphqlutil.HasNextPage(r.opt.LimitOffset != nil && len(events) > r.opt.Limit), nil
+}
+
+type securityEventLogResolver struct {
+	db    dbutil.DB
+	event *database.SecurityEvent
+}
+
+func (r *securityEventLogResolver) Name() string {
+	return string(r.event.Name)
+}
+
+func (r *securityEventLogResolver) User(ctx context.Context) (*UserResolver, error) {
+	if r.event.UserID == 0 {
+		return nil, nil
+	}
+	user, err := UserByIDInt32(ctx, r.db, int32(r.event.UserID))
+	if err != nil && errcode.IsNotFound(err) {
+		// Don't throw an error if a user has been deleted.
+		return nil, nil
+	}
+	return user, err
+}
+
+func (r *securityEventLogResolver) AnonymousUserID() string {
+	return r.event.AnonymousUserID
+}
+
+func (r *securityEventLogResolver) URL() string {
+	// 🚨 SECURITY: It is important to sanitize event URL before responding to the
+	// client to prevent malicious data being rendered in browser.
+	return database.SanitizeEventURL(r.event.URL)
+}
+
+func (r *securityEventLogResolver) Source() string {
+	return r.event.Source
+}
+
+func (r *securityEventLogResolver) Argument() *string {
+	if len(r.event.Argument) == 0 {
+		return nil
+	}
+	argument := string(r.event.Argument)
+	return &argument
+}
+
+func (r *securityEventLogResolver) Version() string {
+	return r.event.Version
+}
+
+func (r *securityEventLogResolver) Timestamp() DateTime {
+	return DateTime{Time: r.event.Timestamp}
+}
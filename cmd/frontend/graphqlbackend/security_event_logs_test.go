@@ -0,0 +1,110 @@
+package graphqlbackend
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestSiteSecurityEventLogs(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	since := time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC)
+	timestamp := time.Date(2021, 9, 2, 0, 0, 0, 0, time.UTC)
+	database.Mocks.SecurityEventLogs.List = func(ctx context.Context, opt database.SecurityEventLogsListOptions) ([]*database.SecurityEvent, error) {
+		want := database.SecurityEventLogsListOptions{
+			UserID:      2,
+			Names:       []database.SecurityEventName{database.SecurityEventNameSignInFailed},
+			Since:       &since,
+			LimitOffset: &database.LimitOffset{Limit: 2},
+		}
+		if diff := cmp.Diff(want, opt); diff != "" {
+			t.Errorf("unexpected options (-want +got):\n%s", diff)
+		}
+		return []*database.SecurityEvent{{
+			ID:        1,
+			Name:      database.SecurityEventNameSignInFailed,
+			URL:       "https://sourcegraph.example.com/sign-in",
+			UserID:    2,
+			Source:    "BACKEND",
+			Argument:  json.RawMessage(`{"reason":"password"}`),
+			Version:   "3.32.0",
+			Timestamp: timestamp,
+		}}, nil
+	}
+	database.Mocks.SecurityEventLogs.Count = func(ctx context.Context, opt database.SecurityEventLogsListOptions) (int, error) {
+		return 1, nil
+	}
+	database.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id, Username: "alice"}, nil
+	}
+
+	query := `
+		{
+			site {
+				securityEventLogs(first: 1, user: "VXNlcjoy", names: ["SignInFailed"], since: "2021-09-01T00:00:00Z") {
+					nodes {
+						name
+						user { username }
+						url
+						source
+						argument
+						version
+						timestamp
+					}
+					totalCount
+					pageInfo { hasNextPage }
+				}
+			}
+		}
+	`
+
+	t.Run("site admin", func(t *testing.T) {
+		database.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+			return &types.User{ID: 1, SiteAdmin: true}, nil
+		}
+		RunTest(t, &Test{
+			Context: actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+			Schema:  mustParseGraphQLSchema(t),
+			Query:   query,
+			ExpectedResult: `
+				{
+					"site": {
+						"securityEventLogs": {
+							"nodes": [{
+								"name": "SignInFailed",
+								"user": { "username": "alice" },
+								"url": "https://sourcegraph.example.com/sign-in",
+								"source": "BACKEND",
+								"argument": "{\"reason\":\"password\"}",
+								"version": "3.32.0",
+								"timestamp": "2021-09-02T00:00:00Z"
+							}],
+							"totalCount": 1,
+							"pageInfo": { "hasNextPage": false }
+						}
+					}
+				}
+			`,
+		})
+	})
+
+	// 🚨 SECURITY: Only site admins can view the audit log.
+	t.Run("non site admin", func(t *testing.T) {
+		database.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+			return &types.User{ID: 1}, nil
+		}
+		result := mustParseGraphQLSchema(t).Exec(actor.WithActor(context.Background(), &actor.Actor{UID: 1}), query, "", nil)
+		if len(result.Errors) == 0 {
+			t.Fatal("got no error, want one")
+		}
+	})
+}
@@ -30,8 +30,8 @@ func (r *schemaResolver) SetExternalServiceRepos(ctx context.Context, args struc
 		return nil, err
 	}
 
-	// 🚨 SECURITY: make sure the user has access to external service
-	if err := backend.CheckExternalServiceAccess(ctx, r.db, es.NamespaceUserID, es.NamespaceOrgID); err != nil {
+	// 🚨 SECURITY: make sure the user can modify the external service
+	if err := backend.CheckExternalServiceWriteAccess(ctx, r.db, es.NamespaceUserID, es.NamespaceOrgID); err != nil {
 		return nil, err
 	}
 
@@ -59,8 +59,11 @@ func (o *settingsResolver) Author(ctx context.Context) (*UserResolver, error) {
 var globalSettingsAllowEdits, _ = strconv.ParseBool(env.Get("GLOBAL_SETTINGS_ALLOW_EDITS", "false", "When GLOBAL_SETTINGS_FILE is in use, allow edits in the application to be made which will be overwritten on next process restart"))
 
 // like database.Settings.CreateIfUpToDate, except it handles notifying the
-// query-runner if any saved queries have changed.
-func settingsCreateIfUpToDate(ctx context.Context, db dbutil.DB, subject *settingsSubject, lastID *int32, authorUserID int32, contents string) (latestSetting *api.Settings, err error) {
+// query-runner if any saved queries have changed. If force is true, settings
+// that do not conform to the settings schema are saved.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin if force is true.
+func settingsCreateIfUpToDate(ctx context.Context, db dbutil.DB, subject *settingsSubject, lastID *int32, authorUserID int32, contents string, force bool) (latestSetting *api.Settings, err error) {
 	if os.Getenv("GLOBAL_SETTINGS_FILE") != "" && subject.site != nil && !globalSettingsAllowEdits {
 		return nil, errors.New("Updating global settings not allowed when using GLOBAL_SETTINGS_FILE")
 	}
@@ -72,7 +75,12 @@ func settingsCreateIfUpToDate(ctx context.Context, db dbutil.DB, subject *settin
 	}
 
 	// Update settings.
-	latestSettings, err := database.Settings(db).CreateIfUpToDate(ctx, subject.toSubject(), lastID, &authorUserID, contents)
+	store := database.Settings(db)
+	createIfUpToDate := store.CreateIfUpToDate
+	if force {
+		createIfUpToDate = store.ForceCreateIfUpToDate
+	}
+	latestSettings, err := createIfUpToDate(ctx, subject.toSubject(), lastID, &authorUserID, contents)
 	if err != nil {
 		return nil, err
 	}
@@ -9,6 +9,7 @@ import (
 	"github.com/graph-gophers/graphql-go"
 	"github.com/sourcegraph/jsonx"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
@@ -163,8 +164,16 @@ func (r *settingsMutation) editSettings(ctx context.Context, keyPath jsonx.Path,
 
 func (r *settingsMutation) OverwriteSettings(ctx context.Context, args *struct {
 	Contents string
+	Force    bool
 }) (*updateSettingsPayload, error) {
-	_, err := settingsCreateIfUpToDate(ctx, r.db, r.subject, r.input.LastID, actor.FromContext(ctx).UID, args.Contents)
+	// 🚨 SECURITY: Only site admins may save settings that do not conform to the settings schema.
+	if args.Force {
+		if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err := settingsCreateIfUpToDate(ctx, r.db, r.subject, r.input.LastID, actor.FromContext(ctx).UID, args.Contents, args.Force)
 	if err != nil {
 		return nil, err
 	}
@@ -188,7 +197,7 @@ func (r *settingsMutation) doUpdateSettings(ctx context.Context, computeEdits fu
 	}
 
 	// Write mutated settings.
-	updatedSettings, err := settingsCreateIfUpToDate(ctx, r.db, r.subject, r.input.LastID, actor.FromContext(ctx).UID, newSettings)
+	updatedSettings, err := settingsCreateIfUpToDate(ctx, r.db, r.subject, r.input.LastID, actor.FromContext(ctx).UID, newSettings, false)
 	if err != nil {
 		return 0, err
 	}
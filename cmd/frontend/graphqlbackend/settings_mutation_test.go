@@ -4,6 +4,9 @@ import (
 	"context"
 	"testing"
 
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database"
@@ -105,5 +108,36 @@ func TestSettingsMutation_OverwriteSettings(t *testing.T) {
 				}
 			`,
 		},
+		{
+			// Only site admins can force settings.
+			Context: actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+			Schema:  mustParseGraphQLSchema(t),
+			Query: `
+				mutation($contents: String!) {
+					settingsMutation(input: {subject: "VXNlcjox", lastID: 1}) {
+						overwriteSettings(contents: $contents, force: true) {
+							empty {
+								alwaysNil
+							}
+						}
+					}
+				}
+			`,
+			Variables: map[string]interface{}{"contents": "x"},
+			ExpectedErrors: []*gqlerrors.QueryError{
+				{
+					Path:          []interface{}{"settingsMutation", "overwriteSettings"},
+					Message:       backend.ErrMustBeSiteAdmin.Error(),
+					ResolverError: backend.ErrMustBeSiteAdmin,
+				},
+			},
+			ExpectedResult: `
+				{
+					"settingsMutation": {
+						"overwriteSettings": null
+					}
+				}
+			`,
+		},
 	})
 }
@@ -40,7 +40,7 @@ func (r *schemaResolver) DeleteUser(ctx context.Context, args *struct {
 
 	// Collect username, verified email addresses, and external accounts to be used
 	// for revoking user permissions later, otherwise they will be removed from database
-	// if it's a hard delete.
+	// once the user is deleted.
 	user, err := database.Users(r.db).GetByID(ctx, userID)
 	if err != nil {
 		return nil, errors.Wrap(err, "get user by ID")
@@ -78,7 +78,9 @@ func (r *schemaResolver) DeleteUser(ctx context.Context, args *struct {
 	})
 
 	if args.Hard != nil && *args.Hard {
-		if err := database.Users(r.db).HardDelete(ctx, user.ID); err != nil {
+		// The user and the resources it owns are permanently deleted asynchronously, by the
+		// routine purging deleted users.
+		if err := database.Users(r.db).DeleteWithGracePeriod(ctx, user.ID, 0); err != nil {
 			return nil, err
 		}
 	} else {
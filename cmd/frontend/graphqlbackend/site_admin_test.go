@@ -3,6 +3,7 @@ package graphqlbackend
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/google/go-cmp/cmp"
@@ -71,7 +72,10 @@ func TestDeleteUser(t *testing.T) {
 	database.Mocks.Users.Delete = func(context.Context, int32) error {
 		return nil
 	}
-	database.Mocks.Users.HardDelete = func(context.Context, int32) error {
+	database.Mocks.Users.DeleteWithGracePeriod = func(_ context.Context, _ int32, gracePeriod time.Duration) error {
+		if gracePeriod != 0 {
+			return errors.Errorf("gracePeriod: want 0 but got %v", gracePeriod)
+		}
 		return nil
 	}
 	database.Mocks.UserEmails.ListByUser = func(context.Context, database.UserEmailsListOptions) ([]*database.UserEmail, error) {
@@ -282,6 +282,19 @@ func (r *UserResolver) SurveyResponses(ctx context.Context) ([]*surveyResponseRe
 	return surveyResponseResolvers, nil
 }
 
+func (r *UserResolver) DataExport(ctx context.Context) (JSONValue, error) {
+	// 🚨 SECURITY: Only the user and admins are allowed to export the user's data.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, r.db, r.user.ID); err != nil {
+		return JSONValue{}, err
+	}
+
+	export, err := database.ExportUserData(ctx, r.db, r.user.ID)
+	if err != nil {
+		return JSONValue{}, err
+	}
+	return JSONValue{Value: export}, nil
+}
+
 func (r *UserResolver) ViewerCanAdminister(ctx context.Context) (bool, error) {
 	if err := backend.CheckSiteAdminOrSameUser(ctx, r.db, r.user.ID); errcode.IsUnauthorized(err) {
 		return false, nil
@@ -301,6 +314,10 @@ func (r *UserResolver) PermissionsInfo(ctx context.Context) (PermissionsInfoReso
 	return EnterpriseResolvers.authzResolver.UserPermissionsInfo(ctx, r.ID())
 }
 
+func (r *UserResolver) PermissionsSyncState(ctx context.Context) (PermissionsSyncStateResolver, error) {
+	return EnterpriseResolvers.authzResolver.UserPermissionsSyncState(ctx, r.ID())
+}
+
 func (r *schemaResolver) UpdatePassword(ctx context.Context, args *struct {
 	OldPassword string
 	NewPassword string
@@ -0,0 +1,216 @@
+package graphqlbackend
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/webhooks"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+func marshalWebhookLogID(id int64) graphql.ID { return relay.MarshalID("WebhookLog", id) }
+
+func unmarshalWebhookLogID(id graphql.ID) (webhookLogID int64, err error) {
+	err = relay.UnmarshalSpec(id, &webhookLogID)
+	return
+}
+
+func (r *schemaResolver) WebhookLogs(ctx context.Context, args *struct {
+	graphqlutil.ConnectionArgs
+	ExternalService *graphql.ID
+	OnlyErrors      bool
+}) (*webhookLogConnectionResolver, error) {
+	// 🚨 SECURITY: Only site admins may read webhook payloads, which can contain
+	// private code host data.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	opt := database.WebhookLogsListOptions{OnlyErrors: args.OnlyErrors}
+	if args.ExternalService != nil {
+		id, err := unmarshalExternalServiceID(*args.ExternalService)
+		if err != nil {
+			return nil, err
+		}
+		opt.ExternalServiceID = id
+	}
+	args.ConnectionArgs.Set(&opt.LimitOffset)
+	return &webhookLogConnectionResolver{db: r.db, opt: opt}, nil
+}
+
+func (r *schemaResolver) ReplayWebhook(ctx context.Context, args *struct {
+	WebhookLog graphql.ID
+}) (*webhookLogResolver, error) {
+	// 🚨 SECURITY: Only site admins may replay webhooks.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	id, err := unmarshalWebhookLogID(args.WebhookLog)
+	if err != nil {
+		return nil, err
+	}
+	if webhooks.DefaultRouter == nil {
+		return nil, errors.New("webhook router is not initialized")
+	}
+	replayed, err := webhooks.DefaultRouter.Replay(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &webhookLogResolver{db: r.db, log: replayed}, nil
+}
+
+// webhookLogConnectionResolver resolves a list of webhook logs.
+//
+// 🚨 SECURITY: When instantiating a webhookLogConnectionResolver value, the caller
+// MUST check that the actor is a site admin.
+type webhookLogConnectionResolver struct {
+	opt database.WebhookLogsListOptions
+
+	// cache results because they are used by multiple fields
+	once sync.Once
+	logs []*database.WebhookLog
+	err  error
+	db   dbutil.DB
+}
+
+func (r *webhookLogConnectionResolver) compute(ctx context.Context) ([]*database.WebhookLog, error) {
+	r.once.Do(func() {
+		opt2 := r.opt
+		if opt2.LimitOffset != nil {
+			tmp := *opt2.LimitOffset
+			opt2.LimitOffset = &tmp
+			opt2.Limit++ // so we can detect if there is a next page
+		}
+
+		r.logs, r.err = database.WebhookLogs(r.db).List(ctx, opt2)
+	})
+	return r.logs, r.err
+}
+
+func (r *webhookLogConnectionResolver) Nodes(ctx context.Context) ([]*webhookLogResolver, error) {
+	logs, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if r.opt.LimitOffset != nil && len(logs) > r.opt.LimitOffset.Limit {
+		logs = logs[:r.opt.LimitOffset.Limit]
+	}
+
+	l := make([]*webhookLogResolver, 0, len(logs))
+	for _, log := range logs {
+		l = append(l, &webhookLogResolver{db: r.db, log: log})
+	}
+	return l, nil
+}
+
+func (r *webhookLogConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	count, err := database.WebhookLogs(r.db).Count(ctx, r.opt)
+	return int32(count), err
+}
+
+func (r *webhookLogConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	logs, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return graphqlutil.HasNextPage(r.opt.LimitOffset != nil && len(logs) > r.opt.Limit), nil
+}
+
+type webhookLogResolver struct {
+	db  dbutil.DB
+	log *database.WebhookLog
+}
+
+func (r *webhookLogResolver) ID() graphql.ID { return marshalWebhookLogID(r.log.ID) }
+
+func (r *webhookLogResolver) ReceivedAt() DateTime { return DateTime{Time: r.log.ReceivedAt} }
+
+func (r *webhookLogResolver) ExternalService(ctx context.Context) (*externalServiceResolver, error) {
+	if r.log.ExternalServiceID == 0 {
+		return nil, nil
+	}
+	es, err := database.ExternalServices(r.db).GetByID(ctx, r.log.ExternalServiceID)
+	if err != nil {
+		return nil, err
+	}
+	return &externalServiceResolver{db: r.db, externalService: es}, nil
+}
+
+func (r *webhookLogResolver) Kind() string { return r.log.Kind }
+
+func (r *webhookLogResolver) StatusCode() int32 { return int32(r.log.StatusCode) }
+
+func (r *webhookLogResolver) Request() *webhookLogMessageResolver {
+	return &webhookLogMessageResolver{message: r.log.Request}
+}
+
+func (r *webhookLogResolver) Response() *webhookLogMessageResolver {
+	return &webhookLogMessageResolver{message: r.log.Response}
+}
+
+func (r *webhookLogResolver) ReplayOf(ctx context.Context) (*webhookLogResolver, error) {
+	if r.log.ReplayOf == 0 {
+		return nil, nil
+	}
+	log, err := database.WebhookLogs(r.db).GetByID(ctx, r.log.ReplayOf)
+	if err != nil {
+		if err == database.ErrWebhookLogNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &webhookLogResolver{db: r.db, log: log}, nil
+}
+
+type webhookLogMessageResolver struct {
+	message database.WebhookLogMessage
+}
+
+func (r *webhookLogMessageResolver) Method() *string {
+	if r.message.Method == "" {
+		return nil
+	}
+	return &r.message.Method
+}
+
+func (r *webhookLogMessageResolver) URL() *string {
+	if r.message.URL == "" {
+		return nil
+	}
+	return &r.message.URL
+}
+
+func (r *webhookLogMessageResolver) Headers() []*webhookLogHeaderResolver {
+	names := make([]string, 0, len(r.message.Header))
+	for name := range r.message.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	headers := make([]*webhookLogHeaderResolver, 0, len(names))
+	for _, name := range names {
+		headers = append(headers, &webhookLogHeaderResolver{name: name, header: r.message.Header})
+	}
+	return headers
+}
+
+func (r *webhookLogMessageResolver) Body() string { return string(r.message.Body) }
+
+type webhookLogHeaderResolver struct {
+	name   string
+	header http.Header
+}
+
+func (r *webhookLogHeaderResolver) Name() string { return r.name }
+
+func (r *webhookLogHeaderResolver) Values() []string { return r.header[r.name] }
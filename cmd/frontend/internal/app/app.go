@@ -12,9 +12,11 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/app/errorutil"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/app/router"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/app/sitemap"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/app/ui"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/session"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 )
 
@@ -34,7 +36,12 @@ func NewHandler(db dbutil.DB) http.Handler {
 	m.Handle("/", r)
 
 	r.Get(router.RobotsTxt).Handler(trace.Route(http.HandlerFunc(robotsTxt)))
-	r.Get(router.SitemapXmlGz).Handler(trace.Route(http.HandlerFunc(sitemapXmlGz)))
+	var sitemapGenerator *sitemap.Generator
+	if generateSitemap() {
+		sitemapGenerator = sitemap.NewGenerator(db)
+		goroutine.Go(sitemapGenerator.Start)
+	}
+	r.Get(router.SitemapXmlGz).Handler(trace.Route(sitemapXmlGz(sitemapGenerator)))
 	r.Get(router.Favicon).Handler(trace.Route(http.HandlerFunc(favicon)))
 	r.Get(router.OpenSearch).Handler(trace.Route(http.HandlerFunc(openSearch)))
 
@@ -61,10 +68,20 @@ func NewHandler(db dbutil.DB) http.Handler {
 	r.Get(router.SignOut).Handler(trace.Route(http.HandlerFunc(serveSignOutHandler(db))))
 	r.Get(router.ResetPasswordInit).Handler(trace.Route(http.HandlerFunc(userpasswd.HandleResetPasswordInit(db))))
 	r.Get(router.ResetPasswordCode).Handler(trace.Route(http.HandlerFunc(userpasswd.HandleResetPasswordCode(db))))
+	r.Get(router.SignInLinkInit).Handler(trace.Route(http.HandlerFunc(userpasswd.HandleSignInLinkInit(db))))
+	r.Get(router.SignInLink).Handler(trace.Route(http.HandlerFunc(userpasswd.HandleSignInLink(db))))
+	r.Get(router.SecurityKeySignInInit).Handler(trace.Route(http.HandlerFunc(userpasswd.HandleSecurityKeySignInInit(db))))
+	r.Get(router.SecurityKeySignIn).Handler(trace.Route(http.HandlerFunc(userpasswd.HandleSecurityKeySignIn(db))))
+	r.Get(router.SecurityKeyRegisterInit).Handler(trace.Route(http.HandlerFunc(userpasswd.HandleSecurityKeyRegisterInit(db))))
+	r.Get(router.SecurityKeyRegister).Handler(trace.Route(http.HandlerFunc(userpasswd.HandleSecurityKeyRegister(db))))
+	r.Get(router.SecurityKeyDelete).Handler(trace.Route(http.HandlerFunc(userpasswd.HandleSecurityKeyDelete(db))))
 	r.Get(router.VerifyEmail).Handler(trace.Route(http.HandlerFunc(serveVerifyEmail(db))))
+	r.Get(router.ResendVerificationEmail).Handler(trace.Route(http.HandlerFunc(serveResendVerificationEmail(db))))
 
 	r.Get(router.CheckUsernameTaken).Handler(trace.Route(http.HandlerFunc(userpasswd.HandleCheckUsernameTaken(db))))
 
+	r.Get(router.SignRawURL).Handler(trace.Route(http.HandlerFunc(serveSignRawURL)))
+
 	r.Get(router.RegistryExtensionBundle).Handler(trace.Route(gziphandler.GzipHandler(http.HandlerFunc(registry.HandleRegistryExtensionBundle))))
 
 	// Usage statistics ZIP download
@@ -89,5 +106,5 @@ func NewHandler(db dbutil.DB) http.Handler {
 		m.Handle(p, rickRoll)
 	}
 
-	return m
+	return maintenanceModeMiddleware(m)
 }
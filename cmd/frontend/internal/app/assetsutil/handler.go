@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/url"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/shurcooL/httpgzip"
@@ -55,7 +56,7 @@ func Mount(mux *http.ServeMux) {
 			if isPhabricatorAsset(r.URL.Path) {
 				w.Header().Set("Cache-Control", "max-age=300, public")
 			} else {
-				w.Header().Set("Cache-Control", "immutable, max-age=172800, public")
+				w.Header().Set("Cache-Control", "immutable, max-age="+strconv.Itoa(assetsMaxAge())+", public")
 			}
 		}
 
@@ -73,6 +74,18 @@ func init() {
 	}
 }
 
+// defaultAssetsMaxAge is the number of seconds assets may be cached if "ui.cacheControl" does not
+// set "assetsMaxAge" (2 days).
+const defaultAssetsMaxAge = 172800
+
+// assetsMaxAge returns the number of seconds (immutable) assets may be cached.
+func assetsMaxAge() int {
+	if c := conf.Get().UiCacheControl; c != nil && c.AssetsMaxAge > 0 {
+		return c.AssetsMaxAge
+	}
+	return defaultAssetsMaxAge
+}
+
 func isPhabricatorAsset(path string) bool {
 	return strings.Contains(path, "phabricator.bundle.js")
 }
@@ -92,6 +92,10 @@ type JSContext struct {
 	ProductResearchPageEnabled bool `json:"productResearchPageEnabled"`
 
 	ExperimentalFeatures schema.ExperimentalFeatures `json:"experimentalFeatures"`
+
+	// MaintenanceModeMessage is the message shown to users while the site is in maintenance mode
+	// and read-only, or empty if it is not.
+	MaintenanceModeMessage string `json:"maintenanceModeMessage,omitempty"`
 }
 
 // NewJSContextFromRequest populates a JSContext struct from the HTTP
@@ -139,6 +143,8 @@ func NewJSContextFromRequest(req *http.Request) JSContext {
 		sentryDSN = &siteConfig.Log.Sentry.Dsn
 	}
 
+	maintenanceModeMessage, _ := conf.MaintenanceMode()
+
 	// 🚨 SECURITY: This struct is sent to all users regardless of whether or
 	// not they are logged in, for example on an auth.public=false private
 	// server. Including secret fields here is OK if it is based on the user's
@@ -190,6 +196,8 @@ func NewJSContextFromRequest(req *http.Request) JSContext {
 		ProductResearchPageEnabled: conf.ProductResearchPageEnabled(),
 
 		ExperimentalFeatures: conf.ExperimentalFeatures(),
+
+		MaintenanceModeMessage: maintenanceModeMessage,
 	}
 }
 
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/app/router"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/app/ui"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+)
+
+// maintenanceModeMiddleware rejects requests that change data (e.g. signing up or resetting a
+// password) with 503 Service Unavailable while the site is in maintenance mode ("maintenanceMode").
+// Pages can still be browsed.
+func maintenanceModeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if message, enabled := conf.MaintenanceMode(); enabled && !isReadOnlyRequest(r) {
+			ui.ServeMaintenanceModeError(w, r, message)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isReadOnlyRequest reports whether the request can be served in maintenance mode.
+func isReadOnlyRequest(r *http.Request) bool {
+	switch r.Method {
+	case "GET", "HEAD", "OPTIONS":
+		return true
+	}
+
+	// Signing in is needed to browse private instances.
+	var m mux.RouteMatch
+	if !router.Router().Match(r, &m) {
+		return false
+	}
+	name := m.Route.GetName()
+	switch name {
+	case router.SignIn, router.SignInLinkInit, router.SignInLink, router.SecurityKeySignInInit, router.SecurityKeySignIn:
+		return true
+	}
+	return false
+}
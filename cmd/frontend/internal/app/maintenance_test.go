@@ -0,0 +1,52 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestMaintenanceModeMiddleware(t *testing.T) {
+	h := maintenanceModeMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		return rec
+	}
+
+	if rec := serve("POST", "/-/sign-up"); rec.Code != http.StatusOK {
+		t.Errorf("want requests to be served when not in maintenance mode, got status %d", rec.Code)
+	}
+
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		MaintenanceMode: &schema.MaintenanceMode{Enabled: true, Message: "Back at 10:00 UTC"},
+	}})
+	defer conf.Mock(nil)
+
+	tests := []struct {
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{method: "GET", path: "/github.com/gorilla/mux", wantStatus: http.StatusOK},
+		{method: "HEAD", path: "/github.com/gorilla/mux/-/raw/mux.go", wantStatus: http.StatusOK},
+		{method: "POST", path: "/-/sign-in", wantStatus: http.StatusOK},
+		{method: "POST", path: "/-/sign-up", wantStatus: http.StatusServiceUnavailable},
+		{method: "POST", path: "/-/reset-password-init", wantStatus: http.StatusServiceUnavailable},
+	}
+	for _, test := range tests {
+		t.Run(test.method+" "+test.path, func(t *testing.T) {
+			rec := serve(test.method, test.path)
+			if rec.Code != test.wantStatus {
+				t.Fatalf("got status %d, want %d", rec.Code, test.wantStatus)
+			}
+			if test.wantStatus == http.StatusServiceUnavailable && !strings.Contains(rec.Body.String(), "Back at 10:00 UTC") {
+				t.Errorf("want maintenance mode message in body, got:\n%s", rec.Body.String())
+			}
+		})
+	}
+}
@@ -5,13 +5,16 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/app/assetsutil"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/app/sitemap"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/env"
 )
@@ -24,6 +27,13 @@ func robotsTxt(w http.ResponseWriter, r *http.Request) {
 	robotsTxtHelper(w, allowRobots)
 }
 
+// generateSitemap reports whether the sitemap of the instance is generated by the frontend (see
+// package sitemap). The sitemap of Sourcegraph.com is generated offline instead.
+func generateSitemap() bool {
+	allowRobots, _ := strconv.ParseBool(allowRobotsVar)
+	return allowRobots && sitemap.Enabled() && !envvar.SourcegraphDotComMode()
+}
+
 func robotsTxtHelper(w io.Writer, allowRobots bool) {
 	var buf bytes.Buffer
 	fmt.Fprintln(&buf, "User-agent: *")
@@ -31,6 +41,8 @@ func robotsTxtHelper(w io.Writer, allowRobots bool) {
 		fmt.Fprintln(&buf, "Allow: /")
 		if envvar.SourcegraphDotComMode() {
 			fmt.Fprintln(&buf, "Sitemap: https://sourcegraph.com/sitemap.xml.gz")
+		} else if generateSitemap() {
+			fmt.Fprintf(&buf, "Sitemap: %s\n", globals.ExternalURL().ResolveReference(&url.URL{Path: "/sitemap.xml.gz"}))
 		}
 	} else {
 		fmt.Fprintln(&buf, "Disallow: /")
@@ -39,13 +51,35 @@ func robotsTxtHelper(w io.Writer, allowRobots bool) {
 	_, _ = buf.WriteTo(w)
 }
 
-func sitemapXmlGz(w http.ResponseWriter, r *http.Request) {
-	if envvar.SourcegraphDotComMode() || conf.DeployType() == conf.DeployDev {
-		number := mux.Vars(r)["number"]
-		http.Redirect(w, r, fmt.Sprintf("https://storage.googleapis.com/sitemap-sourcegraph-com/sitemap%s.xml.gz", number), http.StatusFound)
-		return
+func sitemapXmlGz(gen *sitemap.Generator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if envvar.SourcegraphDotComMode() || (gen == nil && conf.DeployType() == conf.DeployDev) {
+			number := mux.Vars(r)["number"]
+			http.Redirect(w, r, fmt.Sprintf("https://storage.googleapis.com/sitemap-sourcegraph-com/sitemap%s.xml.gz", number), http.StatusFound)
+			return
+		}
+		if gen == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		// The number is empty for the sitemap index, or e.g. "_001" for the first sitemap.
+		var number int
+		if n := strings.TrimPrefix(mux.Vars(r)["number"], "_"); n != "" {
+			number, _ = strconv.Atoi(n)
+			if number == 0 {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+		}
+		data, ok := gen.Sitemap(number)
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/gzip")
+		_, _ = w.Write(data)
 	}
-	w.WriteHeader(http.StatusNotFound)
 }
 
 func favicon(w http.ResponseWriter, r *http.Request) {
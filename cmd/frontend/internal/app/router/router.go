@@ -22,15 +22,25 @@ const (
 
 	Logout = "logout"
 
-	SignIn             = "sign-in"
-	SignOut            = "sign-out"
-	SignUp             = "sign-up"
-	Welcome            = "welcome"
-	SiteInit           = "site-init"
-	VerifyEmail        = "verify-email"
-	ResetPasswordInit  = "reset-password.init"
-	ResetPasswordCode  = "reset-password.code"
-	CheckUsernameTaken = "check-username-taken"
+	SignIn                  = "sign-in"
+	SignOut                 = "sign-out"
+	SignUp                  = "sign-up"
+	Welcome                 = "welcome"
+	SiteInit                = "site-init"
+	VerifyEmail             = "verify-email"
+	ResendVerificationEmail = "resend-verification-email"
+	ResetPasswordInit       = "reset-password.init"
+	ResetPasswordCode       = "reset-password.code"
+	SignInLinkInit          = "sign-in-link.init"
+	SignInLink              = "sign-in-link"
+	SecurityKeySignInInit   = "security-key.sign-in.init"
+	SecurityKeySignIn       = "security-key.sign-in"
+	SecurityKeyRegisterInit = "security-key.register.init"
+	SecurityKeyRegister     = "security-key.register"
+	SecurityKeyDelete       = "security-key.delete"
+	CheckUsernameTaken      = "check-username-taken"
+
+	SignRawURL = "sign-raw-url"
 
 	RegistryExtensionBundle = "registry.extension.bundle"
 
@@ -75,13 +85,23 @@ func newRouter() *mux.Router {
 	base.Path("/-/welcome").Methods("GET").Name(Welcome)
 	base.Path("/-/site-init").Methods("POST").Name(SiteInit)
 	base.Path("/-/verify-email").Methods("GET").Name(VerifyEmail)
+	base.Path("/-/resend-verification-email").Methods("POST").Name(ResendVerificationEmail)
 	base.Path("/-/sign-in").Methods("POST").Name(SignIn)
 	base.Path("/-/sign-out").Methods("GET").Name(SignOut)
 	base.Path("/-/reset-password-init").Methods("POST").Name(ResetPasswordInit)
 	base.Path("/-/reset-password-code").Methods("POST").Name(ResetPasswordCode)
+	base.Path("/-/sign-in-link-init").Methods("POST").Name(SignInLinkInit)
+	base.Path("/-/sign-in-link").Methods("GET", "POST").Name(SignInLink)
+	base.Path("/-/security-keys/sign-in-init").Methods("POST").Name(SecurityKeySignInInit)
+	base.Path("/-/security-keys/sign-in").Methods("POST").Name(SecurityKeySignIn)
+	base.Path("/-/security-keys/register-init").Methods("POST").Name(SecurityKeyRegisterInit)
+	base.Path("/-/security-keys/register").Methods("POST").Name(SecurityKeyRegister)
+	base.Path("/-/security-keys/delete").Methods("POST").Name(SecurityKeyDelete)
 
 	base.Path("/-/check-username-taken/{username}").Methods("GET").Name(CheckUsernameTaken)
 
+	base.Path("/-/sign-raw-url").Methods("POST").Name(SignRawURL)
+
 	base.Path("/-/static/extension/{RegistryExtensionReleaseFilename}").Methods("GET").Name(RegistryExtensionBundle)
 
 	base.Path("/-/godoc/refs").Methods("GET").Name(GDDORefs)
@@ -0,0 +1,78 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/signedurl"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+// defaultSignedURLExpiry is the duration for which a signed URL is valid if the request does not
+// specify one.
+const defaultSignedURLExpiry = 15 * time.Minute
+
+// serveSignRawURL mints a signed URL for downloading raw files or archives of a repository
+// without a session cookie or access token (see package signedurl). The URL authenticates requests
+// as the current user, but only within the requested repository, revision and path.
+func serveSignRawURL(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		http.Error(w, "Not authenticated", http.StatusUnauthorized)
+		return
+	}
+	if !signedurl.Enabled() {
+		http.Error(w, "Signed URLs are not enabled on this site", http.StatusNotFound)
+		return
+	}
+
+	var params struct {
+		Repository       string `json:"repository"`
+		Revision         string `json:"revision"`
+		Path             string `json:"path"`
+		ExpiresInSeconds int    `json:"expiresInSeconds"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		httpLogAndError(w, "Could not decode sign raw URL request body", http.StatusBadRequest, "err", err)
+		return
+	}
+
+	expiry := defaultSignedURLExpiry
+	if params.ExpiresInSeconds != 0 {
+		expiry = time.Duration(params.ExpiresInSeconds) * time.Second
+	}
+	if expiry <= 0 || expiry > signedurl.MaxExpiry {
+		http.Error(w, "Invalid expiresInSeconds", http.StatusBadRequest)
+		return
+	}
+
+	// 🚨 SECURITY: Only sign URLs for repositories that the current user can access. Access is
+	// checked again when the signed URL is used.
+	repo, err := backend.Repos.GetByName(ctx, api.RepoName(params.Repository))
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			http.Error(w, "Repository not found", http.StatusNotFound)
+			return
+		}
+		httpLogAndError(w, "Could not get repository", http.StatusInternalServerError, "repo", params.Repository, "error", err)
+		return
+	}
+
+	expiresAt := timeNow().Add(expiry)
+	scope := signedurl.Scope{Repo: repo.Name, Rev: params.Revision, PathPrefix: params.Path}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(struct {
+		URL       string    `json:"url"`
+		ExpiresAt time.Time `json:"expiresAt"`
+	}{
+		URL:       strings.TrimSuffix(globals.ExternalURL().String(), "/") + signedurl.URL(a.UID, scope, expiresAt),
+		ExpiresAt: expiresAt.UTC(),
+	})
+}
@@ -0,0 +1,29 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+)
+
+func TestServeSignRawURL(t *testing.T) {
+	serve := func(ctx context.Context) int {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/-/sign-raw-url", strings.NewReader(`{"repository":"github.com/foo/bar"}`))
+		serveSignRawURL(rec, req.WithContext(ctx))
+		return rec.Code
+	}
+
+	if got, want := serve(context.Background()), http.StatusUnauthorized; got != want {
+		t.Errorf("anonymous: got status %d, want %d", got, want)
+	}
+
+	// Signed URLs are disabled unless a signing key is configured.
+	if got, want := serve(actor.WithActor(context.Background(), actor.FromUser(1))), http.StatusNotFound; got != want {
+		t.Errorf("disabled: got status %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,218 @@
+// Package sitemap generates the sitemap of the repository, tree and blob pages that search engines
+// may index on a public Sourcegraph instance.
+//
+// Sourcegraph.com does not use this package: its sitemap is generated offline (see cmd/sitemap).
+package sitemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+	"github.com/snabb/sitemap"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+var enabledVar = env.Get("SITEMAP_ENABLED", "false", "generate a sitemap of the public repositories for search engines (requires ROBOTS_TXT_ALLOW)")
+
+// Enabled reports whether the sitemap is generated.
+func Enabled() bool {
+	enabled, _ := strconv.ParseBool(enabledVar)
+	return enabled
+}
+
+const (
+	// maxURLsPerSitemap is the maximum number of URLs in a sitemap file allowed by the sitemaps
+	// protocol.
+	maxURLsPerSitemap = 50000
+
+	// maxURLsPerRepo limits the number of tree and blob pages of a single repository, so that
+	// large repositories do not crowd out the others.
+	maxURLsPerRepo = 5000
+
+	// maxSitemaps limits the total size of the sitemap, which is kept in memory.
+	maxSitemaps = 50
+
+	reposPageSize = 500
+
+	regenerateInterval = 12 * time.Hour
+)
+
+// Generator periodically generates the sitemap. The sitemap consists of a sitemap index
+// (sitemap.xml.gz) pointing to paginated sitemaps (sitemap_001.xml.gz, sitemap_002.xml.gz, ...).
+type Generator struct {
+	db dbutil.DB
+
+	mu       sync.RWMutex
+	index    []byte   // gzipped sitemap index, nil until the sitemap is generated
+	sitemaps [][]byte // gzipped sitemaps
+}
+
+// NewGenerator returns a new sitemap generator. Call Start to generate the sitemap.
+func NewGenerator(db dbutil.DB) *Generator {
+	return &Generator{db: db}
+}
+
+// Start generates the sitemap periodically. It never returns.
+func (g *Generator) Start() {
+	for {
+		start := time.Now()
+		if err := g.generate(context.Background()); err != nil {
+			log15.Error("sitemap: failed to generate", "error", err)
+		} else {
+			log15.Debug("sitemap: generated", "duration", time.Since(start))
+		}
+		time.Sleep(regenerateInterval)
+	}
+}
+
+// Sitemap returns the gzipped sitemap with the given number, or the sitemap index if number is 0.
+// It returns false if there is no such sitemap, or if the sitemap has not been generated yet.
+func (g *Generator) Sitemap(number int) ([]byte, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if g.index == nil {
+		return nil, false
+	}
+	if number == 0 {
+		return g.index, true
+	}
+	if number < 0 || number > len(g.sitemaps) {
+		return nil, false
+	}
+	return g.sitemaps[number-1], true
+}
+
+// generate generates the sitemap and replaces the previous one.
+func (g *Generator) generate(ctx context.Context) error {
+	baseURL := globals.ExternalURL()
+
+	var (
+		sitemaps []*sitemap.Sitemap
+		sm       = sitemap.New()
+		urls     = 0
+		full     = false
+	)
+	add := func(loc string) {
+		if urls == maxURLsPerSitemap {
+			if len(sitemaps)+1 == maxSitemaps {
+				full = true
+				return
+			}
+			sitemaps = append(sitemaps, sm)
+			sm, urls = sitemap.New(), 0
+		}
+		sm.Add(&sitemap.URL{Loc: loc, ChangeFreq: sitemap.Weekly})
+		urls++
+	}
+
+	for offset := 0; !full; offset += reposPageSize {
+		// 🚨 SECURITY: The sitemap is accessible to anonymous users, so it must only include public
+		// repositories. The context has no actor, so the repositories are also filtered as for an
+		// anonymous user.
+		repos, err := database.Repos(g.db).List(ctx, database.ReposListOptions{
+			NoPrivate:   true,
+			OnlyCloned:  true,
+			LimitOffset: &database.LimitOffset{Limit: reposPageSize, Offset: offset},
+		})
+		if err != nil {
+			return errors.Wrap(err, "listing repositories")
+		}
+		for _, repo := range repos {
+			pages, err := repoPages(ctx, repo)
+			if err != nil {
+				log15.Warn("sitemap: skipping repository", "repo", repo.Name, "error", err)
+				continue
+			}
+			for _, page := range pages {
+				add(baseURL.ResolveReference(&url.URL{Path: page}).String())
+			}
+			if full {
+				log15.Warn("sitemap: maximum size reached, omitting remaining repositories", "maxSitemaps", maxSitemaps)
+				break
+			}
+		}
+		if len(repos) < reposPageSize {
+			break
+		}
+	}
+	if urls > 0 {
+		sitemaps = append(sitemaps, sm)
+	}
+
+	index := sitemap.NewSitemapIndex()
+	gzipped := make([][]byte, 0, len(sitemaps))
+	for i, sm := range sitemaps {
+		index.Add(&sitemap.URL{Loc: baseURL.ResolveReference(&url.URL{Path: fmt.Sprintf("/sitemap_%03d.xml.gz", i+1)}).String()})
+		data, err := gzipWriterTo(sm)
+		if err != nil {
+			return err
+		}
+		gzipped = append(gzipped, data)
+	}
+	indexData, err := gzipWriterTo(index)
+	if err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.index, g.sitemaps = indexData, gzipped
+	return nil
+}
+
+// repoPages returns the paths of the pages of the repository on its default branch. Pages of other
+// revisions are not indexed (see the X-Robots-Tag header set for them by the ui package).
+func repoPages(ctx context.Context, repo *types.Repo) ([]string, error) {
+	pages := []string{"/" + string(repo.Name)}
+
+	commitID, err := git.ResolveRevision(ctx, repo.Name, "HEAD", git.ResolveRevisionOptions{NoEnsureRevision: true})
+	if err != nil {
+		if errors.HasType(err, &gitdomain.RevisionNotFoundError{}) {
+			return pages, nil // empty repository
+		}
+		return nil, err
+	}
+	entries, err := git.ReadDir(ctx, repo.Name, commitID, "", true)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if len(pages) > maxURLsPerRepo {
+			break
+		}
+		kind := "blob"
+		if entry.IsDir() {
+			kind = "tree"
+		}
+		pages = append(pages, "/"+string(repo.Name)+"/-/"+kind+"/"+entry.Name())
+	}
+	return pages, nil
+}
+
+func gzipWriterTo(w io.WriterTo) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := w.WriteTo(gz); err != nil {
+		return nil, errors.Wrap(err, "writing sitemap")
+	}
+	if err := gz.Close(); err != nil {
+		return nil, errors.Wrap(err, "writing sitemap")
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,97 @@
+package sitemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/util"
+)
+
+func TestGenerator(t *testing.T) {
+	globals.SetExternalURL(&url.URL{Scheme: "https", Host: "sourcegraph.example.com"})
+
+	database.Mocks.Repos.List = func(ctx context.Context, opt database.ReposListOptions) ([]*types.Repo, error) {
+		if !opt.NoPrivate {
+			t.Error("want only public repositories to be listed")
+		}
+		if opt.Offset > 0 {
+			return nil, nil
+		}
+		return []*types.Repo{{Name: "github.com/foo/bar"}, {Name: "github.com/foo/empty"}}, nil
+	}
+	git.Mocks.ResolveRevision = func(spec string, opt git.ResolveRevisionOptions) (api.CommitID, error) {
+		return "", &gitdomain.RevisionNotFoundError{Spec: spec}
+	}
+	git.Mocks.ReadDir = func(commit api.CommitID, name string, recurse bool) ([]fs.FileInfo, error) {
+		return []fs.FileInfo{
+			&util.FileInfo{Name_: "cmd", Mode_: os.ModeDir},
+			&util.FileInfo{Name_: "cmd/main.go"},
+		}, nil
+	}
+	defer func() {
+		database.Mocks.Repos.List = nil
+		git.ResetMocks()
+	}()
+
+	g := NewGenerator(nil)
+	if _, ok := g.Sitemap(0); ok {
+		t.Fatal("want no sitemap before it is generated")
+	}
+
+	// Only the repository pages of empty repositories are included.
+	if err := g.generate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	assertSitemap(t, g, 1, "https://sourcegraph.example.com/github.com/foo/empty")
+
+	git.Mocks.ResolveRevision = func(spec string, opt git.ResolveRevisionOptions) (api.CommitID, error) {
+		return "c0ffee", nil
+	}
+	if err := g.generate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	assertSitemap(t, g, 0, "https://sourcegraph.example.com/sitemap_001.xml.gz")
+	assertSitemap(t, g, 1,
+		"https://sourcegraph.example.com/github.com/foo/bar",
+		"https://sourcegraph.example.com/github.com/foo/bar/-/tree/cmd",
+		"https://sourcegraph.example.com/github.com/foo/bar/-/blob/cmd/main.go",
+	)
+	if _, ok := g.Sitemap(2); ok {
+		t.Error("want no second sitemap")
+	}
+}
+
+func assertSitemap(t *testing.T, g *Generator, number int, wantLocs ...string) {
+	t.Helper()
+
+	data, ok := g.Sitemap(number)
+	if !ok {
+		t.Fatalf("sitemap %d not found", number)
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	xml, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, loc := range wantLocs {
+		if !strings.Contains(string(xml), "<loc>"+loc+"</loc>") {
+			t.Errorf("sitemap %d does not contain %q:\n%s", number, loc, xml)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// cachePolicy is the HTTP caching policy of a UI route, configured in the site configuration
+// ("ui.cacheControl").
+type cachePolicy int
+
+const (
+	// cachePolicyNone is the policy of most pages, which are not cached (the "Cache-Control:
+	// no-cache, max-age=0" header is set for all responses by the frontend's HTTP middleware).
+	cachePolicyNone cachePolicy = iota
+
+	// cachePolicyRepoPage is the policy of repository pages, which browsers of anonymous users may
+	// cache for a short time.
+	cachePolicyRepoPage
+)
+
+// routeCachePolicies maps UI route names to their caching policy. Routes that are not listed use
+// cachePolicyNone.
+var routeCachePolicies = map[string]cachePolicy{
+	routeRepo: cachePolicyRepoPage,
+	routeTree: cachePolicyRepoPage,
+	routeBlob: cachePolicyRepoPage,
+	routeRaw:  cachePolicyRepoPage,
+}
+
+// cacheControlMiddleware sets the Cache-Control header of the response according to the caching
+// policy of the matched route.
+func cacheControlMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var routeName string
+		if route := mux.CurrentRoute(r); route != nil {
+			routeName = route.GetName()
+		}
+		authenticated := actor.FromContext(r.Context()).IsAuthenticated()
+		if value := cacheControl(conf.Get().UiCacheControl, routeName, authenticated); value != "" {
+			w.Header().Set("Cache-Control", value)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// cacheControl returns the Cache-Control header value of a response of the route, or "" if the
+// default header must be kept.
+func cacheControl(c *schema.UiCacheControl, routeName string, authenticated bool) string {
+	if c == nil {
+		return ""
+	}
+	if authenticated {
+		// 🚨 SECURITY: Pages served to signed-in users may contain private data, so they must never
+		// be cached in shared caches.
+		if c.AuthenticatedPagesNoStore {
+			return "private, no-store"
+		}
+		return ""
+	}
+
+	switch routeCachePolicies[routeName] {
+	case cachePolicyRepoPage:
+		// Pages embed a per-session CSRF token, so they may only be cached by the browser.
+		if c.RepoPagesMaxAge > 0 {
+			return "private, max-age=" + strconv.Itoa(c.RepoPagesMaxAge)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestCacheControl(t *testing.T) {
+	config := &schema.UiCacheControl{RepoPagesMaxAge: 60, AuthenticatedPagesNoStore: true}
+
+	tests := []struct {
+		name          string
+		config        *schema.UiCacheControl
+		routeName     string
+		authenticated bool
+		want          string
+	}{
+		{name: "not configured", config: nil, routeName: routeBlob, want: ""},
+		{name: "anonymous repo page", config: config, routeName: routeBlob, want: "private, max-age=60"},
+		{name: "anonymous raw file", config: config, routeName: routeRaw, want: "private, max-age=60"},
+		{name: "anonymous other page", config: config, routeName: routeSearch, want: ""},
+		{name: "repo pages not cached", config: &schema.UiCacheControl{}, routeName: routeBlob, want: ""},
+		{name: "authenticated repo page", config: config, routeName: routeBlob, authenticated: true, want: "private, no-store"},
+		{name: "authenticated page with default policy", config: &schema.UiCacheControl{RepoPagesMaxAge: 60}, routeName: routeBlob, authenticated: true, want: ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := cacheControl(test.config, test.routeName, test.authenticated); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCacheControlMiddleware(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		UiCacheControl: &schema.UiCacheControl{RepoPagesMaxAge: 60, AuthenticatedPagesNoStore: true},
+	}})
+	defer conf.Mock(nil)
+
+	r := mux.NewRouter()
+	r.Path("/search").Name(routeSearch)
+	r.Path("/blob").Name(routeBlob)
+	r.Use(cacheControlMiddleware)
+	for _, route := range []string{routeSearch, routeBlob} {
+		r.Get(route).HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	}
+
+	serve := func(ctx context.Context, path string) string {
+		rec := httptest.NewRecorder()
+		rec.Header().Set("Cache-Control", "no-cache, max-age=0")
+		r.ServeHTTP(rec, httptest.NewRequest("GET", path, nil).WithContext(ctx))
+		return rec.Header().Get("Cache-Control")
+	}
+
+	if got, want := serve(context.Background(), "/blob"), "private, max-age=60"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := serve(context.Background(), "/search"), "no-cache, max-age=0"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := serve(actor.WithActor(context.Background(), actor.FromUser(1)), "/blob"), "private, no-store"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"net/http"
+
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// defaultSupportURL is the support link shown on error pages unless "ui.errorPages" configures one.
+const defaultSupportURL = "mailto:support@sourcegraph.com"
+
+// errorClass is a class of errors for which site admins can configure troubleshooting text in
+// "ui.errorPages".
+type errorClass int
+
+const (
+	errorClassOther errorClass = iota
+	errorClassCloneInProgress
+	errorClassUnauthorized
+	errorClassNotFound
+)
+
+// errorClassForStatus returns the class of the error served with the given HTTP status code.
+func errorClassForStatus(statusCode int) errorClass {
+	switch statusCode {
+	case http.StatusUnauthorized:
+		return errorClassUnauthorized
+	case http.StatusNotFound:
+		return errorClassNotFound
+	}
+	return errorClassOther
+}
+
+// errorHelp is the help shown to the user on an error page (or while a repository is being cloned).
+type errorHelp struct {
+	SupportURL      string `json:"supportURL"`
+	Troubleshooting string `json:"troubleshooting,omitempty"`
+}
+
+// newErrorHelp returns the help for errors of the given class, as configured in "ui.errorPages".
+func newErrorHelp(c *schema.UiErrorPages, class errorClass) *errorHelp {
+	help := &errorHelp{SupportURL: defaultSupportURL}
+	if c == nil {
+		return help
+	}
+
+	if c.SupportURL != "" {
+		help.SupportURL = c.SupportURL
+	}
+	switch class {
+	case errorClassCloneInProgress:
+		help.Troubleshooting = c.CloneInProgress
+	case errorClassUnauthorized:
+		help.Troubleshooting = c.Unauthorized
+	case errorClassNotFound:
+		help.Troubleshooting = c.NotFound
+	}
+	return help
+}
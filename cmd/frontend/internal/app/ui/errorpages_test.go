@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestNewErrorHelp(t *testing.T) {
+	config := &schema.UiErrorPages{
+		SupportURL:      "https://chat.example.com/help",
+		CloneInProgress: "Large repositories take a while to clone.",
+		Unauthorized:    "Request access on the code host.",
+		NotFound:        "Ask in #help to add the repository.",
+	}
+
+	tests := []struct {
+		name   string
+		config *schema.UiErrorPages
+		class  errorClass
+		want   *errorHelp
+	}{
+		{name: "not configured", config: nil, class: errorClassNotFound, want: &errorHelp{SupportURL: defaultSupportURL}},
+		{name: "default support URL", config: &schema.UiErrorPages{NotFound: "not found"}, class: errorClassNotFound, want: &errorHelp{SupportURL: defaultSupportURL, Troubleshooting: "not found"}},
+		{name: "clone in progress", config: config, class: errorClassCloneInProgress, want: &errorHelp{SupportURL: config.SupportURL, Troubleshooting: config.CloneInProgress}},
+		{name: "unauthorized", config: config, class: errorClassForStatus(http.StatusUnauthorized), want: &errorHelp{SupportURL: config.SupportURL, Troubleshooting: config.Unauthorized}},
+		{name: "not found", config: config, class: errorClassForStatus(http.StatusNotFound), want: &errorHelp{SupportURL: config.SupportURL, Troubleshooting: config.NotFound}},
+		{name: "other error", config: config, class: errorClassForStatus(http.StatusInternalServerError), want: &errorHelp{SupportURL: config.SupportURL}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if diff := cmp.Diff(test.want, newErrorHelp(test.config, test.class)); diff != "" {
+				t.Errorf("unexpected error help (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestErrorTemplateHelp(t *testing.T) {
+	rec := httptest.NewRecorder()
+	err := renderTemplate(context.Background(), rec, "error.html", &pageError{
+		StatusCode: http.StatusNotFound,
+		StatusText: http.StatusText(http.StatusNotFound),
+		ErrorID:    "abc123",
+		Help:       &errorHelp{SupportURL: "https://chat.example.com/help", Troubleshooting: "Ask in #help to add the repository."},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`href="https://chat.example.com/help"`, "Ask in #help to add the repository."} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("error page does not contain %q:\n%s", want, rec.Body.String())
+		}
+	}
+}
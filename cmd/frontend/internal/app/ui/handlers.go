@@ -22,6 +22,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/hubspot"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/hubspot/hubspotutil"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/app/jscontext"
@@ -30,11 +31,12 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/cookie"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
-	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/search/symbol"
 	"github.com/sourcegraph/sourcegraph/internal/types"
@@ -72,14 +74,22 @@ type Common struct {
 	Title    string
 	Error    *pageError
 
+	// ErrorHelp is the support link and troubleshooting text configured in "ui.errorPages" for
+	// the error shown on the page, if any.
+	ErrorHelp *errorHelp
+
 	Manifest *assets.WebpackManifest
 
 	WebpackDevServer bool // whether the Webpack dev server is running (WEBPACK_DEV_SERVER env var)
 
 	// The fields below have zero values when not on a repo page.
 	Repo         *types.Repo
-	Rev          string // unresolved / user-specified revision (e.x.: "@master")
-	api.CommitID        // resolved SHA1 revision
+	RepoAlias    api.RepoName // alias of the repo configured in "repoAliases", if any
+	Rev          string       // unresolved / user-specified revision (e.x.: "@master")
+	api.CommitID              // resolved SHA1 revision
+
+	// CloneInProgress is true if the repository is being cloned, in which case CommitID is empty.
+	CloneInProgress bool
 }
 
 var webpackDevServer, _ = strconv.ParseBool(os.Getenv("WEBPACK_DEV_SERVER"))
@@ -94,6 +104,15 @@ func repoShortName(name api.RepoName) string {
 	return strings.Join(split[1:], "/")
 }
 
+// repoTitleName returns the name of the repo used in page titles: the alias of the repo if
+// one is configured, since it is already short, or else the short name of the repo.
+func repoTitleName(c *Common) string {
+	if c.RepoAlias != "" {
+		return string(c.RepoAlias)
+	}
+	return repoShortName(c.Repo.Name)
+}
+
 // serveErrorHandler is a function signature used in newCommon and
 // mockNewCommon. This is used as syntactic sugar to prevent programmer's
 // (fragile creatures from planet Earth) from crashing out.
@@ -127,9 +146,14 @@ func newCommon(w http.ResponseWriter, r *http.Request, title string, indexed boo
 		return mockNewCommon(w, r, title, serveError)
 	}
 
-	manifest, err := assets.LoadWebpackManifest()
-	if err != nil {
-		return nil, errors.Wrap(err, "loading webpack manifest")
+	// The JSON representation of a page does not load the web app.
+	var manifest *assets.WebpackManifest
+	if !wantsJSON(r) {
+		var err error
+		manifest, err = assets.LoadWebpackManifest()
+		if err != nil {
+			return nil, errors.Wrap(err, "loading webpack manifest")
+		}
 	}
 
 	if !indexed {
@@ -155,10 +179,21 @@ func newCommon(w http.ResponseWriter, r *http.Request, title string, indexed boo
 		WebpackDevServer: webpackDevServer,
 	}
 
+	if _, ok := mux.Vars(r)["username"]; ok {
+		// Users that changed their username are redirected from their old username.
+		if redirected, err := handlerutil.RedirectToRenamedUser(w, r, dbconn.Global); err != nil {
+			return nil, errors.Wrap(err, "when sending renamed user redirect response")
+		} else if redirected {
+			return nil, nil
+		}
+	}
+
 	if _, ok := mux.Vars(r)["Repo"]; ok {
 		// Common repo pages (blob, tree, etc).
 		var err error
+		endStep := startPageTraceStep(r.Context(), "resolve repository and revision")
 		common.Repo, common.CommitID, err = handlerutil.GetRepoAndRev(r.Context(), mux.Vars(r))
+		endStep()
 		isRepoEmptyError := routevar.ToRepoRev(mux.Vars(r)).Rev == "" && errors.HasType(err, &gitdomain.RevisionNotFoundError{}) // should reply with HTTP 200
 		if err != nil && !isRepoEmptyError {
 			var urlMovedError *handlerutil.URLMovedError
@@ -183,6 +218,16 @@ func newCommon(w http.ResponseWriter, r *http.Request, title string, indexed boo
 				http.Redirect(w, r, u.String(), http.StatusSeeOther)
 				return nil, nil
 			}
+			if errcode.IsNotFound(err) && !errors.HasType(err, &gitdomain.RevisionNotFoundError{}) {
+				// Repository aliases, e.g. "src/foo" for "github.com/org/foo", redirect to
+				// the canonical repository name. Only names that aren't repositories
+				// themselves are redirected.
+				if redirected, err := handlerutil.RedirectToRepoAlias(w, r); err != nil {
+					return nil, errors.Wrap(err, "when sending repository alias redirect response")
+				} else if redirected {
+					return nil, nil
+				}
+			}
 			if errors.HasType(err, &gitdomain.RevisionNotFoundError{}) {
 				// Revision does not exist.
 				serveError(w, r, err, http.StatusNotFound)
@@ -202,6 +247,8 @@ func newCommon(w http.ResponseWriter, r *http.Request, title string, indexed boo
 			if gitdomain.IsRepoNotExist(err) {
 				if gitdomain.IsCloneInProgress(err) {
 					// Repo is cloning.
+					common.CloneInProgress = true
+					common.ErrorHelp = newErrorHelp(conf.Get().UiErrorPages, errorClassCloneInProgress)
 					return common, nil
 				}
 				// Repo does not exist.
@@ -209,6 +256,11 @@ func newCommon(w http.ResponseWriter, r *http.Request, title string, indexed boo
 				return nil, nil
 			}
 			if errcode.IsNotFound(err) || errcode.IsBlocked(err) {
+				if goneErr := repoGoneError(r.Context(), dbconn.Global, routevar.ToRepo(mux.Vars(r)), err); goneErr != nil {
+					// Repo was deleted or blocked.
+					dangerouslyServeError(w, r, goneErr, http.StatusGone)
+					return nil, nil
+				}
 				// Repo does not exist.
 				serveError(w, r, err, http.StatusNotFound)
 				return nil, nil
@@ -224,14 +276,9 @@ func newCommon(w http.ResponseWriter, r *http.Request, title string, indexed boo
 			return nil, errors.New("error caused by Always500Test repo name")
 		}
 		common.Rev = mux.Vars(r)["Rev"]
-		// Update gitserver contents for a repo whenever it is visited.
-		go func() {
-			ctx := context.Background()
-			_, err = repoupdater.DefaultClient.EnqueueRepoUpdate(ctx, common.Repo.Name)
-			if err != nil {
-				log15.Error("EnqueueRepoUpdate", "error", err)
-			}
-		}()
+		common.RepoAlias, _ = handlerutil.RepoAlias(common.Repo.Name)
+		// Update gitserver contents for a repo whenever it is visited (debounced and batched).
+		repoUpdates.Visit(common.Repo.Name)
 	}
 
 	// common.Repo and common.CommitID are populated in the above if statement
@@ -244,6 +291,7 @@ func newCommon(w http.ResponseWriter, r *http.Request, title string, indexed boo
 			ctx, cancel := context.WithTimeout(r.Context(), time.Second*1)
 			defer cancel()
 
+			endStep := startPageTraceStep(r.Context(), "symbol lookup")
 			if symbolMatch, _ := symbol.GetMatchAtLineCharacter(
 				ctx,
 				types.RepoName{ID: common.Repo.ID, Name: common.Repo.Name},
@@ -254,11 +302,12 @@ func newCommon(w http.ResponseWriter, r *http.Request, title string, indexed boo
 			); symbolMatch != nil {
 				symbolResult = &symbolMatch.Symbol
 			}
+			endStep()
 		}
 
 		common.Metadata.ShowPreview = true
 		common.Metadata.PreviewImage = getBlobPreviewImageURL(envvar.OpenGraphPreviewServiceURL(), r.URL.Path, lineRange)
-		common.Metadata.Description = fmt.Sprintf("%s/%s", globals.ExternalURL(), mux.Vars(r)["Repo"])
+		common.Metadata.Description = getBlobPreviewDescription(fmt.Sprintf("%s/%s", globals.ExternalURL(), mux.Vars(r)["Repo"]), symbolResult)
 		common.Metadata.Title = getBlobPreviewTitle(blobPath, lineRange, symbolResult)
 	}
 
@@ -291,10 +340,72 @@ func serveBasicPage(title func(c *Common, r *http.Request) string, description *
 			return nil // request was handled
 		}
 		common.Title = title(common, r)
-		return renderTemplate(w, "app.html", common)
+		return renderTemplate(r.Context(), w, "app.html", common)
 	}
 }
 
+// serveSearch serves the search page. On Sourcegraph.com, the results of the search are summarized
+// in the preview metadata of the page, so that shared search links get rich previews.
+func serveSearch(db dbutil.DB) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		common, err := newCommon(w, r, "", index, serveError)
+		if err != nil {
+			return err
+		}
+		if common == nil {
+			return nil // request was handled
+		}
+
+		query := r.URL.Query().Get("q")
+		if shortQuery := limitString(query, 25, true); shortQuery == "" {
+			common.Title = globals.Branding().BrandName
+		} else {
+			// e.g. "myquery - Sourcegraph"
+			common.Title = brandNameSubtitle(shortQuery)
+		}
+
+		if query != "" && envvar.OpenGraphPreviewServiceURL() != "" && envvar.SourcegraphDotComMode() {
+			patternType := r.URL.Query().Get("patternType")
+			common.Metadata.ShowPreview = true
+			common.Metadata.PreviewImage = getSearchPreviewImageURL(envvar.OpenGraphPreviewServiceURL(), query, patternType)
+			common.Metadata.Title = brandNameSubtitle(limitString(query, 60, true))
+
+			// Do not slow down the page load if the search takes too long.
+			ctx, cancel := context.WithTimeout(r.Context(), time.Second*1)
+			defer cancel()
+
+			endStep := startPageTraceStep(r.Context(), "search preview")
+			description, err := searchPreviewDescription(ctx, db, query, patternType)
+			endStep()
+			if err != nil {
+				log15.Debug("search preview", "query", query, "error", err)
+			} else {
+				common.Metadata.Description = description
+			}
+		}
+
+		return renderTemplate(r.Context(), w, "app.html", common)
+	}
+}
+
+// searchPreviewDescription runs a search and summarizes its results for the preview metadata of
+// the search page.
+func searchPreviewDescription(ctx context.Context, db dbutil.DB, query, patternType string) (string, error) {
+	args := &graphqlbackend.SearchArgs{Version: "V2", Query: query}
+	if patternType != "" {
+		args.PatternType = &patternType
+	}
+	job, err := graphqlbackend.NewSearchImplementer(ctx, db, args)
+	if err != nil {
+		return "", err
+	}
+	results, err := job.Results(ctx)
+	if err != nil {
+		return "", err
+	}
+	return getSearchPreviewDescription(results.ApproximateResultCount(), results.Matches), nil
+}
+
 func serveHome(w http.ResponseWriter, r *http.Request) error {
 	common, err := newCommon(w, r, globals.Branding().BrandName, index, serveError)
 	if err != nil {
@@ -320,7 +431,7 @@ func serveSignIn(w http.ResponseWriter, r *http.Request) error {
 	}
 	common.Title = brandNameSubtitle("Sign in")
 
-	return renderTemplate(w, "app.html", common)
+	return renderTemplate(r.Context(), w, "app.html", common)
 }
 
 // redirectTreeOrBlob redirects a blob page to a tree page if the file is actually a directory,
@@ -342,7 +453,9 @@ func redirectTreeOrBlob(routeName, path string, common *Common, w http.ResponseW
 		}
 		return false, nil
 	}
+	endStep := startPageTraceStep(r.Context(), "gitserver: stat")
 	stat, err := git.Stat(r.Context(), common.Repo.Name, common.CommitID, path)
+	endStep()
 	if err != nil {
 		if os.IsNotExist(err) {
 			serveError(w, r, err, http.StatusNotFound)
@@ -368,6 +481,9 @@ func redirectTreeOrBlob(routeName, path string, common *Common, w http.ResponseW
 // serveTree serves the tree (directory) pages.
 func serveTree(title func(c *Common, r *http.Request) string) handlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) error {
+		// The page is served as JSON when requested with the Accept header.
+		w.Header().Add("Vary", "Accept")
+
 		common, err := newCommon(w, r, "", index, serveError)
 		if err != nil {
 			return err
@@ -392,13 +508,20 @@ func serveTree(title func(c *Common, r *http.Request) string) handlerFunc {
 			return err
 		}
 
+		if wantsJSON(r) {
+			return serveRepoJSON(w, r, routeTree, common)
+		}
+
 		common.Title = title(common, r)
-		return renderTemplate(w, "app.html", common)
+		return renderTemplate(r.Context(), w, "app.html", common)
 	}
 }
 
 func serveRepoOrBlob(routeName string, title func(c *Common, r *http.Request) string) handlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) error {
+		// The page is served as JSON when requested with the Accept header.
+		w.Header().Add("Vary", "Accept")
+
 		common, err := newCommon(w, r, "", index, serveError)
 		if err != nil {
 			return err
@@ -423,6 +546,10 @@ func serveRepoOrBlob(routeName string, title func(c *Common, r *http.Request) st
 			return err
 		}
 
+		if wantsJSON(r) {
+			return serveRepoJSON(w, r, routeName, common)
+		}
+
 		common.Title = title(common, r)
 
 		q := r.URL.Query()
@@ -445,7 +572,7 @@ func serveRepoOrBlob(routeName string, title func(c *Common, r *http.Request) st
 			http.Redirect(w, r, r.URL.String(), http.StatusPermanentRedirect)
 			return nil
 		}
-		return renderTemplate(w, "app.html", common)
+		return renderTemplate(r.Context(), w, "app.html", common)
 	}
 }
 
@@ -68,7 +68,7 @@ func serveRepoDocs(codeIntelResolver graphqlbackend.CodeIntelResolver) handlerFu
 							return nil
 						}
 					}
-					title := brandNameSubtitle(fmt.Sprintf("%s - %s API docs", target.Documentation.SearchKey, repoShortName(common.Repo.Name)))
+					title := brandNameSubtitle(fmt.Sprintf("%s - %s API docs", target.Documentation.SearchKey, repoTitleName(common)))
 					common.Title = title
 					common.Metadata.ShowPreview = true
 					common.Metadata.Title = title
@@ -90,7 +90,7 @@ func serveRepoDocs(codeIntelResolver graphqlbackend.CodeIntelResolver) handlerFu
 
 		// TODO(apidocs): emit URL that points to another route capable of generating preview images for API docs.
 		//common.Metadata.PreviewImage = "https://..."
-		return renderTemplate(w, "app.html", common)
+		return renderTemplate(r.Context(), w, "app.html", common)
 	}
 }
 
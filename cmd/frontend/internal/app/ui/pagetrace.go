@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// pageTrace records how long the steps of serving a page took (resolving the repository,
+// gitserver calls, rendering the template, etc.), so that slow page loads are diagnosable. The
+// breakdown is attached to the trace of the request and shown to site admins on error pages.
+type pageTrace struct {
+	start time.Time
+
+	mu    sync.Mutex
+	steps []pageTraceStep
+}
+
+// pageTraceStep is a step of serving a page.
+type pageTraceStep struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"-"`
+
+	// DurationText is the human-readable duration, e.g. "12.3ms".
+	DurationText string `json:"duration"`
+}
+
+type pageTraceKey struct{}
+
+// withPageTrace returns a copy of the context that records the steps of serving a page.
+func withPageTrace(ctx context.Context) context.Context {
+	return context.WithValue(ctx, pageTraceKey{}, &pageTrace{start: time.Now()})
+}
+
+// pageTraceFromContext returns the page trace of the context, or nil if the context has none.
+func pageTraceFromContext(ctx context.Context) *pageTrace {
+	t, _ := ctx.Value(pageTraceKey{}).(*pageTrace)
+	return t
+}
+
+// startPageTraceStep starts a step of serving the page of the request, and returns a function
+// that ends it. It is a no-op if the context has no page trace.
+//
+//	defer startPageTraceStep(ctx, "render template")()
+func startPageTraceStep(ctx context.Context, name string) func() {
+	t := pageTraceFromContext(ctx)
+	if t == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.steps = append(t.steps, newPageTraceStep(name, time.Since(start)))
+	}
+}
+
+// breakdown returns the steps recorded so far in the order they ended, followed by a "total"
+// step with the time elapsed since the page trace was started.
+func (t *pageTrace) breakdown() []pageTraceStep {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	steps := make([]pageTraceStep, 0, len(t.steps)+1)
+	steps = append(steps, t.steps...)
+	return append(steps, newPageTraceStep("total", time.Since(t.start)))
+}
+
+func newPageTraceStep(name string, d time.Duration) pageTraceStep {
+	return pageTraceStep{Name: name, Duration: d, DurationText: d.Round(100 * time.Microsecond).String()}
+}
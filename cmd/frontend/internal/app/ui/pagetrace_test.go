@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+)
+
+func TestPageTrace(t *testing.T) {
+	// Steps are not recorded without a page trace.
+	startPageTraceStep(context.Background(), "noop")()
+
+	ctx := withPageTrace(context.Background())
+	startPageTraceStep(ctx, "resolve repository and revision")()
+	startPageTraceStep(ctx, "render template")()
+
+	var names []string
+	for _, step := range pageTraceFromContext(ctx).breakdown() {
+		names = append(names, step.Name)
+	}
+	if got, want := strings.Join(names, ","), "resolve repository and revision,render template,total"; got != want {
+		t.Errorf("got steps %q, want %q", got, want)
+	}
+}
+
+func TestServeErrorPageTrace(t *testing.T) {
+	mockNewCommon = func(w http.ResponseWriter, r *http.Request, title string, serveError serveErrorHandler) (*Common, error) {
+		return nil, errors.New("fall back to the error template")
+	}
+	defer func() { mockNewCommon = nil }()
+
+	serve := func(ctx context.Context, nodebug bool) string {
+		ctx = withPageTrace(ctx)
+		startPageTraceStep(ctx, "gitserver: stat")()
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/github.com/gorilla/mux", nil).WithContext(ctx)
+		serveErrorNoDebug(rec, req, errors.New("boom"), http.StatusInternalServerError, nodebug, false)
+		return rec.Body.String()
+	}
+
+	// Internal actors are treated as site admins.
+	if body := serve(actor.WithInternalActor(context.Background()), false); !strings.Contains(body, "gitserver: stat") {
+		t.Errorf("want request timing for site admins, got body:\n%s", body)
+	}
+	if body := serve(actor.WithInternalActor(context.Background()), true); strings.Contains(body, "gitserver: stat") {
+		t.Errorf("want no request timing with nodebug, got body:\n%s", body)
+	}
+	if body := serve(context.Background(), false); strings.Contains(body, "gitserver: stat") {
+		t.Errorf("want no request timing for anonymous users, got body:\n%s", body)
+	}
+}
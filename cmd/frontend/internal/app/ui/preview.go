@@ -105,3 +105,62 @@ func getBlobPreviewTitle(blobFilePath string, lineRange *lineRange, symbolResult
 	}
 	return formattedBlob
 }
+
+func formatSymbolSignature(symbolResult *result.Symbol) string {
+	return fmt.Sprintf("%s %s%s", symbolResult.LSPKind().String(), symbolResult.Name, symbolResult.Signature)
+}
+
+func getBlobPreviewDescription(repoURL string, symbolResult *result.Symbol) string {
+	if symbolResult != nil && symbolResult.Signature != "" {
+		return fmt.Sprintf("%s · %s", formatSymbolSignature(symbolResult), repoURL)
+	}
+	return repoURL
+}
+
+func getSearchPreviewImageURL(previewServiceURL string, query string, patternType string) string {
+	queryValues := url.Values{}
+	queryValues.Add("q", query)
+	if patternType != "" {
+		queryValues.Add("patternType", patternType)
+	}
+	return previewServiceURL + "/search?" + queryValues.Encode()
+}
+
+// maxSearchPreviewFileNames is the maximum number of file names listed in the description of a
+// search page preview.
+const maxSearchPreviewFileNames = 3
+
+// getSearchPreviewDescription summarizes the results of a search, e.g. "42 results in a.go, b.go,
+// c.go". The first symbol result of a symbol search is described by its signature instead.
+func getSearchPreviewDescription(approximateResultCount string, matches []result.Match) string {
+	if len(matches) == 0 {
+		return "No results"
+	}
+	summary := approximateResultCount + " results"
+	if approximateResultCount == "1" {
+		summary = "1 result"
+	}
+
+	var fileNames []string
+	seen := map[string]struct{}{}
+	for _, match := range matches {
+		fileMatch, ok := match.(*result.FileMatch)
+		if !ok {
+			continue
+		}
+		if len(fileMatch.Symbols) > 0 {
+			symbolResult := fileMatch.Symbols[0].Symbol
+			return fmt.Sprintf("%s, e.g. %s (%s)", summary, formatSymbolSignature(&symbolResult), path.Base(fileMatch.Path))
+		}
+		fileName := path.Base(fileMatch.Path)
+		if _, ok := seen[fileName]; ok || len(fileNames) == maxSearchPreviewFileNames {
+			continue
+		}
+		seen[fileName] = struct{}{}
+		fileNames = append(fileNames, fileName)
+	}
+	if len(fileNames) == 0 {
+		return summary
+	}
+	return fmt.Sprintf("%s in %s", summary, strings.Join(fileNames, ", "))
+}
@@ -77,3 +77,90 @@ func TestGetBlobPreviewTitle(t *testing.T) {
 		})
 	}
 }
+
+func TestGetBlobPreviewDescription(t *testing.T) {
+	repoURL := "https://sourcegraph.com/github.com/sourcegraph/sourcegraph"
+	tests := []struct {
+		name            string
+		symbolResult    *result.Symbol
+		wantDescription string
+	}{
+		{name: "no symbol", symbolResult: nil, wantDescription: repoURL},
+		{name: "symbol without signature", symbolResult: &result.Symbol{Kind: "function", Name: "myFunc"}, wantDescription: repoURL},
+		{name: "symbol with signature", symbolResult: &result.Symbol{Kind: "function", Name: "myFunc", Signature: "(a int) error"}, wantDescription: "Function myFunc(a int) error · " + repoURL},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := getBlobPreviewDescription(repoURL, test.symbolResult)
+			if got != test.wantDescription {
+				t.Errorf("got %v, want %v", got, test.wantDescription)
+			}
+		})
+	}
+}
+
+func TestGetSearchPreviewImageURL(t *testing.T) {
+	previewServiceURL := "https://preview.sourcegraph.com"
+	tests := []struct {
+		name        string
+		query       string
+		patternType string
+		wantURL     string
+	}{
+		{name: "query", query: "repo:sourcegraph foo bar", wantURL: previewServiceURL + "/search?q=repo%3Asourcegraph+foo+bar"},
+		{name: "query with pattern type", query: "foo", patternType: "regexp", wantURL: previewServiceURL + "/search?patternType=regexp&q=foo"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := getSearchPreviewImageURL(previewServiceURL, test.query, test.patternType)
+			if got != test.wantURL {
+				t.Errorf("got %v, want %v", got, test.wantURL)
+			}
+		})
+	}
+}
+
+func TestGetSearchPreviewDescription(t *testing.T) {
+	fileMatch := func(path string, symbols ...*result.SymbolMatch) *result.FileMatch {
+		return &result.FileMatch{File: result.File{Path: path}, Symbols: symbols}
+	}
+	tests := []struct {
+		name                   string
+		approximateResultCount string
+		matches                []result.Match
+		wantDescription        string
+	}{
+		{name: "no results", approximateResultCount: "0", wantDescription: "No results"},
+		{name: "single result", approximateResultCount: "1", matches: []result.Match{fileMatch("path/a.go")}, wantDescription: "1 result in a.go"},
+		{
+			name:                   "top file names",
+			approximateResultCount: "500+",
+			matches: []result.Match{
+				fileMatch("path/a.go"),
+				fileMatch("other/a.go"),
+				fileMatch("b.go"),
+				fileMatch("c.go"),
+				fileMatch("d.go"),
+			},
+			wantDescription: "500+ results in a.go, b.go, c.go",
+		},
+		{name: "repository results", approximateResultCount: "2", matches: []result.Match{&result.RepoMatch{}, &result.RepoMatch{}}, wantDescription: "2 results"},
+		{
+			name:                   "symbol results",
+			approximateResultCount: "3",
+			matches: []result.Match{
+				fileMatch("path/a.go", &result.SymbolMatch{Symbol: result.Symbol{Kind: "function", Name: "myFunc", Signature: "()"}}),
+				fileMatch("path/b.go", &result.SymbolMatch{Symbol: result.Symbol{Kind: "function", Name: "otherFunc"}}),
+			},
+			wantDescription: "3 results, e.g. Function myFunc() (a.go)",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := getSearchPreviewDescription(test.approximateResultCount, test.matches)
+			if got != test.wantDescription {
+				t.Errorf("got %v, want %v", got, test.wantDescription)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/inconshreveable/log15"
+	"github.com/throttled/throttled/v2"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/cookie"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// rateLimitWatcher stores the rate limiter of anonymous requests to expensive UI handlers,
+// configured in the site configuration ("ui.ratelimit").
+type rateLimitWatcher struct {
+	store throttled.GCRAStore
+	rl    atomic.Value // *uiRateLimiter
+}
+
+// uiRateLimits is the rate limiter of anonymous requests, set by InitRouter.
+var uiRateLimits *rateLimitWatcher
+
+// newRateLimitWatcher creates a new rate limiter with the provided store and starts watching for
+// configuration changes.
+func newRateLimitWatcher(store throttled.GCRAStore) *rateLimitWatcher {
+	w := &rateLimitWatcher{store: store}
+	conf.Watch(func() {
+		w.updateFromConfig(conf.Get().UiRatelimit)
+	})
+	return w
+}
+
+// get returns the current rate limiter, or nil if rate limiting is disabled.
+func (w *rateLimitWatcher) get() *uiRateLimiter {
+	l, _ := w.rl.Load().(*uiRateLimiter)
+	return l
+}
+
+func (w *rateLimitWatcher) updateFromConfig(rlc *schema.UiRatelimit) {
+	if rlc == nil || !rlc.Enabled {
+		w.rl.Store((*uiRateLimiter)(nil))
+		return
+	}
+
+	// We can burst up to a max of 20% of limit, like the API rate limits.
+	maxBurstPercentage := 0.2
+	newLimiter := func(perHour int) (*throttled.GCRARateLimiter, error) {
+		return throttled.NewGCRARateLimiter(w.store, throttled.RateQuota{
+			MaxRate:  throttled.PerHour(perHour),
+			MaxBurst: int(float64(perHour) * maxBurstPercentage),
+		})
+	}
+	ipLimiter, err := newLimiter(rlc.PerIP)
+	if err != nil {
+		log15.Warn("error creating UI ip rate limiter", "error", err)
+		return
+	}
+	anonymousUserLimiter, err := newLimiter(rlc.PerAnonymousUser)
+	if err != nil {
+		log15.Warn("error creating UI anonymous user rate limiter", "error", err)
+		return
+	}
+	trustedProxies := rlc.TrustedProxies
+	if trustedProxies <= 0 {
+		trustedProxies = 1
+	}
+	w.rl.Store(&uiRateLimiter{ipLimiter: ipLimiter, anonymousUserLimiter: anonymousUserLimiter, trustedProxies: trustedProxies})
+}
+
+type uiRateLimiter struct {
+	ipLimiter            *throttled.GCRARateLimiter
+	anonymousUserLimiter *throttled.GCRARateLimiter
+
+	// trustedProxies is the number of proxies in front of Sourcegraph that append the
+	// address of their client to the X-Forwarded-For header.
+	trustedProxies int
+}
+
+// rateLimit limits the request of an anonymous client by its IP and, if it has one, by its
+// anonymous user cookie. The cookie is chosen by the client, so it never exempts the request
+// from the limit of its IP.
+//
+// Both limits are checked before either is charged, so that a request rejected by one limit
+// doesn't use up the other.
+func (rl *uiRateLimiter) rateLimit(r *http.Request) (bool, throttled.RateLimitResult, error) {
+	type bucket struct {
+		limiter *throttled.GCRARateLimiter
+		key     string
+	}
+	buckets := []bucket{{limiter: rl.ipLimiter, key: "ip:" + clientIP(r, rl.trustedProxies)}}
+	if uid, ok := cookie.AnonymousUID(r); ok && uid != "" {
+		buckets = append(buckets, bucket{limiter: rl.anonymousUserLimiter, key: "anon:" + uid})
+	}
+
+	for _, b := range buckets {
+		// A quantity of 0 peeks at the bucket without charging it.
+		_, result, err := b.limiter.RateLimit(b.key, 0)
+		if err != nil {
+			return false, result, err
+		}
+		if result.Remaining < 1 {
+			// Rejected requests don't charge the bucket, but report when to retry.
+			return b.limiter.RateLimit(b.key, 1)
+		}
+	}
+
+	var result throttled.RateLimitResult
+	for _, b := range buckets {
+		limited, res, err := b.limiter.RateLimit(b.key, 1)
+		if err != nil || limited {
+			return limited, res, err
+		}
+		result = res
+	}
+	return false, result, nil
+}
+
+// clientIP returns the IP of the client of the request. The X-Forwarded-For header is set by
+// the client and appended to by every proxy, so only the address appended by the outermost of
+// the given number of trusted proxies is used, counting from the right. Addresses to the left
+// of it are chosen by the client.
+func clientIP(r *http.Request, trustedProxies int) string {
+	if forwardedFor := r.Header.Get("X-Forwarded-For"); forwardedFor != "" {
+		hops := strings.Split(forwardedFor, ",")
+		i := len(hops) - trustedProxies
+		if i < 0 {
+			i = 0
+		}
+		return strings.TrimSpace(hops[i])
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// rateLimited wraps an expensive HTTP handler (e.g. the blob and raw handlers) to rate limit
+// anonymous requests, as configured in "ui.ratelimit". Requests over the limit are responded to
+// with 429 Too Many Requests and a Retry-After header. Requests of signed-in users are never
+// limited.
+func rateLimited(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if uiRateLimits == nil || actor.FromContext(r.Context()).IsAuthenticated() {
+			h.ServeHTTP(w, r)
+			return
+		}
+		rl := uiRateLimits.get()
+		if rl == nil {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		limited, result, err := rl.rateLimit(r)
+		if err != nil {
+			// Fail open: rate limiting must not take down the UI when Redis is unavailable.
+			log15.Error("checking UI rate limit", "error", err)
+		} else if limited {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+			http.Error(w, "Too many requests, please try again later or sign in.", http.StatusTooManyRequests)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/throttled/throttled/v2/store/memstore"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestRateLimited(t *testing.T) {
+	store, err := memstore.New(1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uiRateLimits = &rateLimitWatcher{store: store}
+	defer func() { uiRateLimits = nil }()
+
+	h := rateLimited(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	anonymous := func(forwardedFor, uid string) *http.Request {
+		req := httptest.NewRequest("GET", "/github.com/gorilla/mux/-/raw/mux.go", nil)
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		if uid != "" {
+			req.AddCookie(&http.Cookie{Name: "sourcegraphAnonymousUid", Value: uid})
+		}
+		return req
+	}
+
+	// Rate limiting is disabled by default.
+	uiRateLimits.updateFromConfig(nil)
+	for i := 0; i < 3; i++ {
+		if rec := serve(anonymous("1.1.1.1", "")); rec.Code != http.StatusOK {
+			t.Fatalf("want no rate limiting when disabled, got status %d", rec.Code)
+		}
+	}
+
+	uiRateLimits.updateFromConfig(&schema.UiRatelimit{Enabled: true, PerIP: 1, PerAnonymousUser: 1})
+
+	tests := []struct {
+		name       string
+		req        *http.Request
+		wantStatus int
+	}{
+		{name: "first request by IP", req: anonymous("2.2.2.2", ""), wantStatus: http.StatusOK},
+		{name: "second request by IP", req: anonymous("2.2.2.2", ""), wantStatus: http.StatusTooManyRequests},
+		{name: "other IP", req: anonymous("3.3.3.3", ""), wantStatus: http.StatusOK},
+		{name: "anonymous user from limited IP", req: anonymous("2.2.2.2", "abc"), wantStatus: http.StatusTooManyRequests},
+		{name: "first request by anonymous user", req: anonymous("4.4.4.4", "abc"), wantStatus: http.StatusOK},
+		{name: "second request by anonymous user", req: anonymous("5.5.5.5", "abc"), wantStatus: http.StatusTooManyRequests},
+		{name: "first request with rotating cookie", req: anonymous("6.6.6.6", "rotating-1"), wantStatus: http.StatusOK},
+		{name: "second request with rotating cookie", req: anonymous("6.6.6.6", "rotating-2"), wantStatus: http.StatusTooManyRequests},
+		{name: "first request with spoofed X-Forwarded-For", req: anonymous("7.7.7.7, 8.8.8.8", ""), wantStatus: http.StatusOK},
+		{name: "second request with spoofed X-Forwarded-For", req: anonymous("9.9.9.9, 8.8.8.8", ""), wantStatus: http.StatusTooManyRequests},
+		{name: "signed-in user", req: anonymous("2.2.2.2", "").WithContext(actor.WithActor(context.Background(), actor.FromUser(1))), wantStatus: http.StatusOK},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := serve(test.req)
+			if rec.Code != test.wantStatus {
+				t.Fatalf("got status %d, want %d", rec.Code, test.wantStatus)
+			}
+			if retryAfter := rec.Header().Get("Retry-After"); (test.wantStatus == http.StatusTooManyRequests) != (retryAfter != "") {
+				t.Errorf("unexpected Retry-After header %q", retryAfter)
+			}
+		})
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name           string
+		forwardedFor   string
+		trustedProxies int
+		want           string
+	}{
+		{name: "no X-Forwarded-For", trustedProxies: 1, want: "192.0.2.1"},
+		{name: "single hop", forwardedFor: "1.1.1.1", trustedProxies: 1, want: "1.1.1.1"},
+		{name: "spoofed hop", forwardedFor: "6.6.6.6, 1.1.1.1", trustedProxies: 1, want: "1.1.1.1"},
+		{name: "two trusted proxies", forwardedFor: "6.6.6.6, 1.1.1.1, 10.0.0.1", trustedProxies: 2, want: "1.1.1.1"},
+		{name: "fewer hops than trusted proxies", forwardedFor: "1.1.1.1", trustedProxies: 2, want: "1.1.1.1"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			if test.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", test.forwardedFor)
+			}
+			if got := clientIP(req, test.trustedProxies); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -2,11 +2,14 @@ package ui
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"html"
 	"io"
 	"mime"
 	"net/http"
+	"net/url"
 	"os"
 	"path"
 	"strings"
@@ -21,6 +24,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/vfsutil"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 )
@@ -46,12 +50,21 @@ import (
 // Get a zip/tar archive of a _file_ in a repository:
 //     curl -H 'Accept: application/zip' http://localhost:3080/github.com/gorilla/mux/-/raw/mux.go -o repo-file.zip
 //
+// Get a zip/tar archive of the files of a subdirectory matching glob patterns, at most 2 levels deep:
+//     curl -H 'Accept: application/zip' 'http://localhost:3080/github.com/gorilla/mux/-/raw/.github?include=**/*.yml&exclude=ISSUE_TEMPLATE/**&depth=2' -o repo-filtered.zip
+//
 // Authenticate using an access token:
 //     curl -H 'Accept: application/zip' http://fe70a9eeffc8ea7b1edf7c67095c143d1ada7e1b@localhost:3080/github.com/gorilla/mux/-/raw/ -o repo.zip
 //
+// Download using a short-lived signed URL (minted by POST /-/sign-raw-url, see package signedurl):
+//     curl -O -J 'http://localhost:3080/github.com/gorilla/mux/-/raw/?format=zip&sg_expires=...&sg_user=...&sg_scope=&sg_signature=...'
+//
 // Download an archive without specifying an Accept header (e.g. download via browser):
 //     curl -O -J http://localhost:3080/github.com/gorilla/mux/-/raw?format=zip
 //
+// Revalidate a previously fetched file (responds with 304 Not Modified if the file is unchanged):
+//     curl -H 'If-None-Match: "<ETag of the previous response>"' http://localhost:3080/github.com/gorilla/mux/-/raw/mux.go
+//
 // Known issues:
 //
 // - For security reasons, all non-archive files (e.g. code, images, binaries) are served with a Content-Type of text/plain.
@@ -171,16 +184,26 @@ func serveRaw(w http.ResponseWriter, r *http.Request) (err error) {
 			requestType = "patharchive"
 		}
 
+		opts, err := rawArchiveOptions(r.URL.Query(), relativePath)
+		if err != nil {
+			requestType = "400"
+			http.Error(w, html.EscapeString(err.Error()), http.StatusBadRequest)
+			return nil // request handled
+		}
+		opts.Treeish = string(common.CommitID)
+		opts.Format = string(format)
+
+		pathspecs := opts.Pathspecs()
+		if serveRawNotModified(w, r, common, rawETag(append([]string{string(format), string(common.CommitID)}, pathspecs...)...)) {
+			requestType = "notmodified"
+			return nil
+		}
+
 		metricRunning := metricRawArchiveRunning.WithLabelValues(string(format))
 		metricRunning.Inc()
 		defer metricRunning.Dec()
 
-		f, err := openArchiveReader(r.Context(), vfsutil.ArchiveOpts{
-			Repo:         common.Repo.Name,
-			Commit:       common.CommitID,
-			Format:       format,
-			RelativePath: relativePath,
-		})
+		f, err := openArchiveReader(r.Context(), common.Repo.Name, opts)
 		if err != nil {
 			return err
 		}
@@ -241,6 +264,18 @@ func serveRaw(w http.ResponseWriter, r *http.Request) (err error) {
 			return err
 		}
 
+		// Directory listings and files only change with the Git object at the requested path.
+		if info, ok := fi.Sys().(git.ObjectInfo); ok {
+			kind := "file"
+			if fi.IsDir() {
+				kind = "dir"
+			}
+			if serveRawNotModified(w, r, common, rawETag(kind, info.OID().String())) {
+				requestType = "notmodified"
+				return nil
+			}
+		}
+
 		if fi.IsDir() {
 			requestType = "dir"
 			infos, err := git.ReadDir(r.Context(), common.Repo.Name, common.CommitID, requestedPath, false)
@@ -276,13 +311,119 @@ func serveRaw(w http.ResponseWriter, r *http.Request) (err error) {
 	}
 }
 
+// rawETag returns a strong entity tag derived from the given validators, e.g. the kind of
+// response and the ID of the Git object it was generated from.
+func rawETag(validators ...string) string {
+	h := sha256.New()
+	for _, v := range validators {
+		_, _ = io.WriteString(h, v)
+		_, _ = h.Write([]byte{0})
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil))[:32] + `"`
+}
+
+// serveRawNotModified sets the ETag and Last-Modified headers of a raw response and responds
+// with 304 Not Modified if the conditional headers of the request match them. It reports
+// whether the request was handled.
+//
+// The Last-Modified time is the commit date of the resolved commit: the content of a raw
+// response cannot have changed after the commit it was read from.
+func serveRawNotModified(w http.ResponseWriter, r *http.Request, common *Common, etag string) bool {
+	var lastModified time.Time
+	commit, err := git.GetCommit(r.Context(), common.Repo.Name, common.CommitID, git.ResolveRevisionOptions{NoEnsureRevision: true})
+	if err == nil && commit != nil && commit.Committer != nil {
+		lastModified = commit.Committer.Date
+	}
+
+	w.Header().Set("ETag", etag)
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+	if !rawNotModified(r, etag, lastModified) {
+		return false
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// rawNotModified evaluates the If-None-Match and If-Modified-Since headers of a request.
+func rawNotModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if r.Method != "GET" && r.Method != "HEAD" {
+		return false
+	}
+
+	// If-None-Match takes precedence over If-Modified-Since (RFC 7232, section 6). Entity tags
+	// are compared weakly, as required for If-None-Match.
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ifModifiedSince := r.Header.Get("If-Modified-Since"); ifModifiedSince != "" && !lastModified.IsZero() {
+		t, err := http.ParseTime(ifModifiedSince)
+		return err == nil && !lastModified.Truncate(time.Second).After(t)
+	}
+	return false
+}
+
+// rawArchiveOptions returns the archive options selecting the given path. The
+// "include" and "exclude" glob patterns and the "depth" limit of the query are
+// relative to the path, so that only the matching files are archived instead
+// of the entire path.
+func rawArchiveOptions(q url.Values, relativePath string) (gitserver.ArchiveOptions, error) {
+	var filters gitserver.ArchiveOptions
+	if err := filters.ParseArchiveFilters(q); err != nil {
+		return gitserver.ArchiveOptions{}, err
+	}
+
+	var opts gitserver.ArchiveOptions
+	prefix := ""
+	if relativePath != "." {
+		prefix = escapeGlob(strings.TrimSuffix(relativePath, "/")) + "/"
+	}
+
+	// Include patterns already select paths within the path.
+	if len(filters.Include) == 0 {
+		opts.Paths = []string{relativePath}
+	}
+	for _, pattern := range filters.Include {
+		opts.Include = append(opts.Include, prefix+pattern)
+	}
+	for _, pattern := range filters.Exclude {
+		opts.Exclude = append(opts.Exclude, prefix+pattern)
+	}
+	if filters.MaxDepth > 0 {
+		opts.MaxDepth = strings.Count(prefix, "/") + filters.MaxDepth
+	}
+	return opts, nil
+}
+
+// escapeGlob escapes the characters of a path that have a special meaning in
+// the glob patterns of git pathspecs.
+func escapeGlob(path string) string {
+	var b strings.Builder
+	for _, r := range path {
+		if strings.ContainsRune(`*?[\`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
 // openArchiveReader runs git archive and streams the output. Note: we do not
 // use vfsutil since most archives are just streamed once so caching locally
 // is not useful. Additionally we transfer the output over the internet, so we
 // use default compression levels on zips (instead of no compression).
-func openArchiveReader(ctx context.Context, opts vfsutil.ArchiveOpts) (io.ReadCloser, error) {
-	cmd := gitserver.DefaultClient.Command("git", "archive", "--format="+string(opts.Format), string(opts.Commit), opts.RelativePath)
-	cmd.Repo = opts.Repo
+func openArchiveReader(ctx context.Context, repo api.RepoName, opts gitserver.ArchiveOptions) (io.ReadCloser, error) {
+	args := append([]string{"archive", "--format=" + opts.Format, opts.Treeish, "--"}, opts.Pathspecs()...)
+	cmd := gitserver.DefaultClient.Command("git", args...)
+	cmd.Repo = repo
 	return gitserver.StdoutReader(ctx, cmd)
 }
 
@@ -6,14 +6,20 @@ import (
 	"mime"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git/gitapi"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/util"
 )
 
@@ -138,6 +144,7 @@ func Test_serveRawWithContentArchive(t *testing.T) {
 			"X-Content-Type-Options": "nosniff",
 			"Content-Type":           "application/zip",
 			"Content-Disposition":    mime.FormatMediaType("Attachment", map[string]string{"filename": "test.zip"}),
+			"Etag":                   rawETag("zip", "12345", "."),
 		}
 
 		if len(w.Header()) != len(expectedHeaders) {
@@ -177,6 +184,7 @@ func Test_serveRawWithContentArchive(t *testing.T) {
 			"X-Content-Type-Options": "nosniff",
 			"Content-Type":           "application/x-tar",
 			"Content-Disposition":    mime.FormatMediaType("Attachment", map[string]string{"filename": "test.tar"}),
+			"Etag":                   rawETag("tar", "12345", "."),
 		}
 
 		if len(w.Header()) != len(expectedHeaders) {
@@ -367,3 +375,153 @@ c.go`
 		}
 	})
 }
+
+type fakeObjectInfo gitdomain.OID
+
+func (oid fakeObjectInfo) OID() gitdomain.OID { return gitdomain.OID(oid) }
+
+func Test_serveRawConditionalRequests(t *testing.T) {
+	mockNewCommon = func(w http.ResponseWriter, r *http.Request, title string, serveError serveErrorHandler) (*Common, error) {
+		return &Common{
+			Repo: &types.Repo{
+				Name: "test",
+			},
+			CommitID: api.CommitID("12345"),
+		}, nil
+	}
+	defer func() {
+		mockNewCommon = nil
+	}()
+
+	commitDate := time.Date(2021, time.October, 14, 12, 0, 0, 0, time.UTC)
+	git.Mocks.GetCommit = func(api.CommitID) (*gitapi.Commit, error) {
+		return &gitapi.Commit{ID: "12345", Committer: &gitapi.Signature{Date: commitDate}}, nil
+	}
+	git.Mocks.Stat = func(commit api.CommitID, name string) (fs.FileInfo, error) {
+		return &util.FileInfo{Sys_: fakeObjectInfo{1, 2, 3}}, nil
+	}
+	git.Mocks.NewFileReader = func(commit api.CommitID, name string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader("this is a test file")), nil
+	}
+	defer git.ResetMocks()
+
+	etag := rawETag("file", fakeObjectInfo{1, 2, 3}.OID().String())
+
+	tests := []struct {
+		name       string
+		headers    map[string]string
+		wantStatus int
+	}{
+		{name: "unconditional", wantStatus: http.StatusOK},
+		{name: "matching ETag", headers: map[string]string{"If-None-Match": etag}, wantStatus: http.StatusNotModified},
+		{name: "matching weak ETag in list", headers: map[string]string{"If-None-Match": `"other", W/` + etag}, wantStatus: http.StatusNotModified},
+		{name: "other ETag", headers: map[string]string{"If-None-Match": `"other"`}, wantStatus: http.StatusOK},
+		{name: "ETag takes precedence", headers: map[string]string{"If-None-Match": `"other"`, "If-Modified-Since": commitDate.Format(http.TimeFormat)}, wantStatus: http.StatusOK},
+		{name: "not modified since", headers: map[string]string{"If-Modified-Since": commitDate.Format(http.TimeFormat)}, wantStatus: http.StatusNotModified},
+		{name: "modified since", headers: map[string]string{"If-Modified-Since": commitDate.Add(-time.Hour).Format(http.TimeFormat)}, wantStatus: http.StatusOK},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/github.com/sourcegraph/sourcegraph/-/raw/a.go", nil)
+			for k, v := range test.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+
+			if err := serveRaw(w, req); err != nil {
+				t.Fatalf("Failed to invoke serveRaw: %v", err)
+			}
+
+			if w.Code != test.wantStatus {
+				t.Fatalf("Want %d but got %d", test.wantStatus, w.Code)
+			}
+			if h := w.Header().Get("ETag"); h != etag {
+				t.Errorf("Want ETag %q but got %q", etag, h)
+			}
+			if h, want := w.Header().Get("Last-Modified"), commitDate.Format(http.TimeFormat); h != want {
+				t.Errorf("Want Last-Modified %q but got %q", want, h)
+			}
+
+			wantBody := "this is a test file"
+			if test.wantStatus == http.StatusNotModified {
+				wantBody = ""
+			}
+			if body := w.Body.String(); body != wantBody {
+				t.Errorf("Want %q in body, but got %q", wantBody, body)
+			}
+		})
+	}
+}
+
+func Test_rawArchiveOptions(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		relativePath string
+		want         gitserver.ArchiveOptions
+		wantErr      bool
+	}{
+		{
+			name:         "root",
+			relativePath: ".",
+			want:         gitserver.ArchiveOptions{Paths: []string{"."}},
+		},
+		{
+			name:         "root with filters",
+			query:        "include=**/*.go&exclude=vendor/**&depth=2",
+			relativePath: ".",
+			want: gitserver.ArchiveOptions{
+				Include:  []string{"**/*.go"},
+				Exclude:  []string{"vendor/**"},
+				MaxDepth: 2,
+			},
+		},
+		{
+			name:         "filters are relative to the path",
+			query:        "include=*.md&exclude=internal/**&depth=1",
+			relativePath: "docs/[v2]/",
+			want: gitserver.ArchiveOptions{
+				Include:  []string{`docs/\[v2]/*.md`},
+				Exclude:  []string{`docs/\[v2]/internal/**`},
+				MaxDepth: 3,
+			},
+		},
+		{
+			name:         "exclude only keeps the path",
+			query:        "exclude=*.png",
+			relativePath: "docs",
+			want: gitserver.ArchiveOptions{
+				Paths:   []string{"docs"},
+				Exclude: []string{"docs/*.png"},
+			},
+		},
+		{
+			name:         "invalid depth",
+			query:        "depth=-1",
+			relativePath: ".",
+			wantErr:      true,
+		},
+		{
+			name:         "empty pattern",
+			query:        "include=",
+			relativePath: ".",
+			wantErr:      true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q, err := url.ParseQuery(test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := rawArchiveOptions(q, test.relativePath)
+			if have, want := err != nil, test.wantErr; have != want {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// repoGoneError returns the error shown to the user (with HTTP status 410 Gone) if the repository
+// of a repository page was deleted or blocked, or nil if the repository never existed.
+//
+// err is the error returned when resolving the repository. Only repositories that are blocked or
+// not found in the database are checked.
+//
+// 🚨 SECURITY: The repositories are looked up with the permissions of the current user, so that
+// the existence of private repositories is not leaked. The reason why a repository was blocked is
+// only shown to site admins.
+func repoGoneError(ctx context.Context, db dbutil.DB, repoName api.RepoName, err error) error {
+	var blockedErr *types.BlockedRepoError
+	if errors.As(err, &blockedErr) {
+		msg := fmt.Sprintf("Repository %s has been blocked by a site admin.", repoName)
+		if backend.CheckCurrentUserIsSiteAdmin(ctx, db) == nil {
+			msg += fmt.Sprintf(" It was blocked on %s for the following reason: %s", time.Unix(blockedErr.At, 0).UTC().Format(time.RFC1123), blockedErr.Reason)
+		}
+		return errors.New(msg)
+	}
+	if !errors.HasType(err, &database.RepoNotFoundErr{}) {
+		return nil
+	}
+
+	// Soft-deleted repositories are renamed to "DELETED-<timestamp>-<name>" (see the
+	// soft_deleted_repository_name database function).
+	repos, listErr := database.Repos(db).List(ctx, database.ReposListOptions{
+		IncludePatterns: []string{"^DELETED-[0-9.]+-" + regexp.QuoteMeta(string(repoName)) + "$"},
+		IncludeDeleted:  true,
+		LimitOffset:     &database.LimitOffset{Limit: 1},
+	})
+	if listErr != nil || len(repos) == 0 {
+		return nil
+	}
+	return errors.Errorf("Repository %s has been deleted.", repoName)
+}
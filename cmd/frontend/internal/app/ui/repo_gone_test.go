@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestRepoGoneError(t *testing.T) {
+	var listOpts database.ReposListOptions
+	database.Mocks.Repos.List = func(ctx context.Context, opt database.ReposListOptions) ([]*types.Repo, error) {
+		listOpts = opt
+		if strings.Contains(opt.IncludePatterns[0], "deleted") {
+			return []*types.Repo{{Name: "DELETED-1633046400.123-github.com/foo/deleted"}}, nil
+		}
+		return nil, nil
+	}
+	defer func() { database.Mocks.Repos.List = nil }()
+
+	notFound := &database.RepoNotFoundErr{}
+	blocked := (&types.Repo{
+		Name:    "github.com/foo/blocked",
+		Blocked: &types.RepoBlock{At: time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC).Unix(), Reason: "DMCA takedown"},
+	}).IsBlocked()
+
+	t.Run("never existed", func(t *testing.T) {
+		if err := repoGoneError(context.Background(), nil, "github.com/foo/bar", notFound); err != nil {
+			t.Errorf("got %q, want nil", err)
+		}
+		if !listOpts.IncludeDeleted {
+			t.Error("want deleted repositories to be listed")
+		}
+	})
+
+	t.Run("deleted", func(t *testing.T) {
+		err := repoGoneError(context.Background(), nil, "github.com/foo/deleted", notFound)
+		if want := "Repository github.com/foo/deleted has been deleted."; err == nil || err.Error() != want {
+			t.Errorf("got %v, want %q", err, want)
+		}
+	})
+
+	t.Run("blocked", func(t *testing.T) {
+		err := repoGoneError(context.Background(), nil, "github.com/foo/blocked", blocked)
+		if want := "Repository github.com/foo/blocked has been blocked by a site admin."; err == nil || err.Error() != want {
+			t.Errorf("got %v, want %q", err, want)
+		}
+	})
+
+	t.Run("blocked as site admin", func(t *testing.T) {
+		// Internal actors are treated as site admins.
+		err := repoGoneError(actor.WithInternalActor(context.Background()), nil, "github.com/foo/blocked", blocked)
+		if err == nil || !strings.Contains(err.Error(), "It was blocked on Fri, 01 Oct 2021 00:00:00 UTC for the following reason: DMCA takedown") {
+			t.Errorf("got %v, want reason for site admins", err)
+		}
+	})
+}
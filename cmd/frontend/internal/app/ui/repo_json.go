@@ -0,0 +1,173 @@
+package ui
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/golang/gddo/httputil"
+	"github.com/gorilla/mux"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// Examples:
+//
+// Get the metadata of a repository:
+//     curl -H 'Accept: application/json' http://localhost:3080/github.com/gorilla/mux
+//
+// List the entries of a directory:
+//     curl -H 'Accept: application/json' http://localhost:3080/github.com/gorilla/mux@v1.8.0/-/tree/.github
+//
+// Get the metadata of a file (use the raw route to get its contents):
+//     curl -H 'Accept: application/json' http://localhost:3080/github.com/gorilla/mux/-/blob/mux.go
+
+// jsonRoutes are the routes that serve a JSON representation of the page when it is requested
+// with the Accept header.
+var jsonRoutes = map[string]struct{}{
+	routeRepo: {},
+	routeTree: {},
+	routeBlob: {},
+}
+
+// wantsJSON reports whether the client requested the JSON representation of a page (e.g. a
+// script using the same URLs as users of the web app) instead of the page of the web app.
+func wantsJSON(r *http.Request) bool {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return false
+	}
+	if _, ok := jsonRoutes[route.GetName()]; !ok {
+		return false
+	}
+	return httputil.NegotiateContentType(r, []string{"text/html", "application/json"}, "text/html") == "application/json"
+}
+
+type repoJSON struct {
+	Name        api.RepoName `json:"name"`
+	URL         string       `json:"url"`
+	Description string       `json:"description"`
+	Fork        bool         `json:"fork"`
+	Archived    bool         `json:"archived"`
+	Private     bool         `json:"private"`
+
+	// Rev is the revision specified in the URL, if any, and CommitID the commit it resolved to.
+	// CommitID is empty if the repository is being cloned or has no commits.
+	Rev      string       `json:"rev,omitempty"`
+	CommitID api.CommitID `json:"commitID,omitempty"`
+
+	CloneInProgress bool `json:"cloneInProgress,omitempty"`
+}
+
+type treeEntryJSON struct {
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	IsDirectory bool   `json:"isDirectory"`
+	Size        int64  `json:"size,omitempty"`
+	URL         string `json:"url"`
+}
+
+type treeJSON struct {
+	Repository repoJSON        `json:"repository"`
+	Path       string          `json:"path"`
+	Entries    []treeEntryJSON `json:"entries"`
+}
+
+type blobJSON struct {
+	Repository repoJSON `json:"repository"`
+	Path       string   `json:"path"`
+	Name       string   `json:"name"`
+	Size       int64    `json:"size"`
+	URL        string   `json:"url"`
+	RawURL     string   `json:"rawURL"`
+}
+
+// serveRepoJSON serves the JSON representation of the repository, tree or blob page of the
+// given route, whose repository and revision were resolved by newCommon.
+func serveRepoJSON(w http.ResponseWriter, r *http.Request, routeName string, common *Common) error {
+	repo := newRepoJSON(common)
+	if routeName == routeRepo || repo.CommitID == "" {
+		// The entries of a tree and the size of a blob are only known once the revision
+		// resolved, so only the repository is returned while it is cloning.
+		return writeJSON(w, repo)
+	}
+
+	filePath := strings.Trim(mux.Vars(r)["Path"], "/")
+	if routeName == routeTree {
+		entries, err := git.ReadDir(r.Context(), common.Repo.Name, common.CommitID, filePath, false)
+		if err != nil {
+			if os.IsNotExist(err) {
+				serveError(w, r, err, http.StatusNotFound)
+				return nil
+			}
+			return err
+		}
+
+		tree := treeJSON{Repository: repo, Path: filePath, Entries: make([]treeEntryJSON, 0, len(entries))}
+		for _, entry := range entries {
+			e := treeEntryJSON{
+				Name:        path.Base(entry.Name()),
+				Path:        entry.Name(),
+				IsDirectory: entry.IsDir(),
+				URL:         repoPageURL(common, routeBlob, entry.Name()),
+			}
+			if e.IsDirectory {
+				e.URL = repoPageURL(common, routeTree, entry.Name())
+			} else {
+				e.Size = entry.Size()
+			}
+			tree.Entries = append(tree.Entries, e)
+		}
+		return writeJSON(w, tree)
+	}
+
+	stat, err := git.Stat(r.Context(), common.Repo.Name, common.CommitID, filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			serveError(w, r, err, http.StatusNotFound)
+			return nil
+		}
+		return err
+	}
+	return writeJSON(w, blobJSON{
+		Repository: repo,
+		Path:       filePath,
+		Name:       path.Base(filePath),
+		Size:       stat.Size(),
+		URL:        repoPageURL(common, routeBlob, filePath),
+		RawURL:     repoPageURL(common, routeRaw, filePath),
+	})
+}
+
+func newRepoJSON(common *Common) repoJSON {
+	if common.Repo == nil {
+		return repoJSON{CloneInProgress: true}
+	}
+	return repoJSON{
+		Name:            common.Repo.Name,
+		URL:             "/" + string(common.Repo.Name),
+		Description:     common.Repo.Description,
+		Fork:            common.Repo.Fork,
+		Archived:        common.Repo.Archived,
+		Private:         common.Repo.Private,
+		Rev:             strings.TrimPrefix(common.Rev, "@"),
+		CommitID:        common.CommitID,
+		CloneInProgress: common.CloneInProgress,
+	}
+}
+
+// repoPageURL returns the URL of the tree, blob or raw page of the given path at the revision
+// of the request.
+func repoPageURL(common *Common, routeName, filePath string) string {
+	return "/" + string(common.Repo.Name) + common.Rev + "/-/" + routeName + "/" + filePath
+}
+
+// writeJSON writes a JSON Content-Type header and a JSON-encoded object to the
+// http.ResponseWriter.
+func writeJSON(w http.ResponseWriter, v interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	return json.NewEncoder(w).Encode(v)
+}
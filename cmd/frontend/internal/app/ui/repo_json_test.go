@@ -0,0 +1,150 @@
+package ui
+
+import (
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/util"
+)
+
+func TestWantsJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		route  string
+		accept string
+		want   bool
+	}{
+		{name: "no accept header", route: routeRepo, accept: "", want: false},
+		{name: "browser", route: routeBlob, accept: "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", want: false},
+		{name: "any", route: routeTree, accept: "*/*", want: false},
+		{name: "json", route: routeTree, accept: "application/json", want: true},
+		{name: "json preferred", route: routeBlob, accept: "text/html;q=0.5, application/json", want: true},
+		{name: "other route", route: routeRaw, accept: "application/json", want: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := mux.NewRouter()
+			var have bool
+			router.Path("/").Name(test.route).HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				have = wantsJSON(r)
+			})
+
+			r := httptest.NewRequest("GET", "/", nil)
+			if test.accept != "" {
+				r.Header.Set("Accept", test.accept)
+			}
+			router.ServeHTTP(httptest.NewRecorder(), r)
+
+			if have != test.want {
+				t.Fatalf("want %v but got %v", test.want, have)
+			}
+		})
+	}
+}
+
+func TestServeRepoJSON(t *testing.T) {
+	common := &Common{
+		Repo: &types.Repo{
+			Name:        "github.com/user/repo",
+			Description: "A repository",
+			Fork:        true,
+		},
+		Rev:      "@main",
+		CommitID: "eca7e807356b887ee24b7a7497973bbfc5688dac",
+	}
+	repo := repoJSON{
+		Name:        "github.com/user/repo",
+		URL:         "/github.com/user/repo",
+		Description: "A repository",
+		Fork:        true,
+		Rev:         "main",
+		CommitID:    "eca7e807356b887ee24b7a7497973bbfc5688dac",
+	}
+
+	git.Mocks.ReadDir = func(commit api.CommitID, name string, recurse bool) ([]fs.FileInfo, error) {
+		if name != "dir" {
+			return nil, &os.PathError{Op: "ls-tree", Path: name, Err: os.ErrNotExist}
+		}
+		return []fs.FileInfo{
+			&util.FileInfo{Name_: "dir/sub", Mode_: os.ModeDir},
+			&util.FileInfo{Name_: "dir/file.go", Size_: 42},
+		}, nil
+	}
+	git.Mocks.Stat = func(commit api.CommitID, name string) (fs.FileInfo, error) {
+		return &util.FileInfo{Name_: name, Size_: 42}, nil
+	}
+	t.Cleanup(git.ResetMocks)
+
+	serve := func(t *testing.T, routeName, path string, common *Common, v interface{}) int {
+		w := httptest.NewRecorder()
+		r := mux.SetURLVars(httptest.NewRequest("GET", "/", nil), map[string]string{"Path": path})
+		if err := serveRepoJSON(w, r, routeName, common); err != nil {
+			t.Fatal(err)
+		}
+		if w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return w.Code
+	}
+
+	t.Run("repo", func(t *testing.T) {
+		var have repoJSON
+		serve(t, routeRepo, "", common, &have)
+		if diff := cmp.Diff(repo, have); diff != "" {
+			t.Fatalf("unexpected repository (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("clone in progress", func(t *testing.T) {
+		var have repoJSON
+		serve(t, routeTree, "/dir", &Common{Repo: common.Repo, CloneInProgress: true}, &have)
+		want := repoJSON{Name: repo.Name, URL: repo.URL, Description: repo.Description, Fork: true, CloneInProgress: true}
+		if diff := cmp.Diff(want, have); diff != "" {
+			t.Fatalf("unexpected repository (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("tree", func(t *testing.T) {
+		var have treeJSON
+		serve(t, routeTree, "/dir", common, &have)
+		want := treeJSON{
+			Repository: repo,
+			Path:       "dir",
+			Entries: []treeEntryJSON{
+				{Name: "sub", Path: "dir/sub", IsDirectory: true, URL: "/github.com/user/repo@main/-/tree/dir/sub"},
+				{Name: "file.go", Path: "dir/file.go", Size: 42, URL: "/github.com/user/repo@main/-/blob/dir/file.go"},
+			},
+		}
+		if diff := cmp.Diff(want, have); diff != "" {
+			t.Fatalf("unexpected tree (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("blob", func(t *testing.T) {
+		var have blobJSON
+		serve(t, routeBlob, "/dir/file.go", common, &have)
+		want := blobJSON{
+			Repository: repo,
+			Path:       "dir/file.go",
+			Name:       "file.go",
+			Size:       42,
+			URL:        "/github.com/user/repo@main/-/blob/dir/file.go",
+			RawURL:     "/github.com/user/repo@main/-/raw/dir/file.go",
+		}
+		if diff := cmp.Diff(want, have); diff != "" {
+			t.Fatalf("unexpected blob (-want +got):\n%s", diff)
+		}
+	})
+}
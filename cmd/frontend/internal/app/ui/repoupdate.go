@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
+)
+
+const (
+	// repoUpdateDebounce is the minimum duration between two updates of a repository enqueued
+	// because it was visited.
+	repoUpdateDebounce = 5 * time.Minute
+
+	// repoUpdateBatchInterval is how often the repositories visited in the meantime are enqueued
+	// for update.
+	repoUpdateBatchInterval = 2 * time.Second
+
+	// repoUpdateDebounceCacheSize is the number of recently visited repositories remembered for
+	// debouncing.
+	repoUpdateDebounceCacheSize = 10000
+)
+
+// repoUpdateEnqueuer enqueues the update of visited repositories in repo-updater, so that
+// gitserver contents are kept up to date. Visits of the same repository are debounced, and the
+// visited repositories are enqueued in batches, so that hot repositories do not flood repo-updater
+// with redundant requests.
+type repoUpdateEnqueuer struct {
+	enqueue func(ctx context.Context, repo api.RepoName) error
+
+	once sync.Once
+
+	mu       sync.Mutex
+	enqueued *lru.Cache // api.RepoName -> time.Time when the repository was last enqueued
+	pending  map[api.RepoName]struct{}
+}
+
+// repoUpdates is the enqueuer of updates of visited repositories.
+var repoUpdates = newRepoUpdateEnqueuer(func(ctx context.Context, repo api.RepoName) error {
+	_, err := repoupdater.DefaultClient.EnqueueRepoUpdate(ctx, repo)
+	return err
+})
+
+func newRepoUpdateEnqueuer(enqueue func(ctx context.Context, repo api.RepoName) error) *repoUpdateEnqueuer {
+	enqueued, _ := lru.New(repoUpdateDebounceCacheSize) // only errors for a non-positive size
+	return &repoUpdateEnqueuer{
+		enqueue:  enqueue,
+		enqueued: enqueued,
+		pending:  map[api.RepoName]struct{}{},
+	}
+}
+
+// Visit records a visit of the repository, which enqueues its update in the next batch unless its
+// update was already enqueued less than repoUpdateDebounce ago.
+func (e *repoUpdateEnqueuer) Visit(repo api.RepoName) {
+	e.once.Do(func() { go e.run() })
+	e.visit(repo, time.Now())
+}
+
+func (e *repoUpdateEnqueuer) visit(repo api.RepoName, now time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if last, ok := e.enqueued.Get(repo); ok && now.Sub(last.(time.Time)) < repoUpdateDebounce {
+		return
+	}
+	e.enqueued.Add(repo, now)
+	e.pending[repo] = struct{}{}
+}
+
+func (e *repoUpdateEnqueuer) run() {
+	for range time.NewTicker(repoUpdateBatchInterval).C {
+		e.flush(context.Background())
+	}
+}
+
+// flush enqueues the update of the repositories visited since the last flush.
+func (e *repoUpdateEnqueuer) flush(ctx context.Context) {
+	e.mu.Lock()
+	pending := e.pending
+	e.pending = map[api.RepoName]struct{}{}
+	e.mu.Unlock()
+
+	for repo := range pending {
+		if err := e.enqueue(ctx, repo); err != nil {
+			log15.Error("EnqueueRepoUpdate", "repo", repo, "error", err)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestRepoUpdateEnqueuer(t *testing.T) {
+	var enqueued []string
+	e := newRepoUpdateEnqueuer(func(ctx context.Context, repo api.RepoName) error {
+		enqueued = append(enqueued, string(repo))
+		return nil
+	})
+	flush := func() []string {
+		enqueued = nil
+		e.flush(context.Background())
+		sort.Strings(enqueued)
+		return enqueued
+	}
+
+	now := time.Now()
+
+	// Visits of the same repository are batched.
+	e.visit("github.com/foo/bar", now)
+	e.visit("github.com/foo/bar", now.Add(time.Second))
+	e.visit("github.com/foo/baz", now.Add(time.Second))
+	if diff := cmp.Diff([]string{"github.com/foo/bar", "github.com/foo/baz"}, flush()); diff != "" {
+		t.Errorf("unexpected enqueued repos (-want +got):\n%s", diff)
+	}
+
+	// Visits within the debounce duration are ignored.
+	e.visit("github.com/foo/bar", now.Add(repoUpdateDebounce-time.Second))
+	if got := flush(); len(got) != 0 {
+		t.Errorf("want no enqueued repos, got %v", got)
+	}
+
+	e.visit("github.com/foo/bar", now.Add(repoUpdateDebounce))
+	if diff := cmp.Diff([]string{"github.com/foo/bar"}, flush()); diff != "" {
+		t.Errorf("unexpected enqueued repos (-want +got):\n%s", diff)
+	}
+}
@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
@@ -17,16 +18,22 @@ import (
 	"github.com/inconshreveable/log15"
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/ext"
+	otlog "github.com/opentracing/opentracing-go/log"
+	"github.com/throttled/throttled/v2/store/redigostore"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	uirouter "github.com/sourcegraph/sourcegraph/cmd/frontend/internal/app/ui/router"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/routevar"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbconn"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/randstring"
+	"github.com/sourcegraph/sourcegraph/internal/redispool"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 )
 
@@ -118,6 +125,13 @@ func Router() *mux.Router {
 // and assigns it to uirouter.Router.
 // The router can be accessed by calling Router().
 func InitRouter(db dbutil.DB, codeIntelResolver graphqlbackend.CodeIntelResolver) {
+	store, err := redigostore.New(redispool.Cache, "ui:rl:", 0)
+	if err != nil {
+		log15.Error("ui: creating rate limit store", "error", err)
+	} else {
+		uiRateLimits = newRateLimitWatcher(store)
+	}
+
 	router := newRouter()
 	initRouter(db, router, codeIntelResolver)
 }
@@ -220,6 +234,8 @@ func brandNameSubtitle(titles ...string) string {
 func initRouter(db dbutil.DB, router *mux.Router, codeIntelResolver graphqlbackend.CodeIntelResolver) {
 	uirouter.Router = router // make accessible to other packages
 
+	router.Use(cacheControlMiddleware)
+
 	// basic pages with static titles
 	router.Get(routeHome).Handler(handler(serveHome))
 	router.Get(routeThreads).Handler(handler(serveBrandedPageString("Threads", nil, noIndex)))
@@ -275,20 +291,13 @@ func initRouter(db dbutil.DB, router *mux.Router, codeIntelResolver graphqlbacke
 	}
 
 	// search
-	router.Get(routeSearch).Handler(handler(serveBasicPage(func(c *Common, r *http.Request) string {
-		shortQuery := limitString(r.URL.Query().Get("q"), 25, true)
-		if shortQuery == "" {
-			return globals.Branding().BrandName
-		}
-		// e.g. "myquery - Sourcegraph"
-		return brandNameSubtitle(shortQuery)
-	}, nil, index)))
+	router.Get(routeSearch).Handler(handler(serveSearch(db)))
 
 	// streaming search
 	router.Get(routeSearchStream).Handler(search.StreamHandler(db))
 
 	// search badge
-	router.Get(routeSearchBadge).Handler(searchBadgeHandler())
+	router.Get(routeSearchBadge).Handler(rateLimited(searchBadgeHandler()))
 
 	if envvar.SourcegraphDotComMode() {
 		// about subdomain
@@ -308,7 +317,7 @@ func initRouter(db dbutil.DB, router *mux.Router, codeIntelResolver graphqlbacke
 	// repo
 	serveRepoHandler := handler(serveRepoOrBlob(routeRepo, func(c *Common, r *http.Request) string {
 		// e.g. "gorilla/mux - Sourcegraph"
-		return brandNameSubtitle(repoShortName(c.Repo.Name))
+		return brandNameSubtitle(repoTitleName(c))
 	}))
 	router.Get(routeRepo).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Debug mode: register the __errorTest handler.
@@ -328,18 +337,18 @@ func initRouter(db dbutil.DB, router *mux.Router, codeIntelResolver graphqlbacke
 	router.Get(routeTree).Handler(handler(serveTree(func(c *Common, r *http.Request) string {
 		// e.g. "src - gorilla/mux - Sourcegraph"
 		dirName := path.Base(mux.Vars(r)["Path"])
-		return brandNameSubtitle(dirName, repoShortName(c.Repo.Name))
+		return brandNameSubtitle(dirName, repoTitleName(c))
 	})))
 
 	// blob
-	router.Get(routeBlob).Handler(handler(serveRepoOrBlob(routeBlob, func(c *Common, r *http.Request) string {
+	router.Get(routeBlob).Handler(rateLimited(handler(serveRepoOrBlob(routeBlob, func(c *Common, r *http.Request) string {
 		// e.g. "mux.go - gorilla/mux - Sourcegraph"
 		fileName := path.Base(mux.Vars(r)["Path"])
-		return brandNameSubtitle(fileName, repoShortName(c.Repo.Name))
-	})))
+		return brandNameSubtitle(fileName, repoTitleName(c))
+	}))))
 
 	// raw
-	router.Get(routeRaw).Handler(handler(serveRaw))
+	router.Get(routeRaw).Handler(rateLimited(handler(serveRaw)))
 
 	// All other routes that are not found.
 	router.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -390,7 +399,8 @@ func limitString(s string, n int, ellipsis bool) string {
 }
 
 // handler wraps an HTTP handler that returns potential errors. If any error is
-// returned, serveError is called.
+// returned, serveError is called. The steps of serving the page are recorded in a
+// page trace (see startPageTraceStep).
 //
 // Clients that wish to return their own HTTP status code should use this from
 // their handler:
@@ -400,6 +410,7 @@ func limitString(s string, n int, ellipsis bool) string {
 //
 func handler(f func(w http.ResponseWriter, r *http.Request) error) http.Handler {
 	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(withPageTrace(r.Context()))
 		defer func() {
 			if rec := recover(); rec != nil {
 				serveError(w, r, recoverError{recover: rec, stack: debug.Stack()}, http.StatusInternalServerError)
@@ -442,19 +453,52 @@ type pageError struct {
 	StatusText string `json:"statusText"`
 	Error      string `json:"error"`
 	ErrorID    string `json:"errorID"`
+
+	// Trace is the breakdown of the time spent serving the page. It is only shown to site
+	// admins.
+	Trace *pageErrorTrace `json:"trace,omitempty"`
+
+	// Help is shown on the fallback error page. The web app receives it in Common.ErrorHelp.
+	Help *errorHelp `json:"-"`
+}
+
+type pageErrorTrace struct {
+	URL   string          `json:"url,omitempty"`
+	Steps []pageTraceStep `json:"steps"`
+}
+
+// ServeMaintenanceModeError serves the error page for requests that are rejected because the
+// site is in maintenance mode, showing the maintenance mode message to the user.
+func ServeMaintenanceModeError(w http.ResponseWriter, r *http.Request, message string) {
+	dangerouslyServeError(w, r, errors.New(message), http.StatusServiceUnavailable)
 }
 
 // serveErrorNoDebug should not be called by anyone except serveErrorTest.
 func serveErrorNoDebug(w http.ResponseWriter, r *http.Request, err error, statusCode int, nodebug, forceServeError bool) {
+	// Error pages must never be cached, regardless of the caching policy of the route.
+	w.Header().Set("Cache-Control", "no-cache, max-age=0")
+	asJSON := wantsJSON(r)
+	if asJSON {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	}
 	w.WriteHeader(statusCode)
 	errorID := randstring.NewLen(6)
 
+	// Determine the breakdown of the time spent serving the page so far.
+	var steps []pageTraceStep
+	if t := pageTraceFromContext(r.Context()); t != nil {
+		steps = t.breakdown()
+	}
+
 	// Determine trace URL and log the error.
 	var traceURL string
 	if span := opentracing.SpanFromContext(r.Context()); span != nil {
 		ext.Error.Set(span, true)
 		span.SetTag("err", err)
 		span.SetTag("error-id", errorID)
+		for _, step := range steps {
+			span.LogFields(otlog.String("step", step.Name), otlog.Int64("duration_ms", step.Duration.Milliseconds()))
+		}
 		traceURL = trace.URL(trace.IDFromSpan(span))
 	}
 	log15.Error("ui HTTP handler error response", "method", r.Method, "request_uri", r.URL.RequestURI(), "status_code", statusCode, "error", err, "error_id", errorID, "trace", traceURL)
@@ -478,6 +522,17 @@ func serveErrorNoDebug(w http.ResponseWriter, r *http.Request, err error, status
 		StatusText: http.StatusText(statusCode),
 		Error:      errorIfDebug,
 		ErrorID:    errorID,
+		Help:       newErrorHelp(conf.Get().UiErrorPages, errorClassForStatus(statusCode)),
+	}
+	if steps != nil && !nodebug && backend.CheckCurrentUserIsSiteAdmin(r.Context(), dbconn.Global) == nil {
+		pageErrorContext.Trace = &pageErrorTrace{URL: traceURL, Steps: steps}
+	}
+
+	if asJSON {
+		if jsonErr := json.NewEncoder(w).Encode(pageErrorContext); jsonErr != nil {
+			log15.Error("ui: error while serving JSON error", "error", jsonErr)
+		}
+		return
 	}
 
 	// First try to render the error fancily: this relies on *Common
@@ -496,7 +551,8 @@ func serveErrorNoDebug(w http.ResponseWriter, r *http.Request, err error, status
 		}
 
 		common.Error = pageErrorContext
-		fancyErr := renderTemplate(w, "app.html", &struct {
+		common.ErrorHelp = pageErrorContext.Help
+		fancyErr := renderTemplate(r.Context(), w, "app.html", &struct {
 			*Common
 		}{
 			Common: common,
@@ -510,7 +566,7 @@ func serveErrorNoDebug(w http.ResponseWriter, r *http.Request, err error, status
 	}
 
 	// Fallback to ugly / reliable error template.
-	stdErr := renderTemplate(w, "error.html", pageErrorContext)
+	stdErr := renderTemplate(r.Context(), w, "error.html", pageErrorContext)
 	if stdErr != nil {
 		log15.Error("ui: error while serving final error template", "error", stdErr)
 	}
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"bytes"
+	"context"
 	"crypto/md5"
 	_ "embed"
 	"fmt"
@@ -118,7 +119,9 @@ func doLoadTemplate(path string) (*template.Template, error) {
 // is its file name, relative to the template directory.
 //
 // The given data is accessible in the template via $.Foobar
-func renderTemplate(w http.ResponseWriter, name string, data interface{}) error {
+func renderTemplate(ctx context.Context, w http.ResponseWriter, name string, data interface{}) error {
+	defer startPageTraceStep(ctx, "render template")()
+
 	root, err := loadTemplate(name)
 	if err != nil {
 		return err
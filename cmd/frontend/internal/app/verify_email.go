@@ -2,18 +2,24 @@ package app
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/inconshreveable/log15"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/cookie"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
 )
 
 func serveVerifyEmail(db dbutil.DB) func(w http.ResponseWriter, r *http.Request) {
@@ -45,11 +51,17 @@ func serveVerifyEmail(db dbutil.DB) func(w http.ResponseWriter, r *http.Request)
 			return
 		}
 		verified, err := database.UserEmails(db).Verify(ctx, usr.ID, email, verifyCode)
+		if err == database.ErrEmailVerificationLocked {
+			logEmailVerificationEvent(ctx, db, r, usr.ID, database.SecurityEventNameEmailVerificationLocked, email)
+			http.Error(w, "Too many incorrect email verification codes. Request a new verification email and try again.", http.StatusTooManyRequests)
+			return
+		}
 		if err != nil {
 			httpLogAndError(w, "Could not verify user email", http.StatusInternalServerError, "userID", usr.ID, "email", email, "error", err)
 			return
 		}
 		if !verified {
+			logEmailVerificationEvent(ctx, db, r, usr.ID, database.SecurityEventNameEmailVerificationFailed, email)
 			http.Error(w, "Could not verify user email. Email verification code did not match.", http.StatusUnauthorized)
 			return
 		}
@@ -62,7 +74,7 @@ func serveVerifyEmail(db dbutil.DB) func(w http.ResponseWriter, r *http.Request)
 			}
 		}
 
-		logEmailVerified(ctx, db, r, actr.UID)
+		logEmailVerificationEvent(ctx, db, r, actr.UID, database.SecurityEventNameEmailVerified, email)
 
 		if err = database.Authz(db).GrantPendingPermissions(ctx, &database.GrantPendingPermissionsArgs{
 			UserID: usr.ID,
@@ -76,12 +88,96 @@ func serveVerifyEmail(db dbutil.DB) func(w http.ResponseWriter, r *http.Request)
 	}
 }
 
-func logEmailVerified(ctx context.Context, db dbutil.DB, r *http.Request, userID int32) {
+// resendVerificationEmailCoolDown is the minimum duration between two verification emails sent to
+// the same email address.
+const resendVerificationEmailCoolDown = time.Minute
+
+// timeNow is mocked in tests.
+var timeNow = time.Now
+
+// serveResendVerificationEmail sends a new email verification code to an unverified email of the
+// current user. Each email address can be sent a verification email at most once per
+// resendVerificationEmailCoolDown. Sending a new code resets the failed verification attempts.
+func serveResendVerificationEmail(db dbutil.DB) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if !actor.FromContext(ctx).IsAuthenticated() {
+			http.Error(w, "Not authenticated", http.StatusUnauthorized)
+			return
+		}
+		if !conf.CanSendEmail() {
+			httpLogAndError(w, "Unable to send verification email because email sending is not configured on this site", http.StatusNotFound)
+			return
+		}
+
+		var formData struct {
+			Email string `json:"email"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&formData); err != nil {
+			httpLogAndError(w, "Could not decode resend verification email request body", http.StatusBadRequest, "err", err)
+			return
+		}
+
+		// 🚨 SECURITY: only allow resending verification emails to the current user's own emails
+		usr, err := database.Users(db).GetByCurrentAuthUser(ctx)
+		if err != nil {
+			httpLogAndError(w, "Could not get current user", http.StatusUnauthorized)
+			return
+		}
+		email, alreadyVerified, err := database.UserEmails(db).Get(ctx, usr.ID, formData.Email)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("No email %q found for user %d", formData.Email, usr.ID), http.StatusBadRequest)
+			return
+		}
+		if alreadyVerified {
+			http.Error(w, fmt.Sprintf("User %d email %q is already verified", usr.ID, email), http.StatusBadRequest)
+			return
+		}
+
+		// 🚨 SECURITY: rate limit verification emails per email address, which also limits the
+		// number of verification codes that can be guessed.
+		lastSent, err := database.UserEmails(db).GetLatestVerificationSentEmail(ctx, email)
+		if err != nil && !errcode.IsNotFound(err) {
+			httpLogAndError(w, "Could not get last verification email", http.StatusInternalServerError, "userID", usr.ID, "email", email, "error", err)
+			return
+		}
+		if lastSent != nil && lastSent.LastVerificationSentAt != nil {
+			if wait := resendVerificationEmailCoolDown - timeNow().Sub(*lastSent.LastVerificationSentAt); wait > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+				http.Error(w, "Last verification email sent too recently. Try again in a minute.", http.StatusTooManyRequests)
+				return
+			}
+		}
+
+		code, err := backend.MakeEmailVerificationCode()
+		if err != nil {
+			httpLogAndError(w, "Could not make email verification code", http.StatusInternalServerError, "error", err)
+			return
+		}
+		if err := database.UserEmails(db).SetLastVerification(ctx, usr.ID, email, code); err != nil {
+			httpLogAndError(w, "Could not set email verification code", http.StatusInternalServerError, "userID", usr.ID, "email", email, "error", err)
+			return
+		}
+		if err := backend.SendUserEmailVerificationEmail(ctx, usr.Username, email, code); err != nil {
+			httpLogAndError(w, "Could not send verification email", http.StatusInternalServerError, "userID", usr.ID, "email", email, "error", err)
+			return
+		}
+
+		logEmailVerificationEvent(ctx, db, r, usr.ID, database.SecurityEventNameEmailVerificationResent, email)
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func logEmailVerificationEvent(ctx context.Context, db dbutil.DB, r *http.Request, userID int32, name database.SecurityEventName, email string) {
+	arg, _ := json.Marshal(struct {
+		Email string `json:"email"`
+	}{Email: email})
+
 	event := &database.SecurityEvent{
-		Name:      database.SecurityEventNameEmailVerified,
+		Name:      name,
 		URL:       r.URL.Path,
 		UserID:    uint32(userID),
-		Argument:  nil,
+		Argument:  arg,
 		Source:    "BACKEND",
 		Timestamp: time.Now(),
 	}
@@ -4,15 +4,20 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/txemail"
 	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 func TestServeVerifyEmail(t *testing.T) {
@@ -94,4 +99,96 @@ func TestServeVerifyEmail(t *testing.T) {
 
 		assert.True(t, calledSetPrimaryEmail, "SetPrimaryEmail should be called")
 	})
+
+	t.Run("verification is locked", func(t *testing.T) {
+		database.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+			return &types.User{ID: 1}, nil
+		}
+		database.Mocks.UserEmails.Get = func(userID int32, email string) (emailCanonicalCase string, verified bool, err error) {
+			return "alice@example.com", false, nil
+		}
+		database.Mocks.UserEmails.Verify = func(ctx context.Context, userID int32, email, code string) (bool, error) {
+			return false, database.ErrEmailVerificationLocked
+		}
+		defer func() {
+			database.Mocks.Users = database.MockUsers{}
+			database.Mocks.UserEmails = database.MockUserEmails{}
+		}()
+
+		ctx := context.Background()
+		ctx = actor.WithActor(ctx, &actor.Actor{UID: 1})
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req = req.WithContext(ctx)
+		resp := httptest.NewRecorder()
+
+		handler := serveVerifyEmail(db)
+		handler(resp, req)
+
+		assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	})
+}
+
+func TestServeResendVerificationEmail(t *testing.T) {
+	db := new(dbtesting.MockDB)
+
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{EmailSmtp: &schema.SMTPServerConfig{}}})
+	defer conf.Mock(nil)
+
+	now := time.Now()
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	var sent []string
+	txemail.MockSend = func(ctx context.Context, message txemail.Message) error {
+		sent = append(sent, message.To...)
+		return nil
+	}
+	defer func() { txemail.MockSend = nil }()
+
+	database.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{ID: 1, Username: "alice"}, nil
+	}
+	database.Mocks.UserEmails.Get = func(userID int32, email string) (emailCanonicalCase string, verified bool, err error) {
+		return "alice@example.com", false, nil
+	}
+	var lastSentAt *time.Time
+	database.Mocks.UserEmails.GetLatestVerificationSentEmail = func(ctx context.Context, email string) (*database.UserEmail, error) {
+		return &database.UserEmail{LastVerificationSentAt: lastSentAt}, nil
+	}
+	database.Mocks.UserEmails.SetLastVerification = func(ctx context.Context, userID int32, email, code string) error {
+		sentAt := now
+		lastSentAt = &sentAt
+		return nil
+	}
+	defer func() {
+		database.Mocks.Users = database.MockUsers{}
+		database.Mocks.UserEmails = database.MockUserEmails{}
+	}()
+
+	resend := func() *httptest.ResponseRecorder {
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"email":"alice@example.com"}`))
+		req = req.WithContext(ctx)
+		resp := httptest.NewRecorder()
+
+		handler := serveResendVerificationEmail(db)
+		handler(resp, req)
+		return resp
+	}
+
+	resp := resend()
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, []string{"alice@example.com"}, sent)
+
+	// A second verification email within the cool down is rate limited.
+	resp = resend()
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	assert.Equal(t, "60", resp.Header().Get("Retry-After"))
+	assert.Len(t, sent, 1)
+
+	now = now.Add(resendVerificationEmailCoolDown)
+	resp = resend()
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Len(t, sent, 2)
 }
@@ -227,7 +227,6 @@ func HandleSignIn(db dbutil.DB) func(w http.ResponseWriter, r *http.Request) {
 		}
 
 		var usr types.User
-		var actor actor.Actor
 
 		var signInResult = database.SecurityEventNameSignInAttempted
 		logSignInEvent(r, db, &usr, &signInResult)
@@ -269,11 +268,17 @@ func HandleSignIn(db dbutil.DB) func(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		actor.UID = usr.ID
-
-		// Write the session cookie
-		if err := session.SetActor(w, r, &actor, 0, usr.CreatedAt); err != nil {
-			httpLogAndError(w, "Could not create new user session", http.StatusInternalServerError)
+		// Write the session cookie, unless the user must also provide a second factor.
+		secondFactor, err := signInWithSecondFactor(w, r, db, &usr)
+		if err != nil {
+			httpLogAndError(w, "Could not create new user session", http.StatusInternalServerError, "err", err)
+			return
+		}
+		if secondFactor != "" {
+			signInResult = database.SecurityEventNameSignInSecondFactorRequired
+			writeJSON(w, struct {
+				SecondFactor string `json:"secondFactor"`
+			}{SecondFactor: secondFactor})
 			return
 		}
 
@@ -0,0 +1,402 @@
+package userpasswd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/session"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/webauthn"
+)
+
+// The second factors that users can be asked to provide to complete signing in.
+const (
+	secondFactorSecurityKey             = "securityKey"
+	secondFactorSecurityKeyRegistration = "securityKeyRegistration"
+)
+
+// securityKeysRequired reports whether the user must sign in with a security key (per site config),
+// even if they have not registered one yet.
+func securityKeysRequired(usr *types.User) bool {
+	pc, _ := getProviderConfig()
+	if pc == nil || pc.SecurityKeys == nil {
+		return false
+	}
+	switch pc.SecurityKeys.Enforce {
+	case "allUsers":
+		return true
+	case "siteAdmins":
+		return usr.SiteAdmin
+	}
+	return false
+}
+
+// signInWithSecondFactor signs in the user, who provided their first factor (e.g. their password),
+// unless they must also provide a second factor. In that case, the sign-in is left pending in the
+// session and the second factor to provide is returned.
+//
+// 🚨 SECURITY: The caller must have verified the first factor of the user.
+func signInWithSecondFactor(w http.ResponseWriter, r *http.Request, db dbutil.DB, usr *types.User) (secondFactor string, err error) {
+	keys, err := database.UserSecurityKeys(db).ListByUserID(r.Context(), usr.ID)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case len(keys) > 0:
+		secondFactor = secondFactorSecurityKey
+	case securityKeysRequired(usr):
+		secondFactor = secondFactorSecurityKeyRegistration
+	default:
+		return "", session.SetActor(w, r, &actor.Actor{UID: usr.ID}, 0, usr.CreatedAt)
+	}
+
+	return secondFactor, session.SetSecondFactorState(w, r, &session.SecondFactorState{
+		UserID:               usr.ID,
+		SignInPending:        true,
+		RegistrationRequired: len(keys) == 0,
+	})
+}
+
+// completeSignIn signs in the user of a pending sign-in, once they provided their second factor.
+func completeSignIn(w http.ResponseWriter, r *http.Request, db dbutil.DB, userID int32) {
+	usr, err := database.Users(db).GetByID(r.Context(), userID)
+	if err != nil {
+		httpLogAndError(w, "Could not get user", http.StatusInternalServerError, "err", err)
+		return
+	}
+	if err := session.SetSecondFactorState(w, r, nil); err != nil {
+		httpLogAndError(w, "Could not update session", http.StatusInternalServerError, "err", err)
+		return
+	}
+	if err := session.SetActor(w, r, &actor.Actor{UID: usr.ID}, 0, usr.CreatedAt); err != nil {
+		httpLogAndError(w, "Could not create new user session", http.StatusInternalServerError)
+		return
+	}
+	logSecurityEvent(r, db, usr.ID, database.SecurityEventNameSignInSucceeded)
+}
+
+// relyingParty returns the WebAuthn relying party of this site, based on its external URL.
+func relyingParty() *webauthn.RelyingParty {
+	u := globals.ExternalURL()
+	return &webauthn.RelyingParty{
+		ID:     u.Hostname(),
+		Name:   "Sourcegraph",
+		Origin: (&url.URL{Scheme: u.Scheme, Host: u.Host}).String(),
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		httpLogAndError(w, "Could not encode response", http.StatusInternalServerError, "err", err)
+	}
+}
+
+// HandleSecurityKeySignInInit starts signing in with a security key, for a pending sign-in. It
+// responds with the options to pass to navigator.credentials.get() in the browser.
+func HandleSecurityKeySignInInit(db dbutil.DB) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if handleEnabledCheck(w) {
+			return
+		}
+
+		state, err := session.GetSecondFactorState(r)
+		if err != nil {
+			httpLogAndError(w, "Could not get session", http.StatusInternalServerError, "err", err)
+			return
+		}
+		if state == nil || !state.SignInPending || state.RegistrationRequired {
+			http.Error(w, "No sign-in is pending. Sign in again.", http.StatusUnauthorized)
+			return
+		}
+
+		keys, err := database.UserSecurityKeys(db).ListByUserID(r.Context(), state.UserID)
+		if err != nil {
+			httpLogAndError(w, "Could not list security keys", http.StatusInternalServerError, "err", err)
+			return
+		}
+		allow := make([][]byte, 0, len(keys))
+		for _, k := range keys {
+			allow = append(allow, k.CredentialID)
+		}
+
+		state.Challenge, err = webauthn.NewChallenge()
+		if err != nil {
+			httpLogAndError(w, "Could not create challenge", http.StatusInternalServerError, "err", err)
+			return
+		}
+		if err := session.SetSecondFactorState(w, r, state); err != nil {
+			httpLogAndError(w, "Could not update session", http.StatusInternalServerError, "err", err)
+			return
+		}
+
+		writeJSON(w, relyingParty().RequestOptions(state.Challenge, allow))
+	}
+}
+
+// HandleSecurityKeySignIn completes a pending sign-in with the response of a security key to the
+// challenge issued by HandleSecurityKeySignInInit.
+func HandleSecurityKeySignIn(db dbutil.DB) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if handleEnabledCheck(w) {
+			return
+		}
+
+		ctx := r.Context()
+		state, err := session.GetSecondFactorState(r)
+		if err != nil {
+			httpLogAndError(w, "Could not get session", http.StatusInternalServerError, "err", err)
+			return
+		}
+		if state == nil || !state.SignInPending || state.RegistrationRequired || len(state.Challenge) == 0 {
+			http.Error(w, "No sign-in is pending. Sign in again.", http.StatusUnauthorized)
+			return
+		}
+
+		var resp webauthn.AssertionResponse
+		if err := json.NewDecoder(r.Body).Decode(&resp); err != nil {
+			http.Error(w, "Could not decode request body", http.StatusBadRequest)
+			return
+		}
+
+		// 🚨 SECURITY: Each challenge can only be answered once.
+		challenge := state.Challenge
+		state.Challenge = nil
+		if err := session.SetSecondFactorState(w, r, state); err != nil {
+			httpLogAndError(w, "Could not update session", http.StatusInternalServerError, "err", err)
+			return
+		}
+
+		keys, err := database.UserSecurityKeys(db).ListByUserID(ctx, state.UserID)
+		if err != nil {
+			httpLogAndError(w, "Could not list security keys", http.StatusInternalServerError, "err", err)
+			return
+		}
+		var key *database.UserSecurityKey
+		for _, k := range keys {
+			if bytes.Equal(k.CredentialID, resp.RawID) {
+				key = k
+				break
+			}
+		}
+		if key == nil {
+			logSecurityEvent(r, db, state.UserID, database.SecurityEventNameSignInFailed)
+			httpLogAndError(w, "Security key is not registered", http.StatusUnauthorized)
+			return
+		}
+
+		// 🚨 SECURITY: Verify that the security key signed the challenge.
+		signCount, err := relyingParty().VerifySignIn(challenge, &resp, &webauthn.Credential{
+			ID:        key.CredentialID,
+			PublicKey: key.PublicKey,
+			SignCount: key.SignCount,
+		})
+		if err != nil {
+			logSecurityEvent(r, db, state.UserID, database.SecurityEventNameSignInFailed)
+			httpLogAndError(w, "Security key authentication failed", http.StatusUnauthorized, "err", err)
+			return
+		}
+		if err := database.UserSecurityKeys(db).UpdateSignCount(ctx, key.ID, signCount); err != nil {
+			httpLogAndError(w, "Could not update security key", http.StatusInternalServerError, "err", err)
+			return
+		}
+
+		completeSignIn(w, r, db, state.UserID)
+	}
+}
+
+// securityKeyRegistrant returns the ID of the user who can register a security key: the signed-in
+// user, or the user of a pending sign-in that requires registering one. It returns 0 if there is no
+// such user.
+func securityKeyRegistrant(r *http.Request, state *session.SecondFactorState) int32 {
+	if state != nil && state.SignInPending {
+		if state.RegistrationRequired {
+			return state.UserID
+		}
+		return 0
+	}
+	return actor.FromContext(r.Context()).UID
+}
+
+// HandleSecurityKeyRegisterInit starts registering a security key. It responds with the options to
+// pass to navigator.credentials.create() in the browser.
+func HandleSecurityKeyRegisterInit(db dbutil.DB) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if handleEnabledCheck(w) {
+			return
+		}
+
+		ctx := r.Context()
+		state, err := session.GetSecondFactorState(r)
+		if err != nil {
+			httpLogAndError(w, "Could not get session", http.StatusInternalServerError, "err", err)
+			return
+		}
+		userID := securityKeyRegistrant(r, state)
+		if userID == 0 {
+			http.Error(w, "Sign in to register a security key.", http.StatusUnauthorized)
+			return
+		}
+		if state == nil || !state.SignInPending {
+			state = &session.SecondFactorState{UserID: userID}
+		}
+
+		usr, err := database.Users(db).GetByID(ctx, userID)
+		if err != nil {
+			httpLogAndError(w, "Could not get user", http.StatusInternalServerError, "err", err)
+			return
+		}
+		keys, err := database.UserSecurityKeys(db).ListByUserID(ctx, userID)
+		if err != nil {
+			httpLogAndError(w, "Could not list security keys", http.StatusInternalServerError, "err", err)
+			return
+		}
+		exclude := make([][]byte, 0, len(keys))
+		for _, k := range keys {
+			exclude = append(exclude, k.CredentialID)
+		}
+
+		state.Challenge, err = webauthn.NewChallenge()
+		if err != nil {
+			httpLogAndError(w, "Could not create challenge", http.StatusInternalServerError, "err", err)
+			return
+		}
+		if err := session.SetSecondFactorState(w, r, state); err != nil {
+			httpLogAndError(w, "Could not update session", http.StatusInternalServerError, "err", err)
+			return
+		}
+
+		displayName := usr.DisplayName
+		if displayName == "" {
+			displayName = usr.Username
+		}
+		writeJSON(w, relyingParty().CreationOptions(state.Challenge, webauthn.User{
+			ID:          []byte(strconv.FormatInt(int64(usr.ID), 10)),
+			Name:        usr.Username,
+			DisplayName: displayName,
+		}, exclude))
+	}
+}
+
+// maxSecurityKeyNameLength is the maximum length of the names users give to their security keys.
+const maxSecurityKeyNameLength = 100
+
+// HandleSecurityKeyRegister registers a security key with its response to the challenge issued by
+// HandleSecurityKeyRegisterInit. If the registration was required to complete a pending sign-in,
+// the user is signed in.
+func HandleSecurityKeyRegister(db dbutil.DB) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if handleEnabledCheck(w) {
+			return
+		}
+
+		ctx := r.Context()
+		state, err := session.GetSecondFactorState(r)
+		if err != nil {
+			httpLogAndError(w, "Could not get session", http.StatusInternalServerError, "err", err)
+			return
+		}
+		if state == nil || len(state.Challenge) == 0 || securityKeyRegistrant(r, state) != state.UserID || state.UserID == 0 {
+			http.Error(w, "No security key registration is pending. Try again.", http.StatusUnauthorized)
+			return
+		}
+
+		var req struct {
+			Name       string                       `json:"name"`
+			Credential webauthn.AttestationResponse `json:"credential"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Could not decode request body", http.StatusBadRequest)
+			return
+		}
+		name := strings.TrimSpace(req.Name)
+		if name == "" {
+			name = "Security key"
+		}
+		if len(name) > maxSecurityKeyNameLength {
+			http.Error(w, "Security key name is too long", http.StatusBadRequest)
+			return
+		}
+
+		// 🚨 SECURITY: Each challenge can only be answered once.
+		challenge := state.Challenge
+		state.Challenge = nil
+		if err := session.SetSecondFactorState(w, r, state); err != nil {
+			httpLogAndError(w, "Could not update session", http.StatusInternalServerError, "err", err)
+			return
+		}
+
+		cred, err := relyingParty().VerifyRegistration(challenge, &req.Credential)
+		if err != nil {
+			httpLogAndError(w, "Security key registration failed", http.StatusBadRequest, "err", err)
+			return
+		}
+		err = database.UserSecurityKeys(db).Create(ctx, &database.UserSecurityKey{
+			UserID:       state.UserID,
+			Name:         name,
+			CredentialID: cred.ID,
+			PublicKey:    cred.PublicKey,
+			SignCount:    cred.SignCount,
+		})
+		if err == database.ErrSecurityKeyAlreadyRegistered {
+			http.Error(w, "Security key is already registered.", http.StatusConflict)
+			return
+		} else if err != nil {
+			httpLogAndError(w, "Could not register security key", http.StatusInternalServerError, "err", err)
+			return
+		}
+		logSecurityEvent(r, db, state.UserID, database.SecurityEventNameSecurityKeyRegistered)
+
+		if state.SignInPending {
+			completeSignIn(w, r, db, state.UserID)
+			return
+		}
+		if err := session.SetSecondFactorState(w, r, nil); err != nil {
+			httpLogAndError(w, "Could not update session", http.StatusInternalServerError, "err", err)
+			return
+		}
+	}
+}
+
+// HandleSecurityKeyDelete removes a security key of the signed-in user.
+func HandleSecurityKeyDelete(db dbutil.DB) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if handleEnabledCheck(w) {
+			return
+		}
+
+		a := actor.FromContext(r.Context())
+		if !a.IsAuthenticated() {
+			http.Error(w, "Sign in to remove a security key.", http.StatusUnauthorized)
+			return
+		}
+
+		var req struct {
+			ID int64 `json:"id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Could not decode request body", http.StatusBadRequest)
+			return
+		}
+
+		// 🚨 SECURITY: Users can only remove their own security keys.
+		err := database.UserSecurityKeys(db).Delete(r.Context(), a.UID, req.ID)
+		if err == database.ErrSecurityKeyNotFound {
+			http.Error(w, "Security key not found.", http.StatusNotFound)
+			return
+		} else if err != nil {
+			httpLogAndError(w, "Could not remove security key", http.StatusInternalServerError, "err", err)
+			return
+		}
+		logSecurityEvent(r, db, a.UID, database.SecurityEventNameSecurityKeyDeleted)
+	}
+}
@@ -0,0 +1,270 @@
+package userpasswd
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/session"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/webauthn"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestSecurityKeys(t *testing.T) {
+	mockSecurityKeysConfig := func(enforce string) {
+		conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+			AuthProviders: []schema.AuthProviders{{Builtin: &schema.BuiltinAuthProvider{
+				Type:             "builtin",
+				AllowSignInLinks: true,
+				SecurityKeys:     &schema.SecurityKeys{Enforce: enforce},
+			}}},
+			EmailSmtp: &schema.SMTPServerConfig{},
+		}})
+	}
+	defer conf.Mock(nil)
+
+	cleanup := session.ResetMockSessionStore(t)
+	defer cleanup()
+
+	key := newTestSecurityKey(t)
+	var keys []*database.UserSecurityKey
+	database.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id, Username: "alice"}, nil
+	}
+	database.Mocks.UserSignInLinks.Consume = func(ctx context.Context, token string) (int32, error) {
+		return 1, nil
+	}
+	database.Mocks.UserSecurityKeys.ListByUserID = func(ctx context.Context, userID int32) ([]*database.UserSecurityKey, error) {
+		return keys, nil
+	}
+	database.Mocks.UserSecurityKeys.Create = func(ctx context.Context, k *database.UserSecurityKey) error {
+		k.ID = int64(len(keys) + 1)
+		keys = append(keys, k)
+		return nil
+	}
+	database.Mocks.UserSecurityKeys.UpdateSignCount = func(ctx context.Context, id int64, signCount uint32) error {
+		keys[id-1].SignCount = signCount
+		return nil
+	}
+	defer func() {
+		database.Mocks.Users = database.MockUsers{}
+		database.Mocks.UserSignInLinks = database.MockUserSignInLinks{}
+		database.Mocks.UserSecurityKeys = database.MockUserSecurityKeys{}
+	}()
+
+	// Requests of a test case share a session.
+	var cookies []*http.Cookie
+	do := func(handler func(http.ResponseWriter, *http.Request), method, path string, body interface{}) *httptest.ResponseRecorder {
+		var b bytes.Buffer
+		if body != nil {
+			if err := json.NewEncoder(&b).Encode(body); err != nil {
+				t.Fatal(err)
+			}
+		}
+		req := httptest.NewRequest(method, path, &b)
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		if c := rec.Result().Cookies(); len(c) > 0 {
+			cookies = c
+		}
+		return rec
+	}
+	signIn := func() *httptest.ResponseRecorder {
+		return do(HandleSignInLink(nil), http.MethodPost, "/-/sign-in-link?token=c0ffee", nil)
+	}
+
+	t.Run("required registration", func(t *testing.T) {
+		cookies, keys = nil, nil
+		mockSecurityKeysConfig("allUsers")
+
+		rec := signIn()
+		if got, want := rec.Header().Get("Location"), "/sign-in?secondFactor=securityKeyRegistration"; got != want {
+			t.Fatalf("got redirect to %q, want %q", got, want)
+		}
+		if rec := do(HandleSecurityKeySignInInit(nil), http.MethodPost, "/-/security-keys/sign-in-init", nil); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("got status %d, want %d", rec.Code, http.StatusUnauthorized)
+		}
+
+		rec = do(HandleSecurityKeyRegisterInit(nil), http.MethodPost, "/-/security-keys/register-init", nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("got status %d, want %d", rec.Code, http.StatusOK)
+		}
+		var opts webauthn.CreationOptions
+		if err := json.NewDecoder(rec.Body).Decode(&opts); err != nil {
+			t.Fatal(err)
+		}
+		if opts.RP.ID != "example.com" || opts.User.Name != "alice" {
+			t.Fatalf("got unexpected creation options %+v", opts)
+		}
+
+		rec = do(HandleSecurityKeyRegister(nil), http.MethodPost, "/-/security-keys/register", map[string]interface{}{
+			"name":       "yubikey",
+			"credential": key.attest(t, opts.Challenge),
+		})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("got status %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+		}
+		if len(keys) != 1 || keys[0].Name != "yubikey" || keys[0].UserID != 1 {
+			t.Fatalf("got security keys %+v, want one registered", keys)
+		}
+
+		// The challenge was answered, it cannot be used again.
+		rec = do(HandleSecurityKeyRegister(nil), http.MethodPost, "/-/security-keys/register", map[string]interface{}{
+			"credential": key.attest(t, opts.Challenge),
+		})
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("got status %d, want %d", rec.Code, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("sign in", func(t *testing.T) {
+		cookies = nil
+		mockSecurityKeysConfig("optional")
+
+		// Without a pending sign-in, there is nothing to sign in to.
+		if rec := do(HandleSecurityKeySignInInit(nil), http.MethodPost, "/-/security-keys/sign-in-init", nil); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("got status %d, want %d", rec.Code, http.StatusUnauthorized)
+		}
+
+		rec := signIn()
+		if got, want := rec.Header().Get("Location"), "/sign-in?secondFactor=securityKey"; got != want {
+			t.Fatalf("got redirect to %q, want %q", got, want)
+		}
+
+		// Registering another security key requires being signed in.
+		if rec := do(HandleSecurityKeyRegisterInit(nil), http.MethodPost, "/-/security-keys/register-init", nil); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("got status %d, want %d", rec.Code, http.StatusUnauthorized)
+		}
+
+		rec = do(HandleSecurityKeySignInInit(nil), http.MethodPost, "/-/security-keys/sign-in-init", nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("got status %d, want %d", rec.Code, http.StatusOK)
+		}
+		var opts webauthn.RequestOptions
+		if err := json.NewDecoder(rec.Body).Decode(&opts); err != nil {
+			t.Fatal(err)
+		}
+		if len(opts.AllowCredentials) != 1 {
+			t.Fatalf("got allowed credentials %+v, want the registered one", opts.AllowCredentials)
+		}
+
+		// A signature of another challenge is rejected, and uses up the challenge.
+		if rec := do(HandleSecurityKeySignIn(nil), http.MethodPost, "/-/security-keys/sign-in", key.assert(t, []byte("other"), 1)); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("got status %d, want %d", rec.Code, http.StatusUnauthorized)
+		}
+		if rec := do(HandleSecurityKeySignIn(nil), http.MethodPost, "/-/security-keys/sign-in", key.assert(t, opts.Challenge, 1)); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("got status %d, want %d", rec.Code, http.StatusUnauthorized)
+		}
+
+		rec = do(HandleSecurityKeySignInInit(nil), http.MethodPost, "/-/security-keys/sign-in-init", nil)
+		if err := json.NewDecoder(rec.Body).Decode(&opts); err != nil {
+			t.Fatal(err)
+		}
+		if rec := do(HandleSecurityKeySignIn(nil), http.MethodPost, "/-/security-keys/sign-in", key.assert(t, opts.Challenge, 1)); rec.Code != http.StatusOK {
+			t.Fatalf("got status %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+		}
+		if keys[0].SignCount != 1 {
+			t.Errorf("got sign count %d, want 1", keys[0].SignCount)
+		}
+
+		// The sign-in is complete.
+		if rec := do(HandleSecurityKeySignInInit(nil), http.MethodPost, "/-/security-keys/sign-in-init", nil); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("got status %d, want %d", rec.Code, http.StatusUnauthorized)
+		}
+	})
+}
+
+// testSecurityKey is a software ES256 security key, registered with the relying party of
+// http://example.com.
+type testSecurityKey struct {
+	*ecdsa.PrivateKey
+	credentialID []byte
+}
+
+func newTestSecurityKey(t *testing.T) *testSecurityKey {
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testSecurityKey{PrivateKey: k, credentialID: []byte("credential")}
+}
+
+func (k *testSecurityKey) authData(flags byte, signCount uint32) []byte {
+	rpIDHash := sha256.Sum256([]byte("example.com"))
+	authData := append(rpIDHash[:], flags, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(authData[33:], signCount)
+	return authData
+}
+
+func (k *testSecurityKey) clientData(t *testing.T, typ string, challenge []byte) []byte {
+	data, err := json.Marshal(map[string]string{
+		"type":      typ,
+		"challenge": base64.RawURLEncoding.EncodeToString(challenge),
+		"origin":    "http://example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func (k *testSecurityKey) attest(t *testing.T, challenge []byte) map[string]interface{} {
+	// The COSE encoded public key: {1: 2, 3: -7, -1: 1, -2: x, -3: y}
+	x, y := make([]byte, 32), make([]byte, 32)
+	k.X.FillBytes(x)
+	k.Y.FillBytes(y)
+	publicKey := append([]byte{0xa5, 0x01, 0x02, 0x03, 0x26, 0x20, 0x01, 0x21, 0x58, 0x20}, x...)
+	publicKey = append(append(publicKey, 0x22, 0x58, 0x20), y...)
+
+	authData := k.authData(0x41, 0)
+	authData = append(authData, make([]byte, 16)...)
+	authData = append(authData, 0, byte(len(k.credentialID)))
+	authData = append(append(authData, k.credentialID...), publicKey...)
+
+	// The CBOR encoded attestation object: {"fmt": "none", "attStmt": {}, "authData": authData}
+	attestationObject := []byte{0xa3, 0x63, 'f', 'm', 't', 0x64, 'n', 'o', 'n', 'e', 0x67, 'a', 't', 't', 'S', 't', 'm', 't', 0xa0, 0x68, 'a', 'u', 't', 'h', 'D', 'a', 't', 'a', 0x58, byte(len(authData))}
+	attestationObject = append(attestationObject, authData...)
+
+	return map[string]interface{}{
+		"rawId": webauthn.Base64URL(k.credentialID),
+		"response": map[string]interface{}{
+			"clientDataJSON":    webauthn.Base64URL(k.clientData(t, "webauthn.create", challenge)),
+			"attestationObject": webauthn.Base64URL(attestationObject),
+		},
+	}
+}
+
+func (k *testSecurityKey) assert(t *testing.T, challenge []byte, signCount uint32) map[string]interface{} {
+	authData := k.authData(0x01, signCount)
+	clientData := k.clientData(t, "webauthn.get", challenge)
+	clientDataHash := sha256.Sum256(clientData)
+	h := sha256.Sum256(append(append([]byte(nil), authData...), clientDataHash[:]...))
+	sig, err := ecdsa.SignASN1(rand.Reader, k.PrivateKey, h[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return map[string]interface{}{
+		"rawId": webauthn.Base64URL(k.credentialID),
+		"response": map[string]interface{}{
+			"clientDataJSON":    webauthn.Base64URL(clientData),
+			"authenticatorData": webauthn.Base64URL(authData),
+			"signature":         webauthn.Base64URL(sig),
+		},
+	}
+}
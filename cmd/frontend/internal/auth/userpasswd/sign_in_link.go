@@ -0,0 +1,228 @@
+package userpasswd
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/csrf"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/app/router"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/cookie"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/txemail"
+	"github.com/sourcegraph/sourcegraph/internal/txemail/txtypes"
+)
+
+// signInLinkExpiry is how long a sign-in link can be used after it was sent.
+const signInLinkExpiry = 15 * time.Minute
+
+// SignInLinksEnabled reports whether users can sign in with one-time links sent by email (per site
+// config).
+func SignInLinksEnabled() bool {
+	pc, multiple := getProviderConfig()
+	return pc != nil && !multiple && pc.AllowSignInLinks && conf.CanSendEmail()
+}
+
+// HandleSignInLinkInit sends a one-time sign-in link to the verified email address of a user.
+//
+// 🚨 SECURITY: The response is the same whether or not a user has the email address, so that it
+// doesn't leak the existence of email addresses in the database. Failures after the lookup are
+// only logged.
+func HandleSignInLinkInit(db dbutil.DB) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if handleEnabledCheck(w) {
+			return
+		}
+		if !SignInLinksEnabled() {
+			http.Error(w, "Sign-in links are not enabled on this site.", http.StatusNotFound)
+			return
+		}
+
+		ctx := r.Context()
+		var formData struct {
+			Email string `json:"email"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&formData); err != nil {
+			httpLogAndError(w, "Could not decode sign-in link request body", http.StatusBadRequest, "err", err)
+			return
+		}
+		if formData.Email == "" {
+			httpLogAndError(w, "No email specified in sign-in link request", http.StatusBadRequest)
+			return
+		}
+
+		usr, err := database.Users(db).GetByVerifiedEmail(ctx, formData.Email)
+		if err != nil {
+			// 🚨 SECURITY: We don't show an error message when the user is not found
+			// as to not leak the existence of a given e-mail address in the database.
+			if !errcode.IsNotFound(err) {
+				httpLogAndError(w, "Failed to lookup user", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		token, err := database.UserSignInLinks(db).Create(ctx, usr.ID, signInLinkExpiry)
+		if err == database.ErrSignInLinkRateLimit {
+			// The previous links of the user are still valid.
+			log15.Warn("Too many sign-in link requests", "userID", usr.ID)
+			return
+		} else if err != nil {
+			log15.Error("Could not create sign-in link", "userID", usr.ID, "err", err)
+			return
+		}
+
+		signInLinkPath, _ := router.Router().Get(router.SignInLink).URLPath()
+		q := make(url.Values)
+		q.Set("token", token)
+		if err := txemail.Send(ctx, txemail.Message{
+			To:       []string{formData.Email},
+			Template: signInLinkEmailTemplates,
+			Data: struct {
+				Username string
+				URL      string
+				Host     string
+			}{
+				Username: usr.Username,
+				URL: globals.ExternalURL().ResolveReference(&url.URL{
+					Path:     signInLinkPath.Path,
+					RawQuery: q.Encode(),
+				}).String(),
+				Host: globals.ExternalURL().Host,
+			},
+		}); err != nil {
+			log15.Error("Could not send sign-in link email", "userID", usr.ID, "err", err)
+			return
+		}
+		logSecurityEvent(r, db, usr.ID, database.SecurityEventNameSignInLinkRequested)
+	}
+}
+
+var signInLinkEmailTemplates = txemail.MustValidate(txtypes.Templates{
+	Subject: `Sign in to Sourcegraph ({{.Host}})`,
+	Text: `
+Somebody (likely you) requested a link to sign in as {{.Username}} on Sourcegraph ({{.Host}}).
+
+To sign in, follow this link within 15 minutes. The link can only be used once:
+
+  {{.URL}}
+
+If you did not request this link, you can ignore this email.
+`,
+	HTML: `
+<p>
+  Somebody (likely you) requested a link to sign in as <strong>{{.Username}}</strong>
+  on Sourcegraph ({{.Host}}).
+</p>
+
+<p><strong><a href="{{.URL}}">Sign in as {{.Username}}</a></strong></p>
+
+<p>The link expires in 15 minutes and can only be used once. If you did not request it, you can ignore this email.</p>
+`,
+})
+
+// signInLinkConfirmTemplate is the page of a sign-in link, which asks the user to confirm the
+// sign-in.
+var signInLinkConfirmTemplate = template.Must(template.New("").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Sign in to Sourcegraph</title></head>
+<body>
+<form method="POST">
+{{.CSRFField}}
+<input type="hidden" name="token" value="{{.Token}}">
+<p>Sign in to Sourcegraph ({{.Host}}) with your one-time sign-in link.</p>
+<button type="submit">Sign in</button>
+</form>
+</body>
+</html>
+`))
+
+// HandleSignInLink serves the page of a one-time sign-in link on GET requests. The page posts the
+// link back to sign in the user of the link and redirect to the homepage.
+//
+// 🚨 SECURITY: The link is only consumed on POST requests, so that email scanners and link
+// previews that follow the link don't use it up.
+func HandleSignInLink(db dbutil.DB) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if handleEnabledCheck(w) {
+			return
+		}
+		if !SignInLinksEnabled() {
+			http.Error(w, "Sign-in links are not enabled on this site.", http.StatusNotFound)
+			return
+		}
+
+		if r.Method == http.MethodGet {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Header().Set("Cache-Control", "no-store")
+			// Don't leak the token to the origins of resources of the page.
+			w.Header().Set("Referrer-Policy", "no-referrer")
+			if err := signInLinkConfirmTemplate.Execute(w, struct {
+				CSRFField template.HTML
+				Token     string
+				Host      string
+			}{
+				CSRFField: csrf.TemplateField(r),
+				Token:     r.URL.Query().Get("token"),
+				Host:      globals.ExternalURL().Host,
+			}); err != nil {
+				log15.Error("Could not render sign-in link page", "err", err)
+			}
+			return
+		}
+
+		ctx := r.Context()
+
+		// 🚨 SECURITY: Consuming the sign-in link ensures that it can only be used once.
+		userID, err := database.UserSignInLinks(db).Consume(ctx, r.FormValue("token"))
+		if err == database.ErrSignInLinkNotFound {
+			logSecurityEvent(r, db, 0, database.SecurityEventNameSignInFailed)
+			http.Error(w, "Sign-in link is invalid or expired. Request a new sign-in link and try again.", http.StatusUnauthorized)
+			return
+		} else if err != nil {
+			httpLogAndError(w, "Could not use sign-in link", http.StatusInternalServerError, "err", err)
+			return
+		}
+
+		usr, err := database.Users(db).GetByID(ctx, userID)
+		if err != nil {
+			httpLogAndError(w, "Could not get user", http.StatusInternalServerError, "err", err)
+			return
+		}
+
+		// Write the session cookie, unless the user must also provide a second factor.
+		secondFactor, err := signInWithSecondFactor(w, r, db, usr)
+		if err != nil {
+			httpLogAndError(w, "Could not create new user session", http.StatusInternalServerError, "err", err)
+			return
+		}
+
+		logSecurityEvent(r, db, usr.ID, database.SecurityEventNameSignInLinkUsed)
+		if secondFactor != "" {
+			http.Redirect(w, r, "/sign-in?"+url.Values{"secondFactor": {secondFactor}}.Encode(), http.StatusFound)
+			return
+		}
+		http.Redirect(w, r, "/", http.StatusFound)
+	}
+}
+
+func logSecurityEvent(r *http.Request, db dbutil.DB, userID int32, name database.SecurityEventName) {
+	event := &database.SecurityEvent{
+		Name:      name,
+		URL:       r.URL.Path,
+		UserID:    uint32(userID),
+		Source:    "BACKEND",
+		Timestamp: time.Now(),
+	}
+
+	// Safe to ignore this error
+	event.AnonymousUserID, _ = cookie.AnonymousUID(r)
+	database.SecurityEventLogs(db).LogEvent(r.Context(), event)
+}
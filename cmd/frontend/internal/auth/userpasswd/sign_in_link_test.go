@@ -0,0 +1,173 @@
+package userpasswd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/session"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/txemail"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestHandleSignInLink(t *testing.T) {
+	mockSignInLinksConfig := func(allow bool) {
+		conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+			AuthProviders: []schema.AuthProviders{{Builtin: &schema.BuiltinAuthProvider{Type: "builtin", AllowSignInLinks: allow}}},
+			EmailSmtp:     &schema.SMTPServerConfig{},
+		}})
+	}
+	defer conf.Mock(nil)
+
+	cleanup := session.ResetMockSessionStore(t)
+	defer cleanup()
+
+	var sent *txemail.Message
+	txemail.MockSend = func(ctx context.Context, message txemail.Message) error {
+		sent = &message
+		return nil
+	}
+	defer func() { txemail.MockSend = nil }()
+
+	database.Mocks.Users.GetByVerifiedEmail = func(ctx context.Context, email string) (*types.User, error) {
+		if email != "alice@example.com" {
+			return nil, database.MockUserNotFoundErr
+		}
+		return &types.User{ID: 1, Username: "alice"}, nil
+	}
+	database.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id, Username: "alice"}, nil
+	}
+	var rateLimited bool
+	database.Mocks.UserSignInLinks.Create = func(ctx context.Context, userID int32, expiry time.Duration) (string, error) {
+		if rateLimited {
+			return "", database.ErrSignInLinkRateLimit
+		}
+		return "c0ffee", nil
+	}
+	var consumed int
+	database.Mocks.UserSignInLinks.Consume = func(ctx context.Context, token string) (int32, error) {
+		consumed++
+		if token != "c0ffee" {
+			return 0, database.ErrSignInLinkNotFound
+		}
+		return 1, nil
+	}
+	database.Mocks.UserSecurityKeys.ListByUserID = func(ctx context.Context, userID int32) ([]*database.UserSecurityKey, error) {
+		return nil, nil
+	}
+	defer func() {
+		database.Mocks.Users = database.MockUsers{}
+		database.Mocks.UserSignInLinks = database.MockUserSignInLinks{}
+		database.Mocks.UserSecurityKeys = database.MockUserSecurityKeys{}
+	}()
+
+	requestLinkFor := func(email string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/-/sign-in-link-init", strings.NewReader(`{"email":"`+email+`"}`))
+		rec := httptest.NewRecorder()
+		HandleSignInLinkInit(nil)(rec, req)
+		return rec
+	}
+	requestLink := func() *httptest.ResponseRecorder { return requestLinkFor("alice@example.com") }
+	openLink := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/-/sign-in-link?token="+token, nil)
+		rec := httptest.NewRecorder()
+		HandleSignInLink(nil)(rec, req)
+		return rec
+	}
+	signIn := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/-/sign-in-link", strings.NewReader(url.Values{"token": {token}}.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		HandleSignInLink(nil)(rec, req)
+		return rec
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		mockSignInLinksConfig(false)
+		if rec := requestLink(); rec.Code != http.StatusNotFound {
+			t.Errorf("got status %d, want %d", rec.Code, http.StatusNotFound)
+		}
+		if rec := signIn("c0ffee"); rec.Code != http.StatusNotFound {
+			t.Errorf("got status %d, want %d", rec.Code, http.StatusNotFound)
+		}
+	})
+
+	t.Run("request link", func(t *testing.T) {
+		mockSignInLinksConfig(true)
+		if rec := requestLink(); rec.Code != http.StatusOK {
+			t.Fatalf("got status %d, want %d", rec.Code, http.StatusOK)
+		}
+		if sent == nil {
+			t.Fatal("want sign-in link email to be sent")
+		}
+		if got, want := sent.Data.(struct {
+			Username string
+			URL      string
+			Host     string
+		}).URL, "http://example.com/-/sign-in-link?token=c0ffee"; got != want {
+			t.Errorf("got URL %q, want %q", got, want)
+		}
+	})
+
+	t.Run("same response for unknown and rate limited emails", func(t *testing.T) {
+		mockSignInLinksConfig(true)
+		want := requestLink()
+
+		sent = nil
+		rateLimited = true
+		defer func() { rateLimited = false }()
+		for _, email := range []string{"alice@example.com", "bob@example.com"} {
+			rec := requestLinkFor(email)
+			if rec.Code != want.Code || rec.Body.String() != want.Body.String() {
+				t.Errorf("%s: got response %d %q, want %d %q", email, rec.Code, rec.Body.String(), want.Code, want.Body.String())
+			}
+		}
+		if sent != nil {
+			t.Error("want no sign-in link email to be sent")
+		}
+	})
+
+	t.Run("confirm page", func(t *testing.T) {
+		mockSignInLinksConfig(true)
+		consumed = 0
+		rec := openLink("c0ffee")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("got status %d, want %d", rec.Code, http.StatusOK)
+		}
+		if body := rec.Body.String(); !strings.Contains(body, `<form method="POST">`) || !strings.Contains(body, `value="c0ffee"`) {
+			t.Errorf("want a form posting the token, got %q", body)
+		}
+		if consumed != 0 {
+			t.Error("want the link not to be consumed")
+		}
+		if len(rec.Result().Cookies()) != 0 {
+			t.Error("want no session cookie to be set")
+		}
+	})
+
+	t.Run("invalid link", func(t *testing.T) {
+		mockSignInLinksConfig(true)
+		if rec := signIn("bad"); rec.Code != http.StatusUnauthorized {
+			t.Errorf("got status %d, want %d", rec.Code, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("valid link", func(t *testing.T) {
+		mockSignInLinksConfig(true)
+		rec := signIn("c0ffee")
+		if rec.Code != http.StatusFound {
+			t.Fatalf("got status %d, want %d", rec.Code, http.StatusFound)
+		}
+		if len(rec.Result().Cookies()) == 0 {
+			t.Error("want session cookie to be set")
+		}
+	})
+}
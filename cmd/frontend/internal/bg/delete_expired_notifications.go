@@ -0,0 +1,25 @@
+package bg
+
+import (
+	"context"
+	"time"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// DeleteExpiredNotifications periodically deletes the in-product notifications of
+// users that expired.
+func DeleteExpiredNotifications(ctx context.Context, db dbutil.DB) {
+	for {
+		deleted, err := database.Notifications(db).DeleteExpired(ctx)
+		if err != nil {
+			log15.Error("deleting expired notifications", "error", err)
+		} else if deleted > 0 {
+			log15.Debug("deleted expired notifications", "count", deleted)
+		}
+		time.Sleep(time.Hour)
+	}
+}
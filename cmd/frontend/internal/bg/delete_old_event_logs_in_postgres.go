@@ -6,32 +6,54 @@ import (
 
 	"github.com/inconshreveable/log15"
 
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 )
 
-func DeleteOldEventLogsInPostgres(ctx context.Context, db dbutil.DB) {
+// eventLogsPartitionsAhead is the number of monthly partitions of the event_logs table
+// that are created in advance of the current month.
+const eventLogsPartitionsAhead = 2
+
+// MaintainEventLogsInPostgres creates the upcoming partitions of the event_logs table,
+// and deletes the expired event logs by dropping the partitions which only hold expired
+// events, then deleting the remaining expired rows.
+func MaintainEventLogsInPostgres(ctx context.Context, db dbutil.DB) {
+	store := database.EventLogs(db)
 	for {
+		now := time.Now()
+		if err := store.CreatePartitions(ctx, now, eventLogsPartitionsAhead); err != nil {
+			log15.Error("creating partitions of event_logs table", "error", err)
+		}
+
 		// We choose 93 days as the interval to ensure that we have at least the last three months
 		// of logs at all times.
-		_, err := db.ExecContext(
-			ctx,
-			`DELETE FROM event_logs WHERE "timestamp" < now() - interval '93' day`,
-		)
-		if err != nil {
+		before := now.AddDate(0, 0, -93)
+		if dropped, err := store.DropPartitionsBefore(ctx, before); err != nil {
+			log15.Error("dropping expired partitions of event_logs table", "error", err)
+		} else {
+			for _, p := range dropped {
+				log15.Info("dropped expired partition of event_logs table", "partition", p.Name)
+			}
+		}
+		if err := store.DeleteOlderThan(ctx, before); err != nil {
 			log15.Error("deleting expired rows from event_logs table", "error", err)
 		}
 		time.Sleep(time.Hour)
 	}
 }
 
+// DeleteOldSecurityEventLogsInPostgres deletes the security events that are older than the
+// retention period of the log.securityEventLogs site configuration.
 func DeleteOldSecurityEventLogsInPostgres(ctx context.Context, db dbutil.DB) {
 	for {
-		// We choose 7 days as the interval to ensure that we have at least the last week's worth of
-		// logs at all times.
-		_, err := db.ExecContext(
-			ctx,
-			`DELETE FROM security_event_logs WHERE "timestamp" < now() - interval '7' day`,
-		)
+		// We choose 7 days as the default interval to ensure that we have at least the last week's
+		// worth of logs at all times.
+		retentionDays := 7
+		if log := conf.Get().Log; log != nil && log.SecurityEventLogs != nil && log.SecurityEventLogs.RetentionDays > 0 {
+			retentionDays = log.SecurityEventLogs.RetentionDays
+		}
+		_, err := database.SecurityEventLogs(db).DeleteOlderThan(ctx, time.Now().AddDate(0, 0, -retentionDays))
 		if err != nil {
 			log15.Error("deleting expired rows from security_event_logs table", "error", err)
 		}
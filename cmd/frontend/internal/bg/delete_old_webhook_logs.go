@@ -0,0 +1,29 @@
+package bg
+
+import (
+	"context"
+	"time"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// webhookLogsRetention is how long the webhooks received from code hosts are kept
+// for debugging and replaying them.
+const webhookLogsRetention = 7 * 24 * time.Hour
+
+// DeleteOldWebhookLogs periodically deletes the webhook logs older than
+// webhookLogsRetention.
+func DeleteOldWebhookLogs(ctx context.Context, db dbutil.DB) {
+	for {
+		deleted, err := database.WebhookLogs(db).DeleteStale(ctx, time.Now().Add(-webhookLogsRetention))
+		if err != nil {
+			log15.Error("deleting old webhook logs", "error", err)
+		} else if deleted > 0 {
+			log15.Debug("deleted old webhook logs", "count", deleted)
+		}
+		time.Sleep(time.Hour)
+	}
+}
@@ -0,0 +1,26 @@
+package bg
+
+import (
+	"context"
+	"time"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// DeleteStaleTemporarySettingsKeys deletes the keys that have been removed from the
+// temporary settings schema from the temporary settings of all users. The schema only
+// changes on upgrades, so this runs once on startup and then daily.
+func DeleteStaleTemporarySettingsKeys(ctx context.Context, db dbutil.DB) {
+	for {
+		updated, err := database.TemporarySettings(db).DeleteStaleTemporarySettingsKeys(ctx)
+		if err != nil {
+			log15.Error("deleting stale keys from temporary_settings table", "error", err)
+		} else if updated > 0 {
+			log15.Info("deleted stale keys from temporary_settings table", "users", updated)
+		}
+		time.Sleep(24 * time.Hour)
+	}
+}
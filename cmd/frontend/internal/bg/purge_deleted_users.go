@@ -0,0 +1,35 @@
+package bg
+
+import (
+	"context"
+	"time"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// purgeDeletedUsersBatchSize is the maximum number of users permanently deleted at once.
+const purgeDeletedUsersBatchSize = 100
+
+// PurgeDeletedUsers periodically deletes the deleted users whose grace period has passed (see
+// the auth.userDeletionGracePeriodDays site configuration), along with the resources they own.
+func PurgeDeletedUsers(ctx context.Context, db dbutil.DB) {
+	ctx = actor.WithInternalActor(ctx)
+	for {
+		purged, err := database.Users(db).PurgeDeleted(ctx, purgeDeletedUsersBatchSize)
+		if err != nil {
+			log15.Error("purging deleted users", "error", err)
+		}
+		if len(purged) > 0 {
+			log15.Info("purged deleted users", "ids", purged)
+		}
+		// Keep going right away if there are more users to purge.
+		if err == nil && len(purged) == purgeDeletedUsersBatchSize {
+			continue
+		}
+		time.Sleep(10 * time.Minute)
+	}
+}
@@ -56,6 +56,8 @@ var (
 
 	printLogo, _ = strconv.ParseBool(env.Get("LOGO", "false", "print Sourcegraph logo upon startup"))
 
+	postDeployMigrations, _ = strconv.ParseBool(env.Get("SRC_RUN_POST_DEPLOY_MIGRATIONS", "false", "run the post-deploy database migrations on startup. Only enable once all instances run the current version."))
+
 	httpAddr         = env.Get("SRC_HTTP_ADDR", ":3080", "HTTP listen address for app and HTTP API")
 	httpAddrInternal = envvar.HTTPAddrInternal
 
@@ -119,6 +121,11 @@ func InitDB() (*sql.DB, error) {
 		if err := dbconn.MigrateDB(dbconn.Global, dbconn.Frontend); err != nil {
 			return nil, err
 		}
+		if postDeployMigrations {
+			if err := dbconn.MigratePostDeploy(dbconn.Global, dbconn.Frontend); err != nil {
+				return nil, err
+			}
+		}
 
 		migrate = false
 	}
@@ -190,6 +197,11 @@ func Main(enterpriseSetupHook func(db dbutil.DB, outOfBandMigrationRunner *oobmi
 	if err := outOfBandMigrationRunner.Register(extAccMigrator.ID(), extAccMigrator, oobmigration.MigratorOptions{Interval: 3 * time.Second}); err != nil {
 		log.Fatalf("failed to run user external account encryption job: %v", err)
 	}
+	// Run a background job to move the events of previous versions to the partitioned event_logs table.
+	eventLogsMigrator := database.NewEventLogsMigratorWithDB(db)
+	if err := outOfBandMigrationRunner.Register(eventLogsMigrator.ID(), eventLogsMigrator, oobmigration.MigratorOptions{Interval: 3 * time.Second}); err != nil {
+		log.Fatalf("failed to run event logs migration job: %v", err)
+	}
 
 	// Run enterprise setup hook
 	enterprise := enterpriseSetupHook(db, outOfBandMigrationRunner)
@@ -245,13 +257,25 @@ func Main(enterpriseSetupHook func(db dbutil.DB, outOfBandMigrationRunner *oobmi
 
 	siteid.Init()
 
+	// Event logs are buffered and inserted in batches by a background routine.
+	eventLogBuffer := database.NewEventLogBuffer(db)
+	database.SetEventLogBuffer(eventLogBuffer)
+
+	// User activity is coalesced in memory and recorded periodically by a background routine.
+	userActivityRecorder := database.NewUserActivityRecorder(db)
+	database.SetUserActivityRecorder(userActivityRecorder)
+
 	globals.WatchExternalURL(defaultExternalURL(nginxAddr, httpAddr))
 	globals.WatchPermissionsUserMapping()
 
 	goroutine.Go(func() { bg.CheckRedisCacheEvictionPolicy() })
 	goroutine.Go(func() { bg.DeleteOldCacheDataInRedis() })
-	goroutine.Go(func() { bg.DeleteOldEventLogsInPostgres(context.Background(), db) })
+	goroutine.Go(func() { bg.MaintainEventLogsInPostgres(context.Background(), db) })
 	goroutine.Go(func() { bg.DeleteOldSecurityEventLogsInPostgres(context.Background(), db) })
+	goroutine.Go(func() { bg.DeleteStaleTemporarySettingsKeys(context.Background(), db) })
+	goroutine.Go(func() { bg.DeleteExpiredNotifications(context.Background(), db) })
+	goroutine.Go(func() { bg.DeleteOldWebhookLogs(context.Background(), db) })
+	goroutine.Go(func() { bg.PurgeDeletedUsers(context.Background(), db) })
 	goroutine.Go(func() { updatecheck.Start(db) })
 
 	// Parse GraphQL schema and set up resolvers that depend on dbconn.Global
@@ -265,17 +289,18 @@ func Main(enterpriseSetupHook func(db dbutil.DB, outOfBandMigrationRunner *oobmi
 		return err
 	}
 
-	rateLimitWatcher, err := makeRateLimitWatcher()
+	rateLimitWatcher, internalRateLimitWatcher, err := makeRateLimitWatchers()
 	if err != nil {
 		return err
 	}
+	graphqlbackend.GetAccessTokenRateLimit = rateLimitWatcher.AccessTokenUsage
 
 	server, err := makeExternalAPI(db, schema, enterprise, rateLimitWatcher)
 	if err != nil {
 		return err
 	}
 
-	internalAPI, err := makeInternalAPI(schema, db, enterprise, rateLimitWatcher)
+	internalAPI, err := makeInternalAPI(schema, db, enterprise, internalRateLimitWatcher)
 	if err != nil {
 		return err
 	}
@@ -283,6 +308,8 @@ func Main(enterpriseSetupHook func(db dbutil.DB, outOfBandMigrationRunner *oobmi
 	routines := []goroutine.BackgroundRoutine{
 		server,
 		outOfBandMigrationRunner,
+		eventLogBuffer,
+		userActivityRecorder,
 	}
 	if internalAPI != nil {
 		routines = append(routines, internalAPI)
@@ -302,7 +329,7 @@ func Main(enterpriseSetupHook func(db dbutil.DB, outOfBandMigrationRunner *oobmi
 
 func makeExternalAPI(db dbutil.DB, schema *graphql.Schema, enterprise enterprise.Services, rateLimiter graphqlbackend.LimitWatcher) (goroutine.BackgroundRoutine, error) {
 	// Create the external HTTP handler.
-	externalHandler, err := newExternalHTTPHandler(db, schema, enterprise.GitHubWebhook, enterprise.GitLabWebhook, enterprise.BitbucketServerWebhook, enterprise.NewCodeIntelUploadHandler, enterprise.NewExecutorProxyHandler, rateLimiter)
+	externalHandler, err := newExternalHTTPHandler(db, schema, enterprise.GitHubWebhook, enterprise.GitLabWebhook, enterprise.BitbucketServerWebhook, enterprise.BitbucketCloudWebhook, enterprise.AzureDevOpsWebhook, enterprise.NewCodeIntelUploadHandler, enterprise.NewExecutorProxyHandler, rateLimiter)
 	if err != nil {
 		return nil, err
 	}
@@ -357,10 +384,13 @@ func isAllowedOrigin(origin string, allowedOrigins []string) bool {
 	return false
 }
 
-func makeRateLimitWatcher() (*graphqlbackend.BasicLimitWatcher, error) {
+// makeRateLimitWatchers returns the rate limiters of the GraphQL endpoint of the external and the
+// internal API. The limits of the api.ratelimit site configuration only apply to the external API.
+func makeRateLimitWatchers() (*graphqlbackend.APILimitWatcher, *graphqlbackend.BasicLimitWatcher, error) {
 	ratelimitStore, err := redigostore.New(redispool.Cache, "gql:rl:", 0)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return graphqlbackend.NewBasicLimitWatcher(ratelimitStore), nil
+	basic := graphqlbackend.NewBasicLimitWatcher(ratelimitStore)
+	return graphqlbackend.NewAPILimitWatcher(graphqlbackend.NewRateLimiteWatcher(ratelimitStore), basic), basic, nil
 }
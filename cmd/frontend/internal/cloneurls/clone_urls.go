@@ -46,6 +46,7 @@ func ReposourceCloneURLToRepoName(ctx context.Context, db dbutil.DB, cloneURL st
 			extsvc.KindGitHub,
 			extsvc.KindGitLab,
 			extsvc.KindBitbucketServer,
+			extsvc.KindAzureDevOps,
 			extsvc.KindAWSCodeCommit,
 			extsvc.KindGitolite,
 			extsvc.KindPhabricator,
@@ -126,6 +127,12 @@ func getRepoNameFromService(ctx context.Context, cloneURL string, svc *types.Ext
 	case *schema.BitbucketServerConnection:
 		rs = reposource.BitbucketServer{BitbucketServerConnection: c}
 		host = c.Url
+	case *schema.GiteaConnection:
+		rs = reposource.Gitea{GiteaConnection: c}
+		host = c.Url
+	case *schema.AzureDevOpsConnection:
+		rs = reposource.AzureDevOps{AzureDevOpsConnection: c}
+		host = c.Url
 	case *schema.AWSCodeCommitConnection:
 		rs = reposource.AWS{AWSCodeCommitConnection: c}
 		// AWS type does not have URL
@@ -0,0 +1,112 @@
+package handlerutil
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// ResolveRepoAlias returns the canonical repository name of the given name if it matches
+// one of the repository aliases configured in the site configuration ("repoAliases").
+func ResolveRepoAlias(name api.RepoName) (api.RepoName, bool) {
+	return resolveRepoAlias(conf.Get().RepoAliases, name)
+}
+
+// RepoAlias returns the alias of the given canonical repository name, if any. It is the
+// reverse of ResolveRepoAlias.
+func RepoAlias(name api.RepoName) (api.RepoName, bool) {
+	return reverseRepoAlias(conf.Get().RepoAliases, name)
+}
+
+func resolveRepoAlias(aliases []*schema.RepoAlias, name api.RepoName) (api.RepoName, bool) {
+	return replaceMostSpecificPrefix(aliases, name, func(a *schema.RepoAlias) (string, string) { return a.Alias, a.Name })
+}
+
+func reverseRepoAlias(aliases []*schema.RepoAlias, name api.RepoName) (api.RepoName, bool) {
+	return replaceMostSpecificPrefix(aliases, name, func(a *schema.RepoAlias) (string, string) { return a.Name, a.Alias })
+}
+
+// canonicalRepoAlias is like resolveRepoAlias, but only resolves names that a redirect
+// can't loop on: the canonical name must differ from the given name and must not be an
+// alias itself, e.g. for an alias of "src" to "src/main".
+func canonicalRepoAlias(aliases []*schema.RepoAlias, name api.RepoName) (api.RepoName, bool) {
+	canonical, ok := resolveRepoAlias(aliases, name)
+	if !ok || strings.EqualFold(string(canonical), string(name)) {
+		return "", false
+	}
+	if _, ok := resolveRepoAlias(aliases, canonical); ok {
+		return "", false
+	}
+	return canonical, true
+}
+
+// replaceMostSpecificPrefix replaces the longest prefix of a repository name that matches one of
+// the aliases, e.g. an alias of "github.com/org/foo" wins over an alias of "github.com/org".
+func replaceMostSpecificPrefix(aliases []*schema.RepoAlias, name api.RepoName, prefixAndReplacement func(*schema.RepoAlias) (string, string)) (api.RepoName, bool) {
+	var replaced string
+	var matchLen int
+	for _, a := range aliases {
+		prefix, replacement := prefixAndReplacement(a)
+		if len(prefix) <= matchLen {
+			continue
+		}
+		if r, ok := replaceRepoNamePrefix(string(name), prefix, replacement); ok {
+			replaced, matchLen = r, len(prefix)
+		}
+	}
+	return api.RepoName(replaced), matchLen > 0
+}
+
+// replaceRepoNamePrefix replaces the prefix of a repository name with another one. The prefix
+// must match whole path components of the name, so that the prefix "src" matches "src" and
+// "src/foo", but not "srcfoo".
+func replaceRepoNamePrefix(name, prefix, replacement string) (string, bool) {
+	prefix = strings.TrimSuffix(prefix, "/")
+	replacement = strings.TrimSuffix(replacement, "/")
+	if prefix == "" {
+		return "", false
+	}
+	if strings.EqualFold(name, prefix) {
+		return replacement, true
+	}
+	if len(name) > len(prefix) && strings.EqualFold(name[:len(prefix)], prefix) && name[len(prefix)] == '/' {
+		return replacement + name[len(prefix):], true
+	}
+	return "", false
+}
+
+// RedirectToRepoAlias writes an HTTP redirect response if the Repo route var of the request
+// is an alias of a repository. The redirect points to the same location with the canonical
+// repository name, including the query of the request (e.g. the line range of a blob page).
+// It reports whether a redirect was written.
+//
+// Callers should only redirect requests for repositories that don't exist, so that an
+// alias never hides a repository with the same name.
+func RedirectToRepoAlias(w http.ResponseWriter, r *http.Request) (bool, error) {
+	canonical, ok := canonicalRepoAlias(conf.Get().RepoAliases, api.RepoName(mux.Vars(r)["Repo"]))
+	if !ok {
+		return false, nil
+	}
+
+	origVars := mux.Vars(r)
+	var pairs []string
+	for k, v := range origVars {
+		if k == "Repo" {
+			v = string(canonical)
+		}
+		pairs = append(pairs, k, v)
+	}
+	destURL, err := mux.CurrentRoute(r).URLPath(pairs...)
+	if err != nil {
+		return false, err
+	}
+	destURL.RawQuery = r.URL.RawQuery
+
+	http.Redirect(w, r, destURL.String(), http.StatusMovedPermanently)
+	return true, nil
+}
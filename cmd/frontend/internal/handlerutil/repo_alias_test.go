@@ -0,0 +1,131 @@
+package handlerutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestRepoAliases(t *testing.T) {
+	aliases := []*schema.RepoAlias{
+		{Alias: "src", Name: "github.com/sourcegraph"},
+		{Alias: "mux", Name: "github.com/gorilla/mux"},
+		{Alias: "src/web", Name: "github.com/sourcegraph/sourcegraph/"},
+	}
+
+	tests := []struct {
+		name      string
+		alias     api.RepoName
+		canonical api.RepoName
+	}{
+		{name: "exact alias", alias: "mux", canonical: "github.com/gorilla/mux"},
+		{name: "prefix alias", alias: "src/zoekt", canonical: "github.com/sourcegraph/zoekt"},
+		{name: "most specific alias", alias: "src/web", canonical: "github.com/sourcegraph/sourcegraph"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got, ok := resolveRepoAlias(aliases, test.alias); !ok || got != test.canonical {
+				t.Errorf("resolveRepoAlias(%q): got %q, want %q", test.alias, got, test.canonical)
+			}
+			if got, ok := reverseRepoAlias(aliases, test.canonical); !ok || got != test.alias {
+				t.Errorf("reverseRepoAlias(%q): got %q, want %q", test.canonical, got, test.alias)
+			}
+		})
+	}
+
+	for _, name := range []api.RepoName{"srcgraph/foo", "github.com/gorilla/muxer", "github.com/other/foo"} {
+		if got, ok := resolveRepoAlias(aliases, name); ok {
+			t.Errorf("resolveRepoAlias(%q): want no alias, got %q", name, got)
+		}
+		if got, ok := reverseRepoAlias(aliases, name); ok {
+			t.Errorf("reverseRepoAlias(%q): want no alias, got %q", name, got)
+		}
+	}
+}
+
+func TestRedirectToRepoAlias(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		RepoAliases: []*schema.RepoAlias{{Alias: "src", Name: "github.com/sourcegraph"}},
+	}})
+	defer conf.Mock(nil)
+
+	router := mux.NewRouter()
+	router.Path("/{Repo:.+}/-/blob/{Path:.*}").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if redirected, err := RedirectToRepoAlias(w, r); err != nil {
+			t.Fatal(err)
+		} else if !redirected {
+			w.WriteHeader(http.StatusOK)
+		}
+	})
+
+	tests := []struct {
+		path         string
+		wantStatus   int
+		wantLocation string
+	}{
+		{path: "/src/zoekt/-/blob/README.md?L12", wantStatus: http.StatusMovedPermanently, wantLocation: "/github.com/sourcegraph/zoekt/-/blob/README.md?L12"},
+		{path: "/github.com/sourcegraph/zoekt/-/blob/README.md", wantStatus: http.StatusOK},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest("GET", test.path, nil))
+			if rec.Code != test.wantStatus {
+				t.Errorf("status: got %d, want %d", rec.Code, test.wantStatus)
+			}
+			if location := rec.Header().Get("Location"); location != test.wantLocation {
+				t.Errorf("location: got %q, want %q", location, test.wantLocation)
+			}
+		})
+	}
+}
+
+func TestRedirectToRepoAlias_Loop(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		RepoAliases: []*schema.RepoAlias{
+			{Alias: "src", Name: "src/main"},
+			{Alias: "self", Name: "self"},
+			{Alias: "mux", Name: "github.com/gorilla/mux"},
+		},
+	}})
+	defer conf.Mock(nil)
+
+	router := mux.NewRouter()
+	router.Path("/{Repo:.+}/-/blob/{Path:.*}").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if redirected, err := RedirectToRepoAlias(w, r); err != nil {
+			t.Fatal(err)
+		} else if !redirected {
+			w.WriteHeader(http.StatusOK)
+		}
+	})
+
+	tests := []struct {
+		path         string
+		wantStatus   int
+		wantLocation string
+	}{
+		// Redirecting would match the alias again on every request.
+		{path: "/src/-/blob/README.md", wantStatus: http.StatusOK},
+		{path: "/src/main/-/blob/README.md", wantStatus: http.StatusOK},
+		{path: "/self/-/blob/README.md", wantStatus: http.StatusOK},
+		{path: "/mux/-/blob/README.md", wantStatus: http.StatusMovedPermanently, wantLocation: "/github.com/gorilla/mux/-/blob/README.md"},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest("GET", test.path, nil))
+			if rec.Code != test.wantStatus {
+				t.Errorf("status: got %d, want %d", rec.Code, test.wantStatus)
+			}
+			if location := rec.Header().Get("Location"); location != test.wantLocation {
+				t.Errorf("location: got %q, want %q", location, test.wantLocation)
+			}
+		})
+	}
+}
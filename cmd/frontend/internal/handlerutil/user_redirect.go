@@ -0,0 +1,44 @@
+package handlerutil
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gorilla/mux"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+// RedirectToRenamedUser writes an HTTP redirect response if the username route var of the request
+// is the old username of a user that changed their username. The redirect points to the same
+// location with the new username. It reports whether a redirect was written.
+func RedirectToRenamedUser(w http.ResponseWriter, r *http.Request, db dbutil.DB) (bool, error) {
+	username := mux.Vars(r)["username"]
+	prefix := "/users/" + username
+	if username == "" || !strings.HasPrefix(r.URL.Path, prefix) {
+		return false, nil
+	}
+
+	// A user that took the old username owns it.
+	if _, err := database.Users(db).GetByUsername(r.Context(), username); !errcode.IsNotFound(err) {
+		return false, err
+	}
+
+	newUsername, err := database.Redirects(db).Resolve(r.Context(), database.RedirectKindUser, username)
+	if errors.Is(err, database.ErrRedirectNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	destURL := url.URL{
+		Path:     "/users/" + newUsername + strings.TrimPrefix(r.URL.Path, prefix),
+		RawQuery: r.URL.RawQuery,
+	}
+	http.Redirect(w, r, destURL.String(), http.StatusMovedPermanently)
+	return true, nil
+}
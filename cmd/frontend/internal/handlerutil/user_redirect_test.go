@@ -0,0 +1,65 @@
+package handlerutil
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestRedirectToRenamedUser(t *testing.T) {
+	database.Mocks.Users.GetByUsername = func(ctx context.Context, username string) (*types.User, error) {
+		if username == "bob" {
+			return &types.User{Username: username}, nil
+		}
+		return nil, &errcode.Mock{Message: "user not found", IsNotFound: true}
+	}
+	database.Mocks.Redirects.Resolve = func(ctx context.Context, kind database.RedirectKind, name string) (string, error) {
+		if kind == database.RedirectKindUser && (name == "alice" || name == "bob") {
+			return "alice2", nil
+		}
+		return "", database.ErrRedirectNotFound
+	}
+	t.Cleanup(func() { database.Mocks = database.MockStores{} })
+
+	db := new(dbtesting.MockDB)
+	router := mux.NewRouter()
+	router.PathPrefix("/users/{username}").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if redirected, err := RedirectToRenamedUser(w, r, db); err != nil {
+			t.Fatal(err)
+		} else if !redirected {
+			w.WriteHeader(http.StatusOK)
+		}
+	})
+
+	tests := []struct {
+		path         string
+		wantStatus   int
+		wantLocation string
+	}{
+		{path: "/users/alice/settings/tokens?tab=1", wantStatus: http.StatusMovedPermanently, wantLocation: "/users/alice2/settings/tokens?tab=1"},
+		{path: "/users/alice", wantStatus: http.StatusMovedPermanently, wantLocation: "/users/alice2"},
+		// The old username was taken by another user.
+		{path: "/users/bob", wantStatus: http.StatusOK},
+		{path: "/users/carol", wantStatus: http.StatusOK},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest("GET", test.path, nil))
+			if rec.Code != test.wantStatus {
+				t.Errorf("status: got %d, want %d", rec.Code, test.wantStatus)
+			}
+			if location := rec.Header().Get("Location"); location != test.wantLocation {
+				t.Errorf("location: got %q, want %q", location, test.wantLocation)
+			}
+		})
+	}
+}
@@ -28,6 +28,8 @@ func newTest() *httptestutil.Client {
 		enterpriseServices.GitHubWebhook,
 		enterpriseServices.GitLabWebhook,
 		enterpriseServices.BitbucketServerWebhook,
+		enterpriseServices.BitbucketCloudWebhook,
+		enterpriseServices.AzureDevOpsWebhook,
 		enterpriseServices.NewCodeIntelUploadHandler,
 		rateLimiter,
 	))
@@ -69,7 +69,7 @@ func AccessTokenAuthMiddleware(db dbutil.DB, next http.Handler) http.Handler {
 			} else {
 				requiredScope = authz.ScopeSiteAdminSudo
 			}
-			subjectUserID, err := database.AccessTokens(db).Lookup(r.Context(), token, requiredScope)
+			subjectUserID, tokenID, err := database.AccessTokens(db).Lookup(r.Context(), token, requiredScope)
 			if err != nil {
 				log15.Error("Invalid access token.", "token", token, "err", err)
 				http.Error(w, "Invalid access token.", http.StatusUnauthorized)
@@ -107,7 +107,11 @@ func AccessTokenAuthMiddleware(db dbutil.DB, next http.Handler) http.Handler {
 				log15.Debug("HTTP request used sudo token.", "requestURI", r.URL.RequestURI(), "tokenSubjectUserID", subjectUserID, "actorUserID", actorUserID, "actorUsername", user.Username)
 			}
 
-			r = r.WithContext(actor.WithActor(r.Context(), &actor.Actor{UID: actorUserID}))
+			// The activity is recorded for the owner of the token, since sudo is an
+			// administration tool rather than activity of the impersonated user.
+			database.RecordUserActivity(subjectUserID, database.UserActivityKindAPI)
+
+			r = r.WithContext(actor.WithActor(r.Context(), &actor.Actor{UID: actorUserID, AccessTokenID: tokenID}))
 		}
 
 		next.ServeHTTP(w, r)
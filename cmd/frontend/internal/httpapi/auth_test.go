@@ -71,9 +71,9 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		req, _ := http.NewRequest("GET", "/", nil)
 		req.Header.Set("Authorization", "token badbad")
 		var calledAccessTokensLookup bool
-		database.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+		database.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, id int64, err error) {
 			calledAccessTokensLookup = true
-			return 0, errors.New("x")
+			return 0, 0, errors.New("x")
 		}
 		defer func() { database.Mocks = database.MockStores{} }()
 		checkHTTPResponse(t, req, http.StatusUnauthorized, "Invalid access token.\n")
@@ -87,7 +87,7 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 			req, _ := http.NewRequest("GET", "/", nil)
 			req.Header.Set("Authorization", headerValue)
 			var calledAccessTokensLookup bool
-			database.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+			database.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, id int64, err error) {
 				calledAccessTokensLookup = true
 				if want := "abcdef"; tokenHexEncoded != want {
 					t.Errorf("got %q, want %q", tokenHexEncoded, want)
@@ -95,7 +95,7 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 				if want := authz.ScopeUserAll; requiredScope != want {
 					t.Errorf("got %q, want %q", requiredScope, want)
 				}
-				return 123, nil
+				return 123, 1, nil
 			}
 			defer func() { database.Mocks = database.MockStores{} }()
 			checkHTTPResponse(t, req, http.StatusOK, "user 123")
@@ -111,7 +111,7 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		req.Header.Set("Authorization", "token abcdef")
 		req = req.WithContext(actor.WithActor(context.Background(), &actor.Actor{UID: 456}))
 		var calledAccessTokensLookup bool
-		database.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+		database.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, id int64, err error) {
 			calledAccessTokensLookup = true
 			if want := "abcdef"; tokenHexEncoded != want {
 				t.Errorf("got %q, want %q", tokenHexEncoded, want)
@@ -119,7 +119,7 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 			if want := authz.ScopeUserAll; requiredScope != want {
 				t.Errorf("got %q, want %q", requiredScope, want)
 			}
-			return 123, nil
+			return 123, 1, nil
 		}
 		defer func() { database.Mocks = database.MockStores{} }()
 		checkHTTPResponse(t, req, http.StatusOK, "user 123")
@@ -145,7 +145,7 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 			}
 			req = req.WithContext(actor.WithActor(context.Background(), &actor.Actor{UID: 456}))
 			var calledAccessTokensLookup bool
-			database.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+			database.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, id int64, err error) {
 				calledAccessTokensLookup = true
 				if want := "abcdef"; tokenHexEncoded != want {
 					t.Errorf("got %q, want %q", tokenHexEncoded, want)
@@ -153,7 +153,7 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 				if want := authz.ScopeUserAll; requiredScope != want {
 					t.Errorf("got %q, want %q", requiredScope, want)
 				}
-				return 123, nil
+				return 123, 1, nil
 			}
 			defer func() { database.Mocks = database.MockStores{} }()
 			checkHTTPResponse(t, req, http.StatusOK, "user 123")
@@ -167,7 +167,7 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		req, _ := http.NewRequest("GET", "/", nil)
 		req.Header.Set("Authorization", `token-sudo token="abcdef",user="alice"`)
 		var calledAccessTokensLookup bool
-		database.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+		database.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, id int64, err error) {
 			calledAccessTokensLookup = true
 			if want := "abcdef"; tokenHexEncoded != want {
 				t.Errorf("got %q, want %q", tokenHexEncoded, want)
@@ -175,7 +175,7 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 			if want := authz.ScopeSiteAdminSudo; requiredScope != want {
 				t.Errorf("got %q, want %q", requiredScope, want)
 			}
-			return 123, nil
+			return 123, 1, nil
 		}
 		var calledUsersGetByID bool
 		database.Mocks.Users.GetByID = func(ctx context.Context, userID int32) (*types.User, error) {
@@ -212,7 +212,7 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		req, _ := http.NewRequest("GET", "/", nil)
 		req.Header.Set("Authorization", `token-sudo token="abcdef",user="alice"`)
 		var calledAccessTokensLookup bool
-		database.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+		database.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, id int64, err error) {
 			calledAccessTokensLookup = true
 			if want := "abcdef"; tokenHexEncoded != want {
 				t.Errorf("got %q, want %q", tokenHexEncoded, want)
@@ -220,7 +220,7 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 			if want := authz.ScopeSiteAdminSudo; requiredScope != want {
 				t.Errorf("got %q, want %q", requiredScope, want)
 			}
-			return 123, nil
+			return 123, 1, nil
 		}
 		var calledUsersGetByID bool
 		database.Mocks.Users.GetByID = func(ctx context.Context, userID int32) (*types.User, error) {
@@ -244,7 +244,7 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		req, _ := http.NewRequest("GET", "/", nil)
 		req.Header.Set("Authorization", `token-sudo token="abcdef",user="doesntexist"`)
 		var calledAccessTokensLookup bool
-		database.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+		database.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, id int64, err error) {
 			calledAccessTokensLookup = true
 			if want := "abcdef"; tokenHexEncoded != want {
 				t.Errorf("got %q, want %q", tokenHexEncoded, want)
@@ -252,7 +252,7 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 			if want := authz.ScopeSiteAdminSudo; requiredScope != want {
 				t.Errorf("got %q, want %q", requiredScope, want)
 			}
-			return 123, nil
+			return 123, 1, nil
 		}
 		var calledUsersGetByID bool
 		database.Mocks.Users.GetByID = func(ctx context.Context, userID int32) (*types.User, error) {
@@ -3,6 +3,7 @@ package httpapi
 import (
 	"compress/gzip"
 	"encoding/json"
+	"math"
 	"net/http"
 	"os"
 	"strconv"
@@ -17,6 +18,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/cookie"
 	"github.com/sourcegraph/sourcegraph/internal/honey"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
@@ -69,7 +71,19 @@ func serveGraphQL(schema *graphql.Schema, rlw graphqlbackend.LimitWatcher, isInt
 			traceGraphQL(traceData)
 		}()
 
-		uid, isIP, anonymous := getUID(r)
+		// In maintenance mode, the site is read-only for users. Internal clients (e.g. background
+		// jobs of other services) may still make changes.
+		if message, enabled := conf.MaintenanceMode(); enabled && !isInternal {
+			if isMutation, _ := graphqlbackend.IsMutation(params.Query, params.OperationName); isMutation {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return json.NewEncoder(w).Encode(&graphql.Response{
+					Errors: []*gqlerrors.QueryError{{Message: message}},
+				})
+			}
+		}
+
+		uid, _, anonymous := getUID(r)
 		traceData.uid = uid
 		traceData.anonymous = anonymous
 
@@ -88,13 +102,8 @@ func serveGraphQL(schema *graphql.Schema, rlw graphqlbackend.LimitWatcher, isInt
 			traceData.costError = costErr
 			traceData.cost = cost
 
-			if rl, enabled := rlw.Get(); enabled && cost != nil {
-				limited, result, err := rl.RateLimit(uid, cost.FieldCount, graphqlbackend.LimiterArgs{
-					IsIP:          isIP,
-					Anonymous:     anonymous,
-					RequestName:   requestName,
-					RequestSource: requestSource,
-				})
+			if cost != nil {
+				limited, result, err := checkRateLimit(w, r, rlw, cost.FieldCount, requestName, requestSource)
 				if err != nil {
 					log15.Error("checking GraphQL rate limit", "error", err)
 					traceData.limitError = err
@@ -102,7 +111,6 @@ func serveGraphQL(schema *graphql.Schema, rlw graphqlbackend.LimitWatcher, isInt
 					traceData.limited = limited
 					traceData.limitResult = result
 					if limited {
-						w.Header().Set("Retry-After", strconv.Itoa(int(result.RetryAfter.Seconds())))
 						w.WriteHeader(http.StatusTooManyRequests)
 						return nil
 					}
@@ -125,6 +133,45 @@ func serveGraphQL(schema *graphql.Schema, rlw graphqlbackend.LimitWatcher, isInt
 	}
 }
 
+// checkRateLimit charges cost to the GraphQL rate limits of the user of the request and sets the
+// rate limit headers of the response. If the request is rate limited, it also sets the Retry-After
+// header and the caller must respond with http.StatusTooManyRequests.
+func checkRateLimit(w http.ResponseWriter, r *http.Request, rlw graphqlbackend.LimitWatcher, cost int, requestName string, requestSource trace.SourceType) (limited bool, result throttled.RateLimitResult, err error) {
+	rl, enabled := rlw.Get()
+	if !enabled {
+		return false, result, nil
+	}
+
+	uid, isIP, anonymous := getUID(r)
+	limited, result, err = rl.RateLimit(uid, cost, graphqlbackend.LimiterArgs{
+		IsIP:          isIP,
+		Anonymous:     anonymous,
+		RequestName:   requestName,
+		RequestSource: requestSource,
+		AccessTokenID: actor.FromContext(r.Context()).AccessTokenID,
+	})
+	if err != nil {
+		return false, result, err
+	}
+	setRateLimitHeaders(w, result)
+	if limited {
+		w.Header().Set("Retry-After", strconv.Itoa(int(result.RetryAfter.Seconds())))
+	}
+	return limited, result, nil
+}
+
+// setRateLimitHeaders sets the X-RateLimit headers of a response to the state of the rate limit
+// of the request. Limiters that don't track a limit for the request return an empty result, for
+// which no headers are set.
+func setRateLimitHeaders(w http.ResponseWriter, result throttled.RateLimitResult) {
+	if result.Limit <= 0 {
+		return
+	}
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	w.Header().Set("X-RateLimit-Reset", strconv.Itoa(int(math.Ceil(result.ResetAfter.Seconds()))))
+}
+
 type graphQLQueryParams struct {
 	Query         string                 `json:"query"`
 	OperationName string                 `json:"operationName"`
@@ -26,6 +26,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
@@ -37,7 +38,7 @@ import (
 //
 // 🚨 SECURITY: The caller MUST wrap the returned handler in middleware that checks authentication
 // and sets the actor in the request context.
-func NewHandler(db dbutil.DB, m *mux.Router, schema *graphql.Schema, githubWebhook webhooks.Registerer, gitlabWebhook, bitbucketServerWebhook http.Handler, newCodeIntelUploadHandler enterprise.NewCodeIntelUploadHandler, rateLimiter graphqlbackend.LimitWatcher) http.Handler {
+func NewHandler(db dbutil.DB, m *mux.Router, schema *graphql.Schema, githubWebhook webhooks.Registerer, gitlabWebhook, bitbucketServerWebhook, bitbucketCloudWebhook, azureDevOpsWebhook http.Handler, newCodeIntelUploadHandler enterprise.NewCodeIntelUploadHandler, rateLimiter graphqlbackend.LimitWatcher) http.Handler {
 	if m == nil {
 		m = apirouter.New(nil)
 	}
@@ -61,13 +62,27 @@ func NewHandler(db dbutil.DB, m *mux.Router, schema *graphql.Schema, githubWebho
 
 	webhookhandlers.Init(db, &gh)
 
-	m.Get(apirouter.GitHubWebhooks).Handler(trace.Route(&gh))
-
 	githubWebhook.Register(&gh)
 
-	m.Get(apirouter.GitHubWebhooks).Handler(trace.Route(&gh))
-	m.Get(apirouter.GitLabWebhooks).Handler(trace.Route(gitlabWebhook))
-	m.Get(apirouter.BitbucketServerWebhooks).Handler(trace.Route(bitbucketServerWebhook))
+	// All webhooks are received by the webhook router, which logs them and
+	// dispatches them to the handler of the kind of their external service.
+	webhookRouter := &webhooks.Router{
+		ExternalServices: database.ExternalServices(db),
+		Logs:             database.WebhookLogs(db),
+	}
+	webhookRouter.Register(extsvc.KindGitHub, &gh)
+	webhookRouter.Register(extsvc.KindGitLab, gitlabWebhook)
+	webhookRouter.Register(extsvc.KindBitbucketServer, bitbucketServerWebhook)
+	webhookRouter.Register(extsvc.KindBitbucketCloud, bitbucketCloudWebhook)
+	webhookRouter.Register(extsvc.KindAzureDevOps, azureDevOpsWebhook)
+	webhooks.DefaultRouter = webhookRouter
+
+	m.Get(apirouter.Webhooks).Handler(trace.Route(webhookRouter))
+	m.Get(apirouter.GitHubWebhooks).Handler(trace.Route(webhookRouter.LegacyHandler(extsvc.KindGitHub)))
+	m.Get(apirouter.GitLabWebhooks).Handler(trace.Route(webhookRouter.LegacyHandler(extsvc.KindGitLab)))
+	m.Get(apirouter.BitbucketServerWebhooks).Handler(trace.Route(webhookRouter.LegacyHandler(extsvc.KindBitbucketServer)))
+	m.Get(apirouter.BitbucketCloudWebhooks).Handler(trace.Route(webhookRouter.LegacyHandler(extsvc.KindBitbucketCloud)))
+	m.Get(apirouter.AzureDevOpsWebhooks).Handler(trace.Route(webhookRouter.LegacyHandler(extsvc.KindAzureDevOps)))
 	m.Get(apirouter.LSIFUpload).Handler(trace.Route(newCodeIntelUploadHandler(false)))
 
 	if envvar.SourcegraphDotComMode() {
@@ -84,6 +99,12 @@ func NewHandler(db dbutil.DB, m *mux.Router, schema *graphql.Schema, githubWebho
 
 	m.Get(apirouter.Registry).Handler(trace.Route(handler(registry.HandleRegistry)))
 
+	m.Get(apirouter.RESTOpenAPI).Handler(trace.Route(restHandler(serveRESTOpenAPI(m), true, env.InsecureDev)))
+	m.Get(apirouter.RESTRepos).Handler(trace.Route(restHandler(serveRESTRepos, false, env.InsecureDev)))
+	m.Get(apirouter.RESTRepo).Handler(trace.Route(restHandler(serveRESTRepo, false, env.InsecureDev)))
+	m.Get(apirouter.RESTRepoContents).Handler(trace.Route(restHandler(serveRESTRepoContents, false, env.InsecureDev)))
+	m.Get(apirouter.RESTSearch).Handler(trace.Route(restHandler(serveRESTSearch(schema, rateLimiter), false, env.InsecureDev)))
+
 	m.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		log.Printf("API no route: %s %s from %s", r.Method, r.URL, r.Referer())
 		http.Error(w, "no route", http.StatusNotFound)
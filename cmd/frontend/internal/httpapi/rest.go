@@ -0,0 +1,466 @@
+package httpapi
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gorilla/mux"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// The REST API (/.api/v1/...) is a small, stable facade over the data also available in the
+// GraphQL API, for integrators that can't easily consume GraphQL. Every list endpoint accepts the
+// "first" and "after" query parameters and returns a page of items with an opaque cursor to the
+// next page.
+//
+// The parameters of an endpoint are the fields of a struct decoded by decodeRESTParams, and its
+// OpenAPI specification is generated from the same struct (see restEndpoints).
+
+// restMaxFileBytes is the largest file returned by the contents endpoint.
+const restMaxFileBytes = 10 * 1024 * 1024
+
+type restPageParams struct {
+	First int        `query:"first" default:"50" minimum:"1" maximum:"1000" description:"The maximum number of items to return."`
+	After restCursor `query:"after" description:"The nextCursor of the previous page."`
+}
+
+type restReposParams struct {
+	Query string `query:"query" description:"Only return repositories whose name matches the query."`
+	restPageParams
+}
+
+type restRepoParams struct {
+	Repo api.RepoName `path:"Repo" description:"The name of the repository, such as github.com/sourcegraph/sourcegraph."`
+}
+
+type restRepoContentsParams struct {
+	restRepoParams
+	Path string `path:"Path" description:"The path of the file in the repository."`
+	Rev  string `query:"rev" description:"The revision (branch, tag or commit) to read the file at. Defaults to the default branch."`
+}
+
+type restSearchParams struct {
+	Query string     `query:"q" required:"true" description:"The search query."`
+	First int        `query:"first" default:"50" minimum:"1" maximum:"1000" description:"The maximum number of repositories to search for the page."`
+	After restCursor `query:"after" description:"The nextCursor of the previous page."`
+}
+
+type restRepository struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Fork        bool      `json:"fork"`
+	Archived    bool      `json:"archived"`
+	Private     bool      `json:"private"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type restRepositoryPage struct {
+	Items      []restRepository `json:"items"`
+	NextCursor *string          `json:"nextCursor"`
+}
+
+type restFileContents struct {
+	Repository string `json:"repository"`
+	Commit     string `json:"commit"`
+	Path       string `json:"path"`
+	// Encoding is "utf-8" for text files and "base64" for binary files.
+	Encoding string `json:"encoding"`
+	Content  string `json:"content"`
+}
+
+type restSearchResult struct {
+	// Type is one of "file", "repository" or "commit".
+	Type        string          `json:"type"`
+	Repository  string          `json:"repository"`
+	Path        string          `json:"path,omitempty"`
+	Commit      string          `json:"commit,omitempty"`
+	LineMatches []restLineMatch `json:"lineMatches,omitempty"`
+}
+
+type restLineMatch struct {
+	// LineNumber is 0-based.
+	LineNumber int    `json:"lineNumber"`
+	Preview    string `json:"preview"`
+}
+
+type restSearchPage struct {
+	Items      []restSearchResult `json:"items"`
+	NextCursor *string            `json:"nextCursor"`
+	// LimitHit is true if the search stopped before finding all results in the repositories of
+	// the page. Use a count: filter in the query to search for more.
+	LimitHit bool `json:"limitHit"`
+}
+
+type restError struct {
+	Error struct {
+		Status  int    `json:"status"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// restCursor is the decoded form of the opaque cursors of the REST API.
+type restCursor struct {
+	Name string `json:"n,omitempty"`
+}
+
+func (c restCursor) encode() *string {
+	b, _ := json.Marshal(c)
+	s := base64.RawURLEncoding.EncodeToString(b)
+	return &s
+}
+
+func (c *restCursor) UnmarshalText(text []byte) error {
+	// Decode into a type without this method, which encoding/json would otherwise call.
+	type cursor restCursor
+	b, err := base64.RawURLEncoding.DecodeString(string(text))
+	if err == nil {
+		err = json.Unmarshal(b, (*cursor)(c))
+	}
+	if err != nil {
+		return errors.New("invalid cursor")
+	}
+	return nil
+}
+
+func restBadRequest(format string, args ...interface{}) error {
+	return &errcode.HTTPErr{Status: http.StatusBadRequest, Err: errors.Errorf(format, args...)}
+}
+
+// decodeRESTParams decodes the parameters of a request into the fields of the struct pointed to
+// by v. A field is decoded from the query parameter named by its "query" tag or the route variable
+// named by its "path" tag, and its "required", "default", "minimum" and "maximum" tags are
+// enforced. Embedded structs are decoded recursively.
+func decodeRESTParams(r *http.Request, v interface{}) error {
+	return decodeRESTParamsValue(r, reflect.ValueOf(v).Elem())
+}
+
+func decodeRESTParamsValue(r *http.Request, v reflect.Value) error {
+	for i := 0; i < v.NumField(); i++ {
+		f, fv := v.Type().Field(i), v.Field(i)
+		if f.Anonymous {
+			if err := decodeRESTParamsValue(r, fv); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var value string
+		name, ok := f.Tag.Lookup("query")
+		if ok {
+			value = r.URL.Query().Get(name)
+		} else if name, ok = f.Tag.Lookup("path"); ok {
+			value = mux.Vars(r)[name]
+		} else {
+			continue
+		}
+		if value == "" {
+			if f.Tag.Get("required") == "true" {
+				return restBadRequest("the %s parameter is required", name)
+			}
+			value = f.Tag.Get("default")
+			if value == "" {
+				continue
+			}
+		}
+
+		if u, ok := fv.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			if err := u.UnmarshalText([]byte(value)); err != nil {
+				return restBadRequest("invalid %s parameter: %s", name, err)
+			}
+			continue
+		}
+		switch fv.Kind() {
+		case reflect.String:
+			fv.SetString(value)
+		case reflect.Int:
+			n, err := strconv.Atoi(value)
+			if err != nil || !restInRange(f.Tag, n) {
+				return restBadRequest("%s must be an integer between %s and %s", name, f.Tag.Get("minimum"), f.Tag.Get("maximum"))
+			}
+			fv.SetInt(int64(n))
+		default:
+			panic("unsupported type in REST API parameters: " + f.Type.String())
+		}
+	}
+	return nil
+}
+
+// restInRange reports whether n is within the "minimum" and "maximum" tags of a field, if any.
+func restInRange(tag reflect.StructTag, n int) bool {
+	if min, ok := tag.Lookup("minimum"); ok {
+		if m, _ := strconv.Atoi(min); n < m {
+			return false
+		}
+	}
+	if max, ok := tag.Lookup("maximum"); ok {
+		if m, _ := strconv.Atoi(max); n > m {
+			return false
+		}
+	}
+	return true
+}
+
+// restHandler returns the handler of a REST API endpoint. Unlike the other API handlers, errors
+// are written as JSON objects so that clients can handle them like any other response.
+//
+// 🚨 SECURITY: Unless public is true, the handler refuses unauthenticated requests.
+func restHandler(h func(http.ResponseWriter, *http.Request) error, public bool, writeErrBody bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		if public || actor.FromContext(r.Context()).IsAuthenticated() {
+			err = h(w, r)
+		} else {
+			err = &errcode.HTTPErr{Status: http.StatusUnauthorized, Err: errors.New("authentication required")}
+		}
+		if err == nil {
+			return
+		}
+
+		trace.SetRequestErrorCause(r.Context(), err)
+		status := errcode.HTTP(err)
+		var resp restError
+		resp.Error.Status = status
+		resp.Error.Message = err.Error()
+		if status >= 500 {
+			log15.Error("REST API handler error response", "method", r.Method, "request_uri", r.URL.RequestURI(), "status_code", status, "error", err)
+			if !writeErrBody {
+				// Internal errors may contain sensitive info.
+				resp.Error.Message = http.StatusText(status)
+			}
+		}
+
+		w.Header().Set("cache-control", "no-cache, max-age=0")
+		w.Header().Set("content-type", "application/json; charset=utf-8")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+}
+
+func serveRESTRepos(w http.ResponseWriter, r *http.Request) error {
+	var params restReposParams
+	if err := decodeRESTParams(r, &params); err != nil {
+		return err
+	}
+	first := params.First
+
+	opt := database.ReposListOptions{
+		Query:           params.Query,
+		OrderBy:         database.RepoListOrderBy{{Field: database.RepoListName}},
+		CursorColumn:    string(database.RepoListName),
+		CursorValue:     params.After.Name,
+		CursorDirection: "next",
+		// Fetch one more repository to know whether there is a next page.
+		LimitOffset: &database.LimitOffset{Limit: first + 1},
+	}
+	repos, err := backend.Repos.List(r.Context(), opt)
+	if err != nil {
+		return err
+	}
+
+	page := restRepositoryPage{Items: make([]restRepository, 0, len(repos))}
+	if len(repos) > first {
+		// The cursor is inclusive, so the next page starts at the extra repository.
+		page.NextCursor = restCursor{Name: string(repos[first].Name)}.encode()
+		repos = repos[:first]
+	}
+	for _, repo := range repos {
+		page.Items = append(page.Items, restRepository{
+			Name:        string(repo.Name),
+			Description: repo.Description,
+			Fork:        repo.Fork,
+			Archived:    repo.Archived,
+			Private:     repo.Private,
+			CreatedAt:   repo.CreatedAt,
+		})
+	}
+	return writeJSON(w, page)
+}
+
+func serveRESTRepo(w http.ResponseWriter, r *http.Request) error {
+	var params restRepoParams
+	if err := decodeRESTParams(r, &params); err != nil {
+		return err
+	}
+	repo, err := backend.Repos.GetByName(r.Context(), params.Repo)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, restRepository{
+		Name:        string(repo.Name),
+		Description: repo.Description,
+		Fork:        repo.Fork,
+		Archived:    repo.Archived,
+		Private:     repo.Private,
+		CreatedAt:   repo.CreatedAt,
+	})
+}
+
+func serveRESTRepoContents(w http.ResponseWriter, r *http.Request) error {
+	var params restRepoContentsParams
+	if err := decodeRESTParams(r, &params); err != nil {
+		return err
+	}
+	ctx := r.Context()
+	repo, err := backend.Repos.GetByName(ctx, params.Repo)
+	if err != nil {
+		return err
+	}
+
+	// An empty revision resolves to the default branch.
+	commit, err := backend.Repos.ResolveRev(ctx, repo, params.Rev)
+	if err != nil {
+		return err
+	}
+
+	path := params.Path
+	content, err := git.ReadFile(ctx, repo.Name, commit, path, restMaxFileBytes+1)
+	if err != nil {
+		return err
+	}
+	if len(content) > restMaxFileBytes {
+		return restBadRequest("file is larger than %d bytes", restMaxFileBytes)
+	}
+
+	file := restFileContents{
+		Repository: string(repo.Name),
+		Commit:     string(commit),
+		Path:       path,
+		Encoding:   "utf-8",
+		Content:    string(content),
+	}
+	if !utf8.Valid(content) {
+		file.Encoding = "base64"
+		file.Content = base64.StdEncoding.EncodeToString(content)
+	}
+	return writeJSON(w, file)
+}
+
+const restSearchQuery = `query RESTSearch($query: String!, $first: Int!, $after: String) {
+	search(query: $query, version: V2, repositoriesFirst: $first, repositoriesAfter: $after) {
+		results {
+			limitHit
+			nextRepositoriesCursor
+			results {
+				__typename
+				... on FileMatch {
+					repository { name }
+					file { path commit { oid } }
+					lineMatches { lineNumber preview }
+				}
+				... on Repository { name }
+				... on CommitSearchResult {
+					commit { oid repository { name } }
+				}
+			}
+		}
+	}
+}`
+
+type restSearchResponse struct {
+	Search struct {
+		Results struct {
+			LimitHit               bool
+			NextRepositoriesCursor *string
+			Results                []struct {
+				Typename   string `json:"__typename"`
+				Name       string
+				Repository struct{ Name string }
+				File       struct {
+					Path   string
+					Commit struct{ OID string }
+				}
+				LineMatches []struct {
+					LineNumber int
+					Preview    string
+				}
+				Commit struct {
+					OID        string
+					Repository struct{ Name string }
+				}
+			}
+		}
+	}
+}
+
+// serveRESTSearch runs a search with the GraphQL API, subject to the same rate limits as GraphQL
+// requests. The pages are pages of the repositories searched, so a page may have no results and
+// still have a next page.
+func serveRESTSearch(schema *graphql.Schema, rlw graphqlbackend.LimitWatcher) func(http.ResponseWriter, *http.Request) error {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		var params restSearchParams
+		if err := decodeRESTParams(r, &params); err != nil {
+			return err
+		}
+
+		variables := map[string]interface{}{"query": strings.TrimSpace(params.Query), "first": params.First}
+		if params.After.Name != "" {
+			variables["after"] = params.After.Name
+		}
+
+		if errs := schema.ValidateWithVariables(restSearchQuery, variables); len(errs) > 0 {
+			return restBadRequest("%s", errs[0].Message)
+		}
+		cost, err := graphqlbackend.EstimateQueryCost(restSearchQuery, variables)
+		if err != nil {
+			return err
+		}
+		limited, _, err := checkRateLimit(w, r, rlw, cost.FieldCount, "RESTSearch", search.GuessSource(r))
+		if err != nil {
+			log15.Error("checking GraphQL rate limit", "error", err)
+		} else if limited {
+			return &errcode.HTTPErr{Status: http.StatusTooManyRequests, Err: errors.New("rate limit exceeded")}
+		}
+
+		response := schema.Exec(r.Context(), restSearchQuery, "RESTSearch", variables)
+		if len(response.Errors) > 0 {
+			return restBadRequest("%s", response.Errors[0].Message)
+		}
+		var data restSearchResponse
+		if err := json.Unmarshal(response.Data, &data); err != nil {
+			return err
+		}
+
+		results := data.Search.Results
+		page := restSearchPage{Items: []restSearchResult{}, LimitHit: results.LimitHit}
+		if results.NextRepositoriesCursor != nil {
+			page.NextCursor = restCursor{Name: *results.NextRepositoriesCursor}.encode()
+		}
+		for _, res := range results.Results {
+			var item restSearchResult
+			switch res.Typename {
+			case "FileMatch":
+				item = restSearchResult{Type: "file", Repository: res.Repository.Name, Path: res.File.Path, Commit: res.File.Commit.OID}
+				for _, m := range res.LineMatches {
+					item.LineMatches = append(item.LineMatches, restLineMatch{LineNumber: m.LineNumber, Preview: m.Preview})
+				}
+			case "Repository":
+				item = restSearchResult{Type: "repository", Repository: res.Name}
+			case "CommitSearchResult":
+				item = restSearchResult{Type: "commit", Repository: res.Commit.Repository.Name, Commit: res.Commit.OID}
+			default:
+				continue
+			}
+			page.Items = append(page.Items, item)
+		}
+		return writeJSON(w, page)
+	}
+}
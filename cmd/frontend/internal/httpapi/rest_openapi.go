@@ -0,0 +1,250 @@
+package httpapi
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	apirouter "github.com/sourcegraph/sourcegraph/cmd/frontend/internal/httpapi/router"
+	"github.com/sourcegraph/sourcegraph/internal/version"
+)
+
+// restEndpoint documents an endpoint of the REST API. The OpenAPI specification is generated
+// from the route of the endpoint in the API router, the tags of its parameters struct (see
+// decodeRESTParams) and the Go type of its response, so that it can't drift from the
+// implementation.
+type restEndpoint struct {
+	Route       string
+	ID          string
+	Summary     string
+	Description string
+	Params      interface{}
+	Response    interface{}
+}
+
+var restEndpoints = []restEndpoint{
+	{
+		Route:       apirouter.RESTRepos,
+		ID:          "listRepositories",
+		Summary:     "List repositories",
+		Description: "Lists the repositories visible to the user, ordered by name.",
+		Params:      restReposParams{},
+		Response:    restRepositoryPage{},
+	},
+	{
+		Route:    apirouter.RESTRepo,
+		ID:       "getRepository",
+		Summary:  "Get a repository",
+		Params:   restRepoParams{},
+		Response: restRepository{},
+	},
+	{
+		Route:       apirouter.RESTRepoContents,
+		ID:          "getFileContents",
+		Summary:     "Get the contents of a file",
+		Description: "Returns the contents of a file of a repository. Binary files are base64-encoded.",
+		Params:      restRepoContentsParams{},
+		Response:    restFileContents{},
+	},
+	{
+		Route:       apirouter.RESTSearch,
+		ID:          "search",
+		Summary:     "Search code",
+		Description: "Runs a search with the Sourcegraph query syntax. Every page searches the next repositories in name order, so a page may have no results and still have a next page. Searches count towards the GraphQL API rate limits.",
+		Params:      restSearchParams{},
+		Response:    restSearchPage{},
+	},
+}
+
+// restOpenAPISpec returns the OpenAPI 3 specification of the REST API served by the routes of m.
+func restOpenAPISpec(m *mux.Router) map[string]interface{} {
+	paths := map[string]interface{}{}
+	for _, e := range restEndpoints {
+		route := m.Get(e.Route)
+		if route == nil {
+			panic("no route for REST API endpoint " + e.Route)
+		}
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			panic(err)
+		}
+
+		operation := map[string]interface{}{
+			"operationId": e.ID,
+			"summary":     e.Summary,
+			"parameters":  restOpenAPIParameters(reflect.TypeOf(e.Params)),
+			"security":    []interface{}{map[string]interface{}{"token": []string{}}},
+			"responses": map[string]interface{}{
+				"200":     restOpenAPIResponse("OK", e.Response),
+				"default": restOpenAPIResponse("Error", restError{}),
+			},
+		}
+		if e.Description != "" {
+			operation["description"] = e.Description
+		}
+		paths[restOpenAPIPath(template)] = map[string]interface{}{"get": operation}
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "Sourcegraph REST API",
+			"version": version.Version(),
+		},
+		"servers": []interface{}{map[string]interface{}{"url": globals.ExternalURL().String()}},
+		"paths":   paths,
+		"components": map[string]interface{}{
+			"securitySchemes": map[string]interface{}{
+				"token": map[string]interface{}{
+					"type":        "apiKey",
+					"in":          "header",
+					"name":        "Authorization",
+					"description": `An access token, as "token <access token>".`,
+				},
+			},
+		},
+	}
+}
+
+// restOpenAPIPath converts the path template of a route, such as "/v1/repos/{Repo:<regexp>}", to
+// an OpenAPI path, such as "/v1/repos/{repo}".
+func restOpenAPIPath(template string) string {
+	var b strings.Builder
+	for {
+		i := strings.IndexByte(template, '{')
+		if i < 0 {
+			b.WriteString(template)
+			return b.String()
+		}
+		b.WriteString(template[:i])
+
+		// Skip to the matching brace, as the regexp of the variable may contain braces.
+		depth, end := 0, i
+		for ; end < len(template); end++ {
+			if template[end] == '{' {
+				depth++
+			} else if template[end] == '}' {
+				if depth--; depth == 0 {
+					break
+				}
+			}
+		}
+		name := strings.SplitN(template[i+1:end], ":", 2)[0]
+		b.WriteString("{" + restOpenAPIParamName(name) + "}")
+		template = template[end+1:]
+	}
+}
+
+// restOpenAPIParamName returns the name in the OpenAPI specification of a parameter named by the
+// tag of a field of a parameters struct. Route variables are capitalized, unlike parameters.
+func restOpenAPIParamName(name string) string {
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+// restOpenAPIParameters returns the parameters of an endpoint decoded into a struct of type t.
+func restOpenAPIParameters(t reflect.Type) []interface{} {
+	params := []interface{}{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			params = append(params, restOpenAPIParameters(f.Type)...)
+			continue
+		}
+
+		in := "query"
+		name, ok := f.Tag.Lookup(in)
+		if !ok {
+			in = "path"
+			if name, ok = f.Tag.Lookup(in); !ok {
+				continue
+			}
+		}
+
+		schema := map[string]interface{}{"type": "string"}
+		if f.Type.Kind() == reflect.Int {
+			schema["type"] = "integer"
+			for _, key := range []string{"minimum", "maximum", "default"} {
+				if v, ok := f.Tag.Lookup(key); ok {
+					schema[key], _ = strconv.Atoi(v)
+				}
+			}
+		} else if v, ok := f.Tag.Lookup("default"); ok {
+			schema["default"] = v
+		}
+		params = append(params, map[string]interface{}{
+			"name":        restOpenAPIParamName(name),
+			"in":          in,
+			"description": f.Tag.Get("description"),
+			// Path parameters are always required.
+			"required": in == "path" || f.Tag.Get("required") == "true",
+			"schema":   schema,
+		})
+	}
+	return params
+}
+
+func restOpenAPIResponse(description string, v interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"description": description,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{"schema": restOpenAPISchema(reflect.TypeOf(v))},
+		},
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// restOpenAPISchema returns the schema of the JSON encoding of values of type t. It only supports
+// the types used in REST API responses.
+func restOpenAPISchema(t reflect.Type) map[string]interface{} {
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Ptr:
+		schema := restOpenAPISchema(t.Elem())
+		schema["nullable"] = true
+		return schema
+	case t.Kind() == reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": restOpenAPISchema(t.Elem())}
+	case t.Kind() == reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case t.Kind() == reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case t.Kind() == reflect.Int:
+		return map[string]interface{}{"type": "integer"}
+	case t.Kind() == reflect.Struct:
+		properties := map[string]interface{}{}
+		required := []string{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, opts := f.Name, ""
+			if tag, ok := f.Tag.Lookup("json"); ok {
+				parts := strings.SplitN(tag, ",", 2)
+				name = parts[0]
+				if len(parts) == 2 {
+					opts = parts[1]
+				}
+			}
+			if name == "-" || !f.IsExported() {
+				continue
+			}
+			properties[name] = restOpenAPISchema(f.Type)
+			if opts != "omitempty" {
+				required = append(required, name)
+			}
+		}
+		return map[string]interface{}{"type": "object", "properties": properties, "required": required}
+	}
+	panic("unsupported type in REST API response: " + t.String())
+}
+
+func serveRESTOpenAPI(m *mux.Router) func(http.ResponseWriter, *http.Request) error {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		return writeJSON(w, restOpenAPISpec(m))
+	}
+}
@@ -0,0 +1,297 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+	"github.com/throttled/throttled/v2"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	apirouter "github.com/sourcegraph/sourcegraph/cmd/frontend/internal/httpapi/router"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func getREST(t *testing.T, url string, authenticated bool, v interface{}) int {
+	t.Helper()
+
+	req, _ := http.NewRequest("GET", url, nil)
+	if authenticated {
+		req = req.WithContext(actor.WithActor(context.Background(), actor.FromUser(1)))
+	}
+	resp, err := newTest().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode
+}
+
+func TestRESTRepos(t *testing.T) {
+	all := []*types.Repo{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	backend.Mocks.Repos.List = func(ctx context.Context, opt database.ReposListOptions) ([]*types.Repo, error) {
+		if opt.CursorColumn != "name" || opt.CursorDirection != "next" {
+			t.Errorf("unexpected cursor options %+v", opt)
+		}
+		var repos []*types.Repo
+		for _, r := range all {
+			if string(r.Name) >= opt.CursorValue && len(repos) < opt.Limit {
+				repos = append(repos, r)
+			}
+		}
+		return repos, nil
+	}
+	t.Cleanup(func() { backend.Mocks.Repos.List = nil })
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		var resp restError
+		if status := getREST(t, "/v1/repos", false, &resp); status != http.StatusUnauthorized {
+			t.Fatalf("got status %d, want %d", status, http.StatusUnauthorized)
+		}
+		if resp.Error.Status != http.StatusUnauthorized {
+			t.Fatalf("got error %+v", resp.Error)
+		}
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		var names []string
+		url := "/v1/repos?first=2"
+		for pages := 0; ; pages++ {
+			if pages > 2 {
+				t.Fatal("too many pages")
+			}
+			var page restRepositoryPage
+			if status := getREST(t, url, true, &page); status != http.StatusOK {
+				t.Fatalf("got status %d", status)
+			}
+			for _, r := range page.Items {
+				names = append(names, r.Name)
+			}
+			if page.NextCursor == nil {
+				break
+			}
+			url = "/v1/repos?first=2&after=" + *page.NextCursor
+		}
+		if diff := cmp.Diff([]string{"a", "b", "c"}, names); diff != "" {
+			t.Fatalf("unexpected repositories (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		for _, url := range []string{"/v1/repos?first=0", "/v1/repos?first=1001", "/v1/repos?after=nope"} {
+			var resp restError
+			if status := getREST(t, url, true, &resp); status != http.StatusBadRequest {
+				t.Errorf("%s: got status %d, want %d", url, status, http.StatusBadRequest)
+			}
+		}
+	})
+}
+
+func TestRESTRepoContents(t *testing.T) {
+	backend.Mocks.Repos.GetByName = func(ctx context.Context, name api.RepoName) (*types.Repo, error) {
+		if name != "github.com/gorilla/mux" {
+			t.Errorf("got repo %q", name)
+		}
+		return &types.Repo{ID: 2, Name: name}, nil
+	}
+	backend.Mocks.Repos.ResolveRev = func(ctx context.Context, repo *types.Repo, rev string) (api.CommitID, error) {
+		if rev != "v1" {
+			t.Errorf("got rev %q", rev)
+		}
+		return "aed", nil
+	}
+	git.Mocks.ReadFile = func(commit api.CommitID, name string) ([]byte, error) {
+		if name == "logo.png" {
+			return []byte{0xff, 0xfe}, nil
+		}
+		return []byte("package mux\n"), nil
+	}
+	t.Cleanup(func() {
+		backend.Mocks.Repos = backend.MockRepos{}
+		git.ResetMocks()
+	})
+
+	for _, tc := range []struct {
+		path string
+		want restFileContents
+	}{
+		{
+			path: "doc/mux.go",
+			want: restFileContents{Repository: "github.com/gorilla/mux", Commit: "aed", Path: "doc/mux.go", Encoding: "utf-8", Content: "package mux\n"},
+		},
+		{
+			path: "logo.png",
+			want: restFileContents{Repository: "github.com/gorilla/mux", Commit: "aed", Path: "logo.png", Encoding: "base64", Content: "//4="},
+		},
+	} {
+		var got restFileContents
+		if status := getREST(t, "/v1/repos/github.com/gorilla/mux/-/contents/"+tc.path+"?rev=v1", true, &got); status != http.StatusOK {
+			t.Fatalf("got status %d", status)
+		}
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("unexpected contents (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestRESTOpenAPISpec(t *testing.T) {
+	var spec struct {
+		Paths map[string]map[string]struct {
+			OperationID string `json:"operationId"`
+		}
+	}
+	// The specification is public.
+	if status := getREST(t, "/v1/openapi.json", false, &spec); status != http.StatusOK {
+		t.Fatalf("got status %d", status)
+	}
+	for path, id := range map[string]string{
+		"/v1/repos":                          "listRepositories",
+		"/v1/repos/{repo}":                   "getRepository",
+		"/v1/repos/{repo}/-/contents/{path}": "getFileContents",
+		"/v1/search":                         "search",
+	} {
+		if got := spec.Paths[path]["get"].OperationID; got != id {
+			t.Errorf("%s: got operation %q, want %q", path, got, id)
+		}
+	}
+
+	schema := restOpenAPISchema(reflect.TypeOf(restRepositoryPage{}))
+	want := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"name":        map[string]interface{}{"type": "string"},
+						"description": map[string]interface{}{"type": "string"},
+						"fork":        map[string]interface{}{"type": "boolean"},
+						"archived":    map[string]interface{}{"type": "boolean"},
+						"private":     map[string]interface{}{"type": "boolean"},
+						"createdAt":   map[string]interface{}{"type": "string", "format": "date-time"},
+					},
+					"required": []string{"name", "description", "fork", "archived", "private", "createdAt"},
+				},
+			},
+			"nextCursor": map[string]interface{}{"type": "string", "nullable": true},
+		},
+		"required": []string{"items", "nextCursor"},
+	}
+	if diff := cmp.Diff(want, schema); diff != "" {
+		t.Fatalf("unexpected schema (-want +got):\n%s", diff)
+	}
+}
+
+func TestRESTOpenAPIParameters(t *testing.T) {
+	want := []interface{}{
+		map[string]interface{}{
+			"name":        "repo",
+			"in":          "path",
+			"description": "The name of the repository, such as github.com/sourcegraph/sourcegraph.",
+			"required":    true,
+			"schema":      map[string]interface{}{"type": "string"},
+		},
+		map[string]interface{}{
+			"name":        "path",
+			"in":          "path",
+			"description": "The path of the file in the repository.",
+			"required":    true,
+			"schema":      map[string]interface{}{"type": "string"},
+		},
+		map[string]interface{}{
+			"name":        "rev",
+			"in":          "query",
+			"description": "The revision (branch, tag or commit) to read the file at. Defaults to the default branch.",
+			"required":    false,
+			"schema":      map[string]interface{}{"type": "string"},
+		},
+	}
+	if diff := cmp.Diff(want, restOpenAPIParameters(reflect.TypeOf(restRepoContentsParams{}))); diff != "" {
+		t.Fatalf("unexpected parameters (-want +got):\n%s", diff)
+	}
+
+	want = []interface{}{
+		map[string]interface{}{
+			"name":        "first",
+			"in":          "query",
+			"description": "The maximum number of items to return.",
+			"required":    false,
+			"schema":      map[string]interface{}{"type": "integer", "minimum": 1, "maximum": 1000, "default": 50},
+		},
+		map[string]interface{}{
+			"name":        "after",
+			"in":          "query",
+			"description": "The nextCursor of the previous page.",
+			"required":    false,
+			"schema":      map[string]interface{}{"type": "string"},
+		},
+	}
+	if diff := cmp.Diff(want, restOpenAPIParameters(reflect.TypeOf(restPageParams{}))); diff != "" {
+		t.Fatalf("unexpected parameters (-want +got):\n%s", diff)
+	}
+}
+
+func TestRESTEndpointsCoverRoutes(t *testing.T) {
+	documented := map[string]bool{apirouter.RESTOpenAPI: true}
+	for _, e := range restEndpoints {
+		documented[e.Route] = true
+	}
+	_ = apirouter.New(mux.NewRouter()).Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		if name := route.GetName(); strings.HasPrefix(name, "rest.") && !documented[name] {
+			t.Errorf("REST API route %q is missing from restEndpoints", name)
+		}
+		return nil
+	})
+}
+
+type fakeLimiter struct {
+	limited bool
+	cost    int
+}
+
+func (l *fakeLimiter) Get() (graphqlbackend.Limiter, bool) { return l, true }
+
+func (l *fakeLimiter) RateLimit(key string, quantity int, args graphqlbackend.LimiterArgs) (bool, throttled.RateLimitResult, error) {
+	l.cost = quantity
+	return l.limited, throttled.RateLimitResult{Limit: 10, RetryAfter: time.Minute}, nil
+}
+
+func TestRESTSearchRateLimit(t *testing.T) {
+	schema, err := graphqlbackend.NewSchema(new(dbtesting.MockDB), nil, nil, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	limiter := &fakeLimiter{limited: true}
+	h := restHandler(serveRESTSearch(schema, limiter), false, false)
+
+	req := httptest.NewRequest("GET", "/v1/search?q=foo", nil)
+	req = req.WithContext(actor.WithActor(context.Background(), actor.FromUser(1)))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "60" {
+		t.Fatalf("got Retry-After %q, want %q", got, "60")
+	}
+	if limiter.cost <= 0 {
+		t.Fatalf("got cost %d, want the cost of the search query", limiter.cost)
+	}
+}
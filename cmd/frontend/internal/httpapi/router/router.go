@@ -18,13 +18,22 @@ const (
 
 	Registry = "registry"
 
+	RESTOpenAPI      = "rest.openapi"
+	RESTRepos        = "rest.repos"
+	RESTRepo         = "rest.repo"
+	RESTRepoContents = "rest.repo.contents"
+	RESTSearch       = "rest.search"
+
 	RepoShield  = "repo.shield"
 	RepoRefresh = "repo.refresh"
 	Telemetry   = "telemetry"
 
+	Webhooks                = "webhooks"
 	GitHubWebhooks          = "github.webhooks"
 	GitLabWebhooks          = "gitlab.webhooks"
 	BitbucketServerWebhooks = "bitbucketServer.webhooks"
+	BitbucketCloudWebhooks  = "bitbucketCloud.webhooks"
+	AzureDevOpsWebhooks     = "azureDevOps.webhooks"
 
 	SavedQueriesListAll    = "internal.saved-queries.list-all"
 	SavedQueriesGetInfo    = "internal.saved-queries.get-info"
@@ -69,13 +78,17 @@ func New(base *mux.Router) *mux.Router {
 
 	addRegistryRoute(base)
 	addGraphQLRoute(base)
+	base.Path("/webhooks").Methods("POST").Name(Webhooks)
 	base.Path("/github-webhooks").Methods("POST").Name(GitHubWebhooks)
 	base.Path("/gitlab-webhooks").Methods("POST").Name(GitLabWebhooks)
 	base.Path("/bitbucket-server-webhooks").Methods("POST").Name(BitbucketServerWebhooks)
+	base.Path("/bitbucket-cloud-webhooks").Methods("POST").Name(BitbucketCloudWebhooks)
+	base.Path("/azure-devops-webhooks").Methods("POST").Name(AzureDevOpsWebhooks)
 	base.Path("/lsif/upload").Methods("POST").Name(LSIFUpload)
 	base.Path("/search/stream").Methods("GET").Name(SearchStream)
 	base.Path("/src-cli/version").Methods("GET").Name(SrcCliVersion)
 	base.Path("/src-cli/{rest:.*}").Methods("GET").Name(SrcCliDownload)
+	addRESTRoutes(base)
 
 	// repo contains routes that are NOT specific to a revision. In these routes, the URL may not contain a revspec after the repo (that is, no "github.com/foo/bar@myrevspec").
 	repoPath := `/repos/` + routevar.Repo
@@ -139,6 +152,19 @@ func addRegistryRoute(m *mux.Router) {
 	m.PathPrefix("/registry").Methods("GET").Name(Registry)
 }
 
+// addRESTRoutes adds the routes of the versioned REST API. The paths of the OpenAPI
+// specification served at /v1/openapi.json are generated from these routes.
+func addRESTRoutes(m *mux.Router) {
+	m.Path("/v1/openapi.json").Methods("GET").Name(RESTOpenAPI)
+	m.Path("/v1/repos").Methods("GET").Name(RESTRepos)
+	m.Path("/v1/search").Methods("GET").Name(RESTSearch)
+
+	repoPath := `/v1/repos/` + routevar.Repo
+	repo := m.PathPrefix(repoPath + "/" + routevar.RepoPathDelim + "/").Subrouter()
+	repo.Path("/contents/{Path:.*}").Methods("GET").Name(RESTRepoContents)
+	m.Path(repoPath).Methods("GET").Name(RESTRepo)
+}
+
 func addGraphQLRoute(m *mux.Router) {
 	m.Path("/graphql").Methods("POST").Name(GraphQL)
 }
@@ -0,0 +1,45 @@
+package webhookhandlers
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors"
+	gh "github.com/google/go-github/v28/github"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// handleGitHubPushEvent handles a github push webhook by enqueueing an update of the
+// pushed repository in repo-updater, so that it's fetched without waiting for its
+// next scheduled update.
+func handleGitHubPushEvent(db dbutil.DB) func(ctx context.Context, extSvc *types.ExternalService, payload interface{}) error {
+	return func(ctx context.Context, extSvc *types.ExternalService, payload interface{}) error {
+		e, ok := payload.(*gh.PushEvent)
+		if !ok {
+			return errors.Errorf("expected GitHub push event, got %T", payload)
+		}
+
+		// 🚨 SECURITY: we want to be able to find any private repo here, so set internal actor
+		ctx = actor.WithInternalActor(ctx)
+		r, err := database.Repos(db).GetByName(ctx, api.RepoName("github.com/"+e.GetRepo().GetFullName()))
+		if err != nil {
+			if errcode.IsNotFound(err) {
+				// The repository isn't synced by Sourcegraph.
+				return nil
+			}
+			return err
+		}
+
+		log15.Debug("handleGitHubPushEvent: Enqueueing repo update", "repo", r.Name)
+
+		_, err = repoupdater.DefaultClient.EnqueueRepoUpdate(ctx, r.Name)
+		return err
+	}
+}
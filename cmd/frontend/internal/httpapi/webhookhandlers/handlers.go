@@ -23,4 +23,7 @@ func Init(db dbutil.DB, w *webhooks.GitHubWebhook) {
 	w.Register(handleGitHubUserAuthzEvent(db, authz.FetchPermsOptions{InvalidateCaches: true}), "organisation")
 	w.Register(handleGitHubUserAuthzEvent(db, authz.FetchPermsOptions{InvalidateCaches: true}), "membership")
 
+	// Push events enqueue an update of the pushed repository
+	w.Register(handleGitHubPushEvent(db), "push")
+
 }
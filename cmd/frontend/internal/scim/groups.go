@@ -0,0 +1,343 @@
+package scim
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// groupResource is the SCIM representation of a group (RFC 7643, section 4.2). Groups are
+// organizations, and their members are the organization members.
+type groupResource struct {
+	Schemas     []string      `json:"schemas"`
+	ID          string        `json:"id,omitempty"`
+	DisplayName string        `json:"displayName"`
+	Members     []groupMember `json:"members,omitempty"`
+	Meta        *meta         `json:"meta,omitempty"`
+}
+
+type groupMember struct {
+	// Value is the ID of the user.
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+type groupHandler struct {
+	db dbutil.DB
+}
+
+// withMembers reports whether the members of groups should be returned. Identity providers exclude
+// them when they only look up groups, as groups may be large.
+func withMembers(r *http.Request) bool {
+	for _, attr := range strings.Split(r.URL.Query().Get("excludedAttributes"), ",") {
+		if strings.EqualFold(strings.TrimSpace(attr), "members") {
+			return false
+		}
+	}
+	return true
+}
+
+func (h *groupHandler) list(w http.ResponseWriter, r *http.Request) {
+	params, err := parseListParams(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	var (
+		orgs  []*types.Org
+		total int
+	)
+	if params.filter != nil {
+		org, err := h.getByFilter(ctx, params.filter)
+		if err != nil && !errcode.IsNotFound(err) {
+			writeError(w, err)
+			return
+		}
+		if org != nil {
+			total = 1
+			if params.startIndex == 1 && params.count > 0 {
+				orgs = []*types.Org{org}
+			}
+		}
+	} else {
+		if total, err = database.Orgs(h.db).Count(ctx, database.OrgsListOptions{}); err != nil {
+			writeError(w, err)
+			return
+		}
+		orgs, err = database.Orgs(h.db).List(ctx, &database.OrgsListOptions{
+			LimitOffset: &database.LimitOffset{Limit: params.count, Offset: params.startIndex - 1},
+		})
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+
+	resp := listResponse{
+		Schemas:      []string{schemaListResponse},
+		TotalResults: total,
+		StartIndex:   params.startIndex,
+		ItemsPerPage: len(orgs),
+		Resources:    []interface{}{},
+	}
+	for _, org := range orgs {
+		res, err := h.toResource(ctx, org, withMembers(r))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		resp.Resources = append(resp.Resources, res)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// getByFilter returns the organization matched by the filter.
+func (h *groupHandler) getByFilter(ctx context.Context, f *filter) (*types.Org, error) {
+	switch f.attr {
+	case "displayname":
+		name, err := auth.NormalizeUsername(f.value)
+		if err != nil {
+			return nil, nil
+		}
+		return database.Orgs(h.db).GetByName(ctx, name)
+	case "id":
+		id, err := strconv.ParseInt(f.value, 10, 32)
+		if err != nil {
+			return nil, nil
+		}
+		return database.Orgs(h.db).GetByID(ctx, int32(id))
+	}
+	return nil, &scimError{status: http.StatusBadRequest, scimType: "invalidFilter", detail: "Groups can only be filtered by displayName or id."}
+}
+
+func (h *groupHandler) create(w http.ResponseWriter, r *http.Request) {
+	var res groupResource
+	if err := readJSON(r, &res); err != nil {
+		writeError(w, err)
+		return
+	}
+	if res.DisplayName == "" {
+		writeError(w, errInvalidValue("The displayName attribute is required."))
+		return
+	}
+	name, err := auth.NormalizeUsername(res.DisplayName)
+	if err != nil {
+		writeError(w, errInvalidValue(err.Error()))
+		return
+	}
+
+	// The names of users and organizations share a namespace.
+	ctx := r.Context()
+	if _, err := database.Orgs(h.db).GetByName(ctx, name); !errcode.IsNotFound(err) {
+		writeError(w, errGroupNameInUse(err))
+		return
+	}
+	if _, err := database.Users(h.db).GetByUsername(ctx, name); !errcode.IsNotFound(err) {
+		writeError(w, errGroupNameInUse(err))
+		return
+	}
+
+	org, err := database.Orgs(h.db).Create(ctx, name, &res.DisplayName)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := h.syncMembers(ctx, org.ID, res.Members); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	created, err := h.toResource(ctx, org, true)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Location", created.Meta.Location)
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// errGroupNameInUse returns the error of a name lookup that found the group name in use.
+func errGroupNameInUse(err error) error {
+	if err != nil {
+		return err
+	}
+	return &scimError{status: http.StatusConflict, scimType: "uniqueness", detail: "Group name is already in use."}
+}
+
+// getOrg returns the organization of the request's path. Deleted organizations are not found.
+func (h *groupHandler) getOrg(r *http.Request) (*types.Org, error) {
+	notFound := &scimError{status: http.StatusNotFound, detail: "Group not found."}
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		return nil, notFound
+	}
+	org, err := database.Orgs(h.db).GetByID(r.Context(), int32(id))
+	if errcode.IsNotFound(err) {
+		return nil, notFound
+	}
+	return org, err
+}
+
+func (h *groupHandler) get(w http.ResponseWriter, r *http.Request) {
+	org, err := h.getOrg(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	res, err := h.toResource(r.Context(), org, withMembers(r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (h *groupHandler) replace(w http.ResponseWriter, r *http.Request) {
+	org, err := h.getOrg(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var res groupResource
+	if err := readJSON(r, &res); err != nil {
+		writeError(w, err)
+		return
+	}
+	h.update(w, r, org, &res)
+}
+
+func (h *groupHandler) patch(w http.ResponseWriter, r *http.Request) {
+	org, err := h.getOrg(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	current, err := h.toResource(r.Context(), org, true)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var res groupResource
+	if err := patchResource(r, current, &res); err != nil {
+		writeError(w, err)
+		return
+	}
+	h.update(w, r, org, &res)
+}
+
+// update updates the organization to match the resource. The name of an organization cannot be
+// changed, so a new display name only changes the organization's display name.
+func (h *groupHandler) update(w http.ResponseWriter, r *http.Request, org *types.Org, res *groupResource) {
+	ctx := r.Context()
+	if res.DisplayName != "" && (org.DisplayName == nil || *org.DisplayName != res.DisplayName) {
+		var err error
+		if org, err = database.Orgs(h.db).Update(ctx, org.ID, &res.DisplayName); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+	if err := h.syncMembers(ctx, org.ID, res.Members); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	updated, err := h.toResource(ctx, org, true)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// syncMembers makes the members the only members of the organization. Members that are not users
+// (e.g. because they were deactivated) are ignored.
+func (h *groupHandler) syncMembers(ctx context.Context, orgID int32, members []groupMember) error {
+	want := make(map[int32]bool, len(members))
+	for _, m := range members {
+		id, err := strconv.ParseInt(m.Value, 10, 32)
+		if err != nil {
+			return errInvalidValue("Invalid member " + strconv.Quote(m.Value) + ".")
+		}
+		want[int32(id)] = true
+	}
+
+	current, err := database.OrgMembers(h.db).GetByOrgID(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	for _, m := range current {
+		if want[m.UserID] {
+			delete(want, m.UserID)
+			continue
+		}
+		if err := database.OrgMembers(h.db).Remove(ctx, orgID, m.UserID); err != nil {
+			return err
+		}
+	}
+	for userID := range want {
+		if _, err := database.Users(h.db).GetByID(ctx, userID); errcode.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if _, err := database.OrgMembers(h.db).Create(ctx, orgID, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *groupHandler) delete(w http.ResponseWriter, r *http.Request) {
+	org, err := h.getOrg(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := database.Orgs(h.db).Delete(r.Context(), org.ID); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *groupHandler) toResource(ctx context.Context, org *types.Org, withMembers bool) (*groupResource, error) {
+	res := &groupResource{
+		Schemas:     []string{schemaGroup},
+		ID:          strconv.Itoa(int(org.ID)),
+		DisplayName: org.Name,
+		Meta: &meta{
+			ResourceType: "Group",
+			Created:      org.CreatedAt,
+			LastModified: org.UpdatedAt,
+			Location:     PathPrefix + "/Groups/" + strconv.Itoa(int(org.ID)),
+		},
+	}
+	if org.DisplayName != nil && *org.DisplayName != "" {
+		res.DisplayName = *org.DisplayName
+	}
+	if !withMembers {
+		return res, nil
+	}
+
+	members, err := database.OrgMembers(h.db).GetByOrgID(ctx, org.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range members {
+		id := strconv.Itoa(int(m.UserID))
+		res.Members = append(res.Members, groupMember{Value: id, Ref: PathPrefix + "/Users/" + id})
+	}
+	return res, nil
+}
@@ -0,0 +1,275 @@
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// patchRequest is the body of a PATCH request (RFC 7644, section 3.5.2).
+type patchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []patchOperation `json:"Operations"`
+}
+
+type patchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// patchPath is a parsed PATCH operation path, e.g. `members[value eq "1"]` or `name.givenName`.
+type patchPath struct {
+	attr string
+	// filter selects the values of a multi-valued attribute the operation applies to, if set.
+	filter *filter
+	// subAttr is the sub-attribute of the (selected values of the) attribute, if set.
+	subAttr string
+}
+
+func parsePatchPath(s string) (*patchPath, error) {
+	invalid := &scimError{status: http.StatusBadRequest, scimType: "invalidPath", detail: "Invalid path " + s + "."}
+
+	// Attributes may be prefixed with their schema URN, e.g.
+	// urn:ietf:params:scim:schemas:core:2.0:User:userName.
+	if strings.HasPrefix(s, "urn:") {
+		if i := strings.LastIndex(strings.SplitN(s, "[", 2)[0], ":"); i >= 0 {
+			s = s[i+1:]
+		}
+	}
+
+	p := &patchPath{}
+	if i := strings.Index(s, "["); i >= 0 {
+		j := strings.Index(s, "]")
+		if j < i {
+			return nil, invalid
+		}
+		f, err := parseFilter(s[i+1 : j])
+		if err != nil {
+			return nil, err
+		}
+		p.filter = f
+		p.attr = s[:i]
+		if rest := s[j+1:]; rest != "" {
+			if !strings.HasPrefix(rest, ".") {
+				return nil, invalid
+			}
+			p.subAttr = rest[1:]
+		}
+	} else if i := strings.Index(s, "."); i >= 0 {
+		p.attr, p.subAttr = s[:i], s[i+1:]
+	} else {
+		p.attr = s
+	}
+	if p.attr == "" {
+		return nil, invalid
+	}
+	return p, nil
+}
+
+// applyPatch applies the operations of a PATCH request to the JSON representation of a resource.
+// Attribute names are case-insensitive, as required by SCIM.
+func applyPatch(resource map[string]interface{}, ops []patchOperation) error {
+	for _, op := range ops {
+		switch strings.ToLower(op.Op) {
+		case "add", "replace", "remove":
+		default:
+			return errInvalidValue("Unsupported operation " + op.Op + ".")
+		}
+		op.Op = strings.ToLower(op.Op)
+
+		if op.Path == "" {
+			if op.Op == "remove" {
+				return &scimError{status: http.StatusBadRequest, scimType: "noTarget", detail: "A path is required to remove attributes."}
+			}
+			values, ok := op.Value.(map[string]interface{})
+			if !ok {
+				return errInvalidValue("The value of an operation without path must be an object.")
+			}
+			for k, v := range values {
+				path, err := parsePatchPath(k)
+				if err != nil {
+					return err
+				}
+				if err := applyOperation(resource, op.Op, path, v); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		path, err := parsePatchPath(op.Path)
+		if err != nil {
+			return err
+		}
+		if err := applyOperation(resource, op.Op, path, op.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyOperation(resource map[string]interface{}, op string, path *patchPath, value interface{}) error {
+	key := lookupKey(resource, path.attr)
+
+	if path.filter != nil {
+		values, _ := resource[key].([]interface{})
+		var kept []interface{}
+		for _, v := range values {
+			obj, ok := v.(map[string]interface{})
+			if !ok || !matches(obj, path.filter) {
+				kept = append(kept, v)
+				continue
+			}
+			switch {
+			case op == "remove" && path.subAttr == "":
+				// Drop the value.
+			case op == "remove":
+				delete(obj, lookupKey(obj, path.subAttr))
+				kept = append(kept, obj)
+			case path.subAttr == "":
+				if m, ok := value.(map[string]interface{}); ok {
+					for k, v := range m {
+						obj[lookupKey(obj, k)] = v
+					}
+				}
+				kept = append(kept, obj)
+			default:
+				obj[lookupKey(obj, path.subAttr)] = value
+				kept = append(kept, obj)
+			}
+		}
+		resource[key] = kept
+		return nil
+	}
+
+	if path.subAttr != "" {
+		obj, ok := resource[key].(map[string]interface{})
+		if !ok {
+			if op == "remove" {
+				return nil
+			}
+			obj = map[string]interface{}{}
+			resource[key] = obj
+		}
+		if op == "remove" {
+			delete(obj, lookupKey(obj, path.subAttr))
+		} else {
+			obj[lookupKey(obj, path.subAttr)] = value
+		}
+		return nil
+	}
+
+	switch op {
+	case "remove":
+		// Some identity providers (e.g. Azure AD) remove values of multi-valued attributes by
+		// listing them in the value instead of the path.
+		existing, isList := resource[key].([]interface{})
+		removed, hasValues := value.([]interface{})
+		if !isList || !hasValues {
+			delete(resource, key)
+			return nil
+		}
+		var kept []interface{}
+		for _, v := range existing {
+			if !containsValue(removed, v) {
+				kept = append(kept, v)
+			}
+		}
+		resource[key] = kept
+
+	case "add":
+		if existing, ok := resource[key].([]interface{}); ok {
+			if added, ok := value.([]interface{}); ok {
+				for _, v := range added {
+					if !containsValue(existing, v) {
+						existing = append(existing, v)
+					}
+				}
+				resource[key] = existing
+				return nil
+			}
+		}
+		resource[key] = value
+
+	case "replace":
+		resource[key] = value
+	}
+	return nil
+}
+
+// lookupKey returns the key of the object that matches attr case-insensitively, or attr if there
+// is none.
+func lookupKey(obj map[string]interface{}, attr string) string {
+	if _, ok := obj[attr]; ok {
+		return attr
+	}
+	for k := range obj {
+		if strings.EqualFold(k, attr) {
+			return k
+		}
+	}
+	return attr
+}
+
+// matches reports whether the value of a multi-valued attribute matches the filter.
+func matches(obj map[string]interface{}, f *filter) bool {
+	v, ok := obj[lookupKey(obj, f.attr)]
+	if !ok {
+		return false
+	}
+	switch v := v.(type) {
+	case string:
+		return v == f.value
+	default:
+		b, _ := json.Marshal(v)
+		return string(b) == f.value
+	}
+}
+
+// containsValue reports whether values contains v. Values of multi-valued attributes, such as
+// group members, are identified by their "value" sub-attribute.
+func containsValue(values []interface{}, v interface{}) bool {
+	id := func(v interface{}) interface{} {
+		if obj, ok := v.(map[string]interface{}); ok {
+			return obj[lookupKey(obj, "value")]
+		}
+		return v
+	}
+	for _, w := range values {
+		if reflect.DeepEqual(id(w), id(v)) {
+			return true
+		}
+	}
+	return false
+}
+
+// patchResource applies the PATCH request to the resource and decodes the result into dst.
+func patchResource(r *http.Request, resource, dst interface{}) error {
+	var req patchRequest
+	if err := readJSON(r, &req); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return err
+	}
+	if err := applyPatch(obj, req.Operations); err != nil {
+		return err
+	}
+
+	b, err = json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, dst); err != nil {
+		return errInvalidValue("The patched resource is invalid: " + err.Error())
+	}
+	return nil
+}
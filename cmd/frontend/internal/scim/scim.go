@@ -0,0 +1,240 @@
+// Package scim implements a SCIM 2.0 (RFC 7643, RFC 7644) service provider, which lets identity
+// providers (such as Okta or Azure AD) provision users and sync group membership to organizations.
+package scim
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gorilla/mux"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// PathPrefix is the path under which the SCIM API is served.
+const PathPrefix = "/.api/scim/v2"
+
+const (
+	schemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	schemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	schemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	schemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	schemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	schemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+)
+
+// maxPageSize is the maximum number of resources returned by a list request.
+const maxPageSize = 100
+
+// NewHandler returns the handler of the SCIM API.
+//
+// 🚨 SECURITY: The handler implements its own token auth, using the scim.authToken site
+// configuration. It acts as an internal actor, so it must not be mounted behind any other auth.
+func NewHandler(db dbutil.DB) http.Handler {
+	r := mux.NewRouter().PathPrefix(PathPrefix).Subrouter()
+	r.StrictSlash(true)
+	r.Path("/ServiceProviderConfig").Methods("GET").HandlerFunc(serveServiceProviderConfig)
+
+	users := &userHandler{db: db}
+	r.Path("/Users").Methods("GET").HandlerFunc(users.list)
+	r.Path("/Users").Methods("POST").HandlerFunc(users.create)
+	r.Path("/Users/{id}").Methods("GET").HandlerFunc(users.get)
+	r.Path("/Users/{id}").Methods("PUT").HandlerFunc(users.replace)
+	r.Path("/Users/{id}").Methods("PATCH").HandlerFunc(users.patch)
+	r.Path("/Users/{id}").Methods("DELETE").HandlerFunc(users.delete)
+
+	groups := &groupHandler{db: db}
+	r.Path("/Groups").Methods("GET").HandlerFunc(groups.list)
+	r.Path("/Groups").Methods("POST").HandlerFunc(groups.create)
+	r.Path("/Groups/{id}").Methods("GET").HandlerFunc(groups.get)
+	r.Path("/Groups/{id}").Methods("PUT").HandlerFunc(groups.replace)
+	r.Path("/Groups/{id}").Methods("PATCH").HandlerFunc(groups.patch)
+	r.Path("/Groups/{id}").Methods("DELETE").HandlerFunc(groups.delete)
+
+	r.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, &scimError{status: http.StatusNotFound, detail: "Resource not found."})
+	})
+	r.MethodNotAllowedHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, &scimError{status: http.StatusMethodNotAllowed, detail: "Method not allowed."})
+	})
+
+	return authMiddleware(r)
+}
+
+// authMiddleware authenticates requests with the bearer token of the scim.authToken site
+// configuration. The SCIM API does not exist if no token is configured.
+func authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		want := conf.Get().ScimAuthToken
+		if want == "" {
+			http.NotFound(w, r)
+			return
+		}
+
+		token := r.Header.Get("Authorization")
+		if !strings.HasPrefix(strings.ToLower(token), "bearer ") {
+			writeError(w, &scimError{status: http.StatusUnauthorized, detail: "A bearer token is required."})
+			return
+		}
+		token = strings.TrimSpace(token[len("bearer "):])
+		if subtle.ConstantTimeCompare([]byte(token), []byte(want)) != 1 {
+			writeError(w, &scimError{status: http.StatusUnauthorized, detail: "Invalid bearer token."})
+			return
+		}
+
+		// 🚨 SECURITY: The identity provider is trusted to manage all users and organizations.
+		next.ServeHTTP(w, r.WithContext(actor.WithInternalActor(r.Context())))
+	})
+}
+
+func serveServiceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	supported := func(supported bool) map[string]interface{} {
+		return map[string]interface{}{"supported": supported}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"schemas":        []string{schemaServiceProviderConfig},
+		"patch":          supported(true),
+		"bulk":           map[string]interface{}{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]interface{}{"supported": true, "maxResults": maxPageSize},
+		"changePassword": supported(false),
+		"sort":           supported(false),
+		"etag":           supported(false),
+		"authenticationSchemes": []map[string]interface{}{{
+			"type":        "oauthbearertoken",
+			"name":        "OAuth Bearer Token",
+			"description": "Authentication with the token of the scim.authToken site configuration.",
+		}},
+	})
+}
+
+// scimError is an error response of the SCIM API (RFC 7644, section 3.12).
+type scimError struct {
+	status int
+	// scimType is the SCIM detail error keyword, e.g. "uniqueness" or "invalidFilter".
+	scimType string
+	detail   string
+}
+
+func (e *scimError) Error() string {
+	return e.detail
+}
+
+func errInvalidValue(detail string) *scimError {
+	return &scimError{status: http.StatusBadRequest, scimType: "invalidValue", detail: detail}
+}
+
+// writeError writes err as a SCIM error response. Errors that are not SCIM errors are internal
+// errors, their details are logged but not returned.
+func writeError(w http.ResponseWriter, err error) {
+	var e *scimError
+	if !errors.As(err, &e) {
+		log15.Error("SCIM request failed.", "error", err)
+		e = &scimError{status: http.StatusInternalServerError, detail: "Internal server error."}
+	}
+	body := map[string]interface{}{
+		"schemas": []string{schemaError},
+		"status":  strconv.Itoa(e.status),
+		"detail":  e.detail,
+	}
+	if e.scimType != "" {
+		body["scimType"] = e.scimType
+	}
+	writeJSON(w, e.status, body)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func readJSON(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return &scimError{status: http.StatusBadRequest, scimType: "invalidSyntax", detail: "Invalid JSON request body."}
+	}
+	return nil
+}
+
+// listResponse is the response to a list request (RFC 7644, section 3.4.2).
+type listResponse struct {
+	Schemas      []string      `json:"schemas"`
+	TotalResults int           `json:"totalResults"`
+	StartIndex   int           `json:"startIndex"`
+	ItemsPerPage int           `json:"itemsPerPage"`
+	Resources    []interface{} `json:"Resources"`
+}
+
+// listParams are the parameters of a list request.
+type listParams struct {
+	filter *filter
+	// startIndex is the 1-based index of the first result.
+	startIndex int
+	count      int
+}
+
+func parseListParams(r *http.Request) (*listParams, error) {
+	q := r.URL.Query()
+	p := &listParams{startIndex: 1, count: maxPageSize}
+	if v := q.Get("filter"); v != "" {
+		f, err := parseFilter(v)
+		if err != nil {
+			return nil, err
+		}
+		p.filter = f
+	}
+	if v := q.Get("startIndex"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, errInvalidValue("Invalid startIndex.")
+		}
+		if n > 1 {
+			p.startIndex = n
+		}
+	}
+	if v := q.Get("count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, errInvalidValue("Invalid count.")
+		}
+		if n < 0 {
+			n = 0
+		}
+		if n < p.count {
+			p.count = n
+		}
+	}
+	return p, nil
+}
+
+// filter is a SCIM filter. Only equality filters on a single attribute are supported, which is
+// what identity providers use to look up resources before provisioning them.
+type filter struct {
+	// attr is the lowercased attribute name, e.g. "username".
+	attr  string
+	value string
+}
+
+// parseFilter parses a filter of the form `attr eq "value"`.
+func parseFilter(s string) (*filter, error) {
+	invalid := &scimError{status: http.StatusBadRequest, scimType: "invalidFilter", detail: "Only filters of the form 'attribute eq \"value\"' are supported."}
+
+	fields := strings.SplitN(strings.TrimSpace(s), " ", 3)
+	if len(fields) != 3 || !strings.EqualFold(fields[1], "eq") {
+		return nil, invalid
+	}
+	value := strings.TrimSpace(fields[2])
+	if len(value) < 2 || value[0] != '"' {
+		return nil, invalid
+	}
+	if err := json.Unmarshal([]byte(value), &value); err != nil {
+		return nil, invalid
+	}
+	return &filter{attr: strings.ToLower(fields[0]), value: value}, nil
+}
@@ -0,0 +1,289 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+const testToken = "0123456789abcdef0123"
+
+func do(t *testing.T, method, path, token, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	NewHandler(nil).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAuth(t *testing.T) {
+	defer conf.Mock(nil)
+
+	conf.Mock(&conf.Unified{})
+	if rec := do(t, "GET", "/.api/scim/v2/ServiceProviderConfig", testToken, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("without configured token: got status %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{ScimAuthToken: testToken}})
+	for _, tc := range []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{name: "no token", token: "", want: http.StatusUnauthorized},
+		{name: "wrong token", token: "x" + testToken, want: http.StatusUnauthorized},
+		{name: "valid token", token: testToken, want: http.StatusOK},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := do(t, "GET", "/.api/scim/v2/ServiceProviderConfig", tc.token, "")
+			if rec.Code != tc.want {
+				t.Errorf("got status %d, want %d", rec.Code, tc.want)
+			}
+			if got, want := rec.Header().Get("Content-Type"), "application/scim+json"; got != want {
+				t.Errorf("got content type %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestUsers(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{ScimAuthToken: testToken}})
+	defer conf.Mock(nil)
+
+	users := map[int32]*types.User{}
+	emails := map[int32]string{}
+	database.Mocks.Users.Create = func(ctx context.Context, info database.NewUser) (*types.User, error) {
+		if !info.EmailIsVerified {
+			t.Error("want provisioned email to be verified")
+		}
+		user := &types.User{ID: int32(len(users) + 1), Username: info.Username, DisplayName: info.DisplayName}
+		users[user.ID] = user
+		emails[user.ID] = info.Email
+		return user, nil
+	}
+	database.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		if user, ok := users[id]; ok {
+			return user, nil
+		}
+		return nil, &errcodeNotFound{}
+	}
+	database.Mocks.Users.GetByVerifiedEmail = func(ctx context.Context, email string) (*types.User, error) {
+		for id, e := range emails {
+			if e == email {
+				return users[id], nil
+			}
+		}
+		return nil, &errcodeNotFound{}
+	}
+	database.Mocks.Users.Update = func(id int32, update database.UserUpdate) error {
+		if update.DisplayName != nil {
+			users[id].DisplayName = *update.DisplayName
+		}
+		return nil
+	}
+	database.Mocks.Users.Delete = func(ctx context.Context, id int32) error {
+		delete(users, id)
+		delete(emails, id)
+		return nil
+	}
+	database.Mocks.UserEmails.GetPrimaryEmail = func(ctx context.Context, id int32) (string, bool, error) {
+		if email, ok := emails[id]; ok {
+			return email, true, nil
+		}
+		return "", false, &errcodeNotFound{}
+	}
+	defer func() {
+		database.Mocks.Users = database.MockUsers{}
+		database.Mocks.UserEmails = database.MockUserEmails{}
+	}()
+
+	rec := do(t, "POST", "/.api/scim/v2/Users", testToken, `{
+		"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"],
+		"userName": "alice@example.com",
+		"name": {"givenName": "Alice", "familyName": "Smith"},
+		"active": true
+	}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("got status %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body)
+	}
+	var created userResource
+	if err := json.NewDecoder(rec.Body).Decode(&created); err != nil {
+		t.Fatal(err)
+	}
+	if created.ID != "1" || created.UserName != "alice" || created.DisplayName != "Alice Smith" || emails[1] != "alice@example.com" {
+		t.Fatalf("got unexpected user %+v", created)
+	}
+
+	rec = do(t, "GET", `/.api/scim/v2/Users?filter=userName+eq+"alice@example.com"`, testToken, "")
+	var list listResponse
+	if err := json.NewDecoder(rec.Body).Decode(&list); err != nil {
+		t.Fatal(err)
+	}
+	if list.TotalResults != 1 || len(list.Resources) != 1 {
+		t.Fatalf("got %d results, want the created user", list.TotalResults)
+	}
+
+	rec = do(t, "PATCH", "/.api/scim/v2/Users/1", testToken, `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+		"Operations": [{"op": "Replace", "path": "displayName", "value": "Alice"}]
+	}`)
+	if rec.Code != http.StatusOK || users[1].DisplayName != "Alice" {
+		t.Fatalf("got status %d and user %+v, want display name updated: %s", rec.Code, users[1], rec.Body)
+	}
+
+	// Users provisioned without the active attribute are active.
+	rec = do(t, "POST", "/.api/scim/v2/Users", testToken, `{
+		"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"],
+		"userName": "bob@example.com"
+	}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("got status %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body)
+	}
+
+	// Replacing a user without the active attribute leaves it active.
+	rec = do(t, "PUT", "/.api/scim/v2/Users/2", testToken, `{
+		"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"],
+		"userName": "bob",
+		"displayName": "Bob"
+	}`)
+	if _, ok := users[2]; rec.Code != http.StatusOK || !ok || users[2].DisplayName != "Bob" {
+		t.Fatalf("got status %d and users %+v, want user updated and not deleted: %s", rec.Code, users, rec.Body)
+	}
+
+	// Deactivating a user deletes it, as Azure AD does with a string value.
+	rec = do(t, "PATCH", "/.api/scim/v2/Users/1", testToken, `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+		"Operations": [{"op": "Replace", "path": "active", "value": "False"}]
+	}`)
+	if _, ok := users[1]; rec.Code != http.StatusOK || ok {
+		t.Fatalf("got status %d and users %+v, want user deleted: %s", rec.Code, users, rec.Body)
+	}
+	if rec := do(t, "GET", "/.api/scim/v2/Users/1", testToken, ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+type errcodeNotFound struct{}
+
+func (*errcodeNotFound) Error() string  { return "not found" }
+func (*errcodeNotFound) NotFound() bool { return true }
+
+func TestParseFilter(t *testing.T) {
+	for _, tc := range []struct {
+		filter string
+		want   *filter
+	}{
+		{filter: `userName eq "alice@example.com"`, want: &filter{attr: "username", value: "alice@example.com"}},
+		{filter: `displayName EQ "Team \"A\""`, want: &filter{attr: "displayname", value: `Team "A"`}},
+		{filter: `userName eq alice`},
+		{filter: `userName sw "a"`},
+		{filter: `userName eq "a" and active eq true`},
+		{filter: `userName`},
+	} {
+		t.Run(tc.filter, func(t *testing.T) {
+			got, err := parseFilter(tc.filter)
+			if tc.want == nil {
+				if err == nil {
+					t.Fatalf("got filter %+v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		resource string
+		ops      string
+		want     string
+	}{
+		{
+			name:     "replace attribute case-insensitively",
+			resource: `{"displayName": "a"}`,
+			ops:      `[{"op": "replace", "path": "DISPLAYNAME", "value": "b"}]`,
+			want:     `{"displayName": "b"}`,
+		},
+		{
+			name:     "replace without path",
+			resource: `{"displayName": "a", "active": true}`,
+			ops:      `[{"op": "replace", "value": {"active": false, "name.givenName": "Alice"}}]`,
+			want:     `{"displayName": "a", "active": false, "name": {"givenName": "Alice"}}`,
+		},
+		{
+			name:     "add members",
+			resource: `{"members": [{"value": "1"}]}`,
+			ops:      `[{"op": "add", "path": "members", "value": [{"value": "1"}, {"value": "2"}]}]`,
+			want:     `{"members": [{"value": "1"}, {"value": "2"}]}`,
+		},
+		{
+			name:     "add members to empty group",
+			resource: `{}`,
+			ops:      `[{"op": "add", "path": "members", "value": [{"value": "2"}]}]`,
+			want:     `{"members": [{"value": "2"}]}`,
+		},
+		{
+			name:     "remove member by filter",
+			resource: `{"members": [{"value": "1"}, {"value": "2"}]}`,
+			ops:      `[{"op": "remove", "path": "members[value eq \"1\"]"}]`,
+			want:     `{"members": [{"value": "2"}]}`,
+		},
+		{
+			name:     "remove members by value",
+			resource: `{"members": [{"value": "1"}, {"value": "2"}, {"value": "3"}]}`,
+			ops:      `[{"op": "remove", "path": "members", "value": [{"value": "1"}, {"value": "3"}]}]`,
+			want:     `{"members": [{"value": "2"}]}`,
+		},
+		{
+			name:     "replace sub-attribute by filter",
+			resource: `{"emails": [{"type": "work", "value": "a@example.com"}]}`,
+			ops:      `[{"op": "replace", "path": "emails[type eq \"work\"].value", "value": "b@example.com"}]`,
+			want:     `{"emails": [{"type": "work", "value": "b@example.com"}]}`,
+		},
+		{
+			name:     "schema prefixed path",
+			resource: `{"userName": "a"}`,
+			ops:      `[{"op": "replace", "path": "urn:ietf:params:scim:schemas:core:2.0:User:userName", "value": "b"}]`,
+			want:     `{"userName": "b"}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var resource, want map[string]interface{}
+			var ops []patchOperation
+			for s, v := range map[string]interface{}{tc.resource: &resource, tc.ops: &ops, tc.want: &want} {
+				if err := json.Unmarshal([]byte(s), v); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := applyPatch(resource, ops); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(resource, want) {
+				t.Errorf("got %+v, want %+v", resource, want)
+			}
+		})
+	}
+
+	if err := applyPatch(map[string]interface{}{}, []patchOperation{{Op: "move", Path: "a"}}); err == nil {
+		t.Error("got no error for unsupported operation")
+	}
+}
@@ -0,0 +1,446 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// userResource is the SCIM representation of a user (RFC 7643, section 4.1).
+type userResource struct {
+	Schemas     []string    `json:"schemas"`
+	ID          string      `json:"id,omitempty"`
+	UserName    string      `json:"userName"`
+	DisplayName string      `json:"displayName,omitempty"`
+	Name        *userName   `json:"name,omitempty"`
+	Emails      []userEmail `json:"emails,omitempty"`
+	Active      *scimBool   `json:"active"`
+	Meta        *meta       `json:"meta,omitempty"`
+}
+
+type userName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type userEmail struct {
+	Value   string   `json:"value"`
+	Type    string   `json:"type,omitempty"`
+	Primary scimBool `json:"primary,omitempty"`
+}
+
+type meta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location"`
+}
+
+// scimBool is a boolean that is also decoded from the strings "True" and "False", which some
+// identity providers (e.g. Azure AD) send in PATCH requests.
+type scimBool bool
+
+func newScimBool(b bool) *scimBool {
+	v := scimBool(b)
+	return &v
+}
+
+// isFalse returns whether the boolean is present and false. Attributes like "active" have no
+// default value (RFC 7643, section 4.1.1), so an absent one doesn't change anything.
+func (b *scimBool) isFalse() bool {
+	return b != nil && !bool(*b)
+}
+
+func (b *scimBool) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case bool:
+		*b = scimBool(v)
+	case string:
+		parsed, err := strconv.ParseBool(strings.ToLower(v))
+		if err != nil {
+			return errInvalidValue("Invalid boolean " + strconv.Quote(v) + ".")
+		}
+		*b = scimBool(parsed)
+	case nil:
+		*b = false
+	default:
+		return errInvalidValue("Invalid boolean " + string(data) + ".")
+	}
+	return nil
+}
+
+// primaryEmail returns the primary email of the user resource. If the resource has no emails, the
+// user name is used if it is an email address, as is common for identity providers.
+func (u *userResource) primaryEmail() string {
+	for _, e := range u.Emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	if len(u.Emails) > 0 {
+		return u.Emails[0].Value
+	}
+	if strings.Contains(u.UserName, "@") {
+		return u.UserName
+	}
+	return ""
+}
+
+// displayName returns the display name of the user resource, falling back to its name.
+func (u *userResource) displayName() string {
+	if u.DisplayName != "" || u.Name == nil {
+		return u.DisplayName
+	}
+	if u.Name.Formatted != "" {
+		return u.Name.Formatted
+	}
+	return strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName)
+}
+
+type userHandler struct {
+	db dbutil.DB
+}
+
+func (h *userHandler) list(w http.ResponseWriter, r *http.Request) {
+	params, err := parseListParams(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	var (
+		users []*types.User
+		total int
+	)
+	if params.filter != nil {
+		user, err := h.getByFilter(ctx, params.filter)
+		if err != nil && !errcode.IsNotFound(err) {
+			writeError(w, err)
+			return
+		}
+		if user != nil {
+			total = 1
+			if params.startIndex == 1 && params.count > 0 {
+				users = []*types.User{user}
+			}
+		}
+	} else {
+		if total, err = database.Users(h.db).Count(ctx, nil); err != nil {
+			writeError(w, err)
+			return
+		}
+		users, err = database.Users(h.db).List(ctx, &database.UsersListOptions{
+			LimitOffset: &database.LimitOffset{Limit: params.count, Offset: params.startIndex - 1},
+		})
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+
+	resp := listResponse{
+		Schemas:      []string{schemaListResponse},
+		TotalResults: total,
+		StartIndex:   params.startIndex,
+		ItemsPerPage: len(users),
+		Resources:    []interface{}{},
+	}
+	for _, user := range users {
+		res, err := h.toResource(ctx, user)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		resp.Resources = append(resp.Resources, res)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// getByFilter returns the user matched by the filter. Identity providers look users up by their
+// user name, which is usually their email address.
+func (h *userHandler) getByFilter(ctx context.Context, f *filter) (*types.User, error) {
+	switch f.attr {
+	case "username":
+		if strings.Contains(f.value, "@") {
+			return database.Users(h.db).GetByVerifiedEmail(ctx, f.value)
+		}
+		return database.Users(h.db).GetByUsername(ctx, f.value)
+	case "emails", "emails.value":
+		return database.Users(h.db).GetByVerifiedEmail(ctx, f.value)
+	case "id":
+		id, err := strconv.ParseInt(f.value, 10, 32)
+		if err != nil {
+			return nil, nil
+		}
+		return database.Users(h.db).GetByID(ctx, int32(id))
+	}
+	return nil, &scimError{status: http.StatusBadRequest, scimType: "invalidFilter", detail: "Users can only be filtered by userName, emails or id."}
+}
+
+func (h *userHandler) create(w http.ResponseWriter, r *http.Request) {
+	var res userResource
+	if err := readJSON(r, &res); err != nil {
+		writeError(w, err)
+		return
+	}
+	if res.UserName == "" {
+		writeError(w, errInvalidValue("The userName attribute is required."))
+		return
+	}
+	if res.Active.isFalse() {
+		writeError(w, errInvalidValue("Inactive users cannot be provisioned."))
+		return
+	}
+	username, err := auth.NormalizeUsername(res.UserName)
+	if err != nil {
+		writeError(w, errInvalidValue(err.Error()))
+		return
+	}
+
+	user, err := database.Users(h.db).Create(r.Context(), database.NewUser{
+		Username:    username,
+		Email:       res.primaryEmail(),
+		DisplayName: res.displayName(),
+		// 🚨 SECURITY: The identity provider is trusted to have verified the email address.
+		EmailIsVerified: true,
+	})
+	if err != nil {
+		if database.IsUsernameExists(err) || database.IsEmailExists(err) {
+			err = &scimError{status: http.StatusConflict, scimType: "uniqueness", detail: err.Error()}
+		}
+		writeError(w, err)
+		return
+	}
+
+	created, err := h.toResource(r.Context(), user)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Location", created.Meta.Location)
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// getUser returns the user of the request's path. Deleted users are not found.
+func (h *userHandler) getUser(r *http.Request) (*types.User, error) {
+	notFound := &scimError{status: http.StatusNotFound, detail: "User not found."}
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		return nil, notFound
+	}
+	user, err := database.Users(h.db).GetByID(r.Context(), int32(id))
+	if errcode.IsNotFound(err) {
+		return nil, notFound
+	}
+	return user, err
+}
+
+func (h *userHandler) get(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUser(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	res, err := h.toResource(r.Context(), user)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (h *userHandler) replace(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUser(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var res userResource
+	if err := readJSON(r, &res); err != nil {
+		writeError(w, err)
+		return
+	}
+	h.update(w, r, user, &res)
+}
+
+func (h *userHandler) patch(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUser(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	current, err := h.toResource(r.Context(), user)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var res userResource
+	if err := patchResource(r, current, &res); err != nil {
+		writeError(w, err)
+		return
+	}
+	h.update(w, r, user, &res)
+}
+
+// update updates the user to match the resource. Deactivating a user deletes it, like deleting it
+// through the Users store does: the user is soft-deleted and can no longer be found. A resource
+// without the active attribute leaves the user active.
+func (h *userHandler) update(w http.ResponseWriter, r *http.Request, user *types.User, res *userResource) {
+	ctx := r.Context()
+
+	if res.Active.isFalse() {
+		current, err := h.toResource(ctx, user)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if err := database.Users(h.db).Delete(ctx, user.ID); err != nil {
+			writeError(w, err)
+			return
+		}
+		current.Active = newScimBool(false)
+		writeJSON(w, http.StatusOK, current)
+		return
+	}
+
+	var update database.UserUpdate
+	if res.UserName != "" {
+		username, err := auth.NormalizeUsername(res.UserName)
+		if err != nil {
+			writeError(w, errInvalidValue(err.Error()))
+			return
+		}
+		if username != user.Username {
+			// The names of users and organizations share a namespace.
+			if _, err := database.Users(h.db).GetByUsername(ctx, username); !errcode.IsNotFound(err) {
+				writeError(w, errUsernameInUse(err))
+				return
+			}
+			if _, err := database.Orgs(h.db).GetByName(ctx, username); !errcode.IsNotFound(err) {
+				writeError(w, errUsernameInUse(err))
+				return
+			}
+			update.Username = username
+		}
+	}
+	if displayName := res.displayName(); displayName != user.DisplayName {
+		update.DisplayName = &displayName
+	}
+	if update.Username != "" || update.DisplayName != nil {
+		if err := database.Users(h.db).Update(ctx, user.ID, update); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+
+	if email := res.primaryEmail(); email != "" {
+		if err := h.setPrimaryEmail(ctx, user.ID, email); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+
+	user, err := database.Users(h.db).GetByID(ctx, user.ID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	updated, err := h.toResource(ctx, user)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// errUsernameInUse returns the error of a name lookup that found the username in use.
+func errUsernameInUse(err error) error {
+	if err != nil {
+		return err
+	}
+	return &scimError{status: http.StatusConflict, scimType: "uniqueness", detail: "Username is already in use."}
+}
+
+// setPrimaryEmail makes the email the verified primary email of the user, adding it if needed.
+func (h *userHandler) setPrimaryEmail(ctx context.Context, userID int32, email string) error {
+	current, _, err := database.UserEmails(h.db).GetPrimaryEmail(ctx, userID)
+	if err != nil && !errcode.IsNotFound(err) {
+		return err
+	}
+	if strings.EqualFold(current, email) {
+		return nil
+	}
+
+	if other, err := database.Users(h.db).GetByVerifiedEmail(ctx, email); err == nil && other.ID != userID {
+		return &scimError{status: http.StatusConflict, scimType: "uniqueness", detail: "Email is already in use."}
+	} else if err != nil && !errcode.IsNotFound(err) {
+		return err
+	}
+
+	if _, _, err := database.UserEmails(h.db).Get(ctx, userID, email); errcode.IsNotFound(err) {
+		if err := database.UserEmails(h.db).Add(ctx, userID, email, nil); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+	// 🚨 SECURITY: The identity provider is trusted to have verified the email address.
+	if err := database.UserEmails(h.db).SetVerified(ctx, userID, email, true); err != nil {
+		return err
+	}
+	return database.UserEmails(h.db).SetPrimaryEmail(ctx, userID, email)
+}
+
+func (h *userHandler) delete(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getUser(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := database.Users(h.db).Delete(r.Context(), user.ID); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *userHandler) toResource(ctx context.Context, user *types.User) (*userResource, error) {
+	res := &userResource{
+		Schemas:     []string{schemaUser},
+		ID:          strconv.Itoa(int(user.ID)),
+		UserName:    user.Username,
+		DisplayName: user.DisplayName,
+		Active:      newScimBool(true),
+		Meta: &meta{
+			ResourceType: "User",
+			Created:      user.CreatedAt,
+			LastModified: user.UpdatedAt,
+			Location:     PathPrefix + "/Users/" + strconv.Itoa(int(user.ID)),
+		},
+	}
+	email, _, err := database.UserEmails(h.db).GetPrimaryEmail(ctx, user.ID)
+	if err != nil && !errcode.IsNotFound(err) {
+		return nil, err
+	}
+	if email != "" {
+		res.Emails = []userEmail{{Value: email, Type: "work", Primary: true}}
+	}
+	return res, nil
+}
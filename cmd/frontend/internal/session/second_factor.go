@@ -0,0 +1,47 @@
+package session
+
+import (
+	"net/http"
+	"time"
+)
+
+// secondFactorExpiry is how long users have to complete a second factor challenge.
+const secondFactorExpiry = 5 * time.Minute
+
+// SecondFactorState is the state of an ongoing second factor (security key) ceremony, stored in the
+// session. It is never sent to the client, as the session data is kept server-side.
+type SecondFactorState struct {
+	// UserID is the user who is signing in or registering a security key.
+	UserID int32
+	// SignInPending is true if the user provided their first factor (e.g. their password) but has
+	// not yet been signed in, because they still need to provide their second factor.
+	SignInPending bool
+	// RegistrationRequired is true if the pending sign-in can only be completed by registering a
+	// security key, because the site requires one and the user has none yet.
+	RegistrationRequired bool
+	// Challenge is the WebAuthn challenge the security key must sign, if one was issued.
+	Challenge []byte
+	ExpiresAt time.Time
+}
+
+// SetSecondFactorState stores the second factor state in the session, or removes it if state ==
+// nil. The state expires 5 minutes after it was first stored, updating it does not extend it.
+func SetSecondFactorState(w http.ResponseWriter, r *http.Request, state *SecondFactorState) error {
+	if state != nil && state.ExpiresAt.IsZero() {
+		state.ExpiresAt = time.Now().Add(secondFactorExpiry)
+	}
+	return SetData(w, r, "secondFactor", state)
+}
+
+// GetSecondFactorState returns the second factor state stored in the session, or nil if there is
+// none or it has expired.
+func GetSecondFactorState(r *http.Request) (*SecondFactorState, error) {
+	var state *SecondFactorState
+	if err := GetData(r, "secondFactor", &state); err != nil {
+		return nil, err
+	}
+	if state == nil || time.Now().After(state.ExpiresAt) {
+		return nil, nil
+	}
+	return state, nil
+}
@@ -413,6 +413,8 @@ func authenticateByCookie(r *http.Request, w http.ResponseWriter) context.Contex
 			}
 		}
 
+		database.RecordUserActivity(info.Actor.UID, database.UserActivityKindWeb)
+
 		info.Actor.FromSessionCookie = true
 		return actor.WithActor(r.Context(), info.Actor)
 	}
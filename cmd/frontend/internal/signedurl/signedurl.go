@@ -0,0 +1,170 @@
+// Package signedurl implements short-lived signed URLs for raw file and archive downloads
+// (/-/raw/ URLs). A signed URL authenticates the request as the user who minted it without a
+// session cookie or access token, which is useful for CI jobs and curl.
+//
+// A signed URL is only valid for a single repository, revision, and path prefix (its scope), and
+// only until it expires.
+package signedurl
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/inconshreveable/log15"
+
+	uirouter "github.com/sourcegraph/sourcegraph/cmd/frontend/internal/app/ui/router"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/routevar"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/urlsign"
+)
+
+var signingKey = env.Get("SRC_SIGNED_URL_KEY", "", "secret key used for signing raw file download URLs (signed URLs are disabled if empty)")
+
+var signer = urlsign.Signer{
+	Purpose: "raw",
+	Key:     func() string { return signingKey },
+}
+
+// MaxExpiry is the maximum duration for which a signed URL is valid.
+const MaxExpiry = 24 * time.Hour
+
+// Query parameters of a signed URL.
+const (
+	paramExpires   = "sg_expires"
+	paramUserID    = "sg_user"
+	paramScope     = "sg_scope"
+	paramSignature = "sg_signature"
+)
+
+var (
+	// ErrInvalidSignature is returned by Verify if the signature of a signed URL is invalid or
+	// the URL is outside of the signed scope.
+	ErrInvalidSignature = urlsign.ErrInvalidSignature
+
+	// ErrExpired is returned by Verify if a signed URL has expired.
+	ErrExpired = urlsign.ErrExpired
+)
+
+// Enabled reports whether signed URLs are enabled, which requires a signing key to be configured.
+func Enabled() bool {
+	return signer.Enabled()
+}
+
+// Scope is the set of raw files and archives that a signed URL grants access to.
+type Scope struct {
+	Repo api.RepoName
+	Rev  string // the revision as given in the URL (empty for the default branch)
+
+	// PathPrefix is the path (relative to the repository root) of the file or directory that can be
+	// downloaded. An empty prefix grants access to the whole repository.
+	PathPrefix string
+}
+
+// Sign returns the query parameters that authenticate requests for raw files within the scope as
+// the given user, until expiresAt.
+func Sign(userID int32, scope Scope, expiresAt time.Time) url.Values {
+	uid := strconv.FormatInt(int64(userID), 10)
+	pathPrefix := cleanPath(scope.PathPrefix)
+	expires, signature := signer.Sign(expiresAt, string(scope.Repo), scope.Rev, pathPrefix, uid)
+	return url.Values{
+		paramExpires:   []string{expires},
+		paramUserID:    []string{uid},
+		paramScope:     []string{pathPrefix},
+		paramSignature: []string{signature},
+	}
+}
+
+// URL returns the signed URL of a raw file or archive within the scope, relative to the external URL.
+func URL(userID int32, scope Scope, expiresAt time.Time) string {
+	rev := ""
+	if scope.Rev != "" {
+		rev = "@" + scope.Rev
+	}
+	return "/" + string(scope.Repo) + rev + "/-/raw/" + cleanPath(scope.PathPrefix) + "?" + Sign(userID, scope, expiresAt).Encode()
+}
+
+// IsSigned reports whether the query contains the parameters of a signed URL.
+func IsSigned(query url.Values) bool {
+	return query.Get(paramSignature) != ""
+}
+
+// Verify checks that the query parameters of a signed URL are valid for a request of the file or
+// directory at filePath in the given repository and revision, and returns the ID of the user who
+// minted the signed URL.
+//
+// 🚨 SECURITY: The caller must only use the returned user ID to authenticate requests for raw
+// files of the given repository, revision and path.
+func Verify(query url.Values, repo api.RepoName, rev, filePath string, now time.Time) (userID int32, err error) {
+	uid, pathPrefix := query.Get(paramUserID), query.Get(paramScope)
+	if err := signer.Verify(query.Get(paramExpires), query.Get(paramSignature), now, string(repo), rev, pathPrefix, uid); err != nil {
+		return 0, err
+	}
+	if !hasPathPrefix(cleanPath(filePath), pathPrefix) {
+		return 0, ErrInvalidSignature
+	}
+
+	id, err := strconv.ParseInt(uid, 10, 32)
+	if err != nil {
+		return 0, ErrInvalidSignature
+	}
+	return int32(id), nil
+}
+
+// Middleware authenticates requests for raw files (/-/raw/ URLs) that carry a valid signature
+// as the user who minted the signed URL. Requests for other routes are passed through
+// unchanged.
+//
+// 🚨 SECURITY: Requests with an invalid or expired signature are rejected, so that a signed URL
+// that is no longer valid does not silently fall back to other means of authentication.
+func Middleware(db dbutil.DB, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if !IsSigned(query) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var m mux.RouteMatch
+		if uirouter.Router == nil || !uirouter.Router.Match(r, &m) || m.Route == nil || m.Route.GetName() != uirouter.RouteRaw {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rr := routevar.ToRepoRev(m.Vars)
+		userID, err := Verify(query, rr.Repo, rr.Rev, m.Vars["Path"], timeNow())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		// 🚨 SECURITY: Ensure that the user was not deleted since the URL was signed.
+		if _, err := database.Users(db).GetByID(r.Context(), userID); err != nil {
+			log15.Error("Invalid user of signed URL.", "userID", userID, "err", err)
+			http.Error(w, "Invalid signed URL.", http.StatusUnauthorized)
+			return
+		}
+
+		r = r.WithContext(actor.WithActor(r.Context(), &actor.Actor{UID: userID}))
+		next.ServeHTTP(w, r)
+	})
+}
+
+var timeNow = time.Now
+
+func cleanPath(p string) string {
+	return strings.Trim(path.Clean("/"+p), "/")
+}
+
+// hasPathPrefix reports whether p is the path prefix or a descendant of it.
+func hasPathPrefix(p, prefix string) bool {
+	return prefix == "" || p == prefix || strings.HasPrefix(p, prefix+"/")
+}
@@ -0,0 +1,133 @@
+package signedurl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	uirouter "github.com/sourcegraph/sourcegraph/cmd/frontend/internal/app/ui/router"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/routevar"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func mockSigningKey(t *testing.T) {
+	t.Helper()
+	old := signingKey
+	signingKey = "test-key"
+	t.Cleanup(func() { signingKey = old })
+}
+
+func TestVerify(t *testing.T) {
+	mockSigningKey(t)
+
+	now := time.Now()
+	scope := Scope{Repo: "github.com/foo/bar", Rev: "main", PathPrefix: "/docs/"}
+	query := Sign(1, scope, now.Add(time.Minute))
+
+	tests := []struct {
+		name     string
+		query    url.Values
+		repo     string
+		rev      string
+		filePath string
+		now      time.Time
+		wantErr  error
+	}{
+		{name: "file in scope", query: query, repo: "github.com/foo/bar", rev: "main", filePath: "/docs/README.md", now: now},
+		{name: "scope directory", query: query, repo: "github.com/foo/bar", rev: "main", filePath: "/docs", now: now},
+		{name: "sibling with same prefix", query: query, repo: "github.com/foo/bar", rev: "main", filePath: "/docsx", now: now, wantErr: ErrInvalidSignature},
+		{name: "path traversal", query: query, repo: "github.com/foo/bar", rev: "main", filePath: "/docs/../secret", now: now, wantErr: ErrInvalidSignature},
+		{name: "other repo", query: query, repo: "github.com/foo/baz", rev: "main", filePath: "/docs/README.md", now: now, wantErr: ErrInvalidSignature},
+		{name: "other rev", query: query, repo: "github.com/foo/bar", rev: "", filePath: "/docs/README.md", now: now, wantErr: ErrInvalidSignature},
+		{name: "expired", query: query, repo: "github.com/foo/bar", rev: "main", filePath: "/docs/README.md", now: now.Add(time.Minute), wantErr: ErrExpired},
+		{
+			name: "widened scope",
+			query: func() url.Values {
+				q := Sign(1, scope, now.Add(time.Minute))
+				q.Set(paramScope, "")
+				return q
+			}(),
+			repo: "github.com/foo/bar", rev: "main", filePath: "/secret", now: now, wantErr: ErrInvalidSignature,
+		},
+		{
+			name: "other user",
+			query: func() url.Values {
+				q := Sign(1, scope, now.Add(time.Minute))
+				q.Set(paramUserID, "2")
+				return q
+			}(),
+			repo: "github.com/foo/bar", rev: "main", filePath: "/docs/README.md", now: now, wantErr: ErrInvalidSignature,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			userID, err := Verify(test.query, api.RepoName(test.repo), test.rev, test.filePath, test.now)
+			if err != test.wantErr {
+				t.Fatalf("got error %v, want %v", err, test.wantErr)
+			}
+			if err == nil && userID != 1 {
+				t.Errorf("got user ID %d, want 1", userID)
+			}
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		signingKey = ""
+		if _, err := Verify(query, "github.com/foo/bar", "main", "/docs/README.md", now); err != ErrInvalidSignature {
+			t.Errorf("got error %v, want %v", err, ErrInvalidSignature)
+		}
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	mockSigningKey(t)
+
+	oldRouter := uirouter.Router
+	t.Cleanup(func() { uirouter.Router = oldRouter })
+	uirouter.Router = mux.NewRouter()
+	repoRev := uirouter.Router.PathPrefix("/" + routevar.Repo + routevar.RepoRevSuffix + "/" + routevar.RepoPathDelim).Subrouter()
+	repoRev.Path("/raw{Path:.*}").Methods("GET", "HEAD").Name(uirouter.RouteRaw)
+	repoRev.Path("/blob{Path:.*}").Methods("GET").Name("blob")
+
+	database.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id}, nil
+	}
+	t.Cleanup(func() { database.Mocks.Users.GetByID = nil })
+
+	h := Middleware(nil, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a := actor.FromContext(r.Context()); a.IsAuthenticated() {
+			w.Header().Set("X-User", a.UIDString())
+		}
+	}))
+	serve := func(u string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", u, nil))
+		return rec
+	}
+
+	scope := Scope{Repo: "github.com/foo/bar", Rev: "main", PathPrefix: "docs"}
+	signed := URL(1, scope, time.Now().Add(time.Minute))
+
+	if rec := serve(signed); rec.Code != http.StatusOK || rec.Header().Get("X-User") != "1" {
+		t.Errorf("signed URL: got status %d and user %q, want authenticated request", rec.Code, rec.Header().Get("X-User"))
+	}
+
+	expired := URL(1, scope, time.Now().Add(-time.Minute))
+	if rec := serve(expired); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expired URL: got status %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+
+	// Signatures are only accepted on raw URLs.
+	blob := "/github.com/foo/bar@main/-/blob/docs?" + Sign(1, scope, time.Now().Add(time.Minute)).Encode()
+	if rec := serve(blob); rec.Code != http.StatusOK || rec.Header().Get("X-User") != "" {
+		t.Errorf("blob URL: got status %d and user %q, want unauthenticated request", rec.Code, rec.Header().Get("X-User"))
+	}
+}
@@ -178,5 +178,5 @@ func LogEvent(ctx context.Context, db dbutil.DB, name, url string, userID int32,
 		Argument:        argument,
 		FeatureFlags:    featureFlags,
 	}
-	return database.EventLogs(db).Insert(ctx, info)
+	return database.EventLogs(db).InsertAsync(ctx, info)
 }
@@ -0,0 +1,358 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+	gh "github.com/google/go-github/v28/github"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	gitlabwebhooks "github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab/webhooks"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// maxLoggedResponseSize is the maximum number of bytes of a handler's response that
+// are stored in the webhook log.
+const maxLoggedResponseSize = 64 * 1024
+
+// maxLoggedRequestSize is the maximum number of bytes of a webhook's body that are
+// stored in the webhook log. Larger bodies are truncated, so such webhooks can't be
+// replayed successfully.
+const maxLoggedRequestSize = 1024 * 1024
+
+// redactedHeaders are the request headers that carry a webhook secret as is. They
+// are not stored in the webhook log, and restored from the configuration of the
+// external service when a webhook is replayed.
+var redactedHeaders = []string{
+	gitlabwebhooks.TokenHeaderName,
+	"Authorization",
+}
+
+// DefaultRouter is the router serving the webhook endpoints of the frontend. It is
+// set when the HTTP API handler is created.
+var DefaultRouter *Router
+
+// Router is the single receiver of the webhooks of all code hosts. It identifies
+// the external service a webhook is for from the externalServiceID query parameter,
+// verifies the signature of the webhook with the secrets stored in the configuration
+// of the external service, records the webhook in the webhook log and dispatches it
+// to the handler registered for the kind of the external service.
+type Router struct {
+	ExternalServices *database.ExternalServiceStore
+	Logs             *database.WebhookLogStore
+
+	mu       sync.RWMutex
+	handlers map[string]http.Handler
+}
+
+// Register registers the handler of the webhooks of external services of the given
+// kind, e.g. extsvc.KindGitHub, replacing any previously registered handler.
+// Handlers receive the request with its body and query parameters intact, so they
+// can verify it themselves.
+func (rt *Router) Register(kind string, handler http.Handler) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	if rt.handlers == nil {
+		rt.handlers = make(map[string]http.Handler)
+	}
+	rt.handlers[kind] = handler
+}
+
+func (rt *Router) handler(kind string) http.Handler {
+	rt.mu.RLock()
+	defer rt.mu.RUnlock()
+	return rt.handlers[kind]
+}
+
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "reading body", http.StatusBadRequest)
+		return
+	}
+
+	rawID := r.URL.Query().Get(extsvc.IDParam)
+	if rawID == "" {
+		http.Error(w, "missing "+extsvc.IDParam+" query parameter", http.StatusBadRequest)
+		return
+	}
+	id, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil {
+		http.Error(w, "invalid "+extsvc.IDParam+" query parameter", http.StatusBadRequest)
+		return
+	}
+	svc, ok := rt.verify(w, r, id, body)
+	if !ok {
+		return
+	}
+
+	rt.dispatch(w, r, svc.Kind, svc.ID, body, 0, false)
+}
+
+// verify looks up the external service with the given ID and verifies the signature
+// of the webhook with its secrets. If either fails, it writes an error response and
+// returns false.
+func (rt *Router) verify(w http.ResponseWriter, r *http.Request, id int64, body []byte) (*types.ExternalService, bool) {
+	svc, err := rt.ExternalServices.GetByID(r.Context(), id)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			http.Error(w, "external service not found", http.StatusNotFound)
+			return nil, false
+		}
+		log15.Error("Getting external service of webhook", "id", id, "error", err)
+		http.Error(w, "getting external service", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	// 🚨 SECURITY: Only webhooks signed with one of the secrets of the external
+	// service are logged and dispatched, so that unauthenticated requests can't fill
+	// the webhook log.
+	if err := verifySignature(svc, r.Header, body); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return nil, false
+	}
+	return svc, true
+}
+
+// LegacyHandler returns the handler of the webhook endpoint of a single kind of
+// external service, e.g. /.api/github-webhooks, which code hosts configured before
+// the router was introduced still send webhooks to. Requests identifying their
+// external service are verified like the ones to the router. Other requests are
+// dispatched to the handler of the kind as is, which verifies them, and are only
+// logged if it handled them successfully.
+func (rt *Router) LegacyHandler(kind string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "reading body", http.StatusBadRequest)
+			return
+		}
+
+		rawID := r.URL.Query().Get(extsvc.IDParam)
+		if rawID == "" {
+			rt.dispatch(w, r, kind, 0, body, 0, true)
+			return
+		}
+		id, err := strconv.ParseInt(rawID, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid "+extsvc.IDParam+" query parameter", http.StatusBadRequest)
+			return
+		}
+		svc, ok := rt.verify(w, r, id, body)
+		if !ok {
+			return
+		}
+		if svc.Kind != kind {
+			http.Error(w, "external service is not a "+strings.ToLower(kind)+" external service", http.StatusBadRequest)
+			return
+		}
+		rt.dispatch(w, r, kind, svc.ID, body, 0, false)
+	})
+}
+
+// Replay dispatches the logged webhook with the given ID again and returns the log
+// of the replayed webhook.
+func (rt *Router) Replay(ctx context.Context, id int64) (*database.WebhookLog, error) {
+	logged, err := rt.Logs.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := http.NewRequestWithContext(ctx, logged.Request.Method, logged.Request.URL, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+	r.Header = logged.Request.Header.Clone()
+	if r.Header == nil {
+		r.Header = make(http.Header)
+	}
+	if logged.ExternalServiceID != 0 {
+		svc, err := rt.ExternalServices.GetByID(ctx, logged.ExternalServiceID)
+		if err != nil {
+			return nil, errors.Wrap(err, "getting external service")
+		}
+		if err := restoreSecret(svc, r.Header); err != nil {
+			return nil, err
+		}
+	}
+
+	replayed := rt.dispatch(httptest.NewRecorder(), r, logged.Kind, logged.ExternalServiceID, logged.Request.Body, logged.ID, false)
+	if replayed == nil {
+		return nil, errors.New("the replayed webhook was rejected or couldn't be logged")
+	}
+	return replayed, nil
+}
+
+// dispatch passes the request to the handler registered for the kind and logs it
+// with the handler's response. Requests the handler rejects as unauthorized are
+// never logged, and if onlyLogSuccess is set, neither are requests it answers with
+// any other error. It returns the log, or nil if the request wasn't logged.
+func (rt *Router) dispatch(w http.ResponseWriter, r *http.Request, kind string, externalServiceID int64, body []byte, replayOf int64, onlyLogSuccess bool) *database.WebhookLog {
+	handler := rt.handler(kind)
+	if handler == nil {
+		http.Error(w, "no webhook handler registered for "+strings.ToLower(kind), http.StatusNotFound)
+		return nil
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	rec := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+	handler.ServeHTTP(rec, r)
+
+	if rec.statusCode == http.StatusUnauthorized || (onlyLogSuccess && rec.statusCode >= 400) {
+		return nil
+	}
+
+	loggedBody := body
+	if len(loggedBody) > maxLoggedRequestSize {
+		loggedBody = loggedBody[:maxLoggedRequestSize]
+	}
+
+	header := r.Header.Clone()
+	for _, name := range redactedHeaders {
+		header.Del(name)
+	}
+	logged := &database.WebhookLog{
+		ExternalServiceID: externalServiceID,
+		Kind:              kind,
+		StatusCode:        rec.statusCode,
+		Request: database.WebhookLogMessage{
+			Method: r.Method,
+			URL:    r.URL.String(),
+			Header: header,
+			Body:   loggedBody,
+		},
+		Response: database.WebhookLogMessage{
+			Header: rec.Header().Clone(),
+			Body:   rec.body.Bytes(),
+		},
+		ReplayOf: replayOf,
+	}
+	if err := rt.Logs.Create(r.Context(), logged); err != nil {
+		log15.Error("Logging webhook", "kind", kind, "externalServiceID", externalServiceID, "error", err)
+		return nil
+	}
+	return logged
+}
+
+// verifySignature verifies that the webhook was sent by the code host of the
+// external service, using the webhook secrets stored in its configuration.
+func verifySignature(svc *types.ExternalService, header http.Header, body []byte) error {
+	c, err := svc.Configuration()
+	if err != nil {
+		return errors.Wrap(err, "parsing external service configuration")
+	}
+
+	var secrets []string
+	switch c := c.(type) {
+	case *schema.GitHubConnection:
+		for _, hook := range c.Webhooks {
+			secrets = append(secrets, hook.Secret)
+		}
+		return verifyHMAC(header.Get("X-Hub-Signature"), body, secrets)
+	case *schema.BitbucketServerConnection:
+		return verifyHMAC(header.Get("X-Hub-Signature"), body, []string{c.WebhookSecret()})
+	case *schema.BitbucketCloudConnection:
+		return verifyHMAC(header.Get("X-Hub-Signature"), body, []string{c.WebhookSecret})
+	case *schema.GitLabConnection:
+		for _, hook := range c.Webhooks {
+			secrets = append(secrets, hook.Secret)
+		}
+		return verifyToken(header.Get(gitlabwebhooks.TokenHeaderName), secrets)
+	case *schema.AzureDevOpsConnection:
+		_, password, _ := (&http.Request{Header: header}).BasicAuth()
+		return verifyToken(password, []string{c.WebhookSecret})
+	default:
+		return errors.Errorf("webhooks are not supported for %s external services", strings.ToLower(svc.Kind))
+	}
+}
+
+func verifyHMAC(signature string, body []byte, secrets []string) error {
+	if signature == "" {
+		return errors.New("missing signature")
+	}
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+		if err := gh.ValidateSignature(signature, body, []byte(secret)); err == nil {
+			return nil
+		}
+	}
+	return errors.New("signature doesn't match any webhook secret")
+}
+
+func verifyToken(token string, secrets []string) error {
+	if token == "" {
+		return errors.New("missing secret")
+	}
+	for _, secret := range secrets {
+		if secret != "" && subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1 {
+			return nil
+		}
+	}
+	return errors.New("secret doesn't match any webhook secret")
+}
+
+// restoreSecret restores the redacted header carrying the webhook secret of the
+// external service, if its code host sends one, so that the handler can verify a
+// replayed webhook. Signatures are stored, since they don't reveal the secret.
+func restoreSecret(svc *types.ExternalService, header http.Header) error {
+	c, err := svc.Configuration()
+	if err != nil {
+		return errors.Wrap(err, "parsing external service configuration")
+	}
+	switch c := c.(type) {
+	case *schema.GitLabConnection:
+		for _, hook := range c.Webhooks {
+			if hook.Secret != "" {
+				header.Set(gitlabwebhooks.TokenHeaderName, hook.Secret)
+				break
+			}
+		}
+	case *schema.AzureDevOpsConnection:
+		r := &http.Request{Header: header}
+		r.SetBasicAuth("sourcegraph", c.WebhookSecret)
+	}
+	return nil
+}
+
+// responseRecorder records the status code and the beginning of the body of a
+// response while writing it.
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(statusCode int) {
+	if !r.wroteHeader {
+		r.statusCode = statusCode
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	r.wroteHeader = true
+	if remaining := maxLoggedResponseSize - r.body.Len(); remaining > 0 {
+		if len(p) > remaining {
+			r.body.Write(p[:remaining])
+		} else {
+			r.body.Write(p)
+		}
+	}
+	return r.ResponseWriter.Write(p)
+}
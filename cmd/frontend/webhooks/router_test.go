@@ -0,0 +1,232 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	gitlabwebhooks "github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab/webhooks"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(`{"action":"opened"}`)
+
+	for _, tc := range []struct {
+		name    string
+		svc     *types.ExternalService
+		header  http.Header
+		wantErr bool
+	}{
+		{
+			name:   "github signed with one of the secrets",
+			svc:    &types.ExternalService{Kind: extsvc.KindGitHub, Config: `{"webhooks": [{"org": "a", "secret": "s1"}, {"org": "b", "secret": "s2"}]}`},
+			header: http.Header{"X-Hub-Signature": {sign(t, body, []byte("s2"))}},
+		},
+		{
+			name:    "github signed with another secret",
+			svc:     &types.ExternalService{Kind: extsvc.KindGitHub, Config: `{"webhooks": [{"org": "a", "secret": "s1"}]}`},
+			header:  http.Header{"X-Hub-Signature": {sign(t, body, []byte("other"))}},
+			wantErr: true,
+		},
+		{
+			name:    "github without secrets",
+			svc:     &types.ExternalService{Kind: extsvc.KindGitHub, Config: `{}`},
+			header:  http.Header{"X-Hub-Signature": {sign(t, body, []byte(""))}},
+			wantErr: true,
+		},
+		{
+			name:   "bitbucket cloud",
+			svc:    &types.ExternalService{Kind: extsvc.KindBitbucketCloud, Config: `{"webhookSecret": "s1"}`},
+			header: http.Header{"X-Hub-Signature": {sign(t, body, []byte("s1"))}},
+		},
+		{
+			name:   "gitlab token",
+			svc:    &types.ExternalService{Kind: extsvc.KindGitLab, Config: `{"webhooks": [{"secret": "s1"}]}`},
+			header: http.Header{gitlabwebhooks.TokenHeaderName: {"s1"}},
+		},
+		{
+			name:    "gitlab without token",
+			svc:     &types.ExternalService{Kind: extsvc.KindGitLab, Config: `{"webhooks": [{"secret": "s1"}]}`},
+			header:  http.Header{},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported kind",
+			svc:     &types.ExternalService{Kind: extsvc.KindGitolite, Config: `{}`},
+			header:  http.Header{},
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := verifySignature(tc.svc, tc.header, body)
+			if tc.wantErr && err == nil {
+				t.Error("expected error")
+			} else if !tc.wantErr && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func TestRestoreSecret(t *testing.T) {
+	svc := &types.ExternalService{Kind: extsvc.KindAzureDevOps, Config: `{"webhookSecret": "s1"}`}
+	header := http.Header{}
+	if err := restoreSecret(svc, header); err != nil {
+		t.Fatal(err)
+	}
+	if err := verifySignature(svc, header, nil); err != nil {
+		t.Errorf("expected restored secret to be valid: %s", err)
+	}
+}
+
+func TestRouter(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtest.NewDB(t, *dsn)
+	ctx := context.Background()
+
+	svc := &types.ExternalService{
+		Kind:        extsvc.KindGitLab,
+		DisplayName: "GitLab",
+		Config:      `{"url": "https://gitlab.com", "token": "abc", "projectQuery": ["none"], "webhooks": [{"secret": "s1"}]}`,
+	}
+	if err := database.ExternalServices(db).Upsert(ctx, svc); err != nil {
+		t.Fatal(err)
+	}
+
+	var received []string
+	rt := &Router{ExternalServices: database.ExternalServices(db), Logs: database.WebhookLogs(db)}
+	rt.Register(extsvc.KindGitLab, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(gitlabwebhooks.TokenHeaderName) != "s1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, string(body))
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = w.Write([]byte("handled"))
+	}))
+
+	send := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/.api/webhooks?"+extsvc.IDParam+"="+strconv.FormatInt(svc.ID, 10), strings.NewReader("payload"))
+		req.Header.Set(gitlabwebhooks.TokenHeaderName, token)
+		rec := httptest.NewRecorder()
+		rt.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := send("wrong"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("unexpected status code for unsigned webhook: %d", rec.Code)
+	}
+	if rec := send("s1"); rec.Code != http.StatusTeapot {
+		t.Fatalf("unexpected status code: %d", rec.Code)
+	}
+
+	logs, err := rt.Logs.List(ctx, database.WebhookLogsListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 1 {
+		t.Fatalf("expected only the verified webhook to be logged, have %d logs", len(logs))
+	}
+	logged := logs[0]
+	if logged.ExternalServiceID != svc.ID || logged.StatusCode != http.StatusTeapot || string(logged.Request.Body) != "payload" || string(logged.Response.Body) != "handled" {
+		t.Errorf("unexpected log: %+v", logged)
+	}
+	if logged.Request.Header.Get(gitlabwebhooks.TokenHeaderName) != "" {
+		t.Error("expected secret header to be redacted")
+	}
+
+	replayed, err := rt.Replay(ctx, logged.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayed.ReplayOf != logged.ID {
+		t.Errorf("unexpected ReplayOf: have %d, want %d", replayed.ReplayOf, logged.ID)
+	}
+	if len(received) != 2 || received[1] != "payload" {
+		t.Errorf("expected webhook to be dispatched again, have %v", received)
+	}
+}
+
+func TestRouter_LegacyHandler(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtest.NewDB(t, *dsn)
+	ctx := context.Background()
+
+	svc := &types.ExternalService{
+		Kind:        extsvc.KindGitHub,
+		DisplayName: "GitHub",
+		Config:      `{"url": "https://github.com", "token": "abc", "repositoryQuery": ["none"], "webhooks": [{"org": "a", "secret": "s1"}]}`,
+	}
+	if err := database.ExternalServices(db).Upsert(ctx, svc); err != nil {
+		t.Fatal(err)
+	}
+
+	rt := &Router{ExternalServices: database.ExternalServices(db), Logs: database.WebhookLogs(db)}
+	// Like the GitHub webhook handler, this answers unverified webhooks with an
+	// internal server error rather than 401.
+	rt.Register(extsvc.KindGitHub, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("X-Hub-Signature") != sign(t, body, []byte("s1")) {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	handler := rt.LegacyHandler(extsvc.KindGitHub)
+
+	send := func(query, secret string) *httptest.ResponseRecorder {
+		body := []byte(`{"action":"opened"}`)
+		req := httptest.NewRequest("POST", "/.api/github-webhooks"+query, strings.NewReader(string(body)))
+		req.Header.Set("X-Hub-Signature", sign(t, body, []byte(secret)))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	withID := "?" + extsvc.IDParam + "=" + strconv.FormatInt(svc.ID, 10)
+
+	if rec := send(withID, "wrong"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("unexpected status code for badly signed webhook: %d", rec.Code)
+	}
+	if rec := send("", "wrong"); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("unexpected status code for badly signed webhook without external service: %d", rec.Code)
+	}
+	if rec := send("?"+extsvc.IDParam+"=abc", "s1"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("unexpected status code for invalid external service ID: %d", rec.Code)
+	}
+
+	logs, err := rt.Logs.List(ctx, database.WebhookLogsListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 0 {
+		t.Fatalf("expected rejected webhooks not to be logged, have %d logs", len(logs))
+	}
+
+	if rec := send(withID, "s1"); rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status code: %d", rec.Code)
+	}
+	if rec := send("", "s1"); rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status code for webhook without external service: %d", rec.Code)
+	}
+
+	logs, err = rt.Logs.List(ctx, database.WebhookLogsListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 2 {
+		t.Fatalf("expected the verified webhooks to be logged, have %d logs", len(logs))
+	}
+}
@@ -116,7 +116,7 @@ func main() {
 					return "", err
 				}
 
-				return repos.CloneURL(svc.Kind, svc.Config, r)
+				return repos.CloneURL(ctx, svc.Kind, svc.Config, r)
 			}
 			return "", errors.Errorf("no sources for %q", repo)
 		},
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+// handleCommitGraph returns the commits added to a repository since a watermark. Callers that keep
+// a copy of the commit graph, such as code intelligence, use it to only fetch the new commits
+// instead of the whole graph on every update.
+func (s *Server) handleCommitGraph(w http.ResponseWriter, r *http.Request) {
+	var req protocol.CommitGraphRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	for _, commits := range [][]api.CommitID{req.Tips, req.Watermark} {
+		for _, commit := range commits {
+			// The commits are passed to git log on stdin, where they could otherwise be options.
+			if !isAbsoluteRevision(string(commit)) {
+				http.Error(w, fmt.Sprintf("invalid commit %q", commit), http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	req.Repo = protocol.NormalizeRepo(req.Repo)
+	dir := s.dir(req.Repo)
+	if !repoCloned(dir) {
+		http.Error(w, "repository not cloned", http.StatusNotFound)
+		return
+	}
+
+	commits, err := commitGraphSince(r.Context(), dir, req.Tips, req.Watermark)
+	if err != nil {
+		log15.Error("handleCommitGraph: git log", "repo", req.Repo, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(protocol.CommitGraphResponse{Commits: commits}); err != nil {
+		log15.Error("handleCommitGraph: sending response", "error", err)
+	}
+}
+
+// commitGraphSince returns the commits reachable from tips but not from watermark, children
+// first. Commits of either set that don't exist in the repository are ignored, so a watermark
+// commit that was garbage collected after a force push only makes the result larger.
+func commitGraphSince(ctx context.Context, dir GitDir, tips, watermark []api.CommitID) ([]protocol.CommitParents, error) {
+	commits := []protocol.CommitParents{}
+	if len(tips) == 0 {
+		return commits, nil
+	}
+
+	var stdin strings.Builder
+	for _, commit := range tips {
+		fmt.Fprintf(&stdin, "%s\n", commit)
+	}
+	for _, commit := range watermark {
+		fmt.Fprintf(&stdin, "^%s\n", commit)
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "log", "--pretty=format:%H %P", "--topo-order", "--ignore-missing", "--stdin")
+	dir.Set(cmd)
+	cmd.Stdin = strings.NewReader(stdin.String())
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "git log: %s", bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		commit := protocol.CommitParents{Commit: api.CommitID(fields[0]), Parents: []api.CommitID{}}
+		for _, parent := range fields[1:] {
+			commit.Parents = append(commit.Parents, api.CommitID(parent))
+		}
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
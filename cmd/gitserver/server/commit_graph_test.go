@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+func TestCommitGraphSince(t *testing.T) {
+	dir := t.TempDir()
+	gitDir := GitDir(filepath.Join(dir, ".git"))
+
+	cmd := func(name string, arg ...string) api.CommitID {
+		t.Helper()
+		return api.CommitID(strings.TrimSpace(runCmd(t, dir, name, arg...)))
+	}
+	commit := func(message string) api.CommitID {
+		t.Helper()
+		cmd("git", "commit", "--allow-empty", "-m", message)
+		return cmd("git", "rev-parse", "HEAD")
+	}
+
+	cmd("git", "init", ".")
+	a := commit("a")
+	b := commit("b")
+	watermark := []api.CommitID{b}
+
+	cmd("git", "checkout", "-b", "feature")
+	c := commit("c")
+	cmd("git", "checkout", "-")
+	d := commit("d")
+	cmd("git", "merge", "--no-ff", "-m", "e", "feature")
+	e := cmd("git", "rev-parse", "HEAD")
+
+	commits, err := commitGraphSince(context.Background(), gitDir, []api.CommitID{e}, watermark)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parents := map[api.CommitID][]api.CommitID{}
+	for _, commit := range commits {
+		for _, parent := range commit.Parents {
+			if _, ok := parents[parent]; ok {
+				t.Errorf("parent %s listed before its child %s", parent, commit.Commit)
+			}
+		}
+		parents[commit.Commit] = commit.Parents
+	}
+	want := map[api.CommitID][]api.CommitID{
+		c: {b},
+		d: {b},
+		e: {d, c},
+	}
+	if diff := cmp.Diff(want, parents); diff != "" {
+		t.Errorf("unexpected commits (-want +got):\n%s", diff)
+	}
+
+	// Missing watermark commits are ignored, so the whole history is returned.
+	commits, err = commitGraphSince(context.Background(), gitDir, []api.CommitID{b}, []api.CommitID{"0000000000000000000000000000000000000000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]protocol.CommitParents{{Commit: b, Parents: []api.CommitID{a}}, {Commit: a, Parents: []api.CommitID{}}}, commits); diff != "" {
+		t.Errorf("unexpected commits (-want +got):\n%s", diff)
+	}
+}
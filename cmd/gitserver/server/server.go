@@ -353,6 +353,7 @@ func (s *Server) Handler() http.Handler {
 	mux.HandleFunc("/repo-update", s.handleRepoUpdate)
 	mux.HandleFunc("/getGitolitePhabricatorMetadata", s.handleGetGitolitePhabricatorMetadata)
 	mux.HandleFunc("/create-commit-from-patch", s.handleCreateCommitFromPatch)
+	mux.HandleFunc("/commit-graph", s.handleCommitGraph)
 	mux.HandleFunc("/ping", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
@@ -877,7 +878,7 @@ func (s *Server) handleArchive(w http.ResponseWriter, r *http.Request) {
 		treeish = q.Get("treeish")
 		repo    = q.Get("repo")
 		format  = q.Get("format")
-		paths   = q["path"]
+		opts    = gitserver.ArchiveOptions{Paths: q["path"]}
 	)
 
 	if err := checkSpecArgSafety(treeish); err != nil {
@@ -892,6 +893,12 @@ func (s *Server) handleArchive(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := opts.ParseArchiveFilters(q); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		log15.Error("gitserver.archive.ParseArchiveFilters", "error", err)
+		return
+	}
+
 	req := &protocol.ExecRequest{
 		Repo: api.RepoName(repo),
 		Args: []string{
@@ -916,7 +923,7 @@ func (s *Server) handleArchive(w http.ResponseWriter, r *http.Request) {
 	}
 
 	req.Args = append(req.Args, treeish, "--")
-	req.Args = append(req.Args, paths...)
+	req.Args = append(req.Args, opts.Pathspecs()...)
 
 	s.exec(w, r, req)
 }
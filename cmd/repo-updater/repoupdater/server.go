@@ -63,6 +63,7 @@ func (s *Server) Handler() http.Handler {
 	mux.HandleFunc("/repo-lookup", s.handleRepoLookup)
 	mux.HandleFunc("/enqueue-repo-update", s.handleEnqueueRepoUpdate)
 	mux.HandleFunc("/sync-external-service", s.handleExternalServiceSync)
+	mux.HandleFunc("/sync-external-service-repo", s.handleExternalServiceRepoSync)
 	mux.HandleFunc("/enqueue-changeset-sync", s.handleEnqueueChangesetSync)
 	mux.HandleFunc("/schedule-perms-sync", s.handleSchedulePermsSync)
 	return mux
@@ -248,6 +249,30 @@ func (s *Server) handleExternalServiceSync(w http.ResponseWriter, r *http.Reques
 	})
 }
 
+func (s *Server) handleExternalServiceRepoSync(w http.ResponseWriter, r *http.Request) {
+	var req protocol.ExternalServiceRepoSyncRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond(w, http.StatusBadRequest, err)
+		return
+	}
+	if req.ExternalServiceID == 0 {
+		respond(w, http.StatusBadRequest, errors.New("no external service id provided"))
+		return
+	}
+	if req.Repo == "" {
+		respond(w, http.StatusBadRequest, errors.New("no repo provided"))
+		return
+	}
+
+	err := s.Syncer.TriggerExternalServiceRepoSync(r.Context(), req.ExternalServiceID, req.Repo)
+	if err != nil {
+		log15.Warn("Enqueueing external service repo sync job", "error", err, "id", req.ExternalServiceID, "repo", req.Repo)
+		respond(w, http.StatusInternalServerError, protocol.ExternalServiceRepoSyncResponse{Error: err.Error()})
+		return
+	}
+	respond(w, http.StatusOK, nil)
+}
+
 func externalServiceValidate(ctx context.Context, req protocol.ExternalServiceSyncRequest, src repos.Source) error {
 	if !req.ExternalService.DeletedAt.IsZero() {
 		// We don't need to check deleted services.
@@ -787,6 +787,49 @@ func TestServer_handleExternalServiceSync(t *testing.T) {
 	}
 }
 
+func TestServer_handleExternalServiceRepoSync(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		wantStatusCode int
+		wantBody       string
+	}{
+		{
+			name:           "bad JSON",
+			body:           "{",
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       "unexpected EOF",
+		},
+		{
+			name:           "missing external service id",
+			body:           `{"Repo": "sourcegraph/sourcegraph"}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       "no external service id provided",
+		},
+		{
+			name:           "missing repo",
+			body:           `{"ExternalServiceID": 1}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       "no repo provided",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/sync-external-service-repo", strings.NewReader(test.body))
+			w := httptest.NewRecorder()
+
+			s := &Server{Syncer: &repos.Syncer{}}
+			s.handleExternalServiceRepoSync(w, r)
+
+			if w.Code != test.wantStatusCode {
+				t.Fatalf("Code: want %v but got %v", test.wantStatusCode, w.Code)
+			} else if diff := cmp.Diff(test.wantBody, w.Body.String()); diff != "" {
+				t.Fatalf("Body mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestExternalServiceValidate_ValidatesToken(t *testing.T) {
 	var (
 		src    repos.Source
@@ -161,7 +161,7 @@ func Main(enterpriseInit EnterpriseInit) {
 		SourcegraphDotComMode: envvar.SourcegraphDotComMode(),
 	}
 
-	rateLimitSyncer := repos.NewRateLimitSyncer(ratelimit.DefaultRegistry, store.ExternalServiceStore)
+	rateLimitSyncer := repos.NewRateLimitSyncer(ratelimit.DefaultRegistry, ratelimit.DefaultConcurrencyRegistry, store.ExternalServiceStore)
 	server.RateLimitSyncer = rateLimitSyncer
 	// Attempt to perform an initial sync with all external services
 	if err := rateLimitSyncer.SyncRateLimiters(ctx); err != nil {
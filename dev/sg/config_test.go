@@ -26,6 +26,7 @@ checks:
   docker:
     cmd: docker version
     failMessage: "Failed to run 'docker version'. Please make sure Docker is running."
+    fix: open -a Docker
 
 commandsets:
   oss:
@@ -72,6 +73,7 @@ commandsets:
 				Name:        "docker",
 				Cmd:         "docker version",
 				FailMessage: "Failed to run 'docker version'. Please make sure Docker is running.",
+				Fix:         "open -a Docker",
 			},
 		},
 	}
@@ -14,6 +14,7 @@ require (
 	github.com/peterbourgon/ff/v3 v3.0.0
 	github.com/rjeczalik/notify v0.9.3-0.20210809113154-3472d85e95cd
 	github.com/slack-go/slack v0.9.5
+	github.com/sourcegraph/sourcegraph/enterprise/dev/ci/images v0.0.0-20211020041242-9f6088e5b163
 	github.com/sourcegraph/sourcegraph/lib v0.0.0-20210906140940-dd601b549e29
 	golang.org/x/mod v0.4.2
 	golang.org/x/oauth2 v0.0.0-20210628180205-a41e5a781914
@@ -69,3 +70,5 @@ require (
 )
 
 replace github.com/sourcegraph/sourcegraph/lib => ./../../lib
+
+replace github.com/sourcegraph/sourcegraph/enterprise/dev/ci/images => ./../../enterprise/dev/ci/images
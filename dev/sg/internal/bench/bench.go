@@ -0,0 +1,76 @@
+// Package bench parses the output of Go benchmarks and compares two runs of
+// them, in the spirit of golang.org/x/perf/cmd/benchstat.
+package bench
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Key identifies the measurements of one unit of a benchmark, e.g. the ns/op
+// of BenchmarkSearch-8 in a given package.
+type Key struct {
+	Pkg  string
+	Name string
+	Unit string
+}
+
+// Set holds the measurements of benchmarks, in the order they were first
+// encountered.
+type Set struct {
+	keys   []Key
+	values map[Key][]float64
+}
+
+// Keys returns the keys of the set, in the order they were first encountered.
+func (s *Set) Keys() []Key { return s.keys }
+
+// Values returns the measurements of the given key.
+func (s *Set) Values(k Key) []float64 { return s.values[k] }
+
+func (s *Set) add(k Key, v float64) {
+	if s.values == nil {
+		s.values = map[Key][]float64{}
+	}
+	if _, ok := s.values[k]; !ok {
+		s.keys = append(s.keys, k)
+	}
+	s.values[k] = append(s.values[k], v)
+}
+
+// Parse parses the output of `go test -bench`, ignoring the lines which are
+// not benchmark results.
+func Parse(r io.Reader) (*Set, error) {
+	s := &Set{}
+	pkg := ""
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "pkg: ") {
+			pkg = strings.TrimSpace(strings.TrimPrefix(line, "pkg: "))
+			continue
+		}
+
+		// A result line looks like:
+		//
+		//   BenchmarkSearch-8   1000   1234 ns/op   56 B/op   2 allocs/op
+		fields := strings.Fields(line)
+		if len(fields) < 4 || len(fields)%2 != 0 || !strings.HasPrefix(fields[0], "Benchmark") {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[1]); err != nil {
+			continue
+		}
+		for i := 2; i < len(fields); i += 2 {
+			v, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				break
+			}
+			s.add(Key{Pkg: pkg, Name: fields[0], Unit: fields[i+1]}, v)
+		}
+	}
+	return s, scanner.Err()
+}
@@ -0,0 +1,102 @@
+package bench
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const baseOutput = `goos: linux
+goarch: amd64
+pkg: github.com/sourcegraph/sourcegraph/internal/search
+cpu: Intel(R) Xeon(R) CPU @ 2.20GHz
+BenchmarkSearch-8   	    1000	      1000 ns/op	     512 B/op	       4 allocs/op
+BenchmarkSearch-8   	    1000	      1010 ns/op	     512 B/op	       4 allocs/op
+BenchmarkSearch-8   	    1000	       990 ns/op	     512 B/op	       4 allocs/op
+BenchmarkSearch-8   	    1000	      1005 ns/op	     512 B/op	       4 allocs/op
+BenchmarkSearch-8   	    1000	       995 ns/op	     512 B/op	       4 allocs/op
+BenchmarkSearch-8   	    1000	      1000 ns/op	     512 B/op	       4 allocs/op
+BenchmarkRemoved-8  	    1000	      1000 ns/op
+PASS
+ok  	github.com/sourcegraph/sourcegraph/internal/search	12.345s
+`
+
+const headOutput = `pkg: github.com/sourcegraph/sourcegraph/internal/search
+BenchmarkSearch-8   	    2000	       500 ns/op	     512 B/op	       4 allocs/op
+BenchmarkSearch-8   	    2000	       505 ns/op	     512 B/op	       4 allocs/op
+BenchmarkSearch-8   	    2000	       495 ns/op	     512 B/op	       4 allocs/op
+BenchmarkSearch-8   	    2000	       502 ns/op	     512 B/op	       4 allocs/op
+BenchmarkSearch-8   	    2000	       498 ns/op	     512 B/op	       4 allocs/op
+BenchmarkSearch-8   	    2000	       500 ns/op	     512 B/op	       4 allocs/op
+PASS
+`
+
+func TestParse(t *testing.T) {
+	set, err := Parse(strings.NewReader(baseOutput))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkg := "github.com/sourcegraph/sourcegraph/internal/search"
+	wantKeys := []Key{
+		{Pkg: pkg, Name: "BenchmarkSearch-8", Unit: "ns/op"},
+		{Pkg: pkg, Name: "BenchmarkSearch-8", Unit: "B/op"},
+		{Pkg: pkg, Name: "BenchmarkSearch-8", Unit: "allocs/op"},
+		{Pkg: pkg, Name: "BenchmarkRemoved-8", Unit: "ns/op"},
+	}
+	if diff := cmp.Diff(wantKeys, set.Keys()); diff != "" {
+		t.Fatalf("wrong keys (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]float64{1000, 1010, 990, 1005, 995, 1000}, set.Values(wantKeys[0])); diff != "" {
+		t.Fatalf("wrong values (-want +got):\n%s", diff)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	// The outlier 5000 is excluded.
+	s := Summarize([]float64{500, 505, 495, 502, 498, 5000})
+	if s.N != 6 || s.Mean != 500 || math.Abs(s.Variation-0.01) > 1e-9 {
+		t.Fatalf("wrong summary: %+v", s)
+	}
+}
+
+func TestMannWhitneyUTest(t *testing.T) {
+	x := []float64{1, 2, 3, 4, 5, 6}
+	if p := MannWhitneyUTest(x, []float64{7, 8, 9, 10, 11, 12}); p >= DefaultAlpha {
+		t.Errorf("want significant p-value for disjoint samples, got %f", p)
+	}
+	if p := MannWhitneyUTest(x, []float64{1, 2, 3, 4, 5, 6}); p < 0.9 {
+		t.Errorf("want p-value close to 1 for identical samples, got %f", p)
+	}
+	if p := MannWhitneyUTest([]float64{4, 4, 4}, []float64{4, 4, 4}); p != 1 {
+		t.Errorf("want p-value 1 for constant samples, got %f", p)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	base, err := Parse(strings.NewReader(baseOutput))
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := Parse(strings.NewReader(headOutput))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, "main", "HEAD", Compare(base, head, DefaultAlpha)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "| Benchmark | main | HEAD | Delta |\n" +
+		"|---|---:|---:|---|\n" +
+		"| `search` `Search-8` ns/op | 1µs ±1% | 500ns ±1% | -50.00% (p=0.005 n=6+6) |\n" +
+		"| `search` `Search-8` B/op | 512B ±0% | 512B ±0% | ~ (p=1.000 n=6+6) |\n" +
+		"| `search` `Search-8` allocs/op | 4 ±0% | 4 ±0% | ~ (p=1.000 n=6+6) |\n"
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Fatalf("wrong markdown (-want +got):\n%s", diff)
+	}
+}
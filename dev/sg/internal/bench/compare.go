@@ -0,0 +1,124 @@
+package bench
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// DefaultAlpha is the significance level under which a difference between two
+// runs is reported, the same as benchstat's.
+const DefaultAlpha = 0.05
+
+// Comparison is the comparison of the measurements of a benchmark in two runs.
+type Comparison struct {
+	Key
+	Base, Head Summary
+	// Delta is the change from the base mean to the head mean, as a fraction
+	// of the base mean.
+	Delta float64
+	// P is the p-value of the difference between the runs.
+	P float64
+	// Significant is whether the difference is significant.
+	Significant bool
+}
+
+// Compare compares the benchmarks of base and head, in the order they were
+// run at head. Benchmarks which only exist in one of the runs are omitted.
+func Compare(base, head *Set, alpha float64) []Comparison {
+	var cmps []Comparison
+	for _, k := range head.Keys() {
+		baseValues := base.Values(k)
+		if len(baseValues) == 0 {
+			continue
+		}
+		headValues := head.Values(k)
+
+		c := Comparison{
+			Key:  k,
+			Base: Summarize(baseValues),
+			Head: Summarize(headValues),
+			P:    MannWhitneyUTest(baseValues, headValues),
+		}
+		if c.Base.Mean != 0 {
+			c.Delta = (c.Head.Mean - c.Base.Mean) / c.Base.Mean
+		}
+		c.Significant = c.P < alpha
+		cmps = append(cmps, c)
+	}
+	return cmps
+}
+
+// WriteMarkdown writes the comparisons as a markdown table, titled with the
+// names of the compared revisions.
+func WriteMarkdown(w io.Writer, baseName, headName string, cmps []Comparison) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "| Benchmark | %s | %s | Delta |\n", baseName, headName)
+	b.WriteString("|---|---:|---:|---|\n")
+	for _, c := range cmps {
+		delta := "~"
+		if c.Significant {
+			delta = fmt.Sprintf("%+.2f%%", c.Delta*100)
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s (p=%.3f n=%d+%d) |\n",
+			benchmarkName(c.Key),
+			formatSummary(c.Base, c.Unit),
+			formatSummary(c.Head, c.Unit),
+			delta, c.P, c.Base.N, c.Head.N,
+		)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// benchmarkName returns the name of the benchmark without its "Benchmark"
+// prefix, qualified with its unit and the last element of its package.
+func benchmarkName(k Key) string {
+	name := "`" + strings.TrimPrefix(k.Name, "Benchmark") + "`"
+	if k.Pkg != "" {
+		name = "`" + k.Pkg[strings.LastIndex(k.Pkg, "/")+1:] + "` " + name
+	}
+	return name + " " + k.Unit
+}
+
+func formatSummary(s Summary, unit string) string {
+	return fmt.Sprintf("%s ±%.0f%%", formatValue(s.Mean, unit), s.Variation*100)
+}
+
+// formatValue formats a measurement with a scale suited to its unit.
+func formatValue(v float64, unit string) string {
+	switch unit {
+	case "ns/op":
+		for _, s := range []struct {
+			name string
+			ns   float64
+		}{{"s", 1e9}, {"ms", 1e6}, {"µs", 1e3}} {
+			if math.Abs(v) >= s.ns {
+				return fmt.Sprintf("%.3g%s", v/s.ns, s.name)
+			}
+		}
+		return fmt.Sprintf("%.3gns", v)
+
+	case "B/op":
+		for _, s := range []struct {
+			name  string
+			bytes float64
+		}{{"GiB", 1 << 30}, {"MiB", 1 << 20}, {"KiB", 1 << 10}} {
+			if math.Abs(v) >= s.bytes {
+				return fmt.Sprintf("%.3g%s", v/s.bytes, s.name)
+			}
+		}
+		return fmt.Sprintf("%.0fB", v)
+	}
+
+	for _, s := range []struct {
+		name   string
+		factor float64
+	}{{"G", 1e9}, {"M", 1e6}, {"k", 1e3}} {
+		if math.Abs(v) >= s.factor {
+			return fmt.Sprintf("%.3g%s", v/s.factor, s.name)
+		}
+	}
+	return fmt.Sprintf("%.3g", v)
+}
@@ -0,0 +1,115 @@
+package bench
+
+import (
+	"math"
+	"sort"
+)
+
+// Summary summarizes the measurements of a benchmark.
+type Summary struct {
+	// N is the number of measurements, outliers included.
+	N int
+	// Mean is the mean of the measurements, outliers excluded.
+	Mean float64
+	// Variation is the largest distance of a measurement to the mean, as a
+	// fraction of the mean, outliers excluded.
+	Variation float64
+}
+
+// Summarize summarizes the given measurements, excluding the outliers as
+// benchstat does: values further than 1.5 times the interquartile range from
+// the first and third quartiles.
+func Summarize(values []float64) Summary {
+	s := Summary{N: len(values)}
+	if len(values) == 0 {
+		return s
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	q1, q3 := quantile(sorted, 0.25), quantile(sorted, 0.75)
+	lo, hi := q1-1.5*(q3-q1), q3+1.5*(q3-q1)
+
+	var kept []float64
+	for _, v := range sorted {
+		if v >= lo && v <= hi {
+			kept = append(kept, v)
+		}
+	}
+
+	var sum float64
+	for _, v := range kept {
+		sum += v
+	}
+	s.Mean = sum / float64(len(kept))
+	if s.Mean != 0 {
+		s.Variation = math.Max(kept[len(kept)-1]-s.Mean, s.Mean-kept[0]) / s.Mean
+	}
+	return s
+}
+
+// quantile returns the q-quantile of the sorted values, interpolating
+// linearly between the closest ranks.
+func quantile(sorted []float64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	i := int(pos)
+	if i+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[i] + (pos-float64(i))*(sorted[i+1]-sorted[i])
+}
+
+// MannWhitneyUTest returns the two-sided p-value of the Mann-Whitney U test of
+// the null hypothesis that x and y come from the same distribution. It uses
+// the normal approximation, corrected for ties and continuity.
+func MannWhitneyUTest(x, y []float64) float64 {
+	n1, n2 := float64(len(x)), float64(len(y))
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+
+	type sample struct {
+		v     float64
+		fromX bool
+	}
+	all := make([]sample, 0, len(x)+len(y))
+	for _, v := range x {
+		all = append(all, sample{v: v, fromX: true})
+	}
+	for _, v := range y {
+		all = append(all, sample{v: v})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].v < all[j].v })
+
+	// Rank the samples, giving tied values the average of their ranks.
+	var rankSumX, tieCorrection float64
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j].v == all[i].v {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if all[k].fromX {
+				rankSumX += rank
+			}
+		}
+		t := float64(j - i)
+		tieCorrection += t*t*t - t
+		i = j
+	}
+
+	n := n1 + n2
+	u := rankSumX - n1*(n1+1)/2
+	mean := n1 * n2 / 2
+	variance := n1 * n2 / 12 * ((n + 1) - tieCorrection/(n*(n-1)))
+	if variance <= 0 {
+		return 1
+	}
+
+	z := (math.Abs(u-mean) - 0.5) / math.Sqrt(variance)
+	if z < 0 {
+		return 1
+	}
+	return math.Erfc(z / math.Sqrt2)
+}
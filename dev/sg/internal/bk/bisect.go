@@ -0,0 +1,125 @@
+package bk
+
+import (
+	"context"
+	"strings"
+
+	"github.com/buildkite/go-buildkite/v3/buildkite"
+	"github.com/cockroachdb/errors"
+)
+
+// ErrStepPassing is returned by Bisect when the step passes in the most recent build in
+// which it finished, i.e. there is nothing to bisect.
+var ErrStepPassing = errors.New("step passes in the most recent build")
+
+// ListBuilds returns up to limit of the most recent builds of the given branch, newest
+// first.
+func (c *Client) ListBuilds(ctx context.Context, pipeline, branch string, limit int) ([]buildkite.Build, error) {
+	perPage := 100
+	if limit < perPage {
+		perPage = limit
+	}
+	opts := &buildkite.BuildsListOptions{
+		Branch:      branch,
+		ListOptions: buildkite.ListOptions{Page: 1, PerPage: perPage},
+	}
+
+	var builds []buildkite.Build
+	for len(builds) < limit {
+		page, resp, err := c.bk.Builds.ListByPipeline(buildkiteOrg, pipeline, opts)
+		if err != nil {
+			return nil, err
+		}
+		builds = append(builds, page...)
+		if resp == nil || resp.NextPage == 0 || len(page) == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	if len(builds) > limit {
+		builds = builds[:limit]
+	}
+	return builds, nil
+}
+
+// BisectResult describes where a step started failing.
+type BisectResult struct {
+	// FirstFailed is the oldest build of the most recent streak of builds in which the
+	// step failed.
+	FirstFailed *buildkite.Build
+	// LastPassed is the build right before that streak in which the step passed. It is
+	// nil if the step failed in all the given builds.
+	LastPassed *buildkite.Build
+	// Failed are the builds of the streak, newest first.
+	Failed []*buildkite.Build
+}
+
+// Bisect finds the build in which the step matching stepQuery started failing, given
+// builds ordered newest first as returned by ListBuilds. Like ExportLogs, stepQuery
+// matches jobs by their step key or by part of their name.
+//
+// Builds in which the step did not finish, e.g. because it was skipped or canceled, are
+// ignored.
+func Bisect(builds []buildkite.Build, stepQuery string) (*BisectResult, error) {
+	var (
+		result BisectResult
+		found  bool
+	)
+	for i := range builds {
+		build := &builds[i]
+		state, ok := stepState(build, stepQuery)
+		if !ok {
+			continue
+		}
+		found = true
+
+		if state == "passed" {
+			if len(result.Failed) == 0 {
+				return nil, ErrStepPassing
+			}
+			result.LastPassed = build
+			break
+		}
+		result.Failed = append(result.Failed, build)
+		result.FirstFailed = build
+	}
+
+	if !found {
+		return nil, errors.Newf("no finished job matching %q found in the last %d builds", stepQuery, len(builds))
+	}
+	return &result, nil
+}
+
+// stepState returns "failed" if any job of the build matching stepQuery failed, and
+// "passed" if they all passed. It returns false if no matching job finished.
+func stepState(build *buildkite.Build, stepQuery string) (string, bool) {
+	query := strings.ToLower(stepQuery)
+
+	var passed, failed bool
+	for _, j := range build.Jobs {
+		keyMatch := j.StepKey != nil && *j.StepKey == stepQuery
+		nameMatch := j.Name != nil && strings.Contains(strings.ToLower(*j.Name), query)
+		if !(keyMatch || nameMatch) || j.State == nil {
+			continue
+		}
+
+		switch *j.State {
+		case "passed":
+			passed = true
+		case "failed":
+			if j.SoftFailed {
+				passed = true
+			} else {
+				failed = true
+			}
+		}
+	}
+
+	switch {
+	case failed:
+		return "failed", true
+	case passed:
+		return "passed", true
+	}
+	return "", false
+}
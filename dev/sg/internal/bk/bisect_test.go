@@ -0,0 +1,84 @@
+package bk
+
+import (
+	"testing"
+
+	"github.com/buildkite/go-buildkite/v3/buildkite"
+	"github.com/cockroachdb/errors"
+)
+
+func TestBisect(t *testing.T) {
+	job := func(name, state string) *buildkite.Job {
+		return &buildkite.Job{Name: &name, State: &state}
+	}
+	build := func(number int, jobs ...*buildkite.Job) buildkite.Build {
+		return buildkite.Build{Number: &number, Jobs: jobs}
+	}
+	numbers := func(builds []*buildkite.Build) []int {
+		var ns []int
+		for _, b := range builds {
+			ns = append(ns, *b.Number)
+		}
+		return ns
+	}
+
+	builds := []buildkite.Build{
+		build(10, job(":go: Test", "failed"), job(":eslint: Lint", "passed")),
+		build(9, job(":go: Test", "canceled")),
+		build(8, job(":go: Test", "failed")),
+		build(7, job(":eslint: Lint", "passed")),
+		build(6, job(":go: Test", "passed"), job(":eslint: Lint", "failed")),
+		build(5, job(":go: Test", "failed")),
+	}
+
+	t.Run("failing step", func(t *testing.T) {
+		result, err := Bisect(builds, "go: test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have, want := *result.FirstFailed.Number, 8; have != want {
+			t.Errorf("wrong first failed build: have %d, want %d", have, want)
+		}
+		if result.LastPassed == nil || *result.LastPassed.Number != 6 {
+			t.Errorf("wrong last passed build: have %v, want 6", result.LastPassed)
+		}
+		if have, want := numbers(result.Failed), []int{10, 8}; len(have) != len(want) || have[0] != want[0] || have[1] != want[1] {
+			t.Errorf("wrong failed builds: have %v, want %v", have, want)
+		}
+	})
+
+	t.Run("passing step", func(t *testing.T) {
+		if _, err := Bisect(builds, "lint"); !errors.Is(err, ErrStepPassing) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("no passing build", func(t *testing.T) {
+		result, err := Bisect(builds[:3], "test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.LastPassed != nil {
+			t.Errorf("unexpected last passed build %d", *result.LastPassed.Number)
+		}
+		if have, want := *result.FirstFailed.Number, 8; have != want {
+			t.Errorf("wrong first failed build: have %d, want %d", have, want)
+		}
+	})
+
+	t.Run("unknown step", func(t *testing.T) {
+		if _, err := Bisect(builds, "docker"); err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("soft failures pass", func(t *testing.T) {
+		state := "failed"
+		soft := build(11, &buildkite.Job{Name: stringPtr("Test"), State: &state, SoftFailed: true})
+		if _, err := Bisect(append([]buildkite.Build{soft}, builds...), "test"); !errors.Is(err, ErrStepPassing) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func stringPtr(s string) *string { return &s }
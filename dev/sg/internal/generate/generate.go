@@ -0,0 +1,245 @@
+// Package generate runs the code generators of the repository in dependency
+// order, skipping the ones whose inputs did not change since they last ran.
+package generate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Target is a code generator.
+type Target struct {
+	// Name identifies the target on the command line.
+	Name string
+	// Help describes what the target generates.
+	Help string
+	// Deps are the names of the targets that must run before this one, because
+	// their output is an input of this one.
+	Deps []string
+	// Inputs are git pathspecs, relative to the repository root, of the files
+	// the output of the target depends on, e.g. ":(glob)schema/*.json". The
+	// generated files should be included too, so that they are generated again
+	// when they are edited by hand.
+	Inputs []string
+	// Cmd is the bash command that runs the generator in the repository root.
+	Cmd string
+	// Explicit targets only run when they are named on the command line, e.g.
+	// because they are slow or need network access.
+	Explicit bool
+}
+
+// Plan returns the targets to run to generate the named targets, in dependency
+// order. If no names are given, all targets that are not explicit are planned.
+func Plan(targets []Target, names []string) ([]Target, error) {
+	byName := make(map[string]Target, len(targets))
+	for _, t := range targets {
+		byName[t.Name] = t
+	}
+
+	if len(names) == 0 {
+		for _, t := range targets {
+			if !t.Explicit {
+				names = append(names, t.Name)
+			}
+		}
+	}
+
+	var (
+		plan    []Target
+		done    = map[string]bool{}
+		visited = map[string]bool{}
+		visit   func(name string, path []string) error
+	)
+	visit = func(name string, path []string) error {
+		if done[name] {
+			return nil
+		}
+		if visited[name] {
+			return errors.Errorf("dependency cycle: %s", strings.Join(append(path, name), " -> "))
+		}
+		t, ok := byName[name]
+		if !ok {
+			if len(path) > 0 {
+				return errors.Errorf("target %q depends on unknown target %q", path[len(path)-1], name)
+			}
+			return errors.Errorf("unknown target %q", name)
+		}
+		visited[name] = true
+		for _, dep := range t.Deps {
+			if err := visit(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+		done[name] = true
+		plan = append(plan, t)
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return plan, nil
+}
+
+// Hash returns a hash of the command and of the inputs of the target, as they
+// are in the working tree of the repository. Untracked files that are not
+// ignored are included.
+func Hash(ctx context.Context, repoRoot string, t Target) (string, error) {
+	h := sha256.New()
+	io.WriteString(h, t.Cmd)
+	h.Write([]byte{0})
+	if len(t.Inputs) == 0 {
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+
+	args := append([]string{"ls-files", "-z", "--cached", "--others", "--exclude-standard", "--"}, t.Inputs...)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = repoRoot
+	out, err := cmd.Output()
+	if err != nil {
+		return "", errors.Wrapf(err, "listing inputs of %s", t.Name)
+	}
+
+	files := strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00")
+	sort.Strings(files)
+	for i, file := range files {
+		// Files are listed once per stage when there are merge conflicts.
+		if file == "" || (i > 0 && files[i-1] == file) {
+			continue
+		}
+		io.WriteString(h, file)
+		h.Write([]byte{0})
+		if err := hashFile(h, filepath.Join(repoRoot, file)); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashFile(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		// Deleted files are still listed until the deletion is staged.
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// Cache records the hash of the inputs of the targets when they were last
+// generated.
+type Cache struct {
+	path   string
+	Hashes map[string]string `json:"hashes"`
+}
+
+// LoadCache loads the cache stored at the given path. A missing cache is
+// empty.
+func LoadCache(path string) (*Cache, error) {
+	c := &Cache{path: path, Hashes: map[string]string{}}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", path)
+	}
+	if c.Hashes == nil {
+		c.Hashes = map[string]string{}
+	}
+	return c, nil
+}
+
+// UpToDate returns whether the target was last generated from inputs with the
+// given hash.
+func (c *Cache) UpToDate(target, hash string) bool {
+	return c.Hashes[target] == hash
+}
+
+// Set records that the target was generated from inputs with the given hash.
+func (c *Cache) Set(target, hash string) {
+	c.Hashes[target] = hash
+}
+
+// Save writes the cache to the path it was loaded from.
+func (c *Cache) Save() error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, data, 0600)
+}
+
+// Snapshot maps the files that differ from HEAD in the working tree of the
+// repository, including untracked files, to a hash of their content.
+type Snapshot map[string]string
+
+// TakeSnapshot takes a snapshot of the working tree of the repository.
+func TakeSnapshot(ctx context.Context, repoRoot string) (Snapshot, error) {
+	cmd := exec.CommandContext(ctx, "git", "status", "--porcelain=v1", "-z", "--untracked-files=all")
+	cmd.Dir = repoRoot
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting git status")
+	}
+
+	s := Snapshot{}
+	entries := strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		// Renames and copies are followed by the original path, which is
+		// unchanged in the working tree.
+		if entry[0] == 'R' || entry[0] == 'C' {
+			i++
+		}
+		file := entry[3:]
+		h := sha256.New()
+		if err := hashFile(h, filepath.Join(repoRoot, file)); err != nil {
+			return nil, err
+		}
+		s[file] = hex.EncodeToString(h.Sum(nil))
+	}
+	return s, nil
+}
+
+// Changed returns the files whose content differs between the snapshots,
+// sorted.
+func Changed(before, after Snapshot) []string {
+	var changed []string
+	for file, hash := range after {
+		if before[file] != hash {
+			changed = append(changed, file)
+		}
+	}
+	for file := range before {
+		if _, ok := after[file]; !ok {
+			changed = append(changed, file)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
@@ -0,0 +1,91 @@
+package generate
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPlan(t *testing.T) {
+	targets := []Target{
+		{Name: "go", Deps: []string{"schema"}},
+		{Name: "schema"},
+		{Name: "docs", Deps: []string{"go", "schema"}},
+		{Name: "slow", Explicit: true},
+	}
+
+	names := func(plan []Target) []string {
+		var names []string
+		for _, t := range plan {
+			names = append(names, t.Name)
+		}
+		return names
+	}
+
+	for _, tc := range []struct {
+		name  string
+		names []string
+		want  []string
+	}{
+		{name: "all", want: []string{"schema", "go", "docs"}},
+		{name: "with dependencies", names: []string{"go"}, want: []string{"schema", "go"}},
+		{name: "explicit", names: []string{"slow", "schema"}, want: []string{"slow", "schema"}},
+		{name: "deduplicated", names: []string{"docs", "schema", "go"}, want: []string{"schema", "go", "docs"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			plan, err := Plan(targets, tc.names)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, names(plan)); diff != "" {
+				t.Errorf("unexpected plan (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	if _, err := Plan(targets, []string{"unknown"}); err == nil {
+		t.Error("expected error for unknown target")
+	}
+	cyclic := []Target{{Name: "a", Deps: []string{"b"}}, {Name: "b", Deps: []string{"a"}}}
+	if _, err := Plan(cyclic, nil); err == nil {
+		t.Error("expected error for dependency cycle")
+	}
+}
+
+func TestCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sg", "generate-cache.json")
+
+	cache, err := LoadCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cache.UpToDate("go", "abc") {
+		t.Error("empty cache is up to date")
+	}
+	cache.Set("go", "abc")
+	if err := cache.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	cache, err = LoadCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cache.UpToDate("go", "abc") {
+		t.Error("saved target is not up to date")
+	}
+	if cache.UpToDate("go", "def") {
+		t.Error("target with changed inputs is up to date")
+	}
+}
+
+func TestChanged(t *testing.T) {
+	before := Snapshot{"edited.go": "1", "unchanged.go": "2", "reverted.go": "3"}
+	after := Snapshot{"edited.go": "4", "unchanged.go": "2", "generated.go": "5"}
+
+	want := []string{"edited.go", "generated.go", "reverted.go"}
+	if diff := cmp.Diff(want, Changed(before, after)); diff != "" {
+		t.Errorf("unexpected changed files (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,87 @@
+// Package github provides the few GitHub API operations sg needs.
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/dev/sg/internal/open"
+	"github.com/sourcegraph/sourcegraph/dev/sg/internal/secrets"
+	"github.com/sourcegraph/sourcegraph/lib/output"
+)
+
+const apiURL = "https://api.github.com"
+
+type githubSecrets struct {
+	Token string `json:"token"`
+}
+
+// retrieveToken obtains a token either from the cached configuration or by asking the user for it.
+func retrieveToken(ctx context.Context, out *output.Output) (string, error) {
+	sec := secrets.FromContext(ctx)
+	ghSecrets := githubSecrets{}
+	err := sec.Get("github", &ghSecrets)
+	if errors.Is(err, secrets.ErrSecretNotFound) {
+		out.WriteLine(output.Linef(output.EmojiLightbulb, output.StylePending,
+			"Please create and copy a new token from https://github.com/settings/tokens with the 'repo' scope."))
+		str, err := open.Prompt("Paste your token here:")
+		if err != nil {
+			return "", err
+		}
+		if err := sec.PutAndSave("github", githubSecrets{Token: str}); err != nil {
+			return "", err
+		}
+		return str, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return ghSecrets.Token, nil
+}
+
+// CreateIssue creates an issue in the given repository, e.g. "sourcegraph/sourcegraph",
+// and returns its URL.
+func CreateIssue(ctx context.Context, out *output.Output, repo, title, body string, labels []string) (string, error) {
+	token, err := retrieveToken(ctx, out)
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(struct {
+		Title  string   `json:"title"`
+		Body   string   `json:"body"`
+		Labels []string `json:"labels,omitempty"`
+	}{title, body, labels})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/repos/%s/issues", apiURL, repo), bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("Authorization", "token "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return "", errors.Newf("creating issue in %s: unexpected status %s", repo, resp.Status)
+	}
+
+	var issue struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&issue); err != nil {
+		return "", errors.Wrap(err, "decoding created issue")
+	}
+	return issue.HTMLURL, nil
+}
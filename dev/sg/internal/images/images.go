@@ -0,0 +1,265 @@
+// Package images builds the Docker images of the services of the repository
+// locally, the same way CI builds candidate images.
+package images
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"gopkg.in/yaml.v2"
+
+	ciimages "github.com/sourcegraph/sourcegraph/enterprise/dev/ci/images"
+)
+
+// Service is a service whose Docker image is published by Sourcegraph.
+type Service struct {
+	// Name is the name of the service, as in images.SourcegraphDockerImages.
+	Name string
+	// Dir is the directory, relative to the repository root, containing the
+	// build.sh script of the image.
+	Dir string
+	// Go is true if the image is built from the Go command in Dir, false if
+	// it is built from a Dockerfile in docker-images.
+	Go bool
+}
+
+// Services returns all services whose image is published by Sourcegraph.
+func Services(repoRoot string) []Service {
+	services := make([]Service, 0, len(ciimages.SourcegraphDockerImages))
+	for _, name := range ciimages.SourcegraphDockerImages {
+		services = append(services, resolve(repoRoot, name))
+	}
+	return services
+}
+
+// Lookup returns the service with the given name.
+func Lookup(repoRoot, name string) (Service, error) {
+	for _, known := range ciimages.SourcegraphDockerImages {
+		if known == name {
+			return resolve(repoRoot, name), nil
+		}
+	}
+	return Service{}, errors.Errorf("unknown service %q", name)
+}
+
+// resolve finds the directory an image is built from, like the candidate image
+// steps of the CI pipeline do: images in docker-images are preferred, then the
+// enterprise command and finally the OSS command.
+func resolve(repoRoot, name string) Service {
+	for _, s := range []Service{
+		{Name: name, Dir: path.Join("docker-images", name)},
+		{Name: name, Dir: path.Join("enterprise/cmd", name), Go: true},
+	} {
+		if _, err := os.Stat(filepath.Join(repoRoot, s.Dir)); err == nil {
+			return s
+		}
+	}
+	return Service{Name: name, Dir: path.Join("cmd", name), Go: true}
+}
+
+// Image returns the name of the local image of the service with the given tag,
+// which is the name CI builds candidate images under.
+func (s Service) Image(tag string) string {
+	return "sourcegraph/" + strings.ReplaceAll(s.Name, "/", "-") + ":" + tag
+}
+
+// PreBuildScript returns the script to run before build.sh, e.g. to build the
+// web app, if the service has one.
+func (s Service) PreBuildScript(repoRoot string) string {
+	script := path.Join(s.Dir, "pre-build.sh")
+	if _, err := os.Stat(filepath.Join(repoRoot, script)); err != nil {
+		return ""
+	}
+	return script
+}
+
+// BuildScript returns the script building the image of the service.
+func (s Service) BuildScript() string {
+	return path.Join(s.Dir, "build.sh")
+}
+
+// ChangedFiles returns the files, relative to the repository root, that differ
+// between the merge base of HEAD and base and the working tree, including
+// untracked files.
+func ChangedFiles(ctx context.Context, repoRoot, base string) ([]string, error) {
+	mergeBase, err := git(ctx, repoRoot, "merge-base", "HEAD", base)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding merge base with %s", base)
+	}
+	diff, err := git(ctx, repoRoot, "diff", "--name-only", strings.TrimSpace(mergeBase))
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := git(ctx, repoRoot, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(diff + "\n" + untracked), nil
+}
+
+// GoPackageDirs returns the directories, relative to the repository root, of
+// the packages of the repository the Go command of the service depends on.
+func GoPackageDirs(ctx context.Context, repoRoot string, s Service) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "go", "list", "-deps", "-f", "{{.Dir}}", "./"+s.Dir)
+	cmd.Dir = repoRoot
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "listing dependencies of %s", s.Dir)
+	}
+
+	var dirs []string
+	for _, dir := range strings.Fields(string(out)) {
+		// Packages outside of the repository, e.g. in the module cache or
+		// the standard library, can't be changed locally.
+		rel, err := filepath.Rel(repoRoot, dir)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		dirs = append(dirs, filepath.ToSlash(rel))
+	}
+	return dirs, nil
+}
+
+// Affected returns whether a change to one of the given files requires the
+// image of the service to be rebuilt. goPackageDirs are the directories of the
+// Go packages the service depends on, if it is a Go service. Services with a
+// pre-build script also bundle the web app, so they are affected by changes to
+// the client code.
+func Affected(s Service, changed, goPackageDirs []string, hasPreBuild bool) bool {
+	pkgDirs := make(map[string]bool, len(goPackageDirs))
+	for _, dir := range goPackageDirs {
+		pkgDirs[dir] = true
+	}
+
+	for _, file := range changed {
+		switch {
+		case strings.HasPrefix(file, s.Dir+"/"):
+			return true
+		case s.Go && (file == "go.mod" || file == "go.sum"):
+			return true
+		case s.Go && strings.HasSuffix(file, ".go") && pkgDirs[path.Dir(file)]:
+			return true
+		case hasPreBuild && (strings.HasPrefix(file, "client/") || file == "package.json" || file == "yarn.lock"):
+			return true
+		}
+	}
+	return false
+}
+
+// WorkingTreeTag returns a tag identifying the state of the working tree of
+// the repository: the images built from the same working tree get the same
+// tag.
+func WorkingTreeTag(ctx context.Context, repoRoot string) (string, error) {
+	head, err := git(ctx, repoRoot, "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	diff, err := git(ctx, repoRoot, "diff", "--binary", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	untracked, err := git(ctx, repoRoot, "ls-files", "-z", "--others", "--exclude-standard")
+	if err != nil {
+		return "", err
+	}
+
+	if diff == "" && untracked == "" {
+		return "dev-" + strings.TrimSpace(head)[:12], nil
+	}
+
+	h := sha256.New()
+	io.WriteString(h, head)
+	io.WriteString(h, diff)
+	files := strings.Split(strings.TrimSuffix(untracked, "\x00"), "\x00")
+	sort.Strings(files)
+	for _, file := range files {
+		io.WriteString(h, file)
+		h.Write([]byte{0})
+		f, err := os.Open(filepath.Join(repoRoot, file))
+		if err != nil {
+			return "", err
+		}
+		_, err = io.Copy(h, f)
+		f.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+	return "dev-" + strings.TrimSpace(head)[:12] + "-" + hex.EncodeToString(h.Sum(nil))[:12], nil
+}
+
+// ComposeOverride returns a docker-compose override file replacing the images
+// of the services of the given docker-compose file that run one of the given
+// Sourcegraph images with the local images. images maps the names of
+// Sourcegraph services to the local image to run instead.
+func ComposeOverride(composeFile []byte, images map[string]string) ([]byte, error) {
+	var compose struct {
+		Version  string `yaml:"version"`
+		Services map[string]struct {
+			Image string `yaml:"image"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(composeFile, &compose); err != nil {
+		return nil, errors.Wrap(err, "parsing docker-compose file")
+	}
+
+	type service struct {
+		Image string `yaml:"image"`
+	}
+	override := struct {
+		Version  string             `yaml:"version,omitempty"`
+		Services map[string]service `yaml:"services"`
+	}{
+		Version:  compose.Version,
+		Services: map[string]service{},
+	}
+	for name, s := range compose.Services {
+		if local, ok := images[imageService(s.Image)]; ok {
+			override.Services[name] = service{Image: local}
+		}
+	}
+	if len(override.Services) == 0 {
+		return nil, errors.New("no service of the docker-compose file runs one of the images")
+	}
+	return yaml.Marshal(override)
+}
+
+// imageService returns the name of the Sourcegraph service of an image
+// reference like index.docker.io/sourcegraph/frontend:3.33.0@sha256:..., or
+// the empty string if it is not a Sourcegraph image.
+func imageService(ref string) string {
+	if i := strings.Index(ref, "@"); i >= 0 {
+		ref = ref[:i]
+	}
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		ref = ref[:i]
+	}
+	parts := strings.Split(ref, "/")
+	if len(parts) < 2 || parts[len(parts)-2] != "sourcegraph" {
+		return ""
+	}
+	return parts[len(parts)-1]
+}
+
+func git(ctx context.Context, repoRoot string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = repoRoot
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", errors.Errorf("'git %s' failed: %s", strings.Join(args, " "), exitErr.Stderr)
+		}
+		return "", err
+	}
+	return string(out), nil
+}
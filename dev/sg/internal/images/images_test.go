@@ -0,0 +1,121 @@
+package images
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gopkg.in/yaml.v2"
+)
+
+func TestResolve(t *testing.T) {
+	repoRoot := t.TempDir()
+	for _, dir := range []string{"docker-images/grafana", "enterprise/cmd/frontend", "cmd/frontend", "cmd/searcher"} {
+		if err := os.MkdirAll(filepath.Join(repoRoot, dir), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for name, want := range map[string]Service{
+		"grafana":  {Name: "grafana", Dir: "docker-images/grafana"},
+		"frontend": {Name: "frontend", Dir: "enterprise/cmd/frontend", Go: true},
+		"searcher": {Name: "searcher", Dir: "cmd/searcher", Go: true},
+	} {
+		if diff := cmp.Diff(want, resolve(repoRoot, name)); diff != "" {
+			t.Errorf("unexpected service %s (-want +got):\n%s", name, diff)
+		}
+	}
+}
+
+func TestAffected(t *testing.T) {
+	frontend := Service{Name: "frontend", Dir: "enterprise/cmd/frontend", Go: true}
+	grafana := Service{Name: "grafana", Dir: "docker-images/grafana"}
+	pkgDirs := []string{"enterprise/cmd/frontend", "internal/database"}
+
+	for _, tc := range []struct {
+		name        string
+		service     Service
+		changed     []string
+		hasPreBuild bool
+		want        bool
+	}{
+		{name: "service dir", service: frontend, changed: []string{"enterprise/cmd/frontend/Dockerfile"}, want: true},
+		{name: "dependency", service: frontend, changed: []string{"internal/database/repos.go"}, want: true},
+		{name: "dependency subpackage", service: frontend, changed: []string{"internal/database/dbtest/dbtest.go"}},
+		{name: "go.mod", service: frontend, changed: []string{"go.mod"}, want: true},
+		{name: "unrelated", service: frontend, changed: []string{"internal/search/zoekt.go", "doc/index.md"}},
+		{name: "client without pre-build", service: frontend, changed: []string{"client/web/src/App.tsx"}},
+		{name: "client with pre-build", service: frontend, changed: []string{"client/web/src/App.tsx"}, hasPreBuild: true, want: true},
+		{name: "docker image", service: grafana, changed: []string{"docker-images/grafana/config/grafana.ini"}, want: true},
+		{name: "docker image ignores go.mod", service: grafana, changed: []string{"go.mod"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var dirs []string
+			if tc.service.Go {
+				dirs = pkgDirs
+			}
+			if got := Affected(tc.service, tc.changed, dirs, tc.hasPreBuild); got != tc.want {
+				t.Errorf("Affected = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestImageService(t *testing.T) {
+	for ref, want := range map[string]string{
+		"index.docker.io/sourcegraph/frontend:3.33.0@sha256:abc": "frontend",
+		"sourcegraph/gitserver":                                  "gitserver",
+		"localhost:5000/sourcegraph/searcher:insiders":           "searcher",
+		"redis:6":               "",
+		"grafana/grafana:8.0.0": "",
+	} {
+		if got := imageService(ref); got != want {
+			t.Errorf("imageService(%q) = %q, want %q", ref, got, want)
+		}
+	}
+}
+
+func TestComposeOverride(t *testing.T) {
+	composeFile := []byte(`version: '2.4'
+services:
+  sourcegraph-frontend-0:
+    image: 'index.docker.io/sourcegraph/frontend:3.33.0@sha256:abc'
+    cpus: 4
+  sourcegraph-frontend-internal:
+    image: 'index.docker.io/sourcegraph/frontend:3.33.0@sha256:abc'
+  gitserver-0:
+    image: 'index.docker.io/sourcegraph/gitserver:3.33.0@sha256:def'
+  redis-cache:
+    image: 'index.docker.io/sourcegraph/redis-cache:3.33.0@sha256:ghi'
+`)
+
+	override, err := ComposeOverride(composeFile, map[string]string{
+		"frontend":  "sourcegraph/frontend:dev-abc",
+		"gitserver": "sourcegraph/gitserver:dev-abc",
+		"searcher":  "sourcegraph/searcher:dev-abc",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]interface{}
+	if err := yaml.Unmarshal(override, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"version": "2.4",
+		"services": map[interface{}]interface{}{
+			"sourcegraph-frontend-0":        map[interface{}]interface{}{"image": "sourcegraph/frontend:dev-abc"},
+			"sourcegraph-frontend-internal": map[interface{}]interface{}{"image": "sourcegraph/frontend:dev-abc"},
+			"gitserver-0":                   map[interface{}]interface{}{"image": "sourcegraph/gitserver:dev-abc"},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected override (-want +got):\n%s", diff)
+	}
+
+	if _, err := ComposeOverride(composeFile, map[string]string{"symbols": "sourcegraph/symbols:dev-abc"}); err == nil {
+		t.Error("expected error when no service runs the images")
+	}
+}
@@ -559,11 +559,15 @@ func fileExists(path string) bool {
 }
 
 func getPostgresDB(database db.Database) (*sql.DB, error) {
+	return openPostgresDB(makePostgresDSN(database))
+}
+
+func openPostgresDB(dsn string) (*sql.DB, error) {
 	once.Do(func() {
 		sql.Register("postgres-proxy", stdlib.GetDefaultDriver())
 	})
 
-	db, err := sql.Open("postgres-proxy", makePostgresDSN(database))
+	db, err := sql.Open("postgres-proxy", dsn)
 	if err != nil {
 		return nil, errors.Wrap(err, "sql.Open")
 	}
@@ -592,6 +596,12 @@ func getMigrate(database db.Database, logger mLogger) (*migrate.Migrate, error)
 		return nil, err
 	}
 
+	return newMigrate(database, db, logger)
+}
+
+// newMigrate returns a migrate.Migrate applying the migrations of the given database to
+// the given Postgres database.
+func newMigrate(database db.Database, db *sql.DB, logger mLogger) (*migrate.Migrate, error) {
 	driver, err := postgres.WithInstance(db, &postgres.Config{
 		MigrationsTable: database.MigrationsTable,
 	})
@@ -390,3 +390,46 @@ func TestGetOprerationsWithTwiceLopsidedMigrations(t *testing.T) {
 		t.Errorf("Expected, but got %+v", diff)
 	}
 }
+
+func TestLastTwoMigrationIndexes(t *testing.T) {
+	names := []string{
+		"1528395834_squashed_migrations.down.sql",
+		"1528395834_squashed_migrations.up.sql",
+		"1528395836_add_column.up.sql",
+		"1528395835_add_table.up.sql",
+		"1528395836_add_column.down.sql",
+		"1528395835_add_table.down.sql",
+		"README.md",
+	}
+	last, previous, ok := lastTwoMigrationIndexes(names)
+	if !ok || last != 1528395836 || previous != 1528395835 {
+		t.Fatalf("unexpected indexes: last=%d previous=%d ok=%v", last, previous, ok)
+	}
+
+	last, previous, ok = lastTwoMigrationIndexes(names[:2])
+	if !ok || last != 1528395834 || previous != 0 {
+		t.Fatalf("unexpected indexes for a single migration: last=%d previous=%d ok=%v", last, previous, ok)
+	}
+
+	if _, _, ok := lastTwoMigrationIndexes([]string{"README.md"}); ok {
+		t.Fatal("want no migration")
+	}
+}
+
+func TestNormalizeSchemaDump(t *testing.T) {
+	dump := `--
+-- PostgreSQL database dump
+--
+
+SET statement_timeout = 0;
+
+CREATE TABLE t (
+    id integer NOT NULL
+);
+
+`
+	want := []string{"SET statement_timeout = 0;", "CREATE TABLE t (", "    id integer NOT NULL", ");"}
+	if diff := cmp.Diff(want, normalizeSchemaDump(dump)); diff != "" {
+		t.Fatalf("unexpected lines (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,167 @@
+package migration
+
+import (
+	"fmt"
+	"net/url"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/sourcegraph/sourcegraph/dev/sg/internal/db"
+	"github.com/sourcegraph/sourcegraph/lib/output"
+)
+
+// RunValidateDown validates that the down migration of the last migration of the given
+// database is the inverse of its up migration. It migrates a throwaway database, created
+// next to the development database, up to the previous migration and dumps its schema.
+// Then it applies the up and down migrations of the last migration, and fails with a
+// diff if the schema is not back to the dumped one.
+func RunValidateDown(database db.Database) (err error) {
+	files, err := getMigrationFilesFromDisk(database)
+	if err != nil {
+		return err
+	}
+	lastIndex, previousIndex, ok := lastTwoMigrationIndexes(files)
+	if !ok {
+		return errors.Newf("no migrations exist for database %q", database.Name)
+	}
+
+	block := out.Block(output.Linef("", output.StyleBold, "Validating the down migration of %d", lastIndex))
+	defer block.Close()
+
+	throwawayDSN, teardown, err := createThrowawayDatabase(database, fmt.Sprintf("sg_validate_%d_%d", lastIndex, time.Now().Unix()))
+	if err != nil {
+		return err
+	}
+	defer func() { err = teardown(err) }()
+
+	throwawayDB, err := openPostgresDB(throwawayDSN)
+	if err != nil {
+		return err
+	}
+	defer throwawayDB.Close()
+
+	m, err := newMigrate(database, throwawayDB, mLogger{block: block, prefix: "  applying: "})
+	if err != nil {
+		return err
+	}
+	defer func() { _, _ = m.Close() }()
+
+	if previousIndex != 0 {
+		if err := m.Migrate(uint(previousIndex)); err != nil {
+			return errors.Wrapf(err, "migrating up to %d", previousIndex)
+		}
+	}
+	before, err := dumpSchema(database, throwawayDSN)
+	if err != nil {
+		return err
+	}
+
+	if err := m.Steps(1); err != nil {
+		return errors.Wrapf(err, "applying up migration %d", lastIndex)
+	}
+	if err := m.Steps(-1); err != nil {
+		return errors.Wrapf(err, "applying down migration %d", lastIndex)
+	}
+	after, err := dumpSchema(database, throwawayDSN)
+	if err != nil {
+		return err
+	}
+
+	if diff := cmp.Diff(before, after); diff != "" {
+		block.WriteLine(output.Linef(output.EmojiFailure, output.StyleWarning, "The down migration does not revert the up migration"))
+		return errors.Newf("schema after the down migration of %d differs from the schema before its up migration (-before +after):\n%s", lastIndex, diff)
+	}
+
+	block.WriteLine(output.Line(output.EmojiSuccess, output.StyleSuccess, "The down migration reverts the up migration"))
+	return nil
+}
+
+// lastTwoMigrationIndexes returns the index of the last migration among the given filenames
+// and the one of the migration before it, which is zero if there is none.
+func lastTwoMigrationIndexes(names []string) (last, previous int, ok bool) {
+	seen := map[int]struct{}{}
+	var indexes []int
+	for _, name := range names {
+		if index, ok := ParseMigrationIndex(name); ok {
+			if _, ok := seen[index]; !ok {
+				seen[index] = struct{}{}
+				indexes = append(indexes, index)
+			}
+		}
+	}
+	sort.Ints(indexes)
+
+	switch len(indexes) {
+	case 0:
+		return 0, 0, false
+	case 1:
+		return indexes[0], 0, true
+	}
+	return indexes[len(indexes)-1], indexes[len(indexes)-2], true
+}
+
+// createThrowawayDatabase creates an empty database with the given name on the Postgres
+// server of the given database. It returns the DSN of the new database and a teardown
+// function dropping it, which filters the error value of the calling function.
+func createThrowawayDatabase(database db.Database, name string) (_ string, _ func(error) error, err error) {
+	u, err := url.Parse(makePostgresDSN(database))
+	if err != nil || u.Scheme == "" {
+		return "", nil, errors.New("validating migrations requires a URL-style Postgres DSN")
+	}
+
+	sqlDB, err := getPostgresDB(database)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if _, err := sqlDB.Exec(fmt.Sprintf("CREATE DATABASE %q", name)); err != nil {
+		sqlDB.Close()
+		return "", nil, errors.Wrap(err, "creating throwaway database")
+	}
+
+	teardown := func(err error) error {
+		if _, dropErr := sqlDB.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %q", name)); dropErr != nil {
+			err = multierror.Append(err, errors.Wrapf(dropErr, "dropping throwaway database %q", name))
+		}
+		sqlDB.Close()
+		return err
+	}
+
+	u.Path = "/" + name
+	return u.String(), teardown, nil
+}
+
+// dumpSchema returns the lines of the schema of the database with the given DSN as dumped
+// by pg_dump, without comments, blank lines and the migrations table.
+func dumpSchema(database db.Database, dsn string) ([]string, error) {
+	cmd := exec.Command("pg_dump", dsn, "--schema-only", "--no-owner", "--exclude-table", database.MigrationsTable)
+	dump, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, errors.Wrapf(err, "pg_dump failed: %s", exitErr.Stderr)
+		}
+		return nil, errors.Wrap(err, "pg_dump")
+	}
+
+	return normalizeSchemaDump(string(dump)), nil
+}
+
+// normalizeSchemaDump removes the comments and blank lines of a pg_dump output, which
+// vary between dumps of the same schema.
+func normalizeSchemaDump(dump string) []string {
+	var lines []string
+	for _, line := range strings.Split(dump, "\n") {
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "--") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
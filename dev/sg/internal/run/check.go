@@ -4,4 +4,8 @@ type Check struct {
 	Name        string `yaml:"-"`
 	Cmd         string `yaml:"cmd"`
 	FailMessage string `yaml:"failMessage"`
+	// Fix is an optional command run by `sg doctor -fix` to remediate a failure
+	// of the check, e.g. by installing a missing tool or creating a missing
+	// database.
+	Fix string `yaml:"fix"`
 }
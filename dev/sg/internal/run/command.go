@@ -22,6 +22,7 @@ type Command struct {
 	IgnoreStderr        bool              `yaml:"ignoreStderr"`
 	DefaultArgs         string            `yaml:"defaultArgs"`
 	ContinueWatchOnExit bool              `yaml:"continueWatchOnExit"`
+	Ports               []string          `yaml:"ports"`
 
 	// ATTENTION: If you add a new field here, be sure to also handle that
 	// field in `Merge` (below).
@@ -58,6 +59,10 @@ func (c Command) Merge(other Command) Command {
 		merged.Watch = other.Watch
 	}
 
+	if !equal(merged.Ports, other.Ports) && len(other.Ports) > 0 {
+		merged.Ports = other.Ports
+	}
+
 	return merged
 }
 
@@ -116,9 +121,15 @@ func startCmd(ctx context.Context, dir string, cmd Command, globalEnv map[string
 	commandCtx, cancel := context.WithCancel(ctx)
 	sc.cancel = cancel
 
+	env, err := makeEnv(ctx, globalEnv, cmd.Env)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
 	sc.Cmd = exec.CommandContext(commandCtx, "bash", "-c", cmd.Cmd)
 	sc.Cmd.Dir = dir
-	sc.Cmd.Env = makeEnv(globalEnv, cmd.Env)
+	sc.Cmd.Env = env
 
 	var stdoutWriter, stderrWriter io.Writer
 	logger := newCmdLogger(commandCtx, cmd.Name, stdout.Out)
@@ -0,0 +1,101 @@
+package run
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/dev/sg/internal/secrets"
+)
+
+const (
+	// secretRefPrefix prefixes references to secrets in env values, e.g.
+	// `${secret:github_token}` is the value of the `github_token` secret.
+	secretRefPrefix = "secret:"
+	// portRefPrefix prefixes references to generated ports in env values, e.g.
+	// `${port:frontend}` is the port generated for the `frontend` command.
+	portRefPrefix = "port:"
+)
+
+// expandEnvValue expands the `$VAR` and `${VAR}` references in the given env
+// value with lookup, and the `${secret:name}` and `${port:name}` references
+// with the secrets store in ctx and the generated ports respectively.
+func expandEnvValue(ctx context.Context, value string, lookup func(string) string) (string, error) {
+	var err error
+	expanded := os.Expand(value, func(ref string) string {
+		var v string
+		var refErr error
+		switch {
+		case strings.HasPrefix(ref, secretRefPrefix):
+			v, refErr = lookupSecret(ctx, strings.TrimPrefix(ref, secretRefPrefix))
+		case strings.HasPrefix(ref, portRefPrefix):
+			v, refErr = generatedPorts.get(strings.TrimPrefix(ref, portRefPrefix))
+		default:
+			return lookup(ref)
+		}
+		if refErr != nil && err == nil {
+			err = refErr
+		}
+		return v
+	})
+	return expanded, err
+}
+
+// lookupSecret returns the value of the secret with the given name. Secrets
+// which are not JSON strings are returned as JSON.
+func lookupSecret(ctx context.Context, name string) (string, error) {
+	store := secrets.FromContext(ctx)
+	if store == nil {
+		return "", errors.Newf("cannot resolve secret %q: secrets are not available", name)
+	}
+
+	var raw json.RawMessage
+	if err := store.Get(name, &raw); err != nil {
+		return "", errors.Wrapf(err, "cannot resolve secret %q", name)
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s, nil
+	}
+	return string(raw), nil
+}
+
+// portRegistry hands out free ports, keeping the same port for a given name
+// so that all commands referencing it agree on it.
+type portRegistry struct {
+	mu    sync.Mutex
+	ports map[string]int
+}
+
+var generatedPorts = &portRegistry{ports: map[string]int{}}
+
+func (r *portRegistry) get(name string) (string, error) {
+	if name == "" {
+		return "", errors.New("cannot generate a port without a name")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if port, ok := r.ports[name]; ok {
+		return strconv.Itoa(port), nil
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot generate port %q", name)
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	if err := l.Close(); err != nil {
+		return "", errors.Wrapf(err, "cannot generate port %q", name)
+	}
+
+	r.ports[name] = port
+	return strconv.Itoa(port), nil
+}
@@ -0,0 +1,68 @@
+package run
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/dev/sg/internal/secrets"
+)
+
+func TestExpandEnvValue(t *testing.T) {
+	store := secrets.New("")
+	if err := store.Put("github_token", "s3cr3t"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put("buildkite", map[string]string{"token": "abc"}); err != nil {
+		t.Fatal(err)
+	}
+	ctx := secrets.WithContext(context.Background(), store)
+
+	lookup := func(name string) string {
+		if name == "HOST" {
+			return "localhost"
+		}
+		return ""
+	}
+
+	t.Run("env vars and secrets", func(t *testing.T) {
+		got, err := expandEnvValue(ctx, "https://${secret:github_token}@$HOST/${secret:buildkite}", lookup)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := `https://s3cr3t@localhost/{"token":"abc"}`; got != want {
+			t.Fatalf("wrong value: want %q, got %q", want, got)
+		}
+	})
+
+	t.Run("generated ports", func(t *testing.T) {
+		first, err := expandEnvValue(ctx, "${port:frontend}", lookup)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if port, err := strconv.Atoi(first); err != nil || port == 0 {
+			t.Fatalf("want a port, got %q", first)
+		}
+
+		// The same name always gets the same port.
+		second, err := expandEnvValue(ctx, "$HOST:${port:frontend}", lookup)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "localhost:" + first; second != want {
+			t.Fatalf("wrong value: want %q, got %q", want, second)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, value := range []string{"${secret:missing}", "${port:}"} {
+			if _, err := expandEnvValue(ctx, value, lookup); err == nil {
+				t.Errorf("want error for %q, got none", value)
+			}
+		}
+
+		if _, err := expandEnvValue(context.Background(), "${secret:github_token}", lookup); err == nil {
+			t.Error("want error without secrets store, got none")
+		}
+	})
+}
@@ -0,0 +1,222 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+// PortConflict describes a port declared by a command that is already in use.
+type PortConflict struct {
+	Command string
+	// Env is the env var holding the port.
+	Env  string
+	Port int
+	// Owner describes the process listening on the port, if it could be
+	// found.
+	Owner string
+}
+
+func (c PortConflict) String() string {
+	owner := "another process"
+	if c.Owner != "" {
+		owner = c.Owner
+	}
+	return fmt.Sprintf("%s: port %d (%s) is already in use by %s", c.Command, c.Port, c.Env, owner)
+}
+
+// PortConflictsErr is returned by AssignPorts when ports declared by the
+// commands are in use and could not be reassigned.
+type PortConflictsErr struct {
+	Conflicts []PortConflict
+}
+
+func (e PortConflictsErr) Error() string {
+	lines := make([]string, 0, len(e.Conflicts))
+	for _, c := range e.Conflicts {
+		lines = append(lines, c.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// PortAssignment is a port that was reassigned because the declared one was
+// in use.
+type PortAssignment struct {
+	PortConflict
+	NewPort int
+}
+
+func (a PortAssignment) String() string {
+	return fmt.Sprintf("%s, using port %d instead", a.PortConflict, a.NewPort)
+}
+
+// AssignPorts checks that the ports declared by the commands (see
+// Command.Ports, the names of the env vars holding the ports a command listens
+// on) are free before they are started.
+//
+// If a port is in use and autoAssign is false, a PortConflictsErr describing
+// the conflicts is returned. Otherwise a free port is assigned instead: ports
+// held by env vars of the global env are changed in the returned global env,
+// so that every command referencing them uses the new port, and the others
+// in the env of the command. Ports set in the process env are never changed.
+func AssignPorts(ctx context.Context, globalEnv map[string]string, autoAssign bool, cmds ...Command) (map[string]string, []Command, []PortAssignment, error) {
+	newGlobalEnv := make(map[string]string, len(globalEnv))
+	for k, v := range globalEnv {
+		newGlobalEnv[k] = v
+	}
+	newCmds := make([]Command, 0, len(cmds))
+
+	var (
+		conflicts   []PortConflict
+		assignments []PortAssignment
+		// checked avoids reporting the same global port for every command
+		// using it.
+		checked = map[string]bool{}
+	)
+	for _, cmd := range cmds {
+		env, err := makeEnv(ctx, newGlobalEnv, cmd.Env)
+		if err != nil {
+			return nil, nil, nil, errors.Wrapf(err, "resolving ports of %s", cmd.Name)
+		}
+
+		cmdEnv := make(map[string]string, len(cmd.Env))
+		for k, v := range cmd.Env {
+			cmdEnv[k] = v
+		}
+
+		for _, name := range cmd.Ports {
+			_, local := cmd.Env[name]
+			if !local && checked[name] {
+				continue
+			}
+			checked[name] = true
+
+			value, ok := lookupEnv(env, name)
+			if !ok {
+				return nil, nil, nil, errors.Newf("%s declares the port %s, but it is not set", cmd.Name, name)
+			}
+			host, port, err := parsePort(value)
+			if err != nil {
+				return nil, nil, nil, errors.Wrapf(err, "%s declares the port %s", cmd.Name, name)
+			}
+			if portFree(port) {
+				continue
+			}
+
+			conflict := PortConflict{Command: cmd.Name, Env: name, Port: port, Owner: portOwner(ctx, port)}
+			if _, pinned := os.LookupEnv(name); !autoAssign || pinned {
+				conflicts = append(conflicts, conflict)
+				continue
+			}
+
+			newPort, err := freePort()
+			if err != nil {
+				return nil, nil, nil, errors.Wrapf(err, "assigning a port to %s", name)
+			}
+			newValue := strconv.Itoa(newPort)
+			if host != "" || strings.Contains(value, ":") {
+				newValue = net.JoinHostPort(host, newValue)
+			}
+			if local {
+				cmdEnv[name] = newValue
+			} else {
+				newGlobalEnv[name] = newValue
+			}
+			assignments = append(assignments, PortAssignment{PortConflict: conflict, NewPort: newPort})
+		}
+
+		cmd.Env = cmdEnv
+		newCmds = append(newCmds, cmd)
+	}
+
+	if len(conflicts) > 0 {
+		return nil, nil, nil, PortConflictsErr{Conflicts: conflicts}
+	}
+	return newGlobalEnv, newCmds, assignments, nil
+}
+
+// lookupEnv returns the value of the given key in env, as seen by a process
+// started with it: the last occurrence of the key wins.
+func lookupEnv(env []string, key string) (string, bool) {
+	value, found := "", false
+	for _, kv := range env {
+		if parts := strings.SplitN(kv, "=", 2); len(parts) == 2 && parts[0] == key {
+			value, found = parts[1], true
+		}
+	}
+	return value, found
+}
+
+// parsePort parses a port given either as a number or as an address, e.g.
+// `3080`, `:3080` or `127.0.0.1:3080`.
+func parsePort(value string) (host string, port int, err error) {
+	portStr := value
+	if strings.Contains(value, ":") {
+		if host, portStr, err = net.SplitHostPort(value); err != nil {
+			return "", 0, err
+		}
+	}
+	port, err = strconv.Atoi(portStr)
+	if err != nil || port <= 0 || port > 65535 {
+		return "", 0, errors.Newf("%q is not a valid port", value)
+	}
+	return host, port, nil
+}
+
+// portFree returns whether nothing listens on the given port.
+func portFree(port int) bool {
+	l, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return false
+	}
+	_ = l.Close()
+	return true
+}
+
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		return 0, err
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	return port, l.Close()
+}
+
+// portOwner describes the process listening on the given port, e.g.
+// `postgres (pid 1234)`, using lsof. It returns an empty string if the owner
+// can't be found.
+func portOwner(ctx context.Context, port int) string {
+	out, err := exec.CommandContext(ctx, "lsof", "-nP", fmt.Sprintf("-iTCP:%d", port), "-sTCP:LISTEN", "-Fpc").Output()
+	if err != nil {
+		return ""
+	}
+
+	// The output has one field per line, prefixed with the field name: p for
+	// the pid and c for the command name.
+	var pid, name string
+	for _, line := range strings.Split(string(out), "\n") {
+		if len(line) < 2 {
+			continue
+		}
+		switch line[0] {
+		case 'p':
+			if pid != "" {
+				// Only describe the first process.
+				return fmt.Sprintf("%s (pid %s)", name, pid)
+			}
+			pid = line[1:]
+		case 'c':
+			name = line[1:]
+		}
+	}
+	if pid == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s (pid %s)", name, pid)
+}
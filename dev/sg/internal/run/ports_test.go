@@ -0,0 +1,103 @@
+package run
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAssignPorts(t *testing.T) {
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	busy := strconv.Itoa(l.Addr().(*net.TCPAddr).Port)
+
+	free, err := freePort()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	globalEnv := map[string]string{
+		"SG_TEST_DB_PORT": busy,
+		"SG_TEST_DB_ADDR": "127.0.0.1:$SG_TEST_DB_PORT",
+	}
+	cmds := []Command{
+		{Name: "db", Ports: []string{"SG_TEST_DB_PORT"}},
+		{Name: "web", Env: map[string]string{"SG_TEST_WEB_ADDR": ":" + busy}, Ports: []string{"SG_TEST_WEB_ADDR"}},
+		{Name: "api", Env: map[string]string{"SG_TEST_API_PORT": strconv.Itoa(free)}, Ports: []string{"SG_TEST_API_PORT"}},
+		// Global ports are only reported once.
+		{Name: "db-client", Ports: []string{"SG_TEST_DB_PORT"}},
+	}
+
+	t.Run("conflicts", func(t *testing.T) {
+		_, _, _, err := AssignPorts(context.Background(), globalEnv, false, cmds...)
+		var conflicts PortConflictsErr
+		if !errors.As(err, &conflicts) {
+			t.Fatalf("want PortConflictsErr, got %v", err)
+		}
+
+		var got []string
+		for _, c := range conflicts.Conflicts {
+			got = append(got, c.Command+" "+c.Env+" "+strconv.Itoa(c.Port))
+		}
+		want := []string{"db SG_TEST_DB_PORT " + busy, "web SG_TEST_WEB_ADDR " + busy}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Fatalf("wrong conflicts (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("auto assign", func(t *testing.T) {
+		env, newCmds, assignments, err := AssignPorts(context.Background(), globalEnv, true, cmds...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(assignments) != 2 {
+			t.Fatalf("want 2 assignments, got %v", assignments)
+		}
+
+		// Global ports are changed in the global env, so dependent env vars
+		// use the new port too.
+		dbPort := strconv.Itoa(assignments[0].NewPort)
+		if env["SG_TEST_DB_PORT"] != dbPort {
+			t.Errorf("wrong global port: want %s, got %s", dbPort, env["SG_TEST_DB_PORT"])
+		}
+		resolved, err := makeEnv(context.Background(), env)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if addr, _ := lookupEnv(resolved, "SG_TEST_DB_ADDR"); addr != "127.0.0.1:"+dbPort {
+			t.Errorf("wrong dependent address: %s", addr)
+		}
+
+		// Command ports are changed in the env of the command, keeping the
+		// host.
+		if want, got := ":"+strconv.Itoa(assignments[1].NewPort), newCmds[1].Env["SG_TEST_WEB_ADDR"]; got != want {
+			t.Errorf("wrong command address: want %s, got %s", want, got)
+		}
+		if want, got := strconv.Itoa(free), newCmds[2].Env["SG_TEST_API_PORT"]; got != want {
+			t.Errorf("free port was changed: want %s, got %s", want, got)
+		}
+
+		// The input is left untouched.
+		if globalEnv["SG_TEST_DB_PORT"] != busy || cmds[1].Env["SG_TEST_WEB_ADDR"] != ":"+busy {
+			t.Error("input was modified")
+		}
+	})
+
+	t.Run("invalid port", func(t *testing.T) {
+		cmd := Command{Name: "broken", Env: map[string]string{"SG_TEST_PORT": "http"}, Ports: []string{"SG_TEST_PORT"}}
+		if _, _, _, err := AssignPorts(context.Background(), nil, false, cmd); err == nil {
+			t.Error("want error for invalid port, got none")
+		}
+		cmd.Ports = []string{"SG_TEST_UNSET_PORT"}
+		if _, _, _, err := AssignPorts(context.Background(), nil, false, cmd); err == nil {
+			t.Error("want error for unset port, got none")
+		}
+	})
+}
@@ -207,7 +207,12 @@ func runWatch(ctx context.Context, cmd Command, root string, globalEnv map[strin
 		if cmd.Install != "" {
 			stdout.Out.WriteLine(output.Linef("", output.StylePending, "Installing %s...", cmd.Name))
 
-			cmdOut, err := BashInRoot(ctx, cmd.Install, makeEnv(globalEnv, cmd.Env))
+			env, err := makeEnv(ctx, globalEnv, cmd.Env)
+			if err != nil {
+				return installErr{cmdName: cmd.Name, originalErr: err}
+			}
+
+			cmdOut, err := BashInRoot(ctx, cmd.Install, env)
 			if err != nil {
 				if !startedOnce {
 					return installErr{cmdName: cmd.Name, output: cmdOut, originalErr: err}
@@ -305,7 +310,10 @@ func runWatch(ctx context.Context, cmd Command, root string, globalEnv map[strin
 	}
 }
 
-func makeEnv(envs ...map[string]string) []string {
+// makeEnv combines the process env with the given envs. Values in the given
+// envs are expanded at this point, which includes resolving references to
+// secrets and generated ports (see expandEnvValue).
+func makeEnv(ctx context.Context, envs ...map[string]string) ([]string, error) {
 	combined := os.Environ()
 
 	expandedEnv := map[string]string{}
@@ -326,7 +334,7 @@ func makeEnv(envs ...map[string]string) []string {
 			// so they can be used when expanding too.
 			// TODO: using range to iterate over the env is not stable and thus
 			// this won't work
-			expanded := os.Expand(v, func(lookup string) string {
+			expanded, err := expandEnvValue(ctx, v, func(lookup string) string {
 				// If we're looking up the key that we're trying to define, we
 				// skip the self-reference and look in the OS
 				if lookup == k {
@@ -338,12 +346,15 @@ func makeEnv(envs ...map[string]string) []string {
 				}
 				return os.Getenv(lookup)
 			})
+			if err != nil {
+				return nil, errors.Wrapf(err, "expanding env var %s", k)
+			}
 			expandedEnv[k] = expanded
 			combined = append(combined, fmt.Sprintf("%s=%s", k, expanded))
 		}
 	}
 
-	return combined
+	return combined, nil
 }
 
 func md5HashFile(filename string) (string, error) {
@@ -469,9 +480,14 @@ func Test(ctx context.Context, cmd Command, args []string, globalEnv map[string]
 		cmdArgs = append(cmdArgs, cmd.DefaultArgs)
 	}
 
+	env, err := makeEnv(ctx, globalEnv, cmd.Env)
+	if err != nil {
+		return err
+	}
+
 	c := exec.CommandContext(commandCtx, "bash", "-c", strings.Join(cmdArgs, " "))
 	c.Dir = root
-	c.Env = makeEnv(globalEnv, cmd.Env)
+	c.Env = env
 	c.Stdout = os.Stdout
 	c.Stderr = os.Stderr
 
@@ -481,19 +497,31 @@ func Test(ctx context.Context, cmd Command, args []string, globalEnv map[string]
 }
 
 func Checks(ctx context.Context, globalEnv map[string]string, checks ...Check) (bool, error) {
-	success := true
+	failed, err := FailedChecks(ctx, globalEnv, checks...)
+	return len(failed) == 0, err
+}
+
+// FailedChecks runs the checks, reporting their results, and returns the ones
+// that failed.
+func FailedChecks(ctx context.Context, globalEnv map[string]string, checks ...Check) ([]Check, error) {
+	var failed []Check
 
 	for _, check := range checks {
 		commandCtx, cancel := context.WithCancel(ctx)
 		defer cancel()
 
+		env, err := makeEnv(ctx, globalEnv)
+		if err != nil {
+			return nil, err
+		}
+
 		c := exec.CommandContext(commandCtx, "bash", "-c", check.Cmd)
-		c.Env = makeEnv(globalEnv)
+		c.Env = env
 
 		p := stdout.Out.Pending(output.Linef(output.EmojiLightbulb, output.StylePending, "Running check %q...", check.Name))
 
 		if cmdOut, err := InRoot(c); err != nil {
-			success = false
+			failed = append(failed, check)
 
 			p.Complete(output.Linef(output.EmojiFailure, output.StyleWarning, "Check %q failed: %s", check.Name, err))
 
@@ -513,5 +541,30 @@ func Checks(ctx context.Context, globalEnv map[string]string, checks ...Check) (
 		}
 	}
 
-	return success, nil
+	return failed, nil
+}
+
+// Fix runs the fix command of the check in the repository root, connected to
+// the terminal so that it can prompt for input, e.g. for a sudo password.
+func Fix(ctx context.Context, globalEnv map[string]string, check Check) error {
+	if check.Fix == "" {
+		return errors.Newf("check %q has no fix", check.Name)
+	}
+
+	env, err := makeEnv(ctx, globalEnv)
+	if err != nil {
+		return err
+	}
+	repoRoot, err := root.RepositoryRoot()
+	if err != nil {
+		return err
+	}
+
+	c := exec.CommandContext(ctx, "bash", "-c", check.Fix)
+	c.Env = env
+	c.Dir = repoRoot
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return c.Run()
 }
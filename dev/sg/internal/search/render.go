@@ -0,0 +1,143 @@
+package search
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/lib/output"
+)
+
+// Render returns the lines printing the match in a terminal, with the matched
+// ranges highlighted.
+func Render(m Match) []string {
+	var lines []string
+	switch m.Type {
+	case "repo":
+		lines = append(lines, fmt.Sprintf("%s%s%s", output.StyleSearchRepository, m.Repository, output.StyleReset))
+
+	case "path":
+		lines = append(lines, header(m))
+
+	case "content":
+		lines = append(lines, header(m))
+		for _, lm := range m.LineMatches {
+			ranges := make([][2]int, 0, len(lm.OffsetAndLengths))
+			for _, ol := range lm.OffsetAndLengths {
+				ranges = append(ranges, [2]int{int(ol[0]), int(ol[0] + ol[1])})
+			}
+			// Line numbers are 0-based in the API.
+			lines = append(lines, fmt.Sprintf("%s%6d%s  %s", output.StyleSearchLineNumbers, lm.LineNumber+1, output.StyleReset, highlight(lm.Line, ranges)))
+		}
+
+	case "symbol":
+		lines = append(lines, header(m))
+		for _, s := range m.Symbols {
+			container := ""
+			if s.ContainerName != "" {
+				container = fmt.Sprintf(" %s(%s)%s", output.StyleSuggestion, s.ContainerName, output.StyleReset)
+			}
+			lines = append(lines, fmt.Sprintf("  %s %s%s%s%s", strings.ToLower(s.Kind), output.StyleSearchMatch, s.Name, output.StyleReset, container))
+		}
+
+	case "commit":
+		lines = append(lines, fmt.Sprintf("%s%s%s %s%s%s",
+			output.StyleSearchCommitSubject, stripMarkdownLinks(m.Label), output.StyleReset,
+			output.StyleSearchCommitDate, stripMarkdownLinks(m.Detail), output.StyleReset))
+		lines = append(lines, commitContent(m)...)
+
+	default:
+		lines = append(lines, fmt.Sprintf("%s%s%s (%s match)", output.StyleSearchRepository, m.Repository, output.StyleReset, m.Type))
+	}
+	return lines
+}
+
+func header(m Match) string {
+	return fmt.Sprintf("%s%s%s › %s%s%s",
+		output.StyleSearchRepository, m.Repository, output.StyleReset,
+		output.StyleSearchFilename, m.Path, output.StyleReset)
+}
+
+// commitContent returns the lines of the content of a commit match, which is a
+// markdown code block, with the ranges highlighted.
+func commitContent(m Match) []string {
+	content := strings.TrimSuffix(strings.TrimPrefix(m.Content, "```"), "```")
+	if i := strings.Index(content, "\n"); i >= 0 {
+		// Skip the language of the code block.
+		content = content[i+1:]
+	}
+	contentLines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+
+	rangesByLine := map[int][][2]int{}
+	for _, r := range m.Ranges {
+		// Ranges are [line, character, length], with lines counted from the
+		// first line of the markdown code block.
+		line := int(r[0]) - 1
+		rangesByLine[line] = append(rangesByLine[line], [2]int{int(r[1]), int(r[1] + r[2])})
+	}
+
+	lines := make([]string, 0, len(contentLines))
+	for i, line := range contentLines {
+		lines = append(lines, "  "+highlight(line, rangesByLine[i]))
+	}
+	return lines
+}
+
+// highlight highlights the given byte ranges of s. Ranges that are out of
+// bounds are clamped, overlapping ranges are merged.
+func highlight(s string, ranges [][2]int) string {
+	if len(ranges) == 0 {
+		return s
+	}
+	sorted := append([][2]int(nil), ranges...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i][0] < sorted[j][0] })
+
+	var b strings.Builder
+	pos := 0
+	for _, r := range sorted {
+		start, end := clamp(r[0], pos, len(s)), clamp(r[1], pos, len(s))
+		if start >= end {
+			continue
+		}
+		b.WriteString(s[pos:start])
+		fmt.Fprintf(&b, "%s%s%s", output.StyleSearchMatch, s[start:end], output.StyleReset)
+		pos = end
+	}
+	b.WriteString(s[pos:])
+	return b.String()
+}
+
+func clamp(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+// stripMarkdownLinks turns the markdown links of a commit label, e.g.
+// [repo](url) › [author](url): [subject](url), into their text.
+func stripMarkdownLinks(s string) string {
+	var b strings.Builder
+	for {
+		open := strings.Index(s, "[")
+		if open < 0 {
+			break
+		}
+		mid := strings.Index(s[open:], "](")
+		if mid < 0 {
+			break
+		}
+		end := strings.Index(s[open+mid:], ")")
+		if end < 0 {
+			break
+		}
+		b.WriteString(s[:open])
+		b.WriteString(s[open+1 : open+mid])
+		s = s[open+mid+end+1:]
+	}
+	b.WriteString(s)
+	return b.String()
+}
@@ -0,0 +1,214 @@
+// Package search runs searches against the streaming search API of a
+// Sourcegraph instance.
+//
+// It decodes the subset of the events of the API that is needed to print the
+// results in a terminal. The full types live in
+// internal/search/streaming/http, which sg can't import.
+package search
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+// maxEventSize is the maximum size of an event, matching the limit of the
+// streaming API.
+const maxEventSize = 10 * 1024 * 1024
+
+// ErrUnauthorized is returned when the instance rejects the access token, or
+// requires one.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// Match is a search result. Which fields are set depends on the type of the
+// match.
+type Match struct {
+	// Type is one of content, path, repo, symbol or commit.
+	Type       string `json:"type"`
+	Repository string `json:"repository"`
+	Path       string `json:"path"`
+	Commit     string `json:"commit"`
+
+	LineMatches []LineMatch `json:"lineMatches"`
+	Symbols     []Symbol    `json:"symbols"`
+
+	// Label, URL, Detail, Content and Ranges are set on commit matches.
+	Label   string     `json:"label"`
+	URL     string     `json:"url"`
+	Detail  string     `json:"detail"`
+	Content string     `json:"content"`
+	Ranges  [][3]int32 `json:"ranges"`
+}
+
+// LineMatch is a matching line of a content match.
+type LineMatch struct {
+	Line       string `json:"line"`
+	LineNumber int32  `json:"lineNumber"`
+	// OffsetAndLengths are the byte offsets and lengths of the matches in
+	// Line.
+	OffsetAndLengths [][2]int32 `json:"offsetAndLengths"`
+}
+
+// Symbol is a matching symbol of a symbol match.
+type Symbol struct {
+	Name          string `json:"name"`
+	ContainerName string `json:"containerName"`
+	Kind          string `json:"kind"`
+}
+
+// Progress reports the progress of a search.
+type Progress struct {
+	Done              bool      `json:"done"`
+	RepositoriesCount *int      `json:"repositoriesCount,omitempty"`
+	MatchCount        int       `json:"matchCount"`
+	DurationMs        int       `json:"durationMs"`
+	Skipped           []Skipped `json:"skipped"`
+}
+
+// Skipped describes why some results were not returned.
+type Skipped struct {
+	Reason  string `json:"reason"`
+	Title   string `json:"title"`
+	Message string `json:"message"`
+}
+
+// Alert is an alert returned instead of results, e.g. for an invalid query.
+type Alert struct {
+	Title           string `json:"title"`
+	Description     string `json:"description"`
+	ProposedQueries []struct {
+		Description string `json:"description"`
+		Query       string `json:"query"`
+	} `json:"proposedQueries"`
+}
+
+// Handler receives the events of a search as they are streamed.
+type Handler struct {
+	OnMatches  func([]Match)
+	OnProgress func(Progress)
+	OnAlert    func(Alert)
+}
+
+// Options are the options of a search.
+type Options struct {
+	// Endpoint is the URL of the Sourcegraph instance, e.g.
+	// https://sourcegraph.com.
+	Endpoint string
+	// Token is the access token used to authenticate, if any.
+	Token string
+	// PatternType is the pattern type of the query, e.g. literal or regexp,
+	// if it is not set in the query.
+	PatternType string
+	// DisplayLimit is the maximum number of matches the instance sends. All
+	// matches are sent if it is negative.
+	DisplayLimit int
+}
+
+// Stream runs the search and calls the handler for every event streamed by the
+// instance, until the search is done.
+func Stream(ctx context.Context, query string, opts Options, h Handler) error {
+	params := url.Values{
+		"q":       {query},
+		"v":       {"V2"},
+		"display": {strconv.Itoa(opts.DisplayLimit)},
+	}
+	if opts.PatternType != "" {
+		params.Set("t", opts.PatternType)
+	}
+	u := strings.TrimSuffix(opts.Endpoint, "/") + "/.api/search/stream?" + params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("X-Requested-With", "sg")
+	if opts.Token != "" {
+		req.Header.Set("Authorization", "token "+opts.Token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		return ErrUnauthorized
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Newf("search failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return Decode(resp.Body, h)
+}
+
+// Decode decodes the server-sent events of a search from r until the done
+// event.
+func Decode(r io.Reader, h Handler) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxEventSize)
+	// Events are separated by empty lines.
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		if atEOF && len(data) == 0 {
+			return 0, nil, nil
+		}
+		if i := bytes.Index(data, []byte("\n\n")); i >= 0 {
+			return i + 2, data[:i], nil
+		}
+		if atEOF {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	})
+
+	for scanner.Scan() {
+		var event, data []byte
+		for _, line := range bytes.Split(scanner.Bytes(), []byte("\n")) {
+			if v := bytes.TrimPrefix(line, []byte("event: ")); len(v) != len(line) {
+				event = v
+			} else if v := bytes.TrimPrefix(line, []byte("data: ")); len(v) != len(line) {
+				data = v
+			}
+		}
+
+		var err error
+		switch string(event) {
+		case "matches":
+			var matches []Match
+			if err = json.Unmarshal(data, &matches); err == nil && h.OnMatches != nil {
+				h.OnMatches(matches)
+			}
+		case "progress":
+			var progress Progress
+			if err = json.Unmarshal(data, &progress); err == nil && h.OnProgress != nil {
+				h.OnProgress(progress)
+			}
+		case "alert":
+			var alert Alert
+			if err = json.Unmarshal(data, &alert); err == nil && h.OnAlert != nil {
+				h.OnAlert(alert)
+			}
+		case "error":
+			var e struct {
+				Message string `json:"message"`
+			}
+			if err = json.Unmarshal(data, &e); err == nil {
+				return errors.New(e.Message)
+			}
+		case "done":
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "decoding %s event", event)
+		}
+	}
+	return scanner.Err()
+}
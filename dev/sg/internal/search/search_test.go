@@ -0,0 +1,154 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/lib/output"
+)
+
+const stream = `event: progress
+data: {"done":false,"matchCount":0,"durationMs":5}
+
+event: matches
+data: [{"type":"content","repository":"github.com/sourcegraph/sourcegraph","path":"main.go","lineMatches":[{"line":"func main() {","lineNumber":9,"offsetAndLengths":[[5,4]]}]},{"type":"repo","repository":"github.com/sourcegraph/sg"}]
+
+event: alert
+data: {"title":"Too many results","proposedQueries":[{"query":"repo:sourcegraph main"}]}
+
+event: progress
+data: {"done":true,"repositoriesCount":2,"matchCount":2,"durationMs":42,"skipped":[{"reason":"display","title":"Display limit hit"}]}
+
+event: done
+data: {}
+
+`
+
+func TestDecode(t *testing.T) {
+	var (
+		matches  []Match
+		progress Progress
+		alerts   []string
+	)
+	err := Decode(strings.NewReader(stream), Handler{
+		OnMatches:  func(m []Match) { matches = append(matches, m...) },
+		OnProgress: func(p Progress) { progress = p },
+		OnAlert:    func(a Alert) { alerts = append(alerts, a.Title) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantMatches := []Match{
+		{
+			Type:       "content",
+			Repository: "github.com/sourcegraph/sourcegraph",
+			Path:       "main.go",
+			LineMatches: []LineMatch{
+				{Line: "func main() {", LineNumber: 9, OffsetAndLengths: [][2]int32{{5, 4}}},
+			},
+		},
+		{Type: "repo", Repository: "github.com/sourcegraph/sg"},
+	}
+	if diff := cmp.Diff(wantMatches, matches); diff != "" {
+		t.Errorf("unexpected matches (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"Too many results"}, alerts); diff != "" {
+		t.Errorf("unexpected alerts (-want +got):\n%s", diff)
+	}
+	if !progress.Done || progress.MatchCount != 2 || progress.DurationMs != 42 || len(progress.Skipped) != 1 {
+		t.Errorf("unexpected progress: %+v", progress)
+	}
+
+	err = Decode(strings.NewReader("event: error\ndata: {\"message\":\"invalid query\"}\n\n"), Handler{})
+	if err == nil || err.Error() != "invalid query" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestStream(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/.api/search/stream" || r.URL.Query().Get("q") != "func main" || r.URL.Query().Get("t") != "regexp" {
+			http.Error(w, fmt.Sprintf("unexpected request %s", r.URL), http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(stream))
+	}))
+	defer ts.Close()
+
+	opts := Options{Endpoint: ts.URL + "/", PatternType: "regexp", DisplayLimit: 10}
+	if err := Stream(context.Background(), "func main", opts, Handler{}); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("expected ErrUnauthorized, got %v", err)
+	}
+
+	opts.Token = "secret"
+	count := 0
+	if err := Stream(context.Background(), "func main", opts, Handler{OnMatches: func(m []Match) { count += len(m) }}); err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("unexpected number of matches: %d", count)
+	}
+}
+
+func TestRender(t *testing.T) {
+	match := func(s string) string { return fmt.Sprintf("%s%s%s", output.StyleSearchMatch, s, output.StyleReset) }
+
+	content := Render(Match{
+		Type:        "content",
+		Repository:  "r",
+		Path:        "p",
+		LineMatches: []LineMatch{{Line: "func main() {", LineNumber: 9, OffsetAndLengths: [][2]int32{{5, 4}, {0, 4}}}},
+	})
+	if len(content) != 2 || !strings.HasSuffix(content[1], match("func")+" "+match("main")+"() {") || !strings.Contains(content[1], "10") {
+		t.Errorf("unexpected content match lines: %q", content)
+	}
+
+	commit := Render(Match{
+		Type:    "commit",
+		Label:   "[sourcegraph/sourcegraph](https://r) › [Alice](https://c): [fix the bug](https://c)",
+		Detail:  "[`abc1234` 2 days ago](https://c)",
+		Content: "```COMMIT_EDITMSG\nfix the bug\n\nreally\n```",
+		Ranges:  [][3]int32{{1, 4, 3}},
+	})
+	want := []string{
+		fmt.Sprintf("%ssourcegraph/sourcegraph › Alice: fix the bug%s %s`abc1234` 2 days ago%s", output.StyleSearchCommitSubject, output.StyleReset, output.StyleSearchCommitDate, output.StyleReset),
+		"  fix " + match("the") + " bug",
+		"  ",
+		"  really",
+	}
+	if diff := cmp.Diff(want, commit); diff != "" {
+		t.Errorf("unexpected commit match lines (-want +got):\n%s", diff)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	match := func(s string) string { return fmt.Sprintf("%s%s%s", output.StyleSearchMatch, s, output.StyleReset) }
+
+	for _, tc := range []struct {
+		name   string
+		ranges [][2]int
+		want   string
+	}{
+		{name: "none", want: "hello world"},
+		{name: "overlapping", ranges: [][2]int{{0, 5}, {3, 8}}, want: match("hello") + match(" wo") + "rld"},
+		{name: "out of bounds", ranges: [][2]int{{6, 100}}, want: "hello " + match("world")},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := highlight("hello world", tc.ranges); got != tc.want {
+				t.Errorf("highlight = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// Package update fetches the sg releases published by the sg-binary-release GitHub
+// workflow and installs them in place of the running binary.
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/cockroachdb/errors"
+)
+
+// releasesURL is where the sg-binary-release workflow publishes sg releases. It is a
+// variable so that tests can point it at a fake server.
+var releasesURL = "https://github.com/sourcegraph/sg/releases"
+
+// Manifest describes an sg release. It is uploaded as the manifest.json asset of every
+// release, once all of its binaries have been uploaded.
+type Manifest struct {
+	// Release is the name of the GitHub release.
+	Release string `json:"release"`
+	// Commit is the last commit in ./dev/sg at the time of the release, which is baked
+	// into the released binaries as their BuildCommit.
+	Commit string `json:"commit"`
+}
+
+// AssetURL returns the download URL of the binary of the release for the given platform.
+func (m *Manifest) AssetURL(goos, goarch string) string {
+	return fmt.Sprintf("%s/download/%s/sg_%s_%s", releasesURL, m.Release, goos, goarch)
+}
+
+// LatestManifest fetches the manifest of the latest sg release.
+func LatestManifest(ctx context.Context) (*Manifest, error) {
+	body, err := get(ctx, releasesURL+"/latest/download/manifest.json")
+	if err != nil {
+		return nil, errors.Wrap(err, "fetching release manifest")
+	}
+	defer body.Close()
+
+	var m Manifest
+	if err := json.NewDecoder(body).Decode(&m); err != nil {
+		return nil, errors.Wrap(err, "decoding release manifest")
+	}
+	if m.Release == "" || m.Commit == "" {
+		return nil, errors.New("release manifest is incomplete")
+	}
+	return &m, nil
+}
+
+// Install downloads the binary of the release for the current platform and atomically
+// replaces the executable at path with it.
+func Install(ctx context.Context, m *Manifest, path string) (err error) {
+	body, err := get(ctx, m.AssetURL(runtime.GOOS, runtime.GOARCH))
+	if err != nil {
+		return errors.Wrapf(err, "downloading sg for %s/%s", runtime.GOOS, runtime.GOARCH)
+	}
+	defer body.Close()
+
+	// The new binary is written next to the current one, so that it can be renamed over
+	// it: the rename is atomic and works even though the current binary is running.
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".sg-update-")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "downloading sg")
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0755); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func get(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, errors.Newf("GET %s: unexpected status %s", url, resp.Status)
+	}
+	return resp.Body, nil
+}
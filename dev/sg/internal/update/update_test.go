@@ -0,0 +1,64 @@
+package update
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestLatestManifestAndInstall(t *testing.T) {
+	binaryPath := fmt.Sprintf("/download/2021-10-15-12-00-abcdef12/sg_%s_%s", runtime.GOOS, runtime.GOARCH)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/latest/download/manifest.json":
+			fmt.Fprint(w, `{"release": "2021-10-15-12-00-abcdef12", "commit": "abcdef1234567890"}`)
+		case binaryPath:
+			fmt.Fprint(w, "new sg")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	oldURL := releasesURL
+	releasesURL = srv.URL
+	defer func() { releasesURL = oldURL }()
+
+	ctx := context.Background()
+	m, err := LatestManifest(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Manifest{Release: "2021-10-15-12-00-abcdef12", Commit: "abcdef1234567890"}); *m != want {
+		t.Fatalf("wrong manifest: have %+v, want %+v", *m, want)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sg")
+	if err := os.WriteFile(path, []byte("old sg"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := Install(ctx, m, path); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "new sg" {
+		t.Fatalf("binary not replaced: %q, %v", data, err)
+	}
+
+	// A failed download must leave the current binary and no temporary file behind.
+	if err := Install(ctx, &Manifest{Release: "missing"}, path); err == nil {
+		t.Fatal("expected error")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("unexpected files left behind: %v", entries)
+	}
+}
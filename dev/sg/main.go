@@ -52,6 +52,7 @@ var (
 			runSetCommand,
 			startCommand,
 			testCommand,
+			benchCommand,
 			doctorCommand,
 			liveCommand,
 			migrationCommand,
@@ -60,6 +61,10 @@ var (
 			teammateCommand,
 			ciCommand,
 			installCommand,
+			updateCommand,
+			generateCommand,
+			imagesCommand,
+			searchCommand,
 		},
 	}
 )
@@ -96,11 +101,13 @@ func checkSgVersion() {
 		return
 	}
 
-	rev := BuildCommit
-	if strings.HasPrefix(BuildCommit, "dev-") {
-		rev = BuildCommit[len("dev-"):]
+	if rootFlagSet.Arg(0) == updateCommand.Name {
+		// `sg update` reports on new versions itself.
+		return
 	}
 
+	rev := buildRevision()
+
 	out, err := run.GitCmd("rev-list", fmt.Sprintf("%s..HEAD", rev), "./dev/sg")
 	if err != nil {
 		fmt.Printf("error getting new commits since %s in ./dev/sg: %s\n", rev, err)
@@ -111,7 +118,7 @@ func checkSgVersion() {
 	out = strings.TrimSpace(out)
 	if out != "" {
 		stdout.Out.WriteLine(output.Linef("", output.StyleSearchMatch, "--------------------------------------------------------------------------"))
-		stdout.Out.WriteLine(output.Linef("", output.StyleSearchMatch, "HEY! New version of sg available. Run `./dev/sg/install.sh` to install it."))
+		stdout.Out.WriteLine(output.Linef("", output.StyleSearchMatch, "HEY! New version of sg available. Run `sg update` to install it."))
 		stdout.Out.WriteLine(output.Linef("", output.StyleSearchMatch, "--------------------------------------------------------------------------"))
 	}
 }
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+	"github.com/peterbourgon/ff/v3/ffcli"
+
+	"github.com/sourcegraph/sourcegraph/dev/sg/internal/bench"
+	"github.com/sourcegraph/sourcegraph/dev/sg/internal/run"
+	"github.com/sourcegraph/sourcegraph/lib/output"
+)
+
+var (
+	benchFlagSet       = flag.NewFlagSet("sg bench", flag.ExitOnError)
+	benchBaseFlag      = benchFlagSet.String("base", "main", "The git ref to compare HEAD against.")
+	benchRegexpFlag    = benchFlagSet.String("bench", ".", "Run only the benchmarks matching this regular expression, as in `go test -bench`.")
+	benchCountFlag     = benchFlagSet.Int("count", 6, "The number of times to run each benchmark at each revision.")
+	benchBenchtimeFlag = benchFlagSet.String("benchtime", "", "The benchtime passed to `go test`, e.g. 2s or 100x.")
+	benchAlphaFlag     = benchFlagSet.Float64("alpha", bench.DefaultAlpha, "The p-value under which a difference is considered significant.")
+
+	benchCommand = &ffcli.Command{
+		Name:       "bench",
+		ShortUsage: "sg bench [-base=main] [-bench=regexp] [-count=6] <package>...",
+		ShortHelp:  "Run Go benchmarks at HEAD and at a base ref and compare the results.",
+		LongHelp: `Run the selected Go benchmarks at HEAD and at a base ref (main by default), each in a
+temporary git worktree, and print a markdown table comparing the results that can be pasted into a PR.
+
+Differences are tested for significance with the Mann-Whitney U test, like benchstat does, and
+reported as ~ when they are not significant. Uncommitted changes are not benchmarked.
+
+Examples:
+
+  sg bench -bench=BenchmarkSearch ./internal/search/...
+  sg bench -base=HEAD~3 -count=10 ./cmd/frontend/graphqlbackend`,
+		FlagSet: benchFlagSet,
+		Exec:    benchExec,
+	}
+)
+
+func benchExec(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		out.WriteLine(output.Linef("", output.StyleWarning, "No packages specified"))
+		return flag.ErrHelp
+	}
+	if *benchCountFlag < 1 {
+		out.WriteLine(output.Linef("", output.StyleWarning, "-count must be at least 1"))
+		return flag.ErrHelp
+	}
+
+	baseRev, err := run.TrimResult(run.GitCmd("rev-parse", "--verify", *benchBaseFlag+"^{commit}"))
+	if err != nil {
+		return errors.Wrapf(err, "resolving base ref %q", *benchBaseFlag)
+	}
+	headRev, err := run.TrimResult(run.GitCmd("rev-parse", "--verify", "HEAD^{commit}"))
+	if err != nil {
+		return errors.Wrap(err, "resolving HEAD")
+	}
+
+	baseSet, err := runBenchmarksAt(ctx, *benchBaseFlag, baseRev, args)
+	if err != nil {
+		return err
+	}
+	headSet, err := runBenchmarksAt(ctx, "HEAD", headRev, args)
+	if err != nil {
+		return err
+	}
+
+	cmps := bench.Compare(baseSet, headSet, *benchAlphaFlag)
+	if len(cmps) == 0 {
+		out.WriteLine(output.Linef(output.EmojiWarning, output.StyleWarning, "No benchmark ran at both %s and HEAD", *benchBaseFlag))
+		return nil
+	}
+	return bench.WriteMarkdown(os.Stdout, *benchBaseFlag, "HEAD", cmps)
+}
+
+// runBenchmarksAt runs the benchmarks of the given packages at the given
+// revision, in a temporary git worktree.
+func runBenchmarksAt(ctx context.Context, name, rev string, pkgs []string) (*bench.Set, error) {
+	dir, err := os.MkdirTemp("", "sg-bench-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	if _, err := run.GitCmd("worktree", "add", "--detach", dir, rev); err != nil {
+		return nil, errors.Wrapf(err, "creating worktree for %s", name)
+	}
+	defer func() {
+		if _, err := run.GitCmd("worktree", "remove", "--force", dir); err != nil {
+			out.WriteLine(output.Linef(output.EmojiWarning, output.StyleWarning, "Failed to remove worktree %s: %s", dir, err))
+		}
+	}()
+
+	goArgs := []string{"test", "-run=^$", "-bench=" + *benchRegexpFlag, "-count=" + strconv.Itoa(*benchCountFlag)}
+	if *benchBenchtimeFlag != "" {
+		goArgs = append(goArgs, "-benchtime="+*benchBenchtimeFlag)
+	}
+	goArgs = append(goArgs, pkgs...)
+
+	pending := out.Pending(output.Linef("", output.StylePending, "Running benchmarks at %s (%.7s)...", name, rev))
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "go", goArgs...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		pending.Complete(output.Linef(output.EmojiFailure, output.StyleWarning, "Running benchmarks at %s failed: %s", name, err))
+		out.Write(stdout.String() + stderr.String())
+		return nil, errors.Wrapf(err, "running benchmarks at %s", name)
+	}
+
+	set, err := bench.Parse(&stdout)
+	if err != nil {
+		pending.Destroy()
+		return nil, err
+	}
+	pending.Complete(output.Linef(output.EmojiSuccess, output.StyleSuccess, "Ran benchmarks at %s", name))
+	return set, nil
+}
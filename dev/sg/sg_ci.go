@@ -17,6 +17,7 @@ import (
 	"github.com/peterbourgon/ff/v3/ffcli"
 
 	"github.com/sourcegraph/sourcegraph/dev/sg/internal/bk"
+	"github.com/sourcegraph/sourcegraph/dev/sg/internal/github"
 	"github.com/sourcegraph/sourcegraph/dev/sg/internal/loki"
 	"github.com/sourcegraph/sourcegraph/dev/sg/internal/open"
 	"github.com/sourcegraph/sourcegraph/dev/sg/internal/run"
@@ -41,6 +42,12 @@ var (
 	ciStatusFlagSet    = flag.NewFlagSet("sg ci status", flag.ExitOnError)
 	ciStatusBranchFlag = ciStatusFlagSet.String("branch", "", "Branch name of build to check build status for (defaults to current branch)")
 	ciStatusWaitFlag   = ciStatusFlagSet.Bool("wait", false, "Wait by blocking until the build is finished.")
+
+	ciBisectFlagSet         = flag.NewFlagSet("sg ci bisect", flag.ExitOnError)
+	ciBisectStepFlag        = ciBisectFlagSet.String("step", "", "Step key or part of the name of the job to bisect (required).")
+	ciBisectBranchFlag      = ciBisectFlagSet.String("branch", "main", "Branch whose builds to walk.")
+	ciBisectLimitFlag       = ciBisectFlagSet.Int("limit", 100, "Maximum number of recent builds to inspect.")
+	ciBisectCreateIssueFlag = ciBisectFlagSet.Bool("create-issue", false, "Create a GitHub issue describing the breakage.")
 )
 
 // get branch from flag or git
@@ -61,7 +68,7 @@ func getCIBranch() (branch string, fromFlag bool, err error) {
 var (
 	ciCommand = &ffcli.Command{
 		Name:       "ci",
-		ShortUsage: "sg ci [preview|status|build|logs]",
+		ShortUsage: "sg ci [preview|status|build|logs|bisect]",
 		ShortHelp:  "Interact with Sourcegraph's continuous integration pipelines",
 		LongHelp: `Interact with Sourcegraph's continuous integration pipelines on Buildkite.
 
@@ -302,10 +309,97 @@ From there, you can start exploring logs with the Grafana explore panel.
 
 				return nil
 			},
+		}, {
+			Name:       "bisect",
+			ShortUsage: "sg ci bisect -step=<step> [-branch=main] [-limit=100] [-create-issue]",
+			ShortHelp:  "Find the build and commits that broke a CI step.",
+			LongHelp: `Walk the recent Buildkite builds of a branch (main by default) to find the first build of the
+current streak of failures of a step, and print the commits between it and the last build in which the step
+passed, with their authors.
+
+The '--step' flag takes either the step key or part of the name of the job, like 'sg ci logs --job'.
+With '--create-issue', a GitHub issue describing the breakage is created in sourcegraph/sourcegraph.
+`,
+			FlagSet: ciBisectFlagSet,
+			Exec:    ciBisectExec,
 		}},
 	}
 )
 
+func ciBisectExec(ctx context.Context, args []string) error {
+	if *ciBisectStepFlag == "" {
+		out.WriteLine(output.Linef("", output.StyleWarning, "No step specified"))
+		return flag.ErrHelp
+	}
+	if *ciBisectLimitFlag < 1 {
+		out.WriteLine(output.Linef("", output.StyleWarning, "-limit must be at least 1"))
+		return flag.ErrHelp
+	}
+
+	client, err := bk.NewClient(ctx, out)
+	if err != nil {
+		return err
+	}
+
+	pending := out.Pending(output.Linef("", output.StylePending, "Fetching the last %d builds of %q...", *ciBisectLimitFlag, *ciBisectBranchFlag))
+	builds, err := client.ListBuilds(ctx, "sourcegraph", *ciBisectBranchFlag, *ciBisectLimitFlag)
+	if err != nil {
+		pending.Destroy()
+		return fmt.Errorf("failed to list builds for branch %q: %w", *ciBisectBranchFlag, err)
+	}
+	pending.Destroy()
+
+	result, err := bk.Bisect(builds, *ciBisectStepFlag)
+	if errors.Is(err, bk.ErrStepPassing) {
+		out.WriteLine(output.Linef(output.EmojiSuccess, output.StyleSuccess,
+			"Step %q passes in the most recent build of %q, nothing to bisect.", *ciBisectStepFlag, *ciBisectBranchFlag))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	report := formatBisectReport(result)
+	out.Write(report)
+
+	if *ciBisectCreateIssueFlag {
+		title := fmt.Sprintf("CI: %q failing on %s since build #%d", *ciBisectStepFlag, *ciBisectBranchFlag, *result.FirstFailed.Number)
+		issueURL, err := github.CreateIssue(ctx, out, "sourcegraph/sourcegraph", title, report, nil)
+		if err != nil {
+			return err
+		}
+		out.WriteLine(output.Linef(output.EmojiSuccess, output.StyleSuccess, "Created issue: %s", issueURL))
+	}
+	return nil
+}
+
+// formatBisectReport renders the result of a bisection as markdown, so that it can be
+// used both in the terminal and as the body of a GitHub issue.
+func formatBisectReport(result *bk.BisectResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Step %q has been failing in %d consecutive builds.\n\n", *ciBisectStepFlag, len(result.Failed))
+	fmt.Fprintf(&b, "- First failing build: [#%d](%s) at %s\n", *result.FirstFailed.Number, *result.FirstFailed.WebURL, *result.FirstFailed.Commit)
+	if result.LastPassed == nil {
+		fmt.Fprintf(&b, "- No passing build found in the last %d builds, run with a higher '--limit' to find the start of the breakage.\n", *ciBisectLimitFlag)
+		return b.String()
+	}
+	fmt.Fprintf(&b, "- Last passing build: [#%d](%s) at %s\n", *result.LastPassed.Number, *result.LastPassed.WebURL, *result.LastPassed.Commit)
+	fmt.Fprintf(&b, "- Diff: https://github.com/sourcegraph/sourcegraph/compare/%.12s...%.12s\n\n", *result.LastPassed.Commit, *result.FirstFailed.Commit)
+
+	// The breakage was introduced by one of the commits of the first failing build that
+	// are not in the last passing one.
+	commits, err := run.TrimResult(run.GitCmd("log", "--format=- %h %s (%an <%ae>)",
+		fmt.Sprintf("%s..%s", *result.LastPassed.Commit, *result.FirstFailed.Commit)))
+	if err != nil {
+		fmt.Fprintf(&b, "Could not list the commits in the range, try running 'git fetch origin %s' first.\n", *ciBisectBranchFlag)
+		return b.String()
+	}
+	b.WriteString("Commits in the range:\n\n")
+	b.WriteString(commits)
+	b.WriteString("\n")
+	return b.String()
+}
+
 func allLinesPrefixed(lines []string, match string) bool {
 	for _, l := range lines {
 		if !strings.HasPrefix(l, match) {
@@ -4,20 +4,28 @@ import (
 	"context"
 	"flag"
 	"os"
+	"sort"
 
+	"github.com/cockroachdb/errors"
 	"github.com/peterbourgon/ff/v3/ffcli"
 
 	"github.com/sourcegraph/sourcegraph/dev/sg/internal/run"
+	"github.com/sourcegraph/sourcegraph/lib/output"
 )
 
 var (
 	doctorFlagSet = flag.NewFlagSet("sg doctor", flag.ExitOnError)
+	doctorFixFlag = doctorFlagSet.Bool("fix", false, "Offer to run the fix of every failed check that has one.")
 	doctorCommand = &ffcli.Command{
 		Name:       "doctor",
-		ShortUsage: "sg doctor",
+		ShortUsage: "sg doctor [-fix]",
 		ShortHelp:  "Run the checks defined in the sg config file.",
 		LongHelp: `Run the checks defined in the sg config file to make sure your system is healthy.
 
+With -fix, sg offers to run the "fix:" command of every failed check that has one, e.g. to install a
+missing tool or start a missing database, asking for confirmation before each, and runs the check
+again afterwards.
+
 See the "checks:" in the configuration file.`,
 		FlagSet: doctorFlagSet,
 		Exec:    doctorExec,
@@ -35,6 +43,71 @@ func doctorExec(ctx context.Context, args []string) error {
 	for _, c := range globalConf.Checks {
 		checks = append(checks, c)
 	}
-	_, err := run.Checks(ctx, globalConf.Env, checks...)
-	return err
+	sort.Slice(checks, func(i, j int) bool { return checks[i].Name < checks[j].Name })
+
+	failed, err := run.FailedChecks(ctx, globalConf.Env, checks...)
+	if err != nil || len(failed) == 0 {
+		return err
+	}
+	if !*doctorFixFlag {
+		for _, c := range failed {
+			if c.Fix != "" {
+				out.WriteLine(output.Line(output.EmojiLightbulb, output.StyleSuggestion, "Some failed checks can be fixed automatically, run 'sg doctor -fix' to do so."))
+				break
+			}
+		}
+		return nil
+	}
+
+	return fixChecks(ctx, failed)
+}
+
+// fixChecks offers to run the fix of the failed checks, checks them again and
+// prints a summary of what was changed.
+func fixChecks(ctx context.Context, failed []run.Check) error {
+	var summary []output.FancyLine
+	stillFailing := 0
+	for _, c := range failed {
+		if c.Fix == "" {
+			stillFailing++
+			summary = append(summary, output.Linef(output.EmojiFailure, output.StyleWarning, "%s: no automatic fix, %s", c.Name, c.FailMessage))
+			continue
+		}
+
+		out.WriteLine(output.Linef("", output.StyleBold, "Check %q can be fixed by running:", c.Name))
+		out.WriteLine(output.Linef("", output.StyleReset, "%s", c.Fix))
+		out.Writef("Run it?")
+		if !getBool() {
+			stillFailing++
+			summary = append(summary, output.Linef(output.EmojiFailure, output.StyleWarning, "%s: fix skipped", c.Name))
+			continue
+		}
+
+		if err := run.Fix(ctx, globalConf.Env, c); err != nil {
+			stillFailing++
+			summary = append(summary, output.Linef(output.EmojiFailure, output.StyleWarning, "%s: fix failed: %s", c.Name, err))
+			continue
+		}
+		stillFailed, err := run.FailedChecks(ctx, globalConf.Env, c)
+		if err != nil {
+			return err
+		}
+		if len(stillFailed) > 0 {
+			stillFailing++
+			summary = append(summary, output.Linef(output.EmojiFailure, output.StyleWarning, "%s: fix ran, but the check still fails", c.Name))
+			continue
+		}
+		summary = append(summary, output.Linef(output.EmojiSuccess, output.StyleSuccess, "%s: fixed", c.Name))
+	}
+
+	block := out.Block(output.Line("", output.StyleBold, "Summary:"))
+	for _, line := range summary {
+		block.WriteLine(line)
+	}
+	block.Close()
+
+	if stillFailing > 0 {
+		return errors.Newf("%d checks are still failing", stillFailing)
+	}
+	return nil
 }
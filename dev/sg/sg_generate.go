@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/peterbourgon/ff/v3/ffcli"
+
+	"github.com/sourcegraph/sourcegraph/dev/sg/internal/generate"
+	"github.com/sourcegraph/sourcegraph/dev/sg/internal/run"
+	"github.com/sourcegraph/sourcegraph/dev/sg/root"
+	"github.com/sourcegraph/sourcegraph/lib/output"
+)
+
+// generateTargets are the code generators run by `sg generate`, in the order
+// they are listed in its help.
+var generateTargets = []generate.Target{
+	{
+		Name:   "schema",
+		Help:   "Go types of the site and code host configuration JSON schemas",
+		Inputs: []string{":(glob)schema/*.json", ":(glob)schema/*.go", "schema/gen.sh"},
+		Cmd:    "go generate ./schema",
+	},
+	{
+		Name:   "batches-schema",
+		Help:   "Go string constants of the batch spec and changeset spec JSON schemas in ./lib",
+		Inputs: []string{"schema/batch_spec.schema.json", "schema/changeset_spec.schema.json", ":(glob)lib/batches/schema/*.go"},
+		Cmd:    "cd lib && go generate ./batches/schema",
+	},
+	{
+		Name: "go",
+		Help: "go:generate directives of all other packages (mocks, stringers, ...), followed by goimports and go mod tidy",
+		Deps: []string{"schema"},
+		Inputs: []string{
+			":(glob)**/*.go",
+			"go.mod",
+			"go.sum",
+			"dev/mockgen.sh",
+			":(exclude,glob)lib/**",
+			":(exclude,glob)monitoring/**",
+			":(exclude,glob)doc/cli/references/**",
+		},
+		Cmd: `go list ./... | grep -v -e '/doc/cli/references' -e '/sourcegraph/monitoring' -e '/sourcegraph/schema$' -e '/sourcegraph/internal/database$' | xargs go generate -x &&
+GOBIN="$PWD/.bin" go install golang.org/x/tools/cmd/goimports && ./.bin/goimports -w . &&
+go mod tidy`,
+	},
+	{
+		Name:   "database-schema",
+		Help:   "internal/database/schema.md from the migrations (requires a running Postgres)",
+		Inputs: []string{":(glob)migrations/**", ":(glob)internal/database/schemadoc/**", "internal/database/schema.md"},
+		Cmd:    "go generate ./internal/database",
+	},
+	{
+		Name: "monitoring",
+		Help: "Grafana dashboards, Prometheus rules and observability docs from the monitoring definitions",
+		Inputs: []string{
+			":(glob)monitoring/**",
+			":(glob)doc/admin/observability/alert_solutions.md",
+			":(glob)doc/admin/observability/dashboards.md",
+			":(glob)docker-images/grafana/config/provisioning/dashboards/sourcegraph/**",
+			":(glob)docker-images/prometheus/config/*_rules.yml",
+		},
+		Cmd: "go generate ./monitoring",
+	},
+	{
+		Name: "graphql",
+		Help: "TypeScript types of the GraphQL schema and operations, the JSON schemas and CSS modules",
+		Inputs: []string{
+			":(glob)cmd/frontend/graphqlbackend/*.graphql",
+			":(glob)schema/*.json",
+			":(glob)client/**/*.ts",
+			":(glob)client/**/*.tsx",
+			":(glob)client/**/*.scss",
+			":(exclude,glob)client/**/*.d.ts",
+			"package.json",
+			"yarn.lock",
+		},
+		Cmd: "yarn generate",
+	},
+	{
+		Name:     "cli-docs",
+		Help:     "src-cli reference documentation (only when named, since it fetches and builds src-cli)",
+		Inputs:   []string{":(glob)doc/cli/references/**"},
+		Cmd:      "go generate ./doc/cli/references",
+		Explicit: true,
+	},
+}
+
+var (
+	generateFlagSet   = flag.NewFlagSet("sg generate", flag.ExitOnError)
+	generateCheckFlag = generateFlagSet.Bool("check", false, "Run the targets even if they are up to date and fail if they changed any file, like CI does.")
+	generateForceFlag = generateFlagSet.Bool("force", false, "Run the targets even if their inputs did not change since they last ran.")
+
+	generateCommand = &ffcli.Command{
+		Name:       "generate",
+		ShortUsage: "sg generate [-check] [-force] [target...]",
+		ShortHelp:  "Run the code generators of the repository.",
+		LongHelp:   generateLongHelp(),
+		FlagSet:    generateFlagSet,
+		Exec:       generateExec,
+	}
+)
+
+func generateLongHelp() string {
+	var b strings.Builder
+	b.WriteString(`Run the code generators of the repository in dependency order. If targets are given, only they
+and the targets they depend on are run.
+
+A target is skipped if the hash of its inputs is the same as when it last ran successfully. With
+-check, all targets run and sg fails if they changed any file, which means the generated files
+committed in the repository are stale. Like the CI check, -check leaves the regenerated files in the
+working tree.
+
+Targets:
+`)
+	for _, t := range generateTargets {
+		fmt.Fprintf(&b, "\n  %-16s %s", t.Name, t.Help)
+	}
+	return b.String()
+}
+
+func generateExec(ctx context.Context, args []string) error {
+	plan, err := generate.Plan(generateTargets, args)
+	if err != nil {
+		out.WriteLine(output.Linef("", output.StyleWarning, "%s", err))
+		return flag.ErrHelp
+	}
+
+	repoRoot, err := root.RepositoryRoot()
+	if err != nil {
+		return err
+	}
+	homePath, err := root.GetSGHomePath()
+	if err != nil {
+		return err
+	}
+	cache, err := generate.LoadCache(filepath.Join(homePath, "generate-cache.json"))
+	if err != nil {
+		return err
+	}
+
+	var before generate.Snapshot
+	if *generateCheckFlag {
+		if before, err = generate.TakeSnapshot(ctx, repoRoot); err != nil {
+			return err
+		}
+	}
+
+	for _, t := range plan {
+		hash, err := generate.Hash(ctx, repoRoot, t)
+		if err != nil {
+			return err
+		}
+		if !*generateCheckFlag && !*generateForceFlag && cache.UpToDate(t.Name, hash) {
+			out.WriteLine(output.Linef(output.EmojiSuccess, output.StyleSuggestion, "%s is up to date", t.Name))
+			continue
+		}
+
+		pending := out.Pending(output.Linef("", output.StylePending, "Generating %s...", t.Name))
+		cmdOut, err := run.BashInRoot(ctx, t.Cmd, os.Environ())
+		if err != nil {
+			pending.Complete(output.Linef(output.EmojiFailure, output.StyleWarning, "Generating %s failed", t.Name))
+			out.Write(cmdOut)
+			return errors.Wrapf(err, "generating %s", t.Name)
+		}
+		pending.Complete(output.Linef(output.EmojiSuccess, output.StyleSuccess, "Generated %s", t.Name))
+
+		// The outputs of the target are among its inputs, so hash them again.
+		if hash, err = generate.Hash(ctx, repoRoot, t); err != nil {
+			return err
+		}
+		cache.Set(t.Name, hash)
+		if err := cache.Save(); err != nil {
+			return err
+		}
+	}
+
+	if !*generateCheckFlag {
+		return nil
+	}
+	after, err := generate.TakeSnapshot(ctx, repoRoot)
+	if err != nil {
+		return err
+	}
+	changed := generate.Changed(before, after)
+	if len(changed) == 0 {
+		out.WriteLine(output.Line(output.EmojiSuccess, output.StyleSuccess, "Generated files are up to date"))
+		return nil
+	}
+	block := out.Block(output.Linef(output.EmojiFailure, output.StyleWarning, "%d generated files are stale:", len(changed)))
+	for _, file := range changed {
+		block.Write(file)
+	}
+	block.Close()
+	out.WriteLine(output.Line("", output.StyleSuggestion, "Commit the regenerated files to fix this."))
+	return errors.New("generated files are stale")
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/peterbourgon/ff/v3/ffcli"
+
+	"github.com/sourcegraph/sourcegraph/dev/sg/internal/images"
+	"github.com/sourcegraph/sourcegraph/dev/sg/internal/run"
+	"github.com/sourcegraph/sourcegraph/dev/sg/root"
+	ciimages "github.com/sourcegraph/sourcegraph/enterprise/dev/ci/images"
+	"github.com/sourcegraph/sourcegraph/lib/output"
+)
+
+var (
+	imagesBuildFlagSet      = flag.NewFlagSet("sg images build", flag.ExitOnError)
+	imagesBuildBaseFlag     = imagesBuildFlagSet.String("base", "main", "The git ref local changes are compared against to find the modified services.")
+	imagesBuildAllFlag      = imagesBuildFlagSet.Bool("all", false, "Build the images of all services.")
+	imagesBuildPreBuildFlag = imagesBuildFlagSet.Bool("pre-build", true, "Run the pre-build.sh script of the services that have one, e.g. to build the web app.")
+	imagesBuildCommand      = &ffcli.Command{
+		Name:       "build",
+		ShortUsage: "sg images build [-base=main] [-all] [service...]",
+		ShortHelp:  "Build the Docker images of the services modified locally.",
+		LongHelp: `Build the Docker images of the given services, or of the services affected by the changes made
+since the merge base of HEAD and -base, including uncommitted changes, with the build scripts and build
+arguments used by CI.
+
+Images are tagged sourcegraph/<service>:<tag>, where the tag identifies the state of the working tree.`,
+		FlagSet: imagesBuildFlagSet,
+		Exec:    imagesBuildExec,
+	}
+
+	imagesPushFlagSet           = flag.NewFlagSet("sg images push", flag.ExitOnError)
+	imagesPushBaseFlag          = imagesPushFlagSet.String("base", "main", "The git ref local changes are compared against to find the modified services.")
+	imagesPushAllFlag           = imagesPushFlagSet.Bool("all", false, "Push the images of all services.")
+	imagesPushToFlag            = imagesPushFlagSet.String("to", "registry", "Where to push the images: registry, kind, k3d or compose.")
+	imagesPushClusterFlag       = imagesPushFlagSet.String("cluster", "", "The name of the kind or k3d cluster to load the images into. Defaults to the default cluster.")
+	imagesPushComposeFileFlag   = imagesPushFlagSet.String("compose-file", "docker-compose.yaml", "The docker-compose file of the deployment to run the images in, with -to=compose.")
+	imagesPushComposeOutputFlag = imagesPushFlagSet.String("compose-override", "", "The docker-compose override file to write, with -to=compose. Defaults to docker-compose.override.yaml next to -compose-file.")
+	imagesPushCommand           = &ffcli.Command{
+		Name:       "push",
+		ShortUsage: "sg images push [-to=registry|kind|k3d|compose] [-all] [service...]",
+		ShortHelp:  "Push the images built by `sg images build` to a registry or a local deployment.",
+		LongHelp: `Push the images of the given services, or of the services modified locally, built from the
+current working tree by 'sg images build':
+
+  -to=registry  tag and push them to the dev registry (` + ciimages.SourcegraphDockerDevRegistry + `)
+  -to=kind      load them into a kind cluster
+  -to=k3d       import them into a k3d cluster
+  -to=compose   write a docker-compose override file running them instead of the released images`,
+		FlagSet: imagesPushFlagSet,
+		Exec:    imagesPushExec,
+	}
+
+	imagesFlagSet = flag.NewFlagSet("sg images", flag.ExitOnError)
+	imagesCommand = &ffcli.Command{
+		Name:       "images",
+		ShortUsage: "sg images <command>",
+		ShortHelp:  "Build the Docker images of locally modified services and deploy them locally.",
+		FlagSet:    imagesFlagSet,
+		Exec: func(ctx context.Context, args []string) error {
+			return flag.ErrHelp
+		},
+		Subcommands: []*ffcli.Command{
+			imagesBuildCommand,
+			imagesPushCommand,
+		},
+	}
+)
+
+func imagesBuildExec(ctx context.Context, args []string) error {
+	repoRoot, err := root.RepositoryRoot()
+	if err != nil {
+		return err
+	}
+	services, err := selectImageServices(ctx, repoRoot, args, *imagesBuildAllFlag, *imagesBuildBaseFlag)
+	if err != nil || len(services) == 0 {
+		return err
+	}
+	tag, err := images.WorkingTreeTag(ctx, repoRoot)
+	if err != nil {
+		return err
+	}
+	commit, err := run.TrimResult(run.GitCmd("rev-parse", "HEAD"))
+	if err != nil {
+		return err
+	}
+
+	for _, s := range services {
+		// These are the variables the build scripts of the candidate image
+		// steps get in CI.
+		env := append(os.Environ(),
+			"DOCKER_BUILDKIT=1",
+			"IMAGE="+s.Image(tag),
+			"VERSION="+tag,
+			"COMMIT_SHA="+commit,
+			"DATE="+time.Now().UTC().Format(time.RFC3339),
+		)
+
+		scripts := []string{s.BuildScript()}
+		if preBuild := s.PreBuildScript(repoRoot); preBuild != "" && *imagesBuildPreBuildFlag {
+			scripts = append([]string{preBuild}, scripts...)
+		}
+		for _, script := range scripts {
+			pending := out.Pending(output.Linef("", output.StylePending, "Running %s...", script))
+			cmdOut, err := run.BashInRoot(ctx, "./"+script, env)
+			if err != nil {
+				pending.Complete(output.Linef(output.EmojiFailure, output.StyleWarning, "%s failed", script))
+				out.Write(cmdOut)
+				return errors.Wrapf(err, "building %s", s.Name)
+			}
+			pending.Destroy()
+		}
+		out.WriteLine(output.Linef(output.EmojiSuccess, output.StyleSuccess, "Built %s", s.Image(tag)))
+	}
+	return nil
+}
+
+func imagesPushExec(ctx context.Context, args []string) error {
+	switch *imagesPushToFlag {
+	case "registry", "kind", "k3d", "compose":
+	default:
+		out.WriteLine(output.Linef("", output.StyleWarning, "Unknown -to %q", *imagesPushToFlag))
+		return flag.ErrHelp
+	}
+
+	repoRoot, err := root.RepositoryRoot()
+	if err != nil {
+		return err
+	}
+	services, err := selectImageServices(ctx, repoRoot, args, *imagesPushAllFlag, *imagesPushBaseFlag)
+	if err != nil || len(services) == 0 {
+		return err
+	}
+	tag, err := images.WorkingTreeTag(ctx, repoRoot)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range services {
+		if err := exec.CommandContext(ctx, "docker", "image", "inspect", s.Image(tag)).Run(); err != nil {
+			return errors.Newf("image %s does not exist, build it with 'sg images build %s' first", s.Image(tag), s.Name)
+		}
+	}
+
+	if *imagesPushToFlag == "compose" {
+		return writeComposeOverride(services, tag)
+	}
+
+	for _, s := range services {
+		var cmds [][]string
+		switch *imagesPushToFlag {
+		case "registry":
+			devImage := ciimages.DevRegistryImage(s.Name, tag)
+			cmds = [][]string{{"docker", "tag", s.Image(tag), devImage}, {"docker", "push", devImage}}
+		case "kind":
+			cmd := []string{"kind", "load", "docker-image", s.Image(tag)}
+			if *imagesPushClusterFlag != "" {
+				cmd = append(cmd, "--name", *imagesPushClusterFlag)
+			}
+			cmds = [][]string{cmd}
+		case "k3d":
+			cmd := []string{"k3d", "image", "import", s.Image(tag)}
+			if *imagesPushClusterFlag != "" {
+				cmd = append(cmd, "--cluster", *imagesPushClusterFlag)
+			}
+			cmds = [][]string{cmd}
+		}
+
+		pending := out.Pending(output.Linef("", output.StylePending, "Pushing %s to %s...", s.Image(tag), *imagesPushToFlag))
+		for _, args := range cmds {
+			if cmdOut, err := exec.CommandContext(ctx, args[0], args[1:]...).CombinedOutput(); err != nil {
+				pending.Complete(output.Linef(output.EmojiFailure, output.StyleWarning, "'%s' failed", strings.Join(args, " ")))
+				out.Write(string(cmdOut))
+				return errors.Wrapf(err, "pushing %s", s.Name)
+			}
+		}
+		pending.Complete(output.Linef(output.EmojiSuccess, output.StyleSuccess, "Pushed %s to %s", s.Image(tag), *imagesPushToFlag))
+	}
+	return nil
+}
+
+func writeComposeOverride(services []images.Service, tag string) error {
+	composeFile, err := os.ReadFile(*imagesPushComposeFileFlag)
+	if err != nil {
+		return err
+	}
+	local := make(map[string]string, len(services))
+	for _, s := range services {
+		local[s.Name] = s.Image(tag)
+	}
+	override, err := images.ComposeOverride(composeFile, local)
+	if err != nil {
+		return err
+	}
+
+	path := *imagesPushComposeOutputFlag
+	if path == "" {
+		path = filepath.Join(filepath.Dir(*imagesPushComposeFileFlag), "docker-compose.override.yaml")
+	}
+	if err := os.WriteFile(path, override, 0644); err != nil {
+		return err
+	}
+	out.WriteLine(output.Linef(output.EmojiSuccess, output.StyleSuccess, "Wrote %s, run 'docker-compose up -d' to use the local images", path))
+	return nil
+}
+
+// selectImageServices returns the services named in args, all services or the
+// services affected by the changes made since the merge base of HEAD and base.
+func selectImageServices(ctx context.Context, repoRoot string, args []string, all bool, base string) ([]images.Service, error) {
+	if all {
+		return images.Services(repoRoot), nil
+	}
+	if len(args) > 0 {
+		services := make([]images.Service, 0, len(args))
+		for _, name := range args {
+			s, err := images.Lookup(repoRoot, name)
+			if err != nil {
+				return nil, err
+			}
+			services = append(services, s)
+		}
+		return services, nil
+	}
+
+	changed, err := images.ChangedFiles(ctx, repoRoot, base)
+	if err != nil {
+		return nil, err
+	}
+	pending := out.Pending(output.Linef("", output.StylePending, "Finding the services affected by %d changed files...", len(changed)))
+	var services []images.Service
+	for _, s := range images.Services(repoRoot) {
+		var pkgDirs []string
+		if s.Go {
+			if pkgDirs, err = images.GoPackageDirs(ctx, repoRoot, s); err != nil {
+				pending.Destroy()
+				return nil, err
+			}
+		}
+		if images.Affected(s, changed, pkgDirs, s.PreBuildScript(repoRoot) != "") {
+			services = append(services, s)
+		}
+	}
+	pending.Destroy()
+
+	if len(services) == 0 {
+		out.WriteLine(output.Linef("", output.StyleSuggestion, "No service is affected by the changes since %s.", base))
+		return nil, nil
+	}
+	names := make([]string, 0, len(services))
+	for _, s := range services {
+		names = append(names, s.Name)
+	}
+	out.WriteLine(output.Line("", output.StyleBold, fmt.Sprintf("Modified services: %s", strings.Join(names, ", "))))
+	return services, nil
+}
@@ -18,9 +18,10 @@ import (
 var (
 	migrationAddFlagSet          = flag.NewFlagSet("sg migration add", flag.ExitOnError)
 	migrationAddDatabaseNameFlag = migrationAddFlagSet.String("db", db.DefaultDatabase.Name, "The target database instance.")
+	migrationAddValidateFlag     = migrationAddFlagSet.Bool("validate", false, "Instead of adding a migration, validate that the down migration of the last migration reverts its up migration, using a throwaway database.")
 	migrationAddCommand          = &ffcli.Command{
 		Name:       "add",
-		ShortUsage: fmt.Sprintf("sg migration add [-db=%s] <name>", db.DefaultDatabase.Name),
+		ShortUsage: fmt.Sprintf("sg migration add [-db=%s] <name> | sg migration add [-db=%s] -validate", db.DefaultDatabase.Name, db.DefaultDatabase.Name),
 		ShortHelp:  "Add a new migration file",
 		FlagSet:    migrationAddFlagSet,
 		Exec:       migrationAddExec,
@@ -108,6 +109,10 @@ func constructMigrationSubcmdLongHelp() string {
 }
 
 func migrationAddExec(ctx context.Context, args []string) error {
+	if *migrationAddValidateFlag {
+		return migrationValidateDownExec(args)
+	}
+
 	if len(args) == 0 {
 		out.WriteLine(output.Linef("", output.StyleWarning, "No migration name specified"))
 		return flag.ErrHelp
@@ -137,9 +142,28 @@ func migrationAddExec(ctx context.Context, args []string) error {
 	block.Writef("Down migration: %s", downFile)
 	block.Close()
 
+	out.WriteLine(output.Linef("", output.StyleSuggestion, "Once written, run 'sg migration add -db=%s -validate' to check that the down migration reverts the up migration.", databaseName))
 	return nil
 }
 
+func migrationValidateDownExec(args []string) error {
+	if len(args) != 0 {
+		out.WriteLine(output.Linef("", output.StyleWarning, "ERROR: too many arguments"))
+		return flag.ErrHelp
+	}
+
+	var (
+		databaseName = *migrationAddDatabaseNameFlag
+		database, ok = db.DatabaseByName(databaseName)
+	)
+	if !ok {
+		out.WriteLine(output.Linef("", output.StyleWarning, "ERROR: database %q not found :(", databaseName))
+		return flag.ErrHelp
+	}
+
+	return migration.RunValidateDown(database)
+}
+
 func migrationUpExec(ctx context.Context, args []string) error {
 	if len(args) != 0 {
 		out.WriteLine(output.Linef("", output.StyleWarning, "ERROR: too many arguments"))
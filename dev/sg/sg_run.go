@@ -8,6 +8,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/cockroachdb/errors"
 	"github.com/peterbourgon/ff/v3/ffcli"
 
 	"github.com/sourcegraph/sourcegraph/dev/sg/internal/run"
@@ -15,10 +16,11 @@ import (
 )
 
 var (
-	runFlagSet = flag.NewFlagSet("sg run", flag.ExitOnError)
-	runCommand = &ffcli.Command{
+	runFlagSet       = flag.NewFlagSet("sg run", flag.ExitOnError)
+	runAutoPortsFlag = runFlagSet.Bool("auto-ports", false, "Assign free ports to the commands whose declared ports are already in use, instead of failing.")
+	runCommand       = &ffcli.Command{
 		Name:       "run",
-		ShortUsage: "sg run <command>...",
+		ShortUsage: "sg run [-auto-ports] <command>...",
 		ShortHelp:  "Run the given commands.",
 		LongHelp:   constructRunCmdLongHelp(),
 		FlagSet:    runFlagSet,
@@ -48,7 +50,23 @@ func runExec(ctx context.Context, args []string) error {
 		cmds = append(cmds, cmd)
 	}
 
-	return run.Commands(ctx, globalConf.Env, *verboseFlag, cmds...)
+	env, cmds, assignments, err := run.AssignPorts(ctx, globalConf.Env, *runAutoPortsFlag, cmds...)
+	if err != nil {
+		var conflicts run.PortConflictsErr
+		if errors.As(err, &conflicts) {
+			for _, c := range conflicts.Conflicts {
+				out.WriteLine(output.Linef(output.EmojiFailure, output.StyleWarning, "%s", c))
+			}
+			out.WriteLine(output.Line(output.EmojiLightbulb, output.StyleSuggestion, "Stop the processes using these ports, or run 'sg run -auto-ports' to use free ports instead."))
+			os.Exit(1)
+		}
+		return err
+	}
+	for _, a := range assignments {
+		out.WriteLine(output.Linef(output.EmojiInfo, output.StyleSuggestion, "%s", a))
+	}
+
+	return run.Commands(ctx, env, *verboseFlag, cmds...)
 }
 func constructRunCmdLongHelp() string {
 	var out strings.Builder
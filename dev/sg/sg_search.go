@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/peterbourgon/ff/v3/ffcli"
+
+	"github.com/sourcegraph/sourcegraph/dev/sg/internal/open"
+	"github.com/sourcegraph/sourcegraph/dev/sg/internal/search"
+	"github.com/sourcegraph/sourcegraph/dev/sg/internal/secrets"
+	"github.com/sourcegraph/sourcegraph/lib/output"
+)
+
+// searchInstances are the instances that can be referred to by name with
+// `sg search -instance`.
+var searchInstances = map[string]string{
+	"local":   "http://localhost:3080",
+	"dot-com": "https://sourcegraph.com",
+	"k8s":     "https://k8s.sgdev.org",
+}
+
+var (
+	searchFlagSet         = flag.NewFlagSet("sg search", flag.ExitOnError)
+	searchInstanceFlag    = searchFlagSet.String("instance", "local", "The instance to search: local, dot-com, k8s or the URL of any Sourcegraph instance.")
+	searchPatternTypeFlag = searchFlagSet.String("t", "literal", "The pattern type of the query if it has no patterntype: filter: literal, regexp or structural.")
+	searchDisplayFlag     = searchFlagSet.Int("display", 50, "The maximum number of results to print. All results are printed if it is negative.")
+
+	searchCommand = &ffcli.Command{
+		Name:       "search",
+		ShortUsage: "sg search [-instance=local] [-t=literal] [-display=50] <query>",
+		ShortHelp:  "Run a search query against a local or remote Sourcegraph instance.",
+		LongHelp: `Run a search query with the streaming search API of a Sourcegraph instance and print the results
+as they are streamed, with the matches highlighted. By default the query is run against the local dev
+instance started by 'sg start'.
+
+The first time an instance requires authentication, sg asks for an access token for it, which is
+stored in the sg secrets.
+
+Examples:
+
+  sg search 'repo:^github\.com/sourcegraph/sourcegraph$ func main'
+  sg search -instance=dot-com -t=regexp 'lang:go errors\.Newf\('
+  sg search -instance=https://sourcegraph.example.com 'type:commit fix'`,
+		FlagSet: searchFlagSet,
+		Exec:    searchExec,
+	}
+)
+
+// searchSecrets are the access tokens of the instances, by URL.
+type searchSecrets struct {
+	Tokens map[string]string `json:"tokens"`
+}
+
+func searchExec(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		out.WriteLine(output.Linef("", output.StyleWarning, "No query specified"))
+		return flag.ErrHelp
+	}
+	query := strings.Join(args, " ")
+
+	endpoint, ok := searchInstances[*searchInstanceFlag]
+	if !ok {
+		if !strings.HasPrefix(*searchInstanceFlag, "http://") && !strings.HasPrefix(*searchInstanceFlag, "https://") {
+			out.WriteLine(output.Linef("", output.StyleWarning, "Unknown instance %q", *searchInstanceFlag))
+			return flag.ErrHelp
+		}
+		endpoint = strings.TrimSuffix(*searchInstanceFlag, "/")
+	}
+
+	sec := secrets.FromContext(ctx)
+	var tokens searchSecrets
+	if err := sec.Get("search", &tokens); err != nil && !errors.Is(err, secrets.ErrSecretNotFound) {
+		return err
+	}
+	if tokens.Tokens == nil {
+		tokens.Tokens = map[string]string{}
+	}
+
+	opts := search.Options{
+		Endpoint:     endpoint,
+		Token:        tokens.Tokens[endpoint],
+		PatternType:  *searchPatternTypeFlag,
+		DisplayLimit: *searchDisplayFlag,
+	}
+	err := streamSearch(ctx, query, opts)
+	if errors.Is(err, search.ErrUnauthorized) {
+		out.WriteLine(output.Linef(output.EmojiLightbulb, output.StylePending,
+			"%s requires an access token. Create one at %s/user/settings/tokens.", endpoint, endpoint))
+		if opts.Token, err = open.Prompt("Paste your token here:"); err != nil {
+			return err
+		}
+		if err = streamSearch(ctx, query, opts); err == nil {
+			tokens.Tokens[endpoint] = opts.Token
+			return sec.PutAndSave("search", tokens)
+		}
+	}
+	return err
+}
+
+func streamSearch(ctx context.Context, query string, opts search.Options) error {
+	out.WriteLine(output.Linef("", output.StyleSuggestion, "Searching %s for %s%s", opts.Endpoint, output.StyleSearchQuery, query))
+
+	var progress search.Progress
+	err := search.Stream(ctx, query, opts, search.Handler{
+		OnMatches: func(matches []search.Match) {
+			for _, m := range matches {
+				out.Write("")
+				for _, line := range search.Render(m) {
+					out.Write(line)
+				}
+			}
+		},
+		OnProgress: func(p search.Progress) {
+			progress = p
+		},
+		OnAlert: func(a search.Alert) {
+			out.WriteLine(output.Linef("", output.StyleSearchAlertTitle, "%s", a.Title))
+			if a.Description != "" {
+				out.WriteLine(output.Linef("", output.StyleSearchAlertDescription, "%s", a.Description))
+			}
+			for _, q := range a.ProposedQueries {
+				out.WriteLine(output.Linef("", output.StyleSearchAlertProposedQuery, "  %s", q.Query))
+			}
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	out.Write("")
+	summary := fmt.Sprintf("%d results in %dms", progress.MatchCount, progress.DurationMs)
+	if progress.RepositoriesCount != nil {
+		summary += fmt.Sprintf(" from %d repositories", *progress.RepositoriesCount)
+	}
+	out.WriteLine(output.Line("", output.StyleBold, summary))
+	for _, s := range progress.Skipped {
+		out.WriteLine(output.Linef(output.EmojiWarning, output.StyleWarning, "%s", s.Title))
+	}
+	return nil
+}
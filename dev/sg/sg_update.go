@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/peterbourgon/ff/v3/ffcli"
+
+	"github.com/sourcegraph/sourcegraph/dev/sg/internal/run"
+	"github.com/sourcegraph/sourcegraph/dev/sg/internal/update"
+	"github.com/sourcegraph/sourcegraph/lib/output"
+)
+
+var (
+	updateFlagSet = flag.NewFlagSet("sg update", flag.ExitOnError)
+	updateYesFlag = updateFlagSet.Bool("yes", false, "Install the update without asking for confirmation.")
+
+	updateCommand = &ffcli.Command{
+		Name:       "update",
+		ShortUsage: "sg update [-yes]",
+		ShortHelp:  "Update sg to the latest release.",
+		LongHelp: `Check for a new release of sg, show the changes made to ./dev/sg since the installed version
+and replace the running sg binary with the new release.
+
+Releases are published to https://github.com/sourcegraph/sg/releases for every change to ./dev/sg on main.`,
+		FlagSet: updateFlagSet,
+		Exec:    updateExec,
+	}
+)
+
+func updateExec(ctx context.Context, args []string) error {
+	pending := out.Pending(output.Line("", output.StylePending, "Checking for a new release of sg..."))
+	manifest, err := update.LatestManifest(ctx)
+	if err != nil {
+		pending.Complete(output.Linef(output.EmojiFailure, output.StyleWarning, "Failed to check for a new release: %s", err))
+		return err
+	}
+	pending.Destroy()
+
+	current := buildRevision()
+	if current == manifest.Commit {
+		out.WriteLine(output.Linef(output.EmojiSuccess, output.StyleSuccess, "sg is up to date (%.12s).", current))
+		return nil
+	}
+
+	out.WriteLine(output.Linef("", output.StyleBold, "A new release of sg is available: %s", manifest.Release))
+	printUpdateChangelog(current, manifest.Commit)
+
+	if !*updateYesFlag {
+		out.Writef("Install it?")
+		if !getBool() {
+			return errors.New("update cancelled")
+		}
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if executable, err = filepath.EvalSymlinks(executable); err != nil {
+		return err
+	}
+
+	pending = out.Pending(output.Linef("", output.StylePending, "Installing sg %s to %s...", manifest.Release, executable))
+	if err := update.Install(ctx, manifest, executable); err != nil {
+		pending.Complete(output.Linef(output.EmojiFailure, output.StyleWarning, "Failed: %s", err))
+		return err
+	}
+	pending.Complete(output.Linef(output.EmojiSuccess, output.StyleSuccess, "Installed sg %s to %s", manifest.Release, executable))
+	return nil
+}
+
+// buildRevision returns the commit sg was built from, or "dev" if it is unknown.
+func buildRevision() string {
+	return strings.TrimPrefix(BuildCommit, "dev-")
+}
+
+// printUpdateChangelog prints the commits made to ./dev/sg between the installed and the
+// new release, falling back to a link to them if they are not in the local repository.
+func printUpdateChangelog(current, next string) {
+	if current == "dev" {
+		out.WriteLine(output.Line("", output.StyleSuggestion, "The installed sg is a dev build, so the changes since it are unknown."))
+		return
+	}
+
+	changelog, err := run.TrimResult(run.GitCmd("log", "--format=- %h %s (%an)", fmt.Sprintf("%s..%s", current, next), "--", "./dev/sg"))
+	if err != nil {
+		out.WriteLine(output.Linef("", output.StyleSuggestion,
+			"See the changes since the installed version at https://github.com/sourcegraph/sourcegraph/compare/%s...%s", current, next))
+		return
+	}
+	block := out.Block(output.Line("", output.StyleBold, "Changes since the installed version:"))
+	block.Write(changelog)
+	block.Close()
+}
@@ -116,6 +116,7 @@ func (c *Config) ClientOptions() apiclient.Options {
 		// Be unique but also descriptive.
 		ExecutorName:      hn + "-" + uuid.New().String(),
 		ExecutorHostname:  hn,
+		ExecutorInfo:      apiclient.NewExecutorInfoFunc(hn),
 		PathPrefix:        "/.executors/queue",
 		EndpointOptions:   c.EndpointOptions(),
 		BaseClientOptions: c.BaseClientOptions(),
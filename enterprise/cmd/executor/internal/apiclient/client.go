@@ -3,6 +3,7 @@ package apiclient
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"path/filepath"
@@ -33,6 +34,10 @@ type Options struct {
 	// PathPrefix is the path prefix added to all requests.
 	PathPrefix string
 
+	// ExecutorInfo, if set, is called on every heartbeat to describe the executor and
+	// the resources of the system it is running on.
+	ExecutorInfo func() executor.ExecutorInfo
+
 	// EndpointOptions configures the target request URL.
 	EndpointOptions EndpointOptions
 
@@ -188,6 +193,7 @@ func (c *Client) Canceled(ctx context.Context, queueName string) (canceledIDs []
 func (c *Client) Ping(ctx context.Context, queueName string, jobIDs []int) (err error) {
 	req, err := c.makeRequest("POST", fmt.Sprintf("%s/heartbeat", queueName), executor.HeartbeatRequest{
 		ExecutorName: c.options.ExecutorName,
+		ExecutorInfo: c.executorInfo(),
 	})
 	if err != nil {
 		return err
@@ -206,6 +212,7 @@ func (c *Client) Heartbeat(ctx context.Context, queueName string, jobIDs []int)
 	req, err := c.makeRequest("POST", fmt.Sprintf("%s/heartbeat", queueName), executor.HeartbeatRequest{
 		ExecutorName: c.options.ExecutorName,
 		JobIDs:       jobIDs,
+		ExecutorInfo: c.executorInfo(),
 	})
 	if err != nil {
 		return nil, err
@@ -218,6 +225,47 @@ func (c *Client) Heartbeat(ctx context.Context, queueName string, jobIDs []int)
 	return knownIDs, nil
 }
 
+// UploadArtifact streams the content of the given artifact of a job to the upload store
+// of the queue.
+func (c *Client) UploadArtifact(ctx context.Context, queueName string, jobID int, name string, r io.Reader) (err error) {
+	ctx, endObservation := c.operations.uploadArtifact.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.String("queueName", queueName),
+		log.Int("jobID", jobID),
+		log.String("name", name),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	u, err := makeURL(
+		c.options.EndpointOptions.URL,
+		c.options.EndpointOptions.Password,
+		c.options.PathPrefix,
+		fmt.Sprintf("%s/uploadArtifact", queueName),
+	)
+	if err != nil {
+		return err
+	}
+	u.RawQuery = url.Values{
+		"executorName": []string{c.options.ExecutorName},
+		"jobId":        []string{strconv.Itoa(jobID)},
+		"name":         []string{name},
+	}.Encode()
+
+	req, err := http.NewRequest("POST", u.String(), r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	return c.client.DoAndDrop(ctx, req)
+}
+
+func (c *Client) executorInfo() executor.ExecutorInfo {
+	if c.options.ExecutorInfo == nil {
+		return executor.ExecutorInfo{}
+	}
+	return c.options.ExecutorInfo()
+}
+
 func (c *Client) makeRequest(method, path string, payload interface{}) (*http.Request, error) {
 	u, err := makeURL(
 		c.options.EndpointOptions.URL,
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -383,6 +384,41 @@ func TestHeartbeatBadResponse(t *testing.T) {
 	})
 }
 
+func TestUploadArtifact(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/.executors/queue/test_queue/uploadArtifact" {
+			t.Errorf("unexpected path. want=%s have=%s", "/.executors/queue/test_queue/uploadArtifact", r.URL.Path)
+		}
+		if value := r.URL.Query().Encode(); value != "executorName=deadbeef&jobId=42&name=diff.patch" {
+			t.Errorf("unexpected query. want=%s have=%s", "executorName=deadbeef&jobId=42&name=diff.patch", value)
+		}
+		if value := r.Header.Get("Content-Type"); value != "application/octet-stream" {
+			t.Errorf("unexpected content type. want=%s have=%s", "application/octet-stream", value)
+		}
+
+		content, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("unexpected error reading payload: %s", err)
+		}
+		if string(content) != "diff --git" {
+			t.Errorf("unexpected payload. want=%q have=%q", "diff --git", content)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	client := New(Options{
+		ExecutorName:    "deadbeef",
+		PathPrefix:      "/.executors/queue",
+		EndpointOptions: EndpointOptions{URL: ts.URL, Password: "hunter2"},
+	}, &observation.TestContext)
+
+	if err := client.UploadArtifact(context.Background(), "test_queue", 42, "diff.patch", strings.NewReader("diff --git")); err != nil {
+		t.Fatalf("unexpected error uploading artifact: %s", err)
+	}
+}
+
 type routeSpec struct {
 	expectedMethod   string
 	expectedPath     string
@@ -0,0 +1,80 @@
+package apiclient
+
+import (
+	"bufio"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+	"github.com/sourcegraph/sourcegraph/internal/version"
+)
+
+// NewExecutorInfoFunc returns a function describing the executor running on the given
+// host. The memory and load of the system are read again on every call. They are only
+// available on Linux and left empty elsewhere.
+func NewExecutorInfoFunc(hostname string) func() executor.ExecutorInfo {
+	return func() executor.ExecutorInfo {
+		info := executor.ExecutorInfo{
+			Hostname:        hostname,
+			OS:              runtime.GOOS,
+			Architecture:    runtime.GOARCH,
+			ExecutorVersion: version.Version(),
+			NumCPUs:         runtime.NumCPU(),
+		}
+
+		if content, err := os.ReadFile("/proc/meminfo"); err == nil {
+			info.MemoryTotalBytes, info.MemoryAvailableBytes = parseMeminfo(string(content))
+		}
+		if content, err := os.ReadFile("/proc/loadavg"); err == nil {
+			info.LoadAverage = parseLoadAverage(string(content))
+		}
+
+		return info
+	}
+}
+
+// parseMeminfo returns the total and available memory in bytes from the content
+// of /proc/meminfo.
+func parseMeminfo(content string) (total, available int64) {
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		value, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		if len(fields) == 3 && fields[2] == "kB" {
+			value *= 1024
+		}
+
+		switch fields[0] {
+		case "MemTotal:":
+			total = value
+		case "MemAvailable:":
+			available = value
+		}
+	}
+
+	return total, available
+}
+
+// parseLoadAverage returns the one minute load average from the content of
+// /proc/loadavg.
+func parseLoadAverage(content string) float64 {
+	fields := strings.Fields(content)
+	if len(fields) == 0 {
+		return 0
+	}
+
+	load, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0
+	}
+	return load
+}
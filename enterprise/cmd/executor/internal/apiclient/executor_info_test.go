@@ -0,0 +1,31 @@
+package apiclient
+
+import "testing"
+
+func TestParseMeminfo(t *testing.T) {
+	content := `MemTotal:       16314460 kB
+MemFree:         1123456 kB
+MemAvailable:    8157230 kB
+Buffers:          123456 kB
+`
+
+	total, available := parseMeminfo(content)
+	if want := int64(16314460 * 1024); total != want {
+		t.Errorf("unexpected total memory. want=%d have=%d", want, total)
+	}
+	if want := int64(8157230 * 1024); available != want {
+		t.Errorf("unexpected available memory. want=%d have=%d", want, available)
+	}
+}
+
+func TestParseLoadAverage(t *testing.T) {
+	for content, want := range map[string]float64{
+		"1.25 0.80 0.50 2/345 6789\n": 1.25,
+		"":                            0,
+		"garbage":                     0,
+	} {
+		if have := parseLoadAverage(content); have != want {
+			t.Errorf("unexpected load average for %q. want=%f have=%f", content, want, have)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package command
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
@@ -125,6 +126,21 @@ func teardownFirecracker(ctx context.Context, runner commandRunner, logger *Logg
 	return nil
 }
 
+// copyFromFirecracker copies the file at the given path, relative to the workspace of the
+// Firecracker VM with the given name, to the given path on the host.
+func copyFromFirecracker(ctx context.Context, runner commandRunner, logger *Logger, name, path, hostPath string, operations *Operations) error {
+	copyCommand := command{
+		Key:       "teardown.firecracker.copy",
+		Command:   flatten("ignite", "cp", fmt.Sprintf("%s:%s", name, filepath.Join(firecrackerContainerDir, path)), hostPath),
+		Operation: operations.TeardownFirecrackerCopy,
+	}
+	if err := runner.RunCommand(ctx, copyCommand, logger); err != nil {
+		return errors.Wrap(err, "failed to copy file from firecracker vm")
+	}
+
+	return nil
+}
+
 func firecrackerResourceFlags(options ResourceOptions) []string {
 	return []string{
 		"--cpus", strconv.Itoa(options.NumCPUs),
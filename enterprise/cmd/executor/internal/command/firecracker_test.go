@@ -166,6 +166,27 @@ func TestTeardownFirecracker(t *testing.T) {
 	}
 }
 
+func TestCopyFromFirecracker(t *testing.T) {
+	runner := NewMockCommandRunner()
+	operations := NewOperations(&observation.TestContext)
+
+	if err := copyFromFirecracker(context.Background(), runner, nil, "deadbeef", "out/results.sarif", "/tmp/results.sarif", operations); err != nil {
+		t.Fatalf("unexpected error copying file: %s", err)
+	}
+
+	var actual []string
+	for _, call := range runner.RunCommandFunc.History() {
+		actual = append(actual, strings.Join(call.Arg1.Command, " "))
+	}
+
+	expected := []string{
+		"ignite cp deadbeef:/work/out/results.sarif /tmp/results.sarif",
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("unexpected commands (-want +got):\n%s", diff)
+	}
+}
+
 func TestSanitizeImage(t *testing.T) {
 	image := "sourcegraph/ignite-ubuntu"
 	tag := ":insiders"
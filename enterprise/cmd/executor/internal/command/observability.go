@@ -17,6 +17,7 @@ type Operations struct {
 	SetupFirecrackerStart     *observation.Operation
 	SetupStartupScript        *observation.Operation
 	TeardownFirecrackerRemove *observation.Operation
+	TeardownFirecrackerCopy   *observation.Operation
 	Exec                      *observation.Operation
 
 	RunLockWaitTotal prometheus.Counter
@@ -59,6 +60,7 @@ func NewOperations(observationContext *observation.Context) *Operations {
 		SetupFirecrackerStart:     op("setup.firecracker.start"),
 		SetupStartupScript:        op("setup.startup-script"),
 		TeardownFirecrackerRemove: op("teardown.firecracker.remove"),
+		TeardownFirecrackerCopy:   op("teardown.firecracker.copy"),
 		Exec:                      op("exec"),
 
 		RunLockWaitTotal: runLockWaitTotal,
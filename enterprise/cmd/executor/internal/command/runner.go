@@ -21,6 +21,14 @@ type Runner interface {
 	Run(ctx context.Context, command CommandSpec) error
 }
 
+// FileCopier is implemented by runners whose workspace is not shared with the host,
+// so that files written by the commands of a job can be read by the executor.
+type FileCopier interface {
+	// CopyToHost copies the file at the given path, relative to the workspace of the
+	// runner, to the given path on the host.
+	CopyToHost(ctx context.Context, path, hostPath string) error
+}
+
 // CommandSpec represents a command that can be run on a machine, whether that
 // is the host, in a virtual machine, or in a docker container. If an image is
 // supplied, then the command will be run in a one-shot docker container.
@@ -126,6 +134,12 @@ func (r *firecrackerRunner) Run(ctx context.Context, command CommandSpec) error
 	return runCommand(ctx, formatFirecrackerCommand(command, r.name, r.dir, r.options), r.logger)
 }
 
+var _ FileCopier = &firecrackerRunner{}
+
+func (r *firecrackerRunner) CopyToHost(ctx context.Context, path, hostPath string) error {
+	return copyFromFirecracker(ctx, defaultRunner, r.logger, r.name, path, hostPath, r.operations)
+}
+
 type runnerWrapper struct{}
 
 var defaultRunner = &runnerWrapper{}
@@ -25,6 +25,7 @@ import (
 type handler struct {
 	nameSet       *janitor.NameSet
 	store         workerutil.Store
+	artifactStore ArtifactStore
 	options       Options
 	operations    *command.Operations
 	runnerFactory func(dir string, logger *command.Logger, options command.Options, operations *command.Operations) command.Runner
@@ -172,10 +173,23 @@ func (h *handler) Handle(ctx context.Context, record workerutil.Record) (err err
 		}
 	}()
 
+	err = h.runSteps(ctx, runner, job, scriptNames, wrapError)
+
+	// Upload the artifacts even if a step failed, as they often explain the failure. A
+	// failed upload is recorded in the execution logs but does not fail the job.
+	if uploadErr := h.uploadArtifacts(ctx, runner, logger, job, workingDirectory); uploadErr != nil {
+		log15.Warn("Failed to upload artifacts", "jobID", job.ID, "error", uploadErr)
+	}
+
+	return err
+}
+
+// runSteps invokes the docker steps and then the src-cli steps of the job sequentially.
+func (h *handler) runSteps(ctx context.Context, runner command.Runner, job executor.Job, scriptNames []string, wrapError func(err error, message string) error) error {
 	// Invoke each docker step sequentially
 	for i, dockerStep := range job.DockerSteps {
 		dockerStepCommand := command.CommandSpec{
-			Key:        fmt.Sprintf("step.docker.%d", i),
+			Key:        dockerStep.LogKey(i),
 			Image:      dockerStep.Image,
 			ScriptPath: scriptNames[i],
 			Dir:        dockerStep.Dir,
@@ -195,7 +209,7 @@ func (h *handler) Handle(ctx context.Context, record workerutil.Record) (err err
 		log15.Info(fmt.Sprintf("Running src-cli step #%d", i), "jobID", job.ID, "repositoryName", job.RepositoryName, "commit", job.Commit)
 
 		cliStepCommand := command.CommandSpec{
-			Key:       fmt.Sprintf("step.src.%d", i),
+			Key:       cliStep.LogKey(i),
 			Command:   append([]string{"src"}, cliStep.Commands...),
 			Dir:       cliStep.Dir,
 			Env:       cliStep.Env,
@@ -210,6 +224,112 @@ func (h *handler) Handle(ctx context.Context, record workerutil.Record) (err err
 	return nil
 }
 
+// uploadArtifacts uploads the artifacts of the job that exist in the workspace. Artifacts
+// of runners that don't share their workspace with the host are copied to it first.
+func (h *handler) uploadArtifacts(ctx context.Context, runner command.Runner, logger *command.Logger, job executor.Job, workingDirectory string) (err error) {
+	if len(job.Artifacts) == 0 || h.artifactStore == nil {
+		return nil
+	}
+
+	handle := logger.Log("upload.artifacts", nil)
+	defer func() {
+		if err == nil {
+			handle.Finalize(0)
+		} else {
+			fmt.Fprintf(handle, "%s\n", err)
+			handle.Finalize(1)
+		}
+
+		handle.Close()
+	}()
+
+	var errs error
+	for _, artifact := range job.Artifacts {
+		if err := h.uploadArtifact(ctx, runner, job, workingDirectory, artifact); err != nil {
+			if err == errArtifactNotFound {
+				fmt.Fprintf(handle, "Skipped %s: file not found\n", artifact.Path)
+				continue
+			}
+
+			errs = multierror.Append(errs, errors.Wrapf(err, "artifact %q", artifact.Name))
+			continue
+		}
+
+		fmt.Fprintf(handle, "Uploaded %s as %q\n", artifact.Path, artifact.Name)
+	}
+
+	return errs
+}
+
+// errArtifactNotFound is returned by uploadArtifact if the file of the artifact doesn't exist.
+var errArtifactNotFound = errors.New("file not found")
+
+func (h *handler) uploadArtifact(ctx context.Context, runner command.Runner, job executor.Job, workingDirectory string, artifact executor.Artifact) error {
+	if !executor.ArtifactNamePattern.MatchString(artifact.Name) {
+		return errors.New("invalid name")
+	}
+
+	path, err := filepath.Abs(filepath.Join(workingDirectory, artifact.Path))
+	if err != nil {
+		return err
+	}
+	if !isInDirectory(path, workingDirectory) {
+		return errors.Errorf("refusing to read outside of working directory")
+	}
+
+	if copier, ok := runner.(command.FileCopier); ok {
+		// Copy the file next to the workspace, so that it cannot overwrite a file the
+		// next artifact is read from.
+		hostPath := filepath.Join(workingDirectory, command.ScriptsPath, fmt.Sprintf("artifact.%s", artifact.Name))
+		if err := copier.CopyToHost(ctx, artifact.Path, hostPath); err != nil {
+			// Copying fails the same way for files that don't exist and for other
+			// failures, the details are in the log entry of the copy command.
+			return errArtifactNotFound
+		}
+		path = hostPath
+	}
+
+	// 🚨 SECURITY: The file and the directories leading to it were written by the
+	// job, so any of them can be a symlink to a file of the host outside of the
+	// workspace. Resolve them all before checking where the file is.
+	resolvedWorkingDirectory, err := filepath.EvalSymlinks(workingDirectory)
+	if err != nil {
+		return err
+	}
+	path, err = filepath.EvalSymlinks(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return errArtifactNotFound
+		}
+		return err
+	}
+	if !isInDirectory(path, resolvedWorkingDirectory) {
+		return errors.Errorf("refusing to read outside of working directory")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return errors.New("not a regular file")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return h.artifactStore.UploadArtifact(ctx, job.ID, artifact.Name, f)
+}
+
+// isInDirectory returns whether the given absolute path is inside of the given
+// directory. The directory itself is not inside of it.
+func isInDirectory(path, dir string) bool {
+	return strings.HasPrefix(path, strings.TrimSuffix(dir, string(filepath.Separator))+string(filepath.Separator))
+}
+
 var scriptPreamble = `
 set -x
 `
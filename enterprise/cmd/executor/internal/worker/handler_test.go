@@ -2,10 +2,13 @@ package worker
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/cockroachdb/errors"
 	"github.com/google/go-cmp/cmp"
 
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor/internal/command"
@@ -38,6 +41,7 @@ func TestHandle(t *testing.T) {
 				Env:      []string{"FOO=BAR"},
 			},
 			{
+				Key:      "yarn",
 				Image:    "alpine",
 				Commands: []string{"yarn", "install"},
 				Dir:      "web",
@@ -51,6 +55,7 @@ func TestHandle(t *testing.T) {
 				Env:      []string{},
 			},
 			{
+				Key:      "apply",
 				Commands: []string{"batch", "apply", "-f", "spec.yaml"},
 				Dir:      "cmpg",
 				Env:      []string{"BAR=BAZ"},
@@ -92,7 +97,9 @@ func TestHandle(t *testing.T) {
 	}
 
 	var commands [][]string
+	var keys []string
 	for _, call := range runner.RunFunc.History() {
+		keys = append(keys, call.Arg1.Key)
 		if call.Arg1.Image != "" {
 			commands = append(commands, []string{"/bin/sh", call.Arg1.ScriptPath})
 		} else {
@@ -109,4 +116,137 @@ func TestHandle(t *testing.T) {
 	if diff := cmp.Diff(expectedCommands, commands); diff != "" {
 		t.Errorf("unexpected commands (-want +got):\n%s", diff)
 	}
+
+	expectedKeys := []string{
+		"step.docker.0",
+		"step.docker.yarn",
+		"step.src.0",
+		"step.src.apply",
+	}
+	if diff := cmp.Diff(expectedKeys, keys); diff != "" {
+		t.Errorf("unexpected log keys (-want +got):\n%s", diff)
+	}
+}
+
+func TestHandleArtifacts(t *testing.T) {
+	testDir := t.TempDir()
+	makeTempDir = func() (string, error) { return testDir, nil }
+	if err := os.MkdirAll(filepath.Join(testDir, command.ScriptsPath), os.ModePerm); err != nil {
+		t.Fatalf("unexpected error creating workspace: %s", err)
+	}
+
+	// The step fails, but the artifacts are uploaded nonetheless.
+	runner := NewMockRunner()
+	runner.RunFunc.SetDefaultReturn(errors.New("exit status 1"))
+
+	job := executor.Job{
+		ID: 42,
+		VirtualMachineFiles: map[string]string{
+			"results.sarif": "{}",
+		},
+		DockerSteps: []executor.DockerStep{
+			{Image: "alpine", Commands: []string{"false"}},
+		},
+		Artifacts: []executor.Artifact{
+			{Name: "results.sarif", Path: "results.sarif"},
+			{Name: "missing.log", Path: "missing.log"},
+		},
+	}
+
+	artifactStore := &testArtifactStore{uploaded: map[string]string{}}
+	handler := &handler{
+		store:         NewMockStore(),
+		artifactStore: artifactStore,
+		nameSet:       janitor.NewNameSet(),
+		options:       Options{},
+		operations:    command.NewOperations(&observation.TestContext),
+		runnerFactory: func(dir string, logger *command.Logger, options command.Options, operations *command.Operations) command.Runner {
+			if dir == "" {
+				return NewMockRunner()
+			}
+
+			return runner
+		},
+	}
+
+	if err := handler.Handle(context.Background(), job); err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	if diff := cmp.Diff(map[string]string{"42/results.sarif": "{}"}, artifactStore.uploaded); diff != "" {
+		t.Errorf("unexpected uploaded artifacts (-want +got):\n%s", diff)
+	}
+}
+
+type testArtifactStore struct {
+	uploaded map[string]string
+}
+
+func (s *testArtifactStore) UploadArtifact(ctx context.Context, jobID int, name string, r io.Reader) error {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	s.uploaded[fmt.Sprintf("%d/%s", jobID, name)] = string(content)
+	return nil
+}
+
+func TestUploadArtifactOutsideWorkingDirectory(t *testing.T) {
+	testDir := t.TempDir()
+	workingDirectory := filepath.Join(testDir, "work")
+	for _, dir := range []string{workingDirectory, filepath.Join(testDir, "work123"), filepath.Join(testDir, "host")} {
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			t.Fatalf("unexpected error creating directory: %s", err)
+		}
+	}
+	for path, content := range map[string]string{
+		filepath.Join(workingDirectory, "results.sarif"): "{}",
+		filepath.Join(testDir, "work123", "secret"):      "sibling",
+		filepath.Join(testDir, "host", "secret"):         "host",
+	} {
+		if err := os.WriteFile(path, []byte(content), os.ModePerm); err != nil {
+			t.Fatalf("unexpected error writing file: %s", err)
+		}
+	}
+
+	// Symlinks the job could have created inside of the workspace.
+	if err := os.Symlink(filepath.Join(testDir, "host"), filepath.Join(workingDirectory, "out")); err != nil {
+		t.Fatalf("unexpected error creating symlink: %s", err)
+	}
+	if err := os.Symlink(filepath.Join(testDir, "host", "secret"), filepath.Join(workingDirectory, "secret")); err != nil {
+		t.Fatalf("unexpected error creating symlink: %s", err)
+	}
+	if err := os.Symlink("results.sarif", filepath.Join(workingDirectory, "results.link")); err != nil {
+		t.Fatalf("unexpected error creating symlink: %s", err)
+	}
+
+	artifactStore := &testArtifactStore{uploaded: map[string]string{}}
+	handler := &handler{artifactStore: artifactStore}
+	job := executor.Job{ID: 42}
+
+	for _, testCase := range []struct {
+		path    string
+		wantErr bool
+	}{
+		{path: "results.sarif"},
+		{path: "results.link"},
+		{path: "../work123/secret", wantErr: true},
+		{path: "out/secret", wantErr: true},
+		{path: "secret", wantErr: true},
+	} {
+		t.Run(testCase.path, func(t *testing.T) {
+			err := handler.uploadArtifact(context.Background(), NewMockRunner(), job, workingDirectory, executor.Artifact{Name: "artifact", Path: testCase.path})
+			if testCase.wantErr && err == nil {
+				t.Fatalf("expected an error")
+			}
+			if !testCase.wantErr && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+
+	if diff := cmp.Diff(map[string]string{"42/artifact": "{}"}, artifactStore.uploaded); diff != "" {
+		t.Errorf("unexpected uploaded artifacts (-want +got):\n%s", diff)
+	}
 }
@@ -2,6 +2,7 @@ package worker
 
 import (
 	"context"
+	"io"
 
 	"github.com/cockroachdb/errors"
 
@@ -22,9 +23,16 @@ type QueueStore interface {
 	MarkErrored(ctx context.Context, queueName string, jobID int, errorMessage string) error
 	MarkFailed(ctx context.Context, queueName string, jobID int, errorMessage string) error
 	Heartbeat(ctx context.Context, queueName string, jobIDs []int) (knownIDs []int, err error)
+	UploadArtifact(ctx context.Context, queueName string, jobID int, name string, r io.Reader) error
+}
+
+// ArtifactStore uploads the artifacts of jobs.
+type ArtifactStore interface {
+	UploadArtifact(ctx context.Context, jobID int, name string, r io.Reader) error
 }
 
 var _ workerutil.Store = &storeShim{}
+var _ ArtifactStore = &storeShim{}
 
 func (s *storeShim) QueuedCount(ctx context.Context, extraArguments interface{}) (int, error) {
 	return 0, errors.New("unimplemented")
@@ -63,3 +71,7 @@ func (s *storeShim) MarkErrored(ctx context.Context, id int, errorMessage string
 func (s *storeShim) MarkFailed(ctx context.Context, id int, errorMessage string) (bool, error) {
 	return true, s.queueStore.MarkFailed(ctx, s.queueName, id, errorMessage)
 }
+
+func (s *storeShim) UploadArtifact(ctx context.Context, jobID int, name string, r io.Reader) error {
+	return s.queueStore.UploadArtifact(ctx, s.queueName, jobID, name, r)
+}
@@ -76,6 +76,7 @@ func NewWorker(nameSet *janitor.NameSet, options Options, observationContext *ob
 	handler := &handler{
 		nameSet:       nameSet,
 		store:         store,
+		artifactStore: store,
 		options:       options,
 		operations:    command.NewOperations(observationContext),
 		runnerFactory: command.NewRunner,
@@ -116,6 +116,12 @@ func (s *sessionIssuerHelper) CreateCodeHostConnection(ctx context.Context, toke
 	if err != nil {
 		return "Error checking for existing external service", err
 	}
+	// Users may have external services for other code hosts of the same kind,
+	// we only care about the one of this provider.
+	services, err = servicesForCodeHost(services, p.ServiceID)
+	if err != nil {
+		return "Error checking for existing external service", err
+	}
 	var svc *types.ExternalService
 	now := time.Now()
 
@@ -135,7 +141,7 @@ func (s *sessionIssuerHelper) CreateCodeHostConnection(ctx context.Context, toke
 			NamespaceUserID: actor.UID,
 		}
 	} else if len(services) > 1 {
-		return "Multiple services of same kind found for user", errors.New("multiple services of same kind found for user")
+		return "Multiple services for the same code host found for user", errors.New("multiple services for the same code host found for user")
 	} else {
 		// We have an existing service, update it
 		svc = services[0]
@@ -173,3 +179,19 @@ func (s *sessionIssuerHelper) SessionData(token *oauth2.Token) oauth.SessionData
 		// TODO(beyang): store and use refresh token to auto-refresh sessions
 	}
 }
+
+// servicesForCodeHost returns the external services in svcs that connect to
+// the code host with the given normalized base URL.
+func servicesForCodeHost(svcs []*types.ExternalService, serviceID string) ([]*types.ExternalService, error) {
+	var matching []*types.ExternalService
+	for _, svc := range svcs {
+		id, err := extsvc.UniqueCodeHostIdentifier(svc.Kind, svc.Config)
+		if err != nil {
+			return nil, err
+		}
+		if id == serviceID {
+			matching = append(matching, svc)
+		}
+	}
+	return matching, nil
+}
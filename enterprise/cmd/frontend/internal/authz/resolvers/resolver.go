@@ -412,3 +412,109 @@ func (r *Resolver) UserPermissionsInfo(ctx context.Context, id graphql.ID) (grap
 		updatedAt: p.UpdatedAt,
 	}, nil
 }
+
+type permissionsSyncStateResolver struct {
+	state    *edb.PermsSyncState
+	syncedAt time.Time
+}
+
+func (r *permissionsSyncStateResolver) State() string {
+	return string(r.state.Status())
+}
+
+func (r *permissionsSyncStateResolver) QueuedAt() *graphqlbackend.DateTime {
+	return graphqlbackend.DateTimeOrNil(r.state.QueuedAt)
+}
+
+func (r *permissionsSyncStateResolver) StartedAt() *graphqlbackend.DateTime {
+	return graphqlbackend.DateTimeOrNil(r.state.StartedAt)
+}
+
+func (r *permissionsSyncStateResolver) FinishedAt() *graphqlbackend.DateTime {
+	return graphqlbackend.DateTimeOrNil(r.state.FinishedAt)
+}
+
+func (r *permissionsSyncStateResolver) LastSyncedAt() *graphqlbackend.DateTime {
+	if r.syncedAt.IsZero() {
+		return nil
+	}
+	return &graphqlbackend.DateTime{Time: r.syncedAt}
+}
+
+func (r *permissionsSyncStateResolver) LastError() *string {
+	if r.state.FinishedAt == nil || r.state.LastError == "" {
+		return nil
+	}
+	return &r.state.LastError
+}
+
+func (r *Resolver) RepositoryPermissionsSyncState(ctx context.Context, id graphql.ID) (graphqlbackend.PermissionsSyncStateResolver, error) {
+	// 🚨 SECURITY: Only site admins can query repository permissions.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.store.Handle().DB()); err != nil {
+		return nil, err
+	}
+
+	repoID, err := graphqlbackend.UnmarshalRepositoryID(id)
+	if err != nil {
+		return nil, err
+	}
+	// Make sure the repo ID is valid and not soft-deleted.
+	if _, err = database.Repos(r.store.Handle().DB()).Get(ctx, repoID); err != nil {
+		return nil, err
+	}
+
+	state, err := r.store.LoadPermsSyncState(ctx, edb.PermsSyncTypeRepo, int32(repoID))
+	if err != nil {
+		return nil, err
+	}
+
+	p := &authz.RepoPermissions{
+		RepoID: int32(repoID),
+		Perm:   authz.Read, // Note: We currently only support read for repository permissions.
+	}
+	err = r.store.LoadRepoPermissions(ctx, p)
+	if err != nil && err != authz.ErrPermsNotFound {
+		return nil, err
+	}
+
+	return &permissionsSyncStateResolver{
+		state:    state,
+		syncedAt: p.SyncedAt,
+	}, nil
+}
+
+func (r *Resolver) UserPermissionsSyncState(ctx context.Context, id graphql.ID) (graphqlbackend.PermissionsSyncStateResolver, error) {
+	// 🚨 SECURITY: Only site admins can query user permissions.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.store.Handle().DB()); err != nil {
+		return nil, err
+	}
+
+	userID, err := graphqlbackend.UnmarshalUserID(id)
+	if err != nil {
+		return nil, err
+	}
+	// Make sure the user ID is valid and not soft-deleted.
+	if _, err = database.Users(r.store.Handle().DB()).GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	state, err := r.store.LoadPermsSyncState(ctx, edb.PermsSyncTypeUser, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &authz.UserPermissions{
+		UserID: userID,
+		Perm:   authz.Read, // Note: We currently only support read for repository permissions.
+		Type:   authz.PermRepos,
+	}
+	err = r.store.LoadUserPermissions(ctx, p)
+	if err != nil && err != authz.ErrPermsNotFound {
+		return nil, err
+	}
+
+	return &permissionsSyncStateResolver{
+		state:    state,
+		syncedAt: p.SyncedAt,
+	}, nil
+}
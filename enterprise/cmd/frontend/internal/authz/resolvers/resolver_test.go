@@ -841,3 +841,192 @@ func TestResolver_UserPermissionsInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestResolver_RepositoryPermissionsSyncState(t *testing.T) {
+	db := dbtest.NewDB(t, "")
+
+	t.Run("authenticated as non-admin", func(t *testing.T) {
+		database.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+			return &types.User{}, nil
+		}
+		t.Cleanup(func() {
+			database.Mocks.Users.GetByCurrentAuthUser = nil
+		})
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		result, err := (&Resolver{store: edb.Perms(db, timeutil.Now)}).RepositoryPermissionsSyncState(ctx, graphqlbackend.MarshalRepositoryID(1))
+		if want := backend.ErrMustBeSiteAdmin; err != want {
+			t.Errorf("err: want %q but got %v", want, err)
+		}
+		if result != nil {
+			t.Errorf("result: want nil but got %v", result)
+		}
+	})
+
+	database.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{SiteAdmin: true}, nil
+	}
+	database.Mocks.Repos.GetByName = func(_ context.Context, repo api.RepoName) (*types.Repo, error) {
+		return &types.Repo{ID: 1, Name: repo}, nil
+	}
+	database.Mocks.Repos.Get = func(_ context.Context, id api.RepoID) (*types.Repo, error) {
+		return &types.Repo{ID: id}, nil
+	}
+	edb.Mocks.Perms.LoadPermsSyncState = func(_ context.Context, typ edb.PermsSyncType, id int32) (*edb.PermsSyncState, error) {
+		if typ != edb.PermsSyncTypeRepo || id != 1 {
+			return nil, errors.Errorf("unexpected sync state %s %d", typ, id)
+		}
+		queuedAt := clock()
+		return &edb.PermsSyncState{QueuedAt: &queuedAt}, nil
+	}
+	edb.Mocks.Perms.LoadRepoPermissions = func(_ context.Context, p *authz.RepoPermissions) error {
+		return authz.ErrPermsNotFound
+	}
+	defer func() {
+		database.Mocks.Users = database.MockUsers{}
+		database.Mocks.Repos = database.MockRepos{}
+		edb.Mocks.Perms = edb.MockPerms{}
+	}()
+	tests := []struct {
+		name     string
+		gqlTests []*gqltesting.Test
+	}{
+		{
+			name: "get permissions sync state",
+			gqlTests: []*gqltesting.Test{
+				{
+					Schema: mustParseGraphQLSchema(t, nil),
+					Query: `
+				{
+					repository(name: "github.com/owner/repo") {
+						permissionsSyncState {
+							state
+							queuedAt
+							startedAt
+							finishedAt
+							lastSyncedAt
+							lastError
+						}
+					}
+				}
+			`,
+					ExpectedResult: fmt.Sprintf(`
+				{
+					"repository": {
+						"permissionsSyncState": {
+							"state": "QUEUED",
+							"queuedAt": "%s",
+							"startedAt": null,
+							"finishedAt": null,
+							"lastSyncedAt": null,
+							"lastError": null
+						}
+    				}
+				}
+			`, clock().Format(time.RFC3339)),
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gqltesting.RunTests(t, test.gqlTests)
+		})
+	}
+}
+
+func TestResolver_UserPermissionsSyncState(t *testing.T) {
+	db := dbtest.NewDB(t, "")
+
+	t.Run("authenticated as non-admin", func(t *testing.T) {
+		database.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+			return &types.User{}, nil
+		}
+		t.Cleanup(func() {
+			database.Mocks.Users.GetByCurrentAuthUser = nil
+		})
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		result, err := (&Resolver{store: edb.Perms(db, timeutil.Now)}).UserPermissionsSyncState(ctx, graphqlbackend.MarshalUserID(1))
+		if want := backend.ErrMustBeSiteAdmin; err != want {
+			t.Errorf("err: want %q but got %v", want, err)
+		}
+		if result != nil {
+			t.Errorf("result: want nil but got %v", result)
+		}
+	})
+
+	database.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{ID: 1, SiteAdmin: true}, nil
+	}
+	database.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id}, nil
+	}
+	edb.Mocks.Perms.LoadPermsSyncState = func(_ context.Context, typ edb.PermsSyncType, id int32) (*edb.PermsSyncState, error) {
+		if typ != edb.PermsSyncTypeUser || id != 1 {
+			return nil, errors.Errorf("unexpected sync state %s %d", typ, id)
+		}
+		startedAt := clock()
+		finishedAt := clock().Add(time.Minute)
+		return &edb.PermsSyncState{
+			StartedAt:  &startedAt,
+			FinishedAt: &finishedAt,
+			LastError:  "rate limit exceeded",
+		}, nil
+	}
+	edb.Mocks.Perms.LoadUserPermissions = func(_ context.Context, p *authz.UserPermissions) error {
+		p.UpdatedAt = clock()
+		p.SyncedAt = clock()
+		return nil
+	}
+	defer func() {
+		database.Mocks.Users = database.MockUsers{}
+		edb.Mocks.Perms = edb.MockPerms{}
+	}()
+	tests := []struct {
+		name     string
+		gqlTests []*gqltesting.Test
+	}{
+		{
+			name: "get permissions sync state",
+			gqlTests: []*gqltesting.Test{
+				{
+					Schema: mustParseGraphQLSchema(t, nil),
+					Query: `
+				{
+					currentUser {
+						permissionsSyncState {
+							state
+							queuedAt
+							startedAt
+							finishedAt
+							lastSyncedAt
+							lastError
+						}
+					}
+				}
+			`,
+					ExpectedResult: fmt.Sprintf(`
+				{
+					"currentUser": {
+						"permissionsSyncState": {
+							"state": "ERRORED",
+							"queuedAt": null,
+							"startedAt": "%[1]s",
+							"finishedAt": "%[2]s",
+							"lastSyncedAt": "%[1]s",
+							"lastError": "rate limit exceeded"
+						}
+    				}
+				}
+			`, clock().Format(time.RFC3339), clock().Add(time.Minute).Format(time.RFC3339)),
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gqltesting.RunTests(t, test.gqlTests)
+		})
+	}
+}
@@ -147,6 +147,18 @@ func (r *batchChangeResolver) ClosedAt() *graphqlbackend.DateTime {
 	return &graphqlbackend.DateTime{Time: r.batchChange.ClosedAt}
 }
 
+func (r *batchChangeResolver) AutoRebase() bool {
+	return r.batchChange.AutoRebase
+}
+
+func (r *batchChangeResolver) AutoArchiveAfterDays() *int32 {
+	if r.batchChange.AutoArchiveAfterDays == 0 {
+		return nil
+	}
+	days := int32(r.batchChange.AutoArchiveAfterDays)
+	return &days
+}
+
 func (r *batchChangeResolver) ChangesetsStats(ctx context.Context) (graphqlbackend.ChangesetsStatsResolver, error) {
 	stats, err := r.store.GetChangesetsStats(ctx, r.batchChange.ID)
 	if err != nil {
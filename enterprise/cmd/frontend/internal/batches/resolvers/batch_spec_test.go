@@ -133,9 +133,9 @@ func TestBatchSpecResolver(t *testing.T) {
 		},
 
 		DiffStat: apitest.DiffStat{
-			Added:   changesetSpec.DiffStatAdded,
-			Changed: changesetSpec.DiffStatChanged,
-			Deleted: changesetSpec.DiffStatDeleted,
+			Added:   changesetSpec.DiffStat().Added,
+			Changed: changesetSpec.DiffStat().Changed,
+			Deleted: changesetSpec.DiffStat().Deleted,
 		},
 
 		AppliesToBatchChange: apitest.BatchChange{
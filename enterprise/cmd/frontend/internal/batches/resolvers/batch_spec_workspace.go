@@ -4,15 +4,18 @@ import (
 	"context"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/executorqueue/artifacturl"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/types"
-	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
 )
 
 const batchSpecWorkspaceIDKind = "BatchSpecWorkspace"
@@ -82,7 +85,7 @@ func (r *batchSpecWorkspaceResolver) Steps(ctx context.Context) ([]graphqlbacken
 
 	var stepInfo = make(map[int]*btypes.StepInfo)
 	if r.execution != nil {
-		entry, ok := findExecutionLogEntry(r.execution, "step.src.0")
+		entry, ok := r.execution.SrcExecLogEntry()
 		if ok {
 			logLines := btypes.ParseJSONLogsFromOutput(entry.Out)
 			stepInfo = btypes.ParseLogLines(logLines)
@@ -135,7 +138,7 @@ func (r *batchSpecWorkspaceResolver) Stages() graphqlbackend.BatchSpecWorkspaceS
 	if r.execution == nil {
 		return nil
 	}
-	return &batchSpecWorkspaceStagesResolver{store: r.store, execution: r.execution}
+	return &batchSpecWorkspaceStagesResolver{store: r.store, execution: r.execution, steps: r.workspace.Steps}
 }
 
 func (r *batchSpecWorkspaceResolver) StartedAt() *graphqlbackend.DateTime {
@@ -220,9 +223,37 @@ func (r *batchSpecWorkspaceResolver) PlaceInQueue() *int32 {
 	return &i32
 }
 
+func (r *batchSpecWorkspaceResolver) Artifacts(ctx context.Context) ([]graphqlbackend.ExecutorJobArtifactResolver, error) {
+	if r.execution == nil {
+		return []graphqlbackend.ExecutorJobArtifactResolver{}, nil
+	}
+
+	// The ID of the execution job is the ID of the job in the batches executor queue.
+	artifacts, err := database.ExecutorJobArtifactsWith(r.store).ListForJob(ctx, "batches", int(r.execution.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]graphqlbackend.ExecutorJobArtifactResolver, 0, len(artifacts))
+	for _, artifact := range artifacts {
+		var downloadURL *string
+		if artifacturl.Enabled() {
+			expiresAt := time.Now().Add(artifacturl.Expiry)
+			if artifact.ExpiresAt.Before(expiresAt) {
+				expiresAt = artifact.ExpiresAt
+			}
+			u := artifacturl.URL(artifact.ID, expiresAt)
+			downloadURL = &u
+		}
+		resolvers = append(resolvers, graphqlbackend.NewExecutorJobArtifactResolver(artifact, downloadURL))
+	}
+	return resolvers, nil
+}
+
 type batchSpecWorkspaceStagesResolver struct {
 	store     *store.Store
 	execution *btypes.BatchSpecWorkspaceExecutionJob
+	steps     []batcheslib.Step
 }
 
 var _ graphqlbackend.BatchSpecWorkspaceStagesResolver = &batchSpecWorkspaceStagesResolver{}
@@ -232,13 +263,23 @@ func (r *batchSpecWorkspaceStagesResolver) Setup() []graphqlbackend.ExecutionLog
 }
 
 func (r *batchSpecWorkspaceStagesResolver) SrcExec() graphqlbackend.ExecutionLogEntryResolver {
-	if entry, ok := findExecutionLogEntry(r.execution, "step.src.0"); ok {
+	if entry, ok := r.execution.SrcExecLogEntry(); ok {
 		return graphqlbackend.NewExecutionLogEntryResolver(r.store.DB(), entry)
 	}
 
 	return nil
 }
 
+func (r *batchSpecWorkspaceStagesResolver) Steps() []graphqlbackend.ExecutionLogEntryResolver {
+	entries := r.execution.StepLogEntries(r.steps)
+	resolvers := make([]graphqlbackend.ExecutionLogEntryResolver, 0, len(entries))
+	for _, entry := range entries {
+		resolvers = append(resolvers, graphqlbackend.NewExecutionLogEntryResolver(r.store.DB(), entry))
+	}
+
+	return resolvers
+}
+
 func (r *batchSpecWorkspaceStagesResolver) Teardown() []graphqlbackend.ExecutionLogEntryResolver {
 	return r.executionLogEntryResolversWithPrefix("teardown.")
 }
@@ -255,13 +296,3 @@ func (r *batchSpecWorkspaceStagesResolver) executionLogEntryResolversWithPrefix(
 
 	return resolvers
 }
-
-func findExecutionLogEntry(execution *btypes.BatchSpecWorkspaceExecutionJob, key string) (workerutil.ExecutionLogEntry, bool) {
-	for _, entry := range execution.ExecutionLogs {
-		if entry.Key == key {
-			return entry, true
-		}
-	}
-
-	return workerutil.ExecutionLogEntry{}, false
-}
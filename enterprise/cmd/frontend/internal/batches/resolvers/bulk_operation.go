@@ -55,24 +55,9 @@ func (r *bulkOperationResolver) Errors(ctx context.Context) ([]graphqlbackend.Ch
 	}
 
 	changesetIDs := uniqueChangesetIDsForBulkOperationErrors(errors)
-
-	changesetsByID := map[int64]*btypes.Changeset{}
-	reposByID := map[api.RepoID]*types.Repo{}
-	if len(changesetIDs) > 0 {
-		// Load all changesets and repos at once, to avoid N+1 queries.
-		changesets, _, err := r.store.ListChangesets(ctx, store.ListChangesetsOpts{IDs: changesetIDs})
-		if err != nil {
-			return nil, err
-		}
-		for _, ch := range changesets {
-			changesetsByID[ch.ID] = ch
-		}
-		// 🚨 SECURITY: database.Repos.GetReposSetByIDs uses the authzFilter under the hood and
-		// filters out repositories that the user doesn't have access to.
-		reposByID, err = r.store.Repos().GetReposSetByIDs(ctx, changesets.RepoIDs()...)
-		if err != nil {
-			return nil, err
-		}
+	changesetsByID, reposByID, err := r.loadChangesetsAndRepos(ctx, changesetIDs)
+	if err != nil {
+		return nil, err
 	}
 
 	res := make([]graphqlbackend.ChangesetJobErrorResolver, 0, len(errors))
@@ -88,6 +73,59 @@ func (r *bulkOperationResolver) Errors(ctx context.Context) ([]graphqlbackend.Ch
 	return res, nil
 }
 
+func (r *bulkOperationResolver) Results(ctx context.Context) ([]graphqlbackend.ChangesetJobResultResolver, error) {
+	results, err := r.store.ListBulkOperationResults(ctx, store.ListBulkOperationResultsOpts{BulkOperationID: r.bulkOperation.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	changesetIDs := make([]int64, 0, len(results))
+	for _, res := range results {
+		changesetIDs = append(changesetIDs, res.ChangesetID)
+	}
+	changesetsByID, reposByID, err := r.loadChangesetsAndRepos(ctx, changesetIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]graphqlbackend.ChangesetJobResultResolver, 0, len(results))
+	for _, result := range results {
+		ch := changesetsByID[result.ChangesetID]
+		res = append(res, &changesetJobResultResolver{
+			store:     r.store,
+			changeset: ch,
+			repo:      reposByID[ch.RepoID],
+			result:    result,
+		})
+	}
+	return res, nil
+}
+
+// loadChangesetsAndRepos loads all changesets and their repos at once, to
+// avoid N+1 queries.
+func (r *bulkOperationResolver) loadChangesetsAndRepos(ctx context.Context, changesetIDs []int64) (map[int64]*btypes.Changeset, map[api.RepoID]*types.Repo, error) {
+	changesetsByID := map[int64]*btypes.Changeset{}
+	reposByID := map[api.RepoID]*types.Repo{}
+	if len(changesetIDs) == 0 {
+		return changesetsByID, reposByID, nil
+	}
+
+	changesets, _, err := r.store.ListChangesets(ctx, store.ListChangesetsOpts{IDs: changesetIDs})
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, ch := range changesets {
+		changesetsByID[ch.ID] = ch
+	}
+	// 🚨 SECURITY: database.Repos.GetReposSetByIDs uses the authzFilter under the hood and
+	// filters out repositories that the user doesn't have access to.
+	reposByID, err = r.store.Repos().GetReposSetByIDs(ctx, changesets.RepoIDs()...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return changesetsByID, reposByID, nil
+}
+
 func (r *bulkOperationResolver) Initiator(ctx context.Context) (*graphqlbackend.UserResolver, error) {
 	return graphqlbackend.UserByIDInt32(ctx, r.store.DB(), r.bulkOperation.UserID)
 }
@@ -214,6 +214,7 @@ func (r *visibleChangesetApplyPreviewResolver) computePlan(ctx context.Context)
 			ChangesetSpecID: r.mapping.ChangesetSpecID,
 			ChangesetID:     r.mapping.ChangesetID,
 			RepoID:          r.mapping.RepoID,
+			Identical:       r.mapping.Identical,
 
 			ChangesetSpec: mappingChangesetSpec,
 			Changeset:     mappingChangeset,
@@ -27,3 +27,35 @@ func (r *changesetJobErrorResolver) Error() *string {
 	}
 	return &r.error
 }
+
+type changesetJobResultResolver struct {
+	store     *store.Store
+	changeset *btypes.Changeset
+	repo      *types.Repo
+	result    *btypes.BulkOperationResult
+}
+
+var _ graphqlbackend.ChangesetJobResultResolver = &changesetJobResultResolver{}
+
+func (r *changesetJobResultResolver) Changeset() graphqlbackend.ChangesetResolver {
+	return NewChangesetResolver(r.store, r.changeset, r.repo)
+}
+
+func (r *changesetJobResultResolver) State() string {
+	return string(r.result.State)
+}
+
+func (r *changesetJobResultResolver) Error() *string {
+	// We only show the error when the changeset is visible to the requesting user.
+	if r.repo == nil || r.result.Error == "" {
+		return nil
+	}
+	return &r.result.Error
+}
+
+func (r *changesetJobResultResolver) FinishedAt() *graphqlbackend.DateTime {
+	if r.result.FinishedAt.IsZero() {
+		return nil
+	}
+	return &graphqlbackend.DateTime{Time: r.result.FinishedAt}
+}
@@ -5,6 +5,8 @@ import (
 	"strconv"
 	"sync"
 
+	"github.com/sourcegraph/go-diff/diff"
+
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
@@ -82,6 +84,27 @@ func (r *changesetSpecConnectionResolver) compute(ctx context.Context) (btypes.C
 			return
 		}
 
+		// Compute the diff stats that haven't been computed yet in bulk, so
+		// that they are cached for subsequent requests.
+		var pending []int64
+		for _, c := range r.changesetSpecs {
+			if c.DiffStatPending {
+				pending = append(pending, c.ID)
+			}
+		}
+		if len(pending) > 0 {
+			var stats map[int64]diff.Stat
+			stats, r.err = r.store.ComputeDiffStats(ctx, pending)
+			if r.err != nil {
+				return
+			}
+			for _, c := range r.changesetSpecs {
+				if stat, ok := stats[c.ID]; ok {
+					c.SetDiffStat(stat)
+				}
+			}
+		}
+
 		// 🚨 SECURITY: database.Repos.GetRepoIDsSet uses the authzFilter under the hood and
 		// filters out repositories that the user doesn't have access to.
 		r.reposByID, r.err = r.store.Repos().GetReposSetByIDs(ctx, r.changesetSpecs.RepoIDs()...)
@@ -107,6 +107,14 @@ func (c *batchChangesUserCredentialResolver) IsSiteCredential() bool {
 	return false
 }
 
+func (c *batchChangesUserCredentialResolver) CheckedAt() *graphqlbackend.DateTime {
+	return graphqlbackend.DateTimeOrNil(c.credential.CheckedAt)
+}
+
+func (c *batchChangesUserCredentialResolver) CheckFailureMessage() *string {
+	return c.credential.CheckFailureMessage
+}
+
 type batchChangesSiteCredentialResolver struct {
 	credential *btypes.SiteCredential
 }
@@ -146,3 +154,11 @@ func (c *batchChangesSiteCredentialResolver) CreatedAt() graphqlbackend.DateTime
 func (c *batchChangesSiteCredentialResolver) IsSiteCredential() bool {
 	return true
 }
+
+func (c *batchChangesSiteCredentialResolver) CheckedAt() *graphqlbackend.DateTime {
+	return graphqlbackend.DateTimeOrNil(c.credential.CheckedAt)
+}
+
+func (c *batchChangesSiteCredentialResolver) CheckFailureMessage() *string {
+	return c.credential.CheckFailureMessage
+}
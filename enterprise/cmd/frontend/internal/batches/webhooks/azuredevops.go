@@ -0,0 +1,137 @@
+package webhooks
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type AzureDevOpsWebhook struct {
+	*Webhook
+}
+
+func NewAzureDevOpsWebhook(store *store.Store) *AzureDevOpsWebhook {
+	return &AzureDevOpsWebhook{
+		Webhook: &Webhook{store, extsvc.TypeAzureDevOps},
+	}
+}
+
+func (h *AzureDevOpsWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e, extSvc, hErr := h.parseEvent(r)
+	if hErr != nil {
+		respond(w, hErr.code, hErr)
+		return
+	}
+
+	// 🚨 SECURITY: now that the secret has been validated, we can use an
+	// internal actor on the context.
+	ctx := actor.WithInternalActor(r.Context())
+
+	externalServiceID, err := extractExternalServiceID(extSvc)
+	if err != nil {
+		respond(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	pr, ev := h.convertEvent(e)
+	if pr == (PR{}) {
+		log15.Warn("Dropping Azure DevOps webhook event", "type", fmt.Sprintf("%T", e))
+		return
+	}
+
+	if err := h.upsertChangesetEvent(ctx, externalServiceID, pr, ev); err != nil {
+		respond(w, http.StatusInternalServerError, err)
+	}
+}
+
+func (h *AzureDevOpsWebhook) parseEvent(r *http.Request) (interface{}, *types.ExternalService, *httpError) {
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, nil, &httpError{http.StatusInternalServerError, err}
+	}
+
+	// Azure DevOps doesn't sign service hook payloads, but can send them with
+	// basic authentication, which is where we expect the webhook secret.
+	_, secret, ok := r.BasicAuth()
+	if !ok || secret == "" {
+		return nil, nil, &httpError{http.StatusUnauthorized, errors.New("missing credentials")}
+	}
+
+	rawID := r.FormValue(extsvc.IDParam)
+	var externalServiceID int64
+	if rawID != "" {
+		externalServiceID, err = strconv.ParseInt(rawID, 10, 64)
+		if err != nil {
+			return nil, nil, &httpError{http.StatusBadRequest, errors.Wrap(err, "invalid external service id")}
+		}
+	}
+
+	args := database.ExternalServicesListOptions{Kinds: []string{extsvc.KindAzureDevOps}}
+	if externalServiceID != 0 {
+		args.IDs = append(args.IDs, externalServiceID)
+	}
+	es, err := h.Store.ExternalServices().List(r.Context(), args)
+	if err != nil {
+		return nil, nil, &httpError{http.StatusInternalServerError, err}
+	}
+
+	// 🚨 SECURITY: Each external service has its own webhook secret, so we
+	// only accept the payload if the password matches one of them.
+	var extSvc *types.ExternalService
+	for _, e := range es {
+		c, _ := e.Configuration()
+		con, ok := c.(*schema.AzureDevOpsConnection)
+		if !ok {
+			continue
+		}
+
+		if want := con.WebhookSecret; want != "" && subtle.ConstantTimeCompare([]byte(want), []byte(secret)) == 1 {
+			extSvc = e
+			break
+		}
+	}
+
+	if extSvc == nil {
+		return nil, nil, &httpError{http.StatusUnauthorized, errors.New("invalid credentials")}
+	}
+
+	e, err := azuredevops.ParseWebhookEvent(payload)
+	if err != nil {
+		return nil, nil, &httpError{http.StatusBadRequest, errors.Wrap(err, "parsing webhook")}
+	}
+	return e, extSvc, nil
+}
+
+func (h *AzureDevOpsWebhook) convertEvent(theirs interface{}) (pr PR, ours keyer) {
+	log15.Debug("Azure DevOps webhook received", "type", fmt.Sprintf("%T", theirs))
+
+	switch e := theirs.(type) {
+	case *azuredevops.PullRequestUpdatedEvent:
+		return azureDevOpsPR(&e.PullRequest), e
+	case *azuredevops.PullRequestMergedEvent:
+		return azureDevOpsPR(&e.PullRequest), e
+	case *azuredevops.PullRequestAbandonedEvent:
+		return azureDevOpsPR(&e.PullRequest), e
+	case *azuredevops.PullRequestCommentEvent:
+		return azureDevOpsPR(&e.Resource.PullRequest), e
+	}
+
+	return
+}
+
+func azureDevOpsPR(pr *azuredevops.PullRequest) PR {
+	return PR{ID: pr.ID, RepoExternalID: pr.Repository.ID}
+}
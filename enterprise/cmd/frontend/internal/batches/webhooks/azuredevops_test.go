@@ -0,0 +1,54 @@
+package webhooks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
+)
+
+func TestAzureDevOpsWebhookConvertEvent(t *testing.T) {
+	h := NewAzureDevOpsWebhook(nil)
+
+	base := azuredevops.PullRequestEvent{
+		PullRequest: azuredevops.PullRequest{
+			ID:         7,
+			Repository: azuredevops.Repository{ID: "repo-id"},
+		},
+	}
+	comment := &azuredevops.PullRequestCommentEvent{}
+	comment.Resource.PullRequest = base.PullRequest
+
+	for _, ev := range []interface{}{
+		&azuredevops.PullRequestUpdatedEvent{PullRequestEvent: base},
+		&azuredevops.PullRequestMergedEvent{PullRequestEvent: base},
+		&azuredevops.PullRequestAbandonedEvent{PullRequestEvent: base},
+		comment,
+	} {
+		pr, ours := h.convertEvent(ev)
+		if want := (PR{ID: 7, RepoExternalID: "repo-id"}); pr != want {
+			t.Errorf("%T: unexpected PR: want %+v, have %+v", ev, want, pr)
+		}
+		if ours != ev {
+			t.Errorf("%T: unexpected changeset event metadata %+v", ev, ours)
+		}
+	}
+
+	if pr, ours := h.convertEvent(struct{}{}); pr != (PR{}) || ours != nil {
+		t.Errorf("expected unknown event to be dropped, have %+v, %+v", pr, ours)
+	}
+}
+
+func TestAzureDevOpsWebhookMissingCredentials(t *testing.T) {
+	h := NewAzureDevOpsWebhook(nil)
+
+	req := httptest.NewRequest("POST", "/.api/azure-devops-webhooks", strings.NewReader(`{}`))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if have, want := rec.Code, http.StatusUnauthorized; have != want {
+		t.Errorf("unexpected status code: have %d, want %d", have, want)
+	}
+}
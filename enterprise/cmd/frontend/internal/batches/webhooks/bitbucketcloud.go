@@ -0,0 +1,148 @@
+package webhooks
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+	gh "github.com/google/go-github/v28/github"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type BitbucketCloudWebhook struct {
+	*Webhook
+}
+
+func NewBitbucketCloudWebhook(store *store.Store) *BitbucketCloudWebhook {
+	return &BitbucketCloudWebhook{
+		Webhook: &Webhook{store, extsvc.TypeBitbucketCloud},
+	}
+}
+
+func (h *BitbucketCloudWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e, extSvc, hErr := h.parseEvent(r)
+	if hErr != nil {
+		respond(w, hErr.code, hErr)
+		return
+	}
+
+	// 🚨 SECURITY: now that the signature has been validated, we can use an
+	// internal actor on the context.
+	ctx := actor.WithInternalActor(r.Context())
+
+	externalServiceID, err := extractExternalServiceID(extSvc)
+	if err != nil {
+		respond(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	pr, ev := h.convertEvent(e)
+	if pr == (PR{}) {
+		log15.Warn("Dropping Bitbucket Cloud webhook event", "type", fmt.Sprintf("%T", e))
+		return
+	}
+
+	if err := h.upsertChangesetEvent(ctx, externalServiceID, pr, ev); err != nil {
+		respond(w, http.StatusInternalServerError, err)
+	}
+}
+
+func (h *BitbucketCloudWebhook) parseEvent(r *http.Request) (interface{}, *types.ExternalService, *httpError) {
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, nil, &httpError{http.StatusInternalServerError, err}
+	}
+
+	sig := r.Header.Get(bitbucketcloud.SignatureHeader)
+	if sig == "" {
+		return nil, nil, &httpError{http.StatusUnauthorized, errors.New("missing signature")}
+	}
+
+	rawID := r.FormValue(extsvc.IDParam)
+	var externalServiceID int64
+	if rawID != "" {
+		externalServiceID, err = strconv.ParseInt(rawID, 10, 64)
+		if err != nil {
+			return nil, nil, &httpError{http.StatusBadRequest, errors.Wrap(err, "invalid external service id")}
+		}
+	}
+
+	args := database.ExternalServicesListOptions{Kinds: []string{extsvc.KindBitbucketCloud}}
+	if externalServiceID != 0 {
+		args.IDs = append(args.IDs, externalServiceID)
+	}
+	es, err := h.Store.ExternalServices().List(r.Context(), args)
+	if err != nil {
+		return nil, nil, &httpError{http.StatusInternalServerError, err}
+	}
+
+	// 🚨 SECURITY: Each external service has its own webhook secret, so we
+	// only accept the payload if its signature matches one of them.
+	var extSvc *types.ExternalService
+	for _, e := range es {
+		c, _ := e.Configuration()
+		con, ok := c.(*schema.BitbucketCloudConnection)
+		if !ok {
+			continue
+		}
+
+		if secret := con.WebhookSecret; secret != "" {
+			if err = gh.ValidateSignature(sig, payload, []byte(secret)); err == nil {
+				extSvc = e
+				break
+			}
+		}
+	}
+
+	if extSvc == nil || err != nil {
+		return nil, nil, &httpError{http.StatusUnauthorized, err}
+	}
+
+	e, err := bitbucketcloud.ParseWebhookEvent(bitbucketcloud.WebhookEventType(r), payload)
+	if err != nil {
+		return nil, nil, &httpError{http.StatusBadRequest, errors.Wrap(err, "parsing webhook")}
+	}
+	return e, extSvc, nil
+}
+
+func (h *BitbucketCloudWebhook) convertEvent(theirs interface{}) (pr PR, ours keyer) {
+	log15.Debug("Bitbucket Cloud webhook received", "type", fmt.Sprintf("%T", theirs))
+
+	switch e := theirs.(type) {
+	case *bitbucketcloud.PullRequestApprovedEvent:
+		return bitbucketCloudPR(&e.PullRequestEvent), e
+	case *bitbucketcloud.PullRequestUnapprovedEvent:
+		return bitbucketCloudPR(&e.PullRequestEvent), e
+	case *bitbucketcloud.PullRequestChangesRequestCreatedEvent:
+		return bitbucketCloudPR(&e.PullRequestEvent), e
+	case *bitbucketcloud.PullRequestChangesRequestRemovedEvent:
+		return bitbucketCloudPR(&e.PullRequestEvent), e
+	case *bitbucketcloud.PullRequestCommentEvent:
+		return bitbucketCloudPR(&e.PullRequestEvent), e
+	case *bitbucketcloud.PullRequestUpdatedEvent:
+		return bitbucketCloudPR(&e.PullRequestEvent), e
+	case *bitbucketcloud.PullRequestFulfilledEvent:
+		return bitbucketCloudPR(&e.PullRequestEvent), e
+	case *bitbucketcloud.PullRequestRejectedEvent:
+		return bitbucketCloudPR(&e.PullRequestEvent), e
+	}
+
+	return
+}
+
+// bitbucketCloudPR returns the PR for the given event. Changesets are stored
+// against the repository the pull request targets, which is the repository
+// the webhook is configured on.
+func bitbucketCloudPR(e *bitbucketcloud.PullRequestEvent) PR {
+	return PR{ID: e.PullRequest.ID, RepoExternalID: e.Repository.UUID}
+}
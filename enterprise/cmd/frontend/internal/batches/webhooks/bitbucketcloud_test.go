@@ -0,0 +1,39 @@
+package webhooks
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+)
+
+func TestBitbucketCloudWebhookConvertEvent(t *testing.T) {
+	h := NewBitbucketCloudWebhook(nil)
+
+	base := bitbucketcloud.PullRequestEvent{
+		PullRequest: bitbucketcloud.PullRequest{ID: 7},
+		Repository:  bitbucketcloud.Repo{UUID: "{repo}"},
+	}
+
+	for _, ev := range []interface{}{
+		&bitbucketcloud.PullRequestApprovedEvent{PullRequestEvent: base},
+		&bitbucketcloud.PullRequestUnapprovedEvent{PullRequestEvent: base},
+		&bitbucketcloud.PullRequestChangesRequestCreatedEvent{PullRequestEvent: base},
+		&bitbucketcloud.PullRequestChangesRequestRemovedEvent{PullRequestEvent: base},
+		&bitbucketcloud.PullRequestCommentEvent{PullRequestEvent: base},
+		&bitbucketcloud.PullRequestUpdatedEvent{PullRequestEvent: base},
+		&bitbucketcloud.PullRequestFulfilledEvent{PullRequestEvent: base},
+		&bitbucketcloud.PullRequestRejectedEvent{PullRequestEvent: base},
+	} {
+		pr, ours := h.convertEvent(ev)
+		if want := (PR{ID: 7, RepoExternalID: "{repo}"}); pr != want {
+			t.Errorf("%T: unexpected PR: want %+v, have %+v", ev, want, pr)
+		}
+		if ours != ev {
+			t.Errorf("%T: unexpected changeset event metadata %+v", ev, ours)
+		}
+	}
+
+	if pr, ours := h.convertEvent(struct{}{}); pr != (PR{}) || ours != nil {
+		t.Errorf("expected unknown event to be dropped, have %+v, %+v", pr, ours)
+	}
+}
@@ -37,6 +37,7 @@ func Init(ctx context.Context, db dbutil.DB, outOfBandMigrationRunner *oobmigrat
 
 	enterpriseServices.CodeIntelResolver = resolver
 	enterpriseServices.NewCodeIntelUploadHandler = uploadHandler
+	gql.RankingSignals = &rankingSignalProvider{store: services.dbStore}
 	return nil
 }
 
@@ -0,0 +1,37 @@
+package codeintel
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// referenceCountStore is the subset of the codeintel dbstore used to rank search results.
+type referenceCountStore interface {
+	ReferenceCountsAtTip(ctx context.Context, repositoryIDs []int) (map[int]map[string]int, error)
+}
+
+// rankingSignalProvider provides the reference counts of the code intelligence uploads
+// visible at the tip of the default branch of repositories as search ranking signals.
+type rankingSignalProvider struct {
+	store referenceCountStore
+}
+
+// ReferenceCounts implements graphqlbackend.RankingSignalProvider.
+func (p *rankingSignalProvider) ReferenceCounts(ctx context.Context, repos []api.RepoID) (map[api.RepoID]map[string]int, error) {
+	repositoryIDs := make([]int, 0, len(repos))
+	for _, id := range repos {
+		repositoryIDs = append(repositoryIDs, int(id))
+	}
+
+	counts, err := p.store.ReferenceCountsAtTip(ctx, repositoryIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	referenceCounts := make(map[api.RepoID]map[string]int, len(counts))
+	for id, countsByRoot := range counts {
+		referenceCounts[api.RepoID(id)] = countsByRoot
+	}
+	return referenceCounts, nil
+}
@@ -0,0 +1,59 @@
+package graphql
+
+import (
+	gql "github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/autoindex/enqueuer"
+	store "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+)
+
+type autoIndexJobsDryRunResolver struct {
+	dryRun enqueuer.DryRun
+}
+
+var _ gql.AutoIndexJobsDryRunResolver = &autoIndexJobsDryRunResolver{}
+
+func (r *autoIndexJobsDryRunResolver) Commit() string      { return r.dryRun.Commit }
+func (r *autoIndexJobsDryRunResolver) AlreadyQueued() bool { return r.dryRun.AlreadyQueued }
+func (r *autoIndexJobsDryRunResolver) ConfigurationSource() string {
+	return string(r.dryRun.ConfigurationSource)
+}
+
+func (r *autoIndexJobsDryRunResolver) Jobs() []gql.AutoIndexJobDescriptionResolver {
+	resolvers := make([]gql.AutoIndexJobDescriptionResolver, 0, len(r.dryRun.Indexes))
+	for _, index := range r.dryRun.Indexes {
+		resolvers = append(resolvers, &autoIndexJobDescriptionResolver{index: index})
+	}
+
+	return resolvers
+}
+
+type autoIndexJobDescriptionResolver struct {
+	index store.Index
+}
+
+var _ gql.AutoIndexJobDescriptionResolver = &autoIndexJobDescriptionResolver{}
+
+func (r *autoIndexJobDescriptionResolver) Root() string          { return r.index.Root }
+func (r *autoIndexJobDescriptionResolver) Indexer() string       { return r.index.Indexer }
+func (r *autoIndexJobDescriptionResolver) IndexerArgs() []string { return r.index.IndexerArgs }
+func (r *autoIndexJobDescriptionResolver) Outfile() *string      { return strPtr(r.index.Outfile) }
+func (r *autoIndexJobDescriptionResolver) LocalSteps() []string  { return r.index.LocalSteps }
+
+func (r *autoIndexJobDescriptionResolver) DockerSteps() []gql.AutoIndexJobDockerStepResolver {
+	resolvers := make([]gql.AutoIndexJobDockerStepResolver, 0, len(r.index.DockerSteps))
+	for _, step := range r.index.DockerSteps {
+		resolvers = append(resolvers, &autoIndexJobDockerStepResolver{step: step})
+	}
+
+	return resolvers
+}
+
+type autoIndexJobDockerStepResolver struct {
+	step store.DockerStep
+}
+
+var _ gql.AutoIndexJobDockerStepResolver = &autoIndexJobDockerStepResolver{}
+
+func (r *autoIndexJobDockerStepResolver) Root() string       { return r.step.Root }
+func (r *autoIndexJobDockerStepResolver) Image() string      { return r.step.Image }
+func (r *autoIndexJobDockerStepResolver) Commands() []string { return r.step.Commands }
@@ -61,6 +61,10 @@ func (r *configurationPolicyResolver) RetainIntermediateCommits() bool {
 	return r.configurationPolicy.RetainIntermediateCommits
 }
 
+func (r *configurationPolicyResolver) RetainMostRecentUploads() *int32 {
+	return toInt32(r.configurationPolicy.RetainMostRecentUploads)
+}
+
 func (r *configurationPolicyResolver) IndexingEnabled() bool {
 	return r.configurationPolicy.IndexingEnabled
 }
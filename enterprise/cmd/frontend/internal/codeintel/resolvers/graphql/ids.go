@@ -38,6 +38,15 @@ func unmarshalLSIFIndexGQLID(id graphql.ID) (indexID int64, err error) {
 	return indexID, err
 }
 
+func marshalLSIFIndexRequestGQLID(indexRequestID int64) graphql.ID {
+	return relay.MarshalID("LSIFIndexRequest", indexRequestID)
+}
+
+func unmarshalLSIFIndexRequestGQLID(id graphql.ID) (indexRequestID int64, err error) {
+	err = relay.UnmarshalSpec(id, &indexRequestID)
+	return indexRequestID, err
+}
+
 func marshalConfigurationPolicyGQLID(configurationPolicyID int64) graphql.ID {
 	return relay.MarshalID("CodeIntelligenceConfigurationPolicy", configurationPolicyID)
 }
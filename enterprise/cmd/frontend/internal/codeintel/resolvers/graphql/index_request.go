@@ -0,0 +1,192 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+
+	"github.com/graph-gophers/graphql-go"
+
+	gql "github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel/resolvers"
+	store "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+)
+
+// The states of an index request, from least to most advanced.
+const (
+	indexRequestStateInferring  = "INFERRING"
+	indexRequestStateQueued     = "QUEUED"
+	indexRequestStateProcessing = "PROCESSING"
+	indexRequestStateUploaded   = "UPLOADED"
+	indexRequestStateProcessed  = "PROCESSED"
+	indexRequestStateErrored    = "ERRORED"
+)
+
+var indexRequestStateRanks = map[string]int{
+	indexRequestStateInferring:  0,
+	indexRequestStateQueued:     1,
+	indexRequestStateProcessing: 2,
+	indexRequestStateUploaded:   3,
+	indexRequestStateProcessed:  4,
+}
+
+var missingIndexFailure = "an index job of the request no longer exists"
+
+type indexRequestResolver struct {
+	resolver         resolvers.Resolver
+	indexRequest     store.IndexRequest
+	prefetcher       *Prefetcher
+	locationResolver *CachedLocationResolver
+
+	once    sync.Once
+	indexes []store.Index
+	state   string
+	failure *string
+	err     error
+}
+
+var _ gql.LSIFIndexRequestResolver = &indexRequestResolver{}
+
+func NewIndexRequestResolver(resolver resolvers.Resolver, indexRequest store.IndexRequest, prefetcher *Prefetcher, locationResolver *CachedLocationResolver) gql.LSIFIndexRequestResolver {
+	for _, id := range indexRequest.IndexIDs {
+		prefetcher.MarkIndex(id)
+	}
+
+	return &indexRequestResolver{
+		resolver:         resolver,
+		indexRequest:     indexRequest,
+		prefetcher:       prefetcher,
+		locationResolver: locationResolver,
+	}
+}
+
+func (r *indexRequestResolver) ID() graphql.ID {
+	return marshalLSIFIndexRequestGQLID(int64(r.indexRequest.ID))
+}
+
+func (r *indexRequestResolver) InputRev() string    { return r.indexRequest.Rev }
+func (r *indexRequestResolver) InputCommit() string { return r.indexRequest.Commit }
+func (r *indexRequestResolver) RequestedAt() gql.DateTime {
+	return gql.DateTime{Time: r.indexRequest.RequestedAt}
+}
+
+func (r *indexRequestResolver) State(ctx context.Context) (string, error) {
+	if err := r.resolve(ctx); err != nil {
+		return "", err
+	}
+
+	return r.state, nil
+}
+
+func (r *indexRequestResolver) Failure(ctx context.Context) (*string, error) {
+	if err := r.resolve(ctx); err != nil {
+		return nil, err
+	}
+
+	return r.failure, nil
+}
+
+func (r *indexRequestResolver) Indexes(ctx context.Context) ([]gql.LSIFIndexResolver, error) {
+	if err := r.resolve(ctx); err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]gql.LSIFIndexResolver, 0, len(r.indexes))
+	for i := range r.indexes {
+		resolvers = append(resolvers, NewIndexResolver(r.resolver, r.indexes[i], r.prefetcher, r.locationResolver))
+	}
+	return resolvers, nil
+}
+
+// resolve fetches the index jobs of the request and their uploads, then determines the state
+// of the request. The result is shared by the State, Failure, and Indexes fields.
+func (r *indexRequestResolver) resolve(ctx context.Context) error {
+	r.once.Do(func() {
+		uploads := map[int]store.Upload{}
+
+		for _, id := range r.indexRequest.IndexIDs {
+			index, exists, err := r.prefetcher.GetIndexByID(ctx, id)
+			if err != nil {
+				r.err = err
+				return
+			}
+			if !exists {
+				continue
+			}
+			r.indexes = append(r.indexes, index)
+
+			if index.AssociatedUploadID != nil {
+				upload, exists, err := r.prefetcher.GetUploadByID(ctx, *index.AssociatedUploadID)
+				if err != nil {
+					r.err = err
+					return
+				}
+				if exists {
+					uploads[upload.ID] = upload
+				}
+			}
+		}
+
+		r.state, r.failure = indexRequestProgress(r.indexRequest, r.indexes, uploads)
+	})
+
+	return r.err
+}
+
+// indexRequestProgress determines the state of an index request from the states of its index jobs
+// and their uploads, along with the failure message of the request if it has errored. The request
+// is only as far along as its least advanced index job, unless any of them has failed.
+func indexRequestProgress(indexRequest store.IndexRequest, indexes []store.Index, uploads map[int]store.Upload) (string, *string) {
+	if indexRequest.InferredAt == nil {
+		return indexRequestStateInferring, nil
+	}
+	if indexRequest.FailureMessage != nil {
+		return indexRequestStateErrored, indexRequest.FailureMessage
+	}
+	if len(indexes) != len(indexRequest.IndexIDs) {
+		return indexRequestStateErrored, &missingIndexFailure
+	}
+
+	state := indexRequestStateProcessed
+	for _, index := range indexes {
+		indexState, failure := indexProgress(index, uploads)
+		if indexState == indexRequestStateErrored {
+			return indexState, failure
+		}
+		if indexRequestStateRanks[indexState] < indexRequestStateRanks[state] {
+			state = indexState
+		}
+	}
+
+	return state, nil
+}
+
+// indexProgress determines the progress of a single index job of an index request.
+func indexProgress(index store.Index, uploads map[int]store.Upload) (string, *string) {
+	switch index.State {
+	case "queued":
+		return indexRequestStateQueued, nil
+	case "processing":
+		return indexRequestStateProcessing, nil
+	case "errored", "failed":
+		return indexRequestStateErrored, index.FailureMessage
+	}
+
+	if index.AssociatedUploadID == nil {
+		return indexRequestStateUploaded, nil
+	}
+	upload, ok := uploads[*index.AssociatedUploadID]
+	if !ok {
+		// Deleted uploads are not returned. They have been processed and then replaced by
+		// a newer upload or expired.
+		return indexRequestStateProcessed, nil
+	}
+
+	switch upload.State {
+	case "uploading", "queued", "processing":
+		return indexRequestStateUploaded, nil
+	case "errored", "failed":
+		return indexRequestStateErrored, upload.FailureMessage
+	}
+
+	return indexRequestStateProcessed, nil
+}
@@ -0,0 +1,104 @@
+package graphql
+
+import (
+	"testing"
+	"time"
+
+	store "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+)
+
+func TestIndexRequestProgress(t *testing.T) {
+	now := time.Now()
+	failure := "oops"
+
+	inferred := store.IndexRequest{InferredAt: &now, IndexIDs: []int{1, 2}}
+	index := func(id int, state string, uploadID int) store.Index {
+		index := store.Index{ID: id, State: state}
+		if uploadID != 0 {
+			index.AssociatedUploadID = &uploadID
+		}
+		return index
+	}
+	uploads := map[int]store.Upload{
+		10: {ID: 10, State: "queued"},
+		11: {ID: 11, State: "completed"},
+		12: {ID: 12, State: "errored", FailureMessage: &failure},
+	}
+
+	testCases := []struct {
+		name          string
+		indexRequest  store.IndexRequest
+		indexes       []store.Index
+		expectedState string
+		failed        bool
+	}{
+		{
+			name:          "inferring",
+			indexRequest:  store.IndexRequest{IndexIDs: []int{}},
+			expectedState: "INFERRING",
+		},
+		{
+			name:          "no index jobs",
+			indexRequest:  store.IndexRequest{InferredAt: &now, FailureMessage: &failure, IndexIDs: []int{}},
+			expectedState: "ERRORED",
+			failed:        true,
+		},
+		{
+			name:          "queued",
+			indexRequest:  inferred,
+			indexes:       []store.Index{index(1, "processing", 0), index(2, "queued", 0)},
+			expectedState: "QUEUED",
+		},
+		{
+			name:          "processing",
+			indexRequest:  inferred,
+			indexes:       []store.Index{index(1, "processing", 0), index(2, "completed", 11)},
+			expectedState: "PROCESSING",
+		},
+		{
+			name:          "uploaded",
+			indexRequest:  inferred,
+			indexes:       []store.Index{index(1, "completed", 10), index(2, "completed", 11)},
+			expectedState: "UPLOADED",
+		},
+		{
+			name:          "processed",
+			indexRequest:  inferred,
+			indexes:       []store.Index{index(1, "completed", 11), index(2, "completed", 13)},
+			expectedState: "PROCESSED",
+		},
+		{
+			name:          "index errored",
+			indexRequest:  inferred,
+			indexes:       []store.Index{index(1, "queued", 0), {ID: 2, State: "failed", FailureMessage: &failure}},
+			expectedState: "ERRORED",
+			failed:        true,
+		},
+		{
+			name:          "upload errored",
+			indexRequest:  inferred,
+			indexes:       []store.Index{index(1, "queued", 0), index(2, "completed", 12)},
+			expectedState: "ERRORED",
+			failed:        true,
+		},
+		{
+			name:          "index deleted",
+			indexRequest:  inferred,
+			indexes:       []store.Index{index(1, "completed", 11)},
+			expectedState: "ERRORED",
+			failed:        true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			state, failureMessage := indexRequestProgress(testCase.indexRequest, testCase.indexes, uploads)
+			if state != testCase.expectedState {
+				t.Errorf("unexpected state. want=%q have=%q", testCase.expectedState, state)
+			}
+			if testCase.failed != (failureMessage != nil) {
+				t.Errorf("unexpected failure message. want=%v have=%v", testCase.failed, failureMessage)
+			}
+		})
+	}
+}
@@ -50,6 +50,9 @@ func (r *Resolver) NodeResolvers() map[string]gql.NodeByIDFunc {
 		"LSIFIndex": func(ctx context.Context, id graphql.ID) (gql.Node, error) {
 			return r.LSIFIndexByID(ctx, id)
 		},
+		"LSIFIndexRequest": func(ctx context.Context, id graphql.ID) (gql.Node, error) {
+			return r.LSIFIndexRequestByID(ctx, id)
+		},
 		"CodeIntelligenceConfigurationPolicy": func(ctx context.Context, id graphql.ID) (gql.Node, error) {
 			return r.ConfigurationPolicyByID(ctx, id)
 		},
@@ -237,6 +240,97 @@ func (r *Resolver) QueueAutoIndexJobsForRepo(ctx context.Context, args *gql.Queu
 	return resolvers, nil
 }
 
+// 🚨 SECURITY: Only site admins may queue auto-index jobs
+func (r *Resolver) RequestIndexing(ctx context.Context, args *gql.RequestIndexingArgs) (gql.LSIFIndexRequestResolver, error) {
+	if err := checkCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if !autoIndexingEnabled() {
+		return nil, errAutoIndexingNotEnabled
+	}
+
+	repositoryID, err := gql.UnmarshalRepositoryID(args.Repository)
+	if err != nil {
+		return nil, err
+	}
+
+	rev := "HEAD"
+	if args.Rev != nil {
+		rev = *args.Rev
+	}
+
+	configuration := ""
+	if args.Configuration != nil {
+		configuration = *args.Configuration
+	}
+
+	indexRequest, err := r.resolver.RequestIndexing(ctx, int(repositoryID), rev, configuration)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new prefetcher here as we only want to cache upload and index records in
+	// the same graphQL request, not across different request.
+	prefetcher := NewPrefetcher(r.resolver)
+
+	return NewIndexRequestResolver(r.resolver, indexRequest, prefetcher, r.locationResolver), nil
+}
+
+// 🚨 SECURITY: dbstore layer handles authz for GetIndexRequestByID
+func (r *Resolver) LSIFIndexRequestByID(ctx context.Context, id graphql.ID) (gql.LSIFIndexRequestResolver, error) {
+	if !autoIndexingEnabled() {
+		return nil, errAutoIndexingNotEnabled
+	}
+
+	indexRequestID, err := unmarshalLSIFIndexRequestGQLID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	indexRequest, exists, err := r.resolver.GetIndexRequestByID(ctx, int(indexRequestID))
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	// Create a new prefetcher here as we only want to cache upload and index records in
+	// the same graphQL request, not across different request.
+	prefetcher := NewPrefetcher(r.resolver)
+
+	return NewIndexRequestResolver(r.resolver, indexRequest, prefetcher, r.locationResolver), nil
+}
+
+// 🚨 SECURITY: Only site admins may inspect the auto-index jobs of a repository
+func (r *Resolver) AutoIndexJobsDryRun(ctx context.Context, args *gql.AutoIndexJobsDryRunArgs) (gql.AutoIndexJobsDryRunResolver, error) {
+	if err := checkCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if !autoIndexingEnabled() {
+		return nil, errAutoIndexingNotEnabled
+	}
+
+	repositoryID, err := gql.UnmarshalRepositoryID(args.Repository)
+	if err != nil {
+		return nil, err
+	}
+
+	rev := "HEAD"
+	if args.Rev != nil {
+		rev = *args.Rev
+	}
+
+	configuration := ""
+	if args.Configuration != nil {
+		configuration = *args.Configuration
+	}
+
+	dryRun, err := r.resolver.DryRunAutoIndexJobsForRepo(ctx, int(repositoryID), rev, configuration)
+	if err != nil {
+		return nil, err
+	}
+
+	return &autoIndexJobsDryRunResolver{dryRun: dryRun}, nil
+}
+
 // 🚨 SECURITY: dbstore layer handles authz for query resolution
 func (r *Resolver) GitBlobLSIFData(ctx context.Context, args *gql.GitBlobLSIFDataArgs) (gql.GitBlobLSIFDataResolver, error) {
 	resolver, err := r.resolver.QueryResolver(ctx, args)
@@ -315,6 +409,7 @@ func (r *Resolver) CreateCodeIntelligenceConfigurationPolicy(ctx context.Context
 		RetentionEnabled:          args.RetentionEnabled,
 		RetentionDuration:         toDuration(args.RetentionDurationHours),
 		RetainIntermediateCommits: args.RetainIntermediateCommits,
+		RetainMostRecentUploads:   toInt(args.RetainMostRecentUploads),
 		IndexingEnabled:           args.IndexingEnabled,
 		IndexCommitMaxAge:         toDuration(args.IndexCommitMaxAgeHours),
 		IndexIntermediateCommits:  args.IndexIntermediateCommits,
@@ -349,6 +444,7 @@ func (r *Resolver) UpdateCodeIntelligenceConfigurationPolicy(ctx context.Context
 		RetentionEnabled:          args.RetentionEnabled,
 		RetentionDuration:         toDuration(args.RetentionDurationHours),
 		RetainIntermediateCommits: args.RetainIntermediateCommits,
+		RetainMostRecentUploads:   toInt(args.RetainMostRecentUploads),
 		IndexingEnabled:           args.IndexingEnabled,
 		IndexCommitMaxAge:         toDuration(args.IndexCommitMaxAgeHours),
 		IndexIntermediateCommits:  args.IndexIntermediateCommits,
@@ -544,6 +640,9 @@ func validateConfigurationPolicy(policy gql.CodeIntelConfigurationPolicy) error
 	if policy.RetentionDurationHours != nil && *policy.RetentionDurationHours <= 0 {
 		return errors.Errorf("illegal retention duration '%d'", *policy.RetentionDurationHours)
 	}
+	if policy.RetainMostRecentUploads != nil && *policy.RetainMostRecentUploads <= 0 {
+		return errors.Errorf("illegal number of most recent uploads to retain '%d'", *policy.RetainMostRecentUploads)
+	}
 	if policy.IndexCommitMaxAgeHours != nil && *policy.IndexCommitMaxAgeHours <= 0 {
 		return errors.Errorf("illegal index commit max age '%d'", *policy.IndexCommitMaxAgeHours)
 	}
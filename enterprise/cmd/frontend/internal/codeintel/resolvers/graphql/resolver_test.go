@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/base64"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/graph-gophers/graphql-go"
@@ -12,6 +13,7 @@ import (
 	gql "github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
 	resolvermocks "github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel/resolvers/mocks"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/autoindex/enqueuer"
 	store "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database"
@@ -95,6 +97,114 @@ func TestDeleteLSIFIndexUnauthenticated(t *testing.T) {
 	}
 }
 
+func TestAutoIndexJobsDryRun(t *testing.T) {
+	db := new(dbtesting.MockDB)
+
+	t.Cleanup(func() {
+		database.Mocks.Users.GetByCurrentAuthUser = nil
+	})
+	database.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{SiteAdmin: true}, nil
+	}
+
+	mockResolver := resolvermocks.NewMockResolver()
+	mockResolver.DryRunAutoIndexJobsForRepoFunc.SetDefaultReturn(enqueuer.DryRun{
+		Commit:              "deadbeef",
+		ConfigurationSource: enqueuer.ConfigurationSourceInferred,
+		Indexes:             []store.Index{{Root: "a", Indexer: "lsif-go"}},
+	}, nil)
+
+	args := &gql.AutoIndexJobsDryRunArgs{Repository: graphql.ID(base64.StdEncoding.EncodeToString([]byte("Repository:42")))}
+	dryRun, err := NewResolver(db, mockResolver).AutoIndexJobsDryRun(context.Background(), args)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(mockResolver.DryRunAutoIndexJobsForRepoFunc.History()) != 1 {
+		t.Fatalf("unexpected call count. want=%d have=%d", 1, len(mockResolver.DryRunAutoIndexJobsForRepoFunc.History()))
+	}
+	if call := mockResolver.DryRunAutoIndexJobsForRepoFunc.History()[0]; call.Arg1 != 42 || call.Arg2 != "HEAD" {
+		t.Fatalf("unexpected arguments. want=(%d, %q) have=(%d, %q)", 42, "HEAD", call.Arg1, call.Arg2)
+	}
+	if source := dryRun.ConfigurationSource(); source != "INFERRED" {
+		t.Errorf("unexpected configuration source. want=%q have=%q", "INFERRED", source)
+	}
+	if jobs := dryRun.Jobs(); len(jobs) != 1 || jobs[0].Root() != "a" || jobs[0].Indexer() != "lsif-go" {
+		t.Errorf("unexpected jobs: %v", jobs)
+	}
+}
+
+func TestAutoIndexJobsDryRunUnauthenticated(t *testing.T) {
+	db := new(dbtesting.MockDB)
+
+	args := &gql.AutoIndexJobsDryRunArgs{Repository: graphql.ID(base64.StdEncoding.EncodeToString([]byte("Repository:42")))}
+	mockResolver := resolvermocks.NewMockResolver()
+
+	if _, err := NewResolver(db, mockResolver).AutoIndexJobsDryRun(context.Background(), args); err != backend.ErrNotAuthenticated {
+		t.Errorf("unexpected error. want=%q have=%q", backend.ErrNotAuthenticated, err)
+	}
+}
+
+func TestRequestIndexing(t *testing.T) {
+	db := new(dbtesting.MockDB)
+
+	t.Cleanup(func() {
+		database.Mocks.Users.GetByCurrentAuthUser = nil
+	})
+	database.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{SiteAdmin: true}, nil
+	}
+
+	now := time.Now()
+	mockResolver := resolvermocks.NewMockResolver()
+	mockResolver.RequestIndexingFunc.SetDefaultReturn(store.IndexRequest{
+		ID:         7,
+		Commit:     "deadbeef",
+		Rev:        "main",
+		InferredAt: &now,
+		IndexIDs:   []int{1},
+	}, nil)
+	mockResolver.GetIndexesByIDsFunc.SetDefaultReturn([]store.Index{{ID: 1, State: "processing"}}, nil)
+
+	rev := "main"
+	args := &gql.RequestIndexingArgs{Repository: graphql.ID(base64.StdEncoding.EncodeToString([]byte("Repository:42"))), Rev: &rev}
+	indexRequest, err := NewResolver(db, mockResolver).RequestIndexing(context.Background(), args)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(mockResolver.RequestIndexingFunc.History()) != 1 {
+		t.Fatalf("unexpected call count. want=%d have=%d", 1, len(mockResolver.RequestIndexingFunc.History()))
+	}
+	if call := mockResolver.RequestIndexingFunc.History()[0]; call.Arg1 != 42 || call.Arg2 != "main" {
+		t.Fatalf("unexpected arguments. want=(%d, %q) have=(%d, %q)", 42, "main", call.Arg1, call.Arg2)
+	}
+	if id, err := unmarshalLSIFIndexRequestGQLID(indexRequest.ID()); err != nil || id != 7 {
+		t.Errorf("unexpected id. want=%d have=%d", 7, id)
+	}
+	if state, err := indexRequest.State(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if state != "PROCESSING" {
+		t.Errorf("unexpected state. want=%q have=%q", "PROCESSING", state)
+	}
+	if indexes, err := indexRequest.Indexes(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if len(indexes) != 1 {
+		t.Errorf("unexpected number of indexes. want=%d have=%d", 1, len(indexes))
+	}
+}
+
+func TestRequestIndexingUnauthenticated(t *testing.T) {
+	db := new(dbtesting.MockDB)
+
+	args := &gql.RequestIndexingArgs{Repository: graphql.ID(base64.StdEncoding.EncodeToString([]byte("Repository:42")))}
+	mockResolver := resolvermocks.NewMockResolver()
+
+	if _, err := NewResolver(db, mockResolver).RequestIndexing(context.Background(), args); err != backend.ErrNotAuthenticated {
+		t.Errorf("unexpected error. want=%q have=%q", backend.ErrNotAuthenticated, err)
+	}
+}
+
 func TestMakeGetUploadsOptions(t *testing.T) {
 	t.Cleanup(func() {
 		database.Mocks.Repos.Get = nil
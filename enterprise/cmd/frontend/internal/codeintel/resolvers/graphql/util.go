@@ -35,6 +35,16 @@ func toInt32(val *int) *int32 {
 	return &v
 }
 
+// toInt translates the given int32 pointer into an int pointer.
+func toInt(val *int32) *int {
+	if val == nil {
+		return nil
+	}
+
+	v := int(*val)
+	return &v
+}
+
 // derefString returns the underlying value in the given pointer.
 // If the pointer is nil, the default value is returned.
 func derefString(val *string, defaultValue string) string {
@@ -38,6 +38,7 @@ type DBStore interface {
 	GetIndexesByIDs(ctx context.Context, ids ...int) ([]dbstore.Index, error)
 	GetIndexes(ctx context.Context, opts dbstore.GetIndexesOptions) ([]dbstore.Index, int, error)
 	DeleteIndexByID(ctx context.Context, id int) (bool, error)
+	GetIndexRequestByID(ctx context.Context, id int) (dbstore.IndexRequest, bool, error)
 	GetConfigurationPolicies(ctx context.Context, opts store.GetConfigurationPoliciesOptions) ([]store.ConfigurationPolicy, error)
 	GetConfigurationPolicyByID(ctx context.Context, id int) (store.ConfigurationPolicy, bool, error)
 	CreateConfigurationPolicy(ctx context.Context, configurationPolicy store.ConfigurationPolicy) (store.ConfigurationPolicy, error)
@@ -67,6 +68,8 @@ type LSIFStore interface {
 type IndexEnqueuer interface {
 	QueueIndexes(ctx context.Context, repositoryID int, rev, configuration string, force bool) ([]dbstore.Index, error)
 	InferIndexConfiguration(ctx context.Context, repositoryID int) (*config.IndexConfiguration, error)
+	DryRunIndexes(ctx context.Context, repositoryID int, rev, configuration string) (enqueuer.DryRun, error)
+	RequestIndexes(ctx context.Context, repositoryID int, rev, configuration string) (int, error)
 }
 
 type RepoUpdaterClient = enqueuer.RepoUpdaterClient
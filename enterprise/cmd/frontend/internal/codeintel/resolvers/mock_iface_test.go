@@ -68,6 +68,9 @@ type MockDBStore struct {
 	// function object controlling the behavior of the method
 	// GetIndexConfigurationByRepositoryID.
 	GetIndexConfigurationByRepositoryIDFunc *DBStoreGetIndexConfigurationByRepositoryIDFunc
+	// GetIndexRequestByIDFunc is an instance of a mock function object
+	// controlling the behavior of the method GetIndexRequestByID.
+	GetIndexRequestByIDFunc *DBStoreGetIndexRequestByIDFunc
 	// GetIndexesFunc is an instance of a mock function object controlling
 	// the behavior of the method GetIndexes.
 	GetIndexesFunc *DBStoreGetIndexesFunc
@@ -177,6 +180,11 @@ func NewMockDBStore() *MockDBStore {
 				return dbstore.IndexConfiguration{}, false, nil
 			},
 		},
+		GetIndexRequestByIDFunc: &DBStoreGetIndexRequestByIDFunc{
+			defaultHook: func(context.Context, int) (dbstore.IndexRequest, bool, error) {
+				return dbstore.IndexRequest{}, false, nil
+			},
+		},
 		GetIndexesFunc: &DBStoreGetIndexesFunc{
 			defaultHook: func(context.Context, dbstore.GetIndexesOptions) ([]dbstore.Index, int, error) {
 				return nil, 0, nil
@@ -283,6 +291,9 @@ func NewMockDBStoreFrom(i DBStore) *MockDBStore {
 		GetIndexConfigurationByRepositoryIDFunc: &DBStoreGetIndexConfigurationByRepositoryIDFunc{
 			defaultHook: i.GetIndexConfigurationByRepositoryID,
 		},
+		GetIndexRequestByIDFunc: &DBStoreGetIndexRequestByIDFunc{
+			defaultHook: i.GetIndexRequestByID,
+		},
 		GetIndexesFunc: &DBStoreGetIndexesFunc{
 			defaultHook: i.GetIndexes,
 		},
@@ -1797,6 +1808,118 @@ func (c DBStoreGetIndexConfigurationByRepositoryIDFuncCall) Results() []interfac
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
+// DBStoreGetIndexRequestByIDFunc describes the behavior when the
+// GetIndexRequestByID method of the parent MockDBStore instance is invoked.
+type DBStoreGetIndexRequestByIDFunc struct {
+	defaultHook func(context.Context, int) (dbstore.IndexRequest, bool, error)
+	hooks       []func(context.Context, int) (dbstore.IndexRequest, bool, error)
+	history     []DBStoreGetIndexRequestByIDFuncCall
+	mutex       sync.Mutex
+}
+
+// GetIndexRequestByID delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockDBStore) GetIndexRequestByID(v0 context.Context, v1 int) (dbstore.IndexRequest, bool, error) {
+	r0, r1, r2 := m.GetIndexRequestByIDFunc.nextHook()(v0, v1)
+	m.GetIndexRequestByIDFunc.appendCall(DBStoreGetIndexRequestByIDFuncCall{v0, v1, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the GetIndexRequestByID
+// method of the parent MockDBStore instance is invoked and the hook queue
+// is empty.
+func (f *DBStoreGetIndexRequestByIDFunc) SetDefaultHook(hook func(context.Context, int) (dbstore.IndexRequest, bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetIndexRequestByID method of the parent MockDBStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *DBStoreGetIndexRequestByIDFunc) PushHook(hook func(context.Context, int) (dbstore.IndexRequest, bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DBStoreGetIndexRequestByIDFunc) SetDefaultReturn(r0 dbstore.IndexRequest, r1 bool, r2 error) {
+	f.SetDefaultHook(func(context.Context, int) (dbstore.IndexRequest, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DBStoreGetIndexRequestByIDFunc) PushReturn(r0 dbstore.IndexRequest, r1 bool, r2 error) {
+	f.PushHook(func(context.Context, int) (dbstore.IndexRequest, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *DBStoreGetIndexRequestByIDFunc) nextHook() func(context.Context, int) (dbstore.IndexRequest, bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBStoreGetIndexRequestByIDFunc) appendCall(r0 DBStoreGetIndexRequestByIDFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DBStoreGetIndexRequestByIDFuncCall objects
+// describing the invocations of this function.
+func (f *DBStoreGetIndexRequestByIDFunc) History() []DBStoreGetIndexRequestByIDFuncCall {
+	f.mutex.Lock()
+	history := make([]DBStoreGetIndexRequestByIDFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBStoreGetIndexRequestByIDFuncCall is an object that describes an
+// invocation of method GetIndexRequestByID on an instance of MockDBStore.
+type DBStoreGetIndexRequestByIDFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 dbstore.IndexRequest
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 bool
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBStoreGetIndexRequestByIDFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBStoreGetIndexRequestByIDFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
 // DBStoreGetIndexesFunc describes the behavior when the GetIndexes method
 // of the parent MockDBStore instance is invoked.
 type DBStoreGetIndexesFunc struct {
@@ -3168,12 +3291,18 @@ type MockEnqueuerDBStore struct {
 	// HandleFunc is an instance of a mock function object controlling the
 	// behavior of the method Handle.
 	HandleFunc *EnqueuerDBStoreHandleFunc
+	// InsertIndexRequestFunc is an instance of a mock function object
+	// controlling the behavior of the method InsertIndexRequest.
+	InsertIndexRequestFunc *EnqueuerDBStoreInsertIndexRequestFunc
 	// InsertIndexesFunc is an instance of a mock function object
 	// controlling the behavior of the method InsertIndexes.
 	InsertIndexesFunc *EnqueuerDBStoreInsertIndexesFunc
 	// IsQueuedFunc is an instance of a mock function object controlling the
 	// behavior of the method IsQueued.
 	IsQueuedFunc *EnqueuerDBStoreIsQueuedFunc
+	// MarkIndexRequestInferredFunc is an instance of a mock function object
+	// controlling the behavior of the method MarkIndexRequestInferred.
+	MarkIndexRequestInferredFunc *EnqueuerDBStoreMarkIndexRequestInferredFunc
 	// TransactFunc is an instance of a mock function object controlling the
 	// behavior of the method Transact.
 	TransactFunc *EnqueuerDBStoreTransactFunc
@@ -3209,6 +3338,11 @@ func NewMockEnqueuerDBStore() *MockEnqueuerDBStore {
 				return nil
 			},
 		},
+		InsertIndexRequestFunc: &EnqueuerDBStoreInsertIndexRequestFunc{
+			defaultHook: func(context.Context, int, string, string) (int, error) {
+				return 0, nil
+			},
+		},
 		InsertIndexesFunc: &EnqueuerDBStoreInsertIndexesFunc{
 			defaultHook: func(context.Context, []dbstore.Index) ([]dbstore.Index, error) {
 				return nil, nil
@@ -3219,6 +3353,11 @@ func NewMockEnqueuerDBStore() *MockEnqueuerDBStore {
 				return false, nil
 			},
 		},
+		MarkIndexRequestInferredFunc: &EnqueuerDBStoreMarkIndexRequestInferredFunc{
+			defaultHook: func(context.Context, int, []int, *string) error {
+				return nil
+			},
+		},
 		TransactFunc: &EnqueuerDBStoreTransactFunc{
 			defaultHook: func(context.Context) (enqueuer.DBStore, error) {
 				return nil, nil
@@ -3247,12 +3386,18 @@ func NewMockEnqueuerDBStoreFrom(i EnqueuerDBStore) *MockEnqueuerDBStore {
 		HandleFunc: &EnqueuerDBStoreHandleFunc{
 			defaultHook: i.Handle,
 		},
+		InsertIndexRequestFunc: &EnqueuerDBStoreInsertIndexRequestFunc{
+			defaultHook: i.InsertIndexRequest,
+		},
 		InsertIndexesFunc: &EnqueuerDBStoreInsertIndexesFunc{
 			defaultHook: i.InsertIndexes,
 		},
 		IsQueuedFunc: &EnqueuerDBStoreIsQueuedFunc{
 			defaultHook: i.IsQueued,
 		},
+		MarkIndexRequestInferredFunc: &EnqueuerDBStoreMarkIndexRequestInferredFunc{
+			defaultHook: i.MarkIndexRequestInferred,
+		},
 		TransactFunc: &EnqueuerDBStoreTransactFunc{
 			defaultHook: i.Transact,
 		},
@@ -3807,6 +3952,124 @@ func (c EnqueuerDBStoreHandleFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// EnqueuerDBStoreInsertIndexRequestFunc describes the behavior when the
+// InsertIndexRequest method of the parent MockEnqueuerDBStore instance is
+// invoked.
+type EnqueuerDBStoreInsertIndexRequestFunc struct {
+	defaultHook func(context.Context, int, string, string) (int, error)
+	hooks       []func(context.Context, int, string, string) (int, error)
+	history     []EnqueuerDBStoreInsertIndexRequestFuncCall
+	mutex       sync.Mutex
+}
+
+// InsertIndexRequest delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockEnqueuerDBStore) InsertIndexRequest(v0 context.Context, v1 int, v2 string, v3 string) (int, error) {
+	r0, r1 := m.InsertIndexRequestFunc.nextHook()(v0, v1, v2, v3)
+	m.InsertIndexRequestFunc.appendCall(EnqueuerDBStoreInsertIndexRequestFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the InsertIndexRequest
+// method of the parent MockEnqueuerDBStore instance is invoked and the hook
+// queue is empty.
+func (f *EnqueuerDBStoreInsertIndexRequestFunc) SetDefaultHook(hook func(context.Context, int, string, string) (int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// InsertIndexRequest method of the parent MockEnqueuerDBStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *EnqueuerDBStoreInsertIndexRequestFunc) PushHook(hook func(context.Context, int, string, string) (int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *EnqueuerDBStoreInsertIndexRequestFunc) SetDefaultReturn(r0 int, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, string, string) (int, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *EnqueuerDBStoreInsertIndexRequestFunc) PushReturn(r0 int, r1 error) {
+	f.PushHook(func(context.Context, int, string, string) (int, error) {
+		return r0, r1
+	})
+}
+
+func (f *EnqueuerDBStoreInsertIndexRequestFunc) nextHook() func(context.Context, int, string, string) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *EnqueuerDBStoreInsertIndexRequestFunc) appendCall(r0 EnqueuerDBStoreInsertIndexRequestFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of EnqueuerDBStoreInsertIndexRequestFuncCall
+// objects describing the invocations of this function.
+func (f *EnqueuerDBStoreInsertIndexRequestFunc) History() []EnqueuerDBStoreInsertIndexRequestFuncCall {
+	f.mutex.Lock()
+	history := make([]EnqueuerDBStoreInsertIndexRequestFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// EnqueuerDBStoreInsertIndexRequestFuncCall is an object that describes an
+// invocation of method InsertIndexRequest on an instance of
+// MockEnqueuerDBStore.
+type EnqueuerDBStoreInsertIndexRequestFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c EnqueuerDBStoreInsertIndexRequestFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c EnqueuerDBStoreInsertIndexRequestFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // EnqueuerDBStoreInsertIndexesFunc describes the behavior when the
 // InsertIndexes method of the parent MockEnqueuerDBStore instance is
 // invoked.
@@ -4029,6 +4292,122 @@ func (c EnqueuerDBStoreIsQueuedFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// EnqueuerDBStoreMarkIndexRequestInferredFunc describes the behavior when
+// the MarkIndexRequestInferred method of the parent MockEnqueuerDBStore
+// instance is invoked.
+type EnqueuerDBStoreMarkIndexRequestInferredFunc struct {
+	defaultHook func(context.Context, int, []int, *string) error
+	hooks       []func(context.Context, int, []int, *string) error
+	history     []EnqueuerDBStoreMarkIndexRequestInferredFuncCall
+	mutex       sync.Mutex
+}
+
+// MarkIndexRequestInferred delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockEnqueuerDBStore) MarkIndexRequestInferred(v0 context.Context, v1 int, v2 []int, v3 *string) error {
+	r0 := m.MarkIndexRequestInferredFunc.nextHook()(v0, v1, v2, v3)
+	m.MarkIndexRequestInferredFunc.appendCall(EnqueuerDBStoreMarkIndexRequestInferredFuncCall{v0, v1, v2, v3, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the
+// MarkIndexRequestInferred method of the parent MockEnqueuerDBStore
+// instance is invoked and the hook queue is empty.
+func (f *EnqueuerDBStoreMarkIndexRequestInferredFunc) SetDefaultHook(hook func(context.Context, int, []int, *string) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// MarkIndexRequestInferred method of the parent MockEnqueuerDBStore
+// instance invokes the hook at the front of the queue and discards it.
+// After the queue is empty, the default hook function is invoked for any
+// future action.
+func (f *EnqueuerDBStoreMarkIndexRequestInferredFunc) PushHook(hook func(context.Context, int, []int, *string) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *EnqueuerDBStoreMarkIndexRequestInferredFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int, []int, *string) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *EnqueuerDBStoreMarkIndexRequestInferredFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int, []int, *string) error {
+		return r0
+	})
+}
+
+func (f *EnqueuerDBStoreMarkIndexRequestInferredFunc) nextHook() func(context.Context, int, []int, *string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *EnqueuerDBStoreMarkIndexRequestInferredFunc) appendCall(r0 EnqueuerDBStoreMarkIndexRequestInferredFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// EnqueuerDBStoreMarkIndexRequestInferredFuncCall objects describing the
+// invocations of this function.
+func (f *EnqueuerDBStoreMarkIndexRequestInferredFunc) History() []EnqueuerDBStoreMarkIndexRequestInferredFuncCall {
+	f.mutex.Lock()
+	history := make([]EnqueuerDBStoreMarkIndexRequestInferredFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// EnqueuerDBStoreMarkIndexRequestInferredFuncCall is an object that
+// describes an invocation of method MarkIndexRequestInferred on an instance
+// of MockEnqueuerDBStore.
+type EnqueuerDBStoreMarkIndexRequestInferredFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 []int
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 *string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c EnqueuerDBStoreMarkIndexRequestInferredFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c EnqueuerDBStoreMarkIndexRequestInferredFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // EnqueuerDBStoreTransactFunc describes the behavior when the Transact
 // method of the parent MockEnqueuerDBStore instance is invoked.
 type EnqueuerDBStoreTransactFunc struct {
@@ -5070,18 +5449,29 @@ func (c GitserverClientCommitGraphFuncCall) Results() []interface{} {
 // github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel/resolvers)
 // used for unit testing.
 type MockIndexEnqueuer struct {
+	// DryRunIndexesFunc is an instance of a mock function object
+	// controlling the behavior of the method DryRunIndexes.
+	DryRunIndexesFunc *IndexEnqueuerDryRunIndexesFunc
 	// InferIndexConfigurationFunc is an instance of a mock function object
 	// controlling the behavior of the method InferIndexConfiguration.
 	InferIndexConfigurationFunc *IndexEnqueuerInferIndexConfigurationFunc
 	// QueueIndexesFunc is an instance of a mock function object controlling
 	// the behavior of the method QueueIndexes.
 	QueueIndexesFunc *IndexEnqueuerQueueIndexesFunc
+	// RequestIndexesFunc is an instance of a mock function object
+	// controlling the behavior of the method RequestIndexes.
+	RequestIndexesFunc *IndexEnqueuerRequestIndexesFunc
 }
 
 // NewMockIndexEnqueuer creates a new mock of the IndexEnqueuer interface.
 // All methods return zero values for all results, unless overwritten.
 func NewMockIndexEnqueuer() *MockIndexEnqueuer {
 	return &MockIndexEnqueuer{
+		DryRunIndexesFunc: &IndexEnqueuerDryRunIndexesFunc{
+			defaultHook: func(context.Context, int, string, string) (enqueuer.DryRun, error) {
+				return enqueuer.DryRun{}, nil
+			},
+		},
 		InferIndexConfigurationFunc: &IndexEnqueuerInferIndexConfigurationFunc{
 			defaultHook: func(context.Context, int) (*config.IndexConfiguration, error) {
 				return nil, nil
@@ -5092,6 +5482,11 @@ func NewMockIndexEnqueuer() *MockIndexEnqueuer {
 				return nil, nil
 			},
 		},
+		RequestIndexesFunc: &IndexEnqueuerRequestIndexesFunc{
+			defaultHook: func(context.Context, int, string, string) (int, error) {
+				return 0, nil
+			},
+		},
 	}
 }
 
@@ -5100,13 +5495,134 @@ func NewMockIndexEnqueuer() *MockIndexEnqueuer {
 // overwritten.
 func NewMockIndexEnqueuerFrom(i IndexEnqueuer) *MockIndexEnqueuer {
 	return &MockIndexEnqueuer{
+		DryRunIndexesFunc: &IndexEnqueuerDryRunIndexesFunc{
+			defaultHook: i.DryRunIndexes,
+		},
 		InferIndexConfigurationFunc: &IndexEnqueuerInferIndexConfigurationFunc{
 			defaultHook: i.InferIndexConfiguration,
 		},
 		QueueIndexesFunc: &IndexEnqueuerQueueIndexesFunc{
 			defaultHook: i.QueueIndexes,
 		},
+		RequestIndexesFunc: &IndexEnqueuerRequestIndexesFunc{
+			defaultHook: i.RequestIndexes,
+		},
+	}
+}
+
+// IndexEnqueuerDryRunIndexesFunc describes the behavior when the
+// DryRunIndexes method of the parent MockIndexEnqueuer instance is invoked.
+type IndexEnqueuerDryRunIndexesFunc struct {
+	defaultHook func(context.Context, int, string, string) (enqueuer.DryRun, error)
+	hooks       []func(context.Context, int, string, string) (enqueuer.DryRun, error)
+	history     []IndexEnqueuerDryRunIndexesFuncCall
+	mutex       sync.Mutex
+}
+
+// DryRunIndexes delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockIndexEnqueuer) DryRunIndexes(v0 context.Context, v1 int, v2 string, v3 string) (enqueuer.DryRun, error) {
+	r0, r1 := m.DryRunIndexesFunc.nextHook()(v0, v1, v2, v3)
+	m.DryRunIndexesFunc.appendCall(IndexEnqueuerDryRunIndexesFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the DryRunIndexes method
+// of the parent MockIndexEnqueuer instance is invoked and the hook queue is
+// empty.
+func (f *IndexEnqueuerDryRunIndexesFunc) SetDefaultHook(hook func(context.Context, int, string, string) (enqueuer.DryRun, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// DryRunIndexes method of the parent MockIndexEnqueuer instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *IndexEnqueuerDryRunIndexesFunc) PushHook(hook func(context.Context, int, string, string) (enqueuer.DryRun, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *IndexEnqueuerDryRunIndexesFunc) SetDefaultReturn(r0 enqueuer.DryRun, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, string, string) (enqueuer.DryRun, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *IndexEnqueuerDryRunIndexesFunc) PushReturn(r0 enqueuer.DryRun, r1 error) {
+	f.PushHook(func(context.Context, int, string, string) (enqueuer.DryRun, error) {
+		return r0, r1
+	})
+}
+
+func (f *IndexEnqueuerDryRunIndexesFunc) nextHook() func(context.Context, int, string, string) (enqueuer.DryRun, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
 	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *IndexEnqueuerDryRunIndexesFunc) appendCall(r0 IndexEnqueuerDryRunIndexesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of IndexEnqueuerDryRunIndexesFuncCall objects
+// describing the invocations of this function.
+func (f *IndexEnqueuerDryRunIndexesFunc) History() []IndexEnqueuerDryRunIndexesFuncCall {
+	f.mutex.Lock()
+	history := make([]IndexEnqueuerDryRunIndexesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// IndexEnqueuerDryRunIndexesFuncCall is an object that describes an
+// invocation of method DryRunIndexes on an instance of MockIndexEnqueuer.
+type IndexEnqueuerDryRunIndexesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 enqueuer.DryRun
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c IndexEnqueuerDryRunIndexesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c IndexEnqueuerDryRunIndexesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
 }
 
 // IndexEnqueuerInferIndexConfigurationFunc describes the behavior when the
@@ -5340,6 +5856,122 @@ func (c IndexEnqueuerQueueIndexesFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// IndexEnqueuerRequestIndexesFunc describes the behavior when the
+// RequestIndexes method of the parent MockIndexEnqueuer instance is
+// invoked.
+type IndexEnqueuerRequestIndexesFunc struct {
+	defaultHook func(context.Context, int, string, string) (int, error)
+	hooks       []func(context.Context, int, string, string) (int, error)
+	history     []IndexEnqueuerRequestIndexesFuncCall
+	mutex       sync.Mutex
+}
+
+// RequestIndexes delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockIndexEnqueuer) RequestIndexes(v0 context.Context, v1 int, v2 string, v3 string) (int, error) {
+	r0, r1 := m.RequestIndexesFunc.nextHook()(v0, v1, v2, v3)
+	m.RequestIndexesFunc.appendCall(IndexEnqueuerRequestIndexesFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the RequestIndexes
+// method of the parent MockIndexEnqueuer instance is invoked and the hook
+// queue is empty.
+func (f *IndexEnqueuerRequestIndexesFunc) SetDefaultHook(hook func(context.Context, int, string, string) (int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// RequestIndexes method of the parent MockIndexEnqueuer instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *IndexEnqueuerRequestIndexesFunc) PushHook(hook func(context.Context, int, string, string) (int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *IndexEnqueuerRequestIndexesFunc) SetDefaultReturn(r0 int, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, string, string) (int, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *IndexEnqueuerRequestIndexesFunc) PushReturn(r0 int, r1 error) {
+	f.PushHook(func(context.Context, int, string, string) (int, error) {
+		return r0, r1
+	})
+}
+
+func (f *IndexEnqueuerRequestIndexesFunc) nextHook() func(context.Context, int, string, string) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *IndexEnqueuerRequestIndexesFunc) appendCall(r0 IndexEnqueuerRequestIndexesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of IndexEnqueuerRequestIndexesFuncCall objects
+// describing the invocations of this function.
+func (f *IndexEnqueuerRequestIndexesFunc) History() []IndexEnqueuerRequestIndexesFuncCall {
+	f.mutex.Lock()
+	history := make([]IndexEnqueuerRequestIndexesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// IndexEnqueuerRequestIndexesFuncCall is an object that describes an
+// invocation of method RequestIndexes on an instance of MockIndexEnqueuer.
+type IndexEnqueuerRequestIndexesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c IndexEnqueuerRequestIndexesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c IndexEnqueuerRequestIndexesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // MockLSIFStore is a mock implementation of the LSIFStore interface (from
 // the package
 // github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel/resolvers)
@@ -8,6 +8,7 @@ import (
 
 	graphqlbackend "github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	resolvers "github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel/resolvers"
+	enqueuer "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/autoindex/enqueuer"
 	dbstore "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
 	config "github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
 )
@@ -34,6 +35,10 @@ type MockResolver struct {
 	// DeleteUploadByIDFunc is an instance of a mock function object
 	// controlling the behavior of the method DeleteUploadByID.
 	DeleteUploadByIDFunc *ResolverDeleteUploadByIDFunc
+	// DryRunAutoIndexJobsForRepoFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// DryRunAutoIndexJobsForRepo.
+	DryRunAutoIndexJobsForRepoFunc *ResolverDryRunAutoIndexJobsForRepoFunc
 	// GetConfigurationPoliciesFunc is an instance of a mock function object
 	// controlling the behavior of the method GetConfigurationPolicies.
 	GetConfigurationPoliciesFunc *ResolverGetConfigurationPoliciesFunc
@@ -44,6 +49,9 @@ type MockResolver struct {
 	// GetIndexByIDFunc is an instance of a mock function object controlling
 	// the behavior of the method GetIndexByID.
 	GetIndexByIDFunc *ResolverGetIndexByIDFunc
+	// GetIndexRequestByIDFunc is an instance of a mock function object
+	// controlling the behavior of the method GetIndexRequestByID.
+	GetIndexRequestByIDFunc *ResolverGetIndexRequestByIDFunc
 	// GetIndexesByIDsFunc is an instance of a mock function object
 	// controlling the behavior of the method GetIndexesByIDs.
 	GetIndexesByIDsFunc *ResolverGetIndexesByIDsFunc
@@ -73,6 +81,9 @@ type MockResolver struct {
 	// object controlling the behavior of the method
 	// QueueAutoIndexJobsForRepo.
 	QueueAutoIndexJobsForRepoFunc *ResolverQueueAutoIndexJobsForRepoFunc
+	// RequestIndexingFunc is an instance of a mock function object
+	// controlling the behavior of the method RequestIndexing.
+	RequestIndexingFunc *ResolverRequestIndexingFunc
 	// UpdateConfigurationPolicyFunc is an instance of a mock function
 	// object controlling the behavior of the method
 	// UpdateConfigurationPolicy.
@@ -115,6 +126,11 @@ func NewMockResolver() *MockResolver {
 				return nil
 			},
 		},
+		DryRunAutoIndexJobsForRepoFunc: &ResolverDryRunAutoIndexJobsForRepoFunc{
+			defaultHook: func(context.Context, int, string, string) (enqueuer.DryRun, error) {
+				return enqueuer.DryRun{}, nil
+			},
+		},
 		GetConfigurationPoliciesFunc: &ResolverGetConfigurationPoliciesFunc{
 			defaultHook: func(context.Context, dbstore.GetConfigurationPoliciesOptions) ([]dbstore.ConfigurationPolicy, error) {
 				return nil, nil
@@ -130,6 +146,11 @@ func NewMockResolver() *MockResolver {
 				return dbstore.Index{}, false, nil
 			},
 		},
+		GetIndexRequestByIDFunc: &ResolverGetIndexRequestByIDFunc{
+			defaultHook: func(context.Context, int) (dbstore.IndexRequest, bool, error) {
+				return dbstore.IndexRequest{}, false, nil
+			},
+		},
 		GetIndexesByIDsFunc: &ResolverGetIndexesByIDsFunc{
 			defaultHook: func(context.Context, ...int) ([]dbstore.Index, error) {
 				return nil, nil
@@ -175,6 +196,11 @@ func NewMockResolver() *MockResolver {
 				return nil, nil
 			},
 		},
+		RequestIndexingFunc: &ResolverRequestIndexingFunc{
+			defaultHook: func(context.Context, int, string, string) (dbstore.IndexRequest, error) {
+				return dbstore.IndexRequest{}, nil
+			},
+		},
 		UpdateConfigurationPolicyFunc: &ResolverUpdateConfigurationPolicyFunc{
 			defaultHook: func(context.Context, dbstore.ConfigurationPolicy) error {
 				return nil
@@ -212,6 +238,9 @@ func NewMockResolverFrom(i resolvers.Resolver) *MockResolver {
 		DeleteUploadByIDFunc: &ResolverDeleteUploadByIDFunc{
 			defaultHook: i.DeleteUploadByID,
 		},
+		DryRunAutoIndexJobsForRepoFunc: &ResolverDryRunAutoIndexJobsForRepoFunc{
+			defaultHook: i.DryRunAutoIndexJobsForRepo,
+		},
 		GetConfigurationPoliciesFunc: &ResolverGetConfigurationPoliciesFunc{
 			defaultHook: i.GetConfigurationPolicies,
 		},
@@ -221,6 +250,9 @@ func NewMockResolverFrom(i resolvers.Resolver) *MockResolver {
 		GetIndexByIDFunc: &ResolverGetIndexByIDFunc{
 			defaultHook: i.GetIndexByID,
 		},
+		GetIndexRequestByIDFunc: &ResolverGetIndexRequestByIDFunc{
+			defaultHook: i.GetIndexRequestByID,
+		},
 		GetIndexesByIDsFunc: &ResolverGetIndexesByIDsFunc{
 			defaultHook: i.GetIndexesByIDs,
 		},
@@ -248,6 +280,9 @@ func NewMockResolverFrom(i resolvers.Resolver) *MockResolver {
 		QueueAutoIndexJobsForRepoFunc: &ResolverQueueAutoIndexJobsForRepoFunc{
 			defaultHook: i.QueueAutoIndexJobsForRepo,
 		},
+		RequestIndexingFunc: &ResolverRequestIndexingFunc{
+			defaultHook: i.RequestIndexing,
+		},
 		UpdateConfigurationPolicyFunc: &ResolverUpdateConfigurationPolicyFunc{
 			defaultHook: i.UpdateConfigurationPolicy,
 		},
@@ -803,6 +838,124 @@ func (c ResolverDeleteUploadByIDFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// ResolverDryRunAutoIndexJobsForRepoFunc describes the behavior when the
+// DryRunAutoIndexJobsForRepo method of the parent MockResolver instance is
+// invoked.
+type ResolverDryRunAutoIndexJobsForRepoFunc struct {
+	defaultHook func(context.Context, int, string, string) (enqueuer.DryRun, error)
+	hooks       []func(context.Context, int, string, string) (enqueuer.DryRun, error)
+	history     []ResolverDryRunAutoIndexJobsForRepoFuncCall
+	mutex       sync.Mutex
+}
+
+// DryRunAutoIndexJobsForRepo delegates to the next hook function in the
+// queue and stores the parameter and result values of this invocation.
+func (m *MockResolver) DryRunAutoIndexJobsForRepo(v0 context.Context, v1 int, v2 string, v3 string) (enqueuer.DryRun, error) {
+	r0, r1 := m.DryRunAutoIndexJobsForRepoFunc.nextHook()(v0, v1, v2, v3)
+	m.DryRunAutoIndexJobsForRepoFunc.appendCall(ResolverDryRunAutoIndexJobsForRepoFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// DryRunAutoIndexJobsForRepo method of the parent MockResolver instance is
+// invoked and the hook queue is empty.
+func (f *ResolverDryRunAutoIndexJobsForRepoFunc) SetDefaultHook(hook func(context.Context, int, string, string) (enqueuer.DryRun, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// DryRunAutoIndexJobsForRepo method of the parent MockResolver instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *ResolverDryRunAutoIndexJobsForRepoFunc) PushHook(hook func(context.Context, int, string, string) (enqueuer.DryRun, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *ResolverDryRunAutoIndexJobsForRepoFunc) SetDefaultReturn(r0 enqueuer.DryRun, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, string, string) (enqueuer.DryRun, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *ResolverDryRunAutoIndexJobsForRepoFunc) PushReturn(r0 enqueuer.DryRun, r1 error) {
+	f.PushHook(func(context.Context, int, string, string) (enqueuer.DryRun, error) {
+		return r0, r1
+	})
+}
+
+func (f *ResolverDryRunAutoIndexJobsForRepoFunc) nextHook() func(context.Context, int, string, string) (enqueuer.DryRun, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ResolverDryRunAutoIndexJobsForRepoFunc) appendCall(r0 ResolverDryRunAutoIndexJobsForRepoFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ResolverDryRunAutoIndexJobsForRepoFuncCall
+// objects describing the invocations of this function.
+func (f *ResolverDryRunAutoIndexJobsForRepoFunc) History() []ResolverDryRunAutoIndexJobsForRepoFuncCall {
+	f.mutex.Lock()
+	history := make([]ResolverDryRunAutoIndexJobsForRepoFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ResolverDryRunAutoIndexJobsForRepoFuncCall is an object that describes an
+// invocation of method DryRunAutoIndexJobsForRepo on an instance of
+// MockResolver.
+type ResolverDryRunAutoIndexJobsForRepoFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 enqueuer.DryRun
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ResolverDryRunAutoIndexJobsForRepoFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ResolverDryRunAutoIndexJobsForRepoFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // ResolverGetConfigurationPoliciesFunc describes the behavior when the
 // GetConfigurationPolicies method of the parent MockResolver instance is
 // invoked.
@@ -1142,6 +1295,119 @@ func (c ResolverGetIndexByIDFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
+// ResolverGetIndexRequestByIDFunc describes the behavior when the
+// GetIndexRequestByID method of the parent MockResolver instance is
+// invoked.
+type ResolverGetIndexRequestByIDFunc struct {
+	defaultHook func(context.Context, int) (dbstore.IndexRequest, bool, error)
+	hooks       []func(context.Context, int) (dbstore.IndexRequest, bool, error)
+	history     []ResolverGetIndexRequestByIDFuncCall
+	mutex       sync.Mutex
+}
+
+// GetIndexRequestByID delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockResolver) GetIndexRequestByID(v0 context.Context, v1 int) (dbstore.IndexRequest, bool, error) {
+	r0, r1, r2 := m.GetIndexRequestByIDFunc.nextHook()(v0, v1)
+	m.GetIndexRequestByIDFunc.appendCall(ResolverGetIndexRequestByIDFuncCall{v0, v1, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the GetIndexRequestByID
+// method of the parent MockResolver instance is invoked and the hook queue
+// is empty.
+func (f *ResolverGetIndexRequestByIDFunc) SetDefaultHook(hook func(context.Context, int) (dbstore.IndexRequest, bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetIndexRequestByID method of the parent MockResolver instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *ResolverGetIndexRequestByIDFunc) PushHook(hook func(context.Context, int) (dbstore.IndexRequest, bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *ResolverGetIndexRequestByIDFunc) SetDefaultReturn(r0 dbstore.IndexRequest, r1 bool, r2 error) {
+	f.SetDefaultHook(func(context.Context, int) (dbstore.IndexRequest, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *ResolverGetIndexRequestByIDFunc) PushReturn(r0 dbstore.IndexRequest, r1 bool, r2 error) {
+	f.PushHook(func(context.Context, int) (dbstore.IndexRequest, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *ResolverGetIndexRequestByIDFunc) nextHook() func(context.Context, int) (dbstore.IndexRequest, bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ResolverGetIndexRequestByIDFunc) appendCall(r0 ResolverGetIndexRequestByIDFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ResolverGetIndexRequestByIDFuncCall objects
+// describing the invocations of this function.
+func (f *ResolverGetIndexRequestByIDFunc) History() []ResolverGetIndexRequestByIDFuncCall {
+	f.mutex.Lock()
+	history := make([]ResolverGetIndexRequestByIDFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ResolverGetIndexRequestByIDFuncCall is an object that describes an
+// invocation of method GetIndexRequestByID on an instance of MockResolver.
+type ResolverGetIndexRequestByIDFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 dbstore.IndexRequest
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 bool
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ResolverGetIndexRequestByIDFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ResolverGetIndexRequestByIDFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
 // ResolverGetIndexesByIDsFunc describes the behavior when the
 // GetIndexesByIDs method of the parent MockResolver instance is invoked.
 type ResolverGetIndexesByIDsFunc struct {
@@ -2163,6 +2429,121 @@ func (c ResolverQueueAutoIndexJobsForRepoFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// ResolverRequestIndexingFunc describes the behavior when the
+// RequestIndexing method of the parent MockResolver instance is invoked.
+type ResolverRequestIndexingFunc struct {
+	defaultHook func(context.Context, int, string, string) (dbstore.IndexRequest, error)
+	hooks       []func(context.Context, int, string, string) (dbstore.IndexRequest, error)
+	history     []ResolverRequestIndexingFuncCall
+	mutex       sync.Mutex
+}
+
+// RequestIndexing delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockResolver) RequestIndexing(v0 context.Context, v1 int, v2 string, v3 string) (dbstore.IndexRequest, error) {
+	r0, r1 := m.RequestIndexingFunc.nextHook()(v0, v1, v2, v3)
+	m.RequestIndexingFunc.appendCall(ResolverRequestIndexingFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the RequestIndexing
+// method of the parent MockResolver instance is invoked and the hook queue
+// is empty.
+func (f *ResolverRequestIndexingFunc) SetDefaultHook(hook func(context.Context, int, string, string) (dbstore.IndexRequest, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// RequestIndexing method of the parent MockResolver instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *ResolverRequestIndexingFunc) PushHook(hook func(context.Context, int, string, string) (dbstore.IndexRequest, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *ResolverRequestIndexingFunc) SetDefaultReturn(r0 dbstore.IndexRequest, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, string, string) (dbstore.IndexRequest, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *ResolverRequestIndexingFunc) PushReturn(r0 dbstore.IndexRequest, r1 error) {
+	f.PushHook(func(context.Context, int, string, string) (dbstore.IndexRequest, error) {
+		return r0, r1
+	})
+}
+
+func (f *ResolverRequestIndexingFunc) nextHook() func(context.Context, int, string, string) (dbstore.IndexRequest, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ResolverRequestIndexingFunc) appendCall(r0 ResolverRequestIndexingFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ResolverRequestIndexingFuncCall objects
+// describing the invocations of this function.
+func (f *ResolverRequestIndexingFunc) History() []ResolverRequestIndexingFuncCall {
+	f.mutex.Lock()
+	history := make([]ResolverRequestIndexingFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ResolverRequestIndexingFuncCall is an object that describes an invocation
+// of method RequestIndexing on an instance of MockResolver.
+type ResolverRequestIndexingFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 dbstore.IndexRequest
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ResolverRequestIndexingFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ResolverRequestIndexingFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // ResolverUpdateConfigurationPolicyFunc describes the behavior when the
 // UpdateConfigurationPolicy method of the parent MockResolver instance is
 // invoked.
@@ -4,9 +4,11 @@ import (
 	"context"
 	"time"
 
+	"github.com/cockroachdb/errors"
 	"github.com/opentracing/opentracing-go/log"
 
 	gql "github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/autoindex/enqueuer"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/policies"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
 	store "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
@@ -31,6 +33,9 @@ type Resolver interface {
 	DeleteIndexByID(ctx context.Context, id int) error
 	CommitGraph(ctx context.Context, repositoryID int) (gql.CodeIntelligenceCommitGraphResolver, error)
 	QueueAutoIndexJobsForRepo(ctx context.Context, repositoryID int, rev, configuration string) ([]store.Index, error)
+	DryRunAutoIndexJobsForRepo(ctx context.Context, repositoryID int, rev, configuration string) (enqueuer.DryRun, error)
+	RequestIndexing(ctx context.Context, repositoryID int, rev, configuration string) (store.IndexRequest, error)
+	GetIndexRequestByID(ctx context.Context, id int) (store.IndexRequest, bool, error)
 	QueryResolver(ctx context.Context, args *gql.GitBlobLSIFDataArgs) (QueryResolver, error)
 	GetConfigurationPolicies(ctx context.Context, opts store.GetConfigurationPoliciesOptions) ([]store.ConfigurationPolicy, error)
 	GetConfigurationPolicyByID(ctx context.Context, id int) (store.ConfigurationPolicy, bool, error)
@@ -133,6 +138,31 @@ func (r *resolver) QueueAutoIndexJobsForRepo(ctx context.Context, repositoryID i
 	return r.indexEnqueuer.QueueIndexes(ctx, repositoryID, rev, configuration, true)
 }
 
+func (r *resolver) DryRunAutoIndexJobsForRepo(ctx context.Context, repositoryID int, rev, configuration string) (enqueuer.DryRun, error) {
+	return r.indexEnqueuer.DryRunIndexes(ctx, repositoryID, rev, configuration)
+}
+
+func (r *resolver) RequestIndexing(ctx context.Context, repositoryID int, rev, configuration string) (store.IndexRequest, error) {
+	id, err := r.indexEnqueuer.RequestIndexes(ctx, repositoryID, rev, configuration)
+	if err != nil {
+		return store.IndexRequest{}, err
+	}
+
+	indexRequest, exists, err := r.dbStore.GetIndexRequestByID(ctx, id)
+	if err != nil {
+		return store.IndexRequest{}, err
+	}
+	if !exists {
+		return store.IndexRequest{}, errors.Newf("index request %d not found", id)
+	}
+
+	return indexRequest, nil
+}
+
+func (r *resolver) GetIndexRequestByID(ctx context.Context, id int) (store.IndexRequest, bool, error) {
+	return r.dbStore.GetIndexRequestByID(ctx, id)
+}
+
 const slowQueryResolverRequestThreshold = time.Second
 
 // QueryResolver determines the set of dumps that can answer code intel queries for the
@@ -6,6 +6,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel/httpapi"
 	codeintelhttpapi "github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel/httpapi"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/uploadstore"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 )
 
@@ -25,3 +26,13 @@ func NewCodeIntelUploadHandler(ctx context.Context, db dbutil.DB, internal bool)
 
 	return handler, nil
 }
+
+// UploadStore returns the object store holding LSIF uploads. The executor queue stores the
+// artifacts uploaded by executor jobs in it as well.
+func UploadStore(ctx context.Context, db dbutil.DB) (uploadstore.Store, error) {
+	if err := initServices(ctx, db); err != nil {
+		return nil, err
+	}
+
+	return services.uploadStore, nil
+}
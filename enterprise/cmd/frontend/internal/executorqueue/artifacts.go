@@ -0,0 +1,125 @@
+package executorqueue
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gorilla/mux"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/executorqueue/artifacturl"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/uploadstore"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+// artifactRetention is the duration after which the artifacts uploaded by executor jobs
+// expire. Changing it only affects artifacts uploaded afterwards.
+var artifactRetention = env.MustGetDuration("EXECUTOR_ARTIFACT_RETENTION", 7*24*time.Hour, "The duration for which artifacts uploaded by executor jobs are kept.")
+
+// artifactJanitorInterval is the interval at which expired artifacts are deleted.
+const artifactJanitorInterval = 10 * time.Minute
+
+// artifactJanitorBatchSize is the maximum number of artifacts deleted per iteration.
+const artifactJanitorBatchSize = 500
+
+// ArtifactDownloadStore looks up the artifacts uploaded by executor jobs.
+type ArtifactDownloadStore interface {
+	GetByID(ctx context.Context, id int) (database.ExecutorJobArtifact, bool, error)
+}
+
+// newArtifactDownloadHandler returns a handler that serves the content of the artifact
+// identified by the id route variable.
+//
+// 🚨 SECURITY: This handler is not behind the executor access token. Requests are
+// authenticated by the signature of the URL, which is only minted for users who can
+// view the job of the artifact.
+func newArtifactDownloadHandler(artifactStore ArtifactDownloadStore, uploadStore uploadstore.Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(mux.Vars(r)["id"])
+		if err != nil {
+			http.Error(w, "Invalid artifact ID.", http.StatusBadRequest)
+			return
+		}
+
+		if err := artifacturl.Verify(r.URL.Query(), id, time.Now()); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		artifact, ok, err := artifactStore.GetByID(r.Context(), id)
+		if err != nil {
+			log15.Error("Failed to get artifact", "id", id, "err", err)
+			http.Error(w, "Failed to get artifact.", http.StatusInternalServerError)
+			return
+		}
+		if !ok {
+			http.Error(w, "Artifact not found.", http.StatusNotFound)
+			return
+		}
+
+		rc, err := uploadStore.Get(r.Context(), artifact.ObjectKey)
+		if err != nil {
+			log15.Error("Failed to read artifact", "id", id, "key", artifact.ObjectKey, "err", err)
+			http.Error(w, "Failed to read artifact.", http.StatusInternalServerError)
+			return
+		}
+		defer rc.Close()
+
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", artifact.Name))
+		w.Header().Set("Content-Length", strconv.FormatInt(artifact.Size, 10))
+		if _, err := io.Copy(w, rc); err != nil {
+			log15.Warn("Failed to write artifact", "id", id, "err", err)
+		}
+	})
+}
+
+// ArtifactJanitorStore deletes the metadata of expired artifacts.
+type ArtifactJanitorStore interface {
+	DeleteExpired(ctx context.Context, expiredBefore time.Time, limit int) ([]database.ExecutorJobArtifact, error)
+}
+
+type artifactJanitor struct {
+	artifactStore ArtifactJanitorStore
+	uploadStore   uploadstore.Store
+}
+
+var _ goroutine.Handler = &artifactJanitor{}
+var _ goroutine.ErrorHandler = &artifactJanitor{}
+
+// newArtifactJanitor returns a background routine that periodically deletes the artifacts
+// of executor jobs that expired, both their metadata and their content in the upload store.
+func newArtifactJanitor(artifactStore ArtifactJanitorStore, uploadStore uploadstore.Store, interval time.Duration) goroutine.BackgroundRoutine {
+	return goroutine.NewPeriodicGoroutine(context.Background(), interval, &artifactJanitor{
+		artifactStore: artifactStore,
+		uploadStore:   uploadStore,
+	})
+}
+
+func (h *artifactJanitor) Handle(ctx context.Context) error {
+	artifacts, err := h.artifactStore.DeleteExpired(ctx, time.Now().UTC(), artifactJanitorBatchSize)
+	if err != nil {
+		return errors.Wrap(err, "ExecutorJobArtifactStore.DeleteExpired")
+	}
+
+	for _, artifact := range artifacts {
+		// The metadata is already gone, so a failure only leaves an orphaned object behind,
+		// which is removed by the expiration policy of the bucket if one is configured.
+		if err := h.uploadStore.Delete(ctx, artifact.ObjectKey); err != nil {
+			log15.Warn("Failed to delete expired artifact", "queue", artifact.QueueName, "jobID", artifact.JobID, "key", artifact.ObjectKey, "error", err)
+		}
+	}
+
+	return nil
+}
+
+func (h *artifactJanitor) HandleError(err error) {
+	log15.Error("Failed to delete expired executor job artifacts", "error", err)
+}
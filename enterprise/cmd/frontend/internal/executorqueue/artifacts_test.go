@@ -0,0 +1,74 @@
+package executorqueue
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+
+	uploadstoremocks "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/uploadstore/mocks"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+)
+
+func TestArtifactDownloadHandlerUnsigned(t *testing.T) {
+	artifactStore := testArtifactStore{{ID: 42, Name: "diff.patch", ObjectKey: "executor-artifacts/batches/1/diff.patch"}}
+	uploadStore := uploadstoremocks.NewMockStore()
+
+	router := mux.NewRouter()
+	router.Path("/.executors/artifacts/{id:[0-9]+}").Handler(newArtifactDownloadHandler(artifactStore, uploadStore))
+
+	for _, target := range []string{
+		"/.executors/artifacts/42",
+		"/.executors/artifacts/42?expires=99999999999&signature=deadbeef",
+	} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest("GET", target, nil))
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s: unexpected status code. want=%d have=%d", target, http.StatusUnauthorized, rec.Code)
+		}
+	}
+
+	if len(uploadStore.GetFunc.History()) != 0 {
+		t.Errorf("expected no artifact to be read")
+	}
+}
+
+func TestArtifactJanitor(t *testing.T) {
+	artifactStore := testArtifactStore{
+		{ID: 1, ObjectKey: "executor-artifacts/batches/1/diff.patch"},
+		{ID: 2, ObjectKey: "executor-artifacts/codeintel/2/build.log"},
+	}
+	uploadStore := uploadstoremocks.NewMockStore()
+
+	janitor := &artifactJanitor{artifactStore: artifactStore, uploadStore: uploadStore}
+	if err := janitor.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var keys []string
+	for _, call := range uploadStore.DeleteFunc.History() {
+		keys = append(keys, call.Arg1)
+	}
+	if diff := cmp.Diff([]string{"executor-artifacts/batches/1/diff.patch", "executor-artifacts/codeintel/2/build.log"}, keys); diff != "" {
+		t.Errorf("unexpected deleted keys (-want +got):\n%s", diff)
+	}
+}
+
+type testArtifactStore []database.ExecutorJobArtifact
+
+func (s testArtifactStore) GetByID(ctx context.Context, id int) (database.ExecutorJobArtifact, bool, error) {
+	for _, artifact := range s {
+		if artifact.ID == id {
+			return artifact, true, nil
+		}
+	}
+	return database.ExecutorJobArtifact{}, false, nil
+}
+
+func (s testArtifactStore) DeleteExpired(ctx context.Context, expiredBefore time.Time, limit int) ([]database.ExecutorJobArtifact, error) {
+	return s, nil
+}
@@ -0,0 +1,66 @@
+// Package artifacturl implements short-lived signed URLs for downloading the artifacts
+// uploaded by executor jobs. A signed URL grants access to a single artifact without a
+// session cookie or access token, until it expires.
+package artifacturl
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/urlsign"
+)
+
+var signingKey = env.Get("EXECUTOR_ARTIFACT_SIGNING_KEY", "", "secret key used for signing executor job artifact download URLs (downloads are disabled if empty)")
+
+var signer = urlsign.Signer{
+	Purpose: "executor-artifact",
+	Key:     func() string { return signingKey },
+}
+
+// Expiry is the duration for which a signed URL is valid.
+const Expiry = time.Hour
+
+// Query parameters of a signed URL.
+const (
+	paramExpires   = "expires"
+	paramSignature = "signature"
+)
+
+var (
+	// ErrInvalidSignature is returned by Verify if the signature of a signed URL is invalid.
+	ErrInvalidSignature = urlsign.ErrInvalidSignature
+
+	// ErrExpired is returned by Verify if a signed URL has expired.
+	ErrExpired = urlsign.ErrExpired
+)
+
+// Enabled reports whether signed URLs are enabled, which requires a signing key to be configured.
+func Enabled() bool {
+	return signer.Enabled()
+}
+
+// Path returns the path of the download route of the given artifact, relative to the
+// external URL.
+func Path(artifactID int) string {
+	return fmt.Sprintf("/.executors/artifacts/%d", artifactID)
+}
+
+// URL returns the signed URL of the given artifact, relative to the external URL, that is
+// valid until expiresAt.
+func URL(artifactID int, expiresAt time.Time) string {
+	expires, signature := signer.Sign(expiresAt, strconv.Itoa(artifactID))
+	query := url.Values{
+		paramExpires:   []string{expires},
+		paramSignature: []string{signature},
+	}
+	return Path(artifactID) + "?" + query.Encode()
+}
+
+// Verify checks that the query parameters of a signed URL are valid for a download of the
+// given artifact.
+func Verify(query url.Values, artifactID int, now time.Time) error {
+	return signer.Verify(query.Get(paramExpires), query.Get(paramSignature), now, strconv.Itoa(artifactID))
+}
@@ -0,0 +1,71 @@
+package artifacturl
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mockSigningKey(t *testing.T) {
+	t.Helper()
+	old := signingKey
+	signingKey = "test-key"
+	t.Cleanup(func() { signingKey = old })
+}
+
+func TestVerify(t *testing.T) {
+	mockSigningKey(t)
+
+	now := time.Now()
+	signed, err := url.Parse(URL(42, now.Add(time.Minute)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(signed.Path, "/.executors/artifacts/42") {
+		t.Fatalf("unexpected path %q", signed.Path)
+	}
+	query := signed.Query()
+
+	tests := []struct {
+		name       string
+		query      url.Values
+		artifactID int
+		now        time.Time
+		wantErr    error
+	}{
+		{name: "valid", query: query, artifactID: 42, now: now},
+		{name: "other artifact", query: query, artifactID: 43, now: now, wantErr: ErrInvalidSignature},
+		{name: "expired", query: query, artifactID: 42, now: now.Add(time.Minute), wantErr: ErrExpired},
+		{
+			name: "extended expiry",
+			query: func() url.Values {
+				q := url.Values{}
+				for k, v := range query {
+					q[k] = v
+				}
+				q.Set(paramExpires, "99999999999")
+				return q
+			}(),
+			artifactID: 42, now: now, wantErr: ErrInvalidSignature,
+		},
+		{name: "unsigned", query: url.Values{}, artifactID: 42, now: now, wantErr: ErrInvalidSignature},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := Verify(test.query, test.artifactID, test.now); err != test.wantErr {
+				t.Errorf("unexpected error. want=%v have=%v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestVerifyDisabled(t *testing.T) {
+	mockSigningKey(t)
+	query, _ := url.Parse(URL(42, time.Now().Add(time.Minute)))
+
+	signingKey = ""
+	if err := Verify(query.Query(), 42, time.Now()); err != ErrInvalidSignature {
+		t.Errorf("unexpected error. want=%v have=%v", ErrInvalidSignature, err)
+	}
+}
@@ -0,0 +1,79 @@
+package executorqueue
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/executorqueue/handler"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+// deadExecutorInterval is the interval at which executors that stopped sending
+// heartbeats are looked for.
+const deadExecutorInterval = 30 * time.Second
+
+// DeadExecutorStore deletes the heartbeats of executors that stopped sending them.
+type DeadExecutorStore interface {
+	DeleteInactiveHeartbeats(ctx context.Context, lastSeenBefore time.Time) ([]database.Executor, error)
+}
+
+type deadExecutorResetter struct {
+	executorStore DeadExecutorStore
+	queueOptions  map[string]handler.QueueOptions
+}
+
+var _ goroutine.Handler = &deadExecutorResetter{}
+var _ goroutine.ErrorHandler = &deadExecutorResetter{}
+
+// newDeadExecutorResetter returns a background routine that periodically forgets the
+// executors that haven't sent a heartbeat within database.ExecutorInactiveAfter and
+// requeues the jobs they were processing, without waiting for the stalled job resetter
+// of the queue. Jobs that have been reset too many times are marked as failed instead.
+func newDeadExecutorResetter(executorStore DeadExecutorStore, queueOptions map[string]handler.QueueOptions, interval time.Duration) goroutine.BackgroundRoutine {
+	return goroutine.NewPeriodicGoroutine(context.Background(), interval, &deadExecutorResetter{
+		executorStore: executorStore,
+		queueOptions:  queueOptions,
+	})
+}
+
+func (h *deadExecutorResetter) Handle(ctx context.Context) error {
+	executors, err := h.executorStore.DeleteInactiveHeartbeats(ctx, time.Now().UTC().Add(-database.ExecutorInactiveAfter))
+	if err != nil {
+		return errors.Wrap(err, "ExecutorStore.DeleteInactiveHeartbeats")
+	}
+
+	namesByQueue := map[string][]string{}
+	for _, executor := range executors {
+		log15.Info("Executor stopped sending heartbeats", "name", executor.Name, "hostname", executor.Hostname, "queue", executor.QueueName, "lastSeenAt", executor.LastSeenAt)
+		namesByQueue[executor.QueueName] = append(namesByQueue[executor.QueueName], executor.Name)
+	}
+
+	for queueName, names := range namesByQueue {
+		queueOptions, ok := h.queueOptions[queueName]
+		if !ok {
+			continue
+		}
+
+		resetIDs, failedIDs, err := queueOptions.Store.ResetWorkers(ctx, names)
+		if err != nil {
+			return errors.Wrapf(err, "%s: Store.ResetWorkers", queueName)
+		}
+
+		for _, id := range resetIDs {
+			log15.Info("Requeued job of dead executor", "queue", queueName, "id", id)
+		}
+		for _, id := range failedIDs {
+			log15.Warn("Marked job of dead executor as failed", "queue", queueName, "id", id)
+		}
+	}
+
+	return nil
+}
+
+func (h *deadExecutorResetter) HandleError(err error) {
+	log15.Error("Failed to reset jobs of dead executors", "error", err)
+}
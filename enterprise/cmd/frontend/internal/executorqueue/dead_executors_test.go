@@ -0,0 +1,60 @@
+package executorqueue
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/executorqueue/handler"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	workerstoremocks "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store/mocks"
+)
+
+func TestDeadExecutorResetter(t *testing.T) {
+	executorStore := testDeadExecutorStore{
+		{Name: "executor-1", QueueName: "batches"},
+		{Name: "executor-2", QueueName: "codeintel"},
+		{Name: "executor-3", QueueName: "batches"},
+		{Name: "executor-4", QueueName: "unknown"},
+	}
+
+	batchesStore := workerstoremocks.NewMockStore()
+	codeintelStore := workerstoremocks.NewMockStore()
+	queueOptions := map[string]handler.QueueOptions{
+		"batches":   {Store: batchesStore},
+		"codeintel": {Store: codeintelStore},
+	}
+
+	resetter := &deadExecutorResetter{executorStore: executorStore, queueOptions: queueOptions}
+	if err := resetter.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for _, testCase := range []struct {
+		store *workerstoremocks.MockStore
+		names []string
+	}{
+		{store: batchesStore, names: []string{"executor-1", "executor-3"}},
+		{store: codeintelStore, names: []string{"executor-2"}},
+	} {
+		history := testCase.store.ResetWorkersFunc.History()
+		if len(history) != 1 {
+			t.Fatalf("unexpected number of ResetWorkers calls. want=%d have=%d", 1, len(history))
+		}
+
+		names := history[0].Arg1
+		sort.Strings(names)
+		if diff := cmp.Diff(testCase.names, names); diff != "" {
+			t.Errorf("unexpected worker hostnames (-want +got):\n%s", diff)
+		}
+	}
+}
+
+type testDeadExecutorStore []database.Executor
+
+func (s testDeadExecutorStore) DeleteInactiveHeartbeats(ctx context.Context, lastSeenBefore time.Time) ([]database.Executor, error) {
+	return s, nil
+}
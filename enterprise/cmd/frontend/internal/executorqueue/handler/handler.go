@@ -3,19 +3,53 @@ package handler
 import (
 	"context"
 	"fmt"
+	"io"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/inconshreveable/log15"
 
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/uploadstore"
 	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
 )
 
 type handler struct {
 	QueueOptions
+	queueName     string
+	executorStore ExecutorStore
+	artifacts     ArtifactOptions
 }
 
+// ExecutorStore records the heartbeats of executors, so that the jobs of executors
+// that stopped sending heartbeats can be requeued.
+type ExecutorStore interface {
+	UpsertHeartbeat(ctx context.Context, executor database.Executor) error
+}
+
+// ArtifactStore records the metadata of the artifacts uploaded by executor jobs.
+type ArtifactStore interface {
+	Upsert(ctx context.Context, artifact database.ExecutorJobArtifact) (database.ExecutorJobArtifact, error)
+}
+
+// ArtifactOptions configure where the artifacts uploaded by executor jobs are stored.
+type ArtifactOptions struct {
+	// UploadStore holds the content of the artifacts. If it is nil, artifact uploads
+	// are rejected.
+	UploadStore uploadstore.Store
+
+	// Store records the metadata of the artifacts.
+	Store ArtifactStore
+
+	// Retention is the duration after which an uploaded artifact expires.
+	Retention time.Duration
+}
+
+// MaxArtifactSize is the maximum size in bytes of a single artifact.
+const MaxArtifactSize = 128 * 1024 * 1024
+
 type QueueOptions struct {
 	// Store is a required dbworker store store for each registered queue.
 	Store store.Store
@@ -28,13 +62,42 @@ type QueueOptions struct {
 	// If it is set, it will be invoked periodically and should return the IDs to be
 	// canceled for the given executor.
 	CanceledRecordsFetcher func(ctx context.Context, executorName string) (canceledIDs []int, err error)
+
+	// JobStatisticsFetcher is an optional hook that can be provided to report the metrics of the
+	// queue. If it is set, it will be invoked periodically and should return the given percentiles
+	// of the time spent in the queue by the jobs dequeued since the given time, and the number of
+	// jobs per image that finished since the given time.
+	JobStatisticsFetcher func(ctx context.Context, since time.Time, percentiles []float64) (JobStatistics, error)
+}
+
+// JobStatistics are the statistics of the jobs of a queue over a period of time.
+type JobStatistics struct {
+	// DequeueLatencies are the requested percentiles of the time jobs spent in the queue.
+	DequeueLatencies []time.Duration
+	Images           []ImageStatistics
 }
 
-func newHandler(queueOptions QueueOptions) *handler {
-	return &handler{queueOptions}
+// ImageStatistics counts the jobs running the given image that finished processing.
+type ImageStatistics struct {
+	Image     string
+	Completed int
+	Failed    int
 }
 
-var ErrUnknownJob = errors.New("unknown job")
+func newHandler(queueName string, queueOptions QueueOptions, executorStore ExecutorStore, artifacts ArtifactOptions) *handler {
+	return &handler{
+		QueueOptions:  queueOptions,
+		queueName:     queueName,
+		executorStore: executorStore,
+		artifacts:     artifacts,
+	}
+}
+
+var (
+	ErrUnknownJob           = errors.New("unknown job")
+	ErrArtifactTooLarge     = errors.Newf("artifact exceeds the maximum size of %d bytes", MaxArtifactSize)
+	ErrArtifactsUnsupported = errors.New("artifact uploads are not configured")
+)
 
 // dequeue selects a job record from the database and stashes metadata including
 // the job record and the locking transaction. If no job is available for processing,
@@ -144,14 +207,69 @@ func (h *handler) markFailed(ctx context.Context, executorName string, jobID int
 	return nil
 }
 
-// heartbeat calls Heartbeat for the given jobs.
-func (h *handler) heartbeat(ctx context.Context, executorName string, ids []int) (knownIDs []int, err error) {
-	return h.Store.Heartbeat(ctx, ids, store.HeartbeatOptions{
+// heartbeat records the heartbeat of the given executor and calls Heartbeat for its jobs.
+func (h *handler) heartbeat(ctx context.Context, executor database.Executor) (knownIDs []int, err error) {
+	executor.QueueName = h.queueName
+	if err := h.executorStore.UpsertHeartbeat(ctx, executor); err != nil {
+		// Failing to record the executor must not fail the heartbeat of its jobs, which
+		// would cause them to be requeued by the dbworker resetter.
+		log15.Error("Failed to upsert executor heartbeat", "executor", executor.Name, "error", err)
+	}
+
+	return h.Store.Heartbeat(ctx, executor.JobIDs, store.HeartbeatOptions{
 		// We pass the WorkerHostname, so the store enforces the record to be owned by this executor. When
 		// the previous executor didn't report heartbeats anymore, but is still alive and reporting state,
 		// both executors that ever got the job would be writing to the same record. This prevents it.
+		WorkerHostname: executor.Name,
+	})
+}
+
+// uploadArtifact writes the content of the given artifact of a job to the upload store
+// and records its metadata. The artifact expires after the configured retention.
+func (h *handler) uploadArtifact(ctx context.Context, executorName string, jobID int, name string, r io.Reader) (database.ExecutorJobArtifact, error) {
+	if h.artifacts.UploadStore == nil || h.artifacts.Store == nil {
+		return database.ExecutorJobArtifact{}, ErrArtifactsUnsupported
+	}
+
+	knownIDs, err := h.Store.Heartbeat(ctx, []int{jobID}, store.HeartbeatOptions{
+		// We pass the WorkerHostname, so the store enforces the record to be owned by this executor. An
+		// executor whose job has been requeued must not overwrite the artifacts of the new attempt.
 		WorkerHostname: executorName,
 	})
+	if err != nil {
+		return database.ExecutorJobArtifact{}, err
+	}
+	if len(knownIDs) == 0 {
+		return database.ExecutorJobArtifact{}, ErrUnknownJob
+	}
+
+	key := artifactKey(h.queueName, jobID, name)
+
+	// Read one byte past the limit, so that oversized artifacts can be detected.
+	size, err := h.artifacts.UploadStore.Upload(ctx, key, io.LimitReader(r, MaxArtifactSize+1))
+	if err != nil {
+		return database.ExecutorJobArtifact{}, errors.Wrap(err, "uploadstore.Upload")
+	}
+	if size > MaxArtifactSize {
+		if err := h.artifacts.UploadStore.Delete(ctx, key); err != nil {
+			log15.Error("Failed to delete oversized artifact", "key", key, "error", err)
+		}
+		return database.ExecutorJobArtifact{}, ErrArtifactTooLarge
+	}
+
+	return h.artifacts.Store.Upsert(ctx, database.ExecutorJobArtifact{
+		QueueName: h.queueName,
+		JobID:     jobID,
+		Name:      name,
+		ObjectKey: key,
+		Size:      size,
+		ExpiresAt: time.Now().Add(h.artifacts.Retention),
+	})
+}
+
+// artifactKey returns the key of the given artifact of a job in the upload store.
+func artifactKey(queueName string, jobID int, name string) string {
+	return fmt.Sprintf("executor-artifacts/%s/%d/%s", queueName, jobID, name)
 }
 
 // canceled reaches to the queueOptions.FetchCanceled to determine jobs that need
@@ -2,12 +2,17 @@ package handler
 
 import (
 	"context"
+	"io"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/google/go-cmp/cmp"
 
+	uploadstoremocks "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/uploadstore/mocks"
 	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
 	workerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
@@ -37,7 +42,7 @@ func TestDequeue(t *testing.T) {
 		return transformedJob, nil
 	}
 
-	handler := newHandler(QueueOptions{Store: store, RecordTransformer: recordTransformer})
+	handler := newHandler("test", QueueOptions{Store: store, RecordTransformer: recordTransformer}, &testExecutorStore{}, ArtifactOptions{})
 
 	job, dequeued, err := handler.dequeue(context.Background(), "deadbeef", "test")
 	if err != nil {
@@ -55,7 +60,7 @@ func TestDequeue(t *testing.T) {
 }
 
 func TestDequeueNoRecord(t *testing.T) {
-	handler := newHandler(QueueOptions{Store: workerstoremocks.NewMockStore()})
+	handler := newHandler("test", QueueOptions{Store: workerstoremocks.NewMockStore()}, &testExecutorStore{}, ArtifactOptions{})
 
 	_, dequeued, err := handler.dequeue(context.Background(), "deadbeef", "test")
 	if err != nil {
@@ -75,7 +80,7 @@ func TestAddExecutionLogEntry(t *testing.T) {
 	fakeEntryID := 99
 	store.AddExecutionLogEntryFunc.SetDefaultReturn(fakeEntryID, nil)
 
-	handler := newHandler(QueueOptions{Store: store, RecordTransformer: recordTransformer})
+	handler := newHandler("test", QueueOptions{Store: store, RecordTransformer: recordTransformer}, &testExecutorStore{}, ArtifactOptions{})
 
 	job, dequeued, err := handler.dequeue(context.Background(), "deadbeef", "test")
 	if err != nil {
@@ -112,7 +117,7 @@ func TestAddExecutionLogEntry(t *testing.T) {
 func TestAddExecutionLogEntryUnknownJob(t *testing.T) {
 	store := workerstoremocks.NewMockStore()
 	store.AddExecutionLogEntryFunc.SetDefaultReturn(0, workerstore.ErrExecutionLogEntryNotUpdated)
-	handler := newHandler(QueueOptions{Store: store})
+	handler := newHandler("test", QueueOptions{Store: store}, &testExecutorStore{}, ArtifactOptions{})
 
 	entry := workerutil.ExecutionLogEntry{
 		Command: []string{"ls", "-a"},
@@ -130,7 +135,7 @@ func TestUpdateExecutionLogEntry(t *testing.T) {
 		return apiclient.Job{ID: 42}, nil
 	}
 
-	handler := newHandler(QueueOptions{Store: store, RecordTransformer: recordTransformer})
+	handler := newHandler("test", QueueOptions{Store: store, RecordTransformer: recordTransformer}, &testExecutorStore{}, ArtifactOptions{})
 
 	job, dequeued, err := handler.dequeue(context.Background(), "deadbeef", "test")
 	if err != nil {
@@ -167,7 +172,7 @@ func TestUpdateExecutionLogEntry(t *testing.T) {
 func TestUpdateExecutionLogEntryUnknownJob(t *testing.T) {
 	store := workerstoremocks.NewMockStore()
 	store.UpdateExecutionLogEntryFunc.SetDefaultReturn(workerstore.ErrExecutionLogEntryNotUpdated)
-	handler := newHandler(QueueOptions{Store: store})
+	handler := newHandler("test", QueueOptions{Store: store}, &testExecutorStore{}, ArtifactOptions{})
 
 	entry := workerutil.ExecutionLogEntry{
 		Command: []string{"ls", "-a"},
@@ -186,7 +191,7 @@ func TestMarkComplete(t *testing.T) {
 		return apiclient.Job{ID: 42}, nil
 	}
 
-	handler := newHandler(QueueOptions{Store: store, RecordTransformer: recordTransformer})
+	handler := newHandler("test", QueueOptions{Store: store, RecordTransformer: recordTransformer}, &testExecutorStore{}, ArtifactOptions{})
 
 	job, dequeued, err := handler.dequeue(context.Background(), "deadbeef", "test")
 	if err != nil {
@@ -212,7 +217,7 @@ func TestMarkComplete(t *testing.T) {
 func TestMarkCompleteUnknownJob(t *testing.T) {
 	store := workerstoremocks.NewMockStore()
 	store.MarkCompleteFunc.SetDefaultReturn(false, nil)
-	handler := newHandler(QueueOptions{Store: store})
+	handler := newHandler("test", QueueOptions{Store: store}, &testExecutorStore{}, ArtifactOptions{})
 
 	if err := handler.markComplete(context.Background(), "deadbeef", 42); err != ErrUnknownJob {
 		t.Fatalf("unexpected error. want=%q have=%q", ErrUnknownJob, err)
@@ -223,7 +228,7 @@ func TestMarkCompleteStoreError(t *testing.T) {
 	store := workerstoremocks.NewMockStore()
 	internalErr := errors.New("something went wrong")
 	store.MarkCompleteFunc.SetDefaultReturn(false, internalErr)
-	handler := newHandler(QueueOptions{Store: store})
+	handler := newHandler("test", QueueOptions{Store: store}, &testExecutorStore{}, ArtifactOptions{})
 
 	if err := handler.markComplete(context.Background(), "deadbeef", 42); err != internalErr {
 		t.Fatalf("unexpected error. want=%q have=%q", internalErr, err)
@@ -238,7 +243,7 @@ func TestMarkErrored(t *testing.T) {
 		return apiclient.Job{ID: 42}, nil
 	}
 
-	handler := newHandler(QueueOptions{Store: store, RecordTransformer: recordTransformer})
+	handler := newHandler("test", QueueOptions{Store: store, RecordTransformer: recordTransformer}, &testExecutorStore{}, ArtifactOptions{})
 
 	job, dequeued, err := handler.dequeue(context.Background(), "deadbeef", "test")
 	if err != nil {
@@ -267,7 +272,7 @@ func TestMarkErrored(t *testing.T) {
 func TestMarkErroredUnknownJob(t *testing.T) {
 	store := workerstoremocks.NewMockStore()
 	store.MarkErroredFunc.SetDefaultReturn(false, nil)
-	handler := newHandler(QueueOptions{Store: store})
+	handler := newHandler("test", QueueOptions{Store: store}, &testExecutorStore{}, ArtifactOptions{})
 
 	if err := handler.markErrored(context.Background(), "deadbeef", 42, "OH NO"); err != ErrUnknownJob {
 		t.Fatalf("unexpected error. want=%q have=%q", ErrUnknownJob, err)
@@ -278,7 +283,7 @@ func TestMarkErroredStoreError(t *testing.T) {
 	store := workerstoremocks.NewMockStore()
 	storeErr := errors.New("something went wrong")
 	store.MarkErroredFunc.SetDefaultReturn(false, storeErr)
-	handler := newHandler(QueueOptions{Store: store})
+	handler := newHandler("test", QueueOptions{Store: store}, &testExecutorStore{}, ArtifactOptions{})
 
 	if err := handler.markErrored(context.Background(), "deadbeef", 42, "OH NO"); err != storeErr {
 		t.Fatalf("unexpected error. want=%q have=%q", storeErr, err)
@@ -293,7 +298,7 @@ func TestMarkFailed(t *testing.T) {
 		return apiclient.Job{ID: 42}, nil
 	}
 
-	handler := newHandler(QueueOptions{Store: store, RecordTransformer: recordTransformer})
+	handler := newHandler("test", QueueOptions{Store: store, RecordTransformer: recordTransformer}, &testExecutorStore{}, ArtifactOptions{})
 
 	job, dequeued, err := handler.dequeue(context.Background(), "deadbeef", "test")
 	if err != nil {
@@ -322,7 +327,7 @@ func TestMarkFailed(t *testing.T) {
 func TestMarkFailedUnknownJob(t *testing.T) {
 	store := workerstoremocks.NewMockStore()
 	store.MarkFailedFunc.SetDefaultReturn(false, nil)
-	handler := newHandler(QueueOptions{Store: store})
+	handler := newHandler("test", QueueOptions{Store: store}, &testExecutorStore{}, ArtifactOptions{})
 
 	if err := handler.markFailed(context.Background(), "deadbeef", 42, "OH NO"); err != ErrUnknownJob {
 		t.Fatalf("unexpected error. want=%q have=%q", ErrUnknownJob, err)
@@ -333,7 +338,7 @@ func TestMarkFailedStoreError(t *testing.T) {
 	store := workerstoremocks.NewMockStore()
 	storeErr := errors.New("something went wrong")
 	store.MarkFailedFunc.SetDefaultReturn(false, storeErr)
-	handler := newHandler(QueueOptions{Store: store})
+	handler := newHandler("test", QueueOptions{Store: store}, &testExecutorStore{}, ArtifactOptions{})
 
 	if err := handler.markFailed(context.Background(), "deadbeef", 42, "OH NO"); err != storeErr {
 		t.Fatalf("unexpected error. want=%q have=%q", storeErr, err)
@@ -350,13 +355,137 @@ func TestHeartbeat(t *testing.T) {
 		return []int{testKnownID}, nil
 	})
 
-	handler := newHandler(QueueOptions{Store: s, RecordTransformer: recordTransformer})
+	executorStore := &testExecutorStore{}
+	handler := newHandler("test", QueueOptions{Store: s, RecordTransformer: recordTransformer}, executorStore, ArtifactOptions{})
 
-	if knownIDs, err := handler.heartbeat(context.Background(), "deadbeef", []int{testKnownID, 10}); err != nil {
+	executor := database.Executor{Name: "deadbeef", Hostname: "test-hostname", JobIDs: []int{testKnownID, 10}}
+	if knownIDs, err := handler.heartbeat(context.Background(), executor); err != nil {
 		t.Fatalf("unexpected error performing heartbeat: %s", err)
 	} else if diff := cmp.Diff([]int{testKnownID}, knownIDs); diff != "" {
 		t.Errorf("unexpected unknown ids (-want +got):\n%s", diff)
 	}
+
+	if diff := cmp.Diff([]int{testKnownID, 10}, s.HeartbeatFunc.History()[0].Arg1); diff != "" {
+		t.Errorf("unexpected heartbeat ids (-want +got):\n%s", diff)
+	}
+	if value := s.HeartbeatFunc.History()[0].Arg2.WorkerHostname; value != "deadbeef" {
+		t.Errorf("unexpected worker hostname. want=%q have=%q", "deadbeef", value)
+	}
+
+	executor.QueueName = "test"
+	if diff := cmp.Diff([]database.Executor{executor}, executorStore.heartbeats); diff != "" {
+		t.Errorf("unexpected executor heartbeats (-want +got):\n%s", diff)
+	}
+}
+
+func TestHeartbeatExecutorStoreError(t *testing.T) {
+	s := workerstoremocks.NewMockStore()
+	s.HeartbeatFunc.SetDefaultReturn([]int{10}, nil)
+
+	executorStore := &testExecutorStore{err: errors.New("oops")}
+	handler := newHandler("test", QueueOptions{Store: s}, executorStore, ArtifactOptions{})
+
+	// Jobs are still heartbeated when the executor cannot be recorded.
+	if knownIDs, err := handler.heartbeat(context.Background(), database.Executor{Name: "deadbeef", JobIDs: []int{10}}); err != nil {
+		t.Fatalf("unexpected error performing heartbeat: %s", err)
+	} else if diff := cmp.Diff([]int{10}, knownIDs); diff != "" {
+		t.Errorf("unexpected unknown ids (-want +got):\n%s", diff)
+	}
+}
+
+func TestUploadArtifact(t *testing.T) {
+	s := workerstoremocks.NewMockStore()
+	s.HeartbeatFunc.SetDefaultReturn([]int{42}, nil)
+
+	var uploaded string
+	uploadStore := uploadstoremocks.NewMockStore()
+	uploadStore.UploadFunc.SetDefaultHook(func(ctx context.Context, key string, r io.Reader) (int64, error) {
+		content, err := io.ReadAll(r)
+		uploaded = string(content)
+		return int64(len(content)), err
+	})
+	artifactStore := &testArtifactStore{}
+
+	handler := newHandler("test", QueueOptions{Store: s}, &testExecutorStore{}, ArtifactOptions{
+		UploadStore: uploadStore,
+		Store:       artifactStore,
+		Retention:   time.Hour,
+	})
+
+	if _, err := handler.uploadArtifact(context.Background(), "deadbeef", 42, "diff.patch", strings.NewReader("diff --git")); err != nil {
+		t.Fatalf("unexpected error uploading artifact: %s", err)
+	}
+
+	if value := s.HeartbeatFunc.History()[0].Arg2.WorkerHostname; value != "deadbeef" {
+		t.Errorf("unexpected worker hostname. want=%q have=%q", "deadbeef", value)
+	}
+	if uploaded != "diff --git" {
+		t.Errorf("unexpected uploaded content. want=%q have=%q", "diff --git", uploaded)
+	}
+	if value := uploadStore.UploadFunc.History()[0].Arg1; value != "executor-artifacts/test/42/diff.patch" {
+		t.Errorf("unexpected key. want=%q have=%q", "executor-artifacts/test/42/diff.patch", value)
+	}
+
+	if len(artifactStore.artifacts) != 1 {
+		t.Fatalf("unexpected number of recorded artifacts. want=%d have=%d", 1, len(artifactStore.artifacts))
+	}
+	artifact := artifactStore.artifacts[0]
+	if artifact.ExpiresAt.Before(time.Now().Add(30 * time.Minute)) {
+		t.Errorf("unexpected expiry: %s", artifact.ExpiresAt)
+	}
+	artifact.ExpiresAt = time.Time{}
+	want := database.ExecutorJobArtifact{
+		QueueName: "test",
+		JobID:     42,
+		Name:      "diff.patch",
+		ObjectKey: "executor-artifacts/test/42/diff.patch",
+		Size:      10,
+	}
+	if diff := cmp.Diff(want, artifact); diff != "" {
+		t.Errorf("unexpected artifact (-want +got):\n%s", diff)
+	}
+}
+
+func TestUploadArtifactUnknownJob(t *testing.T) {
+	uploadStore := uploadstoremocks.NewMockStore()
+	handler := newHandler("test", QueueOptions{Store: workerstoremocks.NewMockStore()}, &testExecutorStore{}, ArtifactOptions{
+		UploadStore: uploadStore,
+		Store:       &testArtifactStore{},
+	})
+
+	if _, err := handler.uploadArtifact(context.Background(), "deadbeef", 42, "diff.patch", strings.NewReader("")); err != ErrUnknownJob {
+		t.Fatalf("unexpected error. want=%q have=%q", ErrUnknownJob, err)
+	}
+	if len(uploadStore.UploadFunc.History()) != 0 {
+		t.Errorf("expected nothing to be uploaded")
+	}
+}
+
+func TestUploadArtifactUnsupported(t *testing.T) {
+	handler := newHandler("test", QueueOptions{Store: workerstoremocks.NewMockStore()}, &testExecutorStore{}, ArtifactOptions{})
+
+	if _, err := handler.uploadArtifact(context.Background(), "deadbeef", 42, "diff.patch", strings.NewReader("")); err != ErrArtifactsUnsupported {
+		t.Fatalf("unexpected error. want=%q have=%q", ErrArtifactsUnsupported, err)
+	}
+}
+
+type testArtifactStore struct {
+	artifacts []database.ExecutorJobArtifact
+}
+
+func (s *testArtifactStore) Upsert(ctx context.Context, artifact database.ExecutorJobArtifact) (database.ExecutorJobArtifact, error) {
+	s.artifacts = append(s.artifacts, artifact)
+	return artifact, nil
+}
+
+type testExecutorStore struct {
+	heartbeats []database.Executor
+	err        error
+}
+
+func (s *testExecutorStore) UpsertHeartbeat(ctx context.Context, executor database.Executor) error {
+	s.heartbeats = append(s.heartbeats, executor)
+	return s.err
 }
 
 type testRecord struct {
@@ -7,18 +7,22 @@ import (
 	"io"
 	"net/http"
 	"regexp"
+	"strconv"
 
 	"github.com/gorilla/mux"
 	"github.com/inconshreveable/log15"
 
 	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
 )
 
 // SetupRoutes registers all route handlers required for all configured executor
-// queues with the given router.
-func SetupRoutes(queueOptionsMap map[string]QueueOptions, router *mux.Router) {
+// queues with the given router. The heartbeats of executors are recorded in the
+// given executor store, and the artifacts uploaded by jobs are stored according to
+// the given artifact options.
+func SetupRoutes(queueOptionsMap map[string]QueueOptions, executorStore ExecutorStore, artifacts ArtifactOptions, router *mux.Router) {
 	for name, queueOptions := range queueOptionsMap {
-		h := newHandler(queueOptions)
+		h := newHandler(name, queueOptions, executorStore, artifacts)
 
 		subRouter := router.PathPrefix(fmt.Sprintf("/{queueName:(?:%s)}/", regexp.QuoteMeta(name))).Subrouter()
 		routes := map[string]func(w http.ResponseWriter, r *http.Request){
@@ -30,6 +34,7 @@ func SetupRoutes(queueOptionsMap map[string]QueueOptions, router *mux.Router) {
 			"markFailed":              h.handleMarkFailed,
 			"heartbeat":               h.handleHeartbeat,
 			"canceled":                h.handleCanceled,
+			"uploadArtifact":          h.handleUploadArtifact,
 		}
 		for path, handler := range routes {
 			subRouter.Path(fmt.Sprintf("/%s", path)).Methods("POST").HandlerFunc(handler)
@@ -118,7 +123,18 @@ func (h *handler) handleHeartbeat(w http.ResponseWriter, r *http.Request) {
 	var payload apiclient.HeartbeatRequest
 
 	h.wrapHandler(w, r, &payload, func() (int, interface{}, error) {
-		unknownIDs, err := h.heartbeat(r.Context(), payload.ExecutorName, payload.JobIDs)
+		unknownIDs, err := h.heartbeat(r.Context(), database.Executor{
+			Name:                 payload.ExecutorName,
+			Hostname:             payload.Hostname,
+			OS:                   payload.OS,
+			Architecture:         payload.Architecture,
+			ExecutorVersion:      payload.ExecutorVersion,
+			JobIDs:               payload.JobIDs,
+			NumCPUs:              payload.NumCPUs,
+			MemoryTotalBytes:     payload.MemoryTotalBytes,
+			MemoryAvailableBytes: payload.MemoryAvailableBytes,
+			LoadAverage:          payload.LoadAverage,
+		})
 		return http.StatusOK, unknownIDs, err
 	})
 }
@@ -133,6 +149,39 @@ func (h *handler) handleCanceled(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// POST /{queueName}/uploadArtifact?executorName=...&jobId=...&name=...
+//
+// Unlike the other routes, the request body is the raw content of the artifact.
+func (h *handler) handleUploadArtifact(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	jobID, err := strconv.Atoi(query.Get("jobId"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid job ID: %s", err), http.StatusBadRequest)
+		return
+	}
+	name := query.Get("name")
+	if !apiclient.ArtifactNamePattern.MatchString(name) {
+		http.Error(w, fmt.Sprintf("Invalid artifact name %q", name), http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.uploadArtifact(r.Context(), query.Get("executorName"), jobID, name, r.Body)
+	switch err {
+	case nil:
+		w.WriteHeader(http.StatusNoContent)
+	case ErrUnknownJob:
+		w.WriteHeader(http.StatusNotFound)
+	case ErrArtifactTooLarge:
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case ErrArtifactsUnsupported:
+		http.Error(w, err.Error(), http.StatusNotImplemented)
+	default:
+		log15.Error("Failed to upload artifact", "queue", h.queueName, "jobID", jobID, "name", name, "err", err)
+		http.Error(w, fmt.Sprintf("Failed to upload artifact: %s", err), http.StatusInternalServerError)
+	}
+}
+
 type errorResponse struct {
 	Error string `json:"error"`
 }
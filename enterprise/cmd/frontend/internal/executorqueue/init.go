@@ -5,10 +5,12 @@ import (
 	"os"
 
 	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/enterprise"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/executorqueue/handler"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/executorqueue/queues/batches"
@@ -34,16 +36,40 @@ func Init(ctx context.Context, db dbutil.DB, outOfBandMigrationRunner *oobmigrat
 		"batches":   batches.QueueOptions(db, accessToken, observationContext),
 	}
 
-	handler, err := codeintel.NewCodeIntelUploadHandler(ctx, db, true)
+	uploadHandler, err := codeintel.NewCodeIntelUploadHandler(ctx, db, true)
 	if err != nil {
 		return err
 	}
 
-	queueHandler, err := newExecutorQueueHandler(queueOptions, accessToken, handler)
+	uploadStore, err := codeintel.UploadStore(ctx, db)
 	if err != nil {
 		return err
 	}
 
+	executorStore := database.Executors(db)
+	artifactStore := database.ExecutorJobArtifacts(db)
+	artifactOptions := handler.ArtifactOptions{
+		UploadStore: uploadStore,
+		Store:       artifactStore,
+		Retention:   artifactRetention,
+	}
+
+	queueHandler, err := newExecutorQueueHandler(queueOptions, executorStore, artifactOptions, newArtifactDownloadHandler(artifactStore, uploadStore), accessToken, uploadHandler)
+	if err != nil {
+		return err
+	}
+
+	// Requeue the jobs of executors that stopped sending heartbeats.
+	go newDeadExecutorResetter(executorStore, queueOptions, deadExecutorInterval).Start()
+
+	// Delete the artifacts of executor jobs once they expire.
+	go newArtifactJanitor(artifactStore, uploadStore, artifactJanitorInterval).Start()
+
+	// Export the metrics of all queues to Prometheus and the executors admin page.
+	queueMetrics := newQueueMetricsAggregator(queueOptions)
+	observationContext.Registerer.MustRegister(queueMetrics)
+	graphqlbackend.GetExecutorQueueMetrics = queueMetrics.Metrics
+
 	enterpriseServices.NewExecutorProxyHandler = queueHandler
 	return nil
 }
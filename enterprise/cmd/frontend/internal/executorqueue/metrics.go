@@ -0,0 +1,164 @@
+package executorqueue
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/executorqueue/handler"
+)
+
+const (
+	// queueMetricsWindow is the period over which dequeue latencies and finished jobs
+	// are aggregated.
+	queueMetricsWindow = time.Hour
+
+	// queueMetricsTTL is the duration for which aggregated metrics are reused, so that
+	// scrapes and page loads don't hit the database every time.
+	queueMetricsTTL = 30 * time.Second
+)
+
+// dequeueLatencyPercentiles are the reported percentiles of the time jobs spent in a queue.
+var dequeueLatencyPercentiles = []float64{0.5, 0.9, 0.99}
+
+var (
+	queueDepthDesc = prometheus.NewDesc(
+		"src_executor_queue_depth",
+		"Number of jobs waiting to be dequeued.",
+		[]string{"queue"}, nil,
+	)
+	dequeueLatencyDesc = prometheus.NewDesc(
+		"src_executor_queue_dequeue_latency_seconds",
+		"Percentiles of the time jobs dequeued in the last hour spent in the queue.",
+		[]string{"queue", "quantile"}, nil,
+	)
+	finishedJobsDesc = prometheus.NewDesc(
+		"src_executor_queue_finished_jobs",
+		"Number of jobs that finished in the last hour per image they ran.",
+		[]string{"queue", "image", "state"}, nil,
+	)
+	failureRateDesc = prometheus.NewDesc(
+		"src_executor_queue_failure_rate",
+		"Ratio of jobs that failed to jobs that finished in the last hour per image they ran.",
+		[]string{"queue", "image"}, nil,
+	)
+)
+
+// queueMetricsAggregator aggregates the metrics of all executor queues. It's exported as a
+// Prometheus collector and backs the executorQueueMetrics GraphQL query.
+type queueMetricsAggregator struct {
+	queueOptions map[string]handler.QueueOptions
+
+	mu        sync.Mutex
+	metrics   []graphqlbackend.ExecutorQueueMetrics
+	updatedAt time.Time
+}
+
+var _ prometheus.Collector = &queueMetricsAggregator{}
+
+func newQueueMetricsAggregator(queueOptions map[string]handler.QueueOptions) *queueMetricsAggregator {
+	return &queueMetricsAggregator{queueOptions: queueOptions}
+}
+
+// Metrics returns the metrics of all queues ordered by queue name. The metrics are
+// aggregated again at most once per queueMetricsTTL.
+func (a *queueMetricsAggregator) Metrics(ctx context.Context) ([]graphqlbackend.ExecutorQueueMetrics, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.metrics != nil && time.Since(a.updatedAt) < queueMetricsTTL {
+		return a.metrics, nil
+	}
+
+	names := make([]string, 0, len(a.queueOptions))
+	for name := range a.queueOptions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	metrics := make([]graphqlbackend.ExecutorQueueMetrics, 0, len(names))
+	for _, name := range names {
+		m, err := aggregateQueueMetrics(ctx, name, a.queueOptions[name])
+		if err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, m)
+	}
+
+	a.metrics = metrics
+	a.updatedAt = time.Now()
+	return metrics, nil
+}
+
+func aggregateQueueMetrics(ctx context.Context, name string, queueOptions handler.QueueOptions) (graphqlbackend.ExecutorQueueMetrics, error) {
+	m := graphqlbackend.ExecutorQueueMetrics{QueueName: name}
+
+	depth, err := queueOptions.Store.QueuedCount(ctx, false, nil)
+	if err != nil {
+		return m, errors.Wrapf(err, "%s: Store.QueuedCount", name)
+	}
+	m.Depth = depth
+
+	if queueOptions.JobStatisticsFetcher == nil {
+		return m, nil
+	}
+
+	statistics, err := queueOptions.JobStatisticsFetcher(ctx, time.Now().Add(-queueMetricsWindow), dequeueLatencyPercentiles)
+	if err != nil {
+		return m, errors.Wrapf(err, "%s: JobStatisticsFetcher", name)
+	}
+	if len(statistics.DequeueLatencies) == len(dequeueLatencyPercentiles) {
+		m.DequeueLatencyP50 = statistics.DequeueLatencies[0]
+		m.DequeueLatencyP90 = statistics.DequeueLatencies[1]
+		m.DequeueLatencyP99 = statistics.DequeueLatencies[2]
+	}
+	for _, image := range statistics.Images {
+		m.Images = append(m.Images, graphqlbackend.ExecutorImageMetrics{
+			Image:     image.Image,
+			Completed: image.Completed,
+			Failed:    image.Failed,
+		})
+	}
+
+	return m, nil
+}
+
+func (a *queueMetricsAggregator) Describe(ch chan<- *prometheus.Desc) {
+	ch <- queueDepthDesc
+	ch <- dequeueLatencyDesc
+	ch <- finishedJobsDesc
+	ch <- failureRateDesc
+}
+
+func (a *queueMetricsAggregator) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	metrics, err := a.Metrics(ctx)
+	if err != nil {
+		log15.Error("Failed to aggregate executor queue metrics", "error", err)
+		return
+	}
+
+	for _, m := range metrics {
+		ch <- prometheus.MustNewConstMetric(queueDepthDesc, prometheus.GaugeValue, float64(m.Depth), m.QueueName)
+		ch <- prometheus.MustNewConstMetric(dequeueLatencyDesc, prometheus.GaugeValue, m.DequeueLatencyP50.Seconds(), m.QueueName, "0.5")
+		ch <- prometheus.MustNewConstMetric(dequeueLatencyDesc, prometheus.GaugeValue, m.DequeueLatencyP90.Seconds(), m.QueueName, "0.9")
+		ch <- prometheus.MustNewConstMetric(dequeueLatencyDesc, prometheus.GaugeValue, m.DequeueLatencyP99.Seconds(), m.QueueName, "0.99")
+
+		for _, image := range m.Images {
+			ch <- prometheus.MustNewConstMetric(finishedJobsDesc, prometheus.GaugeValue, float64(image.Completed), m.QueueName, image.Image, "completed")
+			ch <- prometheus.MustNewConstMetric(finishedJobsDesc, prometheus.GaugeValue, float64(image.Failed), m.QueueName, image.Image, "failed")
+
+			if total := image.Completed + image.Failed; total > 0 {
+				ch <- prometheus.MustNewConstMetric(failureRateDesc, prometheus.GaugeValue, float64(image.Failed)/float64(total), m.QueueName, image.Image)
+			}
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package executorqueue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/executorqueue/handler"
+	workerstoremocks "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store/mocks"
+)
+
+func TestQueueMetricsAggregator(t *testing.T) {
+	batchesStore := workerstoremocks.NewMockStore()
+	batchesStore.QueuedCountFunc.SetDefaultReturn(12, nil)
+	codeintelStore := workerstoremocks.NewMockStore()
+	codeintelStore.QueuedCountFunc.SetDefaultReturn(3, nil)
+
+	var fetches int
+	jobStatisticsFetcher := func(ctx context.Context, since time.Time, percentiles []float64) (handler.JobStatistics, error) {
+		fetches++
+		if diff := cmp.Diff(dequeueLatencyPercentiles, percentiles); diff != "" {
+			t.Errorf("unexpected percentiles (-want +got):\n%s", diff)
+		}
+
+		return handler.JobStatistics{
+			DequeueLatencies: []time.Duration{time.Second, 10 * time.Second, time.Minute},
+			Images: []handler.ImageStatistics{
+				{Image: "alpine:3", Completed: 3, Failed: 1},
+			},
+		}, nil
+	}
+
+	aggregator := newQueueMetricsAggregator(map[string]handler.QueueOptions{
+		"codeintel": {Store: codeintelStore},
+		"batches":   {Store: batchesStore, JobStatisticsFetcher: jobStatisticsFetcher},
+	})
+
+	metrics, err := aggregator.Metrics(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []graphqlbackend.ExecutorQueueMetrics{
+		{
+			QueueName:         "batches",
+			Depth:             12,
+			DequeueLatencyP50: time.Second,
+			DequeueLatencyP90: 10 * time.Second,
+			DequeueLatencyP99: time.Minute,
+			Images: []graphqlbackend.ExecutorImageMetrics{
+				{Image: "alpine:3", Completed: 3, Failed: 1},
+			},
+		},
+		{
+			QueueName: "codeintel",
+			Depth:     3,
+		},
+	}
+	if diff := cmp.Diff(expected, metrics); diff != "" {
+		t.Errorf("unexpected metrics (-want +got):\n%s", diff)
+	}
+
+	// Metrics are reused within the TTL.
+	if _, err := aggregator.Metrics(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if fetches != 1 {
+		t.Errorf("unexpected number of fetches. want=%d have=%d", 1, fetches)
+	}
+}
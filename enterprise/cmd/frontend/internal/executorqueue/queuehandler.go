@@ -14,7 +14,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/executorqueue/handler"
 )
 
-func newExecutorQueueHandler(queueOptions map[string]handler.QueueOptions, accessToken func() string, uploadHandler http.Handler) (func() http.Handler, error) {
+func newExecutorQueueHandler(queueOptions map[string]handler.QueueOptions, executorStore handler.ExecutorStore, artifactOptions handler.ArtifactOptions, artifactDownloadHandler http.Handler, accessToken func() string, uploadHandler http.Handler) (func() http.Handler, error) {
 	host, port, err := net.SplitHostPort(envvar.HTTPAddrInternal)
 	if err != nil {
 		return nil, errors.Wrap(err, fmt.Sprintf("failed to parse internal API address %q", envvar.HTTPAddrInternal))
@@ -34,12 +34,18 @@ func newExecutorQueueHandler(queueOptions map[string]handler.QueueOptions, acces
 		base.Path("/git/{rest:.*/(?:info/refs|git-upload-pack)}").Handler(reverseProxy(frontendOrigin))
 
 		// Serve the executor queue API.
-		handler.SetupRoutes(queueOptions, base.PathPrefix("/queue/").Subrouter())
+		handler.SetupRoutes(queueOptions, executorStore, artifactOptions, base.PathPrefix("/queue/").Subrouter())
 
 		// Upload LSIF indexes without a sudo access token or github tokens.
 		base.Path("/lsif/upload").Methods("POST").Handler(uploadHandler)
 
-		return basicAuthMiddleware(accessToken, base)
+		// 🚨 SECURITY: Artifact downloads are requested by users, not executors, so they are
+		// secured by the signature of their URL instead of the shared token.
+		router := mux.NewRouter()
+		router.Path("/.executors/artifacts/{id:[0-9]+}").Methods("GET").Handler(artifactDownloadHandler)
+		router.PathPrefix("/.executors/").Handler(basicAuthMiddleware(accessToken, base))
+
+		return router
 	}
 
 	return factory, nil
@@ -3,6 +3,7 @@ package batches
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/executorqueue/handler"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/background"
@@ -21,10 +22,36 @@ func QueueOptions(db dbutil.DB, accessToken func() string, observationContext *o
 		return transformRecord(ctx, batchesStore, record.(*btypes.BatchSpecWorkspaceExecutionJob), accessToken())
 	}
 
+	jobStatisticsFetcher := func(ctx context.Context, since time.Time, percentiles []float64) (handler.JobStatistics, error) {
+		return fetchJobStatistics(ctx, store.New(db, observationContext, nil), since, percentiles)
+	}
+
 	store := background.NewBatchSpecWorkspaceExecutionWorkerStore(basestore.NewHandleWithDB(db, sql.TxOptions{}), observationContext)
 	return handler.QueueOptions{
 		Store:                  store,
 		RecordTransformer:      recordTransformer,
 		CanceledRecordsFetcher: store.FetchCanceled,
+		JobStatisticsFetcher:   jobStatisticsFetcher,
+	}
+}
+
+// fetchJobStatistics returns the statistics of the workspace execution jobs since the given
+// time. A job counts for every distinct image its steps run.
+func fetchJobStatistics(ctx context.Context, s *store.Store, since time.Time, percentiles []float64) (handler.JobStatistics, error) {
+	latencies, err := s.BatchSpecWorkspaceExecutionJobDequeueLatencyPercentiles(ctx, since, percentiles)
+	if err != nil {
+		return handler.JobStatistics{}, err
 	}
+
+	imageStatistics, err := s.ListBatchSpecWorkspaceExecutionImageStatistics(ctx, since)
+	if err != nil {
+		return handler.JobStatistics{}, err
+	}
+
+	images := make([]handler.ImageStatistics, 0, len(imageStatistics))
+	for _, stat := range imageStatistics {
+		images = append(images, handler.ImageStatistics{Image: stat.Image, Completed: stat.Completed, Failed: stat.Failed})
+	}
+
+	return handler.JobStatistics{DequeueLatencies: latencies, Images: images}, nil
 }
@@ -0,0 +1,88 @@
+package batches
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeowners"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// repoMetadata is the metadata of a repository that is available to the
+// changeset template of a batch spec.
+type repoMetadata struct {
+	Tags     map[string]string
+	Owners   []string
+	Language string
+}
+
+// getRepoMetadata loads the metadata of the repository of the given
+// workspace. It is a variable so that tests can replace it.
+//
+// 🚨 SECURITY: The caller must have checked that the user executing the
+// workspace is allowed to see the repository.
+var getRepoMetadata = func(ctx context.Context, db dbutil.DB, repo *types.Repo, workspace *btypes.BatchSpecWorkspace) (repoMetadata, error) {
+	kvps, err := database.RepoKVPs(db).List(ctx, repo.ID)
+	if err != nil {
+		return repoMetadata{}, errors.Wrap(err, "fetching repo key-value pairs")
+	}
+
+	ruleset, err := codeowners.NewService(db).Ruleset(ctx, types.RepoName{ID: repo.ID, Name: repo.Name})
+	if err != nil {
+		return repoMetadata{}, errors.Wrap(err, "fetching repo ownership rules")
+	}
+
+	inv, err := backend.Repos.GetInventory(ctx, repo, api.CommitID(workspace.Commit), false)
+	if err != nil {
+		return repoMetadata{}, errors.Wrap(err, "fetching repo languages")
+	}
+
+	metadata := repoMetadata{
+		Tags:   make(map[string]string, len(kvps)),
+		Owners: workspaceOwners(ruleset, workspace),
+	}
+	for _, kvp := range kvps {
+		if kvp.Value != nil {
+			metadata.Tags[kvp.Key] = *kvp.Value
+		} else {
+			metadata.Tags[kvp.Key] = ""
+		}
+	}
+	// The languages of an inventory are sorted by lines of code, most first.
+	if len(inv.Languages) > 0 {
+		metadata.Language = inv.Languages[0].Name
+	}
+
+	return metadata, nil
+}
+
+// workspaceOwners returns the sorted, deduplicated owners of the files matched
+// in the workspace, or of the workspace path if no files were matched.
+func workspaceOwners(ruleset *codeowners.Ruleset, workspace *btypes.BatchSpecWorkspace) []string {
+	paths := workspace.FileMatches
+	if len(paths) == 0 {
+		paths = []string{workspace.Path}
+	}
+
+	seen := map[string]struct{}{}
+	owners := []string{}
+	for _, path := range paths {
+		for _, owner := range ruleset.FindOwners(path) {
+			if _, ok := seen[owner]; ok {
+				continue
+			}
+			seen[owner] = struct{}{}
+			owners = append(owners, owner)
+		}
+	}
+	sort.Strings(owners)
+
+	return owners
+}
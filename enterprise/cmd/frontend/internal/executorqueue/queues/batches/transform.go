@@ -69,17 +69,29 @@ func transformRecord(ctx context.Context, s batchesStore, job *btypes.BatchSpecW
 	}
 
 	// 🚨 SECURITY: Set the actor on the context so we check for permissions
-	// when loading the repository.
-	ctx = actor.WithActor(ctx, actor.FromUser(batchSpec.UserID))
+	// when loading the repository. The job runs as the user who started the
+	// execution, which for batch specs in an organization namespace can be a
+	// different maintainer than the creator of the batch spec. Jobs created
+	// before we recorded that user run as the creator.
+	userID := job.UserID
+	if userID == 0 {
+		userID = batchSpec.UserID
+	}
+	ctx = actor.WithActor(ctx, actor.FromUser(userID))
 
 	repo, err := database.Repos(s.DB()).Get(ctx, workspace.RepoID)
 	if err != nil {
 		return apiclient.Job{}, errors.Wrap(err, "fetching repo")
 	}
 
+	metadata, err := getRepoMetadata(ctx, s.DB(), repo, workspace)
+	if err != nil {
+		return apiclient.Job{}, errors.Wrap(err, "fetching repo metadata")
+	}
+
 	// Create an internal access token that will get cleaned up when the job
 	// finishes.
-	token, err := createAndAttachInternalAccessToken(ctx, s, job.ID, batchSpec.UserID)
+	token, err := createAndAttachInternalAccessToken(ctx, s, job.ID, userID)
 	if err != nil {
 		return apiclient.Job{}, errors.Wrap(err, "creating internal access token")
 	}
@@ -89,8 +101,11 @@ func transformRecord(ctx context.Context, s batchesStore, job *btypes.BatchSpecW
 		Workspaces: []*batcheslib.Workspace{
 			{
 				Repository: batcheslib.WorkspaceRepo{
-					ID:   string(graphqlbackend.MarshalRepositoryID(repo.ID)),
-					Name: string(repo.Name),
+					ID:       string(graphqlbackend.MarshalRepositoryID(repo.ID)),
+					Name:     string(repo.Name),
+					Tags:     metadata.Tags,
+					Owners:   metadata.Owners,
+					Language: metadata.Language,
 				},
 				Branch: batcheslib.WorkspaceBranch{
 					Name:   workspace.Branch,
@@ -131,6 +146,7 @@ func transformRecord(ctx context.Context, s batchesStore, job *btypes.BatchSpecW
 		VirtualMachineFiles: map[string]string{"input.json": string(marshaledInput)},
 		CliSteps: []apiclient.CliStep{
 			{
+				Key: btypes.SrcExecStepKey,
 				Commands: []string{
 					"batch",
 					"exec",
@@ -12,6 +12,7 @@ import (
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeowners"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
@@ -24,7 +25,9 @@ import (
 func TestTransformRecord(t *testing.T) {
 	accessToken := "thisissecret-dont-tell-anyone"
 	var accessTokenID int64 = 1234
+	var tokenSubjectUserID int32
 	database.Mocks.AccessTokens.CreateInternal = func(subjectUserID int32, scopes []string, note string, creatorID int32) (int64, string, error) {
+		tokenSubjectUserID = subjectUserID
 		return accessTokenID, accessToken, nil
 	}
 	t.Cleanup(func() { database.Mocks.AccessTokens.CreateInternal = nil })
@@ -34,6 +37,16 @@ func TestTransformRecord(t *testing.T) {
 	}
 	t.Cleanup(func() { database.Mocks.Repos.Get = nil })
 
+	defaultGetRepoMetadata := getRepoMetadata
+	getRepoMetadata = func(ctx context.Context, db dbutil.DB, repo *types.Repo, workspace *btypes.BatchSpecWorkspace) (repoMetadata, error) {
+		return repoMetadata{
+			Tags:     map[string]string{"team": "batchers"},
+			Owners:   []string{"@sourcegraph/batchers"},
+			Language: "Go",
+		}, nil
+	}
+	t.Cleanup(func() { getRepoMetadata = defaultGetRepoMetadata })
+
 	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{ExternalURL: "https://test.io"}})
 	t.Cleanup(func() {
 		conf.Mock(nil)
@@ -65,8 +78,11 @@ func TestTransformRecord(t *testing.T) {
 		Workspaces: []*batcheslib.Workspace{
 			{
 				Repository: batcheslib.WorkspaceRepo{
-					ID:   string(graphqlbackend.MarshalRepositoryID(workspace.RepoID)),
-					Name: "github.com/sourcegraph/sourcegraph",
+					ID:       string(graphqlbackend.MarshalRepositoryID(workspace.RepoID)),
+					Name:     "github.com/sourcegraph/sourcegraph",
+					Tags:     map[string]string{"team": "batchers"},
+					Owners:   []string{"@sourcegraph/batchers"},
+					Language: "Go",
 				},
 				Branch: batcheslib.WorkspaceBranch{
 					Name:   workspace.Branch,
@@ -95,6 +111,7 @@ func TestTransformRecord(t *testing.T) {
 		VirtualMachineFiles: map[string]string{"input.json": string(marshaledInput)},
 		CliSteps: []apiclient.CliStep{
 			{
+				Key: btypes.SrcExecStepKey,
 				Commands: []string{
 					"batch", "exec",
 					"-f", "input.json",
@@ -120,6 +137,23 @@ func TestTransformRecord(t *testing.T) {
 	if store.accessTokenID != accessTokenID {
 		t.Errorf("wrong access token ID set on execution job: %d", store.accessTokenID)
 	}
+	if tokenSubjectUserID != batchSpec.UserID {
+		t.Errorf("access token created for wrong user: %d", tokenSubjectUserID)
+	}
+
+	t.Run("executed by org maintainer", func(t *testing.T) {
+		batchSpec.NamespaceUserID = 0
+		batchSpec.NamespaceOrgID = 99
+		workspaceExecutionJob.UserID = 456
+
+		if _, err := transformRecord(context.Background(), store, workspaceExecutionJob, "hunter2"); err != nil {
+			t.Fatalf("unexpected error transforming record: %s", err)
+		}
+
+		if tokenSubjectUserID != workspaceExecutionJob.UserID {
+			t.Errorf("access token created for wrong user: want %d, have %d", workspaceExecutionJob.UserID, tokenSubjectUserID)
+		}
+	})
 }
 
 type dummyBatchesStore struct {
@@ -141,3 +175,42 @@ func (db *dummyBatchesStore) SetBatchSpecWorkspaceExecutionJobAccessToken(ctx co
 	db.accessTokenID = tokenID
 	return nil
 }
+
+func TestWorkspaceOwners(t *testing.T) {
+	ruleset, err := codeowners.NewRuleset([]codeowners.Rule{
+		{Pattern: "*", Owners: []string{"@sourcegraph/everyone"}},
+		{Pattern: "/a/b/", Owners: []string{"@sourcegraph/batchers", "@alice"}},
+		{Pattern: "*.md", Owners: []string{"@sourcegraph/docs"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name      string
+		workspace *btypes.BatchSpecWorkspace
+		want      []string
+	}{
+		{
+			name:      "file matches",
+			workspace: &btypes.BatchSpecWorkspace{Path: "a/b", FileMatches: []string{"a/b/main.go", "a/b/README.md", "a/b/c/util.go"}},
+			want:      []string{"@alice", "@sourcegraph/batchers", "@sourcegraph/docs"},
+		},
+		{
+			name:      "workspace path",
+			workspace: &btypes.BatchSpecWorkspace{Path: "c"},
+			want:      []string{"@sourcegraph/everyone"},
+		},
+		{
+			name:      "root file",
+			workspace: &btypes.BatchSpecWorkspace{FileMatches: []string{"README"}},
+			want:      []string{"@sourcegraph/everyone"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, workspaceOwners(ruleset, tc.workspace)); diff != "" {
+				t.Errorf("unexpected owners (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package codeintel
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/executorqueue/handler"
 	store "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
@@ -18,8 +19,35 @@ func QueueOptions(db dbutil.DB, accessToken func() string, observationContext *o
 		return transformRecord(record.(store.Index), accessToken())
 	}
 
+	dbStore := store.NewWithDB(db, observationContext)
+	jobStatisticsFetcher := func(ctx context.Context, since time.Time, percentiles []float64) (handler.JobStatistics, error) {
+		return fetchJobStatistics(ctx, dbStore, since, percentiles)
+	}
+
 	return handler.QueueOptions{
-		Store:             store.WorkerutilIndexStore(basestore.NewWithDB(db, sql.TxOptions{}), observationContext),
-		RecordTransformer: recordTransformer,
+		Store:                store.WorkerutilIndexStore(basestore.NewWithDB(db, sql.TxOptions{}), observationContext),
+		RecordTransformer:    recordTransformer,
+		JobStatisticsFetcher: jobStatisticsFetcher,
+	}
+}
+
+// fetchJobStatistics returns the statistics of the index jobs since the given time. The indexer
+// of an index is the image it runs.
+func fetchJobStatistics(ctx context.Context, dbStore *store.Store, since time.Time, percentiles []float64) (handler.JobStatistics, error) {
+	latencies, err := dbStore.IndexDequeueLatencyPercentiles(ctx, since, percentiles)
+	if err != nil {
+		return handler.JobStatistics{}, err
 	}
+
+	indexerStatistics, err := dbStore.GetIndexerStatistics(ctx, since)
+	if err != nil {
+		return handler.JobStatistics{}, err
+	}
+
+	images := make([]handler.ImageStatistics, 0, len(indexerStatistics))
+	for _, s := range indexerStatistics {
+		images = append(images, handler.ImageStatistics{Image: s.Indexer, Completed: s.Completed, Failed: s.Failed})
+	}
+
+	return handler.JobStatistics{DequeueLatencies: latencies, Images: images}, nil
 }
@@ -183,6 +183,25 @@ func (r *Resolver) DeleteSearchContext(ctx context.Context, args graphqlbackend.
 	return &graphqlbackend.EmptyResponse{}, nil
 }
 
+func (r *Resolver) SetSearchContextHidden(ctx context.Context, args graphqlbackend.SetSearchContextHiddenArgs) (*graphqlbackend.EmptyResponse, error) {
+	searchContextSpec, err := unmarshalSearchContextID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	searchContext, err := searchcontexts.ResolveSearchContextSpec(ctx, r.db, searchContextSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	err = searchcontexts.SetSearchContextHidden(ctx, r.db, searchContext, args.Hidden)
+	if err != nil {
+		return nil, err
+	}
+
+	return &graphqlbackend.EmptyResponse{}, nil
+}
+
 func unmarshalSearchContextCursor(cursor *string) (int32, error) {
 	var after int32
 	if cursor == nil {
@@ -246,6 +265,7 @@ func (r *Resolver) SearchContexts(ctx context.Context, args *graphqlbackend.List
 		NamespaceUserIDs:  namespaceUserIDs,
 		NamespaceOrgIDs:   namespaceOrgIDs,
 		NoNamespace:       noNamespace,
+		ExcludeHidden:     args.ExcludeHidden,
 		OrderBy:           orderBy,
 		OrderByDescending: args.Descending,
 	}
@@ -349,6 +369,14 @@ func (r *searchContextResolver) AutoDefined(ctx context.Context) bool {
 	return searchcontexts.IsAutoDefinedSearchContext(r.sc)
 }
 
+func (r *searchContextResolver) Managed(ctx context.Context) bool {
+	return r.sc.Managed
+}
+
+func (r *searchContextResolver) Hidden(ctx context.Context) bool {
+	return r.sc.Hidden
+}
+
 func (r *searchContextResolver) Spec() string {
 	return searchcontexts.GetSearchContextSpec(r.sc)
 }
@@ -377,7 +405,7 @@ func (r *searchContextResolver) Namespace(ctx context.Context) (*graphqlbackend.
 
 func (r *searchContextResolver) ViewerCanManage(ctx context.Context) bool {
 	hasWriteAccess := searchcontexts.ValidateSearchContextWriteAccessForCurrentUser(ctx, r.db, r.sc.NamespaceUserID, r.sc.NamespaceOrgID, r.sc.Public) == nil
-	return !searchcontexts.IsAutoDefinedSearchContext(r.sc) && hasWriteAccess
+	return !searchcontexts.IsAutoDefinedSearchContext(r.sc) && !r.sc.Managed && hasWriteAccess
 }
 
 func (r *searchContextResolver) Repositories(ctx context.Context) ([]graphqlbackend.SearchContextRepositoryRevisionsResolver, error) {
@@ -0,0 +1,27 @@
+package searchcontexts
+
+import (
+	"context"
+	"time"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// syncManagedSearchContextsInterval is the time between two syncs of the managed search contexts.
+const syncManagedSearchContextsInterval = 10 * time.Minute
+
+// syncManagedSearchContexts periodically updates the managed search contexts of the users and
+// organizations from the repositories of their code host connections.
+func syncManagedSearchContexts(ctx context.Context, db dbutil.DB) {
+	ctx = actor.WithInternalActor(ctx)
+	for {
+		if err := database.SearchContexts(db).SyncManagedSearchContexts(ctx); err != nil {
+			log15.Error("syncing managed search contexts", "error", err)
+		}
+		time.Sleep(syncManagedSearchContextsInterval)
+	}
+}
@@ -10,10 +10,11 @@ import (
 type Config struct {
 	env.BaseConfig
 
-	UploadStoreConfig  *uploadstore.Config
-	WorkerPollInterval time.Duration
-	WorkerConcurrency  int
-	WorkerBudget       int64
+	UploadStoreConfig       *uploadstore.Config
+	WorkerPollInterval      time.Duration
+	WorkerHeartbeatInterval time.Duration
+	WorkerConcurrency       int
+	WorkerBudget            int64
 }
 
 func (c *Config) Load() {
@@ -22,6 +23,7 @@ func (c *Config) Load() {
 	c.UploadStoreConfig = uploadStoreConfig
 
 	c.WorkerPollInterval = c.GetInterval("PRECISE_CODE_INTEL_WORKER_POLL_INTERVAL", "1s", "Interval between queries to the upload queue.")
+	c.WorkerHeartbeatInterval = c.GetInterval("PRECISE_CODE_INTEL_WORKER_HEARTBEAT_INTERVAL", "1s", "Interval between heartbeat updates to the records of the uploads being processed.")
 	c.WorkerConcurrency = c.GetInt("PRECISE_CODE_INTEL_WORKER_CONCURRENCY", "1", "The maximum number of indexes that can be processed concurrently.")
 	c.WorkerBudget = int64(c.GetInt("PRECISE_CODE_INTEL_WORKER_BUDGET", "0", "The amount of compressed input data (in bytes) a worker can process concurrently. Zero acts as an infinite budget."))
 }
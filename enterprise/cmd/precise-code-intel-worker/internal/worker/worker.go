@@ -11,9 +11,6 @@ import (
 	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
 )
 
-// UploadHeartbeatInterval is the duration between heartbeat updates to the upload job records.
-const UploadHeartbeatInterval = time.Second
-
 func NewWorker(
 	dbStore DBStore,
 	workerStore dbworkerstore.Store,
@@ -21,6 +18,7 @@ func NewWorker(
 	uploadStore uploadstore.Store,
 	gitserverClient GitserverClient,
 	pollInterval time.Duration,
+	heartbeatInterval time.Duration,
 	numProcessorRoutines int,
 	budgetMax int64,
 	workerMetrics workerutil.WorkerMetrics,
@@ -41,7 +39,7 @@ func NewWorker(
 		Name:              "precise_code_intel_upload_worker",
 		NumHandlers:       numProcessorRoutines,
 		Interval:          pollInterval,
-		HeartbeatInterval: UploadHeartbeatInterval,
+		HeartbeatInterval: heartbeatInterval,
 		Metrics:           workerMetrics,
 	})
 }
@@ -104,6 +104,7 @@ func main() {
 		uploadStore,
 		gitserverClient,
 		config.WorkerPollInterval,
+		config.WorkerHeartbeatInterval,
 		config.WorkerConcurrency,
 		config.WorkerBudget,
 		makeWorkerMetrics(observationContext),
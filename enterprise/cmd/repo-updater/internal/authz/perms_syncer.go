@@ -89,6 +89,7 @@ func (s *PermsSyncer) ScheduleUsers(ctx context.Context, opts authz.FetchPermsOp
 	}
 
 	s.scheduleUsers(ctx, users...)
+	s.setSyncQueued(ctx, requestTypeUser, userIDs...)
 }
 
 func (s *PermsSyncer) scheduleUsers(ctx context.Context, users ...scheduledUser) {
@@ -135,6 +136,29 @@ func (s *PermsSyncer) ScheduleRepos(ctx context.Context, repoIDs ...api.RepoID)
 	}
 
 	s.scheduleRepos(ctx, repos...)
+
+	ids := make([]int32, len(repoIDs))
+	for i := range repoIDs {
+		ids[i] = int32(repoIDs[i])
+	}
+	s.setSyncQueued(ctx, requestTypeRepo, ids...)
+}
+
+// setSyncQueued records the requests of given type and IDs which are waiting in the
+// queue as queued in the database, so that site admins can see their progress.
+// Requests which are already being processed are skipped.
+func (s *PermsSyncer) setSyncQueued(ctx context.Context, typ requestType, ids ...int32) {
+	waiting := ids[:0:0]
+	for _, id := range ids {
+		if s.queue.isWaiting(typ, id) {
+			waiting = append(waiting, id)
+		}
+	}
+
+	err := s.permsStore.SetPermsSyncQueued(ctx, permsSyncType(typ), waiting...)
+	if err != nil {
+		log15.Warn("PermsSyncer.setSyncQueued", "type", typ, "ids", waiting, "error", err)
+	}
 }
 
 func (s *PermsSyncer) scheduleRepos(ctx context.Context, repos ...scheduledRepo) {
@@ -220,19 +244,20 @@ func (s *PermsSyncer) listPrivateRepoNamesByExact(ctx context.Context, repoSpecs
 // connections) to list all accessible private repositories on code hosts for
 // the given user.
 //
-// It returns a list of internal database repository IDs and is a noop when
-// `envvar.SourcegraphDotComMode()` is true.
-func (s *PermsSyncer) fetchUserPermsViaExternalAccounts(ctx context.Context, user *types.User, noPerms bool, fetchOpts authz.FetchPermsOptions) (repoIDs []uint32, err error) {
+// It returns a list of internal database repository IDs and the sub-repo
+// permissions of repositories the user can only access partially, and is a
+// noop when `envvar.SourcegraphDotComMode()` is true.
+func (s *PermsSyncer) fetchUserPermsViaExternalAccounts(ctx context.Context, user *types.User, noPerms bool, fetchOpts authz.FetchPermsOptions) (repoIDs []uint32, subRepoPerms map[api.ExternalRepoSpec]*authz.SubRepoPermissions, err error) {
 	// NOTE: OAuth scope on sourcegraph.com does not grant access to read private
 	//  repositories, therefore it is no point wasting effort and code host API rate
 	//  limit quota on trying.
 	if envvar.SourcegraphDotComMode() {
-		return []uint32{}, nil
+		return []uint32{}, nil, nil
 	}
 
 	accts, err := s.permsStore.ListExternalAccounts(ctx, user.ID)
 	if err != nil {
-		return nil, errors.Wrap(err, "list external accounts")
+		return nil, nil, errors.Wrap(err, "list external accounts")
 	}
 
 	serviceToAccounts := make(map[string]*extsvc.Account)
@@ -247,7 +272,7 @@ func (s *PermsSyncer) fetchUserPermsViaExternalAccounts(ctx context.Context, use
 		},
 	)
 	if err != nil {
-		return nil, errors.Wrap(err, "list user verified emails")
+		return nil, nil, errors.Wrap(err, "list user verified emails")
 	}
 
 	emails := make([]string, len(userEmails))
@@ -295,6 +320,7 @@ func (s *PermsSyncer) fetchUserPermsViaExternalAccounts(ctx context.Context, use
 	}
 
 	var repoSpecs, includeContainsSpecs, excludeContainsSpecs []api.ExternalRepoSpec
+	subRepoPerms = make(map[api.ExternalRepoSpec]*authz.SubRepoPermissions)
 	for _, acct := range accts {
 		provider := byServiceID[acct.ServiceID]
 		if provider == nil {
@@ -303,7 +329,7 @@ func (s *PermsSyncer) fetchUserPermsViaExternalAccounts(ctx context.Context, use
 		}
 
 		if err := s.waitForRateLimit(ctx, provider.ServiceID(), 1); err != nil {
-			return nil, errors.Wrap(err, "wait for rate limiter")
+			return nil, nil, errors.Wrap(err, "wait for rate limiter")
 		}
 
 		extPerms, err := provider.FetchUserPerms(ctx, acct, fetchOpts)
@@ -319,7 +345,7 @@ func (s *PermsSyncer) fetchUserPermsViaExternalAccounts(ctx context.Context, use
 			if unauthorized || accountSuspended || forbidden {
 				err = accounts.TouchExpired(ctx, acct.ID)
 				if err != nil {
-					return nil, errors.Wrapf(err, "set expired for external account %d", acct.ID)
+					return nil, nil, errors.Wrapf(err, "set expired for external account %d", acct.ID)
 				}
 				log15.Debug("PermsSyncer.syncUserPerms.setExternalAccountExpired",
 					"userID", user.ID,
@@ -335,13 +361,13 @@ func (s *PermsSyncer) fetchUserPermsViaExternalAccounts(ctx context.Context, use
 
 			// Process partial results if this is an initial fetch.
 			if !noPerms {
-				return nil, errors.Wrap(err, "fetch user permissions")
+				return nil, nil, errors.Wrap(err, "fetch user permissions")
 			}
 			log15.Warn("PermsSyncer.syncUserPerms.proceedWithPartialResults", "userID", user.ID, "error", err)
 		} else {
 			err = accounts.TouchLastValid(ctx, acct.ID)
 			if err != nil {
-				return nil, errors.Wrapf(err, "set last valid for external account %d", acct.ID)
+				return nil, nil, errors.Wrapf(err, "set last valid for external account %d", acct.ID)
 			}
 		}
 
@@ -376,12 +402,20 @@ func (s *PermsSyncer) fetchUserPermsViaExternalAccounts(ctx context.Context, use
 				},
 			)
 		}
+		for repoID, perms := range extPerms.SubRepoPermissions {
+			spec := api.ExternalRepoSpec{
+				ID:          string(repoID),
+				ServiceType: provider.ServiceType(),
+				ServiceID:   provider.ServiceID(),
+			}
+			subRepoPerms[spec] = perms
+		}
 	}
 
 	// Get corresponding internal database IDs
 	repoNames, err := s.listPrivateRepoNamesByExact(ctx, repoSpecs)
 	if err != nil {
-		return nil, errors.Wrap(err, "list external repositories by exact matching")
+		return nil, nil, errors.Wrap(err, "list external repositories by exact matching")
 	}
 
 	// Exclusions are relative to inclusions, so if there is no inclusion, exclusion
@@ -395,7 +429,7 @@ func (s *PermsSyncer) fetchUserPermsViaExternalAccounts(ctx context.Context, use
 			},
 		)
 		if err != nil {
-			return nil, errors.Wrap(err, "list external repositories by contains matching")
+			return nil, nil, errors.Wrap(err, "list external repositories by contains matching")
 		}
 		repoNames = append(repoNames, rs...)
 	}
@@ -404,7 +438,7 @@ func (s *PermsSyncer) fetchUserPermsViaExternalAccounts(ctx context.Context, use
 	for _, r := range repoNames {
 		repoIDs = append(repoIDs, uint32(r.ID))
 	}
-	return repoIDs, nil
+	return repoIDs, subRepoPerms, nil
 }
 
 // syncUserPerms processes permissions syncing request in user-centric way. When `noPerms` is true,
@@ -425,7 +459,7 @@ func (s *PermsSyncer) syncUserPerms(ctx context.Context, userID int32, noPerms b
 		return errors.Wrap(err, "list external service repo IDs by user ID")
 	}
 
-	fetchedRepoIDs, err := s.fetchUserPermsViaExternalAccounts(ctx, user, noPerms, fetchOpts)
+	fetchedRepoIDs, subRepoPerms, err := s.fetchUserPermsViaExternalAccounts(ctx, user, noPerms, fetchOpts)
 	if err != nil {
 		return errors.Wrap(err, "fetch user permissions via external accounts")
 	}
@@ -447,6 +481,11 @@ func (s *PermsSyncer) syncUserPerms(ctx context.Context, userID int32, noPerms b
 		return errors.Wrap(err, "set user permissions")
 	}
 
+	err = edb.SubRepoPermsWith(s.permsStore).SetUserSubRepoPermissions(ctx, user.ID, subRepoPerms)
+	if err != nil {
+		return errors.Wrap(err, "set user sub-repo permissions")
+	}
+
 	log15.Debug("PermsSyncer.syncUserPerms.synced",
 		"userID", user.ID,
 		"count", p.IDs.GetCardinality(),
@@ -533,6 +572,13 @@ func (s *PermsSyncer) syncRepoPerms(ctx context.Context, repoID api.RepoID, noPe
 			return errors.Wrap(s.permsStore.TouchRepoPermissions(ctx, int32(repoID)), "touch repository permissions")
 		}
 
+		// Permissions of repositories on code hosts that do not support fetching
+		// repository permissions are kept up to date by user-centric syncing only.
+		if errors.Is(err, authz.ErrUnimplemented) {
+			log15.Debug("PermsSyncer.syncRepoPerms.unimplemented", "repoID", repo.ID, "serviceType", provider.ServiceType())
+			return errors.Wrap(s.permsStore.TouchRepoPermissions(ctx, int32(repoID)), "touch repository permissions")
+		}
+
 		if err != nil {
 			// Process partial results if this is an initial fetch.
 			if !noPerms {
@@ -640,19 +686,37 @@ func (s *PermsSyncer) waitForRateLimit(ctx context.Context, serviceID string, n
 func (s *PermsSyncer) syncPerms(ctx context.Context, request *syncRequest) error {
 	defer s.queue.remove(request.Type, request.ID, true)
 
+	if request.Type != requestTypeUser && request.Type != requestTypeRepo {
+		return errors.Errorf("unexpected request type: %v", request.Type)
+	}
+
+	typ := permsSyncType(request.Type)
+	if err := s.permsStore.SetPermsSyncStarted(ctx, typ, request.ID); err != nil {
+		log15.Warn("PermsSyncer.syncPerms.setStarted", "type", typ, "id", request.ID, "error", err)
+	}
+
 	var err error
-	switch request.Type {
-	case requestTypeUser:
+	if request.Type == requestTypeUser {
 		err = s.syncUserPerms(ctx, request.ID, request.NoPerms, request.Options)
-	case requestTypeRepo:
+	} else {
 		err = s.syncRepoPerms(ctx, api.RepoID(request.ID), request.NoPerms, request.Options)
-	default:
-		err = errors.Errorf("unexpected request type: %v", request.Type)
 	}
 
+	if err := s.permsStore.SetPermsSyncFinished(ctx, typ, request.ID, err); err != nil {
+		log15.Warn("PermsSyncer.syncPerms.setFinished", "type", typ, "id", request.ID, "error", err)
+	}
 	return err
 }
 
+// permsSyncType returns the type of permissions syncing recorded in the database
+// for the request type.
+func permsSyncType(typ requestType) edb.PermsSyncType {
+	if typ == requestTypeUser {
+		return edb.PermsSyncTypeUser
+	}
+	return edb.PermsSyncTypeRepo
+}
+
 func (s *PermsSyncer) runSync(ctx context.Context) {
 	log15.Debug("PermsSyncer.runSync.started")
 	defer log15.Info("PermsSyncer.runSync.stopped")
@@ -1033,6 +1097,12 @@ func (s *PermsSyncer) collectMetrics(ctx context.Context) {
 // Run kicks off the permissions syncing process, this method is blocking and
 // should be called as a goroutine.
 func (s *PermsSyncer) Run(ctx context.Context) {
+	// The queue starts empty, any sync recorded as queued or processing in the database
+	// was lost with the previous process.
+	if err := s.permsStore.ResetPermsSyncStates(ctx); err != nil {
+		log15.Error("Failed to reset permissions sync states", "err", err)
+	}
+
 	go s.runSync(ctx)
 	go s.runSchedule(ctx)
 	go s.collectMetrics(ctx)
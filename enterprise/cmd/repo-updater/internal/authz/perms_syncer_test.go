@@ -28,7 +28,17 @@ func TestPermsSyncer_ScheduleUsers(t *testing.T) {
 	authz.SetProviders(true, []authz.Provider{&mockProvider{}})
 	defer authz.SetProviders(true, nil)
 
-	s := NewPermsSyncer(nil, nil, nil, nil)
+	var queued []int32
+	edb.Mocks.Perms.SetPermsSyncQueued = func(_ context.Context, typ edb.PermsSyncType, ids ...int32) error {
+		if typ != edb.PermsSyncTypeUser {
+			return errors.Errorf("type: want %q but got %q", edb.PermsSyncTypeUser, typ)
+		}
+		queued = append(queued, ids...)
+		return nil
+	}
+	defer func() { edb.Mocks.Perms = edb.MockPerms{} }()
+
+	s := NewPermsSyncer(nil, edb.Perms(nil, timeutil.Now), nil, nil)
 	s.ScheduleUsers(context.Background(), authz.FetchPermsOptions{}, 1)
 
 	expHeap := []*syncRequest{
@@ -41,13 +51,26 @@ func TestPermsSyncer_ScheduleUsers(t *testing.T) {
 	if diff := cmp.Diff(expHeap, s.queue.heap, cmpOpts); diff != "" {
 		t.Fatalf("heap: %v", diff)
 	}
+	if diff := cmp.Diff([]int32{1}, queued); diff != "" {
+		t.Fatalf("queued: %v", diff)
+	}
 }
 
 func TestPermsSyncer_ScheduleRepos(t *testing.T) {
 	authz.SetProviders(true, []authz.Provider{&mockProvider{}})
 	defer authz.SetProviders(true, nil)
 
-	s := NewPermsSyncer(nil, nil, nil, nil)
+	var queued []int32
+	edb.Mocks.Perms.SetPermsSyncQueued = func(_ context.Context, typ edb.PermsSyncType, ids ...int32) error {
+		if typ != edb.PermsSyncTypeRepo {
+			return errors.Errorf("type: want %q but got %q", edb.PermsSyncTypeRepo, typ)
+		}
+		queued = append(queued, ids...)
+		return nil
+	}
+	defer func() { edb.Mocks.Perms = edb.MockPerms{} }()
+
+	s := NewPermsSyncer(nil, edb.Perms(nil, timeutil.Now), nil, nil)
 	s.ScheduleRepos(context.Background(), 1)
 
 	expHeap := []*syncRequest{
@@ -60,6 +83,9 @@ func TestPermsSyncer_ScheduleRepos(t *testing.T) {
 	if diff := cmp.Diff(expHeap, s.queue.heap, cmpOpts); diff != "" {
 		t.Fatalf("heap: %v", diff)
 	}
+	if diff := cmp.Diff([]int32{1}, queued); diff != "" {
+		t.Fatalf("queued: %v", diff)
+	}
 }
 
 type mockProvider struct {
@@ -131,6 +157,9 @@ func TestPermsSyncer_syncUserPerms_unionExternalServiceRepos(t *testing.T) {
 	edb.Mocks.Perms.ListExternalAccounts = func(context.Context, int32) ([]*extsvc.Account, error) {
 		return []*extsvc.Account{&extAccount}, nil
 	}
+	edb.Mocks.SubRepoPerms.SetUserSubRepoPermissions = func(context.Context, int32, map[api.ExternalRepoSpec]*authz.SubRepoPermissions) error {
+		return nil
+	}
 	edb.Mocks.Perms.SetUserPermissions = func(_ context.Context, p *authz.UserPermissions) error {
 		wantIDs := []uint32{1, 2, 3, 4}
 		if diff := cmp.Diff(wantIDs, p.IDs.ToArray()); diff != "" {
@@ -159,6 +188,7 @@ func TestPermsSyncer_syncUserPerms_unionExternalServiceRepos(t *testing.T) {
 	defer func() {
 		database.Mocks = database.MockStores{}
 		edb.Mocks.Perms = edb.MockPerms{}
+		edb.Mocks.SubRepoPerms = edb.MockSubRepoPerms{}
 	}()
 
 	permsStore := edb.Perms(nil, timeutil.Now)
@@ -201,6 +231,9 @@ func TestPermsSyncer_syncUserPerms(t *testing.T) {
 	edb.Mocks.Perms.ListExternalAccounts = func(context.Context, int32) ([]*extsvc.Account, error) {
 		return []*extsvc.Account{&extAccount}, nil
 	}
+	edb.Mocks.SubRepoPerms.SetUserSubRepoPermissions = func(context.Context, int32, map[api.ExternalRepoSpec]*authz.SubRepoPermissions) error {
+		return nil
+	}
 	edb.Mocks.Perms.SetUserPermissions = func(_ context.Context, p *authz.UserPermissions) error {
 		if p.UserID != 1 {
 			return errors.Errorf("UserID: want 1 but got %d", p.UserID)
@@ -227,6 +260,7 @@ func TestPermsSyncer_syncUserPerms(t *testing.T) {
 	defer func() {
 		database.Mocks = database.MockStores{}
 		edb.Mocks.Perms = edb.MockPerms{}
+		edb.Mocks.SubRepoPerms = edb.MockSubRepoPerms{}
 	}()
 
 	permsStore := edb.Perms(nil, timeutil.Now)
@@ -284,6 +318,9 @@ func TestPermsSyncer_syncUserPerms_tokenExpire(t *testing.T) {
 	edb.Mocks.Perms.ListExternalAccounts = func(context.Context, int32) ([]*extsvc.Account, error) {
 		return []*extsvc.Account{&extAccount}, nil
 	}
+	edb.Mocks.SubRepoPerms.SetUserSubRepoPermissions = func(context.Context, int32, map[api.ExternalRepoSpec]*authz.SubRepoPermissions) error {
+		return nil
+	}
 	edb.Mocks.Perms.SetUserPermissions = func(_ context.Context, p *authz.UserPermissions) error {
 		return nil
 	}
@@ -302,6 +339,7 @@ func TestPermsSyncer_syncUserPerms_tokenExpire(t *testing.T) {
 	defer func() {
 		database.Mocks = database.MockStores{}
 		edb.Mocks.Perms = edb.MockPerms{}
+		edb.Mocks.SubRepoPerms = edb.MockSubRepoPerms{}
 	}()
 
 	permsStore := edb.Perms(nil, timeutil.Now)
@@ -399,6 +437,9 @@ func TestPermsSyncer_syncUserPerms_prefixSpecs(t *testing.T) {
 	edb.Mocks.Perms.ListExternalAccounts = func(context.Context, int32) ([]*extsvc.Account, error) {
 		return []*extsvc.Account{&extAccount}, nil
 	}
+	edb.Mocks.SubRepoPerms.SetUserSubRepoPermissions = func(context.Context, int32, map[api.ExternalRepoSpec]*authz.SubRepoPermissions) error {
+		return nil
+	}
 	edb.Mocks.Perms.SetUserPermissions = func(_ context.Context, p *authz.UserPermissions) error {
 		return nil
 	}
@@ -421,6 +462,7 @@ func TestPermsSyncer_syncUserPerms_prefixSpecs(t *testing.T) {
 	defer func() {
 		database.Mocks = database.MockStores{}
 		edb.Mocks.Perms = edb.MockPerms{}
+		edb.Mocks.SubRepoPerms = edb.MockSubRepoPerms{}
 	}()
 
 	permsStore := edb.Perms(nil, timeutil.Now)
@@ -439,6 +481,87 @@ func TestPermsSyncer_syncUserPerms_prefixSpecs(t *testing.T) {
 	}
 }
 
+func TestPermsSyncer_syncUserPerms_subRepoPermissions(t *testing.T) {
+	p := &mockProvider{
+		serviceType: extsvc.TypePerforce,
+		serviceID:   "ssl:111.222.333.444:1666",
+	}
+	authz.SetProviders(false, []authz.Provider{p})
+	defer authz.SetProviders(true, nil)
+
+	extAccount := extsvc.Account{
+		AccountSpec: extsvc.AccountSpec{
+			ServiceType: p.ServiceType(),
+			ServiceID:   p.ServiceID(),
+		},
+	}
+
+	database.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id}, nil
+	}
+	database.Mocks.ExternalAccounts.TouchLastValid = func(ctx context.Context, id int32) error {
+		return nil
+	}
+	edb.Mocks.Perms.ListExternalAccounts = func(context.Context, int32) ([]*extsvc.Account, error) {
+		return []*extsvc.Account{&extAccount}, nil
+	}
+	edb.Mocks.Perms.SetUserPermissions = func(_ context.Context, p *authz.UserPermissions) error {
+		return nil
+	}
+	database.Mocks.Repos.ListRepoNames = func(v0 context.Context, args database.ReposListOptions) ([]types.RepoName, error) {
+		return []types.RepoName{{ID: 1}}, nil
+	}
+	database.Mocks.UserEmails.ListByUser = func(ctx context.Context, opt database.UserEmailsListOptions) ([]*database.UserEmail, error) {
+		return nil, nil
+	}
+	database.Mocks.Repos.ListExternalServiceRepoIDsByUserID = func(ctx context.Context, userID int32) ([]api.RepoID, error) {
+		return []api.RepoID{}, nil
+	}
+
+	var gotSubRepoPerms map[api.ExternalRepoSpec]*authz.SubRepoPermissions
+	edb.Mocks.SubRepoPerms.SetUserSubRepoPermissions = func(_ context.Context, userID int32, perms map[api.ExternalRepoSpec]*authz.SubRepoPermissions) error {
+		if userID != 1 {
+			return errors.Errorf("userID: want 1 but got %d", userID)
+		}
+		gotSubRepoPerms = perms
+		return nil
+	}
+	defer func() {
+		database.Mocks = database.MockStores{}
+		edb.Mocks.Perms = edb.MockPerms{}
+		edb.Mocks.SubRepoPerms = edb.MockSubRepoPerms{}
+	}()
+
+	permsStore := edb.Perms(nil, timeutil.Now)
+	s := NewPermsSyncer(repos.NewStore(&dbtesting.MockDB{}, sql.TxOptions{}), permsStore, timeutil.Now, nil)
+
+	subRepoPerms := &authz.SubRepoPermissions{Paths: []string{"**", "-Security/**"}}
+	p.fetchUserPerms = func(context.Context, *extsvc.Account) (*authz.ExternalUserPermissions, error) {
+		return &authz.ExternalUserPermissions{
+			Exacts: []extsvc.RepoID{"//Engineering/"},
+			SubRepoPermissions: map[extsvc.RepoID]*authz.SubRepoPermissions{
+				"//Engineering/": subRepoPerms,
+			},
+		}, nil
+	}
+
+	err := s.syncUserPerms(context.Background(), 1, false, authz.FetchPermsOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantSubRepoPerms := map[api.ExternalRepoSpec]*authz.SubRepoPermissions{
+		{
+			ID:          "//Engineering/",
+			ServiceType: p.ServiceType(),
+			ServiceID:   p.ServiceID(),
+		}: subRepoPerms,
+	}
+	if diff := cmp.Diff(wantSubRepoPerms, gotSubRepoPerms); diff != "" {
+		t.Fatalf("SubRepoPermissions mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func TestPermsSyncer_syncRepoPerms(t *testing.T) {
 	newPermsSyncer := func(store *repos.Store) *PermsSyncer {
 		return NewPermsSyncer(store, edb.Perms(nil, timeutil.Now), timeutil.Now, nil)
@@ -194,6 +194,15 @@ func (q *requestQueue) release(typ requestType, id int32) {
 	heap.Fix(q, request.index)
 }
 
+// isWaiting returns true if the sync request is in the queue and not yet acquired.
+func (q *requestQueue) isWaiting(typ requestType, id int32) bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	request := q.index[requestQueueKey{typ: typ, id: id}]
+	return request != nil && !request.acquired
+}
+
 // The following methods implement heap.Interface based on the priority queue example:
 // https://golang.org/pkg/container/heap/#example__priorityQueue
 // These methods are not safe for concurrent use. Therefore, it is the caller's
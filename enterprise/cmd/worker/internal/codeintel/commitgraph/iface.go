@@ -19,6 +19,16 @@ type DBStore interface {
 		dirtyToken int,
 		now time.Time,
 	) error
+	CalculateVisibleUploadsIncremental(
+		ctx context.Context,
+		repositoryID int,
+		graph *gitserver.CommitGraph,
+		refDescriptions map[string][]gitserver.RefDescription,
+		maxAgeForNonStaleBranches, maxAgeForNonStaleTags time.Duration,
+		dirtyToken int,
+		now time.Time,
+	) (bool, error)
+	CommitGraphWatermark(ctx context.Context, repositoryID int) ([]string, bool, error)
 	GetOldestCommitDate(ctx context.Context, repositoryID int) (time.Time, bool, error)
 }
 
@@ -29,4 +39,5 @@ type Locker interface {
 type GitserverClient interface {
 	RefDescriptions(ctx context.Context, repositoryID int) (map[string][]gitserver.RefDescription, error)
 	CommitGraph(ctx context.Context, repositoryID int, options gitserver.CommitGraphOptions) (*gitserver.CommitGraph, error)
+	CommitGraphSince(ctx context.Context, repositoryID int, tips, watermark []string) (*gitserver.CommitGraph, error)
 }
@@ -19,6 +19,13 @@ type MockDBStore struct {
 	// CalculateVisibleUploadsFunc is an instance of a mock function object
 	// controlling the behavior of the method CalculateVisibleUploads.
 	CalculateVisibleUploadsFunc *DBStoreCalculateVisibleUploadsFunc
+	// CalculateVisibleUploadsIncrementalFunc is an instance of a mock
+	// function object controlling the behavior of the method
+	// CalculateVisibleUploadsIncremental.
+	CalculateVisibleUploadsIncrementalFunc *DBStoreCalculateVisibleUploadsIncrementalFunc
+	// CommitGraphWatermarkFunc is an instance of a mock function object
+	// controlling the behavior of the method CommitGraphWatermark.
+	CommitGraphWatermarkFunc *DBStoreCommitGraphWatermarkFunc
 	// DirtyRepositoriesFunc is an instance of a mock function object
 	// controlling the behavior of the method DirtyRepositories.
 	DirtyRepositoriesFunc *DBStoreDirtyRepositoriesFunc
@@ -36,6 +43,16 @@ func NewMockDBStore() *MockDBStore {
 				return nil
 			},
 		},
+		CalculateVisibleUploadsIncrementalFunc: &DBStoreCalculateVisibleUploadsIncrementalFunc{
+			defaultHook: func(context.Context, int, *gitserver.CommitGraph, map[string][]gitserver.RefDescription, time.Duration, time.Duration, int, time.Time) (bool, error) {
+				return false, nil
+			},
+		},
+		CommitGraphWatermarkFunc: &DBStoreCommitGraphWatermarkFunc{
+			defaultHook: func(context.Context, int) ([]string, bool, error) {
+				return nil, false, nil
+			},
+		},
 		DirtyRepositoriesFunc: &DBStoreDirtyRepositoriesFunc{
 			defaultHook: func(context.Context) (map[int]int, error) {
 				return nil, nil
@@ -56,6 +73,12 @@ func NewMockDBStoreFrom(i DBStore) *MockDBStore {
 		CalculateVisibleUploadsFunc: &DBStoreCalculateVisibleUploadsFunc{
 			defaultHook: i.CalculateVisibleUploads,
 		},
+		CalculateVisibleUploadsIncrementalFunc: &DBStoreCalculateVisibleUploadsIncrementalFunc{
+			defaultHook: i.CalculateVisibleUploadsIncremental,
+		},
+		CommitGraphWatermarkFunc: &DBStoreCommitGraphWatermarkFunc{
+			defaultHook: i.CommitGraphWatermark,
+		},
 		DirtyRepositoriesFunc: &DBStoreDirtyRepositoriesFunc{
 			defaultHook: i.DirtyRepositories,
 		},
@@ -191,6 +214,250 @@ func (c DBStoreCalculateVisibleUploadsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// DBStoreCalculateVisibleUploadsIncrementalFunc describes the behavior when
+// the CalculateVisibleUploadsIncremental method of the parent MockDBStore
+// instance is invoked.
+type DBStoreCalculateVisibleUploadsIncrementalFunc struct {
+	defaultHook func(context.Context, int, *gitserver.CommitGraph, map[string][]gitserver.RefDescription, time.Duration, time.Duration, int, time.Time) (bool, error)
+	hooks       []func(context.Context, int, *gitserver.CommitGraph, map[string][]gitserver.RefDescription, time.Duration, time.Duration, int, time.Time) (bool, error)
+	history     []DBStoreCalculateVisibleUploadsIncrementalFuncCall
+	mutex       sync.Mutex
+}
+
+// CalculateVisibleUploadsIncremental delegates to the next hook function in
+// the queue and stores the parameter and result values of this invocation.
+func (m *MockDBStore) CalculateVisibleUploadsIncremental(v0 context.Context, v1 int, v2 *gitserver.CommitGraph, v3 map[string][]gitserver.RefDescription, v4 time.Duration, v5 time.Duration, v6 int, v7 time.Time) (bool, error) {
+	r0, r1 := m.CalculateVisibleUploadsIncrementalFunc.nextHook()(v0, v1, v2, v3, v4, v5, v6, v7)
+	m.CalculateVisibleUploadsIncrementalFunc.appendCall(DBStoreCalculateVisibleUploadsIncrementalFuncCall{v0, v1, v2, v3, v4, v5, v6, v7, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// CalculateVisibleUploadsIncremental method of the parent MockDBStore
+// instance is invoked and the hook queue is empty.
+func (f *DBStoreCalculateVisibleUploadsIncrementalFunc) SetDefaultHook(hook func(context.Context, int, *gitserver.CommitGraph, map[string][]gitserver.RefDescription, time.Duration, time.Duration, int, time.Time) (bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// CalculateVisibleUploadsIncremental method of the parent MockDBStore
+// instance invokes the hook at the front of the queue and discards it.
+// After the queue is empty, the default hook function is invoked for any
+// future action.
+func (f *DBStoreCalculateVisibleUploadsIncrementalFunc) PushHook(hook func(context.Context, int, *gitserver.CommitGraph, map[string][]gitserver.RefDescription, time.Duration, time.Duration, int, time.Time) (bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DBStoreCalculateVisibleUploadsIncrementalFunc) SetDefaultReturn(r0 bool, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, *gitserver.CommitGraph, map[string][]gitserver.RefDescription, time.Duration, time.Duration, int, time.Time) (bool, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DBStoreCalculateVisibleUploadsIncrementalFunc) PushReturn(r0 bool, r1 error) {
+	f.PushHook(func(context.Context, int, *gitserver.CommitGraph, map[string][]gitserver.RefDescription, time.Duration, time.Duration, int, time.Time) (bool, error) {
+		return r0, r1
+	})
+}
+
+func (f *DBStoreCalculateVisibleUploadsIncrementalFunc) nextHook() func(context.Context, int, *gitserver.CommitGraph, map[string][]gitserver.RefDescription, time.Duration, time.Duration, int, time.Time) (bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBStoreCalculateVisibleUploadsIncrementalFunc) appendCall(r0 DBStoreCalculateVisibleUploadsIncrementalFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// DBStoreCalculateVisibleUploadsIncrementalFuncCall objects describing the
+// invocations of this function.
+func (f *DBStoreCalculateVisibleUploadsIncrementalFunc) History() []DBStoreCalculateVisibleUploadsIncrementalFuncCall {
+	f.mutex.Lock()
+	history := make([]DBStoreCalculateVisibleUploadsIncrementalFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBStoreCalculateVisibleUploadsIncrementalFuncCall is an object that
+// describes an invocation of method CalculateVisibleUploadsIncremental on
+// an instance of MockDBStore.
+type DBStoreCalculateVisibleUploadsIncrementalFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 *gitserver.CommitGraph
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 map[string][]gitserver.RefDescription
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 time.Duration
+	// Arg5 is the value of the 6th argument passed to this method
+	// invocation.
+	Arg5 time.Duration
+	// Arg6 is the value of the 7th argument passed to this method
+	// invocation.
+	Arg6 int
+	// Arg7 is the value of the 8th argument passed to this method
+	// invocation.
+	Arg7 time.Time
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 bool
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBStoreCalculateVisibleUploadsIncrementalFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4, c.Arg5, c.Arg6, c.Arg7}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBStoreCalculateVisibleUploadsIncrementalFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// DBStoreCommitGraphWatermarkFunc describes the behavior when the
+// CommitGraphWatermark method of the parent MockDBStore instance is
+// invoked.
+type DBStoreCommitGraphWatermarkFunc struct {
+	defaultHook func(context.Context, int) ([]string, bool, error)
+	hooks       []func(context.Context, int) ([]string, bool, error)
+	history     []DBStoreCommitGraphWatermarkFuncCall
+	mutex       sync.Mutex
+}
+
+// CommitGraphWatermark delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockDBStore) CommitGraphWatermark(v0 context.Context, v1 int) ([]string, bool, error) {
+	r0, r1, r2 := m.CommitGraphWatermarkFunc.nextHook()(v0, v1)
+	m.CommitGraphWatermarkFunc.appendCall(DBStoreCommitGraphWatermarkFuncCall{v0, v1, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the CommitGraphWatermark
+// method of the parent MockDBStore instance is invoked and the hook queue
+// is empty.
+func (f *DBStoreCommitGraphWatermarkFunc) SetDefaultHook(hook func(context.Context, int) ([]string, bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// CommitGraphWatermark method of the parent MockDBStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *DBStoreCommitGraphWatermarkFunc) PushHook(hook func(context.Context, int) ([]string, bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DBStoreCommitGraphWatermarkFunc) SetDefaultReturn(r0 []string, r1 bool, r2 error) {
+	f.SetDefaultHook(func(context.Context, int) ([]string, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DBStoreCommitGraphWatermarkFunc) PushReturn(r0 []string, r1 bool, r2 error) {
+	f.PushHook(func(context.Context, int) ([]string, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *DBStoreCommitGraphWatermarkFunc) nextHook() func(context.Context, int) ([]string, bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBStoreCommitGraphWatermarkFunc) appendCall(r0 DBStoreCommitGraphWatermarkFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DBStoreCommitGraphWatermarkFuncCall objects
+// describing the invocations of this function.
+func (f *DBStoreCommitGraphWatermarkFunc) History() []DBStoreCommitGraphWatermarkFuncCall {
+	f.mutex.Lock()
+	history := make([]DBStoreCommitGraphWatermarkFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBStoreCommitGraphWatermarkFuncCall is an object that describes an
+// invocation of method CommitGraphWatermark on an instance of MockDBStore.
+type DBStoreCommitGraphWatermarkFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []string
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 bool
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBStoreCommitGraphWatermarkFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBStoreCommitGraphWatermarkFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
 // DBStoreDirtyRepositoriesFunc describes the behavior when the
 // DirtyRepositories method of the parent MockDBStore instance is invoked.
 type DBStoreDirtyRepositoriesFunc struct {
@@ -417,6 +684,9 @@ type MockGitserverClient struct {
 	// CommitGraphFunc is an instance of a mock function object controlling
 	// the behavior of the method CommitGraph.
 	CommitGraphFunc *GitserverClientCommitGraphFunc
+	// CommitGraphSinceFunc is an instance of a mock function object
+	// controlling the behavior of the method CommitGraphSince.
+	CommitGraphSinceFunc *GitserverClientCommitGraphSinceFunc
 	// RefDescriptionsFunc is an instance of a mock function object
 	// controlling the behavior of the method RefDescriptions.
 	RefDescriptionsFunc *GitserverClientRefDescriptionsFunc
@@ -432,6 +702,11 @@ func NewMockGitserverClient() *MockGitserverClient {
 				return nil, nil
 			},
 		},
+		CommitGraphSinceFunc: &GitserverClientCommitGraphSinceFunc{
+			defaultHook: func(context.Context, int, []string, []string) (*gitserver.CommitGraph, error) {
+				return nil, nil
+			},
+		},
 		RefDescriptionsFunc: &GitserverClientRefDescriptionsFunc{
 			defaultHook: func(context.Context, int) (map[string][]gitserver.RefDescription, error) {
 				return nil, nil
@@ -448,6 +723,9 @@ func NewMockGitserverClientFrom(i GitserverClient) *MockGitserverClient {
 		CommitGraphFunc: &GitserverClientCommitGraphFunc{
 			defaultHook: i.CommitGraph,
 		},
+		CommitGraphSinceFunc: &GitserverClientCommitGraphSinceFunc{
+			defaultHook: i.CommitGraphSince,
+		},
 		RefDescriptionsFunc: &GitserverClientRefDescriptionsFunc{
 			defaultHook: i.RefDescriptions,
 		},
@@ -566,6 +844,124 @@ func (c GitserverClientCommitGraphFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// GitserverClientCommitGraphSinceFunc describes the behavior when the
+// CommitGraphSince method of the parent MockGitserverClient instance is
+// invoked.
+type GitserverClientCommitGraphSinceFunc struct {
+	defaultHook func(context.Context, int, []string, []string) (*gitserver.CommitGraph, error)
+	hooks       []func(context.Context, int, []string, []string) (*gitserver.CommitGraph, error)
+	history     []GitserverClientCommitGraphSinceFuncCall
+	mutex       sync.Mutex
+}
+
+// CommitGraphSince delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockGitserverClient) CommitGraphSince(v0 context.Context, v1 int, v2 []string, v3 []string) (*gitserver.CommitGraph, error) {
+	r0, r1 := m.CommitGraphSinceFunc.nextHook()(v0, v1, v2, v3)
+	m.CommitGraphSinceFunc.appendCall(GitserverClientCommitGraphSinceFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the CommitGraphSince
+// method of the parent MockGitserverClient instance is invoked and the hook
+// queue is empty.
+func (f *GitserverClientCommitGraphSinceFunc) SetDefaultHook(hook func(context.Context, int, []string, []string) (*gitserver.CommitGraph, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// CommitGraphSince method of the parent MockGitserverClient instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *GitserverClientCommitGraphSinceFunc) PushHook(hook func(context.Context, int, []string, []string) (*gitserver.CommitGraph, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *GitserverClientCommitGraphSinceFunc) SetDefaultReturn(r0 *gitserver.CommitGraph, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, []string, []string) (*gitserver.CommitGraph, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *GitserverClientCommitGraphSinceFunc) PushReturn(r0 *gitserver.CommitGraph, r1 error) {
+	f.PushHook(func(context.Context, int, []string, []string) (*gitserver.CommitGraph, error) {
+		return r0, r1
+	})
+}
+
+func (f *GitserverClientCommitGraphSinceFunc) nextHook() func(context.Context, int, []string, []string) (*gitserver.CommitGraph, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GitserverClientCommitGraphSinceFunc) appendCall(r0 GitserverClientCommitGraphSinceFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of GitserverClientCommitGraphSinceFuncCall
+// objects describing the invocations of this function.
+func (f *GitserverClientCommitGraphSinceFunc) History() []GitserverClientCommitGraphSinceFuncCall {
+	f.mutex.Lock()
+	history := make([]GitserverClientCommitGraphSinceFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GitserverClientCommitGraphSinceFuncCall is an object that describes an
+// invocation of method CommitGraphSince on an instance of
+// MockGitserverClient.
+type GitserverClientCommitGraphSinceFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 []string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 []string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 *gitserver.CommitGraph
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GitserverClientCommitGraphSinceFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GitserverClientCommitGraphSinceFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // GitserverClientRefDescriptionsFunc describes the behavior when the
 // RefDescriptions method of the parent MockGitserverClient instance is
 // invoked.
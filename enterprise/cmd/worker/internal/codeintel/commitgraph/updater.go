@@ -98,6 +98,9 @@ func (u *Updater) tryUpdate(ctx context.Context, repositoryID, dirtyToken int) (
 // upload objects for the given repository from Postgres, and correlates them into a visibility
 // graph. This graph is then upserted back into Postgres for use by find closest dumps queries.
 //
+// When possible, only the commits added since the last update are pulled from gitserver and
+// correlated with the uploads. Otherwise, the whole commit graph is recalculated.
+//
 // The user should supply a dirty token that is associated with the given repository so that
 // the repository can be unmarked as long as the repository is not marked as dirty again before
 // the update completes.
@@ -110,18 +113,25 @@ func (u *Updater) update(ctx context.Context, repositoryID, dirtyToken int) (err
 	})
 	defer endObservation(1, observation.Args{})
 
-	// Construct a view of the git graph that we will later decorate with upload information.
-	commitGraph, err := u.getCommitGraph(ctx, repositoryID)
+	// Pull the refs before the commit graph so that the commit graph contains the tip of every ref,
+	// which are recorded as the watermark of the next update.
+	refDescriptions, err := u.gitserverClient.RefDescriptions(ctx, repositoryID)
 	if err != nil {
+		return errors.Wrap(err, "gitserver.RefDescriptions")
+	}
+	traceLog(log.Int("numRefDescriptions", len(refDescriptions)))
+
+	if ok, err := u.updateIncrementally(ctx, repositoryID, refDescriptions, dirtyToken); err != nil || ok {
+		traceLog(log.Bool("incremental", ok))
 		return err
 	}
-	traceLog(log.Int("numCommitGraphKeys", len(commitGraph.Order())))
 
-	refDescriptions, err := u.gitserverClient.RefDescriptions(ctx, repositoryID)
+	// Construct a view of the git graph that we will later decorate with upload information.
+	commitGraph, err := u.getCommitGraph(ctx, repositoryID)
 	if err != nil {
-		return errors.Wrap(err, "gitserver.RefDescriptions")
+		return err
 	}
-	traceLog(log.Int("numRefDescriptions", len(refDescriptions)))
+	traceLog(log.Int("numCommitGraphKeys", len(commitGraph.Order())))
 
 	// Decorate the commit graph with the set of processed uploads are visible from each commit,
 	// then bulk update the denormalized view in Postgres. We call this with an empty graph as well
@@ -133,6 +143,37 @@ func (u *Updater) update(ctx context.Context, repositoryID, dirtyToken int) (err
 	return nil
 }
 
+// updateIncrementally pulls the commits added since the last update from gitserver and decorates
+// them with the visible uploads. This returns false if there is no previous update to build on or
+// if the uploads of the repository changed in a way that requires recalculating the whole commit
+// graph.
+func (u *Updater) updateIncrementally(ctx context.Context, repositoryID int, refDescriptions map[string][]gitserver.RefDescription, dirtyToken int) (bool, error) {
+	watermark, ok, err := u.dbStore.CommitGraphWatermark(ctx, repositoryID)
+	if err != nil {
+		return false, errors.Wrap(err, "dbstore.CommitGraphWatermark")
+	}
+	if !ok {
+		return false, nil
+	}
+
+	tips := make([]string, 0, len(refDescriptions))
+	for commit := range refDescriptions {
+		tips = append(tips, commit)
+	}
+
+	commitGraph, err := u.gitserverClient.CommitGraphSince(ctx, repositoryID, tips, watermark)
+	if err != nil {
+		return false, errors.Wrap(err, "gitserver.CommitGraphSince")
+	}
+
+	ok, err = u.dbStore.CalculateVisibleUploadsIncremental(ctx, repositoryID, commitGraph, refDescriptions, u.maxAgeForNonStaleBranches, u.maxAgeForNonStaleTags, dirtyToken, time.Now())
+	if err != nil {
+		return false, errors.Wrap(err, "dbstore.CalculateVisibleUploadsIncremental")
+	}
+
+	return ok, nil
+}
+
 // getCommitGraph builds a partial commit graph that includes the most recent commits on each branch
 // extending back as as the date of the oldest commit for which we have a processed upload for this
 // repository.
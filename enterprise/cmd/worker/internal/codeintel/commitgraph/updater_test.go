@@ -5,6 +5,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
+
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 )
@@ -62,6 +64,99 @@ func TestUpdater(t *testing.T) {
 	}
 }
 
+func TestUpdaterIncremental(t *testing.T) {
+	graph := gitserver.ParseCommitGraph([]string{
+		"c b",
+	})
+
+	mockDBStore := NewMockDBStore()
+	mockDBStore.DirtyRepositoriesFunc.SetDefaultReturn(map[int]int{42: 15}, nil)
+	mockDBStore.CommitGraphWatermarkFunc.SetDefaultReturn([]string{"b"}, true, nil)
+	mockDBStore.CalculateVisibleUploadsIncrementalFunc.SetDefaultReturn(true, nil)
+
+	mockLocker := NewMockLocker()
+	mockLocker.LockFunc.SetDefaultReturn(true, func(err error) error { return err }, nil)
+
+	mockGitserverClient := NewMockGitserverClient()
+	mockGitserverClient.CommitGraphSinceFunc.SetDefaultReturn(graph, nil)
+	mockGitserverClient.RefDescriptionsFunc.SetDefaultReturn(map[string][]gitserver.RefDescription{
+		"c": {{IsDefaultBranch: true}},
+	}, nil)
+
+	updater := &Updater{
+		dbStore:         mockDBStore,
+		locker:          mockLocker,
+		gitserverClient: mockGitserverClient,
+		operations:      newOperations(mockDBStore, &observation.TestContext),
+	}
+
+	if err := updater.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error updating commit graph: %s", err)
+	}
+
+	// Should fetch the commits since the watermark
+	if len(mockGitserverClient.CommitGraphSinceFunc.History()) != 1 {
+		t.Fatalf("unexpected commit graph since call count. want=%d have=%d", 1, len(mockGitserverClient.CommitGraphSinceFunc.History()))
+	} else {
+		call := mockGitserverClient.CommitGraphSinceFunc.History()[0]
+		if diff := cmp.Diff([]string{"c"}, call.Arg2); diff != "" {
+			t.Errorf("unexpected tips (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff([]string{"b"}, call.Arg3); diff != "" {
+			t.Errorf("unexpected watermark (-want +got):\n%s", diff)
+		}
+	}
+	// Should calculate visible uploads incrementally with fetched graph
+	if len(mockDBStore.CalculateVisibleUploadsIncrementalFunc.History()) != 1 {
+		t.Fatalf("unexpected calculate visible uploads incremental call count. want=%d have=%d", 1, len(mockDBStore.CalculateVisibleUploadsIncrementalFunc.History()))
+	}
+	// Should not fetch the whole commit graph
+	if len(mockGitserverClient.CommitGraphFunc.History()) != 0 {
+		t.Fatalf("unexpected commit graph call count. want=%d have=%d", 0, len(mockGitserverClient.CommitGraphFunc.History()))
+	}
+	if len(mockDBStore.CalculateVisibleUploadsFunc.History()) != 0 {
+		t.Fatalf("unexpected calculate visible uploads call count. want=%d have=%d", 0, len(mockDBStore.CalculateVisibleUploadsFunc.History()))
+	}
+}
+
+func TestUpdaterIncrementalFallback(t *testing.T) {
+	commitTime := time.Unix(1587396557, 0).UTC()
+	mockDBStore := NewMockDBStore()
+	mockDBStore.DirtyRepositoriesFunc.SetDefaultReturn(map[int]int{42: 15}, nil)
+	mockDBStore.GetOldestCommitDateFunc.SetDefaultReturn(commitTime, true, nil)
+	mockDBStore.CommitGraphWatermarkFunc.SetDefaultReturn([]string{"b"}, true, nil)
+	mockDBStore.CalculateVisibleUploadsIncrementalFunc.SetDefaultReturn(false, nil)
+
+	mockLocker := NewMockLocker()
+	mockLocker.LockFunc.SetDefaultReturn(true, func(err error) error { return err }, nil)
+
+	mockGitserverClient := NewMockGitserverClient()
+	mockGitserverClient.CommitGraphSinceFunc.SetDefaultReturn(gitserver.ParseCommitGraph([]string{"c b"}), nil)
+	mockGitserverClient.CommitGraphFunc.SetDefaultReturn(gitserver.ParseCommitGraph([]string{"c b", "b a", "a"}), nil)
+	mockGitserverClient.RefDescriptionsFunc.SetDefaultReturn(map[string][]gitserver.RefDescription{
+		"c": {{IsDefaultBranch: true}},
+	}, nil)
+
+	updater := &Updater{
+		dbStore:         mockDBStore,
+		locker:          mockLocker,
+		gitserverClient: mockGitserverClient,
+		operations:      newOperations(mockDBStore, &observation.TestContext),
+	}
+
+	if err := updater.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error updating commit graph: %s", err)
+	}
+
+	// Should fall back to the whole commit graph
+	if len(mockGitserverClient.CommitGraphFunc.History()) != 1 {
+		t.Fatalf("unexpected commit graph call count. want=%d have=%d", 1, len(mockGitserverClient.CommitGraphFunc.History()))
+	}
+	if len(mockDBStore.CalculateVisibleUploadsFunc.History()) != 1 {
+		t.Fatalf("unexpected calculate visible uploads call count. want=%d have=%d", 1, len(mockDBStore.CalculateVisibleUploadsFunc.History()))
+	}
+}
+
 func TestUpdaterNoUploads(t *testing.T) {
 	mockDBStore := NewMockDBStore()
 	mockDBStore.DirtyRepositoriesFunc.SetDefaultReturn(map[int]int{42: 15}, nil)
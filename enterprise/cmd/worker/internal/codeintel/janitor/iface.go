@@ -23,13 +23,18 @@ type DBStore interface {
 	GetConfigurationPolicies(ctx context.Context, opts dbstore.GetConfigurationPoliciesOptions) ([]dbstore.ConfigurationPolicy, error)
 	SelectRepositoriesForRetentionScan(ctx context.Context, processDelay time.Duration, limit int) ([]int, error)
 	CommitsVisibleToUpload(ctx context.Context, uploadID, limit int, token *string) ([]string, *string, error)
+	RecentUploadRanks(ctx context.Context, repositoryID, limit int) (map[int]int, error)
 	UpdateUploadRetention(ctx context.Context, protectedIDs, expiredIDs []int) error
 	SoftDeleteExpiredUploads(ctx context.Context) (int, error)
 	DirtyRepositories(ctx context.Context) (map[int]int, error)
 	DeleteIndexesWithoutRepository(ctx context.Context, now time.Time) (map[int]int, error)
 	DeleteUploadsStuckUploading(ctx context.Context, uploadedBefore time.Time) (int, error)
+	ResetStalledUploads(ctx context.Context, lastHeartbeatBefore time.Time) ([]dbstore.StalledUpload, error)
 	StaleSourcedCommits(ctx context.Context, threshold time.Duration, limit int, now time.Time) ([]dbstore.SourcedCommits, error)
 	RefreshCommitResolvability(ctx context.Context, repositoryID int, commit string, delete bool, now time.Time) (int, int, error)
+	CompletedUploadIDs(ctx context.Context, afterID, limit int) ([]int, error)
+	UnknownUploadIDs(ctx context.Context, ids []int) ([]int, error)
+	MarkUploadsMissingData(ctx context.Context, ids []int, now time.Time) (int, int, error)
 }
 
 type DBStoreShim struct {
@@ -52,6 +57,8 @@ type LSIFStore interface {
 	Clear(ctx context.Context, bundleIDs ...int) error
 	DeleteOldPublicSearchRecords(ctx context.Context, minimumTimeSinceLastCheck time.Duration, limit int) (int, error)
 	DeleteOldPrivateSearchRecords(ctx context.Context, minimumTimeSinceLastCheck time.Duration, limit int) (int, error)
+	BundleIDs(ctx context.Context, afterID, limit int) ([]int, error)
+	MissingBundleIDs(ctx context.Context, bundleIDs []int) ([]int, error)
 }
 
 type LSIFStoreShim struct {
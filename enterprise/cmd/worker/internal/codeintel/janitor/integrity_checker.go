@@ -0,0 +1,130 @@
+package janitor
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+type integrityChecker struct {
+	dbStore   DBStore
+	lsifStore LSIFStore
+	batchSize int
+	repair    bool
+	metrics   *metrics
+
+	// uploadCursor and bundleCursor are the largest upload and bundle identifiers
+	// checked so far. They wrap around once all records have been checked.
+	uploadCursor int
+	bundleCursor int
+}
+
+var _ goroutine.Handler = &integrityChecker{}
+var _ goroutine.ErrorHandler = &integrityChecker{}
+
+// NewIntegrityChecker returns a background routine that periodically checks the
+// consistency of upload records and the bundle data in the codeintel database,
+// which lives in a distinct database and cannot be constrained by a foreign key.
+// Each run checks a batch of completed uploads for missing bundle data, and a
+// batch of bundles for a missing upload record.
+//
+// Inconsistencies are logged and counted. If repair is true, completed uploads
+// missing their data are marked as errored and their index jobs are requeued,
+// and orphaned bundles are cleared from the codeintel database.
+func NewIntegrityChecker(dbStore DBStore, lsifStore LSIFStore, batchSize int, repair bool, interval time.Duration, metrics *metrics) goroutine.BackgroundRoutine {
+	return goroutine.NewPeriodicGoroutine(context.Background(), interval, &integrityChecker{
+		dbStore:   dbStore,
+		lsifStore: lsifStore,
+		batchSize: batchSize,
+		repair:    repair,
+		metrics:   metrics,
+	})
+}
+
+func (c *integrityChecker) Handle(ctx context.Context) error {
+	if err := c.checkUploads(ctx); err != nil {
+		return err
+	}
+
+	return c.checkBundles(ctx)
+}
+
+func (c *integrityChecker) HandleError(err error) {
+	c.metrics.numErrors.Inc()
+	log15.Error("Failed to check codeintel data integrity", "error", err)
+}
+
+// checkUploads looks for completed uploads without bundle data in the codeintel database.
+func (c *integrityChecker) checkUploads(ctx context.Context) error {
+	ids, err := c.dbStore.CompletedUploadIDs(ctx, c.uploadCursor, c.batchSize)
+	if err != nil {
+		return errors.Wrap(err, "dbstore.CompletedUploadIDs")
+	}
+	if len(ids) == 0 {
+		c.uploadCursor = 0
+		return nil
+	}
+	c.uploadCursor = ids[len(ids)-1]
+
+	missingIDs, err := c.lsifStore.MissingBundleIDs(ctx, ids)
+	if err != nil {
+		return errors.Wrap(err, "lsifstore.MissingBundleIDs")
+	}
+	if len(missingIDs) == 0 {
+		return nil
+	}
+
+	log15.Warn("Found completed uploads without data in the codeintel database", "ids", missingIDs)
+	c.metrics.numUploadsMissingData.Add(float64(len(missingIDs)))
+
+	if !c.repair {
+		return nil
+	}
+
+	numUploads, numRequeued, err := c.dbStore.MarkUploadsMissingData(ctx, missingIDs, time.Now().UTC())
+	if err != nil {
+		return errors.Wrap(err, "dbstore.MarkUploadsMissingData")
+	}
+	log15.Info("Marked uploads without data as errored", "uploads", numUploads, "requeuedIndexes", numRequeued)
+
+	return nil
+}
+
+// checkBundles looks for bundles in the codeintel database without an upload record.
+func (c *integrityChecker) checkBundles(ctx context.Context) error {
+	ids, err := c.lsifStore.BundleIDs(ctx, c.bundleCursor, c.batchSize)
+	if err != nil {
+		return errors.Wrap(err, "lsifstore.BundleIDs")
+	}
+	if len(ids) == 0 {
+		c.bundleCursor = 0
+		return nil
+	}
+	c.bundleCursor = ids[len(ids)-1]
+
+	orphanedIDs, err := c.dbStore.UnknownUploadIDs(ctx, ids)
+	if err != nil {
+		return errors.Wrap(err, "dbstore.UnknownUploadIDs")
+	}
+	if len(orphanedIDs) == 0 {
+		return nil
+	}
+
+	log15.Warn("Found bundles without an upload record in the codeintel database", "ids", orphanedIDs)
+	c.metrics.numOrphanedBundles.Add(float64(len(orphanedIDs)))
+
+	if !c.repair {
+		return nil
+	}
+
+	if err := c.lsifStore.Clear(ctx, orphanedIDs...); err != nil {
+		return errors.Wrap(err, "lsifstore.Clear")
+	}
+	c.metrics.numUploadsPurged.Add(float64(len(orphanedIDs)))
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package janitor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+func TestIntegrityChecker(t *testing.T) {
+	dbStore := NewMockDBStore()
+	dbStore.CompletedUploadIDsFunc.PushReturn([]int{1, 2, 3, 4}, nil)
+	dbStore.UnknownUploadIDsFunc.SetDefaultReturn([]int{7}, nil)
+	lsifStore := NewMockLSIFStore()
+	lsifStore.MissingBundleIDsFunc.SetDefaultReturn([]int{2, 4}, nil)
+	lsifStore.BundleIDsFunc.PushReturn([]int{1, 3, 7}, nil)
+
+	checker := &integrityChecker{
+		dbStore:   dbStore,
+		lsifStore: lsifStore,
+		batchSize: 100,
+		metrics:   newMetrics(&observation.TestContext),
+	}
+
+	if err := checker.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error checking integrity: %s", err)
+	}
+
+	if callCount := len(lsifStore.MissingBundleIDsFunc.History()); callCount != 1 {
+		t.Fatalf("unexpected number of MissingBundleIDs calls. want=%d have=%d", 1, callCount)
+	} else if diff := cmp.Diff([]int{1, 2, 3, 4}, lsifStore.MissingBundleIDsFunc.History()[0].Arg1); diff != "" {
+		t.Errorf("unexpected upload identifiers (-want +got):\n%s", diff)
+	}
+	if callCount := len(dbStore.UnknownUploadIDsFunc.History()); callCount != 1 {
+		t.Fatalf("unexpected number of UnknownUploadIDs calls. want=%d have=%d", 1, callCount)
+	} else if diff := cmp.Diff([]int{1, 3, 7}, dbStore.UnknownUploadIDsFunc.History()[0].Arg1); diff != "" {
+		t.Errorf("unexpected bundle identifiers (-want +got):\n%s", diff)
+	}
+
+	// Inconsistencies are only reported without repair
+	if callCount := len(dbStore.MarkUploadsMissingDataFunc.History()); callCount != 0 {
+		t.Errorf("unexpected number of MarkUploadsMissingData calls. want=%d have=%d", 0, callCount)
+	}
+	if callCount := len(lsifStore.ClearFunc.History()); callCount != 0 {
+		t.Errorf("unexpected number of Clear calls. want=%d have=%d", 0, callCount)
+	}
+
+	if checker.uploadCursor != 4 || checker.bundleCursor != 7 {
+		t.Errorf("unexpected cursors. want=(%d, %d) have=(%d, %d)", 4, 7, checker.uploadCursor, checker.bundleCursor)
+	}
+
+	// The next batches are empty, so the cursors wrap around
+	if err := checker.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error checking integrity: %s", err)
+	}
+	if history := dbStore.CompletedUploadIDsFunc.History(); len(history) != 2 || history[1].Arg1 != 4 {
+		t.Errorf("expected second batch of uploads to start after upload 4")
+	}
+	if checker.uploadCursor != 0 || checker.bundleCursor != 0 {
+		t.Errorf("unexpected cursors. want=(%d, %d) have=(%d, %d)", 0, 0, checker.uploadCursor, checker.bundleCursor)
+	}
+}
+
+func TestIntegrityCheckerRepair(t *testing.T) {
+	dbStore := NewMockDBStore()
+	dbStore.CompletedUploadIDsFunc.PushReturn([]int{1, 2, 3, 4}, nil)
+	dbStore.UnknownUploadIDsFunc.SetDefaultReturn([]int{7}, nil)
+	lsifStore := NewMockLSIFStore()
+	lsifStore.MissingBundleIDsFunc.SetDefaultReturn([]int{2, 4}, nil)
+	lsifStore.BundleIDsFunc.PushReturn([]int{1, 3, 7}, nil)
+
+	checker := &integrityChecker{
+		dbStore:   dbStore,
+		lsifStore: lsifStore,
+		batchSize: 100,
+		repair:    true,
+		metrics:   newMetrics(&observation.TestContext),
+	}
+
+	if err := checker.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error checking integrity: %s", err)
+	}
+
+	if callCount := len(dbStore.MarkUploadsMissingDataFunc.History()); callCount != 1 {
+		t.Fatalf("unexpected number of MarkUploadsMissingData calls. want=%d have=%d", 1, callCount)
+	} else if diff := cmp.Diff([]int{2, 4}, dbStore.MarkUploadsMissingDataFunc.History()[0].Arg1); diff != "" {
+		t.Errorf("unexpected upload identifiers (-want +got):\n%s", diff)
+	}
+	if callCount := len(lsifStore.ClearFunc.History()); callCount != 1 {
+		t.Fatalf("unexpected number of Clear calls. want=%d have=%d", 1, callCount)
+	} else if diff := cmp.Diff([]int{7}, lsifStore.ClearFunc.History()[0].Arg1); diff != "" {
+		t.Errorf("unexpected bundle identifiers (-want +got):\n%s", diff)
+	}
+}
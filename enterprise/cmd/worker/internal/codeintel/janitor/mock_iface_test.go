@@ -20,6 +20,9 @@ type MockDBStore struct {
 	// CommitsVisibleToUploadFunc is an instance of a mock function object
 	// controlling the behavior of the method CommitsVisibleToUpload.
 	CommitsVisibleToUploadFunc *DBStoreCommitsVisibleToUploadFunc
+	// CompletedUploadIDsFunc is an instance of a mock function object
+	// controlling the behavior of the method CompletedUploadIDs.
+	CompletedUploadIDsFunc *DBStoreCompletedUploadIDsFunc
 	// DeleteIndexesWithoutRepositoryFunc is an instance of a mock function
 	// object controlling the behavior of the method
 	// DeleteIndexesWithoutRepository.
@@ -50,10 +53,19 @@ type MockDBStore struct {
 	// HardDeleteUploadByIDFunc is an instance of a mock function object
 	// controlling the behavior of the method HardDeleteUploadByID.
 	HardDeleteUploadByIDFunc *DBStoreHardDeleteUploadByIDFunc
+	// MarkUploadsMissingDataFunc is an instance of a mock function object
+	// controlling the behavior of the method MarkUploadsMissingData.
+	MarkUploadsMissingDataFunc *DBStoreMarkUploadsMissingDataFunc
+	// RecentUploadRanksFunc is an instance of a mock function object
+	// controlling the behavior of the method RecentUploadRanks.
+	RecentUploadRanksFunc *DBStoreRecentUploadRanksFunc
 	// RefreshCommitResolvabilityFunc is an instance of a mock function
 	// object controlling the behavior of the method
 	// RefreshCommitResolvability.
 	RefreshCommitResolvabilityFunc *DBStoreRefreshCommitResolvabilityFunc
+	// ResetStalledUploadsFunc is an instance of a mock function object
+	// controlling the behavior of the method ResetStalledUploads.
+	ResetStalledUploadsFunc *DBStoreResetStalledUploadsFunc
 	// SelectRepositoriesForRetentionScanFunc is an instance of a mock
 	// function object controlling the behavior of the method
 	// SelectRepositoriesForRetentionScan.
@@ -67,6 +79,9 @@ type MockDBStore struct {
 	// TransactFunc is an instance of a mock function object controlling the
 	// behavior of the method Transact.
 	TransactFunc *DBStoreTransactFunc
+	// UnknownUploadIDsFunc is an instance of a mock function object
+	// controlling the behavior of the method UnknownUploadIDs.
+	UnknownUploadIDsFunc *DBStoreUnknownUploadIDsFunc
 	// UpdateUploadRetentionFunc is an instance of a mock function object
 	// controlling the behavior of the method UpdateUploadRetention.
 	UpdateUploadRetentionFunc *DBStoreUpdateUploadRetentionFunc
@@ -81,6 +96,11 @@ func NewMockDBStore() *MockDBStore {
 				return nil, nil, nil
 			},
 		},
+		CompletedUploadIDsFunc: &DBStoreCompletedUploadIDsFunc{
+			defaultHook: func(context.Context, int, int) ([]int, error) {
+				return nil, nil
+			},
+		},
 		DeleteIndexesWithoutRepositoryFunc: &DBStoreDeleteIndexesWithoutRepositoryFunc{
 			defaultHook: func(context.Context, time.Time) (map[int]int, error) {
 				return nil, nil
@@ -126,11 +146,26 @@ func NewMockDBStore() *MockDBStore {
 				return nil
 			},
 		},
+		MarkUploadsMissingDataFunc: &DBStoreMarkUploadsMissingDataFunc{
+			defaultHook: func(context.Context, []int, time.Time) (int, int, error) {
+				return 0, 0, nil
+			},
+		},
+		RecentUploadRanksFunc: &DBStoreRecentUploadRanksFunc{
+			defaultHook: func(context.Context, int, int) (map[int]int, error) {
+				return nil, nil
+			},
+		},
 		RefreshCommitResolvabilityFunc: &DBStoreRefreshCommitResolvabilityFunc{
 			defaultHook: func(context.Context, int, string, bool, time.Time) (int, int, error) {
 				return 0, 0, nil
 			},
 		},
+		ResetStalledUploadsFunc: &DBStoreResetStalledUploadsFunc{
+			defaultHook: func(context.Context, time.Time) ([]dbstore.StalledUpload, error) {
+				return nil, nil
+			},
+		},
 		SelectRepositoriesForRetentionScanFunc: &DBStoreSelectRepositoriesForRetentionScanFunc{
 			defaultHook: func(context.Context, time.Duration, int) ([]int, error) {
 				return nil, nil
@@ -151,6 +186,11 @@ func NewMockDBStore() *MockDBStore {
 				return nil, nil
 			},
 		},
+		UnknownUploadIDsFunc: &DBStoreUnknownUploadIDsFunc{
+			defaultHook: func(context.Context, []int) ([]int, error) {
+				return nil, nil
+			},
+		},
 		UpdateUploadRetentionFunc: &DBStoreUpdateUploadRetentionFunc{
 			defaultHook: func(context.Context, []int, []int) error {
 				return nil
@@ -166,6 +206,9 @@ func NewMockDBStoreFrom(i DBStore) *MockDBStore {
 		CommitsVisibleToUploadFunc: &DBStoreCommitsVisibleToUploadFunc{
 			defaultHook: i.CommitsVisibleToUpload,
 		},
+		CompletedUploadIDsFunc: &DBStoreCompletedUploadIDsFunc{
+			defaultHook: i.CompletedUploadIDs,
+		},
 		DeleteIndexesWithoutRepositoryFunc: &DBStoreDeleteIndexesWithoutRepositoryFunc{
 			defaultHook: i.DeleteIndexesWithoutRepository,
 		},
@@ -193,9 +236,18 @@ func NewMockDBStoreFrom(i DBStore) *MockDBStore {
 		HardDeleteUploadByIDFunc: &DBStoreHardDeleteUploadByIDFunc{
 			defaultHook: i.HardDeleteUploadByID,
 		},
+		MarkUploadsMissingDataFunc: &DBStoreMarkUploadsMissingDataFunc{
+			defaultHook: i.MarkUploadsMissingData,
+		},
+		RecentUploadRanksFunc: &DBStoreRecentUploadRanksFunc{
+			defaultHook: i.RecentUploadRanks,
+		},
 		RefreshCommitResolvabilityFunc: &DBStoreRefreshCommitResolvabilityFunc{
 			defaultHook: i.RefreshCommitResolvability,
 		},
+		ResetStalledUploadsFunc: &DBStoreResetStalledUploadsFunc{
+			defaultHook: i.ResetStalledUploads,
+		},
 		SelectRepositoriesForRetentionScanFunc: &DBStoreSelectRepositoriesForRetentionScanFunc{
 			defaultHook: i.SelectRepositoriesForRetentionScan,
 		},
@@ -208,6 +260,9 @@ func NewMockDBStoreFrom(i DBStore) *MockDBStore {
 		TransactFunc: &DBStoreTransactFunc{
 			defaultHook: i.Transact,
 		},
+		UnknownUploadIDsFunc: &DBStoreUnknownUploadIDsFunc{
+			defaultHook: i.UnknownUploadIDs,
+		},
 		UpdateUploadRetentionFunc: &DBStoreUpdateUploadRetentionFunc{
 			defaultHook: i.UpdateUploadRetention,
 		},
@@ -334,6 +389,118 @@ func (c DBStoreCommitsVisibleToUploadFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
+// DBStoreCompletedUploadIDsFunc describes the behavior when the
+// CompletedUploadIDs method of the parent MockDBStore instance is invoked.
+type DBStoreCompletedUploadIDsFunc struct {
+	defaultHook func(context.Context, int, int) ([]int, error)
+	hooks       []func(context.Context, int, int) ([]int, error)
+	history     []DBStoreCompletedUploadIDsFuncCall
+	mutex       sync.Mutex
+}
+
+// CompletedUploadIDs delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockDBStore) CompletedUploadIDs(v0 context.Context, v1 int, v2 int) ([]int, error) {
+	r0, r1 := m.CompletedUploadIDsFunc.nextHook()(v0, v1, v2)
+	m.CompletedUploadIDsFunc.appendCall(DBStoreCompletedUploadIDsFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the CompletedUploadIDs
+// method of the parent MockDBStore instance is invoked and the hook queue
+// is empty.
+func (f *DBStoreCompletedUploadIDsFunc) SetDefaultHook(hook func(context.Context, int, int) ([]int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// CompletedUploadIDs method of the parent MockDBStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *DBStoreCompletedUploadIDsFunc) PushHook(hook func(context.Context, int, int) ([]int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DBStoreCompletedUploadIDsFunc) SetDefaultReturn(r0 []int, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, int) ([]int, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DBStoreCompletedUploadIDsFunc) PushReturn(r0 []int, r1 error) {
+	f.PushHook(func(context.Context, int, int) ([]int, error) {
+		return r0, r1
+	})
+}
+
+func (f *DBStoreCompletedUploadIDsFunc) nextHook() func(context.Context, int, int) ([]int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBStoreCompletedUploadIDsFunc) appendCall(r0 DBStoreCompletedUploadIDsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DBStoreCompletedUploadIDsFuncCall objects
+// describing the invocations of this function.
+func (f *DBStoreCompletedUploadIDsFunc) History() []DBStoreCompletedUploadIDsFuncCall {
+	f.mutex.Lock()
+	history := make([]DBStoreCompletedUploadIDsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBStoreCompletedUploadIDsFuncCall is an object that describes an
+// invocation of method CompletedUploadIDs on an instance of MockDBStore.
+type DBStoreCompletedUploadIDsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBStoreCompletedUploadIDsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBStoreCompletedUploadIDsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // DBStoreDeleteIndexesWithoutRepositoryFunc describes the behavior when the
 // DeleteIndexesWithoutRepository method of the parent MockDBStore instance
 // is invoked.
@@ -1316,37 +1483,36 @@ func (c DBStoreHardDeleteUploadByIDFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
-// DBStoreRefreshCommitResolvabilityFunc describes the behavior when the
-// RefreshCommitResolvability method of the parent MockDBStore instance is
+// DBStoreMarkUploadsMissingDataFunc describes the behavior when the
+// MarkUploadsMissingData method of the parent MockDBStore instance is
 // invoked.
-type DBStoreRefreshCommitResolvabilityFunc struct {
-	defaultHook func(context.Context, int, string, bool, time.Time) (int, int, error)
-	hooks       []func(context.Context, int, string, bool, time.Time) (int, int, error)
-	history     []DBStoreRefreshCommitResolvabilityFuncCall
+type DBStoreMarkUploadsMissingDataFunc struct {
+	defaultHook func(context.Context, []int, time.Time) (int, int, error)
+	hooks       []func(context.Context, []int, time.Time) (int, int, error)
+	history     []DBStoreMarkUploadsMissingDataFuncCall
 	mutex       sync.Mutex
 }
 
-// RefreshCommitResolvability delegates to the next hook function in the
-// queue and stores the parameter and result values of this invocation.
-func (m *MockDBStore) RefreshCommitResolvability(v0 context.Context, v1 int, v2 string, v3 bool, v4 time.Time) (int, int, error) {
-	r0, r1, r2 := m.RefreshCommitResolvabilityFunc.nextHook()(v0, v1, v2, v3, v4)
-	m.RefreshCommitResolvabilityFunc.appendCall(DBStoreRefreshCommitResolvabilityFuncCall{v0, v1, v2, v3, v4, r0, r1, r2})
+// MarkUploadsMissingData delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockDBStore) MarkUploadsMissingData(v0 context.Context, v1 []int, v2 time.Time) (int, int, error) {
+	r0, r1, r2 := m.MarkUploadsMissingDataFunc.nextHook()(v0, v1, v2)
+	m.MarkUploadsMissingDataFunc.appendCall(DBStoreMarkUploadsMissingDataFuncCall{v0, v1, v2, r0, r1, r2})
 	return r0, r1, r2
 }
 
 // SetDefaultHook sets function that is called when the
-// RefreshCommitResolvability method of the parent MockDBStore instance is
+// MarkUploadsMissingData method of the parent MockDBStore instance is
 // invoked and the hook queue is empty.
-func (f *DBStoreRefreshCommitResolvabilityFunc) SetDefaultHook(hook func(context.Context, int, string, bool, time.Time) (int, int, error)) {
+func (f *DBStoreMarkUploadsMissingDataFunc) SetDefaultHook(hook func(context.Context, []int, time.Time) (int, int, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// RefreshCommitResolvability method of the parent MockDBStore instance
-// invokes the hook at the front of the queue and discards it. After the
-// queue is empty, the default hook function is invoked for any future
-// action.
-func (f *DBStoreRefreshCommitResolvabilityFunc) PushHook(hook func(context.Context, int, string, bool, time.Time) (int, int, error)) {
+// MarkUploadsMissingData method of the parent MockDBStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *DBStoreMarkUploadsMissingDataFunc) PushHook(hook func(context.Context, []int, time.Time) (int, int, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -1354,21 +1520,21 @@ func (f *DBStoreRefreshCommitResolvabilityFunc) PushHook(hook func(context.Conte
 
 // SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
 // the given values.
-func (f *DBStoreRefreshCommitResolvabilityFunc) SetDefaultReturn(r0 int, r1 int, r2 error) {
-	f.SetDefaultHook(func(context.Context, int, string, bool, time.Time) (int, int, error) {
+func (f *DBStoreMarkUploadsMissingDataFunc) SetDefaultReturn(r0 int, r1 int, r2 error) {
+	f.SetDefaultHook(func(context.Context, []int, time.Time) (int, int, error) {
 		return r0, r1, r2
 	})
 }
 
 // PushReturn calls PushDefaultHook with a function that returns the given
 // values.
-func (f *DBStoreRefreshCommitResolvabilityFunc) PushReturn(r0 int, r1 int, r2 error) {
-	f.PushHook(func(context.Context, int, string, bool, time.Time) (int, int, error) {
+func (f *DBStoreMarkUploadsMissingDataFunc) PushReturn(r0 int, r1 int, r2 error) {
+	f.PushHook(func(context.Context, []int, time.Time) (int, int, error) {
 		return r0, r1, r2
 	})
 }
 
-func (f *DBStoreRefreshCommitResolvabilityFunc) nextHook() func(context.Context, int, string, bool, time.Time) (int, int, error) {
+func (f *DBStoreMarkUploadsMissingDataFunc) nextHook() func(context.Context, []int, time.Time) (int, int, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -1381,42 +1547,36 @@ func (f *DBStoreRefreshCommitResolvabilityFunc) nextHook() func(context.Context,
 	return hook
 }
 
-func (f *DBStoreRefreshCommitResolvabilityFunc) appendCall(r0 DBStoreRefreshCommitResolvabilityFuncCall) {
+func (f *DBStoreMarkUploadsMissingDataFunc) appendCall(r0 DBStoreMarkUploadsMissingDataFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of DBStoreRefreshCommitResolvabilityFuncCall
+// History returns a sequence of DBStoreMarkUploadsMissingDataFuncCall
 // objects describing the invocations of this function.
-func (f *DBStoreRefreshCommitResolvabilityFunc) History() []DBStoreRefreshCommitResolvabilityFuncCall {
+func (f *DBStoreMarkUploadsMissingDataFunc) History() []DBStoreMarkUploadsMissingDataFuncCall {
 	f.mutex.Lock()
-	history := make([]DBStoreRefreshCommitResolvabilityFuncCall, len(f.history))
+	history := make([]DBStoreMarkUploadsMissingDataFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// DBStoreRefreshCommitResolvabilityFuncCall is an object that describes an
-// invocation of method RefreshCommitResolvability on an instance of
+// DBStoreMarkUploadsMissingDataFuncCall is an object that describes an
+// invocation of method MarkUploadsMissingData on an instance of
 // MockDBStore.
-type DBStoreRefreshCommitResolvabilityFuncCall struct {
+type DBStoreMarkUploadsMissingDataFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
 	// Arg1 is the value of the 2nd argument passed to this method
 	// invocation.
-	Arg1 int
+	Arg1 []int
 	// Arg2 is the value of the 3rd argument passed to this method
 	// invocation.
-	Arg2 string
-	// Arg3 is the value of the 4th argument passed to this method
-	// invocation.
-	Arg3 bool
-	// Arg4 is the value of the 5th argument passed to this method
-	// invocation.
-	Arg4 time.Time
+	Arg2 time.Time
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
 	Result0 int
@@ -1430,47 +1590,45 @@ type DBStoreRefreshCommitResolvabilityFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c DBStoreRefreshCommitResolvabilityFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+func (c DBStoreMarkUploadsMissingDataFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c DBStoreRefreshCommitResolvabilityFuncCall) Results() []interface{} {
+func (c DBStoreMarkUploadsMissingDataFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
-// DBStoreSelectRepositoriesForRetentionScanFunc describes the behavior when
-// the SelectRepositoriesForRetentionScan method of the parent MockDBStore
-// instance is invoked.
-type DBStoreSelectRepositoriesForRetentionScanFunc struct {
-	defaultHook func(context.Context, time.Duration, int) ([]int, error)
-	hooks       []func(context.Context, time.Duration, int) ([]int, error)
-	history     []DBStoreSelectRepositoriesForRetentionScanFuncCall
+// DBStoreRecentUploadRanksFunc describes the behavior when the
+// RecentUploadRanks method of the parent MockDBStore instance is invoked.
+type DBStoreRecentUploadRanksFunc struct {
+	defaultHook func(context.Context, int, int) (map[int]int, error)
+	hooks       []func(context.Context, int, int) (map[int]int, error)
+	history     []DBStoreRecentUploadRanksFuncCall
 	mutex       sync.Mutex
 }
 
-// SelectRepositoriesForRetentionScan delegates to the next hook function in
-// the queue and stores the parameter and result values of this invocation.
-func (m *MockDBStore) SelectRepositoriesForRetentionScan(v0 context.Context, v1 time.Duration, v2 int) ([]int, error) {
-	r0, r1 := m.SelectRepositoriesForRetentionScanFunc.nextHook()(v0, v1, v2)
-	m.SelectRepositoriesForRetentionScanFunc.appendCall(DBStoreSelectRepositoriesForRetentionScanFuncCall{v0, v1, v2, r0, r1})
+// RecentUploadRanks delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockDBStore) RecentUploadRanks(v0 context.Context, v1 int, v2 int) (map[int]int, error) {
+	r0, r1 := m.RecentUploadRanksFunc.nextHook()(v0, v1, v2)
+	m.RecentUploadRanksFunc.appendCall(DBStoreRecentUploadRanksFuncCall{v0, v1, v2, r0, r1})
 	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the
-// SelectRepositoriesForRetentionScan method of the parent MockDBStore
-// instance is invoked and the hook queue is empty.
-func (f *DBStoreSelectRepositoriesForRetentionScanFunc) SetDefaultHook(hook func(context.Context, time.Duration, int) ([]int, error)) {
+// SetDefaultHook sets function that is called when the RecentUploadRanks
+// method of the parent MockDBStore instance is invoked and the hook queue
+// is empty.
+func (f *DBStoreRecentUploadRanksFunc) SetDefaultHook(hook func(context.Context, int, int) (map[int]int, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// SelectRepositoriesForRetentionScan method of the parent MockDBStore
-// instance invokes the hook at the front of the queue and discards it.
-// After the queue is empty, the default hook function is invoked for any
-// future action.
-func (f *DBStoreSelectRepositoriesForRetentionScanFunc) PushHook(hook func(context.Context, time.Duration, int) ([]int, error)) {
+// RecentUploadRanks method of the parent MockDBStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *DBStoreRecentUploadRanksFunc) PushHook(hook func(context.Context, int, int) (map[int]int, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -1478,21 +1636,21 @@ func (f *DBStoreSelectRepositoriesForRetentionScanFunc) PushHook(hook func(conte
 
 // SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
 // the given values.
-func (f *DBStoreSelectRepositoriesForRetentionScanFunc) SetDefaultReturn(r0 []int, r1 error) {
-	f.SetDefaultHook(func(context.Context, time.Duration, int) ([]int, error) {
+func (f *DBStoreRecentUploadRanksFunc) SetDefaultReturn(r0 map[int]int, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, int) (map[int]int, error) {
 		return r0, r1
 	})
 }
 
 // PushReturn calls PushDefaultHook with a function that returns the given
 // values.
-func (f *DBStoreSelectRepositoriesForRetentionScanFunc) PushReturn(r0 []int, r1 error) {
-	f.PushHook(func(context.Context, time.Duration, int) ([]int, error) {
+func (f *DBStoreRecentUploadRanksFunc) PushReturn(r0 map[int]int, r1 error) {
+	f.PushHook(func(context.Context, int, int) (map[int]int, error) {
 		return r0, r1
 	})
 }
 
-func (f *DBStoreSelectRepositoriesForRetentionScanFunc) nextHook() func(context.Context, time.Duration, int) ([]int, error) {
+func (f *DBStoreRecentUploadRanksFunc) nextHook() func(context.Context, int, int) (map[int]int, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -1505,40 +1663,38 @@ func (f *DBStoreSelectRepositoriesForRetentionScanFunc) nextHook() func(context.
 	return hook
 }
 
-func (f *DBStoreSelectRepositoriesForRetentionScanFunc) appendCall(r0 DBStoreSelectRepositoriesForRetentionScanFuncCall) {
+func (f *DBStoreRecentUploadRanksFunc) appendCall(r0 DBStoreRecentUploadRanksFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of
-// DBStoreSelectRepositoriesForRetentionScanFuncCall objects describing the
-// invocations of this function.
-func (f *DBStoreSelectRepositoriesForRetentionScanFunc) History() []DBStoreSelectRepositoriesForRetentionScanFuncCall {
+// History returns a sequence of DBStoreRecentUploadRanksFuncCall objects
+// describing the invocations of this function.
+func (f *DBStoreRecentUploadRanksFunc) History() []DBStoreRecentUploadRanksFuncCall {
 	f.mutex.Lock()
-	history := make([]DBStoreSelectRepositoriesForRetentionScanFuncCall, len(f.history))
+	history := make([]DBStoreRecentUploadRanksFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// DBStoreSelectRepositoriesForRetentionScanFuncCall is an object that
-// describes an invocation of method SelectRepositoriesForRetentionScan on
-// an instance of MockDBStore.
-type DBStoreSelectRepositoriesForRetentionScanFuncCall struct {
+// DBStoreRecentUploadRanksFuncCall is an object that describes an
+// invocation of method RecentUploadRanks on an instance of MockDBStore.
+type DBStoreRecentUploadRanksFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
 	// Arg1 is the value of the 2nd argument passed to this method
 	// invocation.
-	Arg1 time.Duration
+	Arg1 int
 	// Arg2 is the value of the 3rd argument passed to this method
 	// invocation.
 	Arg2 int
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 []int
+	Result0 map[int]int
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
 	Result1 error
@@ -1546,47 +1702,47 @@ type DBStoreSelectRepositoriesForRetentionScanFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c DBStoreSelectRepositoriesForRetentionScanFuncCall) Args() []interface{} {
+func (c DBStoreRecentUploadRanksFuncCall) Args() []interface{} {
 	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c DBStoreSelectRepositoriesForRetentionScanFuncCall) Results() []interface{} {
+func (c DBStoreRecentUploadRanksFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
-// DBStoreSoftDeleteExpiredUploadsFunc describes the behavior when the
-// SoftDeleteExpiredUploads method of the parent MockDBStore instance is
+// DBStoreRefreshCommitResolvabilityFunc describes the behavior when the
+// RefreshCommitResolvability method of the parent MockDBStore instance is
 // invoked.
-type DBStoreSoftDeleteExpiredUploadsFunc struct {
-	defaultHook func(context.Context) (int, error)
-	hooks       []func(context.Context) (int, error)
-	history     []DBStoreSoftDeleteExpiredUploadsFuncCall
+type DBStoreRefreshCommitResolvabilityFunc struct {
+	defaultHook func(context.Context, int, string, bool, time.Time) (int, int, error)
+	hooks       []func(context.Context, int, string, bool, time.Time) (int, int, error)
+	history     []DBStoreRefreshCommitResolvabilityFuncCall
 	mutex       sync.Mutex
 }
 
-// SoftDeleteExpiredUploads delegates to the next hook function in the queue
-// and stores the parameter and result values of this invocation.
-func (m *MockDBStore) SoftDeleteExpiredUploads(v0 context.Context) (int, error) {
-	r0, r1 := m.SoftDeleteExpiredUploadsFunc.nextHook()(v0)
-	m.SoftDeleteExpiredUploadsFunc.appendCall(DBStoreSoftDeleteExpiredUploadsFuncCall{v0, r0, r1})
-	return r0, r1
+// RefreshCommitResolvability delegates to the next hook function in the
+// queue and stores the parameter and result values of this invocation.
+func (m *MockDBStore) RefreshCommitResolvability(v0 context.Context, v1 int, v2 string, v3 bool, v4 time.Time) (int, int, error) {
+	r0, r1, r2 := m.RefreshCommitResolvabilityFunc.nextHook()(v0, v1, v2, v3, v4)
+	m.RefreshCommitResolvabilityFunc.appendCall(DBStoreRefreshCommitResolvabilityFuncCall{v0, v1, v2, v3, v4, r0, r1, r2})
+	return r0, r1, r2
 }
 
 // SetDefaultHook sets function that is called when the
-// SoftDeleteExpiredUploads method of the parent MockDBStore instance is
+// RefreshCommitResolvability method of the parent MockDBStore instance is
 // invoked and the hook queue is empty.
-func (f *DBStoreSoftDeleteExpiredUploadsFunc) SetDefaultHook(hook func(context.Context) (int, error)) {
+func (f *DBStoreRefreshCommitResolvabilityFunc) SetDefaultHook(hook func(context.Context, int, string, bool, time.Time) (int, int, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// SoftDeleteExpiredUploads method of the parent MockDBStore instance
+// RefreshCommitResolvability method of the parent MockDBStore instance
 // invokes the hook at the front of the queue and discards it. After the
 // queue is empty, the default hook function is invoked for any future
 // action.
-func (f *DBStoreSoftDeleteExpiredUploadsFunc) PushHook(hook func(context.Context) (int, error)) {
+func (f *DBStoreRefreshCommitResolvabilityFunc) PushHook(hook func(context.Context, int, string, bool, time.Time) (int, int, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -1594,21 +1750,21 @@ func (f *DBStoreSoftDeleteExpiredUploadsFunc) PushHook(hook func(context.Context
 
 // SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
 // the given values.
-func (f *DBStoreSoftDeleteExpiredUploadsFunc) SetDefaultReturn(r0 int, r1 error) {
-	f.SetDefaultHook(func(context.Context) (int, error) {
-		return r0, r1
+func (f *DBStoreRefreshCommitResolvabilityFunc) SetDefaultReturn(r0 int, r1 int, r2 error) {
+	f.SetDefaultHook(func(context.Context, int, string, bool, time.Time) (int, int, error) {
+		return r0, r1, r2
 	})
 }
 
 // PushReturn calls PushDefaultHook with a function that returns the given
 // values.
-func (f *DBStoreSoftDeleteExpiredUploadsFunc) PushReturn(r0 int, r1 error) {
-	f.PushHook(func(context.Context) (int, error) {
-		return r0, r1
+func (f *DBStoreRefreshCommitResolvabilityFunc) PushReturn(r0 int, r1 int, r2 error) {
+	f.PushHook(func(context.Context, int, string, bool, time.Time) (int, int, error) {
+		return r0, r1, r2
 	})
 }
 
-func (f *DBStoreSoftDeleteExpiredUploadsFunc) nextHook() func(context.Context) (int, error) {
+func (f *DBStoreRefreshCommitResolvabilityFunc) nextHook() func(context.Context, int, string, bool, time.Time) (int, int, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -1621,25 +1777,374 @@ func (f *DBStoreSoftDeleteExpiredUploadsFunc) nextHook() func(context.Context) (
 	return hook
 }
 
-func (f *DBStoreSoftDeleteExpiredUploadsFunc) appendCall(r0 DBStoreSoftDeleteExpiredUploadsFuncCall) {
+func (f *DBStoreRefreshCommitResolvabilityFunc) appendCall(r0 DBStoreRefreshCommitResolvabilityFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of DBStoreSoftDeleteExpiredUploadsFuncCall
+// History returns a sequence of DBStoreRefreshCommitResolvabilityFuncCall
 // objects describing the invocations of this function.
-func (f *DBStoreSoftDeleteExpiredUploadsFunc) History() []DBStoreSoftDeleteExpiredUploadsFuncCall {
+func (f *DBStoreRefreshCommitResolvabilityFunc) History() []DBStoreRefreshCommitResolvabilityFuncCall {
 	f.mutex.Lock()
-	history := make([]DBStoreSoftDeleteExpiredUploadsFuncCall, len(f.history))
+	history := make([]DBStoreRefreshCommitResolvabilityFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// DBStoreSoftDeleteExpiredUploadsFuncCall is an object that describes an
-// invocation of method SoftDeleteExpiredUploads on an instance of
+// DBStoreRefreshCommitResolvabilityFuncCall is an object that describes an
+// invocation of method RefreshCommitResolvability on an instance of
+// MockDBStore.
+type DBStoreRefreshCommitResolvabilityFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 bool
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 time.Time
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 int
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBStoreRefreshCommitResolvabilityFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBStoreRefreshCommitResolvabilityFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
+// DBStoreResetStalledUploadsFunc describes the behavior when the
+// ResetStalledUploads method of the parent MockDBStore instance is invoked.
+type DBStoreResetStalledUploadsFunc struct {
+	defaultHook func(context.Context, time.Time) ([]dbstore.StalledUpload, error)
+	hooks       []func(context.Context, time.Time) ([]dbstore.StalledUpload, error)
+	history     []DBStoreResetStalledUploadsFuncCall
+	mutex       sync.Mutex
+}
+
+// ResetStalledUploads delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockDBStore) ResetStalledUploads(v0 context.Context, v1 time.Time) ([]dbstore.StalledUpload, error) {
+	r0, r1 := m.ResetStalledUploadsFunc.nextHook()(v0, v1)
+	m.ResetStalledUploadsFunc.appendCall(DBStoreResetStalledUploadsFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the ResetStalledUploads
+// method of the parent MockDBStore instance is invoked and the hook queue
+// is empty.
+func (f *DBStoreResetStalledUploadsFunc) SetDefaultHook(hook func(context.Context, time.Time) ([]dbstore.StalledUpload, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ResetStalledUploads method of the parent MockDBStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *DBStoreResetStalledUploadsFunc) PushHook(hook func(context.Context, time.Time) ([]dbstore.StalledUpload, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DBStoreResetStalledUploadsFunc) SetDefaultReturn(r0 []dbstore.StalledUpload, r1 error) {
+	f.SetDefaultHook(func(context.Context, time.Time) ([]dbstore.StalledUpload, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DBStoreResetStalledUploadsFunc) PushReturn(r0 []dbstore.StalledUpload, r1 error) {
+	f.PushHook(func(context.Context, time.Time) ([]dbstore.StalledUpload, error) {
+		return r0, r1
+	})
+}
+
+func (f *DBStoreResetStalledUploadsFunc) nextHook() func(context.Context, time.Time) ([]dbstore.StalledUpload, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBStoreResetStalledUploadsFunc) appendCall(r0 DBStoreResetStalledUploadsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DBStoreResetStalledUploadsFuncCall objects
+// describing the invocations of this function.
+func (f *DBStoreResetStalledUploadsFunc) History() []DBStoreResetStalledUploadsFuncCall {
+	f.mutex.Lock()
+	history := make([]DBStoreResetStalledUploadsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBStoreResetStalledUploadsFuncCall is an object that describes an
+// invocation of method ResetStalledUploads on an instance of MockDBStore.
+type DBStoreResetStalledUploadsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 time.Time
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []dbstore.StalledUpload
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBStoreResetStalledUploadsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBStoreResetStalledUploadsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// DBStoreSelectRepositoriesForRetentionScanFunc describes the behavior when
+// the SelectRepositoriesForRetentionScan method of the parent MockDBStore
+// instance is invoked.
+type DBStoreSelectRepositoriesForRetentionScanFunc struct {
+	defaultHook func(context.Context, time.Duration, int) ([]int, error)
+	hooks       []func(context.Context, time.Duration, int) ([]int, error)
+	history     []DBStoreSelectRepositoriesForRetentionScanFuncCall
+	mutex       sync.Mutex
+}
+
+// SelectRepositoriesForRetentionScan delegates to the next hook function in
+// the queue and stores the parameter and result values of this invocation.
+func (m *MockDBStore) SelectRepositoriesForRetentionScan(v0 context.Context, v1 time.Duration, v2 int) ([]int, error) {
+	r0, r1 := m.SelectRepositoriesForRetentionScanFunc.nextHook()(v0, v1, v2)
+	m.SelectRepositoriesForRetentionScanFunc.appendCall(DBStoreSelectRepositoriesForRetentionScanFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// SelectRepositoriesForRetentionScan method of the parent MockDBStore
+// instance is invoked and the hook queue is empty.
+func (f *DBStoreSelectRepositoriesForRetentionScanFunc) SetDefaultHook(hook func(context.Context, time.Duration, int) ([]int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SelectRepositoriesForRetentionScan method of the parent MockDBStore
+// instance invokes the hook at the front of the queue and discards it.
+// After the queue is empty, the default hook function is invoked for any
+// future action.
+func (f *DBStoreSelectRepositoriesForRetentionScanFunc) PushHook(hook func(context.Context, time.Duration, int) ([]int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DBStoreSelectRepositoriesForRetentionScanFunc) SetDefaultReturn(r0 []int, r1 error) {
+	f.SetDefaultHook(func(context.Context, time.Duration, int) ([]int, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DBStoreSelectRepositoriesForRetentionScanFunc) PushReturn(r0 []int, r1 error) {
+	f.PushHook(func(context.Context, time.Duration, int) ([]int, error) {
+		return r0, r1
+	})
+}
+
+func (f *DBStoreSelectRepositoriesForRetentionScanFunc) nextHook() func(context.Context, time.Duration, int) ([]int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBStoreSelectRepositoriesForRetentionScanFunc) appendCall(r0 DBStoreSelectRepositoriesForRetentionScanFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// DBStoreSelectRepositoriesForRetentionScanFuncCall objects describing the
+// invocations of this function.
+func (f *DBStoreSelectRepositoriesForRetentionScanFunc) History() []DBStoreSelectRepositoriesForRetentionScanFuncCall {
+	f.mutex.Lock()
+	history := make([]DBStoreSelectRepositoriesForRetentionScanFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBStoreSelectRepositoriesForRetentionScanFuncCall is an object that
+// describes an invocation of method SelectRepositoriesForRetentionScan on
+// an instance of MockDBStore.
+type DBStoreSelectRepositoriesForRetentionScanFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 time.Duration
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBStoreSelectRepositoriesForRetentionScanFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBStoreSelectRepositoriesForRetentionScanFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// DBStoreSoftDeleteExpiredUploadsFunc describes the behavior when the
+// SoftDeleteExpiredUploads method of the parent MockDBStore instance is
+// invoked.
+type DBStoreSoftDeleteExpiredUploadsFunc struct {
+	defaultHook func(context.Context) (int, error)
+	hooks       []func(context.Context) (int, error)
+	history     []DBStoreSoftDeleteExpiredUploadsFuncCall
+	mutex       sync.Mutex
+}
+
+// SoftDeleteExpiredUploads delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockDBStore) SoftDeleteExpiredUploads(v0 context.Context) (int, error) {
+	r0, r1 := m.SoftDeleteExpiredUploadsFunc.nextHook()(v0)
+	m.SoftDeleteExpiredUploadsFunc.appendCall(DBStoreSoftDeleteExpiredUploadsFuncCall{v0, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// SoftDeleteExpiredUploads method of the parent MockDBStore instance is
+// invoked and the hook queue is empty.
+func (f *DBStoreSoftDeleteExpiredUploadsFunc) SetDefaultHook(hook func(context.Context) (int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SoftDeleteExpiredUploads method of the parent MockDBStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *DBStoreSoftDeleteExpiredUploadsFunc) PushHook(hook func(context.Context) (int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DBStoreSoftDeleteExpiredUploadsFunc) SetDefaultReturn(r0 int, r1 error) {
+	f.SetDefaultHook(func(context.Context) (int, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DBStoreSoftDeleteExpiredUploadsFunc) PushReturn(r0 int, r1 error) {
+	f.PushHook(func(context.Context) (int, error) {
+		return r0, r1
+	})
+}
+
+func (f *DBStoreSoftDeleteExpiredUploadsFunc) nextHook() func(context.Context) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBStoreSoftDeleteExpiredUploadsFunc) appendCall(r0 DBStoreSoftDeleteExpiredUploadsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DBStoreSoftDeleteExpiredUploadsFuncCall
+// objects describing the invocations of this function.
+func (f *DBStoreSoftDeleteExpiredUploadsFunc) History() []DBStoreSoftDeleteExpiredUploadsFuncCall {
+	f.mutex.Lock()
+	history := make([]DBStoreSoftDeleteExpiredUploadsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBStoreSoftDeleteExpiredUploadsFuncCall is an object that describes an
+// invocation of method SoftDeleteExpiredUploads on an instance of
 // MockDBStore.
 type DBStoreSoftDeleteExpiredUploadsFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
@@ -1885,6 +2390,115 @@ func (c DBStoreTransactFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// DBStoreUnknownUploadIDsFunc describes the behavior when the
+// UnknownUploadIDs method of the parent MockDBStore instance is invoked.
+type DBStoreUnknownUploadIDsFunc struct {
+	defaultHook func(context.Context, []int) ([]int, error)
+	hooks       []func(context.Context, []int) ([]int, error)
+	history     []DBStoreUnknownUploadIDsFuncCall
+	mutex       sync.Mutex
+}
+
+// UnknownUploadIDs delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockDBStore) UnknownUploadIDs(v0 context.Context, v1 []int) ([]int, error) {
+	r0, r1 := m.UnknownUploadIDsFunc.nextHook()(v0, v1)
+	m.UnknownUploadIDsFunc.appendCall(DBStoreUnknownUploadIDsFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the UnknownUploadIDs
+// method of the parent MockDBStore instance is invoked and the hook queue
+// is empty.
+func (f *DBStoreUnknownUploadIDsFunc) SetDefaultHook(hook func(context.Context, []int) ([]int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// UnknownUploadIDs method of the parent MockDBStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *DBStoreUnknownUploadIDsFunc) PushHook(hook func(context.Context, []int) ([]int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DBStoreUnknownUploadIDsFunc) SetDefaultReturn(r0 []int, r1 error) {
+	f.SetDefaultHook(func(context.Context, []int) ([]int, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DBStoreUnknownUploadIDsFunc) PushReturn(r0 []int, r1 error) {
+	f.PushHook(func(context.Context, []int) ([]int, error) {
+		return r0, r1
+	})
+}
+
+func (f *DBStoreUnknownUploadIDsFunc) nextHook() func(context.Context, []int) ([]int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBStoreUnknownUploadIDsFunc) appendCall(r0 DBStoreUnknownUploadIDsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DBStoreUnknownUploadIDsFuncCall objects
+// describing the invocations of this function.
+func (f *DBStoreUnknownUploadIDsFunc) History() []DBStoreUnknownUploadIDsFuncCall {
+	f.mutex.Lock()
+	history := make([]DBStoreUnknownUploadIDsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBStoreUnknownUploadIDsFuncCall is an object that describes an invocation
+// of method UnknownUploadIDs on an instance of MockDBStore.
+type DBStoreUnknownUploadIDsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 []int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBStoreUnknownUploadIDsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBStoreUnknownUploadIDsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // DBStoreUpdateUploadRetentionFunc describes the behavior when the
 // UpdateUploadRetention method of the parent MockDBStore instance is
 // invoked.
@@ -2000,6 +2614,9 @@ func (c DBStoreUpdateUploadRetentionFuncCall) Results() []interface{} {
 // github.com/sourcegraph/sourcegraph/enterprise/cmd/worker/internal/codeintel/janitor)
 // used for unit testing.
 type MockLSIFStore struct {
+	// BundleIDsFunc is an instance of a mock function object controlling
+	// the behavior of the method BundleIDs.
+	BundleIDsFunc *LSIFStoreBundleIDsFunc
 	// ClearFunc is an instance of a mock function object controlling the
 	// behavior of the method Clear.
 	ClearFunc *LSIFStoreClearFunc
@@ -2014,6 +2631,9 @@ type MockLSIFStore struct {
 	// DoneFunc is an instance of a mock function object controlling the
 	// behavior of the method Done.
 	DoneFunc *LSIFStoreDoneFunc
+	// MissingBundleIDsFunc is an instance of a mock function object
+	// controlling the behavior of the method MissingBundleIDs.
+	MissingBundleIDsFunc *LSIFStoreMissingBundleIDsFunc
 	// TransactFunc is an instance of a mock function object controlling the
 	// behavior of the method Transact.
 	TransactFunc *LSIFStoreTransactFunc
@@ -2023,6 +2643,11 @@ type MockLSIFStore struct {
 // methods return zero values for all results, unless overwritten.
 func NewMockLSIFStore() *MockLSIFStore {
 	return &MockLSIFStore{
+		BundleIDsFunc: &LSIFStoreBundleIDsFunc{
+			defaultHook: func(context.Context, int, int) ([]int, error) {
+				return nil, nil
+			},
+		},
 		ClearFunc: &LSIFStoreClearFunc{
 			defaultHook: func(context.Context, ...int) error {
 				return nil
@@ -2043,6 +2668,11 @@ func NewMockLSIFStore() *MockLSIFStore {
 				return nil
 			},
 		},
+		MissingBundleIDsFunc: &LSIFStoreMissingBundleIDsFunc{
+			defaultHook: func(context.Context, []int) ([]int, error) {
+				return nil, nil
+			},
+		},
 		TransactFunc: &LSIFStoreTransactFunc{
 			defaultHook: func(context.Context) (LSIFStore, error) {
 				return nil, nil
@@ -2055,6 +2685,9 @@ func NewMockLSIFStore() *MockLSIFStore {
 // All methods delegate to the given implementation, unless overwritten.
 func NewMockLSIFStoreFrom(i LSIFStore) *MockLSIFStore {
 	return &MockLSIFStore{
+		BundleIDsFunc: &LSIFStoreBundleIDsFunc{
+			defaultHook: i.BundleIDs,
+		},
 		ClearFunc: &LSIFStoreClearFunc{
 			defaultHook: i.Clear,
 		},
@@ -2067,12 +2700,126 @@ func NewMockLSIFStoreFrom(i LSIFStore) *MockLSIFStore {
 		DoneFunc: &LSIFStoreDoneFunc{
 			defaultHook: i.Done,
 		},
+		MissingBundleIDsFunc: &LSIFStoreMissingBundleIDsFunc{
+			defaultHook: i.MissingBundleIDs,
+		},
 		TransactFunc: &LSIFStoreTransactFunc{
 			defaultHook: i.Transact,
 		},
 	}
 }
 
+// LSIFStoreBundleIDsFunc describes the behavior when the BundleIDs method
+// of the parent MockLSIFStore instance is invoked.
+type LSIFStoreBundleIDsFunc struct {
+	defaultHook func(context.Context, int, int) ([]int, error)
+	hooks       []func(context.Context, int, int) ([]int, error)
+	history     []LSIFStoreBundleIDsFuncCall
+	mutex       sync.Mutex
+}
+
+// BundleIDs delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockLSIFStore) BundleIDs(v0 context.Context, v1 int, v2 int) ([]int, error) {
+	r0, r1 := m.BundleIDsFunc.nextHook()(v0, v1, v2)
+	m.BundleIDsFunc.appendCall(LSIFStoreBundleIDsFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the BundleIDs method of
+// the parent MockLSIFStore instance is invoked and the hook queue is empty.
+func (f *LSIFStoreBundleIDsFunc) SetDefaultHook(hook func(context.Context, int, int) ([]int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// BundleIDs method of the parent MockLSIFStore instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *LSIFStoreBundleIDsFunc) PushHook(hook func(context.Context, int, int) ([]int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *LSIFStoreBundleIDsFunc) SetDefaultReturn(r0 []int, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, int) ([]int, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *LSIFStoreBundleIDsFunc) PushReturn(r0 []int, r1 error) {
+	f.PushHook(func(context.Context, int, int) ([]int, error) {
+		return r0, r1
+	})
+}
+
+func (f *LSIFStoreBundleIDsFunc) nextHook() func(context.Context, int, int) ([]int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LSIFStoreBundleIDsFunc) appendCall(r0 LSIFStoreBundleIDsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LSIFStoreBundleIDsFuncCall objects
+// describing the invocations of this function.
+func (f *LSIFStoreBundleIDsFunc) History() []LSIFStoreBundleIDsFuncCall {
+	f.mutex.Lock()
+	history := make([]LSIFStoreBundleIDsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LSIFStoreBundleIDsFuncCall is an object that describes an invocation of
+// method BundleIDs on an instance of MockLSIFStore.
+type LSIFStoreBundleIDsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LSIFStoreBundleIDsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LSIFStoreBundleIDsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // LSIFStoreClearFunc describes the behavior when the Clear method of the
 // parent MockLSIFStore instance is invoked.
 type LSIFStoreClearFunc struct {
@@ -2519,6 +3266,115 @@ func (c LSIFStoreDoneFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// LSIFStoreMissingBundleIDsFunc describes the behavior when the
+// MissingBundleIDs method of the parent MockLSIFStore instance is invoked.
+type LSIFStoreMissingBundleIDsFunc struct {
+	defaultHook func(context.Context, []int) ([]int, error)
+	hooks       []func(context.Context, []int) ([]int, error)
+	history     []LSIFStoreMissingBundleIDsFuncCall
+	mutex       sync.Mutex
+}
+
+// MissingBundleIDs delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockLSIFStore) MissingBundleIDs(v0 context.Context, v1 []int) ([]int, error) {
+	r0, r1 := m.MissingBundleIDsFunc.nextHook()(v0, v1)
+	m.MissingBundleIDsFunc.appendCall(LSIFStoreMissingBundleIDsFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the MissingBundleIDs
+// method of the parent MockLSIFStore instance is invoked and the hook queue
+// is empty.
+func (f *LSIFStoreMissingBundleIDsFunc) SetDefaultHook(hook func(context.Context, []int) ([]int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// MissingBundleIDs method of the parent MockLSIFStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *LSIFStoreMissingBundleIDsFunc) PushHook(hook func(context.Context, []int) ([]int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *LSIFStoreMissingBundleIDsFunc) SetDefaultReturn(r0 []int, r1 error) {
+	f.SetDefaultHook(func(context.Context, []int) ([]int, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *LSIFStoreMissingBundleIDsFunc) PushReturn(r0 []int, r1 error) {
+	f.PushHook(func(context.Context, []int) ([]int, error) {
+		return r0, r1
+	})
+}
+
+func (f *LSIFStoreMissingBundleIDsFunc) nextHook() func(context.Context, []int) ([]int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LSIFStoreMissingBundleIDsFunc) appendCall(r0 LSIFStoreMissingBundleIDsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LSIFStoreMissingBundleIDsFuncCall objects
+// describing the invocations of this function.
+func (f *LSIFStoreMissingBundleIDsFunc) History() []LSIFStoreMissingBundleIDsFuncCall {
+	f.mutex.Lock()
+	history := make([]LSIFStoreMissingBundleIDsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LSIFStoreMissingBundleIDsFuncCall is an object that describes an
+// invocation of method MissingBundleIDs on an instance of MockLSIFStore.
+type LSIFStoreMissingBundleIDsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 []int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LSIFStoreMissingBundleIDsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LSIFStoreMissingBundleIDsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // LSIFStoreTransactFunc describes the behavior when the Transact method of
 // the parent MockLSIFStore instance is invoked.
 type LSIFStoreTransactFunc struct {
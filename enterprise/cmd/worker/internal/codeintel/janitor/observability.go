@@ -18,6 +18,10 @@ type metrics struct {
 	numDocumentSearchRecordsRemoved prometheus.Counter
 	numErrors                       prometheus.Counter
 
+	// Integrity metrics
+	numUploadsMissingData prometheus.Counter
+	numOrphanedBundles    prometheus.Counter
+
 	// Resetter metrics
 	numUploadResets                 prometheus.Counter
 	numUploadResetFailures          prometheus.Counter
@@ -80,6 +84,15 @@ func newMetrics(observationContext *observation.Context) *metrics {
 		"The number of errors that occur during a codeintel expiration job.",
 	)
 
+	numUploadsMissingData := counter(
+		"src_codeintel_background_uploads_missing_data_total",
+		"The number of completed codeintel upload records found without data in the codeintel database.",
+	)
+	numOrphanedBundles := counter(
+		"src_codeintel_background_orphaned_bundles_total",
+		"The number of bundles found in the codeintel database without a codeintel upload record.",
+	)
+
 	numUploadResets := counter(
 		"src_codeintel_background_upload_record_resets_total",
 		"The number of upload record resets.",
@@ -129,6 +142,8 @@ func newMetrics(observationContext *observation.Context) *metrics {
 		numUploadsPurged:                numUploadsPurged,
 		numDocumentSearchRecordsRemoved: numDocumentSearchRecordsRemoved,
 		numErrors:                       numErrors,
+		numUploadsMissingData:           numUploadsMissingData,
+		numOrphanedBundles:              numOrphanedBundles,
 		numUploadResets:                 numUploadResets,
 		numUploadResetFailures:          numUploadResetFailures,
 		numUploadResetErrors:            numUploadResetErrors,
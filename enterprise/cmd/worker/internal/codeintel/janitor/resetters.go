@@ -8,21 +8,6 @@ import (
 	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
 )
 
-// NewUploadResetter returns a background routine that periodically resets upload
-// records that are marked as being processed but are no longer being processed
-// by a worker.
-func NewUploadResetter(s dbworkerstore.Store, interval time.Duration, metrics *metrics, observationContext *observation.Context) *dbworker.Resetter {
-	return dbworker.NewResetter(s, dbworker.ResetterOptions{
-		Name:     "precise_code_intel_upload_worker_resetter",
-		Interval: interval,
-		Metrics: dbworker.ResetterMetrics{
-			RecordResets:        metrics.numUploadResets,
-			RecordResetFailures: metrics.numUploadResetFailures,
-			Errors:              metrics.numUploadResetErrors,
-		},
-	})
-}
-
 // NewIndexResetter returns a background routine that periodically resets index
 // records that are marked as being processed but are no longer being processed
 // by a worker.
@@ -0,0 +1,56 @@
+package janitor
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+type stalledUploadResetter struct {
+	dbStore DBStore
+	maxAge  time.Duration
+	metrics *metrics
+}
+
+var _ goroutine.Handler = &stalledUploadResetter{}
+var _ goroutine.ErrorHandler = &stalledUploadResetter{}
+
+// NewStalledUploadResetter returns a background routine that periodically requeues
+// upload records that are marked as being processed but whose worker has not sent
+// a heartbeat within the given max age. Uploads that have been reset too many times
+// are marked as failed instead.
+func NewStalledUploadResetter(dbStore DBStore, maxAge, interval time.Duration, metrics *metrics) goroutine.BackgroundRoutine {
+	return goroutine.NewPeriodicGoroutine(context.Background(), interval, &stalledUploadResetter{
+		dbStore: dbStore,
+		maxAge:  maxAge,
+		metrics: metrics,
+	})
+}
+
+func (h *stalledUploadResetter) Handle(ctx context.Context) error {
+	uploads, err := h.dbStore.ResetStalledUploads(ctx, time.Now().UTC().Add(-h.maxAge))
+	if err != nil {
+		return errors.Wrap(err, "dbstore.ResetStalledUploads")
+	}
+
+	for _, upload := range uploads {
+		if upload.Failed {
+			log15.Warn("Marked stalled upload as failed", "id", upload.ID, "workerHostname", upload.WorkerHostname, "lastHeartbeatAt", upload.LastHeartbeatAt, "numResets", upload.NumResets)
+			h.metrics.numUploadResetFailures.Inc()
+		} else {
+			log15.Info("Requeued stalled upload", "id", upload.ID, "workerHostname", upload.WorkerHostname, "lastHeartbeatAt", upload.LastHeartbeatAt, "numResets", upload.NumResets)
+			h.metrics.numUploadResets.Inc()
+		}
+	}
+
+	return nil
+}
+
+func (h *stalledUploadResetter) HandleError(err error) {
+	h.metrics.numUploadResetErrors.Inc()
+	log15.Error("Failed to reset stalled uploads", "error", err)
+}
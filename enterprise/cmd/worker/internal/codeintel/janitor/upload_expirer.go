@@ -32,8 +32,9 @@ var _ goroutine.ErrorHandler = &uploadExpirer{}
 // NewUploadExpirer returns a background routine that periodically compares the age of upload records
 // against the age of uploads protected by global and repository specific data retention policies.
 //
-// Uploads that are older than the protected retention age are marked as expired. Expired records with
-// no dependents will be removed by the expiredUploadDeleter.
+// Uploads that are older than the protected retention age and that are not among the most recent uploads
+// retained by a matching policy are marked as expired. Expired records with no dependents will be removed
+// by the expiredUploadDeleter.
 func NewUploadExpirer(
 	dbStore DBStore,
 	policyMatcher PolicyMatcher,
@@ -135,6 +136,27 @@ func (e *uploadExpirer) handleRepository(
 		return errors.Wrap(err, "policies.CommitsDescribedByPolicy")
 	}
 
+	// Policies may also retain the most recent uploads for each root and indexer regardless of their
+	// age, so that code intelligence remains available after rolling back to a recent deployment.
+	retainedCounts := map[int]int{}
+	maxRetainedCount := 0
+	for _, policy := range combinedPolicies {
+		if policy.RetainMostRecentUploads != nil && *policy.RetainMostRecentUploads > 0 {
+			retainedCounts[policy.ID] = *policy.RetainMostRecentUploads
+
+			if *policy.RetainMostRecentUploads > maxRetainedCount {
+				maxRetainedCount = *policy.RetainMostRecentUploads
+			}
+		}
+	}
+
+	var uploadRanks map[int]int
+	if maxRetainedCount > 0 {
+		if uploadRanks, err = e.dbStore.RecentUploadRanks(ctx, repositoryID, maxRetainedCount); err != nil {
+			return errors.Wrap(err, "dbstore.RecentUploadRanks")
+		}
+	}
+
 	// Mark the time after which all unprocessed uploads for this repository will not be touched.
 	// This timestamp field is used as a rate limiting device so we do not busy-loop over the same
 	// protected records in the background.
@@ -163,7 +185,7 @@ func (e *uploadExpirer) handleRepository(
 			return err
 		}
 
-		if err := e.handleUploads(ctx, commitMap, uploads, now); err != nil {
+		if err := e.handleUploads(ctx, commitMap, retainedCounts, uploadRanks, uploads, now); err != nil {
 			// Note that we collect errors in the lop of the handleUploads call, but we will still terminate
 			// this loop on any non-nil error from that function. This is required to prevent us from pullling
 			// back the same set of failing records from the database in a tight loop.
@@ -175,6 +197,8 @@ func (e *uploadExpirer) handleRepository(
 func (e *uploadExpirer) handleUploads(
 	ctx context.Context,
 	commitMap map[string][]policies.PolicyMatch,
+	retainedCounts map[int]int,
+	uploadRanks map[int]int,
 	uploads []dbstore.Upload,
 	now time.Time,
 ) (err error) {
@@ -183,7 +207,7 @@ func (e *uploadExpirer) handleUploads(
 	expiredUploadIDs := make([]int, 0, len(uploads))
 
 	for _, upload := range uploads {
-		protected, checkErr := e.isUploadProtectedByPolicy(ctx, commitMap, upload, now)
+		protected, checkErr := e.isUploadProtectedByPolicy(ctx, commitMap, retainedCounts, uploadRanks, upload, now)
 		if checkErr != nil {
 			if err == nil {
 				err = checkErr
@@ -223,6 +247,8 @@ func (e *uploadExpirer) handleUploads(
 func (e *uploadExpirer) isUploadProtectedByPolicy(
 	ctx context.Context,
 	commitMap map[string][]policies.PolicyMatch,
+	retainedCounts map[int]int,
+	uploadRanks map[int]int,
 	upload dbstore.Upload,
 	now time.Time,
 ) (bool, error) {
@@ -255,6 +281,12 @@ func (e *uploadExpirer) isUploadProtectedByPolicy(
 					if policyMatch.PolicyDuration == nil || now.Sub(upload.UploadedAt) < *policyMatch.PolicyDuration {
 						return true, nil
 					}
+
+					if policyMatch.PolicyID != nil {
+						if rank, ok := uploadRanks[upload.ID]; ok && rank <= retainedCounts[*policyMatch.PolicyID] {
+							return true, nil
+						}
+					}
 				}
 			}
 		}
@@ -47,16 +47,22 @@ func TestUploadExpirer(t *testing.T) {
 	}
 	sort.Ints(expiredIDs)
 
-	expectedProtectedIDs := []int{12, 16, 18, 20, 25, 26, 27, 28}
+	expectedProtectedIDs := []int{12, 16, 18, 20, 23, 24, 25, 26, 27, 28}
 	if diff := cmp.Diff(expectedProtectedIDs, protectedIDs); diff != "" {
 		t.Errorf("unexpected protected upload identifiers (-want +got):\n%s", diff)
 	}
 
-	expectedExpiredIDs := []int{11, 13, 14, 15, 17, 19, 21, 22, 23, 24, 29, 30}
+	expectedExpiredIDs := []int{11, 13, 14, 15, 17, 19, 21, 22, 29, 30}
 	if diff := cmp.Diff(expectedExpiredIDs, expiredIDs); diff != "" {
 		t.Errorf("unexpected expired upload identifiers (-want +got):\n%s", diff)
 	}
 
+	for _, call := range dbStore.RecentUploadRanksFunc.History() {
+		if call.Arg2 != 2 {
+			t.Errorf("unexpected limit supplied to RecentUploadRanks(%d). want=%d have=%d", call.Arg1, 2, call.Arg2)
+		}
+	}
+
 	calls := policyMatcher.CommitsDescribedByPolicyFunc.History()
 	if len(calls) != 4 {
 		t.Fatalf("unexpected number of calls to CommitsDescribedByPolicy. want=%d have=%d", 4, len(calls))
@@ -92,9 +98,9 @@ func testUploadExpirerMockDBStore(now time.Time) *MockDBStore {
 		{ID: 18, State: "completed", RepositoryID: 51, Commit: "deadbeef08", UploadedAt: daysAgo(now, 8)},
 		{ID: 19, State: "completed", RepositoryID: 51, Commit: "deadbeef09", UploadedAt: daysAgo(now, 9)},
 		{ID: 20, State: "completed", RepositoryID: 51, Commit: "deadbeef10", UploadedAt: daysAgo(now, 1)},
-		{ID: 21, State: "completed", RepositoryID: 52, Commit: "deadbeef11", UploadedAt: daysAgo(now, 9)}, // repo 52
-		{ID: 22, State: "completed", RepositoryID: 52, Commit: "deadbeef12", UploadedAt: daysAgo(now, 8)},
-		{ID: 23, State: "completed", RepositoryID: 52, Commit: "deadbeef13", UploadedAt: daysAgo(now, 7)},
+		{ID: 21, State: "completed", RepositoryID: 52, Commit: "deadbeef11", UploadedAt: daysAgo(now, 9), Root: "lib/"}, // repo 52
+		{ID: 22, State: "completed", RepositoryID: 52, Commit: "deadbeef12", UploadedAt: daysAgo(now, 8), Root: "lib/"},
+		{ID: 23, State: "completed", RepositoryID: 52, Commit: "deadbeef13", UploadedAt: daysAgo(now, 7), Root: "lib/"},
 		{ID: 24, State: "completed", RepositoryID: 52, Commit: "deadbeef14", UploadedAt: daysAgo(now, 6)},
 		{ID: 25, State: "completed", RepositoryID: 52, Commit: "deadbeef15", UploadedAt: daysAgo(now, 5)},
 		{ID: 26, State: "completed", RepositoryID: 53, Commit: "deadbeef16", UploadedAt: daysAgo(now, 4)}, // repo 53
@@ -107,7 +113,7 @@ func testUploadExpirerMockDBStore(now time.Time) *MockDBStore {
 	policies := []dbstore.ConfigurationPolicy{
 		{ID: 1, RepositoryID: nil},
 		{ID: 2, RepositoryID: intPtr(53)},
-		{ID: 3, RepositoryID: nil},
+		{ID: 3, RepositoryID: nil, RetainMostRecentUploads: intPtr(2)},
 		{ID: 4, RepositoryID: nil},
 		{ID: 5, RepositoryID: intPtr(50)},
 	}
@@ -186,6 +192,32 @@ func testUploadExpirerMockDBStore(now time.Time) *MockDBStore {
 		return nil
 	}
 
+	recentUploadRanks := func(ctx context.Context, repositoryID, limit int) (map[int]int, error) {
+		type rootIndexer struct{ root, indexer string }
+		partitions := map[rootIndexer][]dbstore.Upload{}
+		for _, upload := range uploads {
+			if _, ok := expired[upload.ID]; !ok && upload.RepositoryID == repositoryID {
+				key := rootIndexer{upload.Root, upload.Indexer}
+				partitions[key] = append(partitions[key], upload)
+			}
+		}
+
+		ranks := map[int]int{}
+		for _, partition := range partitions {
+			sort.Slice(partition, func(i, j int) bool {
+				return partition[i].UploadedAt.After(partition[j].UploadedAt)
+			})
+
+			for i, upload := range partition {
+				if i < limit {
+					ranks[upload.ID] = i + 1
+				}
+			}
+		}
+
+		return ranks, nil
+	}
+
 	commitsVisibleToUpload := func(ctx context.Context, uploadID, limit int, token *string) ([]string, *string, error) {
 		for _, upload := range uploads {
 			if upload.ID == uploadID {
@@ -204,11 +236,14 @@ func testUploadExpirerMockDBStore(now time.Time) *MockDBStore {
 	dbStore.GetConfigurationPoliciesFunc.SetDefaultHook(getConfigurationPolicies)
 	dbStore.GetUploadsFunc.SetDefaultHook(getUploads)
 	dbStore.UpdateUploadRetentionFunc.SetDefaultHook(updateUploadRetention)
+	dbStore.RecentUploadRanksFunc.SetDefaultHook(recentUploadRanks)
 	dbStore.CommitsVisibleToUploadFunc.SetDefaultHook(commitsVisibleToUpload)
 	return dbStore
 }
 
 func testUploadExpirerMockPolicyMatcher(now time.Time) *MockPolicyMatcher {
+	retainMostRecentPolicyID := 3
+
 	policyMatches := map[int]map[string][]policies.PolicyMatch{
 		50: {
 			"deadbeef01": {{PolicyDuration: days(1)}}, // 1 = 1
@@ -226,11 +261,11 @@ func testUploadExpirerMockPolicyMatcher(now time.Time) *MockPolicyMatcher {
 			"deadbeef10": {{PolicyDuration: days(9)}}, // 9 > 1 (protected)
 		},
 		52: {
-			"deadbeef11": {{PolicyDuration: days(5)}},                        // 5 < 9
-			"deadbeef12": {{PolicyDuration: days(5)}},                        // 5 < 8
-			"deadbeef13": {{PolicyDuration: days(5)}},                        // 5 < 7
-			"deadbeef14": {{PolicyDuration: days(5)}},                        // 5 < 6
-			"deadbeef15": {{PolicyDuration: days(5)}, {PolicyDuration: nil}}, // 5 = 5, catch-all (protected)
+			"deadbeef11": {{PolicyID: &retainMostRecentPolicyID, PolicyDuration: days(5)}}, // 5 < 9, third most recent of lib/
+			"deadbeef12": {{PolicyDuration: days(5)}},                                      // 5 < 8
+			"deadbeef13": {{PolicyID: &retainMostRecentPolicyID, PolicyDuration: days(5)}}, // 5 < 7, most recent of lib/ (protected)
+			"deadbeef14": {{PolicyID: &retainMostRecentPolicyID, PolicyDuration: days(5)}}, // 5 < 6, second most recent of the root (protected)
+			"deadbeef15": {{PolicyDuration: days(5)}, {PolicyDuration: nil}},               // 5 = 5, catch-all (protected)
 		},
 		53: {
 			"deadbeef16": {{PolicyDuration: days(5)}}, // 5 > 4 (protected)
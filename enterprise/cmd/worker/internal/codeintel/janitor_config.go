@@ -11,6 +11,7 @@ type janitorConfig struct {
 	env.BaseConfig
 
 	UploadTimeout                                       time.Duration
+	StalledUploadMaxAge                                 time.Duration
 	CleanupTaskInterval                                 time.Duration
 	CommitResolverTaskInterval                          time.Duration
 	CommitResolverMinimumTimeSinceLastCheck             time.Duration
@@ -23,6 +24,9 @@ type janitorConfig struct {
 	BranchesCacheMaxKeys                                int
 	DocumentationSearchCurrentMinimumTimeSinceLastCheck time.Duration
 	DocumentationSearchCurrentBatchSize                 int
+	IntegrityCheckerTaskInterval                        time.Duration
+	IntegrityCheckerBatchSize                           int
+	IntegrityCheckerRepair                              bool
 
 	MetricsConfig *executorqueue.Config
 }
@@ -31,6 +35,7 @@ var janitorConfigInst = &janitorConfig{}
 
 func (c *janitorConfig) Load() {
 	c.UploadTimeout = c.GetInterval("PRECISE_CODE_INTEL_UPLOAD_TIMEOUT", "24h", "The maximum time an upload can be in the 'uploading' state.")
+	c.StalledUploadMaxAge = c.GetInterval("PRECISE_CODE_INTEL_STALLED_UPLOAD_MAX_AGE", "25s", "The maximum time since the last heartbeat of an upload in the 'processing' state before it is requeued. Must be greater than PRECISE_CODE_INTEL_WORKER_HEARTBEAT_INTERVAL.")
 	c.CleanupTaskInterval = c.GetInterval("PRECISE_CODE_INTEL_CLEANUP_TASK_INTERVAL", "1m", "The frequency with which to run periodic codeintel cleanup tasks.")
 	c.CommitResolverTaskInterval = c.GetInterval("PRECISE_CODE_INTEL_COMMIT_RESOLVER_TASK_INTERVAL", "10s", "The frequency with which to run the periodic commit resolver task.")
 	c.CommitResolverMinimumTimeSinceLastCheck = c.GetInterval("PRECISE_CODE_INTEL_COMMIT_RESOLVER_MINIMUM_TIME_SINCE_LAST_CHECK", "24h", "The minimum time the commit resolver will re-check an upload or index record.")
@@ -43,6 +48,9 @@ func (c *janitorConfig) Load() {
 	c.BranchesCacheMaxKeys = c.GetInt("PRECISE_CODE_INTEL_RETENTION_BRANCHES_CACHE_MAX_KEYS", "10000", "The number of maximum keys used to cache the set of branches visible from a commit.")
 	c.DocumentationSearchCurrentMinimumTimeSinceLastCheck = c.GetInterval("PRECISE_CODE_INTEL_DOCUMENTATION_SEARCH_CURRENT_MINIMUM_TIME_SINCE_LAST_CHECK", "24h", "The minimum time the documentation search current janitor will re-check records for a unique search key.")
 	c.DocumentationSearchCurrentBatchSize = c.GetInt("PRECISE_CODE_INTEL_DOCUMENTATION_SEARCH_CURRENT_BATCH_SIZE", "100", "The maximum number of unique search keys to clean up at a time.")
+	c.IntegrityCheckerTaskInterval = c.GetInterval("PRECISE_CODE_INTEL_INTEGRITY_CHECKER_TASK_INTERVAL", "1m", "The frequency with which to check the consistency of upload records and data in the codeintel database.")
+	c.IntegrityCheckerBatchSize = c.GetInt("PRECISE_CODE_INTEL_INTEGRITY_CHECKER_BATCH_SIZE", "1000", "The number of upload records and bundles to check for consistency at a time.")
+	c.IntegrityCheckerRepair = c.GetBool("PRECISE_CODE_INTEL_INTEGRITY_CHECKER_REPAIR", "false", "Whether to requeue the index jobs of uploads missing their data and clear orphaned data in the codeintel database.")
 
 	c.MetricsConfig = executorqueue.InitMetricsConfig()
 	c.MetricsConfig.Load()
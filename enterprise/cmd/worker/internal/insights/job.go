@@ -46,8 +46,12 @@ func (s *insightsJob) Routines(ctx context.Context) ([]goroutine.BackgroundRouti
 	if err != nil {
 		return nil, err
 	}
+	storage, err := insights.SeriesStorage()
+	if err != nil {
+		return nil, err
+	}
 
-	return background.GetBackgroundJobs(context.Background(), mainAppDb, insightsDB), nil
+	return background.GetBackgroundJobs(context.Background(), mainAppDb, insightsDB, storage), nil
 }
 
 func NewInsightsJob() shared.Job {
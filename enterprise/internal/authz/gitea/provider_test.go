@@ -0,0 +1,138 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/time/rate"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func newTestProvider(t *testing.T, h http.Handler) *Provider {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli := gitea.NewClient(extsvc.NormalizeBaseURL(u), "admin-token", nil)
+	cli.RateLimit = rate.NewLimiter(rate.Inf, 1)
+	return NewProvider("", cli)
+}
+
+func TestProvider_FetchAccount(t *testing.T) {
+	p := newTestProvider(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/users/alice" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&gitea.User{ID: 42, Login: "alice"})
+	}))
+
+	acct, err := p.FetchAccount(context.Background(), &types.User{ID: 1, Username: "alice"}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := extsvc.AccountSpec{ServiceType: extsvc.TypeGitea, ServiceID: p.ServiceID(), AccountID: "42"}
+	if diff := cmp.Diff(want, acct.AccountSpec); diff != "" {
+		t.Fatalf("AccountSpec mismatch (-want +got):\n%s", diff)
+	}
+
+	acct, err = p.FetchAccount(context.Background(), &types.User{ID: 2, Username: "bob"}, nil, nil)
+	if err != nil || acct != nil {
+		t.Fatalf("got account %v and error %v for unknown user, want nil", acct, err)
+	}
+}
+
+func TestProvider_FetchUserPerms(t *testing.T) {
+	p := newTestProvider(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/user/repos" || r.Header.Get("Sudo") != "alice" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode([]*gitea.Repo{
+			{ID: 1, FullName: "myorg/public"},
+			{ID: 2, FullName: "myorg/private", Private: true},
+			{ID: 3, FullName: "alice/private", Private: true},
+		})
+	}))
+
+	data := json.RawMessage(`{"id": 42, "login": "alice"}`)
+	acct := &extsvc.Account{
+		AccountSpec: extsvc.AccountSpec{ServiceType: extsvc.TypeGitea, ServiceID: p.ServiceID(), AccountID: "42"},
+		AccountData: extsvc.AccountData{Data: &data},
+	}
+
+	perms, err := p.FetchUserPerms(context.Background(), acct, authz.FetchPermsOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]extsvc.RepoID{"2", "3"}, perms.Exacts); diff != "" {
+		t.Fatalf("Exacts mismatch (-want +got):\n%s", diff)
+	}
+
+	acct.AccountSpec.ServiceID = "https://gitea.example.com/"
+	if _, err := p.FetchUserPerms(context.Background(), acct, authz.FetchPermsOptions{}); err == nil {
+		t.Fatal("expected error for account of another code host")
+	}
+}
+
+func TestProvider_FetchRepoPerms(t *testing.T) {
+	p := newTestProvider(t, http.NotFoundHandler())
+
+	_, err := p.FetchRepoPerms(context.Background(), &extsvc.Repository{}, authz.FetchPermsOptions{})
+	if !errors.Is(err, authz.ErrUnimplemented) {
+		t.Fatalf("got error %v, want %v", err, authz.ErrUnimplemented)
+	}
+}
+
+func TestValidateAuthz(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		conn    *schema.GiteaConnection
+		wantErr bool
+	}{
+		{
+			name: "no authorization",
+			conn: &schema.GiteaConnection{Url: "https://gitea.example.com"},
+		},
+		{
+			name: "username identity provider",
+			conn: &schema.GiteaConnection{
+				Url: "https://gitea.example.com",
+				Authorization: &schema.GiteaAuthorization{
+					IdentityProvider: schema.GiteaIdentityProvider{Type: "username"},
+				},
+			},
+		},
+		{
+			name: "unknown identity provider",
+			conn: &schema.GiteaConnection{
+				Url: "https://gitea.example.com",
+				Authorization: &schema.GiteaAuthorization{
+					IdentityProvider: schema.GiteaIdentityProvider{Type: "email"},
+				},
+			},
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := ValidateAuthz(tc.conn); (err != nil) != tc.wantErr {
+				t.Fatalf("got error %v, want error: %v", err, tc.wantErr)
+			}
+		})
+	}
+}
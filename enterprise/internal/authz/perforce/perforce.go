@@ -35,6 +35,10 @@ type Provider struct {
 
 	p4Execer p4Execer
 
+	// The depots whose path-level permissions are enforced as sub-repo
+	// permissions.
+	depots []extsvc.RepoID
+
 	// NOTE: We do not need mutex because there is no concurrent access to these
 	// 	fields in the current implementation.
 	cachedAllUserEmails map[string]string   // username <-> email
@@ -49,7 +53,9 @@ type p4Execer interface {
 // host, user and password to talk to a Perforce Server that is the source of
 // truth for permissions. It assumes emails of Sourcegraph accounts match 1-1
 // with emails of Perforce Server users. It uses our default gitserver client.
-func NewProvider(urn, host, user, password string) *Provider {
+// The rules of the protections table within the given depots are enforced as
+// sub-repo permissions, no depots disables sub-repo permissions.
+func NewProvider(urn, host, user, password string, depots []extsvc.RepoID) *Provider {
 	baseURL, _ := url.Parse(host)
 	return &Provider{
 		urn:                urn,
@@ -58,6 +64,7 @@ func NewProvider(urn, host, user, password string) *Provider {
 		user:               user,
 		password:           password,
 		p4Execer:           gitserver.DefaultClient,
+		depots:             depots,
 		cachedGroupMembers: make(map[string][]string),
 	}
 }
@@ -204,6 +211,7 @@ func (p *Provider) FetchUserPerms(ctx context.Context, account *extsvc.Account,
 	)
 
 	var includeContains, excludeContains []extsvc.RepoID
+	subRepoRules := newSubRepoRules(p.depots)
 	scanner := bufio.NewScanner(rc)
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -254,6 +262,8 @@ func (p *Provider) FetchUserPerms(ctx context.Context, account *extsvc.Account,
 				continue
 			}
 
+			subRepoRules.add(strings.TrimPrefix(depotMatch, "-"), true)
+
 			if strings.Contains(depotContains, wildcardMatchAll) ||
 				strings.Contains(depotContains, wildcardMatchDirectory) {
 				// Always include wildcard matches, because we don't know what they might
@@ -283,6 +293,7 @@ func (p *Provider) FetchUserPerms(ctx context.Context, account *extsvc.Account,
 				continue
 			}
 
+			subRepoRules.add(depotMatch, false)
 			includeContains = append(includeContains, extsvc.RepoID(depotContains))
 		}
 	}
@@ -295,12 +306,26 @@ func (p *Provider) FetchUserPerms(ctx context.Context, account *extsvc.Account,
 		excludeContains[i] = extsvc.RepoID(string(exclude) + wildcardMatchAll)
 	}
 
-	// As per interface definition for this method, implementation should return
-	// partial but valid results even when something went wrong.
-	return &authz.ExternalUserPermissions{
+	perms := &authz.ExternalUserPermissions{
 		IncludeContains: includeContains,
 		ExcludeContains: excludeContains,
-	}, errors.Wrap(scanner.Err(), "scanner.Err")
+	}
+
+	// Sub-repo permissions only narrow down the access granted above, they never
+	// grant access to a depot by themselves.
+	subRepoPerms := subRepoRules.permissions()
+	for _, depot := range p.depots {
+		if sp := subRepoPerms[depot]; sp != nil {
+			if perms.SubRepoPermissions == nil {
+				perms.SubRepoPermissions = make(map[extsvc.RepoID]*authz.SubRepoPermissions)
+			}
+			perms.SubRepoPermissions[depot] = sp
+		}
+	}
+
+	// As per interface definition for this method, implementation should return
+	// partial but valid results even when something went wrong.
+	return perms, errors.Wrap(scanner.Err(), "scanner.Err")
 }
 
 // getAllUserEmails returns a set of username <-> email pairs of all users in the Perforce server.
@@ -86,7 +86,7 @@ func TestProvider_FetchUserPerms(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("nil account", func(t *testing.T) {
-		p := NewProvider("", "ssl:111.222.333.444:1666", "admin", "password")
+		p := NewProvider("", "ssl:111.222.333.444:1666", "admin", "password", nil)
 		_, err := p.FetchUserPerms(ctx, nil, authz.FetchPermsOptions{})
 		want := "no account provided"
 		got := fmt.Sprintf("%v", err)
@@ -96,7 +96,7 @@ func TestProvider_FetchUserPerms(t *testing.T) {
 	})
 
 	t.Run("not the code host of the account", func(t *testing.T) {
-		p := NewProvider("", "ssl:111.222.333.444:1666", "admin", "password")
+		p := NewProvider("", "ssl:111.222.333.444:1666", "admin", "password", nil)
 		_, err := p.FetchUserPerms(context.Background(),
 			&extsvc.Account{
 				AccountSpec: extsvc.AccountSpec{
@@ -114,7 +114,7 @@ func TestProvider_FetchUserPerms(t *testing.T) {
 	})
 
 	t.Run("no user found in account data", func(t *testing.T) {
-		p := NewProvider("", "ssl:111.222.333.444:1666", "admin", "password")
+		p := NewProvider("", "ssl:111.222.333.444:1666", "admin", "password", nil)
 		_, err := p.FetchUserPerms(ctx,
 			&extsvc.Account{
 				AccountSpec: extsvc.AccountSpec{
@@ -144,6 +144,7 @@ func TestProvider_FetchUserPerms(t *testing.T) {
 
 	tests := []struct {
 		name      string
+		depots    []extsvc.RepoID
 		response  string
 		wantPerms *authz.ExternalUserPermissions
 	}{
@@ -221,6 +222,43 @@ open user alice * -//Sourcegraph/*/Handbook/...                      ## sub-matc
 				},
 			},
 		},
+		{
+			name: "sub-repo permissions",
+			depots: []extsvc.RepoID{
+				"//Sourcegraph/Engineering/",
+				"//Sourcegraph/Handbook/",
+				"//Sourcegraph/Security/",
+			},
+			response: `
+read user alice * //Sourcegraph/...
+read user alice * -//Sourcegraph/Engineering/.../secrets.yml
+read user alice * -//Sourcegraph/Engineering/Backend/...
+read user alice * //Sourcegraph/Engineering/Backend/*.md
+read user alice * -//Sourcegraph/Security/...
+`,
+			wantPerms: &authz.ExternalUserPermissions{
+				IncludeContains: []extsvc.RepoID{
+					"//Sourcegraph/%",
+					"//Sourcegraph/Engineering/Backend/[^/]+.md%",
+				},
+				ExcludeContains: []extsvc.RepoID{
+					"//Sourcegraph/Engineering/%/secrets.yml%",
+					"//Sourcegraph/Engineering/Backend/%",
+					"//Sourcegraph/Security/%",
+				},
+				SubRepoPermissions: map[extsvc.RepoID]*authz.SubRepoPermissions{
+					"//Sourcegraph/Engineering/": {
+						Paths: []string{
+							"**",
+							"-**/secrets.yml",
+							"-secrets.yml",
+							"-Backend/**",
+							"Backend/*.md",
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -229,6 +267,7 @@ open user alice * -//Sourcegraph/*/Handbook/...                      ## sub-matc
 			})
 
 			p := NewTestProvider("", "ssl:111.222.333.444:1666", "admin", "password", execer)
+			p.depots = test.depots
 			got, err := p.FetchUserPerms(ctx,
 				&extsvc.Account{
 					AccountSpec: extsvc.AccountSpec{
@@ -256,7 +295,7 @@ func TestProvider_FetchRepoPerms(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("nil repository", func(t *testing.T) {
-		p := NewProvider("", "ssl:111.222.333.444:1666", "admin", "password")
+		p := NewProvider("", "ssl:111.222.333.444:1666", "admin", "password", nil)
 		_, err := p.FetchRepoPerms(ctx, nil, authz.FetchPermsOptions{})
 		want := "no repository provided"
 		got := fmt.Sprintf("%v", err)
@@ -266,7 +305,7 @@ func TestProvider_FetchRepoPerms(t *testing.T) {
 	})
 
 	t.Run("not the code host of the repository", func(t *testing.T) {
-		p := NewProvider("", "ssl:111.222.333.444:1666", "admin", "password")
+		p := NewProvider("", "ssl:111.222.333.444:1666", "admin", "password", nil)
 		_, err := p.FetchRepoPerms(ctx,
 			&extsvc.Repository{
 				URI: "gitlab.com/user/repo",
@@ -395,7 +434,7 @@ func TestScanAllUsers(t *testing.T) {
 }
 
 func NewTestProvider(urn, host, user, password string, execer p4Execer) *Provider {
-	p := NewProvider(urn, host, user, password)
+	p := NewProvider(urn, host, user, password, nil)
 	p.p4Execer = execer
 	return p
 }
@@ -0,0 +1,204 @@
+package perforce
+
+import (
+	"strings"
+
+	"github.com/gobwas/glob"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+)
+
+// subRepoRules collects the rules of a protections table that apply to paths
+// within depots, in the order of the table.
+type subRepoRules struct {
+	depots []extsvc.RepoID
+	rules  map[extsvc.RepoID][]string
+}
+
+func newSubRepoRules(depots []extsvc.RepoID) *subRepoRules {
+	return &subRepoRules{
+		depots: depots,
+		rules:  make(map[extsvc.RepoID][]string, len(depots)),
+	}
+}
+
+// add adds the rule of a protections line with the given depot match, e.g.
+// //Sourcegraph/.../secret/..., to the rules of all depots it applies to.
+func (r *subRepoRules) add(depotMatch string, exclude bool) {
+	for _, depot := range r.depots {
+		paths, ok := relativePaths(depotMatch, string(depot))
+		if !ok {
+			// We can't tell which paths of the depot the rule applies to, so we
+			// only use it if revoking access to the entire depot is safe.
+			if !exclude {
+				continue
+			}
+			paths = []string{"**"}
+		}
+
+		for _, path := range paths {
+			if exclude {
+				path = "-" + path
+			}
+			r.rules[depot] = append(r.rules[depot], path)
+		}
+	}
+}
+
+// permissions returns the sub-repo permissions of every depot the rules grant
+// access to. The permissions of depots that can be read entirely are nil.
+func (r *subRepoRules) permissions() map[extsvc.RepoID]*authz.SubRepoPermissions {
+	perms := make(map[extsvc.RepoID]*authz.SubRepoPermissions, len(r.rules))
+	for depot, rules := range r.rules {
+		// Rules before revoking access to the entire depot have no effect.
+		for i := len(rules) - 1; i >= 0; i-- {
+			if rules[i] == "-**" {
+				rules = rules[i+1:]
+				break
+			}
+		}
+
+		var includes, excludes int
+		readAll := false
+		for _, rule := range rules {
+			if strings.HasPrefix(rule, "-") {
+				excludes++
+			} else {
+				includes++
+				readAll = readAll || rule == "**"
+			}
+		}
+		if includes == 0 {
+			continue
+		}
+		if readAll && excludes == 0 {
+			perms[depot] = nil
+			continue
+		}
+		perms[depot] = &authz.SubRepoPermissions{Paths: rules}
+	}
+	return perms
+}
+
+// relativePaths returns the glob patterns, relative to the root of the given
+// depot, of the files matched by a Perforce depot path pattern, e.g. "dir/**"
+// for //Sourcegraph/Engineering/dir/... in the depot //Sourcegraph/Engineering/.
+// It returns no patterns if the pattern doesn't match any file in the depot,
+// and false if it can't tell.
+func relativePaths(depotMatch, depot string) ([]string, bool) {
+	match := strings.Split(strings.TrimPrefix(depotMatch, "//"), "/")
+	root := strings.Split(strings.Trim(strings.TrimPrefix(depot, "//"), "/"), "/")
+
+	paths, ok := relativeTo(match, root)
+	if !ok {
+		return nil, false
+	}
+
+	// Wildcards can yield the same pattern more than once.
+	seen := make(map[string]struct{}, len(paths))
+	deduped := paths[:0]
+	for _, p := range paths {
+		if _, ok := seen[p]; !ok {
+			seen[p] = struct{}{}
+			deduped = append(deduped, p)
+		}
+	}
+	return deduped, true
+}
+
+func relativeTo(match, root []string) ([]string, bool) {
+	if len(root) == 0 {
+		return belowRoot(match), true
+	}
+	if len(match) == 0 {
+		return nil, true
+	}
+
+	switch segment := match[0]; {
+	case segment == "...":
+		// "..." matches any number of directories, so it can end at any level
+		// of the depot root, or continue below it.
+		var paths []string
+		if len(match) == 1 {
+			paths = append(paths, "**")
+		}
+		for _, p := range belowRoot(match[1:]) {
+			paths = append(paths, "**/"+p)
+		}
+		for i := range root {
+			ps, ok := relativeTo(match[1:], root[i:])
+			if !ok {
+				return nil, false
+			}
+			paths = append(paths, ps...)
+		}
+		return append(paths, belowRoot(match[1:])...), true
+
+	case strings.Contains(segment, "..."):
+		// A segment like "Eng..." can end anywhere below the depot root, unless
+		// it doesn't even match the start of the depot root.
+		prefix := segment[:strings.Index(segment, "...")]
+		g, err := glob.Compile(toGlob(prefix)+"**", '/')
+		if err == nil && !g.Match(root[0]) {
+			return nil, true
+		}
+		return nil, false
+
+	default:
+		g, err := glob.Compile(toGlob(segment), '/')
+		if err != nil {
+			return nil, false
+		}
+		if !g.Match(root[0]) {
+			return nil, true
+		}
+		return relativeTo(match[1:], root[1:])
+	}
+}
+
+// belowRoot returns the glob patterns of the path segments of a pattern below
+// the depot root. A "..." segment followed by more segments can also match no
+// directories at all, which "**/" doesn't.
+func belowRoot(match []string) []string {
+	if len(match) == 0 || (len(match) == 1 && match[0] == "") {
+		// The pattern matches the depot directory itself but no files.
+		return nil
+	}
+	if len(match) == 1 {
+		return []string{toGlob(match[0])}
+	}
+
+	rest := belowRoot(match[1:])
+	paths := make([]string, 0, 2*len(rest))
+	for _, p := range rest {
+		paths = append(paths, toGlob(match[0])+"/"+p)
+	}
+	if match[0] == "..." {
+		paths = append(paths, rest...)
+	}
+	return paths
+}
+
+// toGlob converts a Perforce path pattern into a glob pattern: "..." becomes
+// "**", and "*" and the positional wildcards "%%1" to "%%9" become "*".
+func toGlob(pattern string) string {
+	var b strings.Builder
+	for i := 0; i < len(pattern); {
+		switch {
+		case strings.HasPrefix(pattern[i:], "..."):
+			b.WriteString("**")
+			i += 3
+		case pattern[i] == '*':
+			b.WriteByte('*')
+			i++
+		case strings.HasPrefix(pattern[i:], "%%") && i+2 < len(pattern) && pattern[i+2] >= '1' && pattern[i+2] <= '9':
+			b.WriteByte('*')
+			i += 3
+		default:
+			b.WriteString(glob.QuoteMeta(pattern[i : i+1]))
+			i++
+		}
+	}
+	return b.String()
+}
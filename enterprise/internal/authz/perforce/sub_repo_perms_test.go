@@ -0,0 +1,118 @@
+package perforce
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+)
+
+func TestRelativePaths(t *testing.T) {
+	const depot = "//Sourcegraph/Engineering/"
+
+	tests := []struct {
+		depotMatch string
+		wantPaths  []string
+		wantOK     bool
+	}{
+		{depotMatch: "//Sourcegraph/Engineering/...", wantPaths: []string{"**"}, wantOK: true},
+		{depotMatch: "//Sourcegraph/Engineering/Backend/...", wantPaths: []string{"Backend/**"}, wantOK: true},
+		{depotMatch: "//Sourcegraph/Engineering/*.md", wantPaths: []string{"*.md"}, wantOK: true},
+		{depotMatch: "//Sourcegraph/Engineering/%%1/README", wantPaths: []string{"*/README"}, wantOK: true},
+		{depotMatch: "//Sourcegraph/...", wantPaths: []string{"**"}, wantOK: true},
+		{depotMatch: "//Sourcegraph/*/Backend/...", wantPaths: []string{"Backend/**"}, wantOK: true},
+		{depotMatch: "//Sourcegraph/.../secrets.yml", wantPaths: []string{"**/secrets.yml", "secrets.yml"}, wantOK: true},
+		{depotMatch: "//Sourcegraph/Engineering/.../test/*.go", wantPaths: []string{"**/test/*.go", "test/*.go"}, wantOK: true},
+		{depotMatch: "//Sourcegraph/Engineering/[a]/...", wantPaths: []string{"\\[a\\]/**"}, wantOK: true},
+		{depotMatch: "//Sourcegraph/Security/...", wantOK: true},
+		{depotMatch: "//Sourcegraph/Security...", wantOK: true},
+		{depotMatch: "//Sourcegraph/Eng.../Backend/...", wantOK: false},
+	}
+	for _, test := range tests {
+		t.Run(test.depotMatch, func(t *testing.T) {
+			paths, ok := relativePaths(test.depotMatch, depot)
+			if ok != test.wantOK {
+				t.Fatalf("ok: want %v but got %v", test.wantOK, ok)
+			}
+			if diff := cmp.Diff(test.wantPaths, paths); diff != "" {
+				t.Fatalf("Mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSubRepoRules_Permissions(t *testing.T) {
+	depot := extsvc.RepoID("//Sourcegraph/Engineering/")
+
+	type rule struct {
+		depotMatch string
+		exclude    bool
+	}
+	tests := []struct {
+		name      string
+		rules     []rule
+		wantPerms map[extsvc.RepoID]*authz.SubRepoPermissions
+	}{
+		{
+			name:      "no rules",
+			wantPerms: map[extsvc.RepoID]*authz.SubRepoPermissions{},
+		},
+		{
+			name: "entire depot",
+			rules: []rule{
+				{depotMatch: "//Sourcegraph/..."},
+			},
+			wantPerms: map[extsvc.RepoID]*authz.SubRepoPermissions{depot: nil},
+		},
+		{
+			name: "excluded after include",
+			rules: []rule{
+				{depotMatch: "//Sourcegraph/Engineering/..."},
+				{depotMatch: "//Sourcegraph/Engineering/...", exclude: true},
+			},
+			wantPerms: map[extsvc.RepoID]*authz.SubRepoPermissions{},
+		},
+		{
+			name: "rules before excluding entire depot are dropped",
+			rules: []rule{
+				{depotMatch: "//Sourcegraph/Engineering/Frontend/..."},
+				{depotMatch: "//Sourcegraph/...", exclude: true},
+				{depotMatch: "//Sourcegraph/Engineering/Backend/..."},
+			},
+			wantPerms: map[extsvc.RepoID]*authz.SubRepoPermissions{
+				depot: {Paths: []string{"Backend/**"}},
+			},
+		},
+		{
+			name: "ambiguous exclude revokes entire depot",
+			rules: []rule{
+				{depotMatch: "//Sourcegraph/Engineering/..."},
+				{depotMatch: "//Sourcegraph/Eng.../secrets.yml", exclude: true},
+			},
+			wantPerms: map[extsvc.RepoID]*authz.SubRepoPermissions{},
+		},
+		{
+			name: "ambiguous include is ignored",
+			rules: []rule{
+				{depotMatch: "//Sourcegraph/Eng.../Backend/..."},
+				{depotMatch: "//Sourcegraph/Engineering/Frontend/..."},
+			},
+			wantPerms: map[extsvc.RepoID]*authz.SubRepoPermissions{
+				depot: {Paths: []string{"Frontend/**"}},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := newSubRepoRules([]extsvc.RepoID{depot})
+			for _, rule := range test.rules {
+				r.add(rule.depotMatch, rule.exclude)
+			}
+			if diff := cmp.Diff(test.wantPerms, r.permissions()); diff != "" {
+				t.Fatalf("Mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -27,6 +27,9 @@ func Routines(ctx context.Context, batchesStore *store.Store, cf *httpcli.Factor
 		newReconcilerWorkerResetter(reconcilerWorkerStore, metrics),
 
 		newSpecExpireJob(ctx, batchesStore),
+		newCredentialExpireJob(ctx, batchesStore),
+		newChangesetRebaser(ctx, batchesStore),
+		newChangesetArchiver(ctx, batchesStore),
 
 		scheduler.NewScheduler(ctx, batchesStore),
 
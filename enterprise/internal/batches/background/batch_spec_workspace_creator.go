@@ -57,7 +57,9 @@ func (r *batchSpecWorkspaceCreator) process(
 
 	resolver := newResolver(tx)
 	userCtx := actor.WithActor(ctx, actor.FromUser(spec.UserID))
-	workspaces, err := resolver.ResolveWorkspacesForBatchSpec(userCtx, evaluatableSpec)
+	workspaces, err := resolver.ResolveWorkspacesForBatchSpec(userCtx, evaluatableSpec, service.ResolveWorkspacesForBatchSpecOpts{
+		NoCache: spec.NoCache,
+	})
 	if err != nil {
 		return err
 	}
@@ -178,6 +178,6 @@ func (d *dummyWorkspaceResolver) DummyBuilder(s *store.Store) service.WorkspaceR
 	return d
 }
 
-func (d *dummyWorkspaceResolver) ResolveWorkspacesForBatchSpec(context.Context, *batcheslib.BatchSpec) ([]*service.RepoWorkspace, error) {
+func (d *dummyWorkspaceResolver) ResolveWorkspacesForBatchSpec(context.Context, *batcheslib.BatchSpec, service.ResolveWorkspacesForBatchSpecOpts) ([]*service.RepoWorkspace, error) {
 	return d.workspaces, d.err
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/cockroachdb/errors"
 	"github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/inconshreveable/log15"
 	"github.com/keegancsmith/sqlf"
 	"github.com/lib/pq"
 
@@ -126,7 +127,14 @@ func deleteAccessToken(ctx context.Context, batchesStore *store.Store, tokenID i
 
 type markFinal func(ctx context.Context, tx dbworkerstore.Store) (_ bool, err error)
 
-func (s *batchSpecWorkspaceExecutionWorkerStore) deleteAccessTokenAndMarkFinal(ctx context.Context, id int, failureMessage string, options dbworkerstore.MarkFinalOptions, fn markFinal) (_ bool, err error) {
+func (s *batchSpecWorkspaceExecutionWorkerStore) deleteAccessTokenAndMarkFinal(ctx context.Context, id int, failureMessage string, options dbworkerstore.MarkFinalOptions, fn markFinal) (ok bool, err error) {
+	// Registered first, so that it runs after the transaction is done.
+	defer func() {
+		if err == nil && ok {
+			s.notifyIfBatchSpecExecutionFinished(ctx, id)
+		}
+	}()
+
 	batchesStore := store.New(s.Store.Handle().DB(), s.observationContext, nil)
 	tx, err := batchesStore.Transact(ctx)
 	if err != nil {
@@ -157,7 +165,13 @@ func (s *batchSpecWorkspaceExecutionWorkerStore) MarkFailed(ctx context.Context,
 	})
 }
 
-func (s *batchSpecWorkspaceExecutionWorkerStore) MarkComplete(ctx context.Context, id int, options dbworkerstore.MarkFinalOptions) (_ bool, err error) {
+func (s *batchSpecWorkspaceExecutionWorkerStore) MarkComplete(ctx context.Context, id int, options dbworkerstore.MarkFinalOptions) (ok bool, err error) {
+	defer func() {
+		if err == nil && ok {
+			s.notifyIfBatchSpecExecutionFinished(ctx, id)
+		}
+	}()
+
 	batchesStore := store.New(s.Store.Handle().DB(), s.observationContext, nil)
 
 	tx, err := batchesStore.Transact(ctx)
@@ -186,10 +200,78 @@ func (s *batchSpecWorkspaceExecutionWorkerStore) MarkComplete(ctx context.Contex
 		return false, tx.Done(err)
 	}
 
-	ok, err := s.Store.With(tx).MarkComplete(ctx, id, options)
+	ok, err = s.Store.With(tx).MarkComplete(ctx, id, options)
 	return ok, tx.Done(err)
 }
 
+// notifyIfBatchSpecExecutionFinished notifies the creator of the batch spec that the
+// job with the given ID belongs to, if the job was the last of its execution to
+// finish. Errors are only logged, since they must not fail the job.
+func (s *batchSpecWorkspaceExecutionWorkerStore) notifyIfBatchSpecExecutionFinished(ctx context.Context, id int) {
+	batchesStore := store.New(s.Store.Handle().DB(), s.observationContext, nil)
+	if err := notifyIfBatchSpecExecutionFinished(ctx, batchesStore, int64(id)); err != nil {
+		log15.Warn("Failed to notify about finished batch spec execution", "job", id, "err", err)
+	}
+}
+
+func notifyIfBatchSpecExecutionFinished(ctx context.Context, s *store.Store, jobID int64) error {
+	job, err := s.GetBatchSpecWorkspaceExecutionJob(ctx, store.GetBatchSpecWorkspaceExecutionJobOpts{ID: jobID})
+	if err != nil {
+		return err
+	}
+	workspace, err := s.GetBatchSpecWorkspace(ctx, store.GetBatchSpecWorkspaceOpts{ID: job.BatchSpecWorkspaceID})
+	if err != nil {
+		return err
+	}
+	spec, err := s.GetBatchSpec(ctx, store.GetBatchSpecOpts{ID: workspace.BatchSpecID})
+	if err != nil {
+		return err
+	}
+	if spec.UserID == 0 {
+		// The creator of the batch spec has been deleted.
+		return nil
+	}
+	stats, err := s.GetBatchSpecStats(ctx, []int64{spec.ID})
+	if err != nil {
+		return err
+	}
+
+	var title string
+	switch btypes.ComputeBatchSpecState(spec, stats[spec.ID]) {
+	case btypes.BatchSpecStateCompleted:
+		title = fmt.Sprintf("Batch spec %q finished executing", spec.Spec.Name)
+	case btypes.BatchSpecStateFailed:
+		title = fmt.Sprintf("Batch spec %q failed to execute", spec.Spec.Name)
+	default:
+		// The execution is still running, or it was canceled by the user.
+		return nil
+	}
+
+	var namespaceURL string
+	if spec.NamespaceOrgID != 0 {
+		org, err := database.OrgsWith(s).GetByID(ctx, spec.NamespaceOrgID)
+		if err != nil {
+			return err
+		}
+		namespaceURL = "/organizations/" + org.Name
+	} else {
+		user, err := database.UsersWith(s).GetByID(ctx, spec.NamespaceUserID)
+		if err != nil {
+			return err
+		}
+		namespaceURL = "/users/" + user.Username
+	}
+
+	return database.NotificationsWith(s).Create(ctx, &database.Notification{
+		UserID:    spec.UserID,
+		Kind:      database.NotificationKindBatchSpecExecutionFinished,
+		Title:     title,
+		Body:      fmt.Sprintf("%d of %d workspaces completed.", stats[spec.ID].Completed, stats[spec.ID].Executions),
+		URL:       fmt.Sprintf("%s/batch-changes/executions/%s", namespaceURL, relay.MarshalID("BatchSpec", spec.RandID)),
+		DedupeKey: "batch-spec-execution:" + spec.RandID,
+	})
+}
+
 const setChangesetSpecIDsOnBatchSpecWorkspace = `
 UPDATE batch_spec_workspaces SET changeset_spec_ids = %s WHERE id = %s
 `
@@ -251,18 +333,7 @@ func loadAndExtractChangesetSpecIDs(ctx context.Context, s *store.Store, id int6
 var ErrNoChangesetSpecIDs = errors.New("no changeset ids found in execution logs")
 
 func extractChangesetSpecRandIDs(logs []workerutil.ExecutionLogEntry) ([]string, error) {
-	var (
-		entry workerutil.ExecutionLogEntry
-		found bool
-	)
-
-	for _, e := range logs {
-		if e.Key == "step.src.0" {
-			entry = e
-			found = true
-			break
-		}
-	}
+	entry, found := btypes.FindSrcExecLogEntry(logs)
 	if !found {
 		return nil, ErrNoChangesetSpecIDs
 	}
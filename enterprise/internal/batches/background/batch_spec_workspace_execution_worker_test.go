@@ -206,6 +206,19 @@ stdout: {"operation":"UPLOADING_CHANGESET_SPECS","timestamp":"2021-09-09T13:20:3
 			// Run `echo "QmF0Y2hTcGVjOiJBZFBMTDU5SXJmWCI=" | base64 -d` to get this
 			wantRandIDs: []string{"6LHacyvB7X6"},
 		},
+		{
+			name: "keyed step",
+			entries: []workerutil.ExecutionLogEntry{
+				{Key: "setup.firecracker.start"},
+				{
+					Key: "step.src.batch-exec",
+					Out: `
+stdout: {"operation":"UPLOADING_CHANGESET_SPECS","timestamp":"2021-09-09T13:20:32.95Z","status":"SUCCESS","metadata":{"ids":["Q2hhbmdlc2V0U3BlYzoiNkxIYWN5dkI3WDYi"]}}
+`,
+				},
+			},
+			wantRandIDs: []string{"6LHacyvB7X6"},
+		},
 		{
 
 			name:    "no step.src.0 log entry",
@@ -0,0 +1,57 @@
+package background
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/usagestats"
+)
+
+const (
+	changesetArchiverInterval  = 1 * time.Hour
+	changesetArchiverBatchSize = 500
+)
+
+func newChangesetArchiver(ctx context.Context, cstore *store.Store) goroutine.BackgroundRoutine {
+	return goroutine.NewPeriodicGoroutine(
+		ctx,
+		changesetArchiverInterval,
+		goroutine.NewHandlerWithErrorMessage("archive stale batch changes changesets", func(ctx context.Context) error {
+			return archiveStaleChangesets(ctx, cstore)
+		}),
+	)
+}
+
+type changesetsAutoArchivedEventArg struct {
+	BatchChangeID   int64 `json:"batch_change_id"`
+	ChangesetsCount int   `json:"changesets_count"`
+}
+
+// archiveStaleChangesets archives the merged and closed changesets of batch
+// changes with automatic archival enabled and logs an event for every batch
+// change that had changesets archived.
+func archiveStaleChangesets(ctx context.Context, cstore *store.Store) error {
+	archived, err := cstore.ArchiveStaleChangesets(ctx, changesetArchiverBatchSize)
+	if err != nil {
+		return errors.Wrap(err, "ArchiveStaleChangesets")
+	}
+
+	for batchChangeID, count := range archived {
+		arg, err := json.Marshal(changesetsAutoArchivedEventArg{BatchChangeID: batchChangeID, ChangesetsCount: count})
+		if err != nil {
+			return err
+		}
+		// The changesets are archived by Sourcegraph rather than a user, so
+		// the event is logged without a user ID.
+		if err := usagestats.LogBackendEvent(cstore.DB(), 0, "", "BatchChangeChangesetsAutoArchived", arg, arg, nil, nil); err != nil {
+			log15.Warn("Failed to log auto archived changesets", "batchChange", batchChangeID, "err", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package background
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+const (
+	changesetRebaserInterval  = 5 * time.Minute
+	changesetRebaserBatchSize = 500
+)
+
+func newChangesetRebaser(ctx context.Context, cstore *store.Store) goroutine.BackgroundRoutine {
+	// cursor is the ID of the last changeset checked, so that every run
+	// checks the next batch of changesets instead of the same first ones.
+	var cursor int64
+	return goroutine.NewPeriodicGoroutine(
+		ctx,
+		changesetRebaserInterval,
+		goroutine.NewHandlerWithErrorMessage("rebase batch changes changesets", func(ctx context.Context) (err error) {
+			cursor, err = enqueueChangesetsToRebase(ctx, cstore, cursor)
+			return err
+		}),
+	)
+}
+
+// enqueueChangesetsToRebase looks at the open changesets of batch changes that
+// have auto rebase enabled and enqueues those whose base branch advanced past
+// the revision their commit was created on. Only changesets with an ID
+// greater than cursor are checked. It returns the cursor for the next run,
+// which is 0 once the last batch of changesets has been checked.
+func enqueueChangesetsToRebase(ctx context.Context, cstore *store.Store, cursor int64) (int64, error) {
+	cs, err := cstore.ListChangesetsToRebase(ctx, cursor, changesetRebaserBatchSize)
+	if err != nil {
+		return cursor, errors.Wrap(err, "ListChangesetsToRebase")
+	}
+
+	for _, ch := range cs {
+		if err := maybeEnqueueChangesetToRebase(ctx, cstore, ch); err != nil {
+			log15.Warn("Failed to check changeset for rebase", "changeset", ch.ID, "err", err)
+		}
+	}
+
+	if len(cs) < changesetRebaserBatchSize {
+		return 0, nil
+	}
+	return cs[len(cs)-1].ID, nil
+}
+
+func maybeEnqueueChangesetToRebase(ctx context.Context, cstore *store.Store, ch *btypes.Changeset) error {
+	spec, err := cstore.GetChangesetSpecByID(ctx, ch.CurrentSpecID)
+	if err != nil {
+		return errors.Wrap(err, "loading changeset spec")
+	}
+
+	repo, err := cstore.Repos().Get(ctx, ch.RepoID)
+	if err != nil {
+		return errors.Wrap(err, "loading repository")
+	}
+
+	head, err := git.ResolveRevision(ctx, repo.Name, spec.Spec.BaseRef, git.ResolveRevisionOptions{})
+	if err != nil {
+		return errors.Wrap(err, "resolving base ref")
+	}
+
+	base := ch.RebasedOnto
+	if base == "" {
+		base = spec.Spec.BaseRev
+	}
+	if string(head) == base {
+		return nil
+	}
+
+	_, err = cstore.EnqueueChangesetToRebase(ctx, ch.ID, string(head))
+	return err
+}
@@ -0,0 +1,28 @@
+package background
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+const credentialExpireInterval = 10 * time.Minute
+
+// newCredentialExpireJob removes the user credentials replaced by a rotation
+// once their overlap window has expired.
+func newCredentialExpireJob(ctx context.Context, cstore *store.Store) goroutine.BackgroundRoutine {
+	return goroutine.NewPeriodicGoroutine(
+		ctx,
+		credentialExpireInterval,
+		goroutine.NewHandlerWithErrorMessage("expire rotated user credentials", func(ctx context.Context) error {
+			if err := cstore.UserCredentials().DeleteExpiredPreviousCredentials(ctx); err != nil {
+				return errors.Wrap(err, "DeleteExpiredPreviousCredentials")
+			}
+			return nil
+		}),
+	)
+}
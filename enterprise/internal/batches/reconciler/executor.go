@@ -9,14 +9,17 @@ import (
 
 	"github.com/cockroachdb/errors"
 	"github.com/inconshreveable/log15"
+	"github.com/sourcegraph/go-diff/diff"
 
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/state"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeowners"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 )
@@ -75,6 +78,9 @@ func (e *executor) Run(ctx context.Context, plan *Plan) (err error) {
 		case btypes.ReconcilerOperationPush:
 			err = e.pushChangesetPatch(ctx)
 
+		case btypes.ReconcilerOperationRebase:
+			err = e.rebaseChangeset(ctx)
+
 		case btypes.ReconcilerOperationPublish:
 			err = e.publishChangeset(ctx, false)
 
@@ -154,7 +160,44 @@ func (e *executor) pushChangesetPatch(ctx context.Context) (err error) {
 	if err != nil {
 		return err
 	}
-	return e.pushCommit(ctx, opts)
+	if err := e.pushCommit(ctx, opts); err != nil {
+		return err
+	}
+
+	// The commit has been created on top of the base revision of the spec,
+	// so any pending rebase is obsolete.
+	e.ch.Rebasing = false
+	e.ch.RebasedOnto = ""
+	return nil
+}
+
+// rebaseChangeset reapplies the diff of the changeset spec onto the base
+// revision recorded in RebasedOnto and force-pushes the resulting commit.
+func (e *executor) rebaseChangeset(ctx context.Context) (err error) {
+	if e.ch.RebasedOnto == "" {
+		return errors.New("changeset has no revision to rebase onto")
+	}
+
+	pushConf, err := e.css.GitserverPushConfig(ctx, e.tx.ExternalServices(), e.repo)
+	if err != nil {
+		return err
+	}
+	opts, err := buildCommitOpts(e.repo, e.spec, pushConf)
+	if err != nil {
+		return err
+	}
+	opts.BaseCommit = api.CommitID(e.ch.RebasedOnto)
+
+	if _, err := e.gitserverClient.CreateCommitFromPatch(ctx, opts); err != nil {
+		var cerr *protocol.CreateCommitFromPatchError
+		if errors.As(err, &cerr) {
+			return errRebaseConflict{baseRev: e.ch.RebasedOnto, output: strings.TrimSpace(cerr.CombinedOutput)}
+		}
+		return err
+	}
+
+	e.ch.Rebasing = false
+	return nil
 }
 
 // publishChangeset creates the given changeset on its code host.
@@ -174,6 +217,27 @@ func (e *executor) publishChangeset(ctx context.Context, asDraft bool) (err erro
 		return errors.Wrapf(err, "decorating body for changeset %d", e.ch.ID)
 	}
 
+	// GitLab merge requests can have reviewers and approval rules set from the
+	// changeset template of the batch spec.
+	if e.repo.ExternalRepo.ServiceType == extsvc.TypeGitLab && e.spec.BatchSpecID != 0 {
+		batchSpec, err := e.tx.GetBatchSpec(ctx, store.GetBatchSpecOpts{ID: e.spec.BatchSpecID})
+		if err != nil {
+			return errors.Wrapf(err, "loading batch spec %d", e.spec.BatchSpecID)
+		}
+		if batchSpec.Spec.ChangesetTemplate != nil {
+			cs.GitLab = batchSpec.Spec.ChangesetTemplate.GitLab
+		}
+		if cs.GitLab != nil && cs.GitLab.ReviewersFromCodeOwners {
+			reviewers, err := e.codeOwnerReviewers(ctx)
+			if err != nil {
+				return errors.Wrap(err, "resolving code owners")
+			}
+			gitlab := *cs.GitLab
+			gitlab.Reviewers = mergeReviewers(gitlab.Reviewers, reviewers)
+			cs.GitLab = &gitlab
+		}
+	}
+
 	var exists bool
 	if asDraft {
 		// If the changeset shall be published in draft mode, make sure the changeset source implements DraftChangesetSource.
@@ -464,6 +528,76 @@ type getBatchChanger interface {
 	GetBatchChange(ctx context.Context, opts store.GetBatchChangeOpts) (*btypes.BatchChange, error)
 }
 
+// codeOwnerReviewers returns the usernames of the code owners of the files changed by
+// the changeset spec, resolved from the ownership rules of the repository. Teams and
+// email addresses can't be requested to review, so they are skipped.
+func (e *executor) codeOwnerReviewers(ctx context.Context) ([]string, error) {
+	d, err := e.spec.Spec.Diff()
+	if err != nil {
+		return nil, err
+	}
+	paths, err := changedPaths(d)
+	if err != nil {
+		return nil, err
+	}
+
+	svc := codeowners.NewService(e.tx.DB())
+	repo := types.RepoName{ID: e.repo.ID, Name: e.repo.Name}
+	var usernames []string
+	for _, path := range paths {
+		owners, err := svc.FileOwners(ctx, repo, path)
+		if err != nil {
+			return nil, err
+		}
+		for _, owner := range owners {
+			if strings.HasPrefix(owner, "@") && !strings.Contains(owner, "/") {
+				usernames = append(usernames, strings.TrimPrefix(owner, "@"))
+			}
+		}
+	}
+	return usernames, nil
+}
+
+// changedPaths returns the paths of the files changed by the diff. Renamed files are
+// returned with both their old and new path.
+func changedPaths(d string) ([]string, error) {
+	fileDiffs, err := diff.ParseMultiFileDiff([]byte(d))
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, fd := range fileDiffs {
+		for _, name := range []string{fd.OrigName, fd.NewName} {
+			if name == "/dev/null" {
+				continue
+			}
+			name = strings.TrimPrefix(strings.TrimPrefix(name, "a/"), "b/")
+			if len(paths) == 0 || paths[len(paths)-1] != name {
+				paths = append(paths, name)
+			}
+		}
+	}
+	return paths, nil
+}
+
+// mergeReviewers returns the reviewers followed by the additional reviewers that are
+// not among them.
+func mergeReviewers(reviewers, additional []string) []string {
+	merged := append([]string{}, reviewers...)
+	seen := make(map[string]struct{}, len(reviewers))
+	for _, r := range reviewers {
+		seen[r] = struct{}{}
+	}
+	for _, r := range additional {
+		if _, ok := seen[r]; ok {
+			continue
+		}
+		seen[r] = struct{}{}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
 func loadBatchChange(ctx context.Context, tx getBatchChanger, id int64) (*btypes.BatchChange, error) {
 	if id == 0 {
 		return nil, errors.New("changeset has no owning batch change")
@@ -557,6 +691,26 @@ func (e errPublishSameBranch) Error() string {
 
 func (e errPublishSameBranch) NonRetryable() bool { return true }
 
+// errRebaseConflict is returned by rebase changeset if the diff of the
+// changeset spec cannot be applied onto the new base revision.
+// It is a terminal error: the changeset needs to be updated by applying a new
+// batch spec.
+type errRebaseConflict struct {
+	baseRev string
+	output  string
+}
+
+func (e errRebaseConflict) Error() string {
+	return fmt.Sprintf(
+		"cannot rebase changeset onto %s because of conflicts, apply an updated batch spec to resolve them:\n"+
+			"```\n"+
+			"%s\n"+
+			"```",
+		e.baseRev, e.output)
+}
+
+func (e errRebaseConflict) NonRetryable() bool { return true }
+
 // errNoSSHCredential is returned, if the  clone URL of the repository uses the
 // ssh:// scheme, but the authenticator doesn't support SSH pushes.
 type errNoSSHCredential struct{}
@@ -1089,6 +1089,49 @@ func TestNamespaceURL(t *testing.T) {
 	}
 }
 
+func TestChangedPaths(t *testing.T) {
+	t.Parallel()
+
+	d := `diff --git a/README.md b/README.md
+index 671e50a..851b23a 100644
+--- a/README.md
++++ b/README.md
+@@ -1 +1 @@
+-# README
++# Readme
+diff --git a/old.go b/old.go
+deleted file mode 100644
+index 671e50a..0000000
+--- a/old.go
++++ /dev/null
+@@ -1 +0,0 @@
+-package old
+diff --git a/cmd/new.go b/cmd/new.go
+new file mode 100644
+index 0000000..671e50a
+--- /dev/null
++++ b/cmd/new.go
+@@ -0,0 +1 @@
++package cmd
+`
+	have, err := changedPaths(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"README.md", "old.go", "cmd/new.go"}, have); diff != "" {
+		t.Errorf("unexpected paths (-want +have):\n%s", diff)
+	}
+}
+
+func TestMergeReviewers(t *testing.T) {
+	t.Parallel()
+
+	have := mergeReviewers([]string{"alice", "bob"}, []string{"carol", "alice", "carol"})
+	if diff := cmp.Diff([]string{"alice", "bob", "carol"}, have); diff != "" {
+		t.Errorf("unexpected reviewers (-want +have):\n%s", diff)
+	}
+}
+
 type mockInternalClient struct {
 	externalURL string
 	err         error
@@ -12,6 +12,7 @@ import (
 
 var operationPrecedence = map[btypes.ReconcilerOperation]int{
 	btypes.ReconcilerOperationPush:         0,
+	btypes.ReconcilerOperationRebase:       0,
 	btypes.ReconcilerOperationDetach:       0,
 	btypes.ReconcilerOperationArchive:      0,
 	btypes.ReconcilerOperationImport:       1,
@@ -235,6 +236,15 @@ func DeterminePlan(previousSpec, currentSpec *btypes.ChangesetSpec, ch *btypes.C
 			}
 		}
 
+		// If the base branch of the changeset advanced and auto rebase is
+		// enabled, we reapply the diff onto the new base. A new commit that is
+		// pushed anyway already takes care of that.
+		if ch.Rebasing && !delta.NeedCommitUpdate() {
+			pl.AddOp(btypes.ReconcilerOperationRebase)
+			pl.AddOp(btypes.ReconcilerOperationSleep)
+			pl.AddOp(btypes.ReconcilerOperationSync)
+		}
+
 	default:
 		return pl, errors.Errorf("unknown changeset publication state: %s", ch.PublicationState)
 	}
@@ -288,6 +298,12 @@ func compareChangesetSpecs(previous, current *btypes.ChangesetSpec, uiPublicatio
 		delta.Undraft = true
 	}
 
+	// Specs with the same diff digest push the same commits, so we don't need
+	// to compare the commits of the specs.
+	if previous.DiffDigest != "" && previous.DiffDigest == current.DiffDigest {
+		return delta, nil
+	}
+
 	// Diff
 	currentDiff, err := current.Spec.Diff()
 	if err != nil {
@@ -393,6 +393,37 @@ func TestDetermineReconcilerPlan(t *testing.T) {
 				btypes.ReconcilerOperationDetach,
 				btypes.ReconcilerOperationImport,
 			},
+		}, {
+			name:         "rebasing",
+			previousSpec: &ct.TestSpecOpts{Published: true},
+			currentSpec:  &ct.TestSpecOpts{Published: true},
+			changeset: ct.TestChangesetOpts{
+				PublicationState: btypes.ChangesetPublicationStatePublished,
+				ExternalState:    btypes.ChangesetExternalStateOpen,
+				Rebasing:         true,
+				RebasedOnto:      "d34db33f",
+			},
+			wantOperations: Operations{
+				btypes.ReconcilerOperationRebase,
+				btypes.ReconcilerOperationSleep,
+				btypes.ReconcilerOperationSync,
+			},
+		},
+		{
+			name:         "rebasing with commit diff changed",
+			previousSpec: &ct.TestSpecOpts{Published: true},
+			currentSpec:  &ct.TestSpecOpts{Published: true, CommitDiff: "new diff"},
+			changeset: ct.TestChangesetOpts{
+				PublicationState: btypes.ChangesetPublicationStatePublished,
+				ExternalState:    btypes.ChangesetExternalStateOpen,
+				Rebasing:         true,
+				RebasedOnto:      "d34db33f",
+			},
+			wantOperations: Operations{
+				btypes.ReconcilerOperationPush,
+				btypes.ReconcilerOperationSleep,
+				btypes.ReconcilerOperationSync,
+			},
 		},
 	}
 
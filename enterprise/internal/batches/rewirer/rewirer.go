@@ -72,7 +72,11 @@ func (r *ChangesetRewirer) Rewire() (changesets []*btypes.Changeset, err error)
 			if spec.Spec.IsImportingExisting() {
 				r.attachTrackingChangeset(changeset)
 			} else if spec.Spec.IsBranch() {
-				r.updateChangesetToNewSpec(changeset, spec)
+				if m.Identical && r.canReuseChangeset(changeset) {
+					r.reuseChangeset(changeset, spec)
+				} else {
+					r.updateChangesetToNewSpec(changeset, spec)
+				}
 			}
 		} else {
 			if spec.Spec.IsImportingExisting() {
@@ -122,6 +126,35 @@ func (r *ChangesetRewirer) updateChangesetToNewSpec(c *btypes.Changeset, spec *b
 	c.ResetReconcilerState(global.DefaultReconcilerEnqueueState())
 }
 
+// canReuseChangeset returns whether the changeset can be reused as-is for a
+// new changeset spec identical to its current spec: it must be open on the
+// code host, attached to the batch change without being detached or archived,
+// and the previous spec must have been applied successfully.
+func (r *ChangesetRewirer) canReuseChangeset(c *btypes.Changeset) bool {
+	if c.ReconcilerState != btypes.ReconcilerStateCompleted || c.Closing || !c.Published() {
+		return false
+	}
+	if c.ExternalState != btypes.ChangesetExternalStateOpen && c.ExternalState != btypes.ChangesetExternalStateDraft {
+		return false
+	}
+
+	for _, assoc := range c.BatchChanges {
+		if assoc.BatchChangeID == r.batchChangeID {
+			return !assoc.Detach && !assoc.Archive && !assoc.IsArchived
+		}
+	}
+	return false
+}
+
+// reuseChangeset points the changeset to the given spec, which is identical
+// to its current spec. Nothing needs to change on the code host, so the
+// changeset is not enqueued for the reconciler: it keeps its pull request
+// as-is, instead of being updated or closed and reopened.
+func (r *ChangesetRewirer) reuseChangeset(c *btypes.Changeset, spec *btypes.ChangesetSpec) {
+	c.SetCurrentSpec(spec)
+	c.PreviousSpecID = spec.ID
+}
+
 func (r *ChangesetRewirer) createTrackingChangeset(repo *types.Repo, externalID string) *btypes.Changeset {
 	newChangeset := &btypes.Changeset{
 		RepoID:              repo.ID,
@@ -26,7 +26,7 @@ func TestRewirer_Rewire(t *testing.T) {
 	unsupportedTestRepo := &types.Repo{
 		ID: unsupportedTestRepoID,
 		ExternalRepo: api.ExternalRepoSpec{
-			ServiceType: extsvc.TypeBitbucketCloud,
+			ServiceType: extsvc.TypeAWSCodeCommit,
 		},
 	}
 	testCases := []struct {
@@ -392,6 +392,81 @@ func TestRewirer_Rewire(t *testing.T) {
 				DiffStat: ct.TestChangsetSpecDiffStat,
 			})},
 		},
+		{
+			name: "update branch spec - identical",
+			mappings: btypes.RewirerMappings{{
+				ChangesetSpec: ct.BuildChangesetSpec(t, ct.TestSpecOpts{
+					ID:   testChangesetSpecID + 1,
+					Repo: testRepoID,
+
+					// Branch spec
+					HeadRef: "refs/heads/test-branch",
+				}),
+				Changeset: ct.BuildChangeset(ct.TestChangesetOpts{
+					Repo:               testRepoID,
+					ExternalID:         "123",
+					CurrentSpec:        testChangesetSpecID,
+					BatchChanges:       []btypes.BatchChangeAssoc{{BatchChangeID: testBatchChangeID}},
+					OwnedByBatchChange: testBatchChangeID,
+					PublicationState:   btypes.ChangesetPublicationStatePublished,
+					ExternalState:      btypes.ChangesetExternalStateOpen,
+					ReconcilerState:    btypes.ReconcilerStateCompleted,
+				}),
+				Repo:      testRepo,
+				Identical: true,
+			}},
+			wantChangesets: []ct.ChangesetAssertions{{
+				Repo:               testRepoID,
+				ExternalID:         "123",
+				ExternalState:      btypes.ChangesetExternalStateOpen,
+				OwnedByBatchChange: testBatchChangeID,
+				AttachedTo:         []int64{testBatchChangeID},
+				PublicationState:   btypes.ChangesetPublicationStatePublished,
+				// The changeset is reused as-is, so it's not enqueued.
+				ReconcilerState: btypes.ReconcilerStateCompleted,
+				CurrentSpec:     testChangesetSpecID + 1,
+				PreviousSpec:    testChangesetSpecID + 1,
+				// Diff stat is copied over from changeset spec
+				DiffStat: ct.TestChangsetSpecDiffStat,
+			}},
+		},
+		{
+			name: "update branch spec - identical but closed",
+			mappings: btypes.RewirerMappings{{
+				ChangesetSpec: ct.BuildChangesetSpec(t, ct.TestSpecOpts{
+					ID:   testChangesetSpecID + 1,
+					Repo: testRepoID,
+
+					// Branch spec
+					HeadRef: "refs/heads/test-branch",
+				}),
+				Changeset: ct.BuildChangeset(ct.TestChangesetOpts{
+					Repo:               testRepoID,
+					ExternalID:         "123",
+					CurrentSpec:        testChangesetSpecID,
+					BatchChanges:       []btypes.BatchChangeAssoc{{BatchChangeID: testBatchChangeID, IsArchived: true}},
+					OwnedByBatchChange: testBatchChangeID,
+					PublicationState:   btypes.ChangesetPublicationStatePublished,
+					ExternalState:      btypes.ChangesetExternalStateClosed,
+					ReconcilerState:    btypes.ReconcilerStateCompleted,
+				}),
+				Repo:      testRepo,
+				Identical: true,
+			}},
+			wantChangesets: []ct.ChangesetAssertions{assertResetReconcilerState(ct.ChangesetAssertions{
+				Repo:               testRepoID,
+				ExternalID:         "123",
+				ExternalState:      btypes.ChangesetExternalStateClosed,
+				OwnedByBatchChange: testBatchChangeID,
+				AttachedTo:         []int64{testBatchChangeID},
+				PublicationState:   btypes.ChangesetPublicationStatePublished,
+				// The changeset needs to be reopened, so it's enqueued.
+				CurrentSpec:  testChangesetSpecID + 1,
+				PreviousSpec: testChangesetSpecID,
+				// Diff stat is copied over from changeset spec
+				DiffStat: ct.TestChangsetSpecDiffStat,
+			})},
+		},
 		{
 			name: "update branch spec - failed before",
 			mappings: btypes.RewirerMappings{{
@@ -8,10 +8,12 @@ import (
 
 type ServiceMocks struct {
 	ValidateAuthenticator func(ctx context.Context, externalServiceID, externalServiceType string, a auth.Authenticator) error
+	CheckAuthenticator    func(ctx context.Context, externalServiceID, externalServiceType string, a auth.Authenticator) error
 }
 
 func (sm ServiceMocks) Reset() {
 	sm.ValidateAuthenticator = nil
+	sm.CheckAuthenticator = nil
 }
 
 var Mocks = ServiceMocks{}
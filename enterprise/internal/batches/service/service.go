@@ -71,8 +71,12 @@ type operations struct {
 	enqueueChangesetSync                 *observation.Operation
 	reenqueueChangeset                   *observation.Operation
 	checkNamespaceAccess                 *observation.Operation
+	checkBatchSpecMaintainerAccess       *observation.Operation
 	fetchUsernameForBitbucketServerToken *observation.Operation
 	validateAuthenticator                *observation.Operation
+	checkAuthenticator                   *observation.Operation
+	checkUserCredential                  *observation.Operation
+	checkSiteCredential                  *observation.Operation
 	createChangesetJobs                  *observation.Operation
 	applyBatchChange                     *observation.Operation
 	reconcileBatchChange                 *observation.Operation
@@ -119,8 +123,12 @@ func newOperations(observationContext *observation.Context) *operations {
 			enqueueChangesetSync:                 op("EnqueueChangesetSync"),
 			reenqueueChangeset:                   op("ReenqueueChangeset"),
 			checkNamespaceAccess:                 op("CheckNamespaceAccess"),
+			checkBatchSpecMaintainerAccess:       op("CheckBatchSpecMaintainerAccess"),
 			fetchUsernameForBitbucketServerToken: op("FetchUsernameForBitbucketServerToken"),
 			validateAuthenticator:                op("ValidateAuthenticator"),
+			checkAuthenticator:                   op("CheckAuthenticator"),
+			checkUserCredential:                  op("CheckUserCredential"),
+			checkSiteCredential:                  op("CheckSiteCredential"),
 			createChangesetJobs:                  op("CreateChangesetJobs"),
 			applyBatchChange:                     op("ApplyBatchChange"),
 			reconcileBatchChange:                 op("ReconcileBatchChange"),
@@ -231,6 +239,7 @@ type CreateBatchSpecFromRawOpts struct {
 
 	AllowIgnored     bool
 	AllowUnsupported bool
+	NoCache          bool
 }
 
 // CreateBatchSpecFromRaw creates the BatchSpec.
@@ -238,6 +247,7 @@ func (s *Service) CreateBatchSpecFromRaw(ctx context.Context, opts CreateBatchSp
 	ctx, endObservation := s.operations.createBatchSpecFromRaw.With(ctx, &err, observation.Args{LogFields: []log.Field{
 		log.Bool("allowIgnored", opts.AllowIgnored),
 		log.Bool("allowUnsupported", opts.AllowUnsupported),
+		log.Bool("noCache", opts.NoCache),
 	}})
 	defer endObservation(1, observation.Args{})
 
@@ -266,6 +276,7 @@ func (s *Service) CreateBatchSpecFromRaw(ctx context.Context, opts CreateBatchSp
 		spec:             spec,
 		allowIgnored:     opts.AllowIgnored,
 		allowUnsupported: opts.AllowUnsupported,
+		noCache:          opts.NoCache,
 	})
 }
 
@@ -273,6 +284,7 @@ type createBatchSpecForExecutionOpts struct {
 	spec             *btypes.BatchSpec
 	allowUnsupported bool
 	allowIgnored     bool
+	noCache          bool
 }
 
 // createBatchSpecForExecution persists the given BatchSpec in the given
@@ -284,6 +296,7 @@ func (s *Service) createBatchSpecForExecution(ctx context.Context, tx *store.Sto
 	opts.spec.CreatedFromRaw = true
 	opts.spec.AllowIgnored = opts.allowIgnored
 	opts.spec.AllowUnsupported = opts.allowUnsupported
+	opts.spec.NoCache = opts.noCache
 
 	if err := tx.CreateBatchSpec(ctx, opts.spec); err != nil {
 		return err
@@ -398,8 +411,8 @@ func (s *Service) ExecuteBatchSpec(ctx context.Context, opts ExecuteBatchSpecOpt
 		return nil, err
 	}
 
-	// Check whether the current user has access to either one of the namespaces.
-	err = s.CheckNamespaceAccess(ctx, batchSpec.NamespaceUserID, batchSpec.NamespaceOrgID)
+	// Check whether the current user is a maintainer of the batch spec.
+	err = s.CheckBatchSpecMaintainerAccess(ctx, batchSpec)
 	if err != nil {
 		return nil, err
 	}
@@ -425,7 +438,9 @@ func (s *Service) ExecuteBatchSpec(ctx context.Context, opts ExecuteBatchSpecOpt
 		return nil, ErrBatchSpecResolutionErrored{resolutionJob.FailureMessage}
 
 	case btypes.BatchSpecResolutionJobStateCompleted:
-		err = tx.CreateBatchSpecWorkspaceExecutionJobs(ctx, batchSpec.ID)
+		// The workspaces are executed with the permissions of the user
+		// starting the execution, not necessarily the creator of the spec.
+		err = tx.CreateBatchSpecWorkspaceExecutionJobs(ctx, batchSpec.ID, actor.FromContext(ctx).UID)
 		if err != nil {
 			return nil, err
 		}
@@ -460,8 +475,8 @@ func (s *Service) CancelBatchSpec(ctx context.Context, opts CancelBatchSpecOpts)
 		return nil, err
 	}
 
-	// Check whether the current user has access to either one of the namespaces.
-	err = s.CheckNamespaceAccess(ctx, batchSpec.NamespaceUserID, batchSpec.NamespaceOrgID)
+	// Check whether the current user is a maintainer of the batch spec.
+	err = s.CheckBatchSpecMaintainerAccess(ctx, batchSpec)
 	if err != nil {
 		return nil, err
 	}
@@ -491,6 +506,7 @@ type ReplaceBatchSpecInputOpts struct {
 	RawSpec          string
 	AllowIgnored     bool
 	AllowUnsupported bool
+	NoCache          bool
 }
 
 // ReplaceBatchSpecInput creates BatchSpecWorkspaceExecutionJobs for every created
@@ -514,8 +530,8 @@ func (s *Service) ReplaceBatchSpecInput(ctx context.Context, opts ReplaceBatchSp
 		return nil, err
 	}
 
-	// Check whether the current user has access to either one of the namespaces.
-	err = s.CheckNamespaceAccess(ctx, batchSpec.NamespaceUserID, batchSpec.NamespaceOrgID)
+	// Check whether the current user is a maintainer of the batch spec.
+	err = s.CheckBatchSpecMaintainerAccess(ctx, batchSpec)
 	if err != nil {
 		return nil, err
 	}
@@ -552,6 +568,7 @@ func (s *Service) ReplaceBatchSpecInput(ctx context.Context, opts ReplaceBatchSp
 		spec:             newSpec,
 		allowUnsupported: opts.AllowUnsupported,
 		allowIgnored:     opts.AllowIgnored,
+		noCache:          opts.NoCache,
 	})
 }
 
@@ -720,7 +737,7 @@ func (s *Service) CloseBatchChange(ctx context.Context, id int64, closeChangeset
 		return batchChange, nil
 	}
 
-	if err := backend.CheckSiteAdminOrSameUser(ctx, s.store.DB(), batchChange.InitialApplierID); err != nil {
+	if err := s.checkBatchChangeAdminAccess(ctx, batchChange); err != nil {
 		return nil, err
 	}
 
@@ -762,7 +779,7 @@ func (s *Service) DeleteBatchChange(ctx context.Context, id int64) (err error) {
 		return err
 	}
 
-	if err := backend.CheckSiteAdminOrSameUser(ctx, s.store.DB(), batchChange.InitialApplierID); err != nil {
+	if err := s.checkBatchChangeAdminAccess(ctx, batchChange); err != nil {
 		return err
 	}
 
@@ -892,9 +909,66 @@ func (s *Service) CheckNamespaceAccess(ctx context.Context, namespaceUserID, nam
 	}
 }
 
+// checkBatchChangeAdminAccess checks whether the current user in the ctx can
+// close or delete the batch change: site admins, the user that created the
+// batch change and, for batch changes in an organization namespace, the
+// admins of the organization can.
+func (s *Service) checkBatchChangeAdminAccess(ctx context.Context, batchChange *btypes.BatchChange) error {
+	err := backend.CheckSiteAdminOrSameUser(ctx, s.store.DB(), batchChange.InitialApplierID)
+	if err == nil || batchChange.NamespaceOrgID == 0 {
+		return err
+	}
+	if backend.CheckOrgAdminOrSiteAdmin(ctx, s.store.DB(), batchChange.NamespaceOrgID) == nil {
+		return nil
+	}
+	return err
+}
+
 // ErrNoNamespace is returned by checkNamespaceAccess if no valid namespace ID is given.
 var ErrNoNamespace = errors.New("no namespace given")
 
+// ErrNotBatchSpecMaintainer is returned by CheckBatchSpecMaintainerAccess if
+// the current user can view, but not maintain, the batch spec.
+var ErrNotBatchSpecMaintainer = errors.New("must be the creator of the batch spec, an admin of its namespace or a site admin")
+
+// CheckBatchSpecMaintainerAccess checks whether the current user in the ctx
+// is a maintainer of the given batch spec, which is required to execute,
+// cancel, replace the input of and apply it, and thereby publish its
+// changesets.
+// Every user with access to the namespace of the batch spec can view it, but
+// only the user who created the batch spec, the admins of its namespace, and
+// site admins can maintain it. For a user namespace the only admin is the user
+// themself, for an organization namespace it's the admins of the organization.
+func (s *Service) CheckBatchSpecMaintainerAccess(ctx context.Context, batchSpec *btypes.BatchSpec) (err error) {
+	ctx, endObservation := s.operations.checkBatchSpecMaintainerAccess.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("batchSpecID", int(batchSpec.ID)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	if err := s.CheckNamespaceAccess(ctx, batchSpec.NamespaceUserID, batchSpec.NamespaceOrgID); err != nil {
+		return err
+	}
+
+	// Having access to a user namespace means being that user or a site
+	// admin, both of which are maintainers.
+	if batchSpec.NamespaceOrgID == 0 {
+		return nil
+	}
+
+	if a := actor.FromContext(ctx); a.IsAuthenticated() && a.UID == batchSpec.UserID {
+		return nil
+	}
+
+	if err := backend.CheckOrgAdminOrSiteAdmin(ctx, s.store.DB(), batchSpec.NamespaceOrgID); err != nil {
+		if err == backend.ErrNotAnOrgAdmin {
+			return ErrNotBatchSpecMaintainer
+		}
+		return err
+	}
+
+	return nil
+}
+
 // FetchUsernameForBitbucketServerToken fetches the username associated with a
 // Bitbucket server token.
 //
@@ -971,6 +1045,144 @@ func (s *Service) ValidateAuthenticator(ctx context.Context, externalServiceID,
 	return nil
 }
 
+// CredentialCheckFailedError is returned by CheckAuthenticator when the
+// authenticator can't be used to publish changesets on the code host.
+type CredentialCheckFailedError struct {
+	Reason string
+}
+
+func (e *CredentialCheckFailedError) Error() string {
+	return e.Reason
+}
+
+// CheckAuthenticator checks, beyond ValidateAuthenticator, that the given
+// authenticator can be used to publish changesets on the code host: that it
+// has been granted the required scopes and, if repositories on the code host
+// are cloned over SSH, that its SSH key has been added to the code host. A
+// *CredentialCheckFailedError describes the first problem found.
+func (s *Service) CheckAuthenticator(ctx context.Context, externalServiceID, externalServiceType string, a auth.Authenticator) (err error) {
+	ctx, endObservation := s.operations.checkAuthenticator.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	if Mocks.CheckAuthenticator != nil {
+		return Mocks.CheckAuthenticator(ctx, externalServiceID, externalServiceType, a)
+	}
+
+	css, err := s.sourcer.ForExternalService(ctx, s.store, store.GetExternalServiceIDsOpts{
+		ExternalServiceType: externalServiceType,
+		ExternalServiceID:   externalServiceID,
+	})
+	if err != nil {
+		return err
+	}
+	css, err = css.WithAuthenticator(a)
+	if err != nil {
+		return &CredentialCheckFailedError{Reason: err.Error()}
+	}
+
+	if err := css.ValidateAuthenticator(ctx); err != nil {
+		return &CredentialCheckFailedError{Reason: fmt.Sprintf("the code host rejected the credential: %s", err)}
+	}
+
+	checker, ok := css.(sources.CredentialCheckingSource)
+	if ok {
+		missing, err := checker.MissingScopes(ctx)
+		if err != nil {
+			return &CredentialCheckFailedError{Reason: fmt.Sprintf("listing the scopes of the credential: %s", err)}
+		}
+		if len(missing) > 0 {
+			return &CredentialCheckFailedError{Reason: fmt.Sprintf("the credential is missing the required scopes: %s", strings.Join(missing, ", "))}
+		}
+	}
+
+	codeHosts, err := s.store.ListCodeHosts(ctx, store.ListCodeHostsOpts{})
+	if err != nil {
+		return err
+	}
+	requiresSSH := false
+	for _, ch := range codeHosts {
+		if ch.ExternalServiceType == externalServiceType && ch.ExternalServiceID == externalServiceID {
+			requiresSSH = ch.RequiresSSH
+			break
+		}
+	}
+	if !requiresSSH {
+		return nil
+	}
+
+	sshA, isSSH := a.(auth.AuthenticatorWithSSH)
+	if !isSSH {
+		return &CredentialCheckFailedError{Reason: "repositories on the code host are cloned over SSH, but the credential has no SSH key"}
+	}
+	if ok {
+		found, err := checker.HasSSHPublicKey(ctx, sshA.SSHPublicKey())
+		if err != nil {
+			return &CredentialCheckFailedError{Reason: fmt.Sprintf("listing the SSH keys added to the code host: %s", err)}
+		}
+		if !found {
+			return &CredentialCheckFailedError{Reason: "the SSH public key of the credential has not been added to the code host"}
+		}
+	}
+
+	return nil
+}
+
+// CheckUserCredential checks the given user credential with CheckAuthenticator
+// and records the result on it.
+func (s *Service) CheckUserCredential(ctx context.Context, cred *database.UserCredential) (err error) {
+	ctx, endObservation := s.operations.checkUserCredential.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int64("credentialID", cred.ID),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	a, err := cred.Authenticator(ctx)
+	if err != nil {
+		return errors.Wrap(err, "retrieving authenticator")
+	}
+
+	failureMessage, err := s.checkCredential(ctx, cred.ExternalServiceID, cred.ExternalServiceType, a)
+	if err != nil {
+		return err
+	}
+	return s.store.UserCredentials().MarkChecked(ctx, cred, failureMessage)
+}
+
+// CheckSiteCredential checks the given site credential with CheckAuthenticator
+// and records the result on it.
+func (s *Service) CheckSiteCredential(ctx context.Context, cred *btypes.SiteCredential) (err error) {
+	ctx, endObservation := s.operations.checkSiteCredential.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int64("credentialID", cred.ID),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	a, err := cred.Authenticator(ctx)
+	if err != nil {
+		return errors.Wrap(err, "decrypting authenticator")
+	}
+
+	failureMessage, err := s.checkCredential(ctx, cred.ExternalServiceID, cred.ExternalServiceType, a)
+	if err != nil {
+		return err
+	}
+	return s.store.MarkSiteCredentialChecked(ctx, cred, failureMessage)
+}
+
+// checkCredential returns why the given authenticator can't be used to
+// publish changesets, or nil if it can. An error is only returned if the
+// check could not be completed.
+func (s *Service) checkCredential(ctx context.Context, externalServiceID, externalServiceType string, a auth.Authenticator) (*string, error) {
+	err := s.CheckAuthenticator(ctx, externalServiceID, externalServiceType, a)
+	if err == nil {
+		return nil, nil
+	}
+
+	var checkErr *CredentialCheckFailedError
+	if errors.As(err, &checkErr) {
+		return &checkErr.Reason, nil
+	}
+	return nil, err
+}
+
 // ErrChangesetsForJobNotFound can be returned by (*Service).CreateChangesetJobs
 // if the number of changesets returned from the database doesn't match the
 // number if IDs passed in. That can happen if some of the changesets are not
@@ -990,8 +1202,13 @@ func (s *Service) CreateChangesetJobs(ctx context.Context, batchChangeID int64,
 		return bulkGroupID, errors.Wrap(err, "loading batch change")
 	}
 
-	// 🚨 SECURITY: Only the author of the batch change can create jobs.
-	if err := backend.CheckSiteAdminOrSameUser(ctx, s.store.DB(), batchChange.InitialApplierID); err != nil {
+	// 🚨 SECURITY: Only the author of the batch change can create jobs. The
+	// admins of the namespace of the batch change can publish its changesets.
+	if jobType == btypes.ChangesetJobTypePublish {
+		if err := s.checkBatchChangeAdminAccess(ctx, batchChange); err != nil {
+			return bulkGroupID, err
+		}
+	} else if err := backend.CheckSiteAdminOrSameUser(ctx, s.store.DB(), batchChange.InitialApplierID); err != nil {
 		return bulkGroupID, err
 	}
 
@@ -6,7 +6,7 @@ import (
 
 	"github.com/cockroachdb/errors"
 
-	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/global"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/rewirer"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
@@ -62,8 +62,8 @@ func (s *Service) ApplyBatchChange(
 		return nil, err
 	}
 
-	// 🚨 SECURITY: Only site-admins or the creator of batchSpec can apply it.
-	if err := backend.CheckSiteAdminOrSameUser(ctx, s.store.DB(), batchSpec.UserID); err != nil {
+	// 🚨 SECURITY: Only maintainers of batchSpec can apply it.
+	if err := s.CheckBatchSpecMaintainerAccess(ctx, batchSpec); err != nil {
 		return nil, err
 	}
 
@@ -158,6 +158,12 @@ func (s *Service) ApplyBatchChange(
 	// Upsert all changesets.
 	for _, changeset := range changesets {
 		if state := opts.PublicationStates.get(changeset.CurrentSpecID); state != nil {
+			// Changesets reused by the rewirer for an identical spec are not
+			// enqueued, so we need to enqueue them if their UI publication
+			// state changes.
+			if changeset.ReconcilerState == btypes.ReconcilerStateCompleted && (changeset.UiPublicationState == nil || *changeset.UiPublicationState != *state) {
+				changeset.ResetReconcilerState(global.DefaultReconcilerEnqueueState())
+			}
 			changeset.UiPublicationState = state
 		}
 
@@ -197,5 +203,10 @@ func (s *Service) ReconcileBatchChange(
 	batchChange.LastApplierID = a.UID
 	batchChange.LastAppliedAt = s.clock()
 	batchChange.Description = batchSpec.Spec.Description
+	batchChange.AutoRebase = batchSpec.Spec.ChangesetTemplate != nil && batchSpec.Spec.ChangesetTemplate.AutoRebase
+	batchChange.AutoArchiveAfterDays = 0
+	if batchSpec.Spec.ChangesetTemplate != nil {
+		batchChange.AutoArchiveAfterDays = batchSpec.Spec.ChangesetTemplate.AutoArchiveAfterDays
+	}
 	return batchChange, previousSpecID, nil
 }
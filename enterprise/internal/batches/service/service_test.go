@@ -29,6 +29,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
 	"github.com/sourcegraph/sourcegraph/internal/timeutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
 )
 
@@ -627,6 +628,19 @@ func TestService(t *testing.T) {
 			}
 		})
 
+		t.Run("invalid diff", func(t *testing.T) {
+			spec := &batcheslib.ChangesetSpec{}
+			if err := json.Unmarshal([]byte(rawSpec), spec); err != nil {
+				t.Fatal(err)
+			}
+			spec.Commits[0].Diff = "--- a/README.md\n+++ b/README.md\n@@ -x +y @@\n-a\n+b\n"
+
+			_, err := svc.CreateChangesetSpec(ctx, ct.MarshalJSON(t, spec), admin.ID)
+			if err == nil {
+				t.Fatal("expected error but got nil")
+			}
+		})
+
 		t.Run("missing repository permissions", func(t *testing.T) {
 			ct.MockRepoPermissions(t, db, user.ID, rs[1].ID, rs[2].ID, rs[3].ID)
 
@@ -885,6 +899,49 @@ func TestService(t *testing.T) {
 		})
 	})
 
+	t.Run("CheckAuthenticator", func(t *testing.T) {
+		t.Cleanup(func() {
+			fakeSource.AuthenticatorIsValid = true
+			fakeSource.FakeMissingScopes = nil
+		})
+
+		check := func() error {
+			return svc.CheckAuthenticator(
+				ctx,
+				"https://github.com/",
+				extsvc.TypeGitHub,
+				&auth.OAuthBearerToken{Token: "test123"},
+			)
+		}
+
+		t.Run("valid", func(t *testing.T) {
+			fakeSource.AuthenticatorIsValid = true
+			fakeSource.FakeMissingScopes = nil
+			if err := check(); err != nil {
+				t.Fatal(err)
+			}
+		})
+		t.Run("invalid", func(t *testing.T) {
+			fakeSource.AuthenticatorIsValid = false
+			fakeSource.FakeMissingScopes = nil
+			var checkErr *CredentialCheckFailedError
+			if err := check(); !errors.As(err, &checkErr) {
+				t.Fatalf("unexpected error returned from CheckAuthenticator: %+v", err)
+			}
+		})
+		t.Run("missing scopes", func(t *testing.T) {
+			fakeSource.AuthenticatorIsValid = true
+			fakeSource.FakeMissingScopes = []string{"read:org", "user:email"}
+			var checkErr *CredentialCheckFailedError
+			if err := check(); !errors.As(err, &checkErr) {
+				t.Fatalf("unexpected error returned from CheckAuthenticator: %+v", err)
+			}
+			if want := "the credential is missing the required scopes: read:org, user:email"; checkErr.Reason != want {
+				t.Fatalf("wrong reason. want=%q, have=%q", want, checkErr.Reason)
+			}
+		})
+	})
+
 	t.Run("CreateChangesetJobs", func(t *testing.T) {
 		spec := testBatchSpec(admin.ID)
 		if err := s.CreateBatchSpec(ctx, spec); err != nil {
@@ -1291,6 +1348,92 @@ func TestService(t *testing.T) {
 				}
 			}
 		})
+
+		t.Run("org namespace", func(t *testing.T) {
+			orgID := ct.InsertTestOrg(t, db, "test-org-execute")
+			member := ct.CreateTestUser(t, db, false)
+			orgAdmin := ct.CreateTestUser(t, db, false)
+			for _, userID := range []int32{user.ID, member.ID} {
+				if _, err := database.OrgMembers(db).Create(ctx, orgID, userID); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if _, err := database.OrgMembers(db).CreateWithRole(ctx, orgID, orgAdmin.ID, types.OrgRoleAdmin); err != nil {
+				t.Fatal(err)
+			}
+
+			spec := testBatchSpec(user.ID)
+			spec.NamespaceUserID = 0
+			spec.NamespaceOrgID = orgID
+			if err := s.CreateBatchSpec(ctx, spec); err != nil {
+				t.Fatal(err)
+			}
+
+			job := &btypes.BatchSpecResolutionJob{
+				State:       btypes.BatchSpecResolutionJobStateCompleted,
+				BatchSpecID: spec.ID,
+			}
+			if err := s.CreateBatchSpecResolutionJob(ctx, job); err != nil {
+				t.Fatal(err)
+			}
+
+			ws := &btypes.BatchSpecWorkspace{
+				BatchSpecID: spec.ID,
+				RepoID:      rs[0].ID,
+				Steps: []batcheslib.Step{
+					{Run: "echo hello", Container: "alpine:3"},
+				},
+			}
+			if err := s.CreateBatchSpecWorkspace(ctx, ws); err != nil {
+				t.Fatal(err)
+			}
+
+			// Other members of the org can see the batch spec, but not
+			// execute, cancel or replace its input.
+			memberCtx := actor.WithActor(context.Background(), actor.FromUser(member.ID))
+			if _, err := svc.ExecuteBatchSpec(memberCtx, ExecuteBatchSpecOpts{BatchSpecRandID: spec.RandID}); err != ErrNotBatchSpecMaintainer {
+				t.Fatalf("unexpected error. want=%s, have=%s", ErrNotBatchSpecMaintainer, err)
+			}
+			if _, err := svc.CancelBatchSpec(memberCtx, CancelBatchSpecOpts{BatchSpecRandID: spec.RandID}); err != ErrNotBatchSpecMaintainer {
+				t.Fatalf("unexpected error. want=%s, have=%s", ErrNotBatchSpecMaintainer, err)
+			}
+			if _, err := svc.ReplaceBatchSpecInput(memberCtx, ReplaceBatchSpecInputOpts{
+				BatchSpecRandID: spec.RandID,
+				RawSpec:         ct.TestRawBatchSpecYAML,
+			}); err != ErrNotBatchSpecMaintainer {
+				t.Fatalf("unexpected error. want=%s, have=%s", ErrNotBatchSpecMaintainer, err)
+			}
+
+			// Org admins can execute it and the jobs run with their
+			// permissions.
+			orgAdminCtx := actor.WithActor(context.Background(), actor.FromUser(orgAdmin.ID))
+			if _, err := svc.ExecuteBatchSpec(orgAdminCtx, ExecuteBatchSpecOpts{BatchSpecRandID: spec.RandID}); err != nil {
+				t.Fatal(err)
+			}
+
+			jobs, err := s.ListBatchSpecWorkspaceExecutionJobs(ctx, store.ListBatchSpecWorkspaceExecutionJobsOpts{
+				BatchSpecWorkspaceIDs: []int64{ws.ID},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(jobs) != 1 {
+				t.Fatalf("wrong number of execution jobs created. want=%d, have=%d", 1, len(jobs))
+			}
+			if have, want := jobs[0].UserID, orgAdmin.ID; have != want {
+				t.Fatalf("execution job has wrong user. want=%d, have=%d", want, have)
+			}
+
+			// Only maintainers can apply it.
+			if _, err := svc.ApplyBatchChange(memberCtx, ApplyBatchChangeOpts{BatchSpecRandID: spec.RandID}); err != ErrNotBatchSpecMaintainer {
+				t.Fatalf("unexpected error. want=%s, have=%s", ErrNotBatchSpecMaintainer, err)
+			}
+
+			orgAdminCtx := actor.WithActor(context.Background(), actor.FromUser(orgAdmin.ID))
+			if _, err := svc.ApplyBatchChange(orgAdminCtx, ApplyBatchChangeOpts{BatchSpecRandID: spec.RandID}); err != nil {
+				t.Fatal(err)
+			}
+		})
 	})
 
 	t.Run("CancelBatchSpec", func(t *testing.T) {
@@ -12,6 +12,8 @@ import (
 	"github.com/cockroachdb/errors"
 	"github.com/gobwas/glob"
 	"github.com/hashicorp/go-multierror"
+	"github.com/inconshreveable/log15"
+	otlog "github.com/opentracing/opentracing-go/log"
 
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
@@ -52,10 +54,20 @@ type RepoWorkspace struct {
 	Unsupported bool
 }
 
+// ResolveWorkspacesForBatchSpecOpts are the options for
+// ResolveWorkspacesForBatchSpec.
+type ResolveWorkspacesForBatchSpecOpts struct {
+	// NoCache disables reading the results of repositoriesMatchingQuery
+	// clauses from the workspace resolution cache. The cache is still
+	// refreshed with the results of the search.
+	NoCache bool
+}
+
 type WorkspaceResolver interface {
 	ResolveWorkspacesForBatchSpec(
 		ctx context.Context,
 		batchSpec *batcheslib.BatchSpec,
+		opts ResolveWorkspacesForBatchSpecOpts,
 	) (
 		workspaces []*RepoWorkspace,
 		err error,
@@ -73,7 +85,7 @@ type workspaceResolver struct {
 	frontendInternalURL string
 }
 
-func (wr *workspaceResolver) ResolveWorkspacesForBatchSpec(ctx context.Context, batchSpec *batcheslib.BatchSpec) (workspaces []*RepoWorkspace, err error) {
+func (wr *workspaceResolver) ResolveWorkspacesForBatchSpec(ctx context.Context, batchSpec *batcheslib.BatchSpec, opts ResolveWorkspacesForBatchSpecOpts) (workspaces []*RepoWorkspace, err error) {
 	tr, ctx := trace.New(ctx, "workspaceResolver.ResolveWorkspacesForBatchSpec", "")
 	defer func() {
 		tr.SetError(err)
@@ -82,7 +94,7 @@ func (wr *workspaceResolver) ResolveWorkspacesForBatchSpec(ctx context.Context,
 
 	// First, find all repositories that match the batch spec on definitions.
 	// This list is filtered by permissions using database.Repos.List.
-	repos, err := wr.determineRepositories(ctx, batchSpec)
+	repos, err := wr.determineRepositories(ctx, batchSpec, opts.NoCache)
 	if err != nil {
 		return nil, err
 	}
@@ -126,13 +138,25 @@ func (wr *workspaceResolver) ResolveWorkspacesForBatchSpec(ctx context.Context,
 	return workspaces, nil
 }
 
-func (wr *workspaceResolver) determineRepositories(ctx context.Context, batchSpec *batcheslib.BatchSpec) ([]*RepoRevision, error) {
+func (wr *workspaceResolver) determineRepositories(ctx context.Context, batchSpec *batcheslib.BatchSpec, noCache bool) ([]*RepoRevision, error) {
 	seen := map[api.RepoID]*RepoRevision{}
 
+	// Look up the repositories referenced by name in a single query.
+	var names []api.RepoName
+	for _, on := range batchSpec.On {
+		if on.Repository != "" {
+			names = append(names, api.RepoName(on.Repository))
+		}
+	}
+	reposByName, err := wr.store.Repos().GetByNames(ctx, names, database.GetByNamesOptions{IncludeAliases: true})
+	if err != nil {
+		return nil, err
+	}
+
 	var errs error
 	// TODO: this could be trivially parallelised in the future.
 	for _, on := range batchSpec.On {
-		repos, err := wr.resolveRepositoriesOn(ctx, &on)
+		repos, err := wr.resolveRepositoriesOn(ctx, &on, reposByName, noCache)
 		if err != nil {
 			errs = multierror.Append(errs, errors.Wrapf(err, "resolving %q", on.String()))
 			continue
@@ -216,7 +240,9 @@ func findIgnoredRepositories(ctx context.Context, repos []*RepoRevision) (map[*t
 
 var ErrMalformedOnQueryOrRepository = batcheslib.NewValidationError(errors.New("malformed 'on' field; missing either a repository name or a query"))
 
-func (wr *workspaceResolver) resolveRepositoriesOn(ctx context.Context, on *batcheslib.OnQueryOrRepository) (_ []*RepoRevision, err error) {
+// resolveRepositoriesOn resolves the repositories of the given on entry.
+// Repositories referenced by name are looked up in reposByName.
+func (wr *workspaceResolver) resolveRepositoriesOn(ctx context.Context, on *batcheslib.OnQueryOrRepository, reposByName map[api.RepoName]*types.Repo, noCache bool) (_ []*RepoRevision, err error) {
 	tr, ctx := trace.New(ctx, "workspaceResolver.resolveRepositoriesOn", "")
 	defer func() {
 		tr.SetError(err)
@@ -224,11 +250,11 @@ func (wr *workspaceResolver) resolveRepositoriesOn(ctx context.Context, on *batc
 	}()
 
 	if on.RepositoriesMatchingQuery != "" {
-		return wr.resolveRepositoriesMatchingQuery(ctx, on.RepositoriesMatchingQuery)
+		return wr.resolveRepositoriesMatchingQuery(ctx, on.RepositoriesMatchingQuery, noCache)
 	}
 
 	if on.Repository != "" && on.Branch != "" {
-		repo, err := wr.resolveRepositoryNameAndBranch(ctx, on.Repository, on.Branch)
+		repo, err := wr.resolveRepositoryNameAndBranch(ctx, on.Repository, on.Branch, reposByName)
 		if err != nil {
 			return nil, err
 		}
@@ -236,7 +262,7 @@ func (wr *workspaceResolver) resolveRepositoriesOn(ctx context.Context, on *batc
 	}
 
 	if on.Repository != "" {
-		repo, err := wr.resolveRepositoryName(ctx, on.Repository)
+		repo, err := wr.resolveRepositoryName(ctx, on.Repository, reposByName)
 		if err != nil {
 			return nil, err
 		}
@@ -248,14 +274,14 @@ func (wr *workspaceResolver) resolveRepositoriesOn(ctx context.Context, on *batc
 	return nil, ErrMalformedOnQueryOrRepository
 }
 
-func (wr *workspaceResolver) resolveRepositoryName(ctx context.Context, name string) (_ *RepoRevision, err error) {
+func (wr *workspaceResolver) resolveRepositoryName(ctx context.Context, name string, reposByName map[api.RepoName]*types.Repo) (_ *RepoRevision, err error) {
 	tr, ctx := trace.New(ctx, "workspaceResolver.resolveRepositoryName", "")
 	defer func() {
 		tr.SetError(err)
 		tr.Finish()
 	}()
 
-	repo, err := wr.store.Repos().GetByName(ctx, api.RepoName(name))
+	repo, err := lookupRepositoryName(reposByName, name)
 	if err != nil {
 		return nil, err
 	}
@@ -268,14 +294,25 @@ func (wr *workspaceResolver) resolveRepositoryName(ctx context.Context, name str
 	)
 }
 
-func (wr *workspaceResolver) resolveRepositoryNameAndBranch(ctx context.Context, name, branch string) (_ *RepoRevision, err error) {
+// lookupRepositoryName returns the repository with the given name from the
+// repositories looked up by determineRepositories, with the same errors as
+// RepoStore.GetByName.
+func lookupRepositoryName(reposByName map[api.RepoName]*types.Repo, name string) (*types.Repo, error) {
+	repo, ok := reposByName[api.RepoName(name)]
+	if !ok {
+		return nil, &database.RepoNotFoundErr{Name: api.RepoName(name)}
+	}
+	return repo, repo.IsBlocked()
+}
+
+func (wr *workspaceResolver) resolveRepositoryNameAndBranch(ctx context.Context, name, branch string, reposByName map[api.RepoName]*types.Repo) (_ *RepoRevision, err error) {
 	tr, ctx := trace.New(ctx, "workspaceResolver.resolveRepositoryNameAndBranch", "")
 	defer func() {
 		tr.SetError(err)
 		tr.Finish()
 	}()
 
-	repo, err := wr.store.Repos().GetByName(ctx, api.RepoName(name))
+	repo, err := lookupRepositoryName(reposByName, name)
 	if err != nil {
 		return nil, err
 	}
@@ -296,7 +333,7 @@ func (wr *workspaceResolver) resolveRepositoryNameAndBranch(ctx context.Context,
 	}, nil
 }
 
-func (wr *workspaceResolver) resolveRepositoriesMatchingQuery(ctx context.Context, query string) (_ []*RepoRevision, err error) {
+func (wr *workspaceResolver) resolveRepositoriesMatchingQuery(ctx context.Context, query string, noCache bool) (_ []*RepoRevision, err error) {
 	tr, ctx := trace.New(ctx, "workspaceResolver.resolveRepositorySearch", "")
 	defer func() {
 		tr.SetError(err)
@@ -305,6 +342,17 @@ func (wr *workspaceResolver) resolveRepositoriesMatchingQuery(ctx context.Contex
 
 	query = setDefaultQueryCount(query)
 
+	if !noCache {
+		revs, ok, err := wr.resolveRepositoriesMatchingQueryFromCache(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			tr.LogFields(otlog.Bool("cached", true))
+			return revs, nil
+		}
+	}
+
 	repoIDs := []api.RepoID{}
 	repoFileMatches := make(map[api.RepoID]map[string]bool)
 	addRepoFilePatch := func(repoID api.RepoID, path string) {
@@ -344,23 +392,94 @@ func (wr *workspaceResolver) resolveRepositoriesMatchingQuery(ctx context.Contex
 		return nil, err
 	}
 
-	revs := make([]*RepoRevision, 0, len(accessibleRepos))
-	for _, repo := range accessibleRepos {
-		fileMatches := make([]string, 0, len(repoFileMatches[repo.ID]))
-		for path := range repoFileMatches[repo.ID] {
+	sortedFileMatches := func(repoID api.RepoID) []string {
+		fileMatches := make([]string, 0, len(repoFileMatches[repoID]))
+		for path := range repoFileMatches[repoID] {
 			fileMatches = append(fileMatches, path)
 		}
 		sort.Strings(fileMatches)
-		rev, err := repoToRepoRevisionWithDefaultBranch(ctx, repo, fileMatches)
+		return fileMatches
+	}
+
+	revs := make([]*RepoRevision, 0, len(accessibleRepos))
+	commits := make(map[api.RepoID]api.CommitID, len(accessibleRepos))
+	for _, repo := range accessibleRepos {
+		rev, err := repoToRepoRevisionWithDefaultBranch(ctx, repo, sortedFileMatches(repo.ID))
 		if err != nil {
 			return nil, err
 		}
 		revs = append(revs, rev)
+		commits[repo.ID] = rev.Commit
+	}
+
+	// We cache the unfiltered search results, so the entries can be reused by
+	// users with access to other repositories. The commits of the
+	// repositories this user can't access are unknown and stored as empty,
+	// which invalidates the entries for users that can access them.
+	entries := make([]*btypes.BatchSpecResolutionCacheEntry, 0, len(repoIDs))
+	seen := make(map[api.RepoID]struct{}, len(repoIDs))
+	for _, id := range repoIDs {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		entries = append(entries, &btypes.BatchSpecResolutionCacheEntry{
+			RepoID:      id,
+			Commit:      string(commits[id]),
+			FileMatches: sortedFileMatches(id),
+		})
+	}
+	if err := wr.store.ReplaceBatchSpecResolutionCacheEntries(ctx, query, entries); err != nil {
+		// Failing to cache the results shouldn't fail the resolution.
+		log15.Warn("failed to cache workspace resolution", "query", query, "err", err)
 	}
 
 	return revs, nil
 }
 
+// resolveRepositoriesMatchingQueryFromCache resolves the given query from the
+// workspace resolution cache. It returns false if there are no cache entries
+// for the query, or if the default branch of one of the repositories has moved
+// on since the entries were created.
+func (wr *workspaceResolver) resolveRepositoriesMatchingQueryFromCache(ctx context.Context, query string) (_ []*RepoRevision, ok bool, err error) {
+	entries, err := wr.store.ListBatchSpecResolutionCacheEntries(ctx, query)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(entries) == 0 {
+		return nil, false, nil
+	}
+
+	repoIDs := make([]api.RepoID, 0, len(entries))
+	entriesByRepo := make(map[api.RepoID]*btypes.BatchSpecResolutionCacheEntry, len(entries))
+	for _, e := range entries {
+		repoIDs = append(repoIDs, e.RepoID)
+		entriesByRepo[e.RepoID] = e
+	}
+
+	// 🚨 SECURITY: We use database.Repos.List to check whether the user has access to
+	// the repositories or not.
+	accessibleRepos, err := wr.store.Repos().List(ctx, database.ReposListOptions{IDs: repoIDs})
+	if err != nil {
+		return nil, false, err
+	}
+
+	revs := make([]*RepoRevision, 0, len(accessibleRepos))
+	for _, repo := range accessibleRepos {
+		e := entriesByRepo[repo.ID]
+		rev, err := repoToRepoRevisionWithDefaultBranch(ctx, repo, e.FileMatches)
+		if err != nil {
+			return nil, false, err
+		}
+		if string(rev.Commit) != e.Commit {
+			return nil, false, nil
+		}
+		revs = append(revs, rev)
+	}
+
+	return revs, true, nil
+}
+
 const internalSearchClientUserAgent = "Batch Changes repository resolver"
 
 func (wr *workspaceResolver) runSearch(ctx context.Context, query string, onMatches func(matches []streamhttp.EventMatch)) (err error) {
@@ -294,6 +294,68 @@ func TestService_ResolveWorkspacesForBatchSpec(t *testing.T) {
 		want := []*RepoWorkspace{ws0, ws1}
 		resolveWorkspacesAndCompare(t, s, searchMatches, batchSpec, want)
 	})
+
+	t.Run("repositoriesMatchingQuery cached", func(t *testing.T) {
+		query := "repohasfile:cached.txt"
+		searchMatches := []streamhttp.EventMatch{
+			&streamhttp.EventPathMatch{
+				Type:         streamhttp.PathMatchType,
+				Path:         "repo-0/cached.txt",
+				RepositoryID: int32(rs[0].ID),
+			},
+		}
+		resolve := func(t *testing.T, matches []streamhttp.EventMatch, noCache bool) []*RepoRevision {
+			t.Helper()
+			wr := &workspaceResolver{
+				store:               s,
+				frontendInternalURL: newStreamSearchTestServer(t, matches),
+			}
+			have, err := wr.resolveRepositoriesMatchingQuery(ctx, query, noCache)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			return have
+		}
+		want := []*RepoRevision{{
+			Repo:        rs[0],
+			Branch:      defaultBranches[rs[0].Name].branch,
+			Commit:      defaultBranches[rs[0].Name].commit,
+			FileMatches: []string{"repo-0/cached.txt"},
+		}}
+
+		// The first resolution runs the search and populates the cache.
+		if diff := cmp.Diff(want, resolve(t, searchMatches, false)); diff != "" {
+			t.Fatalf("returned repos wrong. (-want +got):\n%s", diff)
+		}
+
+		// The search results changed, but the cached entries are still used.
+		if diff := cmp.Diff(want, resolve(t, []streamhttp.EventMatch{}, false)); diff != "" {
+			t.Fatalf("returned repos wrong. (-want +got):\n%s", diff)
+		}
+
+		// Bypassing the cache runs the search again.
+		if have := resolve(t, searchMatches[:0], true); len(have) != 0 {
+			t.Fatalf("expected no repos, got %d", len(have))
+		}
+
+		// The previous resolution replaced the cached entries with an empty
+		// result, which is never a cache hit.
+		if diff := cmp.Diff(want, resolve(t, searchMatches, false)); diff != "" {
+			t.Fatalf("returned repos wrong. (-want +got):\n%s", diff)
+		}
+
+		// Moving the default branch on invalidates the cache.
+		moved := map[api.RepoName]defaultBranch{}
+		for name, b := range defaultBranches {
+			moved[name] = b
+		}
+		moved[rs[0].Name] = defaultBranch{branch: "branch-1", commit: api.CommitID("d34db33f")}
+		mockDefaultBranches(t, moved)
+
+		if have := resolve(t, []streamhttp.EventMatch{}, false); len(have) != 0 {
+			t.Fatalf("expected no repos, got %d", len(have))
+		}
+	})
 }
 
 func resolveWorkspacesAndCompare(t *testing.T, s *store.Store, matches []streamhttp.EventMatch, spec *batcheslib.BatchSpec, want []*RepoWorkspace) {
@@ -303,7 +365,9 @@ func resolveWorkspacesAndCompare(t *testing.T, s *store.Store, matches []streamh
 		store:               s,
 		frontendInternalURL: newStreamSearchTestServer(t, matches),
 	}
-	have, err := wr.ResolveWorkspacesForBatchSpec(context.Background(), spec)
+	// The subtests reuse queries with different search results, so we
+	// bypass the cache.
+	have, err := wr.ResolveWorkspacesForBatchSpec(context.Background(), spec, ResolveWorkspacesForBatchSpecOpts{NoCache: true})
 	if err != nil {
 		t.Fatalf("unexpected error: %s", err)
 	}
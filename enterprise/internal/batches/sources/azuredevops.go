@@ -0,0 +1,282 @@
+package sources
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type AzureDevOpsSource struct {
+	client *azuredevops.Client
+	au     auth.Authenticator
+
+	// org is an organization of the external service, which is needed to
+	// validate credentials: Azure DevOps has no organization independent API
+	// to look up the authenticated user.
+	org string
+}
+
+var _ ChangesetSource = &AzureDevOpsSource{}
+var _ DraftChangesetSource = &AzureDevOpsSource{}
+
+// NewAzureDevOpsSource returns a new AzureDevOpsSource from the given external service.
+func NewAzureDevOpsSource(svc *types.ExternalService, cf *httpcli.Factory) (*AzureDevOpsSource, error) {
+	var c schema.AzureDevOpsConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	return newAzureDevOpsSource(&c, cf, nil)
+}
+
+func newAzureDevOpsSource(c *schema.AzureDevOpsConnection, cf *httpcli.Factory, au auth.Authenticator) (*AzureDevOpsSource, error) {
+	baseURL, err := url.Parse(c.Url)
+	if err != nil {
+		return nil, err
+	}
+	baseURL = extsvc.NormalizeBaseURL(baseURL)
+
+	if cf == nil {
+		cf = httpcli.ExternalClientFactory
+	}
+
+	cli, err := cf.Doer()
+	if err != nil {
+		return nil, err
+	}
+
+	if au == nil {
+		au = &auth.BasicAuth{Username: c.Username, Password: c.Token}
+	}
+
+	var org string
+	if len(c.Orgs) > 0 {
+		org = c.Orgs[0]
+	} else if len(c.Projects) > 0 {
+		org = strings.SplitN(c.Projects[0], "/", 2)[0]
+	}
+
+	return &AzureDevOpsSource{
+		client: azuredevops.NewClient(baseURL, au, cli),
+		au:     au,
+		org:    org,
+	}, nil
+}
+
+func (s AzureDevOpsSource) GitserverPushConfig(ctx context.Context, store *database.ExternalServiceStore, repo *types.Repo) (*protocol.PushConfig, error) {
+	return gitserverPushConfig(ctx, store, repo, s.au)
+}
+
+func (s AzureDevOpsSource) WithAuthenticator(a auth.Authenticator) (ChangesetSource, error) {
+	switch a.(type) {
+	case *auth.OAuthBearerToken,
+		*auth.OAuthBearerTokenWithSSH,
+		*auth.BasicAuth,
+		*auth.BasicAuthWithSSH:
+		break
+
+	default:
+		return nil, newUnsupportedAuthenticatorError("AzureDevOpsSource", a)
+	}
+
+	sc := s
+	sc.au = a
+	sc.client = sc.client.WithAuthenticator(a)
+
+	return &sc, nil
+}
+
+func (s AzureDevOpsSource) ValidateAuthenticator(ctx context.Context) error {
+	if s.org == "" {
+		return errors.New("no Azure DevOps organization configured to validate credentials against")
+	}
+	_, err := s.client.AuthenticatedUser(ctx, s.org)
+	return err
+}
+
+// CreateChangeset creates an Azure DevOps pull request. If it already exists,
+// *Changeset will be populated and the return value will be true.
+func (s *AzureDevOpsSource) CreateChangeset(ctx context.Context, c *Changeset) (bool, error) {
+	return s.createChangeset(ctx, c, false)
+}
+
+// CreateDraftChangeset creates an Azure DevOps draft pull request. If it
+// already exists, *Changeset will be populated and the return value will be
+// true.
+func (s *AzureDevOpsSource) CreateDraftChangeset(ctx context.Context, c *Changeset) (bool, error) {
+	return s.createChangeset(ctx, c, true)
+}
+
+func (s *AzureDevOpsSource) createChangeset(ctx context.Context, c *Changeset, draft bool) (bool, error) {
+	repo := c.Repo.Metadata.(*azuredevops.Repository)
+	exists := false
+	source := git.EnsureRefPrefix(c.HeadRef)
+	target := git.EnsureRefPrefix(c.BaseRef)
+
+	pr, err := s.client.CreatePullRequest(ctx, repo, &azuredevops.CreatePullRequestInput{
+		SourceRefName: source,
+		TargetRefName: target,
+		Title:         c.Title,
+		Description:   c.Body,
+		IsDraft:       draft,
+	})
+	if err != nil {
+		if err != azuredevops.ErrPullRequestAlreadyExists {
+			return exists, errors.Wrap(err, "creating the pull request")
+		}
+		exists = true
+
+		pr, err = s.client.ActivePullRequestByRefs(ctx, repo, source, target)
+		if err != nil {
+			return exists, errors.Wrap(err, "retrieving an extant pull request")
+		}
+	}
+
+	if err := s.setChangesetMetadata(ctx, repo, pr, c); err != nil {
+		return exists, err
+	}
+	return exists, nil
+}
+
+// CloseChangeset abandons the pull request on Azure DevOps.
+func (s *AzureDevOpsSource) CloseChangeset(ctx context.Context, c *Changeset) error {
+	return s.updateStatus(ctx, c, azuredevops.PullRequestStatusAbandoned)
+}
+
+// ReopenChangeset reactivates the abandoned pull request on Azure DevOps.
+func (s *AzureDevOpsSource) ReopenChangeset(ctx context.Context, c *Changeset) error {
+	return s.updateStatus(ctx, c, azuredevops.PullRequestStatusActive)
+}
+
+func (s *AzureDevOpsSource) updateStatus(ctx context.Context, c *Changeset, status azuredevops.PullRequestStatus) error {
+	repo := c.Repo.Metadata.(*azuredevops.Repository)
+	pr, ok := c.Changeset.Metadata.(*azuredevops.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not an Azure DevOps pull request")
+	}
+
+	updated, err := s.client.UpdatePullRequest(ctx, repo, pr.ID, &azuredevops.UpdatePullRequestInput{Status: status})
+	if err != nil {
+		return errors.Wrapf(err, "setting status of pull request %d to %s", pr.ID, status)
+	}
+
+	return s.setChangesetMetadata(ctx, repo, updated, c)
+}
+
+// LoadChangeset loads the given pull request from Azure DevOps and updates it.
+func (s *AzureDevOpsSource) LoadChangeset(ctx context.Context, cs *Changeset) error {
+	repo := cs.Repo.Metadata.(*azuredevops.Repository)
+
+	id, err := strconv.ParseInt(cs.ExternalID, 10, 64)
+	if err != nil {
+		return errors.Wrapf(err, "parsing changeset external ID %s", cs.ExternalID)
+	}
+
+	pr, err := s.client.PullRequest(ctx, repo, id)
+	if err != nil {
+		if err == azuredevops.ErrPullRequestNotFound {
+			return ChangesetNotFoundError{Changeset: cs}
+		}
+		return errors.Wrapf(err, "retrieving pull request %d", id)
+	}
+
+	return s.setChangesetMetadata(ctx, repo, pr, cs)
+}
+
+// UpdateChangeset updates the pull request on Azure DevOps to reflect the
+// local state of the Changeset.
+func (s *AzureDevOpsSource) UpdateChangeset(ctx context.Context, c *Changeset) error {
+	repo := c.Repo.Metadata.(*azuredevops.Repository)
+	pr, ok := c.Changeset.Metadata.(*azuredevops.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not an Azure DevOps pull request")
+	}
+
+	updated, err := s.client.UpdatePullRequest(ctx, repo, pr.ID, &azuredevops.UpdatePullRequestInput{
+		Title:         c.Title,
+		Description:   c.Body,
+		TargetRefName: git.EnsureRefPrefix(c.BaseRef),
+	})
+	if err != nil {
+		return errors.Wrap(err, "updating Azure DevOps pull request")
+	}
+
+	return s.setChangesetMetadata(ctx, repo, updated, c)
+}
+
+// UndraftChangeset publishes the draft pull request.
+func (s *AzureDevOpsSource) UndraftChangeset(ctx context.Context, c *Changeset) error {
+	repo := c.Repo.Metadata.(*azuredevops.Repository)
+	pr, ok := c.Changeset.Metadata.(*azuredevops.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not an Azure DevOps pull request")
+	}
+
+	isDraft := false
+	updated, err := s.client.UpdatePullRequest(ctx, repo, pr.ID, &azuredevops.UpdatePullRequestInput{IsDraft: &isDraft})
+	if err != nil {
+		return errors.Wrap(err, "undrafting Azure DevOps pull request")
+	}
+
+	return s.setChangesetMetadata(ctx, repo, updated, c)
+}
+
+// CreateComment posts a comment on the Changeset.
+func (s *AzureDevOpsSource) CreateComment(ctx context.Context, c *Changeset, text string) error {
+	repo := c.Repo.Metadata.(*azuredevops.Repository)
+	pr, ok := c.Changeset.Metadata.(*azuredevops.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not an Azure DevOps pull request")
+	}
+
+	return s.client.CreatePullRequestComment(ctx, repo, pr.ID, text)
+}
+
+// MergeChangeset completes the pull request on Azure DevOps, if in a mergeable
+// state. If squash is true, the commits of the pull request are squashed.
+func (s *AzureDevOpsSource) MergeChangeset(ctx context.Context, c *Changeset, squash bool) error {
+	repo := c.Repo.Metadata.(*azuredevops.Repository)
+	pr, ok := c.Changeset.Metadata.(*azuredevops.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not an Azure DevOps pull request")
+	}
+
+	updated, err := s.client.CompletePullRequest(ctx, repo, pr, squash)
+	if err != nil {
+		if errors.Is(err, azuredevops.ErrNotMergeable) {
+			return ChangesetNotMergeableError{ErrorMsg: err.Error()}
+		}
+		return errors.Wrap(err, "completing Azure DevOps pull request")
+	}
+
+	return s.setChangesetMetadata(ctx, repo, updated, c)
+}
+
+// setChangesetMetadata loads the statuses of the pull request, which are not
+// part of the pull request API responses, and sets the pull request as the
+// metadata of the changeset.
+func (s *AzureDevOpsSource) setChangesetMetadata(ctx context.Context, repo *azuredevops.Repository, pr *azuredevops.PullRequest, c *Changeset) error {
+	statuses, err := s.client.PullRequestStatuses(ctx, repo, pr.ID)
+	if err != nil {
+		return errors.Wrapf(err, "retrieving statuses of pull request %d", pr.ID)
+	}
+	pr.Statuses = statuses
+
+	if err := c.SetMetadata(pr); err != nil {
+		return errors.Wrap(err, "setting changeset metadata")
+	}
+	return nil
+}
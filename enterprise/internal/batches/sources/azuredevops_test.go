@@ -0,0 +1,244 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"golang.org/x/time/rate"
+
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+const azureDevOpsPullRequestsPath = "/myorg/project-id/_apis/git/repositories/repo-id/pullrequests"
+
+func newTestAzureDevOpsSource(t *testing.T, h http.HandlerFunc) *AzureDevOpsSource {
+	t.Helper()
+
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	src, err := newAzureDevOpsSource(&schema.AzureDevOpsConnection{
+		Url:      srv.URL,
+		Username: "alice",
+		Token:    "secret",
+		Orgs:     []string{"myorg"},
+	}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src.client.RateLimit = rate.NewLimiter(rate.Inf, 1)
+	return src
+}
+
+func newTestAzureDevOpsChangeset(pr *azuredevops.PullRequest) *Changeset {
+	cs := &Changeset{
+		Title:   "Title",
+		Body:    "Body",
+		HeadRef: "refs/heads/feature",
+		BaseRef: "refs/heads/main",
+		Repo: &types.Repo{
+			Metadata: &azuredevops.Repository{
+				ID:      "repo-id",
+				Name:    "myrepo",
+				Project: azuredevops.Project{ID: "project-id", Name: "myproject"},
+				Org:     "myorg",
+			},
+		},
+		Changeset: &btypes.Changeset{ExternalServiceType: extsvc.TypeAzureDevOps},
+	}
+	if pr != nil {
+		cs.Changeset.Metadata = pr
+	}
+	return cs
+}
+
+func TestAzureDevOpsSource_CreateChangeset(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("created", func(t *testing.T) {
+		src := newTestAzureDevOpsSource(t, func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == "POST" && r.URL.Path == azureDevOpsPullRequestsPath:
+				var input azuredevops.CreatePullRequestInput
+				if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+					t.Fatal(err)
+				}
+				if !input.IsDraft {
+					t.Error("expected draft pull request")
+				}
+				_ = json.NewEncoder(w).Encode(&azuredevops.PullRequest{ID: 7, Title: input.Title, IsDraft: input.IsDraft})
+			case r.Method == "GET" && r.URL.Path == azureDevOpsPullRequestsPath+"/7/statuses":
+				_, _ = w.Write([]byte(`{"value": []}`))
+			default:
+				http.NotFound(w, r)
+			}
+		})
+
+		cs := newTestAzureDevOpsChangeset(nil)
+		exists, err := src.CreateDraftChangeset(ctx, cs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exists {
+			t.Error("unexpectedly existing changeset")
+		}
+		if have, want := cs.ExternalID, "7"; have != want {
+			t.Errorf("wrong external ID: have=%q want=%q", have, want)
+		}
+	})
+
+	t.Run("already exists", func(t *testing.T) {
+		src := newTestAzureDevOpsSource(t, func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == "POST" && r.URL.Path == azureDevOpsPullRequestsPath:
+				w.WriteHeader(http.StatusConflict)
+				_, _ = w.Write([]byte(`{"message": "TF401179: An active pull request for the source and target branch already exists."}`))
+			case r.Method == "GET" && r.URL.Path == azureDevOpsPullRequestsPath:
+				_, _ = w.Write([]byte(`{"value": [{"pullRequestId": 8, "status": "active"}]}`))
+			case r.Method == "GET" && r.URL.Path == azureDevOpsPullRequestsPath+"/8/statuses":
+				_, _ = w.Write([]byte(`{"value": []}`))
+			default:
+				http.NotFound(w, r)
+			}
+		})
+
+		cs := newTestAzureDevOpsChangeset(nil)
+		exists, err := src.CreateChangeset(ctx, cs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !exists {
+			t.Error("changeset unexpectedly does not exist")
+		}
+		if have, want := cs.ExternalID, "8"; have != want {
+			t.Errorf("wrong external ID: have=%q want=%q", have, want)
+		}
+	})
+}
+
+func TestAzureDevOpsSource_LoadChangeset_NotFound(t *testing.T) {
+	src := newTestAzureDevOpsSource(t, http.NotFound)
+
+	cs := newTestAzureDevOpsChangeset(nil)
+	cs.ExternalID = "42"
+
+	err := src.LoadChangeset(context.Background(), cs)
+	if !errors.HasType(err, ChangesetNotFoundError{}) {
+		t.Errorf("unexpected error of type %T: %v", err, err)
+	}
+}
+
+func TestAzureDevOpsSource_MergeChangeset_NotMergeable(t *testing.T) {
+	src := newTestAzureDevOpsSource(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PATCH" || r.URL.Path != azureDevOpsPullRequestsPath+"/7" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"pullRequestId": 7, "status": "active", "mergeStatus": "conflicts"}`))
+	})
+
+	cs := newTestAzureDevOpsChangeset(&azuredevops.PullRequest{
+		ID:                    7,
+		LastMergeSourceCommit: &azuredevops.CommitRef{CommitID: "deadbeef"},
+	})
+
+	err := src.MergeChangeset(context.Background(), cs, false)
+	if !errors.HasType(err, ChangesetNotMergeableError{}) {
+		t.Errorf("unexpected error of type %T: %v", err, err)
+	}
+}
+
+func TestAzureDevOpsSource_UndraftChangeset(t *testing.T) {
+	src := newTestAzureDevOpsSource(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "PATCH" && r.URL.Path == azureDevOpsPullRequestsPath+"/7":
+			var input azuredevops.UpdatePullRequestInput
+			if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+				t.Fatal(err)
+			}
+			if input.IsDraft == nil || *input.IsDraft {
+				t.Errorf("unexpected isDraft: %v", input.IsDraft)
+			}
+			_, _ = w.Write([]byte(`{"pullRequestId": 7, "status": "active", "isDraft": false}`))
+		case r.Method == "GET" && r.URL.Path == azureDevOpsPullRequestsPath+"/7/statuses":
+			_, _ = w.Write([]byte(`{"value": []}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	cs := newTestAzureDevOpsChangeset(&azuredevops.PullRequest{ID: 7, IsDraft: true})
+	if err := src.UndraftChangeset(context.Background(), cs); err != nil {
+		t.Fatal(err)
+	}
+	if cs.Changeset.Metadata.(*azuredevops.PullRequest).IsDraft {
+		t.Error("changeset is still a draft")
+	}
+}
+
+func TestAzureDevOpsSource_WithAuthenticator(t *testing.T) {
+	svc := &types.ExternalService{
+		Kind: extsvc.KindAzureDevOps,
+		Config: marshalJSON(t, &schema.AzureDevOpsConnection{
+			Url:      "https://dev.azure.com",
+			Username: "alice",
+			Token:    "secret",
+			Orgs:     []string{"myorg"},
+		}),
+	}
+
+	adoSrc, err := NewAzureDevOpsSource(svc, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("supported", func(t *testing.T) {
+		for name, tc := range map[string]auth.Authenticator{
+			"BasicAuth":        &auth.BasicAuth{},
+			"OAuthBearerToken": &auth.OAuthBearerToken{},
+		} {
+			t.Run(name, func(t *testing.T) {
+				src, err := adoSrc.WithAuthenticator(tc)
+				if err != nil {
+					t.Errorf("unexpected non-nil error: %v", err)
+				}
+
+				if as, ok := src.(*AzureDevOpsSource); !ok {
+					t.Error("cannot coerce Source into AzureDevOpsSource")
+				} else if as == nil {
+					t.Error("unexpected nil Source")
+				} else if as.au != tc {
+					t.Errorf("incorrect authenticator: have=%v want=%v", as.au, tc)
+				}
+			})
+		}
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		for name, tc := range map[string]auth.Authenticator{
+			"nil":         nil,
+			"OAuthClient": &auth.OAuthClient{},
+		} {
+			t.Run(name, func(t *testing.T) {
+				src, err := adoSrc.WithAuthenticator(tc)
+				if err == nil {
+					t.Error("unexpected nil error")
+				} else if !errors.HasType(err, UnsupportedAuthenticatorError{}) {
+					t.Errorf("unexpected error of type %T: %v", err, err)
+				}
+				if src != nil {
+					t.Errorf("expected non-nil Source: %v", src)
+				}
+			})
+		}
+	})
+}
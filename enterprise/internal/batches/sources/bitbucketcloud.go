@@ -0,0 +1,247 @@
+package sources
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// ErrBitbucketCloudReopen is returned by ReopenChangeset, since declined pull
+// requests on Bitbucket Cloud cannot be reopened.
+var ErrBitbucketCloudReopen = errors.New("declined Bitbucket Cloud pull requests cannot be reopened")
+
+type BitbucketCloudSource struct {
+	client *bitbucketcloud.Client
+	au     auth.Authenticator
+}
+
+var _ ChangesetSource = &BitbucketCloudSource{}
+
+// NewBitbucketCloudSource returns a new BitbucketCloudSource from the given external service.
+func NewBitbucketCloudSource(svc *types.ExternalService, cf *httpcli.Factory) (*BitbucketCloudSource, error) {
+	var c schema.BitbucketCloudConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	return newBitbucketCloudSource(&c, cf, nil)
+}
+
+func newBitbucketCloudSource(c *schema.BitbucketCloudConnection, cf *httpcli.Factory, au auth.Authenticator) (*BitbucketCloudSource, error) {
+	if c.ApiURL == "" {
+		c.ApiURL = "https://api.bitbucket.org"
+	}
+	apiURL, err := url.Parse(c.ApiURL)
+	if err != nil {
+		return nil, err
+	}
+	apiURL = extsvc.NormalizeBaseURL(apiURL)
+
+	if cf == nil {
+		cf = httpcli.ExternalClientFactory
+	}
+
+	cli, err := cf.Doer()
+	if err != nil {
+		return nil, err
+	}
+
+	if au == nil {
+		au = &auth.BasicAuth{Username: c.Username, Password: c.AppPassword}
+	}
+
+	s := &BitbucketCloudSource{client: bitbucketcloud.NewClient(apiURL, cli)}
+	return s.withAuthenticator(au)
+}
+
+func (s BitbucketCloudSource) GitserverPushConfig(ctx context.Context, store *database.ExternalServiceStore, repo *types.Repo) (*protocol.PushConfig, error) {
+	return gitserverPushConfig(ctx, store, repo, s.au)
+}
+
+func (s BitbucketCloudSource) WithAuthenticator(a auth.Authenticator) (ChangesetSource, error) {
+	sc, err := s.withAuthenticator(a)
+	if err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+// withAuthenticator returns a copy of the source using the given
+// authenticator. Bitbucket Cloud only supports app passwords, which are used
+// as the password of basic authentication.
+func (s BitbucketCloudSource) withAuthenticator(a auth.Authenticator) (*BitbucketCloudSource, error) {
+	var username, password string
+	switch a := a.(type) {
+	case *auth.BasicAuth:
+		username, password = a.Username, a.Password
+	case *auth.BasicAuthWithSSH:
+		username, password = a.Username, a.Password
+	default:
+		return nil, newUnsupportedAuthenticatorError("BitbucketCloudSource", a)
+	}
+
+	sc := s
+	sc.au = a
+	sc.client = sc.client.WithCredentials(username, password)
+
+	return &sc, nil
+}
+
+func (s BitbucketCloudSource) ValidateAuthenticator(ctx context.Context) error {
+	_, err := s.client.CurrentUser(ctx)
+	return err
+}
+
+// CreateChangeset creates a Bitbucket Cloud pull request. If it already
+// exists, *Changeset will be populated and the return value will be true.
+func (s *BitbucketCloudSource) CreateChangeset(ctx context.Context, c *Changeset) (bool, error) {
+	repo := c.Repo.Metadata.(*bitbucketcloud.Repo)
+	exists := false
+	source := git.AbbreviateRef(c.HeadRef)
+	destination := git.AbbreviateRef(c.BaseRef)
+
+	pr, err := s.client.CreatePullRequest(ctx, repo, &bitbucketcloud.PullRequestInput{
+		Title:             c.Title,
+		Description:       c.Body,
+		SourceBranch:      source,
+		DestinationBranch: destination,
+	})
+	if err != nil {
+		// Bitbucket Cloud doesn't return a distinct error if the pull request
+		// already exists, so we look for an open one before giving up.
+		extant, lookupErr := s.client.OpenPullRequestByBranches(ctx, repo, source, destination)
+		if lookupErr != nil {
+			return exists, errors.Wrap(err, "creating the pull request")
+		}
+		exists = true
+		pr = extant
+	}
+
+	if err := s.setChangesetMetadata(ctx, repo, pr, c); err != nil {
+		return exists, err
+	}
+	return exists, nil
+}
+
+// CloseChangeset declines the pull request on Bitbucket Cloud.
+func (s *BitbucketCloudSource) CloseChangeset(ctx context.Context, c *Changeset) error {
+	repo := c.Repo.Metadata.(*bitbucketcloud.Repo)
+	pr, ok := c.Changeset.Metadata.(*bitbucketcloud.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a Bitbucket Cloud pull request")
+	}
+
+	declined, err := s.client.DeclinePullRequest(ctx, repo, pr.ID)
+	if err != nil {
+		return errors.Wrapf(err, "declining pull request %d", pr.ID)
+	}
+
+	return s.setChangesetMetadata(ctx, repo, declined, c)
+}
+
+// ReopenChangeset always returns ErrBitbucketCloudReopen, since Bitbucket
+// Cloud doesn't support reopening declined pull requests.
+func (s *BitbucketCloudSource) ReopenChangeset(ctx context.Context, c *Changeset) error {
+	return ErrBitbucketCloudReopen
+}
+
+// LoadChangeset loads the given pull request from Bitbucket Cloud and updates it.
+func (s *BitbucketCloudSource) LoadChangeset(ctx context.Context, cs *Changeset) error {
+	repo := cs.Repo.Metadata.(*bitbucketcloud.Repo)
+
+	id, err := strconv.ParseInt(cs.ExternalID, 10, 64)
+	if err != nil {
+		return errors.Wrapf(err, "parsing changeset external ID %s", cs.ExternalID)
+	}
+
+	pr, err := s.client.PullRequest(ctx, repo, id)
+	if err != nil {
+		if err == bitbucketcloud.ErrPullRequestNotFound {
+			return ChangesetNotFoundError{Changeset: cs}
+		}
+		return errors.Wrapf(err, "retrieving pull request %d", id)
+	}
+
+	return s.setChangesetMetadata(ctx, repo, pr, cs)
+}
+
+// UpdateChangeset updates the pull request on Bitbucket Cloud to reflect the
+// local state of the Changeset.
+func (s *BitbucketCloudSource) UpdateChangeset(ctx context.Context, c *Changeset) error {
+	repo := c.Repo.Metadata.(*bitbucketcloud.Repo)
+	pr, ok := c.Changeset.Metadata.(*bitbucketcloud.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a Bitbucket Cloud pull request")
+	}
+
+	updated, err := s.client.UpdatePullRequest(ctx, repo, pr.ID, &bitbucketcloud.PullRequestInput{
+		Title:             c.Title,
+		Description:       c.Body,
+		DestinationBranch: git.AbbreviateRef(c.BaseRef),
+	})
+	if err != nil {
+		return errors.Wrap(err, "updating Bitbucket Cloud pull request")
+	}
+
+	return s.setChangesetMetadata(ctx, repo, updated, c)
+}
+
+// CreateComment posts a comment on the Changeset.
+func (s *BitbucketCloudSource) CreateComment(ctx context.Context, c *Changeset, text string) error {
+	repo := c.Repo.Metadata.(*bitbucketcloud.Repo)
+	pr, ok := c.Changeset.Metadata.(*bitbucketcloud.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a Bitbucket Cloud pull request")
+	}
+
+	return s.client.CreatePullRequestComment(ctx, repo, pr.ID, text)
+}
+
+// MergeChangeset merges the pull request on Bitbucket Cloud, if in a
+// mergeable state. If squash is true, the commits of the pull request are
+// squashed.
+func (s *BitbucketCloudSource) MergeChangeset(ctx context.Context, c *Changeset, squash bool) error {
+	repo := c.Repo.Metadata.(*bitbucketcloud.Repo)
+	pr, ok := c.Changeset.Metadata.(*bitbucketcloud.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a Bitbucket Cloud pull request")
+	}
+
+	merged, err := s.client.MergePullRequest(ctx, repo, pr.ID, squash)
+	if err != nil {
+		if errors.Is(err, bitbucketcloud.ErrNotMergeable) {
+			return ChangesetNotMergeableError{ErrorMsg: err.Error()}
+		}
+		return errors.Wrap(err, "merging Bitbucket Cloud pull request")
+	}
+
+	return s.setChangesetMetadata(ctx, repo, merged, c)
+}
+
+// setChangesetMetadata loads the statuses of the pull request, which are not
+// part of the pull request API responses, and sets the pull request as the
+// metadata of the changeset.
+func (s *BitbucketCloudSource) setChangesetMetadata(ctx context.Context, repo *bitbucketcloud.Repo, pr *bitbucketcloud.PullRequest, c *Changeset) error {
+	statuses, err := s.client.PullRequestStatuses(ctx, repo, pr.ID)
+	if err != nil {
+		return errors.Wrapf(err, "retrieving statuses of pull request %d", pr.ID)
+	}
+	pr.Statuses = statuses
+
+	if err := c.SetMetadata(pr); err != nil {
+		return errors.Wrap(err, "setting changeset metadata")
+	}
+	return nil
+}
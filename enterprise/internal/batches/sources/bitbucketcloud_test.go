@@ -0,0 +1,252 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"golang.org/x/time/rate"
+
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+const bitbucketCloudPullRequestsPath = "/2.0/repositories/my-workspace/my-repo/pullrequests"
+
+func newTestBitbucketCloudSource(t *testing.T, h http.HandlerFunc) *BitbucketCloudSource {
+	t.Helper()
+
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	src, err := newBitbucketCloudSource(&schema.BitbucketCloudConnection{
+		ApiURL:      srv.URL,
+		Username:    "alice",
+		AppPassword: "secret",
+	}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src.client.RateLimit = rate.NewLimiter(rate.Inf, 1)
+	return src
+}
+
+func newTestBitbucketCloudChangeset(pr *bitbucketcloud.PullRequest) *Changeset {
+	cs := &Changeset{
+		Title:   "Title",
+		Body:    "Body",
+		HeadRef: "refs/heads/feature",
+		BaseRef: "refs/heads/main",
+		Repo: &types.Repo{
+			Metadata: &bitbucketcloud.Repo{
+				Slug:     "my-repo",
+				FullName: "my-workspace/my-repo",
+				UUID:     "{repo-uuid}",
+			},
+		},
+		Changeset: &btypes.Changeset{ExternalServiceType: extsvc.TypeBitbucketCloud},
+	}
+	if pr != nil {
+		cs.Changeset.Metadata = pr
+	}
+	return cs
+}
+
+func TestBitbucketCloudSource_CreateChangeset(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("created", func(t *testing.T) {
+		src := newTestBitbucketCloudSource(t, func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == "POST" && r.URL.Path == bitbucketCloudPullRequestsPath:
+				var input struct {
+					Title  string `json:"title"`
+					Source struct {
+						Branch struct {
+							Name string `json:"name"`
+						} `json:"branch"`
+					} `json:"source"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+					t.Fatal(err)
+				}
+				if have, want := input.Source.Branch.Name, "feature"; have != want {
+					t.Errorf("wrong source branch: have=%q want=%q", have, want)
+				}
+				_, _ = w.Write([]byte(`{"id": 7, "title": "Title", "state": "OPEN", "source": {"branch": {"name": "feature"}}}`))
+			case r.Method == "GET" && r.URL.Path == bitbucketCloudPullRequestsPath+"/7/statuses":
+				_, _ = w.Write([]byte(`{"values": [{"key": "build", "state": "INPROGRESS"}]}`))
+			default:
+				http.NotFound(w, r)
+			}
+		})
+
+		cs := newTestBitbucketCloudChangeset(nil)
+		exists, err := src.CreateChangeset(ctx, cs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exists {
+			t.Error("unexpectedly existing changeset")
+		}
+		if have, want := cs.ExternalID, "7"; have != want {
+			t.Errorf("wrong external ID: have=%q want=%q", have, want)
+		}
+		if have, want := cs.ExternalBranch, "refs/heads/feature"; have != want {
+			t.Errorf("wrong external branch: have=%q want=%q", have, want)
+		}
+		if statuses := cs.Changeset.Metadata.(*bitbucketcloud.PullRequest).Statuses; len(statuses) != 1 {
+			t.Errorf("wrong number of statuses: %d", len(statuses))
+		}
+	})
+
+	t.Run("already exists", func(t *testing.T) {
+		src := newTestBitbucketCloudSource(t, func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == "POST" && r.URL.Path == bitbucketCloudPullRequestsPath:
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"type": "error", "error": {"message": "There are no changes to be pulled"}}`))
+			case r.Method == "GET" && r.URL.Path == bitbucketCloudPullRequestsPath:
+				_, _ = w.Write([]byte(`{"values": [{"id": 8, "state": "OPEN"}]}`))
+			case r.Method == "GET" && r.URL.Path == bitbucketCloudPullRequestsPath+"/8/statuses":
+				_, _ = w.Write([]byte(`{"values": []}`))
+			default:
+				http.NotFound(w, r)
+			}
+		})
+
+		cs := newTestBitbucketCloudChangeset(nil)
+		exists, err := src.CreateChangeset(ctx, cs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !exists {
+			t.Error("changeset unexpectedly does not exist")
+		}
+		if have, want := cs.ExternalID, "8"; have != want {
+			t.Errorf("wrong external ID: have=%q want=%q", have, want)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		src := newTestBitbucketCloudSource(t, func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == "POST" && r.URL.Path == bitbucketCloudPullRequestsPath:
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"type": "error", "error": {"message": "bad branch"}}`))
+			case r.Method == "GET" && r.URL.Path == bitbucketCloudPullRequestsPath:
+				_, _ = w.Write([]byte(`{"values": []}`))
+			default:
+				http.NotFound(w, r)
+			}
+		})
+
+		if _, err := src.CreateChangeset(ctx, newTestBitbucketCloudChangeset(nil)); err == nil {
+			t.Error("unexpected nil error")
+		}
+	})
+}
+
+func TestBitbucketCloudSource_LoadChangeset_NotFound(t *testing.T) {
+	src := newTestBitbucketCloudSource(t, http.NotFound)
+
+	cs := newTestBitbucketCloudChangeset(nil)
+	cs.ExternalID = "42"
+
+	err := src.LoadChangeset(context.Background(), cs)
+	if !errors.HasType(err, ChangesetNotFoundError{}) {
+		t.Errorf("unexpected error of type %T: %v", err, err)
+	}
+}
+
+func TestBitbucketCloudSource_MergeChangeset_NotMergeable(t *testing.T) {
+	src := newTestBitbucketCloudSource(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != bitbucketCloudPullRequestsPath+"/7/merge" {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"type": "error", "error": {"message": "You can't merge until you resolve all merge conflicts."}}`))
+	})
+
+	cs := newTestBitbucketCloudChangeset(&bitbucketcloud.PullRequest{ID: 7})
+
+	err := src.MergeChangeset(context.Background(), cs, false)
+	if !errors.HasType(err, ChangesetNotMergeableError{}) {
+		t.Errorf("unexpected error of type %T: %v", err, err)
+	}
+}
+
+func TestBitbucketCloudSource_ReopenChangeset(t *testing.T) {
+	src := newTestBitbucketCloudSource(t, http.NotFound)
+
+	cs := newTestBitbucketCloudChangeset(&bitbucketcloud.PullRequest{ID: 7})
+	if err := src.ReopenChangeset(context.Background(), cs); err != ErrBitbucketCloudReopen {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestBitbucketCloudSource_WithAuthenticator(t *testing.T) {
+	svc := &types.ExternalService{
+		Kind: extsvc.KindBitbucketCloud,
+		Config: marshalJSON(t, &schema.BitbucketCloudConnection{
+			Url:         "https://bitbucket.org",
+			Username:    "alice",
+			AppPassword: "secret",
+		}),
+	}
+
+	bbcSrc, err := NewBitbucketCloudSource(svc, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("supported", func(t *testing.T) {
+		for name, tc := range map[string]auth.Authenticator{
+			"BasicAuth":        &auth.BasicAuth{},
+			"BasicAuthWithSSH": &auth.BasicAuthWithSSH{},
+		} {
+			t.Run(name, func(t *testing.T) {
+				src, err := bbcSrc.WithAuthenticator(tc)
+				if err != nil {
+					t.Errorf("unexpected non-nil error: %v", err)
+				}
+
+				if bs, ok := src.(*BitbucketCloudSource); !ok {
+					t.Error("cannot coerce Source into BitbucketCloudSource")
+				} else if bs == nil {
+					t.Error("unexpected nil Source")
+				} else if bs.au != tc {
+					t.Errorf("incorrect authenticator: have=%v want=%v", bs.au, tc)
+				}
+			})
+		}
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		for name, tc := range map[string]auth.Authenticator{
+			"nil":              nil,
+			"OAuthBearerToken": &auth.OAuthBearerToken{},
+			"OAuthClient":      &auth.OAuthClient{},
+		} {
+			t.Run(name, func(t *testing.T) {
+				src, err := bbcSrc.WithAuthenticator(tc)
+				if err == nil {
+					t.Error("unexpected nil error")
+				} else if !errors.HasType(err, UnsupportedAuthenticatorError{}) {
+					t.Errorf("unexpected error of type %T: %v", err, err)
+				}
+				if src != nil {
+					t.Errorf("expected non-nil Source: %v", src)
+				}
+			})
+		}
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
 )
 
 // ChangesetNotFoundError is returned by LoadChangeset if the changeset
@@ -97,6 +98,10 @@ type Changeset struct {
 	HeadRef string
 	BaseRef string
 
+	// GitLab holds the GitLab specific options of the changeset template. It is
+	// only used when creating merge requests and ignored by other sources.
+	GitLab *batcheslib.GitLabChangesetTemplate
+
 	*btypes.Changeset
 	*types.Repo
 }
@@ -0,0 +1,49 @@
+package sources
+
+import (
+	"context"
+	"strings"
+)
+
+// A CredentialCheckingSource can check, beyond whether its authenticator is
+// usable at all, whether it has everything needed to publish changesets.
+type CredentialCheckingSource interface {
+	// MissingScopes returns the scopes required to publish changesets that
+	// were not granted to the currently set authenticator.
+	MissingScopes(ctx context.Context) ([]string, error)
+	// HasSSHPublicKey returns whether the given SSH public key has been added
+	// to the account of the user the currently set authenticator belongs to.
+	HasSSHPublicKey(ctx context.Context, publicKey string) (bool, error)
+}
+
+// missingScopes returns the required scopes that are neither granted nor
+// implied by a granted scope. implied maps a scope to the broader scopes that
+// include it.
+func missingScopes(required, granted []string, implied map[string][]string) []string {
+	grantedSet := make(map[string]struct{}, len(granted))
+	for _, scope := range granted {
+		grantedSet[strings.TrimSpace(scope)] = struct{}{}
+	}
+
+	var missing []string
+outer:
+	for _, scope := range required {
+		for _, s := range append([]string{scope}, implied[scope]...) {
+			if _, ok := grantedSet[s]; ok {
+				continue outer
+			}
+		}
+		missing = append(missing, scope)
+	}
+	return missing
+}
+
+// samePublicKey returns whether the two SSH public keys in authorized_keys
+// format are the same key, ignoring their comments.
+func samePublicKey(a, b string) bool {
+	fa, fb := strings.Fields(a), strings.Fields(b)
+	if len(fa) < 2 || len(fb) < 2 {
+		return false
+	}
+	return fa[0] == fb[0] && fa[1] == fb[1]
+}
@@ -0,0 +1,102 @@
+package sources
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestMissingScopes(t *testing.T) {
+	implied := map[string][]string{"read:org": {"admin:org"}}
+
+	for name, tc := range map[string]struct {
+		granted []string
+		want    []string
+	}{
+		"all granted":       {granted: []string{"repo", "read:org"}},
+		"implied":           {granted: []string{"repo", "admin:org"}},
+		"none granted":      {granted: []string{}, want: []string{"repo", "read:org"}},
+		"partly granted":    {granted: []string{"read:org", "gist"}, want: []string{"repo"}},
+		"surrounding space": {granted: []string{" repo", "read:org "}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			have := missingScopes([]string{"repo", "read:org"}, tc.granted, implied)
+			if diff := cmp.Diff(tc.want, have); diff != "" {
+				t.Errorf("unexpected missing scopes (-want +have):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSamePublicKey(t *testing.T) {
+	const key = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQC7"
+
+	for _, tc := range []struct {
+		a, b string
+		want bool
+	}{
+		{a: key, b: key, want: true},
+		{a: key + " Sourcegraph sourcegraph.example.com", b: key + "\n", want: true},
+		{a: key, b: "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQC8", want: false},
+		{a: key, b: "ssh-ed25519 AAAAB3NzaC1yc2EAAAADAQABAAABAQC7", want: false},
+		{a: "", b: "", want: false},
+	} {
+		if have := samePublicKey(tc.a, tc.b); have != tc.want {
+			t.Errorf("samePublicKey(%q, %q): want=%t have=%t", tc.a, tc.b, tc.want, have)
+		}
+	}
+}
+
+func TestGithubSource_CheckCredential(t *testing.T) {
+	svc := &types.ExternalService{
+		Kind: extsvc.KindGitHub,
+		Config: marshalJSON(t, &schema.GitHubConnection{
+			Url:   "https://github.com",
+			Token: "abc",
+		}),
+	}
+
+	src, err := NewGithubSource(svc, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("MissingScopes", func(t *testing.T) {
+		github.MockGetAuthenticatedOAuthScopes = func(ctx context.Context) ([]string, error) {
+			return []string{"repo", "admin:org", "user"}, nil
+		}
+		t.Cleanup(func() { github.MockGetAuthenticatedOAuthScopes = nil })
+
+		have, err := src.MissingScopes(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]string{"read:discussion"}, have); diff != "" {
+			t.Errorf("unexpected missing scopes (-want +have):\n%s", diff)
+		}
+	})
+
+	t.Run("HasSSHPublicKey", func(t *testing.T) {
+		github.MockGetAuthenticatedUserPublicKeys = func(ctx context.Context) ([]*github.PublicKey, error) {
+			return []*github.PublicKey{{ID: 1, Key: "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQC7"}}, nil
+		}
+		t.Cleanup(func() { github.MockGetAuthenticatedUserPublicKeys = nil })
+
+		if ok, err := src.HasSSHPublicKey(context.Background(), "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQC7 Sourcegraph"); err != nil {
+			t.Fatal(err)
+		} else if !ok {
+			t.Error("expected public key to be found")
+		}
+		if ok, err := src.HasSSHPublicKey(context.Background(), "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQC8"); err != nil {
+			t.Fatal(err)
+		} else if ok {
+			t.Error("unexpected public key found")
+		}
+	})
+}
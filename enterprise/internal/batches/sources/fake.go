@@ -68,6 +68,11 @@ type FakeChangesetSource struct {
 	// When true, ValidateAuthenticator will return no error.
 	AuthenticatorIsValid bool
 
+	// The scopes returned by MissingScopes.
+	FakeMissingScopes []string
+	// The SSH public keys HasSSHPublicKey looks up the given key in.
+	SSHPublicKeys []string
+
 	// error to be returned from every method
 	Err error
 
@@ -97,6 +102,7 @@ type FakeChangesetSource struct {
 
 var _ ChangesetSource = &FakeChangesetSource{}
 var _ DraftChangesetSource = &FakeChangesetSource{}
+var _ CredentialCheckingSource = &FakeChangesetSource{}
 
 func (s *FakeChangesetSource) CreateDraftChangeset(ctx context.Context, c *Changeset) (bool, error) {
 	s.CreateDraftChangesetCalled = true
@@ -274,6 +280,22 @@ func (s *FakeChangesetSource) ValidateAuthenticator(context.Context) error {
 	return errors.New("invalid authenticator in fake source")
 }
 
+func (s *FakeChangesetSource) MissingScopes(context.Context) ([]string, error) {
+	return s.FakeMissingScopes, s.Err
+}
+
+func (s *FakeChangesetSource) HasSSHPublicKey(ctx context.Context, publicKey string) (bool, error) {
+	if s.Err != nil {
+		return false, s.Err
+	}
+	for _, key := range s.SSHPublicKeys {
+		if samePublicKey(key, publicKey) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func (s *FakeChangesetSource) AuthenticatedUsername(ctx context.Context) (string, error) {
 	s.AuthenticatedUsernameCalled = true
 	return s.Username, nil
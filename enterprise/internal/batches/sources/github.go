@@ -21,10 +21,13 @@ import (
 )
 
 type GithubSource struct {
-	client *github.V4Client
-	au     auth.Authenticator
+	client   *github.V4Client
+	v3Client *github.V3Client
+	au       auth.Authenticator
 }
 
+var _ CredentialCheckingSource = &GithubSource{}
+
 func NewGithubSource(svc *types.ExternalService, cf *httpcli.Factory) (*GithubSource, error) {
 	var c schema.GitHubConnection
 	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
@@ -63,12 +66,16 @@ func newGithubSource(c *schema.GitHubConnection, cf *httpcli.Factory, au auth.Au
 
 	var authr = au
 	if au == nil {
-		authr = &auth.OAuthBearerToken{Token: c.Token}
+		authr, err = github.NewAuthenticatorFromConfig(apiURL, c, cli)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return &GithubSource{
-		au:     authr,
-		client: github.NewV4Client(apiURL, authr, cli),
+		au:       authr,
+		client:   github.NewV4Client(apiURL, authr, cli),
+		v3Client: github.NewV3Client(apiURL, authr, cli),
 	}, nil
 }
 
@@ -89,6 +96,7 @@ func (s GithubSource) WithAuthenticator(a auth.Authenticator) (ChangesetSource,
 	sc := s
 	sc.au = a
 	sc.client = sc.client.WithAuthenticator(a)
+	sc.v3Client = sc.v3Client.WithAuthenticator(a)
 
 	return &sc, nil
 }
@@ -98,6 +106,39 @@ func (s GithubSource) ValidateAuthenticator(ctx context.Context) error {
 	return err
 }
 
+// githubRequiredScopes are the OAuth scopes a token requires to publish
+// changesets on GitHub, as documented for Batch Changes credentials.
+var githubRequiredScopes = []string{"repo", "read:org", "user:email", "read:discussion"}
+
+// githubImpliedScopes maps the required scopes to the broader scopes that
+// include them.
+var githubImpliedScopes = map[string][]string{
+	"read:org":        {"write:org", "admin:org"},
+	"user:email":      {"user"},
+	"read:discussion": {"write:discussion"},
+}
+
+func (s GithubSource) MissingScopes(ctx context.Context) ([]string, error) {
+	scopes, err := s.v3Client.GetAuthenticatedOAuthScopes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return missingScopes(githubRequiredScopes, scopes, githubImpliedScopes), nil
+}
+
+func (s GithubSource) HasSSHPublicKey(ctx context.Context, publicKey string) (bool, error) {
+	keys, err := s.v3Client.GetAuthenticatedUserPublicKeys(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, key := range keys {
+		if samePublicKey(key.Key, publicKey) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // CreateChangeset creates the given changeset on the code host.
 func (s GithubSource) CreateChangeset(ctx context.Context, c *Changeset) (bool, error) {
 	input := buildCreatePullRequestInput(c)
@@ -16,6 +16,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/jsonc"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
@@ -26,6 +27,7 @@ type GitLabSource struct {
 
 var _ ChangesetSource = &GitLabSource{}
 var _ DraftChangesetSource = &GitLabSource{}
+var _ CredentialCheckingSource = &GitLabSource{}
 
 // NewGitLabSource returns a new GitLabSource from the given external service.
 func NewGitLabSource(svc *types.ExternalService, cf *httpcli.Factory) (*GitLabSource, error) {
@@ -102,6 +104,31 @@ func (s GitLabSource) ValidateAuthenticator(ctx context.Context) error {
 	return s.client.ValidateToken(ctx)
 }
 
+// gitLabRequiredScopes are the scopes a token requires to publish changesets
+// on GitLab, as documented for Batch Changes credentials.
+var gitLabRequiredScopes = []string{"api", "read_repository", "write_repository"}
+
+func (s GitLabSource) MissingScopes(ctx context.Context) ([]string, error) {
+	scopes, err := s.client.GetAuthenticatedUserOAuthScopes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return missingScopes(gitLabRequiredScopes, scopes, nil), nil
+}
+
+func (s GitLabSource) HasSSHPublicKey(ctx context.Context, publicKey string) (bool, error) {
+	keys, err := s.client.ListAuthenticatedUserSSHKeys(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, key := range keys {
+		if samePublicKey(key.Key, publicKey) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // CreateChangeset creates a GitLab merge request. If it already exists,
 // *Changeset will be populated and the return value will be true.
 func (s *GitLabSource) CreateChangeset(ctx context.Context, c *Changeset) (bool, error) {
@@ -110,12 +137,21 @@ func (s *GitLabSource) CreateChangeset(ctx context.Context, c *Changeset) (bool,
 	source := git.AbbreviateRef(c.HeadRef)
 	target := git.AbbreviateRef(c.BaseRef)
 
-	mr, err := s.client.CreateMergeRequest(ctx, project, gitlab.CreateMergeRequestOpts{
+	opts := gitlab.CreateMergeRequestOpts{
 		SourceBranch: source,
 		TargetBranch: target,
 		Title:        c.Title,
 		Description:  c.Body,
-	})
+	}
+	if c.GitLab != nil {
+		reviewerIDs, err := s.getUserIDs(ctx, c.GitLab.Reviewers)
+		if err != nil {
+			return exists, errors.Wrap(err, "resolving reviewers")
+		}
+		opts.ReviewerIDs = reviewerIDs
+	}
+
+	mr, err := s.client.CreateMergeRequest(ctx, project, opts)
 	if err != nil {
 		if err == gitlab.ErrMergeRequestAlreadyExists {
 			exists = true
@@ -129,6 +165,14 @@ func (s *GitLabSource) CreateChangeset(ctx context.Context, c *Changeset) (bool,
 		}
 	}
 
+	// Approval rules are only added to merge requests we just created, so that
+	// we don't add the same rules again to an existing merge request.
+	if !exists && c.GitLab != nil {
+		if err := s.createApprovalRules(ctx, project, mr, c.GitLab.ApprovalRules); err != nil {
+			return exists, errors.Wrapf(err, "creating approval rules for merge request %d", mr.IID)
+		}
+	}
+
 	// These additional API calls can go away once we can use the GraphQL API.
 	if err := s.decorateMergeRequestData(ctx, project, mr); err != nil {
 		return exists, errors.Wrapf(err, "retrieving additional data for merge request %d", mr.IID)
@@ -156,7 +200,7 @@ func (s *GitLabSource) CreateDraftChangeset(ctx context.Context, c *Changeset) (
 	}
 
 	// If it already exists, but is not a WIP, we need to update the title.
-	if exists && !mr.WorkInProgress {
+	if exists && !mr.IsDraft() {
 		if err := s.UpdateChangeset(ctx, c); err != nil {
 			return exists, err
 		}
@@ -269,9 +313,47 @@ func (s *GitLabSource) decorateMergeRequestData(ctx context.Context, project *gi
 		return errors.Wrap(err, "retrieving pipelines")
 	}
 
+	approvals, err := s.client.GetMergeRequestApprovals(ctx, project, mr.IID)
+	if err != nil {
+		return errors.Wrap(err, "retrieving approvals")
+	}
+
 	mr.Notes = notes
 	mr.Pipelines = pipelines
 	mr.ResourceStateEvents = events
+	mr.Approvals = approvals
+	return nil
+}
+
+// getUserIDs resolves the given GitLab usernames to their user IDs.
+func (s *GitLabSource) getUserIDs(ctx context.Context, usernames []string) ([]int32, error) {
+	ids := make([]int32, 0, len(usernames))
+	for _, username := range usernames {
+		user, err := s.client.GetUserByUsername(ctx, username)
+		if err != nil {
+			return nil, errors.Wrapf(err, "looking up user %q", username)
+		}
+		ids = append(ids, user.ID)
+	}
+	return ids, nil
+}
+
+// createApprovalRules adds the given approval rules to the merge request.
+func (s *GitLabSource) createApprovalRules(ctx context.Context, project *gitlab.Project, mr *gitlab.MergeRequest, rules []batcheslib.GitLabApprovalRule) error {
+	for _, rule := range rules {
+		userIDs, err := s.getUserIDs(ctx, rule.Users)
+		if err != nil {
+			return errors.Wrapf(err, "resolving users of approval rule %q", rule.Name)
+		}
+
+		if err := s.client.CreateMergeRequestApprovalRule(ctx, project, mr, gitlab.CreateMergeRequestApprovalRuleOpts{
+			Name:              rule.Name,
+			ApprovalsRequired: rule.ApprovalsRequired,
+			UserIDs:           userIDs,
+		}); err != nil {
+			return errors.Wrapf(err, "creating approval rule %q", rule.Name)
+		}
+	}
 	return nil
 }
 
@@ -395,7 +477,7 @@ func (s *GitLabSource) UpdateChangeset(ctx context.Context, c *Changeset) error
 	// Avoid accidentally undrafting the changeset by checking its current
 	// status.
 	title := c.Title
-	if mr.WorkInProgress {
+	if mr.IsDraft() {
 		title = gitlab.SetWIP(c.Title)
 	}
 
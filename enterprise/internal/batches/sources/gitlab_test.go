@@ -18,6 +18,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/testutil"
 	"github.com/sourcegraph/sourcegraph/internal/types"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
@@ -83,6 +84,7 @@ func TestGitLabSource_ChangesetSource(t *testing.T) {
 			p.mockGetMergeRequestNotes(p.mr.IID, nil, 20, nil)
 			p.mockGetMergeRequestResourceStateEvents(p.mr.IID, nil, 20, nil)
 			p.mockGetMergeRequestPipelines(p.mr.IID, nil, 20, nil)
+			p.mockGetMergeRequestApprovals(p.mr.IID, nil, nil)
 			p.mockGetOpenMergeRequestByRefs(p.mr, nil)
 
 			exists, err := p.source.CreateChangeset(p.ctx, p.changeset)
@@ -107,6 +109,7 @@ func TestGitLabSource_ChangesetSource(t *testing.T) {
 			p.mockGetMergeRequestNotes(p.mr.IID, nil, 20, nil)
 			p.mockGetMergeRequestResourceStateEvents(p.mr.IID, nil, 20, nil)
 			p.mockGetMergeRequestPipelines(p.mr.IID, nil, 20, nil)
+			p.mockGetMergeRequestApprovals(p.mr.IID, nil, nil)
 
 			exists, err := p.source.CreateChangeset(p.ctx, p.changeset)
 			if exists {
@@ -122,6 +125,85 @@ func TestGitLabSource_ChangesetSource(t *testing.T) {
 		})
 	})
 
+	t.Run("CreateChangeset with GitLab options", func(t *testing.T) {
+		t.Run("merge request is new", func(t *testing.T) {
+			p := newGitLabChangesetSourceTestProvider(t)
+			p.changeset.GitLab = &batcheslib.GitLabChangesetTemplate{
+				Reviewers: []string{"alice"},
+				ApprovalRules: []batcheslib.GitLabApprovalRule{
+					{Name: "security", ApprovalsRequired: 1, Users: []string{"bob"}},
+				},
+			}
+			p.mockGetUserByUsername(map[string]int32{"alice": 5, "bob": 6})
+			gitlab.MockCreateMergeRequest = func(client *gitlab.Client, ctx context.Context, project *gitlab.Project, opts gitlab.CreateMergeRequestOpts) (*gitlab.MergeRequest, error) {
+				if diff := cmp.Diff([]int32{5}, opts.ReviewerIDs); diff != "" {
+					t.Errorf("unexpected reviewer IDs (-want +have):\n%s", diff)
+				}
+				return p.mr, nil
+			}
+			var rules []gitlab.CreateMergeRequestApprovalRuleOpts
+			gitlab.MockCreateMergeRequestApprovalRule = func(client *gitlab.Client, ctx context.Context, project *gitlab.Project, mr *gitlab.MergeRequest, opts gitlab.CreateMergeRequestApprovalRuleOpts) error {
+				rules = append(rules, opts)
+				return nil
+			}
+			p.mockGetMergeRequestNotes(p.mr.IID, nil, 20, nil)
+			p.mockGetMergeRequestResourceStateEvents(p.mr.IID, nil, 20, nil)
+			p.mockGetMergeRequestPipelines(p.mr.IID, nil, 20, nil)
+			p.mockGetMergeRequestApprovals(p.mr.IID, nil, nil)
+
+			if _, err := p.source.CreateChangeset(p.ctx, p.changeset); err != nil {
+				t.Fatalf("unexpected non-nil err: %+v", err)
+			}
+
+			want := []gitlab.CreateMergeRequestApprovalRuleOpts{
+				{Name: "security", ApprovalsRequired: 1, UserIDs: []int32{6}},
+			}
+			if diff := cmp.Diff(want, rules); diff != "" {
+				t.Errorf("unexpected approval rules (-want +have):\n%s", diff)
+			}
+		})
+
+		t.Run("merge request already exists", func(t *testing.T) {
+			p := newGitLabChangesetSourceTestProvider(t)
+			p.changeset.GitLab = &batcheslib.GitLabChangesetTemplate{
+				ApprovalRules: []batcheslib.GitLabApprovalRule{
+					{Name: "security", ApprovalsRequired: 1},
+				},
+			}
+			p.mockCreateMergeRequest(gitlab.CreateMergeRequestOpts{
+				SourceBranch: p.mr.SourceBranch,
+				TargetBranch: p.mr.TargetBranch,
+			}, nil, gitlab.ErrMergeRequestAlreadyExists)
+			p.mockGetOpenMergeRequestByRefs(p.mr, nil)
+			gitlab.MockCreateMergeRequestApprovalRule = func(client *gitlab.Client, ctx context.Context, project *gitlab.Project, mr *gitlab.MergeRequest, opts gitlab.CreateMergeRequestApprovalRuleOpts) error {
+				t.Error("unexpected call to CreateMergeRequestApprovalRule")
+				return nil
+			}
+			p.mockGetMergeRequestNotes(p.mr.IID, nil, 20, nil)
+			p.mockGetMergeRequestResourceStateEvents(p.mr.IID, nil, 20, nil)
+			p.mockGetMergeRequestPipelines(p.mr.IID, nil, 20, nil)
+			p.mockGetMergeRequestApprovals(p.mr.IID, nil, nil)
+
+			exists, err := p.source.CreateChangeset(p.ctx, p.changeset)
+			if !exists {
+				t.Errorf("unexpected exists value: %v", exists)
+			}
+			if err != nil {
+				t.Errorf("unexpected non-nil err: %+v", err)
+			}
+		})
+
+		t.Run("unknown reviewer", func(t *testing.T) {
+			p := newGitLabChangesetSourceTestProvider(t)
+			p.changeset.GitLab = &batcheslib.GitLabChangesetTemplate{Reviewers: []string{"mallory"}}
+			p.mockGetUserByUsername(map[string]int32{})
+
+			if _, err := p.source.CreateChangeset(p.ctx, p.changeset); !errors.Is(err, gitlab.ErrUserNotFound) {
+				t.Errorf("unexpected error: have %+v; want %+v", err, gitlab.ErrUserNotFound)
+			}
+		})
+	})
+
 	t.Run("CloseChangeset", func(t *testing.T) {
 		t.Run("invalid metadata", func(t *testing.T) {
 			defer func() { _ = recover() }()
@@ -159,6 +241,7 @@ func TestGitLabSource_ChangesetSource(t *testing.T) {
 			p.mockGetMergeRequestNotes(mr.IID, nil, 20, nil)
 			p.mockGetMergeRequestResourceStateEvents(mr.IID, nil, 20, nil)
 			p.mockGetMergeRequestPipelines(mr.IID, nil, 20, nil)
+			p.mockGetMergeRequestApprovals(mr.IID, nil, nil)
 
 			if err := p.source.CloseChangeset(p.ctx, p.changeset); err != nil {
 				t.Errorf("unexpected error: %+v", err)
@@ -204,6 +287,7 @@ func TestGitLabSource_ChangesetSource(t *testing.T) {
 			// TODO: add event
 			p.mockGetMergeRequestResourceStateEvents(mr.IID, nil, 20, nil)
 			p.mockGetMergeRequestPipelines(mr.IID, nil, 20, nil)
+			p.mockGetMergeRequestApprovals(mr.IID, nil, nil)
 
 			if err := p.source.ReopenChangeset(p.ctx, p.changeset); err != nil {
 				t.Errorf("unexpected error: %+v", err)
@@ -245,6 +329,7 @@ func TestGitLabSource_ChangesetSource(t *testing.T) {
 			p.mockGetMergeRequest(42, nil, inner)
 			p.mockGetMergeRequestNotes(42, nil, 20, nil)
 			p.mockGetMergeRequestPipelines(42, nil, 20, nil)
+			p.mockGetMergeRequestApprovals(42, nil, nil)
 
 			if have := p.source.LoadChangeset(p.ctx, p.changeset); !errors.Is(have, inner) {
 				t.Errorf("error does not include inner error: have %+v; want %+v", have, inner)
@@ -263,6 +348,7 @@ func TestGitLabSource_ChangesetSource(t *testing.T) {
 			p.mockGetMergeRequestNotes(43, nil, 20, inner)
 			p.mockGetMergeRequestResourceStateEvents(43, nil, 20, nil)
 			p.mockGetMergeRequestPipelines(43, nil, 20, nil)
+			p.mockGetMergeRequestApprovals(43, nil, nil)
 
 			if err := p.source.LoadChangeset(p.ctx, p.changeset); !errors.Is(err, inner) {
 				t.Errorf("unexpected error: %+v", err)
@@ -284,6 +370,7 @@ func TestGitLabSource_ChangesetSource(t *testing.T) {
 			p.mockGetMergeRequestNotes(43, nil, 20, nil)
 			p.mockGetMergeRequestResourceStateEvents(43, nil, 20, inner)
 			p.mockGetMergeRequestPipelines(43, nil, 20, nil)
+			p.mockGetMergeRequestApprovals(43, nil, nil)
 
 			if err := p.source.LoadChangeset(p.ctx, p.changeset); !errors.Is(err, inner) {
 				t.Errorf("unexpected error: %+v", err)
@@ -305,6 +392,7 @@ func TestGitLabSource_ChangesetSource(t *testing.T) {
 			p.mockGetMergeRequestNotes(43, nil, 20, nil)
 			p.mockGetMergeRequestResourceStateEvents(43, nil, 20, nil)
 			p.mockGetMergeRequestPipelines(43, nil, 20, inner)
+			p.mockGetMergeRequestApprovals(43, nil, nil)
 
 			if err := p.source.LoadChangeset(p.ctx, p.changeset); !errors.Is(err, inner) {
 				t.Errorf("unexpected error: %+v", err)
@@ -325,6 +413,7 @@ func TestGitLabSource_ChangesetSource(t *testing.T) {
 			p.mockGetMergeRequestNotes(43, nil, 20, nil)
 			p.mockGetMergeRequestResourceStateEvents(43, nil, 20, nil)
 			p.mockGetMergeRequestPipelines(43, nil, 20, nil)
+			p.mockGetMergeRequestApprovals(43, nil, nil)
 
 			if err := p.source.LoadChangeset(p.ctx, p.changeset); err != nil {
 				t.Errorf("unexpected error: %+v", err)
@@ -459,6 +548,7 @@ func TestGitLabSource_ChangesetSource(t *testing.T) {
 			p.mockGetMergeRequestNotes(43, notes, 20, nil)
 			p.mockGetMergeRequestResourceStateEvents(43, nil, 20, nil)
 			p.mockGetMergeRequestPipelines(43, nil, 20, nil)
+			p.mockGetMergeRequestApprovals(43, nil, nil)
 
 			if err := p.source.LoadChangeset(p.ctx, p.changeset); err != nil {
 				t.Errorf("unexpected error: %+v", err)
@@ -504,6 +594,7 @@ func TestGitLabSource_ChangesetSource(t *testing.T) {
 			p.mockGetMergeRequestNotes(43, nil, 20, nil)
 			p.mockGetMergeRequestResourceStateEvents(43, events, 20, nil)
 			p.mockGetMergeRequestPipelines(43, nil, 20, nil)
+			p.mockGetMergeRequestApprovals(43, nil, nil)
 
 			if err := p.source.LoadChangeset(p.ctx, p.changeset); err != nil {
 				t.Errorf("unexpected error: %+v", err)
@@ -540,6 +631,7 @@ func TestGitLabSource_ChangesetSource(t *testing.T) {
 			p.mockGetMergeRequestNotes(43, nil, 20, nil)
 			p.mockGetMergeRequestResourceStateEvents(43, nil, 20, nil)
 			p.mockGetMergeRequestPipelines(43, pipelines, 20, nil)
+			p.mockGetMergeRequestApprovals(43, nil, nil)
 
 			if err := p.source.LoadChangeset(p.ctx, p.changeset); err != nil {
 				t.Errorf("unexpected error: %+v", err)
@@ -600,6 +692,7 @@ func TestGitLabSource_ChangesetSource(t *testing.T) {
 			p.mockGetMergeRequestNotes(in.IID, nil, 20, nil)
 			p.mockGetMergeRequestResourceStateEvents(in.IID, nil, 20, nil)
 			p.mockGetMergeRequestPipelines(in.IID, nil, 20, nil)
+			p.mockGetMergeRequestApprovals(in.IID, nil, nil)
 
 			if err := p.source.UpdateChangeset(p.ctx, p.changeset); err != nil {
 				t.Errorf("unexpected non-nil error: %+v", err)
@@ -631,6 +724,7 @@ func TestGitLabSource_ChangesetSource(t *testing.T) {
 		p.mockGetMergeRequestNotes(in.IID, nil, 20, nil)
 		p.mockGetMergeRequestResourceStateEvents(in.IID, nil, 20, nil)
 		p.mockGetMergeRequestPipelines(in.IID, nil, 20, nil)
+		p.mockGetMergeRequestApprovals(in.IID, nil, nil)
 
 		if err := p.source.UpdateChangeset(p.ctx, p.changeset); err != nil {
 			t.Errorf("unexpected non-nil error: %+v", err)
@@ -947,6 +1041,28 @@ func (p *gitLabChangesetSourceTestProvider) mockGetMergeRequestPipelines(expecte
 	}
 }
 
+func (p *gitLabChangesetSourceTestProvider) mockGetMergeRequestApprovals(expectedIID gitlab.ID, approvals *gitlab.MergeRequestApprovals, err error) {
+	gitlab.MockGetMergeRequestApprovals = func(client *gitlab.Client, ctx context.Context, project *gitlab.Project, iid gitlab.ID) (*gitlab.MergeRequestApprovals, error) {
+		p.testCommonParams(ctx, client, project)
+		if expectedIID != iid {
+			p.t.Errorf("unexpected IID: have %d; want %d", iid, expectedIID)
+		}
+		return approvals, err
+	}
+}
+
+// mockGetUserByUsername mocks gitlab.GetUserByUsername with the given map of
+// usernames to user IDs.
+func (p *gitLabChangesetSourceTestProvider) mockGetUserByUsername(users map[string]int32) {
+	gitlab.MockGetUserByUsername = func(client *gitlab.Client, ctx context.Context, username string) (*gitlab.User, error) {
+		id, ok := users[username]
+		if !ok {
+			return nil, gitlab.ErrUserNotFound
+		}
+		return &gitlab.User{ID: id, Username: username}, nil
+	}
+}
+
 func (p *gitLabChangesetSourceTestProvider) mockGetOpenMergeRequestByRefs(mr *gitlab.MergeRequest, err error) {
 	gitlab.MockGetOpenMergeRequestByRefs = func(client *gitlab.Client, ctx context.Context, project *gitlab.Project, source, target string) (*gitlab.MergeRequest, error) {
 		p.testCommonParams(ctx, client, project)
@@ -984,6 +1100,9 @@ func (p *gitLabChangesetSourceTestProvider) unmock() {
 	gitlab.MockGetMergeRequestNotes = nil
 	gitlab.MockGetMergeRequestResourceStateEvents = nil
 	gitlab.MockGetMergeRequestPipelines = nil
+	gitlab.MockGetMergeRequestApprovals = nil
+	gitlab.MockCreateMergeRequestApprovalRule = nil
+	gitlab.MockGetUserByUsername = nil
 	gitlab.MockGetOpenMergeRequestByRefs = nil
 	gitlab.MockUpdateMergeRequest = nil
 	gitlab.MockCreateMergeRequestNote = nil
@@ -265,6 +265,14 @@ func loadExternalService(ctx context.Context, s *database.ExternalServiceStore,
 			if cfg.Token != "" {
 				return e, nil
 			}
+		case *schema.AzureDevOpsConnection:
+			if cfg.Token != "" {
+				return e, nil
+			}
+		case *schema.BitbucketCloudConnection:
+			if cfg.AppPassword != "" {
+				return e, nil
+			}
 		}
 	}
 
@@ -282,6 +290,10 @@ func buildChangesetSource(store SourcerStore, cf *httpcli.Factory, externalServi
 		return NewGitLabSource(externalService, cf)
 	case extsvc.KindBitbucketServer:
 		return NewBitbucketServerSource(externalService, cf)
+	case extsvc.KindAzureDevOps:
+		return NewAzureDevOpsSource(externalService, cf)
+	case extsvc.KindBitbucketCloud:
+		return NewBitbucketCloudSource(externalService, cf)
 	default:
 		return nil, errors.Errorf("unsupported external service type %q", extsvc.KindToType(externalService.Kind))
 	}
@@ -300,6 +312,9 @@ func loadUserCredential(ctx context.Context, s SourcerStore, userID int32, repo
 		return nil, err
 	}
 	if cred != nil {
+		if err := s.UserCredentials().MarkUsed(ctx, cred.ID); err != nil {
+			return nil, errors.Wrap(err, "marking user credential as used")
+		}
 		return cred.Authenticator(ctx)
 	}
 	return nil, nil
@@ -328,12 +343,15 @@ func setOAuthTokenAuth(u *vcs.URL, extSvcType, token string) error {
 	case extsvc.TypeGitHub:
 		u.User = url.User(token)
 
-	case extsvc.TypeGitLab:
+	case extsvc.TypeGitLab, extsvc.TypeAzureDevOps:
 		u.User = url.UserPassword("git", token)
 
 	case extsvc.TypeBitbucketServer:
 		return errors.New("require username/token to push commits to BitbucketServer")
 
+	case extsvc.TypeBitbucketCloud:
+		return errors.New("require username/app password to push commits to Bitbucket Cloud")
+
 	default:
 		panic(fmt.Sprintf("setOAuthTokenAuth: invalid external service type %q", extSvcType))
 	}
@@ -347,7 +365,7 @@ func setBasicAuth(u *vcs.URL, extSvcType, username, password string) error {
 	case extsvc.TypeGitHub, extsvc.TypeGitLab:
 		return errors.New("need token to push commits to " + extSvcType)
 
-	case extsvc.TypeBitbucketServer:
+	case extsvc.TypeBitbucketServer, extsvc.TypeBitbucketCloud, extsvc.TypeAzureDevOps:
 		u.User = url.UserPassword(username, password)
 
 	default:
@@ -378,7 +396,7 @@ func extractCloneURL(ctx context.Context, s *database.ExternalServiceStore, repo
 	for _, svc := range svcs {
 		// build the clone url using the external service config instead of using
 		// the source CloneURL field
-		cloneURL, err := repos.CloneURL(svc.Kind, svc.Config, repo)
+		cloneURL, err := repos.CloneURL(ctx, svc.Kind, svc.Config, repo)
 		if err != nil {
 			return "", err
 		}
@@ -8,6 +8,7 @@ import (
 	"github.com/inconshreveable/log15"
 
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 )
@@ -53,19 +54,28 @@ var RequiredEventTypesForHistory = []btypes.ChangesetEventKind{
 	btypes.ChangesetEventKindGitHubConvertToDraft,
 	btypes.ChangesetEventKindGitHubClosed,
 	btypes.ChangesetEventKindBitbucketServerDeclined,
+	btypes.ChangesetEventKindBitbucketCloudDeclined,
+	btypes.ChangesetEventKindAzureDevOpsAbandoned,
 	btypes.ChangesetEventKindGitLabClosed,
 	btypes.ChangesetEventKindGitHubMerged,
 	btypes.ChangesetEventKindBitbucketServerMerged,
+	btypes.ChangesetEventKindBitbucketCloudMerged,
+	btypes.ChangesetEventKindAzureDevOpsMerged,
 	btypes.ChangesetEventKindGitLabMerged,
+	btypes.ChangesetEventKindAzureDevOpsUpdated,
 	btypes.ChangesetEventKindGitHubReopened,
 	btypes.ChangesetEventKindBitbucketServerReopened,
 	btypes.ChangesetEventKindGitLabReopened,
 	btypes.ChangesetEventKindGitHubReviewed,
 	btypes.ChangesetEventKindBitbucketServerApproved,
 	btypes.ChangesetEventKindBitbucketServerReviewed,
+	btypes.ChangesetEventKindBitbucketCloudApproved,
+	btypes.ChangesetEventKindBitbucketCloudChangesRequestCreated,
 	btypes.ChangesetEventKindGitLabApproved,
 	btypes.ChangesetEventKindBitbucketServerUnapproved,
 	btypes.ChangesetEventKindBitbucketServerDismissed,
+	btypes.ChangesetEventKindBitbucketCloudUnapproved,
+	btypes.ChangesetEventKindBitbucketCloudChangesRequestRemoved,
 	btypes.ChangesetEventKindGitLabUnapproved,
 }
 
@@ -121,6 +131,8 @@ func computeHistory(ch *btypes.Changeset, ce ChangesetEvents) (changesetHistory,
 		switch e.Kind {
 		case btypes.ChangesetEventKindGitHubClosed,
 			btypes.ChangesetEventKindBitbucketServerDeclined,
+			btypes.ChangesetEventKindBitbucketCloudDeclined,
+			btypes.ChangesetEventKindAzureDevOpsAbandoned,
 			btypes.ChangesetEventKindGitLabClosed:
 			// Merged is a final state. We can ignore everything after.
 			if currentExtState != btypes.ChangesetExternalStateMerged {
@@ -130,10 +142,41 @@ func computeHistory(ch *btypes.Changeset, ce ChangesetEvents) (changesetHistory,
 
 		case btypes.ChangesetEventKindGitHubMerged,
 			btypes.ChangesetEventKindBitbucketServerMerged,
+			btypes.ChangesetEventKindBitbucketCloudMerged,
+			btypes.ChangesetEventKindAzureDevOpsMerged,
 			btypes.ChangesetEventKindGitLabMerged:
 			currentExtState = btypes.ChangesetExternalStateMerged
 			pushStates(et)
 
+		case btypes.ChangesetEventKindAzureDevOpsUpdated:
+			// Azure DevOps sends the complete, active pull request with every
+			// update, which covers reopening, draft changes and reviewer votes.
+			ev, ok := e.Metadata.(*azuredevops.PullRequestUpdatedEvent)
+			if !ok {
+				continue
+			}
+
+			changed := false
+			// Merged is a final state. We can ignore everything after.
+			if currentExtState != btypes.ChangesetExternalStateMerged {
+				isDraft = ev.PullRequest.IsDraft
+				s := btypes.ChangesetExternalStateOpen
+				if isDraft {
+					s = btypes.ChangesetExternalStateDraft
+				}
+				if s != currentExtState {
+					currentExtState = s
+					changed = true
+				}
+			}
+			if s := azureDevOpsReviewState(ev.PullRequest.Reviewers); s != currentReviewState {
+				currentReviewState = s
+				changed = true
+			}
+			if changed {
+				pushStates(et)
+			}
+
 		case btypes.ChangesetEventKindGitLabMarkWorkInProgress:
 			isDraft = true
 			// This event only matters when the changeset is open, otherwise a change in the title won't change the overall external state.
@@ -175,6 +218,8 @@ func computeHistory(ch *btypes.Changeset, ce ChangesetEvents) (changesetHistory,
 		case btypes.ChangesetEventKindGitHubReviewed,
 			btypes.ChangesetEventKindBitbucketServerApproved,
 			btypes.ChangesetEventKindBitbucketServerReviewed,
+			btypes.ChangesetEventKindBitbucketCloudApproved,
+			btypes.ChangesetEventKindBitbucketCloudChangesRequestCreated,
 			btypes.ChangesetEventKindGitLabApproved:
 
 			s, err := e.ReviewState()
@@ -217,6 +262,8 @@ func computeHistory(ch *btypes.Changeset, ce ChangesetEvents) (changesetHistory,
 
 		case btypes.ChangesetEventKindBitbucketServerUnapproved,
 			btypes.ChangesetEventKindBitbucketServerDismissed,
+			btypes.ChangesetEventKindBitbucketCloudUnapproved,
+			btypes.ChangesetEventKindBitbucketCloudChangesRequestRemoved,
 			btypes.ChangesetEventKindGitLabUnapproved:
 			author := e.ReviewAuthor()
 			// If the user has been deleted, skip their reviews, as they don't count towards the final state anymore.
@@ -289,7 +336,12 @@ func initialExternalState(ch *btypes.Changeset, ce ChangesetEvents) btypes.Chang
 		}
 
 	case *gitlab.MergeRequest:
-		if m.WorkInProgress {
+		if m.IsDraft() {
+			open = false
+		}
+
+	case *azuredevops.PullRequest:
+		if m.IsDraft {
 			open = false
 		}
 	default:
@@ -298,11 +350,15 @@ func initialExternalState(ch *btypes.Changeset, ce ChangesetEvents) btypes.Chang
 	// Walk the events backwards, since we need to look from the current time to the past.
 	for i := len(ce) - 1; i >= 0; i-- {
 		e := ce[i]
-		switch e.Metadata.(type) {
+		switch m := e.Metadata.(type) {
 		case *gitlab.UnmarkWorkInProgressEvent, *github.ReadyForReviewEvent:
 			open = false
 		case *gitlab.MarkWorkInProgressEvent, *github.ConvertToDraftEvent:
 			open = true
+		case *azuredevops.PullRequestUpdatedEvent:
+			// Updated events carry the draft state of the pull request at the
+			// time of the event, rather than a transition.
+			open = !m.PullRequest.IsDraft
 		}
 	}
 	if open {
@@ -16,6 +16,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
@@ -105,6 +107,12 @@ func computeCheckState(c *btypes.Changeset, events ChangesetEvents) btypes.Chang
 
 	case *gitlab.MergeRequest:
 		return computeGitLabCheckState(c.UpdatedAt, m, events)
+
+	case *azuredevops.PullRequest:
+		return computeAzureDevOpsCheckState(m)
+
+	case *bitbucketcloud.PullRequest:
+		return computeBitbucketCloudCheckState(m)
 	}
 
 	return btypes.ChangesetCheckStateUnknown
@@ -415,6 +423,65 @@ func parseGitLabPipelineStatus(status gitlab.PipelineStatus) btypes.ChangesetChe
 	}
 }
 
+// computeAzureDevOpsCheckState computes the check state from the statuses
+// loaded with the pull request. Services update their status by posting a new
+// one with the same key, so only the most recent status per key counts.
+func computeAzureDevOpsCheckState(pr *azuredevops.PullRequest) btypes.ChangesetCheckState {
+	latest := make(map[string]*azuredevops.Status)
+	for _, status := range pr.Statuses {
+		if l, ok := latest[status.Key()]; ok && l.ID > status.ID {
+			continue
+		}
+		latest[status.Key()] = status
+	}
+
+	states := make([]btypes.ChangesetCheckState, 0, len(latest))
+	for _, status := range latest {
+		if status.State == azuredevops.StatusStateNotApplicable {
+			continue
+		}
+		states = append(states, parseAzureDevOpsStatusState(status.State))
+	}
+
+	return combineCheckStates(states)
+}
+
+func parseAzureDevOpsStatusState(s azuredevops.StatusState) btypes.ChangesetCheckState {
+	switch s {
+	case azuredevops.StatusStateError, azuredevops.StatusStateFailed:
+		return btypes.ChangesetCheckStateFailed
+	case azuredevops.StatusStatePending, azuredevops.StatusStateNotSet:
+		return btypes.ChangesetCheckStatePending
+	case azuredevops.StatusStateSucceeded:
+		return btypes.ChangesetCheckStatePassed
+	default:
+		return btypes.ChangesetCheckStateUnknown
+	}
+}
+
+// computeBitbucketCloudCheckState computes the check state from the commit
+// statuses loaded with the pull request.
+func computeBitbucketCloudCheckState(pr *bitbucketcloud.PullRequest) btypes.ChangesetCheckState {
+	states := make([]btypes.ChangesetCheckState, 0, len(pr.Statuses))
+	for _, status := range pr.Statuses {
+		states = append(states, parseBitbucketCloudStatusState(status.State))
+	}
+	return combineCheckStates(states)
+}
+
+func parseBitbucketCloudStatusState(s bitbucketcloud.PullRequestStatusState) btypes.ChangesetCheckState {
+	switch s {
+	case bitbucketcloud.PullRequestStatusStateFailed, bitbucketcloud.PullRequestStatusStateStopped:
+		return btypes.ChangesetCheckStateFailed
+	case bitbucketcloud.PullRequestStatusStateInProgress:
+		return btypes.ChangesetCheckStatePending
+	case bitbucketcloud.PullRequestStatusStateSuccessful:
+		return btypes.ChangesetCheckStatePassed
+	default:
+		return btypes.ChangesetCheckStateUnknown
+	}
+}
+
 // computeSingleChangesetExternalState of a Changeset based on the metadata.
 // It does NOT reflect the final calculated state, use `ExternalState` instead.
 func computeSingleChangesetExternalState(c *btypes.Changeset) (s btypes.ChangesetExternalState, err error) {
@@ -442,7 +509,7 @@ func computeSingleChangesetExternalState(c *btypes.Changeset) (s btypes.Changese
 		case gitlab.MergeRequestStateMerged:
 			s = btypes.ChangesetExternalStateMerged
 		case gitlab.MergeRequestStateOpened:
-			if m.WorkInProgress {
+			if m.IsDraft() {
 				s = btypes.ChangesetExternalStateDraft
 			} else {
 				s = btypes.ChangesetExternalStateOpen
@@ -450,6 +517,32 @@ func computeSingleChangesetExternalState(c *btypes.Changeset) (s btypes.Changese
 		default:
 			return "", errors.Errorf("unknown GitLab merge request state: %s", m.State)
 		}
+	case *azuredevops.PullRequest:
+		switch m.Status {
+		case azuredevops.PullRequestStatusAbandoned:
+			s = btypes.ChangesetExternalStateClosed
+		case azuredevops.PullRequestStatusCompleted:
+			s = btypes.ChangesetExternalStateMerged
+		case azuredevops.PullRequestStatusActive:
+			if m.IsDraft {
+				s = btypes.ChangesetExternalStateDraft
+			} else {
+				s = btypes.ChangesetExternalStateOpen
+			}
+		default:
+			return "", errors.Errorf("unknown Azure DevOps pull request status: %s", m.Status)
+		}
+	case *bitbucketcloud.PullRequest:
+		switch m.State {
+		case bitbucketcloud.PullRequestStateDeclined, bitbucketcloud.PullRequestStateSuperseded:
+			s = btypes.ChangesetExternalStateClosed
+		case bitbucketcloud.PullRequestStateMerged:
+			s = btypes.ChangesetExternalStateMerged
+		case bitbucketcloud.PullRequestStateOpen:
+			s = btypes.ChangesetExternalStateOpen
+		default:
+			return "", errors.Errorf("unknown Bitbucket Cloud pull request state: %s", m.State)
+		}
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -495,10 +588,16 @@ func computeSingleChangesetReviewState(c *btypes.Changeset) (s btypes.ChangesetR
 		// any unapproval event, then we'll consider the MR approved. If we see
 		// an unapproval, then changes were requested. If we don't see anything,
 		// then we're pending.
+		//
+		// If approval rules require more approvals than the MR has received,
+		// an approval alone isn't enough and the MR is still pending.
 		for _, note := range m.Notes {
 			if e := note.ToEvent(); e != nil {
 				switch e.(type) {
 				case *gitlab.ReviewApprovedEvent:
+					if m.Approvals != nil && m.Approvals.ApprovalsLeft > 0 {
+						return btypes.ChangesetReviewStatePending, nil
+					}
 					return btypes.ChangesetReviewStateApproved, nil
 				case *gitlab.ReviewUnapprovedEvent:
 					return btypes.ChangesetReviewStateChangesRequested, nil
@@ -507,6 +606,19 @@ func computeSingleChangesetReviewState(c *btypes.Changeset) (s btypes.ChangesetR
 		}
 		return btypes.ChangesetReviewStatePending, nil
 
+	case *azuredevops.PullRequest:
+		return azureDevOpsReviewState(m.Reviewers), nil
+
+	case *bitbucketcloud.PullRequest:
+		for _, p := range m.Participants {
+			switch p.State {
+			case bitbucketcloud.PullRequestParticipantStateApproved:
+				states[btypes.ChangesetReviewStateApproved] = true
+			case bitbucketcloud.PullRequestParticipantStateChangesRequested:
+				states[btypes.ChangesetReviewStateChangesRequested] = true
+			}
+		}
+
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -514,6 +626,22 @@ func computeSingleChangesetReviewState(c *btypes.Changeset) (s btypes.ChangesetR
 	return selectReviewState(states), nil
 }
 
+// azureDevOpsReviewState computes the review state from the votes of the
+// reviewers of an Azure DevOps pull request. Approvals with suggestions count
+// as approvals, and waiting for the author counts as requesting changes.
+func azureDevOpsReviewState(reviewers []azuredevops.Reviewer) btypes.ChangesetReviewState {
+	states := map[btypes.ChangesetReviewState]bool{}
+	for _, r := range reviewers {
+		switch {
+		case r.Vote >= azuredevops.VoteApprovedWithSuggestions:
+			states[btypes.ChangesetReviewStateApproved] = true
+		case r.Vote <= azuredevops.VoteWaitingForAuthor:
+			states[btypes.ChangesetReviewStateChangesRequested] = true
+		}
+	}
+	return selectReviewState(states)
+}
+
 // selectReviewState computes the single review state for a given set of
 // ChangesetReviewStates. Since a pull request, for example, can have multiple
 // reviews with different states, we need a function to determine what the
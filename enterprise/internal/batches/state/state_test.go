@@ -9,6 +9,8 @@ import (
 
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
@@ -406,6 +408,96 @@ func TestComputeGitLabCheckState(t *testing.T) {
 	})
 }
 
+func TestComputeAzureDevOpsCheckState(t *testing.T) {
+	t.Parallel()
+
+	status := func(id int64, name string, state azuredevops.StatusState) *azuredevops.Status {
+		s := &azuredevops.Status{ID: id, State: state}
+		s.Context.Genre = "continuous-integration"
+		s.Context.Name = name
+		return s
+	}
+
+	for name, tc := range map[string]struct {
+		statuses []*azuredevops.Status
+		want     btypes.ChangesetCheckState
+	}{
+		"no statuses": {
+			want: btypes.ChangesetCheckStateUnknown,
+		},
+		"single succeeded status": {
+			statuses: []*azuredevops.Status{status(1, "build", azuredevops.StatusStateSucceeded)},
+			want:     btypes.ChangesetCheckStatePassed,
+		},
+		"newer status with the same key wins": {
+			statuses: []*azuredevops.Status{
+				status(2, "build", azuredevops.StatusStateSucceeded),
+				status(1, "build", azuredevops.StatusStatePending),
+			},
+			want: btypes.ChangesetCheckStatePassed,
+		},
+		"failed and succeeded statuses": {
+			statuses: []*azuredevops.Status{
+				status(1, "build", azuredevops.StatusStateFailed),
+				status(2, "lint", azuredevops.StatusStateSucceeded),
+			},
+			want: btypes.ChangesetCheckStateFailed,
+		},
+		"not applicable statuses are ignored": {
+			statuses: []*azuredevops.Status{
+				status(1, "build", azuredevops.StatusStateSucceeded),
+				status(2, "deploy", azuredevops.StatusStateNotApplicable),
+			},
+			want: btypes.ChangesetCheckStatePassed,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			have := computeAzureDevOpsCheckState(&azuredevops.PullRequest{Statuses: tc.statuses})
+			if have != tc.want {
+				t.Errorf("unexpected check state: have %s; want %s", have, tc.want)
+			}
+		})
+	}
+}
+
+func TestComputeBitbucketCloudCheckState(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		statuses []*bitbucketcloud.PullRequestStatus
+		want     btypes.ChangesetCheckState
+	}{
+		"no statuses": {
+			want: btypes.ChangesetCheckStateUnknown,
+		},
+		"single successful status": {
+			statuses: []*bitbucketcloud.PullRequestStatus{{Key: "build", State: bitbucketcloud.PullRequestStatusStateSuccessful}},
+			want:     btypes.ChangesetCheckStatePassed,
+		},
+		"in progress and successful statuses": {
+			statuses: []*bitbucketcloud.PullRequestStatus{
+				{Key: "build", State: bitbucketcloud.PullRequestStatusStateInProgress},
+				{Key: "lint", State: bitbucketcloud.PullRequestStatusStateSuccessful},
+			},
+			want: btypes.ChangesetCheckStatePending,
+		},
+		"stopped status": {
+			statuses: []*bitbucketcloud.PullRequestStatus{
+				{Key: "build", State: bitbucketcloud.PullRequestStatusStateStopped},
+				{Key: "lint", State: bitbucketcloud.PullRequestStatusStateSuccessful},
+			},
+			want: btypes.ChangesetCheckStateFailed,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			have := computeBitbucketCloudCheckState(&bitbucketcloud.PullRequest{Statuses: tc.statuses})
+			if have != tc.want {
+				t.Errorf("unexpected check state: have %s; want %s", have, tc.want)
+			}
+		})
+	}
+}
+
 func TestComputeReviewState(t *testing.T) {
 	t.Parallel()
 
@@ -481,6 +573,28 @@ func TestComputeReviewState(t *testing.T) {
 			history: []changesetStatesAtTime{},
 			want:    btypes.ChangesetReviewStateApproved,
 		},
+		{
+			name: "gitlab - no events, one approval, approvals left",
+			changeset: setGitLabApprovals(gitLabChangeset(daysAgo(0), gitlab.MergeRequestStateOpened, []*gitlab.Note{
+				{
+					System: true,
+					Body:   "approved this merge request",
+				},
+			}), &gitlab.MergeRequestApprovals{ApprovalsRequired: 2, ApprovalsLeft: 1}),
+			history: []changesetStatesAtTime{},
+			want:    btypes.ChangesetReviewStatePending,
+		},
+		{
+			name: "gitlab - no events, one approval, no approvals left",
+			changeset: setGitLabApprovals(gitLabChangeset(daysAgo(0), gitlab.MergeRequestStateOpened, []*gitlab.Note{
+				{
+					System: true,
+					Body:   "approved this merge request",
+				},
+			}), &gitlab.MergeRequestApprovals{Approved: true, ApprovalsRequired: 1}),
+			history: []changesetStatesAtTime{},
+			want:    btypes.ChangesetReviewStateApproved,
+		},
 		{
 			name: "gitlab - no events, one unapproval",
 			changeset: gitLabChangeset(daysAgo(0), gitlab.MergeRequestStateOpened, []*gitlab.Note{
@@ -535,6 +649,50 @@ func TestComputeReviewState(t *testing.T) {
 			},
 			want: btypes.ChangesetReviewStateChangesRequested,
 		},
+		{
+			name:      "azuredevops - no events, no votes",
+			changeset: azureDevOpsChangeset(daysAgo(0), azuredevops.PullRequestStatusActive, azuredevops.VoteNone),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetReviewStatePending,
+		},
+		{
+			name:      "azuredevops - no events, approved with suggestions",
+			changeset: azureDevOpsChangeset(daysAgo(0), azuredevops.PullRequestStatusActive, azuredevops.VoteApprovedWithSuggestions),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetReviewStateApproved,
+		},
+		{
+			name:      "azuredevops - no events, approved and waiting for author",
+			changeset: azureDevOpsChangeset(daysAgo(0), azuredevops.PullRequestStatusActive, azuredevops.VoteApproved, azuredevops.VoteWaitingForAuthor),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetReviewStateChangesRequested,
+		},
+		{
+			name:      "azuredevops - changeset older than events",
+			changeset: azureDevOpsChangeset(daysAgo(10), azuredevops.PullRequestStatusActive, azuredevops.VoteRejected),
+			history: []changesetStatesAtTime{
+				{t: daysAgo(0), reviewState: btypes.ChangesetReviewStateApproved},
+			},
+			want: btypes.ChangesetReviewStateApproved,
+		},
+		{
+			name:      "bitbucketcloud - no events, no reviews",
+			changeset: bitbucketCloudChangeset(daysAgo(0), bitbucketcloud.PullRequestStateOpen, ""),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetReviewStatePending,
+		},
+		{
+			name:      "bitbucketcloud - no events, approved",
+			changeset: bitbucketCloudChangeset(daysAgo(0), bitbucketcloud.PullRequestStateOpen, bitbucketcloud.PullRequestParticipantStateApproved),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetReviewStateApproved,
+		},
+		{
+			name:      "bitbucketcloud - no events, approved and changes requested",
+			changeset: bitbucketCloudChangeset(daysAgo(0), bitbucketcloud.PullRequestStateOpen, bitbucketcloud.PullRequestParticipantStateApproved, bitbucketcloud.PullRequestParticipantStateChangesRequested),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetReviewStateChangesRequested,
+		},
 	}
 
 	for i, tc := range tests {
@@ -699,6 +857,16 @@ func TestComputeExternalState(t *testing.T) {
 			history:   []changesetStatesAtTime{},
 			want:      btypes.ChangesetExternalStateDraft,
 		},
+		{
+			name: "gitlab draft field - no events",
+			changeset: &btypes.Changeset{
+				ExternalServiceType: extsvc.TypeGitLab,
+				UpdatedAt:           daysAgo(10),
+				Metadata:            &gitlab.MergeRequest{State: gitlab.MergeRequestStateOpened, Draft: true},
+			},
+			history: []changesetStatesAtTime{},
+			want:    btypes.ChangesetExternalStateDraft,
+		},
 		{
 			name:      "gitlab draft - changeset older than events",
 			changeset: gitLabChangeset(daysAgo(10), gitlab.MergeRequestStateOpened, nil),
@@ -715,6 +883,64 @@ func TestComputeExternalState(t *testing.T) {
 			},
 			want: btypes.ChangesetExternalStateDraft,
 		},
+		{
+			name:      "azuredevops - no events, active",
+			changeset: azureDevOpsChangeset(daysAgo(0), azuredevops.PullRequestStatusActive),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetExternalStateOpen,
+		},
+		{
+			name:      "azuredevops - no events, abandoned",
+			changeset: azureDevOpsChangeset(daysAgo(0), azuredevops.PullRequestStatusAbandoned),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetExternalStateClosed,
+		},
+		{
+			name:      "azuredevops - no events, completed",
+			changeset: azureDevOpsChangeset(daysAgo(0), azuredevops.PullRequestStatusCompleted),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetExternalStateMerged,
+		},
+		{
+			name:      "azuredevops draft - no events",
+			changeset: setDraft(azureDevOpsChangeset(daysAgo(0), azuredevops.PullRequestStatusActive)),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetExternalStateDraft,
+		},
+		{
+			name:      "azuredevops - changeset older than events",
+			changeset: azureDevOpsChangeset(daysAgo(10), azuredevops.PullRequestStatusActive),
+			history: []changesetStatesAtTime{
+				{t: daysAgo(0), externalState: btypes.ChangesetExternalStateMerged},
+			},
+			want: btypes.ChangesetExternalStateMerged,
+		},
+		{
+			name:      "bitbucketcloud - no events, open",
+			changeset: bitbucketCloudChangeset(daysAgo(0), bitbucketcloud.PullRequestStateOpen),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetExternalStateOpen,
+		},
+		{
+			name:      "bitbucketcloud - no events, declined",
+			changeset: bitbucketCloudChangeset(daysAgo(0), bitbucketcloud.PullRequestStateDeclined),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetExternalStateClosed,
+		},
+		{
+			name:      "bitbucketcloud - no events, merged",
+			changeset: bitbucketCloudChangeset(daysAgo(0), bitbucketcloud.PullRequestStateMerged),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetExternalStateMerged,
+		},
+		{
+			name:      "bitbucketcloud - changeset older than events",
+			changeset: bitbucketCloudChangeset(daysAgo(10), bitbucketcloud.PullRequestStateOpen),
+			history: []changesetStatesAtTime{
+				{t: daysAgo(0), externalState: btypes.ChangesetExternalStateMerged},
+			},
+			want: btypes.ChangesetExternalStateMerged,
+		},
 	}
 
 	for i, tc := range tests {
@@ -865,6 +1091,35 @@ func gitLabChangeset(updatedAt time.Time, state gitlab.MergeRequestState, notes
 	}
 }
 
+func setGitLabApprovals(c *btypes.Changeset, approvals *gitlab.MergeRequestApprovals) *btypes.Changeset {
+	c.Metadata.(*gitlab.MergeRequest).Approvals = approvals
+	return c
+}
+
+func azureDevOpsChangeset(updatedAt time.Time, status azuredevops.PullRequestStatus, votes ...int) *btypes.Changeset {
+	pr := &azuredevops.PullRequest{Status: status}
+	for _, vote := range votes {
+		pr.Reviewers = append(pr.Reviewers, azuredevops.Reviewer{Vote: vote})
+	}
+	return &btypes.Changeset{
+		ExternalServiceType: extsvc.TypeAzureDevOps,
+		UpdatedAt:           updatedAt,
+		Metadata:            pr,
+	}
+}
+
+func bitbucketCloudChangeset(updatedAt time.Time, state bitbucketcloud.PullRequestState, reviews ...bitbucketcloud.PullRequestParticipantState) *btypes.Changeset {
+	pr := &bitbucketcloud.PullRequest{State: state}
+	for _, review := range reviews {
+		pr.Participants = append(pr.Participants, bitbucketcloud.PullRequestParticipant{State: review})
+	}
+	return &btypes.Changeset{
+		ExternalServiceType: extsvc.TypeBitbucketCloud,
+		UpdatedAt:           updatedAt,
+		Metadata:            pr,
+	}
+}
+
 func setDeletedAt(c *btypes.Changeset, deletedAt time.Time) *btypes.Changeset {
 	c.ExternalDeletedAt = deletedAt
 	return c
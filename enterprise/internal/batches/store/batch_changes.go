@@ -31,6 +31,8 @@ var batchChangeColumns = []*sqlf.Query{
 	sqlf.Sprintf("batch_changes.updated_at"),
 	sqlf.Sprintf("batch_changes.closed_at"),
 	sqlf.Sprintf("batch_changes.batch_spec_id"),
+	sqlf.Sprintf("batch_changes.auto_rebase"),
+	sqlf.Sprintf("batch_changes.auto_archive_after_days"),
 }
 
 // batchChangeInsertColumns is the list of batch changes columns that are
@@ -48,6 +50,8 @@ var batchChangeInsertColumns = []*sqlf.Query{
 	sqlf.Sprintf("updated_at"),
 	sqlf.Sprintf("closed_at"),
 	sqlf.Sprintf("batch_spec_id"),
+	sqlf.Sprintf("auto_rebase"),
+	sqlf.Sprintf("auto_archive_after_days"),
 }
 
 // CreateBatchChange creates the given batch change.
@@ -65,7 +69,7 @@ func (s *Store) CreateBatchChange(ctx context.Context, c *btypes.BatchChange) (e
 var createBatchChangeQueryFmtstr = `
 -- source: enterprise/internal/batches/store.go:CreateBatchChange
 INSERT INTO batch_changes (%s)
-VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 RETURNING %s
 `
 
@@ -92,6 +96,8 @@ func (s *Store) createBatchChangeQuery(c *btypes.BatchChange) *sqlf.Query {
 		c.UpdatedAt,
 		nullTimeColumn(c.ClosedAt),
 		c.BatchSpecID,
+		c.AutoRebase,
+		c.AutoArchiveAfterDays,
 		sqlf.Join(batchChangeColumns, ", "),
 	)
 }
@@ -111,7 +117,7 @@ func (s *Store) UpdateBatchChange(ctx context.Context, c *btypes.BatchChange) (e
 var updateBatchChangeQueryFmtstr = `
 -- source: enterprise/internal/batches/store.go:UpdateBatchChange
 UPDATE batch_changes
-SET (%s) = (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+SET (%s) = (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 WHERE id = %s
 RETURNING %s
 `
@@ -133,6 +139,8 @@ func (s *Store) updateBatchChangeQuery(c *btypes.BatchChange) *sqlf.Query {
 		c.UpdatedAt,
 		nullTimeColumn(c.ClosedAt),
 		c.BatchSpecID,
+		c.AutoRebase,
+		c.AutoArchiveAfterDays,
 		c.ID,
 		sqlf.Join(batchChangeColumns, ", "),
 	)
@@ -540,5 +548,7 @@ func scanBatchChange(c *btypes.BatchChange, s dbutil.Scanner) error {
 		&c.UpdatedAt,
 		&dbutil.NullTime{Time: &c.ClosedAt},
 		&c.BatchSpecID,
+		&c.AutoRebase,
+		&c.AutoArchiveAfterDays,
 	)
 }
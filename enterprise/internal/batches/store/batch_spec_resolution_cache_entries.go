@@ -0,0 +1,151 @@
+package store
+
+import (
+	"context"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/opentracing/opentracing-go/log"
+
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/batch"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// batchSpecResolutionCacheEntryInsertColumns is the list of
+// batch_spec_resolution_cache_entries columns that are modified in
+// ReplaceBatchSpecResolutionCacheEntries.
+var batchSpecResolutionCacheEntryInsertColumns = []string{
+	"query",
+	"repo_id",
+	"commit",
+	"file_matches",
+	"created_at",
+}
+
+// batchSpecResolutionCacheEntryColumns are used by the cache entry related
+// Store methods to query cache entries.
+var batchSpecResolutionCacheEntryColumns = SQLColumns{
+	"batch_spec_resolution_cache_entries.id",
+	"batch_spec_resolution_cache_entries.query",
+	"batch_spec_resolution_cache_entries.repo_id",
+	"batch_spec_resolution_cache_entries.commit",
+	"batch_spec_resolution_cache_entries.file_matches",
+	"batch_spec_resolution_cache_entries.created_at",
+}
+
+// ListBatchSpecResolutionCacheEntries lists the cache entries of the given
+// query that haven't expired yet.
+func (s *Store) ListBatchSpecResolutionCacheEntries(ctx context.Context, query string) (es []*btypes.BatchSpecResolutionCacheEntry, err error) {
+	ctx, endObservation := s.operations.listBatchSpecResolutionCacheEntries.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	q := sqlf.Sprintf(
+		listBatchSpecResolutionCacheEntriesQueryFmtstr,
+		sqlf.Join(batchSpecResolutionCacheEntryColumns.ToSqlf(), ", "),
+		query,
+		s.now().Add(-btypes.BatchSpecResolutionCacheTTL),
+	)
+
+	err = s.query(ctx, q, func(sc dbutil.Scanner) error {
+		var e btypes.BatchSpecResolutionCacheEntry
+		if err := scanBatchSpecResolutionCacheEntry(&e, sc); err != nil {
+			return err
+		}
+		es = append(es, &e)
+		return nil
+	})
+
+	return es, err
+}
+
+var listBatchSpecResolutionCacheEntriesQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_spec_resolution_cache_entries.go:ListBatchSpecResolutionCacheEntries
+SELECT %s FROM batch_spec_resolution_cache_entries
+WHERE query = %s AND created_at >= %s
+ORDER BY repo_id ASC
+`
+
+// ReplaceBatchSpecResolutionCacheEntries replaces all cache entries of the
+// given query with the given entries.
+func (s *Store) ReplaceBatchSpecResolutionCacheEntries(ctx context.Context, query string, es []*btypes.BatchSpecResolutionCacheEntry) (err error) {
+	ctx, endObservation := s.operations.replaceBatchSpecResolutionCacheEntries.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("count", len(es)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	if err := tx.Exec(ctx, sqlf.Sprintf(deleteBatchSpecResolutionCacheEntriesQueryFmtstr, query)); err != nil {
+		return err
+	}
+
+	return batch.WithInserter(
+		ctx,
+		tx.Handle().DB(),
+		"batch_spec_resolution_cache_entries",
+		batchSpecResolutionCacheEntryInsertColumns,
+		func(inserter *batch.Inserter) error {
+			for _, e := range es {
+				e.Query = query
+				if e.CreatedAt.IsZero() {
+					e.CreatedAt = tx.now()
+				}
+
+				if e.FileMatches == nil {
+					e.FileMatches = []string{}
+				}
+
+				if err := inserter.Insert(
+					ctx,
+					e.Query,
+					e.RepoID,
+					e.Commit,
+					pq.Array(e.FileMatches),
+					e.CreatedAt,
+				); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	)
+}
+
+var deleteBatchSpecResolutionCacheEntriesQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_spec_resolution_cache_entries.go:ReplaceBatchSpecResolutionCacheEntries
+DELETE FROM batch_spec_resolution_cache_entries WHERE query = %s
+`
+
+// DeleteExpiredBatchSpecResolutionCacheEntries deletes the cache entries that
+// are older than BatchSpecResolutionCacheTTL.
+func (s *Store) DeleteExpiredBatchSpecResolutionCacheEntries(ctx context.Context) (err error) {
+	ctx, endObservation := s.operations.deleteExpiredBatchSpecResolutionCacheEntries.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	expirationTime := s.now().Add(-btypes.BatchSpecResolutionCacheTTL)
+	q := sqlf.Sprintf(deleteExpiredBatchSpecResolutionCacheEntriesQueryFmtstr, expirationTime)
+
+	return s.Store.Exec(ctx, q)
+}
+
+var deleteExpiredBatchSpecResolutionCacheEntriesQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_spec_resolution_cache_entries.go:DeleteExpiredBatchSpecResolutionCacheEntries
+DELETE FROM batch_spec_resolution_cache_entries WHERE created_at < %s
+`
+
+func scanBatchSpecResolutionCacheEntry(e *btypes.BatchSpecResolutionCacheEntry, s dbutil.Scanner) error {
+	return s.Scan(
+		&e.ID,
+		&e.Query,
+		&e.RepoID,
+		&e.Commit,
+		pq.Array(&e.FileMatches),
+		&e.CreatedAt,
+	)
+}
@@ -0,0 +1,106 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/keegancsmith/sqlf"
+
+	ct "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/testing"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+)
+
+func testStoreBatchSpecResolutionCacheEntries(t *testing.T, ctx context.Context, s *Store, clock ct.Clock) {
+	repoStore := database.ReposWith(s)
+	esStore := database.ExternalServicesWith(s)
+
+	repo := ct.TestRepo(t, esStore, extsvc.KindGitHub)
+	otherRepo := ct.TestRepo(t, esStore, extsvc.KindGitHub)
+	if err := repoStore.Create(ctx, repo, otherRepo); err != nil {
+		t.Fatal(err)
+	}
+
+	query := "repohasfile:horse.txt"
+
+	t.Run("Empty", func(t *testing.T) {
+		have, err := s.ListBatchSpecResolutionCacheEntries(ctx, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(have) != 0 {
+			t.Fatalf("expected no entries, got %d", len(have))
+		}
+	})
+
+	t.Run("Replace", func(t *testing.T) {
+		entries := []*btypes.BatchSpecResolutionCacheEntry{
+			{RepoID: repo.ID, Commit: "d34db33f", FileMatches: []string{"horse.txt"}},
+			{RepoID: otherRepo.ID, Commit: "c0ff33"},
+		}
+		if err := s.ReplaceBatchSpecResolutionCacheEntries(ctx, query, entries); err != nil {
+			t.Fatal(err)
+		}
+
+		// Replacing the entries again shouldn't violate the unique constraint.
+		entries = entries[:1]
+		if err := s.ReplaceBatchSpecResolutionCacheEntries(ctx, query, entries); err != nil {
+			t.Fatal(err)
+		}
+
+		have, err := s.ListBatchSpecResolutionCacheEntries(ctx, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(have) != 1 || have[0].ID == 0 {
+			t.Fatalf("expected one entry with ID, got %+v", have)
+		}
+		want := &btypes.BatchSpecResolutionCacheEntry{
+			ID:          have[0].ID,
+			Query:       query,
+			RepoID:      repo.ID,
+			Commit:      "d34db33f",
+			FileMatches: []string{"horse.txt"},
+			CreatedAt:   clock.Now(),
+		}
+		if diff := cmp.Diff(want, have[0]); diff != "" {
+			t.Fatal(diff)
+		}
+
+		// Entries of other queries are not returned.
+		have, err = s.ListBatchSpecResolutionCacheEntries(ctx, query+" other")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(have) != 0 {
+			t.Fatalf("expected no entries, got %d", len(have))
+		}
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		clock.Add(btypes.BatchSpecResolutionCacheTTL + 1)
+
+		have, err := s.ListBatchSpecResolutionCacheEntries(ctx, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(have) != 0 {
+			t.Fatalf("expected expired entries to be skipped, got %d", len(have))
+		}
+
+		if err := s.DeleteExpiredBatchSpecResolutionCacheEntries(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		count, _, err := basestore.ScanFirstInt(s.Query(ctx, sqlf.Sprintf("SELECT COUNT(*) FROM batch_spec_resolution_cache_entries")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 0 {
+			t.Fatalf("expected expired entries to be deleted, got %d", count)
+		}
+	})
+}
@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/keegancsmith/sqlf"
@@ -21,6 +22,7 @@ var BatchSpecWorkspaceExecutionJobColums = SQLColumns{
 
 	"batch_spec_workspace_execution_jobs.batch_spec_workspace_id",
 	"batch_spec_workspace_execution_jobs.access_token_id",
+	"batch_spec_workspace_execution_jobs.user_id",
 
 	"batch_spec_workspace_execution_jobs.state",
 	"batch_spec_workspace_execution_jobs.failure_message",
@@ -50,9 +52,10 @@ WHERE exec.state = 'queued'
 const createBatchSpecWorkspaceExecutionJobsQueryFmtstr = `
 -- source: enterprise/internal/batches/store/batch_spec_workspace_execution_jobs.go:CreateBatchSpecWorkspaceExecutionJobs
 INSERT INTO
-	batch_spec_workspace_execution_jobs (batch_spec_workspace_id)
+	batch_spec_workspace_execution_jobs (batch_spec_workspace_id, user_id)
 SELECT
-	batch_spec_workspaces.id
+	batch_spec_workspaces.id,
+	%s
 FROM
 	batch_spec_workspaces
 JOIN batch_specs ON batch_specs.id = batch_spec_workspaces.batch_spec_id
@@ -71,15 +74,17 @@ const executableWorkspaceJobsConditionFmtstr = `
 	jsonb_array_length(batch_spec_workspaces.steps) > 0
 )`
 
-// CreateBatchSpecWorkspaceExecutionJobs creates the given batch spec workspace jobs.
-func (s *Store) CreateBatchSpecWorkspaceExecutionJobs(ctx context.Context, batchSpecID int64) (err error) {
+// CreateBatchSpecWorkspaceExecutionJobs creates the given batch spec workspace
+// jobs. The jobs will be executed with the permissions of the given user.
+func (s *Store) CreateBatchSpecWorkspaceExecutionJobs(ctx context.Context, batchSpecID int64, userID int32) (err error) {
 	ctx, endObservation := s.operations.createBatchSpecWorkspaceExecutionJobs.With(ctx, &err, observation.Args{LogFields: []log.Field{
 		log.Int("batchSpecID", int(batchSpecID)),
+		log.Int("userID", int(userID)),
 	}})
 	defer endObservation(1, observation.Args{})
 
 	cond := sqlf.Sprintf(executableWorkspaceJobsConditionFmtstr)
-	q := sqlf.Sprintf(createBatchSpecWorkspaceExecutionJobsQueryFmtstr, batchSpecID, cond)
+	q := sqlf.Sprintf(createBatchSpecWorkspaceExecutionJobsQueryFmtstr, nullInt32Column(userID), batchSpecID, cond)
 	return s.Exec(ctx, q)
 }
 
@@ -344,6 +349,93 @@ WHERE
 	id = %s
 `
 
+// BatchSpecWorkspaceExecutionJobDequeueLatencyPercentiles returns the given
+// percentiles of the time spent in the queue by the execution jobs that were
+// dequeued since the given time. Zero durations are returned if no job was
+// dequeued.
+func (s *Store) BatchSpecWorkspaceExecutionJobDequeueLatencyPercentiles(ctx context.Context, since time.Time, percentiles []float64) (latencies []time.Duration, err error) {
+	ctx, endObservation := s.operations.batchSpecWorkspaceExecutionJobDequeueLatencyPercentiles.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	q := sqlf.Sprintf(batchSpecWorkspaceExecutionJobDequeueLatencyPercentilesQueryFmtstr, pq.Array(percentiles), since)
+
+	var seconds pq.Float64Array
+	if err := s.QueryRow(ctx, q).Scan(&seconds); err != nil {
+		return nil, err
+	}
+
+	latencies = make([]time.Duration, len(percentiles))
+	for i := range latencies {
+		if i < len(seconds) {
+			latencies[i] = time.Duration(seconds[i] * float64(time.Second))
+		}
+	}
+	return latencies, nil
+}
+
+var batchSpecWorkspaceExecutionJobDequeueLatencyPercentilesQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_spec_workspace_execution_jobs.go:BatchSpecWorkspaceExecutionJobDequeueLatencyPercentiles
+SELECT
+	percentile_cont(%s::float8[]) WITHIN GROUP (
+		ORDER BY EXTRACT(EPOCH FROM started_at - COALESCE(process_after, created_at))
+	)
+FROM
+	batch_spec_workspace_execution_jobs
+WHERE
+	started_at >= %s
+`
+
+// BatchSpecWorkspaceExecutionImageStatistics counts the finished execution jobs
+// that ran a step in the given image.
+type BatchSpecWorkspaceExecutionImageStatistics struct {
+	Image     string
+	Completed int
+	Failed    int
+}
+
+// ListBatchSpecWorkspaceExecutionImageStatistics returns the number of execution
+// jobs per step image that completed or failed since the given time. Errored
+// jobs that are retried count as failed.
+func (s *Store) ListBatchSpecWorkspaceExecutionImageStatistics(ctx context.Context, since time.Time) (stats []BatchSpecWorkspaceExecutionImageStatistics, err error) {
+	ctx, endObservation := s.operations.listBatchSpecWorkspaceExecutionImageStatistics.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	q := sqlf.Sprintf(listBatchSpecWorkspaceExecutionImageStatisticsQueryFmtstr, since)
+	err = s.query(ctx, q, func(sc dbutil.Scanner) error {
+		var stat BatchSpecWorkspaceExecutionImageStatistics
+		if err := sc.Scan(&stat.Image, &stat.Completed, &stat.Failed); err != nil {
+			return err
+		}
+		stats = append(stats, stat)
+		return nil
+	})
+	return stats, err
+}
+
+var listBatchSpecWorkspaceExecutionImageStatisticsQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_spec_workspace_execution_jobs.go:ListBatchSpecWorkspaceExecutionImageStatistics
+SELECT
+	images.image,
+	COUNT(*) FILTER (WHERE exec.state = 'completed'),
+	COUNT(*) FILTER (WHERE exec.state IN ('errored', 'failed'))
+FROM
+	batch_spec_workspace_execution_jobs exec
+JOIN
+	batch_spec_workspaces ON batch_spec_workspaces.id = exec.batch_spec_workspace_id
+CROSS JOIN LATERAL (
+	SELECT DISTINCT step->>'container' AS image
+	FROM jsonb_array_elements(batch_spec_workspaces.steps) AS step
+) images
+WHERE
+	exec.finished_at >= %s
+AND
+	exec.state IN ('completed', 'errored', 'failed')
+GROUP BY
+	images.image
+ORDER BY
+	images.image
+`
+
 func ScanBatchSpecWorkspaceExecutionJob(wj *btypes.BatchSpecWorkspaceExecutionJob, s dbutil.Scanner) error {
 	var executionLogs []dbworkerstore.ExecutionLogEntry
 	var failureMessage string
@@ -352,6 +444,7 @@ func ScanBatchSpecWorkspaceExecutionJob(wj *btypes.BatchSpecWorkspaceExecutionJo
 		&wj.ID,
 		&wj.BatchSpecWorkspaceID,
 		&dbutil.NullInt64{N: &wj.AccessTokenID},
+		&dbutil.NullInt32{N: &wj.UserID},
 		&wj.State,
 		&dbutil.NullString{S: &failureMessage},
 		&dbutil.NullTime{Time: &wj.StartedAt},
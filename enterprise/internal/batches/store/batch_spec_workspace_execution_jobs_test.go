@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/keegancsmith/sqlf"
@@ -387,7 +388,7 @@ func testStoreBatchSpecWorkspaceExecutionJobs(t *testing.T, ctx context.Context,
 		createJobsAndAssert := func(t *testing.T, batchSpec *btypes.BatchSpec, wantJobsForWorkspaces []int64) {
 			t.Helper()
 
-			err := s.CreateBatchSpecWorkspaceExecutionJobs(ctx, batchSpec.ID)
+			err := s.CreateBatchSpecWorkspaceExecutionJobs(ctx, batchSpec.ID, batchSpec.UserID)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -449,3 +450,92 @@ func testStoreBatchSpecWorkspaceExecutionJobs(t *testing.T, ctx context.Context,
 		})
 	})
 }
+
+func testStoreBatchSpecWorkspaceExecutionJobStatistics(t *testing.T, ctx context.Context, s *Store, clock ct.Clock) {
+	batchSpec := &btypes.BatchSpec{NamespaceUserID: 1}
+	if err := s.CreateBatchSpec(ctx, batchSpec); err != nil {
+		t.Fatal(err)
+	}
+
+	alpineSteps := []batches.Step{{Run: "echo lol", Container: "alpine:3"}, {Run: "echo lol2", Container: "alpine:3"}}
+	mixedSteps := []batches.Step{{Run: "echo lol", Container: "alpine:3"}, {Run: "comby", Container: "comby/comby"}}
+
+	specs := []struct {
+		state   btypes.BatchSpecWorkspaceExecutionJobState
+		steps   []batches.Step
+		latency int
+	}{
+		{btypes.BatchSpecWorkspaceExecutionJobStateCompleted, alpineSteps, 10},
+		{btypes.BatchSpecWorkspaceExecutionJobStateCompleted, mixedSteps, 20},
+		{btypes.BatchSpecWorkspaceExecutionJobStateFailed, mixedSteps, 40},
+		{btypes.BatchSpecWorkspaceExecutionJobStateProcessing, alpineSteps, 30},
+		{btypes.BatchSpecWorkspaceExecutionJobStateQueued, alpineSteps, 0},
+	}
+
+	since := clock.Now()
+	for i, spec := range specs {
+		workspace := &btypes.BatchSpecWorkspace{
+			BatchSpecID: batchSpec.ID,
+			RepoID:      1,
+			Branch:      fmt.Sprintf("refs/heads/main-%d", i),
+			Commit:      fmt.Sprintf("commit-%d", i),
+			Steps:       spec.steps,
+		}
+		if err := s.CreateBatchSpecWorkspace(ctx, workspace); err != nil {
+			t.Fatal(err)
+		}
+
+		job := &btypes.BatchSpecWorkspaceExecutionJob{BatchSpecWorkspaceID: workspace.ID}
+		if err := ct.CreateBatchSpecWorkspaceExecutionJob(ctx, s, ScanBatchSpecWorkspaceExecutionJob, job); err != nil {
+			t.Fatal(err)
+		}
+
+		if spec.state == btypes.BatchSpecWorkspaceExecutionJobStateQueued {
+			continue
+		}
+
+		startedAt := job.CreatedAt.Add(time.Duration(spec.latency) * time.Minute)
+		var finishedAt *time.Time
+		if spec.state != btypes.BatchSpecWorkspaceExecutionJobStateProcessing {
+			finishedAt = &startedAt
+		}
+
+		q := sqlf.Sprintf("UPDATE batch_spec_workspace_execution_jobs SET state = %s, started_at = %s, finished_at = %s WHERE id = %s", spec.state, startedAt, finishedAt, job.ID)
+		if err := s.Exec(ctx, q); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("DequeueLatencyPercentiles", func(t *testing.T) {
+		have, err := s.BatchSpecWorkspaceExecutionJobDequeueLatencyPercentiles(ctx, since, []float64{0, 0.5, 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []time.Duration{10 * time.Minute, 25 * time.Minute, 40 * time.Minute}
+		if diff := cmp.Diff(want, have); diff != "" {
+			t.Fatal(diff)
+		}
+
+		have, err = s.BatchSpecWorkspaceExecutionJobDequeueLatencyPercentiles(ctx, since.Add(time.Hour), []float64{0.5})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]time.Duration{0}, have); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("ImageStatistics", func(t *testing.T) {
+		have, err := s.ListBatchSpecWorkspaceExecutionImageStatistics(ctx, since)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []BatchSpecWorkspaceExecutionImageStatistics{
+			{Image: "alpine:3", Completed: 2, Failed: 1},
+			{Image: "comby/comby", Completed: 1, Failed: 1},
+		}
+		if diff := cmp.Diff(want, have); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+}
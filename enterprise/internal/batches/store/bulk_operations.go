@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/keegancsmith/sqlf"
@@ -269,6 +270,62 @@ func listBulkOperationErrorsQuery(opts *ListBulkOperationErrorsOpts) *sqlf.Query
 	)
 }
 
+// ListBulkOperationResultsOpts captures the query options needed for getting
+// a list of BulkOperationResults.
+type ListBulkOperationResultsOpts struct {
+	BulkOperationID string
+}
+
+// ListBulkOperationResults gets the per-changeset results of a given
+// BulkOperation.
+func (s *Store) ListBulkOperationResults(ctx context.Context, opts ListBulkOperationResultsOpts) (rs []*btypes.BulkOperationResult, err error) {
+	ctx, endObservation := s.operations.listBulkOperationResults.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.String("bulkOperationID", opts.BulkOperationID),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	q := listBulkOperationResultsQuery(&opts)
+
+	rs = make([]*btypes.BulkOperationResult, 0)
+	err = s.query(ctx, q, func(sc dbutil.Scanner) error {
+		var r btypes.BulkOperationResult
+		if err := scanBulkOperationResult(&r, sc); err != nil {
+			return err
+		}
+		rs = append(rs, &r)
+		return nil
+	})
+
+	return rs, err
+}
+
+var listBulkOperationResultsQueryFmtstr = `
+-- source: enterprise/internal/batches/store/bulk_operations.go:ListBulkOperationResults
+SELECT
+    changeset_jobs.changeset_id AS changeset_id,
+    changeset_jobs.state AS state,
+    changeset_jobs.failure_message AS error,
+    changeset_jobs.finished_at AS finished_at
+FROM changeset_jobs
+INNER JOIN changesets ON changesets.id = changeset_jobs.changeset_id
+INNER JOIN repo ON repo.id = changesets.repo_id
+WHERE
+    %s
+ORDER BY changeset_jobs.id ASC
+`
+
+func listBulkOperationResultsQuery(opts *ListBulkOperationResultsOpts) *sqlf.Query {
+	preds := []*sqlf.Query{
+		sqlf.Sprintf("repo.deleted_at IS NULL"),
+		sqlf.Sprintf("changeset_jobs.bulk_group = %s", opts.BulkOperationID),
+	}
+
+	return sqlf.Sprintf(
+		listBulkOperationResultsQueryFmtstr,
+		sqlf.Join(preds, "\n AND "),
+	)
+}
+
 func scanBulkOperation(b *btypes.BulkOperation, s dbutil.Scanner) error {
 	return s.Scan(
 		&b.ID,
@@ -289,3 +346,17 @@ func scanBulkOperationError(b *btypes.BulkOperationError, s dbutil.Scanner) erro
 		&b.Error,
 	)
 }
+
+func scanBulkOperationResult(r *btypes.BulkOperationResult, s dbutil.Scanner) error {
+	var state string
+	if err := s.Scan(
+		&r.ChangesetID,
+		&state,
+		&dbutil.NullString{S: &r.Error},
+		&dbutil.NullTime{Time: &r.FinishedAt},
+	); err != nil {
+		return err
+	}
+	r.State = btypes.ChangesetJobState(strings.ToUpper(state))
+	return nil
+}
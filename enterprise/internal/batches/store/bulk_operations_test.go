@@ -250,4 +250,35 @@ func testStoreBulkOperations(t *testing.T, ctx context.Context, s *Store, clock
 			}
 		}
 	})
+
+	t.Run("ListBulkOperationResults", func(t *testing.T) {
+		for i, job := range jobs {
+			have, err := s.ListBulkOperationResults(ctx, ListBulkOperationResultsOpts{
+				BulkOperationID: job.BulkGroup,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			// The changeset of the last job is in a deleted repo.
+			if i == cap(jobs)-1 {
+				if len(have) != 0 {
+					t.Fatalf("invalid amount of results returned, want=0 have=%d", len(have))
+				}
+				continue
+			}
+			want := []*btypes.BulkOperationResult{
+				{
+					ChangesetID: changeset.ID,
+					State:       btypes.ChangesetJobStateQueued,
+				},
+			}
+			if i == 0 {
+				want[0].State = btypes.ChangesetJobStateFailed
+				want[0].Error = failureMessage
+			}
+			if diff := cmp.Diff(have, want); diff != "" {
+				t.Fatal(diff)
+			}
+		}
+	})
 }
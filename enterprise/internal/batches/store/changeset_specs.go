@@ -2,13 +2,16 @@ package store
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"strconv"
 
 	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
 	"github.com/keegancsmith/sqlf"
 	"github.com/lib/pq"
 	"github.com/opentracing/opentracing-go/log"
+	"github.com/sourcegraph/go-diff/diff"
 
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/search"
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
@@ -39,6 +42,9 @@ var changesetSpecInsertColumns = []*sqlf.Query{
 	sqlf.Sprintf("external_id"),
 	sqlf.Sprintf("head_ref"),
 	sqlf.Sprintf("title"),
+
+	// `diff_digest` is computed from `spec` on every write.
+	sqlf.Sprintf("diff_digest"),
 }
 
 // changesetSpecColumns are used by the changeset spec related Store methods to
@@ -55,6 +61,7 @@ var changesetSpecColumns = []*sqlf.Query{
 	sqlf.Sprintf("changeset_specs.diff_stat_deleted"),
 	sqlf.Sprintf("changeset_specs.created_at"),
 	sqlf.Sprintf("changeset_specs.updated_at"),
+	sqlf.Sprintf("changeset_specs.diff_digest"),
 }
 
 // CreateChangesetSpec creates the given ChangesetSpec.
@@ -73,7 +80,7 @@ func (s *Store) CreateChangesetSpec(ctx context.Context, c *btypes.ChangesetSpec
 var createChangesetSpecQueryFmtstr = `
 -- source: enterprise/internal/batches/store_changeset_specs.go:CreateChangesetSpec
 INSERT INTO changeset_specs (%s)
-VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 RETURNING %s`
 
 func (s *Store) createChangesetSpecQuery(c *btypes.ChangesetSpec) (*sqlf.Query, error) {
@@ -90,6 +97,8 @@ func (s *Store) createChangesetSpecQuery(c *btypes.ChangesetSpec) (*sqlf.Query,
 		c.UpdatedAt = c.CreatedAt
 	}
 
+	c.DiffDigest = btypes.ComputeDiffDigest(c.Spec)
+
 	var externalID, headRef, title *string
 	if c.Spec != nil {
 		if c.Spec.ExternalID != "" {
@@ -117,14 +126,15 @@ func (s *Store) createChangesetSpecQuery(c *btypes.ChangesetSpec) (*sqlf.Query,
 		nullInt64Column(c.BatchSpecID),
 		c.RepoID,
 		nullInt32Column(c.UserID),
-		c.DiffStatAdded,
-		c.DiffStatChanged,
-		c.DiffStatDeleted,
+		diffStatColumn(c, c.DiffStatAdded),
+		diffStatColumn(c, c.DiffStatChanged),
+		diffStatColumn(c, c.DiffStatDeleted),
 		c.CreatedAt,
 		c.UpdatedAt,
 		&dbutil.NullString{S: externalID},
 		&dbutil.NullString{S: headRef},
 		&dbutil.NullString{S: title},
+		dbutil.NewNullString(c.DiffDigest),
 		sqlf.Join(changesetSpecColumns, ", "),
 	), nil
 }
@@ -149,7 +159,7 @@ func (s *Store) UpdateChangesetSpec(ctx context.Context, c *btypes.ChangesetSpec
 var updateChangesetSpecQueryFmtstr = `
 -- source: enterprise/internal/batches/store_changeset_specs.go:UpdateChangesetSpec
 UPDATE changeset_specs
-SET (%s) = (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+SET (%s) = (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 WHERE id = %s
 RETURNING %s`
 
@@ -161,6 +171,8 @@ func (s *Store) updateChangesetSpecQuery(c *btypes.ChangesetSpec) (*sqlf.Query,
 
 	c.UpdatedAt = s.now()
 
+	c.DiffDigest = btypes.ComputeDiffDigest(c.Spec)
+
 	var externalID, headRef, title *string
 	if c.Spec != nil {
 		if c.Spec.ExternalID != "" {
@@ -182,14 +194,15 @@ func (s *Store) updateChangesetSpecQuery(c *btypes.ChangesetSpec) (*sqlf.Query,
 		nullInt64Column(c.BatchSpecID),
 		c.RepoID,
 		nullInt32Column(c.UserID),
-		c.DiffStatAdded,
-		c.DiffStatChanged,
-		c.DiffStatDeleted,
+		diffStatColumn(c, c.DiffStatAdded),
+		diffStatColumn(c, c.DiffStatChanged),
+		diffStatColumn(c, c.DiffStatDeleted),
 		c.CreatedAt,
 		c.UpdatedAt,
 		&dbutil.NullString{S: externalID},
 		&dbutil.NullString{S: headRef},
 		&dbutil.NullString{S: title},
+		dbutil.NewNullString(c.DiffDigest),
 		c.ID,
 		sqlf.Join(changesetSpecColumns, ", "),
 	), nil
@@ -410,6 +423,93 @@ func listChangesetSpecsQuery(opts *ListChangesetSpecsOpts) *sqlf.Query {
 	)
 }
 
+// ComputeDiffStats computes the diff stats of the changeset specs with the
+// given IDs whose diff stats haven't been computed yet and persists them, so
+// that subsequent reads don't have to parse the diffs again. The diff stats of
+// all given changeset specs are returned, keyed by changeset spec ID.
+func (s *Store) ComputeDiffStats(ctx context.Context, specIDs []int64) (stats map[int64]diff.Stat, err error) {
+	ctx, endObservation := s.operations.computeDiffStats.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("count", len(specIDs)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	stats = make(map[int64]diff.Stat, len(specIDs))
+	if len(specIDs) == 0 {
+		return stats, nil
+	}
+
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	var pending btypes.ChangesetSpecs
+	err = tx.query(ctx, computeDiffStatsQuery(specIDs), func(sc dbutil.Scanner) error {
+		var c btypes.ChangesetSpec
+		if err := scanChangesetSpec(&c, sc); err != nil {
+			return err
+		}
+		if c.DiffStatPending {
+			pending = append(pending, &c)
+		} else {
+			stats[c.ID] = c.DiffStat()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pending) == 0 {
+		return stats, nil
+	}
+
+	values := make([]*sqlf.Query, 0, len(pending))
+	for _, c := range pending {
+		if err := c.ComputeDiffStat(); err != nil {
+			// A spec with an unparseable diff is left as is, so it doesn't
+			// prevent the diff stats of the other specs from being cached.
+			log15.Warn("failed to compute diff stat of changeset spec", "id", c.ID, "err", err)
+			continue
+		}
+		stats[c.ID] = c.DiffStat()
+		values = append(values, sqlf.Sprintf("(%s::bigint, %s::integer, %s::integer, %s::integer)", c.ID, c.DiffStatAdded, c.DiffStatChanged, c.DiffStatDeleted))
+	}
+
+	if len(values) == 0 {
+		return stats, nil
+	}
+
+	return stats, tx.Exec(ctx, sqlf.Sprintf(updateDiffStatsQueryFmtstr, sqlf.Join(values, ", ")))
+}
+
+var computeDiffStatsQueryFmtstr = `
+-- source: enterprise/internal/batches/store/changeset_specs.go:ComputeDiffStats
+SELECT %s FROM changeset_specs
+WHERE changeset_specs.id = ANY (%s)
+FOR UPDATE
+`
+
+func computeDiffStatsQuery(specIDs []int64) *sqlf.Query {
+	return sqlf.Sprintf(
+		computeDiffStatsQueryFmtstr,
+		sqlf.Join(changesetSpecColumns, ", "),
+		pq.Array(specIDs),
+	)
+}
+
+const updateDiffStatsQueryFmtstr = `
+-- source: enterprise/internal/batches/store/changeset_specs.go:ComputeDiffStats
+UPDATE changeset_specs
+SET
+	diff_stat_added = v.added,
+	diff_stat_changed = v.changed,
+	diff_stat_deleted = v.deleted
+FROM (VALUES %s) AS v(id, added, changed, deleted)
+WHERE changeset_specs.id = v.id
+`
+
 type ChangesetSpecHeadRefConflict struct {
 	RepoID  api.RepoID
 	HeadRef string
@@ -449,6 +549,74 @@ func (s *Store) ListChangesetSpecsWithConflictingHeadRef(ctx context.Context, ba
 	return conflicts, err
 }
 
+// ListIdenticalChangesetSpecsOpts captures the query options needed for
+// listing identical changeset specs.
+type ListIdenticalChangesetSpecsOpts struct {
+	BatchSpecID   int64
+	BatchChangeID int64
+}
+
+// ListIdenticalChangesetSpecs returns the changeset specs of the given batch
+// spec that are identical to the current spec of a published changeset owned
+// by the given batch change, mapped to the ID of that changeset.
+//
+// Two specs are identical if they target the same repository, base ref and
+// head ref, have the same title, body and published value, and push the same
+// commits, as told by their diff digest. Applying an identical spec to a
+// changeset doesn't require any change on the code host.
+func (s *Store) ListIdenticalChangesetSpecs(ctx context.Context, opts ListIdenticalChangesetSpecsOpts) (changesetIDsBySpecID map[int64]int64, err error) {
+	ctx, endObservation := s.operations.listIdenticalChangesetSpecs.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("batchSpecID", int(opts.BatchSpecID)),
+		log.Int("batchChangeID", int(opts.BatchChangeID)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	q := sqlf.Sprintf(
+		listIdenticalChangesetSpecsQueryFmtstr,
+		opts.BatchChangeID,
+		btypes.ChangesetPublicationStatePublished,
+		opts.BatchSpecID,
+	)
+
+	changesetIDsBySpecID = map[int64]int64{}
+	err = s.query(ctx, q, func(sc dbutil.Scanner) error {
+		var specID, changesetID int64
+		if err := sc.Scan(&specID, &changesetID); err != nil {
+			return errors.Wrap(err, "scanning identical changeset spec")
+		}
+		changesetIDsBySpecID[specID] = changesetID
+		return nil
+	})
+
+	return changesetIDsBySpecID, err
+}
+
+var listIdenticalChangesetSpecsQueryFmtstr = `
+-- source: enterprise/internal/batches/store/changeset_specs.go:ListIdenticalChangesetSpecs
+SELECT
+	new_specs.id, changesets.id
+FROM
+	changeset_specs new_specs
+JOIN
+	changesets ON changesets.repo_id = new_specs.repo_id
+JOIN
+	changeset_specs current_specs ON current_specs.id = changesets.current_spec_id
+WHERE
+	changesets.owned_by_batch_change_id = %s AND
+	changesets.publication_state = %s AND
+	new_specs.batch_spec_id = %s AND
+	new_specs.id != current_specs.id AND
+	new_specs.external_id IS NULL AND
+	new_specs.diff_digest IS NOT NULL AND
+	new_specs.diff_digest = current_specs.diff_digest AND
+	new_specs.head_ref = current_specs.head_ref AND
+	new_specs.title IS NOT DISTINCT FROM current_specs.title AND
+	new_specs.spec->'baseRef' IS NOT DISTINCT FROM current_specs.spec->'baseRef' AND
+	new_specs.spec->'body' IS NOT DISTINCT FROM current_specs.spec->'body' AND
+	new_specs.spec->'published' IS NOT DISTINCT FROM current_specs.spec->'published'
+ORDER BY new_specs.id
+`
+
 // DeleteExpiredChangesetSpecs deletes each ChangesetSpec that has not been
 // attached to a BatchSpec within ChangesetSpecTTL, OR that is attached
 // to a BatchSpec that is not applied and is not attached to a Changeset
@@ -523,8 +691,21 @@ func deleteChangesetSpecsQuery(opts *DeleteChangesetSpecsOpts) *sqlf.Query {
 	return sqlf.Sprintf(deleteChangesetSpecsQueryFmtstr, sqlf.Join(preds, "\n AND "))
 }
 
+// diffStatColumn returns the value to persist for the given diff stat field of
+// the changeset spec. Diff stats that haven't been computed yet are stored as
+// NULL.
+func diffStatColumn(c *btypes.ChangesetSpec, n int32) *int32 {
+	if c.DiffStatPending {
+		return nil
+	}
+	return &n
+}
+
 func scanChangesetSpec(c *btypes.ChangesetSpec, s dbutil.Scanner) error {
-	var spec json.RawMessage
+	var (
+		spec                    json.RawMessage
+		added, changed, deleted sql.NullInt32
+	)
 
 	err := s.Scan(
 		&c.ID,
@@ -533,17 +714,23 @@ func scanChangesetSpec(c *btypes.ChangesetSpec, s dbutil.Scanner) error {
 		&dbutil.NullInt64{N: &c.BatchSpecID},
 		&c.RepoID,
 		&dbutil.NullInt32{N: &c.UserID},
-		&c.DiffStatAdded,
-		&c.DiffStatChanged,
-		&c.DiffStatDeleted,
+		&added,
+		&changed,
+		&deleted,
 		&c.CreatedAt,
 		&c.UpdatedAt,
+		&dbutil.NullString{S: &c.DiffDigest},
 	)
 
 	if err != nil {
 		return errors.Wrap(err, "scanning changeset spec")
 	}
 
+	c.DiffStatAdded = added.Int32
+	c.DiffStatChanged = changed.Int32
+	c.DiffStatDeleted = deleted.Int32
+	c.DiffStatPending = !added.Valid || !changed.Valid || !deleted.Valid
+
 	c.Spec = new(batcheslib.ChangesetSpec)
 	if err = json.Unmarshal(spec, c.Spec); err != nil {
 		return errors.Wrap(err, "scanChangesetSpec: failed to unmarshal spec")
@@ -579,14 +766,14 @@ type GetRewirerMappingsOpts struct {
 // └───────────────────────────────────────┘   └───────────────────────────────┘
 //
 // We need to:
-// 1. Find out whether our new specs should _update_ an existing
-//    changeset (ChangesetSpec != 0, Changeset != 0), or whether we need to create a new one.
-// 2. Since we can have multiple changesets per repository, we need to match
-//    based on repo and external ID for imported changesets and on repo and head_ref for 'branch' changesets.
-// 3. If a changeset wasn't published yet, it doesn't have an external ID nor does it have an external head_ref.
-//    In that case, we need to check whether the branch on which we _might_
-//    push the commit (because the changeset might not be published
-//    yet) is the same or compare the external IDs in the current and new specs.
+//  1. Find out whether our new specs should _update_ an existing
+//     changeset (ChangesetSpec != 0, Changeset != 0), or whether we need to create a new one.
+//  2. Since we can have multiple changesets per repository, we need to match
+//     based on repo and external ID for imported changesets and on repo and head_ref for 'branch' changesets.
+//  3. If a changeset wasn't published yet, it doesn't have an external ID nor does it have an external head_ref.
+//     In that case, we need to check whether the branch on which we _might_
+//     push the commit (because the changeset might not be published
+//     yet) is the same or compare the external IDs in the current and new specs.
 //
 // What we want:
 //
@@ -667,6 +854,17 @@ func (s *Store) GetRewirerMappings(ctx context.Context, opts GetRewirerMappingsO
 		return nil, err
 	}
 
+	var identicalChangesetIDsBySpecID map[int64]int64
+	if opts.BatchChangeID != 0 && len(changesetSpecIDs) > 0 && len(changesetIDs) > 0 {
+		identicalChangesetIDsBySpecID, err = s.ListIdenticalChangesetSpecs(ctx, ListIdenticalChangesetSpecsOpts{
+			BatchSpecID:   opts.BatchSpecID,
+			BatchChangeID: opts.BatchChangeID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	for _, m := range mappings {
 		if m.ChangesetID != 0 {
 			m.Changeset = changesetsByID[m.ChangesetID]
@@ -678,6 +876,9 @@ func (s *Store) GetRewirerMappings(ctx context.Context, opts GetRewirerMappingsO
 			// This can be nil, but that's okay. It just means the ctx actor has no access to the repo.
 			m.Repo = accessibleReposByID[m.RepoID]
 		}
+		if m.ChangesetSpecID != 0 && m.ChangesetID != 0 {
+			m.Identical = identicalChangesetIDsBySpecID[m.ChangesetSpecID] == m.ChangesetID
+		}
 	}
 
 	return mappings, err
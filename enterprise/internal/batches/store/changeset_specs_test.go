@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/go-diff/diff"
 
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/search"
 	ct "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/testing"
@@ -352,6 +353,49 @@ func testStoreChangesetSpecs(t *testing.T, ctx context.Context, s *Store, clock
 		})
 	})
 
+	t.Run("ComputeDiffStats", func(t *testing.T) {
+		pending, err := btypes.NewChangesetSpecFromRaw(ct.NewRawChangesetSpecGitBranch("graphql-id", "d34db33f"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		pending.RepoID = repo.ID
+		// Store the spec without a diff stat.
+		pending.DiffStatPending = true
+		if err := s.CreateChangesetSpec(ctx, pending); err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			if err := s.DeleteChangesetSpec(ctx, pending.ID); err != nil {
+				t.Fatal(err)
+			}
+		}()
+
+		computed := changesetSpecs[0]
+		stats, err := s.ComputeDiffStats(ctx, []int64{pending.ID, computed.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := map[int64]diff.Stat{
+			pending.ID:  *ct.ChangesetSpecDiffStat,
+			computed.ID: computed.DiffStat(),
+		}
+		if diff := cmp.Diff(want, stats); diff != "" {
+			t.Fatalf("unexpected diff stats (-want +have):\n%s", diff)
+		}
+
+		have, err := s.GetChangesetSpecByID(ctx, pending.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have.DiffStatPending {
+			t.Fatal("computed diff stat was not persisted")
+		}
+		if diff := cmp.Diff(want[pending.ID], have.DiffStat()); diff != "" {
+			t.Fatalf("unexpected persisted diff stat (-want +have):\n%s", diff)
+		}
+	})
+
 	t.Run("DeleteChangesetSpec", func(t *testing.T) {
 		for i := range changesetSpecs {
 			err := s.DeleteChangesetSpec(ctx, changesetSpecs[i].ID)
@@ -832,6 +876,101 @@ func testStoreGetRewirerMappingWithArchivedChangesets(t *testing.T, ctx context.
 	}
 }
 
+func testStoreListIdenticalChangesetSpecs(t *testing.T, ctx context.Context, s *Store, clock ct.Clock) {
+	repoStore := database.ReposWith(s)
+	esStore := database.ExternalServicesWith(s)
+
+	repo := ct.TestRepo(t, esStore, extsvc.KindGitHub)
+	if err := repoStore.Create(ctx, repo); err != nil {
+		t.Fatal(err)
+	}
+
+	user := ct.CreateTestUser(t, s.DB(), false)
+
+	oldBatchSpec := ct.CreateBatchSpec(t, ctx, s, "old", user.ID)
+	batchChange := ct.CreateBatchChange(t, ctx, s, "identical", user.ID, oldBatchSpec.ID)
+	newBatchSpec := ct.CreateBatchSpec(t, ctx, s, "new", user.ID)
+
+	specOpts := func(batchSpecID int64, headRef, diff string) ct.TestSpecOpts {
+		return ct.TestSpecOpts{
+			User:       user.ID,
+			Repo:       repo.ID,
+			BatchSpec:  batchSpecID,
+			Title:      "title",
+			Body:       "body",
+			Published:  true,
+			BaseRef:    "refs/heads/main",
+			HeadRef:    headRef,
+			CommitDiff: diff,
+		}
+	}
+
+	// A published changeset whose new spec is identical.
+	identicalOldSpec := ct.CreateChangesetSpec(t, ctx, s, specOpts(oldBatchSpec.ID, "refs/heads/identical", "diff"))
+	identicalNewSpec := ct.CreateChangesetSpec(t, ctx, s, specOpts(newBatchSpec.ID, "refs/heads/identical", "diff"))
+	identicalChangeset := ct.CreateChangeset(t, ctx, s, ct.TestChangesetOpts{
+		Repo:               repo.ID,
+		BatchChange:        batchChange.ID,
+		CurrentSpec:        identicalOldSpec.ID,
+		OwnedByBatchChange: batchChange.ID,
+		PublicationState:   btypes.ChangesetPublicationStatePublished,
+		ExternalState:      btypes.ChangesetExternalStateOpen,
+		ExternalID:         "1",
+	})
+
+	// A published changeset whose new spec has another diff.
+	changedOldSpec := ct.CreateChangesetSpec(t, ctx, s, specOpts(oldBatchSpec.ID, "refs/heads/changed", "diff"))
+	ct.CreateChangesetSpec(t, ctx, s, specOpts(newBatchSpec.ID, "refs/heads/changed", "another diff"))
+	ct.CreateChangeset(t, ctx, s, ct.TestChangesetOpts{
+		Repo:               repo.ID,
+		BatchChange:        batchChange.ID,
+		CurrentSpec:        changedOldSpec.ID,
+		OwnedByBatchChange: batchChange.ID,
+		PublicationState:   btypes.ChangesetPublicationStatePublished,
+		ExternalState:      btypes.ChangesetExternalStateOpen,
+		ExternalID:         "2",
+	})
+
+	// An unpublished changeset whose new spec is identical.
+	unpublishedOldSpec := ct.CreateChangesetSpec(t, ctx, s, specOpts(oldBatchSpec.ID, "refs/heads/unpublished", "diff"))
+	ct.CreateChangesetSpec(t, ctx, s, specOpts(newBatchSpec.ID, "refs/heads/unpublished", "diff"))
+	ct.CreateChangeset(t, ctx, s, ct.TestChangesetOpts{
+		Repo:               repo.ID,
+		BatchChange:        batchChange.ID,
+		CurrentSpec:        unpublishedOldSpec.ID,
+		OwnedByBatchChange: batchChange.ID,
+		PublicationState:   btypes.ChangesetPublicationStateUnpublished,
+	})
+
+	have, err := s.ListIdenticalChangesetSpecs(ctx, ListIdenticalChangesetSpecsOpts{
+		BatchSpecID:   newBatchSpec.ID,
+		BatchChangeID: batchChange.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[int64]int64{identicalNewSpec.ID: identicalChangeset.ID}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Fatalf("unexpected identical changeset specs (-want +have):\n%s", diff)
+	}
+
+	mappings, err := s.GetRewirerMappings(ctx, GetRewirerMappingsOpts{
+		BatchSpecID:   newBatchSpec.ID,
+		BatchChangeID: batchChange.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mappings) != 3 {
+		t.Fatalf("unexpected number of mappings. want=%d have=%d", 3, len(mappings))
+	}
+	for _, m := range mappings {
+		if wantIdentical := m.ChangesetSpecID == identicalNewSpec.ID; m.Identical != wantIdentical {
+			t.Errorf("unexpected identical flag for changeset spec %d. want=%t have=%t", m.ChangesetSpecID, wantIdentical, m.Identical)
+		}
+	}
+}
+
 func testStoreChangesetSpecsCurrentState(t *testing.T, ctx context.Context, s *Store, clock ct.Clock) {
 	repoStore := database.ReposWith(s)
 	esStore := database.ExternalServicesWith(s)
@@ -22,6 +22,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
@@ -64,6 +66,8 @@ var ChangesetColumns = []*sqlf.Query{
 	sqlf.Sprintf("changesets.num_failures"),
 	sqlf.Sprintf("changesets.closing"),
 	sqlf.Sprintf("changesets.syncer_error"),
+	sqlf.Sprintf("changesets.rebasing"),
+	sqlf.Sprintf("changesets.rebased_onto"),
 }
 
 // changesetInsertColumns is the list of changeset columns that are modified in
@@ -100,6 +104,8 @@ var changesetInsertColumns = []*sqlf.Query{
 	sqlf.Sprintf("num_failures"),
 	sqlf.Sprintf("closing"),
 	sqlf.Sprintf("syncer_error"),
+	sqlf.Sprintf("rebasing"),
+	sqlf.Sprintf("rebased_onto"),
 	// We additionally store the result of changeset.Title() in a column, so
 	// the business logic for determining it is in one place and the field is
 	// indexable for searching.
@@ -181,6 +187,8 @@ func (s *Store) changesetWriteQuery(q string, includeID bool, c *btypes.Changese
 		c.NumFailures,
 		c.Closing,
 		c.SyncErrorMessage,
+		c.Rebasing,
+		nullStringColumn(c.RebasedOnto),
 		nullStringColumn(title),
 	}
 
@@ -225,7 +233,7 @@ func (s *Store) CreateChangeset(ctx context.Context, c *btypes.Changeset) (err e
 var createChangesetQueryFmtstr = `
 -- source: enterprise/internal/batches/store.go:CreateChangeset
 INSERT INTO changesets (%s)
-VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 RETURNING %s
 `
 
@@ -713,7 +721,7 @@ func (s *Store) UpdateChangeset(ctx context.Context, cs *btypes.Changeset) (err
 var updateChangesetQueryFmtstr = `
 -- source: enterprise/internal/batches/store_changesets.go:UpdateChangeset
 UPDATE changesets
-SET (%s) = (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+SET (%s) = (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 WHERE id = %s
 RETURNING
   %s
@@ -1032,6 +1040,188 @@ updated_records AS (
 SELECT COUNT(id) FROM all_matching WHERE all_matching.reconciler_state = %s
 `
 
+// ListChangesetsToRebase returns the open, published changesets that are
+// owned by a batch change with auto rebase enabled and that have been fully
+// processed by the reconciler. Changesets that are currently being rebased
+// are not returned. Only changesets with an ID greater than afterID are
+// returned, ordered by ID, so that callers can page through all candidates.
+func (s *Store) ListChangesetsToRebase(ctx context.Context, afterID int64, limit int) (cs btypes.Changesets, err error) {
+	ctx, endObservation := s.operations.listChangesetsToRebase.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("afterID", int(afterID)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	q := sqlf.Sprintf(
+		listChangesetsToRebaseFmtstr,
+		sqlf.Join(ChangesetColumns, ", "),
+		btypes.ChangesetPublicationStatePublished,
+		btypes.ReconcilerStateCompleted.ToDB(),
+		btypes.ChangesetExternalStateOpen,
+		btypes.ChangesetExternalStateDraft,
+		afterID,
+		limit,
+	)
+
+	err = s.query(ctx, q, func(sc dbutil.Scanner) error {
+		var c btypes.Changeset
+		if err := scanChangeset(&c, sc); err != nil {
+			return err
+		}
+		cs = append(cs, &c)
+		return nil
+	})
+	return cs, err
+}
+
+const listChangesetsToRebaseFmtstr = `
+-- source: enterprise/internal/batches/store/changesets.go:ListChangesetsToRebase
+SELECT %s
+FROM changesets
+INNER JOIN batch_changes ON batch_changes.id = changesets.owned_by_batch_change_id
+INNER JOIN repo ON repo.id = changesets.repo_id
+WHERE
+	batch_changes.auto_rebase
+	AND
+	changesets.current_spec_id IS NOT NULL
+	AND
+	batch_changes.closed_at IS NULL
+	AND
+	changesets.publication_state = %s
+	AND
+	changesets.reconciler_state = %s
+	AND
+	changesets.external_state IN (%s, %s)
+	AND
+	NOT changesets.rebasing
+	AND
+	NOT changesets.closing
+	AND
+	repo.deleted_at IS NULL
+	AND
+	changesets.id > %s
+ORDER BY changesets.id ASC
+LIMIT %s
+`
+
+// EnqueueChangesetToRebase marks the given changeset as to be rebased onto
+// the given base revision and enqueues it for the reconciler. It returns
+// false if the changeset has been modified in the meantime and was not
+// enqueued.
+func (s *Store) EnqueueChangesetToRebase(ctx context.Context, id int64, baseRev string) (ok bool, err error) {
+	ctx, endObservation := s.operations.enqueueChangesetToRebase.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("ID", int(id)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	q := sqlf.Sprintf(
+		enqueueChangesetToRebaseFmtstr,
+		btypes.ReconcilerStateQueued.ToDB(),
+		baseRev,
+		s.now(),
+		id,
+		btypes.ReconcilerStateCompleted.ToDB(),
+	)
+	_, ok, err = basestore.ScanFirstInt(s.Query(ctx, q))
+	return ok, err
+}
+
+const enqueueChangesetToRebaseFmtstr = `
+-- source: enterprise/internal/batches/store/changesets.go:EnqueueChangesetToRebase
+UPDATE changesets
+SET
+	reconciler_state = %s,
+	num_resets = 0,
+	num_failures = 0,
+	failure_message = NULL,
+	rebasing = TRUE,
+	rebased_onto = %s,
+	updated_at = %s
+WHERE
+	id = %s
+	AND
+	reconciler_state = %s
+	AND
+	NOT rebasing
+RETURNING changesets.id
+`
+
+// ArchiveStaleChangesets archives up to limit merged and closed changesets in
+// the batch changes with automatic archival enabled whose last update on the
+// code host is older than the number of days configured for the batch
+// change. It returns the number of changesets archived per batch change.
+func (s *Store) ArchiveStaleChangesets(ctx context.Context, limit int) (archived map[int64]int, err error) {
+	ctx, endObservation := s.operations.archiveStaleChangesets.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	q := sqlf.Sprintf(
+		archiveStaleChangesetsFmtstr,
+		btypes.ChangesetPublicationStatePublished,
+		btypes.ReconcilerStateCompleted.ToDB(),
+		btypes.ChangesetExternalStateMerged,
+		btypes.ChangesetExternalStateClosed,
+		s.now(),
+		limit,
+		s.now(),
+	)
+
+	archived = map[int64]int{}
+	err = s.query(ctx, q, func(sc dbutil.Scanner) error {
+		var batchChangeID int64
+		if err := sc.Scan(&batchChangeID); err != nil {
+			return err
+		}
+		archived[batchChangeID]++
+		return nil
+	})
+	return archived, err
+}
+
+const archiveStaleChangesetsFmtstr = `
+-- source: enterprise/internal/batches/store/changesets.go:ArchiveStaleChangesets
+WITH candidates AS (
+	SELECT
+		changesets.id,
+		batch_changes.id AS batch_change_id
+	FROM changesets
+	INNER JOIN batch_changes ON changesets.batch_change_ids ? batch_changes.id::text
+	INNER JOIN repo ON repo.id = changesets.repo_id
+	WHERE
+		batch_changes.auto_archive_after_days > 0
+		AND
+		batch_changes.closed_at IS NULL
+		AND
+		changesets.publication_state = %s
+		AND
+		changesets.reconciler_state = %s
+		AND
+		changesets.external_state IN (%s, %s)
+		AND
+		changesets.external_updated_at < %s::timestamptz - batch_changes.auto_archive_after_days * INTERVAL '1 day'
+		AND
+		NOT COALESCE((changesets.batch_change_ids->batch_changes.id::text->>'isArchived')::bool, false)
+		AND
+		NOT COALESCE((changesets.batch_change_ids->batch_changes.id::text->>'archive')::bool, false)
+		AND
+		NOT COALESCE((changesets.batch_change_ids->batch_changes.id::text->>'detach')::bool, false)
+		AND
+		repo.deleted_at IS NULL
+	ORDER BY changesets.id ASC
+	LIMIT %s
+	FOR UPDATE OF changesets SKIP LOCKED
+)
+UPDATE changesets
+SET
+	batch_change_ids = jsonb_set(
+		changesets.batch_change_ids,
+		ARRAY[candidates.batch_change_id::text],
+		changesets.batch_change_ids->candidates.batch_change_id::text || '{"isArchived": true}'
+	),
+	updated_at = %s
+FROM candidates
+WHERE changesets.id = candidates.id
+RETURNING candidates.batch_change_id
+`
+
 func ScanFirstChangeset(rows *sql.Rows, err error) (*btypes.Changeset, bool, error) {
 	changesets, err := scanChangesets(rows, err)
 	if err != nil || len(changesets) == 0 {
@@ -1149,6 +1339,8 @@ func scanChangeset(t *btypes.Changeset, s dbutil.Scanner) error {
 		&t.NumFailures,
 		&t.Closing,
 		&dbutil.NullString{S: &syncErrorMessage},
+		&t.Rebasing,
+		&dbutil.NullString{S: &t.RebasedOnto},
 	)
 	if err != nil {
 		return errors.Wrap(err, "scanning changeset")
@@ -1172,6 +1364,10 @@ func scanChangeset(t *btypes.Changeset, s dbutil.Scanner) error {
 		t.Metadata = new(bitbucketserver.PullRequest)
 	case extsvc.TypeGitLab:
 		t.Metadata = new(gitlab.MergeRequest)
+	case extsvc.TypeAzureDevOps:
+		t.Metadata = new(azuredevops.PullRequest)
+	case extsvc.TypeBitbucketCloud:
+		t.Metadata = new(bitbucketcloud.PullRequest)
 	default:
 		return errors.New("unknown external service type")
 	}
@@ -1186,8 +1382,9 @@ func scanChangeset(t *btypes.Changeset, s dbutil.Scanner) error {
 	return nil
 }
 
-// GetChangesetsStats returns statistics on all the changesets associated to the given batch change,
-// or all changesets across the instance.
+// GetChangesetsStats returns statistics on all the changesets associated to the given batch change.
+// All state counts are computed in a single aggregated query, so that the cost doesn't grow with
+// the number of states.
 func (s *Store) GetChangesetsStats(ctx context.Context, batchChangeID int64) (stats btypes.ChangesetsStats, err error) {
 	ctx, endObservation := s.operations.getChangesetsStats.With(ctx, &err, observation.Args{LogFields: []log.Field{
 		log.Int("batchChangeID", int(batchChangeID)),
@@ -1221,7 +1418,7 @@ func (s *Store) GetChangesetsStats(ctx context.Context, batchChangeID int64) (st
 }
 
 const getChangesetStatsFmtstr = `
--- source: enterprise/internal/batches/store_changesets.go:GetChangesetsStats
+-- source: enterprise/internal/batches/store/changesets.go:GetChangesetsStats
 SELECT
 	COUNT(*) AS total,
 	COUNT(*) FILTER (WHERE changesets.reconciler_state = 'errored') AS retrying,
@@ -1386,6 +1386,133 @@ func testStoreChangesets(t *testing.T, ctx context.Context, s *Store, clock ct.C
 		})
 	})
 
+	t.Run("ArchiveStaleChangesets", func(t *testing.T) {
+		spec := ct.CreateBatchSpec(t, ctx, s, "auto-archive", user.ID)
+		batchChange := ct.BuildBatchChange(s, "auto-archive", user.ID, spec.ID)
+		batchChange.AutoArchiveAfterDays = 7
+		if err := s.CreateBatchChange(ctx, batchChange); err != nil {
+			t.Fatal(err)
+		}
+		otherBatchChange := ct.CreateBatchChange(t, ctx, s, "no-auto-archive", user.ID, spec.ID)
+
+		stale := clock.Now().Add(-8 * 24 * time.Hour)
+		recent := clock.Now().Add(-6 * 24 * time.Hour)
+
+		create := func(batchChangeID int64, state btypes.ChangesetExternalState, updatedAt time.Time) *btypes.Changeset {
+			return ct.CreateChangeset(t, ctx, s, ct.TestChangesetOpts{
+				Repo:              repo.ID,
+				BatchChange:       batchChangeID,
+				ExternalState:     state,
+				ExternalUpdatedAt: updatedAt,
+				PublicationState:  btypes.ChangesetPublicationStatePublished,
+				ReconcilerState:   btypes.ReconcilerStateCompleted,
+			})
+		}
+
+		staleMerged := create(batchChange.ID, btypes.ChangesetExternalStateMerged, stale)
+		staleClosed := create(batchChange.ID, btypes.ChangesetExternalStateClosed, stale)
+		recentMerged := create(batchChange.ID, btypes.ChangesetExternalStateMerged, recent)
+		staleOpen := create(batchChange.ID, btypes.ChangesetExternalStateOpen, stale)
+		otherStaleMerged := create(otherBatchChange.ID, btypes.ChangesetExternalStateMerged, stale)
+
+		archived, err := s.ArchiveStaleChangesets(ctx, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(map[int64]int{batchChange.ID: 2}, archived); diff != "" {
+			t.Fatalf("wrong archived changesets. diff=%s", diff)
+		}
+
+		for _, tc := range []struct {
+			changeset *btypes.Changeset
+			archived  bool
+		}{
+			{staleMerged, true},
+			{staleClosed, true},
+			{recentMerged, false},
+			{staleOpen, false},
+			{otherStaleMerged, false},
+		} {
+			reloaded, err := s.GetChangesetByID(ctx, tc.changeset.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if have := reloaded.ArchivedIn(tc.changeset.BatchChanges[0].BatchChangeID); have != tc.archived {
+				t.Fatalf("changeset %d: wrong archived state. want=%t, have=%t", tc.changeset.ID, tc.archived, have)
+			}
+		}
+
+		// Archived changesets are not archived again.
+		archived, err = s.ArchiveStaleChangesets(ctx, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(archived) != 0 {
+			t.Fatalf("unexpected changesets archived: %+v", archived)
+		}
+	})
+
+	t.Run("ListChangesetsToRebase", func(t *testing.T) {
+		spec := ct.CreateBatchSpec(t, ctx, s, "auto-rebase", user.ID)
+		batchChange := ct.BuildBatchChange(s, "auto-rebase", user.ID, spec.ID)
+		batchChange.AutoRebase = true
+		if err := s.CreateBatchChange(ctx, batchChange); err != nil {
+			t.Fatal(err)
+		}
+
+		var want []int64
+		for i := 0; i < 3; i++ {
+			changesetSpec := ct.CreateChangesetSpec(t, ctx, s, ct.TestSpecOpts{
+				HeadRef:   fmt.Sprintf("refs/heads/auto-rebase-%d", i),
+				Repo:      repo.ID,
+				BatchSpec: spec.ID,
+			})
+			c := ct.CreateChangeset(t, ctx, s, ct.TestChangesetOpts{
+				Repo:               repo.ID,
+				BatchChange:        batchChange.ID,
+				OwnedByBatchChange: batchChange.ID,
+				CurrentSpec:        changesetSpec.ID,
+				ExternalState:      btypes.ChangesetExternalStateOpen,
+				PublicationState:   btypes.ChangesetPublicationStatePublished,
+				ReconcilerState:    btypes.ReconcilerStateCompleted,
+			})
+			want = append(want, c.ID)
+		}
+
+		var have []int64
+		var cursor int64
+		for {
+			cs, err := s.ListChangesetsToRebase(ctx, cursor, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, c := range cs {
+				have = append(have, c.ID)
+			}
+			if len(cs) < 2 {
+				break
+			}
+			cursor = cs[len(cs)-1].ID
+		}
+		if diff := cmp.Diff(want, have); diff != "" {
+			t.Fatalf("wrong changesets listed. diff=%s", diff)
+		}
+
+		// Changesets that are being rebased are not listed.
+		if ok, err := s.EnqueueChangesetToRebase(ctx, want[0], "d34db33f"); err != nil {
+			t.Fatal(err)
+		} else if !ok {
+			t.Fatal("changeset not enqueued")
+		}
+		cs, err := s.ListChangesetsToRebase(ctx, 0, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want[1:], cs.IDs()); diff != "" {
+			t.Fatalf("wrong changesets listed. diff=%s", diff)
+		}
+	})
+
 	t.Run("UpdateChangesetBatchChanges", func(t *testing.T) {
 		c1 := ct.CreateChangeset(t, ctx, s, ct.TestChangesetOpts{
 			ReconcilerState: btypes.ReconcilerStateCompleted,
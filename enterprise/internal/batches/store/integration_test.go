@@ -27,6 +27,7 @@ func TestIntegration(t *testing.T) {
 		t.Run("BatchSpecs", storeTest(db, nil, testStoreBatchSpecs))
 		t.Run("ChangesetSpecs", storeTest(db, nil, testStoreChangesetSpecs))
 		t.Run("GetRewirerMappingWithArchivedChangesets", storeTest(db, nil, testStoreGetRewirerMappingWithArchivedChangesets))
+		t.Run("ListIdenticalChangesetSpecs", storeTest(db, nil, testStoreListIdenticalChangesetSpecs))
 		t.Run("ChangesetSpecsCurrentState", storeTest(db, nil, testStoreChangesetSpecsCurrentState))
 		t.Run("ChangesetSpecsCurrentStateAndTextSearch", storeTest(db, nil, testStoreChangesetSpecsCurrentStateAndTextSearch))
 		t.Run("ChangesetSpecsTextSearch", storeTest(db, nil, testStoreChangesetSpecsTextSearch))
@@ -36,7 +37,9 @@ func TestIntegration(t *testing.T) {
 		t.Run("BulkOperations", storeTest(db, nil, testStoreBulkOperations))
 		t.Run("BatchSpecWorkspaces", storeTest(db, nil, testStoreBatchSpecWorkspaces))
 		t.Run("BatchSpecWorkspaceExecutionJobs", storeTest(db, nil, testStoreBatchSpecWorkspaceExecutionJobs))
+		t.Run("BatchSpecWorkspaceExecutionJobStatistics", storeTest(db, nil, testStoreBatchSpecWorkspaceExecutionJobStatistics))
 		t.Run("BatchSpecResolutionJobs", storeTest(db, nil, testStoreBatchSpecResolutionJobs))
+		t.Run("BatchSpecResolutionCacheEntries", storeTest(db, nil, testStoreBatchSpecResolutionCacheEntries))
 
 		for name, key := range map[string]encryption.Key{
 			"no key":   nil,
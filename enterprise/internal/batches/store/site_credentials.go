@@ -284,6 +284,42 @@ func (s *Store) updateSiteCredentialQuery(c *btypes.SiteCredential) *sqlf.Query
 	)
 }
 
+// MarkSiteCredentialChecked records the result of checking the given site
+// credential against its code host now. A nil failure message records a
+// successful check.
+func (s *Store) MarkSiteCredentialChecked(ctx context.Context, c *btypes.SiteCredential, failureMessage *string) (err error) {
+	ctx, endObservation := s.operations.markSiteCredentialChecked.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("ID", int(c.ID)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	checkedAt := s.now()
+	res, err := s.ExecResult(ctx, sqlf.Sprintf(markSiteCredentialCheckedQueryFmtstr, checkedAt, failureMessage, c.ID))
+	if err != nil {
+		return err
+	}
+	if rows, err := res.RowsAffected(); err != nil {
+		return err
+	} else if rows == 0 {
+		return ErrNoResults
+	}
+
+	c.CheckedAt = &checkedAt
+	c.CheckFailureMessage = failureMessage
+	return nil
+}
+
+const markSiteCredentialCheckedQueryFmtstr = `
+-- source: enterprise/internal/batches/store/site_credentials.go:MarkSiteCredentialChecked
+UPDATE
+	batch_changes_site_credentials
+SET
+	checked_at = %s,
+	check_failure_message = %s
+WHERE
+	id = %s
+`
+
 var siteCredentialColumns = []*sqlf.Query{
 	sqlf.Sprintf("id"),
 	sqlf.Sprintf("external_service_type"),
@@ -292,6 +328,8 @@ var siteCredentialColumns = []*sqlf.Query{
 	sqlf.Sprintf("encryption_key_id"),
 	sqlf.Sprintf("created_at"),
 	sqlf.Sprintf("updated_at"),
+	sqlf.Sprintf("checked_at"),
+	sqlf.Sprintf("check_failure_message"),
 }
 
 func scanSiteCredential(c *btypes.SiteCredential, sc dbutil.Scanner) error {
@@ -303,5 +341,7 @@ func scanSiteCredential(c *btypes.SiteCredential, sc dbutil.Scanner) error {
 		&c.EncryptionKeyID,
 		&dbutil.NullTime{Time: &c.CreatedAt},
 		&dbutil.NullTime{Time: &c.UpdatedAt},
+		&c.CheckedAt,
+		&c.CheckFailureMessage,
 	)
 }
@@ -185,6 +185,38 @@ func testStoreSiteCredentials(t *testing.T, ctx context.Context, s *Store, clock
 		})
 	})
 
+	t.Run("MarkChecked", func(t *testing.T) {
+		t.Run("Found", func(t *testing.T) {
+			failure := "credential is missing the required scopes: repo"
+			for _, cred := range credentials {
+				if err := s.MarkSiteCredentialChecked(ctx, cred, &failure); err != nil {
+					t.Fatal(err)
+				}
+				if cred.CheckedAt == nil || !cred.CheckedAt.Equal(clock.Now()) {
+					t.Errorf("unexpected checked time: %v", cred.CheckedAt)
+				}
+
+				if have, err := s.GetSiteCredential(ctx, GetSiteCredentialOpts{
+					ID: cred.ID,
+				}); err != nil {
+					t.Errorf("error retrieving credential: %+v", err)
+				} else if diff := cmp.Diff(have, cred, diffOpts...); diff != "" {
+					t.Errorf("unexpected difference in credentials (-have +want):\n%s", diff)
+				}
+			}
+		})
+		t.Run("NotFound", func(t *testing.T) {
+			cred := &btypes.SiteCredential{
+				ID: 0xdeadbeef,
+			}
+			if err := s.MarkSiteCredentialChecked(ctx, cred, nil); err == nil {
+				t.Errorf("unexpected nil error")
+			} else if err != ErrNoResults {
+				t.Errorf("unexpected error: have=%v want=%v", err, ErrNoResults)
+			}
+		})
+	})
+
 	t.Run("Delete", func(t *testing.T) {
 		t.Run("ByID", func(t *testing.T) {
 			for _, cred := range credentials {
@@ -180,10 +180,11 @@ type operations struct {
 	listBatchSpecs          *observation.Operation
 	deleteExpiredBatchSpecs *observation.Operation
 
-	getBulkOperation        *observation.Operation
-	listBulkOperations      *observation.Operation
-	countBulkOperations     *observation.Operation
-	listBulkOperationErrors *observation.Operation
+	getBulkOperation         *observation.Operation
+	listBulkOperations       *observation.Operation
+	countBulkOperations      *observation.Operation
+	listBulkOperationErrors  *observation.Operation
+	listBulkOperationResults *observation.Operation
 
 	getChangesetEvent     *observation.Operation
 	listChangesetEvents   *observation.Operation
@@ -202,7 +203,9 @@ type operations struct {
 	deleteExpiredChangesetSpecs              *observation.Operation
 	getRewirerMappings                       *observation.Operation
 	listChangesetSpecsWithConflictingHeadRef *observation.Operation
+	listIdenticalChangesetSpecs              *observation.Operation
 	deleteChangesetSpecs                     *observation.Operation
+	computeDiffStats                         *observation.Operation
 
 	createChangeset                   *observation.Operation
 	deleteChangeset                   *observation.Operation
@@ -218,6 +221,9 @@ type operations struct {
 	getChangesetExternalIDs           *observation.Operation
 	cancelQueuedBatchChangeChangesets *observation.Operation
 	enqueueChangesetsToClose          *observation.Operation
+	listChangesetsToRebase            *observation.Operation
+	enqueueChangesetToRebase          *observation.Operation
+	archiveStaleChangesets            *observation.Operation
 	getChangesetsStats                *observation.Operation
 	getRepoChangesetsStats            *observation.Operation
 	enqueueNextScheduledChangeset     *observation.Operation
@@ -226,11 +232,12 @@ type operations struct {
 	listCodeHosts         *observation.Operation
 	getExternalServiceIDs *observation.Operation
 
-	createSiteCredential *observation.Operation
-	deleteSiteCredential *observation.Operation
-	getSiteCredential    *observation.Operation
-	listSiteCredentials  *observation.Operation
-	updateSiteCredential *observation.Operation
+	createSiteCredential      *observation.Operation
+	deleteSiteCredential      *observation.Operation
+	getSiteCredential         *observation.Operation
+	listSiteCredentials       *observation.Operation
+	markSiteCredentialChecked *observation.Operation
+	updateSiteCredential      *observation.Operation
 
 	createBatchSpecWorkspace       *observation.Operation
 	getBatchSpecWorkspace          *observation.Operation
@@ -242,10 +249,17 @@ type operations struct {
 	listBatchSpecWorkspaceExecutionJobs   *observation.Operation
 	cancelBatchSpecWorkspaceExecutionJobs *observation.Operation
 
+	batchSpecWorkspaceExecutionJobDequeueLatencyPercentiles *observation.Operation
+	listBatchSpecWorkspaceExecutionImageStatistics          *observation.Operation
+
 	createBatchSpecResolutionJob *observation.Operation
 	getBatchSpecResolutionJob    *observation.Operation
 	listBatchSpecResolutionJobs  *observation.Operation
 
+	listBatchSpecResolutionCacheEntries          *observation.Operation
+	replaceBatchSpecResolutionCacheEntries       *observation.Operation
+	deleteExpiredBatchSpecResolutionCacheEntries *observation.Operation
+
 	setBatchSpecWorkspaceExecutionJobAccessToken   *observation.Operation
 	resetBatchSpecWorkspaceExecutionJobAccessToken *observation.Operation
 }
@@ -304,10 +318,11 @@ func newOperations(observationContext *observation.Context) *operations {
 			listBatchSpecs:          op("ListBatchSpecs"),
 			deleteExpiredBatchSpecs: op("DeleteExpiredBatchSpecs"),
 
-			getBulkOperation:        op("GetBulkOperation"),
-			listBulkOperations:      op("ListBulkOperations"),
-			countBulkOperations:     op("CountBulkOperations"),
-			listBulkOperationErrors: op("ListBulkOperationErrors"),
+			getBulkOperation:         op("GetBulkOperation"),
+			listBulkOperations:       op("ListBulkOperations"),
+			countBulkOperations:      op("CountBulkOperations"),
+			listBulkOperationErrors:  op("ListBulkOperationErrors"),
+			listBulkOperationResults: op("ListBulkOperationResults"),
 
 			getChangesetEvent:     op("GetChangesetEvent"),
 			listChangesetEvents:   op("ListChangesetEvents"),
@@ -325,8 +340,10 @@ func newOperations(observationContext *observation.Context) *operations {
 			listChangesetSpecs:                       op("ListChangesetSpecs"),
 			deleteExpiredChangesetSpecs:              op("DeleteExpiredChangesetSpecs"),
 			deleteChangesetSpecs:                     op("DeleteChangesetSpecs"),
+			computeDiffStats:                         op("ComputeDiffStats"),
 			getRewirerMappings:                       op("GetRewirerMappings"),
 			listChangesetSpecsWithConflictingHeadRef: op("ListChangesetSpecsWithConflictingHeadRef"),
+			listIdenticalChangesetSpecs:              op("ListIdenticalChangesetSpecs"),
 
 			createChangeset:                   op("CreateChangeset"),
 			deleteChangeset:                   op("DeleteChangeset"),
@@ -342,6 +359,9 @@ func newOperations(observationContext *observation.Context) *operations {
 			getChangesetExternalIDs:           op("GetChangesetExternalIDs"),
 			cancelQueuedBatchChangeChangesets: op("CancelQueuedBatchChangeChangesets"),
 			enqueueChangesetsToClose:          op("EnqueueChangesetsToClose"),
+			listChangesetsToRebase:            op("ListChangesetsToRebase"),
+			enqueueChangesetToRebase:          op("EnqueueChangesetToRebase"),
+			archiveStaleChangesets:            op("ArchiveStaleChangesets"),
 			getChangesetsStats:                op("GetChangesetsStats"),
 			getRepoChangesetsStats:            op("GetRepoChangesetsStats"),
 			enqueueNextScheduledChangeset:     op("EnqueueNextScheduledChangeset"),
@@ -350,11 +370,12 @@ func newOperations(observationContext *observation.Context) *operations {
 			listCodeHosts:         op("ListCodeHosts"),
 			getExternalServiceIDs: op("GetExternalServiceIDs"),
 
-			createSiteCredential: op("CreateSiteCredential"),
-			deleteSiteCredential: op("DeleteSiteCredential"),
-			getSiteCredential:    op("GetSiteCredential"),
-			listSiteCredentials:  op("ListSiteCredentials"),
-			updateSiteCredential: op("UpdateSiteCredential"),
+			createSiteCredential:      op("CreateSiteCredential"),
+			deleteSiteCredential:      op("DeleteSiteCredential"),
+			getSiteCredential:         op("GetSiteCredential"),
+			listSiteCredentials:       op("ListSiteCredentials"),
+			markSiteCredentialChecked: op("MarkSiteCredentialChecked"),
+			updateSiteCredential:      op("UpdateSiteCredential"),
 
 			createBatchSpecWorkspace:       op("CreateBatchSpecWorkspace"),
 			getBatchSpecWorkspace:          op("GetBatchSpecWorkspace"),
@@ -366,10 +387,17 @@ func newOperations(observationContext *observation.Context) *operations {
 			listBatchSpecWorkspaceExecutionJobs:   op("ListBatchSpecWorkspaceExecutionJobs"),
 			cancelBatchSpecWorkspaceExecutionJobs: op("CancelBatchSpecWorkspaceExecutionJobs"),
 
+			batchSpecWorkspaceExecutionJobDequeueLatencyPercentiles: op("BatchSpecWorkspaceExecutionJobDequeueLatencyPercentiles"),
+			listBatchSpecWorkspaceExecutionImageStatistics:          op("ListBatchSpecWorkspaceExecutionImageStatistics"),
+
 			createBatchSpecResolutionJob: op("CreateBatchSpecResolutionJob"),
 			getBatchSpecResolutionJob:    op("GetBatchSpecResolutionJob"),
 			listBatchSpecResolutionJobs:  op("ListBatchSpecResolutionJobs"),
 
+			listBatchSpecResolutionCacheEntries:          op("ListBatchSpecResolutionCacheEntries"),
+			replaceBatchSpecResolutionCacheEntries:       op("ReplaceBatchSpecResolutionCacheEntries"),
+			deleteExpiredBatchSpecResolutionCacheEntries: op("DeleteExpiredBatchSpecResolutionCacheEntries"),
+
 			setBatchSpecWorkspaceExecutionJobAccessToken:   op("SetBatchSpecWorkspaceExecutionJobAccessToken"),
 			resetBatchSpecWorkspaceExecutionJobAccessToken: op("ResetBatchSpecWorkspaceExecutionJobAccessToken"),
 		}
@@ -68,6 +68,7 @@ func CreateBatchSpecWorkspaceExecutionJob(ctx context.Context, s createBatchSpec
 			if err := inserter.Insert(
 				ctx,
 				job.BatchSpecWorkspaceID,
+				nullInt32Column(job.UserID),
 				job.CreatedAt,
 				job.UpdatedAt,
 			); err != nil {
@@ -82,12 +83,13 @@ func CreateBatchSpecWorkspaceExecutionJob(ctx context.Context, s createBatchSpec
 		ctx,
 		s.Handle().DB(),
 		"batch_spec_workspace_execution_jobs",
-		[]string{"batch_spec_workspace_id", "created_at", "updated_at"},
+		[]string{"batch_spec_workspace_id", "user_id", "created_at", "updated_at"},
 		"",
 		[]string{
 			"batch_spec_workspace_execution_jobs.id",
 			"batch_spec_workspace_execution_jobs.batch_spec_workspace_id",
 			"batch_spec_workspace_execution_jobs.access_token_id",
+			"batch_spec_workspace_execution_jobs.user_id",
 			"batch_spec_workspace_execution_jobs.state",
 			"batch_spec_workspace_execution_jobs.failure_message",
 			"batch_spec_workspace_execution_jobs.started_at",
@@ -110,6 +112,13 @@ func CreateBatchSpecWorkspaceExecutionJob(ctx context.Context, s createBatchSpec
 	)
 }
 
+func nullInt32Column(n int32) *int32 {
+	if n == 0 {
+		return nil
+	}
+	return &n
+}
+
 func nullTimeColumn(t time.Time) *time.Time {
 	if t.IsZero() {
 		return nil
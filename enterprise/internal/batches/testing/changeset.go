@@ -4,6 +4,7 @@ import (
 	"context"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/sourcegraph/go-diff/diff"
@@ -27,6 +28,7 @@ type TestChangesetOpts struct {
 	ExternalState       btypes.ChangesetExternalState
 	ExternalReviewState btypes.ChangesetReviewState
 	ExternalCheckState  btypes.ChangesetCheckState
+	ExternalUpdatedAt   time.Time
 
 	DiffStatAdded   int32
 	DiffStatChanged int32
@@ -48,6 +50,9 @@ type TestChangesetOpts struct {
 	IsArchived bool
 	Archive    bool
 
+	Rebasing    bool
+	RebasedOnto string
+
 	Metadata interface{}
 }
 
@@ -88,6 +93,7 @@ func BuildChangeset(opts TestChangesetOpts) *btypes.Changeset {
 		ExternalState:       opts.ExternalState,
 		ExternalReviewState: opts.ExternalReviewState,
 		ExternalCheckState:  opts.ExternalCheckState,
+		ExternalUpdatedAt:   opts.ExternalUpdatedAt,
 
 		PublicationState:   opts.PublicationState,
 		UiPublicationState: opts.UiPublicationState,
@@ -96,6 +102,9 @@ func BuildChangeset(opts TestChangesetOpts) *btypes.Changeset {
 
 		Closing: opts.Closing,
 
+		Rebasing:    opts.Rebasing,
+		RebasedOnto: opts.RebasedOnto,
+
 		ReconcilerState: opts.ReconcilerState,
 		NumFailures:     opts.NumFailures,
 		NumResets:       opts.NumResets,
@@ -28,6 +28,15 @@ type BatchChange struct {
 
 	ClosedAt time.Time
 
+	// AutoRebase is set if the changesets of the batch change should be
+	// rebased onto their base branch whenever it advances.
+	AutoRebase bool
+
+	// AutoArchiveAfterDays is the number of days after which the merged and
+	// closed changesets of the batch change are archived. If it is 0,
+	// changesets are not archived automatically.
+	AutoArchiveAfterDays int
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -42,6 +42,10 @@ type BatchSpec struct {
 	AllowUnsupported bool
 	AllowIgnored     bool
 
+	// NoCache is true when the workspaces of the BatchSpec should be resolved
+	// without consulting the workspace resolution cache.
+	NoCache bool
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
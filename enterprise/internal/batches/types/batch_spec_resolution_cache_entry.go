@@ -0,0 +1,32 @@
+package types
+
+import (
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// BatchSpecResolutionCacheTTL specifies how long the repositories resolved for
+// a repositoriesMatchingQuery clause are reused, as long as the default
+// branches of the repositories didn't change in the meantime.
+const BatchSpecResolutionCacheTTL = 1 * time.Hour
+
+// BatchSpecResolutionCacheEntry is a repository that matched the given
+// repositoriesMatchingQuery query when it was last resolved.
+type BatchSpecResolutionCacheEntry struct {
+	ID int64
+
+	Query  string
+	RepoID api.RepoID
+	// Commit is the commit of the default branch of the repository at the
+	// time the query was resolved.
+	Commit      string
+	FileMatches []string
+
+	CreatedAt time.Time
+}
+
+// ExpiresAt returns the time after which the entry is no longer used.
+func (e *BatchSpecResolutionCacheEntry) ExpiresAt() time.Time {
+	return e.CreatedAt.Add(BatchSpecResolutionCacheTTL)
+}
@@ -1,12 +1,20 @@
 package types
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 	"time"
 
+	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
 )
 
+// SrcExecStepKey is the key of the executor step that runs `src batch exec`
+// for a workspace.
+const SrcExecStepKey = "batch-exec"
+
 // BatchSpecWorkspaceExecutionJobState defines the possible states of a changeset job.
 type BatchSpecWorkspaceExecutionJobState string
 
@@ -42,6 +50,11 @@ type BatchSpecWorkspaceExecutionJob struct {
 	BatchSpecWorkspaceID int64
 	AccessTokenID        int64
 
+	// UserID is the user who started the execution. The job is executed with
+	// their permissions, which, for batch specs in an organization namespace,
+	// needn't be the user who created the batch spec.
+	UserID int32
+
 	State           BatchSpecWorkspaceExecutionJobState
 	FailureMessage  *string
 	StartedAt       time.Time
@@ -61,3 +74,74 @@ type BatchSpecWorkspaceExecutionJob struct {
 }
 
 func (j *BatchSpecWorkspaceExecutionJob) RecordID() int { return int(j.ID) }
+
+// SrcExecLogEntry returns the execution log entry of the step that runs `src
+// batch exec`, if the step has started. Jobs dequeued before the step had a
+// key store the entry under the index of the step.
+func (j *BatchSpecWorkspaceExecutionJob) SrcExecLogEntry() (workerutil.ExecutionLogEntry, bool) {
+	return FindSrcExecLogEntry(j.ExecutionLogs)
+}
+
+// FindSrcExecLogEntry returns the execution log entry of the step that runs
+// `src batch exec` from the given logs.
+func FindSrcExecLogEntry(logs []workerutil.ExecutionLogEntry) (workerutil.ExecutionLogEntry, bool) {
+	keys := []string{
+		apiclient.CliStep{Key: SrcExecStepKey}.LogKey(0),
+		apiclient.CliStep{}.LogKey(0),
+	}
+	for _, key := range keys {
+		for _, entry := range logs {
+			if entry.Key == key {
+				return entry, true
+			}
+		}
+	}
+
+	return workerutil.ExecutionLogEntry{}, false
+}
+
+// StepLogKey returns the key of the execution log entry of the batch spec
+// step with the given index, starting at 0.
+func StepLogKey(index int) string {
+	return fmt.Sprintf("%s.step.%d", apiclient.CliStep{Key: SrcExecStepKey}.LogKey(0), index)
+}
+
+// StepLogEntries returns one execution log entry per step of the workspace
+// that has started running, derived from the output of `src batch exec`.
+// Each entry has its own key, start time and output, and exit code and
+// duration once the step has finished.
+func (j *BatchSpecWorkspaceExecutionJob) StepLogEntries(steps []batcheslib.Step) []workerutil.ExecutionLogEntry {
+	entry, ok := j.SrcExecLogEntry()
+	if !ok {
+		return []workerutil.ExecutionLogEntry{}
+	}
+
+	// The step numbers in the logs start at 1.
+	infos := ParseLogLines(ParseJSONLogsFromOutput(entry.Out))
+	indexes := make([]int, 0, len(infos))
+	for step, info := range infos {
+		if info.Skipped || info.StartedAt.IsZero() || step < 1 || step > len(steps) {
+			continue
+		}
+		indexes = append(indexes, step-1)
+	}
+	sort.Ints(indexes)
+
+	entries := make([]workerutil.ExecutionLogEntry, 0, len(indexes))
+	for _, index := range indexes {
+		info := infos[index+1]
+		e := workerutil.ExecutionLogEntry{
+			Key:       StepLogKey(index),
+			Command:   []string{steps[index].Run},
+			StartTime: info.StartedAt,
+			ExitCode:  info.ExitCode,
+			Out:       strings.Join(info.OutputLines, "\n"),
+		}
+		if !info.FinishedAt.IsZero() {
+			durationMs := int(info.FinishedAt.Sub(info.StartedAt) / time.Millisecond)
+			e.DurationMs = &durationMs
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
@@ -0,0 +1,98 @@
+package types
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
+)
+
+func TestBatchSpecWorkspaceExecutionJob_StepLogEntries(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2021, 11, 1, 12, 0, 0, 0, time.UTC)
+	events := []*batcheslib.LogEvent{
+		{
+			Operation: batcheslib.LogEventOperationTaskPreparingStep,
+			Status:    batcheslib.LogEventStatusStarted,
+			Timestamp: start,
+			Metadata:  &batcheslib.TaskPreparingStepMetadata{Step: 1},
+		},
+		{
+			Operation: batcheslib.LogEventOperationTaskStep,
+			Status:    batcheslib.LogEventStatusProgress,
+			Timestamp: start.Add(time.Second),
+			Metadata:  &batcheslib.TaskStepMetadata{Step: 1, Out: "stdout: hello\nstderr: world\n"},
+		},
+		{
+			Operation: batcheslib.LogEventOperationTaskStep,
+			Status:    batcheslib.LogEventStatusSuccess,
+			Timestamp: start.Add(2 * time.Second),
+			Metadata:  &batcheslib.TaskStepMetadata{Step: 1, ExitCode: 0},
+		},
+		{
+			Operation: batcheslib.LogEventOperationTaskStepSkipped,
+			Status:    batcheslib.LogEventStatusProgress,
+			Timestamp: start.Add(3 * time.Second),
+			Metadata:  &batcheslib.TaskStepSkippedMetadata{Step: 2},
+		},
+		{
+			Operation: batcheslib.LogEventOperationTaskPreparingStep,
+			Status:    batcheslib.LogEventStatusStarted,
+			Timestamp: start.Add(4 * time.Second),
+			Metadata:  &batcheslib.TaskPreparingStepMetadata{Step: 3},
+		},
+	}
+
+	var out []string
+	for _, e := range events {
+		line, err := json.Marshal(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, stdoutLinePrefix+string(line))
+	}
+
+	job := &BatchSpecWorkspaceExecutionJob{
+		ExecutionLogs: []workerutil.ExecutionLogEntry{
+			{Key: "setup.git.init"},
+			{Key: apiclient.CliStep{Key: SrcExecStepKey}.LogKey(0), Out: strings.Join(out, "\n")},
+		},
+	}
+	steps := []batcheslib.Step{
+		{Run: "echo hello"},
+		{Run: "echo skipped"},
+		{Run: "sleep 100"},
+	}
+
+	zero := 0
+	duration := 2000
+	want := []workerutil.ExecutionLogEntry{
+		{
+			Key:        "step.src.batch-exec.step.0",
+			Command:    []string{"echo hello"},
+			StartTime:  start,
+			ExitCode:   &zero,
+			Out:        "stdout: hello\nstderr: world",
+			DurationMs: &duration,
+		},
+		{
+			Key:       "step.src.batch-exec.step.2",
+			Command:   []string{"sleep 100"},
+			StartTime: start.Add(4 * time.Second),
+		},
+	}
+	if diff := cmp.Diff(want, job.StepLogEntries(steps)); diff != "" {
+		t.Errorf("unexpected entries (-want +got):\n%s", diff)
+	}
+
+	if entries := (&BatchSpecWorkspaceExecutionJob{}).StepLogEntries(steps); len(entries) != 0 {
+		t.Errorf("unexpected entries for job without logs: %+v", entries)
+	}
+}
@@ -47,3 +47,12 @@ type BulkOperationError struct {
 	ChangesetID int64
 	Error       string
 }
+
+// BulkOperationResult represents the outcome of a bulk operation on a single
+// changeset.
+type BulkOperationResult struct {
+	ChangesetID int64
+	State       ChangesetJobState
+	Error       string
+	FinishedAt  time.Time
+}
@@ -11,6 +11,8 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
@@ -284,6 +286,15 @@ type Changeset struct {
 	// Closing is set to true (along with the ReocncilerState) when the
 	// reconciler should close the changeset.
 	Closing bool
+
+	// Rebasing is set to true (along with the ReconcilerState) when the
+	// reconciler should rebase the changeset onto the current head of its base
+	// branch.
+	Rebasing bool
+	// RebasedOnto is the base commit of the pushed changeset commit, if the
+	// changeset has been rebased since its current spec was pushed. If empty,
+	// the commit is based on the BaseRev of the current spec.
+	RebasedOnto string
 }
 
 // RecordID is needed to implement the workerutil.Record interface.
@@ -379,6 +390,18 @@ func (c *Changeset) SetMetadata(meta interface{}) error {
 		c.ExternalServiceType = extsvc.TypeGitLab
 		c.ExternalBranch = git.EnsureRefPrefix(pr.SourceBranch)
 		c.ExternalUpdatedAt = pr.UpdatedAt.Time
+	case *azuredevops.PullRequest:
+		c.Metadata = pr
+		c.ExternalID = strconv.FormatInt(pr.ID, 10)
+		c.ExternalServiceType = extsvc.TypeAzureDevOps
+		c.ExternalBranch = git.EnsureRefPrefix(pr.SourceRefName)
+		c.ExternalUpdatedAt = pr.UpdatedAt()
+	case *bitbucketcloud.PullRequest:
+		c.Metadata = pr
+		c.ExternalID = strconv.FormatInt(pr.ID, 10)
+		c.ExternalServiceType = extsvc.TypeBitbucketCloud
+		c.ExternalBranch = git.EnsureRefPrefix(pr.Source.Branch.Name)
+		c.ExternalUpdatedAt = pr.UpdatedOn
 	default:
 		return errors.New("unknown changeset type")
 	}
@@ -404,6 +427,10 @@ func (c *Changeset) Title() (string, error) {
 		return m.Title, nil
 	case *gitlab.MergeRequest:
 		return m.Title, nil
+	case *azuredevops.PullRequest:
+		return m.Title, nil
+	case *bitbucketcloud.PullRequest:
+		return m.Title, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -421,6 +448,10 @@ func (c *Changeset) AuthorName() (string, error) {
 		return m.Author.User.Name, nil
 	case *gitlab.MergeRequest:
 		return m.Author.Username, nil
+	case *azuredevops.PullRequest:
+		return m.CreatedBy.DisplayName, nil
+	case *bitbucketcloud.PullRequest:
+		return m.Author.Nickname, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -444,6 +475,16 @@ func (c *Changeset) AuthorEmail() (string, error) {
 		return m.Author.User.EmailAddress, nil
 	case *gitlab.MergeRequest:
 		return m.Author.Email, nil
+	case *azuredevops.PullRequest:
+		// The unique name is the sign in name of the author, which is only
+		// an email address for Azure Active Directory and Microsoft accounts.
+		if !strings.Contains(m.CreatedBy.UniqueName, "@") {
+			return "", nil
+		}
+		return m.CreatedBy.UniqueName, nil
+	case *bitbucketcloud.PullRequest:
+		// Bitbucket Cloud doesn't expose the email addresses of users.
+		return "", nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -460,6 +501,10 @@ func (c *Changeset) ExternalCreatedAt() time.Time {
 		return unixMilliToTime(int64(m.CreatedDate))
 	case *gitlab.MergeRequest:
 		return m.CreatedAt.Time
+	case *azuredevops.PullRequest:
+		return m.CreationDate
+	case *bitbucketcloud.PullRequest:
+		return m.CreatedOn
 	default:
 		return time.Time{}
 	}
@@ -474,6 +519,10 @@ func (c *Changeset) Body() (string, error) {
 		return m.Description, nil
 	case *gitlab.MergeRequest:
 		return m.Description, nil
+	case *azuredevops.PullRequest:
+		return m.Description, nil
+	case *bitbucketcloud.PullRequest:
+		return m.Description, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -511,6 +560,10 @@ func (c *Changeset) URL() (s string, err error) {
 		return selfLink.Href, nil
 	case *gitlab.MergeRequest:
 		return m.WebURL, nil
+	case *azuredevops.PullRequest:
+		return m.WebURL(), nil
+	case *bitbucketcloud.PullRequest:
+		return m.Links.HTML.Href, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -658,6 +711,13 @@ func (c *Changeset) HeadRefOid() (string, error) {
 		return "", nil
 	case *gitlab.MergeRequest:
 		return m.DiffRefs.HeadSHA, nil
+	case *azuredevops.PullRequest:
+		if m.LastMergeSourceCommit == nil {
+			return "", nil
+		}
+		return m.LastMergeSourceCommit.CommitID, nil
+	case *bitbucketcloud.PullRequest:
+		return m.Source.Commit.Hash, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -673,6 +733,10 @@ func (c *Changeset) HeadRef() (string, error) {
 		return m.FromRef.ID, nil
 	case *gitlab.MergeRequest:
 		return "refs/heads/" + m.SourceBranch, nil
+	case *azuredevops.PullRequest:
+		return m.SourceRefName, nil
+	case *bitbucketcloud.PullRequest:
+		return "refs/heads/" + m.Source.Branch.Name, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -689,6 +753,13 @@ func (c *Changeset) BaseRefOid() (string, error) {
 		return "", nil
 	case *gitlab.MergeRequest:
 		return m.DiffRefs.BaseSHA, nil
+	case *azuredevops.PullRequest:
+		if m.LastMergeTargetCommit == nil {
+			return "", nil
+		}
+		return m.LastMergeTargetCommit.CommitID, nil
+	case *bitbucketcloud.PullRequest:
+		return m.Destination.Commit.Hash, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -704,6 +775,10 @@ func (c *Changeset) BaseRef() (string, error) {
 		return m.ToRef.ID, nil
 	case *gitlab.MergeRequest:
 		return "refs/heads/" + m.TargetBranch, nil
+	case *azuredevops.PullRequest:
+		return m.TargetRefName, nil
+	case *bitbucketcloud.PullRequest:
+		return "refs/heads/" + m.Destination.Branch.Name, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -964,6 +1039,30 @@ func ChangesetEventKindFor(e interface{}) (ChangesetEventKind, error) {
 		return ChangesetEventKind("bitbucketserver:participant_status:" + strings.ToLower(string(e.Action))), nil
 	case *bitbucketserver.CommitStatus:
 		return ChangesetEventKindBitbucketServerCommitStatus, nil
+	case *bitbucketcloud.PullRequestApprovedEvent:
+		return ChangesetEventKindBitbucketCloudApproved, nil
+	case *bitbucketcloud.PullRequestUnapprovedEvent:
+		return ChangesetEventKindBitbucketCloudUnapproved, nil
+	case *bitbucketcloud.PullRequestChangesRequestCreatedEvent:
+		return ChangesetEventKindBitbucketCloudChangesRequestCreated, nil
+	case *bitbucketcloud.PullRequestChangesRequestRemovedEvent:
+		return ChangesetEventKindBitbucketCloudChangesRequestRemoved, nil
+	case *bitbucketcloud.PullRequestCommentEvent:
+		return ChangesetEventKindBitbucketCloudCommented, nil
+	case *bitbucketcloud.PullRequestUpdatedEvent:
+		return ChangesetEventKindBitbucketCloudUpdated, nil
+	case *bitbucketcloud.PullRequestFulfilledEvent:
+		return ChangesetEventKindBitbucketCloudMerged, nil
+	case *bitbucketcloud.PullRequestRejectedEvent:
+		return ChangesetEventKindBitbucketCloudDeclined, nil
+	case *azuredevops.PullRequestUpdatedEvent:
+		return ChangesetEventKindAzureDevOpsUpdated, nil
+	case *azuredevops.PullRequestMergedEvent:
+		return ChangesetEventKindAzureDevOpsMerged, nil
+	case *azuredevops.PullRequestAbandonedEvent:
+		return ChangesetEventKindAzureDevOpsAbandoned, nil
+	case *azuredevops.PullRequestCommentEvent:
+		return ChangesetEventKindAzureDevOpsCommented, nil
 	case *gitlab.Pipeline:
 		return ChangesetEventKindGitLabPipeline, nil
 	case *gitlab.ReviewApprovedEvent:
@@ -990,6 +1089,36 @@ func ChangesetEventKindFor(e interface{}) (ChangesetEventKind, error) {
 // ChangesetEventKind.
 func NewChangesetEventMetadata(k ChangesetEventKind) (interface{}, error) {
 	switch {
+	case strings.HasPrefix(string(k), "azuredevops"):
+		switch k {
+		case ChangesetEventKindAzureDevOpsUpdated:
+			return new(azuredevops.PullRequestUpdatedEvent), nil
+		case ChangesetEventKindAzureDevOpsMerged:
+			return new(azuredevops.PullRequestMergedEvent), nil
+		case ChangesetEventKindAzureDevOpsAbandoned:
+			return new(azuredevops.PullRequestAbandonedEvent), nil
+		case ChangesetEventKindAzureDevOpsCommented:
+			return new(azuredevops.PullRequestCommentEvent), nil
+		}
+	case strings.HasPrefix(string(k), "bitbucketcloud"):
+		switch k {
+		case ChangesetEventKindBitbucketCloudApproved:
+			return new(bitbucketcloud.PullRequestApprovedEvent), nil
+		case ChangesetEventKindBitbucketCloudUnapproved:
+			return new(bitbucketcloud.PullRequestUnapprovedEvent), nil
+		case ChangesetEventKindBitbucketCloudChangesRequestCreated:
+			return new(bitbucketcloud.PullRequestChangesRequestCreatedEvent), nil
+		case ChangesetEventKindBitbucketCloudChangesRequestRemoved:
+			return new(bitbucketcloud.PullRequestChangesRequestRemovedEvent), nil
+		case ChangesetEventKindBitbucketCloudCommented:
+			return new(bitbucketcloud.PullRequestCommentEvent), nil
+		case ChangesetEventKindBitbucketCloudUpdated:
+			return new(bitbucketcloud.PullRequestUpdatedEvent), nil
+		case ChangesetEventKindBitbucketCloudMerged:
+			return new(bitbucketcloud.PullRequestFulfilledEvent), nil
+		case ChangesetEventKindBitbucketCloudDeclined:
+			return new(bitbucketcloud.PullRequestRejectedEvent), nil
+		}
 	case strings.HasPrefix(string(k), "bitbucketserver"):
 		switch k {
 		case ChangesetEventKindBitbucketServerCommitStatus:
@@ -9,6 +9,8 @@ import (
 	"github.com/cockroachdb/errors"
 	"github.com/inconshreveable/log15"
 
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
@@ -69,6 +71,20 @@ const (
 	// clearly convey that it only occurs when a request for changes has been dismissed.
 	ChangesetEventKindBitbucketServerDismissed ChangesetEventKind = "bitbucketserver:participant_status:unapproved"
 
+	ChangesetEventKindBitbucketCloudApproved              ChangesetEventKind = "bitbucketcloud:approved"
+	ChangesetEventKindBitbucketCloudUnapproved            ChangesetEventKind = "bitbucketcloud:unapproved"
+	ChangesetEventKindBitbucketCloudChangesRequestCreated ChangesetEventKind = "bitbucketcloud:changes_request_created"
+	ChangesetEventKindBitbucketCloudChangesRequestRemoved ChangesetEventKind = "bitbucketcloud:changes_request_removed"
+	ChangesetEventKindBitbucketCloudCommented             ChangesetEventKind = "bitbucketcloud:commented"
+	ChangesetEventKindBitbucketCloudUpdated               ChangesetEventKind = "bitbucketcloud:updated"
+	ChangesetEventKindBitbucketCloudMerged                ChangesetEventKind = "bitbucketcloud:merged"
+	ChangesetEventKindBitbucketCloudDeclined              ChangesetEventKind = "bitbucketcloud:declined"
+
+	ChangesetEventKindAzureDevOpsUpdated   ChangesetEventKind = "azuredevops:updated"
+	ChangesetEventKindAzureDevOpsMerged    ChangesetEventKind = "azuredevops:merged"
+	ChangesetEventKindAzureDevOpsAbandoned ChangesetEventKind = "azuredevops:abandoned"
+	ChangesetEventKindAzureDevOpsCommented ChangesetEventKind = "azuredevops:commented"
+
 	ChangesetEventKindGitLabApproved             ChangesetEventKind = "gitlab:approved"
 	ChangesetEventKindGitLabClosed               ChangesetEventKind = "gitlab:closed"
 	ChangesetEventKindGitLabMerged               ChangesetEventKind = "gitlab:merged"
@@ -115,6 +131,18 @@ func (e *ChangesetEvent) ReviewAuthor() string {
 	case *bitbucketserver.ParticipantStatusEvent:
 		return meta.User.Name
 
+	case *bitbucketcloud.PullRequestApprovedEvent:
+		return meta.Approval.User.Nickname
+
+	case *bitbucketcloud.PullRequestUnapprovedEvent:
+		return meta.Approval.User.Nickname
+
+	case *bitbucketcloud.PullRequestChangesRequestCreatedEvent:
+		return meta.ChangesRequest.User.Nickname
+
+	case *bitbucketcloud.PullRequestChangesRequestRemovedEvent:
+		return meta.ChangesRequest.User.Nickname
+
 	case *gitlab.ReviewApprovedEvent:
 		return meta.Author.Username
 
@@ -130,12 +158,14 @@ func (e *ChangesetEvent) ReviewAuthor() string {
 func (e *ChangesetEvent) ReviewState() (ChangesetReviewState, error) {
 	switch e.Kind {
 	case ChangesetEventKindBitbucketServerApproved,
+		ChangesetEventKindBitbucketCloudApproved,
 		ChangesetEventKindGitLabApproved:
 		return ChangesetReviewStateApproved, nil
 
 	// BitbucketServer's "REVIEWED" activity is created when someone clicks
 	// the "Needs work" button in the UI, which is why we map it to "Changes Requested"
-	case ChangesetEventKindBitbucketServerReviewed:
+	case ChangesetEventKindBitbucketServerReviewed,
+		ChangesetEventKindBitbucketCloudChangesRequestCreated:
 		return ChangesetReviewStateChangesRequested, nil
 
 	case ChangesetEventKindGitHubReviewed:
@@ -155,6 +185,8 @@ func (e *ChangesetEvent) ReviewState() (ChangesetReviewState, error) {
 	case ChangesetEventKindGitHubReviewDismissed,
 		ChangesetEventKindBitbucketServerUnapproved,
 		ChangesetEventKindBitbucketServerDismissed,
+		ChangesetEventKindBitbucketCloudUnapproved,
+		ChangesetEventKindBitbucketCloudChangesRequestRemoved,
 		ChangesetEventKindGitLabUnapproved:
 		return ChangesetReviewStateDismissed, nil
 
@@ -221,6 +253,36 @@ func (e *ChangesetEvent) Timestamp() time.Time {
 		t = unixMilliToTime(int64(ev.CreatedDate))
 	case *bitbucketserver.CommitStatus:
 		t = unixMilliToTime(ev.Status.DateAdded)
+	case *bitbucketcloud.PullRequestApprovedEvent:
+		t = ev.Approval.Date
+	case *bitbucketcloud.PullRequestUnapprovedEvent:
+		t = ev.Approval.Date
+	case *bitbucketcloud.PullRequestChangesRequestCreatedEvent:
+		t = ev.ChangesRequest.Date
+	case *bitbucketcloud.PullRequestChangesRequestRemovedEvent:
+		t = ev.ChangesRequest.Date
+	case *bitbucketcloud.PullRequestCommentEvent:
+		t = ev.Comment.UpdatedOn
+	case *bitbucketcloud.PullRequestUpdatedEvent:
+		t = ev.PullRequest.UpdatedOn
+	case *bitbucketcloud.PullRequestFulfilledEvent:
+		t = ev.PullRequest.UpdatedOn
+	case *bitbucketcloud.PullRequestRejectedEvent:
+		t = ev.PullRequest.UpdatedOn
+	case *azuredevops.PullRequestUpdatedEvent:
+		t = ev.CreatedDate
+	case *azuredevops.PullRequestMergedEvent:
+		t = ev.PullRequest.ClosedDate
+		if t.IsZero() {
+			t = ev.CreatedDate
+		}
+	case *azuredevops.PullRequestAbandonedEvent:
+		t = ev.PullRequest.ClosedDate
+		if t.IsZero() {
+			t = ev.CreatedDate
+		}
+	case *azuredevops.PullRequestCommentEvent:
+		t = ev.Resource.Comment.LastUpdatedDate
 	case *gitlab.ReviewApprovedEvent:
 		t = ev.CreatedAt.Time
 	case *gitlab.ReviewUnapprovedEvent:
@@ -621,6 +683,56 @@ func (e *ChangesetEvent) Update(o *ChangesetEvent) error {
 		}
 		e.CheckRuns = o.CheckRuns
 
+	case *bitbucketcloud.PullRequestApprovedEvent:
+		o := o.Metadata.(*bitbucketcloud.PullRequestApprovedEvent)
+		// We always get the full event, so safe to replace it
+		*e = *o
+
+	case *bitbucketcloud.PullRequestUnapprovedEvent:
+		o := o.Metadata.(*bitbucketcloud.PullRequestUnapprovedEvent)
+		*e = *o
+
+	case *bitbucketcloud.PullRequestChangesRequestCreatedEvent:
+		o := o.Metadata.(*bitbucketcloud.PullRequestChangesRequestCreatedEvent)
+		*e = *o
+
+	case *bitbucketcloud.PullRequestChangesRequestRemovedEvent:
+		o := o.Metadata.(*bitbucketcloud.PullRequestChangesRequestRemovedEvent)
+		*e = *o
+
+	case *bitbucketcloud.PullRequestCommentEvent:
+		o := o.Metadata.(*bitbucketcloud.PullRequestCommentEvent)
+		*e = *o
+
+	case *bitbucketcloud.PullRequestUpdatedEvent:
+		o := o.Metadata.(*bitbucketcloud.PullRequestUpdatedEvent)
+		*e = *o
+
+	case *bitbucketcloud.PullRequestFulfilledEvent:
+		o := o.Metadata.(*bitbucketcloud.PullRequestFulfilledEvent)
+		*e = *o
+
+	case *bitbucketcloud.PullRequestRejectedEvent:
+		o := o.Metadata.(*bitbucketcloud.PullRequestRejectedEvent)
+		*e = *o
+
+	case *azuredevops.PullRequestUpdatedEvent:
+		o := o.Metadata.(*azuredevops.PullRequestUpdatedEvent)
+		// We always get the full event, so safe to replace it
+		*e = *o
+
+	case *azuredevops.PullRequestMergedEvent:
+		o := o.Metadata.(*azuredevops.PullRequestMergedEvent)
+		*e = *o
+
+	case *azuredevops.PullRequestAbandonedEvent:
+		o := o.Metadata.(*azuredevops.PullRequestAbandonedEvent)
+		*e = *o
+
+	case *azuredevops.PullRequestCommentEvent:
+		o := o.Metadata.(*azuredevops.PullRequestCommentEvent)
+		*e = *o
+
 	case *gitlab.ReviewApprovedEvent:
 		o := o.Metadata.(*gitlab.ReviewApprovedEvent)
 		if e.CreatedAt.IsZero() {
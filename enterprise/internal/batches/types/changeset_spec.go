@@ -1,16 +1,23 @@
 package types
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"io"
 	"strings"
 	"time"
 
+	"github.com/inconshreveable/log15"
 	"github.com/sourcegraph/go-diff/diff"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
 )
 
+// NewChangesetSpecFromRaw parses the given raw spec into a ChangesetSpec and
+// computes the diff stat of its diff. If the diff is invalid, an error is
+// returned.
 func NewChangesetSpecFromRaw(rawSpec string) (*ChangesetSpec, error) {
 	c := &ChangesetSpec{}
 
@@ -19,8 +26,9 @@ func NewChangesetSpecFromRaw(rawSpec string) (*ChangesetSpec, error) {
 	if err != nil {
 		return nil, err
 	}
+	c.DiffDigest = ComputeDiffDigest(c.Spec)
 
-	return c, c.computeDiffStat()
+	return c, c.ComputeDiffStat()
 }
 
 type ChangesetSpec struct {
@@ -32,6 +40,17 @@ type ChangesetSpec struct {
 	DiffStatAdded   int32
 	DiffStatChanged int32
 	DiffStatDeleted int32
+	// DiffStatPending is true if the diff stat fields above haven't been
+	// computed from the diff in Spec yet, which is the case for specs stored
+	// without a diff stat. In the database, this is represented by NULL diff
+	// stat columns.
+	DiffStatPending bool
+
+	// DiffDigest is the digest of the commits of the spec, as computed by
+	// ComputeDiffDigest. Two specs with the same digest push the same commits.
+	// It is empty for specs importing existing changesets, and for specs
+	// created before the digest was stored.
+	DiffDigest string
 
 	BatchSpecID int64
 	RepoID      api.RepoID
@@ -47,11 +66,12 @@ func (cs *ChangesetSpec) Clone() *ChangesetSpec {
 	return &cc
 }
 
-// computeDiffStat parses the Diff of the ChangesetSpecDescription and sets the
+// ComputeDiffStat parses the Diff of the ChangesetSpecDescription and sets the
 // diff stat fields that can be retrieved with DiffStat().
 // If the Diff is invalid or parsing failed, an error is returned.
-func (cs *ChangesetSpec) computeDiffStat() error {
+func (cs *ChangesetSpec) ComputeDiffStat() error {
 	if cs.Spec.IsImportingExisting() {
+		cs.SetDiffStat(diff.Stat{})
 		return nil
 	}
 
@@ -77,15 +97,29 @@ func (cs *ChangesetSpec) computeDiffStat() error {
 		stats.Changed += stat.Changed
 	}
 
-	cs.DiffStatAdded = stats.Added
-	cs.DiffStatDeleted = stats.Deleted
-	cs.DiffStatChanged = stats.Changed
+	cs.SetDiffStat(stats)
 
 	return nil
 }
 
-// DiffStat returns a *diff.Stat.
+// SetDiffStat sets the diff stat fields to the given stat and marks them as
+// computed.
+func (cs *ChangesetSpec) SetDiffStat(stat diff.Stat) {
+	cs.DiffStatAdded = stat.Added
+	cs.DiffStatChanged = stat.Changed
+	cs.DiffStatDeleted = stat.Deleted
+	cs.DiffStatPending = false
+}
+
+// DiffStat returns a *diff.Stat. If the diff stat hasn't been computed yet, it
+// is computed first. If the diff can't be parsed, an empty stat is returned.
 func (cs *ChangesetSpec) DiffStat() diff.Stat {
+	if cs.DiffStatPending {
+		if err := cs.ComputeDiffStat(); err != nil {
+			log15.Warn("failed to compute diff stat of changeset spec", "id", cs.ID, "err", err)
+		}
+	}
+
 	return diff.Stat{
 		Added:   cs.DiffStatAdded,
 		Deleted: cs.DiffStatDeleted,
@@ -93,6 +127,26 @@ func (cs *ChangesetSpec) DiffStat() diff.Stat {
 	}
 }
 
+// ComputeDiffDigest returns the SHA-256 digest of the commits of the given
+// spec: their diff, message and author. An empty string is returned for specs
+// importing existing changesets, which don't have commits.
+func ComputeDiffDigest(spec *batcheslib.ChangesetSpec) string {
+	if spec == nil || spec.IsImportingExisting() {
+		return ""
+	}
+
+	h := sha256.New()
+	for _, commit := range spec.Commits {
+		for _, field := range []string{commit.Diff, commit.Message, commit.AuthorName, commit.AuthorEmail} {
+			// Prefix each field with its length, so that the boundaries
+			// between fields are part of the digest.
+			fmt.Fprintf(h, "%d:%s", len(field), field)
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
 // ChangesetSpecTTL specifies the TTL of ChangesetSpecs that haven't been
 // attached to a BatchSpec.
 // It's lower than BatchSpecTTL because ChangesetSpecs should be attached to
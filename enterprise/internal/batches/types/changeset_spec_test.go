@@ -0,0 +1,123 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/go-diff/diff"
+
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
+)
+
+const testChangesetSpecDiff = `diff --git INSTALL.md INSTALL.md
+index e5af166..d44c3fc 100644
+--- INSTALL.md
++++ INSTALL.md
+@@ -3,10 +3,10 @@
+ Line 1
+ Line 2
+ Line 3
+-Line 4
++This is cool: Line 4
+ Line 5
+ Line 6
+-Line 7
+-Line 8
++Another Line 7
++Foobar Line 8
+ Line 9
+ Line 10
+`
+
+func TestChangesetSpecDiffStat(t *testing.T) {
+	rawSpec, err := json.Marshal(batcheslib.ChangesetSpec{
+		BaseRepository: "graphql-id",
+		BaseRef:        "refs/heads/main",
+		BaseRev:        "d34db33f",
+		HeadRepository: "graphql-id",
+		HeadRef:        "refs/heads/my-branch",
+		Title:          "the title",
+		Body:           "the body",
+		Published:      batcheslib.PublishedValue{Val: false},
+		Commits: []batcheslib.GitCommitDescription{{
+			Message:     "the message",
+			Diff:        testChangesetSpecDiff,
+			AuthorName:  "Mary McButtons",
+			AuthorEmail: "mary@example.com",
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spec, err := NewChangesetSpecFromRaw(string(rawSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if spec.DiffStatPending {
+		t.Fatal("diff stat not computed when creating spec")
+	}
+
+	want := diff.Stat{Added: 1, Changed: 2, Deleted: 1}
+	if diff := cmp.Diff(want, spec.DiffStat()); diff != "" {
+		t.Fatalf("unexpected diff stat (-want +have):\n%s", diff)
+	}
+
+	// Specs stored without a diff stat compute it when it's first needed.
+	stored := &ChangesetSpec{Spec: spec.Spec, DiffStatPending: true}
+	if diff := cmp.Diff(want, stored.DiffStat()); diff != "" {
+		t.Fatalf("unexpected lazily computed diff stat (-want +have):\n%s", diff)
+	}
+	if stored.DiffStatPending {
+		t.Fatal("diff stat not marked as computed")
+	}
+	if stored.DiffStatAdded != 1 || stored.DiffStatChanged != 2 || stored.DiffStatDeleted != 1 {
+		t.Fatalf("diff stat fields not set: %+v", stored)
+	}
+}
+
+func TestComputeDiffDigest(t *testing.T) {
+	commit := batcheslib.GitCommitDescription{
+		Message:     "the message",
+		Diff:        testChangesetSpecDiff,
+		AuthorName:  "Mary McButtons",
+		AuthorEmail: "mary@example.com",
+	}
+	spec := &batcheslib.ChangesetSpec{
+		HeadRef: "refs/heads/my-branch",
+		Title:   "the title",
+		Commits: []batcheslib.GitCommitDescription{commit},
+	}
+
+	digest := ComputeDiffDigest(spec)
+	if digest == "" {
+		t.Fatal("no digest computed for branch spec")
+	}
+
+	// The digest only depends on the commits.
+	other := *spec
+	other.Title = "another title"
+	if have := ComputeDiffDigest(&other); have != digest {
+		t.Errorf("unexpected digest for spec with another title. want=%q have=%q", digest, have)
+	}
+
+	otherCommit := commit
+	otherCommit.Message = "another message"
+	other.Commits = []batcheslib.GitCommitDescription{otherCommit}
+	if have := ComputeDiffDigest(&other); have == digest {
+		t.Error("unexpected identical digest for spec with another commit message")
+	}
+
+	// The boundaries between fields are part of the digest.
+	a := &batcheslib.ChangesetSpec{Commits: []batcheslib.GitCommitDescription{{Diff: "ab", Message: "c"}}}
+	b := &batcheslib.ChangesetSpec{Commits: []batcheslib.GitCommitDescription{{Diff: "a", Message: "bc"}}}
+	if ComputeDiffDigest(a) == ComputeDiffDigest(b) {
+		t.Error("unexpected identical digest for specs with different commits")
+	}
+
+	if have := ComputeDiffDigest(&batcheslib.ChangesetSpec{ExternalID: "123"}); have != "" {
+		t.Errorf("unexpected digest for importing spec: %q", have)
+	}
+}
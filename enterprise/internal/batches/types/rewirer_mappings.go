@@ -18,6 +18,9 @@ type RewirerMapping struct {
 	Changeset       *Changeset
 	RepoID          api.RepoID
 	Repo            *types.Repo
+	// Identical is true if the ChangesetSpec is identical to the current spec
+	// of the Changeset, as determined by Store.ListIdenticalChangesetSpecs.
+	Identical bool
 }
 
 type RewirerMappings []*RewirerMapping
@@ -20,6 +20,11 @@ type SiteCredential struct {
 	CreatedAt           time.Time
 	UpdatedAt           time.Time
 
+	// CheckedAt is when the credential was last checked against its code host,
+	// and CheckFailureMessage why that check found the credential unusable.
+	CheckedAt           *time.Time
+	CheckFailureMessage *string
+
 	Key encryption.Key
 }
 
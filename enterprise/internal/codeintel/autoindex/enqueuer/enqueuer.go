@@ -99,6 +99,109 @@ func (s *IndexEnqueuer) QueueIndexes(ctx context.Context, repositoryID int, rev,
 	return s.queueIndexForRepositoryAndCommit(ctx, repositoryID, commit, configuration, force, traceLog)
 }
 
+// DryRun describes the index jobs that would be enqueued for a repository and commit.
+type DryRun struct {
+	// Commit is the commit the revision resolved to.
+	Commit string
+	// AlreadyQueued is true if an upload or index record exists for the commit, in which case
+	// the index scheduler does not enqueue the index jobs.
+	AlreadyQueued bool
+	// ConfigurationSource is the source of the configuration the index jobs are determined from.
+	ConfigurationSource ConfigurationSource
+	// Indexes are the index records that would be inserted. They are not assigned an identifier.
+	Indexes []store.Index
+}
+
+// DryRunIndexes determines the set of index jobs that QueueIndexes would enqueue for the given repository
+// and revision, without enqueueing them. This is used to debug why a repository is, or is not, indexed.
+func (s *IndexEnqueuer) DryRunIndexes(ctx context.Context, repositoryID int, rev, configuration string) (_ DryRun, err error) {
+	ctx, traceLog, endObservation := s.operations.DryRunIndexes.WithAndLogger(ctx, &err, observation.Args{
+		LogFields: []log.Field{
+			log.Int("repositoryID", repositoryID),
+		},
+	})
+	defer endObservation(1, observation.Args{})
+
+	commitID, err := s.gitserverClient.ResolveRevision(ctx, repositoryID, rev)
+	if err != nil {
+		return DryRun{}, errors.Wrap(err, "gitserver.ResolveRevision")
+	}
+	commit := string(commitID)
+	traceLog(log.String("commit", commit))
+
+	isQueued, err := s.dbStore.IsQueued(ctx, repositoryID, commit)
+	if err != nil {
+		return DryRun{}, errors.Wrap(err, "dbstore.IsQueued")
+	}
+
+	indexes, source, err := s.getIndexRecords(ctx, repositoryID, commit, configuration)
+	if err != nil {
+		return DryRun{}, err
+	}
+	traceLog(log.Int("numIndexes", len(indexes)))
+
+	return DryRun{
+		Commit:              commit,
+		AlreadyQueued:       isQueued,
+		ConfigurationSource: source,
+		Indexes:             indexes,
+	}, nil
+}
+
+// errNoIndexJobs is recorded on an index request for which no index jobs could be determined.
+var errNoIndexJobs = errors.New("no index jobs could be determined for this commit")
+
+// RequestIndexes records a request to index the given repository and revision, then enqueues a set of
+// index jobs for it as QueueIndexes does when forced. The request is recorded before the index jobs are
+// determined, and the index jobs are recorded on the request once enqueued, so that the progress of the
+// request can be tracked from its identifier.
+//
+// A failure to determine or enqueue the index jobs is recorded on the request rather than returned.
+func (s *IndexEnqueuer) RequestIndexes(ctx context.Context, repositoryID int, rev, configuration string) (_ int, err error) {
+	ctx, traceLog, endObservation := s.operations.RequestIndexes.WithAndLogger(ctx, &err, observation.Args{
+		LogFields: []log.Field{
+			log.Int("repositoryID", repositoryID),
+			log.String("rev", rev),
+		},
+	})
+	defer endObservation(1, observation.Args{})
+
+	commitID, err := s.gitserverClient.ResolveRevision(ctx, repositoryID, rev)
+	if err != nil {
+		return 0, errors.Wrap(err, "gitserver.ResolveRevision")
+	}
+	commit := string(commitID)
+	traceLog(log.String("commit", commit))
+
+	id, err := s.dbStore.InsertIndexRequest(ctx, repositoryID, commit, rev)
+	if err != nil {
+		return 0, errors.Wrap(err, "dbstore.InsertIndexRequest")
+	}
+	traceLog(log.Int("indexRequestID", id))
+
+	indexes, queueErr := s.queueIndexForRepositoryAndCommit(ctx, repositoryID, commit, configuration, true, traceLog)
+	if queueErr == nil && len(indexes) == 0 {
+		queueErr = errNoIndexJobs
+	}
+
+	var failureMessage *string
+	if queueErr != nil {
+		message := queueErr.Error()
+		failureMessage = &message
+	}
+
+	indexIDs := make([]int, 0, len(indexes))
+	for _, index := range indexes {
+		indexIDs = append(indexIDs, index.ID)
+	}
+
+	if err := s.dbStore.MarkIndexRequestInferred(ctx, id, indexIDs, failureMessage); err != nil {
+		return 0, errors.Wrap(err, "dbstore.MarkIndexRequestInferred")
+	}
+
+	return id, nil
+}
+
 // QueueIndexesForPackage enqueues index jobs for a dependency of a recently-processed precise code
 // intelligence index.
 func (s *IndexEnqueuer) QueueIndexesForPackage(ctx context.Context, pkg precise.Package) (err error) {
@@ -160,7 +263,7 @@ func (s *IndexEnqueuer) queueIndexForRepositoryAndCommit(ctx context.Context, re
 		}
 	}
 
-	indexes, err := s.getIndexRecords(ctx, repositoryID, commit, configuration)
+	indexes, _, err := s.getIndexRecords(ctx, repositoryID, commit, configuration)
 	if err != nil {
 		return nil, err
 	}
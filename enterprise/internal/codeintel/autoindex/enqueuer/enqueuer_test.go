@@ -449,6 +449,125 @@ func TestQueueIndexesInferredTooLarge(t *testing.T) {
 	}
 }
 
+func TestDryRunIndexes(t *testing.T) {
+	mockDBStore := NewMockDBStore()
+	mockDBStore.IsQueuedFunc.SetDefaultReturn(true, nil)
+
+	mockGitserverClient := NewMockGitserverClient()
+	mockGitserverClient.ResolveRevisionFunc.SetDefaultHook(func(ctx context.Context, repositoryID int, rev string) (api.CommitID, error) {
+		return api.CommitID(fmt.Sprintf("c%d", repositoryID)), nil
+	})
+	mockGitserverClient.ListFilesFunc.SetDefaultHook(func(ctx context.Context, repositoryID int, commit string, pattern *regexp.Regexp) ([]string, error) {
+		if repositoryID == 42 {
+			return []string{"a/go.mod", "b/go.mod"}, nil
+		}
+
+		return nil, nil
+	})
+
+	scheduler := NewIndexEnqueuer(mockDBStore, mockGitserverClient, nil, &testConfig, &observation.TestContext)
+
+	dryRun, err := scheduler.DryRunIndexes(context.Background(), 42, "HEAD", "")
+	if err != nil {
+		t.Fatalf("unexpected error performing dry run: %s", err)
+	}
+	if dryRun.Commit != "c42" {
+		t.Errorf("unexpected commit. want=%q have=%q", "c42", dryRun.Commit)
+	}
+	if !dryRun.AlreadyQueued {
+		t.Errorf("expected commit to be queued")
+	}
+	if dryRun.ConfigurationSource != ConfigurationSourceInferred {
+		t.Errorf("unexpected configuration source. want=%q have=%q", ConfigurationSourceInferred, dryRun.ConfigurationSource)
+	}
+
+	var roots []string
+	for _, index := range dryRun.Indexes {
+		roots = append(roots, index.Root)
+	}
+	if diff := cmp.Diff([]string{"a", "b"}, roots); diff != "" {
+		t.Errorf("unexpected index roots (-want +got):\n%s", diff)
+	}
+
+	if len(mockDBStore.InsertIndexesFunc.History()) != 0 {
+		t.Errorf("unexpected number of calls to InsertIndexes. want=%d have=%d", 0, len(mockDBStore.InsertIndexesFunc.History()))
+	}
+
+	dryRun, err = scheduler.DryRunIndexes(context.Background(), 43, "HEAD", "")
+	if err != nil {
+		t.Fatalf("unexpected error performing dry run: %s", err)
+	}
+	if dryRun.ConfigurationSource != ConfigurationSourceNone || len(dryRun.Indexes) != 0 {
+		t.Errorf("unexpected dry run for unconfigured repository: %+v", dryRun)
+	}
+}
+
+func TestRequestIndexes(t *testing.T) {
+	mockDBStore := NewMockDBStore()
+	mockDBStore.InsertIndexRequestFunc.SetDefaultReturn(7, nil)
+	mockDBStore.InsertIndexesFunc.SetDefaultHook(func(ctx context.Context, indexes []store.Index) ([]store.Index, error) {
+		for i := range indexes {
+			indexes[i].ID = i + 1
+		}
+		return indexes, nil
+	})
+
+	mockGitserverClient := NewMockGitserverClient()
+	mockGitserverClient.ResolveRevisionFunc.SetDefaultHook(func(ctx context.Context, repositoryID int, rev string) (api.CommitID, error) {
+		return api.CommitID(fmt.Sprintf("c%d", repositoryID)), nil
+	})
+	mockGitserverClient.ListFilesFunc.SetDefaultHook(func(ctx context.Context, repositoryID int, commit string, pattern *regexp.Regexp) ([]string, error) {
+		if repositoryID == 42 {
+			return []string{"a/go.mod", "b/go.mod"}, nil
+		}
+
+		return nil, nil
+	})
+
+	scheduler := NewIndexEnqueuer(mockDBStore, mockGitserverClient, nil, &testConfig, &observation.TestContext)
+
+	id, err := scheduler.RequestIndexes(context.Background(), 42, "main", "")
+	if err != nil {
+		t.Fatalf("unexpected error requesting indexes: %s", err)
+	}
+	if id != 7 {
+		t.Errorf("unexpected index request id. want=%d have=%d", 7, id)
+	}
+
+	if history := mockDBStore.InsertIndexRequestFunc.History(); len(history) != 1 {
+		t.Fatalf("unexpected number of calls to InsertIndexRequest. want=%d have=%d", 1, len(history))
+	} else if history[0].Arg2 != "c42" || history[0].Arg3 != "main" {
+		t.Errorf("unexpected commit and revision. want=%q have=%q", []string{"c42", "main"}, []string{history[0].Arg2, history[0].Arg3})
+	}
+	if len(mockDBStore.IsQueuedFunc.History()) != 0 {
+		t.Errorf("unexpected number of calls to IsQueued. want=%d have=%d", 0, len(mockDBStore.IsQueuedFunc.History()))
+	}
+
+	if history := mockDBStore.MarkIndexRequestInferredFunc.History(); len(history) != 1 {
+		t.Fatalf("unexpected number of calls to MarkIndexRequestInferred. want=%d have=%d", 1, len(history))
+	} else {
+		if history[0].Arg1 != 7 {
+			t.Errorf("unexpected index request id. want=%d have=%d", 7, history[0].Arg1)
+		}
+		if diff := cmp.Diff([]int{1, 2}, history[0].Arg2); diff != "" {
+			t.Errorf("unexpected index ids (-want +got):\n%s", diff)
+		}
+		if history[0].Arg3 != nil {
+			t.Errorf("unexpected failure message: %s", *history[0].Arg3)
+		}
+	}
+
+	// A repository without index jobs records a failure on the request
+	if _, err := scheduler.RequestIndexes(context.Background(), 43, "main", ""); err != nil {
+		t.Fatalf("unexpected error requesting indexes: %s", err)
+	}
+	if history := mockDBStore.MarkIndexRequestInferredFunc.History(); len(history) != 2 {
+		t.Fatalf("unexpected number of calls to MarkIndexRequestInferred. want=%d have=%d", 2, len(history))
+	} else if len(history[1].Arg2) != 0 || history[1].Arg3 == nil {
+		t.Errorf("expected a failure message and no index ids: %v %v", history[1].Arg2, history[1].Arg3)
+	}
+}
+
 func TestQueueIndexesForPackage(t *testing.T) {
 	mockDBStore := NewMockDBStore()
 	mockDBStore.TransactFunc.SetDefaultReturn(mockDBStore, nil)
@@ -21,6 +21,8 @@ type DBStore interface {
 	DirtyRepositories(ctx context.Context) (map[int]int, error)
 	IsQueued(ctx context.Context, repositoryID int, commit string) (bool, error)
 	InsertIndexes(ctx context.Context, index []dbstore.Index) ([]dbstore.Index, error)
+	InsertIndexRequest(ctx context.Context, repositoryID int, commit, rev string) (int, error)
+	MarkIndexRequestInferred(ctx context.Context, id int, indexIDs []int, failureMessage *string) error
 	GetIndexConfigurationByRepositoryID(ctx context.Context, repositoryID int) (dbstore.IndexConfiguration, bool, error)
 }
 
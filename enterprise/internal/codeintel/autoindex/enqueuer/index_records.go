@@ -12,6 +12,18 @@ import (
 
 type configurationFactoryFunc func(ctx context.Context, repositoryID int, commit string) ([]store.Index, bool, error)
 
+// ConfigurationSource describes where the index configuration used to determine a set of index
+// records comes from.
+type ConfigurationSource string
+
+const (
+	ConfigurationSourceExplicit   ConfigurationSource = "EXPLICIT"
+	ConfigurationSourceDatabase   ConfigurationSource = "DATABASE"
+	ConfigurationSourceRepository ConfigurationSource = "REPOSITORY"
+	ConfigurationSourceInferred   ConfigurationSource = "INFERRED"
+	ConfigurationSourceNone       ConfigurationSource = "NONE"
+)
+
 // getIndexRecords determines the set of index records that should be enqueued for the given commit.
 // For each repository, we look for index configuration in the following order:
 //
@@ -19,23 +31,28 @@ type configurationFactoryFunc func(ctx context.Context, repositoryID int, commit
 //  - in the database
 //  - committed to `sourcegraph.yaml` in the repository
 //  - inferred from the repository structure
-func (s *IndexEnqueuer) getIndexRecords(ctx context.Context, repositoryID int, commit, configuration string) ([]store.Index, error) {
-	fns := []configurationFactoryFunc{
-		makeExplicitConfigurationFactory(configuration),
-		s.getIndexRecordsFromConfigurationInDatabase,
-		s.getIndexRecordsFromConfigurationInRepository,
-		s.inferIndexRecordsFromRepositoryStructure,
+//
+// The source of the configuration that was used is returned along with the index records.
+func (s *IndexEnqueuer) getIndexRecords(ctx context.Context, repositoryID int, commit, configuration string) ([]store.Index, ConfigurationSource, error) {
+	fns := []struct {
+		source ConfigurationSource
+		fn     configurationFactoryFunc
+	}{
+		{ConfigurationSourceExplicit, makeExplicitConfigurationFactory(configuration)},
+		{ConfigurationSourceDatabase, s.getIndexRecordsFromConfigurationInDatabase},
+		{ConfigurationSourceRepository, s.getIndexRecordsFromConfigurationInRepository},
+		{ConfigurationSourceInferred, s.inferIndexRecordsFromRepositoryStructure},
 	}
 
 	for _, fn := range fns {
-		if indexRecords, ok, err := fn(ctx, repositoryID, commit); err != nil {
-			return nil, err
+		if indexRecords, ok, err := fn.fn(ctx, repositoryID, commit); err != nil {
+			return nil, ConfigurationSourceNone, err
 		} else if ok {
-			return indexRecords, nil
+			return indexRecords, fn.source, nil
 		}
 	}
 
-	return nil, nil
+	return nil, ConfigurationSourceNone, nil
 }
 
 // makeExplicitConfigurationFactory returns a factory that returns a set of index jobs configured
@@ -34,12 +34,18 @@ type MockDBStore struct {
 	// HandleFunc is an instance of a mock function object controlling the
 	// behavior of the method Handle.
 	HandleFunc *DBStoreHandleFunc
+	// InsertIndexRequestFunc is an instance of a mock function object
+	// controlling the behavior of the method InsertIndexRequest.
+	InsertIndexRequestFunc *DBStoreInsertIndexRequestFunc
 	// InsertIndexesFunc is an instance of a mock function object
 	// controlling the behavior of the method InsertIndexes.
 	InsertIndexesFunc *DBStoreInsertIndexesFunc
 	// IsQueuedFunc is an instance of a mock function object controlling the
 	// behavior of the method IsQueued.
 	IsQueuedFunc *DBStoreIsQueuedFunc
+	// MarkIndexRequestInferredFunc is an instance of a mock function object
+	// controlling the behavior of the method MarkIndexRequestInferred.
+	MarkIndexRequestInferredFunc *DBStoreMarkIndexRequestInferredFunc
 	// TransactFunc is an instance of a mock function object controlling the
 	// behavior of the method Transact.
 	TransactFunc *DBStoreTransactFunc
@@ -74,6 +80,11 @@ func NewMockDBStore() *MockDBStore {
 				return nil
 			},
 		},
+		InsertIndexRequestFunc: &DBStoreInsertIndexRequestFunc{
+			defaultHook: func(context.Context, int, string, string) (int, error) {
+				return 0, nil
+			},
+		},
 		InsertIndexesFunc: &DBStoreInsertIndexesFunc{
 			defaultHook: func(context.Context, []dbstore.Index) ([]dbstore.Index, error) {
 				return nil, nil
@@ -84,6 +95,11 @@ func NewMockDBStore() *MockDBStore {
 				return false, nil
 			},
 		},
+		MarkIndexRequestInferredFunc: &DBStoreMarkIndexRequestInferredFunc{
+			defaultHook: func(context.Context, int, []int, *string) error {
+				return nil
+			},
+		},
 		TransactFunc: &DBStoreTransactFunc{
 			defaultHook: func(context.Context) (DBStore, error) {
 				return nil, nil
@@ -111,12 +127,18 @@ func NewMockDBStoreFrom(i DBStore) *MockDBStore {
 		HandleFunc: &DBStoreHandleFunc{
 			defaultHook: i.Handle,
 		},
+		InsertIndexRequestFunc: &DBStoreInsertIndexRequestFunc{
+			defaultHook: i.InsertIndexRequest,
+		},
 		InsertIndexesFunc: &DBStoreInsertIndexesFunc{
 			defaultHook: i.InsertIndexes,
 		},
 		IsQueuedFunc: &DBStoreIsQueuedFunc{
 			defaultHook: i.IsQueued,
 		},
+		MarkIndexRequestInferredFunc: &DBStoreMarkIndexRequestInferredFunc{
+			defaultHook: i.MarkIndexRequestInferred,
+		},
 		TransactFunc: &DBStoreTransactFunc{
 			defaultHook: i.Transact,
 		},
@@ -663,6 +685,121 @@ func (c DBStoreHandleFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// DBStoreInsertIndexRequestFunc describes the behavior when the
+// InsertIndexRequest method of the parent MockDBStore instance is invoked.
+type DBStoreInsertIndexRequestFunc struct {
+	defaultHook func(context.Context, int, string, string) (int, error)
+	hooks       []func(context.Context, int, string, string) (int, error)
+	history     []DBStoreInsertIndexRequestFuncCall
+	mutex       sync.Mutex
+}
+
+// InsertIndexRequest delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockDBStore) InsertIndexRequest(v0 context.Context, v1 int, v2 string, v3 string) (int, error) {
+	r0, r1 := m.InsertIndexRequestFunc.nextHook()(v0, v1, v2, v3)
+	m.InsertIndexRequestFunc.appendCall(DBStoreInsertIndexRequestFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the InsertIndexRequest
+// method of the parent MockDBStore instance is invoked and the hook queue
+// is empty.
+func (f *DBStoreInsertIndexRequestFunc) SetDefaultHook(hook func(context.Context, int, string, string) (int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// InsertIndexRequest method of the parent MockDBStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *DBStoreInsertIndexRequestFunc) PushHook(hook func(context.Context, int, string, string) (int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DBStoreInsertIndexRequestFunc) SetDefaultReturn(r0 int, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, string, string) (int, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DBStoreInsertIndexRequestFunc) PushReturn(r0 int, r1 error) {
+	f.PushHook(func(context.Context, int, string, string) (int, error) {
+		return r0, r1
+	})
+}
+
+func (f *DBStoreInsertIndexRequestFunc) nextHook() func(context.Context, int, string, string) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBStoreInsertIndexRequestFunc) appendCall(r0 DBStoreInsertIndexRequestFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DBStoreInsertIndexRequestFuncCall objects
+// describing the invocations of this function.
+func (f *DBStoreInsertIndexRequestFunc) History() []DBStoreInsertIndexRequestFuncCall {
+	f.mutex.Lock()
+	history := make([]DBStoreInsertIndexRequestFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBStoreInsertIndexRequestFuncCall is an object that describes an
+// invocation of method InsertIndexRequest on an instance of MockDBStore.
+type DBStoreInsertIndexRequestFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBStoreInsertIndexRequestFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBStoreInsertIndexRequestFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // DBStoreInsertIndexesFunc describes the behavior when the InsertIndexes
 // method of the parent MockDBStore instance is invoked.
 type DBStoreInsertIndexesFunc struct {
@@ -883,6 +1020,121 @@ func (c DBStoreIsQueuedFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// DBStoreMarkIndexRequestInferredFunc describes the behavior when the
+// MarkIndexRequestInferred method of the parent MockDBStore instance is
+// invoked.
+type DBStoreMarkIndexRequestInferredFunc struct {
+	defaultHook func(context.Context, int, []int, *string) error
+	hooks       []func(context.Context, int, []int, *string) error
+	history     []DBStoreMarkIndexRequestInferredFuncCall
+	mutex       sync.Mutex
+}
+
+// MarkIndexRequestInferred delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockDBStore) MarkIndexRequestInferred(v0 context.Context, v1 int, v2 []int, v3 *string) error {
+	r0 := m.MarkIndexRequestInferredFunc.nextHook()(v0, v1, v2, v3)
+	m.MarkIndexRequestInferredFunc.appendCall(DBStoreMarkIndexRequestInferredFuncCall{v0, v1, v2, v3, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the
+// MarkIndexRequestInferred method of the parent MockDBStore instance is
+// invoked and the hook queue is empty.
+func (f *DBStoreMarkIndexRequestInferredFunc) SetDefaultHook(hook func(context.Context, int, []int, *string) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// MarkIndexRequestInferred method of the parent MockDBStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *DBStoreMarkIndexRequestInferredFunc) PushHook(hook func(context.Context, int, []int, *string) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DBStoreMarkIndexRequestInferredFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int, []int, *string) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DBStoreMarkIndexRequestInferredFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int, []int, *string) error {
+		return r0
+	})
+}
+
+func (f *DBStoreMarkIndexRequestInferredFunc) nextHook() func(context.Context, int, []int, *string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBStoreMarkIndexRequestInferredFunc) appendCall(r0 DBStoreMarkIndexRequestInferredFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DBStoreMarkIndexRequestInferredFuncCall
+// objects describing the invocations of this function.
+func (f *DBStoreMarkIndexRequestInferredFunc) History() []DBStoreMarkIndexRequestInferredFuncCall {
+	f.mutex.Lock()
+	history := make([]DBStoreMarkIndexRequestInferredFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBStoreMarkIndexRequestInferredFuncCall is an object that describes an
+// invocation of method MarkIndexRequestInferred on an instance of
+// MockDBStore.
+type DBStoreMarkIndexRequestInferredFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 []int
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 *string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBStoreMarkIndexRequestInferredFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBStoreMarkIndexRequestInferredFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // DBStoreTransactFunc describes the behavior when the Transact method of
 // the parent MockDBStore instance is invoked.
 type DBStoreTransactFunc struct {
@@ -11,6 +11,8 @@ type operations struct {
 	QueueIndex              *observation.Operation
 	InferIndexConfiguration *observation.Operation
 	QueueIndexForPackage    *observation.Operation
+	DryRunIndexes           *observation.Operation
+	RequestIndexes          *observation.Operation
 }
 
 func newOperations(observationContext *observation.Context) *operations {
@@ -33,5 +35,7 @@ func newOperations(observationContext *observation.Context) *operations {
 		QueueIndex:              op("QueueIndex"),
 		InferIndexConfiguration: op("InferIndexConfiguration"),
 		QueueIndexForPackage:    op("QueueIndexForPackage"),
+		DryRunIndexes:           op("DryRunIndexes"),
+		RequestIndexes:          op("RequestIndexes"),
 	}
 }
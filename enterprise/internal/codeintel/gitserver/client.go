@@ -125,13 +125,18 @@ func (c *Client) RepoInfo(ctx context.Context, repos ...api.RepoName) (_ map[api
 }
 
 type CommitGraph struct {
-	graph map[string][]string
-	order []string
+	graph    map[string][]string
+	order    []string
+	boundary []string
 }
 
 func (c *CommitGraph) Graph() map[string][]string { return c.graph }
 func (c *CommitGraph) Order() []string            { return c.order }
 
+// Boundary returns the commits of the graph that were not part of the git log output, but that
+// are the parents of commits that were. They are part of the graph without parents.
+func (c *CommitGraph) Boundary() []string { return c.boundary }
+
 type CommitGraphOptions struct {
 	Commit  string
 	AllRefs bool
@@ -172,6 +177,70 @@ func (c *Client) CommitGraph(ctx context.Context, repositoryID int, opts CommitG
 	return ParseCommitGraph(strings.Split(out, "\n")), nil
 }
 
+// CommitGraphSince returns the commit graph of the commits reachable from the given tips but not
+// from any commit of the given watermark. The parents of these commits that are reachable from the
+// watermark are part of the graph, but without parents of their own.
+func (c *Client) CommitGraphSince(ctx context.Context, repositoryID int, tips, watermark []string) (_ *CommitGraph, err error) {
+	ctx, endObservation := c.operations.commitGraphSince.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("repositoryID", repositoryID),
+		log.Int("numTips", len(tips)),
+		log.Int("numWatermarkCommits", len(watermark)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	repo, err := c.repositoryIDToRepo(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	commits, err := gitserver.DefaultClient.CommitGraphSince(ctx, repo, toCommitIDs(tips), toCommitIDs(watermark))
+	if err != nil {
+		return nil, errors.Wrap(err, "gitserver.CommitGraphSince")
+	}
+
+	lines := make([]string, 0, len(commits))
+	for _, commit := range commits {
+		parts := []string{string(commit.Commit)}
+		for _, parent := range commit.Parents {
+			parts = append(parts, string(parent))
+		}
+		lines = append(lines, strings.Join(parts, " "))
+	}
+
+	return ParseCommitGraph(lines), nil
+}
+
+func toCommitIDs(commits []string) []api.CommitID {
+	ids := make([]api.CommitID, 0, len(commits))
+	for _, commit := range commits {
+		ids = append(ids, api.CommitID(commit))
+	}
+	return ids
+}
+
+// WithRoots returns a copy of the commit graph that also contains the given commits. The commits
+// that are not already part of the graph are added to its boundary.
+func (c *CommitGraph) WithRoots(commits []string) *CommitGraph {
+	graph := make(map[string][]string, len(c.graph)+len(commits))
+	for commit, parents := range c.graph {
+		graph[commit] = parents
+	}
+
+	var roots []string
+	for _, commit := range commits {
+		if _, ok := graph[commit]; !ok {
+			graph[commit] = []string{}
+			roots = append(roots, commit)
+		}
+	}
+
+	return &CommitGraph{
+		graph:    graph,
+		order:    append(append([]string(nil), roots...), c.order...),
+		boundary: append(roots, c.boundary...),
+	}
+}
+
 // ParseCommitGraph converts the output of git log into a map from commits to parent commits,
 // and a topological ordering of commits such that parents come before children. If a commit
 // is listed but has no ancestors then its parent slice is empty, but is still present in
@@ -216,8 +285,9 @@ func ParseCommitGraph(lines []string) *CommitGraph {
 	}
 
 	return &CommitGraph{
-		graph: graph,
-		order: append(prefix, order...),
+		graph:    graph,
+		order:    append(append([]string(nil), prefix...), order...),
+		boundary: prefix,
 	}
 }
 
@@ -115,6 +115,41 @@ func TestParseCommitGraphPartial(t *testing.T) {
 	if diff := cmp.Diff(expectedOrder, graph.Order()); diff != "" {
 		t.Errorf("unexpected commit order (-want +got):\n%s", diff)
 	}
+
+	expectedBoundary := []string{
+		"2716762a5213f5fe2576d2a52d1182282704004c",
+		"02f41985f46b400b7a673c3dfb6bab8fd1ac6a6d",
+	}
+	if diff := cmp.Diff(expectedBoundary, graph.Boundary()); diff != "" {
+		t.Errorf("unexpected boundary (-want +got):\n%s", diff)
+	}
+}
+
+func TestCommitGraphWithRoots(t *testing.T) {
+	graph := ParseCommitGraph([]string{
+		"c b",
+		"b a",
+	}).WithRoots([]string{"a", "d"})
+
+	expectedGraph := map[string][]string{
+		"a": {},
+		"b": {"a"},
+		"c": {"b"},
+		"d": {},
+	}
+	if diff := cmp.Diff(expectedGraph, graph.Graph()); diff != "" {
+		t.Errorf("unexpected commit mapping (-want +got):\n%s", diff)
+	}
+
+	expectedOrder := []string{"d", "a", "b", "c"}
+	if diff := cmp.Diff(expectedOrder, graph.Order()); diff != "" {
+		t.Errorf("unexpected commit order (-want +got):\n%s", diff)
+	}
+
+	expectedBoundary := []string{"d", "a"}
+	if diff := cmp.Diff(expectedBoundary, graph.Boundary()); diff != "" {
+		t.Errorf("unexpected boundary (-want +got):\n%s", diff)
+	}
 }
 
 func TestParseRefDescriptions(t *testing.T) {
@@ -14,6 +14,7 @@ type operations struct {
 	commitDate            *observation.Operation
 	commitExists          *observation.Operation
 	commitGraph           *observation.Operation
+	commitGraphSince      *observation.Operation
 	commitsUniqueToBranch *observation.Operation
 	directoryChildren     *observation.Operation
 	fileExists            *observation.Operation
@@ -51,6 +52,7 @@ func newOperations(observationContext *observation.Context) *operations {
 		commitDate:            op("CommitDate"),
 		commitExists:          op("CommitExists"),
 		commitGraph:           op("CommitGraph"),
+		commitGraphSince:      op("CommitGraphSince"),
 		commitsUniqueToBranch: op("CommitsUniqueToBranch"),
 		directoryChildren:     op("DirectoryChildren"),
 		fileExists:            op("FileExists"),
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
 	"github.com/opentracing/opentracing-go/log"
 
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/commitgraph"
@@ -304,17 +305,17 @@ func (s *Store) CalculateVisibleUploads(
 	graph := commitgraph.NewGraph(commitGraph, commitGraphView)
 
 	// Write the graph into temporary tables in Postgres
-	if err := tx.writeVisibleUploads(ctx, sanitizeCommitInput(ctx, graph, refDescriptions, maxAgeForNonStaleBranches, maxAgeForNonStaleTags)); err != nil {
+	if err := tx.writeVisibleUploads(ctx, sanitizeCommitInput(ctx, graph, nil, refDescriptions, maxAgeForNonStaleBranches, maxAgeForNonStaleTags)); err != nil {
 		return err
 	}
 
 	// Persist data to permenant table: t_lsif_nearest_uploads -> lsif_nearest_uploads
-	if err := tx.persistNearestUploads(ctx, repositoryID); err != nil {
+	if err := tx.persistNearestUploads(ctx, repositoryID, false); err != nil {
 		return err
 	}
 
 	// Persist data to permenant table: t_lsif_nearest_uploads_links -> lsif_nearest_uploads_links
-	if err := tx.persistNearestUploadsLinks(ctx, repositoryID); err != nil {
+	if err := tx.persistNearestUploadsLinks(ctx, repositoryID, false); err != nil {
 		return err
 	}
 
@@ -323,12 +324,48 @@ func (s *Store) CalculateVisibleUploads(
 		return err
 	}
 
+	// Record the commit graph watermark so that the next update can only look at new commits. If
+	// the graph is empty while there are uploads (their commit dates are still being backfilled),
+	// nothing was persisted and the next update must see the whole graph.
+	watermarkCommits := refDescriptionCommits(refDescriptions)
+	if len(commitGraph.Order()) == 0 && len(commitGraphView.Tokens) > 0 {
+		watermarkCommits = nil
+	}
+
+	return tx.finishVisibleUploadsCalculation(ctx, repositoryID, watermarkCommits, commitGraphView, dirtyToken, now)
+}
+
+// finishVisibleUploadsCalculation records the given commit graph watermark and the uploads of the
+// given view, unmarks the repository as dirty, and marks the uploads queued for deletion as deleted.
+func (s *Store) finishVisibleUploadsCalculation(
+	ctx context.Context,
+	repositoryID int,
+	watermarkCommits []string,
+	commitGraphView *commitgraph.CommitGraphView,
+	dirtyToken int,
+	now time.Time,
+) error {
+	watermarkUploadIDs := make([]int, 0, len(commitGraphView.Tokens))
+	for uploadID := range commitGraphView.Tokens {
+		watermarkUploadIDs = append(watermarkUploadIDs, uploadID)
+	}
+	sort.Ints(watermarkUploadIDs)
+
+	if err := s.Store.Exec(ctx, sqlf.Sprintf(
+		calculateVisibleUploadsWatermarkQuery,
+		pq.Array(watermarkCommits),
+		pq.Array(watermarkUploadIDs),
+		repositoryID,
+	)); err != nil {
+		return err
+	}
+
 	if dirtyToken != 0 {
 		// If the user requests us to clear a dirty token, set the updated_token value to
 		// the dirty token if it wouldn't decrease the value. Dirty repositories are determined
 		// by having a non-equal dirty and update token, and we want the most recent upload
 		// token to win this write.
-		if err := tx.Store.Exec(ctx, sqlf.Sprintf(calculateVisibleUploadsDirtyRepositoryQuery, dirtyToken, now, repositoryID)); err != nil {
+		if err := s.Store.Exec(ctx, sqlf.Sprintf(calculateVisibleUploadsDirtyRepositoryQuery, dirtyToken, now, repositoryID)); err != nil {
 			return err
 		}
 	}
@@ -336,25 +373,41 @@ func (s *Store) CalculateVisibleUploads(
 	// All completed uploads are now visible. Mark any uploads queued for deletion as deleted as
 	// they are no longer reachable from the commit graph and cannot be used to fulfill any API
 	// requests.
-	if err := tx.Store.Exec(ctx, sqlf.Sprintf(calculateVisibleUploadsDeleteUploadsQueuedForDeletionQuery, repositoryID)); err != nil {
+	if err := s.Store.Exec(ctx, sqlf.Sprintf(calculateVisibleUploadsDeleteUploadsQueuedForDeletionQuery, repositoryID)); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// refDescriptionCommits returns the sorted commits at the tip of the given branches and tags.
+func refDescriptionCommits(refDescriptions map[string][]gitserver.RefDescription) []string {
+	commits := make([]string, 0, len(refDescriptions))
+	for commit := range refDescriptions {
+		commits = append(commits, commit)
+	}
+	sort.Strings(commits)
+
+	return commits
+}
+
 const calculateVisibleUploadsCommitGraphQuery = `
 -- source: enterprise/internal/codeintel/stores/dbstore/commits.go:CalculateVisibleUploads
 SELECT id, commit, md5(root || ':' || indexer) as token, 0 as distance FROM lsif_uploads WHERE state = 'completed' AND repository_id = %s
 `
 
+const calculateVisibleUploadsWatermarkQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/commits.go:finishVisibleUploadsCalculation
+UPDATE lsif_dirty_repositories SET watermark_commits = %s, watermark_upload_ids = %s WHERE repository_id = %s
+`
+
 const calculateVisibleUploadsDirtyRepositoryQuery = `
--- source: enterprise/internal/codeintel/stores/dbstore/commits.go:CalculateVisibleUploads
+-- source: enterprise/internal/codeintel/stores/dbstore/commits.go:finishVisibleUploadsCalculation
 UPDATE lsif_dirty_repositories SET update_token = GREATEST(update_token, %s), updated_at = %s WHERE repository_id = %s
 `
 
 const calculateVisibleUploadsDeleteUploadsQueuedForDeletionQuery = `
--- source: enterprise/internal/codeintel/stores/dbstore/commits.go:CalculateVisibleUploads
+-- source: enterprise/internal/codeintel/stores/dbstore/commits.go:finishVisibleUploadsCalculation
 WITH
 candidates AS (
 	SELECT u.id
@@ -370,6 +423,244 @@ SET state = 'deleted'
 WHERE id IN (SELECT id FROM candidates)
 `
 
+// CommitGraphWatermark returns the tips of the branches and tags of the commit graph used by the last
+// update of the visible uploads of the given repository. A false-valued flag is returned if the next
+// update must use the whole commit graph.
+func (s *Store) CommitGraphWatermark(ctx context.Context, repositoryID int) (_ []string, _ bool, err error) {
+	ctx, endObservation := s.operations.commitGraphWatermark.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("repositoryID", repositoryID),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	rows, err := s.Store.Query(ctx, sqlf.Sprintf(commitGraphWatermarkQuery, repositoryID))
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	if !rows.Next() {
+		return nil, false, nil
+	}
+
+	var commits []string
+	if err := rows.Scan(pq.Array(&commits)); err != nil {
+		return nil, false, err
+	}
+
+	return commits, true, nil
+}
+
+const commitGraphWatermarkQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/commits.go:CommitGraphWatermark
+SELECT watermark_commits
+FROM lsif_dirty_repositories
+WHERE repository_id = %s AND watermark_commits IS NOT NULL AND watermark_upload_ids IS NOT NULL
+`
+
+// CalculateVisibleUploadsIncremental updates the visible uploads of the given repository like
+// CalculateVisibleUploads, but only calculates the visible uploads of the commits added since the
+// commit graph watermark. The given commit graph must contain the commits reachable from the tip of
+// the given branches and tags but not from the watermark commits (see CommitGraphWatermark).
+//
+// The visible uploads of the existing commits do not change as long as uploads are only added to new
+// commits. If any other upload was added or removed since the last update, nothing is written and a
+// false-valued flag is returned: the caller must then call CalculateVisibleUploads with the whole
+// commit graph.
+func (s *Store) CalculateVisibleUploadsIncremental(
+	ctx context.Context,
+	repositoryID int,
+	commitGraph *gitserver.CommitGraph,
+	refDescriptions map[string][]gitserver.RefDescription,
+	maxAgeForNonStaleBranches time.Duration,
+	maxAgeForNonStaleTags time.Duration,
+	dirtyToken int,
+	now time.Time,
+) (_ bool, err error) {
+	ctx, traceLog, endObservation := s.operations.calculateVisibleUploadsIncremental.WithAndLogger(ctx, &err, observation.Args{
+		LogFields: []log.Field{
+			log.Int("repositoryID", repositoryID),
+			log.Int("numCommitGraphKeys", len(commitGraph.Order())),
+			log.Int("numRefDescriptions", len(refDescriptions)),
+			log.Int("dirtyToken", dirtyToken),
+		},
+	})
+	defer endObservation(1, observation.Args{})
+
+	tx, err := s.transact(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	maxAgeForNonStaleBranches, maxAgeForNonStaleTags, err = refineRetentionConfiguration(ctx, tx, repositoryID, maxAgeForNonStaleBranches, maxAgeForNonStaleTags)
+	if err != nil {
+		return false, err
+	}
+
+	watermarkUploadIDs, err := basestore.ScanInts(tx.Store.Query(ctx, sqlf.Sprintf(calculateVisibleUploadsIncrementalWatermarkQuery, repositoryID)))
+	if err != nil {
+		return false, err
+	}
+
+	commitGraphView, err := scanCommitGraphView(tx.Store.Query(ctx, sqlf.Sprintf(calculateVisibleUploadsCommitGraphQuery, repositoryID)))
+	if err != nil {
+		return false, err
+	}
+
+	// The tips of the branches and tags that did not move are not part of the commit graph. Add them
+	// to its boundary, so that we can recalculate the uploads visible at the tip of all of them.
+	commitGraph = commitGraph.WithRoots(refDescriptionCommits(refDescriptions))
+
+	boundaryCommits := make(map[string]struct{}, len(commitGraph.Boundary()))
+	for _, commit := range commitGraph.Boundary() {
+		boundaryCommits[commit] = struct{}{}
+	}
+	traceLog(
+		log.Int("numBoundaryCommits", len(boundaryCommits)),
+		log.Int("numCommitGraphViewMetaKeys", len(commitGraphView.Meta)),
+	)
+
+	if !canCalculateVisibleUploadsIncrementally(commitGraph, boundaryCommits, commitGraphView, watermarkUploadIDs) {
+		traceLog(log.Bool("incremental", false))
+		return false, nil
+	}
+
+	// Decorate the boundary commits with the uploads visible from them, which have not changed since
+	// the last update. The new commits inherit these uploads from their old parents.
+	boundaryUploads, err := tx.visibleUploadsForCommits(ctx, repositoryID, commitGraph.Boundary())
+	if err != nil {
+		return false, err
+	}
+	for commit := range boundaryCommits {
+		if len(commitGraphView.Meta[commit]) > 0 && len(boundaryUploads[commit]) == 0 {
+			// This old commit defines an upload but was not part of the persisted commit graph,
+			// so its descendants were not either.
+			traceLog(log.Bool("incremental", false))
+			return false, nil
+		}
+
+		if uploads, ok := boundaryUploads[commit]; ok {
+			commitGraphView.Meta[commit] = uploads
+		} else {
+			delete(commitGraphView.Meta, commit)
+		}
+	}
+	traceLog(log.Bool("incremental", true))
+
+	graph := commitgraph.NewGraph(commitGraph, commitGraphView)
+
+	if err := tx.writeVisibleUploads(ctx, sanitizeCommitInput(ctx, graph, boundaryCommits, refDescriptions, maxAgeForNonStaleBranches, maxAgeForNonStaleTags)); err != nil {
+		return false, err
+	}
+	if err := tx.persistNearestUploads(ctx, repositoryID, true); err != nil {
+		return false, err
+	}
+	if err := tx.persistNearestUploadsLinks(ctx, repositoryID, true); err != nil {
+		return false, err
+	}
+	if err := tx.persistUploadsVisibleAtTip(ctx, repositoryID); err != nil {
+		return false, err
+	}
+
+	if err := tx.finishVisibleUploadsCalculation(ctx, repositoryID, refDescriptionCommits(refDescriptions), commitGraphView, dirtyToken, now); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+const calculateVisibleUploadsIncrementalWatermarkQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/commits.go:CalculateVisibleUploadsIncremental
+SELECT unnest(watermark_upload_ids) FROM lsif_dirty_repositories WHERE repository_id = %s
+`
+
+// canCalculateVisibleUploadsIncrementally returns true if the uploads of the given view differ from
+// the uploads at the last update of the commit graph only by uploads added to new commits of the
+// given graph.
+func canCalculateVisibleUploadsIncrementally(
+	commitGraph *gitserver.CommitGraph,
+	boundaryCommits map[string]struct{},
+	commitGraphView *commitgraph.CommitGraphView,
+	watermarkUploadIDs []int,
+) bool {
+	watermarkUploads := make(map[int]struct{}, len(watermarkUploadIDs))
+	for _, uploadID := range watermarkUploadIDs {
+		if _, ok := commitGraphView.Tokens[uploadID]; !ok {
+			// The upload was deleted since the last update
+			return false
+		}
+
+		watermarkUploads[uploadID] = struct{}{}
+	}
+
+	graph := commitGraph.Graph()
+	for commit, uploads := range commitGraphView.Meta {
+		for _, upload := range uploads {
+			if _, ok := watermarkUploads[upload.UploadID]; ok {
+				continue
+			}
+
+			if _, ok := graph[commit]; !ok {
+				// The upload was added to an old commit
+				return false
+			}
+			if _, ok := boundaryCommits[commit]; ok {
+				// The upload was added to an old commit
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// visibleUploadsForCommits returns the persisted uploads visible from the given commits.
+func (s *Store) visibleUploadsForCommits(ctx context.Context, repositoryID int, commits []string) (_ map[string][]commitgraph.UploadMeta, err error) {
+	uploads := map[string][]commitgraph.UploadMeta{}
+
+	// Query in batches to stay well below the maximum number of query parameters
+	for len(commits) > 0 {
+		chunk := commits
+		if len(chunk) > visibleUploadsForCommitsBatchSize {
+			chunk = chunk[:visibleUploadsForCommitsBatchSize]
+		}
+		commits = commits[len(chunk):]
+
+		if err := s.scanVisibleUploadsForCommits(ctx, uploads, sqlf.Sprintf(visibleUploadsForCommitsQuery, makeVisibleUploadCandidatesQuery(repositoryID, chunk...))); err != nil {
+			return nil, err
+		}
+	}
+
+	return uploads, nil
+}
+
+const visibleUploadsForCommitsBatchSize = 10000
+
+const visibleUploadsForCommitsQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/commits.go:visibleUploadsForCommits
+SELECT t.upload_id, t.commit_bytea, t.distance FROM (%s) t
+`
+
+func (s *Store) scanVisibleUploadsForCommits(ctx context.Context, uploads map[string][]commitgraph.UploadMeta, query *sqlf.Query) (err error) {
+	rows, err := s.Store.Query(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	for rows.Next() {
+		var meta commitgraph.UploadMeta
+		var commit dbutil.CommitBytea
+		if err := rows.Scan(&meta.UploadID, &commit, &meta.Distance); err != nil {
+			return err
+		}
+
+		uploads[string(commit)] = append(uploads[string(commit)], meta)
+	}
+
+	return nil
+}
+
 // refineRetentionConfiguration returns the maximum age for no-stale branches and tags, effectively, as configured
 // for the given repository. If there is no retention configuration for the given repository, the given default
 // values are returned unchanged.
@@ -508,16 +799,23 @@ CREATE TEMPORARY TABLE t_lsif_uploads_visible_at_tip (
 `
 
 // persistNearestUploads modifies the lsif_nearest_uploads table so that it has same data
-// as t_lsif_nearest_uploads for the given repository.
-func (s *Store) persistNearestUploads(ctx context.Context, repositoryID int) (err error) {
+// as t_lsif_nearest_uploads for the given repository. If incremental is true, the rows of
+// the commits missing from t_lsif_nearest_uploads are kept, except for the commits that
+// are now in t_lsif_nearest_uploads_links.
+func (s *Store) persistNearestUploads(ctx context.Context, repositoryID int, incremental bool) (err error) {
 	ctx, traceLog, endObservation := s.operations.persistNearestUploads.WithAndLogger(ctx, &err, observation.Args{})
 	defer endObservation(1, observation.Args{})
 
+	deleteQuery := sqlf.Sprintf(nearestUploadsDeleteQuery, repositoryID)
+	if incremental {
+		deleteQuery = sqlf.Sprintf(nearestUploadsIncrementalDeleteQuery, repositoryID)
+	}
+
 	rowsInserted, rowsUpdated, rowsDeleted, err := s.bulkTransfer(
 		ctx,
 		sqlf.Sprintf(nearestUploadsInsertQuery, repositoryID, repositoryID),
 		sqlf.Sprintf(nearestUploadsUpdateQuery, repositoryID),
-		sqlf.Sprintf(nearestUploadsDeleteQuery, repositoryID),
+		deleteQuery,
 	)
 	if err != nil {
 		return err
@@ -558,17 +856,32 @@ WHERE
 	nu.commit_bytea NOT IN (SELECT source.commit_bytea FROM t_lsif_nearest_uploads source)
 `
 
+const nearestUploadsIncrementalDeleteQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/commits.go:persistNearestUploads
+DELETE FROM lsif_nearest_uploads nu
+WHERE
+	nu.repository_id = %s AND
+	nu.commit_bytea IN (SELECT source.commit_bytea FROM t_lsif_nearest_uploads_links source)
+`
+
 // persistNearestUploadsLinks modifies the lsif_nearest_uploads_links table so that it has same
-// data as t_lsif_nearest_uploads_links for the given repository.
-func (s *Store) persistNearestUploadsLinks(ctx context.Context, repositoryID int) (err error) {
+// data as t_lsif_nearest_uploads_links for the given repository. If incremental is true, the
+// rows of the commits missing from t_lsif_nearest_uploads_links are kept, except for the commits
+// that are now in t_lsif_nearest_uploads.
+func (s *Store) persistNearestUploadsLinks(ctx context.Context, repositoryID int, incremental bool) (err error) {
 	ctx, traceLog, endObservation := s.operations.persistNearestUploadsLinks.WithAndLogger(ctx, &err, observation.Args{})
 	defer endObservation(1, observation.Args{})
 
+	deleteQuery := sqlf.Sprintf(nearestUploadsLinksDeleteQuery, repositoryID)
+	if incremental {
+		deleteQuery = sqlf.Sprintf(nearestUploadsLinksIncrementalDeleteQuery, repositoryID)
+	}
+
 	rowsInserted, rowsUpdated, rowsDeleted, err := s.bulkTransfer(
 		ctx,
 		sqlf.Sprintf(nearestUploadsLinksInsertQuery, repositoryID, repositoryID),
 		sqlf.Sprintf(nearestUploadsLinksUpdateQuery, repositoryID),
-		sqlf.Sprintf(nearestUploadsLinksDeleteQuery, repositoryID),
+		deleteQuery,
 	)
 	if err != nil {
 		return err
@@ -610,6 +923,14 @@ WHERE
 	nul.commit_bytea NOT IN (SELECT source.commit_bytea FROM t_lsif_nearest_uploads_links source)
 `
 
+const nearestUploadsLinksIncrementalDeleteQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/commits.go:persistNearestUploadsLinks
+DELETE FROM lsif_nearest_uploads_links nul
+WHERE
+	nul.repository_id = %s AND
+	nul.commit_bytea IN (SELECT source.commit_bytea FROM t_lsif_nearest_uploads source)
+`
+
 // persistUploadsVisibleAtTip modifies the lsif_uploads_visible_at_tip table so that it has same
 // data as t_lsif_uploads_visible_at_tip for the given repository.
 func (s *Store) persistUploadsVisibleAtTip(ctx context.Context, repositoryID int) (err error) {
@@ -779,9 +1100,14 @@ type sanitizedCommitInput struct {
 // sanitizeCommitInput reads the data that needs to be persisted from the given graph and writes the
 // sanitized values (ensures values match the column types) into channels for insertion into a particular
 // table.
+//
+// The visible uploads of the given boundary commits are already persisted and are not written again.
+// Commits that would link to a boundary commit store their visible uploads instead, as the boundary
+// commit may itself be persisted as a link.
 func sanitizeCommitInput(
 	ctx context.Context,
 	graph *commitgraph.Graph,
+	boundaryCommits map[string]struct{},
 	refDescriptions map[string][]gitserver.RefDescription,
 	maxAgeForNonStaleBranches time.Duration,
 	maxAgeForNonStaleTags time.Duration,
@@ -810,6 +1136,10 @@ func sanitizeCommitInput(
 
 		for envelope := range graph.Stream() {
 			if envelope.Uploads != nil {
+				if _, ok := boundaryCommits[envelope.Uploads.Commit]; ok {
+					continue
+				}
+
 				if !countingWrite(
 					ctx,
 					nearestUploadsRowValues,
@@ -823,6 +1153,25 @@ func sanitizeCommitInput(
 			}
 
 			if envelope.Links != nil {
+				if _, ok := boundaryCommits[envelope.Links.Commit]; ok {
+					continue
+				}
+
+				if _, ok := boundaryCommits[envelope.Links.AncestorCommit]; ok {
+					if !countingWrite(
+						ctx,
+						nearestUploadsRowValues,
+						&sanitized.numNearestUploadsRecords,
+						// row values
+						dbutil.CommitBytea(envelope.Links.Commit),
+						listSerializer.Serialize(graph.UploadsVisibleAtCommit(envelope.Links.Commit)),
+					) {
+						return
+					}
+
+					continue
+				}
+
 				if !countingWrite(
 					ctx,
 					nearestUploadsLinksRowValues,
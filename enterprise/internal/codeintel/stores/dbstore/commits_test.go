@@ -571,6 +571,89 @@ func TestCalculateVisibleUploadsResetsDirtyFlag(t *testing.T) {
 	}
 }
 
+func TestCalculateVisibleUploadsIncremental(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := testStore(db)
+
+	// This database has the following commit graph, where commits 4 and 5
+	// are pushed after the first calculation:
+	//
+	// [1] -- 2 -- 3 -- [4] -- 5
+
+	insertUploads(t, db, Upload{ID: 1, Commit: makeCommit(1), Root: "a/"})
+	if err := store.MarkRepositoryAsDirty(context.Background(), 50); err != nil {
+		t.Fatalf("unexpected error marking repository as dirty: %s", err)
+	}
+
+	graph := gitserver.ParseCommitGraph([]string{
+		strings.Join([]string{makeCommit(3), makeCommit(2)}, " "),
+		strings.Join([]string{makeCommit(2), makeCommit(1)}, " "),
+		strings.Join([]string{makeCommit(1)}, " "),
+	})
+	refDescriptions := map[string][]gitserver.RefDescription{
+		makeCommit(3): {{IsDefaultBranch: true}},
+	}
+	if err := store.CalculateVisibleUploads(context.Background(), 50, graph, refDescriptions, time.Hour, time.Hour, 0, time.Time{}); err != nil {
+		t.Fatalf("unexpected error while calculating visible uploads: %s", err)
+	}
+
+	watermark, ok, err := store.CommitGraphWatermark(context.Background(), 50)
+	if err != nil {
+		t.Fatalf("unexpected error getting commit graph watermark: %s", err)
+	}
+	if !ok {
+		t.Fatalf("expected a commit graph watermark")
+	}
+	if diff := cmp.Diff([]string{makeCommit(3)}, watermark); diff != "" {
+		t.Errorf("unexpected watermark (-want +got):\n%s", diff)
+	}
+
+	insertUploads(t, db, Upload{ID: 2, Commit: makeCommit(4), Root: "b/"})
+
+	graph = gitserver.ParseCommitGraph([]string{
+		strings.Join([]string{makeCommit(5), makeCommit(4)}, " "),
+		strings.Join([]string{makeCommit(4), makeCommit(3)}, " "),
+	})
+	refDescriptions = map[string][]gitserver.RefDescription{
+		makeCommit(5): {{IsDefaultBranch: true}},
+	}
+	updated, err := store.CalculateVisibleUploadsIncremental(context.Background(), 50, graph, refDescriptions, time.Hour, time.Hour, 0, time.Time{})
+	if err != nil {
+		t.Fatalf("unexpected error while calculating visible uploads: %s", err)
+	}
+	if !updated {
+		t.Fatalf("expected visible uploads to be calculated incrementally")
+	}
+
+	expectedVisibleUploads := map[string][]int{
+		makeCommit(1): {1},
+		makeCommit(2): {1},
+		makeCommit(3): {1},
+		makeCommit(4): {1, 2},
+		makeCommit(5): {1, 2},
+	}
+	if diff := cmp.Diff(expectedVisibleUploads, getVisibleUploads(t, db, 50, keysOf(expectedVisibleUploads))); diff != "" {
+		t.Errorf("unexpected visible uploads (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int{1, 2}, getUploadsVisibleAtTip(t, db, 50)); diff != "" {
+		t.Errorf("unexpected uploads visible at tip (-want +got):\n%s", diff)
+	}
+
+	// An upload on an old commit changes the visible uploads of existing commits
+	insertUploads(t, db, Upload{ID: 3, Commit: makeCommit(2), Root: "c/"})
+
+	updated, err = store.CalculateVisibleUploadsIncremental(context.Background(), 50, gitserver.ParseCommitGraph(nil), refDescriptions, time.Hour, time.Hour, 0, time.Time{})
+	if err != nil {
+		t.Fatalf("unexpected error while calculating visible uploads: %s", err)
+	}
+	if updated {
+		t.Fatalf("expected visible uploads not to be calculated incrementally")
+	}
+}
+
 func TestCalculateVisibleUploadsNonDefaultBranches(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
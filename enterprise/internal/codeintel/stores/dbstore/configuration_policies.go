@@ -32,6 +32,7 @@ type ConfigurationPolicy struct {
 	RetentionEnabled          bool
 	RetentionDuration         *time.Duration
 	RetainIntermediateCommits bool
+	RetainMostRecentUploads   *int
 	IndexingEnabled           bool
 	IndexCommitMaxAge         *time.Duration
 	IndexIntermediateCommits  bool
@@ -59,6 +60,7 @@ func scanConfigurationPolicies(rows *sql.Rows, queryErr error) (_ []Configuratio
 			&configurationPolicy.RetentionEnabled,
 			&retentionDurationHours,
 			&configurationPolicy.RetainIntermediateCommits,
+			&configurationPolicy.RetainMostRecentUploads,
 			&configurationPolicy.IndexingEnabled,
 			&indexCommitMaxAgeHours,
 			&configurationPolicy.IndexIntermediateCommits,
@@ -148,6 +150,7 @@ SELECT
 	p.retention_enabled,
 	p.retention_duration_hours,
 	p.retain_intermediate_commits,
+	p.retain_most_recent_uploads,
 	p.indexing_enabled,
 	p.index_commit_max_age_hours,
 	p.index_intermediate_commits
@@ -184,6 +187,7 @@ SELECT
 	p.retention_enabled,
 	p.retention_duration_hours,
 	p.retain_intermediate_commits,
+	p.retain_most_recent_uploads,
 	p.indexing_enabled,
 	p.index_commit_max_age_hours,
 	p.index_intermediate_commits
@@ -221,6 +225,7 @@ func (s *Store) CreateConfigurationPolicy(ctx context.Context, configurationPoli
 		configurationPolicy.RetentionEnabled,
 		retentionDurationHours,
 		configurationPolicy.RetainIntermediateCommits,
+		configurationPolicy.RetainMostRecentUploads,
 		configurationPolicy.IndexingEnabled,
 		indexingCommitMaxAgeHours,
 		configurationPolicy.IndexIntermediateCommits,
@@ -242,10 +247,11 @@ INSERT INTO lsif_configuration_policies (
 	retention_enabled,
 	retention_duration_hours,
 	retain_intermediate_commits,
+	retain_most_recent_uploads,
 	indexing_enabled,
 	index_commit_max_age_hours,
 	index_intermediate_commits
-) VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+) VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 RETURNING
 	id,
 	repository_id,
@@ -256,6 +262,7 @@ RETURNING
 	retention_enabled,
 	retention_duration_hours,
 	retain_intermediate_commits,
+	retain_most_recent_uploads,
 	indexing_enabled,
 	index_commit_max_age_hours,
 	index_intermediate_commits
@@ -314,6 +321,7 @@ func (s *Store) UpdateConfigurationPolicy(ctx context.Context, policy Configurat
 		policy.RetentionEnabled,
 		retentionDuration,
 		policy.RetainIntermediateCommits,
+		policy.RetainMostRecentUploads,
 		policy.IndexingEnabled,
 		indexCommitMaxAge,
 		policy.IndexIntermediateCommits,
@@ -333,6 +341,7 @@ SELECT
 	retention_enabled,
 	retention_duration_hours,
 	retain_intermediate_commits,
+	retain_most_recent_uploads,
 	indexing_enabled,
 	index_commit_max_age_hours,
 	index_intermediate_commits
@@ -350,6 +359,7 @@ UPDATE lsif_configuration_policies SET
 	retention_enabled = %s,
 	retention_duration_hours = %s,
 	retain_intermediate_commits = %s,
+	retain_most_recent_uploads = %s,
 	indexing_enabled = %s,
 	index_commit_max_age_hours = %s,
 	index_intermediate_commits = %s
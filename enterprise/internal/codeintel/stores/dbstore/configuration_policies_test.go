@@ -263,6 +263,7 @@ func TestCreateConfigurationPolicy(t *testing.T) {
 	repositoryID := 42
 	d1 := time.Hour * 5
 	d2 := time.Hour * 6
+	retainMostRecentUploads := 3
 
 	configurationPolicy := ConfigurationPolicy{
 		RepositoryID:              &repositoryID,
@@ -272,6 +273,7 @@ func TestCreateConfigurationPolicy(t *testing.T) {
 		RetentionEnabled:          false,
 		RetentionDuration:         &d1,
 		RetainIntermediateCommits: true,
+		RetainMostRecentUploads:   &retainMostRecentUploads,
 		IndexingEnabled:           false,
 		IndexCommitMaxAge:         &d2,
 		IndexIntermediateCommits:  true,
@@ -312,6 +314,7 @@ func TestUpdateConfigurationPolicy(t *testing.T) {
 	repositoryID := 42
 	d1 := time.Hour * 5
 	d2 := time.Hour * 6
+	retainMostRecentUploads := 3
 
 	configurationPolicy := ConfigurationPolicy{
 		RepositoryID:              &repositoryID,
@@ -321,6 +324,7 @@ func TestUpdateConfigurationPolicy(t *testing.T) {
 		RetentionEnabled:          false,
 		RetentionDuration:         &d1,
 		RetainIntermediateCommits: true,
+		RetainMostRecentUploads:   &retainMostRecentUploads,
 		IndexingEnabled:           false,
 		IndexCommitMaxAge:         &d2,
 		IndexIntermediateCommits:  true,
@@ -348,6 +352,7 @@ func TestUpdateConfigurationPolicy(t *testing.T) {
 		RetentionEnabled:          true,
 		RetentionDuration:         &d3,
 		RetainIntermediateCommits: false,
+		RetainMostRecentUploads:   nil,
 		IndexingEnabled:           true,
 		IndexCommitMaxAge:         &d4,
 		IndexIntermediateCommits:  false,
@@ -0,0 +1,268 @@
+package dbstore
+
+import (
+	"context"
+	"database/sql"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// IndexCoverage describes the precise code intelligence coverage of a repository for a single
+// language.
+type IndexCoverage struct {
+	RepositoryID   int
+	RepositoryName string
+	Language       string
+	// Indexers are the names of the indexers that produced uploads or ran index jobs for the language.
+	Indexers []string
+	// UploadAtTip is true if a completed upload can answer queries for the tip of the default branch.
+	UploadAtTip bool
+	// UploadAtTipCommittedAt is the commit date of the most recent upload visible from the tip of the
+	// default branch. It is nil if there is no such upload or if its commit date is not known yet.
+	UploadAtTipCommittedAt *time.Time
+	// LastFailureMessage and LastFailureAt describe the most recent upload or index job that errored
+	// or failed.
+	LastFailureMessage *string
+	LastFailureAt      *time.Time
+}
+
+// Staleness returns how long before the given time the commit of the upload visible from the tip of
+// the default branch was made. The flag is false if there is no such upload.
+func (c IndexCoverage) Staleness(now time.Time) (time.Duration, bool) {
+	if c.UploadAtTipCommittedAt == nil {
+		return 0, false
+	}
+
+	return now.Sub(*c.UploadAtTipCommittedAt), true
+}
+
+// IndexCoverageOptions filters and paginates the result of IndexCoverage.
+type IndexCoverageOptions struct {
+	RepositoryID int
+	Limit        int
+	Offset       int
+}
+
+// IndexCoverage returns the precise code intelligence coverage of repositories with uploads or index
+// jobs, per repository and language, along with the total number of such repositories. Pagination
+// applies to repositories, so that all languages of a repository are always returned together.
+func (s *Store) IndexCoverage(ctx context.Context, opts IndexCoverageOptions) (_ []IndexCoverage, _ int, err error) {
+	ctx, traceLog, endObservation := s.operations.indexCoverage.WithAndLogger(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("repositoryID", opts.RepositoryID),
+		log.Int("limit", opts.Limit),
+		log.Int("offset", opts.Offset),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	tx, err := s.transact(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	conds := make([]*sqlf.Query, 0, 2)
+	if opts.RepositoryID != 0 {
+		conds = append(conds, sqlf.Sprintf("repo.id = %s", opts.RepositoryID))
+	}
+
+	authzConds, err := database.AuthzQueryConds(ctx, tx.Store.Handle().DB())
+	if err != nil {
+		return nil, 0, err
+	}
+	conds = append(conds, authzConds)
+
+	totalCount, _, err := basestore.ScanFirstInt(tx.Store.Query(
+		ctx,
+		sqlf.Sprintf(indexCoverageCountQuery, sqlf.Join(conds, " AND ")),
+	))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	coverage, err := scanIndexCoverage(tx.Store.Query(ctx, sqlf.Sprintf(indexCoverageQuery, sqlf.Join(conds, " AND "), opts.Limit, opts.Offset)))
+	if err != nil {
+		return nil, 0, err
+	}
+	traceLog(
+		log.Int("totalCount", totalCount),
+		log.Int("numCoverage", len(coverage)),
+	)
+
+	return coverage, totalCount, nil
+}
+
+const indexCoverageRepositoriesQueryFragment = `
+SELECT repo.id, repo.name
+FROM repo
+WHERE
+	repo.deleted_at IS NULL AND
+	(
+		EXISTS (SELECT 1 FROM lsif_uploads u WHERE u.repository_id = repo.id AND u.state != 'deleted') OR
+		EXISTS (SELECT 1 FROM lsif_indexes i WHERE i.repository_id = repo.id)
+	) AND
+	%s
+`
+
+const indexCoverageCountQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/coverage.go:IndexCoverage
+SELECT COUNT(*) FROM (` + indexCoverageRepositoriesQueryFragment + `) r
+`
+
+// Note: committed_at is '-infinity' when the commit is no longer known by gitserver.
+const indexCoverageQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/coverage.go:IndexCoverage
+WITH
+repositories AS (
+	` + indexCoverageRepositoriesQueryFragment + `
+	ORDER BY repo.id
+	LIMIT %d OFFSET %d
+),
+uploads_at_tip AS (
+	SELECT u.repository_id, u.indexer, MAX(NULLIF(u.committed_at, '-infinity')) AS committed_at
+	FROM lsif_uploads u
+	JOIN lsif_uploads_visible_at_tip t ON t.repository_id = u.repository_id AND t.upload_id = u.id
+	WHERE
+		u.repository_id IN (SELECT id FROM repositories) AND
+		u.state = 'completed' AND
+		t.is_default_branch
+	GROUP BY u.repository_id, u.indexer
+),
+failures AS (
+	SELECT DISTINCT ON (f.repository_id, f.indexer) f.repository_id, f.indexer, f.failure_message, f.finished_at
+	FROM (
+		SELECT u.repository_id, u.indexer, u.failure_message, u.finished_at
+		FROM lsif_uploads u
+		WHERE u.repository_id IN (SELECT id FROM repositories) AND u.state IN ('errored', 'failed')
+		UNION ALL
+		SELECT i.repository_id, i.indexer, i.failure_message, i.finished_at
+		FROM lsif_indexes i
+		WHERE i.repository_id IN (SELECT id FROM repositories) AND i.state IN ('errored', 'failed')
+	) f
+	ORDER BY f.repository_id, f.indexer, f.finished_at DESC NULLS LAST
+),
+indexers AS (
+	SELECT u.repository_id, u.indexer FROM lsif_uploads u WHERE u.repository_id IN (SELECT id FROM repositories) AND u.state != 'deleted'
+	UNION
+	SELECT i.repository_id, i.indexer FROM lsif_indexes i WHERE i.repository_id IN (SELECT id FROM repositories)
+)
+SELECT
+	r.id,
+	r.name,
+	x.indexer,
+	t.repository_id IS NOT NULL,
+	t.committed_at,
+	f.failure_message,
+	f.finished_at
+FROM repositories r
+JOIN indexers x ON x.repository_id = r.id
+LEFT JOIN uploads_at_tip t ON t.repository_id = x.repository_id AND t.indexer = x.indexer
+LEFT JOIN failures f ON f.repository_id = x.repository_id AND f.indexer = x.indexer
+ORDER BY r.id, x.indexer
+`
+
+// scanIndexCoverage scans the coverage of each repository and indexer from the return value of
+// `*Store.query`, and merges the rows of the indexers of the same language.
+func scanIndexCoverage(rows *sql.Rows, queryErr error) (_ []IndexCoverage, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var coverage []IndexCoverage
+	indexes := map[int]map[string]int{}
+
+	for rows.Next() {
+		var (
+			c       IndexCoverage
+			indexer string
+		)
+		if err := rows.Scan(
+			&c.RepositoryID,
+			&c.RepositoryName,
+			&indexer,
+			&c.UploadAtTip,
+			&c.UploadAtTipCommittedAt,
+			&c.LastFailureMessage,
+			&c.LastFailureAt,
+		); err != nil {
+			return nil, err
+		}
+		c.Language = IndexerLanguage(indexer)
+		c.Indexers = []string{indexer}
+
+		if _, ok := indexes[c.RepositoryID]; !ok {
+			indexes[c.RepositoryID] = map[string]int{}
+		}
+		i, ok := indexes[c.RepositoryID][c.Language]
+		if !ok {
+			indexes[c.RepositoryID][c.Language] = len(coverage)
+			coverage = append(coverage, c)
+			continue
+		}
+
+		merged := &coverage[i]
+		merged.Indexers = append(merged.Indexers, indexer)
+		merged.UploadAtTip = merged.UploadAtTip || c.UploadAtTip
+		if c.UploadAtTipCommittedAt != nil && (merged.UploadAtTipCommittedAt == nil || c.UploadAtTipCommittedAt.After(*merged.UploadAtTipCommittedAt)) {
+			merged.UploadAtTipCommittedAt = c.UploadAtTipCommittedAt
+		}
+		if c.LastFailureAt != nil && (merged.LastFailureAt == nil || c.LastFailureAt.After(*merged.LastFailureAt)) {
+			merged.LastFailureMessage = c.LastFailureMessage
+			merged.LastFailureAt = c.LastFailureAt
+		}
+	}
+
+	sort.SliceStable(coverage, func(i, j int) bool {
+		if coverage[i].RepositoryID != coverage[j].RepositoryID {
+			return coverage[i].RepositoryID < coverage[j].RepositoryID
+		}
+		return coverage[i].Language < coverage[j].Language
+	})
+
+	return coverage, nil
+}
+
+// indexerLanguages maps the names of known indexers to the language they index.
+var indexerLanguages = map[string]string{
+	"lsif-clang":      "cpp",
+	"lsif-cpp":        "cpp",
+	"lsif-dart":       "dart",
+	"lsif-dotnet":     "csharp",
+	"lsif-go":         "go",
+	"lsif-hie":        "haskell",
+	"lsif-java":       "java",
+	"lsif-jsonnet":    "jsonnet",
+	"lsif-node":       "typescript",
+	"lsif-ocaml":      "ocaml",
+	"lsif-php":        "php",
+	"lsif-py":         "python",
+	"lsif-ruby":       "ruby",
+	"lsif-tsc":        "typescript",
+	"lsif-typescript": "typescript",
+	"rust-analyzer":   "rust",
+}
+
+// IndexerLanguage returns the language indexed by the given indexer. The indexer is either the name
+// of the indexer reported by an upload (e.g. lsif-go) or the Docker image of an index job (e.g.
+// sourcegraph/lsif-go:latest). The name of the indexer is returned for unknown indexers.
+func IndexerLanguage(indexer string) string {
+	name := path.Base(indexer)
+	if i := strings.IndexAny(name, ":@"); i >= 0 {
+		name = name[:i]
+	}
+
+	if language, ok := indexerLanguages[name]; ok {
+		return language
+	}
+
+	return name
+}
@@ -0,0 +1,99 @@
+package dbstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
+)
+
+func TestIndexCoverage(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := testStore(db)
+
+	t1 := time.Unix(1587396557, 0).UTC()
+	t2 := t1.Add(time.Hour)
+	t3 := t1.Add(time.Hour * 2)
+	failure1 := "failure 1"
+	failure2 := "failure 2"
+
+	insertUploads(t, db,
+		Upload{ID: 1, RepositoryID: 50, RepositoryName: "n-50", Indexer: "lsif-go"},
+		Upload{ID: 2, RepositoryID: 50, RepositoryName: "n-50", Indexer: "lsif-go"},
+		Upload{ID: 3, RepositoryID: 50, RepositoryName: "n-50", Indexer: "lsif-tsc", State: "errored", FailureMessage: &failure1, FinishedAt: &t1},
+		Upload{ID: 4, RepositoryID: 51, RepositoryName: "n-51", Indexer: "lsif-tsc"},
+		Upload{ID: 5, RepositoryID: 52, RepositoryName: "n-52", Indexer: "lsif-go"},
+	)
+	insertIndexes(t, db,
+		Index{ID: 1, RepositoryID: 50, RepositoryName: "n-50", Indexer: "sourcegraph/lsif-node:autoindex", State: "failed", FailureMessage: &failure2, FinishedAt: &t2},
+	)
+	insertVisibleAtTip(t, db, 50, 1, 2)
+	insertVisibleAtTipNonDefaultBranch(t, db, 51, 4)
+	insertVisibleAtTip(t, db, 52, 5)
+
+	committedAt := map[int]time.Time{1: t1, 2: t3, 4: t2}
+	for id, committedAt := range committedAt {
+		if err := store.Exec(context.Background(), sqlf.Sprintf(`UPDATE lsif_uploads SET committed_at = %s WHERE id = %s`, committedAt, id)); err != nil {
+			t.Fatalf("unexpected error updating committed_at: %s", err)
+		}
+	}
+
+	coverage, totalCount, err := store.IndexCoverage(context.Background(), IndexCoverageOptions{Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error getting index coverage: %s", err)
+	}
+	if totalCount != 3 {
+		t.Errorf("unexpected total count. want=%d have=%d", 3, totalCount)
+	}
+
+	expectedCoverage := []IndexCoverage{
+		{
+			RepositoryID:           50,
+			RepositoryName:         "n-50",
+			Language:               "go",
+			Indexers:               []string{"lsif-go"},
+			UploadAtTip:            true,
+			UploadAtTipCommittedAt: &t3,
+		},
+		{
+			RepositoryID:       50,
+			RepositoryName:     "n-50",
+			Language:           "typescript",
+			Indexers:           []string{"lsif-tsc", "sourcegraph/lsif-node:autoindex"},
+			LastFailureMessage: &failure2,
+			LastFailureAt:      &t2,
+		},
+		{
+			RepositoryID:   51,
+			RepositoryName: "n-51",
+			Language:       "typescript",
+			Indexers:       []string{"lsif-tsc"},
+		},
+	}
+	if diff := cmp.Diff(expectedCoverage, coverage); diff != "" {
+		t.Errorf("unexpected index coverage (-want +got):\n%s", diff)
+	}
+}
+
+func TestIndexerLanguage(t *testing.T) {
+	testCases := map[string]string{
+		"lsif-go":                               "go",
+		"lsif-tsc":                              "typescript",
+		"sourcegraph/lsif-node:autoindex":       "typescript",
+		"sourcegraph/lsif-java@sha256:deadbeef": "java",
+		"lsif-unknown":                          "lsif-unknown",
+	}
+
+	for indexer, expected := range testCases {
+		if language := IndexerLanguage(indexer); language != expected {
+			t.Errorf("unexpected language for %q. want=%q have=%q", indexer, expected, language)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package dbstore
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// IndexRequest is a request to index a repository at a commit on demand. The index jobs queued
+// for the request are tracked so that their progress can be reported back to the user.
+type IndexRequest struct {
+	ID             int        `json:"id"`
+	RepositoryID   int        `json:"repositoryId"`
+	Commit         string     `json:"commit"`
+	Rev            string     `json:"rev"`
+	RequestedAt    time.Time  `json:"requestedAt"`
+	InferredAt     *time.Time `json:"inferredAt"`
+	FailureMessage *string    `json:"failureMessage"`
+	IndexIDs       []int      `json:"indexIds"`
+}
+
+// scanIndexRequests scans a slice of index requests from the return value of `*Store.query`.
+func scanIndexRequests(rows *sql.Rows, queryErr error) (_ []IndexRequest, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var indexRequests []IndexRequest
+	for rows.Next() {
+		var indexRequest IndexRequest
+		var indexIDs []int64
+
+		if err := rows.Scan(
+			&indexRequest.ID,
+			&indexRequest.RepositoryID,
+			&indexRequest.Commit,
+			&indexRequest.Rev,
+			&indexRequest.RequestedAt,
+			&indexRequest.InferredAt,
+			&indexRequest.FailureMessage,
+			pq.Array(&indexIDs),
+		); err != nil {
+			return nil, err
+		}
+
+		indexRequest.IndexIDs = make([]int, 0, len(indexIDs))
+		for _, id := range indexIDs {
+			indexRequest.IndexIDs = append(indexRequest.IndexIDs, int(id))
+		}
+
+		indexRequests = append(indexRequests, indexRequest)
+	}
+
+	return indexRequests, nil
+}
+
+// scanFirstIndexRequest scans a slice of index requests from the return value of `*Store.query`
+// and returns the first.
+func scanFirstIndexRequest(rows *sql.Rows, err error) (IndexRequest, bool, error) {
+	indexRequests, err := scanIndexRequests(rows, err)
+	if err != nil || len(indexRequests) == 0 {
+		return IndexRequest{}, false, err
+	}
+	return indexRequests[0], true, nil
+}
+
+// GetIndexRequestByID returns an index request by its identifier and boolean flag indicating its existence.
+func (s *Store) GetIndexRequestByID(ctx context.Context, id int) (_ IndexRequest, _ bool, err error) {
+	ctx, endObservation := s.operations.getIndexRequestByID.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("id", id),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	authzConds, err := database.AuthzQueryConds(ctx, s.Store.Handle().DB())
+	if err != nil {
+		return IndexRequest{}, false, err
+	}
+
+	return scanFirstIndexRequest(s.Store.Query(ctx, sqlf.Sprintf(getIndexRequestByIDQuery, id, authzConds)))
+}
+
+const getIndexRequestByIDQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/index_requests.go:GetIndexRequestByID
+SELECT
+	r.id,
+	r.repository_id,
+	r.commit,
+	r.rev,
+	r.requested_at,
+	r.inferred_at,
+	r.failure_message,
+	r.index_ids
+FROM lsif_index_requests r
+JOIN repo ON repo.id = r.repository_id
+WHERE r.id = %s AND repo.deleted_at IS NULL AND %s
+`
+
+// InsertIndexRequest inserts a new index request for the given repository and commit and returns its
+// identifier. The request is reported as inferring until MarkIndexRequestInferred is called.
+func (s *Store) InsertIndexRequest(ctx context.Context, repositoryID int, commit, rev string) (_ int, err error) {
+	ctx, endObservation := s.operations.insertIndexRequest.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("repositoryID", repositoryID),
+		log.String("commit", commit),
+		log.String("rev", rev),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	id, _, err := basestore.ScanFirstInt(s.Store.Query(ctx, sqlf.Sprintf(insertIndexRequestQuery, repositoryID, commit, rev)))
+	return id, err
+}
+
+const insertIndexRequestQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/index_requests.go:InsertIndexRequest
+INSERT INTO lsif_index_requests (repository_id, commit, rev) VALUES (%s, %s, %s)
+RETURNING id
+`
+
+// MarkIndexRequestInferred records the index jobs queued for the given index request. A non-nil failure
+// message records why no index jobs could be queued.
+func (s *Store) MarkIndexRequestInferred(ctx context.Context, id int, indexIDs []int, failureMessage *string) (err error) {
+	ctx, endObservation := s.operations.markIndexRequestInferred.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("id", id),
+		log.Int("numIndexIDs", len(indexIDs)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	if indexIDs == nil {
+		indexIDs = []int{}
+	}
+
+	return s.Store.Exec(ctx, sqlf.Sprintf(markIndexRequestInferredQuery, pq.Array(indexIDs), failureMessage, id))
+}
+
+const markIndexRequestInferredQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/index_requests.go:MarkIndexRequestInferred
+UPDATE lsif_index_requests SET inferred_at = NOW(), index_ids = %s, failure_message = %s WHERE id = %s
+`
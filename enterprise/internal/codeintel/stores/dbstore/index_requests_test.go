@@ -0,0 +1,66 @@
+package dbstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
+)
+
+func TestIndexRequests(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := testStore(db)
+
+	insertRepo(t, db, 50, "")
+
+	id, err := store.InsertIndexRequest(context.Background(), 50, makeCommit(1), "main")
+	if err != nil {
+		t.Fatalf("unexpected error inserting index request: %s", err)
+	}
+
+	expected := IndexRequest{
+		ID:           id,
+		RepositoryID: 50,
+		Commit:       makeCommit(1),
+		Rev:          "main",
+		IndexIDs:     []int{},
+	}
+	ignoreTimes := cmpopts.IgnoreFields(IndexRequest{}, "RequestedAt", "InferredAt")
+
+	if indexRequest, exists, err := store.GetIndexRequestByID(context.Background(), id); err != nil {
+		t.Fatalf("unexpected error getting index request: %s", err)
+	} else if !exists {
+		t.Fatal("expected record to exist")
+	} else if indexRequest.InferredAt != nil {
+		t.Errorf("expected request to be inferring")
+	} else if diff := cmp.Diff(expected, indexRequest, ignoreTimes); diff != "" {
+		t.Errorf("unexpected index request (-want +got):\n%s", diff)
+	}
+
+	if err := store.MarkIndexRequestInferred(context.Background(), id, []int{1, 2}, nil); err != nil {
+		t.Fatalf("unexpected error marking index request as inferred: %s", err)
+	}
+
+	expected.IndexIDs = []int{1, 2}
+	if indexRequest, exists, err := store.GetIndexRequestByID(context.Background(), id); err != nil {
+		t.Fatalf("unexpected error getting index request: %s", err)
+	} else if !exists {
+		t.Fatal("expected record to exist")
+	} else if indexRequest.InferredAt == nil {
+		t.Errorf("expected request to be inferred")
+	} else if diff := cmp.Diff(expected, indexRequest, ignoreTimes); diff != "" {
+		t.Errorf("unexpected index request (-want +got):\n%s", diff)
+	}
+
+	if _, exists, err := store.GetIndexRequestByID(context.Background(), id+1); err != nil {
+		t.Fatalf("unexpected error getting index request: %s", err)
+	} else if exists {
+		t.Fatal("unexpected record")
+	}
+}
@@ -532,3 +532,76 @@ deleted AS (
 )
 SELECT d.repository_id, COUNT(*) FROM deleted d GROUP BY d.repository_id
 `
+
+// IndexDequeueLatencyPercentiles returns the given percentiles of the time spent in the queue by the
+// index records that were dequeued since the given time. Zero durations are returned if no record was
+// dequeued.
+func (s *Store) IndexDequeueLatencyPercentiles(ctx context.Context, since time.Time, percentiles []float64) (_ []time.Duration, err error) {
+	ctx, endObservation := s.operations.indexDequeueLatencyPercentiles.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	var seconds pq.Float64Array
+	if err := s.Store.QueryRow(ctx, sqlf.Sprintf(indexDequeueLatencyPercentilesQuery, pq.Array(percentiles), since.UTC())).Scan(&seconds); err != nil {
+		return nil, err
+	}
+
+	latencies := make([]time.Duration, len(percentiles))
+	for i := range latencies {
+		if i < len(seconds) {
+			latencies[i] = time.Duration(seconds[i] * float64(time.Second))
+		}
+	}
+
+	return latencies, nil
+}
+
+const indexDequeueLatencyPercentilesQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/indexes.go:IndexDequeueLatencyPercentiles
+SELECT percentile_cont(%s::float8[]) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM u.started_at - COALESCE(u.process_after, u.queued_at)))
+FROM lsif_indexes u
+WHERE u.started_at >= %s
+`
+
+// IndexerStatistics counts the index records of an indexer that finished processing.
+type IndexerStatistics struct {
+	Indexer   string
+	Completed int
+	Failed    int
+}
+
+// GetIndexerStatistics returns the number of index records per indexer that completed or failed
+// since the given time. Errored records that are retried count as failed.
+func (s *Store) GetIndexerStatistics(ctx context.Context, since time.Time) (_ []IndexerStatistics, err error) {
+	ctx, endObservation := s.operations.getIndexerStatistics.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	rows, err := s.Store.Query(ctx, sqlf.Sprintf(getIndexerStatisticsQuery, since.UTC()))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var statistics []IndexerStatistics
+	for rows.Next() {
+		var stat IndexerStatistics
+		if err := rows.Scan(&stat.Indexer, &stat.Completed, &stat.Failed); err != nil {
+			return nil, err
+		}
+
+		statistics = append(statistics, stat)
+	}
+
+	return statistics, nil
+}
+
+const getIndexerStatisticsQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/indexes.go:GetIndexerStatistics
+SELECT
+	u.indexer,
+	COUNT(*) FILTER (WHERE u.state = 'completed'),
+	COUNT(*) FILTER (WHERE u.state IN ('errored', 'failed'))
+FROM lsif_indexes u
+WHERE u.finished_at >= %s AND u.state IN ('completed', 'errored', 'failed')
+GROUP BY u.indexer
+ORDER BY u.indexer
+`
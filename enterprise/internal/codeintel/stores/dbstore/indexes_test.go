@@ -583,3 +583,73 @@ func TestDeleteIndexesWithoutRepository(t *testing.T) {
 		t.Errorf("unexpected ids (-want +got):\n%s", diff)
 	}
 }
+
+func TestIndexDequeueLatencyPercentiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := testStore(db)
+
+	t1 := time.Unix(1587396557, 0).UTC()
+	t2 := t1.Add(time.Minute * 10)
+	t3 := t1.Add(time.Minute * 20)
+	t4 := t1.Add(time.Minute * 40)
+
+	insertIndexes(t, db,
+		Index{ID: 1, QueuedAt: t1, StartedAt: &t2, State: "processing"},
+		Index{ID: 2, QueuedAt: t1, StartedAt: &t3, State: "completed"},
+		Index{ID: 3, QueuedAt: t1, StartedAt: &t4, State: "completed"},
+		Index{ID: 4, QueuedAt: t1, State: "queued"},                                       // not dequeued
+		Index{ID: 5, QueuedAt: t1, ProcessAfter: &t3, StartedAt: &t4, State: "completed"}, // retried
+	)
+
+	latencies, err := store.IndexDequeueLatencyPercentiles(context.Background(), t1, []float64{0, 1})
+	if err != nil {
+		t.Fatalf("unexpected error getting latencies: %s", err)
+	}
+	if diff := cmp.Diff([]time.Duration{time.Minute * 10, time.Minute * 40}, latencies); diff != "" {
+		t.Errorf("unexpected latencies (-want +got):\n%s", diff)
+	}
+
+	latencies, err = store.IndexDequeueLatencyPercentiles(context.Background(), t4.Add(time.Minute), []float64{0.5})
+	if err != nil {
+		t.Fatalf("unexpected error getting latencies: %s", err)
+	}
+	if diff := cmp.Diff([]time.Duration{0}, latencies); diff != "" {
+		t.Errorf("unexpected latencies (-want +got):\n%s", diff)
+	}
+}
+
+func TestGetIndexerStatistics(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := testStore(db)
+
+	t1 := time.Unix(1587396557, 0).UTC()
+	t2 := t1.Add(time.Minute)
+
+	insertIndexes(t, db,
+		Index{ID: 1, Indexer: "sourcegraph/lsif-go", FinishedAt: &t2, State: "completed"},
+		Index{ID: 2, Indexer: "sourcegraph/lsif-go", FinishedAt: &t2, State: "failed"},
+		Index{ID: 3, Indexer: "sourcegraph/lsif-go", FinishedAt: &t2, State: "completed"},
+		Index{ID: 4, Indexer: "sourcegraph/lsif-node", FinishedAt: &t2, State: "errored"},
+		Index{ID: 5, Indexer: "sourcegraph/lsif-node", State: "processing"},
+		Index{ID: 6, Indexer: "sourcegraph/lsif-java", FinishedAt: &t1, State: "failed"}, // too old
+	)
+
+	statistics, err := store.GetIndexerStatistics(context.Background(), t2)
+	if err != nil {
+		t.Fatalf("unexpected error getting indexer statistics: %s", err)
+	}
+
+	expected := []IndexerStatistics{
+		{Indexer: "sourcegraph/lsif-go", Completed: 2, Failed: 1},
+		{Indexer: "sourcegraph/lsif-node", Completed: 0, Failed: 1},
+	}
+	if diff := cmp.Diff(expected, statistics); diff != "" {
+		t.Errorf("unexpected statistics (-want +got):\n%s", diff)
+	}
+}
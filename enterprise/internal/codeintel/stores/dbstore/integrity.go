@@ -0,0 +1,188 @@
+package dbstore
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// MissingDataFailureMessage is the failure message of completed uploads whose data was found to be
+// missing from the codeintel database.
+const MissingDataFailureMessage = "upload data is missing from the codeintel database"
+
+// CompletedUploadIDs returns the identifiers of completed uploads that are greater than the given
+// identifier, in ascending order. This is used to page through all completed uploads.
+func (s *Store) CompletedUploadIDs(ctx context.Context, afterID, limit int) (_ []int, err error) {
+	ctx, traceLog, endObservation := s.operations.completedUploadIDs.WithAndLogger(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("afterID", afterID),
+		log.Int("limit", limit),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	ids, err := basestore.ScanInts(s.Store.Query(ctx, sqlf.Sprintf(completedUploadIDsQuery, afterID, limit)))
+	if err != nil {
+		return nil, err
+	}
+	traceLog(log.Int("numUploadIDs", len(ids)))
+
+	return ids, nil
+}
+
+const completedUploadIDsQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/integrity.go:CompletedUploadIDs
+SELECT id FROM lsif_uploads WHERE id > %s AND state = 'completed' ORDER BY id LIMIT %s
+`
+
+// UnknownUploadIDs returns the given identifiers that do not belong to any upload record, regardless
+// of its state, in ascending order. Bundles in the codeintel database with such an identifier are
+// orphaned: no upload record refers to them anymore, so they are never cleared by the janitor.
+func (s *Store) UnknownUploadIDs(ctx context.Context, ids []int) (_ []int, err error) {
+	ctx, traceLog, endObservation := s.operations.unknownUploadIDs.WithAndLogger(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("numIDs", len(ids)),
+		log.String("ids", intsToString(ids)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	unknownIDs, err := basestore.ScanInts(s.Store.Query(ctx, sqlf.Sprintf(unknownUploadIDsQuery, pq.Array(ids))))
+	if err != nil {
+		return nil, err
+	}
+	traceLog(log.Int("numUnknownIDs", len(unknownIDs)))
+
+	return unknownIDs, nil
+}
+
+const unknownUploadIDsQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/integrity.go:UnknownUploadIDs
+SELECT t.id FROM unnest(%s::integer[]) AS t(id)
+WHERE NOT EXISTS (SELECT 1 FROM lsif_uploads u WHERE u.id = t.id)
+ORDER BY t.id
+`
+
+// MarkUploadsMissingData marks the given completed uploads as errored because their data is missing from
+// the codeintel database, so that they no longer answer code intelligence queries. The index records that
+// produced these uploads are requeued so that the uploads are re-created, and the commit graph of each
+// affected repository is marked as dirty. This method returns the number of uploads marked as errored and
+// the number of index records requeued.
+func (s *Store) MarkUploadsMissingData(ctx context.Context, ids []int, now time.Time) (_, _ int, err error) {
+	ctx, traceLog, endObservation := s.operations.markUploadsMissingData.WithAndLogger(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("numIDs", len(ids)),
+		log.String("ids", intsToString(ids)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	if len(ids) == 0 {
+		return 0, 0, nil
+	}
+
+	tx, err := s.transact(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	repositoryIDs, indexIDs, err := scanMissingDataUploads(tx.Store.Query(ctx, sqlf.Sprintf(markUploadsMissingDataQuery, pq.Array(ids), MissingDataFailureMessage, now)))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	numUploads := 0
+	for _, n := range repositoryIDs {
+		numUploads += n
+	}
+
+	numRequeued := 0
+	if len(indexIDs) > 0 {
+		if numRequeued, _, err = basestore.ScanFirstInt(tx.Store.Query(ctx, sqlf.Sprintf(requeueIndexesMissingDataQuery, pq.Array(indexIDs)))); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	ids = make([]int, 0, len(repositoryIDs))
+	for repositoryID := range repositoryIDs {
+		ids = append(ids, repositoryID)
+	}
+	sort.Ints(ids)
+
+	for _, repositoryID := range ids {
+		if err := tx.MarkRepositoryAsDirty(ctx, repositoryID); err != nil {
+			return 0, 0, err
+		}
+	}
+	traceLog(
+		log.Int("numUploads", numUploads),
+		log.Int("numRequeued", numRequeued),
+	)
+
+	return numUploads, numRequeued, nil
+}
+
+const markUploadsMissingDataQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/integrity.go:MarkUploadsMissingData
+WITH candidates AS (
+	SELECT id FROM lsif_uploads
+	WHERE id = ANY(%s) AND state = 'completed'
+	ORDER BY id FOR UPDATE
+)
+UPDATE lsif_uploads u
+SET state = 'errored', failure_message = %s, finished_at = %s
+WHERE u.id IN (SELECT id FROM candidates)
+RETURNING u.repository_id, u.associated_index_id
+`
+
+const requeueIndexesMissingDataQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/integrity.go:MarkUploadsMissingData
+WITH requeued AS (
+	UPDATE lsif_indexes
+	SET
+		state = 'queued',
+		failure_message = NULL,
+		started_at = NULL,
+		finished_at = NULL,
+		process_after = NULL,
+		num_resets = 0,
+		num_failures = 0
+	WHERE id = ANY(%s) AND state = 'completed'
+	RETURNING 1
+)
+SELECT COUNT(*) FROM requeued
+`
+
+// scanMissingDataUploads scans the repository and associated index identifiers of the uploads marked by
+// MarkUploadsMissingData. The repository identifiers are mapped to the number of uploads marked within
+// that repository.
+func scanMissingDataUploads(rows *sql.Rows, queryErr error) (_ map[int]int, _ []int, err error) {
+	if queryErr != nil {
+		return nil, nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	repositoryIDs := map[int]int{}
+	var indexIDs []int
+	for rows.Next() {
+		var repositoryID int
+		var indexID *int
+		if err := rows.Scan(&repositoryID, &indexID); err != nil {
+			return nil, nil, err
+		}
+
+		repositoryIDs[repositoryID]++
+		if indexID != nil {
+			indexIDs = append(indexIDs, *indexID)
+		}
+	}
+
+	return repositoryIDs, indexIDs, nil
+}
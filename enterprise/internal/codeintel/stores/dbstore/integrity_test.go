@@ -0,0 +1,136 @@
+package dbstore
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
+)
+
+func TestCompletedUploadIDs(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := testStore(db)
+
+	insertUploads(t, db,
+		Upload{ID: 1},
+		Upload{ID: 2, State: "errored"},
+		Upload{ID: 3},
+		Upload{ID: 4, State: "queued"},
+		Upload{ID: 5},
+		Upload{ID: 6},
+	)
+
+	testCases := []struct {
+		afterID  int
+		limit    int
+		expected []int
+	}{
+		{0, 10, []int{1, 3, 5, 6}},
+		{0, 2, []int{1, 3}},
+		{3, 2, []int{5, 6}},
+		{6, 2, nil},
+	}
+
+	for _, testCase := range testCases {
+		ids, err := store.CompletedUploadIDs(context.Background(), testCase.afterID, testCase.limit)
+		if err != nil {
+			t.Fatalf("unexpected error getting completed upload ids: %s", err)
+		}
+		if diff := cmp.Diff(testCase.expected, ids); diff != "" {
+			t.Errorf("unexpected upload ids after %d (-want +got):\n%s", testCase.afterID, diff)
+		}
+	}
+}
+
+func TestUnknownUploadIDs(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := testStore(db)
+
+	insertUploads(t, db,
+		Upload{ID: 1},
+		Upload{ID: 2, State: "deleting"},
+		Upload{ID: 3, State: "errored"},
+	)
+
+	ids, err := store.UnknownUploadIDs(context.Background(), []int{1, 2, 3, 4, 5})
+	if err != nil {
+		t.Fatalf("unexpected error getting unknown upload ids: %s", err)
+	}
+	if diff := cmp.Diff([]int{4, 5}, ids); diff != "" {
+		t.Errorf("unexpected upload ids (-want +got):\n%s", diff)
+	}
+}
+
+func TestMarkUploadsMissingData(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := testStore(db)
+
+	indexID1, indexID2 := 10, 11
+	insertIndexes(t, db,
+		Index{ID: 10},
+		Index{ID: 11, State: "queued"},
+	)
+	insertUploads(t, db,
+		Upload{ID: 1, RepositoryID: 50, AssociatedIndexID: &indexID1},
+		Upload{ID: 2, RepositoryID: 51, AssociatedIndexID: &indexID2},
+		Upload{ID: 3, RepositoryID: 52, State: "deleting"},
+		Upload{ID: 4, RepositoryID: 53},
+	)
+
+	numUploads, numRequeued, err := store.MarkUploadsMissingData(context.Background(), []int{1, 2, 3}, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error marking uploads: %s", err)
+	}
+	if numUploads != 2 {
+		t.Errorf("unexpected number of uploads marked. want=%d have=%d", 2, numUploads)
+	}
+	if numRequeued != 1 {
+		t.Errorf("unexpected number of indexes requeued. want=%d have=%d", 1, numRequeued)
+	}
+
+	uploadStates, err := getUploadStates(db, 1, 2, 3, 4)
+	if err != nil {
+		t.Fatalf("unexpected error getting upload states: %s", err)
+	}
+	expectedUploadStates := map[int]string{1: "errored", 2: "errored", 3: "deleting", 4: "completed"}
+	if diff := cmp.Diff(expectedUploadStates, uploadStates); diff != "" {
+		t.Errorf("unexpected upload states (-want +got):\n%s", diff)
+	}
+
+	indexStates, err := getIndexStates(db, 10, 11)
+	if err != nil {
+		t.Fatalf("unexpected error getting index states: %s", err)
+	}
+	expectedIndexStates := map[int]string{10: "queued", 11: "queued"}
+	if diff := cmp.Diff(expectedIndexStates, indexStates); diff != "" {
+		t.Errorf("unexpected index states (-want +got):\n%s", diff)
+	}
+
+	repositoryIDs, err := store.DirtyRepositories(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error listing dirty repositories: %s", err)
+	}
+
+	var keys []int
+	for repositoryID := range repositoryIDs {
+		keys = append(keys, repositoryID)
+	}
+	sort.Ints(keys)
+
+	if diff := cmp.Diff([]int{50, 51}, keys); diff != "" {
+		t.Errorf("unexpected dirty repository ids (-want +got):\n%s", diff)
+	}
+}
@@ -10,8 +10,11 @@ import (
 type operations struct {
 	addUploadPart                          *observation.Operation
 	calculateVisibleUploads                *observation.Operation
+	calculateVisibleUploadsIncremental     *observation.Operation
 	commitGraphMetadata                    *observation.Operation
+	commitGraphWatermark                   *observation.Operation
 	commitsVisibleToUpload                 *observation.Operation
+	completedUploadIDs                     *observation.Operation
 	createConfigurationPolicy              *observation.Operation
 	definitionDumps                        *observation.Operation
 	deleteConfigurationPolicyByID          *observation.Operation
@@ -31,8 +34,10 @@ type operations struct {
 	getDumpsByIDs                          *observation.Operation
 	getIndexByID                           *observation.Operation
 	getIndexConfigurationByRepositoryID    *observation.Operation
+	getIndexRequestByID                    *observation.Operation
 	getIndexes                             *observation.Operation
 	getIndexesByIDs                        *observation.Operation
+	getIndexerStatistics                   *observation.Operation
 	getOldestCommitDate                    *observation.Operation
 	getUploadByID                          *observation.Operation
 	getUploads                             *observation.Operation
@@ -40,11 +45,14 @@ type operations struct {
 	hardDeleteUploadByID                   *observation.Operation
 	hasCommit                              *observation.Operation
 	hasRepository                          *observation.Operation
+	indexCoverage                          *observation.Operation
+	indexDequeueLatencyPercentiles         *observation.Operation
 	indexQueueSize                         *observation.Operation
 	insertCloneableDependencyRepo          *observation.Operation
 	insertDependencyIndexingJob            *observation.Operation
 	insertDependencySyncingJob             *observation.Operation
 	insertIndex                            *observation.Operation
+	insertIndexRequest                     *observation.Operation
 	insertUpload                           *observation.Operation
 	isQueued                               *observation.Operation
 	markComplete                           *observation.Operation
@@ -52,19 +60,25 @@ type operations struct {
 	markFailed                             *observation.Operation
 	markIndexComplete                      *observation.Operation
 	markIndexErrored                       *observation.Operation
+	markIndexRequestInferred               *observation.Operation
 	markQueued                             *observation.Operation
 	markRepositoryAsDirty                  *observation.Operation
+	markUploadsMissingData                 *observation.Operation
 	queueSize                              *observation.Operation
+	recentUploadRanks                      *observation.Operation
+	referenceCountsAtTip                   *observation.Operation
 	referenceIDsAndFilters                 *observation.Operation
 	referencesForUpload                    *observation.Operation
 	refreshCommitResolvability             *observation.Operation
 	repoName                               *observation.Operation
 	requeue                                *observation.Operation
 	requeueIndex                           *observation.Operation
+	resetStalledUploads                    *observation.Operation
 	selectRepositoriesForIndexScan         *observation.Operation
 	selectRepositoriesForRetentionScan     *observation.Operation
 	softDeleteExpiredUploads               *observation.Operation
 	staleSourcedCommits                    *observation.Operation
+	unknownUploadIDs                       *observation.Operation
 	updateCommitedAt                       *observation.Operation
 	updateConfigurationPolicy              *observation.Operation
 	updateDependencyNumReferences          *observation.Operation
@@ -101,8 +115,11 @@ func newOperations(observationContext *observation.Context, metrics *metrics.Ope
 	return &operations{
 		addUploadPart:                          op("AddUploadPart"),
 		calculateVisibleUploads:                op("CalculateVisibleUploads"),
+		calculateVisibleUploadsIncremental:     op("CalculateVisibleUploadsIncremental"),
 		commitGraphMetadata:                    op("CommitGraphMetadata"),
+		commitGraphWatermark:                   op("CommitGraphWatermark"),
 		commitsVisibleToUpload:                 op("CommitsVisibleToUpload"),
+		completedUploadIDs:                     op("CompletedUploadIDs"),
 		createConfigurationPolicy:              op("CreateConfigurationPolicy"),
 		definitionDumps:                        op("DefinitionDumps"),
 		deleteConfigurationPolicyByID:          op("DeleteConfigurationPolicyByID"),
@@ -122,8 +139,10 @@ func newOperations(observationContext *observation.Context, metrics *metrics.Ope
 		getDumpsByIDs:                          op("GetDumpsByIDs"),
 		getIndexByID:                           op("GetIndexByID"),
 		getIndexConfigurationByRepositoryID:    op("GetIndexConfigurationByRepositoryID"),
+		getIndexRequestByID:                    op("GetIndexRequestByID"),
 		getIndexes:                             op("GetIndexes"),
 		getIndexesByIDs:                        op("GetIndexesByIDs"),
+		getIndexerStatistics:                   op("GetIndexerStatistics"),
 		getOldestCommitDate:                    op("GetOldestCommitDate"),
 		getUploadByID:                          op("GetUploadByID"),
 		getUploads:                             op("GetUploads"),
@@ -131,11 +150,14 @@ func newOperations(observationContext *observation.Context, metrics *metrics.Ope
 		hardDeleteUploadByID:                   op("HardDeleteUploadByID"),
 		hasCommit:                              op("HasCommit"),
 		hasRepository:                          op("HasRepository"),
+		indexCoverage:                          op("IndexCoverage"),
+		indexDequeueLatencyPercentiles:         op("IndexDequeueLatencyPercentiles"),
 		indexQueueSize:                         op("IndexQueueSize"),
 		insertCloneableDependencyRepo:          op("InsertCloneableDependencyRepo"),
 		insertDependencyIndexingJob:            op("InsertDependencyIndexingJob"),
 		insertDependencySyncingJob:             op("InsertDependencySyncingJob"),
 		insertIndex:                            op("InsertIndex"),
+		insertIndexRequest:                     op("InsertIndexRequest"),
 		insertUpload:                           op("InsertUpload"),
 		isQueued:                               op("IsQueued"),
 		markComplete:                           op("MarkComplete"),
@@ -143,19 +165,25 @@ func newOperations(observationContext *observation.Context, metrics *metrics.Ope
 		markFailed:                             op("MarkFailed"),
 		markIndexComplete:                      op("MarkIndexComplete"),
 		markIndexErrored:                       op("MarkIndexErrored"),
+		markIndexRequestInferred:               op("MarkIndexRequestInferred"),
 		markQueued:                             op("MarkQueued"),
 		markRepositoryAsDirty:                  op("MarkRepositoryAsDirty"),
+		markUploadsMissingData:                 op("MarkUploadsMissingData"),
 		queueSize:                              op("QueueSize"),
+		recentUploadRanks:                      op("RecentUploadRanks"),
+		referenceCountsAtTip:                   op("ReferenceCountsAtTip"),
 		referenceIDsAndFilters:                 op("ReferenceIDsAndFilters"),
 		referencesForUpload:                    op("ReferencesForUpload"),
 		refreshCommitResolvability:             op("RefreshCommitResolvability"),
 		repoName:                               op("RepoName"),
 		requeue:                                op("Requeue"),
 		requeueIndex:                           op("RequeueIndex"),
+		resetStalledUploads:                    op("ResetStalledUploads"),
 		selectRepositoriesForIndexScan:         op("SelectRepositoriesForIndexScan"),
 		selectRepositoriesForRetentionScan:     op("SelectRepositoriesForRetentionScan"),
 		softDeleteExpiredUploads:               op("SoftDeleteExpiredUploads"),
 		staleSourcedCommits:                    op("StaleSourcedCommits"),
+		unknownUploadIDs:                       op("UnknownUploadIDs"),
 		updateCommitedAt:                       op("UpdateCommitedAt"),
 		updateConfigurationPolicy:              op("UpdateConfigurationPolicy"),
 		updateDependencyNumReferences:          op("UpdateDependencyNumReferences"),
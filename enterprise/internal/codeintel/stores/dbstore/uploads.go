@@ -48,6 +48,36 @@ func (u Upload) RecordID() int {
 	return u.ID
 }
 
+// scanStalledUploads scans a slice of stalled uploads from the return value of `*Store.query`.
+func scanStalledUploads(rows *sql.Rows, queryErr error) (_ []StalledUpload, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var uploads []StalledUpload
+	for rows.Next() {
+		var upload StalledUpload
+		var lastHeartbeatAt *time.Time
+		if err := rows.Scan(
+			&upload.ID,
+			&upload.WorkerHostname,
+			&lastHeartbeatAt,
+			&upload.NumResets,
+			&upload.Failed,
+		); err != nil {
+			return nil, err
+		}
+		if lastHeartbeatAt != nil {
+			upload.LastHeartbeatAt = *lastHeartbeatAt
+		}
+
+		uploads = append(uploads, upload)
+	}
+
+	return uploads, nil
+}
+
 // scanUploads scans a slice of uploads from the return value of `*Store.query`.
 func scanUploads(rows *sql.Rows, queryErr error) (_ []Upload, err error) {
 	if queryErr != nil {
@@ -278,6 +308,86 @@ deleted AS (
 SELECT count(*) FROM deleted
 `
 
+// StalledUploadResetFailureMessage is the failure message of uploads that have been reset too many times
+// by ResetStalledUploads.
+const StalledUploadResetFailureMessage = "upload processor died while handling this upload too many times"
+
+// StalledUpload is an upload that was left in the processing state by a worker that stopped sending
+// heartbeats, and which has been reset by ResetStalledUploads.
+type StalledUpload struct {
+	ID              int
+	WorkerHostname  string
+	LastHeartbeatAt time.Time
+	NumResets       int
+	// Failed is true if the upload has been reset too many times and was moved into the failed state
+	// rather than requeued.
+	Failed bool
+}
+
+// ResetStalledUploads requeues every upload being processed whose worker has not sent a heartbeat since
+// the given time, which likely indicates that the worker died while processing it. Unlike the uploads
+// stuck uploading, these uploads are not deleted: their data is still available in the upload store and
+// they can be processed again. In order to prevent input that continually crashes workers, uploads that
+// have already been reset UploadMaxNumResets times are marked as failed instead.
+func (s *Store) ResetStalledUploads(ctx context.Context, lastHeartbeatBefore time.Time) (_ []StalledUpload, err error) {
+	ctx, traceLog, endObservation := s.operations.resetStalledUploads.WithAndLogger(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.String("lastHeartbeatBefore", lastHeartbeatBefore.Format(time.RFC3339)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	uploads, err := scanStalledUploads(s.Store.Query(ctx, sqlf.Sprintf(
+		resetStalledUploadsQuery,
+		UploadMaxNumResets,
+		lastHeartbeatBefore,
+		StalledUploadResetFailureMessage,
+	)))
+	if err != nil {
+		return nil, err
+	}
+
+	numFailed := 0
+	for _, upload := range uploads {
+		if upload.Failed {
+			numFailed++
+		}
+	}
+	traceLog(
+		log.Int("numReset", len(uploads)-numFailed),
+		log.Int("numFailed", numFailed),
+	)
+
+	return uploads, nil
+}
+
+const resetStalledUploadsQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/uploads.go:ResetStalledUploads
+WITH
+candidates AS (
+	SELECT u.id, u.num_resets >= %s AS exhausted
+	FROM lsif_uploads u
+	WHERE u.state = 'processing' AND COALESCE(u.last_heartbeat_at, u.started_at) < %s
+
+	-- Lock these rows in a deterministic order so that we don't
+	-- deadlock with other processes updating the lsif_uploads table.
+	ORDER BY u.id FOR UPDATE SKIP LOCKED
+),
+updated AS (
+	UPDATE lsif_uploads u
+	SET
+		state = CASE WHEN c.exhausted THEN 'failed' ELSE 'queued' END,
+		started_at = CASE WHEN c.exhausted THEN u.started_at END,
+		num_resets = CASE WHEN c.exhausted THEN u.num_resets ELSE u.num_resets + 1 END,
+		finished_at = CASE WHEN c.exhausted THEN clock_timestamp() END,
+		failure_message = CASE WHEN c.exhausted THEN %s END
+	FROM candidates c
+	WHERE u.id = c.id
+	RETURNING u.id, u.worker_hostname, u.last_heartbeat_at, u.num_resets, u.state
+)
+SELECT id, worker_hostname, last_heartbeat_at, num_resets, state = 'failed'
+FROM updated
+ORDER BY id
+`
+
 type GetUploadsOptions struct {
 	RepositoryID            int
 	State                   string
@@ -880,6 +990,37 @@ const updateUploadRetentionQuery = `
 -- source: enterprise/internal/codeintel/stores/dbstore/uploads.go:UpdateUploadRetention
 UPDATE lsif_uploads SET %s WHERE id IN (%s)`
 
+// RecentUploadRanks returns the rank of the most recent completed and unexpired uploads of the given
+// repository within their root and indexer, keyed by upload identifier. The most recent upload for
+// each root and indexer has rank one. Uploads ranked beyond the given limit are not returned.
+func (s *Store) RecentUploadRanks(ctx context.Context, repositoryID, limit int) (_ map[int]int, err error) {
+	ctx, traceLog, endObservation := s.operations.recentUploadRanks.WithAndLogger(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("repositoryID", repositoryID),
+		log.Int("limit", limit),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	ranks, err := scanIntPairs(s.Store.Query(ctx, sqlf.Sprintf(recentUploadRanksQuery, repositoryID, limit)))
+	if err != nil {
+		return nil, err
+	}
+	traceLog(log.Int("numRanks", len(ranks)))
+
+	return ranks, nil
+}
+
+const recentUploadRanksQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/uploads.go:RecentUploadRanks
+SELECT r.id, r.rank FROM (
+	SELECT
+		u.id,
+		ROW_NUMBER() OVER (PARTITION BY u.root, u.indexer ORDER BY u.uploaded_at DESC, u.id DESC) AS rank
+	FROM lsif_uploads u
+	WHERE u.repository_id = %s AND u.state = 'completed' AND NOT u.expired
+) r
+WHERE r.rank <= %s
+`
+
 // UpdateNumReferences calculates the number of existant uploads that reference any
 // of the given upload identifiers and updates the num_references field of each
 // upload.
@@ -929,6 +1070,66 @@ SET num_references = COALESCE((SELECT rc.count FROM reference_counts rc WHERE rc
 WHERE u.id IN (SELECT id FROM locked_uploads)
 `
 
+// ReferenceCountsAtTip returns the number of references from other uploads to the uploads
+// visible at the tip of the default branch of the given repositories. The counts are keyed
+// by repository identifier and then by the root of the uploads, so that they can be used to
+// rank the code within the repositories.
+func (s *Store) ReferenceCountsAtTip(ctx context.Context, repositoryIDs []int) (_ map[int]map[string]int, err error) {
+	ctx, endObservation := s.operations.referenceCountsAtTip.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("numRepositoryIDs", len(repositoryIDs)),
+		log.String("repositoryIDs", intsToString(repositoryIDs)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	if len(repositoryIDs) == 0 {
+		return nil, nil
+	}
+
+	queries := make([]*sqlf.Query, 0, len(repositoryIDs))
+	for _, id := range repositoryIDs {
+		queries = append(queries, sqlf.Sprintf("%s", id))
+	}
+
+	return scanReferenceCounts(s.Query(ctx, sqlf.Sprintf(referenceCountsAtTipQuery, sqlf.Join(queries, ", "))))
+}
+
+const referenceCountsAtTipQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/uploads.go:ReferenceCountsAtTip
+SELECT u.repository_id, u.root, SUM(u.num_references)
+FROM lsif_uploads u
+JOIN lsif_uploads_visible_at_tip t ON t.upload_id = u.id
+WHERE
+	t.repository_id IN (%s) AND
+	t.is_default_branch AND
+	u.num_references > 0
+GROUP BY u.repository_id, u.root
+`
+
+// scanReferenceCounts scans triples of repository id/root/counts from the return value of `*Store.query`.
+func scanReferenceCounts(rows *sql.Rows, queryErr error) (_ map[int]map[string]int, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	counts := map[int]map[string]int{}
+	for rows.Next() {
+		var repositoryID int
+		var root string
+		var count int
+		if err := rows.Scan(&repositoryID, &root, &count); err != nil {
+			return nil, err
+		}
+
+		if _, ok := counts[repositoryID]; !ok {
+			counts[repositoryID] = map[string]int{}
+		}
+		counts[repositoryID][root] = count
+	}
+
+	return counts, nil
+}
+
 // UpdateDependencyNumReferences increments (or decrements) the number of references for
 // each dependency of the uploads with any of the given identifiers.
 func (s *Store) UpdateDependencyNumReferences(ctx context.Context, ids []int, decrement bool) (err error) {
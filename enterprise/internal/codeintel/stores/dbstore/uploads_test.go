@@ -285,6 +285,68 @@ func TestDeleteUploadsStuckUploading(t *testing.T) {
 	}
 }
 
+func TestResetStalledUploads(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := testStore(db)
+
+	t1 := time.Unix(1587396557, 0).UTC()
+	t2 := t1.Add(time.Minute * 1)
+	t3 := t1.Add(time.Minute * 2)
+
+	insertUploads(t, db,
+		Upload{ID: 1, State: "queued"},
+		Upload{ID: 2, State: "processing"},                                // reset
+		Upload{ID: 3, State: "processing"},                                // recent heartbeat
+		Upload{ID: 4, State: "processing", NumResets: UploadMaxNumResets}, // failed
+		Upload{ID: 5, State: "completed"},
+	)
+
+	heartbeats := map[int]time.Time{1: t1, 2: t1, 3: t3, 4: t2, 5: t1}
+	for id, lastHeartbeatAt := range heartbeats {
+		query := sqlf.Sprintf(
+			"UPDATE lsif_uploads SET worker_hostname = %s, last_heartbeat_at = %s WHERE id = %s",
+			fmt.Sprintf("worker-%d", id),
+			lastHeartbeatAt,
+			id,
+		)
+		if _, err := db.ExecContext(context.Background(), query.Query(sqlf.PostgresBindVar), query.Args()...); err != nil {
+			t.Fatalf("unexpected error updating heartbeat: %s", err)
+		}
+	}
+
+	stalledUploads, err := store.ResetStalledUploads(context.Background(), t3)
+	if err != nil {
+		t.Fatalf("unexpected error resetting stalled uploads: %s", err)
+	}
+
+	expectedStalledUploads := []StalledUpload{
+		{ID: 2, WorkerHostname: "worker-2", LastHeartbeatAt: t1, NumResets: 1},
+		{ID: 4, WorkerHostname: "worker-4", LastHeartbeatAt: t2, NumResets: UploadMaxNumResets, Failed: true},
+	}
+	if diff := cmp.Diff(expectedStalledUploads, stalledUploads); diff != "" {
+		t.Errorf("unexpected stalled uploads (-want +got):\n%s", diff)
+	}
+
+	states, err := getUploadStates(db, 1, 2, 3, 4, 5)
+	if err != nil {
+		t.Fatalf("unexpected error getting states: %s", err)
+	}
+
+	expectedStates := map[int]string{
+		1: "queued",
+		2: "queued",
+		3: "processing",
+		4: "failed",
+		5: "completed",
+	}
+	if diff := cmp.Diff(expectedStates, states); diff != "" {
+		t.Errorf("unexpected upload states (-want +got):\n%s", diff)
+	}
+}
+
 func TestGetUploads(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
@@ -1073,6 +1135,45 @@ func TestUpdateUploadRetention(t *testing.T) {
 	}
 }
 
+func TestRecentUploadRanks(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := testStore(db)
+
+	t1 := time.Unix(1587396557, 0).UTC()
+	t2 := t1.Add(time.Minute)
+	t3 := t1.Add(time.Minute * 2)
+
+	insertUploads(t, db,
+		Upload{ID: 1, Root: "a/", UploadedAt: t1},
+		Upload{ID: 2, Root: "a/", UploadedAt: t2},
+		Upload{ID: 3, Root: "a/", UploadedAt: t3},
+		Upload{ID: 4, Root: "b/", UploadedAt: t1},
+		Upload{ID: 5, Root: "b/", UploadedAt: t2},
+		Upload{ID: 6, Root: "a/", Indexer: "lsif-tsc", UploadedAt: t1},
+		Upload{ID: 7, Root: "a/", UploadedAt: t3, State: "queued"},
+		Upload{ID: 8, Root: "a/", UploadedAt: t3, RepositoryID: 51},
+	)
+
+	ranks, err := store.RecentUploadRanks(context.Background(), 50, 2)
+	if err != nil {
+		t.Fatalf("unexpected error getting recent upload ranks: %s", err)
+	}
+
+	expectedRanks := map[int]int{
+		2: 2,
+		3: 1,
+		4: 2,
+		5: 1,
+		6: 1,
+	}
+	if diff := cmp.Diff(expectedRanks, ranks); diff != "" {
+		t.Errorf("unexpected ranks (-want +got):\n%s", diff)
+	}
+}
+
 func TestUpdateNumReferences(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
@@ -1131,6 +1232,48 @@ func TestUpdateNumReferences(t *testing.T) {
 	}
 }
 
+func TestReferenceCountsAtTip(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := testStore(db)
+
+	insertUploads(t, db,
+		Upload{ID: 50, RepositoryID: 50, Root: ""},
+		Upload{ID: 51, RepositoryID: 50, Root: "lib/", Indexer: "lsif-go"},
+		Upload{ID: 52, RepositoryID: 50, Root: "lib/", Indexer: "lsif-tsc"},
+		Upload{ID: 53, RepositoryID: 50, Root: "cmd/"},
+		Upload{ID: 54, RepositoryID: 51, Root: ""},
+		Upload{ID: 55, RepositoryID: 51, Root: "old/"},
+		Upload{ID: 56, RepositoryID: 52, Root: ""},
+	)
+	insertVisibleAtTip(t, db, 50, 50, 51, 52, 53)
+	insertVisibleAtTip(t, db, 51, 54)
+	insertVisibleAtTipNonDefaultBranch(t, db, 51, 55)
+	insertVisibleAtTip(t, db, 52, 56)
+
+	numReferences := map[int]int{50: 1, 51: 2, 52: 3, 53: 0, 54: 4, 55: 5, 56: 6}
+	for id, n := range numReferences {
+		if err := store.Exec(context.Background(), sqlf.Sprintf(`UPDATE lsif_uploads SET num_references = %s WHERE id = %s`, n, id)); err != nil {
+			t.Fatalf("unexpected error updating num_references: %s", err)
+		}
+	}
+
+	counts, err := store.ReferenceCountsAtTip(context.Background(), []int{50, 51})
+	if err != nil {
+		t.Fatalf("unexpected error getting reference counts: %s", err)
+	}
+
+	expectedCounts := map[int]map[string]int{
+		50: {"": 1, "lib/": 5},
+		51: {"": 4},
+	}
+	if diff := cmp.Diff(expectedCounts, counts); diff != "" {
+		t.Errorf("unexpected reference counts (-want +got):\n%s", diff)
+	}
+}
+
 func TestUpdateDependencyNumReferences(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
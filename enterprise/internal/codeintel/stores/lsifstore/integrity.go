@@ -0,0 +1,64 @@
+package lsifstore
+
+import (
+	"context"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// BundleIDs returns the identifiers of the bundles with data in the codeintel database that are greater
+// than the given identifier, in ascending order. This is used to page through all bundles.
+func (s *Store) BundleIDs(ctx context.Context, afterID, limit int) (_ []int, err error) {
+	ctx, traceLog, endObservation := s.operations.bundleIDs.WithAndLogger(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("afterID", afterID),
+		log.Int("limit", limit),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	ids, err := basestore.ScanInts(s.Store.Query(ctx, sqlf.Sprintf(bundleIDsQuery, afterID, limit)))
+	if err != nil {
+		return nil, err
+	}
+	traceLog(log.Int("numBundleIDs", len(ids)))
+
+	return ids, nil
+}
+
+const bundleIDsQuery = `
+-- source: enterprise/internal/codeintel/stores/lsifstore/integrity.go:BundleIDs
+SELECT dump_id FROM lsif_data_metadata WHERE dump_id > %s ORDER BY dump_id LIMIT %s
+`
+
+// MissingBundleIDs returns the given bundle identifiers that have no data in the codeintel database,
+// in ascending order.
+func (s *Store) MissingBundleIDs(ctx context.Context, bundleIDs []int) (_ []int, err error) {
+	ctx, traceLog, endObservation := s.operations.missingBundleIDs.WithAndLogger(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("numBundleIDs", len(bundleIDs)),
+		log.String("bundleIDs", intsToString(bundleIDs)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	if len(bundleIDs) == 0 {
+		return nil, nil
+	}
+
+	ids, err := basestore.ScanInts(s.Store.Query(ctx, sqlf.Sprintf(missingBundleIDsQuery, pq.Array(bundleIDs))))
+	if err != nil {
+		return nil, err
+	}
+	traceLog(log.Int("numMissingBundleIDs", len(ids)))
+
+	return ids, nil
+}
+
+const missingBundleIDsQuery = `
+-- source: enterprise/internal/codeintel/stores/lsifstore/integrity.go:MissingBundleIDs
+SELECT t.id FROM unnest(%s::integer[]) AS t(id)
+WHERE NOT EXISTS (SELECT 1 FROM lsif_data_metadata m WHERE m.dump_id = t.id)
+ORDER BY t.id
+`
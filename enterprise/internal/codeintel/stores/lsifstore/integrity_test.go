@@ -0,0 +1,44 @@
+package lsifstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+func TestBundleIDs(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := NewStore(db, &observation.TestContext)
+
+	for _, id := range []int{1, 3, 5, 6} {
+		query := sqlf.Sprintf("INSERT INTO lsif_data_metadata (dump_id, num_result_chunks) VALUES (%s, 0)", id)
+
+		if _, err := db.Exec(query.Query(sqlf.PostgresBindVar), query.Args()...); err != nil {
+			t.Fatalf("unexpected error inserting metadata: %s", err)
+		}
+	}
+
+	ids, err := store.BundleIDs(context.Background(), 1, 2)
+	if err != nil {
+		t.Fatalf("unexpected error getting bundle ids: %s", err)
+	}
+	if diff := cmp.Diff([]int{3, 5}, ids); diff != "" {
+		t.Errorf("unexpected bundle ids (-want +got):\n%s", diff)
+	}
+
+	missingIDs, err := store.MissingBundleIDs(context.Background(), []int{1, 2, 3, 4})
+	if err != nil {
+		t.Fatalf("unexpected error getting missing bundle ids: %s", err)
+	}
+	if diff := cmp.Diff([]int{2, 4}, missingIDs); diff != "" {
+		t.Errorf("unexpected missing bundle ids (-want +got):\n%s", diff)
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 type operations struct {
 	bulkMonikerResults              *observation.Operation
+	bundleIDs                       *observation.Operation
 	clear                           *observation.Operation
 	definitions                     *observation.Operation
 	deleteOldSearchRecords          *observation.Operation
@@ -23,6 +24,7 @@ type operations struct {
 	documentationReferences         *observation.Operation
 	exists                          *observation.Operation
 	hover                           *observation.Operation
+	missingBundleIDs                *observation.Operation
 	monikerResults                  *observation.Operation
 	monikersByPosition              *observation.Operation
 	packageInformation              *observation.Operation
@@ -71,6 +73,7 @@ func newOperations(observationContext *observation.Context) *operations {
 
 	return &operations{
 		bulkMonikerResults:              op("BulkMonikerResults"),
+		bundleIDs:                       op("BundleIDs"),
 		clear:                           op("Clear"),
 		definitions:                     op("Definitions"),
 		deleteOldSearchRecords:          op("DeleteOldSearchRecords"),
@@ -85,6 +88,7 @@ func newOperations(observationContext *observation.Context) *operations {
 		documentationReferences:         op("DocumentationReferences"),
 		exists:                          op("Exists"),
 		hover:                           op("Hover"),
+		missingBundleIDs:                op("MissingBundleIDs"),
 		monikerResults:                  op("MonikerResults"),
 		monikersByPosition:              op("MonikersByPosition"),
 		packageInformation:              op("PackageInformation"),
@@ -59,6 +59,8 @@ func TestIntegration_PermsStore(t *testing.T) {
 		{"UserIDsWithOldestPerms", testPermsStore_UserIDsWithOldestPerms(db)},
 		{"ReposIDsWithOldestPerms", testPermsStore_ReposIDsWithOldestPerms(db)},
 		{"Metrics", testPermsStore_Metrics(db)},
+
+		{"PermsSyncStates", testPermsStore_PermsSyncStates(db)},
 	} {
 		t.Run(tc.name, tc.test)
 	}
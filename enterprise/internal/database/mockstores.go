@@ -4,5 +4,6 @@ var Mocks MockStores
 
 // MockStores has a field for each store interface with the concrete mock type (to obviate the need for tedious type assertions in test code).
 type MockStores struct {
-	Perms MockPerms
+	Perms        MockPerms
+	SubRepoPerms MockSubRepoPerms
 }
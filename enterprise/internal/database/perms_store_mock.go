@@ -19,4 +19,8 @@ type MockPerms struct {
 	ListPendingUsers             func(ctx context.Context) ([]string, error)
 	ListExternalAccounts         func(ctx context.Context, userID int32) ([]*extsvc.Account, error)
 	GetUserIDsByExternalAccounts func(ctx context.Context, accounts *extsvc.Accounts) (map[string]int32, error)
+	LoadPermsSyncState           func(ctx context.Context, typ PermsSyncType, id int32) (*PermsSyncState, error)
+	SetPermsSyncQueued           func(ctx context.Context, typ PermsSyncType, ids ...int32) error
+	SetPermsSyncStarted          func(ctx context.Context, typ PermsSyncType, id int32) error
+	SetPermsSyncFinished         func(ctx context.Context, typ PermsSyncType, id int32, syncErr error) error
 }
@@ -0,0 +1,214 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	otlog "github.com/opentracing/opentracing-go/log"
+)
+
+// PermsSyncType is the type of permissions syncing, either user-centric or repository-centric.
+type PermsSyncType string
+
+const (
+	PermsSyncTypeUser PermsSyncType = "user"
+	PermsSyncTypeRepo PermsSyncType = "repo"
+)
+
+// PermsSyncStatus is the status of the permissions syncing of a user or repository.
+type PermsSyncStatus string
+
+const (
+	// PermsSyncStatusNone means no sync was reported yet.
+	PermsSyncStatusNone       PermsSyncStatus = "NONE"
+	PermsSyncStatusQueued     PermsSyncStatus = "QUEUED"
+	PermsSyncStatusProcessing PermsSyncStatus = "PROCESSING"
+	PermsSyncStatusCompleted  PermsSyncStatus = "COMPLETED"
+	PermsSyncStatusErrored    PermsSyncStatus = "ERRORED"
+)
+
+// errPermsSyncInterrupted is the error recorded for syncs that were still processing when the
+// permissions syncer stopped.
+const errPermsSyncInterrupted = "interrupted: the permissions syncer was restarted"
+
+// PermsSyncState is the progress of the permissions syncing of a user or repository, as reported
+// by the permissions syncer in the 'perms_sync_states' table.
+type PermsSyncState struct {
+	// QueuedAt is when the last sync request was queued.
+	QueuedAt *time.Time
+	// StartedAt is when the last sync started.
+	StartedAt *time.Time
+	// FinishedAt is when the last sync finished, successfully or not.
+	FinishedAt *time.Time
+	// LastError is the error of the last finished sync, empty if it succeeded.
+	LastError string
+}
+
+// Status returns the status of the permissions syncing, derived from the order of its timestamps.
+func (s *PermsSyncState) Status() PermsSyncStatus {
+	switch {
+	case s.StartedAt != nil && (s.FinishedAt == nil || s.FinishedAt.Before(*s.StartedAt)):
+		return PermsSyncStatusProcessing
+	case s.QueuedAt != nil && (s.StartedAt == nil || s.QueuedAt.After(*s.StartedAt)):
+		return PermsSyncStatusQueued
+	case s.FinishedAt != nil && s.LastError != "":
+		return PermsSyncStatusErrored
+	case s.FinishedAt != nil:
+		return PermsSyncStatusCompleted
+	}
+	return PermsSyncStatusNone
+}
+
+// LoadPermsSyncState returns the progress of the permissions syncing of the user or repository
+// with given ID. A state with PermsSyncStatusNone is returned when no sync was reported yet.
+func (s *PermsStore) LoadPermsSyncState(ctx context.Context, typ PermsSyncType, id int32) (_ *PermsSyncState, err error) {
+	if Mocks.Perms.LoadPermsSyncState != nil {
+		return Mocks.Perms.LoadPermsSyncState(ctx, typ, id)
+	}
+
+	ctx, save := s.observe(ctx, "LoadPermsSyncState", "")
+	defer func() { save(&err, otlog.String("type", string(typ)), otlog.Int32("id", id)) }()
+
+	q := sqlf.Sprintf(`
+-- source: enterprise/internal/database/perms_sync_states.go:PermsStore.LoadPermsSyncState
+SELECT queued_at, started_at, finished_at, last_error
+FROM perms_sync_states
+WHERE request_type = %s AND id = %s
+`, typ, id)
+
+	var (
+		state     PermsSyncState
+		lastError sql.NullString
+	)
+	err = s.QueryRow(ctx, q).Scan(&state.QueuedAt, &state.StartedAt, &state.FinishedAt, &lastError)
+	if err == sql.ErrNoRows {
+		return &state, nil
+	} else if err != nil {
+		return nil, err
+	}
+	state.LastError = lastError.String
+	return &state, nil
+}
+
+// SetPermsSyncQueued records that sync requests for the users or repositories with given IDs were
+// queued. Requests that are already queued keep their original queued time.
+func (s *PermsStore) SetPermsSyncQueued(ctx context.Context, typ PermsSyncType, ids ...int32) (err error) {
+	if Mocks.Perms.SetPermsSyncQueued != nil {
+		return Mocks.Perms.SetPermsSyncQueued(ctx, typ, ids...)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	ctx, save := s.observe(ctx, "SetPermsSyncQueued", "")
+	defer func() { save(&err, otlog.String("type", string(typ)), otlog.Int("count", len(ids))) }()
+
+	q := sqlf.Sprintf(`
+-- source: enterprise/internal/database/perms_sync_states.go:PermsStore.SetPermsSyncQueued
+INSERT INTO perms_sync_states
+	(request_type, id, queued_at)
+SELECT %s, unnest(%s::integer[]), %s
+ON CONFLICT (request_type, id)
+DO UPDATE SET
+	queued_at = excluded.queued_at
+WHERE
+	perms_sync_states.queued_at IS NULL
+OR  perms_sync_states.queued_at <= perms_sync_states.started_at
+`, typ, pq.Array(ids), s.clock().UTC())
+	return errors.Wrap(s.Exec(ctx, q), "upsert perms sync states")
+}
+
+// SetPermsSyncStarted records that the sync of the user or repository with given ID started.
+func (s *PermsStore) SetPermsSyncStarted(ctx context.Context, typ PermsSyncType, id int32) (err error) {
+	if Mocks.Perms.SetPermsSyncStarted != nil {
+		return Mocks.Perms.SetPermsSyncStarted(ctx, typ, id)
+	}
+
+	ctx, save := s.observe(ctx, "SetPermsSyncStarted", "")
+	defer func() { save(&err, otlog.String("type", string(typ)), otlog.Int32("id", id)) }()
+
+	q := sqlf.Sprintf(`
+-- source: enterprise/internal/database/perms_sync_states.go:PermsStore.SetPermsSyncStarted
+INSERT INTO perms_sync_states
+	(request_type, id, started_at)
+VALUES
+	(%s, %s, %s)
+ON CONFLICT (request_type, id)
+DO UPDATE SET
+	started_at = excluded.started_at
+`, typ, id, s.clock().UTC())
+	return errors.Wrap(s.Exec(ctx, q), "upsert perms sync state")
+}
+
+// SetPermsSyncFinished records that the sync of the user or repository with given ID finished,
+// with the given error if it failed.
+func (s *PermsStore) SetPermsSyncFinished(ctx context.Context, typ PermsSyncType, id int32, syncErr error) (err error) {
+	if Mocks.Perms.SetPermsSyncFinished != nil {
+		return Mocks.Perms.SetPermsSyncFinished(ctx, typ, id, syncErr)
+	}
+
+	ctx, save := s.observe(ctx, "SetPermsSyncFinished", "")
+	defer func() { save(&err, otlog.String("type", string(typ)), otlog.Int32("id", id)) }()
+
+	var lastError *string
+	if syncErr != nil {
+		msg := syncErr.Error()
+		lastError = &msg
+	}
+	q := sqlf.Sprintf(`
+-- source: enterprise/internal/database/perms_sync_states.go:PermsStore.SetPermsSyncFinished
+INSERT INTO perms_sync_states
+	(request_type, id, finished_at, last_error)
+VALUES
+	(%s, %s, %s, %s)
+ON CONFLICT (request_type, id)
+DO UPDATE SET
+	finished_at = excluded.finished_at,
+	last_error = excluded.last_error
+`, typ, id, s.clock().UTC(), lastError)
+	return errors.Wrap(s.Exec(ctx, q), "upsert perms sync state")
+}
+
+// ResetPermsSyncStates clears the queued requests and fails the processing syncs. The permissions
+// syncer keeps its queue in memory, so it must call this method when it starts.
+func (s *PermsStore) ResetPermsSyncStates(ctx context.Context) (err error) {
+	ctx, save := s.observe(ctx, "ResetPermsSyncStates", "")
+	defer func() { save(&err) }()
+
+	txs, err := s.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = txs.Done(err) }()
+
+	q := sqlf.Sprintf(`
+-- source: enterprise/internal/database/perms_sync_states.go:PermsStore.ResetPermsSyncStates
+UPDATE perms_sync_states
+SET queued_at = NULL
+WHERE
+	queued_at IS NOT NULL
+AND (started_at IS NULL OR queued_at > started_at)
+`)
+	if err = txs.Exec(ctx, q); err != nil {
+		return errors.Wrap(err, "reset queued perms sync states")
+	}
+
+	q = sqlf.Sprintf(`
+-- source: enterprise/internal/database/perms_sync_states.go:PermsStore.ResetPermsSyncStates
+UPDATE perms_sync_states
+SET
+	finished_at = %s,
+	last_error = %s
+WHERE
+	started_at IS NOT NULL
+AND (finished_at IS NULL OR finished_at < started_at)
+`, s.clock().UTC(), errPermsSyncInterrupted)
+	if err = txs.Exec(ctx, q); err != nil {
+		return errors.Wrap(err, "reset processing perms sync states")
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/timeutil"
+)
+
+func TestPermsSyncState_Status(t *testing.T) {
+	t1 := time.Unix(1, 0)
+	t2 := time.Unix(2, 0)
+	t3 := time.Unix(3, 0)
+
+	tests := []struct {
+		name  string
+		state PermsSyncState
+		want  PermsSyncStatus
+	}{
+		{name: "never synced", state: PermsSyncState{}, want: PermsSyncStatusNone},
+		{name: "queued", state: PermsSyncState{QueuedAt: &t1}, want: PermsSyncStatusQueued},
+		{name: "processing", state: PermsSyncState{QueuedAt: &t1, StartedAt: &t2}, want: PermsSyncStatusProcessing},
+		{name: "processing again", state: PermsSyncState{StartedAt: &t3, FinishedAt: &t2}, want: PermsSyncStatusProcessing},
+		{name: "completed", state: PermsSyncState{QueuedAt: &t1, StartedAt: &t2, FinishedAt: &t3}, want: PermsSyncStatusCompleted},
+		{name: "errored", state: PermsSyncState{StartedAt: &t2, FinishedAt: &t3, LastError: "boom"}, want: PermsSyncStatusErrored},
+		{name: "queued after completed", state: PermsSyncState{QueuedAt: &t3, StartedAt: &t1, FinishedAt: &t2}, want: PermsSyncStatusQueued},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.state.Status(); got != test.want {
+				t.Fatalf("want %q but got %q", test.want, got)
+			}
+		})
+	}
+}
+
+func testPermsStore_PermsSyncStates(db *sql.DB) func(*testing.T) {
+	return func(t *testing.T) {
+		now := timeutil.Now().Unix()
+		s := Perms(db, func() time.Time {
+			return time.Unix(atomic.AddInt64(&now, 1), 0)
+		})
+		t.Cleanup(func() {
+			cleanupPermsTables(t, s)
+		})
+
+		ctx := context.Background()
+		status := func(t *testing.T, id int32) *PermsSyncState {
+			t.Helper()
+			state, err := s.LoadPermsSyncState(ctx, PermsSyncTypeUser, id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return state
+		}
+
+		if got := status(t, 1).Status(); got != PermsSyncStatusNone {
+			t.Fatalf("status: want %q but got %q", PermsSyncStatusNone, got)
+		}
+
+		if err := s.SetPermsSyncQueued(ctx, PermsSyncTypeUser, 1, 2); err != nil {
+			t.Fatal(err)
+		}
+		if got := status(t, 1).Status(); got != PermsSyncStatusQueued {
+			t.Fatalf("status: want %q but got %q", PermsSyncStatusQueued, got)
+		}
+		// Repository states are independent of user states.
+		repoState, err := s.LoadPermsSyncState(ctx, PermsSyncTypeRepo, 1)
+		if err != nil {
+			t.Fatal(err)
+		} else if got := repoState.Status(); got != PermsSyncStatusNone {
+			t.Fatalf("repo status: want %q but got %q", PermsSyncStatusNone, got)
+		}
+
+		if err := s.SetPermsSyncStarted(ctx, PermsSyncTypeUser, 1); err != nil {
+			t.Fatal(err)
+		}
+		if got := status(t, 1).Status(); got != PermsSyncStatusProcessing {
+			t.Fatalf("status: want %q but got %q", PermsSyncStatusProcessing, got)
+		}
+
+		if err := s.SetPermsSyncFinished(ctx, PermsSyncTypeUser, 1, errors.New("boom")); err != nil {
+			t.Fatal(err)
+		}
+		state := status(t, 1)
+		if got := state.Status(); got != PermsSyncStatusErrored {
+			t.Fatalf("status: want %q but got %q", PermsSyncStatusErrored, got)
+		}
+		equal(t, "LastError", "boom", state.LastError)
+
+		// Restarting the syncer clears the queued requests and fails the processing ones.
+		if err := s.SetPermsSyncStarted(ctx, PermsSyncTypeUser, 1); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.ResetPermsSyncStates(ctx); err != nil {
+			t.Fatal(err)
+		}
+		state = status(t, 1)
+		if got := state.Status(); got != PermsSyncStatusErrored {
+			t.Fatalf("status: want %q but got %q", PermsSyncStatusErrored, got)
+		}
+		equal(t, "LastError", errPermsSyncInterrupted, state.LastError)
+		if got := status(t, 2).Status(); got != PermsSyncStatusNone {
+			t.Fatalf("status: want %q but got %q", PermsSyncStatusNone, got)
+		}
+	}
+}
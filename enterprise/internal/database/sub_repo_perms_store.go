@@ -0,0 +1,136 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// SubRepoPermsStore is used to manage the permissions of users to paths within
+// repositories, stored in the 'sub_repo_permissions' table.
+type SubRepoPermsStore struct {
+	*basestore.Store
+}
+
+// SubRepoPerms returns a new SubRepoPermsStore with the given database handle.
+func SubRepoPerms(db dbutil.DB) *SubRepoPermsStore {
+	return &SubRepoPermsStore{Store: basestore.NewWithDB(db, sql.TxOptions{})}
+}
+
+// SubRepoPermsWith instantiates and returns a new SubRepoPermsStore using the
+// other store handle.
+func SubRepoPermsWith(other basestore.ShareableStore) *SubRepoPermsStore {
+	return &SubRepoPermsStore{Store: basestore.NewWithHandle(other.Handle())}
+}
+
+// Transact begins a new transaction and make a new SubRepoPermsStore over it.
+func (s *SubRepoPermsStore) Transact(ctx context.Context) (*SubRepoPermsStore, error) {
+	txBase, err := s.Store.Transact(ctx)
+	return &SubRepoPermsStore{Store: txBase}, err
+}
+
+func (s *SubRepoPermsStore) Done(err error) error {
+	return s.Store.Done(err)
+}
+
+// Upsert will upsert sub repo permissions data.
+func (s *SubRepoPermsStore) Upsert(ctx context.Context, userID int32, repoID api.RepoID, perms authz.SubRepoPermissions) error {
+	q := sqlf.Sprintf(`
+-- source: enterprise/internal/database/sub_repo_perms_store.go:SubRepoPermsStore.Upsert
+INSERT INTO sub_repo_permissions (user_id, repo_id, path_rules, updated_at)
+VALUES (%s, %s, %s, NOW())
+ON CONFLICT (repo_id, user_id)
+DO UPDATE
+SET
+  path_rules = EXCLUDED.path_rules,
+  updated_at = EXCLUDED.updated_at
+`, userID, repoID, pq.Array(perms.Paths))
+	return errors.Wrap(s.Exec(ctx, q), "upserting sub repo permissions")
+}
+
+// UpsertWithSpec will upsert sub repo permissions data using the provided
+// external repo spec to map to our internal repo id. If there is no mapping,
+// nothing is written.
+func (s *SubRepoPermsStore) UpsertWithSpec(ctx context.Context, userID int32, spec api.ExternalRepoSpec, perms authz.SubRepoPermissions) error {
+	q := sqlf.Sprintf(`
+-- source: enterprise/internal/database/sub_repo_perms_store.go:SubRepoPermsStore.UpsertWithSpec
+INSERT INTO sub_repo_permissions (user_id, repo_id, path_rules, updated_at)
+SELECT %s, id, %s, NOW()
+FROM repo
+WHERE external_service_id = %s
+  AND external_service_type = %s
+  AND external_id = %s
+ON CONFLICT (repo_id, user_id)
+DO UPDATE
+SET
+  path_rules = EXCLUDED.path_rules,
+  updated_at = EXCLUDED.updated_at
+`, userID, pq.Array(perms.Paths), spec.ServiceID, spec.ServiceType, spec.ID)
+	return errors.Wrap(s.Exec(ctx, q), "upserting sub repo permissions with spec")
+}
+
+// Get will fetch the sub repo permissions of the given user for the given
+// repo. It returns nil if there are none, i.e. the access of the user to the
+// repo isn't restricted to certain paths.
+func (s *SubRepoPermsStore) Get(ctx context.Context, userID int32, repoID api.RepoID) (*authz.SubRepoPermissions, error) {
+	if Mocks.SubRepoPerms.Get != nil {
+		return Mocks.SubRepoPerms.Get(ctx, userID, repoID)
+	}
+
+	q := sqlf.Sprintf(`
+-- source: enterprise/internal/database/sub_repo_perms_store.go:SubRepoPermsStore.Get
+SELECT path_rules
+FROM sub_repo_permissions
+WHERE user_id = %s
+  AND repo_id = %s
+`, userID, repoID)
+
+	var paths []string
+	if err := s.QueryRow(ctx, q).Scan(pq.Array(&paths)); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "getting sub repo permissions")
+	}
+	return &authz.SubRepoPermissions{Paths: paths}, nil
+}
+
+// SetUserSubRepoPermissions replaces all sub repo permissions of the given user
+// with the given permissions, keyed by the external repo spec of the repos.
+func (s *SubRepoPermsStore) SetUserSubRepoPermissions(ctx context.Context, userID int32, perms map[api.ExternalRepoSpec]*authz.SubRepoPermissions) (err error) {
+	if Mocks.SubRepoPerms.SetUserSubRepoPermissions != nil {
+		return Mocks.SubRepoPerms.SetUserSubRepoPermissions(ctx, userID, perms)
+	}
+
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	q := sqlf.Sprintf(`
+-- source: enterprise/internal/database/sub_repo_perms_store.go:SubRepoPermsStore.SetUserSubRepoPermissions
+DELETE FROM sub_repo_permissions WHERE user_id = %s
+`, userID)
+	if err = tx.Exec(ctx, q); err != nil {
+		return errors.Wrap(err, "deleting sub repo permissions")
+	}
+
+	for spec, p := range perms {
+		if p == nil {
+			continue
+		}
+		if err = tx.UpsertWithSpec(ctx, userID, spec, *p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
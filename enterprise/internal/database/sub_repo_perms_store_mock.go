@@ -0,0 +1,13 @@
+package database
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+)
+
+type MockSubRepoPerms struct {
+	Get                       func(ctx context.Context, userID int32, repoID api.RepoID) (*authz.SubRepoPermissions, error)
+	SetUserSubRepoPermissions func(ctx context.Context, userID int32, perms map[api.ExternalRepoSpec]*authz.SubRepoPermissions) error
+}
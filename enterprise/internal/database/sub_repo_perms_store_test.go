@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+)
+
+func TestIntegration_SubRepoPermsStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	t.Parallel()
+
+	db := dbtest.NewDB(t, *dsn)
+	ctx := context.Background()
+	s := SubRepoPerms(db)
+
+	qs := []*sqlf.Query{
+		sqlf.Sprintf(`INSERT INTO users(username) VALUES ('alice')`), // ID=1
+		sqlf.Sprintf(`INSERT INTO repo(name, external_id, external_service_type, external_service_id) VALUES ('perforce/Engineering', '//Sourcegraph/Engineering/', %s, 'ssl:111.222.333.444:1666')`, extsvc.TypePerforce), // ID=1
+		sqlf.Sprintf(`INSERT INTO repo(name, external_id, external_service_type, external_service_id) VALUES ('perforce/Handbook', '//Sourcegraph/Handbook/', %s, 'ssl:111.222.333.444:1666')`, extsvc.TypePerforce),       // ID=2
+	}
+	for _, q := range qs {
+		if err := s.Exec(ctx, q); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	assertPerms := func(t *testing.T, repoID api.RepoID, want *authz.SubRepoPermissions) {
+		t.Helper()
+		have, err := s.Get(ctx, 1, repoID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, have); diff != "" {
+			t.Fatalf("Mismatch (-want +got):\n%s", diff)
+		}
+	}
+
+	t.Run("Upsert", func(t *testing.T) {
+		assertPerms(t, 1, nil)
+
+		for _, paths := range [][]string{{"**", "-secrets/**"}, {"docs/**"}} {
+			perms := authz.SubRepoPermissions{Paths: paths}
+			if err := s.Upsert(ctx, 1, 1, perms); err != nil {
+				t.Fatal(err)
+			}
+			assertPerms(t, 1, &perms)
+		}
+	})
+
+	t.Run("SetUserSubRepoPermissions", func(t *testing.T) {
+		spec := func(id string) api.ExternalRepoSpec {
+			return api.ExternalRepoSpec{
+				ID:          id,
+				ServiceType: extsvc.TypePerforce,
+				ServiceID:   "ssl:111.222.333.444:1666",
+			}
+		}
+
+		handbook := &authz.SubRepoPermissions{Paths: []string{"*.md"}}
+		err := s.SetUserSubRepoPermissions(ctx, 1, map[api.ExternalRepoSpec]*authz.SubRepoPermissions{
+			spec("//Sourcegraph/Handbook/"): handbook,
+			spec("//Sourcegraph/Unknown/"):  {Paths: []string{"**"}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Permissions not in the given set are removed.
+		assertPerms(t, 1, nil)
+		assertPerms(t, 2, handbook)
+	})
+}
@@ -1,6 +1,11 @@
 package executor
 
-import "github.com/sourcegraph/sourcegraph/internal/workerutil"
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+)
 
 // Job describes a series of steps to perform within an executor.
 type Job struct {
@@ -34,6 +39,10 @@ type Job struct {
 	// environment variables, as well as secret values passed along with the dequeued job
 	// payload, which may be sensitive (e.g. shared API tokens, URLs with credentials).
 	RedactedValues map[string]string `json:"redactedValues"`
+
+	// Artifacts describe the files of the workspace to be uploaded after all steps
+	// have been run, even if one of them failed. Files that don't exist are skipped.
+	Artifacts []Artifact `json:"artifacts,omitempty"`
 }
 
 func (j Job) RecordID() int {
@@ -41,6 +50,12 @@ func (j Job) RecordID() int {
 }
 
 type DockerStep struct {
+	// Key optionally identifies the step. The output of the step is streamed
+	// into the execution log entry with the key "step.docker.<Key>", so it
+	// can be found while the job is still running. If empty, the index of the
+	// step is used instead.
+	Key string `json:"key,omitempty"`
+
 	// Image specifies the docker image.
 	Image string `json:"image"`
 
@@ -54,7 +69,19 @@ type DockerStep struct {
 	Env []string `json:"env"`
 }
 
+// LogKey returns the key of the execution log entry of the step, given its
+// index in the job.
+func (s DockerStep) LogKey(i int) string {
+	return stepLogKey("docker", s.Key, i)
+}
+
 type CliStep struct {
+	// Key optionally identifies the step. The output of the step is streamed
+	// into the execution log entry with the key "step.src.<Key>", so it can be
+	// found while the job is still running. If empty, the index of the step
+	// is used instead.
+	Key string `json:"key,omitempty"`
+
 	// Commands specifies the arguments supplied to the src command.
 	Commands []string `json:"command"`
 
@@ -65,6 +92,30 @@ type CliStep struct {
 	Env []string `json:"env"`
 }
 
+// LogKey returns the key of the execution log entry of the step, given its
+// index in the job.
+func (s CliStep) LogKey(i int) string {
+	return stepLogKey("src", s.Key, i)
+}
+
+func stepLogKey(kind, key string, i int) string {
+	if key == "" {
+		return fmt.Sprintf("step.%s.%d", kind, i)
+	}
+	return fmt.Sprintf("step.%s.%s", kind, key)
+}
+
+type Artifact struct {
+	// Name identifies the artifact within the job. It must match ArtifactNamePattern.
+	Name string `json:"name"`
+
+	// Path is the path of the file to upload, relative to the workspace.
+	Path string `json:"path"`
+}
+
+// ArtifactNamePattern matches the valid names of artifacts.
+var ArtifactNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,127}$`)
+
 type DequeueRequest struct {
 	ExecutorName     string `json:"executorName"`
 	ExecutorHostname string `json:"executorHostname"`
@@ -97,6 +148,21 @@ type MarkErroredRequest struct {
 type HeartbeatRequest struct {
 	ExecutorName string `json:"executorName"`
 	JobIDs       []int  `json:"jobIds"`
+	ExecutorInfo
+}
+
+// ExecutorInfo describes an executor and the resources of the system it is running
+// on. It's sent along with heartbeats, so that site admins can see the active
+// executors.
+type ExecutorInfo struct {
+	Hostname             string  `json:"hostname,omitempty"`
+	OS                   string  `json:"os,omitempty"`
+	Architecture         string  `json:"architecture,omitempty"`
+	ExecutorVersion      string  `json:"executorVersion,omitempty"`
+	NumCPUs              int     `json:"numCPUs,omitempty"`
+	MemoryTotalBytes     int64   `json:"memoryTotalBytes,omitempty"`
+	MemoryAvailableBytes int64   `json:"memoryAvailableBytes,omitempty"`
+	LoadAverage          float64 `json:"loadAverage,omitempty"`
 }
 
 type CanceledRequest struct {
@@ -0,0 +1,152 @@
+package background
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// newInsightAlertEvaluator returns a background goroutine which will periodically evaluate all
+// insight series alerts, and notify their owners when an alert starts to trigger.
+func newInsightAlertEvaluator(ctx context.Context, mainAppDB dbutil.DB, alertStore store.AlertStore, dataSeriesStore store.DataSeriesStore, insightsStore store.Interface, observationContext *observation.Context) goroutine.BackgroundRoutine {
+	metrics := metrics.NewOperationMetrics(
+		observationContext.Registerer,
+		"insights_alert_evaluator",
+		metrics.WithCountHelp("Total number of insights alert evaluator executions"),
+	)
+	operation := observationContext.Operation(observation.Op{
+		Name:    "AlertEvaluator.Run",
+		Metrics: metrics,
+	})
+
+	evaluator := &alertEvaluator{
+		now:             time.Now,
+		alertStore:      alertStore,
+		dataSeriesStore: dataSeriesStore,
+		insightsStore:   insightsStore,
+		notify: func(ctx context.Context, n alertNotification) error {
+			return notifyAlert(ctx, mainAppDB, n)
+		},
+	}
+
+	return goroutine.NewPeriodicGoroutineWithMetrics(ctx, time.Hour, goroutine.NewHandlerWithErrorMessage(
+		"insights_alert_evaluator",
+		evaluator.Handler,
+	), operation)
+}
+
+// alertEvaluator evaluates insight series alerts. An alert compares either the latest value of a
+// series, or the change of the series over its evaluation window, to its threshold. Owners are
+// only notified when the condition of an alert starts to hold, not on every evaluation.
+type alertEvaluator struct {
+	now             func() time.Time
+	alertStore      store.AlertStore
+	dataSeriesStore store.DataSeriesStore
+	insightsStore   store.Interface
+	notify          func(ctx context.Context, notification alertNotification) error
+}
+
+func (e *alertEvaluator) Handler(ctx context.Context) error {
+	alerts, err := e.alertStore.GetAlerts(ctx, store.AlertQueryArgs{})
+	if err != nil {
+		return errors.Wrap(err, "GetAlerts")
+	}
+
+	var multi error
+	for _, alert := range alerts {
+		if err := e.evaluate(ctx, alert); err != nil {
+			multi = multierror.Append(multi, errors.Wrapf(err, "evaluating alert %d", alert.ID))
+		}
+	}
+	return multi
+}
+
+func (e *alertEvaluator) evaluate(ctx context.Context, alert types.InsightSeriesAlert) error {
+	// 🚨 SECURITY: The series is evaluated with the repository permissions of the owner of the
+	// alert, so that notifications never include data from repositories the owner cannot see.
+	ctx = actor.WithActor(ctx, actor.FromUser(alert.UserID))
+
+	seriesID := alert.SeriesID
+	points, err := e.insightsStore.SeriesPoints(ctx, store.SeriesPointsOpts{SeriesID: &seriesID})
+	if err != nil {
+		return errors.Wrap(err, "SeriesPoints")
+	}
+	value, ok := evaluatedValue(alert, points, e.now())
+	if !ok {
+		// There is not enough data yet to evaluate this alert.
+		return nil
+	}
+
+	triggered := alertConditionHolds(alert, value)
+	if triggered && !alert.Triggered {
+		series, err := e.dataSeriesStore.GetDataSeries(ctx, store.GetDataSeriesArgs{SeriesID: alert.SeriesID})
+		if err != nil {
+			return errors.Wrap(err, "GetDataSeries")
+		}
+		if len(series) == 0 {
+			return errors.Newf("series %q not found", alert.SeriesID)
+		}
+		// If the notification fails, the state is not updated so that it is retried in the next
+		// evaluation.
+		if err := e.notify(ctx, alertNotification{Alert: alert, Series: series[0], Value: value}); err != nil {
+			return errors.Wrap(err, "notify")
+		}
+	}
+	return e.alertStore.UpdateAlertState(ctx, alert.ID, triggered)
+}
+
+// evaluatedValue returns the value of the given series points that is compared to the threshold
+// of the alert: the latest value of the series, or its change over the evaluation window. It
+// returns false if there is not enough data to evaluate the alert.
+func evaluatedValue(alert types.InsightSeriesAlert, points []store.SeriesPoint, now time.Time) (float64, bool) {
+	// Series generated from capture groups have multiple points per time, one per capture group
+	// value. Alerts are evaluated on their total.
+	totals := make(map[time.Time]float64, len(points))
+	times := make([]time.Time, 0, len(points))
+	for _, point := range points {
+		if _, ok := totals[point.Time]; !ok {
+			times = append(times, point.Time)
+		}
+		totals[point.Time] += point.Value
+	}
+	if len(times) == 0 {
+		return 0, false
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].After(times[j]) })
+
+	latest := totals[times[0]]
+	if alert.EvaluationWindowDays == 0 {
+		return latest, true
+	}
+
+	// The change is computed against the most recent point at or before the start of the window.
+	windowStart := now.AddDate(0, 0, -alert.EvaluationWindowDays)
+	for _, t := range times {
+		if !t.After(windowStart) {
+			return latest - totals[t], true
+		}
+	}
+	return 0, false
+}
+
+// alertConditionHolds returns true if the given value triggers the alert.
+func alertConditionHolds(alert types.InsightSeriesAlert, value float64) bool {
+	switch alert.Direction {
+	case types.AlertDirectionAtLeast:
+		return value >= alert.Threshold
+	case types.AlertDirectionAtMost:
+		return value <= alert.Threshold
+	}
+	return false
+}
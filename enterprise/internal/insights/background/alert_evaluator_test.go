@@ -0,0 +1,109 @@
+package background
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+)
+
+func TestAlertEvaluator(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC)
+	points := []store.SeriesPoint{
+		{SeriesID: "s1", Time: now.AddDate(0, 0, -1), Value: 120},
+		{SeriesID: "s1", Time: now.AddDate(0, 0, -8), Value: 80},
+		{SeriesID: "s1", Time: now.AddDate(0, 0, -15), Value: 100},
+	}
+
+	testCases := []struct {
+		name          string
+		alert         types.InsightSeriesAlert
+		wantNotified  bool
+		wantTriggered bool
+	}{
+		{
+			name:          "latest value crosses threshold",
+			alert:         types.InsightSeriesAlert{ID: 1, SeriesID: "s1", Threshold: 100, Direction: types.AlertDirectionAtLeast},
+			wantNotified:  true,
+			wantTriggered: true,
+		},
+		{
+			name:          "already triggered",
+			alert:         types.InsightSeriesAlert{ID: 1, SeriesID: "s1", Threshold: 100, Direction: types.AlertDirectionAtLeast, Triggered: true},
+			wantNotified:  false,
+			wantTriggered: true,
+		},
+		{
+			name:          "latest value within threshold",
+			alert:         types.InsightSeriesAlert{ID: 1, SeriesID: "s1", Threshold: 100, Direction: types.AlertDirectionAtMost, Triggered: true},
+			wantNotified:  false,
+			wantTriggered: false,
+		},
+		{
+			// The change over the last 7 days is 120 - 80 = 40.
+			name:          "change over window",
+			alert:         types.InsightSeriesAlert{ID: 1, SeriesID: "s1", Threshold: 40, Direction: types.AlertDirectionAtLeast, EvaluationWindowDays: 7},
+			wantNotified:  true,
+			wantTriggered: true,
+		},
+		{
+			// The change over the last 14 days is 120 - 100 = 20.
+			name:          "change over window within threshold",
+			alert:         types.InsightSeriesAlert{ID: 1, SeriesID: "s1", Threshold: 40, Direction: types.AlertDirectionAtLeast, EvaluationWindowDays: 14},
+			wantNotified:  false,
+			wantTriggered: false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			alertStore := store.NewMockAlertStore()
+			alertStore.GetAlertsFunc.SetDefaultReturn([]types.InsightSeriesAlert{tc.alert}, nil)
+			var gotTriggered *bool
+			alertStore.UpdateAlertStateFunc.SetDefaultHook(func(ctx context.Context, id int, triggered bool) error {
+				gotTriggered = &triggered
+				return nil
+			})
+			dataSeriesStore := store.NewMockDataSeriesStore()
+			dataSeriesStore.GetDataSeriesFunc.SetDefaultReturn([]types.InsightSeries{{SeriesID: "s1", Query: "TODO count:all"}}, nil)
+			insightsStore := store.NewMockInterface()
+			insightsStore.SeriesPointsFunc.SetDefaultReturn(points, nil)
+
+			var notifications []alertNotification
+			evaluator := &alertEvaluator{
+				now:             func() time.Time { return now },
+				alertStore:      alertStore,
+				dataSeriesStore: dataSeriesStore,
+				insightsStore:   insightsStore,
+				notify: func(ctx context.Context, n alertNotification) error {
+					notifications = append(notifications, n)
+					return nil
+				},
+			}
+			if err := evaluator.Handler(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := len(notifications) == 1; got != tc.wantNotified {
+				t.Errorf("unexpected notifications: %+v", notifications)
+			}
+			if gotTriggered == nil || *gotTriggered != tc.wantTriggered {
+				t.Errorf("unexpected alert state: want triggered=%v", tc.wantTriggered)
+			}
+		})
+	}
+}
+
+func TestEvaluatedValueInsufficientData(t *testing.T) {
+	now := time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC)
+	alert := types.InsightSeriesAlert{EvaluationWindowDays: 30}
+	points := []store.SeriesPoint{{Time: now.AddDate(0, 0, -1), Value: 10}}
+	if _, ok := evaluatedValue(alert, points, now); ok {
+		t.Error("expected no value for a window without a baseline point")
+	}
+	if _, ok := evaluatedValue(types.InsightSeriesAlert{}, nil, now); ok {
+		t.Error("expected no value for a series without points")
+	}
+}
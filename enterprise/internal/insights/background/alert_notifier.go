@@ -0,0 +1,191 @@
+package background
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/cockroachdb/errors"
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/txemail"
+	"github.com/sourcegraph/sourcegraph/internal/txemail/txtypes"
+)
+
+// alertNotification describes an alert which started to trigger.
+type alertNotification struct {
+	Alert  types.InsightSeriesAlert
+	Series types.InsightSeries
+	// Value is the evaluated value of the series which triggered the alert.
+	Value float64
+}
+
+// notifyAlert delivers the notification in-product and through all channels configured on
+// the alert.
+func notifyAlert(ctx context.Context, db dbutil.DB, n alertNotification) error {
+	var multi error
+	if err := createAlertNotification(ctx, db, n); err != nil {
+		multi = multierror.Append(multi, errors.Wrap(err, "in-product"))
+	}
+	if n.Alert.Email {
+		if err := sendAlertEmail(ctx, n); err != nil {
+			multi = multierror.Append(multi, errors.Wrap(err, "email"))
+		}
+	}
+	if n.Alert.WebhookURL != nil && *n.Alert.WebhookURL != "" {
+		if err := sendAlertWebhook(ctx, n); err != nil {
+			multi = multierror.Append(multi, errors.Wrap(err, "webhook"))
+		}
+	}
+	return multi
+}
+
+func createAlertNotification(ctx context.Context, db dbutil.DB, n alertNotification) error {
+	body := fmt.Sprintf("Latest value: %v", n.Value)
+	if n.Alert.EvaluationWindowDays > 0 {
+		body = fmt.Sprintf("Change over the last %d days: %v", n.Alert.EvaluationWindowDays, n.Value)
+	}
+	return database.Notifications(db).Create(ctx, &database.Notification{
+		UserID: n.Alert.UserID,
+		Kind:   database.NotificationKindInsightAlert,
+		Title:  fmt.Sprintf("Code Insights alert: %s", n.Series.Query),
+		Body:   fmt.Sprintf("%s (alert condition: %s %v)", body, alertDirection(n.Alert.Direction), n.Alert.Threshold),
+		URL:    (&url.URL{Path: "/search", RawQuery: url.Values{"q": []string{n.Series.Query}}.Encode()}).String(),
+	})
+}
+
+func alertDirection(direction types.AlertDirection) string {
+	if direction == types.AlertDirectionAtMost {
+		return "at most"
+	}
+	return "at least"
+}
+
+type alertEmailTemplateData struct {
+	Query                string
+	Threshold            float64
+	Direction            string
+	EvaluationWindowDays int
+	Value                float64
+	SearchURL            string
+}
+
+var alertEmailTemplates = txemail.MustValidate(txtypes.Templates{
+	Subject: `Code Insights alert: {{.Query}}`,
+	Text: `
+A Code Insights series you set an alert on crossed its threshold.
+
+Query: {{.Query}}
+{{ if .EvaluationWindowDays }}Change over the last {{.EvaluationWindowDays}} days{{ else }}Latest value{{ end }}: {{.Value}}
+Alert condition: {{.Direction}} {{.Threshold}}
+
+View search on Sourcegraph {{.SearchURL}}
+
+__
+You are receiving this notification because you created an alert on a Code Insights series.
+`,
+	HTML: `
+<p>A Code Insights series you set an alert on crossed its threshold.</p>
+
+<p>
+  Query: <code>{{.Query}}</code><br>
+  {{ if .EvaluationWindowDays }}Change over the last {{.EvaluationWindowDays}} days{{ else }}Latest value{{ end }}: <strong>{{.Value}}</strong><br>
+  Alert condition: {{.Direction}} {{.Threshold}}
+</p>
+
+<p><a href="{{.SearchURL}}">View search on Sourcegraph</a></p>
+
+<p style="color: #5E6E8C">You are receiving this notification because you created an alert on a Code Insights series.</p>
+`,
+})
+
+func sendAlertEmail(ctx context.Context, n alertNotification) error {
+	searchURL, err := alertSearchURL(ctx, n.Series.Query)
+	if err != nil {
+		return err
+	}
+	email, err := api.InternalClient.UserEmailsGetEmail(ctx, n.Alert.UserID)
+	if err != nil {
+		return errors.Errorf("InternalClient.UserEmailsGetEmail for userID=%d: %w", n.Alert.UserID, err)
+	}
+	if email == nil {
+		return errors.Errorf("unable to send email to user ID %d with unknown email address", n.Alert.UserID)
+	}
+	return api.InternalClient.SendEmail(ctx, txtypes.Message{
+		To:       []string{*email},
+		Template: alertEmailTemplates,
+		Data: alertEmailTemplateData{
+			Query:                n.Series.Query,
+			Threshold:            n.Alert.Threshold,
+			Direction:            alertDirection(n.Alert.Direction),
+			EvaluationWindowDays: n.Alert.EvaluationWindowDays,
+			Value:                n.Value,
+			SearchURL:            searchURL,
+		},
+	})
+}
+
+func alertSearchURL(ctx context.Context, query string) (string, error) {
+	externalURLStr, err := api.InternalClient.ExternalURL(ctx)
+	if err != nil {
+		return "", errors.Errorf("failed to get ExternalURL: %w", err)
+	}
+	externalURL, err := url.Parse(externalURLStr)
+	if err != nil {
+		return "", errors.Errorf("failed to get ExternalURL: %w", err)
+	}
+	u := externalURL.ResolveReference(&url.URL{Path: "search"})
+	q := u.Query()
+	q.Set("q", query)
+	q.Set("utm_source", "code-insights-alert")
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// alertWebhookPayload is the JSON body posted to the webhook URL of a triggered alert.
+type alertWebhookPayload struct {
+	AlertID              int     `json:"alertId"`
+	SeriesID             string  `json:"seriesId"`
+	Query                string  `json:"query"`
+	Threshold            float64 `json:"threshold"`
+	Direction            string  `json:"direction"`
+	EvaluationWindowDays int     `json:"evaluationWindowDays"`
+	Value                float64 `json:"value"`
+}
+
+func sendAlertWebhook(ctx context.Context, n alertNotification) error {
+	body, err := json.Marshal(alertWebhookPayload{
+		AlertID:              n.Alert.ID,
+		SeriesID:             n.Alert.SeriesID,
+		Query:                n.Series.Query,
+		Threshold:            n.Alert.Threshold,
+		Direction:            string(n.Alert.Direction),
+		EvaluationWindowDays: n.Alert.EvaluationWindowDays,
+		Value:                n.Value,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, *n.Alert.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := httpcli.ExternalDoer.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -6,7 +6,6 @@ import (
 	"os"
 	"strconv"
 
-	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
 
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/compression"
 
@@ -26,7 +25,7 @@ import (
 
 // GetBackgroundJobs is the main entrypoint which starts background jobs for code insights. It is
 // called from the worker service.
-func GetBackgroundJobs(ctx context.Context, mainAppDB *sql.DB, insightsDB *sql.DB) []goroutine.BackgroundRoutine {
+func GetBackgroundJobs(ctx context.Context, mainAppDB *sql.DB, insightsDB *sql.DB, storage store.SeriesStorage) []goroutine.BackgroundRoutine {
 	insightPermStore := store.NewInsightPermissionStore(mainAppDB)
 	insightsStore := store.New(insightsDB, insightPermStore)
 
@@ -59,6 +58,14 @@ func GetBackgroundJobs(ctx context.Context, mainAppDB *sql.DB, insightsDB *sql.D
 		queryrunner.NewCleaner(ctx, workerBaseStore, observationContext),
 
 		// TODO(slimsag): future: register another worker here for webhook querying.
+
+		// Register the background goroutine which maintains the storage of the series points,
+		// e.g. by creating partitions ahead of time.
+		newSeriesStorageMaintainer(ctx, insightsDB, storage, observationContext),
+
+		// Register the background goroutine which downsamples old series points according to the
+		// configured retention.
+		newSeriesPointsRetentionJanitor(ctx, insightsStore, observationContext),
 	}
 
 	// todo(insights) add setting to disable this indexer
@@ -71,7 +78,8 @@ func GetBackgroundJobs(ctx context.Context, mainAppDB *sql.DB, insightsDB *sql.D
 		routines = append(routines, newInsightHistoricalEnqueuer(ctx, workerBaseStore, insightsMetadataStore, insightsStore, observationContext))
 	}
 
-	routines = append(routines, discovery.NewMigrateSettingInsightsJob(ctx, mainAppDB, insightsDB))
+	// Register the background goroutine which evaluates series alerts and notifies their owners.
+	routines = append(routines, newInsightAlertEvaluator(ctx, mainAppDB, store.NewAlertStore(insightsDB), insightsMetadataStore, insightsStore, observationContext))
 
 	return routines
 }
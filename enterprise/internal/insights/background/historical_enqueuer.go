@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -145,6 +146,10 @@ func newInsightHistoricalEnqueuer(ctx context.Context, workerBaseStore *basestor
 		frameFilter: compression.NewHistoricalFilter(true, maxTime, insightsStore.Handle().DB()),
 
 		allReposIterator: iterator.ForEach,
+
+		budget: func() int {
+			return conf.Get().InsightsHistoricalBudget
+		},
 	}
 
 	// We use a periodic goroutine here just for metrics tracking. We specify 5s here so it runs as
@@ -231,14 +236,77 @@ type historicalEnqueuer struct {
 	// The iterator to use for walking over all repositories on Sourcegraph.
 	allReposIterator func(ctx context.Context, each func(repoName string) error) error
 	limiter          *rate.Limiter
+
+	// budget describes the maximum number of queryrunner jobs that may be enqueued in a single
+	// run of the handler. Zero means unlimited.
+	budget func() int
+}
+
+// errBudgetExhausted is returned from the repository iteration when the backfill budget of the
+// current run has been spent. It is not a failure: the remaining work is picked up in the next run.
+var errBudgetExhausted = errors.New("insights historical backfill budget exhausted")
+
+// backfillProgress tracks the cost spent by a single run of the historical enqueuer. The cost of
+// a backfill is the number of queryrunner jobs enqueued for it.
+type backfillProgress struct {
+	budget  int
+	total   int
+	spent   map[string]int
+	cursors map[string]backfillCursor
+}
+
+// backfillCursor is the position a backfill has reached: the first frame frames of the repository
+// with the given ID, and all repositories with a lower ID, have been enqueued.
+type backfillCursor struct {
+	repoID api.RepoID
+	frame  int
+}
+
+func newBackfillProgress(budget int) *backfillProgress {
+	return &backfillProgress{budget: budget, spent: map[string]int{}, cursors: map[string]backfillCursor{}}
+}
+
+// cursor returns the position the backfill of the given series has reached, either in this run or
+// in a previous one that exhausted its budget.
+func (p *backfillProgress) cursor(series itypes.InsightSeries) backfillCursor {
+	if c, ok := p.cursors[series.SeriesID]; ok {
+		return c
+	}
+	return backfillCursor{repoID: series.BackfillCursorRepoID, frame: series.BackfillCursorFrame}
+}
+
+func (p *backfillProgress) advance(seriesID string, repoID api.RepoID, frame int) {
+	p.cursors[seriesID] = backfillCursor{repoID: repoID, frame: frame}
+}
+
+// exhausted returns true if no more jobs may be enqueued in this run.
+func (p *backfillProgress) exhausted() bool {
+	return p.budget > 0 && p.total >= p.budget
+}
+
+// fits returns true if a cost may still be spent entirely in this run.
+func (p *backfillProgress) fits(cost int) bool {
+	return p.budget <= 0 || p.total+cost <= p.budget
+}
+
+func (p *backfillProgress) spend(seriesID string) {
+	p.spent[seriesID]++
+	p.total++
 }
 
 func (h *historicalEnqueuer) Handler(ctx context.Context) error {
 	// Discover all insights on the instance.
-	foundInsights, err := h.dataSeriesStore.GetDataSeries(ctx, store.GetDataSeriesArgs{BackfillIncomplete: true, GlobalOnly: true})
+	foundInsights, err := h.dataSeriesStore.GetDataSeries(ctx, store.GetDataSeriesArgs{BackfillIncomplete: true, GlobalOnly: true, GenerationMethod: itypes.GenerationMethodSearch})
 	if err != nil {
 		return errors.Wrap(err, "Discover")
 	}
+	foundInsights = h.skipRepositoryQueryBackfill(ctx, foundInsights)
+
+	// Series are backfilled oldest first, so that when the budget is exhausted the series that
+	// have been waiting the longest make progress first.
+	sort.SliceStable(foundInsights, func(i, j int) bool {
+		return foundInsights[i].CreatedAt.Before(foundInsights[j].CreatedAt)
+	})
 
 	// Deduplicate series that may be unique (e.g. different name/description) but do not have
 	// unique data (i.e. use the same exact search query or webhook URL.)
@@ -257,19 +325,172 @@ func (h *historicalEnqueuer) Handler(ctx context.Context) error {
 		uniqueSeries[seriesID] = series
 		sortedSeriesIDs = append(sortedSeriesIDs, seriesID)
 	}
-	if err := h.buildFrames(ctx, uniqueSeries, sortedSeriesIDs); err != nil {
+	if err := h.estimateCosts(ctx, uniqueSeries, sortedSeriesIDs); err != nil {
 		multi = multierror.Append(multi, err)
 	}
-	if err == nil {
+
+	progress := newBackfillProgress(h.budget())
+	err = h.buildFrames(ctx, uniqueSeries, sortedSeriesIDs, progress)
+	h.recordSpentCosts(ctx, uniqueSeries, progress)
+	switch {
+	case errors.Is(err, errBudgetExhausted):
+		// The next run resumes every series from where this one stopped, so that the jobs
+		// enqueued in this run are not enqueued again.
+		h.recordCursors(ctx, uniqueSeries, progress)
+		log15.Info("insights: historical backfill budget exhausted, continuing in next run", "budget", progress.budget)
+	case err != nil:
+		multi = multierror.Append(multi, err)
+	default:
 		// we successfully performed a full repo iteration without any "hard" errors, so we will update the metadata
 		// of each insight series to reflect they have seen a full iteration. This does not mean they were necessarily successful,
 		// only that they had a chance to queue up queries for each repo.
 		h.markInsightsComplete(ctx, foundInsights)
 	}
 
+	if err := h.backfillLanguageStats(ctx, progress); err != nil {
+		multi = multierror.Append(multi, err)
+	}
+
+	if err := h.skipTopRepositoriesBackfill(ctx); err != nil {
+		multi = multierror.Append(multi, err)
+	}
+
 	return multi
 }
 
+// backfillLanguageStats enqueues one queryrunner job per historical frame of every language
+// statistics series that has not been backfilled yet. Unlike for search series, a single job
+// records the language statistics of all repositories of the series, so there is no need to
+// iterate over repositories here.
+func (h *historicalEnqueuer) backfillLanguageStats(ctx context.Context, progress *backfillProgress) error {
+	foundSeries, err := h.dataSeriesStore.GetDataSeries(ctx, store.GetDataSeriesArgs{BackfillIncomplete: true, GenerationMethod: itypes.GenerationMethodLanguageStats})
+	if err != nil {
+		return errors.Wrap(err, "Discover language statistics series")
+	}
+	foundSeries = h.skipRepositoryQueryBackfill(ctx, foundSeries)
+
+	var multi error
+	for _, series := range foundSeries {
+		frames := FirstOfMonthFrames(backfillFrames, series.CreatedAt.Truncate(time.Hour*24))
+		// A series is backfilled in a single run, so that it is never enqueued twice.
+		if !progress.fits(len(frames)) {
+			return nil
+		}
+		if series.BackfillEstimatedCost == nil {
+			if series, err = h.dataSeriesStore.SetBackfillEstimatedCost(ctx, series, len(frames)); err != nil {
+				multi = multierror.Append(multi, errors.Wrap(err, "SetBackfillEstimatedCost"))
+				continue
+			}
+		}
+
+		var (
+			spent      int
+			enqueueErr error
+		)
+		for _, frame := range frames {
+			execution := compression.QueryExecution{RecordingTime: frame.From}
+			job := execution.ToQueueJob(series.SeriesID, "", priority.Unindexed, priority.FromTimeInterval(frame.From, series.CreatedAt))
+			if enqueueErr = h.enqueueQueryRunnerJob(ctx, job); enqueueErr != nil {
+				break
+			}
+			progress.spend(series.SeriesID)
+			spent++
+		}
+		if spent > 0 {
+			if _, err := h.dataSeriesStore.AddBackfillSpentCost(ctx, series, spent); err != nil {
+				log15.Error("insights: failed to record backfill progress", "series_id", series.SeriesID, "error", err)
+			}
+		}
+		if enqueueErr != nil {
+			multi = multierror.Append(multi, errors.Wrapf(enqueueErr, "failed to enqueue language statistics backfill for series_id: %s", series.SeriesID))
+			continue
+		}
+		h.markInsightsComplete(ctx, []itypes.InsightSeries{series})
+	}
+	return multi
+}
+
+// skipTopRepositoriesBackfill marks every top repositories series as backfilled. Historical
+// searches are performed per repository, so the top N repositories of a past frame cannot be
+// determined and these series are only recorded from their creation onwards.
+func (h *historicalEnqueuer) skipTopRepositoriesBackfill(ctx context.Context) error {
+	foundSeries, err := h.dataSeriesStore.GetDataSeries(ctx, store.GetDataSeriesArgs{BackfillIncomplete: true, GenerationMethod: itypes.GenerationMethodTopRepositories})
+	if err != nil {
+		return errors.Wrap(err, "Discover top repositories series")
+	}
+	h.markInsightsComplete(ctx, foundSeries)
+	return nil
+}
+
+// skipRepositoryQueryBackfill marks the given series that have a repository query as backfilled,
+// and returns the other ones. The repository query of a series is resolved at every recording
+// time, so the repositories it matched at a past frame cannot be determined and these series are
+// only recorded from their creation onwards.
+func (h *historicalEnqueuer) skipRepositoryQueryBackfill(ctx context.Context, series []itypes.InsightSeries) []itypes.InsightSeries {
+	var skipped, remaining []itypes.InsightSeries
+	for _, s := range series {
+		if s.RepositoryQuery != "" {
+			skipped = append(skipped, s)
+		} else {
+			remaining = append(remaining, s)
+		}
+	}
+	h.markInsightsComplete(ctx, skipped)
+	return remaining
+}
+
+// estimateCosts records the estimated backfill cost of every series that has not been estimated
+// yet. The cost is the number of repositories times the number of frames to backfill.
+func (h *historicalEnqueuer) estimateCosts(ctx context.Context, uniqueSeries map[string]itypes.InsightSeries, sortedSeriesIDs []string) error {
+	var pending []string
+	for _, seriesID := range sortedSeriesIDs {
+		if uniqueSeries[seriesID].BackfillEstimatedCost == nil {
+			pending = append(pending, seriesID)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	var numRepos int
+	if err := h.allReposIterator(ctx, func(string) error {
+		numRepos++
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, "CountRepositories")
+	}
+
+	var multi error
+	for _, seriesID := range pending {
+		series, err := h.dataSeriesStore.SetBackfillEstimatedCost(ctx, uniqueSeries[seriesID], numRepos*backfillFrames)
+		if err != nil {
+			multi = multierror.Append(multi, errors.Wrap(err, "SetBackfillEstimatedCost"))
+			continue
+		}
+		uniqueSeries[seriesID] = series
+	}
+	return multi
+}
+
+// recordSpentCosts persists the cost spent on each series in this run, so that backfill progress
+// survives restarts.
+func (h *historicalEnqueuer) recordSpentCosts(ctx context.Context, uniqueSeries map[string]itypes.InsightSeries, progress *backfillProgress) {
+	for seriesID, spent := range progress.spent {
+		if _, err := h.dataSeriesStore.AddBackfillSpentCost(ctx, uniqueSeries[seriesID], spent); err != nil {
+			log15.Error("insights: failed to record backfill progress", "series_id", seriesID, "error", err)
+		}
+	}
+}
+
+// recordCursors persists the position the backfill of each series reached in this run.
+func (h *historicalEnqueuer) recordCursors(ctx context.Context, uniqueSeries map[string]itypes.InsightSeries, progress *backfillProgress) {
+	for seriesID, cursor := range progress.cursors {
+		if _, err := h.dataSeriesStore.SetBackfillCursor(ctx, uniqueSeries[seriesID], cursor.repoID, cursor.frame); err != nil {
+			log15.Error("insights: failed to record backfill cursor", "series_id", seriesID, "error", err)
+		}
+	}
+}
+
 func (h *historicalEnqueuer) markInsightsComplete(ctx context.Context, completed []itypes.InsightSeries) {
 	for _, series := range completed {
 		_, err := h.dataSeriesStore.StampBackfill(ctx, series)
@@ -287,18 +508,22 @@ func (h *historicalEnqueuer) markInsightsComplete(ctx context.Context, completed
 // It is only called if there is at least one insights series defined.
 //
 // It will return instantly if there are no unique series.
-func (h *historicalEnqueuer) buildFrames(ctx context.Context, uniqueSeries map[string]itypes.InsightSeries, sortedSeriesIDs []string) error {
+func (h *historicalEnqueuer) buildFrames(ctx context.Context, uniqueSeries map[string]itypes.InsightSeries, sortedSeriesIDs []string, progress *backfillProgress) error {
 	if len(uniqueSeries) == 0 {
 		return nil // nothing to do.
 	}
 	var multi error
 
-	hardErr := h.allReposIterator(ctx, h.buildForRepo(ctx, uniqueSeries, sortedSeriesIDs, multi))
+	hardErr := h.allReposIterator(ctx, h.buildForRepo(ctx, uniqueSeries, sortedSeriesIDs, progress, multi))
 	return hardErr
 }
 
-func (h *historicalEnqueuer) buildForRepo(ctx context.Context, uniqueSeries map[string]itypes.InsightSeries, sortedSeriesIDs []string, softErr error) func(repoName string) error {
+func (h *historicalEnqueuer) buildForRepo(ctx context.Context, uniqueSeries map[string]itypes.InsightSeries, sortedSeriesIDs []string, progress *backfillProgress, softErr error) func(repoName string) error {
 	return func(repoName string) error {
+		if progress.exhausted() {
+			return errBudgetExhausted
+		}
+
 		// Lookup the repository (we need its database ID)
 		repo, err := h.repoStore.GetByName(ctx, api.RepoName(repoName))
 		if err != nil {
@@ -328,15 +553,33 @@ func (h *historicalEnqueuer) buildForRepo(ctx context.Context, uniqueSeries map[
 		for _, seriesID := range sortedSeriesIDs {
 			series := uniqueSeries[seriesID]
 
-			frames := FirstOfMonthFrames(12, series.CreatedAt.Truncate(time.Hour*24))
+			// Skip the frames that were enqueued by a previous run that exhausted its budget.
+			// Repositories are iterated in ID order, see allReposIterator.
+			cursor := progress.cursor(series)
+			if repo.ID < cursor.repoID {
+				continue
+			}
+			var skip int
+			if repo.ID == cursor.repoID {
+				skip = cursor.frame
+			}
+
+			frames := FirstOfMonthFrames(backfillFrames, series.CreatedAt.Truncate(time.Hour*24))
 
 			log15.Debug("insights: starting frames", "repo_id", repo.ID, "series_id", series.SeriesID, "frames", frames)
 			plan := h.frameFilter.FilterFrames(ctx, frames, repo.ID)
 			log15.Debug("insights: sampling historical data frames", "repo_id", repo.ID, "series_id", series.SeriesID, "frames", frames)
 
-			for i := len(plan.Executions) - 1; i >= 0; i-- {
+			for i, done := len(plan.Executions)-1, 0; i >= 0; i, done = i-1, done+1 {
 				queryExecution := plan.Executions[i]
 
+				if done < skip {
+					continue
+				}
+				if progress.exhausted() {
+					return errBudgetExhausted
+				}
+
 				err := h.limiter.Wait(ctx)
 				if err != nil {
 					return err
@@ -355,6 +598,7 @@ func (h *historicalEnqueuer) buildForRepo(ctx context.Context, uniqueSeries map[
 					softErr = multierror.Append(softErr, err)
 					// In this case we will assume the point does not exist and query for it anyway.
 				} else if numDataPoints > 0 {
+					progress.advance(seriesID, repo.ID, done+1)
 					continue
 				}
 
@@ -365,14 +609,15 @@ func (h *historicalEnqueuer) buildForRepo(ctx context.Context, uniqueSeries map[
 					firstHEADCommit: firstHEADCommit,
 					seriesID:        seriesID,
 					series:          series,
+					progress:        progress,
 				})
-				if err != nil {
-					softErr = multierror.Append(softErr, err)
-					continue
-				}
 				if hardErr != nil {
 					return multierror.Append(softErr, hardErr)
 				}
+				progress.advance(seriesID, repo.ID, done+1)
+				if err != nil {
+					softErr = multierror.Append(softErr, err)
+				}
 			}
 
 		}
@@ -395,8 +640,14 @@ type buildSeriesContext struct {
 	// The series we're building historical data for.
 	seriesID string
 	series   itypes.InsightSeries
+
+	// The cost spent in the current run, charged for every enqueued job.
+	progress *backfillProgress
 }
 
+// backfillFrames is the number of monthly frames that are backfilled for every series.
+const backfillFrames = 12
+
 // FirstOfMonthFrames builds a set of frames with a specific number of elements, such that all of the
 // starting times of each frame < current will fall on the first of a month.
 func FirstOfMonthFrames(numPoints int, current time.Time) []compression.Frame {
@@ -431,16 +682,7 @@ func FirstOfMonthFrames(numPoints int, current time.Time) []compression.Frame {
 // It may return both hard errors (e.g. DB connection failure, future series are unlikely to build)
 // and soft errors (e.g. user's search query is invalid, future series are likely to build.)
 func (h *historicalEnqueuer) buildSeries(ctx context.Context, bctx *buildSeriesContext) (hardErr, softErr error) {
-	query := bctx.series.Query
-	// TODO(slimsag): future: use the search query parser here to avoid any false-positives like a
-	// search query with `content:"repo:"`.
-	if strings.Contains(query, "repo:") {
-		// We need to specify the repo: filter ourselves, so rewriting their query which already
-		// contains this would be complex (we would need to enumerate all repos their query would
-		// have matched the same way the search backend would've). We don't support this today.
-		//
-		// Another possibility is that they are specifying a non-default branch with the `repo:`
-		// filter. We would need to handle this below if so - we don't today.
+	if !SupportsHistoricalQuery(bctx.series.Query) {
 		return nil, nil
 	}
 
@@ -506,15 +748,37 @@ func (h *historicalEnqueuer) buildSeries(ctx context.Context, bctx *buildSeriesC
 		revision = string(nearestCommit.ID)
 	}
 
-	// Build the search query we will run. The most important part here is
-	query = withCountUnlimited(query)
-	query = fmt.Sprintf("%s repo:^%s$@%s", query, regexp.QuoteMeta(repoName), revision)
-
+	query := HistoricalQuery(bctx.series.Query, repoName, revision)
 	job := bctx.execution.ToQueueJob(bctx.seriesID, query, priority.Unindexed, priority.FromTimeInterval(bctx.execution.RecordingTime, bctx.series.CreatedAt))
 	hardErr = h.enqueueQueryRunnerJob(ctx, job)
+	if hardErr == nil && bctx.progress != nil {
+		bctx.progress.spend(bctx.seriesID)
+	}
 	return
 }
 
+// SupportsHistoricalQuery reports whether historical data can be computed for the given series
+// query with HistoricalQuery.
+func SupportsHistoricalQuery(query string) bool {
+	// TODO(slimsag): future: use the search query parser here to avoid any false-positives like a
+	// search query with `content:"repo:"`.
+	//
+	// We need to specify the repo: filter ourselves, so rewriting their query which already
+	// contains this would be complex (we would need to enumerate all repos their query would
+	// have matched the same way the search backend would've). We don't support this today.
+	//
+	// Another possibility is that they are specifying a non-default branch with the `repo:`
+	// filter. We would need to handle this if so - we don't today.
+	return !strings.Contains(query, "repo:")
+}
+
+// HistoricalQuery returns the search query which computes the value of the given series query
+// for a single repository at the given revision.
+func HistoricalQuery(query, repoName, revision string) string {
+	query = withCountUnlimited(query)
+	return fmt.Sprintf("%s repo:^%s$@%s", query, regexp.QuoteMeta(repoName), revision)
+}
+
 // cachedGitFirstEverCommit is a simple in-memory cache for gitFirstEverCommit calls. It does so
 // using a map, and entries are never evicted because they are expected to be small and in general
 // unchanging.
@@ -25,12 +25,18 @@ type testParams struct {
 	frames                int
 	recordSleepOperations bool
 	haveData              bool
+	budget                int
+	languageStatsSeries   []itypes.InsightSeries
+	backfillCursors       map[string]backfillCursor
 }
 
 type testResults struct {
 	allReposIteratorCalls int
 	reposGetByName        int
 	operations            []string
+	estimatedCosts        map[string]int
+	spentCosts            map[string]int
+	backfillCursors       map[string]string
 }
 
 func testHistoricalEnqueuer(t *testing.T, p *testParams) *testResults {
@@ -50,24 +56,65 @@ func testHistoricalEnqueuer(t *testing.T, p *testParams) *testResults {
 	}
 
 	dataSeriesStore := store.NewMockDataSeriesStore()
-	dataSeriesStore.GetDataSeriesFunc.SetDefaultReturn([]itypes.InsightSeries{
-		{
-			ID:                 1,
-			SeriesID:           "series1",
-			Query:              "query1",
-			NextRecordingAfter: clock().Add(-1 * time.Hour),
-			CreatedAt:          clock(),
-			OldestHistoricalAt: clock().Add(-time.Hour * 24 * 365),
-		},
-		{
-			ID:                 2,
-			SeriesID:           "series2",
-			Query:              "query2",
-			NextRecordingAfter: clock().Add(1 * time.Hour),
-			CreatedAt:          clock(),
-			OldestHistoricalAt: clock().Add(-time.Hour * 24 * 365),
-		},
-	}, nil)
+	dataSeriesStore.GetDataSeriesFunc.SetDefaultHook(func(ctx context.Context, args store.GetDataSeriesArgs) ([]itypes.InsightSeries, error) {
+		if args.GenerationMethod == itypes.GenerationMethodTopRepositories {
+			return nil, nil
+		}
+		if args.GenerationMethod == itypes.GenerationMethodLanguageStats {
+			return p.languageStatsSeries, nil
+		}
+		series := []itypes.InsightSeries{
+			{
+				ID:                 1,
+				SeriesID:           "series1",
+				Query:              "query1",
+				NextRecordingAfter: clock().Add(-1 * time.Hour),
+				CreatedAt:          clock(),
+				OldestHistoricalAt: clock().Add(-time.Hour * 24 * 365),
+			},
+			{
+				ID:                 2,
+				SeriesID:           "series2",
+				Query:              "query2",
+				NextRecordingAfter: clock().Add(1 * time.Hour),
+				CreatedAt:          clock(),
+				OldestHistoricalAt: clock().Add(-time.Hour * 24 * 365),
+			},
+		}
+		for i := range series {
+			if cursor, ok := p.backfillCursors[series[i].SeriesID]; ok {
+				series[i].BackfillCursorRepoID = cursor.repoID
+				series[i].BackfillCursorFrame = cursor.frame
+			}
+		}
+		return series, nil
+	})
+	dataSeriesStore.SetBackfillEstimatedCostFunc.SetDefaultHook(func(ctx context.Context, series itypes.InsightSeries, cost int) (itypes.InsightSeries, error) {
+		if r.estimatedCosts == nil {
+			r.estimatedCosts = map[string]int{}
+		}
+		r.estimatedCosts[series.SeriesID] = cost
+		series.BackfillEstimatedCost = &cost
+		return series, nil
+	})
+	dataSeriesStore.AddBackfillSpentCostFunc.SetDefaultHook(func(ctx context.Context, series itypes.InsightSeries, cost int) (itypes.InsightSeries, error) {
+		if r.spentCosts == nil {
+			r.spentCosts = map[string]int{}
+		}
+		r.spentCosts[series.SeriesID] += cost
+		series.BackfillSpentCost += cost
+		return series, nil
+	})
+
+	dataSeriesStore.SetBackfillCursorFunc.SetDefaultHook(func(ctx context.Context, series itypes.InsightSeries, repoID api.RepoID, frame int) (itypes.InsightSeries, error) {
+		if r.backfillCursors == nil {
+			r.backfillCursors = map[string]string{}
+		}
+		r.backfillCursors[series.SeriesID] = fmt.Sprintf("repo=%d frame=%d", repoID, frame)
+		series.BackfillCursorRepoID = repoID
+		series.BackfillCursorFrame = frame
+		return series, nil
+	})
 
 	dataFrameFilter := compression.NoopFilter{}
 
@@ -141,6 +188,7 @@ func testHistoricalEnqueuer(t *testing.T, p *testParams) *testResults {
 		framesToBackfill:      func() int { return p.frames },
 		frameLength:           func() time.Duration { return 7 * 24 * time.Hour },
 		dataSeriesStore:       dataSeriesStore,
+		budget:                func() int { return p.budget },
 	}
 
 	// If we do an iteration without any insights or repos, we should expect no sleep calls to be made.
@@ -153,13 +201,19 @@ func testHistoricalEnqueuer(t *testing.T, p *testParams) *testResults {
 func Test_historicalEnqueuer(t *testing.T) {
 	// Test that when no insights are defined, no work or sleeping is performed.
 	t.Run("no_insights_no_repos", func(t *testing.T) {
-		want := autogold.Want("no_insights_no_repos", &testResults{allReposIteratorCalls: 1})
+		want := autogold.Want("no_insights_no_repos", &testResults{allReposIteratorCalls: 2, estimatedCosts: map[string]int{
+			"series1": 0,
+			"series2": 0,
+		}})
 		want.Equal(t, testHistoricalEnqueuer(t, &testParams{}))
 	})
 
 	// Test that when insights are defined, but no repos exist, no work or sleeping is performed.
 	t.Run("some_insights_no_repos", func(t *testing.T) {
-		want := autogold.Want("some_insights_no_repos", &testResults{allReposIteratorCalls: 1})
+		want := autogold.Want("some_insights_no_repos", &testResults{allReposIteratorCalls: 2, estimatedCosts: map[string]int{
+			"series1": 0,
+			"series2": 0,
+		}})
 		want.Equal(t, testHistoricalEnqueuer(t, &testParams{
 			settings: testRealGlobalSettings,
 		}))
@@ -168,7 +222,13 @@ func Test_historicalEnqueuer(t *testing.T) {
 	// Test that when there is no work to perform (because all insights have historical data) that
 	// no work is performed.
 	t.Run("no_work", func(t *testing.T) {
-		want := autogold.Want("no_work", &testResults{allReposIteratorCalls: 1, reposGetByName: 2})
+		want := autogold.Want("no_work", &testResults{
+			allReposIteratorCalls: 2, reposGetByName: 2,
+			estimatedCosts: map[string]int{
+				"series1": 24,
+				"series2": 24,
+			},
+		})
 		want.Equal(t, testHistoricalEnqueuer(t, &testParams{
 			settings:              testRealGlobalSettings,
 			numRepos:              2,
@@ -186,7 +246,7 @@ func Test_historicalEnqueuer(t *testing.T) {
 	//
 	t.Run("no_data", func(t *testing.T) {
 		want := autogold.Want("no_data", &testResults{
-			allReposIteratorCalls: 1, reposGetByName: 2,
+			allReposIteratorCalls: 2, reposGetByName: 2,
 			operations: []string{
 				`enqueueQueryRunnerJob("2021-01-01T00:00:00Z", "query1 count:all repo:^repo/0$@")`,
 				`enqueueQueryRunnerJob("2020-12-01T00:00:00Z", "query1 count:all repo:^repo/0$@")`,
@@ -237,12 +297,188 @@ func Test_historicalEnqueuer(t *testing.T) {
 				`enqueueQueryRunnerJob("2020-03-01T00:00:00Z", "query2 count:all repo:^repo/1$@")`,
 				`enqueueQueryRunnerJob("2020-02-01T00:00:00Z", "query2 count:all repo:^repo/1$@")`,
 			},
+			estimatedCosts: map[string]int{
+				"series1": 24,
+				"series2": 24,
+			},
+			spentCosts: map[string]int{
+				"series1": 24,
+				"series2": 24,
+			},
+		})
+		want.Equal(t, testHistoricalEnqueuer(t, &testParams{
+			settings:              testRealGlobalSettings,
+			numRepos:              2,
+			frames:                2,
+			recordSleepOperations: true,
+		}))
+	})
+
+	// Test that when a budget is configured, we stop enqueueing jobs once it is spent and record
+	// the cost spent on each series so far.
+	t.Run("budget_exhausted", func(t *testing.T) {
+		want := autogold.Want("budget_exhausted", &testResults{
+			allReposIteratorCalls: 2, reposGetByName: 1,
+			operations: []string{
+				`enqueueQueryRunnerJob("2021-01-01T00:00:00Z", "query1 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-12-01T00:00:00Z", "query1 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-11-01T00:00:00Z", "query1 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-10-01T00:00:00Z", "query1 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-09-01T00:00:00Z", "query1 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-08-01T00:00:00Z", "query1 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-07-01T00:00:00Z", "query1 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-06-01T00:00:00Z", "query1 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-05-01T00:00:00Z", "query1 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-04-01T00:00:00Z", "query1 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-03-01T00:00:00Z", "query1 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-02-01T00:00:00Z", "query1 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2021-01-01T00:00:00Z", "query2 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-12-01T00:00:00Z", "query2 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-11-01T00:00:00Z", "query2 count:all repo:^repo/0$@")`,
+			},
+			estimatedCosts: map[string]int{
+				"series1": 24,
+				"series2": 24,
+			},
+			spentCosts: map[string]int{
+				"series1": 12,
+				"series2": 3,
+			},
+			backfillCursors: map[string]string{
+				"series1": "repo=0 frame=12",
+				"series2": "repo=0 frame=3",
+			},
+		})
+		want.Equal(t, testHistoricalEnqueuer(t, &testParams{
+			settings:              testRealGlobalSettings,
+			numRepos:              2,
+			frames:                2,
+			recordSleepOperations: true,
+			budget:                15,
+		}))
+	})
+
+	// Test that a run following one that exhausted its budget resumes every series from the
+	// cursor recorded by it, instead of enqueueing the same jobs again.
+	t.Run("budget_exhausted_resume", func(t *testing.T) {
+		want := autogold.Want("budget_exhausted_resume", &testResults{
+			allReposIteratorCalls: 2, reposGetByName: 2,
+			operations: []string{
+				`enqueueQueryRunnerJob("2020-10-01T00:00:00Z", "query2 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-09-01T00:00:00Z", "query2 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-08-01T00:00:00Z", "query2 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-07-01T00:00:00Z", "query2 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-06-01T00:00:00Z", "query2 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-05-01T00:00:00Z", "query2 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-04-01T00:00:00Z", "query2 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-03-01T00:00:00Z", "query2 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-02-01T00:00:00Z", "query2 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2021-01-01T00:00:00Z", "query1 count:all repo:^repo/1$@")`,
+				`enqueueQueryRunnerJob("2020-12-01T00:00:00Z", "query1 count:all repo:^repo/1$@")`,
+				`enqueueQueryRunnerJob("2020-11-01T00:00:00Z", "query1 count:all repo:^repo/1$@")`,
+				`enqueueQueryRunnerJob("2020-10-01T00:00:00Z", "query1 count:all repo:^repo/1$@")`,
+				`enqueueQueryRunnerJob("2020-09-01T00:00:00Z", "query1 count:all repo:^repo/1$@")`,
+				`enqueueQueryRunnerJob("2020-08-01T00:00:00Z", "query1 count:all repo:^repo/1$@")`,
+			},
+			estimatedCosts: map[string]int{
+				"series1": 24,
+				"series2": 24,
+			},
+			spentCosts: map[string]int{
+				"series1": 6,
+				"series2": 9,
+			},
+			backfillCursors: map[string]string{
+				"series1": "repo=1 frame=6",
+				"series2": "repo=0 frame=12",
+			},
 		})
 		want.Equal(t, testHistoricalEnqueuer(t, &testParams{
 			settings:              testRealGlobalSettings,
 			numRepos:              2,
 			frames:                2,
 			recordSleepOperations: true,
+			budget:                15,
+			backfillCursors: map[string]backfillCursor{
+				"series1": {repoID: 0, frame: 12},
+				"series2": {repoID: 0, frame: 3},
+			},
+		}))
+	})
+
+	// Test that language statistics series are backfilled with one job per frame, without a
+	// search query, and only if the whole series fits in the remaining budget.
+	t.Run("language_stats", func(t *testing.T) {
+		want := autogold.Want("language_stats", &testResults{
+			allReposIteratorCalls: 2, reposGetByName: 1,
+			operations: []string{
+				`enqueueQueryRunnerJob("2021-01-01T00:00:00Z", "query1 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-12-01T00:00:00Z", "query1 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-11-01T00:00:00Z", "query1 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-10-01T00:00:00Z", "query1 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-09-01T00:00:00Z", "query1 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-08-01T00:00:00Z", "query1 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-07-01T00:00:00Z", "query1 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-06-01T00:00:00Z", "query1 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-05-01T00:00:00Z", "query1 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-04-01T00:00:00Z", "query1 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-03-01T00:00:00Z", "query1 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-02-01T00:00:00Z", "query1 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2021-01-01T00:00:00Z", "query2 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-12-01T00:00:00Z", "query2 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-11-01T00:00:00Z", "query2 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-10-01T00:00:00Z", "query2 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-09-01T00:00:00Z", "query2 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-08-01T00:00:00Z", "query2 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-07-01T00:00:00Z", "query2 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-06-01T00:00:00Z", "query2 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-05-01T00:00:00Z", "query2 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-04-01T00:00:00Z", "query2 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-03-01T00:00:00Z", "query2 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-02-01T00:00:00Z", "query2 count:all repo:^repo/0$@")`,
+				`enqueueQueryRunnerJob("2020-02-01T00:00:00Z", "")`,
+				`enqueueQueryRunnerJob("2020-03-01T00:00:00Z", "")`,
+				`enqueueQueryRunnerJob("2020-04-01T00:00:00Z", "")`,
+				`enqueueQueryRunnerJob("2020-05-01T00:00:00Z", "")`,
+				`enqueueQueryRunnerJob("2020-06-01T00:00:00Z", "")`,
+				`enqueueQueryRunnerJob("2020-07-01T00:00:00Z", "")`,
+				`enqueueQueryRunnerJob("2020-08-01T00:00:00Z", "")`,
+				`enqueueQueryRunnerJob("2020-09-01T00:00:00Z", "")`,
+				`enqueueQueryRunnerJob("2020-10-01T00:00:00Z", "")`,
+				`enqueueQueryRunnerJob("2020-11-01T00:00:00Z", "")`,
+				`enqueueQueryRunnerJob("2020-12-01T00:00:00Z", "")`,
+				`enqueueQueryRunnerJob("2021-01-01T00:00:00Z", "")`,
+			},
+			estimatedCosts: map[string]int{
+				"series1": 12,
+				"series2": 12,
+				"series3": 12,
+			},
+			spentCosts: map[string]int{
+				"series1": 12,
+				"series2": 12,
+				"series3": 12,
+			},
+		})
+		want.Equal(t, testHistoricalEnqueuer(t, &testParams{
+			numRepos: 1,
+			budget:   36,
+			languageStatsSeries: []itypes.InsightSeries{
+				{
+					ID:               3,
+					SeriesID:         "series3",
+					CreatedAt:        time.Date(2021, 1, 1, 0, 0, 1, 0, time.UTC),
+					Repositories:     []string{"repo/0"},
+					GenerationMethod: itypes.GenerationMethodLanguageStats,
+				},
+				{
+					ID:               4,
+					SeriesID:         "series4",
+					CreatedAt:        time.Date(2021, 1, 1, 0, 0, 1, 0, time.UTC),
+					Repositories:     []string{"repo/0"},
+					GenerationMethod: itypes.GenerationMethodLanguageStats,
+				},
+			},
 		}))
 	})
 }
@@ -67,7 +67,7 @@ func discoverAndEnqueueInsights(
 
 	log15.Info("enqueuing indexed insight recordings")
 	// this job will do the work of both recording (permanent) queries, and snapshot (ephemeral) queries. We want to try both, so if either has a soft-failure we will attempt both.
-	recordingSeries, err := insightStore.GetDataSeries(ctx, store.GetDataSeriesArgs{NextRecordingBefore: now(), GlobalOnly: true})
+	recordingSeries, err := insightStore.GetDataSeries(ctx, store.GetDataSeriesArgs{NextRecordingBefore: now(), GlobalOnly: true, GenerationMethod: types.GenerationMethodSearch})
 	if err != nil {
 		return errors.Wrap(err, "indexed insight recorder: unable to fetch series for recordings")
 	}
@@ -77,7 +77,7 @@ func discoverAndEnqueueInsights(
 	}
 
 	log15.Info("enqueuing indexed insight snapshots")
-	snapshotSeries, err := insightStore.GetDataSeries(ctx, store.GetDataSeriesArgs{NextSnapshotBefore: now(), GlobalOnly: true})
+	snapshotSeries, err := insightStore.GetDataSeries(ctx, store.GetDataSeriesArgs{NextSnapshotBefore: now(), GlobalOnly: true, GenerationMethod: types.GenerationMethodSearch})
 	if err != nil {
 		return errors.Wrap(err, "indexed insight recorder: unable to fetch series for snapshots")
 	}
@@ -86,6 +86,31 @@ func discoverAndEnqueueInsights(
 		multi = multierror.Append(multi, err)
 	}
 
+	// Top repositories series are global like search series, but the matches of the previous
+	// recording are pruned as soon as a repository drops out of the top N, so they are only
+	// recorded.
+	log15.Info("enqueuing top repositories insight recordings")
+	topRepositoriesSeries, err := insightStore.GetDataSeries(ctx, store.GetDataSeriesArgs{NextRecordingBefore: now(), GlobalOnly: true, GenerationMethod: types.GenerationMethodTopRepositories})
+	if err != nil {
+		return errors.Wrap(err, "indexed insight recorder: unable to fetch top repositories series for recordings")
+	}
+	err = enqueue(ctx, topRepositoriesSeries, store.RecordMode, insightStore.StampRecording, queryRunnerEnqueueJob)
+	if err != nil {
+		multi = multierror.Append(multi, err)
+	}
+
+	// Language statistics series are scoped to their repositories and change slowly, so they are
+	// only recorded and never snapshotted.
+	log15.Info("enqueuing language statistics insight recordings")
+	languageStatsSeries, err := insightStore.GetDataSeries(ctx, store.GetDataSeriesArgs{NextRecordingBefore: now(), GenerationMethod: types.GenerationMethodLanguageStats})
+	if err != nil {
+		return errors.Wrap(err, "indexed insight recorder: unable to fetch language statistics series for recordings")
+	}
+	err = enqueue(ctx, languageStatsSeries, store.RecordMode, insightStore.StampRecording, queryRunnerEnqueueJob)
+	if err != nil {
+		multi = multierror.Append(multi, err)
+	}
+
 	return multi
 }
 
@@ -109,7 +134,7 @@ func enqueue(ctx context.Context, dataSeries []types.InsightSeries, mode store.P
 
 		err := enqueueQueryRunnerJob(ctx, &queryrunner.Job{
 			SeriesID:    seriesID,
-			SearchQuery: withCountUnlimited(series.Query),
+			SearchQuery: jobQuery(series),
 			State:       "queued",
 			Priority:    int(priority.High),
 			Cost:        int(priority.Indexed),
@@ -132,6 +157,15 @@ func enqueue(ctx context.Context, dataSeries []types.InsightSeries, mode store.P
 	return multi
 }
 
+// jobQuery returns the search query of a queryrunner job recording the given series. Language
+// statistics series do not have a search query.
+func jobQuery(series types.InsightSeries) string {
+	if series.GenerationMethod == types.GenerationMethodLanguageStats {
+		return ""
+	}
+	return withCountUnlimited(series.Query)
+}
+
 // withCountUnlimited adds `count:9999999` to the given search query string iff `count:` does not
 // exist in the query string. This is extremely important as otherwise the number of results our
 // search query would return would be incomplete and fluctuate.
@@ -63,6 +63,7 @@ var testRealGlobalSettings = &api.Settings{ID: 1, Contents: `{
 // 1. Webhook insights are not enqueued (not yet supported.)
 // 2. Duplicate insights are deduplicated / do not submit multiple jobs.
 // 3. Jobs are scheduled not to all run at the same time.
+// 4. Language statistics series are recorded without a search query, and never snapshotted.
 //
 func Test_discoverAndEnqueueInsights(t *testing.T) {
 	// Setup the setting store and job enqueuer mocks.
@@ -82,20 +83,36 @@ func Test_discoverAndEnqueueInsights(t *testing.T) {
 
 	dataSeriesStore := store.NewMockDataSeriesStore()
 
-	dataSeriesStore.GetDataSeriesFunc.SetDefaultReturn([]types.InsightSeries{
-		{
-			ID:                 1,
-			SeriesID:           "series1",
-			Query:              "query1",
-			NextRecordingAfter: now.Add(-1 * time.Hour),
-		},
-		{
-			ID:                 2,
-			SeriesID:           "series2",
-			Query:              "query2",
-			NextRecordingAfter: now.Add(1 * time.Hour),
-		},
-	}, nil)
+	dataSeriesStore.GetDataSeriesFunc.SetDefaultHook(func(ctx context.Context, args store.GetDataSeriesArgs) ([]types.InsightSeries, error) {
+		if args.GenerationMethod == types.GenerationMethodTopRepositories {
+			return nil, nil
+		}
+		if args.GenerationMethod == types.GenerationMethodLanguageStats {
+			return []types.InsightSeries{
+				{
+					ID:                 3,
+					SeriesID:           "series3",
+					NextRecordingAfter: now.Add(-1 * time.Hour),
+					Repositories:       []string{"github.com/sourcegraph/sourcegraph"},
+					GenerationMethod:   types.GenerationMethodLanguageStats,
+				},
+			}, nil
+		}
+		return []types.InsightSeries{
+			{
+				ID:                 1,
+				SeriesID:           "series1",
+				Query:              "query1",
+				NextRecordingAfter: now.Add(-1 * time.Hour),
+			},
+			{
+				ID:                 2,
+				SeriesID:           "series2",
+				Query:              "query2",
+				NextRecordingAfter: now.Add(1 * time.Hour),
+			},
+		}, nil
+	})
 
 	if err := discoverAndEnqueueInsights(ctx, clock, dataSeriesStore, enqueueQueryRunnerJob); err != nil {
 		t.Fatal(err)
@@ -178,6 +195,24 @@ func Test_discoverAndEnqueueInsights(t *testing.T) {
     "NumResets": 0,
     "NumFailures": 0,
     "ExecutionLogs": null
+  },
+  {
+    "SeriesID": "series3",
+    "SearchQuery": "",
+    "RecordTime": null,
+    "Cost": 500,
+    "Priority": 10,
+    "PersistMode": "record",
+    "DependentFrames": null,
+    "ID": 0,
+    "State": "queued",
+    "FailureMessage": null,
+    "StartedAt": null,
+    "FinishedAt": null,
+    "ProcessAfter": null,
+    "NumResets": 0,
+    "NumFailures": 0,
+    "ExecutionLogs": null
   }
 ]`).Equal(t, string(enqueuedJSON))
 }
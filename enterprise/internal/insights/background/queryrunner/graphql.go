@@ -98,41 +98,136 @@ type gqlSearchResponse struct {
 
 // search executes the given search query.
 func search(ctx context.Context, query string) (*gqlSearchResponse, error) {
-	var buf bytes.Buffer
-	err := json.NewEncoder(&buf).Encode(graphQLQuery{
+	var res *gqlSearchResponse
+	if err := doGraphQL(ctx, "InsightsSearch", graphQLQuery{
 		Query:     gqlSearchQuery,
 		Variables: gqlSearchVars{Query: query},
-	})
+	}, &res); err != nil {
+		return nil, err
+	}
+	if len(res.Errors) > 0 {
+		return res, errors.Errorf("graphql: errors: %v", res.Errors)
+	}
+	return res, nil
+}
+
+// noRepositoriesAlertTitle is the title of the search alert returned when no repository matched
+// the repo: filter of a query.
+const noRepositoriesAlertTitle = "No repositories satisfied your repo: filter"
+
+// SearchMatchCount executes the given search query and returns its total number of matches. It is
+// used to compute insight values synchronously, without going through the queue.
+//
+// 🚨 SECURITY: The request is performed without authentication. Callers must ensure the query is
+// restricted to repositories the current user has access to.
+func SearchMatchCount(ctx context.Context, query string) (int, error) {
+	results, err := search(ctx, query)
 	if err != nil {
-		return nil, errors.Wrap(err, "Encode")
+		return 0, err
+	}
+	if alert := results.Data.Search.Results.Alert; alert != nil && alert.Title != noRepositoriesAlertTitle {
+		return 0, errors.Errorf("insights query issue: alert: %v query=%q", alert, query)
+	}
+
+	var count int
+	for _, result := range results.Data.Search.Results.Results {
+		decoded, err := decodeResult(result)
+		if err != nil {
+			return 0, errors.Wrapf(err, "for query %q", query)
+		}
+		count += decoded.matchCount()
 	}
+	return count, nil
+}
 
-	url, err := gqlURL("InsightsSearch")
+const gqlComputeQuery = `query Compute(
+	$query: String!,
+) {
+	compute(query: $query) {
+		__typename
+		... on ComputeMatchContext {
+			repository {
+				id
+				name
+			}
+			matches {
+				environment {
+					variable
+					value
+				}
+			}
+		}
+	}
+}`
+
+type gqlComputeResponse struct {
+	Data struct {
+		Compute []struct {
+			Typename   string `json:"__typename"`
+			Repository struct {
+				ID   string
+				Name string
+			}
+			Matches []struct {
+				Environment []gqlComputeEnvironmentEntry
+			}
+		}
+	}
+	Errors []interface{}
+}
+
+type gqlComputeEnvironmentEntry struct {
+	Variable string
+	Value    string
+}
+
+// computeSearch executes the given search query with the compute API, which extracts the values
+// of regexp capture groups from the matches.
+func computeSearch(ctx context.Context, query string) (*gqlComputeResponse, error) {
+	var res *gqlComputeResponse
+	if err := doGraphQL(ctx, "InsightsComputeSearch", graphQLQuery{
+		Query:     gqlComputeQuery,
+		Variables: gqlSearchVars{Query: query},
+	}, &res); err != nil {
+		return nil, err
+	}
+	if len(res.Errors) > 0 {
+		return res, errors.Errorf("graphql: errors: %v", res.Errors)
+	}
+	return res, nil
+}
+
+// doGraphQL sends the given query to the frontend's internal GraphQL API and decodes the response
+// into result.
+func doGraphQL(ctx context.Context, queryName string, query graphQLQuery, result interface{}) error {
+	var buf bytes.Buffer
+	err := json.NewEncoder(&buf).Encode(query)
+	if err != nil {
+		return errors.Wrap(err, "Encode")
+	}
+
+	url, err := gqlURL(queryName)
 	if err != nil {
-		return nil, errors.Wrap(err, "constructing frontend URL")
+		return errors.Wrap(err, "constructing frontend URL")
 	}
 
 	req, err := http.NewRequest("POST", url, &buf)
 	if err != nil {
-		return nil, errors.Wrap(err, "Post")
+		return errors.Wrap(err, "Post")
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := httpcli.InternalDoer.Do(req.WithContext(ctx))
 	if err != nil {
-		return nil, errors.Wrap(err, "Post")
+		return errors.Wrap(err, "Post")
 	}
 	defer resp.Body.Close()
 
-	var res *gqlSearchResponse
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		return nil, errors.Wrap(err, "Decode")
-	}
-	if len(res.Errors) > 0 {
-		return res, errors.Errorf("graphql: errors: %v", res.Errors)
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return errors.Wrap(err, "Decode")
 	}
-	return res, nil
+	return nil
 }
 
 // gqlURL returns the frontend's internal GraphQL API URL, with the given ?queryName parameter
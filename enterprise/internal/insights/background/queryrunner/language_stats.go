@@ -0,0 +1,101 @@
+package queryrunner
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	internalTypes "github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// RepoStore is the subset of the database.Repos() store used to look up the repositories of
+// language statistics series.
+type RepoStore interface {
+	GetByName(ctx context.Context, name api.RepoName) (*internalTypes.Repo, error)
+}
+
+// languageStatsFunc computes the number of bytes of code per language in a repository, at the most
+// recent commit before the given time. It returns a nil map if the repository had no commits yet.
+type languageStatsFunc func(ctx context.Context, repo *internalTypes.Repo, before time.Time) (map[string]uint64, error)
+
+// gitLanguageStats computes language statistics with the same inventory as the repository
+// language statistics in the UI. Inventories are cached per Git tree, so computing them for
+// consecutive points in time is cheap.
+func gitLanguageStats(ctx context.Context, repo *internalTypes.Repo, before time.Time) (map[string]uint64, error) {
+	commits, err := git.Commits(ctx, repo.Name, git.CommitsOptions{N: 1, Before: before.Format(time.RFC3339), DateOrder: true})
+	if err != nil {
+		return nil, errors.Wrap(err, "FindNearestCommit")
+	}
+	if len(commits) == 0 {
+		return nil, nil
+	}
+	inv, err := backend.Repos.GetInventory(ctx, repo, commits[0].ID, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "GetInventory")
+	}
+	bytesPerLanguage := make(map[string]uint64, len(inv.Languages))
+	for _, lang := range inv.Languages {
+		bytesPerLanguage[lang.Name] = lang.TotalBytes
+	}
+	return bytesPerLanguage, nil
+}
+
+// handleLanguageStats records the language statistics of every repository of a language
+// statistics series, one data point per repository and language.
+func (r *workHandler) handleLanguageStats(ctx context.Context, job *Job, series *types.InsightSeries, recordTime time.Time) (err error) {
+	args, err := r.languageStatsRecordings(ctx, job, series, recordTime)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.insightsStore.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	return tx.RecordSeriesPoints(ctx, args)
+}
+
+// languageStatsRecordings computes the series points of a language statistics series job.
+func (r *workHandler) languageStatsRecordings(ctx context.Context, job *Job, series *types.InsightSeries, recordTime time.Time) ([]store.RecordSeriesPointArgs, error) {
+	// 🚨 SECURITY: Language statistics are recorded per repository, and are only returned to users
+	// who have access to the repository they were recorded for.
+	var args []store.RecordSeriesPointArgs
+	for _, repoName := range series.Repositories {
+		repo, err := r.repoStore.GetByName(ctx, api.RepoName(repoName))
+		if err != nil {
+			if errors.HasType(err, &database.RepoNotFoundErr{}) {
+				log15.Warn("insights: repository of language statistics series not found", "series_id", series.SeriesID, "repo", repoName)
+				continue
+			}
+			return nil, errors.Wrap(err, "GetByName")
+		}
+
+		bytesPerLanguage, err := r.languageStats(ctx, repo, recordTime)
+		if err != nil {
+			if errors.HasType(err, &gitdomain.RevisionNotFoundError{}) || gitdomain.IsRepoNotExist(err) {
+				continue // the repository may not be cloned yet
+			}
+			return nil, errors.Wrapf(err, "language statistics of %s", repoName)
+		}
+		for language, bytes := range bytesPerLanguage {
+			language := language
+			repoArgs := ToRecording(job, float64(bytes), recordTime, string(repo.Name), repo.ID)
+			for i := range repoArgs {
+				repoArgs[i].Point.Capture = &language
+			}
+			args = append(args, repoArgs...)
+		}
+	}
+	return args, nil
+}
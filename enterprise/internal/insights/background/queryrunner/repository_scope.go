@@ -0,0 +1,104 @@
+package queryrunner
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+)
+
+// scopeToRepositoryQuery resolves the repository query of the given series and returns a job and
+// series that are restricted to the repositories it matched at this time. The resolved
+// repositories are recorded for recordings, so that the points of the series can be traced back
+// to the repositories they were computed over.
+//
+// The returned series is a copy, as the given one is shared through the series cache.
+func (r *workHandler) scopeToRepositoryQuery(ctx context.Context, job *Job, series *types.InsightSeries, recordTime time.Time) (*Job, *types.InsightSeries, error) {
+	repositories, err := resolveRepositoryScope(ctx, series.RepositoryQuery)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "resolving repository query %q", series.RepositoryQuery)
+	}
+
+	if job.PersistMode == string(store.RecordMode) {
+		if err := r.insightsStore.RecordRepositoryScope(ctx, store.RepositoryScope{
+			SeriesID:     series.SeriesID,
+			RecordedAt:   recordTime,
+			Repositories: repositories,
+		}); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	scopedJob := *job
+	if scopedJob.SearchQuery != "" {
+		scopedJob.SearchQuery = withRepositoryScope(scopedJob.SearchQuery, repositories)
+	}
+	scopedSeries := *series
+	scopedSeries.Repositories = repositories
+	return &scopedJob, &scopedSeries, nil
+}
+
+// resolveRepositoryScope returns the sorted names of the repositories matched by the given
+// repository query.
+func resolveRepositoryScope(ctx context.Context, query string) ([]string, error) {
+	// 🚨 SECURITY: The request is performed without authentication. The resolved repositories are
+	// only used to scope the search query of the series, and are never returned to users.
+	results, err := search(ctx, repositoryScopeQuery(query))
+	if err != nil {
+		return nil, err
+	}
+	if alert := results.Data.Search.Results.Alert; alert != nil && alert.Title != noRepositoriesAlertTitle {
+		return nil, errors.Errorf("alert: %v", alert)
+	}
+
+	seen := make(map[string]struct{}, len(results.Data.Search.Results.Results))
+	repositories := make([]string, 0, len(results.Data.Search.Results.Results))
+	for _, result := range results.Data.Search.Results.Results {
+		decoded, err := decodeResult(result)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[decoded.repoName()]; ok {
+			continue
+		}
+		seen[decoded.repoName()] = struct{}{}
+		repositories = append(repositories, decoded.repoName())
+	}
+	sort.Strings(repositories)
+	return repositories, nil
+}
+
+// repositoryScopeQuery returns the search query that returns the repositories matched by the
+// given repository query. The repository query may contain any search filter, e.g. `file:` to
+// match repositories that contain a given file.
+func repositoryScopeQuery(query string) string {
+	if !strings.Contains(query, "select:") {
+		query += " select:repo"
+	}
+	if !strings.Contains(query, "count:") {
+		query += " count:all"
+	}
+	return query
+}
+
+// withRepositoryScope restricts the given search query to the given repositories. If there are
+// no repositories, the query matches no repositories at all.
+func withRepositoryScope(query string, repositories []string) string {
+	if len(repositories) == 0 {
+		// There is no repo: filter that matches nothing, so use one that cannot match a
+		// repository name.
+		return query + ` repo:^$`
+	}
+	patterns := make([]string, 0, len(repositories))
+	for _, repo := range repositories {
+		patterns = append(patterns, regexp.QuoteMeta(repo))
+	}
+	return fmt.Sprintf("%s repo:^(%s)$", query, strings.Join(patterns, "|"))
+}
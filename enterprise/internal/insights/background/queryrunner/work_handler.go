@@ -3,6 +3,7 @@ package queryrunner
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -34,6 +35,8 @@ type workHandler struct {
 	insightsStore   *store.Store
 	metadadataStore *store.InsightStore
 	limiter         *rate.Limiter
+	repoStore       RepoStore
+	languageStats   languageStatsFunc
 
 	mu          sync.RWMutex
 	seriesCache map[string]*types.InsightSeries
@@ -92,6 +95,25 @@ func (r *workHandler) Handle(ctx context.Context, record workerutil.Record) (err
 		return err
 	}
 
+	recordTime := time.Now()
+	if job.RecordTime != nil {
+		recordTime = *job.RecordTime
+	}
+
+	if series.RepositoryQuery != "" {
+		job, series, err = r.scopeToRepositoryQuery(ctx, job, series, recordTime)
+		if err != nil {
+			return err
+		}
+	}
+
+	if series.GenerationMethod == types.GenerationMethodLanguageStats {
+		return r.handleLanguageStats(ctx, job, series, recordTime)
+	}
+	if series.GeneratedFromCaptureGroups {
+		return r.handleCaptureGroups(ctx, job, series, recordTime)
+	}
+
 	// Actually perform the search query.
 	//
 	// 🚨 SECURITY: The request is performed without authentication, we get back results from every
@@ -105,16 +127,11 @@ func (r *workHandler) Handle(ctx context.Context, record workerutil.Record) (err
 		return err
 	}
 
-	recordTime := time.Now()
-	if job.RecordTime != nil {
-		recordTime = *job.RecordTime
-	}
-
 	if len(results.Errors) > 0 {
 		return errors.Errorf("GraphQL errors: %v", results.Errors)
 	}
 	if alert := results.Data.Search.Results.Alert; alert != nil {
-		if alert.Title == "No repositories satisfied your repo: filter" {
+		if alert.Title == noRepositoriesAlertTitle {
 			// We got zero results and no repositories matched. This could be for a few reasons:
 			//
 			// 1. The repo hasn't been cloned by Sourcegraph yet.
@@ -172,6 +189,11 @@ func (r *workHandler) Handle(ctx context.Context, record workerutil.Record) (err
 		matchesPerRepo[decoded.repoID()] = matchesPerRepo[decoded.repoID()] + decoded.matchCount()
 	}
 
+	topRepositories := series.GenerationMethod == types.GenerationMethodTopRepositories
+	if topRepositories {
+		matchesPerRepo = topRepositoryMatches(matchesPerRepo, repoNames, series.TopRepositoriesLimit)
+	}
+
 	tx, err := r.insightsStore.Transact(ctx)
 	if err != nil {
 		return err
@@ -201,6 +223,107 @@ func (r *workHandler) Handle(ctx context.Context, record workerutil.Record) (err
 		}
 
 		args := ToRecording(job, float64(matchCount), recordTime, repoName, dbRepoID)
+		if topRepositories {
+			// Each repository is its own series of values, which are keyed by repository name
+			// in the same way that capture group values are.
+			for i := range args {
+				args[i].Point.Capture = &repoName
+			}
+		}
+		if recordErr := tx.RecordSeriesPoints(ctx, args); recordErr != nil {
+			err = multierror.Append(err, errors.Wrap(recordErr, "RecordSeriesPoints"))
+		}
+	}
+	if topRepositories && err == nil && len(matchesPerRepo) > 0 && job.PersistMode == string(store.RecordMode) {
+		// Repositories that dropped out of the top N are removed entirely, so that the series
+		// only ever describes the current top N repositories.
+		if pruneErr := tx.PruneTopRepositories(ctx, series.SeriesID); pruneErr != nil {
+			err = errors.Wrap(pruneErr, "PruneTopRepositories")
+		}
+	}
+	return err
+}
+
+// topRepositoryMatches returns the match counts of the limit repositories with the most matches.
+// Ties are broken by repository name so that the selection is stable between recordings.
+func topRepositoryMatches(matchesPerRepo map[string]int, repoNames map[string]string, limit int) map[string]int {
+	if limit <= 0 || len(matchesPerRepo) <= limit {
+		return matchesPerRepo
+	}
+
+	repoIDs := make([]string, 0, len(matchesPerRepo))
+	for repoID := range matchesPerRepo {
+		repoIDs = append(repoIDs, repoID)
+	}
+	sort.Slice(repoIDs, func(i, j int) bool {
+		if matchesPerRepo[repoIDs[i]] != matchesPerRepo[repoIDs[j]] {
+			return matchesPerRepo[repoIDs[i]] > matchesPerRepo[repoIDs[j]]
+		}
+		return repoNames[repoIDs[i]] < repoNames[repoIDs[j]]
+	})
+
+	top := make(map[string]int, limit)
+	for _, repoID := range repoIDs[:limit] {
+		top[repoID] = matchesPerRepo[repoID]
+	}
+	return top
+}
+
+// handleCaptureGroups performs the search query of a job for a series that is generated from
+// capture groups. It uses the compute API to extract the capture group value of every match, and
+// records one data point per repository and distinct capture group value.
+func (r *workHandler) handleCaptureGroups(ctx context.Context, job *Job, series *types.InsightSeries, recordTime time.Time) (err error) {
+	// 🚨 SECURITY: As with regular searches, the request is performed without authentication.
+	// Capture group values are recorded per repository, and are only returned to users who have
+	// access to the repository they were recorded for.
+	results, err := computeSearch(ctx, job.SearchQuery)
+	if err != nil {
+		return err
+	}
+
+	type captureKey struct {
+		repoID  string
+		capture string
+	}
+	matchesPerCapture := make(map[captureKey]int)
+	repoNames := make(map[string]string)
+	for _, result := range results.Data.Compute {
+		if result.Typename != "ComputeMatchContext" {
+			continue
+		}
+		repoNames[result.Repository.ID] = result.Repository.Name
+		for _, match := range result.Matches {
+			capture, ok := captureValue(match.Environment)
+			if !ok {
+				continue
+			}
+			matchesPerCapture[captureKey{repoID: result.Repository.ID, capture: capture}]++
+		}
+	}
+
+	tx, err := r.insightsStore.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	if job.PersistMode == string(store.SnapshotMode) {
+		if err := tx.DeleteSnapshots(ctx, series); err != nil {
+			return err
+		}
+	}
+
+	for key, matchCount := range matchesPerCapture {
+		dbRepoID, idErr := graphqlbackend.UnmarshalRepositoryID(graphql.ID(key.repoID))
+		if idErr != nil {
+			err = multierror.Append(err, errors.Wrap(idErr, "UnmarshalRepositoryID"))
+			continue
+		}
+		capture := key.capture
+		args := ToRecording(job, float64(matchCount), recordTime, repoNames[key.repoID], dbRepoID)
+		for i := range args {
+			args[i].Point.Capture = &capture
+		}
 		if recordErr := tx.RecordSeriesPoints(ctx, args); recordErr != nil {
 			err = multierror.Append(err, errors.Wrap(recordErr, "RecordSeriesPoints"))
 		}
@@ -208,6 +331,20 @@ func (r *workHandler) Handle(ctx context.Context, record workerutil.Record) (err
 	return err
 }
 
+// captureValue returns the capture group value of a compute match. This is the first unnamed
+// capture group, or the only capture group if the pattern has a single named group.
+func captureValue(environment []gqlComputeEnvironmentEntry) (string, bool) {
+	for _, entry := range environment {
+		if entry.Variable == "1" {
+			return entry.Value, true
+		}
+	}
+	if len(environment) == 1 {
+		return environment[0].Value, true
+	}
+	return "", false
+}
+
 func ToRecording(record *Job, value float64, recordTime time.Time, repoName string, repoID api.RepoID) []store.RecordSeriesPointArgs {
 	args := make([]store.RecordSeriesPointArgs, 0, len(record.DependentFrames)+1)
 	base := store.RecordSeriesPointArgs{
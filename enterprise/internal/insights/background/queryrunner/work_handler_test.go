@@ -0,0 +1,146 @@
+package queryrunner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hexops/autogold"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	internalTypes "github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestCaptureValue(t *testing.T) {
+	type result struct {
+		Value string
+		OK    bool
+	}
+	captureValueOf := func(environment []gqlComputeEnvironmentEntry) result {
+		value, ok := captureValue(environment)
+		return result{Value: value, OK: ok}
+	}
+
+	t.Run("no capture groups", func(t *testing.T) {
+		autogold.Want("no capture groups", result{}).Equal(t, captureValueOf(nil))
+	})
+	t.Run("first unnamed group", func(t *testing.T) {
+		autogold.Want("first unnamed group", result{Value: "1.2.3", OK: true}).Equal(t, captureValueOf([]gqlComputeEnvironmentEntry{
+			{Variable: "2", Value: "beta"},
+			{Variable: "1", Value: "1.2.3"},
+		}))
+	})
+	t.Run("single named group", func(t *testing.T) {
+		autogold.Want("single named group", result{Value: "1.2.3", OK: true}).Equal(t, captureValueOf([]gqlComputeEnvironmentEntry{
+			{Variable: "version", Value: "1.2.3"},
+		}))
+	})
+	t.Run("multiple named groups", func(t *testing.T) {
+		autogold.Want("multiple named groups", result{}).Equal(t, captureValueOf([]gqlComputeEnvironmentEntry{
+			{Variable: "major", Value: "1"},
+			{Variable: "minor", Value: "2"},
+		}))
+	})
+}
+
+func TestTopRepositoryMatches(t *testing.T) {
+	matchesPerRepo := map[string]int{"r1": 5, "r2": 10, "r3": 5, "r4": 1}
+	repoNames := map[string]string{"r1": "github.com/b/b", "r2": "github.com/c/c", "r3": "github.com/a/a", "r4": "github.com/d/d"}
+
+	t.Run("top N", func(t *testing.T) {
+		autogold.Want("top N", map[string]int{"r2": 10, "r3": 5}).Equal(t, topRepositoryMatches(matchesPerRepo, repoNames, 2))
+	})
+	t.Run("fewer repositories than limit", func(t *testing.T) {
+		autogold.Want("fewer repositories than limit", map[string]int{"r1": 5, "r2": 10, "r3": 5, "r4": 1}).Equal(t, topRepositoryMatches(matchesPerRepo, repoNames, 10))
+	})
+}
+
+func TestRepositoryScopeQuery(t *testing.T) {
+	t.Run("repository filter", func(t *testing.T) {
+		autogold.Want("repository filter", "repo:has.meta(team:payments) select:repo count:all").Equal(t, repositoryScopeQuery("repo:has.meta(team:payments)"))
+	})
+	t.Run("explicit select and count", func(t *testing.T) {
+		autogold.Want("explicit select and count", "file:go.mod select:repo count:100").Equal(t, repositoryScopeQuery("file:go.mod select:repo count:100"))
+	})
+}
+
+func TestWithRepositoryScope(t *testing.T) {
+	t.Run("repositories", func(t *testing.T) {
+		autogold.Want("repositories", "errors.New repo:^(github\\.com/a/a|github\\.com/b/b)$").Equal(t, withRepositoryScope("errors.New", []string{"github.com/a/a", "github.com/b/b"}))
+	})
+	t.Run("no repositories", func(t *testing.T) {
+		autogold.Want("no repositories", "errors.New repo:^$").Equal(t, withRepositoryScope("errors.New", nil))
+	})
+}
+
+type fakeRepoStore map[api.RepoName]*internalTypes.Repo
+
+func (s fakeRepoStore) GetByName(ctx context.Context, name api.RepoName) (*internalTypes.Repo, error) {
+	repo, ok := s[name]
+	if !ok {
+		return nil, &database.RepoNotFoundErr{Name: name}
+	}
+	return repo, nil
+}
+
+func TestLanguageStatsRecordings(t *testing.T) {
+	recordTime := time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC)
+	handler := &workHandler{
+		repoStore: fakeRepoStore{
+			"github.com/a/a": {ID: 1, Name: "github.com/a/a"},
+			"github.com/b/b": {ID: 2, Name: "github.com/b/b"},
+		},
+		languageStats: func(ctx context.Context, repo *internalTypes.Repo, before time.Time) (map[string]uint64, error) {
+			if !before.Equal(recordTime) {
+				t.Errorf("unexpected time: %v", before)
+			}
+			if repo.ID == 2 {
+				return nil, nil // no commits yet
+			}
+			return map[string]uint64{"Go": 1000}, nil
+		},
+	}
+	job := &Job{
+		SeriesID:        "languages",
+		PersistMode:     string(store.RecordMode),
+		DependentFrames: []time.Time{recordTime.AddDate(0, 1, 0)},
+	}
+	series := &types.InsightSeries{
+		SeriesID:         "languages",
+		Repositories:     []string{"github.com/a/a", "github.com/b/b", "github.com/deleted/deleted"},
+		GenerationMethod: types.GenerationMethodLanguageStats,
+	}
+
+	args, err := handler.languageStatsRecordings(context.Background(), job, series, recordTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type recording struct {
+		Time     string
+		Value    float64
+		RepoName string
+		Capture  string
+	}
+	var got []recording
+	for _, arg := range args {
+		got = append(got, recording{Time: arg.Point.Time.Format(time.RFC3339), Value: arg.Point.Value, RepoName: *arg.RepoName, Capture: *arg.Point.Capture})
+	}
+	autogold.Want("language stats recordings", []recording{
+		{
+			Time:     "2021-10-01T00:00:00Z",
+			Value:    1000,
+			RepoName: "github.com/a/a",
+			Capture:  "Go",
+		},
+		{
+			Time:     "2021-11-01T00:00:00Z",
+			Value:    1000,
+			RepoName: "github.com/a/a",
+			Capture:  "Go",
+		},
+	}).Equal(t, got)
+}
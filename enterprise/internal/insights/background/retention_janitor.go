@@ -0,0 +1,63 @@
+package background
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// newSeriesPointsRetentionJanitor returns a background goroutine which will periodically
+// downsample old series points into daily and weekly aggregates, according to the retention
+// configured in the site configuration. This keeps the size of the insights DB bounded.
+func newSeriesPointsRetentionJanitor(ctx context.Context, insightsStore *store.Store, observationContext *observation.Context) goroutine.BackgroundRoutine {
+	metrics := metrics.NewOperationMetrics(
+		observationContext.Registerer,
+		"insights_retention_janitor",
+		metrics.WithCountHelp("Total number of insights retention janitor executions"),
+	)
+	operation := observationContext.Operation(observation.Op{
+		Name:    "RetentionJanitor.Run",
+		Metrics: metrics,
+	})
+
+	return goroutine.NewPeriodicGoroutineWithMetrics(ctx, time.Hour, goroutine.NewHandlerWithErrorMessage(
+		"insights_retention_janitor",
+		func(ctx context.Context) error {
+			siteConfig := conf.Get()
+			opts := downsampleOpts(time.Now(), siteConfig.InsightsRetentionRawDays, siteConfig.InsightsRetentionDailyDays)
+			if opts.RawBefore.IsZero() && opts.DailyBefore.IsZero() {
+				return nil
+			}
+			return insightsStore.Downsample(ctx, opts)
+		},
+	), operation)
+}
+
+// downsampleOpts returns the cutoffs for downsampling series points given the configured number
+// of days raw and daily points are kept. A zero number of days disables the respective
+// downsampling. Cutoffs are aligned to the start of a day (raw) or week (daily) so that every
+// aggregate only ever covers one full period.
+func downsampleOpts(now time.Time, rawDays, dailyDays int) store.DownsampleOpts {
+	var opts store.DownsampleOpts
+	now = now.UTC()
+	if rawDays > 0 {
+		opts.RawBefore = startOfDay(now.AddDate(0, 0, -rawDays))
+	}
+	if dailyDays > 0 {
+		day := startOfDay(now.AddDate(0, 0, -dailyDays))
+		// Weeks start on Monday, like Postgres' date_trunc('week', ...).
+		daysSinceMonday := (int(day.Weekday()) + 6) % 7
+		opts.DailyBefore = day.AddDate(0, 0, -daysSinceMonday)
+	}
+	return opts
+}
+
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
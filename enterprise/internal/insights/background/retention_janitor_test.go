@@ -0,0 +1,59 @@
+package background
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+)
+
+func TestDownsampleOpts(t *testing.T) {
+	// Thursday 14 October 2021.
+	now := time.Date(2021, time.October, 14, 15, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name      string
+		rawDays   int
+		dailyDays int
+		want      store.DownsampleOpts
+	}{
+		{
+			name: "disabled",
+			want: store.DownsampleOpts{},
+		},
+		{
+			name:    "raw only",
+			rawDays: 30,
+			want: store.DownsampleOpts{
+				RawBefore: time.Date(2021, time.September, 14, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:      "raw and daily",
+			rawDays:   30,
+			dailyDays: 7,
+			want: store.DownsampleOpts{
+				RawBefore: time.Date(2021, time.September, 14, 0, 0, 0, 0, time.UTC),
+				// 7 October is a Thursday, the week started on Monday 4 October.
+				DailyBefore: time.Date(2021, time.October, 4, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:      "daily cutoff on a Monday",
+			dailyDays: 3,
+			want: store.DownsampleOpts{
+				DailyBefore: time.Date(2021, time.October, 11, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := downsampleOpts(now, tc.rawDays, tc.dailyDays)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected opts (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package background
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// newSeriesStorageMaintainer returns a background goroutine which will periodically perform the
+// housekeeping required by the storage backend of the insights DB, such as creating the upcoming
+// partitions of the series points table.
+func newSeriesStorageMaintainer(ctx context.Context, insightsDB dbutil.DB, storage store.SeriesStorage, observationContext *observation.Context) goroutine.BackgroundRoutine {
+	metrics := metrics.NewOperationMetrics(
+		observationContext.Registerer,
+		"insights_series_storage_maintainer",
+		metrics.WithCountHelp("Total number of insights series storage maintainer executions"),
+	)
+	operation := observationContext.Operation(observation.Op{
+		Name:    "SeriesStorageMaintainer.Run",
+		Metrics: metrics,
+	})
+
+	return goroutine.NewPeriodicGoroutineWithMetrics(ctx, 6*time.Hour, goroutine.NewHandlerWithErrorMessage(
+		"insights_series_storage_maintainer",
+		func(ctx context.Context) error {
+			return storage.Maintain(ctx, insightsDB, time.Now())
+		},
+	), operation)
+}
@@ -2,6 +2,7 @@ package discovery
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -65,6 +66,9 @@ func (a *AllReposIterator) timeSince(t time.Time) time.Duration {
 // 500,000+ of them). It also takes into account Sourcegraph.com, where we only gather historical
 // data for the same subset of repos we index for search.
 //
+// Repositories are visited in ID order, so that an iteration that was stopped can be resumed after
+// the last repository it visited.
+//
 // If the forEach function returns an error, pagination is stopped and the error returned.
 func (a *AllReposIterator) ForEach(ctx context.Context, forEach func(repoName string) error) error {
 	// 🚨 SECURITY: this context will ensure that this iterator goes over all repositories
@@ -82,6 +86,7 @@ func (a *AllReposIterator) ForEach(ctx context.Context, forEach func(repoName st
 			if err != nil {
 				return errors.Wrap(err, "IndexableReposLister.List")
 			}
+			sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
 			for _, r := range res {
 				a.cachedRepoNames = append(a.cachedRepoNames, string(r.Name))
 			}
@@ -146,8 +151,8 @@ func (a *AllReposIterator) cachedRepoStoreList(ctx context.Context, page databas
 	repos, err := a.RepoStore.List(ctx, database.ReposListOptions{
 		Index: &trueP,
 
-		// Order by repository name.
-		OrderBy: database.RepoListOrderBy{{Field: database.RepoListName}},
+		// Order by repository ID, so that new repositories are visited last.
+		OrderBy: database.RepoListOrderBy{{Field: database.RepoListID}},
 
 		LimitOffset: &page,
 	})
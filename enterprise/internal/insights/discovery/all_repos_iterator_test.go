@@ -57,13 +57,13 @@ func TestAllReposIterator(t *testing.T) {
 		{
 			Index: &trueP,
 			OrderBy: database.RepoListOrderBy{database.RepoListSort{
-				Field: database.RepoListColumn("name"),
+				Field: database.RepoListColumn("id"),
 			}},
 			LimitOffset: &database.LimitOffset{Limit: 1000},
 		},
 		{
 			Index:   &trueP,
-			OrderBy: database.RepoListOrderBy{database.RepoListSort{Field: database.RepoListColumn("name")}},
+			OrderBy: database.RepoListOrderBy{database.RepoListSort{Field: database.RepoListColumn("id")}},
 			LimitOffset: &database.LimitOffset{
 				Limit:  1000,
 				Offset: 3,
@@ -71,7 +71,7 @@ func TestAllReposIterator(t *testing.T) {
 		},
 		{
 			Index:   &trueP,
-			OrderBy: database.RepoListOrderBy{database.RepoListSort{Field: database.RepoListColumn("name")}},
+			OrderBy: database.RepoListOrderBy{database.RepoListSort{Field: database.RepoListColumn("id")}},
 			LimitOffset: &database.LimitOffset{
 				Limit:  1000,
 				Offset: 6,
@@ -79,7 +79,7 @@ func TestAllReposIterator(t *testing.T) {
 		},
 		{
 			Index:   &trueP,
-			OrderBy: database.RepoListOrderBy{database.RepoListSort{Field: database.RepoListColumn("name")}},
+			OrderBy: database.RepoListOrderBy{database.RepoListSort{Field: database.RepoListColumn("id")}},
 			LimitOffset: &database.LimitOffset{
 				Limit:  1000,
 				Offset: 9,
@@ -115,13 +115,13 @@ func TestAllReposIterator(t *testing.T) {
 			{
 				Index: &trueP,
 				OrderBy: database.RepoListOrderBy{database.RepoListSort{
-					Field: database.RepoListColumn("name"),
+					Field: database.RepoListColumn("id"),
 				}},
 				LimitOffset: &database.LimitOffset{Limit: 1000},
 			},
 			{
 				Index:   &trueP,
-				OrderBy: database.RepoListOrderBy{database.RepoListSort{Field: database.RepoListColumn("name")}},
+				OrderBy: database.RepoListOrderBy{database.RepoListSort{Field: database.RepoListColumn("id")}},
 				LimitOffset: &database.LimitOffset{
 					Limit:  1000,
 					Offset: 3,
@@ -129,7 +129,7 @@ func TestAllReposIterator(t *testing.T) {
 			},
 			{
 				Index:   &trueP,
-				OrderBy: database.RepoListOrderBy{database.RepoListSort{Field: database.RepoListColumn("name")}},
+				OrderBy: database.RepoListOrderBy{database.RepoListSort{Field: database.RepoListColumn("id")}},
 				LimitOffset: &database.LimitOffset{
 					Limit:  1000,
 					Offset: 6,
@@ -137,7 +137,7 @@ func TestAllReposIterator(t *testing.T) {
 			},
 			{
 				Index:   &trueP,
-				OrderBy: database.RepoListOrderBy{database.RepoListSort{Field: database.RepoListColumn("name")}},
+				OrderBy: database.RepoListOrderBy{database.RepoListSort{Field: database.RepoListColumn("id")}},
 				LimitOffset: &database.LimitOffset{
 					Limit:  1000,
 					Offset: 9,
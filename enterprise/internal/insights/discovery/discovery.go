@@ -2,23 +2,9 @@ package discovery
 
 import (
 	"context"
-	"time"
-
-	"github.com/cockroachdb/errors"
-
-	"github.com/inconshreveable/log15"
-
-	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
-
-	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
-
-	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
-
-	"github.com/sourcegraph/sourcegraph/internal/goroutine"
-
-	"github.com/sourcegraph/sourcegraph/internal/insights"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
 	"github.com/sourcegraph/sourcegraph/internal/jsonc"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
@@ -127,190 +113,3 @@ func filterByIds(ids []string, insight []insights.SearchInsight) []insights.Sear
 	}
 	return filtered
 }
-
-type settingMigrator struct {
-	base     dbutil.DB
-	insights dbutil.DB
-}
-
-// NewMigrateSettingInsightsJob will migrate insights from settings into the database. This is a job that will be
-// deprecated as soon as this functionality is available over an API.
-func NewMigrateSettingInsightsJob(ctx context.Context, base dbutil.DB, insights dbutil.DB) goroutine.BackgroundRoutine {
-	interval := time.Minute * 10
-	m := settingMigrator{
-		base:     base,
-		insights: insights,
-	}
-
-	return goroutine.NewPeriodicGoroutine(ctx, interval,
-		goroutine.NewHandlerWithErrorMessage("insight_setting_migrator", m.migrate))
-}
-
-func (m *settingMigrator) migrate(ctx context.Context) error {
-	insightStore := store.NewInsightStore(m.insights)
-	loader := insights.NewLoader(m.base)
-	dashboardStore := store.NewDashboardStore(m.insights)
-
-	discovered, err := discoverIntegrated(ctx, loader)
-	if err != nil {
-		return err
-	}
-
-	var count, skipped, errorCount int
-	for _, d := range discovered {
-		if d.ID == "" {
-			// we need a unique ID, and if for some reason this insight doesn't have one, it can't be migrated.
-			skipped++
-			continue
-		}
-		err := insightStore.DeleteViewByUniqueID(ctx, d.ID)
-		log15.Info("insights migration: deleting insight view", "unique_id", d.ID)
-		if err != nil {
-			// if we fail here there isn't much we can do in this migration, so continue
-			skipped++
-			continue
-		}
-
-		err = migrateSeries(ctx, insightStore, d)
-		if err != nil {
-			// we can't do anything about errors, so we will just skip it and log it
-			errorCount++
-			log15.Error("insights migration: error while migrating insight", "error", err)
-		}
-		count++
-	}
-	log15.Info("insights settings migration complete", "count", count, "skipped", skipped, "errors", errorCount)
-
-	log15.Info("insights migration: migrating dashboards")
-	dashboards, err := loader.LoadDashboards(ctx)
-	if err != nil {
-		return err
-	}
-	err = clearDashboards(ctx, m.insights)
-	if err != nil {
-		return errors.Wrap(err, "clearDashboards")
-	}
-	for _, dashboard := range dashboards {
-		err := migrateDashboard(ctx, dashboardStore, dashboard)
-		if err != nil {
-			log15.Info("insights migration: error while migrating dashboard", "error", err)
-			continue
-		}
-	}
-	return nil
-}
-
-func migrateDashboard(ctx context.Context, dashboardStore *store.DBDashboardStore, from insights.SettingDashboard) (err error) {
-	tx, err := dashboardStore.Transact(ctx)
-	if err != nil {
-		return err
-	}
-	defer func() { err = tx.Store.Done(err) }()
-
-	dashboard := types.Dashboard{
-		Title:      from.Title,
-		InsightIDs: from.InsightIds,
-	}
-	log15.Info("insights migration: migrating dashboard", "settings_unique_id", from.ID)
-
-	var grants []store.DashboardGrant
-	if from.UserID != nil {
-		grants = []store.DashboardGrant{store.UserDashboardGrant(int(*from.UserID))}
-	} else if from.OrgID != nil {
-		grants = []store.DashboardGrant{store.OrgDashboardGrant(int(*from.OrgID))}
-	} else {
-		grants = []store.DashboardGrant{store.GlobalDashboardGrant()}
-	}
-	_, err = dashboardStore.CreateDashboard(ctx, store.CreateDashboardArgs{Dashboard: dashboard, Grants: grants})
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-// clearDashboards will delete all dashboards. This should be deprecated as soon as possible, and is only useful to ensure a smooth migration from settings to database.
-func clearDashboards(ctx context.Context, db dbutil.DB) error {
-	_, err := db.ExecContext(ctx, deleteAllDashboardsSql)
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
-const deleteAllDashboardsSql = `
--- source: enterprise/internal/insights/discovery/discovery.go:clearDashboards
-delete from dashboard where save != true;
-`
-
-// migrateSeries will attempt to take an insight defined in Sourcegraph settings and migrate it to the database.
-func migrateSeries(ctx context.Context, insightStore *store.InsightStore, from insights.SearchInsight) (err error) {
-	tx, err := insightStore.Transact(ctx)
-	if err != nil {
-		return err
-	}
-	defer func() { err = tx.Store.Done(err) }()
-
-	log15.Info("insights migration: attempting to migrate insight", "unique_id", from.ID)
-	dataSeries := make([]types.InsightSeries, len(from.Series))
-	metadata := make([]types.InsightViewSeriesMetadata, len(from.Series))
-
-	for i, timeSeries := range from.Series {
-		temp := types.InsightSeries{
-			SeriesID:            Encode(timeSeries),
-			Query:               timeSeries.Query,
-			NextRecordingAfter:  insights.NextRecording(time.Now()),
-			NextSnapshotAfter:   insights.NextSnapshot(time.Now()),
-			SampleIntervalUnit:  string(types.Month),
-			SampleIntervalValue: 1,
-		}
-		var series types.InsightSeries
-		// first check if this data series already exists (somebody already created an insight of this query), in which case we just need to attach the view to this data series
-		existing, err := tx.GetDataSeries(ctx, store.GetDataSeriesArgs{SeriesID: Encode(timeSeries)})
-		if err != nil {
-			return errors.Wrapf(err, "unable to migrate insight unique_id: %s series_id: %s", from.ID, temp.SeriesID)
-		} else if len(existing) > 0 {
-			series = existing[0]
-			log15.Info("insights migration: existing data series identified, attempting to construct and attach new view", "series_id", series.SeriesID, "unique_id", from.ID)
-		} else {
-			series, err = tx.CreateSeries(ctx, temp)
-			if err != nil {
-				return errors.Wrapf(err, "unable to migrate insight unique_id: %s series_id: %s", from.ID, temp.SeriesID)
-			}
-		}
-		dataSeries[i] = series
-
-		metadata[i] = types.InsightViewSeriesMetadata{
-			Label:  timeSeries.Name,
-			Stroke: timeSeries.Stroke,
-		}
-	}
-
-	view := types.InsightView{
-		Title:       from.Title,
-		Description: from.Description,
-		UniqueID:    from.ID,
-	}
-
-	var grants []store.InsightViewGrant
-	if from.UserID != nil {
-		grants = []store.InsightViewGrant{store.UserGrant(int(*from.UserID))}
-	} else if from.OrgID != nil {
-		grants = []store.InsightViewGrant{store.OrgGrant(int(*from.OrgID))}
-	} else {
-		grants = []store.InsightViewGrant{store.GlobalGrant()}
-	}
-
-	view, err = tx.CreateView(ctx, view, grants)
-	if err != nil {
-		return errors.Wrapf(err, "unable to migrate insight unique_id: %s", from.ID)
-	}
-
-	for i, insightSeries := range dataSeries {
-		err := tx.AttachSeriesToView(ctx, insightSeries, view, metadata[i])
-		if err != nil {
-			return errors.Wrapf(err, "unable to migrate insight unique_id: %s", from.ID)
-		}
-	}
-	return nil
-}
@@ -6,11 +6,15 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/cockroachdb/errors"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/enterprise"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/migration"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/resolvers"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbconn"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
@@ -29,13 +33,27 @@ func IsEnabled() bool {
 		// Insights.
 		return false
 	}
-	if conf.IsDeployTypeSingleDockerContainer(conf.DeployType()) {
-		// Code insights is not supported in single-container Docker demo deployments.
+	if conf.IsDeployTypeSingleDockerContainer(conf.DeployType()) && StorageBackend() != store.StorageBackendPostgres {
+		// Code insights is not supported in single-container Docker demo deployments, unless
+		// the series are stored in plain Postgres.
 		return false
 	}
 	return true
 }
 
+// StorageBackend returns the storage backend of the code insights database, as configured by
+// the CODE_INSIGHTS_STORAGE environment variable. TimescaleDB is used by default; "postgres"
+// stores the series points in plain Postgres, which lets the code insights database live on any
+// Postgres server, e.g. next to the main database.
+func StorageBackend() store.StorageBackend {
+	return store.StorageBackend(os.Getenv("CODE_INSIGHTS_STORAGE"))
+}
+
+// SeriesStorage returns the configured SeriesStorage.
+func SeriesStorage() (store.SeriesStorage, error) {
+	return store.NewSeriesStorage(StorageBackend())
+}
+
 // Init initializes the given enterpriseServices to include the required resolvers for insights.
 func Init(ctx context.Context, postgres dbutil.DB, outOfBandMigrationRunner *oobmigration.Runner, enterpriseServices *enterprise.Services, observationContext *observation.Context) error {
 	if !IsEnabled() {
@@ -51,13 +69,22 @@ func Init(ctx context.Context, postgres dbutil.DB, outOfBandMigrationRunner *oob
 		return err
 	}
 	enterpriseServices.InsightsResolver = resolvers.New(timescale, postgres)
+	graphqlbackend.ComputeOverCommits = resolvers.ComputeOverCommits
+
+	if err := outOfBandMigrationRunner.Register(
+		migration.SettingsMigrationID,
+		migration.NewMigrator(postgres, timescale),
+		oobmigration.MigratorOptions{Interval: 10 * time.Second},
+	); err != nil {
+		return errors.Wrap(err, "failed to register settings migration")
+	}
 	return nil
 }
 
-// InitializeCodeInsightsDB connects to and initializes the Code Insights Timescale DB, running
-// database migrations before returning. It is safe to call from multiple services/containers (in
-// which case, one's migration will win and the other caller will receive an error and should exit
-// and restart until the other finishes.)
+// InitializeCodeInsightsDB connects to and initializes the Code Insights DB, running the database
+// migrations of the configured storage backend before returning. It is safe to call
+// from multiple services/containers (in which case, one's migration will win and the other caller
+// will receive an error and should exit and restart until the other finishes.)
 func InitializeCodeInsightsDB(app string) (*sql.DB, error) {
 	timescaleDSN := conf.Get().ServiceConnections.CodeInsightsTimescaleDSN
 	conf.Watch(func() {
@@ -71,7 +98,11 @@ func InitializeCodeInsightsDB(app string) (*sql.DB, error) {
 		return nil, errors.Errorf("Failed to connect to codeinsights database: %s", err)
 	}
 
-	if err := dbconn.MigrateDB(db, dbconn.CodeInsights); err != nil {
+	storage, err := SeriesStorage()
+	if err != nil {
+		return nil, err
+	}
+	if err := dbconn.MigrateDB(db, storage.Migrations()); err != nil {
 		return nil, errors.Errorf("Failed to perform codeinsights database migration: %s", err)
 	}
 	return db, nil
@@ -0,0 +1,467 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/hashicorp/go-multierror"
+	"github.com/inconshreveable/log15"
+	"github.com/keegancsmith/sqlf"
+	"github.com/segmentio/ksuid"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+	"github.com/sourcegraph/sourcegraph/internal/oobmigration"
+)
+
+// SettingsMigrationID is the ID of the row holding the migration of insights and dashboards from
+// settings into the code insights database. It is defined in
+// `1528395921_insights_settings_migration_jobs.up.sql`.
+const SettingsMigrationID = 13
+
+const (
+	// jobsPerRun is the number of settings subjects migrated by a single call to Up.
+	jobsPerRun = 10
+
+	// maxRuns is the number of attempts after which a failing subject is given up on. Its last
+	// error is kept in the jobs table, so that it can be investigated.
+	maxRuns = 5
+)
+
+// settingsMigrator migrates the code insights and dashboards defined in user, organization and
+// global settings into the code insights database. Every settings subject is migrated by a row of
+// the insights_settings_migration_jobs table in the frontend database, which records the progress
+// and the errors of the subject.
+type settingsMigrator struct {
+	frontendStore  *basestore.Store
+	settingStore   discovery.SettingStore
+	insightStore   *store.InsightStore
+	dashboardStore *store.DBDashboardStore
+}
+
+var _ oobmigration.Migrator = &settingsMigrator{}
+
+// NewMigrator returns the out-of-band migrator of insights and dashboards defined in settings.
+func NewMigrator(frontendDB, insightsDB dbutil.DB) oobmigration.Migrator {
+	return &settingsMigrator{
+		frontendStore:  basestore.NewWithDB(frontendDB, sql.TxOptions{}),
+		settingStore:   database.Settings(frontendDB),
+		insightStore:   store.NewInsightStore(insightsDB),
+		dashboardStore: store.NewDashboardStore(insightsDB),
+	}
+}
+
+// Progress returns the ratio of completed settings subjects to all settings subjects.
+func (m *settingsMigrator) Progress(ctx context.Context) (float64, error) {
+	progress, _, err := basestore.ScanFirstFloat(m.frontendStore.Query(ctx, sqlf.Sprintf(settingsMigratorProgressQuery)))
+	if err != nil {
+		return 0, err
+	}
+	return progress, nil
+}
+
+const settingsMigratorProgressQuery = `
+-- source: enterprise/internal/insights/migration/migrator.go:Progress
+SELECT CASE c2.count WHEN 0 THEN 1 ELSE CAST(c1.count AS float) / CAST(c2.count AS float) END FROM
+	(SELECT COUNT(*) AS count FROM insights_settings_migration_jobs WHERE completed_at IS NOT NULL) c1,
+	(SELECT COUNT(*) AS count FROM insights_settings_migration_jobs) c2
+`
+
+// Up migrates the insights and dashboards of a batch of settings subjects. Subjects that were
+// attempted the least often are migrated first, so that a failing subject does not block the
+// others. The errors of the subjects are recorded in the jobs table, and returned combined.
+func (m *settingsMigrator) Up(ctx context.Context) error {
+	jobErrs, err := m.migrateJobs(ctx)
+	if err != nil {
+		return err
+	}
+	return jobErrs
+}
+
+// migrateJobs migrates a batch of settings subjects. The returned jobErrs are the errors of the
+// individual subjects, which do not roll back the updates of the jobs table.
+func (m *settingsMigrator) migrateJobs(ctx context.Context) (jobErrs error, err error) {
+	tx, err := m.frontendStore.Transact(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	jobs, err := scanJobs(tx.Query(ctx, sqlf.Sprintf(selectSettingsMigrationJobsSql, jobsPerRun)))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, job := range jobs {
+		result, jobErr := m.migrateSubject(ctx, job.subject())
+		if jobErr != nil {
+			log15.Error("insights migration: failed to migrate settings", "job_id", job.ID, "error", jobErr)
+			jobErrs = multierror.Append(jobErrs, errors.Wrapf(jobErr, "job %d", job.ID))
+		}
+
+		var errorMessage *string
+		var completedAt *time.Time
+		if jobErr != nil {
+			msg := jobErr.Error()
+			errorMessage = &msg
+		}
+		if jobErr == nil || job.Runs+1 >= maxRuns {
+			now := time.Now()
+			completedAt = &now
+		}
+		if err := tx.Exec(ctx, sqlf.Sprintf(updateSettingsMigrationJobSql,
+			result.insights,
+			result.dashboards,
+			errorMessage,
+			completedAt,
+			job.ID,
+		)); err != nil {
+			return nil, err
+		}
+	}
+	return jobErrs, nil
+}
+
+const selectSettingsMigrationJobsSql = `
+-- source: enterprise/internal/insights/migration/migrator.go:migrateJobs
+SELECT id, user_id, org_id, global, runs
+FROM insights_settings_migration_jobs
+WHERE completed_at IS NULL
+ORDER BY runs, id
+LIMIT %s
+FOR UPDATE SKIP LOCKED
+`
+
+const updateSettingsMigrationJobSql = `
+-- source: enterprise/internal/insights/migration/migrator.go:migrateJobs
+UPDATE insights_settings_migration_jobs
+SET runs = runs + 1, migrated_insights = %s, migrated_dashboards = %s, error_message = %s, completed_at = %s
+WHERE id = %s
+`
+
+// Down is a no-op, the migration only adds data to the code insights database.
+func (m *settingsMigrator) Down(ctx context.Context) error {
+	return nil
+}
+
+type settingsMigrationJob struct {
+	ID     int
+	UserID *int32
+	OrgID  *int32
+	Global bool
+	Runs   int
+}
+
+// subject returns the settings subject migrated by the job.
+func (j settingsMigrationJob) subject() api.SettingsSubject {
+	switch {
+	case j.UserID != nil:
+		return api.SettingsSubject{User: j.UserID}
+	case j.OrgID != nil:
+		return api.SettingsSubject{Org: j.OrgID}
+	default:
+		return api.SettingsSubject{Site: true}
+	}
+}
+
+func scanJobs(rows *sql.Rows, queryErr error) (_ []settingsMigrationJob, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var jobs []settingsMigrationJob
+	for rows.Next() {
+		var job settingsMigrationJob
+		if err := rows.Scan(&job.ID, &job.UserID, &job.OrgID, &job.Global, &job.Runs); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// migrationResult counts the insights and dashboards of a settings subject that exist in the code
+// insights database.
+type migrationResult struct {
+	insights   int
+	dashboards int
+}
+
+// migrateSubject migrates the insights and dashboards of the latest settings of a subject. It is
+// idempotent: insights and dashboards that were migrated by a previous attempt are skipped. All
+// insights and dashboards are attempted even if some fail, and the errors are returned combined.
+func (m *settingsMigrator) migrateSubject(ctx context.Context, subject api.SettingsSubject) (result migrationResult, err error) {
+	settings, err := m.settingStore.GetLatest(ctx, subject)
+	if err != nil {
+		return result, errors.Wrap(err, "GetLatest")
+	}
+	if settings == nil {
+		// Settings were never saved for this subject, so there is nothing to migrate.
+		return result, nil
+	}
+
+	var multi error
+	integrated, err := insights.IntegratedInsightsFromSettings(settings)
+	if err != nil {
+		multi = multierror.Append(multi, err)
+	}
+	search, err := insights.SearchInsightsFromSettings(settings)
+	if err != nil {
+		multi = multierror.Append(multi, err)
+	}
+	langStats, err := insights.LangStatsInsightsFromSettings(settings)
+	if err != nil {
+		multi = multierror.Append(multi, err)
+	}
+	dashboards, err := insights.DashboardsFromSettings(settings)
+	if err != nil {
+		multi = multierror.Append(multi, err)
+	}
+
+	toMigrate := make([]settingsInsight, 0, len(integrated)+len(search)+len(langStats))
+	for _, insight := range integrated {
+		toMigrate = append(toMigrate, integratedInsight(insight))
+	}
+	for _, insight := range search {
+		toMigrate = append(toMigrate, searchInsight(insight))
+	}
+	for _, insight := range langStats {
+		toMigrate = append(toMigrate, langStatsInsight(insight))
+	}
+
+	for _, insight := range toMigrate {
+		if insight.view.UniqueID == "" || len(insight.series) == 0 {
+			// An insight needs a unique ID to be referenced by dashboards, and an insight without
+			// series has nothing to show.
+			log15.Warn("insights migration: skipping insight", "unique_id", insight.view.UniqueID, "title", insight.view.Title)
+			continue
+		}
+		if err := m.migrateInsight(ctx, insight); err != nil {
+			multi = multierror.Append(multi, errors.Wrapf(err, "insight %q", insight.view.UniqueID))
+			continue
+		}
+		result.insights++
+	}
+
+	for _, dashboard := range dashboards {
+		if err := m.migrateDashboard(ctx, dashboard); err != nil {
+			multi = multierror.Append(multi, errors.Wrapf(err, "dashboard %q", dashboard.ID))
+			continue
+		}
+		result.dashboards++
+	}
+	return result, multi
+}
+
+// settingsInsight is an insight defined in settings, converted to the records that represent it in
+// the code insights database.
+type settingsInsight struct {
+	view     types.InsightView
+	series   []types.InsightSeries
+	metadata []types.InsightViewSeriesMetadata
+	grant    store.InsightViewGrant
+
+	// shareSeries reuses existing data series with the same series ID, rather than failing to
+	// create them. Integrated insights derive their series IDs from their queries, so the same
+	// query across insights is only recorded once.
+	shareSeries bool
+}
+
+// integratedInsight converts an insight that runs over all repositories.
+func integratedInsight(from insights.SearchInsight) settingsInsight {
+	insight := settingsInsight{
+		view:        types.InsightView{Title: from.Title, Description: from.Description, UniqueID: from.ID},
+		grant:       viewGrant(from.UserID, from.OrgID),
+		shareSeries: true,
+	}
+	for _, timeSeries := range from.Series {
+		insight.series = append(insight.series, types.InsightSeries{
+			SeriesID:            discovery.Encode(timeSeries),
+			Query:               timeSeries.Query,
+			SampleIntervalUnit:  string(types.Month),
+			SampleIntervalValue: 1,
+			GenerationMethod:    types.GenerationMethodSearch,
+		})
+		insight.metadata = append(insight.metadata, types.InsightViewSeriesMetadata{Label: timeSeries.Name, Stroke: timeSeries.Stroke})
+	}
+	return insight
+}
+
+// searchInsight converts an insight that runs over a list of repositories.
+func searchInsight(from insights.SearchInsight) settingsInsight {
+	unit, value := sampleInterval(from.Step)
+	insight := settingsInsight{
+		view:  types.InsightView{Title: from.Title, Description: from.Description, UniqueID: from.ID},
+		grant: viewGrant(from.UserID, from.OrgID),
+	}
+	for _, timeSeries := range from.Series {
+		insight.series = append(insight.series, types.InsightSeries{
+			SeriesID:            ksuid.New().String(),
+			Query:               timeSeries.Query,
+			Repositories:        from.Repositories,
+			SampleIntervalUnit:  string(unit),
+			SampleIntervalValue: value,
+			GenerationMethod:    types.GenerationMethodSearch,
+		})
+		insight.metadata = append(insight.metadata, types.InsightViewSeriesMetadata{Label: timeSeries.Name, Stroke: timeSeries.Stroke})
+	}
+	return insight
+}
+
+// langStatsInsight converts a language statistics insight of a single repository.
+func langStatsInsight(from insights.LangStatsInsight) settingsInsight {
+	insight := settingsInsight{
+		view:  types.InsightView{Title: from.Title, UniqueID: from.ID},
+		grant: viewGrant(from.UserID, from.OrgID),
+	}
+	if from.Repository != "" {
+		insight.series = []types.InsightSeries{{
+			SeriesID:            ksuid.New().String(),
+			Repositories:        []string{from.Repository},
+			SampleIntervalUnit:  string(types.Month),
+			SampleIntervalValue: 1,
+			GenerationMethod:    types.GenerationMethodLanguageStats,
+		}}
+		insight.metadata = []types.InsightViewSeriesMetadata{{Label: from.Title}}
+	}
+	return insight
+}
+
+// sampleInterval returns the largest unit of the given step interval that is set. Insights without
+// a step interval are sampled monthly, like integrated insights.
+func sampleInterval(step insights.Interval) (types.IntervalUnit, int) {
+	switch {
+	case step.Years != nil:
+		return types.Year, *step.Years
+	case step.Months != nil:
+		return types.Month, *step.Months
+	case step.Weeks != nil:
+		return types.Week, *step.Weeks
+	case step.Days != nil:
+		return types.Day, *step.Days
+	case step.Hours != nil:
+		return types.Hour, *step.Hours
+	default:
+		return types.Month, 1
+	}
+}
+
+func viewGrant(userID, orgID *int32) store.InsightViewGrant {
+	switch {
+	case userID != nil:
+		return store.UserGrant(int(*userID))
+	case orgID != nil:
+		return store.OrgGrant(int(*orgID))
+	default:
+		return store.GlobalGrant()
+	}
+}
+
+// migrateInsight creates the view and series of an insight, unless a view with the same unique ID
+// already exists.
+func (m *settingsMigrator) migrateInsight(ctx context.Context, insight settingsInsight) (err error) {
+	tx, err := m.insightStore.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Store.Done(err) }()
+
+	existing, err := tx.Get(ctx, store.InsightQueryArgs{UniqueID: insight.view.UniqueID, WithoutAuthorization: true})
+	if err != nil {
+		return errors.Wrap(err, "Get")
+	}
+	if len(existing) > 0 {
+		return nil
+	}
+
+	now := time.Now()
+	dataSeries := make([]types.InsightSeries, 0, len(insight.series))
+	for _, series := range insight.series {
+		if insight.shareSeries {
+			shared, err := tx.GetDataSeries(ctx, store.GetDataSeriesArgs{SeriesID: series.SeriesID})
+			if err != nil {
+				return errors.Wrap(err, "GetDataSeries")
+			}
+			if len(shared) > 0 {
+				dataSeries = append(dataSeries, shared[0])
+				continue
+			}
+		}
+		series.CreatedAt = now
+		series.NextRecordingAfter = insights.NextRecording(now)
+		series.NextSnapshotAfter = insights.NextSnapshot(now)
+		created, err := tx.CreateSeries(ctx, series)
+		if err != nil {
+			return errors.Wrap(err, "CreateSeries")
+		}
+		dataSeries = append(dataSeries, created)
+	}
+
+	view, err := tx.CreateView(ctx, insight.view, []store.InsightViewGrant{insight.grant})
+	if err != nil {
+		return errors.Wrap(err, "CreateView")
+	}
+	for i, series := range dataSeries {
+		if err := tx.AttachSeriesToView(ctx, series, view, insight.metadata[i]); err != nil {
+			return errors.Wrap(err, "AttachSeriesToView")
+		}
+	}
+	return nil
+}
+
+// migrateDashboard creates a dashboard, unless a dashboard with the same title and owner already
+// exists. Dashboards reference insights by their unique IDs, which are the keys of the insights in
+// settings.
+func (m *settingsMigrator) migrateDashboard(ctx context.Context, from insights.SettingDashboard) (err error) {
+	tx, err := m.dashboardStore.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Store.Done(err) }()
+
+	var grant store.DashboardGrant
+	var grantPred *sqlf.Query
+	switch {
+	case from.UserID != nil:
+		grant = store.UserDashboardGrant(int(*from.UserID))
+		grantPred = sqlf.Sprintf("dg.user_id = %s", *from.UserID)
+	case from.OrgID != nil:
+		grant = store.OrgDashboardGrant(int(*from.OrgID))
+		grantPred = sqlf.Sprintf("dg.org_id = %s", *from.OrgID)
+	default:
+		grant = store.GlobalDashboardGrant()
+		grantPred = sqlf.Sprintf("dg.global IS TRUE")
+	}
+
+	exists, _, err := basestore.ScanFirstBool(tx.Query(ctx, sqlf.Sprintf(dashboardExistsSql, from.Title, grantPred)))
+	if err != nil {
+		return errors.Wrap(err, "dashboardExists")
+	}
+	if exists {
+		return nil
+	}
+
+	_, err = tx.CreateDashboard(ctx, store.CreateDashboardArgs{
+		Dashboard: types.Dashboard{Title: from.Title, InsightIDs: from.InsightIds},
+		Grants:    []store.DashboardGrant{grant},
+	})
+	return err
+}
+
+const dashboardExistsSql = `
+-- source: enterprise/internal/insights/migration/migrator.go:migrateDashboard
+SELECT EXISTS (
+	SELECT 1 FROM dashboard db
+	JOIN dashboard_grants dg ON db.id = dg.dashboard_id
+	WHERE db.title = %s AND db.deleted_at IS NULL AND %s
+)
+`
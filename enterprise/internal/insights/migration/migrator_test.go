@@ -0,0 +1,135 @@
+package migration
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+func TestSampleInterval(t *testing.T) {
+	two := 2
+	testCases := []struct {
+		name      string
+		step      insights.Interval
+		wantUnit  types.IntervalUnit
+		wantValue int
+	}{
+		{name: "no step", step: insights.Interval{}, wantUnit: types.Month, wantValue: 1},
+		{name: "weeks", step: insights.Interval{Weeks: &two}, wantUnit: types.Week, wantValue: 2},
+		{name: "hours", step: insights.Interval{Hours: &two}, wantUnit: types.Hour, wantValue: 2},
+		{name: "largest unit", step: insights.Interval{Days: &two, Years: &two}, wantUnit: types.Year, wantValue: 2},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			unit, value := sampleInterval(tc.step)
+			if unit != tc.wantUnit || value != tc.wantValue {
+				t.Errorf("unexpected interval: want %d %s, got %d %s", tc.wantValue, tc.wantUnit, value, unit)
+			}
+		})
+	}
+}
+
+func TestJobSubject(t *testing.T) {
+	id := int32(7)
+	testCases := []struct {
+		job  settingsMigrationJob
+		want api.SettingsSubject
+	}{
+		{job: settingsMigrationJob{Global: true}, want: api.SettingsSubject{Site: true}},
+		{job: settingsMigrationJob{OrgID: &id}, want: api.SettingsSubject{Org: &id}},
+		{job: settingsMigrationJob{UserID: &id}, want: api.SettingsSubject{User: &id}},
+	}
+	for _, tc := range testCases {
+		if diff := cmp.Diff(tc.want, tc.job.subject()); diff != "" {
+			t.Errorf("unexpected subject (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestConvertInsights(t *testing.T) {
+	org := int32(3)
+	weeks := 2
+	seriesOpts := cmpopts.IgnoreFields(types.InsightSeries{}, "SeriesID")
+
+	t.Run("integrated", func(t *testing.T) {
+		from := insights.SearchInsight{
+			ID:     "insights.allrepos.todo",
+			Title:  "TODOs",
+			Series: []insights.TimeSeries{{Name: "todo", Stroke: "red", Query: "TODO"}},
+			OrgID:  &org,
+		}
+		got := integratedInsight(from)
+		want := settingsInsight{
+			view: types.InsightView{Title: "TODOs", UniqueID: "insights.allrepos.todo"},
+			series: []types.InsightSeries{{
+				SeriesID:            got.series[0].SeriesID,
+				Query:               "TODO",
+				SampleIntervalUnit:  string(types.Month),
+				SampleIntervalValue: 1,
+				GenerationMethod:    types.GenerationMethodSearch,
+			}},
+			metadata:    []types.InsightViewSeriesMetadata{{Label: "todo", Stroke: "red"}},
+			grant:       store.OrgGrant(3),
+			shareSeries: true,
+		}
+		if diff := cmp.Diff(want, got, cmp.AllowUnexported(settingsInsight{})); diff != "" {
+			t.Errorf("unexpected insight (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("search", func(t *testing.T) {
+		from := insights.SearchInsight{
+			ID:           "searchInsights.insight.todo",
+			Title:        "TODOs",
+			Repositories: []string{"github.com/sourcegraph/sourcegraph"},
+			Series:       []insights.TimeSeries{{Name: "todo", Stroke: "red", Query: "TODO"}},
+			Step:         insights.Interval{Weeks: &weeks},
+		}
+		got := searchInsight(from)
+		want := settingsInsight{
+			view: types.InsightView{Title: "TODOs", UniqueID: "searchInsights.insight.todo"},
+			series: []types.InsightSeries{{
+				Query:               "TODO",
+				Repositories:        []string{"github.com/sourcegraph/sourcegraph"},
+				SampleIntervalUnit:  string(types.Week),
+				SampleIntervalValue: 2,
+				GenerationMethod:    types.GenerationMethodSearch,
+			}},
+			metadata: []types.InsightViewSeriesMetadata{{Label: "todo", Stroke: "red"}},
+			grant:    store.GlobalGrant(),
+		}
+		if diff := cmp.Diff(want, got, cmp.AllowUnexported(settingsInsight{}), seriesOpts); diff != "" {
+			t.Errorf("unexpected insight (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("language stats", func(t *testing.T) {
+		from := insights.LangStatsInsight{
+			ID:         "codeStatsInsights.insight.languages",
+			Title:      "Languages",
+			Repository: "github.com/sourcegraph/sourcegraph",
+			OrgID:      &org,
+		}
+		got := langStatsInsight(from)
+		want := settingsInsight{
+			view: types.InsightView{Title: "Languages", UniqueID: "codeStatsInsights.insight.languages"},
+			series: []types.InsightSeries{{
+				Repositories:        []string{"github.com/sourcegraph/sourcegraph"},
+				SampleIntervalUnit:  string(types.Month),
+				SampleIntervalValue: 1,
+				GenerationMethod:    types.GenerationMethodLanguageStats,
+			}},
+			metadata: []types.InsightViewSeriesMetadata{{Label: "Languages"}},
+			grant:    store.OrgGrant(3),
+		}
+		if diff := cmp.Diff(want, got, cmp.AllowUnexported(settingsInsight{}), seriesOpts); diff != "" {
+			t.Errorf("unexpected insight (-want +got):\n%s", diff)
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package resolvers
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/cockroachdb/errors"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+)
+
+var _ graphqlbackend.InsightSeriesAlertResolver = &insightSeriesAlertResolver{}
+
+const insightSeriesAlertKind = "InsightSeriesAlert"
+
+func (r *Resolver) InsightSeriesAlerts(ctx context.Context, args *graphqlbackend.InsightSeriesAlertsArgs) ([]graphqlbackend.InsightSeriesAlertResolver, error) {
+	// 🚨 SECURITY: Users can only list their own alerts.
+	uid := actor.FromContext(ctx).UID
+	if uid == 0 {
+		return nil, backend.ErrNotAuthenticated
+	}
+	alerts, err := r.alertStore.GetAlerts(ctx, store.AlertQueryArgs{SeriesID: args.SeriesId, UserID: uid})
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]graphqlbackend.InsightSeriesAlertResolver, 0, len(alerts))
+	for _, alert := range alerts {
+		resolvers = append(resolvers, &insightSeriesAlertResolver{alert: alert})
+	}
+	return resolvers, nil
+}
+
+func (r *Resolver) CreateInsightSeriesAlert(ctx context.Context, args *graphqlbackend.CreateInsightSeriesAlertArgs) (graphqlbackend.InsightSeriesAlertResolver, error) {
+	uid := actor.FromContext(ctx).UID
+	if uid == 0 {
+		return nil, backend.ErrNotAuthenticated
+	}
+
+	input := args.Input
+	alert := types.InsightSeriesAlert{
+		SeriesID:  input.SeriesId,
+		UserID:    uid,
+		Threshold: input.Threshold,
+		Direction: types.AlertDirection(input.Direction),
+		Email:     true,
+	}
+	if input.EvaluationWindowDays != nil {
+		if *input.EvaluationWindowDays < 0 {
+			return nil, errors.New("evaluationWindowDays must not be negative")
+		}
+		alert.EvaluationWindowDays = int(*input.EvaluationWindowDays)
+	}
+	if input.Email != nil {
+		alert.Email = *input.Email
+	}
+	if input.WebhookURL != nil && *input.WebhookURL != "" {
+		// 🚨 SECURITY: Webhooks are posted to from within the instance, so only site admins may
+		// configure them.
+		if err := backend.CheckUserIsSiteAdmin(ctx, r.postgresDB, uid); err != nil {
+			return nil, err
+		}
+		u, err := url.Parse(*input.WebhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, errors.Newf("invalid webhook URL: %q", *input.WebhookURL)
+		}
+		alert.WebhookURL = input.WebhookURL
+	}
+	if !alert.Email && alert.WebhookURL == nil {
+		return nil, errors.New("an alert must notify by email or webhook")
+	}
+
+	series, err := r.dataSeriesStore.GetDataSeries(ctx, store.GetDataSeriesArgs{SeriesID: input.SeriesId})
+	if err != nil {
+		return nil, err
+	}
+	if len(series) == 0 {
+		return nil, errors.Newf("unable to fetch series with series_id: %v", input.SeriesId)
+	}
+
+	alert, err = r.alertStore.CreateAlert(ctx, alert)
+	if err != nil {
+		return nil, errors.Wrap(err, "CreateAlert")
+	}
+	return &insightSeriesAlertResolver{alert: alert}, nil
+}
+
+func (r *Resolver) DeleteInsightSeriesAlert(ctx context.Context, args *graphqlbackend.DeleteInsightSeriesAlertArgs) (*graphqlbackend.EmptyResponse, error) {
+	uid := actor.FromContext(ctx).UID
+	if uid == 0 {
+		return nil, backend.ErrNotAuthenticated
+	}
+	var id int
+	if err := relay.UnmarshalSpec(args.Id, &id); err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal alert id")
+	}
+
+	// 🚨 SECURITY: Users can only delete their own alerts.
+	alerts, err := r.alertStore.GetAlerts(ctx, store.AlertQueryArgs{ID: id, UserID: uid})
+	if err != nil {
+		return nil, err
+	}
+	if len(alerts) == 0 {
+		return nil, errors.Newf("alert not found: %d", id)
+	}
+
+	if err := r.alertStore.DeleteAlert(ctx, id); err != nil {
+		return nil, err
+	}
+	return &graphqlbackend.EmptyResponse{}, nil
+}
+
+type insightSeriesAlertResolver struct {
+	alert types.InsightSeriesAlert
+}
+
+func (r *insightSeriesAlertResolver) ID() graphql.ID {
+	return relay.MarshalID(insightSeriesAlertKind, r.alert.ID)
+}
+
+func (r *insightSeriesAlertResolver) SeriesId() string { return r.alert.SeriesID }
+
+func (r *insightSeriesAlertResolver) Threshold() float64 { return r.alert.Threshold }
+
+func (r *insightSeriesAlertResolver) Direction() string { return string(r.alert.Direction) }
+
+func (r *insightSeriesAlertResolver) EvaluationWindowDays() int32 {
+	return int32(r.alert.EvaluationWindowDays)
+}
+
+func (r *insightSeriesAlertResolver) Email() bool { return r.alert.Email }
+
+func (r *insightSeriesAlertResolver) WebhookURL() *string { return r.alert.WebhookURL }
+
+func (r *insightSeriesAlertResolver) Triggered() bool { return r.alert.Triggered }
+
+func (r *insightSeriesAlertResolver) LastEvaluatedAt() *graphqlbackend.DateTime {
+	return graphqlbackend.DateTimeOrNil(r.alert.LastEvaluatedAt)
+}
+
+func (r *insightSeriesAlertResolver) LastTriggeredAt() *graphqlbackend.DateTime {
+	return graphqlbackend.DateTimeOrNil(r.alert.LastTriggeredAt)
+}
@@ -0,0 +1,76 @@
+package resolvers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	itypes "github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/compute"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// computeOverCommitsPoints is the number of points computed for a compute query over commits.
+const computeOverCommitsPoints = 12
+
+// ComputeOverCommits counts the matches of the given compute query over commits. Like a live
+// preview, the matches are counted at the most recent commit before each point in time in a
+// sample of the repositories matched by the repo filters of the query. Predicates such as
+// `repo:contains.file()` are evaluated by the search at each commit.
+func ComputeOverCommits(ctx context.Context, db dbutil.DB, q *compute.Query) (*compute.TimeSeries, error) {
+	return newLivePreviewer(db).computeOverCommits(ctx, q)
+}
+
+func (p *livePreviewer) computeOverCommits(ctx context.Context, q *compute.Query) (*compute.TimeSeries, error) {
+	command, ok := q.Command.(*compute.CountOverCommits)
+	if !ok {
+		return nil, errors.Errorf("unsupported compute command %T", q.Command)
+	}
+	times, err := livePreviewTimes(p.now(), itypes.IntervalUnit(command.Interval.Unit), command.Interval.Value, computeOverCommitsPoints)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Repositories are listed with the permissions of the current user. Every search
+	// below is restricted to one of them, so the series only contains data the user can see.
+	opt := database.ReposListOptions{
+		OnlyCloned:  true,
+		LimitOffset: &database.LimitOffset{Limit: livePreviewSampleSize},
+	}
+	includePatterns, excludePatterns := compute.RepoFilters(q.Parameters)
+	for _, pattern := range append(includePatterns, excludePatterns...) {
+		if strings.Contains(pattern, "@") {
+			return nil, errors.New("repo: filters with revisions are not supported over commits")
+		}
+	}
+	if len(includePatterns) > 0 {
+		opt.IncludePatterns = includePatterns
+	} else {
+		opt.NoForks = true
+		opt.NoArchived = true
+		opt.OrderBy = database.RepoListOrderBy{{Field: database.RepoListStars, Descending: true}}
+	}
+	if len(excludePatterns) > 0 {
+		opt.ExcludePattern = strings.Join(excludePatterns, "|")
+	}
+	repos, err := p.listRepos(ctx, opt)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing repositories")
+	}
+
+	points, complete, err := p.compute(ctx, command.SearchQuery(q.Parameters), repos, times)
+	if err != nil {
+		return nil, err
+	}
+	series := &compute.TimeSeries{
+		Points:              make([]compute.TimeSeriesPoint, 0, len(points)),
+		SampledRepositories: len(repos),
+		Complete:            complete,
+	}
+	for _, point := range points {
+		series.Points = append(series.Points, compute.TimeSeriesPoint{Time: point.Time, Value: int(point.Value)})
+	}
+	return series, nil
+}
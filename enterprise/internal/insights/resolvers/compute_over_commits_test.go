@@ -0,0 +1,86 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/compute"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git/gitapi"
+)
+
+func TestComputeOverCommits(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC)
+	firstCommit := now.AddDate(0, 0, -14)
+
+	var listOptions database.ReposListOptions
+	var queries []string
+	previewer := &livePreviewer{
+		now: func() time.Time { return now },
+		listRepos: func(ctx context.Context, opt database.ReposListOptions) ([]*types.Repo, error) {
+			listOptions = opt
+			// Compute over a single repository so that queries is not appended to concurrently.
+			return []*types.Repo{{ID: 1, Name: "github.com/a/a"}}, nil
+		},
+		findRecentCommit: func(ctx context.Context, repoName api.RepoName, target time.Time) ([]*gitapi.Commit, error) {
+			if target.Before(firstCommit) {
+				return nil, nil
+			}
+			return []*gitapi.Commit{{ID: "deadbeef"}}, nil
+		},
+		countMatches: func(ctx context.Context, query string) (int, error) {
+			queries = append(queries, query)
+			return 3, nil
+		},
+		timeBudget: time.Minute,
+	}
+
+	q, err := compute.Parse(`repo:^github\.com/a/ -repo:b$ repo:contains.file(go.mod) content:count(errorf) over commits(1 week)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	series, err := previewer.computeOverCommits(ctx, q)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]string{`^github\.com/a/`}, listOptions.IncludePatterns); diff != "" {
+		t.Errorf("unexpected include patterns (-want +got):\n%s", diff)
+	}
+	if listOptions.ExcludePattern != "b$" {
+		t.Errorf("unexpected exclude pattern: %q", listOptions.ExcludePattern)
+	}
+	if !series.Complete || series.SampledRepositories != 1 || len(series.Points) != computeOverCommitsPoints {
+		t.Fatalf("unexpected series: %+v", series)
+	}
+	var values []int
+	for _, point := range series.Points {
+		values = append(values, point.Value)
+	}
+	// Only the last three points are at or after the first commit.
+	if diff := cmp.Diff([]int{0, 0, 0, 0, 0, 0, 0, 0, 0, 3, 3, 3}, values); diff != "" {
+		t.Errorf("unexpected values (-want +got):\n%s", diff)
+	}
+	if !series.Points[11].Time.Equal(now) || !series.Points[10].Time.Equal(now.AddDate(0, 0, -7)) {
+		t.Errorf("unexpected times: %v, %v", series.Points[10].Time, series.Points[11].Time)
+	}
+	if diff := cmp.Diff(`patterntype:regexp repo:contains.file(go.mod) "errorf" count:all repo:^github\.com/a/a$@deadbeef`, queries[0]); diff != "" {
+		t.Errorf("unexpected query (-want +got):\n%s", diff)
+	}
+
+	t.Run("repo filter with revision", func(t *testing.T) {
+		q, err := compute.Parse(`repo:foo@main content:count(errorf) over commits`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := previewer.computeOverCommits(ctx, q); err == nil {
+			t.Error("expected error for repo: filter with revision")
+		}
+	})
+}
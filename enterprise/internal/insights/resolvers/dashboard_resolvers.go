@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 
 	"github.com/graph-gophers/graphql-go/relay"
@@ -302,6 +303,53 @@ func (r *Resolver) DeleteInsightsDashboard(ctx context.Context, args *graphqlbac
 	return emptyResponse, nil
 }
 
+func (r *Resolver) DuplicateInsightsDashboard(ctx context.Context, args *graphqlbackend.DuplicateInsightsDashboardArgs) (graphqlbackend.InsightsDashboardPayloadResolver, error) {
+	dashboardID, err := unmarshalDashboardID(args.Id)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal dashboard id")
+	}
+	if dashboardID.isVirtualized() {
+		return nil, errors.New("unable to duplicate a virtualized dashboard")
+	}
+
+	dashboardGrants := []store.DashboardGrant{store.UserDashboardGrant(int(actor.FromContext(ctx).UID))}
+	if args.Input.Grants != nil {
+		if dashboardGrants, err = parseDashboardGrants(*args.Input.Grants); err != nil {
+			return nil, errors.Wrap(err, "unable to parse dashboard grants")
+		}
+	}
+	var scope *store.SeriesScope
+	if args.Input.RepositoryScope != nil {
+		repositories, repositoryQuery, err := repositoryScopeFromInput(*args.Input.RepositoryScope)
+		if err != nil {
+			return nil, err
+		}
+		scope = &store.SeriesScope{Repositories: repositories, RepositoryQuery: repositoryQuery}
+	}
+
+	userIds, orgIds, err := getUserPermissions(ctx, database.Orgs(r.workerBaseStore.Handle().DB()))
+	if err != nil {
+		return nil, errors.Wrap(err, "getUserPermissions")
+	}
+	// 🚨 SECURITY: The dashboard store checks that the user has permission to access the
+	// duplicated dashboard, which grants access to its insights.
+	dashboard, err := r.dashboardStore.DuplicateDashboard(ctx, store.DuplicateDashboardArgs{
+		ID:     int(dashboardID.Arg),
+		Title:  emptyIfNil(args.Input.Title),
+		Grants: dashboardGrants,
+		Scope:  scope,
+		UserID: userIds,
+		OrgID:  orgIds,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if dashboard == nil {
+		return nil, nil
+	}
+	return &insightsDashboardPayloadResolver{dashboard: dashboard, baseInsightResolver: r.baseInsightResolver}, nil
+}
+
 func (r *Resolver) AddInsightViewToDashboard(ctx context.Context, args *graphqlbackend.AddInsightViewToDashboardArgs) (graphqlbackend.InsightsDashboardPayloadResolver, error) {
 	var viewID string
 	err := relay.UnmarshalSpec(args.Input.InsightViewID, &viewID)
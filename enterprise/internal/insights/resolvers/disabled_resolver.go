@@ -35,6 +35,10 @@ func (r *disabledResolver) DeleteInsightsDashboard(ctx context.Context, args *gr
 	return nil, errors.New(r.reason)
 }
 
+func (r *disabledResolver) DuplicateInsightsDashboard(ctx context.Context, args *graphqlbackend.DuplicateInsightsDashboardArgs) (graphqlbackend.InsightsDashboardPayloadResolver, error) {
+	return nil, errors.New(r.reason)
+}
+
 func (r *disabledResolver) AddInsightViewToDashboard(ctx context.Context, args *graphqlbackend.AddInsightViewToDashboardArgs) (graphqlbackend.InsightsDashboardPayloadResolver, error) {
 	return nil, errors.New(r.reason)
 }
@@ -51,6 +55,22 @@ func (r *disabledResolver) InsightSeriesQueryStatus(ctx context.Context) ([]grap
 	return nil, errors.New(r.reason)
 }
 
+func (r *disabledResolver) SearchInsightLivePreview(ctx context.Context, args *graphqlbackend.SearchInsightLivePreviewArgs) (graphqlbackend.SearchInsightLivePreviewSeriesResolver, error) {
+	return nil, errors.New(r.reason)
+}
+
 func (r *disabledResolver) CreateLineChartSearchInsight(ctx context.Context, args *graphqlbackend.CreateLineChartSearchInsightArgs) (graphqlbackend.CreateInsightResultResolver, error) {
 	return nil, errors.New(r.reason)
 }
+
+func (r *disabledResolver) InsightSeriesAlerts(ctx context.Context, args *graphqlbackend.InsightSeriesAlertsArgs) ([]graphqlbackend.InsightSeriesAlertResolver, error) {
+	return nil, errors.New(r.reason)
+}
+
+func (r *disabledResolver) CreateInsightSeriesAlert(ctx context.Context, args *graphqlbackend.CreateInsightSeriesAlertArgs) (graphqlbackend.InsightSeriesAlertResolver, error) {
+	return nil, errors.New(r.reason)
+}
+
+func (r *disabledResolver) DeleteInsightSeriesAlert(ctx context.Context, args *graphqlbackend.DeleteInsightSeriesAlertArgs) (*graphqlbackend.EmptyResponse, error) {
+	return nil, errors.New(r.reason)
+}
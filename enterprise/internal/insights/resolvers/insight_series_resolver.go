@@ -19,9 +19,18 @@ type insightSeriesResolver struct {
 	workerBaseStore *basestore.Store
 	series          types.InsightViewSeries
 	metadataStore   store.InsightMetadataStore
+
+	// capture is the capture group value this resolver represents, if the series is generated
+	// from capture groups.
+	capture *string
 }
 
-func (r *insightSeriesResolver) Label() string { return r.series.Label }
+func (r *insightSeriesResolver) Label() string {
+	if r.capture != nil {
+		return *r.capture
+	}
+	return r.series.Label
+}
 
 func (r *insightSeriesResolver) Points(ctx context.Context, args *graphqlbackend.InsightsPointsArgs) ([]graphqlbackend.InsightsDataPointResolver, error) {
 	var opts store.SeriesPointsOpts
@@ -29,6 +38,7 @@ func (r *insightSeriesResolver) Points(ctx context.Context, args *graphqlbackend
 	// Query data points only for the series we are representing.
 	seriesID := r.series.SeriesID
 	opts.SeriesID = &seriesID
+	opts.Capture = r.capture
 
 	if args.From == nil {
 		// Default to last 12mo of data
@@ -83,6 +93,9 @@ func (r *insightSeriesResolver) Status(ctx context.Context) (graphqlbackend.Insi
 		completedJobs:    int32(status.Completed),
 		failedJobs:       int32(status.Failed),
 		backfillQueuedAt: r.series.BackfillQueuedAt,
+
+		backfillEstimatedCost: r.series.BackfillEstimatedCost,
+		backfillSpentCost:     int32(r.series.BackfillSpentCost),
 	}, nil
 }
 
@@ -111,6 +124,8 @@ func (i insightsDataPointResolver) Value() float64 { return i.p.Value }
 type insightStatusResolver struct {
 	totalPoints, pendingJobs, completedJobs, failedJobs int32
 	backfillQueuedAt                                    *time.Time
+	backfillEstimatedCost                               *int
+	backfillSpentCost                                   int32
 }
 
 func (i insightStatusResolver) TotalPoints() int32   { return i.totalPoints }
@@ -120,3 +135,11 @@ func (i insightStatusResolver) FailedJobs() int32    { return i.failedJobs }
 func (i insightStatusResolver) BackfillQueuedAt() *graphqlbackend.DateTime {
 	return graphqlbackend.DateTimeOrNil(i.backfillQueuedAt)
 }
+func (i insightStatusResolver) BackfillEstimatedCost() *int32 {
+	if i.backfillEstimatedCost == nil {
+		return nil
+	}
+	cost := int32(*i.backfillEstimatedCost)
+	return &cost
+}
+func (i insightStatusResolver) BackfillSpentCost() int32 { return i.backfillSpentCost }
@@ -2,12 +2,15 @@ package resolvers
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/cockroachdb/errors"
 
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/compute"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
 
 	"github.com/segmentio/ksuid"
 
@@ -63,13 +66,45 @@ func (i *insightViewResolver) AppliedFilters(ctx context.Context) (graphqlbacken
 
 func (i *insightViewResolver) DataSeries(ctx context.Context) ([]graphqlbackend.InsightSeriesResolver, error) {
 	var resolvers []graphqlbackend.InsightSeriesResolver
+	maxJustInTimeRepositories := justInTimeMaxRepositories()
 	for j := range i.view.Series {
-		resolvers = append(resolvers, &insightSeriesResolver{
-			insightsStore:   i.timeSeriesStore,
-			workerBaseStore: i.workerBaseStore,
-			series:          i.view.Series[j],
-			metadataStore:   i.insightStore,
-		})
+		if !i.view.Series[j].GeneratedFromCaptureGroups && i.view.Series[j].GenerationMethod == types.GenerationMethodSearch {
+			resolver := &insightSeriesResolver{
+				insightsStore:   i.timeSeriesStore,
+				workerBaseStore: i.workerBaseStore,
+				series:          i.view.Series[j],
+				metadataStore:   i.insightStore,
+			}
+			// Series over a handful of repositories are cheap enough to compute on demand, so
+			// they render without waiting for the next recording.
+			if supportsJustInTime(i.view.Series[j], maxJustInTimeRepositories) {
+				resolvers = append(resolvers, &justInTimeSeriesResolver{
+					insightSeriesResolver: resolver,
+					previewer:             newLivePreviewer(i.postgresDB),
+				})
+				continue
+			}
+			resolvers = append(resolvers, resolver)
+			continue
+		}
+
+		// A series generated from capture groups resolves to one series per distinct capture
+		// group value that has been recorded so far. Language statistics series record the
+		// language as the capture group value, so they resolve to one series per language, and
+		// top repositories series likewise resolve to one series per repository.
+		captures, err := i.timeSeriesStore.CaptureValues(ctx, i.view.Series[j].SeriesID)
+		if err != nil {
+			return nil, errors.Wrap(err, "CaptureValues")
+		}
+		for k := range captures {
+			resolvers = append(resolvers, &insightSeriesResolver{
+				insightsStore:   i.timeSeriesStore,
+				workerBaseStore: i.workerBaseStore,
+				series:          i.view.Series[j],
+				metadataStore:   i.insightStore,
+				capture:         &captures[k],
+			})
+		}
 	}
 
 	return resolvers, nil
@@ -101,8 +136,30 @@ func (s *searchInsightDataSeriesDefinitionResolver) Query(ctx context.Context) (
 	return s.series.Query, nil
 }
 
+func (s *searchInsightDataSeriesDefinitionResolver) GeneratedFromCaptureGroups(ctx context.Context) (bool, error) {
+	return s.series.GeneratedFromCaptureGroups, nil
+}
+
+func (s *searchInsightDataSeriesDefinitionResolver) GenerationMethod(ctx context.Context) (string, error) {
+	if s.series.GenerationMethod == types.GenerationMethodLanguageStats {
+		return "LANGUAGE_STATS", nil
+	}
+	if s.series.GenerationMethod == types.GenerationMethodTopRepositories {
+		return "TOP_REPOSITORIES", nil
+	}
+	return "SEARCH", nil
+}
+
+func (s *searchInsightDataSeriesDefinitionResolver) TopRepositoriesLimit(ctx context.Context) (*int32, error) {
+	if s.series.GenerationMethod != types.GenerationMethodTopRepositories {
+		return nil, nil
+	}
+	limit := int32(s.series.TopRepositoriesLimit)
+	return &limit, nil
+}
+
 func (s *searchInsightDataSeriesDefinitionResolver) RepositoryScope(ctx context.Context) (graphqlbackend.InsightRepositoryScopeResolver, error) {
-	return &insightRepositoryScopeResolver{repositories: s.series.Repositories}, nil
+	return &insightRepositoryScopeResolver{repositories: s.series.Repositories, repositoryQuery: s.series.RepositoryQuery}, nil
 }
 
 func (s *searchInsightDataSeriesDefinitionResolver) TimeScope(ctx context.Context) (graphqlbackend.InsightTimeScope, error) {
@@ -128,13 +185,21 @@ func (i *insightIntervalTimeScopeResolver) Value(ctx context.Context) (int32, er
 }
 
 type insightRepositoryScopeResolver struct {
-	repositories []string
+	repositories    []string
+	repositoryQuery string
 }
 
 func (i *insightRepositoryScopeResolver) Repositories(ctx context.Context) ([]string, error) {
 	return i.repositories, nil
 }
 
+func (i *insightRepositoryScopeResolver) RepositoryQuery(ctx context.Context) (*string, error) {
+	if i.repositoryQuery == "" {
+		return nil, nil
+	}
+	return &i.repositoryQuery, nil
+}
+
 type lineChartInsightViewPresentation struct {
 	view *types.Insight
 }
@@ -188,13 +253,46 @@ func (r *Resolver) CreateLineChartSearchInsight(ctx context.Context, args *graph
 	}
 
 	for _, series := range args.Input.DataSeries {
+		generationMethod, err := generationMethodFromInput(series.GenerationMethod)
+		if err != nil {
+			return nil, err
+		}
+		if series.GeneratedFromCaptureGroups != nil && *series.GeneratedFromCaptureGroups {
+			if generationMethod != types.GenerationMethodSearch {
+				return nil, errors.New("only search series can be generated from capture groups")
+			}
+			if err := validateCaptureGroupQuery(series.Query); err != nil {
+				return nil, err
+			}
+		}
+		repositories, repositoryQuery, err := repositoryScopeFromInput(series.RepositoryScope)
+		if err != nil {
+			return nil, err
+		}
+		if generationMethod == types.GenerationMethodLanguageStats && len(repositories) == 0 && repositoryQuery == "" {
+			return nil, errors.New("language statistics series require a repository scope")
+		}
+		var topRepositoriesLimit int
+		if generationMethod == types.GenerationMethodTopRepositories {
+			if len(repositories) > 0 {
+				return nil, errors.New("top repositories series cannot have a repository scope")
+			}
+			if topRepositoriesLimit, err = topRepositoriesLimitFromInput(series.TopRepositoriesLimit); err != nil {
+				return nil, err
+			}
+		}
 		created, err := tx.CreateSeries(ctx, types.InsightSeries{
 			SeriesID:            ksuid.New().String(), // ignoring sharing data series for now, we will just always generate unique series
 			Query:               series.Query,
 			CreatedAt:           time.Now(),
-			Repositories:        series.RepositoryScope.Repositories,
+			Repositories:        repositories,
 			SampleIntervalUnit:  series.TimeScope.StepInterval.Unit,
 			SampleIntervalValue: int(series.TimeScope.StepInterval.Value),
+
+			GeneratedFromCaptureGroups: series.GeneratedFromCaptureGroups != nil && *series.GeneratedFromCaptureGroups,
+			GenerationMethod:           generationMethod,
+			TopRepositoriesLimit:       topRepositoriesLimit,
+			RepositoryQuery:            repositoryQuery,
 		})
 		if err != nil {
 			return nil, errors.Wrap(err, "CreateSeries")
@@ -210,6 +308,71 @@ func (r *Resolver) CreateLineChartSearchInsight(ctx context.Context, args *graph
 	return &createInsightResultResolver{baseInsightResolver: r.baseInsightResolver, viewId: view.UniqueID}, nil
 }
 
+// generationMethodFromInput returns the generation method of a series given its GraphQL
+// InsightSeriesGenerationMethod, which defaults to SEARCH.
+func generationMethodFromInput(method *string) (types.GenerationMethod, error) {
+	if method == nil {
+		return types.GenerationMethodSearch, nil
+	}
+	switch *method {
+	case "SEARCH":
+		return types.GenerationMethodSearch, nil
+	case "LANGUAGE_STATS":
+		return types.GenerationMethodLanguageStats, nil
+	case "TOP_REPOSITORIES":
+		return types.GenerationMethodTopRepositories, nil
+	default:
+		return "", errors.Newf("unsupported series generation method: %q", *method)
+	}
+}
+
+// topRepositoriesLimitFromInput returns the number of repositories recorded by a top repositories
+// series, which defaults to 10.
+func topRepositoriesLimitFromInput(limit *int32) (int, error) {
+	if limit == nil {
+		return 10, nil
+	}
+	if *limit <= 0 {
+		return 0, errors.New("topRepositoriesLimit must be positive")
+	}
+	return int(*limit), nil
+}
+
+// repositoryScopeFromInput returns the repositories and the repository query of a series given
+// its GraphQL RepositoryScopeInput. A scope defined by a repository query has no repositories, so
+// that the series is recorded like a global series and scoped when it is recorded.
+func repositoryScopeFromInput(input graphqlbackend.RepositoryScopeInput) ([]string, string, error) {
+	repositoryQuery := strings.TrimSpace(emptyIfNil(input.RepositoryQuery))
+	if repositoryQuery == "" {
+		return input.Repositories, "", nil
+	}
+	if len(input.Repositories) > 0 {
+		return nil, "", errors.New("a repository scope cannot have both repositories and a repository query")
+	}
+	if _, err := query.ParseLiteral(repositoryQuery); err != nil {
+		return nil, "", errors.Wrap(err, "invalid repository query")
+	}
+	return nil, repositoryQuery, nil
+}
+
+// validateCaptureGroupQuery returns an error if the given query cannot be used for a series that
+// is generated from capture groups.
+func validateCaptureGroupQuery(query string) error {
+	computeQuery, err := compute.Parse(query)
+	if err != nil {
+		return errors.Wrap(err, "invalid capture group query")
+	}
+	matchOnly, ok := computeQuery.Command.(*compute.MatchOnly)
+	if !ok {
+		return errors.New("invalid capture group query: only regexp search patterns are supported")
+	}
+	pattern, ok := matchOnly.MatchPattern.(*compute.Regexp)
+	if !ok || pattern.Value.NumSubexp() == 0 {
+		return errors.New("invalid capture group query: the search pattern must contain a capture group")
+	}
+	return nil
+}
+
 type createInsightResultResolver struct {
 	viewId string
 	baseInsightResolver
@@ -0,0 +1,144 @@
+package resolvers
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	itypes "github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// justInTimePoints is the maximum number of points computed for a series that is computed just in
+// time.
+const justInTimePoints = 12
+
+// justInTimeMaxRepositories returns the maximum number of repositories in the repository scope of
+// a series for it to be computed just in time. Zero disables just in time computation.
+func justInTimeMaxRepositories() int {
+	if max := conf.Get().InsightsJustInTimeMaxRepositories; max != nil {
+		return *max
+	}
+	return 10
+}
+
+// supportsJustInTime returns true if the points of the given series can be computed when the
+// series is resolved, rather than read from the recordings of the query runner.
+func supportsJustInTime(series itypes.InsightViewSeries, maxRepositories int) bool {
+	return series.GenerationMethod == itypes.GenerationMethodSearch &&
+		!series.GeneratedFromCaptureGroups &&
+		len(series.Repositories) > 0 &&
+		len(series.Repositories) <= maxRepositories &&
+		series.SampleIntervalValue > 0 &&
+		background.SupportsHistoricalQuery(series.Query)
+}
+
+var _ graphqlbackend.InsightSeriesResolver = &justInTimeSeriesResolver{}
+
+// justInTimeSeriesResolver resolves the points of a series with a small repository scope by
+// computing them synchronously, so that the series does not have to wait for the query runner to
+// record it. The recorded points are used if the points cannot be computed within the time budget
+// of the previewer.
+type justInTimeSeriesResolver struct {
+	*insightSeriesResolver
+
+	previewer *livePreviewer
+}
+
+func (r *justInTimeSeriesResolver) Points(ctx context.Context, args *graphqlbackend.InsightsPointsArgs) ([]graphqlbackend.InsightsDataPointResolver, error) {
+	points, complete, err := r.computePoints(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	if !complete {
+		return r.insightSeriesResolver.Points(ctx, args)
+	}
+	resolvers := make([]graphqlbackend.InsightsDataPointResolver, 0, len(points))
+	for _, point := range points {
+		resolvers = append(resolvers, insightsDataPointResolver{point})
+	}
+	return resolvers, nil
+}
+
+// computePoints computes the points of the series in the requested time range, at the sample
+// interval of the series and ending now.
+func (r *justInTimeSeriesResolver) computePoints(ctx context.Context, args *graphqlbackend.InsightsPointsArgs) ([]store.SeriesPoint, bool, error) {
+	now := r.previewer.now()
+	from := now.AddDate(-1, 0, 0) // Default to last 12mo of data, like recorded series
+	if args.From != nil {
+		from = args.From.Time
+	}
+	to := now
+	if args.To != nil {
+		to = args.To.Time
+	}
+	allTimes, err := livePreviewTimes(now, itypes.IntervalUnit(r.series.SampleIntervalUnit), r.series.SampleIntervalValue, justInTimePoints)
+	if err != nil {
+		return nil, false, err
+	}
+	var times []time.Time
+	for _, t := range allTimes {
+		if !t.Before(from) && !t.After(to) {
+			times = append(times, t)
+		}
+	}
+
+	// 🚨 SECURITY: Repositories are listed with the permissions of the current user. Every search
+	// is restricted to one of them, so the points only contain data the user can see.
+	repos, err := r.previewer.listRepos(ctx, database.ReposListOptions{
+		Names:      r.series.Repositories,
+		OnlyCloned: true,
+	})
+	if err != nil {
+		return nil, false, errors.Wrap(err, "listing repositories")
+	}
+	repos, err = filterRepos(repos, args.IncludeRepoRegex, args.ExcludeRepoRegex)
+	if err != nil {
+		return nil, false, err
+	}
+
+	points, complete, err := r.previewer.compute(ctx, r.series.Query, repos, times)
+	if err != nil {
+		return nil, false, err
+	}
+	for i := range points {
+		points[i].SeriesID = r.series.SeriesID
+	}
+	return points, complete, nil
+}
+
+// filterRepos returns the repositories whose names match the include pattern and do not match the
+// exclude pattern, if given.
+func filterRepos(repos []*types.Repo, includeRepoRegex, excludeRepoRegex *string) ([]*types.Repo, error) {
+	var include, exclude *regexp.Regexp
+	var err error
+	if includeRepoRegex != nil && *includeRepoRegex != "" {
+		if include, err = regexp.Compile(*includeRepoRegex); err != nil {
+			return nil, errors.Wrap(err, "includeRepoRegex")
+		}
+	}
+	if excludeRepoRegex != nil && *excludeRepoRegex != "" {
+		if exclude, err = regexp.Compile(*excludeRepoRegex); err != nil {
+			return nil, errors.Wrap(err, "excludeRepoRegex")
+		}
+	}
+
+	filtered := make([]*types.Repo, 0, len(repos))
+	for _, repo := range repos {
+		if include != nil && !include.MatchString(string(repo.Name)) {
+			continue
+		}
+		if exclude != nil && exclude.MatchString(string(repo.Name)) {
+			continue
+		}
+		filtered = append(filtered, repo)
+	}
+	return filtered, nil
+}
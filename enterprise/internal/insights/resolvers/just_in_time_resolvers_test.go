@@ -0,0 +1,103 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	itypes "github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git/gitapi"
+)
+
+func TestSupportsJustInTime(t *testing.T) {
+	series := itypes.InsightViewSeries{
+		Query:               "errorf",
+		GenerationMethod:    itypes.GenerationMethodSearch,
+		Repositories:        []string{"github.com/a/a", "github.com/b/b"},
+		SampleIntervalUnit:  string(itypes.Month),
+		SampleIntervalValue: 1,
+	}
+	if !supportsJustInTime(series, 2) {
+		t.Error("expected series to support just in time computation")
+	}
+
+	for name, modify := range map[string]func(s *itypes.InsightViewSeries){
+		"too many repositories": func(s *itypes.InsightViewSeries) { s.Repositories = append(s.Repositories, "github.com/c/c") },
+		"global":                func(s *itypes.InsightViewSeries) { s.Repositories = nil },
+		"capture groups":        func(s *itypes.InsightViewSeries) { s.GeneratedFromCaptureGroups = true },
+		"language stats":        func(s *itypes.InsightViewSeries) { s.GenerationMethod = itypes.GenerationMethodLanguageStats },
+		"repo filter":           func(s *itypes.InsightViewSeries) { s.Query = "errorf repo:foo" },
+	} {
+		t.Run(name, func(t *testing.T) {
+			modified := series
+			modify(&modified)
+			if supportsJustInTime(modified, 2) {
+				t.Error("expected series not to support just in time computation")
+			}
+		})
+	}
+}
+
+func TestJustInTimeSeriesResolver(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	var listed database.ReposListOptions
+	resolver := &justInTimeSeriesResolver{
+		insightSeriesResolver: &insightSeriesResolver{series: itypes.InsightViewSeries{
+			SeriesID:            "s1",
+			Query:               "errorf",
+			Repositories:        []string{"github.com/a/a", "github.com/b/b"},
+			SampleIntervalUnit:  string(itypes.Month),
+			SampleIntervalValue: 1,
+		}},
+		previewer: &livePreviewer{
+			now: func() time.Time { return now },
+			listRepos: func(ctx context.Context, opt database.ReposListOptions) ([]*types.Repo, error) {
+				listed = opt
+				return []*types.Repo{{ID: 1, Name: "github.com/a/a"}, {ID: 2, Name: "github.com/b/b"}}, nil
+			},
+			findRecentCommit: func(ctx context.Context, repoName api.RepoName, target time.Time) ([]*gitapi.Commit, error) {
+				return []*gitapi.Commit{{ID: "deadbeef"}}, nil
+			},
+			countMatches: func(ctx context.Context, query string) (int, error) { return 2, nil },
+			timeBudget:   time.Minute,
+		},
+	}
+
+	t.Run("computes points in range", func(t *testing.T) {
+		from := graphqlbackend.DateTime{Time: now.AddDate(0, -2, 0)}
+		points, err := resolver.Points(ctx, &graphqlbackend.InsightsPointsArgs{From: &from})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []float64
+		for _, point := range points {
+			got = append(got, point.Value())
+		}
+		if diff := cmp.Diff([]float64{4, 4, 4}, got); diff != "" {
+			t.Errorf("unexpected values (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff([]string{"github.com/a/a", "github.com/b/b"}, listed.Names); diff != "" {
+			t.Errorf("unexpected listed repositories (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("repo filters", func(t *testing.T) {
+		from := graphqlbackend.DateTime{Time: now}
+		exclude := "/b/b$"
+		points, err := resolver.Points(ctx, &graphqlbackend.InsightsPointsArgs{From: &from, ExcludeRepoRegex: &exclude})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(points) != 1 || points[0].Value() != 2 {
+			t.Errorf("unexpected points: %+v", points)
+		}
+	})
+}
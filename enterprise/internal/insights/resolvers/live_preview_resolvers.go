@@ -0,0 +1,228 @@
+package resolvers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/queryrunner"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	itypes "github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git/gitapi"
+)
+
+var _ graphqlbackend.SearchInsightLivePreviewSeriesResolver = &searchInsightLivePreviewSeriesResolver{}
+
+const (
+	// livePreviewSampleSize is the maximum number of repositories a live preview is computed over.
+	livePreviewSampleSize = 20
+	// livePreviewPoints is the number of points computed for a live preview.
+	livePreviewPoints = 7
+	// livePreviewTimeBudget bounds the time spent computing a live preview. Points that could not
+	// be computed within the budget are partial.
+	livePreviewTimeBudget = 10 * time.Second
+	// livePreviewConcurrency is the number of repositories a live preview is computed for
+	// concurrently.
+	livePreviewConcurrency = 8
+)
+
+func (r *Resolver) SearchInsightLivePreview(ctx context.Context, args *graphqlbackend.SearchInsightLivePreviewArgs) (graphqlbackend.SearchInsightLivePreviewSeriesResolver, error) {
+	return newLivePreviewer(r.postgresDB).preview(ctx, args.Input)
+}
+
+func newLivePreviewer(postgresDB dbutil.DB) *livePreviewer {
+	return &livePreviewer{
+		now:       time.Now,
+		listRepos: database.Repos(postgresDB).List,
+		findRecentCommit: func(ctx context.Context, repoName api.RepoName, target time.Time) ([]*gitapi.Commit, error) {
+			return git.Commits(ctx, repoName, git.CommitsOptions{N: 1, Before: target.Format(time.RFC3339), DateOrder: true})
+		},
+		countMatches: queryrunner.SearchMatchCount,
+		timeBudget:   livePreviewTimeBudget,
+	}
+}
+
+// livePreviewer computes a search insight series synchronously over a sample of repositories. It
+// generates the same queries as the historical enqueuer, but runs them directly instead of
+// enqueuing them to the query runner.
+type livePreviewer struct {
+	now              func() time.Time
+	listRepos        func(ctx context.Context, opt database.ReposListOptions) ([]*types.Repo, error)
+	findRecentCommit func(ctx context.Context, repoName api.RepoName, target time.Time) ([]*gitapi.Commit, error)
+	countMatches     func(ctx context.Context, query string) (int, error)
+	timeBudget       time.Duration
+}
+
+func (p *livePreviewer) preview(ctx context.Context, input graphqlbackend.SearchInsightLivePreviewInput) (*searchInsightLivePreviewSeriesResolver, error) {
+	if input.Query == "" {
+		return nil, errors.New("query must not be empty")
+	}
+	if !background.SupportsHistoricalQuery(input.Query) {
+		return nil, errors.New("live preview does not support queries with a repo: filter, use the repository scope instead")
+	}
+	if emptyIfNil(input.RepositoryScope.RepositoryQuery) != "" {
+		return nil, errors.New("live preview does not support repository queries, use a list of repositories instead")
+	}
+	interval := input.TimeScope.StepInterval
+	if interval == nil || interval.Value <= 0 {
+		return nil, errors.New("a positive step interval is required")
+	}
+	times, err := livePreviewTimes(p.now(), itypes.IntervalUnit(interval.Unit), int(interval.Value), livePreviewPoints)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Repositories are listed with the permissions of the current user. Every search
+	// below is restricted to one of them, so the preview only contains data the user can see.
+	opt := database.ReposListOptions{
+		OnlyCloned:  true,
+		LimitOffset: &database.LimitOffset{Limit: livePreviewSampleSize},
+	}
+	if len(input.RepositoryScope.Repositories) > 0 {
+		opt.Names = input.RepositoryScope.Repositories
+	} else {
+		opt.NoForks = true
+		opt.NoArchived = true
+		opt.OrderBy = database.RepoListOrderBy{{Field: database.RepoListStars, Descending: true}}
+	}
+	repos, err := p.listRepos(ctx, opt)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing repositories")
+	}
+
+	points, complete, err := p.compute(ctx, input.Query, repos, times)
+	if err != nil {
+		return nil, err
+	}
+	return &searchInsightLivePreviewSeriesResolver{
+		label:               input.Label,
+		points:              points,
+		sampledRepositories: len(repos),
+		complete:            complete,
+	}, nil
+}
+
+// compute computes the value of the query summed over the given repositories at each of the given
+// times. The returned points are not complete if the time budget was exhausted before all values
+// could be computed.
+func (p *livePreviewer) compute(ctx context.Context, query string, repos []*types.Repo, times []time.Time) (_ []store.SeriesPoint, complete bool, _ error) {
+	budgetCtx, cancel := context.WithTimeout(ctx, p.timeBudget)
+	defer cancel()
+
+	var mu sync.Mutex
+	values := make([]float64, len(times))
+	bounded := goroutine.NewBounded(livePreviewConcurrency)
+	for _, repo := range repos {
+		repo := repo
+		bounded.Go(func() error {
+			return p.previewRepo(budgetCtx, query, repo, times, func(i int, count int) {
+				mu.Lock()
+				values[i] += float64(count)
+				mu.Unlock()
+			})
+		})
+	}
+	complete = true
+	if err := bounded.Wait(); err != nil {
+		if !errors.Is(budgetCtx.Err(), context.DeadlineExceeded) {
+			return nil, false, err
+		}
+		complete = false
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	points := make([]store.SeriesPoint, 0, len(times))
+	for i, t := range times {
+		points = append(points, store.SeriesPoint{Time: t, Value: values[i]})
+	}
+	return points, complete, nil
+}
+
+// previewRepo computes the value of the query in the given repository at each of the given times
+// and passes it to record.
+func (p *livePreviewer) previewRepo(ctx context.Context, query string, repo *types.Repo, times []time.Time, record func(i int, count int)) error {
+	for i, t := range times {
+		commits, err := p.findRecentCommit(ctx, repo.Name, t)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if errors.HasType(err, &gitdomain.RevisionNotFoundError{}) || gitdomain.IsRepoNotExist(err) {
+				return nil // the repository may not be cloned yet
+			}
+			return errors.Wrap(err, "FindNearestCommit")
+		}
+		if len(commits) == 0 {
+			continue // the repository had no commits yet at that time
+		}
+		count, err := p.countMatches(ctx, background.HistoricalQuery(query, string(repo.Name), string(commits[0].ID)))
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		record(i, count)
+	}
+	return nil
+}
+
+// livePreviewTimes returns the given number of times, spaced by the given interval and ending at
+// now, in ascending order.
+func livePreviewTimes(now time.Time, unit itypes.IntervalUnit, value int, points int) ([]time.Time, error) {
+	times := make([]time.Time, points)
+	t := now
+	for i := points - 1; i >= 0; i-- {
+		times[i] = t
+		switch unit {
+		case itypes.Year:
+			t = t.AddDate(-value, 0, 0)
+		case itypes.Month:
+			t = t.AddDate(0, -value, 0)
+		case itypes.Week:
+			t = t.AddDate(0, 0, -7*value)
+		case itypes.Day:
+			t = t.AddDate(0, 0, -value)
+		case itypes.Hour:
+			t = t.Add(-time.Duration(value) * time.Hour)
+		default:
+			return nil, errors.Newf("unsupported interval unit: %q", unit)
+		}
+	}
+	return times, nil
+}
+
+type searchInsightLivePreviewSeriesResolver struct {
+	label               string
+	points              []store.SeriesPoint
+	sampledRepositories int
+	complete            bool
+}
+
+func (r *searchInsightLivePreviewSeriesResolver) Label() string { return r.label }
+
+func (r *searchInsightLivePreviewSeriesResolver) Points() []graphqlbackend.InsightsDataPointResolver {
+	resolvers := make([]graphqlbackend.InsightsDataPointResolver, 0, len(r.points))
+	for _, point := range r.points {
+		resolvers = append(resolvers, insightsDataPointResolver{point})
+	}
+	return resolvers
+}
+
+func (r *searchInsightLivePreviewSeriesResolver) SampledRepositories() int32 {
+	return int32(r.sampledRepositories)
+}
+
+func (r *searchInsightLivePreviewSeriesResolver) Complete() bool { return r.complete }
@@ -0,0 +1,139 @@
+package resolvers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git/gitapi"
+)
+
+func TestLivePreview(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC)
+	firstCommit := now.AddDate(0, -1, 0)
+
+	input := graphqlbackend.SearchInsightLivePreviewInput{
+		Query:     "errorf",
+		Label:     "errors",
+		TimeScope: graphqlbackend.TimeScopeInput{StepInterval: &graphqlbackend.TimeIntervalStepInput{Unit: "MONTH", Value: 1}},
+	}
+
+	newPreviewer := func(countMatches func(ctx context.Context, query string) (int, error)) *livePreviewer {
+		return &livePreviewer{
+			now: func() time.Time { return now },
+			listRepos: func(ctx context.Context, opt database.ReposListOptions) ([]*types.Repo, error) {
+				return []*types.Repo{{ID: 1, Name: "github.com/a/a"}, {ID: 2, Name: "github.com/b/b"}}, nil
+			},
+			findRecentCommit: func(ctx context.Context, repoName api.RepoName, target time.Time) ([]*gitapi.Commit, error) {
+				if target.Before(firstCommit) {
+					return nil, nil
+				}
+				return []*gitapi.Commit{{ID: "deadbeef"}}, nil
+			},
+			countMatches: countMatches,
+			timeBudget:   time.Minute,
+		}
+	}
+
+	t.Run("queries", func(t *testing.T) {
+		var queries []string
+		previewer := newPreviewer(func(ctx context.Context, query string) (int, error) {
+			queries = append(queries, query)
+			return 2, nil
+		})
+		// Preview a single repository so that queries is not appended to concurrently.
+		previewer.listRepos = func(ctx context.Context, opt database.ReposListOptions) ([]*types.Repo, error) {
+			return []*types.Repo{{ID: 1, Name: "github.com/a/a"}}, nil
+		}
+
+		got, err := previewer.preview(ctx, input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Complete() || got.SampledRepositories() != 1 || got.Label() != "errors" {
+			t.Fatalf("unexpected preview: %+v", got)
+		}
+		var values []float64
+		for _, point := range got.Points() {
+			values = append(values, point.Value())
+		}
+		// Only the last two points are at or after the first commit.
+		if diff := cmp.Diff([]float64{0, 0, 0, 0, 0, 2, 2}, values); diff != "" {
+			t.Errorf("unexpected values (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff([]string{
+			"errorf count:all repo:^github\\.com/a/a$@deadbeef",
+			"errorf count:all repo:^github\\.com/a/a$@deadbeef",
+		}, queries); diff != "" {
+			t.Errorf("unexpected queries (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("sums sampled repositories", func(t *testing.T) {
+		previewer := newPreviewer(func(ctx context.Context, query string) (int, error) {
+			if strings.Contains(query, "github\\.com/a/a") {
+				return 2, nil
+			}
+			return 3, nil
+		})
+
+		got, err := previewer.preview(ctx, input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.SampledRepositories() != 2 {
+			t.Fatalf("unexpected sampled repositories: %d", got.SampledRepositories())
+		}
+		if v := got.Points()[6].Value(); v != 5 {
+			t.Errorf("unexpected latest value: %v", v)
+		}
+	})
+
+	t.Run("time budget exhausted", func(t *testing.T) {
+		previewer := newPreviewer(func(ctx context.Context, query string) (int, error) {
+			<-ctx.Done()
+			return 0, ctx.Err()
+		})
+		previewer.timeBudget = 10 * time.Millisecond
+
+		got, err := previewer.preview(ctx, input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Complete() {
+			t.Error("expected preview to be incomplete")
+		}
+	})
+
+	t.Run("repo filter", func(t *testing.T) {
+		previewer := newPreviewer(nil)
+		withRepo := input
+		withRepo.Query = "errorf repo:foo"
+		if _, err := previewer.preview(ctx, withRepo); err == nil {
+			t.Error("expected error for query with repo: filter")
+		}
+	})
+}
+
+func TestLivePreviewTimes(t *testing.T) {
+	now := time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC)
+	got, err := livePreviewTimes(now, "WEEK", 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []time.Time{now.AddDate(0, 0, -28), now.AddDate(0, 0, -14), now}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected times (-want +got):\n%s", diff)
+	}
+	if _, err := livePreviewTimes(now, "FORTNIGHT", 1, 3); err == nil {
+		t.Error("expected error for unknown unit")
+	}
+}
@@ -0,0 +1,160 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// AlertStore is the interface describing the storage of insight series alerts.
+type AlertStore interface {
+	GetAlerts(ctx context.Context, args AlertQueryArgs) ([]types.InsightSeriesAlert, error)
+	CreateAlert(ctx context.Context, alert types.InsightSeriesAlert) (types.InsightSeriesAlert, error)
+	DeleteAlert(ctx context.Context, id int) error
+	UpdateAlertState(ctx context.Context, id int, triggered bool) error
+}
+
+var _ AlertStore = &DBAlertStore{}
+
+type DBAlertStore struct {
+	*basestore.Store
+	Now func() time.Time
+}
+
+// NewAlertStore returns a new DBAlertStore backed by the given Timescale db.
+func NewAlertStore(db dbutil.DB) *DBAlertStore {
+	return &DBAlertStore{Store: basestore.NewWithDB(db, sql.TxOptions{}), Now: time.Now}
+}
+
+// Handle returns the underlying transactable database handle.
+// Needed to implement the ShareableStore interface.
+func (s *DBAlertStore) Handle() *basestore.TransactableHandle { return s.Store.Handle() }
+
+// With creates a new DBAlertStore with the given basestore. Shareable store as the underlying basestore.Store.
+// Needed to implement the basestore.Store interface
+func (s *DBAlertStore) With(other *DBAlertStore) *DBAlertStore {
+	return &DBAlertStore{Store: s.Store.With(other.Store), Now: other.Now}
+}
+
+func (s *DBAlertStore) Transact(ctx context.Context) (*DBAlertStore, error) {
+	txBase, err := s.Store.Transact(ctx)
+	return &DBAlertStore{Store: txBase, Now: s.Now}, err
+}
+
+type AlertQueryArgs struct {
+	ID       int
+	SeriesID string
+	UserID   int32
+}
+
+// GetAlerts returns all alerts matching the given arguments, ordered by ID.
+func (s *DBAlertStore) GetAlerts(ctx context.Context, args AlertQueryArgs) ([]types.InsightSeriesAlert, error) {
+	preds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if args.ID > 0 {
+		preds = append(preds, sqlf.Sprintf("id = %s", args.ID))
+	}
+	if args.SeriesID != "" {
+		preds = append(preds, sqlf.Sprintf("series_id = %s", args.SeriesID))
+	}
+	if args.UserID > 0 {
+		preds = append(preds, sqlf.Sprintf("user_id = %s", args.UserID))
+	}
+	return scanAlerts(s.Query(ctx, sqlf.Sprintf(getAlertsSql, sqlf.Join(preds, "\n AND "))))
+}
+
+// CreateAlert stores a new alert and returns it with its ID and creation time populated.
+func (s *DBAlertStore) CreateAlert(ctx context.Context, alert types.InsightSeriesAlert) (types.InsightSeriesAlert, error) {
+	alert.CreatedAt = s.Now().UTC()
+	row := s.QueryRow(ctx, sqlf.Sprintf(insertAlertSql,
+		alert.SeriesID,
+		alert.UserID,
+		alert.Threshold,
+		string(alert.Direction),
+		alert.EvaluationWindowDays,
+		alert.Email,
+		alert.WebhookURL,
+		alert.CreatedAt,
+	))
+	if err := row.Scan(&alert.ID); err != nil {
+		return types.InsightSeriesAlert{}, err
+	}
+	return alert, nil
+}
+
+// DeleteAlert deletes the alert with the given ID.
+func (s *DBAlertStore) DeleteAlert(ctx context.Context, id int) error {
+	return s.Exec(ctx, sqlf.Sprintf(deleteAlertSql, id))
+}
+
+// UpdateAlertState records the result of an evaluation of the alert with the given ID. The time
+// the alert was last triggered is only updated when it changes from not triggered to triggered.
+func (s *DBAlertStore) UpdateAlertState(ctx context.Context, id int, triggered bool) error {
+	now := s.Now().UTC()
+	return s.Exec(ctx, sqlf.Sprintf(updateAlertStateSql, triggered, now, triggered, now, id))
+}
+
+func scanAlerts(rows *sql.Rows, queryErr error) (_ []types.InsightSeriesAlert, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	results := make([]types.InsightSeriesAlert, 0)
+	for rows.Next() {
+		var temp types.InsightSeriesAlert
+		if err := rows.Scan(
+			&temp.ID,
+			&temp.SeriesID,
+			&temp.UserID,
+			&temp.Threshold,
+			&temp.Direction,
+			&temp.EvaluationWindowDays,
+			&temp.Email,
+			&temp.WebhookURL,
+			&temp.Triggered,
+			&temp.CreatedAt,
+			&temp.LastEvaluatedAt,
+			&temp.LastTriggeredAt,
+		); err != nil {
+			return nil, err
+		}
+		results = append(results, temp)
+	}
+	return results, nil
+}
+
+const getAlertsSql = `
+-- source: enterprise/internal/insights/store/alert_store.go:GetAlerts
+SELECT id, series_id, user_id, threshold, direction, evaluation_window_days, email, webhook_url,
+triggered, created_at, last_evaluated_at, last_triggered_at
+FROM insight_series_alerts
+WHERE %s
+ORDER BY id
+`
+
+const insertAlertSql = `
+-- source: enterprise/internal/insights/store/alert_store.go:CreateAlert
+INSERT INTO insight_series_alerts (series_id, user_id, threshold, direction, evaluation_window_days, email, webhook_url, created_at)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s)
+RETURNING id;
+`
+
+const deleteAlertSql = `
+-- source: enterprise/internal/insights/store/alert_store.go:DeleteAlert
+DELETE FROM insight_series_alerts WHERE id = %s;
+`
+
+const updateAlertStateSql = `
+-- source: enterprise/internal/insights/store/alert_store.go:UpdateAlertState
+UPDATE insight_series_alerts
+SET triggered = %s,
+    last_evaluated_at = %s,
+    last_triggered_at = CASE WHEN %s AND NOT triggered THEN %s ELSE last_triggered_at END
+WHERE id = %s;
+`
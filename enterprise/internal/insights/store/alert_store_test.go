@@ -0,0 +1,93 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	insightsdbtesting "github.com/sourcegraph/sourcegraph/enterprise/internal/insights/dbtesting"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+)
+
+func TestAlertStore(t *testing.T) {
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	now := time.Now().UTC().Truncate(time.Microsecond).Round(0)
+	ctx := context.Background()
+	store := NewAlertStore(timescale)
+	store.Now = func() time.Time {
+		return now
+	}
+
+	webhookURL := "https://example.com/hook"
+	created, err := store.CreateAlert(ctx, types.InsightSeriesAlert{
+		SeriesID:             "series1",
+		UserID:               1,
+		Threshold:            0,
+		Direction:            types.AlertDirectionAtLeast,
+		EvaluationWindowDays: 30,
+		Email:                true,
+		WebhookURL:           &webhookURL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.CreateAlert(ctx, types.InsightSeriesAlert{
+		SeriesID:  "series2",
+		UserID:    2,
+		Threshold: 100,
+		Direction: types.AlertDirectionAtMost,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("get by series", func(t *testing.T) {
+		got, err := store.GetAlerts(ctx, AlertQueryArgs{SeriesID: "series1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]types.InsightSeriesAlert{created}, got); diff != "" {
+			t.Errorf("unexpected alerts (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("update state", func(t *testing.T) {
+		if err := store.UpdateAlertState(ctx, created.ID, true); err != nil {
+			t.Fatal(err)
+		}
+		later := now.Add(time.Hour)
+		store.Now = func() time.Time { return later }
+		if err := store.UpdateAlertState(ctx, created.ID, true); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := store.GetAlerts(ctx, AlertQueryArgs{ID: created.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := created
+		want.Triggered = true
+		want.LastEvaluatedAt = &later
+		// The alert was already triggered by the first evaluation, so the second one must not
+		// change the time it was last triggered at.
+		want.LastTriggeredAt = &now
+		if diff := cmp.Diff([]types.InsightSeriesAlert{want}, got); diff != "" {
+			t.Errorf("unexpected alerts (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if err := store.DeleteAlert(ctx, created.ID); err != nil {
+			t.Fatal(err)
+		}
+		got, err := store.GetAlerts(ctx, AlertQueryArgs{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || got[0].SeriesID != "series2" {
+			t.Errorf("unexpected alerts after delete: %+v", got)
+		}
+	})
+}
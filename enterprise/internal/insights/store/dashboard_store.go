@@ -311,6 +311,86 @@ func (s *DBDashboardStore) AddDashboardGrants(ctx context.Context, dashboardId i
 	return nil
 }
 
+// ErrDashboardPermission is returned when a user does not have permission to access a dashboard.
+var ErrDashboardPermission = errors.New("this user does not have permission to access this dashboard")
+
+// SeriesScope is a repository scope that replaces the scope of the series of duplicated insights.
+type SeriesScope struct {
+	Repositories    []string
+	RepositoryQuery string
+}
+
+type DuplicateDashboardArgs struct {
+	ID int
+	// Title defaults to the title of the duplicated dashboard followed by "(copy)".
+	Title  string
+	Grants []DashboardGrant
+	// Scope, if set, re-scopes every series of the duplicated insights. Otherwise the
+	// duplicated insights share the series of the original ones.
+	Scope  *SeriesScope
+	UserID []int // For dashboard permissions
+	OrgID  []int // For dashboard permissions
+}
+
+// DuplicateDashboard creates a new dashboard with a copy of every insight view of the given
+// dashboard. The copied insight views are granted to the same users and organizations as the new
+// dashboard. The given user and organizations must have permission to access the dashboard.
+func (s *DBDashboardStore) DuplicateDashboard(ctx context.Context, args DuplicateDashboardArgs) (_ *types.Dashboard, err error) {
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	hasPermission, err := tx.HasDashboardPermission(ctx, args.ID, args.UserID, args.OrgID)
+	if err != nil {
+		return nil, errors.Wrap(err, "HasDashboardPermission")
+	}
+	if !hasPermission {
+		return nil, ErrDashboardPermission
+	}
+	dashboards, err := tx.GetDashboards(ctx, DashboardQueryArgs{ID: args.ID, UserID: args.UserID, OrgID: args.OrgID})
+	if err != nil {
+		return nil, errors.Wrap(err, "GetDashboards")
+	}
+	if len(dashboards) == 0 {
+		return nil, errors.Newf("dashboard not found: %d", args.ID)
+	}
+
+	// The insight views are in the same database, so they are copied in the same transaction.
+	insightTx := &InsightStore{Store: basestore.NewWithHandle(tx.Handle()), Now: tx.Now}
+	viewIDs := make([]string, 0, len(dashboards[0].InsightIDs))
+	for _, uniqueID := range dashboards[0].InsightIDs {
+		viewID, err := insightTx.duplicateView(ctx, uniqueID, viewGrants(args.Grants), args.Scope)
+		if err != nil {
+			return nil, errors.Wrapf(err, "duplicating insight view %s", uniqueID)
+		}
+		if viewID != "" {
+			viewIDs = append(viewIDs, viewID)
+		}
+	}
+
+	title := args.Title
+	if title == "" {
+		title = dashboards[0].Title + " (copy)"
+	}
+	return tx.CreateDashboard(ctx, CreateDashboardArgs{
+		Dashboard: types.Dashboard{Title: title, InsightIDs: viewIDs, Save: true},
+		Grants:    args.Grants,
+		UserID:    args.UserID,
+		OrgID:     args.OrgID,
+	})
+}
+
+// viewGrants returns the insight view grants equivalent to the given dashboard grants.
+func viewGrants(grants []DashboardGrant) []InsightViewGrant {
+	viewGrants := make([]InsightViewGrant, 0, len(grants))
+	for _, grant := range grants {
+		viewGrants = append(viewGrants, InsightViewGrant{UserID: grant.UserID, OrgID: grant.OrgID, Global: grant.Global})
+	}
+	return viewGrants
+}
+
 const insertDashboardSql = `
 -- source: enterprise/internal/insights/store/dashboard_store.go:CreateDashboard
 INSERT INTO dashboard (title, save) VALUES (%s, %s) RETURNING id;
@@ -375,4 +455,5 @@ type DashboardStore interface {
 	UpdateDashboard(ctx context.Context, args UpdateDashboardArgs) (_ *types.Dashboard, err error)
 	DeleteDashboard(ctx context.Context, id int64) error
 	HasDashboardPermission(ctx context.Context, dashboardId int, userIds []int, orgIds []int) (bool, error)
+	DuplicateDashboard(ctx context.Context, args DuplicateDashboardArgs) (_ *types.Dashboard, err error)
 }
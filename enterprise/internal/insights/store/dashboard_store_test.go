@@ -425,3 +425,133 @@ func TestRemoveViewsFromDashboard(t *testing.T) {
 	})
 
 }
+
+func TestDuplicateDashboard(t *testing.T) {
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	now := time.Now().Truncate(time.Microsecond).Round(0)
+	ctx := context.Background()
+
+	store := NewDashboardStore(timescale)
+	store.Now = func() time.Time {
+		return now
+	}
+	insightStore := NewInsightStore(timescale)
+
+	view, err := insightStore.CreateView(ctx, types.InsightView{
+		Title:       "view1",
+		Description: "view1",
+		UniqueID:    "view1",
+	}, []InsightViewGrant{UserGrant(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	series, err := insightStore.CreateSeries(ctx, types.InsightSeries{
+		SeriesID:            "series1",
+		Query:               "errors.New",
+		Repositories:        []string{"github.com/a/a"},
+		SampleIntervalUnit:  string(types.Month),
+		SampleIntervalValue: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := insightStore.AttachSeriesToView(ctx, series, view, types.InsightViewSeriesMetadata{Label: "label1", Stroke: "blue"}); err != nil {
+		t.Fatal(err)
+	}
+	original, err := store.CreateDashboard(ctx, CreateDashboardArgs{
+		Dashboard: types.Dashboard{Title: "original", InsightIDs: []string{view.UniqueID}},
+		Grants:    []DashboardGrant{UserDashboardGrant(1)},
+		UserID:    []int{1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// duplicatedSeries returns the series of the only view of the given dashboard.
+	duplicatedSeries := func(t *testing.T, dashboard *types.Dashboard) []types.InsightViewSeries {
+		t.Helper()
+		if len(dashboard.InsightIDs) != 1 || dashboard.InsightIDs[0] == view.UniqueID {
+			t.Fatalf("expected a copy of the view, have %v", dashboard.InsightIDs)
+		}
+		viewSeries, err := insightStore.Get(ctx, InsightQueryArgs{UniqueID: dashboard.InsightIDs[0], UserID: []int{2}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return viewSeries
+	}
+
+	t.Run("without permission", func(t *testing.T) {
+		_, err := store.DuplicateDashboard(ctx, DuplicateDashboardArgs{ID: original.ID, UserID: []int{2}})
+		if err != ErrDashboardPermission {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("sharing series", func(t *testing.T) {
+		duplicated, err := store.DuplicateDashboard(ctx, DuplicateDashboardArgs{
+			ID:     original.ID,
+			Grants: []DashboardGrant{UserDashboardGrant(1), UserDashboardGrant(2)},
+			UserID: []int{1},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		autogold.Want("duplicated title", "original (copy)").Equal(t, duplicated.Title)
+
+		viewSeries := duplicatedSeries(t, duplicated)
+		if len(viewSeries) != 1 {
+			t.Fatalf("expected 1 series, have %d", len(viewSeries))
+		}
+		autogold.Want("shared series", []string{"series1", "label1", "blue", "github.com/a/a"}).Equal(t, []string{
+			viewSeries[0].SeriesID,
+			viewSeries[0].Label,
+			viewSeries[0].LineColor,
+			viewSeries[0].Repositories[0],
+		})
+	})
+
+	t.Run("re-scoping series", func(t *testing.T) {
+		duplicated, err := store.DuplicateDashboard(ctx, DuplicateDashboardArgs{
+			ID:     original.ID,
+			Title:  "payments",
+			Grants: []DashboardGrant{UserDashboardGrant(2)},
+			Scope:  &SeriesScope{RepositoryQuery: "repo:has.meta(team:payments)"},
+			UserID: []int{1},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The dashboard is only granted to user 2, so it is not returned to user 1.
+		if duplicated != nil {
+			t.Fatalf("expected duplicated dashboard to not be visible, have %v", duplicated)
+		}
+
+		dashboards, err := store.GetDashboards(ctx, DashboardQueryArgs{UserID: []int{2}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var rescoped *types.Dashboard
+		for _, dashboard := range dashboards {
+			if dashboard.Title == "payments" {
+				rescoped = dashboard
+			}
+		}
+		if rescoped == nil {
+			t.Fatal("expected duplicated dashboard to be visible to user 2")
+		}
+
+		viewSeries := duplicatedSeries(t, rescoped)
+		if len(viewSeries) != 1 {
+			t.Fatalf("expected 1 series, have %d", len(viewSeries))
+		}
+		if viewSeries[0].SeriesID == series.SeriesID {
+			t.Fatal("expected a new series")
+		}
+		autogold.Want("re-scoped series", []interface{}{"errors.New", "repo:has.meta(team:payments)", 0}).Equal(t, []interface{}{
+			viewSeries[0].Query,
+			viewSeries[0].RepositoryQuery,
+			len(viewSeries[0].Repositories),
+		})
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/lib/pq"
+	"github.com/segmentio/ksuid"
 
 	"github.com/sourcegraph/sourcegraph/internal/insights"
 
@@ -15,6 +16,7 @@ import (
 
 	"github.com/keegancsmith/sqlf"
 
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 )
@@ -224,6 +226,8 @@ type GetDataSeriesArgs struct {
 	BackfillIncomplete  bool
 	SeriesID            string
 	GlobalOnly          bool
+	// GenerationMethod, if set, filters for series generated with the given method.
+	GenerationMethod types.GenerationMethod
 }
 
 func (s *InsightStore) GetDataSeries(ctx context.Context, args GetDataSeriesArgs) ([]types.InsightSeries, error) {
@@ -250,6 +254,9 @@ func (s *InsightStore) GetDataSeries(ctx context.Context, args GetDataSeriesArgs
 	if args.GlobalOnly {
 		preds = append(preds, sqlf.Sprintf("repositories is null"))
 	}
+	if args.GenerationMethod != "" {
+		preds = append(preds, sqlf.Sprintf("generation_method = %s", args.GenerationMethod))
+	}
 
 	q := sqlf.Sprintf(getInsightDataSeriesSql, sqlf.Join(preds, "\n AND"))
 	return scanDataSeries(s.Query(ctx, q))
@@ -277,6 +284,15 @@ func scanDataSeries(rows *sql.Rows, queryErr error) (_ []types.InsightSeries, er
 			&temp.Enabled,
 			&temp.SampleIntervalUnit,
 			&temp.SampleIntervalValue,
+			&temp.BackfillEstimatedCost,
+			&temp.BackfillSpentCost,
+			&temp.BackfillCursorRepoID,
+			&temp.BackfillCursorFrame,
+			&temp.GeneratedFromCaptureGroups,
+			&temp.GenerationMethod,
+			&temp.TopRepositoriesLimit,
+			&temp.RepositoryQuery,
+			pq.Array(&temp.Repositories),
 		); err != nil {
 			return []types.InsightSeries{}, err
 		}
@@ -307,6 +323,12 @@ func scanInsightViewSeries(rows *sql.Rows, queryErr error) (_ []types.InsightVie
 			&temp.LastRecordedAt,
 			&temp.NextRecordingAfter,
 			&temp.BackfillQueuedAt,
+			&temp.BackfillEstimatedCost,
+			&temp.BackfillSpentCost,
+			&temp.GeneratedFromCaptureGroups,
+			&temp.GenerationMethod,
+			&temp.TopRepositoriesLimit,
+			&temp.RepositoryQuery,
 			&temp.LastSnapshotAt,
 			&temp.NextSnapshotAfter,
 			pq.Array(&temp.Repositories),
@@ -409,6 +431,80 @@ const deleteViewSql = `
 delete from insight_view where %s;
 `
 
+// duplicateView creates a copy of the insight view with the given unique ID, granted with the given
+// grants, and returns the unique ID of the copy. The copy shares the series of the original view,
+// unless a scope is given, in which case every series is re-created with that repository scope.
+// Views without any enabled series are not copied, and an empty unique ID is returned.
+func (s *InsightStore) duplicateView(ctx context.Context, uniqueID string, grants []InsightViewGrant, scope *SeriesScope) (string, error) {
+	views, err := s.GetMapped(ctx, InsightQueryArgs{UniqueID: uniqueID, WithoutAuthorization: true})
+	if err != nil {
+		return "", errors.Wrap(err, "GetMapped")
+	}
+	if len(views) == 0 {
+		return "", nil
+	}
+	view := views[0]
+
+	copied, err := s.CreateView(ctx, types.InsightView{
+		Title:       view.Title,
+		Description: view.Description,
+		UniqueID:    ksuid.New().String(),
+		Filters:     view.Filters,
+	}, grants)
+	if err != nil {
+		return "", errors.Wrap(err, "CreateView")
+	}
+
+	for _, viewSeries := range view.Series {
+		found, err := s.GetDataSeries(ctx, GetDataSeriesArgs{SeriesID: viewSeries.SeriesID})
+		if err != nil {
+			return "", errors.Wrap(err, "GetDataSeries")
+		}
+		if len(found) == 0 {
+			return "", errors.Newf("insight series not found: %s", viewSeries.SeriesID)
+		}
+		series := found[0]
+		if scope != nil {
+			rescoped, err := rescopeSeries(series, *scope)
+			if err != nil {
+				return "", err
+			}
+			if series, err = s.CreateSeries(ctx, rescoped); err != nil {
+				return "", errors.Wrap(err, "CreateSeries")
+			}
+		}
+		if err := s.AttachSeriesToView(ctx, series, copied, types.InsightViewSeriesMetadata{
+			Label:  viewSeries.Label,
+			Stroke: viewSeries.LineColor,
+		}); err != nil {
+			return "", errors.Wrap(err, "AttachSeriesToView")
+		}
+	}
+	return copied.UniqueID, nil
+}
+
+// rescopeSeries returns a new series with the same definition as the given one and the given
+// repository scope. The new series is recorded and backfilled from scratch.
+func rescopeSeries(series types.InsightSeries, scope SeriesScope) (types.InsightSeries, error) {
+	if series.GenerationMethod == types.GenerationMethodTopRepositories && len(scope.Repositories) > 0 {
+		return types.InsightSeries{}, errors.New("top repositories series cannot have a repository scope")
+	}
+	if series.GenerationMethod == types.GenerationMethodLanguageStats && len(scope.Repositories) == 0 && scope.RepositoryQuery == "" {
+		return types.InsightSeries{}, errors.New("language statistics series require a repository scope")
+	}
+	return types.InsightSeries{
+		SeriesID:                   ksuid.New().String(),
+		Query:                      series.Query,
+		Repositories:               scope.Repositories,
+		RepositoryQuery:            scope.RepositoryQuery,
+		SampleIntervalUnit:         series.SampleIntervalUnit,
+		SampleIntervalValue:        series.SampleIntervalValue,
+		GeneratedFromCaptureGroups: series.GeneratedFromCaptureGroups,
+		GenerationMethod:           series.GenerationMethod,
+		TopRepositoriesLimit:       series.TopRepositoriesLimit,
+	}, nil
+}
+
 // CreateSeries will create a new insight data series. This series must be uniquely identified by the series ID.
 func (s *InsightStore) CreateSeries(ctx context.Context, series types.InsightSeries) (types.InsightSeries, error) {
 	if series.CreatedAt.IsZero() {
@@ -420,6 +516,9 @@ func (s *InsightStore) CreateSeries(ctx context.Context, series types.InsightSer
 	if series.NextSnapshotAfter.IsZero() {
 		series.NextSnapshotAfter = s.Now()
 	}
+	if series.GenerationMethod == "" {
+		series.GenerationMethod = types.GenerationMethodSearch
+	}
 	if series.OldestHistoricalAt.IsZero() {
 		// TODO(insights): this value should probably somewhere more discoverable / obvious than here
 		series.OldestHistoricalAt = s.Now().Add(-time.Hour * 24 * 7 * 26)
@@ -436,6 +535,10 @@ func (s *InsightStore) CreateSeries(ctx context.Context, series types.InsightSer
 		pq.Array(series.Repositories),
 		series.SampleIntervalUnit,
 		series.SampleIntervalValue,
+		series.GeneratedFromCaptureGroups,
+		series.GenerationMethod,
+		series.TopRepositoriesLimit,
+		series.RepositoryQuery,
 	))
 	var id int
 	err := row.Scan(&id)
@@ -452,6 +555,9 @@ type DataSeriesStore interface {
 	StampRecording(ctx context.Context, series types.InsightSeries) (types.InsightSeries, error)
 	StampSnapshot(ctx context.Context, series types.InsightSeries) (types.InsightSeries, error)
 	StampBackfill(ctx context.Context, series types.InsightSeries) (types.InsightSeries, error)
+	SetBackfillEstimatedCost(ctx context.Context, series types.InsightSeries, cost int) (types.InsightSeries, error)
+	AddBackfillSpentCost(ctx context.Context, series types.InsightSeries, cost int) (types.InsightSeries, error)
+	SetBackfillCursor(ctx context.Context, series types.InsightSeries, repoID api.RepoID, frame int) (types.InsightSeries, error)
 	SetSeriesEnabled(ctx context.Context, seriesId string, enabled bool) error
 }
 
@@ -492,6 +598,41 @@ func (s *InsightStore) StampBackfill(ctx context.Context, series types.InsightSe
 		return types.InsightSeries{}, err
 	}
 	series.BackfillQueuedAt = current
+	series.BackfillCursorRepoID = 0
+	series.BackfillCursorFrame = 0
+	return series, nil
+}
+
+// SetBackfillEstimatedCost records the estimated number of historical search queries required to
+// backfill this series and returns the InsightSeries struct with updated values.
+func (s *InsightStore) SetBackfillEstimatedCost(ctx context.Context, series types.InsightSeries, cost int) (types.InsightSeries, error) {
+	if err := s.Exec(ctx, sqlf.Sprintf(setBackfillEstimatedCostSql, cost, series.ID)); err != nil {
+		return types.InsightSeries{}, err
+	}
+	series.BackfillEstimatedCost = &cost
+	return series, nil
+}
+
+// AddBackfillSpentCost adds the given number of enqueued historical search queries to the backfill
+// progress of this series and returns the InsightSeries struct with updated values.
+func (s *InsightStore) AddBackfillSpentCost(ctx context.Context, series types.InsightSeries, cost int) (types.InsightSeries, error) {
+	spent, _, err := basestore.ScanFirstInt(s.Query(ctx, sqlf.Sprintf(addBackfillSpentCostSql, cost, series.ID)))
+	if err != nil {
+		return types.InsightSeries{}, err
+	}
+	series.BackfillSpentCost = spent
+	return series, nil
+}
+
+// SetBackfillCursor records where the historical backfill of this series stopped: the first frame
+// frames of the given repository have been enqueued, as well as all frames of the repositories with
+// a lower ID. It returns the InsightSeries struct with updated values.
+func (s *InsightStore) SetBackfillCursor(ctx context.Context, series types.InsightSeries, repoID api.RepoID, frame int) (types.InsightSeries, error) {
+	if err := s.Exec(ctx, sqlf.Sprintf(setBackfillCursorSql, repoID, frame, series.ID)); err != nil {
+		return types.InsightSeries{}, err
+	}
+	series.BackfillCursorRepoID = repoID
+	series.BackfillCursorFrame = frame
 	return series, nil
 }
 
@@ -515,7 +656,29 @@ WHERE series_id = %s;
 const stampBackfillSql = `
 -- source: enterprise/internal/insights/store/insight_store.go:StampRecording
 UPDATE insight_series
-SET backfill_queued_at = %s
+SET backfill_queued_at = %s, backfill_cursor_repo_id = 0, backfill_cursor_frame = 0
+WHERE id = %s;
+`
+
+const setBackfillEstimatedCostSql = `
+-- source: enterprise/internal/insights/store/insight_store.go:SetBackfillEstimatedCost
+UPDATE insight_series
+SET backfill_estimated_cost = %s
+WHERE id = %s;
+`
+
+const addBackfillSpentCostSql = `
+-- source: enterprise/internal/insights/store/insight_store.go:AddBackfillSpentCost
+UPDATE insight_series
+SET backfill_spent_cost = backfill_spent_cost + %s
+WHERE id = %s
+RETURNING backfill_spent_cost;
+`
+
+const setBackfillCursorSql = `
+-- source: enterprise/internal/insights/store/insight_store.go:SetBackfillCursor
+UPDATE insight_series
+SET backfill_cursor_repo_id = %s, backfill_cursor_frame = %s
 WHERE id = %s;
 `
 
@@ -551,15 +714,15 @@ const createInsightSeriesSql = `
 -- source: enterprise/internal/insights/store/insight_store.go:CreateSeries
 INSERT INTO insight_series (series_id, query, created_at, oldest_historical_at, last_recorded_at,
                             next_recording_after, last_snapshot_at, next_snapshot_after, repositories,
-							sample_interval_unit, sample_interval_value)
-VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+							sample_interval_unit, sample_interval_value, generated_from_capture_groups, generation_method, top_repositories_limit, repository_query)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 RETURNING id;`
 
 const getInsightByViewSql = `
 -- source: enterprise/internal/insights/store/insight_store.go:Get
 SELECT iv.unique_id, iv.title, iv.description, ivs.label, ivs.stroke,
 i.series_id, i.query, i.created_at, i.oldest_historical_at, i.last_recorded_at,
-i.next_recording_after, i.backfill_queued_at, i.last_snapshot_at, i.next_snapshot_after, i.repositories,
+i.next_recording_after, i.backfill_queued_at, i.backfill_estimated_cost, i.backfill_spent_cost, i.generated_from_capture_groups, i.generation_method, i.top_repositories_limit, i.repository_query, i.last_snapshot_at, i.next_snapshot_after, i.repositories,
 i.sample_interval_unit, i.sample_interval_value, iv.default_filter_include_repo_regex, iv.default_filter_exclude_repo_regex
 FROM insight_view iv
          JOIN insight_view_series ivs ON iv.id = ivs.insight_view_id
@@ -573,6 +736,7 @@ const getInsightDataSeriesSql = `
 -- source: enterprise/internal/insights/store/insight_store.go:GetDataSeries
 select id, series_id, query, created_at, oldest_historical_at, last_recorded_at, next_recording_after,
 last_snapshot_at, next_snapshot_after, (CASE WHEN deleted_at IS NULL THEN TRUE ELSE FALSE END) AS enabled,
-sample_interval_unit, sample_interval_value from insight_series
+sample_interval_unit, sample_interval_value, backfill_estimated_cost, backfill_spent_cost, backfill_cursor_repo_id, backfill_cursor_frame, generated_from_capture_groups, generation_method, top_repositories_limit, repository_query, repositories from insight_series
 WHERE %s
+ORDER BY created_at, id
 `
@@ -79,6 +79,7 @@ func TestGet(t *testing.T) {
 				NextSnapshotAfter:   now,
 				SampleIntervalValue: 1,
 				SampleIntervalUnit:  sampleIntervalUnit,
+				GenerationMethod:    types.GenerationMethodSearch,
 				Label:               "label1",
 				LineColor:           "color1",
 			},
@@ -96,6 +97,7 @@ func TestGet(t *testing.T) {
 				NextSnapshotAfter:   now,
 				SampleIntervalValue: 1,
 				SampleIntervalUnit:  sampleIntervalUnit,
+				GenerationMethod:    types.GenerationMethodSearch,
 				Label:               "label2",
 				LineColor:           "color2",
 			},
@@ -113,6 +115,7 @@ func TestGet(t *testing.T) {
 				NextSnapshotAfter:   now,
 				SampleIntervalValue: 1,
 				SampleIntervalUnit:  sampleIntervalUnit,
+				GenerationMethod:    types.GenerationMethodSearch,
 				Label:               "second-label-2",
 				LineColor:           "second-color-2",
 			},
@@ -146,6 +149,7 @@ func TestGet(t *testing.T) {
 				NextSnapshotAfter:   now,
 				SampleIntervalValue: 1,
 				SampleIntervalUnit:  sampleIntervalUnit,
+				GenerationMethod:    types.GenerationMethodSearch,
 				Label:               "label1",
 				LineColor:           "color1",
 			},
@@ -163,6 +167,7 @@ func TestGet(t *testing.T) {
 				NextSnapshotAfter:   now,
 				SampleIntervalValue: 1,
 				SampleIntervalUnit:  sampleIntervalUnit,
+				GenerationMethod:    types.GenerationMethodSearch,
 				Label:               "label2",
 				LineColor:           "color2",
 			},
@@ -195,6 +200,7 @@ func TestGet(t *testing.T) {
 				NextSnapshotAfter:   now,
 				SampleIntervalValue: 1,
 				SampleIntervalUnit:  sampleIntervalUnit,
+				GenerationMethod:    types.GenerationMethodSearch,
 				Label:               "label1",
 				LineColor:           "color1",
 			},
@@ -212,6 +218,7 @@ func TestGet(t *testing.T) {
 				NextSnapshotAfter:   now,
 				SampleIntervalValue: 1,
 				SampleIntervalUnit:  sampleIntervalUnit,
+				GenerationMethod:    types.GenerationMethodSearch,
 				Label:               "label2",
 				LineColor:           "color2",
 			},
@@ -265,6 +272,7 @@ func TestCreateSeries(t *testing.T) {
 			CreatedAt:          now,
 			Enabled:            true,
 			SampleIntervalUnit: string(types.Month),
+			GenerationMethod:   types.GenerationMethodSearch,
 		}
 
 		log15.Info("values", "want", want, "got", got)
@@ -568,6 +576,7 @@ func TestAttachSeriesView(t *testing.T) {
 			NextSnapshotAfter:   now,
 			SampleIntervalValue: 1,
 			SampleIntervalUnit:  sampleIntervalUnit,
+			GenerationMethod:    types.GenerationMethodSearch,
 			Label:               "my label",
 			LineColor:           "my stroke",
 		}}
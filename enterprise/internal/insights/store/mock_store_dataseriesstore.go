@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	types "github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	api "github.com/sourcegraph/sourcegraph/internal/api"
 )
 
 // MockDataSeriesStore is a mock implementation of the DataSeriesStore
@@ -14,9 +15,18 @@ import (
 // github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store)
 // used for unit testing.
 type MockDataSeriesStore struct {
+	// AddBackfillSpentCostFunc is an instance of a mock function object
+	// controlling the behavior of the method AddBackfillSpentCost.
+	AddBackfillSpentCostFunc *DataSeriesStoreAddBackfillSpentCostFunc
 	// GetDataSeriesFunc is an instance of a mock function object
 	// controlling the behavior of the method GetDataSeries.
 	GetDataSeriesFunc *DataSeriesStoreGetDataSeriesFunc
+	// SetBackfillCursorFunc is an instance of a mock function object
+	// controlling the behavior of the method SetBackfillCursor.
+	SetBackfillCursorFunc *DataSeriesStoreSetBackfillCursorFunc
+	// SetBackfillEstimatedCostFunc is an instance of a mock function object
+	// controlling the behavior of the method SetBackfillEstimatedCost.
+	SetBackfillEstimatedCostFunc *DataSeriesStoreSetBackfillEstimatedCostFunc
 	// SetSeriesEnabledFunc is an instance of a mock function object
 	// controlling the behavior of the method SetSeriesEnabled.
 	SetSeriesEnabledFunc *DataSeriesStoreSetSeriesEnabledFunc
@@ -36,11 +46,26 @@ type MockDataSeriesStore struct {
 // overwritten.
 func NewMockDataSeriesStore() *MockDataSeriesStore {
 	return &MockDataSeriesStore{
+		AddBackfillSpentCostFunc: &DataSeriesStoreAddBackfillSpentCostFunc{
+			defaultHook: func(context.Context, types.InsightSeries, int) (types.InsightSeries, error) {
+				return types.InsightSeries{}, nil
+			},
+		},
 		GetDataSeriesFunc: &DataSeriesStoreGetDataSeriesFunc{
 			defaultHook: func(context.Context, GetDataSeriesArgs) ([]types.InsightSeries, error) {
 				return nil, nil
 			},
 		},
+		SetBackfillCursorFunc: &DataSeriesStoreSetBackfillCursorFunc{
+			defaultHook: func(context.Context, types.InsightSeries, api.RepoID, int) (types.InsightSeries, error) {
+				return types.InsightSeries{}, nil
+			},
+		},
+		SetBackfillEstimatedCostFunc: &DataSeriesStoreSetBackfillEstimatedCostFunc{
+			defaultHook: func(context.Context, types.InsightSeries, int) (types.InsightSeries, error) {
+				return types.InsightSeries{}, nil
+			},
+		},
 		SetSeriesEnabledFunc: &DataSeriesStoreSetSeriesEnabledFunc{
 			defaultHook: func(context.Context, string, bool) error {
 				return nil
@@ -69,9 +94,18 @@ func NewMockDataSeriesStore() *MockDataSeriesStore {
 // overwritten.
 func NewMockDataSeriesStoreFrom(i DataSeriesStore) *MockDataSeriesStore {
 	return &MockDataSeriesStore{
+		AddBackfillSpentCostFunc: &DataSeriesStoreAddBackfillSpentCostFunc{
+			defaultHook: i.AddBackfillSpentCost,
+		},
 		GetDataSeriesFunc: &DataSeriesStoreGetDataSeriesFunc{
 			defaultHook: i.GetDataSeries,
 		},
+		SetBackfillCursorFunc: &DataSeriesStoreSetBackfillCursorFunc{
+			defaultHook: i.SetBackfillCursor,
+		},
+		SetBackfillEstimatedCostFunc: &DataSeriesStoreSetBackfillEstimatedCostFunc{
+			defaultHook: i.SetBackfillEstimatedCost,
+		},
 		SetSeriesEnabledFunc: &DataSeriesStoreSetSeriesEnabledFunc{
 			defaultHook: i.SetSeriesEnabled,
 		},
@@ -87,6 +121,116 @@ func NewMockDataSeriesStoreFrom(i DataSeriesStore) *MockDataSeriesStore {
 	}
 }
 
+// DataSeriesStoreAddBackfillSpentCostFunc describes the behavior when the
+// AddBackfillSpentCost method of the parent MockDataSeriesStore instance is
+// invoked.
+type DataSeriesStoreAddBackfillSpentCostFunc struct {
+	defaultHook func(context.Context, types.InsightSeries, int) (types.InsightSeries, error)
+	hooks       []func(context.Context, types.InsightSeries, int) (types.InsightSeries, error)
+	history     []DataSeriesStoreAddBackfillSpentCostFuncCall
+	mutex       sync.Mutex
+}
+
+// AddBackfillSpentCost delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockDataSeriesStore) AddBackfillSpentCost(v0 context.Context, v1 types.InsightSeries, v2 int) (types.InsightSeries, error) {
+	r0, r1 := m.AddBackfillSpentCostFunc.nextHook()(v0, v1, v2)
+	m.AddBackfillSpentCostFunc.appendCall(DataSeriesStoreAddBackfillSpentCostFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the AddBackfillSpentCost
+// method of the parent MockDataSeriesStore instance is invoked and the hook
+// queue is empty.
+func (f *DataSeriesStoreAddBackfillSpentCostFunc) SetDefaultHook(hook func(context.Context, types.InsightSeries, int) (types.InsightSeries, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// AddBackfillSpentCost method of the parent MockDataSeriesStore instance
+// invokes the hook at the front of the queue and discards it. After the queue
+// is empty, the default hook function is invoked for any future action.
+func (f *DataSeriesStoreAddBackfillSpentCostFunc) PushHook(hook func(context.Context, types.InsightSeries, int) (types.InsightSeries, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DataSeriesStoreAddBackfillSpentCostFunc) SetDefaultReturn(r0 types.InsightSeries, r1 error) {
+	f.SetDefaultHook(func(context.Context, types.InsightSeries, int) (types.InsightSeries, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DataSeriesStoreAddBackfillSpentCostFunc) PushReturn(r0 types.InsightSeries, r1 error) {
+	f.PushHook(func(context.Context, types.InsightSeries, int) (types.InsightSeries, error) {
+		return r0, r1
+	})
+}
+
+func (f *DataSeriesStoreAddBackfillSpentCostFunc) nextHook() func(context.Context, types.InsightSeries, int) (types.InsightSeries, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DataSeriesStoreAddBackfillSpentCostFunc) appendCall(r0 DataSeriesStoreAddBackfillSpentCostFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DataSeriesStoreAddBackfillSpentCostFuncCall
+// objects describing the invocations of this function.
+func (f *DataSeriesStoreAddBackfillSpentCostFunc) History() []DataSeriesStoreAddBackfillSpentCostFuncCall {
+	f.mutex.Lock()
+	history := make([]DataSeriesStoreAddBackfillSpentCostFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DataSeriesStoreAddBackfillSpentCostFuncCall is an object that describes an
+// invocation of method AddBackfillSpentCost on an instance of
+// MockDataSeriesStore.
+type DataSeriesStoreAddBackfillSpentCostFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method invocation.
+	Arg1 types.InsightSeries
+	// Arg2 is the value of the 3rd argument passed to this method invocation.
+	Arg2 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 types.InsightSeries
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this invocation.
+func (c DataSeriesStoreAddBackfillSpentCostFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DataSeriesStoreAddBackfillSpentCostFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // DataSeriesStoreGetDataSeriesFunc describes the behavior when the
 // GetDataSeries method of the parent MockDataSeriesStore instance is
 // invoked.
@@ -197,6 +341,229 @@ func (c DataSeriesStoreGetDataSeriesFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// DataSeriesStoreSetBackfillCursorFunc describes the behavior when the
+// SetBackfillCursor method of the parent MockDataSeriesStore instance is
+// invoked.
+type DataSeriesStoreSetBackfillCursorFunc struct {
+	defaultHook func(context.Context, types.InsightSeries, api.RepoID, int) (types.InsightSeries, error)
+	hooks       []func(context.Context, types.InsightSeries, api.RepoID, int) (types.InsightSeries, error)
+	history     []DataSeriesStoreSetBackfillCursorFuncCall
+	mutex       sync.Mutex
+}
+
+// SetBackfillCursor delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockDataSeriesStore) SetBackfillCursor(v0 context.Context, v1 types.InsightSeries, v2 api.RepoID, v3 int) (types.InsightSeries, error) {
+	r0, r1 := m.SetBackfillCursorFunc.nextHook()(v0, v1, v2, v3)
+	m.SetBackfillCursorFunc.appendCall(DataSeriesStoreSetBackfillCursorFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the SetBackfillCursor
+// method of the parent MockDataSeriesStore instance is invoked and the hook
+// queue is empty.
+func (f *DataSeriesStoreSetBackfillCursorFunc) SetDefaultHook(hook func(context.Context, types.InsightSeries, api.RepoID, int) (types.InsightSeries, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SetBackfillCursor method of the parent MockDataSeriesStore instance
+// invokes the hook at the front of the queue and discards it. After the queue
+// is empty, the default hook function is invoked for any future action.
+func (f *DataSeriesStoreSetBackfillCursorFunc) PushHook(hook func(context.Context, types.InsightSeries, api.RepoID, int) (types.InsightSeries, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DataSeriesStoreSetBackfillCursorFunc) SetDefaultReturn(r0 types.InsightSeries, r1 error) {
+	f.SetDefaultHook(func(context.Context, types.InsightSeries, api.RepoID, int) (types.InsightSeries, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DataSeriesStoreSetBackfillCursorFunc) PushReturn(r0 types.InsightSeries, r1 error) {
+	f.PushHook(func(context.Context, types.InsightSeries, api.RepoID, int) (types.InsightSeries, error) {
+		return r0, r1
+	})
+}
+
+func (f *DataSeriesStoreSetBackfillCursorFunc) nextHook() func(context.Context, types.InsightSeries, api.RepoID, int) (types.InsightSeries, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DataSeriesStoreSetBackfillCursorFunc) appendCall(r0 DataSeriesStoreSetBackfillCursorFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DataSeriesStoreSetBackfillCursorFuncCall
+// objects describing the invocations of this function.
+func (f *DataSeriesStoreSetBackfillCursorFunc) History() []DataSeriesStoreSetBackfillCursorFuncCall {
+	f.mutex.Lock()
+	history := make([]DataSeriesStoreSetBackfillCursorFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DataSeriesStoreSetBackfillCursorFuncCall is an object that describes an
+// invocation of method SetBackfillCursor on an instance of
+// MockDataSeriesStore.
+type DataSeriesStoreSetBackfillCursorFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method invocation.
+	Arg1 types.InsightSeries
+	// Arg2 is the value of the 3rd argument passed to this method invocation.
+	Arg2 api.RepoID
+	// Arg3 is the value of the 4th argument passed to this method invocation.
+	Arg3 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 types.InsightSeries
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this invocation.
+func (c DataSeriesStoreSetBackfillCursorFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DataSeriesStoreSetBackfillCursorFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// DataSeriesStoreSetBackfillEstimatedCostFunc describes the behavior when the
+// SetBackfillEstimatedCost method of the parent MockDataSeriesStore instance
+// is invoked.
+type DataSeriesStoreSetBackfillEstimatedCostFunc struct {
+	defaultHook func(context.Context, types.InsightSeries, int) (types.InsightSeries, error)
+	hooks       []func(context.Context, types.InsightSeries, int) (types.InsightSeries, error)
+	history     []DataSeriesStoreSetBackfillEstimatedCostFuncCall
+	mutex       sync.Mutex
+}
+
+// SetBackfillEstimatedCost delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockDataSeriesStore) SetBackfillEstimatedCost(v0 context.Context, v1 types.InsightSeries, v2 int) (types.InsightSeries, error) {
+	r0, r1 := m.SetBackfillEstimatedCostFunc.nextHook()(v0, v1, v2)
+	m.SetBackfillEstimatedCostFunc.appendCall(DataSeriesStoreSetBackfillEstimatedCostFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// SetBackfillEstimatedCost method of the parent MockDataSeriesStore instance
+// is invoked and the hook queue is empty.
+func (f *DataSeriesStoreSetBackfillEstimatedCostFunc) SetDefaultHook(hook func(context.Context, types.InsightSeries, int) (types.InsightSeries, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SetBackfillEstimatedCost method of the parent MockDataSeriesStore instance
+// invokes the hook at the front of the queue and discards it. After the queue
+// is empty, the default hook function is invoked for any future action.
+func (f *DataSeriesStoreSetBackfillEstimatedCostFunc) PushHook(hook func(context.Context, types.InsightSeries, int) (types.InsightSeries, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DataSeriesStoreSetBackfillEstimatedCostFunc) SetDefaultReturn(r0 types.InsightSeries, r1 error) {
+	f.SetDefaultHook(func(context.Context, types.InsightSeries, int) (types.InsightSeries, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DataSeriesStoreSetBackfillEstimatedCostFunc) PushReturn(r0 types.InsightSeries, r1 error) {
+	f.PushHook(func(context.Context, types.InsightSeries, int) (types.InsightSeries, error) {
+		return r0, r1
+	})
+}
+
+func (f *DataSeriesStoreSetBackfillEstimatedCostFunc) nextHook() func(context.Context, types.InsightSeries, int) (types.InsightSeries, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DataSeriesStoreSetBackfillEstimatedCostFunc) appendCall(r0 DataSeriesStoreSetBackfillEstimatedCostFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// DataSeriesStoreSetBackfillEstimatedCostFuncCall objects describing the
+// invocations of this function.
+func (f *DataSeriesStoreSetBackfillEstimatedCostFunc) History() []DataSeriesStoreSetBackfillEstimatedCostFuncCall {
+	f.mutex.Lock()
+	history := make([]DataSeriesStoreSetBackfillEstimatedCostFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DataSeriesStoreSetBackfillEstimatedCostFuncCall is an object that describes
+// an invocation of method SetBackfillEstimatedCost on an instance of
+// MockDataSeriesStore.
+type DataSeriesStoreSetBackfillEstimatedCostFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method invocation.
+	Arg1 types.InsightSeries
+	// Arg2 is the value of the 3rd argument passed to this method invocation.
+	Arg2 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 types.InsightSeries
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this invocation.
+func (c DataSeriesStoreSetBackfillEstimatedCostFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DataSeriesStoreSetBackfillEstimatedCostFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // DataSeriesStoreSetSeriesEnabledFunc describes the behavior when the
 // SetSeriesEnabled method of the parent MockDataSeriesStore instance is
 // invoked.
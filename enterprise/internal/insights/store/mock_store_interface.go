@@ -12,6 +12,9 @@ import (
 // github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store)
 // used for unit testing.
 type MockInterface struct {
+	// CaptureValuesFunc is an instance of a mock function object controlling the
+	// behavior of the method CaptureValues.
+	CaptureValuesFunc *InterfaceCaptureValuesFunc
 	// CountDataFunc is an instance of a mock function object controlling
 	// the behavior of the method CountData.
 	CountDataFunc *InterfaceCountDataFunc
@@ -30,6 +33,11 @@ type MockInterface struct {
 // methods return zero values for all results, unless overwritten.
 func NewMockInterface() *MockInterface {
 	return &MockInterface{
+		CaptureValuesFunc: &InterfaceCaptureValuesFunc{
+			defaultHook: func(context.Context, string) ([]string, error) {
+				return nil, nil
+			},
+		},
 		CountDataFunc: &InterfaceCountDataFunc{
 			defaultHook: func(context.Context, CountDataOpts) (int, error) {
 				return 0, nil
@@ -57,6 +65,9 @@ func NewMockInterface() *MockInterface {
 // All methods delegate to the given implementation, unless overwritten.
 func NewMockInterfaceFrom(i Interface) *MockInterface {
 	return &MockInterface{
+		CaptureValuesFunc: &InterfaceCaptureValuesFunc{
+			defaultHook: i.CaptureValues,
+		},
 		CountDataFunc: &InterfaceCountDataFunc{
 			defaultHook: i.CountData,
 		},
@@ -72,6 +83,111 @@ func NewMockInterfaceFrom(i Interface) *MockInterface {
 	}
 }
 
+// InterfaceCaptureValuesFunc describes the behavior when the CaptureValues
+// method of the parent MockInterface instance is invoked.
+type InterfaceCaptureValuesFunc struct {
+	defaultHook func(context.Context, string) ([]string, error)
+	hooks       []func(context.Context, string) ([]string, error)
+	history     []InterfaceCaptureValuesFuncCall
+	mutex       sync.Mutex
+}
+
+// CaptureValues delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockInterface) CaptureValues(v0 context.Context, v1 string) ([]string, error) {
+	r0, r1 := m.CaptureValuesFunc.nextHook()(v0, v1)
+	m.CaptureValuesFunc.appendCall(InterfaceCaptureValuesFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the CaptureValues method of
+// the parent MockInterface instance is invoked and the hook queue is empty.
+func (f *InterfaceCaptureValuesFunc) SetDefaultHook(hook func(context.Context, string) ([]string, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// CaptureValues method of the parent MockInterface instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *InterfaceCaptureValuesFunc) PushHook(hook func(context.Context, string) ([]string, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *InterfaceCaptureValuesFunc) SetDefaultReturn(r0 []string, r1 error) {
+	f.SetDefaultHook(func(context.Context, string) ([]string, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *InterfaceCaptureValuesFunc) PushReturn(r0 []string, r1 error) {
+	f.PushHook(func(context.Context, string) ([]string, error) {
+		return r0, r1
+	})
+}
+
+func (f *InterfaceCaptureValuesFunc) nextHook() func(context.Context, string) ([]string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *InterfaceCaptureValuesFunc) appendCall(r0 InterfaceCaptureValuesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of InterfaceCaptureValuesFuncCall objects
+// describing the invocations of this function.
+func (f *InterfaceCaptureValuesFunc) History() []InterfaceCaptureValuesFuncCall {
+	f.mutex.Lock()
+	history := make([]InterfaceCaptureValuesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// InterfaceCaptureValuesFuncCall is an object that describes an invocation of
+// method CaptureValues on an instance of MockInterface.
+type InterfaceCaptureValuesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method invocation.
+	Arg1 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []string
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this invocation.
+func (c InterfaceCaptureValuesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c InterfaceCaptureValuesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // InterfaceCountDataFunc describes the behavior when the CountData method
 // of the parent MockInterface instance is invoked.
 type InterfaceCountDataFunc struct {
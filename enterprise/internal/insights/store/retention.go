@@ -0,0 +1,78 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+)
+
+const (
+	// dailyBucket is the period of time aggregated by a point of series_points_daily.
+	dailyBucket = 24 * time.Hour
+	// weeklyBucket is the period of time aggregated by a point of series_points_weekly.
+	weeklyBucket = 7 * 24 * time.Hour
+)
+
+// DownsampleOpts describes which series points are downsampled by Downsample.
+type DownsampleOpts struct {
+	// RawBefore, if non-zero, downsamples the points recorded before this time into daily
+	// aggregates. It should be the start of a day (UTC).
+	RawBefore time.Time
+
+	// DailyBefore, if non-zero, downsamples the daily aggregates before this time into weekly
+	// aggregates. It should be the start of a week (Monday, UTC).
+	DailyBefore time.Time
+}
+
+// Downsample rolls up old series points into aggregate tables to bound the size of the insights
+// DB. Every aggregate holds the maximum value recorded for a series, repository and capture group
+// value within its day or week, the same way SeriesPoints deduplicates points recorded at the
+// same time.
+func (s *Store) Downsample(ctx context.Context, opts DownsampleOpts) (err error) {
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	if !opts.RawBefore.IsZero() {
+		if err := tx.Exec(ctx, downsampleQuery("series_points", "series_points_daily", "day", opts.RawBefore)); err != nil {
+			return errors.Wrap(err, "downsampling to daily points")
+		}
+	}
+	if !opts.DailyBefore.IsZero() {
+		if err := tx.Exec(ctx, downsampleQuery("series_points_daily", "series_points_weekly", "week", opts.DailyBefore)); err != nil {
+			return errors.Wrap(err, "downsampling to weekly points")
+		}
+	}
+	return nil
+}
+
+func downsampleQuery(from, to, unit string, before time.Time) *sqlf.Query {
+	// The table names are constants, so they can be formatted into the query directly.
+	return sqlf.Sprintf(downsampleFmtstr, sqlf.Sprintf(from), before.UTC(), sqlf.Sprintf(to), unit)
+}
+
+// downsampleFmtstr moves the points of a table recorded before a given time into an aggregate
+// table, truncating their time to the start of their day or week in UTC.
+const downsampleFmtstr = `
+-- source: enterprise/internal/insights/store/retention.go:Downsample
+WITH moved AS (
+	DELETE FROM %s AS src WHERE src.time < %s
+	RETURNING src.*
+)
+INSERT INTO %s (series_id, time, value, metadata_id, repo_id, repo_name_id, original_repo_name_id, capture)
+SELECT
+	series_id,
+	date_trunc(%s, time AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS bucket,
+	MAX(value),
+	metadata_id,
+	repo_id,
+	repo_name_id,
+	original_repo_name_id,
+	capture
+FROM moved
+GROUP BY series_id, bucket, metadata_id, repo_id, repo_name_id, original_repo_name_id, capture
+`
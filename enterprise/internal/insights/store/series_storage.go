@@ -0,0 +1,153 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/hashicorp/go-multierror"
+	"github.com/inconshreveable/log15"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// StorageBackend names the database technology used to store series points.
+type StorageBackend string
+
+const (
+	// StorageBackendTimescaleDB stores series points in a TimescaleDB hypertable. This is the
+	// default.
+	StorageBackendTimescaleDB StorageBackend = "timescaledb"
+
+	// StorageBackendPostgres stores series points in a regular Postgres table that is
+	// partitioned by month, so no database extension is required.
+	StorageBackendPostgres StorageBackend = "postgres"
+)
+
+// SeriesStorage abstracts over the storage backend of the code insights database.
+type SeriesStorage interface {
+	// Backend returns the storage backend that is used.
+	Backend() StorageBackend
+
+	// Migrations returns the migrations of the code insights database for the backend.
+	Migrations() *dbconn.Database
+
+	// Maintain is called periodically to perform housekeeping, e.g. managing partitions.
+	Maintain(ctx context.Context, db dbutil.DB, now time.Time) error
+}
+
+// NewSeriesStorage returns the SeriesStorage for the given backend.
+func NewSeriesStorage(backend StorageBackend) (SeriesStorage, error) {
+	switch backend {
+	case StorageBackendTimescaleDB, "":
+		return timescaleSeriesStorage{}, nil
+	case StorageBackendPostgres:
+		return postgresSeriesStorage{}, nil
+	default:
+		return nil, errors.Errorf("unknown code insights storage backend %q", backend)
+	}
+}
+
+type timescaleSeriesStorage struct{}
+
+func (timescaleSeriesStorage) Backend() StorageBackend { return StorageBackendTimescaleDB }
+
+func (timescaleSeriesStorage) Migrations() *dbconn.Database { return dbconn.CodeInsights }
+
+func (timescaleSeriesStorage) Maintain(ctx context.Context, db dbutil.DB, now time.Time) error {
+	// TimescaleDB manages the chunks of the hypertable itself.
+	return nil
+}
+
+// seriesPointsPartitionsAhead is the number of monthly partitions that are created in
+// advance of the current month.
+const seriesPointsPartitionsAhead = 3
+
+type postgresSeriesStorage struct{}
+
+func (postgresSeriesStorage) Backend() StorageBackend { return StorageBackendPostgres }
+
+// Migrations returns the code insights migrations in which the migrations that use TimescaleDB
+// are replaced by plain Postgres variants.
+func (postgresSeriesStorage) Migrations() *dbconn.Database { return dbconn.CodeInsightsPostgres }
+
+// Maintain creates the monthly partitions of the series_points table for the current month
+// and the months ahead. Points outside of the created partitions, e.g. those recorded by
+// historical backfilling, are stored in the default partition. A partition that fails to be
+// created is logged and retried on the next run, without preventing the creation of the others.
+func (postgresSeriesStorage) Maintain(ctx context.Context, db dbutil.DB, now time.Time) error {
+	store := basestore.NewWithDB(db, sql.TxOptions{})
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	var errs error
+	for i := 0; i <= seriesPointsPartitionsAhead; i++ {
+		from := month.AddDate(0, i, 0)
+		if err := createSeriesPointsPartition(ctx, store, from, from.AddDate(0, 1, 0)); err != nil {
+			err = errors.Wrapf(err, "creating series_points partition for %s", from.Format("2006-01"))
+			log15.Error("Failed to create code insights series points partition", "error", err)
+			errs = multierror.Append(errs, err)
+		}
+	}
+	return errs
+}
+
+// createSeriesPointsPartition creates the partition of series_points for the points from
+// (inclusive) to (exclusive), unless it exists. A partition cannot be created while the default
+// partition has points in its range, so the default partition is detached while its points in the
+// range are moved to the new partition.
+func createSeriesPointsPartition(ctx context.Context, store *basestore.Store, from, to time.Time) (err error) {
+	name := seriesPointsPartitionName(from)
+	exists, _, err := basestore.ScanFirstBool(store.Query(ctx, sqlf.Sprintf(seriesPointsPartitionExistsQuery, name)))
+	if err != nil || exists {
+		return err
+	}
+
+	tx, err := store.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	for _, q := range []*sqlf.Query{
+		sqlf.Sprintf("ALTER TABLE series_points DETACH PARTITION series_points_default"),
+		createSeriesPointsPartitionQuery(from, to),
+		sqlf.Sprintf(moveSeriesPointsFromDefaultPartitionQuery, from, to),
+		sqlf.Sprintf("ALTER TABLE series_points ATTACH PARTITION series_points_default DEFAULT"),
+	} {
+		if err := tx.Exec(ctx, q); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const seriesPointsPartitionExistsQuery = `
+-- source: enterprise/internal/insights/store/series_storage.go:createSeriesPointsPartition
+SELECT to_regclass(%s) IS NOT NULL
+`
+
+const moveSeriesPointsFromDefaultPartitionQuery = `
+-- source: enterprise/internal/insights/store/series_storage.go:createSeriesPointsPartition
+WITH moved AS (
+	DELETE FROM series_points_default WHERE time >= %s AND time < %s RETURNING *
+)
+INSERT INTO series_points SELECT * FROM moved
+`
+
+func seriesPointsPartitionName(from time.Time) string {
+	return fmt.Sprintf("series_points_y%04dm%02d", from.Year(), from.Month())
+}
+
+func createSeriesPointsPartitionQuery(from, to time.Time) *sqlf.Query {
+	// DDL statements cannot take query arguments, but the name and the bounds are derived
+	// from the timestamps only.
+	return sqlf.Sprintf(fmt.Sprintf(
+		"CREATE TABLE %s PARTITION OF series_points FOR VALUES FROM ('%s') TO ('%s')",
+		seriesPointsPartitionName(from), from.Format(time.RFC3339), to.Format(time.RFC3339),
+	))
+}
@@ -0,0 +1,41 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hexops/autogold"
+	"github.com/keegancsmith/sqlf"
+)
+
+func TestNewSeriesStorage(t *testing.T) {
+	for _, tc := range []struct {
+		backend StorageBackend
+		want    StorageBackend
+	}{
+		{backend: "", want: StorageBackendTimescaleDB},
+		{backend: StorageBackendTimescaleDB, want: StorageBackendTimescaleDB},
+		{backend: StorageBackendPostgres, want: StorageBackendPostgres},
+	} {
+		storage, err := NewSeriesStorage(tc.backend)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have := storage.Backend(); have != tc.want {
+			t.Errorf("wrong backend for %q. want=%q have=%q", tc.backend, tc.want, have)
+		}
+	}
+
+	if _, err := NewSeriesStorage("influxdb"); err == nil {
+		t.Fatal("expected error for unknown backend")
+	}
+}
+
+func TestCreateSeriesPointsPartitionQuery(t *testing.T) {
+	from := time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC)
+	q := createSeriesPointsPartitionQuery(from, from.AddDate(0, 1, 0))
+	autogold.Want("partition query", "CREATE TABLE series_points_y2021m12 PARTITION OF series_points FOR VALUES FROM ('2021-12-01T00:00:00Z') TO ('2022-01-01T00:00:00Z')").Equal(t, q.Query(sqlf.PostgresBindVar))
+	if args := q.Args(); len(args) != 0 {
+		t.Fatalf("expected no query arguments, have %v", args)
+	}
+}
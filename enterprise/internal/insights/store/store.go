@@ -12,6 +12,7 @@ import (
 
 	"github.com/cockroachdb/errors"
 	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
@@ -23,6 +24,7 @@ import (
 // for actual API usage.
 type Interface interface {
 	SeriesPoints(ctx context.Context, opts SeriesPointsOpts) ([]SeriesPoint, error)
+	CaptureValues(ctx context.Context, seriesID string) ([]string, error)
 	RecordSeriesPoint(ctx context.Context, v RecordSeriesPointArgs) error
 	RecordSeriesPoints(ctx context.Context, pts []RecordSeriesPointArgs) error
 	CountData(ctx context.Context, opts CountDataOpts) (int, error)
@@ -87,9 +89,16 @@ type SeriesPoint struct {
 	Time     time.Time
 	Value    float64
 	Metadata []byte
+
+	// Capture is the value of the regexp capture group this point was recorded for, if the series
+	// is generated from capture groups.
+	Capture *string
 }
 
 func (s *SeriesPoint) String() string {
+	if s.Capture != nil {
+		return fmt.Sprintf("SeriesPoint{Time: %q, Value: %v, Metadata: %s, Capture: %q}", s.Time, s.Value, s.Metadata, *s.Capture)
+	}
 	return fmt.Sprintf("SeriesPoint{Time: %q, Value: %v, Metadata: %s}", s.Time, s.Value, s.Metadata)
 }
 
@@ -101,6 +110,10 @@ type SeriesPointsOpts struct {
 	// RepoID, if non-nil, indicates to filter results to only points recorded with this repo ID.
 	RepoID *api.RepoID
 
+	// Capture, if non-nil, indicates to filter results to only points recorded for this capture
+	// group value.
+	Capture *string
+
 	Excluded []api.RepoID
 	Included []api.RepoID
 
@@ -146,6 +159,7 @@ func (s *Store) SeriesPoints(ctx context.Context, opts SeriesPointsOpts) ([]Seri
 			&point.Time,
 			&point.Value,
 			&point.Metadata,
+			&point.Capture,
 		)
 		if err != nil {
 			return err
@@ -162,18 +176,22 @@ func (s *Store) SeriesPoints(ctx context.Context, opts SeriesPointsOpts) ([]Seri
 // and then SUM the result for each repository, giving us our final total number.
 const fullVectorSeriesAggregation = `
 -- source: enterprise/internal/insights/store/store.go:SeriesPoints
-SELECT sub.series_id, sub.interval_time, SUM(sub.value) as value, sub.metadata FROM (
-	SELECT sp.repo_name_id, sp.series_id, sp.time AS interval_time, MAX(value) as value, null as metadata
+SELECT sub.series_id, sub.interval_time, SUM(sub.value) as value, sub.metadata, sub.capture FROM (
+	SELECT sp.repo_name_id, sp.series_id, sp.time AS interval_time, MAX(value) as value, null as metadata, sp.capture
 	FROM (  select * from series_points
 			union
 			select * from series_points_snapshots
+			union
+			select * from series_points_daily
+			union
+			select * from series_points_weekly
 	) AS sp
 	JOIN repo_names rn ON sp.repo_name_id = rn.id
 	WHERE %s
-	GROUP BY sp.series_id, interval_time, sp.repo_name_id
+	GROUP BY sp.series_id, interval_time, sp.repo_name_id, sp.capture
 	ORDER BY sp.series_id, interval_time, sp.repo_name_id DESC
 ) sub
-GROUP BY sub.series_id, sub.interval_time, sub.metadata
+GROUP BY sub.series_id, sub.interval_time, sub.metadata, sub.capture
 ORDER BY sub.series_id, sub.interval_time DESC
 `
 
@@ -196,6 +214,9 @@ func seriesPointsQuery(opts SeriesPointsOpts) *sqlf.Query {
 	if opts.RepoID != nil {
 		preds = append(preds, sqlf.Sprintf("repo_id = %d", int32(*opts.RepoID)))
 	}
+	if opts.Capture != nil {
+		preds = append(preds, sqlf.Sprintf("capture = %s", *opts.Capture))
+	}
 	if opts.From != nil {
 		preds = append(preds, sqlf.Sprintf("time >= %s", *opts.From))
 	}
@@ -230,6 +251,41 @@ func seriesPointsQuery(opts SeriesPointsOpts) *sqlf.Query {
 	)
 }
 
+// CaptureValues returns the distinct capture group values recorded for the given series, in
+// alphabetical order.
+func (s *Store) CaptureValues(ctx context.Context, seriesID string) ([]string, error) {
+	// 🚨 SECURITY: Capture group values are extracted from file contents, so values recorded only
+	// for repositories the current user cannot see must not be returned. 🚨
+	denylist, err := s.permStore.GetUnauthorizedRepoIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	preds := []*sqlf.Query{
+		sqlf.Sprintf("series_id = %s", seriesID),
+		sqlf.Sprintf("capture IS NOT NULL"),
+	}
+	if len(denylist) > 0 {
+		preds = append(preds, sqlf.Sprintf(fmt.Sprintf("repo_id != all(%v)", values(denylist))))
+	}
+	return basestore.ScanStrings(s.Store.Query(ctx, sqlf.Sprintf(captureValuesFmtstr, sqlf.Join(preds, "\n AND "))))
+}
+
+const captureValuesFmtstr = `
+-- source: enterprise/internal/insights/store/store.go:CaptureValues
+SELECT DISTINCT capture FROM (
+	select series_id, repo_id, capture from series_points
+	union
+	select series_id, repo_id, capture from series_points_snapshots
+	union
+	select series_id, repo_id, capture from series_points_daily
+	union
+	select series_id, repo_id, capture from series_points_weekly
+) AS sp
+WHERE %s
+ORDER BY capture
+`
+
 //values constructs a SQL values statement out of an array of repository ids
 func values(ids []api.RepoID) string {
 	if len(ids) == 0 {
@@ -260,7 +316,8 @@ type CountDataOpts struct {
 	RepoID *api.RepoID
 }
 
-// CountData counts the amount of data points in a given time range.
+// CountData counts the amount of data points in a given time range. Downsampled points are
+// counted if the day or week they aggregate overlaps the time range.
 func (s *Store) CountData(ctx context.Context, opts CountDataOpts) (int, error) {
 	count, ok, err := basestore.ScanFirstInt(s.Store.Query(ctx, countDataQuery(opts)))
 	if err != nil {
@@ -273,13 +330,32 @@ func (s *Store) CountData(ctx context.Context, opts CountDataOpts) (int, error)
 }
 
 const countDataFmtstr = `
-SELECT COUNT(*) FROM series_points WHERE %s
+-- source: enterprise/internal/insights/store/store.go:CountData
+SELECT
+	(SELECT COUNT(*) FROM series_points WHERE %s) +
+	(SELECT COUNT(*) FROM series_points_daily WHERE %s) +
+	(SELECT COUNT(*) FROM series_points_weekly WHERE %s)
 `
 
 func countDataQuery(opts CountDataOpts) *sqlf.Query {
+	return sqlf.Sprintf(
+		countDataFmtstr,
+		countDataPreds(opts, 0),
+		countDataPreds(opts, dailyBucket),
+		countDataPreds(opts, weeklyBucket),
+	)
+}
+
+// countDataPreds returns the conditions matching the points of a table whose points each
+// aggregate the given bucket of time, starting at the time of the point.
+func countDataPreds(opts CountDataOpts, bucket time.Duration) *sqlf.Query {
 	preds := []*sqlf.Query{}
 	if opts.From != nil {
-		preds = append(preds, sqlf.Sprintf("time >= %s", *opts.From))
+		if bucket > 0 {
+			preds = append(preds, sqlf.Sprintf("time > %s", opts.From.Add(-bucket)))
+		} else {
+			preds = append(preds, sqlf.Sprintf("time >= %s", *opts.From))
+		}
 	}
 	if opts.To != nil {
 		preds = append(preds, sqlf.Sprintf("time <= %s", *opts.To))
@@ -293,10 +369,7 @@ func countDataQuery(opts CountDataOpts) *sqlf.Query {
 	if len(preds) == 0 {
 		preds = append(preds, sqlf.Sprintf("TRUE"))
 	}
-	return sqlf.Sprintf(
-		countDataFmtstr,
-		sqlf.Join(preds, "\n AND "),
-	)
+	return sqlf.Join(preds, "\n AND ")
 }
 
 func (s *Store) DeleteSnapshots(ctx context.Context, series *types.InsightSeries) error {
@@ -312,6 +385,78 @@ const deleteSnapshotsSql = `
 delete from %s where series_id = %s;
 `
 
+// PruneTopRepositories deletes the points of the given series recorded for repositories
+// that have no point at the most recent recording time of the series. Series generated for the
+// top repositories only record the top repositories at every recording time, so this removes the
+// history of repositories that dropped out of the top.
+func (s *Store) PruneTopRepositories(ctx context.Context, seriesID string) error {
+	for _, table := range []string{recordingTable, "series_points_daily", "series_points_weekly"} {
+		if err := s.Exec(ctx, sqlf.Sprintf(pruneTopRepositoriesFmtstr, sqlf.Sprintf(table), seriesID, seriesID, seriesID)); err != nil {
+			return errors.Wrapf(err, "failed to prune top repositories of series_id: %s", seriesID)
+		}
+	}
+	return nil
+}
+
+const pruneTopRepositoriesFmtstr = `
+-- source: enterprise/internal/insights/store/store.go:PruneTopRepositories
+DELETE FROM %s
+WHERE series_id = %s AND repo_id NOT IN (
+	SELECT repo_id FROM series_points
+	WHERE series_id = %s AND repo_id IS NOT NULL AND time = (
+		SELECT MAX(time) FROM series_points WHERE series_id = %s
+	)
+)
+`
+
+// RepositoryScope is the set of repositories that the repository query of a series resolved to
+// at one of its recording times.
+type RepositoryScope struct {
+	SeriesID     string
+	RecordedAt   time.Time
+	Repositories []string
+}
+
+// RecordRepositoryScope records the repositories that the repository query of the given series
+// resolved to at the given recording time, so that the points recorded at that time can be
+// traced back to the repositories they were computed over. A scope recorded earlier for the same
+// recording time, e.g. by a job that was retried, is replaced.
+func (s *Store) RecordRepositoryScope(ctx context.Context, scope RepositoryScope) error {
+	if err := s.Exec(ctx, sqlf.Sprintf(recordRepositoryScopeSql, scope.SeriesID, scope.RecordedAt.UTC(), pq.Array(scope.Repositories))); err != nil {
+		return errors.Wrapf(err, "failed to record repository scope of series_id: %s", scope.SeriesID)
+	}
+	return nil
+}
+
+const recordRepositoryScopeSql = `
+-- source: enterprise/internal/insights/store/store.go:RecordRepositoryScope
+INSERT INTO insight_series_repository_scopes (series_id, recorded_at, repositories)
+VALUES (%s, %s, %s)
+ON CONFLICT (series_id, recorded_at) DO UPDATE SET repositories = EXCLUDED.repositories
+`
+
+// RepositoryScopes returns the repository scopes recorded for the given series, oldest first.
+func (s *Store) RepositoryScopes(ctx context.Context, seriesID string) ([]RepositoryScope, error) {
+	var scopes []RepositoryScope
+	err := s.query(ctx, sqlf.Sprintf(repositoryScopesSql, seriesID), func(sc scanner) error {
+		var scope RepositoryScope
+		if err := sc.Scan(&scope.SeriesID, &scope.RecordedAt, pq.Array(&scope.Repositories)); err != nil {
+			return err
+		}
+		scopes = append(scopes, scope)
+		return nil
+	})
+	return scopes, err
+}
+
+const repositoryScopesSql = `
+-- source: enterprise/internal/insights/store/store.go:RepositoryScopes
+SELECT series_id, recorded_at, repositories
+FROM insight_series_repository_scopes
+WHERE series_id = %s
+ORDER BY recorded_at, id
+`
+
 type PersistMode string
 
 const (
@@ -412,6 +557,7 @@ func (s *Store) RecordSeriesPoint(ctx context.Context, v RecordSeriesPointArgs)
 		v.RepoID,           // repo_id
 		repoNameID,         // repo_name_id
 		repoNameID,         // original_repo_name_id
+		v.Point.Capture,    // capture
 	)
 	// Insert the actual data point.
 	return txStore.Exec(ctx, q)
@@ -469,8 +615,9 @@ INSERT INTO %s (
 	metadata_id,
 	repo_id,
 	repo_name_id,
-	original_repo_name_id)
-VALUES (%s, %s, %s, %s, %s, %s, %s);
+	original_repo_name_id,
+	capture)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s);
 `
 
 func (s *Store) query(ctx context.Context, q *sqlf.Query, sc scanFunc) error {
@@ -332,6 +332,262 @@ func TestRecordSeriesPoints(t *testing.T) {
 	}
 }
 
+func TestRecordSeriesPointsWithCapture(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	clock := timeutil.Now
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	postgres := dbtest.NewDB(t, "")
+	permStore := NewInsightPermissionStore(postgres)
+	store := NewWithClock(timescale, permStore, clock)
+
+	optionalString := func(v string) *string { return &v }
+	optionalRepoID := func(v api.RepoID) *api.RepoID { return &v }
+
+	current := time.Date(2021, time.September, 10, 10, 0, 0, 0, time.UTC)
+
+	for _, record := range []RecordSeriesPointArgs{
+		{
+			SeriesID:    "one",
+			Point:       SeriesPoint{Time: current, Value: 1, Capture: optionalString("v1.0.0")},
+			RepoName:    optionalString("repo1"),
+			RepoID:      optionalRepoID(3),
+			PersistMode: RecordMode,
+		},
+		{
+			SeriesID:    "one",
+			Point:       SeriesPoint{Time: current, Value: 2, Capture: optionalString("v0.9.1")},
+			RepoName:    optionalString("repo1"),
+			RepoID:      optionalRepoID(3),
+			PersistMode: RecordMode,
+		},
+		{
+			SeriesID:    "one",
+			Point:       SeriesPoint{Time: current, Value: 3, Capture: optionalString("v1.0.0")},
+			RepoName:    optionalString("repo2"),
+			RepoID:      optionalRepoID(4),
+			PersistMode: RecordMode,
+		},
+	} {
+		if err := store.RecordSeriesPoint(ctx, record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	captures, err := store.CaptureValues(ctx, "one")
+	if err != nil {
+		t.Fatal(err)
+	}
+	autogold.Want("captures", []string{"v0.9.1", "v1.0.0"}).Equal(t, captures)
+
+	points, err := store.SeriesPoints(ctx, SeriesPointsOpts{Capture: optionalString("v1.0.0")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SeriesPoint{
+		{
+			SeriesID: "one",
+			Time:     current,
+			Value:    4,
+			Capture:  optionalString("v1.0.0"),
+		},
+	}
+	if diff := cmp.Diff(want, points); diff != "" {
+		t.Errorf("unexpected points (-want +got):\n%s", diff)
+	}
+}
+
+func TestPruneTopRepositories(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	clock := timeutil.Now
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	postgres := dbtest.NewDB(t, "")
+	permStore := NewInsightPermissionStore(postgres)
+	store := NewWithClock(timescale, permStore, clock)
+
+	optionalString := func(v string) *string { return &v }
+	optionalRepoID := func(v api.RepoID) *api.RepoID { return &v }
+
+	previous := time.Date(2021, time.September, 10, 10, 0, 0, 0, time.UTC)
+	current := previous.Add(24 * time.Hour)
+
+	// repo2 dropped out of the top repositories at the current recording time.
+	for _, record := range []struct {
+		time   time.Time
+		repo   string
+		repoID api.RepoID
+	}{
+		{previous, "repo1", 3},
+		{previous, "repo2", 4},
+		{current, "repo1", 3},
+		{current, "repo3", 5},
+	} {
+		if err := store.RecordSeriesPoint(ctx, RecordSeriesPointArgs{
+			SeriesID:    "one",
+			Point:       SeriesPoint{Time: record.time, Value: 1, Capture: optionalString(record.repo)},
+			RepoName:    optionalString(record.repo),
+			RepoID:      optionalRepoID(record.repoID),
+			PersistMode: RecordMode,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := store.PruneTopRepositories(ctx, "one"); err != nil {
+		t.Fatal(err)
+	}
+
+	captures, err := store.CaptureValues(ctx, "one")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"repo1", "repo3"}, captures); diff != "" {
+		t.Errorf("unexpected repositories (-want +got):\n%s", diff)
+	}
+
+	count, err := store.CountData(ctx, CountDataOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Errorf("unexpected number of points: want 3, have %d", count)
+	}
+}
+
+func TestRepositoryScopes(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	clock := timeutil.Now
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	postgres := dbtest.NewDB(t, "")
+	permStore := NewInsightPermissionStore(postgres)
+	store := NewWithClock(timescale, permStore, clock)
+
+	first := time.Date(2021, time.September, 10, 10, 0, 0, 0, time.UTC)
+	second := first.Add(24 * time.Hour)
+
+	for _, scope := range []RepositoryScope{
+		{SeriesID: "one", RecordedAt: second, Repositories: []string{"repo1", "repo3"}},
+		{SeriesID: "one", RecordedAt: first, Repositories: []string{"repo1"}},
+		{SeriesID: "two", RecordedAt: first, Repositories: []string{"repo2"}},
+		// A retried job replaces the scope recorded earlier for the same time.
+		{SeriesID: "one", RecordedAt: first, Repositories: []string{"repo1", "repo2"}},
+	} {
+		if err := store.RecordRepositoryScope(ctx, scope); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scopes, err := store.RepositoryScopes(ctx, "one")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []RepositoryScope{
+		{SeriesID: "one", RecordedAt: first, Repositories: []string{"repo1", "repo2"}},
+		{SeriesID: "one", RecordedAt: second, Repositories: []string{"repo1", "repo3"}},
+	}
+	if diff := cmp.Diff(want, scopes); diff != "" {
+		t.Errorf("unexpected repository scopes (-want +got):\n%s", diff)
+	}
+}
+
+func TestDownsample(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	clock := timeutil.Now
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	postgres := dbtest.NewDB(t, "")
+	permStore := NewInsightPermissionStore(postgres)
+	store := NewWithClock(timescale, permStore, clock)
+
+	optionalString := func(v string) *string { return &v }
+	optionalRepoID := func(v api.RepoID) *api.RepoID { return &v }
+
+	// Wednesday 1 September 2021.
+	day := time.Date(2021, time.September, 1, 0, 0, 0, 0, time.UTC)
+	for _, point := range []SeriesPoint{
+		{Time: day.Add(2 * time.Hour), Value: 1},
+		{Time: day.Add(14 * time.Hour), Value: 3},
+		{Time: day.AddDate(0, 0, 1), Value: 2},
+		{Time: day.AddDate(0, 0, 10), Value: 5},
+	} {
+		if err := store.RecordSeriesPoint(ctx, RecordSeriesPointArgs{
+			SeriesID:    "one",
+			Point:       point,
+			RepoName:    optionalString("repo1"),
+			RepoID:      optionalRepoID(3),
+			PersistMode: RecordMode,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	getValues := func() map[time.Time]float64 {
+		points, err := store.SeriesPoints(ctx, SeriesPointsOpts{SeriesID: optionalString("one")})
+		if err != nil {
+			t.Fatal(err)
+		}
+		values := make(map[time.Time]float64, len(points))
+		for _, point := range points {
+			values[point.Time.UTC()] = point.Value
+		}
+		return values
+	}
+
+	// Downsample the first two days to daily points.
+	if err := store.Downsample(ctx, DownsampleOpts{RawBefore: day.AddDate(0, 0, 2)}); err != nil {
+		t.Fatal(err)
+	}
+	want := map[time.Time]float64{
+		day:                   3,
+		day.AddDate(0, 0, 1):  2,
+		day.AddDate(0, 0, 10): 5,
+	}
+	if diff := cmp.Diff(want, getValues()); diff != "" {
+		t.Errorf("unexpected values after daily downsampling (-want +got):\n%s", diff)
+	}
+
+	// Downsample the daily points of the week starting Monday 30 August to a weekly point.
+	if err := store.Downsample(ctx, DownsampleOpts{DailyBefore: time.Date(2021, time.September, 6, 0, 0, 0, 0, time.UTC)}); err != nil {
+		t.Fatal(err)
+	}
+	want = map[time.Time]float64{
+		time.Date(2021, time.August, 30, 0, 0, 0, 0, time.UTC): 3,
+		day.AddDate(0, 0, 10): 5,
+	}
+	if diff := cmp.Diff(want, getValues()); diff != "" {
+		t.Errorf("unexpected values after weekly downsampling (-want +got):\n%s", diff)
+	}
+
+	// Downsampled points still count as data for the period they aggregate, so that they are not
+	// backfilled again.
+	from, to := day, day.Add(24*time.Hour)
+	count, err := store.CountData(ctx, CountDataOpts{From: &from, To: &to, SeriesID: optionalString("one")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("unexpected count of downsampled data: %d", count)
+	}
+}
+
 func TestRecordSeriesPointsSnapshotOnly(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
@@ -2,6 +2,8 @@ package types
 
 import (
 	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
 )
 
 // InsightViewSeries is an abstraction of a complete Code Insight. This type materializes a view with any associated series.
@@ -18,6 +20,12 @@ type InsightViewSeries struct {
 	LastSnapshotAt                time.Time
 	NextSnapshotAfter             time.Time
 	BackfillQueuedAt              *time.Time
+	BackfillEstimatedCost         *int
+	BackfillSpentCost             int
+	GeneratedFromCaptureGroups    bool
+	GenerationMethod              GenerationMethod
+	TopRepositoriesLimit          int
+	RepositoryQuery               string
 	Label                         string
 	LineColor                     string
 	Repositories                  []string
@@ -58,22 +66,60 @@ type InsightView struct {
 // InsightSeries is a single data series for a Code Insight. This contains some metadata about the data series, as well
 // as its unique series ID.
 type InsightSeries struct {
-	ID                  int
-	SeriesID            string
-	Query               string
-	CreatedAt           time.Time
-	OldestHistoricalAt  time.Time
-	LastRecordedAt      time.Time
-	NextRecordingAfter  time.Time
-	LastSnapshotAt      time.Time
-	NextSnapshotAfter   time.Time
-	BackfillQueuedAt    time.Time
-	Enabled             bool
-	Repositories        []string
-	SampleIntervalUnit  string
-	SampleIntervalValue int
+	ID                    int
+	SeriesID              string
+	Query                 string
+	CreatedAt             time.Time
+	OldestHistoricalAt    time.Time
+	LastRecordedAt        time.Time
+	NextRecordingAfter    time.Time
+	LastSnapshotAt        time.Time
+	NextSnapshotAfter     time.Time
+	BackfillQueuedAt      time.Time
+	Enabled               bool
+	Repositories          []string
+	SampleIntervalUnit    string
+	SampleIntervalValue   int
+	BackfillEstimatedCost *int
+	BackfillSpentCost     int
+
+	// BackfillCursorRepoID and BackfillCursorFrame are the position the historical backfill of
+	// this series stopped at when its budget was exhausted: the first BackfillCursorFrame frames of
+	// that repository, and all repositories with a lower ID, have been enqueued.
+	BackfillCursorRepoID api.RepoID
+	BackfillCursorFrame  int
+
+	// GeneratedFromCaptureGroups indicates that this series generates one data series per distinct
+	// value of the regexp capture group in its query.
+	GeneratedFromCaptureGroups bool
+
+	// GenerationMethod describes how the data of this series is generated.
+	GenerationMethod GenerationMethod
+
+	// TopRepositoriesLimit is the number of repositories recorded at every recording time by
+	// series generated with GenerationMethodTopRepositories.
+	TopRepositoriesLimit int
+
+	// RepositoryQuery, if set, is a search query whose matching repositories are the
+	// repository scope of this series. It is resolved again at every recording time.
+	RepositoryQuery string
 }
 
+// GenerationMethod describes how the data of an insight series is generated.
+type GenerationMethod string
+
+const (
+	// GenerationMethodSearch series record the number of matches of a search query.
+	GenerationMethodSearch GenerationMethod = "search"
+	// GenerationMethodLanguageStats series record the number of bytes of code per language in
+	// each of their repositories. They generate one data series per language.
+	GenerationMethodLanguageStats GenerationMethod = "language-stats"
+	// GenerationMethodTopRepositories series record the number of matches of a search query in
+	// the repositories with the most matches at every recording time. They generate one data
+	// series per repository.
+	GenerationMethodTopRepositories GenerationMethod = "top-repositories"
+)
+
 type IntervalUnit string
 
 const (
@@ -118,3 +164,33 @@ type InsightSeriesStatus struct {
 	Failed     int
 	Completed  int
 }
+
+// AlertDirection describes how the value of an insight series is compared to the threshold of an
+// alert.
+type AlertDirection string
+
+const (
+	AlertDirectionAtLeast AlertDirection = "AT_LEAST"
+	AlertDirectionAtMost  AlertDirection = "AT_MOST"
+)
+
+// InsightSeriesAlert notifies a user when the value of an insight series crosses a threshold.
+type InsightSeriesAlert struct {
+	ID        int
+	SeriesID  string
+	UserID    int32
+	Threshold float64
+	Direction AlertDirection
+
+	// EvaluationWindowDays is the number of days over which the change of the series is evaluated.
+	// If zero, the latest value of the series is evaluated instead.
+	EvaluationWindowDays int
+
+	Email      bool
+	WebhookURL *string
+
+	Triggered       bool
+	CreatedAt       time.Time
+	LastEvaluatedAt *time.Time
+	LastTriggeredAt *time.Time
+}
@@ -29,6 +29,10 @@ type Actor struct {
 	// cookie, logout would be ineffective.)
 	FromSessionCookie bool `json:"-"`
 
+	// AccessTokenID is the ID of the access token that was used to authenticate the actor, if any.
+	// It is used to rate limit requests per access token.
+	AccessTokenID int64 `json:"-"`
+
 	// user is populated lazily by (*Actor).User()
 	user     *types.User
 	userErr  error
@@ -5,6 +5,8 @@ package authz
 import (
 	"context"
 
+	"github.com/cockroachdb/errors"
+
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 )
@@ -19,8 +21,17 @@ type ExternalUserPermissions struct {
 	Exacts          []extsvc.RepoID
 	IncludeContains []extsvc.RepoID
 	ExcludeContains []extsvc.RepoID
+
+	// SubRepoPermissions restricts the access to the contents of the listed
+	// repositories. Repositories that are not listed can be read entirely.
+	SubRepoPermissions map[extsvc.RepoID]*SubRepoPermissions
 }
 
+// ErrUnimplemented is returned by Provider methods that are not supported by the code host of the
+// authz provider, e.g. FetchRepoPerms for code hosts that cannot list the users who have access
+// to a repository.
+var ErrUnimplemented = errors.New("not implemented by the authz provider")
+
 // FetchPermsOptions declares options when performing permissions sync.
 type FetchPermsOptions struct {
 	// InvalidateCaches indicates that caches added for optimization encountered during
@@ -0,0 +1,39 @@
+package authz
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gobwas/glob"
+)
+
+// SubRepoPermissions denotes the paths within a repository that a user can
+// read. Paths are glob patterns relative to the repository root, e.g. "dir/**",
+// where "*" doesn't match "/" but "**" does. A pattern prefixed with "-"
+// revokes access to the paths it matches. Later patterns take precedence over
+// earlier ones, and paths that match no pattern can't be read.
+type SubRepoPermissions struct {
+	Paths []string
+}
+
+// CanRead returns true if the given path, relative to the repository root, can
+// be read.
+func (p *SubRepoPermissions) CanRead(path string) (bool, error) {
+	path = strings.TrimPrefix(path, "/")
+	for i := len(p.Paths) - 1; i >= 0; i-- {
+		pattern := p.Paths[i]
+		exclude := strings.HasPrefix(pattern, "-")
+		if exclude {
+			pattern = pattern[1:]
+		}
+
+		g, err := glob.Compile(pattern, '/')
+		if err != nil {
+			return false, errors.Wrapf(err, "compiling path pattern %q", p.Paths[i])
+		}
+		if g.Match(path) {
+			return !exclude, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,38 @@
+package authz
+
+import "testing"
+
+func TestSubRepoPermissions_CanRead(t *testing.T) {
+	p := &SubRepoPermissions{
+		Paths: []string{
+			"**",
+			"-secret/**",
+			"secret/public/**",
+			"-*.key",
+		},
+	}
+
+	for path, want := range map[string]bool{
+		"README.md":                true,
+		"/README.md":               true,
+		"dir/main.go":              true,
+		"secret/token":             false,
+		"secret/public/index.html": true,
+		"server.key":               false,
+		"dir/server.key":           true, // "*" doesn't match "/"
+	} {
+		have, err := p.CanRead(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have != want {
+			t.Errorf("%s: want %t, have %t", path, want, have)
+		}
+	}
+
+	t.Run("no rules", func(t *testing.T) {
+		if ok, err := (&SubRepoPermissions{}).CanRead("README.md"); err != nil || ok {
+			t.Fatalf("unexpected result: %t, %v", ok, err)
+		}
+	})
+}
@@ -0,0 +1,197 @@
+// Package codeowners parses CODEOWNERS files and resolves the owners of files in a
+// repository from them.
+package codeowners
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Paths are the paths, relative to the repository root, at which a CODEOWNERS file is
+// looked up on the default branch of a repository, in order of precedence. They match
+// the locations supported by GitHub and GitLab.
+var Paths = []string{
+	"CODEOWNERS",
+	".github/CODEOWNERS",
+	".gitlab/CODEOWNERS",
+	"docs/CODEOWNERS",
+}
+
+// Rule assigns owners to the files matching a pattern. A rule without owners marks
+// the matching files as unowned, overriding the rules before it.
+type Rule struct {
+	// Pattern is a gitignore-style pattern, e.g. "/cmd/" or "*.go".
+	Pattern string `json:"pattern"`
+	// Owners are the owners as written in the CODEOWNERS file, e.g. "@alice",
+	// "@org/team" or "bob@example.com".
+	Owners []string `json:"owners"`
+	// LineNumber is the 1-based line of the rule in the file it was parsed from.
+	LineNumber int `json:"lineNumber"`
+}
+
+// Ruleset is an ordered list of rules. The last rule matching a path determines its
+// owners.
+type Ruleset struct {
+	rules    []Rule
+	matchers []*regexp.Regexp
+}
+
+// NewRuleset returns a ruleset of the given rules.
+func NewRuleset(rules []Rule) (*Ruleset, error) {
+	matchers := make([]*regexp.Regexp, 0, len(rules))
+	for _, rule := range rules {
+		matcher, err := compilePattern(rule.Pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", rule.LineNumber)
+		}
+		matchers = append(matchers, matcher)
+	}
+	return &Ruleset{rules: rules, matchers: matchers}, nil
+}
+
+// Parse parses the contents of a CODEOWNERS file. Comments, blank lines and GitLab
+// section headers are skipped.
+func Parse(r io.Reader) (*Ruleset, error) {
+	var rules []Rule
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		fields := splitLine(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if isSectionHeader(fields[0]) {
+			continue
+		}
+		rules = append(rules, Rule{
+			Pattern:    fields[0],
+			Owners:     fields[1:],
+			LineNumber: lineNumber,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return NewRuleset(rules)
+}
+
+// Rules returns the rules of the ruleset in order.
+func (rs *Ruleset) Rules() []Rule {
+	return rs.rules
+}
+
+// Match returns the rule determining the owners of the file at the given path,
+// relative to the repository root, or nil if no rule matches.
+func (rs *Ruleset) Match(path string) *Rule {
+	path = strings.TrimPrefix(path, "/")
+	for i := len(rs.rules) - 1; i >= 0; i-- {
+		if rs.matchers[i].MatchString(path) {
+			return &rs.rules[i]
+		}
+	}
+	return nil
+}
+
+// FindOwners returns the owners of the file at the given path, relative to the
+// repository root.
+func (rs *Ruleset) FindOwners(path string) []string {
+	if rule := rs.Match(path); rule != nil {
+		return rule.Owners
+	}
+	return nil
+}
+
+// splitLine splits a line into its whitespace separated fields, dropping comments.
+// A backslash escapes the following character, so that patterns can contain spaces
+// and start with "#".
+func splitLine(line string) []string {
+	var (
+		fields  []string
+		current strings.Builder
+		escaped bool
+	)
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '#':
+			if current.Len() > 0 {
+				fields = append(fields, current.String())
+			}
+			return fields
+		case r == ' ' || r == '\t':
+			if current.Len() > 0 {
+				fields = append(fields, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		fields = append(fields, current.String())
+	}
+	return fields
+}
+
+// isSectionHeader returns whether the field starts a GitLab section, e.g.
+// "[Documentation]" or "^[Optional]".
+func isSectionHeader(field string) bool {
+	return strings.HasPrefix(field, "[") || strings.HasPrefix(field, "^[")
+}
+
+// compilePattern converts a gitignore-style pattern into a regular expression
+// matching the paths, relative to the repository root, of the files it applies to.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, errors.New("empty pattern")
+	}
+
+	// A pattern with a slash anywhere but at its end is relative to the repository
+	// root, other patterns match at any depth.
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	// A trailing slash only matches directories, so the pattern applies to the
+	// files inside them.
+	directory := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	switch {
+	case directory:
+		b.WriteString("/.*$")
+	case strings.HasSuffix(pattern, "/*") && !strings.HasSuffix(pattern, "/**"):
+		// Like GitHub, only match the files directly inside the directory.
+		b.WriteString("$")
+	default:
+		b.WriteString("(?:/.*)?$")
+	}
+	return regexp.Compile(b.String())
+}
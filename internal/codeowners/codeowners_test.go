@@ -0,0 +1,114 @@
+package codeowners
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParse(t *testing.T) {
+	ruleset, err := Parse(strings.NewReader(`# Default owners
+*       @org/everyone
+
+[Documentation]
+/docs/  @alice docs@example.com # writers
+*.go    @bob
+\#notes @carol
+/cmd/frontend/graphqlbackend/schema.graphql
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Rule{
+		{Pattern: "*", Owners: []string{"@org/everyone"}, LineNumber: 2},
+		{Pattern: "/docs/", Owners: []string{"@alice", "docs@example.com"}, LineNumber: 5},
+		{Pattern: "*.go", Owners: []string{"@bob"}, LineNumber: 6},
+		{Pattern: "#notes", Owners: []string{"@carol"}, LineNumber: 7},
+		{Pattern: "/cmd/frontend/graphqlbackend/schema.graphql", Owners: []string{}, LineNumber: 8},
+	}
+	if diff := cmp.Diff(want, ruleset.Rules()); diff != "" {
+		t.Fatalf("unexpected rules (-want +got):\n%s", diff)
+	}
+
+	for path, want := range map[string][]string{
+		"README.md":            {"@org/everyone"},
+		"docs/index.md":        {"@alice", "docs@example.com"},
+		"docs/main.go":         {"@bob"},
+		"cmd/frontend/main.go": {"@bob"},
+		"#notes":               {"@carol"},
+		"cmd/frontend/graphqlbackend/schema.graphql":  {},
+		"/cmd/frontend/graphqlbackend/schema.graphql": {},
+	} {
+		if diff := cmp.Diff(want, ruleset.FindOwners(path)); diff != "" {
+			t.Errorf("unexpected owners of %q (-want +got):\n%s", path, diff)
+		}
+	}
+}
+
+func TestCompilePattern(t *testing.T) {
+	for _, tc := range []struct {
+		pattern string
+		matches []string
+		misses  []string
+	}{
+		{
+			pattern: "*.js",
+			matches: []string{"a.js", "web/src/a.js"},
+			misses:  []string{"a.jsx", "a.js.map"},
+		},
+		{
+			pattern: "build/logs/",
+			matches: []string{"build/logs/a.log", "build/logs/2021/a.log"},
+			misses:  []string{"build/logs", "src/build/logs/a.log"},
+		},
+		{
+			pattern: "docs/*",
+			matches: []string{"docs/getting-started.md"},
+			misses:  []string{"foo/docs/getting-started.md", "docs/build-app/troubleshooting.md"},
+		},
+		{
+			pattern: "apps/",
+			matches: []string{"apps/a.go", "src/apps/a.go"},
+			misses:  []string{"apps", "myapps/a.go"},
+		},
+		{
+			pattern: "/scripts",
+			matches: []string{"scripts", "scripts/build.sh"},
+			misses:  []string{"src/scripts/build.sh"},
+		},
+		{
+			pattern: "**/logs",
+			matches: []string{"logs/a.log", "build/logs/a.log", "deeply/nested/logs"},
+			misses:  []string{"build/mylogs/a.log"},
+		},
+		{
+			pattern: "/internal/**/store.go",
+			matches: []string{"internal/store.go", "internal/database/store.go", "internal/a/b/store.go"},
+			misses:  []string{"cmd/internal/store.go"},
+		},
+		{
+			pattern: "file?.txt",
+			matches: []string{"file1.txt"},
+			misses:  []string{"file10.txt", "file/.txt"},
+		},
+	} {
+		t.Run(tc.pattern, func(t *testing.T) {
+			re, err := compilePattern(tc.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, path := range tc.matches {
+				if !re.MatchString(path) {
+					t.Errorf("expected %q to match %q", tc.pattern, path)
+				}
+			}
+			for _, path := range tc.misses {
+				if re.MatchString(path) {
+					t.Errorf("expected %q not to match %q", tc.pattern, path)
+				}
+			}
+		})
+	}
+}
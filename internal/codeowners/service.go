@@ -0,0 +1,150 @@
+package codeowners
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// maxFileSize is the maximum size of a CODEOWNERS file or uploaded manifest. GitHub
+// ignores CODEOWNERS files larger than 3 MB.
+const maxFileSize = 3 * 1024 * 1024
+
+// Service resolves the owners of files in repositories. The rules of an uploaded
+// ownership manifest take precedence over the CODEOWNERS file on the default branch,
+// which is ingested when the default branch moves.
+//
+// 🚨 SECURITY: The service does not check that the caller is allowed to see the
+// repositories. Callers must do so.
+type Service struct {
+	store *Store
+
+	resolveDefaultBranch func(ctx context.Context, repo api.RepoName) (api.CommitID, error)
+	readFile             func(ctx context.Context, repo api.RepoName, commit api.CommitID, path string) ([]byte, error)
+}
+
+// NewService returns a new Service storing the rules in the given database.
+func NewService(db dbutil.DB) *Service {
+	return &Service{
+		store: NewStore(db),
+		resolveDefaultBranch: func(ctx context.Context, repo api.RepoName) (api.CommitID, error) {
+			return git.ResolveRevision(ctx, repo, "HEAD", git.ResolveRevisionOptions{NoEnsureRevision: true})
+		},
+		readFile: func(ctx context.Context, repo api.RepoName, commit api.CommitID, path string) ([]byte, error) {
+			return git.ReadFile(ctx, repo, commit, path, maxFileSize)
+		},
+	}
+}
+
+// FileOwners returns the owners of the file at the given path in the repository. It
+// returns no owners if the repository has no ownership rules or none match the path.
+func (s *Service) FileOwners(ctx context.Context, repo types.RepoName, path string) ([]string, error) {
+	ruleset, err := s.Ruleset(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	return ruleset.FindOwners(path), nil
+}
+
+// Ruleset returns the ownership rules of the repository. If the repository has no
+// uploaded manifest, the CODEOWNERS file is ingested first if the default branch moved
+// since it was last ingested.
+func (s *Service) Ruleset(ctx context.Context, repo types.RepoName) (*Ruleset, error) {
+	stored, err := s.store.List(ctx, repo.ID)
+	if err != nil {
+		return nil, err
+	}
+	var file *StoredRuleset
+	for i := range stored {
+		switch stored[i].Source {
+		case SourceUpload:
+			return NewRuleset(stored[i].Rules)
+		case SourceFile:
+			file = &stored[i]
+		}
+	}
+
+	commit, err := s.resolveDefaultBranch(ctx, repo.Name)
+	if err != nil {
+		if errors.HasType(err, &gitdomain.RevisionNotFoundError{}) {
+			// The repository is empty.
+			return NewRuleset(nil)
+		}
+		return nil, errors.Wrap(err, "resolving default branch")
+	}
+	if file != nil && file.CommitID == commit {
+		return NewRuleset(file.Rules)
+	}
+
+	ingested, err := s.ingest(ctx, repo, commit)
+	if err != nil {
+		return nil, err
+	}
+	return NewRuleset(ingested.Rules)
+}
+
+// Ingest parses the CODEOWNERS file on the default branch of the repository and stores
+// its rules.
+func (s *Service) Ingest(ctx context.Context, repo types.RepoName) error {
+	commit, err := s.resolveDefaultBranch(ctx, repo.Name)
+	if err != nil {
+		return errors.Wrap(err, "resolving default branch")
+	}
+	_, err = s.ingest(ctx, repo, commit)
+	return err
+}
+
+func (s *Service) ingest(ctx context.Context, repo types.RepoName, commit api.CommitID) (StoredRuleset, error) {
+	stored := StoredRuleset{RepoID: repo.ID, Source: SourceFile, CommitID: commit}
+	for _, path := range Paths {
+		contents, err := s.readFile(ctx, repo.Name, commit, path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return StoredRuleset{}, errors.Wrapf(err, "reading %s", path)
+		}
+		ruleset, err := Parse(bytes.NewReader(contents))
+		if err != nil {
+			return StoredRuleset{}, errors.Wrapf(err, "parsing %s", path)
+		}
+		stored.Path = path
+		stored.Rules = ruleset.Rules()
+		break
+	}
+
+	// Store the ruleset even if the repository has no CODEOWNERS file, so that the
+	// file isn't looked up again until the default branch moves.
+	if err := s.store.Upsert(ctx, stored); err != nil {
+		return StoredRuleset{}, err
+	}
+	return stored, nil
+}
+
+// UploadManifest parses the ownership manifest, in the CODEOWNERS format, and stores
+// its rules for the repository. They take precedence over the CODEOWNERS file of the
+// repository until the manifest is deleted.
+func (s *Service) UploadManifest(ctx context.Context, repoID api.RepoID, contents string) error {
+	if len(contents) > maxFileSize {
+		return errors.Errorf("manifest is larger than %d bytes", maxFileSize)
+	}
+	ruleset, err := Parse(strings.NewReader(contents))
+	if err != nil {
+		return errors.Wrap(err, "parsing manifest")
+	}
+	return s.store.Upsert(ctx, StoredRuleset{RepoID: repoID, Source: SourceUpload, Rules: ruleset.Rules()})
+}
+
+// DeleteManifest deletes the uploaded ownership manifest of the repository, if any.
+func (s *Service) DeleteManifest(ctx context.Context, repoID api.RepoID) error {
+	return s.store.Delete(ctx, repoID, SourceUpload)
+}
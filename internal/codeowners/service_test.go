@@ -0,0 +1,91 @@
+package codeowners
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestService(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtest.NewDB(t, "")
+	ctx := context.Background()
+
+	if err := database.Repos(db).Create(ctx, &types.Repo{Name: "github.com/sourcegraph/sourcegraph"}); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := database.Repos(db).GetByName(ctx, "github.com/sourcegraph/sourcegraph")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := types.RepoName{ID: stored.ID, Name: stored.Name}
+
+	var (
+		head  api.CommitID = "c1"
+		files              = map[string]string{".github/CODEOWNERS": "*.go @alice\n"}
+		reads int
+	)
+	svc := NewService(db)
+	svc.resolveDefaultBranch = func(ctx context.Context, repo api.RepoName) (api.CommitID, error) { return head, nil }
+	svc.readFile = func(ctx context.Context, repo api.RepoName, commit api.CommitID, path string) ([]byte, error) {
+		reads++
+		contents, ok := files[path]
+		if !ok {
+			return nil, &os.PathError{Op: "open", Path: path, Err: os.ErrNotExist}
+		}
+		return []byte(contents), nil
+	}
+
+	assertOwners := func(t *testing.T, path string, want []string) {
+		t.Helper()
+		have, err := svc.FileOwners(ctx, repo, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, have); diff != "" {
+			t.Errorf("unexpected owners of %q (-want +have):\n%s", path, diff)
+		}
+	}
+
+	t.Run("ingests the CODEOWNERS file", func(t *testing.T) {
+		assertOwners(t, "cmd/main.go", []string{"@alice"})
+		assertOwners(t, "README.md", nil)
+		if reads != 2 {
+			t.Errorf("expected the CODEOWNERS file to be looked up once, have %d reads", reads)
+		}
+	})
+
+	t.Run("reingests when the default branch moves", func(t *testing.T) {
+		head = "c2"
+		files = map[string]string{"CODEOWNERS": "* @bob\n"}
+		assertOwners(t, "cmd/main.go", []string{"@bob"})
+	})
+
+	t.Run("uploaded manifest takes precedence", func(t *testing.T) {
+		if err := svc.UploadManifest(ctx, repo.ID, "/cmd/ @org/team\n"); err != nil {
+			t.Fatal(err)
+		}
+		assertOwners(t, "cmd/main.go", []string{"@org/team"})
+		assertOwners(t, "README.md", nil)
+
+		if err := svc.DeleteManifest(ctx, repo.ID); err != nil {
+			t.Fatal(err)
+		}
+		assertOwners(t, "README.md", []string{"@bob"})
+	})
+
+	t.Run("no CODEOWNERS file", func(t *testing.T) {
+		head = "c3"
+		files = nil
+		assertOwners(t, "cmd/main.go", nil)
+	})
+}
@@ -0,0 +1,151 @@
+package codeowners
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// Source is where the rules of a repository come from.
+type Source string
+
+const (
+	// SourceFile denotes the rules ingested from the CODEOWNERS file on the default
+	// branch of a repository.
+	SourceFile Source = "file"
+	// SourceUpload denotes the rules of an uploaded ownership manifest. They take
+	// precedence over the CODEOWNERS file.
+	SourceUpload Source = "upload"
+)
+
+// StoredRuleset is the stored ownership rules of a repository from one source.
+type StoredRuleset struct {
+	RepoID api.RepoID
+	Source Source
+	// CommitID is the commit of the default branch the rules were ingested at. It is
+	// empty for uploaded rules.
+	CommitID api.CommitID
+	// Path is the path of the CODEOWNERS file the rules were parsed from, or empty if
+	// the repository has none.
+	Path      string
+	Rules     []Rule
+	UpdatedAt time.Time
+}
+
+// Store stores the ownership rules of repositories.
+//
+// 🚨 SECURITY: The store does not check that the caller is allowed to see the
+// repositories. Callers must do so.
+type Store struct {
+	*basestore.Store
+}
+
+// NewStore instantiates and returns a new Store.
+func NewStore(db dbutil.DB) *Store {
+	return &Store{Store: basestore.NewWithDB(db, sql.TxOptions{})}
+}
+
+func (s *Store) With(other basestore.ShareableStore) *Store {
+	return &Store{Store: s.Store.With(other)}
+}
+
+func (s *Store) Transact(ctx context.Context) (*Store, error) {
+	txBase, err := s.Store.Transact(ctx)
+	return &Store{Store: txBase}, err
+}
+
+// List returns the stored rulesets of the given repository.
+func (s *Store) List(ctx context.Context, repoID api.RepoID) ([]StoredRuleset, error) {
+	return scanStoredRulesets(s.Query(ctx, sqlf.Sprintf(listRulesetsQuery, repoID)))
+}
+
+const listRulesetsQuery = `
+-- source: internal/codeowners/store.go:List
+SELECT repo_id, source, commit_id, path, rules, updated_at
+FROM codeowners_rulesets
+WHERE repo_id = %s
+ORDER BY source
+`
+
+// Upsert stores the ruleset, replacing the stored ruleset of the same repository and
+// source.
+func (s *Store) Upsert(ctx context.Context, rs StoredRuleset) error {
+	rules := rs.Rules
+	if rules == nil {
+		rules = []Rule{}
+	}
+	encoded, err := json.Marshal(rules)
+	if err != nil {
+		return err
+	}
+	return s.Exec(ctx, sqlf.Sprintf(
+		upsertRulesetQuery,
+		rs.RepoID,
+		rs.Source,
+		dbutil.NewNullString(string(rs.CommitID)),
+		dbutil.NewNullString(rs.Path),
+		encoded,
+	))
+}
+
+const upsertRulesetQuery = `
+-- source: internal/codeowners/store.go:Upsert
+INSERT INTO codeowners_rulesets (repo_id, source, commit_id, path, rules, updated_at)
+VALUES (%s, %s, %s, %s, %s, NOW())
+ON CONFLICT (repo_id, source) DO UPDATE
+SET
+	commit_id = EXCLUDED.commit_id,
+	path = EXCLUDED.path,
+	rules = EXCLUDED.rules,
+	updated_at = EXCLUDED.updated_at
+`
+
+// Delete deletes the stored ruleset of the given repository and source.
+func (s *Store) Delete(ctx context.Context, repoID api.RepoID, source Source) error {
+	return s.Exec(ctx, sqlf.Sprintf(deleteRulesetQuery, repoID, source))
+}
+
+const deleteRulesetQuery = `
+-- source: internal/codeowners/store.go:Delete
+DELETE FROM codeowners_rulesets
+WHERE repo_id = %s AND source = %s
+`
+
+func scanStoredRulesets(rows *sql.Rows, queryErr error) (_ []StoredRuleset, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var rulesets []StoredRuleset
+	for rows.Next() {
+		var (
+			rs       StoredRuleset
+			commitID string
+			encoded  []byte
+		)
+		if err := rows.Scan(
+			&rs.RepoID,
+			&rs.Source,
+			&dbutil.NullString{S: &commitID},
+			&dbutil.NullString{S: &rs.Path},
+			&encoded,
+			&rs.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(encoded, &rs.Rules); err != nil {
+			return nil, err
+		}
+		rs.CommitID = api.CommitID(commitID)
+		rulesets = append(rulesets, rs)
+	}
+	return rulesets, nil
+}
@@ -3,6 +3,7 @@ package compute
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
@@ -29,6 +30,7 @@ type Command interface {
 func (MatchOnly) command()            {}
 func (ReplaceInPlace) command()       {}
 func (ReplaceWithSeparator) command() {}
+func (CountOverCommits) command()     {}
 
 type MatchOnly struct {
 	MatchPattern MatchPattern
@@ -45,6 +47,32 @@ type ReplaceWithSeparator struct {
 	Separator      string
 }
 
+// CountOverCommits counts the matches of a pattern in the repositories matched by a
+// query at commits spaced by an interval, which yields a time series.
+type CountOverCommits struct {
+	MatchPattern MatchPattern
+	Interval     Interval
+}
+
+type IntervalUnit string
+
+const (
+	Hour  IntervalUnit = "HOUR"
+	Day   IntervalUnit = "DAY"
+	Week  IntervalUnit = "WEEK"
+	Month IntervalUnit = "MONTH"
+	Year  IntervalUnit = "YEAR"
+)
+
+type Interval struct {
+	Unit  IntervalUnit
+	Value int
+}
+
+func (i Interval) String() string {
+	return fmt.Sprintf("%d %s", i.Value, strings.ToLower(string(i.Unit)))
+}
+
 func (c MatchOnly) String() string {
 	return fmt.Sprintf("Match only: %s", c.MatchPattern.String())
 }
@@ -57,6 +85,52 @@ func (c ReplaceWithSeparator) String() string {
 	return fmt.Sprintf("Replace with separator: %s -> %s separator: %s", c.MatchPattern.String(), c.ReplacePattern, c.Separator)
 }
 
+func (c CountOverCommits) String() string {
+	return fmt.Sprintf("Count over commits: %s every %s", c.MatchPattern.String(), c.Interval.String())
+}
+
+// SearchQuery returns the search query whose matches are counted at each commit. It
+// contains the given parameters except plain repo filters, which select the
+// repositories to walk instead, and the match pattern. Predicates like
+// `repo:contains.file()` and `file:contains()` are kept, so that the search scopes
+// the count the same way at each commit.
+func (c CountOverCommits) SearchQuery(parameters []query.Parameter) string {
+	nodes := make([]query.Node, 0, len(parameters)+2)
+	nodes = append(nodes, query.Parameter{Field: query.FieldPatternType, Value: "regexp"})
+	for _, parameter := range parameters {
+		if !isRepoFilter(parameter) {
+			nodes = append(nodes, parameter)
+		}
+	}
+	nodes = append(nodes, query.Pattern{
+		Value:      c.MatchPattern.String(),
+		Annotation: query.Annotation{Labels: query.Regexp | query.Quoted},
+	})
+	return query.StringHuman(nodes)
+}
+
+// RepoFilters returns the patterns of the plain repo filters in the given parameters
+// that repository names must and must not match, respectively.
+func RepoFilters(parameters []query.Parameter) (include, exclude []string) {
+	for _, parameter := range parameters {
+		if !isRepoFilter(parameter) {
+			continue
+		}
+		if parameter.Negated {
+			exclude = append(exclude, parameter.Value)
+		} else {
+			include = append(include, parameter.Value)
+		}
+	}
+	return include, exclude
+}
+
+// isRepoFilter returns true if the given parameter matches repository names. Repo
+// predicates like `repo:contains.file()` are evaluated by the search instead.
+func isRepoFilter(parameter query.Parameter) bool {
+	return parameter.Field == query.FieldRepo && !parameter.Annotation.Labels.IsSet(query.IsPredicate)
+}
+
 type MatchPattern interface {
 	pattern()
 	String() string
@@ -120,9 +194,80 @@ func toRegexpPattern(value string) (*Regexp, error) {
 var ComputePredicateRegistry = query.PredicateRegistry{
 	query.FieldContent: {
 		"replace": func() query.Predicate { return query.EmptyPredicate{} },
+		"count":   func() query.Predicate { return query.EmptyPredicate{} },
 	},
 }
 
+// defaultCommitInterval is the interval between the commits a pattern is counted at
+// when `over commits` does not specify one.
+var defaultCommitInterval = Interval{Unit: Month, Value: 1}
+
+var overCommitsPattern = regexp.MustCompile(`(?i)\s+over\s+commits(?:\(([^)]*)\))?\s*$`)
+
+// splitOverCommits removes a trailing `over commits` clause, optionally with an interval
+// like `over commits(2 weeks)`, from the given query. It returns the interval of the
+// clause, or nil if the query does not end with one.
+func splitOverCommits(q string) (string, *Interval, error) {
+	match := overCommitsPattern.FindStringSubmatchIndex(q)
+	if match == nil {
+		return q, nil, nil
+	}
+	if match[2] == -1 {
+		interval := defaultCommitInterval
+		return q[:match[0]], &interval, nil
+	}
+	interval, err := parseInterval(q[match[2]:match[3]])
+	if err != nil {
+		return "", nil, err
+	}
+	return q[:match[0]], interval, nil
+}
+
+// parseInterval parses an interval like `2 weeks` or `day`.
+func parseInterval(value string) (*Interval, error) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 || len(fields) > 2 {
+		return nil, errors.Errorf("invalid interval %q, expected a value like '2 weeks'", value)
+	}
+	n := 1
+	if len(fields) == 2 {
+		var err error
+		if n, err = strconv.Atoi(fields[0]); err != nil || n <= 0 {
+			return nil, errors.Errorf("invalid interval %q, expected a positive number", value)
+		}
+	}
+	unit := IntervalUnit(strings.ToUpper(strings.TrimSuffix(strings.ToLower(fields[len(fields)-1]), "s")))
+	switch unit {
+	case Hour, Day, Week, Month, Year:
+	default:
+		return nil, errors.Errorf("invalid interval unit %q, expected one of hour, day, week, month or year", fields[len(fields)-1])
+	}
+	return &Interval{Unit: unit, Value: n}, nil
+}
+
+func parseCountOverCommits(pattern *query.Pattern, interval *Interval) (*CountOverCommits, bool, error) {
+	if !pattern.Annotation.Labels.IsSet(query.IsAlias) {
+		// pattern is not set via `content:`, so it cannot be a count command.
+		return nil, false, nil
+	}
+	value, _, ok := query.ScanPredicate("content", []byte(pattern.Value), ComputePredicateRegistry)
+	if !ok {
+		return nil, false, nil
+	}
+	name, args := query.ParseAsPredicate(value)
+	if name != "count" {
+		return nil, false, nil
+	}
+	if interval == nil {
+		return nil, false, errors.New("count command must be followed by `over commits`, e.g. `content:count(foo) over commits(2 weeks)`")
+	}
+	rp, err := toRegexpPattern(args)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "count command")
+	}
+	return &CountOverCommits{MatchPattern: rp, Interval: *interval}, true, nil
+}
+
 func parseReplaceInPlace(pattern *query.Pattern) (*ReplaceInPlace, bool, error) {
 	if !pattern.Annotation.Labels.IsSet(query.IsAlias) {
 		// pattern is not set via `content:`, so it cannot be a replace command.
@@ -144,7 +289,18 @@ func parseReplaceInPlace(pattern *query.Pattern) (*ReplaceInPlace, bool, error)
 	return &ReplaceInPlace{MatchPattern: rp, ReplacePattern: parts[1]}, true, nil
 }
 
-func toCommand(pattern *query.Pattern) (Command, error) {
+func toCommand(pattern *query.Pattern, interval *Interval) (Command, error) {
+	countCommand, ok, err := parseCountOverCommits(pattern, interval)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return countCommand, nil
+	}
+	if interval != nil {
+		return nil, errors.New("only the count command can be evaluated `over commits`")
+	}
+
 	command, ok, err := parseReplaceInPlace(pattern)
 	if err != nil {
 		return nil, err
@@ -160,7 +316,7 @@ func toCommand(pattern *query.Pattern) (Command, error) {
 	return &MatchOnly{MatchPattern: rp}, nil
 }
 
-func toComputeQuery(plan query.Plan) (*Query, error) {
+func toComputeQuery(plan query.Plan, interval *Interval) (*Query, error) {
 	if len(plan) != 1 {
 		return nil, errors.New("compute endpoint only supports one search pattern currently ('and' or 'or' operators are not supported yet)")
 	}
@@ -168,7 +324,7 @@ func toComputeQuery(plan query.Plan) (*Query, error) {
 	if err != nil {
 		return nil, err
 	}
-	command, err := toCommand(pattern)
+	command, err := toCommand(pattern, interval)
 	if err != nil {
 		return nil, err
 	}
@@ -179,9 +335,13 @@ func toComputeQuery(plan query.Plan) (*Query, error) {
 }
 
 func Parse(q string) (*Query, error) {
+	q, interval, err := splitOverCommits(q)
+	if err != nil {
+		return nil, err
+	}
 	plan, err := query.Pipeline(query.Init(q, query.SearchTypeRegex))
 	if err != nil {
 		return nil, err
 	}
-	return toComputeQuery(plan)
+	return toComputeQuery(plan, interval)
 }
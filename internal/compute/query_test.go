@@ -20,4 +20,36 @@ func TestParse(t *testing.T) {
 	autogold.Want("no pattern", "compute endpoint expects nonempty pattern").Equal(t, test("repo:cool"))
 	autogold.Want("unsupported operators", "compute endpoint only supports one search pattern currently ('and' or 'or' operators are not supported yet)").Equal(t, test("a or b"))
 	autogold.Want("replace command", "Command: `Replace in place: sourcegraph  ->  smorgasboard`, Parameters: ``").Equal(t, test("content:replace(sourcegraph -> smorgasboard)"))
+	autogold.Want("count over commits", "Command: `Count over commits: errorf every 1 month`, Parameters: `\"repo:foo\"`").Equal(t, test("repo:foo content:count(errorf) over commits"))
+	autogold.Want("count over commits with interval", "Command: `Count over commits: errorf every 2 week`, Parameters: ``").Equal(t, test("content:count(errorf) over commits(2 weeks)"))
+	autogold.Want("count over commits with unit", "Command: `Count over commits: errorf every 1 day`, Parameters: ``").Equal(t, test("content:count(errorf) OVER COMMITS(day)"))
+	autogold.Want("count without over commits", "count command must be followed by `over commits`, e.g. `content:count(foo) over commits(2 weeks)`").Equal(t, test("content:count(errorf)"))
+	autogold.Want("over commits without count", "only the count command can be evaluated `over commits`").Equal(t, test("errorf over commits"))
+	autogold.Want("repo predicate", "Command: `Match only: errorf`, Parameters: `\"repo:contains.file(go.mod)\"`").Equal(t, test("repo:contains.file(go.mod) errorf"))
+	autogold.Want("file predicate", "Command: `Replace in place: a  ->  b`, Parameters: `\"file:contains(TODO)\"`").Equal(t, test("file:contains(TODO) content:replace(a -> b)"))
+	autogold.Want("invalid predicate", "invalid predicate value: contains.file argument should not be empty").Equal(t, test("repo:contains.file() errorf"))
+	autogold.Want("invalid interval", "invalid interval unit \"fortnights\", expected one of hour, day, week, month or year").Equal(t, test("content:count(errorf) over commits(2 fortnights)"))
+}
+
+func TestCountOverCommitsSearchQuery(t *testing.T) {
+	test := func(input string) string {
+		q, err := Parse(input)
+		if err != nil {
+			return err.Error()
+		}
+		return q.Command.(*CountOverCommits).SearchQuery(q.Parameters)
+	}
+
+	autogold.Want("predicates kept", "patterntype:regexp repo:contains.file(go.mod) file:contains(TODO) \"errorf\"").Equal(t, test(`repo:foo repo:contains.file(go.mod) file:contains(TODO) content:count(errorf) over commits`))
+	autogold.Want("repo filters removed", "patterntype:regexp file:\\.go$ \"errorf\\\\(\"").Equal(t, test(`repo:foo file:\.go$ content:count(errorf\() over commits`))
+}
+
+func TestRepoFilters(t *testing.T) {
+	q, err := Parse(`repo:^github\.com/a/ -repo:b$ repo:contains.file(go.mod) content:count(errorf) over commits`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	include, exclude := RepoFilters(q.Parameters)
+	autogold.Want("include", []string{`^github\.com/a/`}).Equal(t, include)
+	autogold.Want("exclude", []string{"b$"}).Equal(t, exclude)
 }
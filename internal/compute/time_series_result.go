@@ -0,0 +1,15 @@
+package compute
+
+import "time"
+
+// TimeSeries is the result of counting the matches of a pattern over commits.
+type TimeSeries struct {
+	Points              []TimeSeriesPoint `json:"points"`
+	SampledRepositories int               `json:"sampledRepositories"`
+	Complete            bool              `json:"complete"`
+}
+
+type TimeSeriesPoint struct {
+	Time  time.Time `json:"time"`
+	Value int       `json:"value"`
+}
@@ -244,6 +244,22 @@ func ExternalURL() string {
 	return Get().ExternalURL
 }
 
+// defaultMaintenanceModeMessage is shown to users in maintenance mode if no message is configured.
+const defaultMaintenanceModeMessage = "Sourcegraph is undergoing maintenance and is read-only. Changes cannot be saved until maintenance is over."
+
+// MaintenanceMode reports whether the site is in maintenance mode ("maintenanceMode"), and if so,
+// returns the message shown to users.
+func MaintenanceMode() (message string, enabled bool) {
+	mm := Get().MaintenanceMode
+	if mm == nil || !mm.Enabled {
+		return "", false
+	}
+	if mm.Message == "" {
+		return defaultMaintenanceModeMessage, true
+	}
+	return mm.Message, true
+}
+
 func UsingExternalURL() bool {
 	url := Get().ExternalURL
 	return !(url == "" || strings.HasPrefix(url, "http://localhost") || strings.HasPrefix(url, "https://localhost") || strings.HasPrefix(url, "http://127.0.0.1") || strings.HasPrefix(url, "https://127.0.0.1")) // CI:LOCALHOST_OK
@@ -341,6 +357,16 @@ func AuthPasswordResetLinkExpiry() int {
 	return val
 }
 
+// AuthUserDeletionGracePeriod returns how long deleted users are kept before they are
+// permanently deleted. If not set, it returns the default value of 30 days.
+func AuthUserDeletionGracePeriod() time.Duration {
+	days := Get().AuthUserDeletionGracePeriodDays
+	if days <= 0 {
+		days = 30
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
 type ExternalServiceMode int
 
 const (
@@ -105,6 +105,41 @@ func TestAuthPasswordResetLinkDuration(t *testing.T) {
 	}
 }
 
+func TestMaintenanceMode(t *testing.T) {
+	tests := []struct {
+		name        string
+		sc          *Unified
+		wantMessage string
+		wantEnabled bool
+	}{{
+		name: "not in maintenance mode by default",
+		sc:   &Unified{},
+	}, {
+		name: "disabled",
+		sc:   &Unified{SiteConfiguration: schema.SiteConfiguration{MaintenanceMode: &schema.MaintenanceMode{Enabled: false, Message: "foo"}}},
+	}, {
+		name:        "enabled with default message",
+		sc:          &Unified{SiteConfiguration: schema.SiteConfiguration{MaintenanceMode: &schema.MaintenanceMode{Enabled: true}}},
+		wantMessage: defaultMaintenanceModeMessage,
+		wantEnabled: true,
+	}, {
+		name:        "enabled with custom message",
+		sc:          &Unified{SiteConfiguration: schema.SiteConfiguration{MaintenanceMode: &schema.MaintenanceMode{Enabled: true, Message: "Back at 10:00 UTC"}}},
+		wantMessage: "Back at 10:00 UTC",
+		wantEnabled: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			Mock(test.sc)
+			message, enabled := MaintenanceMode()
+			if message != test.wantMessage || enabled != test.wantEnabled {
+				t.Fatalf("MaintenanceMode() = (%q, %v), want (%q, %v)", message, enabled, test.wantMessage, test.wantEnabled)
+			}
+		})
+	}
+}
+
 func TestGitLongCommandTimeout(t *testing.T) {
 	tests := []struct {
 		name string
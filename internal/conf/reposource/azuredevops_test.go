@@ -0,0 +1,52 @@
+package reposource
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestAzureDevOps_cloneURLToRepoName(t *testing.T) {
+	tests := []struct {
+		conn schema.AzureDevOpsConnection
+		urls []urlToRepoName
+	}{
+		{
+			conn: schema.AzureDevOpsConnection{
+				Url: "https://dev.azure.com",
+			},
+			urls: []urlToRepoName{
+				{"https://dev.azure.com/myorg/myproject/_git/myrepo", "dev.azure.com/myorg/myproject/myrepo"},
+				{"https://myorg@dev.azure.com/myorg/myproject/_git/myrepo", "dev.azure.com/myorg/myproject/myrepo"},
+				{"https://dev.azure.com/myorg/my%20project/_git/myrepo", "dev.azure.com/myorg/my project/myrepo"},
+				{"git@ssh.dev.azure.com:v3/myorg/myproject/myrepo", "dev.azure.com/myorg/myproject/myrepo"},
+
+				{"https://dev.azure.com/myorg/myproject", ""},
+				{"git@ssh.dev.azure.com:myorg/myproject/myrepo", ""},
+				{"https://asdf.com/myorg/myproject/_git/myrepo", ""},
+			},
+		},
+		{
+			conn: schema.AzureDevOpsConnection{
+				Url:                   "https://dev.azure.com",
+				RepositoryPathPattern: "ado/{org}/{repo}",
+			},
+			urls: []urlToRepoName{
+				{"https://dev.azure.com/myorg/myproject/_git/myrepo", "ado/myorg/myrepo"},
+				{"git@ssh.dev.azure.com:v3/myorg/myproject/myrepo", "ado/myorg/myrepo"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		for _, u := range test.urls {
+			repoName, err := AzureDevOps{&test.conn}.CloneURLToRepoName(u.cloneURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if u.repoName != string(repoName) {
+				t.Errorf("expected %q but got %q for clone URL %q (connection: %+v)", u.repoName, repoName, u.cloneURL, test.conn)
+			}
+		}
+	}
+}
@@ -201,14 +201,44 @@ func ValidateSetting(input string) (problems []string, err error) {
 	return doValidate(input, schema.SettingsSchemaJSON)
 }
 
+// SettingsProblem is a problem with settings found by ValidateSettingProblems.
+type SettingsProblem struct {
+	// Path is the JSON path of the invalid property, such as "quicklinks.0.url". It is "(root)"
+	// for problems with the settings as a whole.
+	Path        string `json:"path"`
+	Description string `json:"description"`
+}
+
+func (p SettingsProblem) String() string {
+	return fmt.Sprintf("%s: %s", p.Path, p.Description)
+}
+
+// ValidateSettingProblems is like ValidateSetting, except it returns the JSON path of the
+// property each problem is about.
+func ValidateSettingProblems(input string) ([]SettingsProblem, error) {
+	return doValidateProblems(input, schema.SettingsSchemaJSON)
+}
+
 func doValidate(inputStr, schema string) (messages []string, err error) {
+	problems, err := doValidateProblems(inputStr, schema)
+	if err != nil {
+		return nil, err
+	}
+	messages = make([]string, 0, len(problems))
+	for _, p := range problems {
+		messages = append(messages, p.String())
+	}
+	return messages, nil
+}
+
+func doValidateProblems(inputStr, schema string) (problems []SettingsProblem, err error) {
 	input := jsonc.Normalize(inputStr)
 
 	res, err := validate([]byte(schema), input)
 	if err != nil {
 		return nil, err
 	}
-	messages = make([]string, 0, len(res.Errors()))
+	problems = make([]SettingsProblem, 0, len(res.Errors()))
 	for _, e := range res.Errors() {
 		if _, ok := ignoreLegacyKubernetesFields[e.Field()]; ok {
 			continue
@@ -221,9 +251,9 @@ func doValidate(inputStr, schema string) (messages []string, err error) {
 			keyPath = e.Field()
 		}
 
-		messages = append(messages, fmt.Sprintf("%s: %s", keyPath, e.Description()))
+		problems = append(problems, SettingsProblem{Path: keyPath, Description: e.Description()})
 	}
-	return messages, nil
+	return problems, nil
 }
 
 func validate(schema, input []byte) (*gojsonschema.Result, error) {
@@ -135,24 +135,24 @@ INSERT INTO access_tokens(subject_user_id, scopes, value_sha256, note, creator_u
 }
 
 // Lookup looks up the access token. If it's valid and contains the required scope, it returns the
-// subject's user ID. Otherwise ErrAccessTokenNotFound is returned.
+// subject's user ID and the ID of the access token. Otherwise ErrAccessTokenNotFound is returned.
 //
 // Calling Lookup also updates the access token's last-used-at date.
 //
 // 🚨 SECURITY: This returns a user ID if and only if the tokenHexEncoded corresponds to a valid,
 // non-deleted access token.
-func (s *AccessTokenStore) Lookup(ctx context.Context, tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+func (s *AccessTokenStore) Lookup(ctx context.Context, tokenHexEncoded, requiredScope string) (subjectUserID int32, id int64, err error) {
 	if Mocks.AccessTokens.Lookup != nil {
 		return Mocks.AccessTokens.Lookup(tokenHexEncoded, requiredScope)
 	}
 
 	if requiredScope == "" {
-		return 0, errors.New("no scope provided in access token lookup")
+		return 0, 0, errors.New("no scope provided in access token lookup")
 	}
 
 	token, err := hex.DecodeString(tokenHexEncoded)
 	if err != nil {
-		return 0, errors.Wrap(err, "AccessTokens.Lookup")
+		return 0, 0, errors.Wrap(err, "AccessTokens.Lookup")
 	}
 
 	if err := s.Handle().DB().QueryRowContext(ctx,
@@ -166,16 +166,16 @@ WHERE t.id IN (
 	WHERE t2.value_sha256=$1 AND t2.deleted_at IS NULL AND
 	$2 = ANY (t2.scopes)
 )
-RETURNING t.subject_user_id
+RETURNING t.subject_user_id, t.id
 `,
 		toSHA256Bytes(token), requiredScope,
-	).Scan(&subjectUserID); err != nil {
+	).Scan(&subjectUserID, &id); err != nil {
 		if err == sql.ErrNoRows {
-			return 0, ErrAccessTokenNotFound
+			return 0, 0, ErrAccessTokenNotFound
 		}
-		return 0, err
+		return 0, 0, err
 	}
-	return subjectUserID, nil
+	return subjectUserID, id, nil
 }
 
 // GetByID retrieves the access token (if any) given its ID.
@@ -361,6 +361,6 @@ type MockAccessTokens struct {
 	CreateInternal func(subjectUserID int32, scopes []string, note string, creatorUserID int32) (id int64, token string, err error)
 	DeleteByID     func(id int64) error
 	HardDeleteByID func(id int64) error
-	Lookup         func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error)
+	Lookup         func(tokenHexEncoded, requiredScope string) (subjectUserID int32, id int64, err error)
 	GetByID        func(id int64) (*AccessToken, error)
 }
@@ -54,7 +54,7 @@ func TestAccessTokens_Create(t *testing.T) {
 		t.Errorf("got %q, want %q", got.Note, want)
 	}
 
-	gotSubjectUserID, err := AccessTokens(db).Lookup(ctx, tv0, "a")
+	gotSubjectUserID, _, err := AccessTokens(db).Lookup(ctx, tv0, "a")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -196,7 +196,7 @@ func TestAccessTokens_Lookup(t *testing.T) {
 	}
 
 	for _, scope := range []string{"a", "b"} {
-		gotSubjectUserID, err := AccessTokens(db).Lookup(ctx, tv0, scope)
+		gotSubjectUserID, _, err := AccessTokens(db).Lookup(ctx, tv0, scope)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -206,12 +206,12 @@ func TestAccessTokens_Lookup(t *testing.T) {
 	}
 
 	// Lookup with a nonexistent scope and ensure it fails.
-	if _, err := AccessTokens(db).Lookup(ctx, tv0, "x"); err == nil {
+	if _, _, err := AccessTokens(db).Lookup(ctx, tv0, "x"); err == nil {
 		t.Fatal(err)
 	}
 
 	// Lookup with an empty scope and ensure it fails.
-	if _, err := AccessTokens(db).Lookup(ctx, tv0, ""); err == nil {
+	if _, _, err := AccessTokens(db).Lookup(ctx, tv0, ""); err == nil {
 		t.Fatal(err)
 	}
 
@@ -219,12 +219,12 @@ func TestAccessTokens_Lookup(t *testing.T) {
 	if err := AccessTokens(db).DeleteByID(ctx, tid0); err != nil {
 		t.Fatal(err)
 	}
-	if _, err := AccessTokens(db).Lookup(ctx, tv0, "a"); err == nil {
+	if _, _, err := AccessTokens(db).Lookup(ctx, tv0, "a"); err == nil {
 		t.Fatal(err)
 	}
 
 	// Try to Lookup a token that was never created.
-	if _, err := AccessTokens(db).Lookup(ctx, "abcdefg" /* this token value was never created */, "a"); err == nil {
+	if _, _, err := AccessTokens(db).Lookup(ctx, "abcdefg" /* this token value was never created */, "a"); err == nil {
 		t.Fatal(err)
 	}
 }
@@ -266,7 +266,7 @@ func TestAccessTokens_Lookup_deletedUser(t *testing.T) {
 		if err := Users(db).Delete(ctx, subject.ID); err != nil {
 			t.Fatal(err)
 		}
-		if _, err := AccessTokens(db).Lookup(ctx, tv0, "a"); err == nil {
+		if _, _, err := AccessTokens(db).Lookup(ctx, tv0, "a"); err == nil {
 			t.Fatal("Lookup: want error looking up token for deleted subject user")
 		}
 
@@ -302,7 +302,7 @@ func TestAccessTokens_Lookup_deletedUser(t *testing.T) {
 		if err := Users(db).Delete(ctx, creator.ID); err != nil {
 			t.Fatal(err)
 		}
-		if _, err := AccessTokens(db).Lookup(ctx, tv0, "a"); err == nil {
+		if _, _, err := AccessTokens(db).Lookup(ctx, tv0, "a"); err == nil {
 			t.Fatal("Lookup: want error looking up token for deleted creator user")
 		}
 
@@ -0,0 +1,303 @@
+package dbconn
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"hash/fnv"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/inconshreveable/log15"
+	"github.com/lib/pq"
+)
+
+// MigrationPhase is the phase of a deploy in which a migration runs. It is set
+// with a `-- migration: <phase>` comment at the top of the up migration, e.g.
+//
+//	-- migration: concurrent-index
+//	CREATE INDEX CONCURRENTLY IF NOT EXISTS repo_name_idx ON repo(name);
+type MigrationPhase string
+
+const (
+	// MigrationPhasePreDeploy migrations run when the new version starts,
+	// before it serves requests. It is the phase of migrations without a
+	// `-- migration:` comment.
+	MigrationPhasePreDeploy MigrationPhase = "pre-deploy"
+	// MigrationPhaseConcurrentIndex migrations create or drop indexes
+	// concurrently, which can't be done in a transaction. They run right after
+	// the pre-deploy migrations, outside of a transaction and statement by
+	// statement, so they must not contain BEGIN or COMMIT.
+	MigrationPhaseConcurrentIndex MigrationPhase = "concurrent-index"
+	// MigrationPhasePostDeploy migrations run once all instances run the new
+	// version (see MigratePostDeploy), e.g. to drop a column the previous
+	// version still reads.
+	MigrationPhasePostDeploy MigrationPhase = "post-deploy"
+)
+
+// deferredMigration is a migration that doesn't run with the pre-deploy
+// migrations, but in a later phase.
+type deferredMigration struct {
+	Version uint
+	Name    string
+	Phase   MigrationPhase
+	Body    string
+}
+
+// readDeferredMigrations returns the deferred migrations of the given
+// migrations, by version.
+func readDeferredMigrations(fsys fs.FS) (map[uint]deferredMigration, error) {
+	names, err := fs.Glob(fsys, "*.up.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	deferred := map[uint]deferredMigration{}
+	for _, name := range names {
+		body, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		phase, err := parseMigrationPhase(string(body))
+		if err != nil {
+			return nil, errors.Wrap(err, name)
+		}
+		if phase == MigrationPhasePreDeploy {
+			continue
+		}
+
+		version, err := strconv.ParseUint(name[:strings.IndexByte(name, '_')], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing version of %s", name)
+		}
+		if phase == MigrationPhaseConcurrentIndex {
+			for _, stmt := range splitStatements(string(body)) {
+				if s := strings.ToUpper(stmt); s == "BEGIN" || s == "COMMIT" {
+					return nil, errors.Newf("%s: concurrent-index migrations run outside of a transaction and must not contain %s", name, s)
+				}
+			}
+		}
+		deferred[uint(version)] = deferredMigration{Version: uint(version), Name: name, Phase: phase, Body: string(body)}
+	}
+	return deferred, nil
+}
+
+// parseMigrationPhase returns the phase set by the `-- migration:` comment in
+// the leading comments of the migration.
+func parseMigrationPhase(body string) (MigrationPhase, error) {
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "--") {
+			break
+		}
+		directive := strings.TrimSpace(strings.TrimPrefix(line, "--"))
+		if !strings.HasPrefix(directive, "migration:") {
+			continue
+		}
+		switch phase := MigrationPhase(strings.TrimSpace(strings.TrimPrefix(directive, "migration:"))); phase {
+		case MigrationPhasePreDeploy, MigrationPhaseConcurrentIndex, MigrationPhasePostDeploy:
+			return phase, nil
+		default:
+			return "", errors.Newf("unknown migration phase %q", phase)
+		}
+	}
+	return MigrationPhasePreDeploy, scanner.Err()
+}
+
+// splitStatements splits the statements of a concurrent-index migration,
+// dropping comments. Statements are expected to end with a semicolon at the end
+// of a line.
+func splitStatements(body string) []string {
+	var (
+		stmts   []string
+		current strings.Builder
+	)
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			stmts = append(stmts, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+	if s := strings.TrimSpace(current.String()); s != "" {
+		stmts = append(stmts, s)
+	}
+	return stmts
+}
+
+// deferringSource is a migration source that hides the deferred migrations
+// from golang-migrate, which then only records their version. They are run by
+// RunDeferredMigrations instead.
+type deferringSource struct {
+	source.Driver
+	deferred map[uint]deferredMigration
+}
+
+func (s *deferringSource) ReadUp(version uint) (io.ReadCloser, string, error) {
+	if _, ok := s.deferred[version]; ok {
+		return nil, "", os.ErrNotExist
+	}
+	return s.Driver.ReadUp(version)
+}
+
+// MigratePostDeploy runs the post-deploy migrations of the database that have
+// not run yet. It must only be called once all instances run the version
+// that applied them.
+func MigratePostDeploy(db *sql.DB, database *Database) error {
+	return RunDeferredMigrations(db, database, MigrationPhasePostDeploy)
+}
+
+// RunDeferredMigrations runs the migrations of the given phases that were
+// applied by golang-migrate, but have not run yet, in order of their versions.
+//
+// Their completion is recorded in the <migrations table>_deferred table, which
+// is created if it doesn't exist yet.
+func RunDeferredMigrations(db *sql.DB, database *Database, phases ...MigrationPhase) error {
+	ctx := context.Background()
+
+	deferred, err := readDeferredMigrations(database.FS)
+	if err != nil {
+		return err
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// Only one instance runs deferred migrations at a time.
+	lockKey := deferredMigrationsLockKey(database)
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockKey); err != nil {
+		return errors.Wrap(err, "acquiring deferred migrations lock")
+	}
+	defer func() { _, _ = conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, lockKey) }()
+
+	table := pq.QuoteIdentifier(database.MigrationsTable + "_deferred")
+	if _, err := conn.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS `+table+` (
+	version bigint PRIMARY KEY,
+	phase text NOT NULL,
+	started_at timestamp with time zone NOT NULL DEFAULT now(),
+	finished_at timestamp with time zone,
+	error text
+)`); err != nil {
+		return errors.Wrap(err, "creating deferred migrations table")
+	}
+
+	var (
+		version int64
+		dirty   bool
+	)
+	err = conn.QueryRowContext(ctx, `SELECT version, dirty FROM `+pq.QuoteIdentifier(database.MigrationsTable)+` LIMIT 1`).Scan(&version, &dirty)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "reading schema version")
+	}
+	if dirty {
+		return errors.Newf("cannot run deferred migrations: schema version %d is dirty", version)
+	}
+
+	// Migrations that were migrated down must run again when they are migrated
+	// up again.
+	if _, err := conn.ExecContext(ctx, `DELETE FROM `+table+` WHERE version > $1`, version); err != nil {
+		return errors.Wrap(err, "deleting deferred migrations of newer versions")
+	}
+
+	finished := map[uint]bool{}
+	rows, err := conn.QueryContext(ctx, `SELECT version FROM `+table+` WHERE finished_at IS NOT NULL`)
+	if err != nil {
+		return errors.Wrap(err, "listing finished deferred migrations")
+	}
+	for rows.Next() {
+		var v int64
+		if err := rows.Scan(&v); err != nil {
+			rows.Close()
+			return err
+		}
+		finished[uint(v)] = true
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+
+	var pending []deferredMigration
+	for v, m := range deferred {
+		if int64(v) <= version && !finished[v] && containsPhase(phases, m.Phase) {
+			pending = append(pending, m)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Version < pending[j].Version })
+
+	for _, m := range pending {
+		log15.Info("Running deferred migration", "database", database.Name, "migration", m.Name, "phase", m.Phase)
+
+		if _, err := conn.ExecContext(ctx, `
+INSERT INTO `+table+` (version, phase) VALUES ($1, $2)
+ON CONFLICT (version) DO UPDATE SET phase = excluded.phase, started_at = now(), finished_at = NULL, error = NULL
+`, m.Version, string(m.Phase)); err != nil {
+			return errors.Wrap(err, "recording deferred migration")
+		}
+
+		runErr := runDeferredMigration(ctx, conn, m)
+		if runErr != nil {
+			if _, err := conn.ExecContext(ctx, `UPDATE `+table+` SET error = $2 WHERE version = $1`, m.Version, runErr.Error()); err != nil {
+				log15.Error("Failed to record error of deferred migration", "migration", m.Name, "error", err)
+			}
+			return errors.Wrapf(runErr, "running %s migration %s", m.Phase, m.Name)
+		}
+
+		if _, err := conn.ExecContext(ctx, `UPDATE `+table+` SET finished_at = now() WHERE version = $1`, m.Version); err != nil {
+			return errors.Wrap(err, "recording deferred migration")
+		}
+	}
+	return nil
+}
+
+func runDeferredMigration(ctx context.Context, conn *sql.Conn, m deferredMigration) error {
+	if m.Phase != MigrationPhaseConcurrentIndex {
+		_, err := conn.ExecContext(ctx, m.Body)
+		return err
+	}
+
+	for _, stmt := range splitStatements(m.Body) {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func containsPhase(phases []MigrationPhase, phase MigrationPhase) bool {
+	for _, p := range phases {
+		if p == phase {
+			return true
+		}
+	}
+	return false
+}
+
+// deferredMigrationsLockKey returns the key of the advisory lock held while
+// running the deferred migrations of the database.
+func deferredMigrationsLockKey(database *Database) int64 {
+	h := fnv.New32a()
+	_, _ = io.WriteString(h, database.MigrationsTable+"_deferred")
+	return int64(h.Sum32())
+}
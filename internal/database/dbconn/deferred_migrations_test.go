@@ -0,0 +1,88 @@
+package dbconn
+
+import (
+	"io"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadDeferredMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"1_init.up.sql":   {Data: []byte("BEGIN;\nCREATE TABLE repo (id serial, name text);\nCOMMIT;\n")},
+		"1_init.down.sql": {Data: []byte("DROP TABLE repo;\n")},
+		"2_repo_name_idx.up.sql": {Data: []byte(`-- Index the names of repositories.
+-- migration: concurrent-index
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS repo_name_idx ON repo(name);
+`)},
+		"3_drop_repo_uri.up.sql": {Data: []byte("-- migration: post-deploy\nBEGIN;\nALTER TABLE repo DROP COLUMN IF EXISTS uri;\nCOMMIT;\n")},
+		// Only leading comments set the phase.
+		"4_comment.up.sql": {Data: []byte("BEGIN;\n-- migration: post-deploy\nCOMMIT;\n")},
+	}
+
+	deferred, err := readDeferredMigrations(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[uint]deferredMigration{
+		2: {Version: 2, Name: "2_repo_name_idx.up.sql", Phase: MigrationPhaseConcurrentIndex, Body: string(fsys["2_repo_name_idx.up.sql"].Data)},
+		3: {Version: 3, Name: "3_drop_repo_uri.up.sql", Phase: MigrationPhasePostDeploy, Body: string(fsys["3_drop_repo_uri.up.sql"].Data)},
+	}
+	if diff := cmp.Diff(want, deferred); diff != "" {
+		t.Fatalf("unexpected deferred migrations (-want +got):\n%s", diff)
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		for name, body := range map[string]string{
+			"unknown phase": "-- migration: someday\nSELECT 1;\n",
+			"transaction":   "-- migration: concurrent-index\nBEGIN;\nCREATE INDEX CONCURRENTLY repo_name_idx ON repo(name);\nCOMMIT;\n",
+		} {
+			if _, err := readDeferredMigrations(fstest.MapFS{"5_invalid.up.sql": {Data: []byte(body)}}); err == nil {
+				t.Errorf("%s: want error, got none", name)
+			}
+		}
+	})
+}
+
+func TestSplitStatements(t *testing.T) {
+	body := `-- migration: concurrent-index
+CREATE INDEX CONCURRENTLY IF NOT EXISTS repo_name_idx
+    ON repo(name);
+
+-- The old index is superseded.
+DROP INDEX CONCURRENTLY IF EXISTS repo_name_old_idx;
+CREATE INDEX CONCURRENTLY repo_uri_idx ON repo(uri)`
+
+	want := []string{
+		"CREATE INDEX CONCURRENTLY IF NOT EXISTS repo_name_idx\n    ON repo(name)",
+		"DROP INDEX CONCURRENTLY IF EXISTS repo_name_old_idx",
+		"CREATE INDEX CONCURRENTLY repo_uri_idx ON repo(uri)",
+	}
+	if diff := cmp.Diff(want, splitStatements(body)); diff != "" {
+		t.Fatalf("unexpected statements (-want +got):\n%s", diff)
+	}
+}
+
+func TestDeferringSource(t *testing.T) {
+	src := &deferringSource{
+		Driver:   fakeSource{},
+		deferred: map[uint]deferredMigration{2: {Version: 2}},
+	}
+	if _, _, err := src.ReadUp(1); err != nil {
+		t.Fatalf("unexpected error reading pre-deploy migration: %s", err)
+	}
+	if _, _, err := src.ReadUp(2); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("want os.ErrNotExist reading deferred migration, got %v", err)
+	}
+}
+
+type fakeSource struct{ source.Driver }
+
+func (fakeSource) ReadUp(version uint) (io.ReadCloser, string, error) {
+	return io.NopCloser(nil), "", nil
+}
@@ -52,22 +52,53 @@ var (
 		MigrationsTable:    "codeinsights_schema_migrations",
 		FS:                 migrations.CodeInsights,
 	}
+
+	// CodeInsightsPostgres is the code insights database when code insights use the Postgres
+	// storage backend instead of TimescaleDB.
+	CodeInsightsPostgres = &Database{
+		Name:            "codeinsights",
+		MigrationsTable: "codeinsights_schema_migrations",
+		FS:              migrations.CodeInsightsPostgres,
+	}
 )
 
+// MigrateDB runs the pre-deploy migrations of the database, followed by its
+// concurrent-index migrations (see MigrationPhase). The post-deploy migrations
+// are only run if the database was not migrated before, otherwise they are
+// left for MigratePostDeploy.
 func MigrateDB(db *sql.DB, database *Database) error {
 	m, err := NewMigrate(db, database)
 	if err != nil {
 		return err
 	}
+	_, _, versionErr := m.Version()
+	fresh := versionErr == migrate.ErrNilVersion
+
 	if err := DoMigrate(m); err != nil {
 		return errors.Wrap(err, "Failed to migrate the DB. Please contact support@sourcegraph.com for further assistance")
 	}
+
+	phases := []MigrationPhase{MigrationPhaseConcurrentIndex}
+	if fresh {
+		phases = append(phases, MigrationPhasePostDeploy)
+	}
+	if err := RunDeferredMigrations(db, database, phases...); err != nil {
+		return errors.Wrap(err, "Failed to migrate the DB. Please contact support@sourcegraph.com for further assistance")
+	}
 	return nil
 }
 
 // NewMigrate returns a new configured migration object for the given database. The migration can
 // be subsequently run by invoking `dbconn.DoMigrate`.
+//
+// The migration only records the version of the migrations that don't run in
+// the pre-deploy phase, which are run by RunDeferredMigrations.
 func NewMigrate(db *sql.DB, database *Database) (*migrate.Migrate, error) {
+	deferred, err := readDeferredMigrations(database.FS)
+	if err != nil {
+		return nil, err
+	}
+
 	driver, err := postgres.WithInstance(db, &postgres.Config{
 		MigrationsTable: database.MigrationsTable,
 	})
@@ -80,7 +111,7 @@ func NewMigrate(db *sql.DB, database *Database) (*migrate.Migrate, error) {
 		return nil, err
 	}
 
-	m, err := migrate.NewWithInstance("httpfs", d, "postgres", driver)
+	m, err := migrate.NewWithInstance("httpfs", &deferringSource{Driver: d, deferred: deferred}, "postgres", driver)
 	if err != nil {
 		return nil, err
 	}
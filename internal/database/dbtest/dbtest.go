@@ -8,10 +8,15 @@ import (
 	"math/rand"
 	"net/url"
 	"os"
+	"runtime"
 	"strconv"
+	"strings"
 	"sync"
+	"syscall"
 	"testing"
+	"time"
 
+	"github.com/cockroachdb/errors"
 	"github.com/lib/pq"
 
 	"github.com/sourcegraph/sourcegraph/internal/database/dbconn"
@@ -56,6 +61,15 @@ var rngLock sync.Mutex
 
 // NewDB returns a connection to a clean, new temporary testing database
 // with the same schema as Sourcegraph's production Postgres database.
+//
+// The database is taken from a pool of databases cloned from the template
+// database ahead of time (see dbPool), and dropped once the test is done,
+// unless it failed.
+//
+// Every test gets a database of its own, so tests using NewDB don't share
+// tables and can run in parallel. The pool doesn't isolate anything else:
+// tests that set global state, such as database.Mocks or conf.Mock, still
+// can't be run in parallel with other tests of their package.
 func NewDB(t testing.TB, dsn string) *sql.DB {
 	if testing.Short() {
 		t.Skip("skipping DB test since -short specified")
@@ -81,12 +95,8 @@ func NewDB(t testing.TB, dsn string) *sql.DB {
 
 	initTemplateDB(t, config)
 
-	rngLock.Lock()
-	dbname := "sourcegraph-test-" + strconv.FormatUint(rng.Uint64(), 10)
-	rngLock.Unlock()
-
-	db := dbConn(t, config)
-	dbExec(t, db, `CREATE DATABASE `+pq.QuoteIdentifier(dbname)+` TEMPLATE `+pq.QuoteIdentifier(templateDBName()))
+	pool := getPool(t, config)
+	dbname := pool.take(t)
 
 	config.Path = "/" + dbname
 	testDB := dbConn(t, config)
@@ -97,9 +107,8 @@ func NewDB(t testing.TB, dsn string) *sql.DB {
 	testDB.SetMaxOpenConns(10)
 
 	t.Cleanup(func() {
-		defer db.Close()
-
 		if t.Failed() {
+			pool.keep(t, dbname)
 			t.Logf("DATABASE %s left intact for inspection", dbname)
 			return
 		}
@@ -107,13 +116,226 @@ func NewDB(t testing.TB, dsn string) *sql.DB {
 		if err := testDB.Close(); err != nil {
 			t.Fatalf("failed to close test database: %s", err)
 		}
-		dbExec(t, db, killClientConnsQuery, dbname)
-		dbExec(t, db, `DROP DATABASE `+pq.QuoteIdentifier(dbname))
+		pool.drop(t, dbname)
 	})
 
 	return testDB
 }
 
+// poolSize is the number of test databases a dbPool provisions ahead of time.
+// It can be set with the DB_TEST_POOL_SIZE env var, 0 disables provisioning
+// ahead of time.
+var poolSize = func() int {
+	if n, err := strconv.Atoi(os.Getenv("DB_TEST_POOL_SIZE")); err == nil && n >= 0 {
+		return n
+	}
+	if n := runtime.GOMAXPROCS(0); n < 4 {
+		return n
+	}
+	return 4
+}()
+
+// dbPool provisions test databases cloned from the template database of the
+// current package ahead of time. Postgres doesn't clone a template database
+// concurrently, so cloning while other tests run, rather than when a test
+// asks for a database, cuts the time parallel tests wait for their database.
+//
+// The databases are named after the package and the test process that created
+// them, so that databases left over by previous runs of its tests, e.g. the ones
+// provisioned but not used before the test binary exited, are dropped when the
+// pool is created. Only provably stale databases are dropped, see isStaleDB.
+type dbPool struct {
+	admin *sql.DB
+	ready chan pooledDB
+}
+
+type pooledDB struct {
+	name string
+	err  error
+}
+
+var (
+	poolsMu sync.Mutex
+	pools   = map[string]*dbPool{}
+)
+
+// getPool returns the pool of test databases of the Postgres server of the
+// given config, creating it on first use.
+func getPool(t testing.TB, config *url.URL) *dbPool {
+	poolsMu.Lock()
+	defer poolsMu.Unlock()
+
+	key := config.Host + "?" + config.RawQuery
+	if p, ok := pools[key]; ok {
+		return p
+	}
+
+	p := &dbPool{
+		admin: dbConn(t, config),
+		ready: make(chan pooledDB, poolSize),
+	}
+	p.dropLeftovers(t)
+	for i := 0; i < poolSize; i++ {
+		go p.provision()
+	}
+
+	pools[key] = p
+	return p
+}
+
+// take returns the name of a new test database, provisioning a replacement
+// for it in the background.
+func (p *dbPool) take(t testing.TB) string {
+	var db pooledDB
+	if poolSize == 0 {
+		db.name, db.err = p.create()
+	} else {
+		db = <-p.ready
+		go p.provision()
+	}
+	if db.err != nil {
+		t.Fatalf("failed to create test database: %s", db.err)
+	}
+	return db.name
+}
+
+func (p *dbPool) provision() {
+	name, err := p.create()
+	p.ready <- pooledDB{name: name, err: err}
+}
+
+func (p *dbPool) create() (string, error) {
+	rngLock.Lock()
+	n := rng.Uint32()
+	rngLock.Unlock()
+	dbname := poolDBName(hostHash(), os.Getpid(), time.Now(), n)
+
+	_, err := p.admin.Exec(`CREATE DATABASE ` + pq.QuoteIdentifier(dbname) + ` TEMPLATE ` + pq.QuoteIdentifier(templateDBName()))
+	return dbname, err
+}
+
+func (p *dbPool) drop(t testing.TB, dbname string) {
+	dbExec(t, p.admin, killClientConnsQuery, dbname)
+	dbExec(t, p.admin, `DROP DATABASE IF EXISTS `+pq.QuoteIdentifier(dbname))
+}
+
+// keep marks the given test database as left intact for inspection, so that
+// it isn't dropped as a leftover by later runs.
+func (p *dbPool) keep(t testing.TB, dbname string) {
+	dbExec(t, p.admin, `COMMENT ON DATABASE `+pq.QuoteIdentifier(dbname)+` IS `+pq.QuoteLiteral(keptDBComment))
+}
+
+// keptDBComment is the comment of test databases left intact for inspection.
+const keptDBComment = "kept for inspection"
+
+// leftoverTTL is the age after which a test database that wasn't kept for
+// inspection is considered a leftover, even if the process that created it
+// may still be running, e.g. on another host.
+const leftoverTTL = 24 * time.Hour
+
+// dropLeftovers drops the test databases of the current package left over by
+// previous runs of its tests. Databases kept for inspection and databases that
+// may still be in use by other test processes are left alone.
+func (p *dbPool) dropLeftovers(t testing.TB) {
+	rows, err := p.admin.Query(`
+SELECT datname FROM pg_database
+WHERE datname LIKE $1 || '%'
+AND COALESCE(shobj_description(oid, 'pg_database'), '') <> $2
+`, poolDBPrefix(), keptDBComment)
+	if err != nil {
+		t.Fatalf("failed to list leftover test databases: %s", err)
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatalf("failed to list leftover test databases: %s", err)
+		}
+		names = append(names, name)
+	}
+	if err := rows.Close(); err != nil {
+		t.Fatalf("failed to list leftover test databases: %s", err)
+	}
+
+	now := time.Now()
+	host := hostHash()
+	for _, name := range names {
+		if isStaleDB(name, now, host, processAlive) {
+			p.drop(t, name)
+		}
+	}
+}
+
+// poolDBPrefix returns the prefix of the names of the test databases of the
+// currently running package.
+func poolDBPrefix() string {
+	h := fnv.New64()
+	wd, _ := os.Getwd()
+	h.Write([]byte(wd))
+	return "sourcegraph-test-" + strconv.FormatUint(h.Sum64(), 36) + "-"
+}
+
+// poolDBName returns the name of a test database of the current package
+// created by the process with the given PID on the host with the given hash at
+// the given time. n distinguishes the databases created by the process at the
+// same time. All numbers are formatted in base 36 to keep the name within the
+// 63 bytes Postgres allows.
+func poolDBName(host string, pid int, createdAt time.Time, n uint32) string {
+	return poolDBPrefix() + strings.Join([]string{
+		host,
+		strconv.FormatInt(int64(pid), 36),
+		strconv.FormatInt(createdAt.Unix(), 36),
+		strconv.FormatUint(uint64(n), 36),
+	}, "-")
+}
+
+// isStaleDB returns true if the test database with the given name is provably
+// not in use anymore: it was created more than leftoverTTL ago, or by a process
+// on the given host that isn't alive anymore. Databases whose names can't be
+// parsed are never stale.
+func isStaleDB(name string, now time.Time, host string, alive func(pid int) bool) bool {
+	if !strings.HasPrefix(name, poolDBPrefix()) {
+		return false
+	}
+	parts := strings.Split(strings.TrimPrefix(name, poolDBPrefix()), "-")
+	if len(parts) != 4 {
+		return false
+	}
+	pid, err := strconv.ParseInt(parts[1], 36, 64)
+	if err != nil {
+		return false
+	}
+	createdAt, err := strconv.ParseInt(parts[2], 36, 64)
+	if err != nil {
+		return false
+	}
+
+	if now.Sub(time.Unix(createdAt, 0)) > leftoverTTL {
+		return true
+	}
+	return parts[0] == host && !alive(int(pid))
+}
+
+// hostHash returns a short hash of the hostname, which identifies the host
+// that created a test database.
+func hostHash() string {
+	h := fnv.New32()
+	hostname, _ := os.Hostname()
+	h.Write([]byte(hostname))
+	return strconv.FormatUint(uint64(h.Sum32()), 36)
+}
+
+// processAlive returns false if the process with the given PID provably
+// doesn't exist anymore.
+func processAlive(pid int) bool {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = proc.Signal(syscall.Signal(0))
+	return !errors.Is(err, os.ErrProcessDone) && !errors.Is(err, syscall.ESRCH)
+}
+
 var templateOnce sync.Once
 
 // initTemplateDB creates a template database with a fully migrated schema for the
@@ -148,6 +370,9 @@ func initTemplateDB(t testing.TB, config *url.URL) {
 			if err = dbconn.DoMigrate(m); err != nil {
 				t.Fatalf("failed to apply migrations: %s", err)
 			}
+			if err = dbconn.RunDeferredMigrations(templateDB, database, dbconn.MigrationPhaseConcurrentIndex, dbconn.MigrationPhasePostDeploy); err != nil {
+				t.Fatalf("failed to apply deferred migrations: %s", err)
+			}
 		}
 	})
 }
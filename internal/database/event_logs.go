@@ -15,6 +15,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/batch"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/featureflag"
 	"github.com/sourcegraph/sourcegraph/internal/timeutil"
@@ -86,6 +87,61 @@ type Event struct {
 }
 
 func (l *EventLogStore) Insert(ctx context.Context, e *Event) error {
+	values, err := eventLogValues(e)
+	if err != nil {
+		return err
+	}
+
+	_, err = l.Handle().DB().ExecContext(
+		ctx,
+		"INSERT INTO event_logs(name, url, user_id, anonymous_user_id, source, argument, public_argument, version, timestamp, feature_flags, cohort_id) VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)",
+		values...,
+	)
+	if err != nil {
+		return errors.Wrap(err, "INSERT")
+	}
+	return nil
+}
+
+// BulkInsert inserts the given events in batches, within a single transaction.
+func (l *EventLogStore) BulkInsert(ctx context.Context, events []*Event) (err error) {
+	tx, err := l.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	return batch.WithInserter(ctx, tx.Handle().DB(), "event_logs", eventLogsInsertColumns, func(inserter *batch.Inserter) error {
+		for _, e := range events {
+			values, err := eventLogValues(e)
+			if err != nil {
+				return err
+			}
+			if err := inserter.Insert(ctx, values...); err != nil {
+				return errors.Wrap(err, "INSERT")
+			}
+		}
+		return nil
+	})
+}
+
+// eventLogsInsertColumns are the columns of event_logs set by eventLogValues.
+var eventLogsInsertColumns = []string{
+	"name",
+	"url",
+	"user_id",
+	"anonymous_user_id",
+	"source",
+	"argument",
+	"public_argument",
+	"version",
+	"timestamp",
+	"feature_flags",
+	"cohort_id",
+}
+
+// eventLogValues returns the values of eventLogsInsertColumns for the given event.
+func eventLogValues(e *Event) ([]interface{}, error) {
 	// 🚨 SECURITY: It is important to sanitize event URL before being stored to the
 	// database to help guarantee no malicious data at rest.
 	e.URL = SanitizeEventURL(e.URL)
@@ -101,12 +157,10 @@ func (l *EventLogStore) Insert(ctx context.Context, e *Event) error {
 
 	featureFlags, err := json.Marshal(e.FeatureFlags)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	_, err = l.Handle().DB().ExecContext(
-		ctx,
-		"INSERT INTO event_logs(name, url, user_id, anonymous_user_id, source, argument, public_argument, version, timestamp, feature_flags, cohort_id) VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)",
+	return []interface{}{
 		e.Name,
 		e.URL,
 		e.UserID,
@@ -118,11 +172,7 @@ func (l *EventLogStore) Insert(ctx context.Context, e *Event) error {
 		e.Timestamp.UTC(),
 		featureFlags,
 		e.CohortID,
-	)
-	if err != nil {
-		return errors.Wrap(err, "INSERT")
-	}
-	return nil
+	}, nil
 }
 
 func (l *EventLogStore) getBySQL(ctx context.Context, querySuffix *sqlf.Query) ([]*types.Event, error) {
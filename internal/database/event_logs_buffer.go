@@ -0,0 +1,194 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+const (
+	// eventLogBufferCapacity is the number of events that can be buffered before
+	// EventLogBuffer.Insert falls back to inserting events synchronously.
+	eventLogBufferCapacity = 4096
+	// eventLogBatchSize is the number of buffered events which triggers a flush.
+	eventLogBatchSize = 500
+	// eventLogFlushInterval is the maximum time an event stays in the buffer.
+	eventLogFlushInterval = 5 * time.Second
+)
+
+// EventLogBuffer buffers events in memory and inserts them into the event_logs table in
+// batches, so that logging events doesn't cost a round trip to the database per event.
+// It is a background routine: events are flushed by Start, until Stop is called.
+type EventLogBuffer struct {
+	insert        func(context.Context, []*Event) error
+	events        chan *Event
+	batchSize     int
+	flushInterval time.Duration
+
+	mu      sync.RWMutex
+	started bool
+	stopped bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewEventLogBuffer returns a buffer inserting events into the event_logs table of the
+// given database.
+func NewEventLogBuffer(db dbutil.DB) *EventLogBuffer {
+	return newEventLogBuffer(EventLogs(db).BulkInsert, eventLogBufferCapacity, eventLogBatchSize, eventLogFlushInterval)
+}
+
+func newEventLogBuffer(insert func(context.Context, []*Event) error, capacity, batchSize int, flushInterval time.Duration) *EventLogBuffer {
+	return &EventLogBuffer{
+		insert:        insert,
+		events:        make(chan *Event, capacity),
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+}
+
+// Insert adds the event to the buffer. If the buffer is full or stopped, the event is
+// inserted right away instead, which also applies back pressure to the callers.
+func (b *EventLogBuffer) Insert(ctx context.Context, e *Event) error {
+	b.mu.RLock()
+	if !b.stopped {
+		select {
+		case b.events <- e:
+			b.mu.RUnlock()
+			return nil
+		default:
+		}
+	}
+	b.mu.RUnlock()
+
+	return b.insert(ctx, []*Event{e})
+}
+
+// Start flushes the buffered events until Stop is called.
+func (b *EventLogBuffer) Start() {
+	b.mu.Lock()
+	if b.stopped {
+		// Stop has already flushed the buffer.
+		b.mu.Unlock()
+		return
+	}
+	b.started = true
+	b.mu.Unlock()
+	defer close(b.done)
+
+	ticker := time.NewTicker(b.flushInterval)
+	defer ticker.Stop()
+
+	var pending []*Event
+	flush := func() {
+		b.flush(pending)
+		pending = nil
+	}
+
+	for {
+		select {
+		case e := <-b.events:
+			pending = append(pending, e)
+			if len(pending) >= b.batchSize {
+				flush()
+			}
+
+		case <-ticker.C:
+			flush()
+
+		case <-b.stop:
+			// Insert cannot add events anymore, so the buffer can be drained.
+			b.flush(append(pending, b.drain()...))
+			return
+		}
+	}
+}
+
+// Stop stops accepting events into the buffer, and blocks until the buffered events have
+// been flushed.
+func (b *EventLogBuffer) Stop() {
+	b.mu.Lock()
+	if b.stopped {
+		b.mu.Unlock()
+		return
+	}
+	b.stopped = true
+	started := b.started
+	b.mu.Unlock()
+
+	close(b.stop)
+	if started {
+		<-b.done
+	} else {
+		b.flush(b.drain())
+	}
+}
+
+// drain returns the buffered events. It must only be called once Insert cannot add events
+// to the buffer anymore.
+func (b *EventLogBuffer) drain() (events []*Event) {
+	for {
+		select {
+		case e := <-b.events:
+			events = append(events, e)
+		default:
+			return events
+		}
+	}
+}
+
+// flush inserts the given events in batches of at most batchSize events. If inserting a
+// batch fails, e.g. because one of its events violates a constraint of the table, its
+// events are inserted one by one so that only the invalid ones are lost.
+func (b *EventLogBuffer) flush(events []*Event) {
+	for len(events) > b.batchSize {
+		b.flush(events[:b.batchSize])
+		events = events[b.batchSize:]
+	}
+	if len(events) == 0 {
+		return
+	}
+
+	ctx := context.Background()
+	if err := b.insert(ctx, events); err == nil {
+		return
+	}
+	for _, e := range events {
+		if err := b.insert(ctx, []*Event{e}); err != nil {
+			log15.Error("Failed to insert event log", "name", e.Name, "error", err)
+		}
+	}
+}
+
+var (
+	eventLogBufferMu sync.RWMutex
+	eventLogBuffer   *EventLogBuffer
+)
+
+// SetEventLogBuffer sets the buffer used by EventLogStore.InsertAsync. It is set by the
+// frontend, which runs the buffer as one of its background routines.
+func SetEventLogBuffer(b *EventLogBuffer) {
+	eventLogBufferMu.Lock()
+	defer eventLogBufferMu.Unlock()
+	eventLogBuffer = b
+}
+
+// InsertAsync inserts the event through the buffer set by SetEventLogBuffer, so that it
+// is written to the database in a later batch. If no buffer is set, the event is inserted
+// right away.
+func (l *EventLogStore) InsertAsync(ctx context.Context, e *Event) error {
+	eventLogBufferMu.RLock()
+	b := eventLogBuffer
+	eventLogBufferMu.RUnlock()
+
+	if b == nil {
+		return l.Insert(ctx, e)
+	}
+	return b.Insert(ctx, e)
+}
@@ -0,0 +1,118 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeEventLogInserter struct {
+	mu      sync.Mutex
+	batches [][]string
+	// fail makes inserting a batch including the event with this name fail.
+	fail string
+}
+
+func (f *fakeEventLogInserter) insert(_ context.Context, events []*Event) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var names []string
+	for _, e := range events {
+		if e.Name == f.fail {
+			return errors.New("invalid event")
+		}
+		names = append(names, e.Name)
+	}
+	f.batches = append(f.batches, names)
+	return nil
+}
+
+func (f *fakeEventLogInserter) inserted() [][]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([][]string(nil), f.batches...)
+}
+
+func TestEventLogBuffer(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("flushes full batches and on stop", func(t *testing.T) {
+		f := &fakeEventLogInserter{}
+		b := newEventLogBuffer(f.insert, 10, 2, time.Hour)
+		go b.Start()
+
+		for _, name := range []string{"a", "b", "c"} {
+			if err := b.Insert(ctx, &Event{Name: name}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		b.Stop()
+
+		have := f.inserted()
+		if len(have) != 2 || len(have[0]) != 2 || len(have[1]) != 1 || have[1][0] != "c" {
+			t.Errorf("unexpected batches: %v", have)
+		}
+
+		// Events inserted after Stop are inserted right away.
+		if err := b.Insert(ctx, &Event{Name: "d"}); err != nil {
+			t.Fatal(err)
+		}
+		if have := f.inserted(); len(have) != 3 || have[2][0] != "d" {
+			t.Errorf("unexpected batches after stop: %v", have)
+		}
+	})
+
+	t.Run("flushes on interval", func(t *testing.T) {
+		f := &fakeEventLogInserter{}
+		b := newEventLogBuffer(f.insert, 10, 100, 10*time.Millisecond)
+		go b.Start()
+		defer b.Stop()
+
+		if err := b.Insert(ctx, &Event{Name: "a"}); err != nil {
+			t.Fatal(err)
+		}
+		deadline := time.Now().Add(5 * time.Second)
+		for len(f.inserted()) == 0 {
+			if time.Now().After(deadline) {
+				t.Fatal("event not flushed")
+			}
+			time.Sleep(time.Millisecond)
+		}
+	})
+
+	t.Run("inserts right away when full", func(t *testing.T) {
+		f := &fakeEventLogInserter{}
+		// The buffer is not started, so it is full after one event.
+		b := newEventLogBuffer(f.insert, 1, 100, time.Hour)
+
+		for _, name := range []string{"a", "b"} {
+			if err := b.Insert(ctx, &Event{Name: name}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if have := f.inserted(); len(have) != 1 || have[0][0] != "b" {
+			t.Errorf("unexpected batches: %v", have)
+		}
+	})
+
+	t.Run("invalid events don't drop the batch", func(t *testing.T) {
+		f := &fakeEventLogInserter{fail: "invalid"}
+		b := newEventLogBuffer(f.insert, 10, 100, time.Hour)
+		go b.Start()
+
+		for _, name := range []string{"a", "invalid", "b"} {
+			if err := b.Insert(ctx, &Event{Name: name}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		b.Stop()
+
+		have := f.inserted()
+		if len(have) != 2 || have[0][0] != "a" || have[1][0] != "b" {
+			t.Errorf("unexpected batches: %v", have)
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+)
+
+// The event_logs table is partitioned by month of the event timestamp, in UTC. Partitions
+// are created ahead of time by CreatePartitions. Events outside of the existing partitions
+// are stored in the default partition, event_logs_default.
+
+// EventLogPartition is a monthly partition of the event_logs table.
+type EventLogPartition struct {
+	Name string
+	// From is the inclusive lower bound of the timestamps of the partition.
+	From time.Time
+	// To is the exclusive upper bound of the timestamps of the partition.
+	To time.Time
+}
+
+// eventLogPartitionNameFormat is the time layout of the names of the monthly partitions,
+// e.g. event_logs_y2021m10.
+const eventLogPartitionNameFormat = "event_logs_y2006m01"
+
+// eventLogPartitionFor returns the monthly partition containing the given time.
+func eventLogPartitionFor(t time.Time) EventLogPartition {
+	t = t.UTC()
+	from := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return EventLogPartition{
+		Name: from.Format(eventLogPartitionNameFormat),
+		From: from,
+		To:   from.AddDate(0, 1, 0),
+	}
+}
+
+// parseEventLogPartition parses the name of a monthly partition. It returns false for
+// other tables, such as the default partition.
+func parseEventLogPartition(name string) (EventLogPartition, bool) {
+	from, err := time.Parse(eventLogPartitionNameFormat, name)
+	if err != nil {
+		return EventLogPartition{}, false
+	}
+	return eventLogPartitionFor(from), true
+}
+
+// CreatePartitions creates the partitions of the month of now and of the given number of
+// following months, if they don't exist yet.
+func (l *EventLogStore) CreatePartitions(ctx context.Context, now time.Time, ahead int) error {
+	p := eventLogPartitionFor(now)
+	for i := 0; i <= ahead; i++ {
+		if err := l.Exec(ctx, createEventLogPartitionQuery(p)); err != nil {
+			return errors.Wrapf(err, "creating event_logs partition %s", p.Name)
+		}
+		p = eventLogPartitionFor(p.To)
+	}
+	return nil
+}
+
+func createEventLogPartitionQuery(p EventLogPartition) *sqlf.Query {
+	// DDL statements cannot take query arguments, but the name and the bounds are derived
+	// from the timestamps only.
+	return sqlf.Sprintf(fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s PARTITION OF event_logs FOR VALUES FROM ('%s') TO ('%s')",
+		p.Name, p.From.Format(time.RFC3339), p.To.Format(time.RFC3339),
+	))
+}
+
+// ListPartitions returns the monthly partitions of the event_logs table, ordered by time.
+func (l *EventLogStore) ListPartitions(ctx context.Context) ([]EventLogPartition, error) {
+	names, err := basestore.ScanStrings(l.Query(ctx, sqlf.Sprintf(listEventLogPartitionsQuery)))
+	if err != nil {
+		return nil, err
+	}
+
+	var partitions []EventLogPartition
+	for _, name := range names {
+		if p, ok := parseEventLogPartition(name); ok {
+			partitions = append(partitions, p)
+		}
+	}
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i].From.Before(partitions[j].From) })
+	return partitions, nil
+}
+
+const listEventLogPartitionsQuery = `
+-- source: internal/database/event_logs_partitions.go:ListPartitions
+SELECT c.relname
+FROM pg_inherits i
+JOIN pg_class c ON c.oid = i.inhrelid
+WHERE i.inhparent = 'event_logs'::regclass
+`
+
+// DropPartitionsBefore drops the monthly partitions whose events are all older than the
+// given time, and returns them. Dropping a partition is much cheaper than deleting its
+// rows, and immediately returns its storage to the operating system.
+func (l *EventLogStore) DropPartitionsBefore(ctx context.Context, before time.Time) ([]EventLogPartition, error) {
+	partitions, err := l.ListPartitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var dropped []EventLogPartition
+	for _, p := range partitions {
+		if p.To.After(before) {
+			break
+		}
+		// See createEventLogPartitionQuery: the name is derived from the bounds only.
+		if err := l.Exec(ctx, sqlf.Sprintf(fmt.Sprintf("DROP TABLE IF EXISTS %s", p.Name))); err != nil {
+			return dropped, errors.Wrapf(err, "dropping event_logs partition %s", p.Name)
+		}
+		dropped = append(dropped, p)
+	}
+	return dropped, nil
+}
+
+// DeleteOlderThan deletes the events older than the given time that are not in a
+// partition dropped by DropPartitionsBefore, i.e. those in the partition of the month of
+// the given time and in the default partition.
+func (l *EventLogStore) DeleteOlderThan(ctx context.Context, before time.Time) error {
+	return l.Exec(ctx, sqlf.Sprintf(`DELETE FROM event_logs WHERE "timestamp" < %s`, before.UTC()))
+}
@@ -35,17 +35,17 @@ func TestEventLogs_ValidInfo(t *testing.T) {
 		{
 			name:  "EmptyName",
 			event: &Event{UserID: 1, URL: "http://sourcegraph.com", Source: "WEB"},
-			err:   `INSERT: ERROR: new row for relation "event_logs" violates check constraint "event_logs_check_name_not_empty" (SQLSTATE 23514)`,
+			err:   `INSERT: ERROR: new row for relation "event_logs_default" violates check constraint "event_logs_check_name_not_empty" (SQLSTATE 23514)`,
 		},
 		{
 			name:  "InvalidUser",
 			event: &Event{Name: "test_event", URL: "http://sourcegraph.com", Source: "WEB"},
-			err:   `INSERT: ERROR: new row for relation "event_logs" violates check constraint "event_logs_check_has_user" (SQLSTATE 23514)`,
+			err:   `INSERT: ERROR: new row for relation "event_logs_default" violates check constraint "event_logs_check_has_user" (SQLSTATE 23514)`,
 		},
 		{
 			name:  "EmptySource",
 			event: &Event{Name: "test_event", URL: "http://sourcegraph.com", UserID: 1},
-			err:   `INSERT: ERROR: new row for relation "event_logs" violates check constraint "event_logs_check_source_not_empty" (SQLSTATE 23514)`,
+			err:   `INSERT: ERROR: new row for relation "event_logs_default" violates check constraint "event_logs_check_source_not_empty" (SQLSTATE 23514)`,
 		},
 		{
 			name:  "ValidInsert",
@@ -64,6 +64,132 @@ func TestEventLogs_ValidInfo(t *testing.T) {
 	}
 }
 
+func TestEventLogs_BulkInsert(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	ctx := context.Background()
+
+	now := time.Now()
+	events := []*Event{
+		{Name: "a", UserID: 1, Source: "WEB", Timestamp: now},
+		{Name: "b", UserID: 1, Source: "WEB", Timestamp: now.AddDate(0, -1, 0)},
+		{Name: "c", UserID: 2, Source: "WEB", Timestamp: now},
+	}
+	if err := EventLogs(db).BulkInsert(ctx, events); err != nil {
+		t.Fatal(err)
+	}
+	if have, err := EventLogs(db).CountByUserID(ctx, 1); err != nil {
+		t.Fatal(err)
+	} else if have != 2 {
+		t.Errorf("unexpected count: have %d, want 2", have)
+	}
+
+	// A single invalid event fails the whole batch.
+	invalid := []*Event{
+		{Name: "d", UserID: 3, Source: "WEB", Timestamp: now},
+		{Name: "", UserID: 3, Source: "WEB", Timestamp: now},
+	}
+	if err := EventLogs(db).BulkInsert(ctx, invalid); err == nil {
+		t.Error("unexpected nil error")
+	}
+	if have, err := EventLogs(db).CountByUserID(ctx, 3); err != nil {
+		t.Fatal(err)
+	} else if have != 0 {
+		t.Errorf("unexpected count: have %d, want 0", have)
+	}
+}
+
+func TestEventLogPartitionNames(t *testing.T) {
+	p := eventLogPartitionFor(time.Date(2021, 12, 31, 23, 0, 0, 0, time.FixedZone("UTC-2", -2*60*60)))
+	want := EventLogPartition{
+		Name: "event_logs_y2022m01",
+		From: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if diff := cmp.Diff(want, p); diff != "" {
+		t.Errorf("unexpected partition:\n%s", diff)
+	}
+
+	if parsed, ok := parseEventLogPartition(p.Name); !ok {
+		t.Errorf("failed to parse %q", p.Name)
+	} else if diff := cmp.Diff(want, parsed); diff != "" {
+		t.Errorf("unexpected parsed partition:\n%s", diff)
+	}
+	for _, name := range []string{"event_logs_default", "event_logs_y2022", "event_logs_y2022m13"} {
+		if _, ok := parseEventLogPartition(name); ok {
+			t.Errorf("unexpectedly parsed %q", name)
+		}
+	}
+}
+
+func TestEventLogs_Partitions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	ctx := context.Background()
+	store := EventLogs(db)
+
+	// Use months far in the future, which don't overlap with the partitions created by
+	// the migrations.
+	now := time.Date(2100, 5, 15, 0, 0, 0, 0, time.UTC)
+	if err := store.CreatePartitions(ctx, now, 2); err != nil {
+		t.Fatal(err)
+	}
+	// Creating partitions is idempotent.
+	if err := store.CreatePartitions(ctx, now, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	partitions, err := store.ListPartitions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, p := range partitions {
+		if p.From.Year() == 2100 {
+			names = append(names, p.Name)
+		}
+	}
+	if diff := cmp.Diff([]string{"event_logs_y2100m05", "event_logs_y2100m06", "event_logs_y2100m07"}, names); diff != "" {
+		t.Fatalf("unexpected partitions:\n%s", diff)
+	}
+
+	events := []*Event{
+		{Name: "old", UserID: 1, Source: "WEB", Timestamp: now},
+		{Name: "recent", UserID: 1, Source: "WEB", Timestamp: now.AddDate(0, 1, 0)},
+	}
+	if err := store.BulkInsert(ctx, events); err != nil {
+		t.Fatal(err)
+	}
+
+	dropped, err := store.DropPartitionsBefore(ctx, time.Date(2100, 6, 10, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var droppedNames []string
+	for _, p := range dropped {
+		droppedNames = append(droppedNames, p.Name)
+	}
+	if droppedNames[len(droppedNames)-1] != "event_logs_y2100m05" {
+		t.Errorf("unexpected dropped partitions: %v", droppedNames)
+	}
+	if have, err := store.CountByUserIDAndEventName(ctx, 1, "old"); err != nil {
+		t.Fatal(err)
+	} else if have != 0 {
+		t.Errorf("events of dropped partition not deleted")
+	}
+	if have, err := store.CountByUserIDAndEventName(ctx, 1, "recent"); err != nil {
+		t.Fatal(err)
+	} else if have != 1 {
+		t.Errorf("events of kept partition deleted")
+	}
+}
+
 func TestEventLogs_CountUniqueUsersPerPeriod(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
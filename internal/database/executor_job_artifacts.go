@@ -0,0 +1,183 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// ExecutorJobArtifact is a file (such as a log, a patch or a SARIF file) that an executor
+// job uploaded to the upload store.
+type ExecutorJobArtifact struct {
+	ID int
+
+	// QueueName and JobID identify the record the artifact was uploaded for.
+	QueueName string
+	JobID     int
+
+	// Name uniquely identifies the artifact within the job.
+	Name string
+
+	// ObjectKey is the key of the content of the artifact in the upload store.
+	ObjectKey string
+	Size      int64
+
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// ExecutorJobArtifactStore records the metadata of the artifacts uploaded by executor jobs.
+type ExecutorJobArtifactStore struct {
+	*basestore.Store
+}
+
+// ExecutorJobArtifacts instantiates and returns a new ExecutorJobArtifactStore.
+func ExecutorJobArtifacts(db dbutil.DB) *ExecutorJobArtifactStore {
+	return &ExecutorJobArtifactStore{Store: basestore.NewWithDB(db, sql.TxOptions{})}
+}
+
+// ExecutorJobArtifactsWith instantiates and returns a new ExecutorJobArtifactStore using
+// the other store handle.
+func ExecutorJobArtifactsWith(other basestore.ShareableStore) *ExecutorJobArtifactStore {
+	return &ExecutorJobArtifactStore{Store: basestore.NewWithHandle(other.Handle())}
+}
+
+func (s *ExecutorJobArtifactStore) With(other basestore.ShareableStore) *ExecutorJobArtifactStore {
+	return &ExecutorJobArtifactStore{Store: s.Store.With(other)}
+}
+
+func (s *ExecutorJobArtifactStore) Transact(ctx context.Context) (*ExecutorJobArtifactStore, error) {
+	txBase, err := s.Store.Transact(ctx)
+	return &ExecutorJobArtifactStore{Store: txBase}, err
+}
+
+// Upsert records the given artifact and returns it with its generated fields set. An
+// artifact with the same name uploaded earlier for the same job is replaced.
+func (s *ExecutorJobArtifactStore) Upsert(ctx context.Context, a ExecutorJobArtifact) (ExecutorJobArtifact, error) {
+	artifacts, err := scanExecutorJobArtifacts(s.Query(ctx, sqlf.Sprintf(
+		upsertExecutorJobArtifactQuery,
+		a.QueueName,
+		a.JobID,
+		a.Name,
+		a.ObjectKey,
+		a.Size,
+		a.ExpiresAt.UTC(),
+	)))
+	if err != nil || len(artifacts) == 0 {
+		return ExecutorJobArtifact{}, err
+	}
+	return artifacts[0], nil
+}
+
+const upsertExecutorJobArtifactQuery = `
+-- source: internal/database/executor_job_artifacts.go:Upsert
+INSERT INTO executor_job_artifacts (
+	queue_name,
+	job_id,
+	name,
+	object_key,
+	size,
+	expires_at
+)
+VALUES (%s, %s, %s, %s, %s, %s)
+ON CONFLICT (queue_name, job_id, name) DO UPDATE
+SET
+	object_key = EXCLUDED.object_key,
+	size = EXCLUDED.size,
+	created_at = NOW(),
+	expires_at = EXCLUDED.expires_at
+RETURNING ` + executorJobArtifactColumns + `
+`
+
+// GetByID returns the artifact with the given ID. The second return value is false if
+// the artifact does not exist or has expired.
+func (s *ExecutorJobArtifactStore) GetByID(ctx context.Context, id int) (ExecutorJobArtifact, bool, error) {
+	artifacts, err := scanExecutorJobArtifacts(s.Query(ctx, sqlf.Sprintf(getExecutorJobArtifactQuery, id)))
+	if err != nil || len(artifacts) == 0 {
+		return ExecutorJobArtifact{}, false, err
+	}
+	return artifacts[0], true, nil
+}
+
+const getExecutorJobArtifactQuery = `
+-- source: internal/database/executor_job_artifacts.go:GetByID
+SELECT ` + executorJobArtifactColumns + `
+FROM executor_job_artifacts
+WHERE id = %s AND expires_at > NOW()
+`
+
+// ListForJob returns the unexpired artifacts of the given job of the given queue, ordered
+// by name.
+func (s *ExecutorJobArtifactStore) ListForJob(ctx context.Context, queueName string, jobID int) ([]ExecutorJobArtifact, error) {
+	return scanExecutorJobArtifacts(s.Query(ctx, sqlf.Sprintf(listExecutorJobArtifactsForJobQuery, queueName, jobID)))
+}
+
+const listExecutorJobArtifactsForJobQuery = `
+-- source: internal/database/executor_job_artifacts.go:ListForJob
+SELECT ` + executorJobArtifactColumns + `
+FROM executor_job_artifacts
+WHERE queue_name = %s AND job_id = %s AND expires_at > NOW()
+ORDER BY name
+`
+
+// DeleteExpired deletes at most limit artifacts that expired before the given time and
+// returns them, so that their content can be deleted from the upload store.
+func (s *ExecutorJobArtifactStore) DeleteExpired(ctx context.Context, expiredBefore time.Time, limit int) ([]ExecutorJobArtifact, error) {
+	return scanExecutorJobArtifacts(s.Query(ctx, sqlf.Sprintf(deleteExpiredExecutorJobArtifactsQuery, expiredBefore.UTC(), limit)))
+}
+
+const deleteExpiredExecutorJobArtifactsQuery = `
+-- source: internal/database/executor_job_artifacts.go:DeleteExpired
+WITH candidates AS (
+	SELECT id
+	FROM executor_job_artifacts
+	WHERE expires_at <= %s
+	ORDER BY expires_at, id
+	LIMIT %s
+	FOR UPDATE SKIP LOCKED
+)
+DELETE FROM executor_job_artifacts
+WHERE id IN (SELECT id FROM candidates)
+RETURNING ` + executorJobArtifactColumns + `
+`
+
+const executorJobArtifactColumns = `
+	id,
+	queue_name,
+	job_id,
+	name,
+	object_key,
+	size,
+	created_at,
+	expires_at`
+
+func scanExecutorJobArtifacts(rows *sql.Rows, queryErr error) (_ []ExecutorJobArtifact, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var artifacts []ExecutorJobArtifact
+	for rows.Next() {
+		var a ExecutorJobArtifact
+		if err := rows.Scan(
+			&a.ID,
+			&a.QueueName,
+			&a.JobID,
+			&a.Name,
+			&a.ObjectKey,
+			&a.Size,
+			&a.CreatedAt,
+			&a.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, a)
+	}
+	return artifacts, nil
+}
@@ -0,0 +1,107 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+)
+
+func TestExecutorJobArtifacts(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	ctx := context.Background()
+	store := ExecutorJobArtifacts(db)
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	a1 := ExecutorJobArtifact{QueueName: "batches", JobID: 1, Name: "diff.patch", ObjectKey: "key-1", Size: 10, ExpiresAt: now.Add(time.Hour)}
+	a2 := ExecutorJobArtifact{QueueName: "batches", JobID: 1, Name: "build.log", ObjectKey: "key-2", Size: 20, ExpiresAt: now.Add(time.Hour)}
+	a3 := ExecutorJobArtifact{QueueName: "codeintel", JobID: 1, Name: "build.log", ObjectKey: "key-3", Size: 30, ExpiresAt: now.Add(-time.Hour)}
+	for i, a := range []*ExecutorJobArtifact{&a1, &a2, &a3} {
+		created, err := store.Upsert(ctx, *a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if created.ID == 0 {
+			t.Fatalf("artifact %d: expected ID to be set", i)
+		}
+		a.ID = created.ID
+	}
+
+	// Uploading an artifact with the same name again replaces it.
+	a1.ObjectKey = "key-1b"
+	a1.Size = 15
+	updated, err := store.Upsert(ctx, a1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.ID != a1.ID {
+		t.Fatalf("unexpected ID after upsert. want=%d have=%d", a1.ID, updated.ID)
+	}
+
+	ignoreCreatedAt := cmpopts.IgnoreFields(ExecutorJobArtifact{}, "CreatedAt")
+
+	t.Run("GetByID", func(t *testing.T) {
+		have, ok, err := store.GetByID(ctx, a1.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatal("expected artifact to exist")
+		}
+		if diff := cmp.Diff(a1, have, ignoreCreatedAt); diff != "" {
+			t.Fatalf("unexpected artifact (-want +got):\n%s", diff)
+		}
+
+		// Expired artifacts can't be downloaded anymore.
+		if _, ok, err := store.GetByID(ctx, a3.ID); err != nil {
+			t.Fatal(err)
+		} else if ok {
+			t.Fatal("expected expired artifact to not be returned")
+		}
+	})
+
+	t.Run("ListForJob", func(t *testing.T) {
+		have, err := store.ListForJob(ctx, "batches", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]ExecutorJobArtifact{a2, a1}, have, ignoreCreatedAt); diff != "" {
+			t.Fatalf("unexpected artifacts (-want +got):\n%s", diff)
+		}
+
+		have, err = store.ListForJob(ctx, "codeintel", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(have) != 0 {
+			t.Fatalf("expected no unexpired artifacts, got %d", len(have))
+		}
+	})
+
+	t.Run("DeleteExpired", func(t *testing.T) {
+		deleted, err := store.DeleteExpired(ctx, now, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]ExecutorJobArtifact{a3}, deleted, ignoreCreatedAt); diff != "" {
+			t.Fatalf("unexpected deleted artifacts (-want +got):\n%s", diff)
+		}
+
+		deleted, err = store.DeleteExpired(ctx, now, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(deleted) != 0 {
+			t.Fatalf("expected no artifacts to be deleted, got %d", len(deleted))
+		}
+	})
+}
@@ -0,0 +1,247 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// ExecutorInactiveAfter is the duration after which an executor that hasn't sent a
+// heartbeat is considered dead. Executors send a heartbeat every few seconds while they
+// are running.
+const ExecutorInactiveAfter = time.Minute
+
+// Executor is the latest heartbeat of an executor.
+type Executor struct {
+	ID int
+
+	// Name uniquely identifies the executor process. It's the worker hostname of the
+	// jobs the executor dequeued.
+	Name      string
+	Hostname  string
+	QueueName string
+
+	OS              string
+	Architecture    string
+	ExecutorVersion string
+
+	// JobIDs are the jobs of the queue the executor was processing.
+	JobIDs []int
+
+	NumCPUs              int
+	MemoryTotalBytes     int64
+	MemoryAvailableBytes int64
+	LoadAverage          float64
+
+	FirstSeenAt time.Time
+	LastSeenAt  time.Time
+}
+
+// Active returns whether the executor sent a heartbeat within ExecutorInactiveAfter
+// of the given time.
+func (e Executor) Active(now time.Time) bool {
+	return now.Sub(e.LastSeenAt) < ExecutorInactiveAfter
+}
+
+// ExecutorStore records the heartbeats of executors.
+type ExecutorStore struct {
+	*basestore.Store
+}
+
+// Executors instantiates and returns a new ExecutorStore.
+func Executors(db dbutil.DB) *ExecutorStore {
+	return &ExecutorStore{Store: basestore.NewWithDB(db, sql.TxOptions{})}
+}
+
+// ExecutorsWith instantiates and returns a new ExecutorStore using the other store
+// handle.
+func ExecutorsWith(other basestore.ShareableStore) *ExecutorStore {
+	return &ExecutorStore{Store: basestore.NewWithHandle(other.Handle())}
+}
+
+func (s *ExecutorStore) With(other basestore.ShareableStore) *ExecutorStore {
+	return &ExecutorStore{Store: s.Store.With(other)}
+}
+
+func (s *ExecutorStore) Transact(ctx context.Context) (*ExecutorStore, error) {
+	txBase, err := s.Store.Transact(ctx)
+	return &ExecutorStore{Store: txBase}, err
+}
+
+// UpsertHeartbeat records a heartbeat of the given executor. The first seen time of an
+// executor is kept, all other fields are replaced.
+func (s *ExecutorStore) UpsertHeartbeat(ctx context.Context, e Executor) error {
+	jobIDs := make([]int64, 0, len(e.JobIDs))
+	for _, id := range e.JobIDs {
+		jobIDs = append(jobIDs, int64(id))
+	}
+
+	return s.Exec(ctx, sqlf.Sprintf(
+		upsertExecutorHeartbeatQuery,
+		e.Name,
+		e.Hostname,
+		e.QueueName,
+		e.OS,
+		e.Architecture,
+		e.ExecutorVersion,
+		pq.Array(jobIDs),
+		e.NumCPUs,
+		e.MemoryTotalBytes,
+		e.MemoryAvailableBytes,
+		e.LoadAverage,
+	))
+}
+
+const upsertExecutorHeartbeatQuery = `
+-- source: internal/database/executors.go:UpsertHeartbeat
+INSERT INTO executor_heartbeats (
+	name,
+	hostname,
+	queue_name,
+	os,
+	architecture,
+	executor_version,
+	job_ids,
+	num_cpus,
+	memory_total_bytes,
+	memory_available_bytes,
+	load_average,
+	first_seen_at,
+	last_seen_at
+)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, NOW(), NOW())
+ON CONFLICT (name) DO UPDATE
+SET
+	hostname = EXCLUDED.hostname,
+	queue_name = EXCLUDED.queue_name,
+	os = EXCLUDED.os,
+	architecture = EXCLUDED.architecture,
+	executor_version = EXCLUDED.executor_version,
+	job_ids = EXCLUDED.job_ids,
+	num_cpus = EXCLUDED.num_cpus,
+	memory_total_bytes = EXCLUDED.memory_total_bytes,
+	memory_available_bytes = EXCLUDED.memory_available_bytes,
+	load_average = EXCLUDED.load_average,
+	last_seen_at = EXCLUDED.last_seen_at
+`
+
+// ExecutorListOptions filters the executors returned by List and Count.
+type ExecutorListOptions struct {
+	// ActiveOnly restricts the executors to the ones that sent a heartbeat within
+	// ExecutorInactiveAfter.
+	ActiveOnly bool
+	// QueueName restricts the executors to the ones processing the given queue.
+	QueueName string
+	*LimitOffset
+}
+
+func (o ExecutorListOptions) conditions() *sqlf.Query {
+	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if o.ActiveOnly {
+		conds = append(conds, sqlf.Sprintf("last_seen_at >= NOW() - (%s * '1 second'::interval)", int(ExecutorInactiveAfter/time.Second)))
+	}
+	if o.QueueName != "" {
+		conds = append(conds, sqlf.Sprintf("queue_name = %s", o.QueueName))
+	}
+	return sqlf.Join(conds, "AND")
+}
+
+// List returns the executors matching the given options, most recently seen first.
+func (s *ExecutorStore) List(ctx context.Context, opts ExecutorListOptions) ([]Executor, error) {
+	return scanExecutors(s.Query(ctx, sqlf.Sprintf(listExecutorsQuery, opts.conditions(), opts.LimitOffset.SQL())))
+}
+
+const listExecutorsQuery = `
+-- source: internal/database/executors.go:List
+SELECT ` + executorColumns + `
+FROM executor_heartbeats
+WHERE %s
+ORDER BY last_seen_at DESC, id
+%s
+`
+
+// Count returns the number of executors matching the given options, ignoring the
+// LimitOffset.
+func (s *ExecutorStore) Count(ctx context.Context, opts ExecutorListOptions) (int, error) {
+	count, _, err := basestore.ScanFirstInt(s.Query(ctx, sqlf.Sprintf(countExecutorsQuery, opts.conditions())))
+	return count, err
+}
+
+const countExecutorsQuery = `
+-- source: internal/database/executors.go:Count
+SELECT COUNT(*) FROM executor_heartbeats WHERE %s
+`
+
+// DeleteInactiveHeartbeats deletes the heartbeats of the executors that haven't been
+// seen since the given time and returns them, so that their jobs can be requeued.
+func (s *ExecutorStore) DeleteInactiveHeartbeats(ctx context.Context, lastSeenBefore time.Time) ([]Executor, error) {
+	return scanExecutors(s.Query(ctx, sqlf.Sprintf(deleteInactiveExecutorHeartbeatsQuery, lastSeenBefore.UTC())))
+}
+
+const deleteInactiveExecutorHeartbeatsQuery = `
+-- source: internal/database/executors.go:DeleteInactiveHeartbeats
+DELETE FROM executor_heartbeats
+WHERE last_seen_at < %s
+RETURNING ` + executorColumns + `
+`
+
+const executorColumns = `
+	id,
+	name,
+	hostname,
+	queue_name,
+	os,
+	architecture,
+	executor_version,
+	job_ids,
+	num_cpus,
+	memory_total_bytes,
+	memory_available_bytes,
+	load_average,
+	first_seen_at,
+	last_seen_at`
+
+func scanExecutors(rows *sql.Rows, queryErr error) (_ []Executor, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var executors []Executor
+	for rows.Next() {
+		var (
+			e      Executor
+			jobIDs []int64
+		)
+		if err := rows.Scan(
+			&e.ID,
+			&e.Name,
+			&e.Hostname,
+			&e.QueueName,
+			&e.OS,
+			&e.Architecture,
+			&e.ExecutorVersion,
+			pq.Array(&jobIDs),
+			&e.NumCPUs,
+			&e.MemoryTotalBytes,
+			&e.MemoryAvailableBytes,
+			&e.LoadAverage,
+			&e.FirstSeenAt,
+			&e.LastSeenAt,
+		); err != nil {
+			return nil, err
+		}
+		e.JobIDs = make([]int, 0, len(jobIDs))
+		for _, id := range jobIDs {
+			e.JobIDs = append(e.JobIDs, int(id))
+		}
+		executors = append(executors, e)
+	}
+	return executors, nil
+}
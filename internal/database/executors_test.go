@@ -0,0 +1,115 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+)
+
+func TestExecutors(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	ctx := context.Background()
+	store := Executors(db)
+
+	e1 := Executor{
+		Name:                 "executor-1",
+		Hostname:             "host-1",
+		QueueName:            "batches",
+		OS:                   "linux",
+		Architecture:         "amd64",
+		ExecutorVersion:      "1.0.0",
+		JobIDs:               []int{1, 2},
+		NumCPUs:              8,
+		MemoryTotalBytes:     16 << 30,
+		MemoryAvailableBytes: 8 << 30,
+		LoadAverage:          1.5,
+	}
+	e2 := Executor{Name: "executor-2", Hostname: "host-2", QueueName: "codeintel", JobIDs: []int{}}
+	for _, e := range []Executor{e1, e2} {
+		if err := store.UpsertHeartbeat(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A second heartbeat replaces the jobs of the executor.
+	e1.JobIDs = []int{2, 3}
+	if err := store.UpsertHeartbeat(ctx, e1); err != nil {
+		t.Fatal(err)
+	}
+
+	// executor-2 stopped sending heartbeats.
+	if err := store.Exec(ctx, sqlf.Sprintf("UPDATE executor_heartbeats SET last_seen_at = NOW() - '1 hour'::interval WHERE name = %s", e2.Name)); err != nil {
+		t.Fatal(err)
+	}
+
+	ignoreGenerated := cmpopts.IgnoreFields(Executor{}, "ID", "FirstSeenAt", "LastSeenAt")
+
+	t.Run("List", func(t *testing.T) {
+		have, err := store.List(ctx, ExecutorListOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]Executor{e1, e2}, have, ignoreGenerated); diff != "" {
+			t.Fatalf("unexpected executors (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("ListActiveOnly", func(t *testing.T) {
+		have, err := store.List(ctx, ExecutorListOptions{ActiveOnly: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]Executor{e1}, have, ignoreGenerated); diff != "" {
+			t.Fatalf("unexpected executors (-want +got):\n%s", diff)
+		}
+		if !have[0].Active(time.Now()) {
+			t.Error("expected executor to be active")
+		}
+
+		count, err := store.Count(ctx, ExecutorListOptions{ActiveOnly: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 1 {
+			t.Errorf("unexpected count: have %d, want 1", count)
+		}
+	})
+
+	t.Run("ListQueueName", func(t *testing.T) {
+		have, err := store.List(ctx, ExecutorListOptions{QueueName: "codeintel"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]Executor{e2}, have, ignoreGenerated); diff != "" {
+			t.Fatalf("unexpected executors (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("DeleteInactiveHeartbeats", func(t *testing.T) {
+		deleted, err := store.DeleteInactiveHeartbeats(ctx, time.Now().Add(-ExecutorInactiveAfter))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]Executor{e2}, deleted, ignoreGenerated); diff != "" {
+			t.Fatalf("unexpected deleted executors (-want +got):\n%s", diff)
+		}
+
+		count, err := store.Count(ctx, ExecutorListOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 1 {
+			t.Errorf("unexpected count: have %d, want 1", count)
+		}
+	})
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/encryption"
 	"github.com/sourcegraph/sourcegraph/internal/encryption/keyring"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/jsonc"
 	"github.com/sourcegraph/sourcegraph/internal/timeutil"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
@@ -48,6 +49,7 @@ type ExternalServiceStore struct {
 	GitHubValidators          []func(*schema.GitHubConnection) error
 	GitLabValidators          []func(*schema.GitLabConnection, []schema.AuthProviders) error
 	BitbucketServerValidators []func(*schema.BitbucketServerConnection) error
+	GiteaValidators           []func(*schema.GiteaConnection) error
 	PerforceValidators        []func(*schema.PerforceConnection) error
 
 	key encryption.Key
@@ -62,6 +64,7 @@ func (e *ExternalServiceStore) copy() *ExternalServiceStore {
 		GitHubValidators:          e.GitHubValidators,
 		GitLabValidators:          e.GitLabValidators,
 		BitbucketServerValidators: e.BitbucketServerValidators,
+		GiteaValidators:           e.GiteaValidators,
 		PerforceValidators:        e.PerforceValidators,
 	}
 }
@@ -121,8 +124,10 @@ func (e *ExternalServiceStore) ensureStore() {
 // external services.
 var ExternalServiceKinds = map[string]ExternalServiceKind{
 	extsvc.KindAWSCodeCommit:   {CodeHost: true, JSONSchema: schema.AWSCodeCommitSchemaJSON},
+	extsvc.KindAzureDevOps:     {CodeHost: true, JSONSchema: schema.AzureDevOpsSchemaJSON},
 	extsvc.KindBitbucketCloud:  {CodeHost: true, JSONSchema: schema.BitbucketCloudSchemaJSON},
 	extsvc.KindBitbucketServer: {CodeHost: true, JSONSchema: schema.BitbucketServerSchemaJSON},
+	extsvc.KindGitea:           {CodeHost: true, JSONSchema: schema.GiteaSchemaJSON},
 	extsvc.KindGitHub:          {CodeHost: true, JSONSchema: schema.GitHubSchemaJSON},
 	extsvc.KindGitLab:          {CodeHost: true, JSONSchema: schema.GitLabSchemaJSON},
 	extsvc.KindGitolite:        {CodeHost: true, JSONSchema: schema.GitoliteSchemaJSON},
@@ -161,10 +166,66 @@ type ExternalServicesListOptions struct {
 	// When true, will only return services that have the cloud_default flag set to
 	// true.
 	OnlyCloudDefault bool
+	// When specified, only include external services whose display name or code
+	// host URL contains the query, ignoring case.
+	Query string
+	// The column to sort by. Defaults to the ID. Ties are broken by the ID, in the
+	// same direction. AfterID only makes sense when sorting by the ID.
+	OrderBy ExternalServiceOrderBy
 
 	*LimitOffset
 }
 
+// ExternalServiceOrderBy is a column external services can be sorted by.
+type ExternalServiceOrderBy string
+
+const (
+	ExternalServiceOrderByID          ExternalServiceOrderBy = "id"
+	ExternalServiceOrderByDisplayName ExternalServiceOrderBy = "display_name"
+	ExternalServiceOrderByKind        ExternalServiceOrderBy = "kind"
+	ExternalServiceOrderByCreatedAt   ExternalServiceOrderBy = "created_at"
+	ExternalServiceOrderByLastSyncAt  ExternalServiceOrderBy = "last_sync_at"
+)
+
+// sqlOrderBy returns the ORDER BY clause for the options.
+func (o ExternalServicesListOptions) sqlOrderBy() (*sqlf.Query, error) {
+	direction := "DESC"
+	if o.OrderByDirection == "ASC" {
+		direction = "ASC"
+	}
+
+	switch o.OrderBy {
+	case "", ExternalServiceOrderByID:
+		return sqlf.Sprintf("ORDER BY id " + direction), nil
+	case ExternalServiceOrderByDisplayName, ExternalServiceOrderByKind, ExternalServiceOrderByCreatedAt:
+		return sqlf.Sprintf("ORDER BY " + string(o.OrderBy) + " " + direction + ", id " + direction), nil
+	case ExternalServiceOrderByLastSyncAt:
+		// External services that were never synced come last, either way.
+		return sqlf.Sprintf("ORDER BY last_sync_at " + direction + " NULLS LAST, id " + direction), nil
+	}
+	return nil, errors.Errorf("invalid external service order by %q", o.OrderBy)
+}
+
+// matchesQuery reports whether the display name or the code host URL of the
+// external service contains the query of the options, ignoring case.
+func (o ExternalServicesListOptions) matchesQuery(svc *types.ExternalService) bool {
+	query := strings.ToLower(o.Query)
+	if strings.Contains(strings.ToLower(svc.DisplayName), query) {
+		return true
+	}
+
+	// AWS CodeCommit connections have no URL, their identifier contains the access
+	// key ID instead, which we don't want to match.
+	if svc.Kind == extsvc.KindAWSCodeCommit {
+		return false
+	}
+	codeHostURL, err := extsvc.UniqueCodeHostIdentifier(svc.Kind, svc.Config)
+	if err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(codeHostURL), query)
+}
+
 func (o ExternalServicesListOptions) sqlConditions() []*sqlf.Query {
 	conds := []*sqlf.Query{sqlf.Sprintf("deleted_at IS NULL")}
 	if len(o.IDs) > 0 {
@@ -252,19 +313,20 @@ func (e *ExternalServiceStore) ValidateConfig(ctx context.Context, opt ValidateE
 
 	// For user-added external services, we need to prevent them from using disallowed fields.
 	if opt.NamespaceUserID > 0 {
-		// We do not allow users to add external service other than GitHub.com and GitLab.com
+		// We do not allow users to add external service other than GitHub.com, GitLab.com,
+		// Azure DevOps Services and the code hosts of the GitHub and GitLab auth providers.
 		result := gjson.GetBytes(normalized, "url")
 		baseURL, err := url.Parse(result.String())
 		if err != nil {
 			return nil, errors.Wrap(err, "parse base URL")
 		}
 		normalizedURL := extsvc.NormalizeBaseURL(baseURL).String()
-		if normalizedURL != "https://github.com/" &&
-			normalizedURL != "https://gitlab.com/" {
-			return nil, errors.New("users are only allowed to add external service for https://github.com/ and https://gitlab.com/")
+		allowed := userCodeHostURLs(opt.Kind, opt.AuthProviders)
+		if !containsString(allowed, normalizedURL) {
+			return nil, errors.Errorf("users are only allowed to add external service for %s", joinURLs(allowed))
 		}
 
-		disallowedFields := []string{"repositoryPathPattern", "nameTransformations", "rateLimit"}
+		disallowedFields := []string{"repositoryPathPattern", "nameTransformations", "rateLimit", "gitHubApp"}
 		results := gjson.GetManyBytes(normalized, disallowedFields...)
 		for i, r := range results {
 			if r.Exists() {
@@ -272,8 +334,8 @@ func (e *ExternalServiceStore) ValidateConfig(ctx context.Context, opt ValidateE
 			}
 		}
 
-		// A user can only create one external service per kind
-		if err := e.validateSingleKindPerUser(ctx, opt.ExternalServiceID, opt.Kind, opt.NamespaceUserID); err != nil {
+		// A user can only create one external service per code host
+		if err := e.validateSingleCodeHostPerUser(ctx, opt.ExternalServiceID, opt.Kind, normalizedURL, opt.NamespaceUserID); err != nil {
 			return nil, err
 		}
 	}
@@ -322,6 +384,20 @@ func (e *ExternalServiceStore) ValidateConfig(ctx context.Context, opt ValidateE
 		}
 		err = e.validateBitbucketCloudConnection(ctx, opt.ExternalServiceID, &c)
 
+	case extsvc.KindGitea:
+		var c schema.GiteaConnection
+		if err = jsoniter.Unmarshal(normalized, &c); err != nil {
+			return nil, err
+		}
+		err = e.validateGiteaConnection(ctx, opt.ExternalServiceID, &c)
+
+	case extsvc.KindAzureDevOps:
+		var c schema.AzureDevOpsConnection
+		if err = jsoniter.Unmarshal(normalized, &c); err != nil {
+			return nil, err
+		}
+		err = e.validateAzureDevOpsConnection(ctx, opt.ExternalServiceID, &c)
+
 	case extsvc.KindPerforce:
 		var c schema.PerforceConnection
 		if err = jsoniter.Unmarshal(normalized, &c); err != nil {
@@ -379,6 +455,24 @@ func (e *ExternalServiceStore) validateGitHubConnection(ctx context.Context, id
 		err = multierror.Append(err, errors.New("at least one of repositoryQuery, repos or orgs must be set"))
 	}
 
+	if c.GitHubApp != nil {
+		if _, perr := github.ParseAppPrivateKey(c.GitHubApp.PrivateKey); perr != nil {
+			err = multierror.Append(err, errors.Wrap(perr, "gitHubApp.privateKey"))
+		}
+		// Repository permissions are synced with the token of the connection,
+		// which GitHub App connections don't have.
+		if c.Authorization != nil {
+			err = multierror.Append(err, errors.New("authorization is not supported for connections authenticating with gitHubApp"))
+		}
+		// Installation access tokens don't belong to a user, so there are no
+		// affiliated repositories.
+		for _, q := range c.RepositoryQuery {
+			if q == "affiliated" {
+				err = multierror.Append(err, errors.New(`repositoryQuery "affiliated" is not supported for connections authenticating with gitHubApp`))
+			}
+		}
+	}
+
 	err = multierror.Append(err, e.validateDuplicateRateLimits(ctx, id, extsvc.KindGitHub, c))
 
 	return err.ErrorOrNil()
@@ -414,6 +508,33 @@ func (e *ExternalServiceStore) validateBitbucketCloudConnection(ctx context.Cont
 	return e.validateDuplicateRateLimits(ctx, id, extsvc.KindBitbucketCloud, c)
 }
 
+func (e *ExternalServiceStore) validateGiteaConnection(ctx context.Context, id int64, c *schema.GiteaConnection) error {
+	err := new(multierror.Error)
+	for _, validate := range e.GiteaValidators {
+		err = multierror.Append(err, validate(c))
+	}
+
+	if c.Orgs == nil && c.Repos == nil {
+		err = multierror.Append(err, errors.New("at least one of orgs or repos must be set"))
+	}
+
+	err = multierror.Append(err, e.validateDuplicateRateLimits(ctx, id, extsvc.KindGitea, c))
+
+	return err.ErrorOrNil()
+}
+
+func (e *ExternalServiceStore) validateAzureDevOpsConnection(ctx context.Context, id int64, c *schema.AzureDevOpsConnection) error {
+	err := new(multierror.Error)
+
+	if c.Orgs == nil && c.Projects == nil {
+		err = multierror.Append(err, errors.New("at least one of orgs or projects must be set"))
+	}
+
+	err = multierror.Append(err, e.validateDuplicateRateLimits(ctx, id, extsvc.KindAzureDevOps, c))
+
+	return err.ErrorOrNil()
+}
+
 func (e *ExternalServiceStore) validatePerforceConnection(ctx context.Context, id int64, c *schema.PerforceConnection) error {
 	err := new(multierror.Error)
 	for _, validate := range e.PerforceValidators {
@@ -477,8 +598,9 @@ func (e *ExternalServiceStore) validateDuplicateRateLimits(ctx context.Context,
 	return nil
 }
 
-// validateSingleKindPerUser returns an error if the user attempts to add more than one external service of the same kind.
-func (e *ExternalServiceStore) validateSingleKindPerUser(ctx context.Context, id int64, kind string, userID int32) error {
+// validateSingleCodeHostPerUser returns an error if the user attempts to add more than one external service
+// of the same kind for the same code host. baseURL must be normalized with extsvc.NormalizeBaseURL.
+func (e *ExternalServiceStore) validateSingleCodeHostPerUser(ctx context.Context, id int64, kind, baseURL string, userID int32) error {
 	opt := ExternalServicesListOptions{
 		Kinds: []string{kind},
 		LimitOffset: &LimitOffset{
@@ -496,10 +618,18 @@ func (e *ExternalServiceStore) validateSingleKindPerUser(ctx context.Context, id
 		}
 		opt.AfterID = svcs[len(svcs)-1].ID // Advance the cursor
 
-		// Fail if a service already exists that is not the current service
+		// Fail if a service for the same code host already exists that is not the
+		// current service
 		for _, svc := range svcs {
-			if svc.ID != id {
-				return errors.Errorf("existing external service, %q, of same kind already added", svc.DisplayName)
+			if svc.ID == id {
+				continue
+			}
+			svcURL, err := extsvc.UniqueCodeHostIdentifier(svc.Kind, svc.Config)
+			if err != nil {
+				return errors.Wrapf(err, "getting code host of external service %d", svc.ID)
+			}
+			if svcURL == baseURL {
+				return errors.Errorf("existing external service, %q, of same kind and URL already added", svc.DisplayName)
 			}
 		}
 		if len(svcs) < opt.Limit {
@@ -509,6 +639,51 @@ func (e *ExternalServiceStore) validateSingleKindPerUser(ctx context.Context, id
 	return nil
 }
 
+// userCodeHostURLs returns the normalized base URLs of the code hosts users are
+// allowed to add external services of the given kind for. Besides the public
+// code hosts, these are the code hosts of the GitHub and GitLab auth providers,
+// so that users can connect e.g. both GitHub.com and GitHub Enterprise.
+func userCodeHostURLs(kind string, ps []schema.AuthProviders) []string {
+	urls := []string{"https://github.com/", "https://gitlab.com/", "https://dev.azure.com/"}
+	for _, p := range ps {
+		var rawURL string
+		switch {
+		case p.Github != nil && kind == extsvc.KindGitHub:
+			rawURL = p.Github.Url
+		case p.Gitlab != nil && kind == extsvc.KindGitLab:
+			rawURL = p.Gitlab.Url
+		}
+		if rawURL == "" {
+			continue
+		}
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			continue
+		}
+		if normalized := extsvc.NormalizeBaseURL(u).String(); !containsString(urls, normalized) {
+			urls = append(urls, normalized)
+		}
+	}
+	return urls
+}
+
+// joinURLs joins urls into a human readable list, e.g. "a, b and c".
+func joinURLs(urls []string) string {
+	if len(urls) < 2 {
+		return strings.Join(urls, "")
+	}
+	return strings.Join(urls[:len(urls)-1], ", ") + " and " + urls[len(urls)-1]
+}
+
+func containsString(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
 // upsertAuthorizationToExternalService adds "authorization" field to the
 // external service config when not yet present for GitHub and GitLab.
 func upsertAuthorizationToExternalService(kind, config string) (string, error) {
@@ -724,6 +899,7 @@ func (e *ExternalServiceStore) Upsert(ctx context.Context, svcs ...*types.Extern
 			&svcs[i].CloudDefault,
 			&encryptionKeyID,
 			&dbutil.NullInt32{N: &svcs[i].NamespaceOrgID},
+			&svcs[i].TokenShared,
 		)
 		if err != nil {
 			return err
@@ -821,7 +997,8 @@ RETURNING
 	unrestricted,
 	cloud_default,
 	encryption_key_id,
-	namespace_org_id
+	namespace_org_id,
+	token_shared
 `
 
 // ExternalServiceUpdate contains optional fields to update.
@@ -1157,6 +1334,86 @@ ORDER BY es.id, essj.finished_at DESC
 	return messages, nil
 }
 
+// RecordSyncStats records the outcome of a sync of an external service, so
+// that it's included in the statistics returned by GetSyncStatistics.
+func (e *ExternalServiceStore) RecordSyncStats(ctx context.Context, stats *types.ExternalServiceSyncStats) error {
+	e.ensureStore()
+
+	q := sqlf.Sprintf(`
+-- source: internal/database/external_services.go:RecordSyncStats
+INSERT INTO external_service_sync_stats (
+	external_service_id, started_at, finished_at, repos_added, repos_removed, repos_errored, api_requests, errored, backoff_reason
+)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s)
+RETURNING id
+`,
+		stats.ExternalServiceID,
+		stats.StartedAt,
+		stats.FinishedAt,
+		stats.ReposAdded,
+		stats.ReposRemoved,
+		stats.ReposErrored,
+		stats.APIRequests,
+		stats.Errored,
+		string(stats.BackoffReason),
+	)
+
+	return e.QueryRow(ctx, q).Scan(&stats.ID)
+}
+
+// GetSyncStatistics aggregates the stats of the syncs of the given external
+// service that finished within the given window before now.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin or owner of the external service
+func (e *ExternalServiceStore) GetSyncStatistics(ctx context.Context, id int64, window time.Duration) (*types.ExternalServiceSyncStatistics, error) {
+	if Mocks.ExternalServices.GetSyncStatistics != nil {
+		return Mocks.ExternalServices.GetSyncStatistics(id, window)
+	}
+	e.ensureStore()
+
+	q := sqlf.Sprintf(`
+-- source: internal/database/external_services.go:GetSyncStatistics
+SELECT
+	COUNT(*),
+	COUNT(*) FILTER (WHERE errored),
+	COALESCE(AVG(EXTRACT(EPOCH FROM finished_at - started_at)), 0),
+	COALESCE(MAX(EXTRACT(EPOCH FROM finished_at - started_at)), 0),
+	COALESCE(SUM(repos_added), 0),
+	COALESCE(SUM(repos_removed), 0),
+	COALESCE(SUM(repos_errored), 0),
+	COALESCE(SUM(api_requests), 0),
+	MAX(finished_at) FILTER (WHERE errored)
+FROM external_service_sync_stats
+WHERE external_service_id = %s
+AND finished_at > now() - (%s * interval '1 second')
+`, id, window.Seconds())
+
+	var (
+		stats         types.ExternalServiceSyncStatistics
+		avg, max      float64
+		lastErroredAt time.Time
+	)
+	err := e.QueryRow(ctx, q).Scan(
+		&stats.Syncs,
+		&stats.ErroredSyncs,
+		&avg,
+		&max,
+		&stats.ReposAdded,
+		&stats.ReposRemoved,
+		&stats.ReposErrored,
+		&stats.APIRequests,
+		&dbutil.NullTime{Time: &lastErroredAt},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.AverageDuration = time.Duration(avg * float64(time.Second))
+	stats.MaxDuration = time.Duration(max * float64(time.Second))
+	stats.LastErroredAt = lastErroredAt
+	return &stats, nil
+}
+
 // List returns external services under given namespace.
 // If no namespace is given, it returns all external services.
 //
@@ -1198,18 +1455,28 @@ func (e *ExternalServiceStore) list(ctx context.Context, opt ExternalServicesLis
 	span, _ := ot.StartSpanFromContext(ctx, "ExternalServiceStore.list")
 	defer span.Finish()
 
-	if opt.OrderByDirection != "ASC" {
-		opt.OrderByDirection = "DESC"
+	orderBy, err := opt.sqlOrderBy()
+	if err != nil {
+		return nil, err
+	}
+
+	// The code host URL is part of the config, which may be encrypted, so we can
+	// only match the query after decrypting the configs. In that case we paginate
+	// after matching too.
+	limitOffset := opt.LimitOffset
+	if opt.Query != "" {
+		limitOffset = nil
 	}
 
 	q := sqlf.Sprintf(`
-		SELECT id, kind, display_name, config, encryption_key_id, created_at, updated_at, deleted_at, last_sync_at, next_sync_at, namespace_user_id, namespace_org_id, unrestricted, cloud_default
+		SELECT id, kind, display_name, config, encryption_key_id, created_at, updated_at, deleted_at, last_sync_at, next_sync_at, namespace_user_id, namespace_org_id, unrestricted, cloud_default, token_shared
 		FROM external_services
 		WHERE (%s)
-		ORDER BY id `+opt.OrderByDirection+`
+		%s
 		%s`,
 		sqlf.Join(opt.sqlConditions(), ") AND ("),
-		opt.LimitOffset.SQL(),
+		orderBy,
+		limitOffset.SQL(),
 	)
 
 	rows, err := e.Query(ctx, q)
@@ -1231,7 +1498,7 @@ func (e *ExternalServiceStore) list(ctx context.Context, opt ExternalServicesLis
 			namespaceOrgID  sql.NullInt32
 			keyID           string
 		)
-		if err := rows.Scan(&h.ID, &h.Kind, &h.DisplayName, &h.Config, &keyID, &h.CreatedAt, &h.UpdatedAt, &deletedAt, &lastSyncAt, &nextSyncAt, &namespaceUserID, &namespaceOrgID, &h.Unrestricted, &h.CloudDefault); err != nil {
+		if err := rows.Scan(&h.ID, &h.Kind, &h.DisplayName, &h.Config, &keyID, &h.CreatedAt, &h.UpdatedAt, &deletedAt, &lastSyncAt, &nextSyncAt, &namespaceUserID, &namespaceOrgID, &h.Unrestricted, &h.CloudDefault, &h.TokenShared); err != nil {
 			return nil, err
 		}
 
@@ -1280,9 +1547,36 @@ func (e *ExternalServiceStore) list(ctx context.Context, opt ExternalServicesLis
 		return nil, err
 	}
 
+	if opt.Query != "" {
+		results = filterExternalServices(results, opt)
+	}
+
 	return results, nil
 }
 
+// filterExternalServices returns the external services matching the query of
+// the options, limited to the page of the options.
+func filterExternalServices(svcs []*types.ExternalService, opt ExternalServicesListOptions) []*types.ExternalService {
+	matches := svcs[:0]
+	for _, svc := range svcs {
+		if opt.matchesQuery(svc) {
+			matches = append(matches, svc)
+		}
+	}
+
+	if opt.LimitOffset == nil {
+		return matches
+	}
+	if opt.Offset >= len(matches) {
+		return nil
+	}
+	matches = matches[opt.Offset:]
+	if opt.Limit > 0 && opt.Limit < len(matches) {
+		matches = matches[:opt.Limit]
+	}
+	return matches
+}
+
 // Count counts all external services that satisfy the options (ignoring limit and offset).
 //
 // 🚨 SECURITY: The caller must ensure that the actor is a site admin or owner of the external service.
@@ -1292,6 +1586,13 @@ func (e *ExternalServiceStore) Count(ctx context.Context, opt ExternalServicesLi
 	}
 	e.ensureStore()
 
+	// Matching the query requires the decrypted configs, see list.
+	if opt.Query != "" {
+		opt.LimitOffset = nil
+		svcs, err := e.list(ctx, opt)
+		return len(svcs), err
+	}
+
 	q := sqlf.Sprintf("SELECT COUNT(*) FROM external_services WHERE (%s)", sqlf.Join(opt.sqlConditions(), ") AND ("))
 	var count int
 	if err := e.QueryRow(ctx, q).Scan(&count); err != nil {
@@ -1317,6 +1618,86 @@ func (e *ExternalServiceStore) RepoCount(ctx context.Context, id int64) (int32,
 	return count, nil
 }
 
+// ConfirmRepoDeletion allows the next sync of the external service to delete
+// more repositories than the deletion threshold of the syncer.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin
+func (e *ExternalServiceStore) ConfirmRepoDeletion(ctx context.Context, id int64) error {
+	if Mocks.ExternalServices.ConfirmRepoDeletion != nil {
+		return Mocks.ExternalServices.ConfirmRepoDeletion(ctx, id)
+	}
+	e.ensureStore()
+
+	q := sqlf.Sprintf(`
+-- source: internal/database/external_services.go:ConfirmRepoDeletion
+UPDATE external_services SET repo_deletion_confirmed = true WHERE id = %s AND deleted_at IS NULL
+`, id)
+	res, err := e.ExecResult(ctx, q)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return externalServiceNotFoundError{id: id}
+	}
+	return nil
+}
+
+// ConsumeRepoDeletionConfirmation returns whether the deletion of repositories
+// exceeding the deletion threshold was confirmed with ConfirmRepoDeletion, and
+// resets the confirmation so that it only applies to a single sync.
+func (e *ExternalServiceStore) ConsumeRepoDeletionConfirmation(ctx context.Context, id int64) (confirmed bool, err error) {
+	e.ensureStore()
+
+	q := sqlf.Sprintf(`
+-- source: internal/database/external_services.go:ConsumeRepoDeletionConfirmation
+UPDATE external_services SET repo_deletion_confirmed = false WHERE id = %s AND repo_deletion_confirmed
+`, id)
+	res, err := e.ExecResult(ctx, q)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// SetTokenShared sets whether the token of the organization external service
+// with the given id is shared with all members of the organization. Members
+// of the organization get read access to the repositories synced by an external
+// service whose token is shared, like the user owning a user external service.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is an admin of the
+// organization owning the external service or a site admin.
+func (e *ExternalServiceStore) SetTokenShared(ctx context.Context, id int64, shared bool) error {
+	if Mocks.ExternalServices.SetTokenShared != nil {
+		return Mocks.ExternalServices.SetTokenShared(ctx, id, shared)
+	}
+	e.ensureStore()
+
+	q := sqlf.Sprintf(`
+-- source: internal/database/external_services.go:SetTokenShared
+UPDATE external_services SET token_shared = %s WHERE id = %s AND namespace_org_id IS NOT NULL AND deleted_at IS NULL
+`, shared, id)
+	res, err := e.ExecResult(ctx, q)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return externalServiceNotFoundError{id: id}
+	}
+	return nil
+}
+
 // SyncDue returns true if any of the supplied external services are due to sync
 // now or within given duration from now.
 func (e *ExternalServiceStore) SyncDue(ctx context.Context, intIDs []int64, d time.Duration) (bool, error) {
@@ -1355,17 +1736,85 @@ WHERE EXISTS(
 	return v && exists, nil
 }
 
+// NextSyncInfo returns when the given external services are synced next, in
+// order of their IDs: when they are due, whether a sync job is queued or
+// processing, and why the syncer backed off after their last sync, if it did.
+// External services that don't exist are omitted.
+func (e *ExternalServiceStore) NextSyncInfo(ctx context.Context, ids []int64) (infos []*types.ExternalServiceNextSyncInfo, err error) {
+	if Mocks.ExternalServices.NextSyncInfo != nil {
+		return Mocks.ExternalServices.NextSyncInfo(ctx, ids)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	e.ensureStore()
+
+	q := sqlf.Sprintf(`
+-- source: internal/database/external_services.go:NextSyncInfo
+SELECT
+	es.id,
+	es.next_sync_at,
+	COALESCE(job.state, ''),
+	COALESCE(stats.backoff_reason, '')
+FROM external_services es
+LEFT JOIN LATERAL (
+	SELECT state
+	FROM external_service_sync_jobs
+	WHERE external_service_id = es.id AND state IN ('queued', 'processing')
+	ORDER BY state = 'processing' DESC, id
+	LIMIT 1
+) job ON TRUE
+LEFT JOIN LATERAL (
+	SELECT backoff_reason
+	FROM external_service_sync_stats
+	WHERE external_service_id = es.id
+	ORDER BY finished_at DESC
+	LIMIT 1
+) stats ON TRUE
+WHERE es.id = ANY(%s) AND es.deleted_at IS NULL
+ORDER BY es.id
+`, pq.Array(ids))
+
+	rows, err := e.Query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	for rows.Next() {
+		var (
+			info          types.ExternalServiceNextSyncInfo
+			backoffReason string
+		)
+		if err := rows.Scan(
+			&info.ExternalServiceID,
+			&dbutil.NullTime{Time: &info.NextSyncAt},
+			&info.SyncJobState,
+			&backoffReason,
+		); err != nil {
+			return nil, err
+		}
+		info.BackoffReason = types.ExternalServiceSyncBackoffReason(backoffReason)
+		infos = append(infos, &info)
+	}
+	return infos, nil
+}
+
 // MockExternalServices mocks the external services store.
 type MockExternalServices struct {
-	Create           func(ctx context.Context, confGet func() *conf.Unified, externalService *types.ExternalService) error
-	Delete           func(ctx context.Context, id int64) error
-	GetByID          func(id int64) (*types.ExternalService, error)
-	GetLastSyncError func(id int64) (string, error)
-	ListSyncErrors   func(ctx context.Context) (map[int64]string, error)
-	List             func(opt ExternalServicesListOptions) ([]*types.ExternalService, error)
-	Update           func(ctx context.Context, ps []schema.AuthProviders, id int64, update *ExternalServiceUpdate) error
-	Count            func(ctx context.Context, opt ExternalServicesListOptions) (int, error)
-	Upsert           func(ctx context.Context, services ...*types.ExternalService) error
-	Transact         func(ctx context.Context) (*ExternalServiceStore, error)
-	Done             func(error) error
+	Create              func(ctx context.Context, confGet func() *conf.Unified, externalService *types.ExternalService) error
+	ConfirmRepoDeletion func(ctx context.Context, id int64) error
+	SetTokenShared      func(ctx context.Context, id int64, shared bool) error
+	Delete              func(ctx context.Context, id int64) error
+	GetByID             func(id int64) (*types.ExternalService, error)
+	GetLastSyncError    func(id int64) (string, error)
+	GetSyncStatistics   func(id int64, window time.Duration) (*types.ExternalServiceSyncStatistics, error)
+	ListSyncErrors      func(ctx context.Context) (map[int64]string, error)
+	NextSyncInfo        func(ctx context.Context, ids []int64) ([]*types.ExternalServiceNextSyncInfo, error)
+	List                func(opt ExternalServicesListOptions) ([]*types.ExternalService, error)
+	Update              func(ctx context.Context, ps []schema.AuthProviders, id int64, update *ExternalServiceUpdate) error
+	Count               func(ctx context.Context, opt ExternalServicesListOptions) (int, error)
+	Upsert              func(ctx context.Context, services ...*types.ExternalService) error
+	Transact            func(ctx context.Context) (*ExternalServiceStore, error)
+	Done                func(error) error
 }
@@ -3,9 +3,11 @@ package database
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 
 	"github.com/cockroachdb/errors"
 	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
@@ -40,17 +42,20 @@ func (f *FeatureFlagStore) CreateFeatureFlag(ctx context.Context, flag *ff.Featu
 			flag_name,
 			flag_type,
 			bool_value,
-			rollout
+			rollout,
+			rules
 		) VALUES (
 			%s,
 			%s,
 			%s,
+			%s,
 			%s
 		) RETURNING
 			flag_name,
 			flag_type,
 			bool_value,
 			rollout,
+			rules,
 			created_at,
 			updated_at,
 			deleted_at
@@ -72,12 +77,18 @@ func (f *FeatureFlagStore) CreateFeatureFlag(ctx context.Context, flag *ff.Featu
 		return nil, errors.New("feature flag must have exactly one type")
 	}
 
+	rules, err := marshalFeatureFlagRules(flag.Rules)
+	if err != nil {
+		return nil, err
+	}
+
 	row := f.QueryRow(ctx, sqlf.Sprintf(
 		newFeatureFlagFmtStr,
 		flag.Name,
 		flagType,
 		boolVal,
-		rollout))
+		rollout,
+		rules))
 	return scanFeatureFlag(row)
 }
 
@@ -94,6 +105,7 @@ func (f *FeatureFlagStore) UpdateFeatureFlag(ctx context.Context, flag *ff.Featu
 			flag_type,
 			bool_value,
 			rollout,
+			rules,
 			created_at,
 			updated_at,
 			deleted_at
@@ -125,6 +137,49 @@ func (f *FeatureFlagStore) UpdateFeatureFlag(ctx context.Context, flag *ff.Featu
 	return scanFeatureFlag(row)
 }
 
+// SetFeatureFlagRules replaces the rules of the given feature flag. Rules are managed
+// separately from the flag's type and value, so that updating those keeps the rules.
+func (f *FeatureFlagStore) SetFeatureFlagRules(ctx context.Context, flagName string, rules []ff.FeatureFlagRule) (*ff.FeatureFlag, error) {
+	const setFeatureFlagRulesFmtStr = `
+		UPDATE feature_flags
+		SET
+			rules = %s,
+			updated_at = now()
+		WHERE flag_name = %s
+			AND deleted_at IS NULL
+		RETURNING
+			flag_name,
+			flag_type,
+			bool_value,
+			rollout,
+			rules,
+			created_at,
+			updated_at,
+			deleted_at
+		;
+	`
+
+	encoded, err := marshalFeatureFlagRules(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	row := f.QueryRow(ctx, sqlf.Sprintf(setFeatureFlagRulesFmtStr, encoded, flagName))
+	return scanFeatureFlag(row)
+}
+
+func marshalFeatureFlagRules(rules []ff.FeatureFlagRule) ([]byte, error) {
+	for i := range rules {
+		if err := rules[i].Validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid rule %d", i)
+		}
+	}
+	if rules == nil {
+		rules = []ff.FeatureFlagRule{}
+	}
+	return json.Marshal(rules)
+}
+
 func (f *FeatureFlagStore) DeleteFeatureFlag(ctx context.Context, name string) error {
 	const deleteFeatureFlagFmtStr = `
 		UPDATE feature_flags
@@ -168,12 +223,14 @@ func scanFeatureFlag(scanner rowScanner) (*ff.FeatureFlag, error) {
 		flagType string
 		boolVal  *bool
 		rollout  *int32
+		rules    []byte
 	)
 	err := scanner.Scan(
 		&res.Name,
 		&flagType,
 		&boolVal,
 		&rollout,
+		&rules,
 		&res.CreatedAt,
 		&res.UpdatedAt,
 		&res.DeletedAt,
@@ -201,6 +258,13 @@ func scanFeatureFlag(scanner rowScanner) (*ff.FeatureFlag, error) {
 		return nil, ErrInvalidColumnState
 	}
 
+	if err := json.Unmarshal(rules, &res.Rules); err != nil {
+		return nil, errors.Wrap(err, "decoding feature flag rules")
+	}
+	if len(res.Rules) == 0 {
+		res.Rules = nil
+	}
+
 	return &res, nil
 }
 
@@ -211,6 +275,7 @@ func (f *FeatureFlagStore) GetFeatureFlag(ctx context.Context, flagName string)
 			flag_type,
 			bool_value,
 			rollout,
+			rules,
 			created_at,
 			updated_at,
 			deleted_at
@@ -230,6 +295,7 @@ func (f *FeatureFlagStore) GetFeatureFlags(ctx context.Context) ([]*ff.FeatureFl
 			flag_type,
 			bool_value,
 			rollout,
+			rules,
 			created_at,
 			updated_at,
 			deleted_at
@@ -452,55 +518,133 @@ func scanFeatureFlagOverride(scanner rowScanner) (*ff.Override, error) {
 	return &res, err
 }
 
-// GetUserFlags returns the calculated values for feature flags for the given userID. This should
-// be the primary entrypoint for getting the user flags since it handles retrieving all the flags,
-// the org overrides, and the user overrides, and merges them in priority order.
-func (f *FeatureFlagStore) GetUserFlags(ctx context.Context, userID int32) (map[string]bool, error) {
-	g, ctx := errgroup.WithContext(ctx)
+// GetUserAttributes returns the attributes of the given user that feature flag rules are
+// evaluated against.
+func (f *FeatureFlagStore) GetUserAttributes(ctx context.Context, userID int32) (*ff.UserAttributes, error) {
+	const getUserAttributesFmtStr = `
+		SELECT
+			users.site_admin,
+			ARRAY(
+				SELECT org_id
+				FROM org_members
+				WHERE org_members.user_id = users.id
+			),
+			ARRAY(
+				SELECT DISTINCT lower(split_part(email, '@', 2))
+				FROM user_emails
+				WHERE user_emails.user_id = users.id
+					AND verified_at IS NOT NULL
+			)
+		FROM users
+		WHERE id = %s
+			AND deleted_at IS NULL;
+	`
 
-	var flags []*ff.FeatureFlag
+	attrs := ff.UserAttributes{UserID: userID}
+	err := f.QueryRow(ctx, sqlf.Sprintf(getUserAttributesFmtStr, userID)).Scan(
+		&attrs.SiteAdmin,
+		pq.Array(&attrs.OrgIDs),
+		pq.Array(&attrs.EmailDomains),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &attrs, nil
+}
+
+// userFlagInputs is everything needed to evaluate feature flags for a user.
+type userFlagInputs struct {
+	attrs         *ff.UserAttributes
+	orgOverrides  []*ff.Override
+	userOverrides []*ff.Override
+}
+
+func (f *FeatureFlagStore) getUserFlagInputs(ctx context.Context, g *errgroup.Group, userID int32) *userFlagInputs {
+	var in userFlagInputs
 	g.Go(func() error {
-		res, err := f.GetFeatureFlags(ctx)
-		flags = res
+		res, err := f.GetUserAttributes(ctx, userID)
+		if err == sql.ErrNoRows {
+			// Evaluate the flags of deleted users like those of users without attributes.
+			res, err = &ff.UserAttributes{UserID: userID}, nil
+		}
+		in.attrs = res
 		return err
 	})
-
-	var orgOverrides []*ff.Override
 	g.Go(func() error {
 		res, err := f.GetOrgOverridesForUser(ctx, userID)
-		orgOverrides = res
+		in.orgOverrides = res
 		return err
 	})
-
-	var userOverrides []*ff.Override
 	g.Go(func() error {
 		res, err := f.GetUserOverrides(ctx, userID)
-		userOverrides = res
+		in.userOverrides = res
 		return err
 	})
+	return &in
+}
+
+// evaluate evaluates the given flag, in order of precedence: user overrides, org overrides,
+// the flag's rules and finally the flag's value.
+func (in *userFlagInputs) evaluate(flag *ff.FeatureFlag) *ff.Evaluation {
+	for _, uo := range in.userOverrides {
+		if uo.FlagName == flag.Name {
+			return &ff.Evaluation{FlagName: flag.Name, Value: uo.Value, Source: ff.EvaluationSourceUserOverride, Override: uo}
+		}
+	}
+	for _, oo := range in.orgOverrides {
+		if oo.FlagName == flag.Name {
+			return &ff.Evaluation{FlagName: flag.Name, Value: oo.Value, Source: ff.EvaluationSourceOrgOverride, Override: oo}
+		}
+	}
+	return flag.EvaluateForUserAttributes(in.attrs)
+}
+
+// GetUserFlags returns the calculated values for feature flags for the given userID. This should
+// be the primary entrypoint for getting the user flags since it handles retrieving all the flags,
+// the rules, the org overrides, and the user overrides, and merges them in priority order.
+func (f *FeatureFlagStore) GetUserFlags(ctx context.Context, userID int32) (map[string]bool, error) {
+	g, ctx := errgroup.WithContext(ctx)
+
+	var flags []*ff.FeatureFlag
+	g.Go(func() error {
+		res, err := f.GetFeatureFlags(ctx)
+		flags = res
+		return err
+	})
+	in := f.getUserFlagInputs(ctx, g, userID)
 
 	if err := g.Wait(); err != nil {
 		return nil, err
 	}
 
 	res := make(map[string]bool, len(flags))
-	for _, ff := range flags {
-		res[ff.Name] = ff.EvaluateForUser(userID)
-
-		// Org overrides are higher priority than default
-		for _, oo := range orgOverrides {
-			res[oo.FlagName] = oo.Value
-		}
-
-		// User overrides are higher priority than org overrides
-		for _, uo := range userOverrides {
-			res[uo.FlagName] = uo.Value
-		}
+	for _, flag := range flags {
+		res[flag.Name] = in.evaluate(flag).Value
 	}
 
 	return res, nil
 }
 
+// GetUserFlag evaluates the given feature flag for the given userID like GetUserFlags, and
+// reports what decided its value. This is useful to explain why a flag is enabled or
+// disabled for a user.
+func (f *FeatureFlagStore) GetUserFlag(ctx context.Context, userID int32, flagName string) (*ff.Evaluation, error) {
+	g, ctx := errgroup.WithContext(ctx)
+
+	var flag *ff.FeatureFlag
+	g.Go(func() error {
+		res, err := f.GetFeatureFlag(ctx, flagName)
+		flag = res
+		return err
+	})
+	in := f.getUserFlagInputs(ctx, g, userID)
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return in.evaluate(flag), nil
+}
+
 // GetAnonymousUserFlags returns the calculated values for feature flags for the given anonymousUID
 func (f *FeatureFlagStore) GetAnonymousUserFlags(ctx context.Context, anonymousUID string) (map[string]bool, error) {
 	flags, err := f.GetFeatureFlags(ctx)
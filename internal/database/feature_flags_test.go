@@ -25,6 +25,7 @@ func TestFeatureFlagStore(t *testing.T) {
 		t.Run("ListOrgOverrides", testListOrgOverrides)
 	})
 	t.Run("UserFlags", testUserFlags)
+	t.Run("UserFlagRules", testUserFlagRules)
 	t.Run("AnonymousUserFlags", testAnonymousUserFlags)
 	t.Run("UserlessFeatureFlags", testUserlessFeatureFlags)
 	t.Run("OrganizationFeatureFlag", testOrgFeatureFlag)
@@ -80,6 +81,21 @@ func testNewFeatureFlagRoundtrip(t *testing.T) {
 			flag:      &ff.FeatureFlag{Name: "err_too_low_rollout", Rollout: &ff.FeatureFlagRollout{Rollout: -1}},
 			assertErr: errorContains(`violates check constraint "feature_flags_rollout_check"`),
 		},
+		{
+			flag: &ff.FeatureFlag{
+				Name:    "rules",
+				Rollout: &ff.FeatureFlagRollout{Rollout: 0},
+				Rules:   []ff.FeatureFlagRule{{EmailDomains: []string{"sourcegraph.com"}, Rollout: 5000}},
+			},
+		},
+		{
+			flag: &ff.FeatureFlag{
+				Name:  "err_rule_without_conditions",
+				Bool:  &ff.FeatureFlagBool{Value: false},
+				Rules: []ff.FeatureFlagRule{{Rollout: 5000}},
+			},
+			assertErr: errorContains(`feature flag rule must have at least one condition`),
+		},
 		{
 			flag:      &ff.FeatureFlag{Name: "err_no_types"},
 			assertErr: errorContains(`feature flag must have exactly one type`),
@@ -100,6 +116,7 @@ func testNewFeatureFlagRoundtrip(t *testing.T) {
 			require.Equal(t, tc.flag.Name, res.Name)
 			require.Equal(t, tc.flag.Bool, res.Bool)
 			require.Equal(t, tc.flag.Rollout, res.Rollout)
+			require.Equal(t, tc.flag.Rules, res.Rules)
 		})
 	}
 }
@@ -494,6 +511,141 @@ func testUserFlags(t *testing.T) {
 	})
 }
 
+func testUserFlagRules(t *testing.T) {
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	flagStore := FeatureFlags(db)
+	users := Users(db)
+	ctx := actor.WithInternalActor(context.Background())
+
+	mkUser := func(name string, email string, orgIDs ...int32) *types.User {
+		u, err := users.Create(ctx, NewUser{Username: name, Password: "p"})
+		require.NoError(t, err)
+		if email != "" {
+			require.NoError(t, UserEmails(db).Add(ctx, u.ID, email, nil))
+			require.NoError(t, UserEmails(db).SetVerified(ctx, u.ID, email, true))
+		}
+		for _, id := range orgIDs {
+			_, err := OrgMembers(db).Create(ctx, id, u.ID)
+			require.NoError(t, err)
+		}
+		return u
+	}
+
+	mkFFWithRules := func(name string, rules ...ff.FeatureFlagRule) {
+		_, err := flagStore.CreateBool(ctx, name, false)
+		require.NoError(t, err)
+		_, err = flagStore.SetFeatureFlagRules(ctx, name, rules)
+		require.NoError(t, err)
+	}
+
+	t.Run("email domain", func(t *testing.T) {
+		t.Cleanup(cleanup(t, db))
+		u1 := mkUser("u1", "u1@Sourcegraph.com")
+		u2 := mkUser("u2", "u2@example.com")
+		u3 := mkUser("u3", "")
+		mkFFWithRules("f1", ff.FeatureFlagRule{EmailDomains: []string{"sourcegraph.com"}, Rollout: 10000})
+
+		for _, tc := range []struct {
+			user *types.User
+			want bool
+		}{{u1, true}, {u2, false}, {u3, false}} {
+			got, err := flagStore.GetUserFlags(ctx, tc.user.ID)
+			require.NoError(t, err)
+			require.Equal(t, map[string]bool{"f1": tc.want}, got, tc.user.Username)
+		}
+	})
+
+	t.Run("unverified email", func(t *testing.T) {
+		t.Cleanup(cleanup(t, db))
+		u1, err := users.Create(ctx, NewUser{Username: "u1", Email: "u1@sourcegraph.com", Password: "p", EmailVerificationCode: "c"})
+		require.NoError(t, err)
+		mkFFWithRules("f1", ff.FeatureFlagRule{EmailDomains: []string{"sourcegraph.com"}, Rollout: 10000})
+
+		got, err := flagStore.GetUserFlags(ctx, u1.ID)
+		require.NoError(t, err)
+		require.Equal(t, map[string]bool{"f1": false}, got)
+	})
+
+	t.Run("org and site admin", func(t *testing.T) {
+		t.Cleanup(cleanup(t, db))
+		o1, err := Orgs(db).Create(ctx, "o1", nil)
+		require.NoError(t, err)
+		u1 := mkUser("u1", "", o1.ID)
+		u2 := mkUser("u2", "", o1.ID)
+		require.NoError(t, users.SetIsSiteAdmin(ctx, u2.ID, true))
+		u3 := mkUser("u3", "")
+		isSiteAdmin := true
+		mkFFWithRules("f1", ff.FeatureFlagRule{OrgIDs: []int32{o1.ID}, SiteAdmin: &isSiteAdmin, Rollout: 10000})
+		mkFFWithRules("f2", ff.FeatureFlagRule{OrgIDs: []int32{o1.ID}, Rollout: 10000})
+
+		for _, tc := range []struct {
+			user *types.User
+			want map[string]bool
+		}{
+			{u1, map[string]bool{"f1": false, "f2": true}},
+			{u2, map[string]bool{"f1": true, "f2": true}},
+			{u3, map[string]bool{"f1": false, "f2": false}},
+		} {
+			got, err := flagStore.GetUserFlags(ctx, tc.user.ID)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got, tc.user.Username)
+		}
+	})
+
+	t.Run("first matching rule wins", func(t *testing.T) {
+		t.Cleanup(cleanup(t, db))
+		u1 := mkUser("u1", "")
+		mkFFWithRules("f1",
+			ff.FeatureFlagRule{UserIDs: []int32{u1.ID}, Rollout: 0},
+			ff.FeatureFlagRule{UserIDs: []int32{u1.ID}, Rollout: 10000},
+		)
+
+		got, err := flagStore.GetUserFlag(ctx, u1.ID, "f1")
+		require.NoError(t, err)
+		require.Equal(t, &ff.Evaluation{FlagName: "f1", Value: false, Source: ff.EvaluationSourceRule, Rule: 0}, got)
+	})
+
+	t.Run("overrides beat rules", func(t *testing.T) {
+		t.Cleanup(cleanup(t, db))
+		o1, err := Orgs(db).Create(ctx, "o1", nil)
+		require.NoError(t, err)
+		u1 := mkUser("u1", "", o1.ID)
+		mkFFWithRules("f1", ff.FeatureFlagRule{UserIDs: []int32{u1.ID}, Rollout: 10000})
+		mkFFWithRules("f2", ff.FeatureFlagRule{UserIDs: []int32{u1.ID}, Rollout: 10000})
+		_, err = flagStore.CreateOverride(ctx, &ff.Override{OrgID: &o1.ID, FlagName: "f1", Value: false})
+		require.NoError(t, err)
+		_, err = flagStore.CreateOverride(ctx, &ff.Override{OrgID: &o1.ID, FlagName: "f2", Value: false})
+		require.NoError(t, err)
+		_, err = flagStore.CreateOverride(ctx, &ff.Override{UserID: &u1.ID, FlagName: "f2", Value: true})
+		require.NoError(t, err)
+
+		got, err := flagStore.GetUserFlag(ctx, u1.ID, "f1")
+		require.NoError(t, err)
+		require.Equal(t, false, got.Value)
+		require.Equal(t, ff.EvaluationSourceOrgOverride, got.Source)
+
+		got, err = flagStore.GetUserFlag(ctx, u1.ID, "f2")
+		require.NoError(t, err)
+		require.Equal(t, true, got.Value)
+		require.Equal(t, ff.EvaluationSourceUserOverride, got.Source)
+	})
+
+	t.Run("rules survive updates", func(t *testing.T) {
+		t.Cleanup(cleanup(t, db))
+		rule := ff.FeatureFlagRule{EmailDomains: []string{"sourcegraph.com"}, Rollout: 2500}
+		mkFFWithRules("f1", rule)
+
+		res, err := flagStore.UpdateFeatureFlag(ctx, &ff.FeatureFlag{Name: "f1", Rollout: &ff.FeatureFlagRollout{Rollout: 100}})
+		require.NoError(t, err)
+		require.Equal(t, []ff.FeatureFlagRule{rule}, res.Rules)
+
+		res, err = flagStore.SetFeatureFlagRules(ctx, "f1", nil)
+		require.NoError(t, err)
+		require.Nil(t, res.Rules)
+	})
+}
+
 func testAnonymousUserFlags(t *testing.T) {
 	t.Parallel()
 	db := dbtest.NewDB(t, "")
@@ -0,0 +1,198 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// FeatureUsageStatistic is the usage of a product feature (or of a single event of it) during
+// a period, as aggregated from the event logs.
+type FeatureUsageStatistic struct {
+	Feature     string
+	Period      PeriodType
+	PeriodStart time.Time
+
+	// EventName is the name of the aggregated event. It is empty for the totals over all events
+	// of the feature.
+	EventName string
+
+	EventCount int
+	// UserCount is the number of unique registered and anonymous users that logged the event.
+	UserCount int
+
+	AggregatedAt time.Time
+}
+
+// FeatureUsageEvents describes the events of the event logs that make up the usage of a feature.
+type FeatureUsageEvents struct {
+	Feature string
+
+	// EventNames are the names of the events of the feature.
+	EventNames []string
+	// EventNamePrefixes match the events of the feature by the prefix of their name.
+	EventNamePrefixes []string
+}
+
+// FeatureUsageStatisticsStore stores the usage of product features aggregated from the event
+// logs, so that reading it does not require to aggregate the event logs on demand.
+type FeatureUsageStatisticsStore struct {
+	*basestore.Store
+}
+
+// FeatureUsageStatistics instantiates and returns a new FeatureUsageStatisticsStore.
+func FeatureUsageStatistics(db dbutil.DB) *FeatureUsageStatisticsStore {
+	return &FeatureUsageStatisticsStore{Store: basestore.NewWithDB(db, sql.TxOptions{})}
+}
+
+// FeatureUsageStatisticsWith instantiates and returns a new FeatureUsageStatisticsStore using
+// the other store handle.
+func FeatureUsageStatisticsWith(other basestore.ShareableStore) *FeatureUsageStatisticsStore {
+	return &FeatureUsageStatisticsStore{Store: basestore.NewWithHandle(other.Handle())}
+}
+
+func (s *FeatureUsageStatisticsStore) With(other basestore.ShareableStore) *FeatureUsageStatisticsStore {
+	return &FeatureUsageStatisticsStore{Store: s.Store.With(other)}
+}
+
+func (s *FeatureUsageStatisticsStore) Transact(ctx context.Context) (*FeatureUsageStatisticsStore, error) {
+	txBase, err := s.Store.Transact(ctx)
+	return &FeatureUsageStatisticsStore{Store: txBase}, err
+}
+
+// Aggregate (re)computes the usage of the given feature for the given number of periods of the
+// given type, up to and including the period containing now. The usage of each event of the
+// feature is recorded next to the totals over all of its events.
+func (s *FeatureUsageStatisticsStore) Aggregate(ctx context.Context, events FeatureUsageEvents, periodType PeriodType, now time.Time, periods int) error {
+	startDate, ok := calcStartDate(now, periodType, periods)
+	if !ok {
+		return errors.Errorf("periodType must be \"daily\", \"weekly\", or \"monthly\". Got %q", periodType)
+	}
+
+	prefixes := make([]string, 0, len(events.EventNamePrefixes))
+	for _, prefix := range events.EventNamePrefixes {
+		prefixes = append(prefixes, prefix+"%")
+	}
+
+	return s.Exec(ctx, sqlf.Sprintf(
+		aggregateFeatureUsageStatisticsQuery,
+		events.Feature,
+		string(periodType),
+		periodByPeriodType[periodType],
+		pq.Array(events.EventNames),
+		pq.Array(prefixes),
+		startDate,
+	))
+}
+
+const aggregateFeatureUsageStatisticsQuery = `
+-- source: internal/database/feature_usage_statistics.go:Aggregate
+INSERT INTO feature_usage_statistics (
+	feature,
+	period,
+	period_start,
+	event_name,
+	event_count,
+	user_count,
+	aggregated_at
+)
+SELECT
+	%s,
+	%s,
+	period_start,
+	COALESCE(name, ''),
+	COUNT(*),
+	COUNT(DISTINCT user_id),
+	NOW()
+FROM (
+	SELECT
+		name,
+		(%s) AT TIME ZONE 'UTC' AS period_start,
+		` + aggregatedUserIDQueryFragment + ` AS user_id
+	FROM event_logs
+	WHERE (name = ANY(%s) OR name LIKE ANY(%s)) AND timestamp >= %s
+) events
+GROUP BY GROUPING SETS ((period_start, name), (period_start))
+ON CONFLICT (feature, period, period_start, event_name) DO UPDATE
+SET
+	event_count = EXCLUDED.event_count,
+	user_count = EXCLUDED.user_count,
+	aggregated_at = EXCLUDED.aggregated_at
+`
+
+// FeatureUsageStatisticsListOptions provides the options for listing aggregated feature usage.
+type FeatureUsageStatisticsListOptions struct {
+	Feature string
+	Period  PeriodType
+
+	// EventNames restricts the result to the given events. The totals over all events of the
+	// feature are listed under the empty event name. If not set, all events are listed.
+	EventNames []string
+
+	// Since restricts the result to the periods starting at or after the given time.
+	Since time.Time
+}
+
+// List returns the aggregated usage of a feature, ordered by the start of the period (most recent
+// first) and event name.
+func (s *FeatureUsageStatisticsStore) List(ctx context.Context, opts FeatureUsageStatisticsListOptions) ([]FeatureUsageStatistic, error) {
+	conds := []*sqlf.Query{
+		sqlf.Sprintf("feature = %s", opts.Feature),
+		sqlf.Sprintf("period = %s", string(opts.Period)),
+	}
+	if opts.EventNames != nil {
+		conds = append(conds, sqlf.Sprintf("event_name = ANY(%s)", pq.Array(opts.EventNames)))
+	}
+	if !opts.Since.IsZero() {
+		conds = append(conds, sqlf.Sprintf("period_start >= %s", opts.Since))
+	}
+
+	return scanFeatureUsageStatistics(s.Query(ctx, sqlf.Sprintf(listFeatureUsageStatisticsQuery, sqlf.Join(conds, "AND"))))
+}
+
+const listFeatureUsageStatisticsQuery = `
+-- source: internal/database/feature_usage_statistics.go:List
+SELECT
+	feature,
+	period,
+	period_start,
+	event_name,
+	event_count,
+	user_count,
+	aggregated_at
+FROM feature_usage_statistics
+WHERE %s
+ORDER BY period_start DESC, event_name
+`
+
+func scanFeatureUsageStatistics(rows *sql.Rows, queryErr error) (_ []FeatureUsageStatistic, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var stats []FeatureUsageStatistic
+	for rows.Next() {
+		var s FeatureUsageStatistic
+		if err := rows.Scan(
+			&s.Feature,
+			&s.Period,
+			&s.PeriodStart,
+			&s.EventName,
+			&s.EventCount,
+			&s.UserCount,
+			&s.AggregatedAt,
+		); err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
@@ -0,0 +1,114 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+)
+
+func TestFeatureUsageStatistics(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	ctx := context.Background()
+	store := FeatureUsageStatistics(db)
+
+	// A Wednesday, so that all events below fall into the same week.
+	now := time.Date(2021, 10, 13, 12, 0, 0, 0, time.UTC)
+	today := time.Date(2021, 10, 13, 0, 0, 0, 0, time.UTC)
+	yesterday := today.AddDate(0, 0, -1)
+
+	events := []*Event{
+		{Name: "InsightAddition", UserID: 1, Timestamp: now.Add(-time.Hour)},
+		{Name: "InsightAddition", UserID: 2, Timestamp: now.Add(-time.Hour)},
+		{Name: "InsightEdit", UserID: 1, Timestamp: now.Add(-time.Hour)},
+		{Name: "InsightEdit", UserID: 1, Timestamp: now.Add(-25 * time.Hour)},
+		{Name: "InsightEdit", AnonymousUserID: "anon", Timestamp: now.Add(-25 * time.Hour)},
+		// Events of other features are not aggregated.
+		{Name: "codeintel.lsifHover", UserID: 1, Timestamp: now.Add(-time.Hour)},
+		// Events before the aggregated periods are not aggregated.
+		{Name: "InsightEdit", UserID: 1, Timestamp: now.AddDate(0, 0, -3)},
+	}
+	for _, e := range events {
+		e.URL = "http://sourcegraph.com"
+		e.Source = "WEB"
+		if err := EventLogs(db).Insert(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	insights := FeatureUsageEvents{Feature: "code-insights", EventNames: []string{"InsightAddition"}, EventNamePrefixes: []string{"InsightE"}}
+	for _, periodType := range []PeriodType{Daily, Weekly} {
+		if err := store.Aggregate(ctx, insights, periodType, now, 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ignoreAggregatedAt := cmpopts.IgnoreFields(FeatureUsageStatistic{}, "AggregatedAt")
+	stat := func(period PeriodType, start time.Time, name string, events, users int) FeatureUsageStatistic {
+		return FeatureUsageStatistic{Feature: "code-insights", Period: period, PeriodStart: start, EventName: name, EventCount: events, UserCount: users}
+	}
+	normalize := func(stats []FeatureUsageStatistic) []FeatureUsageStatistic {
+		for i := range stats {
+			stats[i].PeriodStart = stats[i].PeriodStart.UTC()
+		}
+		return stats
+	}
+
+	t.Run("daily", func(t *testing.T) {
+		have, err := store.List(ctx, FeatureUsageStatisticsListOptions{Feature: "code-insights", Period: Daily})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []FeatureUsageStatistic{
+			stat(Daily, today, "", 3, 2),
+			stat(Daily, today, "InsightAddition", 2, 2),
+			stat(Daily, today, "InsightEdit", 1, 1),
+			stat(Daily, yesterday, "", 2, 2),
+			stat(Daily, yesterday, "InsightEdit", 2, 2),
+		}
+		if diff := cmp.Diff(want, normalize(have), ignoreAggregatedAt); diff != "" {
+			t.Fatalf("unexpected statistics (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("weekly totals", func(t *testing.T) {
+		have, err := store.List(ctx, FeatureUsageStatisticsListOptions{Feature: "code-insights", Period: Weekly, EventNames: []string{""}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []FeatureUsageStatistic{
+			stat(Weekly, time.Date(2021, 10, 10, 0, 0, 0, 0, time.UTC), "", 6, 3),
+		}
+		if diff := cmp.Diff(want, normalize(have), ignoreAggregatedAt); diff != "" {
+			t.Fatalf("unexpected statistics (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("reaggregation", func(t *testing.T) {
+		if err := EventLogs(db).Insert(ctx, &Event{Name: "InsightAddition", UserID: 3, URL: "http://sourcegraph.com", Source: "WEB", Timestamp: now}); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Aggregate(ctx, insights, Daily, now, 1); err != nil {
+			t.Fatal(err)
+		}
+
+		have, err := store.List(ctx, FeatureUsageStatisticsListOptions{Feature: "code-insights", Period: Daily, EventNames: []string{"InsightAddition"}, Since: today})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []FeatureUsageStatistic{
+			stat(Daily, today, "InsightAddition", 3, 3),
+		}
+		if diff := cmp.Diff(want, normalize(have), ignoreAggregatedAt); diff != "" {
+			t.Fatalf("unexpected statistics (-want +got):\n%s", diff)
+		}
+	})
+}
@@ -6,17 +6,20 @@ var Mocks MockStores
 type MockStores struct {
 	AccessTokens MockAccessTokens
 
-	Repos           MockRepos
-	Namespaces      MockNamespaces
-	Orgs            MockOrgs
-	OrgMembers      MockOrgMembers
-	SavedSearches   MockSavedSearches
-	Settings        MockSettings
-	Users           MockUsers
-	UserCredentials MockUserCredentials
-	UserEmails      MockUserEmails
-	UserPublicRepos MockUserPublicRepos
-	SearchContexts  MockSearchContexts
+	Repos            MockRepos
+	Namespaces       MockNamespaces
+	Orgs             MockOrgs
+	OrgMembers       MockOrgMembers
+	SavedSearches    MockSavedSearches
+	Settings         MockSettings
+	Users            MockUsers
+	UserCredentials  MockUserCredentials
+	UserEmails       MockUserEmails
+	UserPublicRepos  MockUserPublicRepos
+	UserSecurityKeys MockUserSecurityKeys
+	UserSignInLinks  MockUserSignInLinks
+	SearchContexts   MockSearchContexts
+	Redirects        MockRedirects
 
 	Phabricator MockPhabricator
 
@@ -30,6 +33,8 @@ type MockStores struct {
 
 	EventLogs MockEventLogs
 
+	SecurityEventLogs MockSecurityEventLogs
+
 	TemporarySettings MockTemporarySettings
 
 	FeatureFlags MockFeatureFlags
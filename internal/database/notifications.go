@@ -0,0 +1,275 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// NotificationKind is the kind of event a notification is about.
+type NotificationKind string
+
+const (
+	// NotificationKindBatchSpecExecutionFinished notifies the creator of a batch spec
+	// that its server-side execution finished.
+	NotificationKindBatchSpecExecutionFinished NotificationKind = "batch_spec_execution_finished"
+	// NotificationKindExternalServiceSyncFailed notifies the owners of an external
+	// service that syncing it with the code host failed.
+	NotificationKindExternalServiceSyncFailed NotificationKind = "external_service_sync_failed"
+	// NotificationKindInsightAlert notifies the owner of a Code Insights alert that it
+	// started to trigger.
+	NotificationKindInsightAlert NotificationKind = "insight_alert"
+)
+
+// DefaultNotificationTTL is how long a notification is kept if it is created without
+// an expiry.
+const DefaultNotificationTTL = 30 * 24 * time.Hour
+
+// Notification is an in-product notification of a user.
+type Notification struct {
+	ID     int64
+	UserID int32
+	Kind   NotificationKind
+	Title  string
+	Body   string
+	// URL is the URL, usually relative to the external URL, of the page with the
+	// details of the event, or empty.
+	URL string
+	// DedupeKey identifies the event the notification is about. A user has at most
+	// one unread notification with the same non-empty key.
+	DedupeKey string
+	CreatedAt time.Time
+	// ReadAt is nil if the notification is unread.
+	ReadAt    *time.Time
+	ExpiresAt time.Time
+}
+
+// NotificationsListOptions contains options for listing notifications.
+type NotificationsListOptions struct {
+	UserID     int32 // only list the notifications of this user
+	UnreadOnly bool  // only list unread notifications
+	*LimitOffset
+}
+
+func (o NotificationsListOptions) sqlConditions() []*sqlf.Query {
+	conds := []*sqlf.Query{
+		sqlf.Sprintf("expires_at > NOW()"),
+	}
+	if o.UserID != 0 {
+		conds = append(conds, sqlf.Sprintf("user_id = %s", o.UserID))
+	}
+	if o.UnreadOnly {
+		conds = append(conds, sqlf.Sprintf("read_at IS NULL"))
+	}
+	return conds
+}
+
+// NotificationStore stores the in-product notifications of users.
+//
+// 🚨 SECURITY: The store does not check that the caller is allowed to see or modify
+// the notifications of a user. Callers must do so.
+type NotificationStore struct {
+	*basestore.Store
+}
+
+// Notifications instantiates and returns a new NotificationStore.
+func Notifications(db dbutil.DB) *NotificationStore {
+	return &NotificationStore{Store: basestore.NewWithDB(db, sql.TxOptions{})}
+}
+
+// NotificationsWith instantiates and returns a new NotificationStore using the other
+// store handle.
+func NotificationsWith(other basestore.ShareableStore) *NotificationStore {
+	return &NotificationStore{Store: basestore.NewWithHandle(other.Handle())}
+}
+
+func (s *NotificationStore) With(other basestore.ShareableStore) *NotificationStore {
+	return &NotificationStore{Store: s.Store.With(other)}
+}
+
+func (s *NotificationStore) Transact(ctx context.Context) (*NotificationStore, error) {
+	txBase, err := s.Store.Transact(ctx)
+	return &NotificationStore{Store: txBase}, err
+}
+
+// Create creates the notification for n.UserID and sets its ID, CreatedAt and
+// ExpiresAt. If ExpiresAt is zero, the notification expires after
+// DefaultNotificationTTL. If the user already has an unread notification with the
+// same DedupeKey, no notification is created and n.ID is left zero.
+func (s *NotificationStore) Create(ctx context.Context, n *Notification) error {
+	if n.ExpiresAt.IsZero() {
+		n.ExpiresAt = time.Now().Add(DefaultNotificationTTL)
+	}
+	row := s.QueryRow(ctx, sqlf.Sprintf(
+		createNotificationQuery,
+		n.UserID,
+		n.Kind,
+		n.Title,
+		n.Body,
+		dbutil.NewNullString(n.URL),
+		dbutil.NewNullString(n.DedupeKey),
+		n.ExpiresAt,
+	))
+	if err := row.Scan(&n.ID, &n.CreatedAt); err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	return nil
+}
+
+const createNotificationQuery = `
+-- source: internal/database/notifications.go:Create
+INSERT INTO notifications (user_id, kind, title, body, url, dedupe_key, expires_at)
+VALUES (%s, %s, %s, %s, %s, %s, %s)
+ON CONFLICT (user_id, dedupe_key) WHERE read_at IS NULL DO NOTHING
+RETURNING id, created_at
+`
+
+// CreateForSiteAdmins creates a copy of the notification, ignoring its UserID, for
+// every site admin.
+func (s *NotificationStore) CreateForSiteAdmins(ctx context.Context, n Notification) error {
+	return s.createForUsers(ctx, sqlf.Sprintf("SELECT id FROM users WHERE site_admin AND deleted_at IS NULL"), n)
+}
+
+// CreateForOrgMembers creates a copy of the notification, ignoring its UserID, for
+// every member of the given organization.
+func (s *NotificationStore) CreateForOrgMembers(ctx context.Context, orgID int32, n Notification) error {
+	return s.createForUsers(ctx, sqlf.Sprintf(`
+SELECT u.id FROM org_members m
+JOIN users u ON u.id = m.user_id
+WHERE m.org_id = %s AND u.deleted_at IS NULL
+`, orgID), n)
+}
+
+func (s *NotificationStore) createForUsers(ctx context.Context, users *sqlf.Query, n Notification) error {
+	if n.ExpiresAt.IsZero() {
+		n.ExpiresAt = time.Now().Add(DefaultNotificationTTL)
+	}
+	return s.Exec(ctx, sqlf.Sprintf(
+		createNotificationsForUsersQuery,
+		n.Kind,
+		n.Title,
+		n.Body,
+		dbutil.NewNullString(n.URL),
+		dbutil.NewNullString(n.DedupeKey),
+		n.ExpiresAt,
+		users,
+	))
+}
+
+const createNotificationsForUsersQuery = `
+-- source: internal/database/notifications.go:createForUsers
+INSERT INTO notifications (user_id, kind, title, body, url, dedupe_key, expires_at)
+SELECT recipients.id, %s, %s, %s, %s, %s, %s
+FROM (%s) AS recipients
+ON CONFLICT (user_id, dedupe_key) WHERE read_at IS NULL DO NOTHING
+`
+
+// List returns the unexpired notifications matching the options, newest first.
+func (s *NotificationStore) List(ctx context.Context, opt NotificationsListOptions) ([]*Notification, error) {
+	return scanNotifications(s.Query(ctx, sqlf.Sprintf(
+		listNotificationsQuery,
+		sqlf.Join(opt.sqlConditions(), ") AND ("),
+		opt.LimitOffset.SQL(),
+	)))
+}
+
+const listNotificationsQuery = `
+-- source: internal/database/notifications.go:List
+SELECT id, user_id, kind, title, body, url, dedupe_key, created_at, read_at, expires_at
+FROM notifications
+WHERE (%s)
+ORDER BY created_at DESC, id DESC
+%s
+`
+
+// Count counts the unexpired notifications matching the options. The limit and
+// offset are ignored.
+func (s *NotificationStore) Count(ctx context.Context, opt NotificationsListOptions) (int, error) {
+	count, _, err := basestore.ScanFirstInt(s.Query(ctx, sqlf.Sprintf(
+		countNotificationsQuery,
+		sqlf.Join(opt.sqlConditions(), ") AND ("),
+	)))
+	return count, err
+}
+
+const countNotificationsQuery = `
+-- source: internal/database/notifications.go:Count
+SELECT COUNT(*)
+FROM notifications
+WHERE (%s)
+`
+
+// MarkRead marks the given notifications of the user as read. IDs of notifications
+// of other users are ignored.
+func (s *NotificationStore) MarkRead(ctx context.Context, userID int32, ids []int64) error {
+	return s.Exec(ctx, sqlf.Sprintf(markNotificationsReadQuery, userID, pq.Array(ids)))
+}
+
+const markNotificationsReadQuery = `
+-- source: internal/database/notifications.go:MarkRead
+UPDATE notifications
+SET read_at = NOW()
+WHERE user_id = %s AND id = ANY(%s) AND read_at IS NULL
+`
+
+// MarkAllRead marks all notifications of the user as read.
+func (s *NotificationStore) MarkAllRead(ctx context.Context, userID int32) error {
+	return s.Exec(ctx, sqlf.Sprintf(markAllNotificationsReadQuery, userID))
+}
+
+const markAllNotificationsReadQuery = `
+-- source: internal/database/notifications.go:MarkAllRead
+UPDATE notifications
+SET read_at = NOW()
+WHERE user_id = %s AND read_at IS NULL
+`
+
+// DeleteExpired deletes all expired notifications and returns how many were
+// deleted.
+func (s *NotificationStore) DeleteExpired(ctx context.Context) (int64, error) {
+	res, err := s.ExecResult(ctx, sqlf.Sprintf(deleteExpiredNotificationsQuery))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+const deleteExpiredNotificationsQuery = `
+-- source: internal/database/notifications.go:DeleteExpired
+DELETE FROM notifications
+WHERE expires_at <= NOW()
+`
+
+func scanNotifications(rows *sql.Rows, queryErr error) (_ []*Notification, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var notifications []*Notification
+	for rows.Next() {
+		var n Notification
+		if err := rows.Scan(
+			&n.ID,
+			&n.UserID,
+			&n.Kind,
+			&n.Title,
+			&n.Body,
+			&dbutil.NullString{S: &n.URL},
+			&dbutil.NullString{S: &n.DedupeKey},
+			&n.CreatedAt,
+			&n.ReadAt,
+			&n.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, &n)
+	}
+	return notifications, nil
+}
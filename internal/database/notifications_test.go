@@ -0,0 +1,143 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+)
+
+func TestNotifications(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	ctx := context.Background()
+	store := Notifications(db)
+
+	// The first user is a site admin.
+	admin, err := Users(db).Create(ctx, NewUser{Username: "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := Users(db).Create(ctx, NewUser{Username: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	titles := func(t *testing.T, opt NotificationsListOptions) []string {
+		t.Helper()
+		notifications, err := store.List(ctx, opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var titles []string
+		for _, n := range notifications {
+			titles = append(titles, n.Title)
+		}
+		return titles
+	}
+
+	first := &Notification{UserID: user.ID, Kind: NotificationKindInsightAlert, Title: "first", URL: "/insights"}
+	if err := store.Create(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+	if first.ID == 0 || first.CreatedAt.IsZero() {
+		t.Fatalf("expected ID and CreatedAt to be set, have %+v", first)
+	}
+	if have, want := first.ExpiresAt.Sub(first.CreatedAt).Round(time.Hour), DefaultNotificationTTL; have != want {
+		t.Errorf("unexpected TTL: have %s, want %s", have, want)
+	}
+	second := &Notification{UserID: user.ID, Kind: NotificationKindInsightAlert, Title: "second", DedupeKey: "k"}
+	if err := store.Create(ctx, second); err != nil {
+		t.Fatal(err)
+	}
+	expired := &Notification{UserID: user.ID, Kind: NotificationKindInsightAlert, Title: "expired", ExpiresAt: time.Now().Add(-time.Minute)}
+	if err := store.Create(ctx, expired); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("List", func(t *testing.T) {
+		if diff := cmp.Diff([]string{"second", "first"}, titles(t, NotificationsListOptions{UserID: user.ID})); diff != "" {
+			t.Errorf("unexpected notifications (-want +have):\n%s", diff)
+		}
+		if have := titles(t, NotificationsListOptions{UserID: user.ID, LimitOffset: &LimitOffset{Limit: 1}}); len(have) != 1 {
+			t.Errorf("expected one notification, have %v", have)
+		}
+	})
+
+	t.Run("Create deduplicates unread notifications", func(t *testing.T) {
+		duplicate := &Notification{UserID: user.ID, Kind: NotificationKindInsightAlert, Title: "duplicate", DedupeKey: "k"}
+		if err := store.Create(ctx, duplicate); err != nil {
+			t.Fatal(err)
+		}
+		if duplicate.ID != 0 {
+			t.Errorf("expected no notification to be created, have ID %d", duplicate.ID)
+		}
+	})
+
+	t.Run("MarkRead", func(t *testing.T) {
+		// The ID of the other user's notification is ignored.
+		if err := store.MarkRead(ctx, admin.ID, []int64{first.ID}); err != nil {
+			t.Fatal(err)
+		}
+		if count, err := store.Count(ctx, NotificationsListOptions{UserID: user.ID, UnreadOnly: true}); err != nil {
+			t.Fatal(err)
+		} else if count != 2 {
+			t.Errorf("unexpected unread count: have %d, want 2", count)
+		}
+
+		if err := store.MarkRead(ctx, user.ID, []int64{first.ID}); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]string{"second"}, titles(t, NotificationsListOptions{UserID: user.ID, UnreadOnly: true})); diff != "" {
+			t.Errorf("unexpected unread notifications (-want +have):\n%s", diff)
+		}
+	})
+
+	t.Run("MarkAllRead", func(t *testing.T) {
+		if err := store.MarkAllRead(ctx, user.ID); err != nil {
+			t.Fatal(err)
+		}
+		if count, err := store.Count(ctx, NotificationsListOptions{UserID: user.ID, UnreadOnly: true}); err != nil {
+			t.Fatal(err)
+		} else if count != 0 {
+			t.Errorf("unexpected unread count: have %d, want 0", count)
+		}
+
+		// Once read, a notification with the same key can be created again.
+		again := &Notification{UserID: user.ID, Kind: NotificationKindInsightAlert, Title: "again", DedupeKey: "k"}
+		if err := store.Create(ctx, again); err != nil {
+			t.Fatal(err)
+		}
+		if again.ID == 0 {
+			t.Error("expected notification to be created")
+		}
+	})
+
+	t.Run("CreateForSiteAdmins", func(t *testing.T) {
+		if err := store.CreateForSiteAdmins(ctx, Notification{Kind: NotificationKindExternalServiceSyncFailed, Title: "sync failed"}); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]string{"sync failed"}, titles(t, NotificationsListOptions{UserID: admin.ID})); diff != "" {
+			t.Errorf("unexpected notifications of the site admin (-want +have):\n%s", diff)
+		}
+		if have := titles(t, NotificationsListOptions{UserID: user.ID}); len(have) != 3 {
+			t.Errorf("expected no notification for the user, have %v", have)
+		}
+	})
+
+	t.Run("DeleteExpired", func(t *testing.T) {
+		deleted, err := store.DeleteExpired(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if deleted != 1 {
+			t.Errorf("unexpected number of deleted notifications: have %d, want 1", deleted)
+		}
+	})
+}
@@ -348,3 +348,61 @@ func (m *ExternalAccountsMigrator) Down(ctx context.Context) (err error) {
 
 	return nil
 }
+
+// EventLogsMigrator is a background job that moves the events of the unpartitioned event_logs
+// table of previous versions to the partitioned event_logs table, in batches so that large
+// tables don't block the upgrade.
+// The migration is non destructive: the down migration of the partitioned table also restores
+// the events that were not moved yet.
+type EventLogsMigrator struct {
+	store     *basestore.Store
+	BatchSize int
+}
+
+func NewEventLogsMigrator(store *basestore.Store) *EventLogsMigrator {
+	return &EventLogsMigrator{store: store, BatchSize: 10000}
+}
+
+func NewEventLogsMigratorWithDB(db dbutil.DB) *EventLogsMigrator {
+	return NewEventLogsMigrator(basestore.NewWithDB(db, sql.TxOptions{}))
+}
+
+// ID of the migration row in the out_of_band_migrations table.
+// This ID was defined arbitrarily in this migration file: frontend/1528395932_event_logs_partitioning.up.sql
+func (m *EventLogsMigrator) ID() int {
+	return 14
+}
+
+// Progress returns a value from 0 to 1 representing the percentage of events already moved.
+// Events are moved in the order of their IDs, and new events have greater IDs than all the
+// events to move, so the moved events are those with a smaller ID than all remaining events.
+func (m *EventLogsMigrator) Progress(ctx context.Context) (float64, error) {
+	progress, _, err := basestore.ScanFirstFloat(m.store.Query(ctx, sqlf.Sprintf(`
+		SELECT
+			CASE c2.count WHEN 0 THEN 1 ELSE
+				CAST(c1.count AS float) / CAST(c1.count + c2.count AS float)
+			END
+		FROM
+			(SELECT COUNT(*) AS count FROM event_logs WHERE id < (SELECT MIN(id) FROM event_logs_unpartitioned)) c1,
+			(SELECT COUNT(*) AS count FROM event_logs_unpartitioned) c2
+	`)))
+	return progress, err
+}
+
+// Up moves the BatchSize events with the smallest IDs to the partitioned table.
+func (m *EventLogsMigrator) Up(ctx context.Context) error {
+	return m.store.Exec(ctx, sqlf.Sprintf(`
+		WITH moved AS (
+			DELETE FROM event_logs_unpartitioned
+			WHERE id IN (SELECT id FROM event_logs_unpartitioned ORDER BY id LIMIT %s FOR UPDATE SKIP LOCKED)
+			RETURNING *
+		)
+		INSERT INTO event_logs SELECT * FROM moved
+	`, m.BatchSize))
+}
+
+// Down does nothing: the down migration of the partitioned table moves all events back to an
+// unpartitioned table.
+func (m *EventLogsMigrator) Down(ctx context.Context) error {
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
@@ -658,3 +659,62 @@ func TestExternalAccountsMigrator(t *testing.T) {
 		}
 	})
 }
+
+func TestEventLogsMigrator(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := context.Background()
+	db := dbtest.NewDB(t, "")
+
+	migrator := NewEventLogsMigratorWithDB(db)
+	migrator.BatchSize = 2
+
+	requireProgressEqual := func(want float64) {
+		t.Helper()
+
+		got, err := migrator.Progress(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprintf("%.3f", want) != fmt.Sprintf("%.3f", got) {
+			t.Fatalf("invalid progress: want %f, got %f", want, got)
+		}
+	}
+
+	// progress on empty table should be 1
+	requireProgressEqual(1)
+
+	// Create 10 events of a previous version, including one older than the partitions, which
+	// is moved to the default partition.
+	for i := 0; i < 10; i++ {
+		if _, err := db.ExecContext(ctx, `
+			INSERT INTO event_logs_unpartitioned (name, url, user_id, anonymous_user_id, source, argument, version, timestamp)
+			VALUES ('test', '', 1, '', 'WEB', '{}', 'v', NOW() - $1 * INTERVAL '1 month')
+		`, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	requireProgressEqual(0)
+
+	// Events logged during the migration don't count towards its progress.
+	if err := EventLogs(db).Insert(ctx, &Event{Name: "new", UserID: 1, Source: "WEB", Timestamp: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	requireProgressEqual(0)
+
+	for i := 1; i <= 5; i++ {
+		if err := migrator.Up(ctx); err != nil {
+			t.Fatal(err)
+		}
+		requireProgressEqual(float64(i) * 0.2)
+	}
+
+	var count int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM event_logs`).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 11 {
+		t.Fatalf("got %d events, want 11", count)
+	}
+}
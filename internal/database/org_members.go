@@ -38,14 +38,23 @@ func (m *OrgMemberStore) Transact(ctx context.Context) (*OrgMemberStore, error)
 }
 
 func (m *OrgMemberStore) Create(ctx context.Context, orgID, userID int32) (*types.OrgMembership, error) {
+	return m.CreateWithRole(ctx, orgID, userID, types.OrgRoleMember)
+}
+
+// CreateWithRole adds the user to the organization with the given role.
+func (m *OrgMemberStore) CreateWithRole(ctx context.Context, orgID, userID int32, role types.OrgRole) (*types.OrgMembership, error) {
+	if !role.Valid() {
+		return nil, errors.Errorf("invalid organization role %q", role)
+	}
 	om := types.OrgMembership{
 		OrgID:  orgID,
 		UserID: userID,
+		Role:   role,
 	}
 	err := m.Handle().DB().QueryRowContext(
 		ctx,
-		"INSERT INTO org_members(org_id, user_id) VALUES($1, $2) RETURNING id, created_at, updated_at",
-		om.OrgID, om.UserID).Scan(&om.ID, &om.CreatedAt, &om.UpdatedAt)
+		"INSERT INTO org_members(org_id, user_id, role) VALUES($1, $2, $3) RETURNING id, created_at, updated_at",
+		om.OrgID, om.UserID, om.Role).Scan(&om.ID, &om.CreatedAt, &om.UpdatedAt)
 	if err != nil {
 		var e *pgconn.PgError
 		if errors.As(err, &e) && e.ConstraintName == "org_members_org_id_user_id_key" {
@@ -56,6 +65,66 @@ func (m *OrgMemberStore) Create(ctx context.Context, orgID, userID int32) (*type
 	return &om, nil
 }
 
+// ErrLastOrgAdmin is returned when changing the role of the only admin of an organization.
+var ErrLastOrgAdmin = errors.New("the organization must have at least one admin")
+
+// SetRole changes the role of the user in the organization. The last admin of an organization
+// cannot be demoted, so that the organization can still be managed by one of its members.
+func (m *OrgMemberStore) SetRole(ctx context.Context, orgID, userID int32, role types.OrgRole) (err error) {
+	if Mocks.OrgMembers.SetRole != nil {
+		return Mocks.OrgMembers.SetRole(ctx, orgID, userID, role)
+	}
+
+	if !role.Valid() {
+		return errors.Errorf("invalid organization role %q", role)
+	}
+
+	tx, err := m.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	member, err := tx.GetByOrgIDAndUserID(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if member.Role == role {
+		return nil
+	}
+
+	if member.Role == types.OrgRoleAdmin {
+		hasOtherAdmins, _, err := basestore.ScanFirstBool(tx.Query(ctx, sqlf.Sprintf(otherOrgAdminsExistQuery, orgID, userID)))
+		if err != nil {
+			return err
+		}
+		if !hasOtherAdmins {
+			return ErrLastOrgAdmin
+		}
+	}
+
+	return tx.Exec(ctx, sqlf.Sprintf(setOrgMemberRoleQuery, role, orgID, userID))
+}
+
+const otherOrgAdminsExistQuery = `
+-- source: internal/database/org_members.go:SetRole
+SELECT EXISTS (
+	SELECT 1
+	FROM org_members
+	JOIN users ON users.id = org_members.user_id
+	WHERE
+		org_members.org_id = %s AND
+		org_members.user_id != %s AND
+		org_members.role = 'admin' AND
+		users.deleted_at IS NULL
+)
+`
+
+const setOrgMemberRoleQuery = `
+-- source: internal/database/org_members.go:SetRole
+UPDATE org_members SET role = %s, updated_at = now() WHERE org_id = %s AND user_id = %s
+`
+
 func (m *OrgMemberStore) GetByUserID(ctx context.Context, userID int32) ([]*types.OrgMembership, error) {
 	return m.getBySQL(ctx, "INNER JOIN users ON org_members.user_id=users.id WHERE org_members.user_id=$1 AND users.deleted_at IS NULL", userID)
 }
@@ -81,6 +150,29 @@ func (m *OrgMemberStore) GetByOrgID(ctx context.Context, orgID int32) ([]*types.
 	return m.getBySQL(ctx, "INNER JOIN users ON org_members.user_id = users.id WHERE org_id=$1 AND users.deleted_at IS NULL ORDER BY upper(users.display_name), users.id", org.ID)
 }
 
+// ErrInsufficientOrgRole is returned by CheckOrgAccess when the user is a member of the
+// organization, but does not have the required role.
+var ErrInsufficientOrgRole = errors.New("user does not have the required role in the organization")
+
+// CheckOrgAccess returns an error if the user is not a member of the organization with at least
+// the required role. It returns an ErrOrgMemberNotFound if the user is not a member, and
+// ErrInsufficientOrgRole if the role of the user is insufficient.
+func (m *OrgMemberStore) CheckOrgAccess(ctx context.Context, userID, orgID int32, requiredRole types.OrgRole) error {
+	member, err := m.GetByOrgIDAndUserID(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	// Be robust in case GetByOrgIDAndUserID changes so that lack of membership returns
+	// a nil error.
+	if member == nil {
+		return &ErrOrgMemberNotFound{[]interface{}{orgID, userID}}
+	}
+	if !member.Role.Includes(requiredRole) {
+		return ErrInsufficientOrgRole
+	}
+	return nil
+}
+
 // ErrOrgMemberNotFound is the error that is returned when
 // a user is not in an org.
 type ErrOrgMemberNotFound struct {
@@ -105,7 +197,7 @@ func (m *OrgMemberStore) getOneBySQL(ctx context.Context, query string, args ...
 }
 
 func (m *OrgMemberStore) getBySQL(ctx context.Context, query string, args ...interface{}) ([]*types.OrgMembership, error) {
-	rows, err := m.Handle().DB().QueryContext(ctx, "SELECT org_members.id, org_members.org_id, org_members.user_id, org_members.created_at, org_members.updated_at FROM org_members "+query, args...)
+	rows, err := m.Handle().DB().QueryContext(ctx, "SELECT org_members.id, org_members.org_id, org_members.user_id, org_members.role, org_members.created_at, org_members.updated_at FROM org_members "+query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -114,7 +206,7 @@ func (m *OrgMemberStore) getBySQL(ctx context.Context, query string, args ...int
 	defer rows.Close()
 	for rows.Next() {
 		m := types.OrgMembership{}
-		err := rows.Scan(&m.ID, &m.OrgID, &m.UserID, &m.CreatedAt, &m.UpdatedAt)
+		err := rows.Scan(&m.ID, &m.OrgID, &m.UserID, &m.Role, &m.CreatedAt, &m.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -110,3 +110,67 @@ func TestOrgMembers_CreateMembershipInOrgsForAllUsers(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestOrgMembers_Roles(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	ctx := context.Background()
+
+	org, err := Orgs(db).Create(ctx, "org", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var users []*types.User
+	for _, name := range []string{"u1", "u2"} {
+		user, err := Users(db).Create(ctx, NewUser{Username: name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		users = append(users, user)
+	}
+	admin, member := users[0], users[1]
+
+	if _, err := OrgMembers(db).CreateWithRole(ctx, org.ID, admin.ID, types.OrgRoleAdmin); err != nil {
+		t.Fatal(err)
+	}
+	m, err := OrgMembers(db).Create(ctx, org.ID, member.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Role != types.OrgRoleMember {
+		t.Fatalf("want role %q, got %q", types.OrgRoleMember, m.Role)
+	}
+
+	checkAccess := func(userID int32, role types.OrgRole, want error) {
+		t.Helper()
+		if err := OrgMembers(db).CheckOrgAccess(ctx, userID, org.ID, role); !errors.Is(err, want) {
+			t.Errorf("user %d, role %q: want %v, got %v", userID, role, want, err)
+		}
+	}
+	checkAccess(admin.ID, types.OrgRoleAdmin, nil)
+	checkAccess(admin.ID, types.OrgRoleMember, nil)
+	checkAccess(member.ID, types.OrgRoleMember, nil)
+	checkAccess(member.ID, types.OrgRoleAdmin, ErrInsufficientOrgRole)
+
+	// The last admin cannot be demoted.
+	if err := OrgMembers(db).SetRole(ctx, org.ID, admin.ID, types.OrgRoleMember); err != ErrLastOrgAdmin {
+		t.Fatalf("want ErrLastOrgAdmin, got %v", err)
+	}
+
+	if err := OrgMembers(db).SetRole(ctx, org.ID, member.ID, types.OrgRoleAdmin); err != nil {
+		t.Fatal(err)
+	}
+	if err := OrgMembers(db).SetRole(ctx, org.ID, admin.ID, types.OrgRoleMember); err != nil {
+		t.Fatal(err)
+	}
+	checkAccess(admin.ID, types.OrgRoleAdmin, ErrInsufficientOrgRole)
+	checkAccess(member.ID, types.OrgRoleAdmin, nil)
+
+	if err := OrgMembers(db).SetRole(ctx, org.ID, admin.ID, "owner"); err == nil {
+		t.Fatal("want error setting an invalid role, got none")
+	}
+}
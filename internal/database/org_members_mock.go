@@ -9,6 +9,7 @@ import (
 
 type MockOrgMembers struct {
 	GetByOrgIDAndUserID func(ctx context.Context, orgID, userID int32) (*types.OrgMembership, error)
+	SetRole             func(ctx context.Context, orgID, userID int32, role types.OrgRole) error
 }
 
 func (s *MockOrgMembers) MockGetByOrgIDAndUserID_Return(t *testing.T, returns *types.OrgMembership, returnsErr error) (called *bool) {
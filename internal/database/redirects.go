@@ -0,0 +1,219 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// RedirectKind is the kind of resource that a redirect applies to.
+type RedirectKind string
+
+const (
+	RedirectKindRepo RedirectKind = "repo"
+	RedirectKindUser RedirectKind = "user"
+)
+
+// Redirect records that a repository or user was renamed, so that links to its old name keep
+// working after the code host stops reporting the rename.
+type Redirect struct {
+	ID        int64
+	Kind      RedirectKind
+	OldName   string
+	NewName   string
+	Reason    string
+	CreatedAt time.Time
+}
+
+// ErrRedirectNotFound is returned when a redirect does not exist.
+var ErrRedirectNotFound = errors.New("redirect not found")
+
+// RedirectStore stores the redirects from the old names of renamed repositories and users to
+// their new names.
+//
+// 🚨 SECURITY: The store does not check that the caller is allowed to see the repositories. Callers
+// must only follow a redirect to a repository that the actor can see.
+type RedirectStore struct {
+	*basestore.Store
+}
+
+// Redirects instantiates and returns a new RedirectStore.
+func Redirects(db dbutil.DB) *RedirectStore {
+	return &RedirectStore{Store: basestore.NewWithDB(db, sql.TxOptions{})}
+}
+
+// RedirectsWith instantiates and returns a new RedirectStore using the other store handle.
+func RedirectsWith(other basestore.ShareableStore) *RedirectStore {
+	return &RedirectStore{Store: basestore.NewWithHandle(other.Handle())}
+}
+
+func (s *RedirectStore) With(other basestore.ShareableStore) *RedirectStore {
+	return &RedirectStore{Store: s.Store.With(other)}
+}
+
+func (s *RedirectStore) Transact(ctx context.Context) (*RedirectStore, error) {
+	txBase, err := s.Store.Transact(ctx)
+	return &RedirectStore{Store: txBase}, err
+}
+
+// Create records that the resource of the given kind named oldName is now named newName. If a
+// redirect from oldName already exists, it is replaced.
+//
+// Redirects are never chained: existing redirects to oldName are updated to point to newName. A
+// redirect from newName is deleted, since the name is now taken. Renames that only change the case
+// of a name are not recorded, since names are case-insensitive.
+func (s *RedirectStore) Create(ctx context.Context, kind RedirectKind, oldName, newName, reason string) (err error) {
+	if strings.EqualFold(oldName, newName) {
+		return nil
+	}
+
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	if err := tx.Exec(ctx, sqlf.Sprintf(deleteRedirectsFromNameQuery, kind, newName)); err != nil {
+		return err
+	}
+	if err := tx.Exec(ctx, sqlf.Sprintf(updateRedirectsToNameQuery, newName, kind, oldName)); err != nil {
+		return err
+	}
+	return tx.Exec(ctx, sqlf.Sprintf(createRedirectQuery, kind, oldName, newName, reason))
+}
+
+const deleteRedirectsFromNameQuery = `
+-- source: internal/database/redirects.go:Create
+DELETE FROM redirects WHERE kind = %s AND old_name = %s
+`
+
+const updateRedirectsToNameQuery = `
+-- source: internal/database/redirects.go:Create
+UPDATE redirects SET new_name = %s WHERE kind = %s AND new_name = %s
+`
+
+const createRedirectQuery = `
+-- source: internal/database/redirects.go:Create
+INSERT INTO redirects (kind, old_name, new_name, reason)
+VALUES (%s, %s, %s, %s)
+ON CONFLICT (kind, old_name) DO UPDATE
+SET new_name = EXCLUDED.new_name, reason = EXCLUDED.reason, created_at = now()
+`
+
+// Resolve returns the current name of the resource of the given kind that was named name, or
+// ErrRedirectNotFound.
+func (s *RedirectStore) Resolve(ctx context.Context, kind RedirectKind, name string) (string, error) {
+	if Mocks.Redirects.Resolve != nil {
+		return Mocks.Redirects.Resolve(ctx, kind, name)
+	}
+
+	newName, ok, err := basestore.ScanFirstString(s.Query(ctx, sqlf.Sprintf(resolveRedirectQuery, kind, name)))
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", ErrRedirectNotFound
+	}
+	return newName, nil
+}
+
+const resolveRedirectQuery = `
+-- source: internal/database/redirects.go:Resolve
+SELECT new_name FROM redirects WHERE kind = %s AND old_name = %s
+`
+
+// GetByID returns the redirect with the given ID, or ErrRedirectNotFound.
+func (s *RedirectStore) GetByID(ctx context.Context, id int64) (*Redirect, error) {
+	redirects, err := scanRedirects(s.Query(ctx, sqlf.Sprintf(getRedirectByIDQuery, id)))
+	if err != nil {
+		return nil, err
+	}
+	if len(redirects) == 0 {
+		return nil, ErrRedirectNotFound
+	}
+	return redirects[0], nil
+}
+
+const getRedirectByIDQuery = `
+-- source: internal/database/redirects.go:GetByID
+SELECT id, kind, old_name, new_name, reason, created_at
+FROM redirects
+WHERE id = %s
+`
+
+// RedirectsListOptions contains the options for listing redirects.
+type RedirectsListOptions struct {
+	// Kind, if set, only lists the redirects of the given kind.
+	Kind RedirectKind
+	// Name, if set, only lists the redirects from or to the given name.
+	Name string
+	*LimitOffset
+}
+
+// List returns the redirects matching the options, newest first.
+func (s *RedirectStore) List(ctx context.Context, opt RedirectsListOptions) ([]*Redirect, error) {
+	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if opt.Kind != "" {
+		conds = append(conds, sqlf.Sprintf("kind = %s", opt.Kind))
+	}
+	if opt.Name != "" {
+		conds = append(conds, sqlf.Sprintf("(old_name = %s OR new_name = %s)", opt.Name, opt.Name))
+	}
+	return scanRedirects(s.Query(ctx, sqlf.Sprintf(listRedirectsQuery, sqlf.Join(conds, "AND"), opt.LimitOffset.SQL())))
+}
+
+const listRedirectsQuery = `
+-- source: internal/database/redirects.go:List
+SELECT id, kind, old_name, new_name, reason, created_at
+FROM redirects
+WHERE %s
+ORDER BY created_at DESC, id DESC
+%s
+`
+
+// Delete deletes the redirect with the given ID, or returns ErrRedirectNotFound.
+func (s *RedirectStore) Delete(ctx context.Context, id int64) error {
+	res, err := s.ExecResult(ctx, sqlf.Sprintf(deleteRedirectQuery, id))
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrRedirectNotFound
+	}
+	return nil
+}
+
+const deleteRedirectQuery = `
+-- source: internal/database/redirects.go:Delete
+DELETE FROM redirects WHERE id = %s
+`
+
+func scanRedirects(rows *sql.Rows, queryErr error) (_ []*Redirect, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var redirects []*Redirect
+	for rows.Next() {
+		var r Redirect
+		if err := rows.Scan(&r.ID, &r.Kind, &r.OldName, &r.NewName, &r.Reason, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		redirects = append(redirects, &r)
+	}
+	return redirects, nil
+}
+
+type MockRedirects struct {
+	Resolve func(ctx context.Context, kind RedirectKind, name string) (string, error)
+}
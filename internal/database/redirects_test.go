@@ -0,0 +1,118 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+)
+
+func TestRedirects(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	ctx := context.Background()
+	store := Redirects(db)
+
+	resolve := func(kind RedirectKind, name string) string {
+		t.Helper()
+		newName, err := store.Resolve(ctx, kind, name)
+		if errors.Is(err, ErrRedirectNotFound) {
+			return ""
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		return newName
+	}
+
+	// a -> b -> c: redirects are not chained.
+	if err := store.Create(ctx, RedirectKindRepo, "github.com/o/a", "github.com/o/b", "renamed"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Create(ctx, RedirectKindRepo, "github.com/o/b", "github.com/o/c", "renamed"); err != nil {
+		t.Fatal(err)
+	}
+	if have := resolve(RedirectKindRepo, "GitHub.com/o/A"); have != "github.com/o/c" {
+		t.Fatalf("a: want redirect to c, have %q", have)
+	}
+	if have := resolve(RedirectKindUser, "github.com/o/a"); have != "" {
+		t.Fatalf("a: want no user redirect, have %q", have)
+	}
+
+	// c -> a: a is taken again, so it no longer redirects.
+	if err := store.Create(ctx, RedirectKindRepo, "github.com/o/c", "github.com/o/a", "renamed"); err != nil {
+		t.Fatal(err)
+	}
+	if have := resolve(RedirectKindRepo, "github.com/o/a"); have != "" {
+		t.Fatalf("a: want no redirect, have %q", have)
+	}
+	if have := resolve(RedirectKindRepo, "github.com/o/b"); have != "github.com/o/a" {
+		t.Fatalf("b: want redirect to a, have %q", have)
+	}
+
+	// Case-only renames are not recorded.
+	if err := store.Create(ctx, RedirectKindRepo, "github.com/o/a", "github.com/o/A", "renamed"); err != nil {
+		t.Fatal(err)
+	}
+
+	redirects, err := store.List(ctx, RedirectsListOptions{Kind: RedirectKindRepo})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var have [][2]string
+	for _, r := range redirects {
+		have = append(have, [2]string{r.OldName, r.NewName})
+	}
+	want := [][2]string{{"github.com/o/c", "github.com/o/a"}, {"github.com/o/b", "github.com/o/a"}}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Fatalf("unexpected redirects (-want +have):\n%s", diff)
+	}
+
+	r, err := store.GetByID(ctx, redirects[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(redirects[0], r); diff != "" {
+		t.Fatalf("unexpected redirect (-want +have):\n%s", diff)
+	}
+
+	if err := store.Delete(ctx, r.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete(ctx, r.ID); !errors.Is(err, ErrRedirectNotFound) {
+		t.Fatalf("want ErrRedirectNotFound, have %v", err)
+	}
+	if _, err := store.GetByID(ctx, r.ID); !errors.Is(err, ErrRedirectNotFound) {
+		t.Fatalf("want ErrRedirectNotFound, have %v", err)
+	}
+}
+
+func TestRedirects_UserRename(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	ctx := context.Background()
+
+	user, err := Users(db).Create(ctx, NewUser{Username: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Users(db).Update(ctx, user.ID, UserUpdate{Username: "alice2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	newName, err := Redirects(db).Resolve(ctx, RedirectKindUser, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newName != "alice2" {
+		t.Fatalf("want redirect to alice2, have %q", newName)
+	}
+}
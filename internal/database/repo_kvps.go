@@ -0,0 +1,198 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// RepoKVP is a key-value pair of custom metadata of a repository. A nil Value
+// denotes a tag without a value.
+type RepoKVP struct {
+	RepoID api.RepoID
+	Key    string
+	Value  *string
+}
+
+// RepoKVPFilter matches the repositories that have a key-value pair with the given
+// key. If Value is non-nil, the value of the pair must be equal to it.
+type RepoKVPFilter struct {
+	Key   string
+	Value *string
+}
+
+// ErrRepoKVPNotFound is returned when a key-value pair of a repository does not
+// exist.
+var ErrRepoKVPNotFound = errors.New("repository key-value pair not found")
+
+// RepoKVPStore stores the custom key-value metadata of repositories.
+//
+// 🚨 SECURITY: The store does not check that the caller is allowed to see or modify
+// the repositories. Callers must do so.
+type RepoKVPStore struct {
+	*basestore.Store
+}
+
+// RepoKVPs instantiates and returns a new RepoKVPStore.
+func RepoKVPs(db dbutil.DB) *RepoKVPStore {
+	return &RepoKVPStore{Store: basestore.NewWithDB(db, sql.TxOptions{})}
+}
+
+// RepoKVPsWith instantiates and returns a new RepoKVPStore using the other store
+// handle.
+func RepoKVPsWith(other basestore.ShareableStore) *RepoKVPStore {
+	return &RepoKVPStore{Store: basestore.NewWithHandle(other.Handle())}
+}
+
+func (s *RepoKVPStore) With(other basestore.ShareableStore) *RepoKVPStore {
+	return &RepoKVPStore{Store: s.Store.With(other)}
+}
+
+func (s *RepoKVPStore) Transact(ctx context.Context) (*RepoKVPStore, error) {
+	txBase, err := s.Store.Transact(ctx)
+	return &RepoKVPStore{Store: txBase}, err
+}
+
+// List returns the key-value pairs of the given repository, ordered by key.
+func (s *RepoKVPStore) List(ctx context.Context, repoID api.RepoID) ([]RepoKVP, error) {
+	return scanRepoKVPs(s.Query(ctx, sqlf.Sprintf(listRepoKVPsQuery, repoID)))
+}
+
+const listRepoKVPsQuery = `
+-- source: internal/database/repo_kvps.go:List
+SELECT repo_id, key, value
+FROM repo_kvps
+WHERE repo_id = %s
+ORDER BY key
+`
+
+// Get returns the key-value pair of the given repository with the given key, or
+// ErrRepoKVPNotFound.
+func (s *RepoKVPStore) Get(ctx context.Context, repoID api.RepoID, key string) (RepoKVP, error) {
+	kvps, err := scanRepoKVPs(s.Query(ctx, sqlf.Sprintf(getRepoKVPQuery, repoID, key)))
+	if err != nil {
+		return RepoKVP{}, err
+	}
+	if len(kvps) == 0 {
+		return RepoKVP{}, ErrRepoKVPNotFound
+	}
+	return kvps[0], nil
+}
+
+const getRepoKVPQuery = `
+-- source: internal/database/repo_kvps.go:Get
+SELECT repo_id, key, value
+FROM repo_kvps
+WHERE repo_id = %s AND key = %s
+`
+
+// Create adds the key-value pair to its repository. It fails if the repository
+// already has a pair with the same key.
+func (s *RepoKVPStore) Create(ctx context.Context, kvp RepoKVP) error {
+	return s.Exec(ctx, sqlf.Sprintf(createRepoKVPQuery, kvp.RepoID, kvp.Key, kvp.Value))
+}
+
+const createRepoKVPQuery = `
+-- source: internal/database/repo_kvps.go:Create
+INSERT INTO repo_kvps (repo_id, key, value)
+VALUES (%s, %s, %s)
+`
+
+// Update sets the value of an existing key-value pair and returns the updated pair,
+// or ErrRepoKVPNotFound.
+func (s *RepoKVPStore) Update(ctx context.Context, kvp RepoKVP) (RepoKVP, error) {
+	kvps, err := scanRepoKVPs(s.Query(ctx, sqlf.Sprintf(updateRepoKVPQuery, kvp.Value, kvp.RepoID, kvp.Key)))
+	if err != nil {
+		return RepoKVP{}, err
+	}
+	if len(kvps) == 0 {
+		return RepoKVP{}, ErrRepoKVPNotFound
+	}
+	return kvps[0], nil
+}
+
+const updateRepoKVPQuery = `
+-- source: internal/database/repo_kvps.go:Update
+UPDATE repo_kvps
+SET value = %s
+WHERE repo_id = %s AND key = %s
+RETURNING repo_id, key, value
+`
+
+// Delete removes the key-value pair of the given repository with the given key. It
+// is not an error if the pair does not exist.
+func (s *RepoKVPStore) Delete(ctx context.Context, repoID api.RepoID, key string) error {
+	return s.Exec(ctx, sqlf.Sprintf(deleteRepoKVPQuery, repoID, key))
+}
+
+const deleteRepoKVPQuery = `
+-- source: internal/database/repo_kvps.go:Delete
+DELETE FROM repo_kvps
+WHERE repo_id = %s AND key = %s
+`
+
+// BulkUpsert sets the key-value pair with the given key and value on all of the given
+// repositories, replacing the value of the repositories that already have the key.
+func (s *RepoKVPStore) BulkUpsert(ctx context.Context, repoIDs []api.RepoID, key string, value *string) error {
+	if len(repoIDs) == 0 {
+		return nil
+	}
+	ids := make([]int64, 0, len(repoIDs))
+	for _, id := range repoIDs {
+		ids = append(ids, int64(id))
+	}
+	return s.Exec(ctx, sqlf.Sprintf(bulkUpsertRepoKVPsQuery, key, value, pq.Array(ids)))
+}
+
+const bulkUpsertRepoKVPsQuery = `
+-- source: internal/database/repo_kvps.go:BulkUpsert
+INSERT INTO repo_kvps (repo_id, key, value)
+SELECT repo.id, %s, %s
+FROM repo
+WHERE repo.id = ANY(%s) AND repo.deleted_at IS NULL
+ON CONFLICT (repo_id, key) DO UPDATE
+SET value = EXCLUDED.value
+`
+
+// BulkDelete removes the key-value pair with the given key from all of the given
+// repositories.
+func (s *RepoKVPStore) BulkDelete(ctx context.Context, repoIDs []api.RepoID, key string) error {
+	if len(repoIDs) == 0 {
+		return nil
+	}
+	ids := make([]int64, 0, len(repoIDs))
+	for _, id := range repoIDs {
+		ids = append(ids, int64(id))
+	}
+	return s.Exec(ctx, sqlf.Sprintf(bulkDeleteRepoKVPsQuery, key, pq.Array(ids)))
+}
+
+const bulkDeleteRepoKVPsQuery = `
+-- source: internal/database/repo_kvps.go:BulkDelete
+DELETE FROM repo_kvps
+WHERE key = %s AND repo_id = ANY(%s)
+`
+
+func scanRepoKVPs(rows *sql.Rows, queryErr error) (_ []RepoKVP, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var kvps []RepoKVP
+	for rows.Next() {
+		var kvp RepoKVP
+		if err := rows.Scan(&kvp.RepoID, &kvp.Key, &kvp.Value); err != nil {
+			return nil, err
+		}
+		kvps = append(kvps, kvp)
+	}
+	return kvps, nil
+}
@@ -0,0 +1,125 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestRepoKVPs(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	ctx := actor.WithInternalActor(context.Background())
+	store := RepoKVPs(db)
+
+	repos := types.Repos(mustCreate(ctx, t, db, types.MakeGithubRepo()))
+	repos = append(repos, mustCreate(ctx, t, db, types.MakeGitlabRepo())...)
+	r1, r2 := repos[0].ID, repos[1].ID
+
+	strPtr := func(s string) *string { return &s }
+
+	if err := store.Create(ctx, RepoKVP{RepoID: r1, Key: "team", Value: strPtr("search")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Create(ctx, RepoKVP{RepoID: r1, Key: "archived"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Create(ctx, RepoKVP{RepoID: r1, Key: "team", Value: strPtr("batches")}); err == nil {
+		t.Fatal("expected error creating duplicate key")
+	}
+
+	t.Run("List", func(t *testing.T) {
+		have, err := store.List(ctx, r1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []RepoKVP{{RepoID: r1, Key: "archived"}, {RepoID: r1, Key: "team", Value: strPtr("search")}}
+		if diff := cmp.Diff(want, have); diff != "" {
+			t.Errorf("unexpected key-value pairs (-want +have):\n%s", diff)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		have, err := store.Update(ctx, RepoKVP{RepoID: r1, Key: "team", Value: strPtr("code-intel")})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(RepoKVP{RepoID: r1, Key: "team", Value: strPtr("code-intel")}, have); diff != "" {
+			t.Errorf("unexpected key-value pair (-want +have):\n%s", diff)
+		}
+
+		if _, err := store.Update(ctx, RepoKVP{RepoID: r2, Key: "team"}); !errors.Is(err, ErrRepoKVPNotFound) {
+			t.Errorf("unexpected error: want %v, have %v", ErrRepoKVPNotFound, err)
+		}
+	})
+
+	t.Run("BulkUpsert", func(t *testing.T) {
+		if err := store.BulkUpsert(ctx, []api.RepoID{r1, r2}, "tier", strPtr("1")); err != nil {
+			t.Fatal(err)
+		}
+		for _, id := range []api.RepoID{r1, r2} {
+			have, err := store.Get(ctx, id, "tier")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(RepoKVP{RepoID: id, Key: "tier", Value: strPtr("1")}, have); diff != "" {
+				t.Errorf("unexpected key-value pair (-want +have):\n%s", diff)
+			}
+		}
+	})
+
+	t.Run("ReposList", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			filters []RepoKVPFilter
+			want    []string
+		}{
+			"key":              {filters: []RepoKVPFilter{{Key: "tier"}}, want: []string{string(repos[0].Name), string(repos[1].Name)}},
+			"key and value":    {filters: []RepoKVPFilter{{Key: "team", Value: strPtr("code-intel")}}, want: []string{string(repos[0].Name)}},
+			"multiple filters": {filters: []RepoKVPFilter{{Key: "tier"}, {Key: "archived"}}, want: []string{string(repos[0].Name)}},
+			"no match":         {filters: []RepoKVPFilter{{Key: "team", Value: strPtr("search")}}, want: nil},
+		} {
+			t.Run(name, func(t *testing.T) {
+				have, err := Repos(db).List(ctx, ReposListOptions{KVPFilters: tc.filters, OrderBy: RepoListOrderBy{{Field: RepoListID}}})
+				if err != nil {
+					t.Fatal(err)
+				}
+				var names []string
+				for _, r := range have {
+					names = append(names, string(r.Name))
+				}
+				if diff := cmp.Diff(tc.want, names); diff != "" {
+					t.Errorf("unexpected repositories (-want +have):\n%s", diff)
+				}
+			})
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		if err := store.Delete(ctx, r1, "team"); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.BulkDelete(ctx, []api.RepoID{r1, r2}, "tier"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.Get(ctx, r1, "team"); !errors.Is(err, ErrRepoKVPNotFound) {
+			t.Errorf("unexpected error: want %v, have %v", ErrRepoKVPNotFound, err)
+		}
+		have, err := store.List(ctx, r2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(have) != 0 {
+			t.Errorf("unexpected key-value pairs: %+v", have)
+		}
+	})
+}
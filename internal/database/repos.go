@@ -30,8 +30,10 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitolite"
@@ -229,6 +231,86 @@ func (s *RepoStore) GetByName(ctx context.Context, nameOrURI api.RepoName) (_ *t
 	return repos[0], repos[0].IsBlocked()
 }
 
+// GetByNamesOptions configures how GetByNames matches repository names.
+type GetByNamesOptions struct {
+	// CaseSensitive, if true, only matches names with the same casing. Names
+	// are matched case-insensitively otherwise, like GetByName does.
+	CaseSensitive bool
+
+	// IncludeAliases, if true, also matches the names against the URIs of the
+	// repositories, which differ from their names if the code host connection
+	// configures a non-default repositoryPathPattern. A match on the name is
+	// preferred.
+	IncludeAliases bool
+}
+
+// GetByNames returns the repositories with the given names in a single query,
+// keyed by the given name. Names without a matching repository are missing from the
+// result.
+//
+// Unlike GetByName, GetByNames doesn't return an error for blocked
+// repositories: callers must check Repo.IsBlocked themselves.
+func (s *RepoStore) GetByNames(ctx context.Context, names []api.RepoName, opts GetByNamesOptions) (_ map[api.RepoName]*types.Repo, err error) {
+	if Mocks.Repos.GetByNames != nil {
+		return Mocks.Repos.GetByNames(ctx, names, opts)
+	}
+	s.ensureStore()
+
+	tr, ctx := trace.New(ctx, "repos.GetByNames", "")
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	result := make(map[api.RepoName]*types.Repo, len(names))
+	if len(names) == 0 {
+		return result, nil
+	}
+
+	strNames := make([]string, len(names))
+	for i, name := range names {
+		strNames[i] = string(name)
+	}
+	listOpts := ReposListOptions{
+		Names:          strNames,
+		IncludeBlocked: true,
+	}
+	if opts.IncludeAliases {
+		listOpts.URIs = strNames
+		listOpts.UseOr = true
+	}
+	repos, err := s.listRepos(ctx, tr, listOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	// The names are always matched case-insensitively in the query, which
+	// allows it to use the repo_name_idx index.
+	key := func(name string) string {
+		if opts.CaseSensitive {
+			return name
+		}
+		return strings.ToLower(name)
+	}
+	byName := make(map[string]*types.Repo, len(repos))
+	byURI := make(map[string]*types.Repo, len(repos))
+	for _, r := range repos {
+		byName[key(string(r.Name))] = r
+		if r.URI != "" {
+			byURI[key(r.URI)] = r
+		}
+	}
+
+	for _, name := range names {
+		if r, ok := byName[key(string(name))]; ok {
+			result[name] = r
+		} else if r, ok := byURI[key(string(name))]; ok && opts.IncludeAliases {
+			result[name] = r
+		}
+	}
+	return result, nil
+}
+
 // GetByIDs returns a list of repositories by given IDs. The number of results list could be less
 // than the candidate list due to no repository is associated with some IDs.
 func (s *RepoStore) GetByIDs(ctx context.Context, ids ...api.RepoID) (_ []*types.Repo, err error) {
@@ -465,6 +547,10 @@ func scanRepo(rows *sql.Rows, r *types.Repo) (err error) {
 		r.Metadata = new(bitbucketserver.Repo)
 	case extsvc.TypeBitbucketCloud:
 		r.Metadata = new(bitbucketcloud.Repo)
+	case extsvc.TypeGitea:
+		r.Metadata = new(gitea.Repo)
+	case extsvc.TypeAzureDevOps:
+		r.Metadata = new(azuredevops.Repository)
 	case extsvc.TypeAWSCodeCommit:
 		r.Metadata = new(awscodecommit.Repository)
 	case extsvc.TypeGitolite:
@@ -580,6 +666,10 @@ type ReposListOptions struct {
 	// OnlyPrivate excludes non-private repositories from the list.
 	OnlyPrivate bool
 
+	// KVPFilters restricts the list to the repositories that have a matching
+	// key-value pair for every filter.
+	KVPFilters []RepoKVPFilter
+
 	// Index when set will only include repositories which should be indexed
 	// if true. If false it will exclude repositories which should be
 	// indexed. An example use case of this is for indexed search only
@@ -928,6 +1018,13 @@ func (s *RepoStore) listSQL(ctx context.Context, opt ReposListOptions) (*sqlf.Qu
 	if opt.OnlyPrivate {
 		where = append(where, sqlf.Sprintf("private"))
 	}
+	for _, filter := range opt.KVPFilters {
+		if filter.Value != nil {
+			where = append(where, sqlf.Sprintf("EXISTS (SELECT 1 FROM repo_kvps WHERE repo_id = repo.id AND key = %s AND value = %s)", filter.Key, *filter.Value))
+		} else {
+			where = append(where, sqlf.Sprintf("EXISTS (SELECT 1 FROM repo_kvps WHERE repo_id = repo.id AND key = %s)", filter.Key))
+		}
+	}
 
 	if len(opt.Names) > 0 {
 		lowerNames := make([]string, len(opt.Names))
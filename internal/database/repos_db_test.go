@@ -308,6 +308,74 @@ func TestRepos_GetByIDs(t *testing.T) {
 	}
 }
 
+func TestRepos_GetByNames(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	ctx := actor.WithInternalActor(context.Background())
+
+	sourcegraph := mustCreate(ctx, t, db, &types.Repo{
+		Name:         "github.com/sourcegraph/Sourcegraph",
+		URI:          "github.com/sourcegraph/Sourcegraph",
+		ExternalRepo: api.ExternalRepoSpec{ID: "a", ServiceType: "b", ServiceID: "c"},
+	})[0]
+	srcCLI := mustCreate(ctx, t, db, &types.Repo{
+		Name:         "sourcegraph/src-cli",
+		URI:          "github.com/sourcegraph/src-cli",
+		ExternalRepo: api.ExternalRepoSpec{ID: "d", ServiceType: "b", ServiceID: "c"},
+	})[0]
+
+	names := []api.RepoName{
+		"github.com/sourcegraph/sourcegraph",
+		"github.com/sourcegraph/Sourcegraph",
+		"github.com/sourcegraph/src-cli",
+		"github.com/sourcegraph/missing",
+	}
+
+	for _, tc := range []struct {
+		name string
+		opts GetByNamesOptions
+		want map[api.RepoName]*types.Repo
+	}{
+		{
+			name: "case-insensitive",
+			want: map[api.RepoName]*types.Repo{
+				"github.com/sourcegraph/sourcegraph": sourcegraph,
+				"github.com/sourcegraph/Sourcegraph": sourcegraph,
+			},
+		},
+		{
+			name: "case-sensitive",
+			opts: GetByNamesOptions{CaseSensitive: true},
+			want: map[api.RepoName]*types.Repo{
+				"github.com/sourcegraph/Sourcegraph": sourcegraph,
+			},
+		},
+		{
+			name: "aliases",
+			opts: GetByNamesOptions{IncludeAliases: true},
+			want: map[api.RepoName]*types.Repo{
+				"github.com/sourcegraph/sourcegraph": sourcegraph,
+				"github.com/sourcegraph/Sourcegraph": sourcegraph,
+				"github.com/sourcegraph/src-cli":     srcCLI,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			have, err := Repos(db).GetByNames(ctx, names, tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !jsonEqual(t, have, tc.want) {
+				t.Errorf("got %v, want %v", have, tc.want)
+			}
+		})
+	}
+}
+
 func TestRepos_List(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
@@ -84,6 +84,18 @@ OR EXISTS ( -- We assume that all repos added by the authenticated user should b
 	WHERE repo_id = repo.id
 	AND user_id = %s
 )
+OR EXISTS ( -- Members of an organization can read the repos added with a token shared by the organization
+	SELECT
+	FROM external_service_repos AS esr
+	JOIN external_services AS es ON (
+			es.id = esr.external_service_id
+		AND es.token_shared = TRUE
+		AND es.deleted_at IS NULL
+	)
+	JOIN org_members AS om ON om.org_id = esr.org_id
+	WHERE esr.repo_id = repo.id
+	AND om.user_id = %s
+)
 OR (                             -- Restricted repositories require checking permissions
 	SELECT object_ids_ints @> INTSET(repo.id)
 	FROM user_permissions
@@ -100,6 +112,7 @@ OR (                             -- Restricted repositories require checking per
 		usePermissionsUserMapping,
 		authenticatedUserID,
 		authenticatedUserID,
+		authenticatedUserID,
 		perms.String(),
 	)
 }
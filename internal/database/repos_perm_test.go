@@ -253,6 +253,108 @@ VALUES (%s, %s, NULLIF(%s, 0), '')
 	}
 }
 
+// 🚨 SECURITY: Tests are necessary to ensure security.
+func TestRepos_orgMemberCanViewSharedPrivateCode(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	db := dbtest.NewDB(t, "")
+	ctx := context.Background()
+
+	// Set up two users who are NOT site admins, only alice is a member of the org
+	alice, err := Users(db).Create(ctx, NewUser{
+		Email:                 "alice@example.com",
+		Username:              "alice",
+		Password:              "alice",
+		EmailVerificationCode: "alice",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := Users(db).Create(ctx, NewUser{
+		Email:                 "bob@example.com",
+		Username:              "bob",
+		Password:              "bob",
+		EmailVerificationCode: "bob",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range []*types.User{alice, bob} {
+		if err := Users(db).SetIsSiteAdmin(ctx, u.ID, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	org, err := Orgs(db).Create(ctx, "acme", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OrgMembers(db).Create(ctx, org.ID, alice.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	internalCtx := actor.WithInternalActor(ctx)
+	orgPrivateRepo := mustCreate(internalCtx, t, db,
+		&types.Repo{
+			Name:    "org_private_repo",
+			Private: true,
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          "org_private_repo",
+				ServiceType: extsvc.TypeGitHub,
+				ServiceID:   "https://github.com/",
+			},
+		},
+	)[0]
+
+	confGet := func() *conf.Unified {
+		return &conf.Unified{}
+	}
+	orgExternalService := &types.ExternalService{
+		Kind:           extsvc.KindGitHub,
+		DisplayName:    "GITHUB #1",
+		Config:         `{"url": "https://github.com", "repositoryQuery": ["none"], "token": "abc", "authorization": {}}`,
+		NamespaceOrgID: org.ID,
+	}
+	if err := ExternalServices(db).Create(ctx, confGet, orgExternalService); err != nil {
+		t.Fatal(err)
+	}
+
+	// Set it up so that the org added the repo via its external service
+	q := sqlf.Sprintf(`
+INSERT INTO external_service_repos (external_service_id, repo_id, org_id, clone_url)
+VALUES (%s, %s, %s, '')
+`, orgExternalService.ID, orgPrivateRepo.ID, org.ID)
+	if _, err := db.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...); err != nil {
+		t.Fatal(err)
+	}
+
+	aliceCtx := actor.WithActor(ctx, &actor.Actor{UID: alice.ID})
+	bobCtx := actor.WithActor(ctx, &actor.Actor{UID: bob.ID})
+	assertRepos := func(t *testing.T, ctx context.Context, want []*types.Repo) {
+		t.Helper()
+		repos, err := Repos(db).List(ctx, ReposListOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, repos, cmpopts.IgnoreFields(types.Repo{}, "Sources"), cmpopts.EquateEmpty()); diff != "" {
+			t.Fatalf("Mismatch (-want +got):\n%s", diff)
+		}
+	}
+
+	// The token isn't shared yet, so no one should see the repo
+	assertRepos(t, aliceCtx, nil)
+	assertRepos(t, bobCtx, nil)
+
+	if err := ExternalServices(db).SetTokenShared(ctx, orgExternalService.ID, true); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only alice as a member of the org should see the repo
+	assertRepos(t, aliceCtx, []*types.Repo{orgPrivateRepo})
+	assertRepos(t, bobCtx, nil)
+}
+
 func createGitHubExternalService(t *testing.T, db dbutil.DB, userID int32) *types.ExternalService {
 	now := time.Now()
 	svc := &types.ExternalService{
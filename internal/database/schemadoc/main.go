@@ -243,9 +243,11 @@ type table struct {
 }
 
 func getTables(db *sql.DB) (tables []table, _ error) {
-	// Query names of all public tables and views.
+	// Query names of all public tables and views. Partitions are omitted, as they are created
+	// over time (e.g. the monthly partitions of event_logs) and are described by their parent.
 	rows, err := db.Query(`
 		SELECT table_name, FALSE AS is_view FROM information_schema.tables WHERE table_schema = 'public' AND table_type = 'BASE TABLE'
+			AND table_name NOT IN (SELECT relname FROM pg_class WHERE relispartition)
 		UNION
 		SELECT table_name, TRUE AS is_view FROM information_schema.views WHERE table_schema = 'public' AND table_name != 'pg_stat_statements';
 	`)
@@ -285,7 +287,14 @@ func describeTable(db *sql.DB, databaseName string, table table, run runFunc) (s
 		return "", errors.Wrap(err, fmt.Sprintf("run: %s", out))
 	}
 
-	lines := strings.Split(out, "\n")
+	var lines []string
+	for _, line := range strings.Split(out, "\n") {
+		// The number of partitions of a partitioned table varies over time.
+		if strings.HasPrefix(line, "Number of partitions:") {
+			continue
+		}
+		lines = append(lines, line)
+	}
 
 	buf := bytes.NewBuffer(nil)
 	buf.WriteString("# ")
@@ -65,7 +65,7 @@ func searchContextsPermissionsCondition(ctx context.Context, db dbutil.DB) (*sql
 }
 
 const listSearchContextsFmtStr = `
-SELECT sc.id, sc.name, sc.description, sc.public, sc.namespace_user_id, sc.namespace_org_id, sc.updated_at, u.username, o.name
+SELECT sc.id, sc.name, sc.description, sc.public, sc.namespace_user_id, sc.namespace_org_id, sc.updated_at, sc.managed, sc.hidden, u.username, o.name
 FROM search_contexts sc
 LEFT JOIN users u on sc.namespace_user_id = u.id
 LEFT JOIN orgs o on sc.namespace_org_id = o.id
@@ -114,6 +114,8 @@ type ListSearchContextsOptions struct {
 	NamespaceOrgIDs []int32
 	// NoNamespace matches search contexts without a namespace ("instance-level contexts").
 	NoNamespace bool
+	// ExcludeHidden excludes the search contexts hidden by their owners.
+	ExcludeHidden bool
 	// OrderBy specifies the ordering option for search contexts. Search contexts are ordered using SearchContextsOrderByID by default.
 	// SearchContextsOrderBySpec option sorts contexts by coallesced namespace names first
 	// (user name and org name) and then by context name. SearchContextsOrderByUpdatedAt option sorts
@@ -187,6 +189,10 @@ func getSearchContextsQueryConditions(opts ListSearchContextsOptions) ([]*sqlf.Q
 		conds = append(conds, sqlf.Sprintf("COALESCE(u.username, o.name, '') ILIKE %s", "%"+opts.NamespaceName+"%"))
 	}
 
+	if opts.ExcludeHidden {
+		conds = append(conds, sqlf.Sprintf("NOT sc.hidden"))
+	}
+
 	if len(conds) == 0 {
 		// If no conditions are present, append a catch-all condition to avoid a SQL syntax error
 		conds = append(conds, sqlf.Sprintf("1 = 1"))
@@ -301,6 +307,132 @@ func (s *SearchContextsStore) DeleteSearchContext(ctx context.Context, searchCon
 	return s.Exec(ctx, sqlf.Sprintf(deleteSearchContextFmtStr, searchContextID))
 }
 
+const setSearchContextHiddenFmtStr = `
+-- source: internal/database/search_contexts.go:SetSearchContextHidden
+UPDATE search_contexts SET hidden = %s WHERE id = %d AND deleted_at IS NULL
+`
+
+// SetSearchContextHidden hides the search context from the search context selector, or shows it
+// again.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin or has permission to update the search context.
+func (s *SearchContextsStore) SetSearchContextHidden(ctx context.Context, searchContextID int64, hidden bool) error {
+	return s.Exec(ctx, sqlf.Sprintf(setSearchContextHiddenFmtStr, hidden, searchContextID))
+}
+
+// ManagedSearchContextName is the name of the search contexts maintained by SyncManagedSearchContexts.
+const ManagedSearchContextName = "repos"
+
+const (
+	managedUserSearchContextDescription = "Repositories from your code host connections"
+	managedOrgSearchContextDescription  = "Repositories from the code host connections of the organization"
+)
+
+// managedSearchContextNamespaceReposCTE lists the repositories of the code host connections owned
+// by each user and organization.
+const managedSearchContextNamespaceReposCTE = `
+WITH namespace_repos AS (
+	SELECT es.namespace_user_id, es.namespace_org_id, esr.repo_id
+	FROM external_service_repos esr
+	JOIN external_services es ON es.id = esr.external_service_id AND es.deleted_at IS NULL
+	JOIN repo r ON r.id = esr.repo_id AND r.deleted_at IS NULL
+	LEFT JOIN users u ON u.id = es.namespace_user_id
+	LEFT JOIN orgs o ON o.id = es.namespace_org_id
+	WHERE
+		(u.id IS NOT NULL AND u.deleted_at IS NULL) OR
+		(o.id IS NOT NULL AND o.deleted_at IS NULL)
+)
+`
+
+const insertManagedSearchContextsFmtStr = `
+-- source: internal/database/search_contexts.go:SyncManagedSearchContexts
+` + managedSearchContextNamespaceReposCTE + `
+INSERT INTO search_contexts (name, description, public, namespace_user_id, namespace_org_id, managed)
+SELECT DISTINCT
+	%s,
+	CASE WHEN nr.namespace_user_id IS NOT NULL THEN %s ELSE %s END,
+	false,
+	nr.namespace_user_id,
+	nr.namespace_org_id,
+	true
+FROM namespace_repos nr
+WHERE NOT EXISTS (
+	-- Search contexts created by users with the same name are left alone.
+	SELECT FROM search_contexts sc
+	WHERE
+		sc.name = %s AND
+		sc.deleted_at IS NULL AND
+		(sc.namespace_user_id = nr.namespace_user_id OR sc.namespace_org_id = nr.namespace_org_id)
+)
+`
+
+const deleteStaleManagedSearchContextReposFmtStr = `
+-- source: internal/database/search_contexts.go:SyncManagedSearchContexts
+` + managedSearchContextNamespaceReposCTE + `
+DELETE FROM search_context_repos scr
+USING search_contexts sc
+WHERE
+	sc.id = scr.search_context_id AND
+	sc.managed AND
+	NOT EXISTS (
+		SELECT FROM namespace_repos nr
+		WHERE
+			nr.repo_id = scr.repo_id AND
+			(nr.namespace_user_id = sc.namespace_user_id OR nr.namespace_org_id = sc.namespace_org_id)
+	)
+`
+
+const insertManagedSearchContextReposFmtStr = `
+-- source: internal/database/search_contexts.go:SyncManagedSearchContexts
+` + managedSearchContextNamespaceReposCTE + `
+INSERT INTO search_context_repos (search_context_id, repo_id, revision)
+SELECT DISTINCT sc.id, nr.repo_id, 'HEAD'
+FROM search_contexts sc
+JOIN namespace_repos nr ON nr.namespace_user_id = sc.namespace_user_id OR nr.namespace_org_id = sc.namespace_org_id
+WHERE sc.managed AND sc.deleted_at IS NULL
+ON CONFLICT DO NOTHING
+`
+
+const deleteEmptyManagedSearchContextsFmtStr = `
+-- source: internal/database/search_contexts.go:SyncManagedSearchContexts
+DELETE FROM search_contexts sc
+WHERE
+	sc.managed AND
+	NOT EXISTS (SELECT FROM search_context_repos scr WHERE scr.search_context_id = sc.id)
+`
+
+// SyncManagedSearchContexts maintains a private managed search context for every user and
+// organization that owns code host connections, containing the default branch of the
+// repositories of these code host connections. Managed search contexts of namespaces without
+// repositories anymore are deleted.
+//
+// Repository permissions are enforced when the repositories of the search contexts are read.
+func (s *SearchContextsStore) SyncManagedSearchContexts(ctx context.Context) (err error) {
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	for _, q := range []*sqlf.Query{
+		sqlf.Sprintf(
+			insertManagedSearchContextsFmtStr,
+			ManagedSearchContextName,
+			managedUserSearchContextDescription,
+			managedOrgSearchContextDescription,
+			ManagedSearchContextName,
+		),
+		sqlf.Sprintf(deleteStaleManagedSearchContextReposFmtStr),
+		sqlf.Sprintf(insertManagedSearchContextReposFmtStr),
+		sqlf.Sprintf(deleteEmptyManagedSearchContextsFmtStr),
+	} {
+		if err := tx.Exec(ctx, q); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 const insertSearchContextFmtStr = `
 INSERT INTO search_contexts
 (name, description, public, namespace_user_id, namespace_org_id)
@@ -449,6 +581,8 @@ func scanSearchContexts(rows *sql.Rows) ([]*types.SearchContext, error) {
 			&dbutil.NullInt32{N: &sc.NamespaceUserID},
 			&dbutil.NullInt32{N: &sc.NamespaceOrgID},
 			&sc.UpdatedAt,
+			&sc.Managed,
+			&sc.Hidden,
 			&dbutil.NullString{S: &sc.NamespaceUserName},
 			&dbutil.NullString{S: &sc.NamespaceOrgName},
 		)
@@ -857,3 +857,131 @@ func TestSearchContexts_GetAllRevisionsForRepos(t *testing.T) {
 		})
 	}
 }
+
+func TestSearchContexts_SyncManagedSearchContexts(t *testing.T) {
+	db := dbtest.NewDB(t, "")
+	t.Parallel()
+	ctx := actor.WithInternalActor(context.Background())
+	sc := SearchContexts(db)
+
+	user, err := Users(db).Create(ctx, NewUser{Username: "u"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	org, err := Orgs(db).Create(ctx, "org", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repos := []*types.Repo{
+		{Name: "github.com/u/a"},
+		{Name: "github.com/u/b"},
+		{Name: "github.com/org/c"},
+	}
+	if err := Repos(db).Create(ctx, repos...); err != nil {
+		t.Fatal(err)
+	}
+
+	var userServiceID, orgServiceID int64
+	for _, es := range []struct {
+		id              *int64
+		namespaceUserID *int32
+		namespaceOrgID  *int32
+	}{
+		{&userServiceID, &user.ID, nil},
+		{&orgServiceID, nil, &org.ID},
+	} {
+		if err := db.QueryRowContext(ctx,
+			"INSERT INTO external_services (kind, display_name, config, namespace_user_id, namespace_org_id) VALUES ('GITHUB', 'GitHub', '{}', $1, $2) RETURNING id",
+			es.namespaceUserID, es.namespaceOrgID,
+		).Scan(es.id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	addRepo := func(externalServiceID int64, repo *types.Repo) {
+		t.Helper()
+		if _, err := db.ExecContext(ctx, "INSERT INTO external_service_repos (external_service_id, repo_id, clone_url) VALUES ($1, $2, '')", externalServiceID, repo.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	addRepo(userServiceID, repos[0])
+	addRepo(userServiceID, repos[1])
+	addRepo(orgServiceID, repos[2])
+
+	// A search context created by the org with the managed name is left alone.
+	if _, err := createSearchContexts(ctx, sc, []*types.SearchContext{{Name: ManagedSearchContextName, NamespaceOrgID: org.ID}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sc.SyncManagedSearchContexts(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// Syncing is idempotent.
+	if err := sc.SyncManagedSearchContexts(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	managed, err := sc.GetSearchContext(ctx, GetSearchContextOptions{Name: ManagedSearchContextName, NamespaceUserID: user.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !managed.Managed || managed.Public {
+		t.Fatalf("want private managed search context, got %+v", managed)
+	}
+	getRepoIDs := func() []api.RepoID {
+		t.Helper()
+		repoRevs, err := sc.GetSearchContextRepositoryRevisions(ctx, managed.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []api.RepoID
+		for _, rr := range repoRevs {
+			ids = append(ids, rr.Repo.ID)
+		}
+		return ids
+	}
+	if diff := cmp.Diff([]api.RepoID{repos[0].ID, repos[1].ID}, getRepoIDs()); diff != "" {
+		t.Fatalf("unexpected repositories (-want +got):\n%s", diff)
+	}
+
+	orgContext, err := sc.GetSearchContext(ctx, GetSearchContextOptions{Name: ManagedSearchContextName, NamespaceOrgID: org.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if orgContext.Managed {
+		t.Fatal("want the search context of the org not to be managed")
+	}
+
+	// Hidden contexts are excluded on request.
+	if err := sc.SetSearchContextHidden(ctx, managed.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	visible, err := sc.ListSearchContexts(ctx, ListSearchContextsPageOptions{First: 10}, ListSearchContextsOptions{NamespaceUserIDs: []int32{user.ID}, ExcludeHidden: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(visible) != 0 {
+		t.Fatalf("want no visible search contexts, got %d", len(visible))
+	}
+
+	// Removed repositories are removed from the search context.
+	if _, err := db.ExecContext(ctx, "DELETE FROM external_service_repos WHERE repo_id = $1", repos[1].ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := sc.SyncManagedSearchContexts(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]api.RepoID{repos[0].ID}, getRepoIDs()); diff != "" {
+		t.Fatalf("unexpected repositories (-want +got):\n%s", diff)
+	}
+
+	// The search context is deleted once the user has no repositories anymore.
+	if _, err := db.ExecContext(ctx, "DELETE FROM external_service_repos WHERE external_service_id = $1", userServiceID); err != nil {
+		t.Fatal(err)
+	}
+	if err := sc.SyncManagedSearchContexts(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sc.GetSearchContext(ctx, GetSearchContextOptions{Name: ManagedSearchContextName, NamespaceUserID: user.ID}); err != ErrSearchContextNotFound {
+		t.Fatalf("want ErrSearchContextNotFound, got %v", err)
+	}
+}
@@ -8,8 +8,10 @@ import (
 
 	"github.com/cockroachdb/errors"
 	"github.com/inconshreveable/log15"
+	"github.com/keegancsmith/sqlf"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/sentry"
@@ -28,6 +30,13 @@ const (
 	SecurityEventNameSignInFailed    SecurityEventName = "SignInFailed"
 	SecurityEventNameSignInSucceeded SecurityEventName = "SignInSucceeded"
 
+	SecurityEventNameSignInLinkRequested SecurityEventName = "SignInLinkRequested"
+	SecurityEventNameSignInLinkUsed      SecurityEventName = "SignInLinkUsed"
+
+	SecurityEventNameSignInSecondFactorRequired SecurityEventName = "SignInSecondFactorRequired"
+	SecurityEventNameSecurityKeyRegistered      SecurityEventName = "SecurityKeyRegistered"
+	SecurityEventNameSecurityKeyDeleted         SecurityEventName = "SecurityKeyDeleted"
+
 	SecurityEventNameAccountCreated SecurityEventName = "AccountCreated"
 	SecurityEventNameAccountDeleted SecurityEventName = "AccountDeleted"
 	SecurityEventNameAccountNuked   SecurityEventName = "AccountNuked"
@@ -36,7 +45,10 @@ const (
 	SecurityEventNamPasswordRandomized     SecurityEventName = "PasswordRandomized"
 	SecurityEventNamePasswordChanged       SecurityEventName = "PasswordChanged"
 
-	SecurityEventNameEmailVerified SecurityEventName = "EmailVerified"
+	SecurityEventNameEmailVerified           SecurityEventName = "EmailVerified"
+	SecurityEventNameEmailVerificationFailed SecurityEventName = "EmailVerificationFailed"
+	SecurityEventNameEmailVerificationLocked SecurityEventName = "EmailVerificationLocked"
+	SecurityEventNameEmailVerificationResent SecurityEventName = "EmailVerificationResent"
 
 	SecurityEventNameRoleChangeDenied  SecurityEventName = "RoleChangeDenied"
 	SecurityEventNameRoleChangeGranted SecurityEventName = "RoleChangeGranted"
@@ -46,13 +58,18 @@ const (
 
 // SecurityEvent contains information needed for logging a security-relevant event.
 type SecurityEvent struct {
+	// ID is set when the event is read from the store.
+	ID              int64
 	Name            SecurityEventName
 	URL             string
 	UserID          uint32
 	AnonymousUserID string
 	Argument        json.RawMessage
 	Source          string
-	Timestamp       time.Time
+	// Version is the Sourcegraph version that logged the event. It is set when the event is read
+	// from the store.
+	Version   string
+	Timestamp time.Time
 }
 
 // A SecurityEventLogStore provides persistence for security events.
@@ -90,13 +107,119 @@ func (s *SecurityEventLogStore) Insert(ctx context.Context, e *SecurityEvent) er
 	return nil
 }
 
-// LogEvent will log security events.
+// SecurityEventLogsListOptions specifies the options for listing security events.
+type SecurityEventLogsListOptions struct {
+	// UserID, if set, only includes the events of this user.
+	UserID int32
+	// Names, if set, only includes events with one of these names.
+	Names []SecurityEventName
+	// Source, if set, only includes events of this source.
+	Source string
+	// Since and Until, if set, only include events logged at or after Since and before Until.
+	Since, Until *time.Time
+
+	*LimitOffset
+}
+
+func (o SecurityEventLogsListOptions) sqlConditions() []*sqlf.Query {
+	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if o.UserID != 0 {
+		conds = append(conds, sqlf.Sprintf("user_id = %d", o.UserID))
+	}
+	if len(o.Names) > 0 {
+		names := make([]*sqlf.Query, 0, len(o.Names))
+		for _, name := range o.Names {
+			names = append(names, sqlf.Sprintf("%s", string(name)))
+		}
+		conds = append(conds, sqlf.Sprintf("name IN (%s)", sqlf.Join(names, ",")))
+	}
+	if o.Source != "" {
+		conds = append(conds, sqlf.Sprintf("source = %s", o.Source))
+	}
+	if o.Since != nil {
+		conds = append(conds, sqlf.Sprintf(`"timestamp" >= %s`, o.Since.UTC()))
+	}
+	if o.Until != nil {
+		conds = append(conds, sqlf.Sprintf(`"timestamp" < %s`, o.Until.UTC()))
+	}
+	return conds
+}
+
+// List returns the security events matching the options, most recent first.
+func (s *SecurityEventLogStore) List(ctx context.Context, opt SecurityEventLogsListOptions) ([]*SecurityEvent, error) {
+	if Mocks.SecurityEventLogs.List != nil {
+		return Mocks.SecurityEventLogs.List(ctx, opt)
+	}
+
+	q := sqlf.Sprintf(`
+SELECT id, name, url, user_id, anonymous_user_id, source, argument, version, "timestamp"
+FROM security_event_logs
+WHERE %s
+ORDER BY "timestamp" DESC, id DESC
+%s`,
+		sqlf.Join(opt.sqlConditions(), "AND"),
+		opt.LimitOffset.SQL(),
+	)
+	rows, err := s.Query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*SecurityEvent
+	for rows.Next() {
+		var (
+			e        SecurityEvent
+			argument []byte
+		)
+		if err := rows.Scan(&e.ID, &e.Name, &e.URL, &e.UserID, &e.AnonymousUserID, &e.Source, &argument, &e.Version, &e.Timestamp); err != nil {
+			return nil, err
+		}
+		e.Argument = argument
+		events = append(events, &e)
+	}
+	return events, rows.Err()
+}
+
+// Count returns the number of security events matching the options (ignoring their limit and
+// offset).
+func (s *SecurityEventLogStore) Count(ctx context.Context, opt SecurityEventLogsListOptions) (int, error) {
+	if Mocks.SecurityEventLogs.Count != nil {
+		return Mocks.SecurityEventLogs.Count(ctx, opt)
+	}
+
+	q := sqlf.Sprintf("SELECT COUNT(*) FROM security_event_logs WHERE %s", sqlf.Join(opt.sqlConditions(), "AND"))
+	count, _, err := basestore.ScanFirstInt(s.Query(ctx, q))
+	return count, err
+}
+
+// DeleteOlderThan deletes the security events logged before the given time, and returns the
+// number of deleted events.
+func (s *SecurityEventLogStore) DeleteOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	res, err := s.ExecResult(ctx, sqlf.Sprintf(`DELETE FROM security_event_logs WHERE "timestamp" < %s`, before.UTC()))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// SecurityEventLogsEnabled reports whether security events are logged. They are always logged on
+// Sourcegraph.com, and on other instances if the audit log is configured in the
+// log.securityEventLogs site configuration.
+func SecurityEventLogsEnabled() bool {
+	if envvar.SourcegraphDotComMode() {
+		return true
+	}
+	log := conf.Get().Log
+	return log != nil && log.SecurityEventLogs != nil
+}
+
+// LogEvent will log security events, and export them in the background if an export is
+// configured.
 //
 // Note that it does not return an error and will instead simply log it.
 func (s *SecurityEventLogStore) LogEvent(ctx context.Context, e *SecurityEvent) {
-	// We don't want to begin logging authentication or authorization events in
-	// on-premises installations yet.
-	if !envvar.SourcegraphDotComMode() {
+	if !SecurityEventLogsEnabled() {
 		return
 	}
 
@@ -107,4 +230,11 @@ func (s *SecurityEventLogStore) LogEvent(ctx context.Context, e *SecurityEvent)
 		// to track down the root cause.
 		sentry.CaptureError(err, map[string]string{})
 	}
+
+	exportSecurityEvent(e)
+}
+
+type MockSecurityEventLogs struct {
+	List  func(ctx context.Context, opt SecurityEventLogsListOptions) ([]*SecurityEvent, error)
+	Count func(ctx context.Context, opt SecurityEventLogsListOptions) (int, error)
 }
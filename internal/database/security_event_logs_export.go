@@ -0,0 +1,257 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/version"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// securityEventExporter exports security events to the destination configured in the
+// log.securityEventLogs.export site configuration. The destination is (re)opened lazily, when the
+// configuration changes or after a failed write.
+//
+// Events are queued and exported in the background, so that an unreachable destination doesn't
+// block the requests logging them. Events are dropped when the queue is full, and while the
+// exporter waits to reopen a destination that failed to open.
+type securityEventExporter struct {
+	mu     sync.Mutex
+	config schema.SecurityEventLogsExport
+	w      io.WriteCloser
+	// octetCounting is whether syslog messages are framed with their length, as required on
+	// stream transports (RFC 6587).
+	octetCounting bool
+	// backoff is the time to wait before opening the destination again after it failed to open,
+	// and retryAt is when that wait is over.
+	backoff time.Duration
+	retryAt time.Time
+
+	start sync.Once
+	queue chan queuedSecurityEvent
+}
+
+type queuedSecurityEvent struct {
+	config *schema.SecurityEventLogsExport
+	event  SecurityEvent
+}
+
+const (
+	securityEventExportQueueSize  = 1024
+	securityEventExportMinBackoff = time.Second
+	securityEventExportMaxBackoff = time.Minute
+)
+
+// errSecurityEventExportBackoff is returned by export while it waits to reopen the destination.
+var errSecurityEventExportBackoff = errors.New("waiting to reopen security event export destination")
+
+var securityEventExportsDropped = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "src_security_event_exports_dropped_total",
+	Help: "Total number of security events that were not exported, because the export queue was full or the export destination was unavailable.",
+})
+
+var securityEventsExporter securityEventExporter
+
+// exportSecurityEvent queues the event to be exported with the current configuration.
+func exportSecurityEvent(e *SecurityEvent) {
+	var config *schema.SecurityEventLogsExport
+	if log := conf.Get().Log; log != nil && log.SecurityEventLogs != nil {
+		config = log.SecurityEventLogs.Export
+	}
+	securityEventsExporter.enqueue(config, e)
+}
+
+// enqueue queues the event to be exported in the background, starting the exporter if needed.
+// It reports whether the event was queued, events are dropped if the queue is full.
+func (x *securityEventExporter) enqueue(config *schema.SecurityEventLogsExport, e *SecurityEvent) bool {
+	x.start.Do(func() {
+		x.queue = make(chan queuedSecurityEvent, securityEventExportQueueSize)
+		go x.run()
+	})
+
+	select {
+	case x.queue <- queuedSecurityEvent{config: config, event: *e}:
+		return true
+	default:
+		securityEventExportsDropped.Inc()
+		return false
+	}
+}
+
+func (x *securityEventExporter) run() {
+	for q := range x.queue {
+		err := x.export(q.config, &q.event)
+		if err == nil {
+			continue
+		}
+		securityEventExportsDropped.Inc()
+		if err != errSecurityEventExportBackoff {
+			log15.Error("Failed to export security event.", "event", q.event.Name, "error", err)
+		}
+	}
+}
+
+func (x *securityEventExporter) export(config *schema.SecurityEventLogsExport, e *SecurityEvent) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if config == nil || *config != x.config {
+		x.close()
+	}
+	if config == nil {
+		return nil
+	}
+	if x.w == nil {
+		if time.Now().Before(x.retryAt) {
+			return errSecurityEventExportBackoff
+		}
+		if err := x.open(*config); err != nil {
+			x.config = *config
+			x.backoff *= 2
+			if x.backoff < securityEventExportMinBackoff {
+				x.backoff = securityEventExportMinBackoff
+			} else if x.backoff > securityEventExportMaxBackoff {
+				x.backoff = securityEventExportMaxBackoff
+			}
+			x.retryAt = time.Now().Add(x.backoff)
+			return err
+		}
+		x.backoff, x.retryAt = 0, time.Time{}
+	}
+
+	var msg []byte
+	switch config.Type {
+	case "syslog":
+		msg = formatSyslogSecurityEvent(e)
+		if x.octetCounting {
+			msg = append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+		}
+	default:
+		msg = append(formatJSONSecurityEvent(e), '\n')
+	}
+	if _, err := x.w.Write(msg); err != nil {
+		x.close()
+		return err
+	}
+	return nil
+}
+
+func (x *securityEventExporter) open(config schema.SecurityEventLogsExport) error {
+	switch config.Type {
+	case "syslog":
+		address := config.Address
+		if address == "" {
+			address = "udp://localhost:514"
+		}
+		u, err := url.Parse(address)
+		if err != nil {
+			return errors.Wrap(err, "parsing syslog address")
+		}
+		if u.Scheme != "udp" && u.Scheme != "tcp" {
+			return errors.Errorf("unsupported syslog address %q", address)
+		}
+		conn, err := net.DialTimeout(u.Scheme, u.Host, 5*time.Second)
+		if err != nil {
+			return err
+		}
+		x.w, x.octetCounting = conn, u.Scheme == "tcp"
+
+	case "jsonLines":
+		if config.Path == "" {
+			return errors.New("a path is required to export security events as JSON lines")
+		}
+		f, err := os.OpenFile(config.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+		if err != nil {
+			return err
+		}
+		x.w, x.octetCounting = f, false
+
+	default:
+		return errors.Errorf("unsupported security event export type %q", config.Type)
+	}
+	x.config = config
+	return nil
+}
+
+func (x *securityEventExporter) close() {
+	if x.w != nil {
+		_ = x.w.Close()
+	}
+	x.w = nil
+	x.config = schema.SecurityEventLogsExport{}
+	x.backoff, x.retryAt = 0, time.Time{}
+}
+
+// exportedSecurityEvent is the JSON representation of exported security events.
+type exportedSecurityEvent struct {
+	Name            SecurityEventName `json:"name"`
+	URL             string            `json:"url"`
+	UserID          uint32            `json:"userID"`
+	AnonymousUserID string            `json:"anonymousUserID"`
+	Source          string            `json:"source"`
+	Argument        json.RawMessage   `json:"argument"`
+	Version         string            `json:"version"`
+	Timestamp       time.Time         `json:"timestamp"`
+}
+
+func formatJSONSecurityEvent(e *SecurityEvent) []byte {
+	exported := exportedSecurityEvent{
+		Name:            e.Name,
+		URL:             e.URL,
+		UserID:          e.UserID,
+		AnonymousUserID: e.AnonymousUserID,
+		Source:          e.Source,
+		Argument:        e.Argument,
+		Version:         e.Version,
+		Timestamp:       e.Timestamp.UTC(),
+	}
+	if len(exported.Argument) == 0 || !json.Valid(exported.Argument) {
+		exported.Argument = json.RawMessage(`{}`)
+	}
+	if exported.Version == "" {
+		exported.Version = version.Version()
+	}
+	if exported.Timestamp.IsZero() {
+		exported.Timestamp = time.Now().UTC()
+	}
+	b, _ := json.Marshal(exported)
+	return b
+}
+
+// syslogPriority is the priority of exported syslog messages: the security/authorization
+// (authpriv) facility with the informational severity.
+const syslogPriority = 10*8 + 6
+
+// formatSyslogSecurityEvent formats the event as an RFC 5424 syslog message, whose message ID is the
+// event name and whose message is the event as JSON.
+func formatSyslogSecurityEvent(e *SecurityEvent) []byte {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	timestamp := e.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	header := fmt.Sprintf("<%d>1 %s %s sourcegraph %d %s - ",
+		syslogPriority,
+		timestamp.UTC().Format("2006-01-02T15:04:05.000000Z"),
+		hostname,
+		os.Getpid(),
+		e.Name,
+	)
+	return append([]byte(header), formatJSONSecurityEvent(e)...)
+}
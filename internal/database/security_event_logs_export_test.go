@@ -0,0 +1,137 @@
+package database
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestSecurityEventExporter(t *testing.T) {
+	event := &SecurityEvent{
+		Name:      SecurityEventNameSignInFailed,
+		URL:       "https://sourcegraph.example.com/sign-in",
+		UserID:    1,
+		Source:    "BACKEND",
+		Argument:  json.RawMessage(`{"reason":"password"}`),
+		Version:   "3.32.0",
+		Timestamp: time.Date(2021, 9, 1, 12, 0, 0, 0, time.UTC),
+	}
+	wantJSON := `{"name":"SignInFailed","url":"https://sourcegraph.example.com/sign-in","userID":1,"anonymousUserID":"","source":"BACKEND","argument":{"reason":"password"},"version":"3.32.0","timestamp":"2021-09-01T12:00:00Z"}`
+
+	t.Run("jsonLines", func(t *testing.T) {
+		var x securityEventExporter
+		defer x.close()
+
+		path := filepath.Join(t.TempDir(), "security-events.jsonl")
+		config := &schema.SecurityEventLogsExport{Type: "jsonLines", Path: path}
+		for i := 0; i < 2; i++ {
+			if err := x.export(config, event); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := string(b), wantJSON+"\n"+wantJSON+"\n"; got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+
+		// Removing the configuration stops the export.
+		if err := x.export(nil, event); err != nil {
+			t.Fatal(err)
+		}
+		if x.w != nil {
+			t.Error("want export destination closed")
+		}
+	})
+
+	t.Run("syslog", func(t *testing.T) {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		var x securityEventExporter
+		defer x.close()
+
+		config := &schema.SecurityEventLogsExport{Type: "syslog", Address: "udp://" + conn.LocalAddr().String()}
+		if err := x.export(config, event); err != nil {
+			t.Fatal(err)
+		}
+
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		buf := make([]byte, 4096)
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := string(buf[:n])
+		header := regexp.MustCompile(`^<86>1 2021-09-01T12:00:00\.000000Z \S+ sourcegraph \d+ SignInFailed - `)
+		if !header.MatchString(got) || !strings.HasSuffix(got, wantJSON) {
+			t.Errorf("got unexpected syslog message %q", got)
+		}
+	})
+
+	t.Run("invalid configuration", func(t *testing.T) {
+		var x securityEventExporter
+		for _, config := range []*schema.SecurityEventLogsExport{
+			{Type: "jsonLines"},
+			{Type: "syslog", Address: "unix:///dev/log"},
+			{Type: "kafka"},
+		} {
+			if err := x.export(config, event); err == nil {
+				t.Errorf("got no error for %+v", config)
+			}
+		}
+	})
+	t.Run("unreachable destination", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		address := l.Addr().String()
+		l.Close()
+
+		var x securityEventExporter
+		defer x.close()
+
+		config := &schema.SecurityEventLogsExport{Type: "syslog", Address: "tcp://" + address}
+		if err := x.export(config, event); err == nil || err == errSecurityEventExportBackoff {
+			t.Fatalf("want dial error, got %v", err)
+		}
+		// The destination isn't dialed again for every event.
+		if err := x.export(config, event); err != errSecurityEventExportBackoff {
+			t.Fatalf("want %v, got %v", errSecurityEventExportBackoff, err)
+		}
+
+		// Changing the configuration opens the new destination right away.
+		path := filepath.Join(t.TempDir(), "security-events.jsonl")
+		if err := x.export(&schema.SecurityEventLogsExport{Type: "jsonLines", Path: path}, event); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("full queue", func(t *testing.T) {
+		x := securityEventExporter{queue: make(chan queuedSecurityEvent, 1)}
+		// Don't start draining the queue.
+		x.start.Do(func() {})
+
+		config := &schema.SecurityEventLogsExport{Type: "jsonLines", Path: filepath.Join(t.TempDir(), "security-events.jsonl")}
+		if !x.enqueue(config, event) {
+			t.Fatal("want event queued")
+		}
+		if x.enqueue(config, event) {
+			t.Fatal("want event dropped when the queue is full")
+		}
+	})
+}
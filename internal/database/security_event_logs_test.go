@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
 )
@@ -67,3 +68,70 @@ func TestSecurityEventLogs_ValidInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestSecurityEventLogs_List(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Microsecond)
+	events := []*SecurityEvent{
+		{Name: SecurityEventNameSignInFailed, UserID: 1, URL: "", Source: "BACKEND", Timestamp: now.Add(-48 * time.Hour)},
+		{Name: SecurityEventNameSignInSucceeded, UserID: 1, URL: "", Source: "BACKEND", Timestamp: now.Add(-24 * time.Hour)},
+		{Name: SecurityEventNameSignInSucceeded, UserID: 2, URL: "", Source: "WEB", Timestamp: now},
+	}
+	for _, e := range events {
+		if err := SecurityEventLogs(db).Insert(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	since := now.Add(-36 * time.Hour)
+	for _, tc := range []struct {
+		name string
+		opt  SecurityEventLogsListOptions
+		want []*SecurityEvent
+	}{
+		{name: "all", want: []*SecurityEvent{events[2], events[1], events[0]}},
+		{name: "user", opt: SecurityEventLogsListOptions{UserID: 1}, want: []*SecurityEvent{events[1], events[0]}},
+		{name: "names", opt: SecurityEventLogsListOptions{Names: []SecurityEventName{SecurityEventNameSignInFailed}}, want: []*SecurityEvent{events[0]}},
+		{name: "source", opt: SecurityEventLogsListOptions{Source: "WEB"}, want: []*SecurityEvent{events[2]}},
+		{name: "since", opt: SecurityEventLogsListOptions{Since: &since}, want: []*SecurityEvent{events[2], events[1]}},
+		{name: "until", opt: SecurityEventLogsListOptions{Until: &since}, want: []*SecurityEvent{events[0]}},
+		{name: "limit", opt: SecurityEventLogsListOptions{LimitOffset: &LimitOffset{Limit: 1, Offset: 1}}, want: []*SecurityEvent{events[1]}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := SecurityEventLogs(db).List(ctx, tc.opt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %d events, want %d", len(got), len(tc.want))
+			}
+			for i := range got {
+				if got[i].Name != tc.want[i].Name || got[i].UserID != tc.want[i].UserID || !got[i].Timestamp.Equal(tc.want[i].Timestamp) {
+					t.Errorf("event %d: got %+v, want %+v", i, got[i], tc.want[i])
+				}
+			}
+
+			count, err := SecurityEventLogs(db).Count(ctx, SecurityEventLogsListOptions{UserID: tc.opt.UserID, Names: tc.opt.Names, Source: tc.opt.Source, Since: tc.opt.Since, Until: tc.opt.Until})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.opt.LimitOffset == nil && count != len(tc.want) {
+				t.Errorf("got count %d, want %d", count, len(tc.want))
+			}
+		})
+	}
+
+	deleted, err := SecurityEventLogs(db).DeleteOlderThan(ctx, since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 1 {
+		t.Errorf("got %d deleted events, want 1", deleted)
+	}
+}
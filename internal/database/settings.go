@@ -41,11 +41,45 @@ func (s *SettingStore) Transact(ctx context.Context) (*SettingStore, error) {
 	return &SettingStore{Store: txBase}, err
 }
 
+// SettingsValidationError is returned when settings do not conform to the settings schema.
+type SettingsValidationError struct {
+	Problems []conf.SettingsProblem
+}
+
+func (e *SettingsValidationError) Error() string {
+	messages := make([]string, 0, len(e.Problems))
+	for _, p := range e.Problems {
+		messages = append(messages, p.String())
+	}
+	return "invalid settings: " + strings.Join(messages, ",")
+}
+
+// Extensions returns the problems, so that GraphQL clients can point at the invalid properties.
+func (e *SettingsValidationError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": "ErrInvalidSettings", "problems": e.Problems}
+}
+
+// CreateIfUpToDate saves the settings of the subject if lastID is the ID of their latest
+// version, and returns the latest version of the settings. It returns a
+// *SettingsValidationError if the settings do not conform to the settings schema.
 func (o *SettingStore) CreateIfUpToDate(ctx context.Context, subject api.SettingsSubject, lastID *int32, authorUserID *int32, contents string) (latestSetting *api.Settings, err error) {
 	if Mocks.Settings.CreateIfUpToDate != nil {
 		return Mocks.Settings.CreateIfUpToDate(ctx, subject, lastID, authorUserID, contents)
 	}
 
+	return o.createIfUpToDate(ctx, subject, lastID, authorUserID, contents, true)
+}
+
+// ForceCreateIfUpToDate is like CreateIfUpToDate, except it saves settings that do not conform to
+// the settings schema. The settings must still be valid JSON. It lets site admins save settings
+// that a schema change made invalid.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (o *SettingStore) ForceCreateIfUpToDate(ctx context.Context, subject api.SettingsSubject, lastID *int32, authorUserID *int32, contents string) (latestSetting *api.Settings, err error) {
+	return o.createIfUpToDate(ctx, subject, lastID, authorUserID, contents, false)
+}
+
+func (o *SettingStore) createIfUpToDate(ctx context.Context, subject api.SettingsSubject, lastID *int32, authorUserID *int32, contents string, validateSchema bool) (latestSetting *api.Settings, err error) {
 	if strings.TrimSpace(contents) == "" {
 		return nil, errors.Errorf("blank settings are invalid (you can clear the settings by entering an empty JSON object: {})")
 	}
@@ -56,12 +90,14 @@ func (o *SettingStore) CreateIfUpToDate(ctx context.Context, subject api.Setting
 	}
 
 	// Validate setting schema
-	problems, err := conf.ValidateSetting(contents)
-	if err != nil {
-		return nil, err
-	}
-	if len(problems) > 0 {
-		return nil, errors.Errorf("invalid settings: %s", strings.Join(problems, ","))
+	if validateSchema {
+		problems, err := conf.ValidateSettingProblems(contents)
+		if err != nil {
+			return nil, err
+		}
+		if len(problems) > 0 {
+			return nil, &SettingsValidationError{Problems: problems}
+		}
 	}
 
 	s := api.Settings{
@@ -4,7 +4,11 @@ import (
 	"context"
 	"testing"
 
+	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
+
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
 )
 
@@ -89,6 +93,36 @@ func TestCreateIfUpToDate(t *testing.T) {
 			if got != want {
 				t.Errorf("err: want %q but got %q", want, got)
 			}
+
+			var validationErr *SettingsValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("want a *SettingsValidationError, got %T", err)
+			}
+			wantProblems := []conf.SettingsProblem{{Path: "quicklinks.0.url", Description: "Does not match pattern '^(https?://|/)'"}}
+			if diff := cmp.Diff(wantProblems, validationErr.Problems); diff != "" {
+				t.Errorf("unexpected problems (-want +got):\n%s", diff)
+			}
+		}
+	})
+
+	t.Run("forced quicklink with javascript link", func(t *testing.T) {
+		contents := "{\"quicklinks\": [{\"name\": \"malicious link test\",      \"url\": \"javascript:alert(1)\"}]}"
+
+		latest, err := Settings(db).GetLatest(ctx, api.SettingsSubject{User: &u.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		saved, err := Settings(db).ForceCreateIfUpToDate(ctx, api.SettingsSubject{User: &u.ID}, &latest.ID, nil, contents)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if saved.Contents != contents {
+			t.Fatalf("want contents %q, got %q", contents, saved.Contents)
+		}
+
+		// The JSON syntax is still validated.
+		if _, err := Settings(db).ForceCreateIfUpToDate(ctx, api.SettingsSubject{User: &u.ID}, nil, nil, "{"); err == nil {
+			t.Fatal("want error saving invalid JSON, got none")
 		}
 	})
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/cockroachdb/errors"
 
 	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
 
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
@@ -51,6 +52,10 @@ func (f *TemporarySettingsStore) OverwriteTemporarySettings(ctx context.Context,
 		return Mocks.TemporarySettings.OverwriteTemporarySettings(ctx, userID, contents)
 	}
 
+	if err := ts.Validate(contents); err != nil {
+		return err
+	}
+
 	const overwriteTemporarySettingsQuery = `
 		INSERT INTO temporary_settings (user_id, contents)
 		VALUES (%s, %s)
@@ -67,13 +72,81 @@ func (f *TemporarySettingsStore) EditTemporarySettings(ctx context.Context, user
 		return Mocks.TemporarySettings.EditTemporarySettings(ctx, userID, settingsToEdit)
 	}
 
+	if err := ts.Validate(settingsToEdit); err != nil {
+		return err
+	}
+
+	// The edit is only applied if the edited settings don't exceed the quota, in which case
+	// no row is affected.
 	const editTemporarySettingsQuery = `
 		INSERT INTO temporary_settings AS t (user_id, contents)
 			VALUES (%s, %s)
 			ON CONFLICT (user_id) DO UPDATE SET
-				contents = t.contents || %s,
-				updated_at = now();
+				contents = COALESCE(t.contents, '{}') || %s,
+				updated_at = now()
+			WHERE octet_length((COALESCE(t.contents, '{}') || %s)::text) <= %s;
+	`
+
+	res, err := f.ExecResult(ctx, sqlf.Sprintf(editTemporarySettingsQuery, userID, settingsToEdit, settingsToEdit, settingsToEdit, ts.MaxSize))
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ts.ErrQuotaExceeded
+	}
+	return nil
+}
+
+// RenameTemporarySettingsKey renames the given key in the temporary settings of all users,
+// to migrate settings when a key of the schema is renamed. Users who already have a value
+// for the new key keep it. It returns the number of users whose settings were changed.
+func (f *TemporarySettingsStore) RenameTemporarySettingsKey(ctx context.Context, from, to string) (int64, error) {
+	const renameTemporarySettingsKeyQuery = `
+		UPDATE temporary_settings
+		SET
+			contents = jsonb_build_object(%s, contents->%s) || (contents - %s),
+			updated_at = now()
+		WHERE jsonb_typeof(contents) = 'object'
+			AND contents ? %s;
 	`
 
-	return f.Exec(ctx, sqlf.Sprintf(editTemporarySettingsQuery, userID, settingsToEdit, settingsToEdit))
+	return f.execRowsAffected(ctx, sqlf.Sprintf(renameTemporarySettingsKeyQuery, to, from, from, from))
+}
+
+// DeleteStaleTemporarySettingsKeys deletes the keys that are not part of the temporary
+// settings schema anymore from the temporary settings of all users. It returns the number
+// of users whose settings were changed.
+func (f *TemporarySettingsStore) DeleteStaleTemporarySettingsKeys(ctx context.Context) (int64, error) {
+	const deleteStaleTemporarySettingsKeysQuery = `
+		UPDATE temporary_settings
+		SET
+			contents = contents - ARRAY(
+				SELECT key
+				FROM jsonb_object_keys(contents) AS key
+				WHERE NOT key = ANY(%s)
+			),
+			updated_at = now()
+		-- jsonb_object_keys fails on settings that aren't objects, which can only be
+		-- written by an older version.
+		WHERE CASE WHEN jsonb_typeof(contents) = 'object' THEN EXISTS (
+			SELECT 1
+			FROM jsonb_object_keys(contents) AS key
+			WHERE NOT key = ANY(%s)
+		) ELSE false END;
+	`
+
+	knownKeys := pq.Array(ts.KnownKeys())
+	return f.execRowsAffected(ctx, sqlf.Sprintf(deleteStaleTemporarySettingsKeysQuery, knownKeys, knownKeys))
+}
+
+func (f *TemporarySettingsStore) execRowsAffected(ctx context.Context, q *sqlf.Query) (int64, error) {
+	res, err := f.ExecResult(ctx, q)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
 }
@@ -2,9 +2,11 @@ package database
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"testing"
 
-	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
 	"github.com/stretchr/testify/require"
 
 	"github.com/sourcegraph/sourcegraph/internal/actor"
@@ -19,6 +21,9 @@ func TestTemporarySettingsStore(t *testing.T) {
 	t.Run("UpdateAndGet", testUpdateAndGet)
 	t.Run("InsertWithInvalidData", testInsertWithInvalidData)
 	t.Run("TestEdit", testEdit)
+	t.Run("InsertWithUnknownKey", testInsertWithUnknownKey)
+	t.Run("EditQuota", testEditQuota)
+	t.Run("RenameAndDeleteStaleKeys", testRenameAndDeleteStaleKeys)
 }
 
 func testGetEmpty(t *testing.T) {
@@ -100,7 +105,101 @@ func testInsertWithInvalidData(t *testing.T) {
 	require.NoError(t, err)
 
 	err = temporarySettingsStore.OverwriteTemporarySettings(ctx, user.ID, contents)
-	require.EqualError(t, errors.Unwrap(errors.Unwrap(err)), "ERROR: invalid input syntax for type json (SQLSTATE 22P02)")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid temporary settings")
+}
+
+func testInsertWithUnknownKey(t *testing.T) {
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	usersStore := Users(db)
+	temporarySettingsStore := TemporarySettings(db)
+
+	ctx := actor.WithInternalActor(context.Background())
+
+	user, err := usersStore.Create(ctx, NewUser{Username: "u", Password: "p"})
+	require.NoError(t, err)
+
+	err = temporarySettingsStore.OverwriteTemporarySettings(ctx, user.ID, "{\"unknown\": \"value\"}")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Additional property unknown is not allowed")
+
+	err = temporarySettingsStore.EditTemporarySettings(ctx, user.ID, "{\"search.onboarding.tourCancelled\": \"yes\"}")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid temporary settings")
+}
+
+func testEditQuota(t *testing.T) {
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	usersStore := Users(db)
+	temporarySettingsStore := TemporarySettings(db)
+
+	ctx := actor.WithInternalActor(context.Background())
+
+	user, err := usersStore.Create(ctx, NewUser{Username: "u", Password: "p"})
+	require.NoError(t, err)
+
+	// Each edit is below the quota, but their sum is not.
+	var sections []string
+	for i := 0; i < ts.MaxSize/30; i++ {
+		sections = append(sections, fmt.Sprintf("\"section-%d\": true", i))
+	}
+	edit1 := fmt.Sprintf("{\"search.collapsedSidebarSections\": {%s}}", strings.Join(sections, ", "))
+	edit2 := fmt.Sprintf("{\"user.lastDayActive\": %q}", strings.Repeat("x", ts.MaxSize/2))
+
+	err = temporarySettingsStore.EditTemporarySettings(ctx, user.ID, edit1)
+	require.NoError(t, err)
+	err = temporarySettingsStore.EditTemporarySettings(ctx, user.ID, edit2)
+	require.Equal(t, ts.ErrQuotaExceeded, err)
+
+	// The settings are left unchanged.
+	res, err := temporarySettingsStore.GetTemporarySettings(ctx, user.ID)
+	require.NoError(t, err)
+	require.NotContains(t, res.Contents, "user.lastDayActive")
+}
+
+func testRenameAndDeleteStaleKeys(t *testing.T) {
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	usersStore := Users(db)
+	temporarySettingsStore := TemporarySettings(db)
+
+	ctx := actor.WithInternalActor(context.Background())
+
+	var userIDs []int32
+	for i, contents := range []string{
+		"{\"old.key\": true, \"search.usedNonGlobalContext\": false}",
+		"{\"old.key\": true, \"stale.key\": 1, \"search.onboarding.tourCancelled\": true}",
+		"{\"search.usedNonGlobalContext\": true}",
+	} {
+		user, err := usersStore.Create(ctx, NewUser{Username: fmt.Sprintf("u%d", i), Password: "p"})
+		require.NoError(t, err)
+		// Write the settings directly, as they would have been written before the keys
+		// were removed from the schema.
+		err = temporarySettingsStore.Exec(ctx, sqlf.Sprintf("INSERT INTO temporary_settings (user_id, contents) VALUES (%s, %s)", user.ID, contents))
+		require.NoError(t, err)
+		userIDs = append(userIDs, user.ID)
+	}
+
+	renamed, err := temporarySettingsStore.RenameTemporarySettingsKey(ctx, "old.key", "search.usedNonGlobalContext")
+	require.NoError(t, err)
+	require.Equal(t, int64(2), renamed)
+
+	deleted, err := temporarySettingsStore.DeleteStaleTemporarySettingsKeys(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+
+	for i, want := range []string{
+		// The existing value of the new key is kept.
+		"{\"search.usedNonGlobalContext\": false}",
+		"{\"search.usedNonGlobalContext\": true, \"search.onboarding.tourCancelled\": true}",
+		"{\"search.usedNonGlobalContext\": true}",
+	} {
+		res, err := temporarySettingsStore.GetTemporarySettings(ctx, userIDs[i])
+		require.NoError(t, err)
+		require.JSONEq(t, want, res.Contents)
+	}
 }
 
 func testEdit(t *testing.T) {
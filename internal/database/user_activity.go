@@ -0,0 +1,195 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// UserActivityKind is the kind of activity a user was last seen doing. Callers may record
+// activity of other kinds than the ones below, each kind is tracked separately.
+type UserActivityKind string
+
+const (
+	// UserActivityKindWeb is activity in the web app, i.e. requests authenticated with a
+	// session cookie.
+	UserActivityKindWeb UserActivityKind = "web"
+	// UserActivityKindAPI is activity through the API, i.e. requests authenticated with an
+	// access token.
+	UserActivityKindAPI UserActivityKind = "api"
+	// UserActivityKindGit is git operations performed on behalf of the user.
+	UserActivityKindGit UserActivityKind = "git"
+)
+
+// UserActivity is the last time a user was seen doing a kind of activity.
+type UserActivity struct {
+	UserID     int32
+	Kind       UserActivityKind
+	LastSeenAt time.Time
+}
+
+// UserActivityStore records the last time users were seen, per kind of activity. Unlike
+// event logs, it only keeps the latest activity of each user, so that answering which
+// users are active or idle doesn't require scanning the event_logs table.
+type UserActivityStore struct {
+	*basestore.Store
+}
+
+// UserActivities instantiates and returns a new UserActivityStore.
+func UserActivities(db dbutil.DB) *UserActivityStore {
+	return &UserActivityStore{Store: basestore.NewWithDB(db, sql.TxOptions{})}
+}
+
+// UserActivitiesWith instantiates and returns a new UserActivityStore using the other
+// store handle.
+func UserActivitiesWith(other basestore.ShareableStore) *UserActivityStore {
+	return &UserActivityStore{Store: basestore.NewWithHandle(other.Handle())}
+}
+
+func (s *UserActivityStore) With(other basestore.ShareableStore) *UserActivityStore {
+	return &UserActivityStore{Store: s.Store.With(other)}
+}
+
+func (s *UserActivityStore) Transact(ctx context.Context) (*UserActivityStore, error) {
+	txBase, err := s.Store.Transact(ctx)
+	return &UserActivityStore{Store: txBase}, err
+}
+
+// Record upserts the given activities in a single query. The last seen time of a user
+// and kind only ever moves forward, so activities may be recorded out of order.
+func (s *UserActivityStore) Record(ctx context.Context, activities ...UserActivity) error {
+	if len(activities) == 0 {
+		return nil
+	}
+
+	values := make([]*sqlf.Query, 0, len(activities))
+	for _, a := range activities {
+		values = append(values, sqlf.Sprintf("(%s::integer, %s::text, %s::timestamptz)", a.UserID, string(a.Kind), a.LastSeenAt.UTC()))
+	}
+	return s.Exec(ctx, sqlf.Sprintf(recordUserActivityQuery, sqlf.Join(values, ",")))
+}
+
+const recordUserActivityQuery = `
+-- source: internal/database/user_activity.go:Record
+INSERT INTO user_activity AS a (user_id, kind, last_seen_at)
+-- Activities of deleted users are skipped rather than failing the whole batch.
+SELECT v.user_id, v.kind, MAX(v.last_seen_at)
+FROM (VALUES %s) AS v (user_id, kind, last_seen_at)
+JOIN users ON users.id = v.user_id
+GROUP BY v.user_id, v.kind
+ON CONFLICT (user_id, kind) DO UPDATE
+SET last_seen_at = GREATEST(a.last_seen_at, EXCLUDED.last_seen_at)
+`
+
+// LastSeen returns the activities of the given user, one per kind.
+func (s *UserActivityStore) LastSeen(ctx context.Context, userID int32) (_ []UserActivity, err error) {
+	rows, err := s.Query(ctx, sqlf.Sprintf(lastSeenQuery, userID))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var activities []UserActivity
+	for rows.Next() {
+		var a UserActivity
+		if err := rows.Scan(&a.UserID, &a.Kind, &a.LastSeenAt); err != nil {
+			return nil, err
+		}
+		activities = append(activities, a)
+	}
+	return activities, nil
+}
+
+const lastSeenQuery = `
+-- source: internal/database/user_activity.go:LastSeen
+SELECT user_id, kind, last_seen_at
+FROM user_activity
+WHERE user_id = %s
+ORDER BY kind
+`
+
+// UserActivityListOptions filters the users returned by UsersActiveSince and IdleUsers.
+type UserActivityListOptions struct {
+	// Kinds restricts the activity to the given kinds. All kinds are considered if it is
+	// empty.
+	Kinds []UserActivityKind
+	*LimitOffset
+}
+
+func (o UserActivityListOptions) kindCondition() *sqlf.Query {
+	if len(o.Kinds) == 0 {
+		return sqlf.Sprintf("TRUE")
+	}
+	kinds := make([]string, 0, len(o.Kinds))
+	for _, k := range o.Kinds {
+		kinds = append(kinds, string(k))
+	}
+	return sqlf.Sprintf("a.kind = ANY(%s)", pq.Array(kinds))
+}
+
+// UsersActiveSince returns the IDs of the users who were seen since the given time,
+// ordered by ID.
+func (s *UserActivityStore) UsersActiveSince(ctx context.Context, since time.Time, opts UserActivityListOptions) ([]int32, error) {
+	return basestore.ScanInt32s(s.Query(ctx, sqlf.Sprintf(usersActiveSinceQuery, since.UTC(), opts.kindCondition(), opts.LimitOffset.SQL())))
+}
+
+const usersActiveSinceQuery = `
+-- source: internal/database/user_activity.go:UsersActiveSince
+SELECT DISTINCT a.user_id
+FROM user_activity a
+JOIN users u ON u.id = a.user_id
+WHERE
+	a.last_seen_at >= %s AND
+	%s AND
+	u.deleted_at IS NULL
+ORDER BY a.user_id
+%s
+`
+
+// IdleUser is a user who hasn't been seen since a given time. LastSeenAt is nil if the
+// user has never been seen.
+type IdleUser struct {
+	UserID     int32
+	LastSeenAt *time.Time
+}
+
+// IdleUsers returns the users who haven't been seen since the given time, including the
+// users who have never been seen. Users created after the given time are not idle yet.
+// The users idle for the longest time are returned first.
+func (s *UserActivityStore) IdleUsers(ctx context.Context, since time.Time, opts UserActivityListOptions) (_ []IdleUser, err error) {
+	rows, err := s.Query(ctx, sqlf.Sprintf(idleUsersQuery, opts.kindCondition(), since.UTC(), since.UTC(), opts.LimitOffset.SQL()))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var users []IdleUser
+	for rows.Next() {
+		var u IdleUser
+		if err := rows.Scan(&u.UserID, &u.LastSeenAt); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, nil
+}
+
+const idleUsersQuery = `
+-- source: internal/database/user_activity.go:IdleUsers
+SELECT u.id, MAX(a.last_seen_at)
+FROM users u
+LEFT JOIN user_activity a ON a.user_id = u.id AND %s
+WHERE
+	u.created_at < %s AND
+	u.deleted_at IS NULL
+GROUP BY u.id
+HAVING MAX(a.last_seen_at) IS NULL OR MAX(a.last_seen_at) < %s
+ORDER BY MAX(a.last_seen_at) ASC NULLS FIRST, u.id
+%s
+`
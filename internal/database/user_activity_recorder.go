@@ -0,0 +1,155 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// userActivityFlushInterval is how often the recorded activity is written to the database.
+// The last seen times are therefore up to this much behind.
+const userActivityFlushInterval = 30 * time.Second
+
+type userActivityKey struct {
+	userID int32
+	kind   UserActivityKind
+}
+
+// UserActivityRecorder coalesces user activity in memory and periodically writes the
+// latest activity of each user and kind to the user_activity table in a single query, so
+// that recording activity on every request doesn't cost a write per request. It is a
+// background routine: activity is flushed by Start, until Stop is called.
+type UserActivityRecorder struct {
+	record        func(context.Context, ...UserActivity) error
+	flushInterval time.Duration
+
+	mu      sync.Mutex
+	pending map[userActivityKey]time.Time
+	started bool
+	stopped bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewUserActivityRecorder returns a recorder writing to the user_activity table of the
+// given database.
+func NewUserActivityRecorder(db dbutil.DB) *UserActivityRecorder {
+	return newUserActivityRecorder(UserActivities(db).Record, userActivityFlushInterval)
+}
+
+func newUserActivityRecorder(record func(context.Context, ...UserActivity) error, flushInterval time.Duration) *UserActivityRecorder {
+	return &UserActivityRecorder{
+		record:        record,
+		flushInterval: flushInterval,
+		pending:       map[userActivityKey]time.Time{},
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+}
+
+// Record records that the given user was seen at the given time.
+func (r *UserActivityRecorder) Record(userID int32, kind UserActivityKind, at time.Time) {
+	key := userActivityKey{userID: userID, kind: kind}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if last, ok := r.pending[key]; !ok || at.After(last) {
+		r.pending[key] = at
+	}
+}
+
+// Start flushes the recorded activity until Stop is called.
+func (r *UserActivityRecorder) Start() {
+	r.mu.Lock()
+	if r.stopped {
+		// Stop has already flushed the recorded activity.
+		r.mu.Unlock()
+		return
+	}
+	r.started = true
+	r.mu.Unlock()
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.flush()
+		case <-r.stop:
+			r.flush()
+			return
+		}
+	}
+}
+
+// Stop stops the recorder and blocks until the recorded activity has been flushed.
+func (r *UserActivityRecorder) Stop() {
+	r.mu.Lock()
+	if r.stopped {
+		r.mu.Unlock()
+		return
+	}
+	r.stopped = true
+	started := r.started
+	r.mu.Unlock()
+
+	close(r.stop)
+	if started {
+		<-r.done
+	} else {
+		r.flush()
+	}
+}
+
+func (r *UserActivityRecorder) flush() {
+	r.mu.Lock()
+	pending := r.pending
+	r.pending = make(map[userActivityKey]time.Time, len(pending))
+	r.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	activities := make([]UserActivity, 0, len(pending))
+	for key, at := range pending {
+		activities = append(activities, UserActivity{UserID: key.userID, Kind: key.kind, LastSeenAt: at})
+	}
+	// The activity is lost if it can't be written, but it is recorded again on the next
+	// request of the user.
+	if err := r.record(context.Background(), activities...); err != nil {
+		log15.Error("Failed to record user activity", "users", len(activities), "error", err)
+	}
+}
+
+var (
+	userActivityRecorderMu sync.RWMutex
+	userActivityRecorder   *UserActivityRecorder
+)
+
+// SetUserActivityRecorder sets the recorder used by RecordUserActivity. It is set by the
+// frontend, which runs the recorder as one of its background routines.
+func SetUserActivityRecorder(r *UserActivityRecorder) {
+	userActivityRecorderMu.Lock()
+	defer userActivityRecorderMu.Unlock()
+	userActivityRecorder = r
+}
+
+// RecordUserActivity records that the given user was seen now through the recorder set by
+// SetUserActivityRecorder, so that it is written to the database in a later batch. Activity
+// is recorded on a best-effort basis: this is a no-op if no recorder is set.
+func RecordUserActivity(userID int32, kind UserActivityKind) {
+	userActivityRecorderMu.RLock()
+	r := userActivityRecorder
+	userActivityRecorderMu.RUnlock()
+
+	if r != nil {
+		r.Record(userID, kind, time.Now())
+	}
+}
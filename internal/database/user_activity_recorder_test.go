@@ -0,0 +1,53 @@
+package database
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUserActivityRecorder(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		recorded [][]UserActivity
+	)
+	record := func(_ context.Context, activities ...UserActivity) error {
+		sort.Slice(activities, func(i, j int) bool {
+			if activities[i].UserID != activities[j].UserID {
+				return activities[i].UserID < activities[j].UserID
+			}
+			return activities[i].Kind < activities[j].Kind
+		})
+		mu.Lock()
+		defer mu.Unlock()
+		recorded = append(recorded, activities)
+		return nil
+	}
+
+	r := newUserActivityRecorder(record, time.Hour)
+	go r.Start()
+
+	now := time.Now()
+	r.Record(1, UserActivityKindWeb, now.Add(-time.Minute))
+	r.Record(1, UserActivityKindWeb, now)
+	// Out of order activity doesn't move the last seen time backwards.
+	r.Record(1, UserActivityKindWeb, now.Add(-time.Hour))
+	r.Record(1, UserActivityKindAPI, now)
+	r.Record(2, UserActivityKindWeb, now)
+	r.Stop()
+
+	want := [][]UserActivity{{
+		{UserID: 1, Kind: UserActivityKindAPI, LastSeenAt: now},
+		{UserID: 1, Kind: UserActivityKindWeb, LastSeenAt: now},
+		{UserID: 2, Kind: UserActivityKindWeb, LastSeenAt: now},
+	}}
+	mu.Lock()
+	defer mu.Unlock()
+	if diff := cmp.Diff(want, recorded); diff != "" {
+		t.Errorf("unexpected recorded activity (-want +have):\n%s", diff)
+	}
+}
@@ -0,0 +1,136 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+)
+
+func TestUserActivities(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	ctx := context.Background()
+	store := UserActivities(db)
+
+	var userIDs []int32
+	for _, username := range []string{"u1", "u2", "u3", "u4"} {
+		user, err := Users(db).Create(ctx, NewUser{Username: username})
+		if err != nil {
+			t.Fatal(err)
+		}
+		userIDs = append(userIDs, user.ID)
+	}
+	u1, u2, u3, u4 := userIDs[0], userIDs[1], userIDs[2], userIDs[3]
+	if err := Users(db).Delete(ctx, u4); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	day := 24 * time.Hour
+	err := store.Record(ctx,
+		UserActivity{UserID: u1, Kind: UserActivityKindWeb, LastSeenAt: now.Add(-10 * day)},
+		// Activities of the same user and kind in one batch are coalesced.
+		UserActivity{UserID: u1, Kind: UserActivityKindWeb, LastSeenAt: now.Add(-day)},
+		UserActivity{UserID: u1, Kind: UserActivityKindAPI, LastSeenAt: now.Add(-20 * day)},
+		UserActivity{UserID: u2, Kind: UserActivityKindAPI, LastSeenAt: now.Add(-2 * day)},
+		UserActivity{UserID: u4, Kind: UserActivityKindWeb, LastSeenAt: now},
+		// Activities of users that don't exist are skipped.
+		UserActivity{UserID: 9999, Kind: UserActivityKindWeb, LastSeenAt: now},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Last seen times only move forward.
+	if err := store.Record(ctx, UserActivity{UserID: u1, Kind: UserActivityKindWeb, LastSeenAt: now.Add(-5 * day)}); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("LastSeen", func(t *testing.T) {
+		have, err := store.LastSeen(ctx, u1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []UserActivity{
+			{UserID: u1, Kind: UserActivityKindAPI, LastSeenAt: now.Add(-20 * day)},
+			{UserID: u1, Kind: UserActivityKindWeb, LastSeenAt: now.Add(-day)},
+		}
+		if diff := cmp.Diff(want, have, cmp.Comparer(func(a, b time.Time) bool { return a.Equal(b) })); diff != "" {
+			t.Errorf("unexpected activities (-want +have):\n%s", diff)
+		}
+	})
+
+	t.Run("UsersActiveSince", func(t *testing.T) {
+		for _, tc := range []struct {
+			name  string
+			since time.Time
+			opts  UserActivityListOptions
+			want  []int32
+		}{
+			{name: "all kinds", since: now.Add(-3 * day), want: []int32{u1, u2}},
+			{name: "web", since: now.Add(-3 * day), opts: UserActivityListOptions{Kinds: []UserActivityKind{UserActivityKindWeb}}, want: []int32{u1}},
+			{name: "api", since: now.Add(-30 * day), opts: UserActivityListOptions{Kinds: []UserActivityKind{UserActivityKindAPI}}, want: []int32{u1, u2}},
+			{name: "limit", since: now.Add(-3 * day), opts: UserActivityListOptions{LimitOffset: &LimitOffset{Limit: 1, Offset: 1}}, want: []int32{u2}},
+			{name: "none", since: now.Add(day)},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				have, err := store.UsersActiveSince(ctx, tc.since, tc.opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(tc.want, have); diff != "" {
+					t.Errorf("unexpected users (-want +have):\n%s", diff)
+				}
+			})
+		}
+	})
+
+	t.Run("IdleUsers", func(t *testing.T) {
+		idleUserIDs := func(users []IdleUser) []int32 {
+			var ids []int32
+			for _, u := range users {
+				ids = append(ids, u.UserID)
+			}
+			return ids
+		}
+
+		// Users are created just now, so they're only idle since a time after now.
+		have, err := store.IdleUsers(ctx, now.Add(-3*day), UserActivityListOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(have) != 0 {
+			t.Errorf("unexpected idle users: %v", have)
+		}
+
+		// u3 has never been seen, and u2 was seen before u1.
+		have, err = store.IdleUsers(ctx, now.Add(time.Minute), UserActivityListOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]int32{u3, u2, u1}, idleUserIDs(have)); diff != "" {
+			t.Errorf("unexpected idle users (-want +have):\n%s", diff)
+		}
+		if have[0].LastSeenAt != nil || have[1].LastSeenAt == nil || !have[1].LastSeenAt.Equal(now.Add(-2*day)) {
+			t.Errorf("unexpected last seen times: %v, %v", have[0].LastSeenAt, have[1].LastSeenAt)
+		}
+
+		// u2 has never used the web app.
+		have, err = store.IdleUsers(ctx, now.Add(time.Minute), UserActivityListOptions{
+			Kinds:       []UserActivityKind{UserActivityKindWeb},
+			LimitOffset: &LimitOffset{Limit: 2},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]int32{u2, u3}, idleUserIDs(have)); diff != "" {
+			t.Errorf("unexpected idle users (-want +have):\n%s", diff)
+		}
+	})
+}
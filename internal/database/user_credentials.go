@@ -27,6 +27,19 @@ type UserCredential struct {
 	EncryptionKeyID     string
 	CreatedAt           time.Time
 	UpdatedAt           time.Time
+	LastUsedAt          *time.Time
+
+	// CheckedAt is when the credential was last checked against its code host,
+	// and CheckFailureMessage why that check found the credential unusable.
+	CheckedAt           *time.Time
+	CheckFailureMessage *string
+
+	// PreviousEncryptedCredential is the credential replaced by the last
+	// rotation, encrypted with the same key as EncryptedCredential. It remains
+	// valid until PreviousCredentialExpiresAt, so that in-flight operations
+	// using it don't fail.
+	PreviousEncryptedCredential []byte
+	PreviousCredentialExpiresAt *time.Time
 
 	// TODO(batch-change-credential-encryption): On or after Sourcegraph 3.30,
 	// we should remove the credential and SSHMigrationApplied fields.
@@ -38,17 +51,31 @@ type UserCredential struct {
 // Authenticator decrypts and creates the authenticator associated with the user
 // credential.
 func (uc *UserCredential) Authenticator(ctx context.Context) (auth.Authenticator, error) {
+	return uc.decryptAuthenticator(ctx, uc.EncryptedCredential)
+}
+
+// PreviousAuthenticator decrypts and creates the authenticator replaced by the
+// last rotation of the user credential. It returns nil if there is none, or if
+// its overlap window has expired.
+func (uc *UserCredential) PreviousAuthenticator(ctx context.Context) (auth.Authenticator, error) {
+	if uc.PreviousEncryptedCredential == nil || uc.PreviousCredentialExpiresAt == nil || !timeutil.Now().Before(*uc.PreviousCredentialExpiresAt) {
+		return nil, nil
+	}
+	return uc.decryptAuthenticator(ctx, uc.PreviousEncryptedCredential)
+}
+
+func (uc *UserCredential) decryptAuthenticator(ctx context.Context, encrypted []byte) (auth.Authenticator, error) {
 	// The record includes a field indicating the encryption key ID. We don't
 	// really have a way to look up a key by ID right now, so this is used as a
 	// marker of whether we should expect a key or not.
 	if uc.EncryptionKeyID == "" || uc.EncryptionKeyID == UserCredentialUnmigratedEncryptionKeyID {
-		return UnmarshalAuthenticator(string(uc.EncryptedCredential))
+		return UnmarshalAuthenticator(string(encrypted))
 	}
 	if uc.key == nil {
 		return nil, errors.New("user credential is encrypted, but no key is available to decrypt it")
 	}
 
-	secret, err := uc.key.Decrypt(ctx, uc.EncryptedCredential)
+	secret, err := uc.key.Decrypt(ctx, encrypted)
 	if err != nil {
 		return nil, errors.Wrap(err, "decrypting credential")
 	}
@@ -62,8 +89,21 @@ func (uc *UserCredential) Authenticator(ctx context.Context) (auth.Authenticator
 }
 
 // SetAuthenticator encrypts and sets the authenticator within the user
-// credential.
+// credential. The previous authenticator, if any, is re-encrypted along with it.
 func (uc *UserCredential) SetAuthenticator(ctx context.Context, a auth.Authenticator) error {
+	var previous auth.Authenticator
+	if uc.PreviousEncryptedCredential != nil {
+		var err error
+		if previous, err = uc.decryptAuthenticator(ctx, uc.PreviousEncryptedCredential); err != nil {
+			return errors.Wrap(err, "decrypting previous credential")
+		}
+	}
+	return uc.setAuthenticators(ctx, a, previous)
+}
+
+// setAuthenticators encrypts and sets the current and the previous
+// authenticators within the user credential. previous may be nil.
+func (uc *UserCredential) setAuthenticators(ctx context.Context, a, previous auth.Authenticator) error {
 	// Set the key ID. This is cargo culted from external_accounts.go, and the
 	// key ID doesn't appear to be actually useful as anything other than a
 	// marker of whether the data is expected to be encrypted or not.
@@ -77,7 +117,15 @@ func (uc *UserCredential) SetAuthenticator(ctx context.Context, a auth.Authentic
 		return errors.Wrap(err, "encrypting authenticator")
 	}
 
+	var previousSecret []byte
+	if previous != nil {
+		if previousSecret, err = EncryptAuthenticator(ctx, uc.key, previous); err != nil {
+			return errors.Wrap(err, "encrypting previous authenticator")
+		}
+	}
+
 	uc.EncryptedCredential = secret
+	uc.PreviousEncryptedCredential = previousSecret
 	uc.EncryptionKeyID = id
 
 	return nil
@@ -127,12 +175,12 @@ func UserCredentialsWith(other basestore.ShareableStore, key encryption.Key) *Us
 }
 
 func (s *UserCredentialsStore) With(other basestore.ShareableStore) *UserCredentialsStore {
-	return &UserCredentialsStore{Store: s.Store.With(other)}
+	return &UserCredentialsStore{Store: s.Store.With(other), key: s.key}
 }
 
 func (s *UserCredentialsStore) Transact(ctx context.Context) (*UserCredentialsStore, error) {
 	txBase, err := s.Store.Transact(ctx)
-	return &UserCredentialsStore{Store: txBase}, err
+	return &UserCredentialsStore{Store: txBase, key: s.key}, err
 }
 
 // UserCredentialScope represents the unique scope for a credential. Only one
@@ -201,6 +249,8 @@ func (s *UserCredentialsStore) Update(ctx context.Context, credential *UserCrede
 		credential.EncryptionKeyID,
 		credential.UpdatedAt,
 		credential.SSHMigrationApplied,
+		credential.PreviousEncryptedCredential,
+		credential.PreviousCredentialExpiresAt,
 		credential.ID,
 		sqlf.Join(userCredentialsColumns, ", "),
 	)
@@ -287,6 +337,135 @@ func (s *UserCredentialsStore) GetByScope(ctx context.Context, scope UserCredent
 	return &cred, nil
 }
 
+// CreateWithSSHKeypair creates a new user credential like Create, after adding
+// an SSH keypair generated server-side to the authenticator, so that the
+// credential can be used to push over SSH without the user providing a private
+// key. The public key can be read from the authenticator of the credential, to
+// be added to the code host.
+func (s *UserCredentialsStore) CreateWithSSHKeypair(ctx context.Context, scope UserCredentialScope, credential auth.Authenticator) (*UserCredential, error) {
+	keypair, err := encryption.GenerateRSAKey()
+	if err != nil {
+		return nil, err
+	}
+	a, err := withSSHKeypair(credential, keypair)
+	if err != nil {
+		return nil, err
+	}
+	return s.Create(ctx, scope, a)
+}
+
+// Rotate replaces the authenticator of the user credential with the given
+// one. The replaced authenticator remains available through
+// PreviousAuthenticator for the given overlap, so that operations which
+// started with it don't fail until the new authenticator has been rolled out.
+func (s *UserCredentialsStore) Rotate(ctx context.Context, id int64, credential auth.Authenticator, overlap time.Duration) (*UserCredential, error) {
+	if Mocks.UserCredentials.Rotate != nil {
+		return Mocks.UserCredentials.Rotate(ctx, id, credential, overlap)
+	}
+
+	return s.rotate(ctx, id, overlap, func(auth.Authenticator) (auth.Authenticator, error) {
+		return credential, nil
+	})
+}
+
+// RotateSSHKeypair replaces the SSH keypair of the user credential with a new
+// one generated server-side, keeping the rest of its authenticator. Like with
+// Rotate, the replaced keypair remains available for the given overlap.
+func (s *UserCredentialsStore) RotateSSHKeypair(ctx context.Context, id int64, overlap time.Duration) (*UserCredential, error) {
+	if Mocks.UserCredentials.RotateSSHKeypair != nil {
+		return Mocks.UserCredentials.RotateSSHKeypair(ctx, id, overlap)
+	}
+
+	return s.rotate(ctx, id, overlap, func(current auth.Authenticator) (auth.Authenticator, error) {
+		keypair, err := encryption.GenerateRSAKey()
+		if err != nil {
+			return nil, err
+		}
+		return withSSHKeypair(current, keypair)
+	})
+}
+
+// rotate replaces the authenticator of the user credential with the one
+// returned by next, keeping the current one as the previous authenticator for
+// the given overlap.
+func (s *UserCredentialsStore) rotate(ctx context.Context, id int64, overlap time.Duration, next func(auth.Authenticator) (auth.Authenticator, error)) (_ *UserCredential, err error) {
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	q := sqlf.Sprintf(
+		"SELECT %s FROM user_credentials WHERE id = %s FOR UPDATE",
+		sqlf.Join(userCredentialsColumns, ", "),
+		id,
+	)
+	cred := UserCredential{key: tx.key}
+	if err := scanUserCredential(&cred, tx.QueryRow(ctx, q)); err == sql.ErrNoRows {
+		return nil, UserCredentialNotFoundErr{args: []interface{}{id}}
+	} else if err != nil {
+		return nil, err
+	}
+
+	current, err := cred.Authenticator(ctx)
+	if err != nil {
+		return nil, err
+	}
+	a, err := next(current)
+	if err != nil {
+		return nil, err
+	}
+	if err := cred.setAuthenticators(ctx, a, current); err != nil {
+		return nil, err
+	}
+	expiresAt := timeutil.Now().Add(overlap)
+	cred.PreviousCredentialExpiresAt = &expiresAt
+
+	if err := tx.Update(ctx, &cred); err != nil {
+		return nil, err
+	}
+	return &cred, nil
+}
+
+// MarkUsed records that the given user credential was used to authenticate
+// against its code host now.
+func (s *UserCredentialsStore) MarkUsed(ctx context.Context, id int64) error {
+	if Mocks.UserCredentials.MarkUsed != nil {
+		return Mocks.UserCredentials.MarkUsed(ctx, id)
+	}
+
+	return s.Exec(ctx, sqlf.Sprintf("UPDATE user_credentials SET last_used_at = %s WHERE id = %s", timeutil.Now(), id))
+}
+
+// MarkChecked records the result of checking the given user credential
+// against its code host now. A nil failure message records a successful check.
+func (s *UserCredentialsStore) MarkChecked(ctx context.Context, credential *UserCredential, failureMessage *string) error {
+	if Mocks.UserCredentials.MarkChecked != nil {
+		return Mocks.UserCredentials.MarkChecked(ctx, credential, failureMessage)
+	}
+
+	now := timeutil.Now()
+	if err := s.Exec(ctx, sqlf.Sprintf(
+		"UPDATE user_credentials SET checked_at = %s, check_failure_message = %s WHERE id = %s",
+		now,
+		failureMessage,
+		credential.ID,
+	)); err != nil {
+		return err
+	}
+
+	credential.CheckedAt = &now
+	credential.CheckFailureMessage = failureMessage
+	return nil
+}
+
+// DeleteExpiredPreviousCredentials removes the previous authenticators whose
+// overlap window has expired, so that we don't hold on to credentials that
+// were rotated away longer than needed.
+func (s *UserCredentialsStore) DeleteExpiredPreviousCredentials(ctx context.Context) error {
+	return s.Exec(ctx, sqlf.Sprintf(userCredentialsDeleteExpiredPreviousQueryFmtstr, timeutil.Now()))
+}
+
 // UserCredentialsListOpts provide the options when listing credentials. At
 // least one field in Scope must be set.
 type UserCredentialsListOpts struct {
@@ -413,6 +592,11 @@ var userCredentialsColumns = []*sqlf.Query{
 	sqlf.Sprintf("created_at"),
 	sqlf.Sprintf("updated_at"),
 	sqlf.Sprintf("ssh_migration_applied"),
+	sqlf.Sprintf("last_used_at"),
+	sqlf.Sprintf("previous_credential"),
+	sqlf.Sprintf("previous_credential_expires_at"),
+	sqlf.Sprintf("checked_at"),
+	sqlf.Sprintf("check_failure_message"),
 }
 
 // The more unwieldy queries are below rather than inline in the above methods
@@ -478,12 +662,52 @@ SET
 	credential = %s,
 	encryption_key_id = %s,
 	updated_at = %s,
-	ssh_migration_applied = %s
+	ssh_migration_applied = %s,
+	previous_credential = %s,
+	previous_credential_expires_at = %s
 WHERE
 	id = %s
 RETURNING %s
 `
 
+const userCredentialsDeleteExpiredPreviousQueryFmtstr = `
+-- source: internal/database/user_credentials.go:DeleteExpiredPreviousCredentials
+UPDATE user_credentials
+SET
+	previous_credential = NULL,
+	previous_credential_expires_at = NULL
+WHERE
+	previous_credential IS NOT NULL AND
+	(previous_credential_expires_at IS NULL OR previous_credential_expires_at <= %s)
+`
+
+// withSSHKeypair returns a copy of the authenticator which authenticates over
+// SSH with the given keypair. Only authenticators which have an SSH-capable
+// counterpart are supported.
+func withSSHKeypair(a auth.Authenticator, keypair *encryption.RSAKey) (auth.Authenticator, error) {
+	switch a := a.(type) {
+	case *auth.OAuthBearerToken:
+		return &auth.OAuthBearerTokenWithSSH{
+			OAuthBearerToken: *a,
+			PrivateKey:       keypair.PrivateKey,
+			PublicKey:        keypair.PublicKey,
+			Passphrase:       keypair.Passphrase,
+		}, nil
+	case *auth.OAuthBearerTokenWithSSH:
+		return withSSHKeypair(&a.OAuthBearerToken, keypair)
+	case *auth.BasicAuth:
+		return &auth.BasicAuthWithSSH{
+			BasicAuth:  *a,
+			PrivateKey: keypair.PrivateKey,
+			PublicKey:  keypair.PublicKey,
+			Passphrase: keypair.Passphrase,
+		}, nil
+	case *auth.BasicAuthWithSSH:
+		return withSSHKeypair(&a.BasicAuth, keypair)
+	}
+	return nil, errors.Errorf("authenticator of type %T does not support SSH", a)
+}
+
 // scanUserCredential scans a credential from the given scanner into the given
 // credential.
 //
@@ -503,6 +727,11 @@ func scanUserCredential(cred *UserCredential, s interface {
 		&cred.CreatedAt,
 		&cred.UpdatedAt,
 		&cred.SSHMigrationApplied,
+		&cred.LastUsedAt,
+		&cred.PreviousEncryptedCredential,
+		&cred.PreviousCredentialExpiresAt,
+		&cred.CheckedAt,
+		&cred.CheckFailureMessage,
 	)
 }
 
@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"time"
 
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
 )
@@ -13,4 +14,9 @@ type MockUserCredentials struct {
 	GetByID    func(context.Context, int64) (*UserCredential, error)
 	GetByScope func(context.Context, UserCredentialScope) (*UserCredential, error)
 	List       func(context.Context, UserCredentialsListOpts) ([]*UserCredential, int, error)
+
+	Rotate           func(context.Context, int64, auth.Authenticator, time.Duration) (*UserCredential, error)
+	RotateSSHKeypair func(context.Context, int64, time.Duration) (*UserCredential, error)
+	MarkUsed         func(context.Context, int64) error
+	MarkChecked      func(context.Context, *UserCredential, *string) error
 }
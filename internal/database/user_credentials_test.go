@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/gomodule/oauth1/oauth"
@@ -23,6 +24,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
+	"github.com/sourcegraph/sourcegraph/internal/timeutil"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 )
 
@@ -157,6 +159,92 @@ func TestUserCredential_SetAuthenticator(t *testing.T) {
 	})
 }
 
+func TestUserCredential_PreviousAuthenticator(t *testing.T) {
+	ctx := context.Background()
+	previous := &auth.OAuthBearerToken{Token: "previous"}
+
+	uc := &UserCredential{key: et.TestKey{}}
+	if err := uc.setAuthenticators(ctx, &auth.OAuthBearerToken{Token: "current"}, previous); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("no expiry", func(t *testing.T) {
+		if a, err := uc.PreviousAuthenticator(ctx); err != nil || a != nil {
+			t.Errorf("unexpected previous authenticator: %v, %v", a, err)
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		expiresAt := timeutil.Now().Add(-time.Minute)
+		uc.PreviousCredentialExpiresAt = &expiresAt
+		if a, err := uc.PreviousAuthenticator(ctx); err != nil || a != nil {
+			t.Errorf("unexpected previous authenticator: %v, %v", a, err)
+		}
+	})
+
+	t.Run("within overlap", func(t *testing.T) {
+		expiresAt := timeutil.Now().Add(time.Hour)
+		uc.PreviousCredentialExpiresAt = &expiresAt
+		a, err := uc.PreviousAuthenticator(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(previous, a); diff != "" {
+			t.Errorf("unexpected previous authenticator:\n%s", diff)
+		}
+
+		// Setting a new authenticator keeps the previous one.
+		if err := uc.SetAuthenticator(ctx, &auth.OAuthBearerToken{Token: "next"}); err != nil {
+			t.Fatal(err)
+		}
+		if a, err := uc.PreviousAuthenticator(ctx); err != nil {
+			t.Fatal(err)
+		} else if diff := cmp.Diff(previous, a); diff != "" {
+			t.Errorf("unexpected previous authenticator after SetAuthenticator:\n%s", diff)
+		}
+	})
+}
+
+func TestWithSSHKeypair(t *testing.T) {
+	keypair := &encryption.RSAKey{PrivateKey: "private", PublicKey: "public", Passphrase: "pass"}
+
+	for name, tc := range map[string]struct {
+		a    auth.Authenticator
+		want auth.Authenticator
+	}{
+		"bearer token": {
+			a:    &auth.OAuthBearerToken{Token: "abc"},
+			want: &auth.OAuthBearerTokenWithSSH{OAuthBearerToken: auth.OAuthBearerToken{Token: "abc"}, PrivateKey: "private", PublicKey: "public", Passphrase: "pass"},
+		},
+		"bearer token with ssh": {
+			a:    &auth.OAuthBearerTokenWithSSH{OAuthBearerToken: auth.OAuthBearerToken{Token: "abc"}, PrivateKey: "old", PublicKey: "old", Passphrase: "old"},
+			want: &auth.OAuthBearerTokenWithSSH{OAuthBearerToken: auth.OAuthBearerToken{Token: "abc"}, PrivateKey: "private", PublicKey: "public", Passphrase: "pass"},
+		},
+		"basic auth": {
+			a:    &auth.BasicAuth{Username: "foo", Password: "bar"},
+			want: &auth.BasicAuthWithSSH{BasicAuth: auth.BasicAuth{Username: "foo", Password: "bar"}, PrivateKey: "private", PublicKey: "public", Passphrase: "pass"},
+		},
+		"basic auth with ssh": {
+			a:    &auth.BasicAuthWithSSH{BasicAuth: auth.BasicAuth{Username: "foo", Password: "bar"}, PrivateKey: "old"},
+			want: &auth.BasicAuthWithSSH{BasicAuth: auth.BasicAuth{Username: "foo", Password: "bar"}, PrivateKey: "private", PublicKey: "public", Passphrase: "pass"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			have, err := withSSHKeypair(tc.a, keypair)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, have); diff != "" {
+				t.Errorf("unexpected authenticator:\n%s", diff)
+			}
+		})
+	}
+
+	if _, err := withSSHKeypair(&gitlab.SudoableToken{Token: "abc"}, keypair); err == nil {
+		t.Error("unexpected nil error")
+	}
+}
+
 func TestUserCredentials_CreateUpdate(t *testing.T) {
 	t.Parallel()
 	db := dbtest.NewDB(t, "")
@@ -290,6 +378,168 @@ func TestUserCredentials_Delete(t *testing.T) {
 	})
 }
 
+func TestUserCredentials_Rotate(t *testing.T) {
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	ctx, key, user := setUpUserCredentialTest(t, db)
+
+	encryption.MockGenerateRSAKey = func() (*encryption.RSAKey, error) {
+		return &encryption.RSAKey{PrivateKey: "private", PublicKey: "public", Passphrase: "pass"}, nil
+	}
+	t.Cleanup(func() { encryption.MockGenerateRSAKey = nil })
+
+	scope := UserCredentialScope{
+		Domain:              UserCredentialDomainBatches,
+		UserID:              user.ID,
+		ExternalServiceType: extsvc.TypeGitHub,
+		ExternalServiceID:   "https://github.com",
+	}
+	initial := &auth.OAuthBearerToken{Token: "abcdef"}
+
+	cred, err := UserCredentials(db, key).CreateWithSSHKeypair(ctx, scope, initial)
+	if err != nil {
+		t.Fatal(err)
+	}
+	withSSH := &auth.OAuthBearerTokenWithSSH{OAuthBearerToken: *initial, PrivateKey: "private", PublicKey: "public", Passphrase: "pass"}
+	if have, err := cred.Authenticator(ctx); err != nil {
+		t.Fatal(err)
+	} else if diff := cmp.Diff(withSSH, have); diff != "" {
+		t.Errorf("unexpected authenticator:\n%s", diff)
+	}
+
+	t.Run("nonextant", func(t *testing.T) {
+		_, err := UserCredentials(db, key).Rotate(ctx, cred.ID+1, initial, time.Hour)
+		if !errors.HasType(err, UserCredentialNotFoundErr{}) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	rotated := &auth.OAuthBearerTokenWithSSH{OAuthBearerToken: auth.OAuthBearerToken{Token: "ghijkl"}, PrivateKey: "private", PublicKey: "public", Passphrase: "pass"}
+	cred, err = UserCredentials(db, key).Rotate(ctx, cred.ID, rotated, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The rotated credential must be persisted, with the previous one.
+	cred, err = UserCredentials(db, key).GetByID(ctx, cred.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, err := cred.Authenticator(ctx); err != nil {
+		t.Fatal(err)
+	} else if diff := cmp.Diff(rotated, have); diff != "" {
+		t.Errorf("unexpected authenticator after rotation:\n%s", diff)
+	}
+	if have, err := cred.PreviousAuthenticator(ctx); err != nil {
+		t.Fatal(err)
+	} else if diff := cmp.Diff(withSSH, have); diff != "" {
+		t.Errorf("unexpected previous authenticator after rotation:\n%s", diff)
+	}
+
+	// Rotating the keypair keeps the token.
+	encryption.MockGenerateRSAKey = func() (*encryption.RSAKey, error) {
+		return &encryption.RSAKey{PrivateKey: "private2", PublicKey: "public2", Passphrase: "pass2"}, nil
+	}
+	cred, err = UserCredentials(db, key).RotateSSHKeypair(ctx, cred.ID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &auth.OAuthBearerTokenWithSSH{OAuthBearerToken: auth.OAuthBearerToken{Token: "ghijkl"}, PrivateKey: "private2", PublicKey: "public2", Passphrase: "pass2"}
+	if have, err := cred.Authenticator(ctx); err != nil {
+		t.Fatal(err)
+	} else if diff := cmp.Diff(want, have); diff != "" {
+		t.Errorf("unexpected authenticator after keypair rotation:\n%s", diff)
+	}
+
+	// Without overlap, the previous credential is expired right away and can
+	// be deleted.
+	if err := UserCredentials(db, key).DeleteExpiredPreviousCredentials(ctx); err != nil {
+		t.Fatal(err)
+	}
+	cred, err = UserCredentials(db, key).GetByID(ctx, cred.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cred.PreviousEncryptedCredential != nil || cred.PreviousCredentialExpiresAt != nil {
+		t.Errorf("previous credential not deleted: %+v", cred)
+	}
+}
+
+func TestUserCredentials_MarkUsed(t *testing.T) {
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	ctx, key, user := setUpUserCredentialTest(t, db)
+
+	cred, err := UserCredentials(db, key).Create(ctx, UserCredentialScope{
+		Domain:              UserCredentialDomainBatches,
+		UserID:              user.ID,
+		ExternalServiceType: extsvc.TypeGitHub,
+		ExternalServiceID:   "https://github.com",
+	}, &auth.OAuthBearerToken{Token: "abcdef"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cred.LastUsedAt != nil {
+		t.Errorf("unexpected last used time: %v", cred.LastUsedAt)
+	}
+
+	if err := UserCredentials(db, key).MarkUsed(ctx, cred.ID); err != nil {
+		t.Fatal(err)
+	}
+	cred, err = UserCredentials(db, key).GetByID(ctx, cred.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cred.LastUsedAt == nil {
+		t.Error("unexpected nil last used time")
+	}
+}
+
+func TestUserCredentials_MarkChecked(t *testing.T) {
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	ctx, key, user := setUpUserCredentialTest(t, db)
+
+	cred, err := UserCredentials(db, key).Create(ctx, UserCredentialScope{
+		Domain:              UserCredentialDomainBatches,
+		UserID:              user.ID,
+		ExternalServiceType: extsvc.TypeGitHub,
+		ExternalServiceID:   "https://github.com",
+	}, &auth.OAuthBearerToken{Token: "abcdef"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cred.CheckedAt != nil || cred.CheckFailureMessage != nil {
+		t.Errorf("unexpected check result: %v %v", cred.CheckedAt, cred.CheckFailureMessage)
+	}
+
+	failure := "credential is missing the required scopes: repo"
+	if err := UserCredentials(db, key).MarkChecked(ctx, cred, &failure); err != nil {
+		t.Fatal(err)
+	}
+	have, err := UserCredentials(db, key).GetByID(ctx, cred.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have.CheckedAt == nil {
+		t.Error("unexpected nil checked time")
+	}
+	if have.CheckFailureMessage == nil || *have.CheckFailureMessage != failure {
+		t.Errorf("unexpected check failure message: %v", have.CheckFailureMessage)
+	}
+
+	if err := UserCredentials(db, key).MarkChecked(ctx, cred, nil); err != nil {
+		t.Fatal(err)
+	}
+	have, err = UserCredentials(db, key).GetByID(ctx, cred.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have.CheckFailureMessage != nil {
+		t.Errorf("unexpected check failure message after successful check: %q", *have.CheckFailureMessage)
+	}
+}
+
 func TestUserCredentials_GetByID(t *testing.T) {
 	t.Parallel()
 	db := dbtest.NewDB(t, "")
@@ -0,0 +1,189 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// UserDataExport is the data Sourcegraph holds about a user, exported so that it can be handed to
+// the user before their account is deleted. Secrets (passwords, tokens, code host credentials and
+// external service configurations) are never exported.
+type UserDataExport struct {
+	ExportedAt time.Time `json:"exportedAt"`
+
+	User struct {
+		ID          int32     `json:"id"`
+		Username    string    `json:"username"`
+		DisplayName string    `json:"displayName,omitempty"`
+		AvatarURL   string    `json:"avatarURL,omitempty"`
+		SiteAdmin   bool      `json:"siteAdmin"`
+		CreatedAt   time.Time `json:"createdAt"`
+	} `json:"user"`
+
+	Emails []UserDataExportEmail `json:"emails"`
+
+	ExternalAccounts []UserDataExportExternalAccount `json:"externalAccounts"`
+
+	AccessTokens []UserDataExportAccessToken `json:"accessTokens"`
+
+	Organizations []string `json:"organizations"`
+
+	// Settings is the latest version of the settings of the user, as authored.
+	Settings *string `json:"settings"`
+
+	SavedSearches []UserDataExportSavedSearch `json:"savedSearches"`
+
+	ExternalServices []UserDataExportExternalService `json:"externalServices"`
+}
+
+type UserDataExportEmail struct {
+	Email      string     `json:"email"`
+	Primary    bool       `json:"primary"`
+	VerifiedAt *time.Time `json:"verifiedAt"`
+}
+
+type UserDataExportExternalAccount struct {
+	ServiceType string          `json:"serviceType"`
+	ServiceID   string          `json:"serviceID"`
+	AccountID   string          `json:"accountID"`
+	Data        json.RawMessage `json:"data,omitempty"`
+	CreatedAt   time.Time       `json:"createdAt"`
+}
+
+type UserDataExportAccessToken struct {
+	Note       string     `json:"note"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+}
+
+type UserDataExportSavedSearch struct {
+	Description string `json:"description"`
+	Query       string `json:"query"`
+	Notify      bool   `json:"notify"`
+}
+
+type UserDataExportExternalService struct {
+	Kind        string    `json:"kind"`
+	DisplayName string    `json:"displayName"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// ExportUserData returns the data held about the user with the given ID. It must be called
+// before the user is deleted: the email addresses of the user are removed when it is
+// soft-deleted.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin or the user.
+func ExportUserData(ctx context.Context, db dbutil.DB, userID int32) (*UserDataExport, error) {
+	user, err := Users(db).GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &UserDataExport{ExportedAt: time.Now().UTC()}
+	export.User.ID = user.ID
+	export.User.Username = user.Username
+	export.User.DisplayName = user.DisplayName
+	export.User.AvatarURL = user.AvatarURL
+	export.User.SiteAdmin = user.SiteAdmin
+	export.User.CreatedAt = user.CreatedAt
+
+	emails, err := UserEmails(db).ListByUser(ctx, UserEmailsListOptions{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	export.Emails = make([]UserDataExportEmail, 0, len(emails))
+	for _, e := range emails {
+		export.Emails = append(export.Emails, UserDataExportEmail{Email: e.Email, Primary: e.Primary, VerifiedAt: e.VerifiedAt})
+	}
+
+	accounts, err := ExternalAccounts(db).List(ctx, ExternalAccountsListOptions{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	export.ExternalAccounts = make([]UserDataExportExternalAccount, 0, len(accounts))
+	for _, a := range accounts {
+		// Only the account data is exported, the auth data holds the credentials of the account.
+		var data json.RawMessage
+		if a.Data != nil {
+			data = *a.Data
+		}
+		export.ExternalAccounts = append(export.ExternalAccounts, UserDataExportExternalAccount{
+			ServiceType: a.ServiceType,
+			ServiceID:   a.ServiceID,
+			AccountID:   a.AccountID,
+			Data:        data,
+			CreatedAt:   a.CreatedAt,
+		})
+	}
+
+	tokens, err := AccessTokens(db).List(ctx, AccessTokensListOptions{SubjectUserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	export.AccessTokens = make([]UserDataExportAccessToken, 0, len(tokens))
+	for _, t := range tokens {
+		if t.Internal {
+			continue
+		}
+		export.AccessTokens = append(export.AccessTokens, UserDataExportAccessToken{
+			Note:       t.Note,
+			Scopes:     t.Scopes,
+			CreatedAt:  t.CreatedAt,
+			LastUsedAt: t.LastUsedAt,
+		})
+	}
+
+	orgs, err := Orgs(db).GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	export.Organizations = make([]string, 0, len(orgs))
+	for _, o := range orgs {
+		export.Organizations = append(export.Organizations, o.Name)
+	}
+
+	settings, err := Settings(db).GetLatest(ctx, api.SettingsSubject{User: &userID})
+	if err != nil {
+		return nil, err
+	}
+	if settings != nil {
+		export.Settings = &settings.Contents
+	}
+
+	searches, err := SavedSearches(db).ListSavedSearchesByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	export.SavedSearches = make([]UserDataExportSavedSearch, 0, len(searches))
+	for _, s := range searches {
+		// Saved searches of the orgs of the user are listed too.
+		if s.UserID == nil || *s.UserID != userID {
+			continue
+		}
+		export.SavedSearches = append(export.SavedSearches, UserDataExportSavedSearch{
+			Description: s.Description,
+			Query:       s.Query,
+			Notify:      s.Notify,
+		})
+	}
+
+	services, err := ExternalServices(db).List(ctx, ExternalServicesListOptions{NamespaceUserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	export.ExternalServices = make([]UserDataExportExternalService, 0, len(services))
+	for _, s := range services {
+		export.ExternalServices = append(export.ExternalServices, UserDataExportExternalService{
+			Kind:        s.Kind,
+			DisplayName: s.DisplayName,
+			CreatedAt:   s.CreatedAt,
+		})
+	}
+
+	return export, nil
+}
@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestExportUserData(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	ctx := actor.WithInternalActor(context.Background())
+
+	user, err := Users(db).Create(ctx, NewUser{
+		Email:                 "alice@example.com",
+		Username:              "alice",
+		Password:              "correct horse battery staple",
+		EmailVerificationCode: "c",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := AccessTokens(db).Create(ctx, user.ID, []string{"user:all"}, "my token", user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Settings(db).CreateIfUpToDate(ctx, api.SettingsSubject{User: &user.ID}, nil, &user.ID, `{"search.defaultPatternType": "regexp"}`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SavedSearches(db).Create(ctx, &types.SavedSearch{Description: "todos", Query: "TODO", UserID: &user.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	export, err := ExportUserData(ctx, db, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if export.User.Username != "alice" {
+		t.Errorf("wrong username: %q", export.User.Username)
+	}
+	if len(export.Emails) != 1 || export.Emails[0].Email != "alice@example.com" || export.Emails[0].VerifiedAt != nil {
+		t.Errorf("wrong emails: %+v", export.Emails)
+	}
+	if len(export.AccessTokens) != 1 || export.AccessTokens[0].Note != "my token" {
+		t.Errorf("wrong access tokens: %+v", export.AccessTokens)
+	}
+	if export.Settings == nil || *export.Settings != `{"search.defaultPatternType": "regexp"}` {
+		t.Errorf("wrong settings: %v", export.Settings)
+	}
+	if len(export.SavedSearches) != 1 || export.SavedSearches[0].Query != "TODO" {
+		t.Errorf("wrong saved searches: %+v", export.SavedSearches)
+	}
+}
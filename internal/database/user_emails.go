@@ -207,26 +207,43 @@ func (s *UserEmailsStore) Remove(ctx context.Context, userID int32, email string
 	return nil
 }
 
+// maxEmailVerificationAttempts is the number of incorrect verification codes after which the
+// verification of an email is locked until a new verification code is sent.
+const maxEmailVerificationAttempts = 5
+
+var ErrEmailVerificationLocked = errors.New("too many failed email verification attempts")
+
 // Verify verifies the user's email address given the email verification code. If the code is not
 // correct (not the one originally used when creating the user or adding the user email), then it
 // returns false.
+//
+// After maxEmailVerificationAttempts incorrect codes, it returns ErrEmailVerificationLocked until a
+// new verification code is set with SetLastVerification.
 func (s *UserEmailsStore) Verify(ctx context.Context, userID int32, email, code string) (bool, error) {
 	if Mocks.UserEmails.Verify != nil {
 		return Mocks.UserEmails.Verify(ctx, userID, email, code)
 	}
 	s.ensureStore()
 	var dbCode sql.NullString
-	if err := s.Handle().DB().QueryRowContext(ctx, "SELECT verification_code FROM user_emails WHERE user_id=$1 AND email=$2", userID, email).Scan(&dbCode); err != nil {
+	var attempts int
+	if err := s.Handle().DB().QueryRowContext(ctx, "SELECT verification_code, verification_attempts FROM user_emails WHERE user_id=$1 AND email=$2", userID, email).Scan(&dbCode, &attempts); err != nil {
 		return false, err
 	}
 	if !dbCode.Valid {
 		return false, errors.New("email already verified")
 	}
+	// 🚨 SECURITY: Limit the number of attempts per verification code to prevent brute forcing it.
+	if attempts >= maxEmailVerificationAttempts {
+		return false, ErrEmailVerificationLocked
+	}
 	// 🚨 SECURITY: Use constant-time comparisons to avoid leaking the verification code via timing attack. It is not important to avoid leaking the *length* of the code, because the length of verification codes is constant.
 	if len(dbCode.String) != len(code) || subtle.ConstantTimeCompare([]byte(dbCode.String), []byte(code)) != 1 {
+		if _, err := s.Handle().DB().ExecContext(ctx, "UPDATE user_emails SET verification_attempts=verification_attempts+1 WHERE user_id=$1 AND email=$2", userID, email); err != nil {
+			return false, err
+		}
 		return false, nil
 	}
-	if _, err := s.Handle().DB().ExecContext(ctx, "UPDATE user_emails SET verification_code=null, verified_at=now() WHERE user_id=$1 AND email=$2", userID, email); err != nil {
+	if _, err := s.Handle().DB().ExecContext(ctx, "UPDATE user_emails SET verification_code=null, verification_attempts=0, verified_at=now() WHERE user_id=$1 AND email=$2", userID, email); err != nil {
 		return false, err
 	}
 
@@ -264,12 +281,13 @@ func (s *UserEmailsStore) SetVerified(ctx context.Context, userID int32, email s
 }
 
 // SetLastVerification sets the "last_verification_sent_at" column to now() and updates the verification code for given email of the user.
+// It also resets the number of failed verification attempts.
 func (s *UserEmailsStore) SetLastVerification(ctx context.Context, userID int32, email, code string) error {
 	if Mocks.UserEmails.SetLastVerification != nil {
 		return Mocks.UserEmails.SetLastVerification(ctx, userID, email, code)
 	}
 	s.ensureStore()
-	res, err := s.Handle().DB().ExecContext(ctx, "UPDATE user_emails SET last_verification_sent_at=now(), verification_code = $3 WHERE user_id=$1 AND email=$2", userID, email, code)
+	res, err := s.Handle().DB().ExecContext(ctx, "UPDATE user_emails SET last_verification_sent_at=now(), verification_code = $3, verification_attempts = 0 WHERE user_id=$1 AND email=$2", userID, email, code)
 	if err != nil {
 		return err
 	}
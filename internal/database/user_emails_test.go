@@ -474,6 +474,49 @@ func TestUserEmails_SetLastVerificationSentAt(t *testing.T) {
 	}
 }
 
+func TestUserEmails_VerifyAttempts(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	ctx := context.Background()
+
+	const addr = "alice@example.com"
+	user, err := Users(db).Create(ctx, NewUser{
+		Email:                 addr,
+		Username:              "alice",
+		Password:              "pw",
+		EmailVerificationCode: "c",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < maxEmailVerificationAttempts; i++ {
+		if verified, err := UserEmails(db).Verify(ctx, user.ID, addr, "x"); err != nil {
+			t.Fatal(err)
+		} else if verified {
+			t.Fatal("want not verified with incorrect code")
+		}
+	}
+
+	// The correct code is rejected once the verification is locked.
+	if _, err := UserEmails(db).Verify(ctx, user.ID, addr, "c"); err != ErrEmailVerificationLocked {
+		t.Fatalf("got error %v, want %v", err, ErrEmailVerificationLocked)
+	}
+
+	// Sending a new code unlocks the verification.
+	if err := UserEmails(db).SetLastVerification(ctx, user.ID, addr, "d"); err != nil {
+		t.Fatal(err)
+	}
+	if verified, err := UserEmails(db).Verify(ctx, user.ID, addr, "d"); err != nil {
+		t.Fatal(err)
+	} else if !verified {
+		t.Fatal("want verified with new code")
+	}
+}
+
 func TestUserEmails_GetLatestVerificationSentEmail(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
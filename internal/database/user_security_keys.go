@@ -0,0 +1,197 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+	"github.com/sourcegraph/sourcegraph/internal/encryption/keyring"
+)
+
+var (
+	// ErrSecurityKeyNotFound occurs when a security key does not exist or belongs to another user.
+	ErrSecurityKeyNotFound = errors.New("security key not found")
+
+	// ErrSecurityKeyAlreadyRegistered occurs when the credential of a security key is already
+	// registered, for any user.
+	ErrSecurityKeyAlreadyRegistered = errors.New("security key is already registered")
+)
+
+// UserSecurityKey is a security key (WebAuthn credential) registered by a user as a second factor.
+type UserSecurityKey struct {
+	ID     int64
+	UserID int32
+	// Name is the name given to the security key by the user.
+	Name string
+	// CredentialID is the ID of the credential, chosen by the security key.
+	CredentialID []byte
+	// PublicKey is the COSE encoded public key of the credential.
+	PublicKey []byte
+	// SignCount is the last signature counter seen from the security key.
+	SignCount  uint32
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+}
+
+// UserSecurityKeyStore provides access to the `user_security_keys` table. The public keys of the
+// security keys are encrypted with the user security key encryption key, if one is configured.
+type UserSecurityKeyStore struct {
+	*basestore.Store
+	key encryption.Key
+}
+
+// UserSecurityKeys instantiates and returns a new UserSecurityKeyStore.
+func UserSecurityKeys(db dbutil.DB) *UserSecurityKeyStore {
+	return &UserSecurityKeyStore{Store: basestore.NewWithDB(db, sql.TxOptions{})}
+}
+
+// WithEncryptionKey returns a copy of the store that uses the given encryption key instead of the
+// configured one.
+func (s *UserSecurityKeyStore) WithEncryptionKey(key encryption.Key) *UserSecurityKeyStore {
+	return &UserSecurityKeyStore{Store: s.Store, key: key}
+}
+
+func (s *UserSecurityKeyStore) getEncryptionKey() encryption.Key {
+	if s.key != nil {
+		return s.key
+	}
+	return keyring.Default().UserSecurityKeyKey
+}
+
+// Create registers the security key for its user and sets its ID and creation time. If the
+// credential is already registered, ErrSecurityKeyAlreadyRegistered is returned.
+//
+// 🚨 SECURITY: The caller must ensure that the credential was verified for the user.
+func (s *UserSecurityKeyStore) Create(ctx context.Context, k *UserSecurityKey) error {
+	if Mocks.UserSecurityKeys.Create != nil {
+		return Mocks.UserSecurityKeys.Create(ctx, k)
+	}
+
+	publicKey, keyID, err := MaybeEncrypt(ctx, s.getEncryptionKey(), base64.StdEncoding.EncodeToString(k.PublicKey))
+	if err != nil {
+		return err
+	}
+
+	if err := s.Handle().DB().QueryRowContext(ctx, `
+INSERT INTO user_security_keys(user_id, name, credential_id, public_key, encryption_key_id, sign_count)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (credential_id) DO NOTHING
+RETURNING id, created_at
+`,
+		k.UserID, k.Name, k.CredentialID, publicKey, keyID, int64(k.SignCount),
+	).Scan(&k.ID, &k.CreatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrSecurityKeyAlreadyRegistered
+		}
+		return err
+	}
+	return nil
+}
+
+// ListByUserID returns the security keys of the user, oldest first.
+func (s *UserSecurityKeyStore) ListByUserID(ctx context.Context, userID int32) ([]*UserSecurityKey, error) {
+	if Mocks.UserSecurityKeys.ListByUserID != nil {
+		return Mocks.UserSecurityKeys.ListByUserID(ctx, userID)
+	}
+
+	rows, err := s.Handle().DB().QueryContext(ctx, `
+SELECT id, user_id, name, credential_id, public_key, encryption_key_id, sign_count, created_at, last_used_at
+FROM user_security_keys
+WHERE user_id=$1
+ORDER BY id ASC
+`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*UserSecurityKey
+	for rows.Next() {
+		var (
+			k                UserSecurityKey
+			publicKey, keyID string
+			signCount        int64
+		)
+		if err := rows.Scan(&k.ID, &k.UserID, &k.Name, &k.CredentialID, &publicKey, &keyID, &signCount, &k.CreatedAt, &k.LastUsedAt); err != nil {
+			return nil, err
+		}
+
+		publicKey, err = MaybeDecrypt(ctx, s.getEncryptionKey(), publicKey, keyID)
+		if err != nil {
+			return nil, err
+		}
+		k.PublicKey, err = base64.StdEncoding.DecodeString(publicKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "decoding security key public key")
+		}
+		k.SignCount = uint32(signCount)
+		keys = append(keys, &k)
+	}
+	return keys, rows.Err()
+}
+
+// UpdateSignCount records a use of the security key, along with its new signature counter.
+func (s *UserSecurityKeyStore) UpdateSignCount(ctx context.Context, id int64, signCount uint32) error {
+	if Mocks.UserSecurityKeys.UpdateSignCount != nil {
+		return Mocks.UserSecurityKeys.UpdateSignCount(ctx, id, signCount)
+	}
+
+	res, err := s.Handle().DB().ExecContext(ctx, `
+UPDATE user_security_keys SET sign_count=$2, last_used_at=now()
+WHERE id=$1
+`,
+		id, int64(signCount),
+	)
+	if err != nil {
+		return err
+	}
+	nrows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if nrows == 0 {
+		return ErrSecurityKeyNotFound
+	}
+	return nil
+}
+
+// Delete removes the user's security key. If it does not exist or belongs to another user,
+// ErrSecurityKeyNotFound is returned.
+func (s *UserSecurityKeyStore) Delete(ctx context.Context, userID int32, id int64) error {
+	if Mocks.UserSecurityKeys.Delete != nil {
+		return Mocks.UserSecurityKeys.Delete(ctx, userID, id)
+	}
+
+	res, err := s.Handle().DB().ExecContext(ctx, `
+DELETE FROM user_security_keys
+WHERE id=$1 AND user_id=$2
+`,
+		id, userID,
+	)
+	if err != nil {
+		return err
+	}
+	nrows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if nrows == 0 {
+		return ErrSecurityKeyNotFound
+	}
+	return nil
+}
+
+type MockUserSecurityKeys struct {
+	Create          func(ctx context.Context, k *UserSecurityKey) error
+	ListByUserID    func(ctx context.Context, userID int32) ([]*UserSecurityKey, error)
+	UpdateSignCount func(ctx context.Context, id int64, signCount uint32) error
+	Delete          func(ctx context.Context, userID int32, id int64) error
+}
@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	et "github.com/sourcegraph/sourcegraph/internal/encryption/testing"
+)
+
+func TestUserSecurityKeys(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	ctx := context.Background()
+
+	newUser := func(username string) int32 {
+		user, err := Users(db).Create(ctx, NewUser{Username: username})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return user.ID
+	}
+	alice, bob := newUser("alice"), newUser("bob")
+
+	for _, store := range []*UserSecurityKeyStore{UserSecurityKeys(db), UserSecurityKeys(db).WithEncryptionKey(et.TestKey{})} {
+		if _, err := db.ExecContext(ctx, "DELETE FROM user_security_keys"); err != nil {
+			t.Fatal(err)
+		}
+
+		key := &UserSecurityKey{
+			UserID:       alice,
+			Name:         "yubikey",
+			CredentialID: []byte("credential"),
+			PublicKey:    []byte("public key"),
+			SignCount:    1,
+		}
+		if err := store.Create(ctx, key); err != nil {
+			t.Fatal(err)
+		}
+
+		// A credential can only be registered once.
+		if err := store.Create(ctx, &UserSecurityKey{UserID: bob, Name: "yubikey", CredentialID: []byte("credential"), PublicKey: []byte("other")}); err != ErrSecurityKeyAlreadyRegistered {
+			t.Fatalf("got error %v, want %v", err, ErrSecurityKeyAlreadyRegistered)
+		}
+
+		if err := store.UpdateSignCount(ctx, key.ID, 2); err != nil {
+			t.Fatal(err)
+		}
+
+		keys, err := store.ListByUserID(ctx, alice)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(keys) != 1 || keys[0].LastUsedAt == nil {
+			t.Fatalf("got %+v, want one used security key", keys)
+		}
+		want := *key
+		want.SignCount = 2
+		want.LastUsedAt = keys[0].LastUsedAt
+		if diff := cmp.Diff(&want, keys[0]); diff != "" {
+			t.Fatalf("mismatch (-want +got):\n%s", diff)
+		}
+
+		// Users can only delete their own security keys.
+		if err := store.Delete(ctx, bob, key.ID); err != ErrSecurityKeyNotFound {
+			t.Fatalf("got error %v, want %v", err, ErrSecurityKeyNotFound)
+		}
+		if err := store.Delete(ctx, alice, key.ID); err != nil {
+			t.Fatal(err)
+		}
+		if keys, err := store.ListByUserID(ctx, alice); err != nil || len(keys) != 0 {
+			t.Fatalf("got %+v, %v, want no security keys", keys, err)
+		}
+	}
+}
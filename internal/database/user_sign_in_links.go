@@ -0,0 +1,111 @@
+package database
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+var (
+	// ErrSignInLinkNotFound occurs when a sign-in link does not exist, has expired or has already
+	// been used.
+	ErrSignInLinkNotFound = errors.New("sign-in link not found")
+
+	signInLinkRateLimit    = "1 minute"
+	ErrSignInLinkRateLimit = errors.New("sign-in link rate limit reached")
+)
+
+// UserSignInLinkStore provides access to the `user_sign_in_links` table, which stores the
+// one-time links that users can request by email to sign in without a password.
+type UserSignInLinkStore struct {
+	*basestore.Store
+}
+
+// UserSignInLinks instantiates and returns a new UserSignInLinkStore.
+func UserSignInLinks(db dbutil.DB) *UserSignInLinkStore {
+	return &UserSignInLinkStore{Store: basestore.NewWithDB(db, sql.TxOptions{})}
+}
+
+// Create creates a sign-in link for the user that expires after the given duration. The secret
+// token of the link is returned; like access tokens, only its SHA-256 hash is stored.
+//
+// At most one sign-in link can be created per user per minute, otherwise ErrSignInLinkRateLimit is
+// returned.
+//
+// 🚨 SECURITY: The caller must only send the token to a verified email address of the user.
+func (s *UserSignInLinkStore) Create(ctx context.Context, userID int32, expiry time.Duration) (token string, err error) {
+	if Mocks.UserSignInLinks.Create != nil {
+		return Mocks.UserSignInLinks.Create(ctx, userID, expiry)
+	}
+
+	var b [20]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+
+	res, err := s.Handle().DB().ExecContext(ctx, `
+INSERT INTO user_sign_in_links(user_id, value_sha256, expires_at)
+SELECT u.id, $2, now() + $3 * interval '1 second'
+FROM users u
+WHERE u.id=$1 AND u.deleted_at IS NULL AND NOT EXISTS (
+	SELECT 1 FROM user_sign_in_links l
+	WHERE l.user_id=u.id AND l.created_at > now() - $4::interval
+)
+`,
+		userID, toSHA256Bytes(b[:]), expiry.Seconds(), signInLinkRateLimit,
+	)
+	if err != nil {
+		return "", err
+	}
+	nrows, err := res.RowsAffected()
+	if err != nil {
+		return "", err
+	}
+	if nrows == 0 {
+		return "", ErrSignInLinkRateLimit
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+// Consume marks the sign-in link as used and returns the ID of its user. If the link does not exist,
+// has expired or has already been used, ErrSignInLinkNotFound is returned.
+//
+// 🚨 SECURITY: A sign-in link can only be consumed once.
+func (s *UserSignInLinkStore) Consume(ctx context.Context, tokenHexEncoded string) (userID int32, err error) {
+	if Mocks.UserSignInLinks.Consume != nil {
+		return Mocks.UserSignInLinks.Consume(ctx, tokenHexEncoded)
+	}
+
+	token, err := hex.DecodeString(tokenHexEncoded)
+	if err != nil {
+		return 0, ErrSignInLinkNotFound
+	}
+
+	if err := s.Handle().DB().QueryRowContext(ctx, `
+UPDATE user_sign_in_links l SET used_at=now()
+FROM users u
+WHERE l.value_sha256=$1 AND l.used_at IS NULL AND l.expires_at > now()
+AND u.id=l.user_id AND u.deleted_at IS NULL
+RETURNING l.user_id
+`,
+		toSHA256Bytes(token),
+	).Scan(&userID); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrSignInLinkNotFound
+		}
+		return 0, err
+	}
+	return userID, nil
+}
+
+type MockUserSignInLinks struct {
+	Create  func(ctx context.Context, userID int32, expiry time.Duration) (string, error)
+	Consume func(ctx context.Context, tokenHexEncoded string) (int32, error)
+}
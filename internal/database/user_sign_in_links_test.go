@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+)
+
+func TestUserSignInLinks(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	ctx := context.Background()
+
+	user, err := Users(db).Create(ctx, NewUser{
+		Email:                 "alice@example.com",
+		Username:              "alice",
+		Password:              "pw",
+		EmailVerificationCode: "c",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := UserSignInLinks(db).Create(ctx, user.ID, 15*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only one sign-in link can be created per minute.
+	if _, err := UserSignInLinks(db).Create(ctx, user.ID, 15*time.Minute); err != ErrSignInLinkRateLimit {
+		t.Fatalf("got error %v, want %v", err, ErrSignInLinkRateLimit)
+	}
+
+	if _, err := UserSignInLinks(db).Consume(ctx, "00"+token[2:]); err != ErrSignInLinkNotFound {
+		t.Fatalf("got error %v, want %v for wrong token", err, ErrSignInLinkNotFound)
+	}
+	userID, err := UserSignInLinks(db).Consume(ctx, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if userID != user.ID {
+		t.Errorf("got user ID %d, want %d", userID, user.ID)
+	}
+
+	// Sign-in links can only be used once.
+	if _, err := UserSignInLinks(db).Consume(ctx, token); err != ErrSignInLinkNotFound {
+		t.Fatalf("got error %v, want %v for used token", err, ErrSignInLinkNotFound)
+	}
+
+	// Expired sign-in links cannot be used.
+	if _, err := db.ExecContext(ctx, "DELETE FROM user_sign_in_links"); err != nil {
+		t.Fatal(err)
+	}
+	token, err = UserSignInLinks(db).Create(ctx, user.ID, -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := UserSignInLinks(db).Consume(ctx, token); err != ErrSignInLinkNotFound {
+		t.Fatalf("got error %v, want %v for expired token", err, ErrSignInLinkNotFound)
+	}
+}
@@ -450,6 +450,17 @@ func (u *UserStore) Update(ctx context.Context, id int32, update UserUpdate) (er
 	if update.Username != "" {
 		fieldUpdates = append(fieldUpdates, sqlf.Sprintf("username=%s", update.Username))
 
+		// Keep links to the old username working.
+		oldUsername, ok, err := basestore.ScanFirstString(tx.Query(ctx, sqlf.Sprintf("SELECT username FROM users WHERE id=%s AND deleted_at IS NULL", id)))
+		if err != nil {
+			return err
+		}
+		if ok {
+			if err := RedirectsWith(tx).Create(ctx, RedirectKindUser, oldUsername, update.Username, "username changed"); err != nil {
+				return err
+			}
+		}
+
 		// Ensure new username is available in shared users+orgs namespace.
 		if err := tx.Exec(ctx, sqlf.Sprintf("UPDATE names SET name=%s WHERE user_id=%s", update.Username, id)); err != nil {
 			var e *pgconn.PgError
@@ -490,11 +501,23 @@ func (u *UserStore) Update(ctx context.Context, id int32, update UserUpdate) (er
 	return nil
 }
 
-// Delete performs a soft-delete of the user and all resources associated with this user.
-func (u *UserStore) Delete(ctx context.Context, id int32) (err error) {
+// Delete performs a soft-delete of the user and all resources associated with this user. The
+// user is permanently deleted once the grace period of the auth.userDeletionGracePeriodDays site
+// configuration has passed (see PurgeDeleted).
+func (u *UserStore) Delete(ctx context.Context, id int32) error {
 	if Mocks.Users.Delete != nil {
 		return Mocks.Users.Delete(ctx, id)
 	}
+	return u.DeleteWithGracePeriod(ctx, id, conf.AuthUserDeletionGracePeriod())
+}
+
+// DeleteWithGracePeriod performs a soft-delete of the user and all resources associated with this
+// user, like Delete, but the user is permanently deleted once the given grace period has passed. A
+// zero grace period permanently deletes the user the next time deleted users are purged.
+func (u *UserStore) DeleteWithGracePeriod(ctx context.Context, id int32, gracePeriod time.Duration) (err error) {
+	if Mocks.Users.DeleteWithGracePeriod != nil {
+		return Mocks.Users.DeleteWithGracePeriod(ctx, id, gracePeriod)
+	}
 	u.ensureStore()
 
 	tx, err := u.Transact(ctx)
@@ -503,7 +526,11 @@ func (u *UserStore) Delete(ctx context.Context, id int32) (err error) {
 	}
 	defer func() { err = tx.Done(err) }()
 
-	res, err := tx.ExecResult(ctx, sqlf.Sprintf("UPDATE users SET deleted_at=now() WHERE id=%s AND deleted_at IS NULL", id))
+	res, err := tx.ExecResult(ctx, sqlf.Sprintf(
+		"UPDATE users SET deleted_at=now(), purge_after=now() + %s * interval '1 second' WHERE id=%s AND deleted_at IS NULL",
+		int64(gracePeriod/time.Second),
+		id,
+	))
 	if err != nil {
 		return err
 	}
@@ -615,6 +642,41 @@ func (u *UserStore) HardDelete(ctx context.Context, id int32) (err error) {
 	return nil
 }
 
+// ListPurgeable returns the IDs of the deleted users whose grace period has passed, oldest
+// first, up to the given limit.
+func (u *UserStore) ListPurgeable(ctx context.Context, limit int) ([]int32, error) {
+	u.ensureStore()
+
+	return basestore.ScanInt32s(u.Query(ctx, sqlf.Sprintf(listPurgeableUsersQuery, limit)))
+}
+
+const listPurgeableUsersQuery = `
+-- source: internal/database/users.go:ListPurgeable
+SELECT id FROM users
+WHERE deleted_at IS NOT NULL AND purge_after <= now()
+ORDER BY purge_after
+LIMIT %s
+`
+
+// PurgeDeleted permanently deletes the deleted users whose grace period has passed, along with
+// the resources they own, up to the given number of users. External services and batch changes
+// in the namespace of the users are deleted with them. It returns the IDs of the purged users.
+func (u *UserStore) PurgeDeleted(ctx context.Context, limit int) ([]int32, error) {
+	ids, err := u.ListPurgeable(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	purged := make([]int32, 0, len(ids))
+	for _, id := range ids {
+		if err := u.HardDelete(ctx, id); err != nil {
+			return purged, errors.Wrapf(err, "purging user %d", id)
+		}
+		purged = append(purged, id)
+	}
+	return purged, nil
+}
+
 func logUserDeletionEvent(ctx context.Context, db dbutil.DB, id int32, name SecurityEventName) {
 	// The actor deleting the user could be a different user, for example a site
 	// admin
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/sourcegraph/sourcegraph/internal/types"
 )
@@ -11,6 +12,7 @@ type MockUsers struct {
 	Create                       func(ctx context.Context, info NewUser) (newUser *types.User, err error)
 	Update                       func(userID int32, update UserUpdate) error
 	Delete                       func(ctx context.Context, id int32) error
+	DeleteWithGracePeriod        func(ctx context.Context, id int32, gracePeriod time.Duration) error
 	HardDelete                   func(ctx context.Context, id int32) error
 	SetIsSiteAdmin               func(id int32, isSiteAdmin bool) error
 	CheckAndDecrementInviteQuota func(ctx context.Context, userID int32) (bool, error)
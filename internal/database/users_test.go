@@ -11,10 +11,12 @@ import (
 
 	"github.com/cockroachdb/errors"
 	"github.com/google/go-cmp/cmp"
+	"github.com/keegancsmith/sqlf"
 
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
 	"github.com/sourcegraph/sourcegraph/internal/database/globalstatedb"
@@ -731,6 +733,51 @@ func TestUsers_Delete(t *testing.T) {
 	}
 }
 
+func TestUsers_PurgeDeleted(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	ctx := actor.WithInternalActor(context.Background())
+
+	newUser := func(username string) int32 {
+		user, err := Users(db).Create(ctx, NewUser{Username: username})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return user.ID
+	}
+	active, inGracePeriod, expired, legacy := newUser("active"), newUser("grace"), newUser("expired"), newUser("legacy")
+
+	if err := Users(db).DeleteWithGracePeriod(ctx, inGracePeriod, 24*time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := Users(db).DeleteWithGracePeriod(ctx, expired, 0); err != nil {
+		t.Fatal(err)
+	}
+	// Users deleted before the grace period was introduced have no purge_after and are kept.
+	if err := Users(db).Exec(ctx, sqlf.Sprintf("UPDATE users SET deleted_at = now() WHERE id = %s", legacy)); err != nil {
+		t.Fatal(err)
+	}
+
+	purged, err := Users(db).PurgeDeleted(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]int32{expired}, purged); diff != "" {
+		t.Fatalf("unexpected purged users (-want +got):\n%s", diff)
+	}
+
+	remaining, err := basestore.ScanInt32s(db.QueryContext(ctx, "SELECT id FROM users ORDER BY id"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]int32{active, inGracePeriod, legacy}, remaining); diff != "" {
+		t.Fatalf("unexpected remaining users (-want +got):\n%s", diff)
+	}
+}
+
 func TestUsers_HasTag(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
@@ -0,0 +1,216 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// WebhookLog is a webhook received from a code host, as handled by the webhook
+// router.
+type WebhookLog struct {
+	ID         int64
+	ReceivedAt time.Time
+	// ExternalServiceID is the ID of the external service the webhook was received
+	// for, or zero if it couldn't be identified.
+	ExternalServiceID int64
+	// Kind is the kind of the external service, e.g. GITHUB.
+	Kind       string
+	StatusCode int
+	Request    WebhookLogMessage
+	Response   WebhookLogMessage
+	// ReplayOf is the ID of the webhook log this webhook was replayed from, or zero.
+	ReplayOf int64
+}
+
+// WebhookLogMessage is the request or response of a logged webhook. Method and URL
+// are only set on requests.
+type WebhookLogMessage struct {
+	Method string      `json:"method,omitempty"`
+	URL    string      `json:"url,omitempty"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+// WebhookLogsListOptions contains options for listing webhook logs.
+type WebhookLogsListOptions struct {
+	ExternalServiceID int64 // only list the webhooks of this external service
+	OnlyErrors        bool  // only list the webhooks whose handler responded with a status code >= 400
+	*LimitOffset
+}
+
+func (o WebhookLogsListOptions) sqlConditions() []*sqlf.Query {
+	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if o.ExternalServiceID != 0 {
+		conds = append(conds, sqlf.Sprintf("external_service_id = %s", o.ExternalServiceID))
+	}
+	if o.OnlyErrors {
+		conds = append(conds, sqlf.Sprintf("status_code >= 400"))
+	}
+	return conds
+}
+
+// ErrWebhookLogNotFound is returned when a webhook log does not exist.
+var ErrWebhookLogNotFound = errors.New("webhook log not found")
+
+// WebhookLogStore stores the webhooks received from code hosts.
+//
+// 🚨 SECURITY: Webhook payloads can contain private code host data. Only site admins
+// may read them.
+type WebhookLogStore struct {
+	*basestore.Store
+}
+
+// WebhookLogs instantiates and returns a new WebhookLogStore.
+func WebhookLogs(db dbutil.DB) *WebhookLogStore {
+	return &WebhookLogStore{Store: basestore.NewWithDB(db, sql.TxOptions{})}
+}
+
+// WebhookLogsWith instantiates and returns a new WebhookLogStore using the other
+// store handle.
+func WebhookLogsWith(other basestore.ShareableStore) *WebhookLogStore {
+	return &WebhookLogStore{Store: basestore.NewWithHandle(other.Handle())}
+}
+
+func (s *WebhookLogStore) With(other basestore.ShareableStore) *WebhookLogStore {
+	return &WebhookLogStore{Store: s.Store.With(other)}
+}
+
+func (s *WebhookLogStore) Transact(ctx context.Context) (*WebhookLogStore, error) {
+	txBase, err := s.Store.Transact(ctx)
+	return &WebhookLogStore{Store: txBase}, err
+}
+
+// Create stores the webhook log and sets its ID and ReceivedAt.
+func (s *WebhookLogStore) Create(ctx context.Context, l *WebhookLog) error {
+	request, err := json.Marshal(l.Request)
+	if err != nil {
+		return errors.Wrap(err, "marshalling request")
+	}
+	response, err := json.Marshal(l.Response)
+	if err != nil {
+		return errors.Wrap(err, "marshalling response")
+	}
+	return s.QueryRow(ctx, sqlf.Sprintf(
+		createWebhookLogQuery,
+		dbutil.NewNullInt64(l.ExternalServiceID),
+		l.Kind,
+		l.StatusCode,
+		request,
+		response,
+		dbutil.NewNullInt64(l.ReplayOf),
+	)).Scan(&l.ID, &l.ReceivedAt)
+}
+
+const createWebhookLogQuery = `
+-- source: internal/database/webhook_logs.go:Create
+INSERT INTO webhook_logs (external_service_id, kind, status_code, request, response, replay_of)
+VALUES (%s, %s, %s, %s, %s, %s)
+RETURNING id, received_at
+`
+
+// GetByID returns the webhook log with the given ID, or ErrWebhookLogNotFound.
+func (s *WebhookLogStore) GetByID(ctx context.Context, id int64) (*WebhookLog, error) {
+	logs, err := scanWebhookLogs(s.Query(ctx, sqlf.Sprintf(listWebhookLogsQuery, sqlf.Sprintf("id = %s", id), (*LimitOffset)(nil).SQL())))
+	if err != nil {
+		return nil, err
+	}
+	if len(logs) == 0 {
+		return nil, ErrWebhookLogNotFound
+	}
+	return logs[0], nil
+}
+
+// List returns the webhook logs matching the options, newest first.
+func (s *WebhookLogStore) List(ctx context.Context, opt WebhookLogsListOptions) ([]*WebhookLog, error) {
+	return scanWebhookLogs(s.Query(ctx, sqlf.Sprintf(
+		listWebhookLogsQuery,
+		sqlf.Join(opt.sqlConditions(), ") AND ("),
+		opt.LimitOffset.SQL(),
+	)))
+}
+
+const listWebhookLogsQuery = `
+-- source: internal/database/webhook_logs.go:List
+SELECT id, received_at, external_service_id, kind, status_code, request, response, replay_of
+FROM webhook_logs
+WHERE (%s)
+ORDER BY received_at DESC, id DESC
+%s
+`
+
+// Count counts the webhook logs matching the options. The limit and offset are
+// ignored.
+func (s *WebhookLogStore) Count(ctx context.Context, opt WebhookLogsListOptions) (int, error) {
+	count, _, err := basestore.ScanFirstInt(s.Query(ctx, sqlf.Sprintf(
+		countWebhookLogsQuery,
+		sqlf.Join(opt.sqlConditions(), ") AND ("),
+	)))
+	return count, err
+}
+
+const countWebhookLogsQuery = `
+-- source: internal/database/webhook_logs.go:Count
+SELECT COUNT(*)
+FROM webhook_logs
+WHERE (%s)
+`
+
+// DeleteStale deletes the webhook logs received before the given time and returns
+// how many were deleted.
+func (s *WebhookLogStore) DeleteStale(ctx context.Context, before time.Time) (int64, error) {
+	res, err := s.ExecResult(ctx, sqlf.Sprintf(deleteStaleWebhookLogsQuery, before))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+const deleteStaleWebhookLogsQuery = `
+-- source: internal/database/webhook_logs.go:DeleteStale
+DELETE FROM webhook_logs
+WHERE received_at < %s
+`
+
+func scanWebhookLogs(rows *sql.Rows, queryErr error) (_ []*WebhookLog, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var logs []*WebhookLog
+	for rows.Next() {
+		var (
+			l                 WebhookLog
+			request, response []byte
+		)
+		if err := rows.Scan(
+			&l.ID,
+			&l.ReceivedAt,
+			&dbutil.NullInt64{N: &l.ExternalServiceID},
+			&l.Kind,
+			&l.StatusCode,
+			&request,
+			&response,
+			&dbutil.NullInt64{N: &l.ReplayOf},
+		); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(request, &l.Request); err != nil {
+			return nil, errors.Wrap(err, "unmarshalling request")
+		}
+		if err := json.Unmarshal(response, &l.Response); err != nil {
+			return nil, errors.Wrap(err, "unmarshalling response")
+		}
+		logs = append(logs, &l)
+	}
+	return logs, nil
+}
@@ -0,0 +1,84 @@
+package database
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+)
+
+func TestWebhookLogs(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	ctx := context.Background()
+	store := WebhookLogs(db)
+
+	ok := &WebhookLog{
+		Kind:       extsvc.KindGitHub,
+		StatusCode: http.StatusOK,
+		Request: WebhookLogMessage{
+			Method: "POST",
+			URL:    "/.api/github-webhooks",
+			Header: http.Header{"X-Github-Event": {"push"}},
+			Body:   []byte(`{"ref":"refs/heads/main"}`),
+		},
+		Response: WebhookLogMessage{Header: http.Header{}, Body: []byte{}},
+	}
+	failed := &WebhookLog{
+		Kind:       extsvc.KindGitLab,
+		StatusCode: http.StatusInternalServerError,
+		Request:    WebhookLogMessage{Method: "POST", URL: "/.api/gitlab-webhooks", Header: http.Header{}, Body: []byte("{}")},
+		Response:   WebhookLogMessage{Header: http.Header{}, Body: []byte("boom")},
+	}
+	for _, l := range []*WebhookLog{ok, failed} {
+		if err := store.Create(ctx, l); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("GetByID", func(t *testing.T) {
+		have, err := store.GetByID(ctx, ok.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(ok, have); diff != "" {
+			t.Errorf("unexpected webhook log (-want +have):\n%s", diff)
+		}
+		if _, err := store.GetByID(ctx, failed.ID+1); err != ErrWebhookLogNotFound {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		logs, err := store.List(ctx, WebhookLogsListOptions{OnlyErrors: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(logs) != 1 || logs[0].ID != failed.ID {
+			t.Errorf("expected only the failed webhook, have %+v", logs)
+		}
+		if count, err := store.Count(ctx, WebhookLogsListOptions{}); err != nil {
+			t.Fatal(err)
+		} else if count != 2 {
+			t.Errorf("unexpected count: have %d, want 2", count)
+		}
+	})
+
+	t.Run("DeleteStale", func(t *testing.T) {
+		deleted, err := store.DeleteStale(ctx, time.Now().Add(time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if deleted != 2 {
+			t.Errorf("unexpected number of deleted webhook logs: have %d, want 2", deleted)
+		}
+	})
+}
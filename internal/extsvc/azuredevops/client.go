@@ -0,0 +1,437 @@
+// Package azuredevops implements a client for the Azure DevOps Services REST API.
+package azuredevops
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+	"github.com/opentracing-contrib/go-stdlib/nethttp"
+	"golang.org/x/time/rate"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
+)
+
+var requestCounter = metrics.NewRequestMeter("azuredevops_requests_count", "Total number of requests sent to the Azure DevOps API.")
+
+// These fields define the self-imposed Azure DevOps rate limit. Azure DevOps throttles clients
+// based on the resources their requests consume rather than on a fixed number of requests, so we
+// stay well below what a single user is typically allowed.
+//
+// See https://godoc.org/golang.org/x/time/rate#Limiter for an explanation of these fields.
+const (
+	rateLimitRequestsPerSecond = 2 // 120/min or 7200/hr
+	RateLimitMaxBurstRequests  = 500
+)
+
+// apiVersion is the version of the REST API requested by the client.
+const apiVersion = "6.0"
+
+var (
+	// ErrPullRequestAlreadyExists is returned when trying to create a pull request
+	// for a source and target branch that already have an active pull request.
+	ErrPullRequestAlreadyExists = errors.New("an active pull request for the source and target branch already exists")
+
+	// ErrPullRequestNotFound is returned when no pull request could be found.
+	ErrPullRequestNotFound = errors.New("pull request not found")
+
+	// ErrNotMergeable is returned when a pull request could not be completed.
+	ErrNotMergeable = errors.New("pull request is not mergeable")
+)
+
+// Client accesses Azure DevOps Services via the REST API.
+type Client struct {
+	// HTTP Client used to communicate with the API
+	httpClient httpcli.Doer
+
+	// URL is the base URL of Azure DevOps Services (https://dev.azure.com/).
+	URL *url.URL
+
+	// Auth is the authenticator used to authenticate API requests, usually
+	// basic authentication with a personal access token as the password.
+	Auth auth.Authenticator
+
+	// RateLimit is the self-imposed rate limiter.
+	RateLimit *rate.Limiter
+}
+
+// NewClient creates a new Azure DevOps API client for the given base URL. If a
+// nil httpClient is provided, httpcli.ExternalDoer will be used.
+func NewClient(baseURL *url.URL, a auth.Authenticator, httpClient httpcli.Doer) *Client {
+	if httpClient == nil {
+		httpClient = httpcli.ExternalDoer
+	}
+
+	httpClient = requestCounter.Doer(httpClient, func(u *url.URL) string {
+		// Paths have the form /{org}/{project}/_apis/{area}/... or
+		// /{org}/_apis/{area}/..., and the area maps to the type of API
+		// request we are making.
+		parts := strings.Split(u.Path, "/")
+		for i, p := range parts {
+			if p == "_apis" && i+1 < len(parts) {
+				return parts[i+1]
+			}
+		}
+		return ""
+	})
+
+	// Normally our registry will return a default infinite limiter when nothing has been
+	// synced from config. However, we always want to ensure there is at least some form of rate
+	// limiting for Azure DevOps.
+	defaultLimiter := rate.NewLimiter(rateLimitRequestsPerSecond, RateLimitMaxBurstRequests)
+	l := ratelimit.DefaultRegistry.GetOrSet(baseURL.String(), defaultLimiter)
+
+	return &Client{
+		httpClient: httpClient,
+		URL:        baseURL,
+		Auth:       a,
+		RateLimit:  l,
+	}
+}
+
+// WithAuthenticator returns a copy of the client that authenticates requests
+// with the given authenticator.
+func (c *Client) WithAuthenticator(a auth.Authenticator) *Client {
+	c2 := *c
+	c2.Auth = a
+	return &c2
+}
+
+// OrgRepos returns all repositories in all projects of the given organization.
+func (c *Client) OrgRepos(ctx context.Context, org string) ([]*Repository, error) {
+	return c.listRepos(ctx, org, "/"+org+"/_apis/git/repositories")
+}
+
+// ProjectRepos returns all repositories of the given project.
+func (c *Client) ProjectRepos(ctx context.Context, org, project string) ([]*Repository, error) {
+	return c.listRepos(ctx, org, "/"+org+"/"+project+"/_apis/git/repositories")
+}
+
+func (c *Client) listRepos(ctx context.Context, org, path string) ([]*Repository, error) {
+	req, err := c.newRequest("GET", path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Value []*Repository `json:"value"`
+	}
+	if err := c.do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	for _, r := range resp.Value {
+		r.Org = org
+	}
+	return resp.Value, nil
+}
+
+// AuthenticatedUser returns the user the client is authenticated as, in the
+// context of the given organization.
+func (c *Client) AuthenticatedUser(ctx context.Context, org string) (*Identity, error) {
+	// connectionData is only available as a preview API.
+	qry := url.Values{"api-version": []string{apiVersion + "-preview"}}
+	req, err := c.newRequest("GET", "/"+org+"/_apis/connectionData", qry, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		AuthenticatedUser Identity `json:"authenticatedUser"`
+	}
+	if err := c.do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.AuthenticatedUser, nil
+}
+
+// CreatePullRequestInput contains the fields of a new pull request.
+type CreatePullRequestInput struct {
+	SourceRefName string `json:"sourceRefName"`
+	TargetRefName string `json:"targetRefName"`
+	Title         string `json:"title"`
+	Description   string `json:"description"`
+	IsDraft       bool   `json:"isDraft"`
+}
+
+// CreatePullRequest creates a pull request in the given repository. If an
+// active pull request for the same source and target branch already exists,
+// ErrPullRequestAlreadyExists is returned.
+func (c *Client) CreatePullRequest(ctx context.Context, repo *Repository, input *CreatePullRequestInput) (*PullRequest, error) {
+	req, err := c.newRequest("POST", pullRequestsPath(repo), nil, input)
+	if err != nil {
+		return nil, err
+	}
+
+	var pr PullRequest
+	if err := c.do(ctx, req, &pr); err != nil {
+		var e *httpError
+		if errors.As(err, &e) && e.StatusCode == http.StatusConflict && bytes.Contains(e.Body, []byte("TF401179")) {
+			return nil, ErrPullRequestAlreadyExists
+		}
+		return nil, err
+	}
+	return repo.withPullRequest(&pr), nil
+}
+
+// ActivePullRequestByRefs returns the active pull request from the given
+// source ref to the given target ref. If there is none,
+// ErrPullRequestNotFound is returned.
+func (c *Client) ActivePullRequestByRefs(ctx context.Context, repo *Repository, sourceRefName, targetRefName string) (*PullRequest, error) {
+	qry := url.Values{
+		"searchCriteria.sourceRefName": []string{sourceRefName},
+		"searchCriteria.targetRefName": []string{targetRefName},
+		"searchCriteria.status":        []string{string(PullRequestStatusActive)},
+	}
+	req, err := c.newRequest("GET", pullRequestsPath(repo), qry, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Value []*PullRequest `json:"value"`
+	}
+	if err := c.do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Value) == 0 {
+		return nil, ErrPullRequestNotFound
+	}
+	return repo.withPullRequest(resp.Value[0]), nil
+}
+
+// PullRequest returns the pull request with the given ID. If it doesn't
+// exist, ErrPullRequestNotFound is returned.
+func (c *Client) PullRequest(ctx context.Context, repo *Repository, id int64) (*PullRequest, error) {
+	req, err := c.newRequest("GET", pullRequestPath(repo, id), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var pr PullRequest
+	if err := c.do(ctx, req, &pr); err != nil {
+		var e *httpError
+		if errors.As(err, &e) && e.NotFound() {
+			return nil, ErrPullRequestNotFound
+		}
+		return nil, err
+	}
+	return repo.withPullRequest(&pr), nil
+}
+
+// UpdatePullRequestInput contains the fields of a pull request to update.
+// Empty fields are left unchanged.
+type UpdatePullRequestInput struct {
+	Title                 string             `json:"title,omitempty"`
+	Description           string             `json:"description,omitempty"`
+	TargetRefName         string             `json:"targetRefName,omitempty"`
+	Status                PullRequestStatus  `json:"status,omitempty"`
+	IsDraft               *bool              `json:"isDraft,omitempty"`
+	LastMergeSourceCommit *CommitRef         `json:"lastMergeSourceCommit,omitempty"`
+	CompletionOptions     *CompletionOptions `json:"completionOptions,omitempty"`
+}
+
+// CompletionOptions define how a pull request is merged when it is completed.
+type CompletionOptions struct {
+	// MergeStrategy is one of "noFastForward", "squash", "rebase" and
+	// "rebaseMerge".
+	MergeStrategy      string `json:"mergeStrategy,omitempty"`
+	DeleteSourceBranch bool   `json:"deleteSourceBranch"`
+}
+
+// UpdatePullRequest updates the pull request with the given ID.
+func (c *Client) UpdatePullRequest(ctx context.Context, repo *Repository, id int64, input *UpdatePullRequestInput) (*PullRequest, error) {
+	req, err := c.newRequest("PATCH", pullRequestPath(repo, id), nil, input)
+	if err != nil {
+		return nil, err
+	}
+
+	var pr PullRequest
+	if err := c.do(ctx, req, &pr); err != nil {
+		return nil, err
+	}
+	return repo.withPullRequest(&pr), nil
+}
+
+// CompletePullRequest merges the given pull request. If squash is true, the
+// changes are squashed into a single commit. If Azure DevOps refuses to
+// complete the pull request, ErrNotMergeable is returned.
+func (c *Client) CompletePullRequest(ctx context.Context, repo *Repository, pr *PullRequest, squash bool) (*PullRequest, error) {
+	strategy := "noFastForward"
+	if squash {
+		strategy = "squash"
+	}
+
+	updated, err := c.UpdatePullRequest(ctx, repo, pr.ID, &UpdatePullRequestInput{
+		Status:                PullRequestStatusCompleted,
+		LastMergeSourceCommit: pr.LastMergeSourceCommit,
+		CompletionOptions:     &CompletionOptions{MergeStrategy: strategy},
+	})
+	if err != nil {
+		var e *httpError
+		if errors.As(err, &e) && (e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusConflict) {
+			return nil, errors.Wrap(ErrNotMergeable, err.Error())
+		}
+		return nil, err
+	}
+
+	switch updated.MergeStatus {
+	case MergeStatusConflicts, MergeStatusFailure, MergeStatusRejectedByPolicy:
+		return nil, errors.Wrapf(ErrNotMergeable, "merge status %q", updated.MergeStatus)
+	}
+	return updated, nil
+}
+
+// PullRequestStatuses returns the statuses posted to the given pull request.
+func (c *Client) PullRequestStatuses(ctx context.Context, repo *Repository, id int64) ([]*Status, error) {
+	req, err := c.newRequest("GET", pullRequestPath(repo, id)+"/statuses", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Value []*Status `json:"value"`
+	}
+	if err := c.do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Value, nil
+}
+
+// CreatePullRequestComment starts a new comment thread on the given pull
+// request.
+func (c *Client) CreatePullRequestComment(ctx context.Context, repo *Repository, id int64, text string) error {
+	type comment struct {
+		Content     string `json:"content"`
+		CommentType string `json:"commentType"`
+	}
+	thread := struct {
+		Comments []comment `json:"comments"`
+		Status   string    `json:"status"`
+	}{
+		Comments: []comment{{Content: text, CommentType: "text"}},
+		Status:   "active",
+	}
+
+	req, err := c.newRequest("POST", pullRequestPath(repo, id)+"/threads", nil, &thread)
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, req, nil)
+}
+
+func pullRequestsPath(repo *Repository) string {
+	return "/" + repo.Org + "/" + repo.Project.ID + "/_apis/git/repositories/" + repo.ID + "/pullrequests"
+}
+
+func pullRequestPath(repo *Repository, id int64) string {
+	return pullRequestsPath(repo) + "/" + strconv.FormatInt(id, 10)
+}
+
+func (c *Client) newRequest(method, path string, qry url.Values, body interface{}) (*http.Request, error) {
+	if qry == nil {
+		qry = url.Values{}
+	}
+	if qry.Get("api-version") == "" {
+		qry.Set("api-version", apiVersion)
+	}
+
+	var r io.Reader
+	if body != nil {
+		bs, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(bs)
+	}
+
+	return http.NewRequest(method, (&url.URL{Path: path, RawQuery: qry.Encode()}).String(), r)
+}
+
+func (c *Client) do(ctx context.Context, req *http.Request, result interface{}) error {
+	req.URL = c.URL.ResolveReference(req.URL)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if c.Auth != nil {
+		if err := c.Auth.Authenticate(req); err != nil {
+			return errors.Wrap(err, "authenticating request")
+		}
+	}
+
+	req, ht := nethttp.TraceRequest(ot.GetTracer(ctx),
+		req.WithContext(ctx),
+		nethttp.OperationName("Azure DevOps"),
+		nethttp.ClientTrace(false))
+	defer ht.Finish()
+
+	startWait := time.Now()
+	if err := c.RateLimit.Wait(ctx); err != nil {
+		return err
+	}
+
+	if d := time.Since(startWait); d > 200*time.Millisecond {
+		log15.Warn("Azure DevOps self-enforced API rate limit: request delayed longer than expected due to rate limit", "delay", d)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	// Azure DevOps responds to requests with invalid credentials with a sign
+	// in page and a 203 status code instead of a 401.
+	code := resp.StatusCode
+	if code == http.StatusNonAuthoritativeInfo {
+		code = http.StatusUnauthorized
+	}
+	if code < 200 || code >= 300 {
+		return errors.WithStack(&httpError{
+			URL:        req.URL,
+			StatusCode: code,
+			Body:       bs,
+		})
+	}
+
+	if result != nil {
+		return json.Unmarshal(bs, result)
+	}
+
+	return nil
+}
+
+type httpError struct {
+	StatusCode int
+	URL        *url.URL
+	Body       []byte
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("Azure DevOps API HTTP error: code=%d url=%q body=%q", e.StatusCode, e.URL, e.Body)
+}
+
+func (e *httpError) Unauthorized() bool {
+	return e.StatusCode == http.StatusUnauthorized
+}
+
+func (e *httpError) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
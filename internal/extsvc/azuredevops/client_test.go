@@ -0,0 +1,153 @@
+package azuredevops
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/time/rate"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+)
+
+func newTestClient(t *testing.T, h http.Handler) *Client {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewClient(u, &auth.BasicAuth{Username: "alice", Password: "secret"}, nil)
+	c.RateLimit = rate.NewLimiter(rate.Inf, 1)
+	return c
+}
+
+var testRepo = &Repository{ID: "repo-id", Name: "myrepo", Project: Project{ID: "project-id", Name: "myproject"}, Org: "myorg"}
+
+func TestClient_ProjectRepos(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/myorg/my project/_apis/git/repositories" {
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.URL.Query().Get("api-version"); got != apiVersion {
+			t.Errorf("got api-version %q", got)
+		}
+		if user, pass, ok := r.BasicAuth(); !ok || user != "alice" || pass != "secret" {
+			t.Errorf("got basic auth %q:%q", user, pass)
+		}
+		_, _ = w.Write([]byte(`{"count": 1, "value": [{"id": "repo-id", "name": "myrepo", "project": {"id": "project-id", "name": "my project"}}]}`))
+	}))
+
+	repos, err := c.ProjectRepos(context.Background(), "myorg", "my project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Repository{{ID: "repo-id", Name: "myrepo", Project: Project{ID: "project-id", Name: "my project"}, Org: "myorg"}}
+	if diff := cmp.Diff(want, repos); diff != "" {
+		t.Fatalf("unexpected repos (-want +got):\n%s", diff)
+	}
+	if have, want := repos[0].NameWithOwner(), "myorg/my project/myrepo"; have != want {
+		t.Errorf("got name %q, want %q", have, want)
+	}
+}
+
+func TestClient_CreatePullRequest(t *testing.T) {
+	t.Run("created", func(t *testing.T) {
+		c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != "POST" || r.URL.Path != "/myorg/project-id/_apis/git/repositories/repo-id/pullrequests" {
+				http.NotFound(w, r)
+				return
+			}
+			var input CreatePullRequestInput
+			if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+				t.Fatal(err)
+			}
+			_ = json.NewEncoder(w).Encode(&PullRequest{ID: 7, Title: input.Title, IsDraft: input.IsDraft, SourceRefName: input.SourceRefName})
+		}))
+
+		pr, err := c.CreatePullRequest(context.Background(), testRepo, &CreatePullRequestInput{
+			SourceRefName: "refs/heads/feature",
+			TargetRefName: "refs/heads/main",
+			Title:         "Title",
+			IsDraft:       true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pr.ID != 7 || pr.Title != "Title" || !pr.IsDraft || pr.Repository.Org != "myorg" {
+			t.Errorf("unexpected pull request %+v", pr)
+		}
+	})
+
+	t.Run("already exists", func(t *testing.T) {
+		c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"message": "TF401179: An active pull request for the source and target branch already exists."}`))
+		}))
+
+		_, err := c.CreatePullRequest(context.Background(), testRepo, &CreatePullRequestInput{})
+		if err != ErrPullRequestAlreadyExists {
+			t.Fatalf("got error %v, want %v", err, ErrPullRequestAlreadyExists)
+		}
+	})
+}
+
+func TestClient_PullRequest_NotFound(t *testing.T) {
+	c := newTestClient(t, http.NotFoundHandler())
+
+	_, err := c.PullRequest(context.Background(), testRepo, 7)
+	if err != ErrPullRequestNotFound {
+		t.Fatalf("got error %v, want %v", err, ErrPullRequestNotFound)
+	}
+}
+
+func TestClient_CompletePullRequest(t *testing.T) {
+	for name, tc := range map[string]struct {
+		mergeStatus MergeStatus
+		wantErr     error
+	}{
+		"succeeded": {mergeStatus: MergeStatusSucceeded},
+		"queued":    {mergeStatus: MergeStatusQueued},
+		"conflicts": {mergeStatus: MergeStatusConflicts, wantErr: ErrNotMergeable},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var input UpdatePullRequestInput
+				if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+					t.Fatal(err)
+				}
+				if input.Status != PullRequestStatusCompleted || input.CompletionOptions.MergeStrategy != "squash" {
+					t.Errorf("unexpected input %+v", input)
+				}
+				_ = json.NewEncoder(w).Encode(&PullRequest{ID: 7, Status: PullRequestStatusCompleted, MergeStatus: tc.mergeStatus})
+			}))
+
+			_, err := c.CompletePullRequest(context.Background(), testRepo, &PullRequest{ID: 7}, true)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_Unauthorized(t *testing.T) {
+	// Azure DevOps responds with a sign in page for invalid credentials.
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNonAuthoritativeInfo)
+		_, _ = w.Write([]byte(`<html></html>`))
+	}))
+
+	_, err := c.AuthenticatedUser(context.Background(), "myorg")
+	var e *httpError
+	if !errors.As(err, &e) || !e.Unauthorized() {
+		t.Fatalf("got error %v, want unauthorized", err)
+	}
+}
@@ -0,0 +1,110 @@
+package azuredevops
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// ParseWebhookEvent parses the payload of an Azure DevOps service hook
+// request into one of the event types defined in this package.
+//
+// Azure DevOps sends the complete pull request with every pull request event,
+// but doesn't tell us which of its fields changed. We therefore classify
+// events by the status of the pull request they carry: completed pull requests
+// yield a PullRequestMergedEvent, abandoned ones a PullRequestAbandonedEvent,
+// and active ones a PullRequestUpdatedEvent.
+func ParseWebhookEvent(payload []byte) (interface{}, error) {
+	var header struct {
+		EventType string `json:"eventType"`
+	}
+	if err := json.Unmarshal(payload, &header); err != nil {
+		return nil, err
+	}
+
+	switch header.EventType {
+	case "git.pullrequest.created", "git.pullrequest.updated", "git.pullrequest.merged":
+		var e PullRequestEvent
+		if err := json.Unmarshal(payload, &e); err != nil {
+			return nil, err
+		}
+
+		switch e.PullRequest.Status {
+		case PullRequestStatusCompleted:
+			return &PullRequestMergedEvent{e}, nil
+		case PullRequestStatusAbandoned:
+			return &PullRequestAbandonedEvent{e}, nil
+		default:
+			return &PullRequestUpdatedEvent{e}, nil
+		}
+
+	case "ms.vss-code.git-pullrequest-comment-event":
+		var e PullRequestCommentEvent
+		if err := json.Unmarshal(payload, &e); err != nil {
+			return nil, err
+		}
+		return &e, nil
+
+	default:
+		return nil, errors.Errorf("unknown webhook event type: %q", header.EventType)
+	}
+}
+
+// PullRequestEvent contains the fields common to all pull request service
+// hook payloads.
+type PullRequestEvent struct {
+	// ID is the unique ID of the event, which Azure DevOps retains when
+	// retrying deliveries.
+	ID          string      `json:"id"`
+	EventType   string      `json:"eventType"`
+	CreatedDate time.Time   `json:"createdDate"`
+	PullRequest PullRequest `json:"resource"`
+}
+
+// PullRequestUpdatedEvent is sent when an active pull request is created or
+// updated, for example when reviewers vote or new commits are pushed.
+type PullRequestUpdatedEvent struct {
+	PullRequestEvent
+}
+
+func (e *PullRequestUpdatedEvent) Key() string {
+	return fmt.Sprintf("updated:%s", e.ID)
+}
+
+// PullRequestMergedEvent is sent when a pull request has been completed.
+type PullRequestMergedEvent struct {
+	PullRequestEvent
+}
+
+func (e *PullRequestMergedEvent) Key() string {
+	// Completing a pull request results in both an update and a merge event,
+	// which we only want to record once.
+	return fmt.Sprintf("merged:%d", e.PullRequest.ID)
+}
+
+// PullRequestAbandonedEvent is sent when a pull request has been abandoned.
+type PullRequestAbandonedEvent struct {
+	PullRequestEvent
+}
+
+func (e *PullRequestAbandonedEvent) Key() string {
+	return fmt.Sprintf("abandoned:%s", e.ID)
+}
+
+// PullRequestCommentEvent is sent when a comment on a pull request has been
+// created or edited.
+type PullRequestCommentEvent struct {
+	ID          string    `json:"id"`
+	EventType   string    `json:"eventType"`
+	CreatedDate time.Time `json:"createdDate"`
+	Resource    struct {
+		Comment     Comment     `json:"comment"`
+		PullRequest PullRequest `json:"pullRequest"`
+	} `json:"resource"`
+}
+
+func (e *PullRequestCommentEvent) Key() string {
+	return fmt.Sprintf("commented:%d:%d", e.Resource.PullRequest.ID, e.Resource.Comment.ID)
+}
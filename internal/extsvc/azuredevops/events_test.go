@@ -0,0 +1,73 @@
+package azuredevops
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseWebhookEvent(t *testing.T) {
+	for name, tc := range map[string]struct {
+		payload string
+		want    interface{}
+		wantErr bool
+	}{
+		"created": {
+			payload: `{"id": "ev", "eventType": "git.pullrequest.created", "resource": {"pullRequestId": 7, "status": "active"}}`,
+			want: &PullRequestUpdatedEvent{PullRequestEvent{
+				ID: "ev", EventType: "git.pullrequest.created", PullRequest: PullRequest{ID: 7, Status: PullRequestStatusActive},
+			}},
+		},
+		"abandoned": {
+			payload: `{"id": "ev", "eventType": "git.pullrequest.updated", "resource": {"pullRequestId": 7, "status": "abandoned"}}`,
+			want: &PullRequestAbandonedEvent{PullRequestEvent{
+				ID: "ev", EventType: "git.pullrequest.updated", PullRequest: PullRequest{ID: 7, Status: PullRequestStatusAbandoned},
+			}},
+		},
+		"merged": {
+			payload: `{"id": "ev", "eventType": "git.pullrequest.merged", "resource": {"pullRequestId": 7, "status": "completed"}}`,
+			want: &PullRequestMergedEvent{PullRequestEvent{
+				ID: "ev", EventType: "git.pullrequest.merged", PullRequest: PullRequest{ID: 7, Status: PullRequestStatusCompleted},
+			}},
+		},
+		"failed merge attempt": {
+			payload: `{"id": "ev", "eventType": "git.pullrequest.merged", "resource": {"pullRequestId": 7, "status": "active", "mergeStatus": "conflicts"}}`,
+			want: &PullRequestUpdatedEvent{PullRequestEvent{
+				ID: "ev", EventType: "git.pullrequest.merged", PullRequest: PullRequest{ID: 7, Status: PullRequestStatusActive, MergeStatus: MergeStatusConflicts},
+			}},
+		},
+		"commented": {
+			payload: `{"id": "ev", "eventType": "ms.vss-code.git-pullrequest-comment-event", "resource": {"comment": {"id": 2, "content": "LGTM"}, "pullRequest": {"pullRequestId": 7}}}`,
+			want: func() interface{} {
+				e := &PullRequestCommentEvent{ID: "ev", EventType: "ms.vss-code.git-pullrequest-comment-event"}
+				e.Resource.Comment = Comment{ID: 2, Content: "LGTM"}
+				e.Resource.PullRequest = PullRequest{ID: 7}
+				return e
+			}(),
+		},
+		"unknown event type": {
+			payload: `{"eventType": "git.push"}`,
+			wantErr: true,
+		},
+		"invalid payload": {
+			payload: `{`,
+			wantErr: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			have, err := ParseWebhookEvent([]byte(tc.payload))
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("unexpected nil error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, have); diff != "" {
+				t.Errorf("unexpected event (-want +have):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package azuredevops
+
+import (
+	"strconv"
+	"time"
+)
+
+// Repository is an Azure DevOps Git repository.
+type Repository struct {
+	ID            string  `json:"id"`
+	Name          string  `json:"name"`
+	Project       Project `json:"project"`
+	DefaultBranch string  `json:"defaultBranch"`
+	Size          int64   `json:"size"`
+	RemoteURL     string  `json:"remoteUrl"`
+	SSHURL        string  `json:"sshUrl"`
+	WebURL        string  `json:"webUrl"`
+	IsDisabled    bool    `json:"isDisabled"`
+	IsFork        bool    `json:"isFork"`
+
+	// Org is the name of the organization the repository belongs to. It is
+	// not part of the API response, but set by the Client, since it's needed
+	// to address the repository in later requests.
+	Org string `json:"org"`
+}
+
+// NameWithOwner returns the "org/project/repo" name of the repository.
+func (r *Repository) NameWithOwner() string {
+	return r.Org + "/" + r.Project.Name + "/" + r.Name
+}
+
+// withPullRequest sets the repository of the given pull request to r. The
+// repository embedded in pull request responses lacks the organization and
+// web URL, which we already know.
+func (r *Repository) withPullRequest(pr *PullRequest) *PullRequest {
+	pr.Repository = *r
+	return pr
+}
+
+// Project is an Azure DevOps project.
+type Project struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Visibility string `json:"visibility"`
+}
+
+// Identity is an Azure DevOps user or group.
+type Identity struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName"`
+	// UniqueName is the sign in name of the user, which is usually their
+	// email address on Azure DevOps Services.
+	UniqueName string `json:"uniqueName"`
+}
+
+// PullRequestStatus is the status of a pull request.
+type PullRequestStatus string
+
+const (
+	PullRequestStatusActive    PullRequestStatus = "active"
+	PullRequestStatusAbandoned PullRequestStatus = "abandoned"
+	PullRequestStatusCompleted PullRequestStatus = "completed"
+)
+
+// MergeStatus is the status of the most recent merge attempt of a pull
+// request.
+type MergeStatus string
+
+const (
+	MergeStatusConflicts        MergeStatus = "conflicts"
+	MergeStatusFailure          MergeStatus = "failure"
+	MergeStatusQueued           MergeStatus = "queued"
+	MergeStatusRejectedByPolicy MergeStatus = "rejectedByPolicy"
+	MergeStatusSucceeded        MergeStatus = "succeeded"
+)
+
+// CommitRef references a commit.
+type CommitRef struct {
+	CommitID string `json:"commitId"`
+}
+
+// Vote values of pull request reviewers.
+const (
+	VoteApproved                = 10
+	VoteApprovedWithSuggestions = 5
+	VoteNone                    = 0
+	VoteWaitingForAuthor        = -5
+	VoteRejected                = -10
+)
+
+// Reviewer is a reviewer of a pull request and their vote.
+type Reviewer struct {
+	Identity
+	Vote       int  `json:"vote"`
+	IsRequired bool `json:"isRequired"`
+}
+
+// PullRequest is an Azure DevOps pull request.
+type PullRequest struct {
+	ID                    int64             `json:"pullRequestId"`
+	Repository            Repository        `json:"repository"`
+	Status                PullRequestStatus `json:"status"`
+	CreatedBy             Identity          `json:"createdBy"`
+	CreationDate          time.Time         `json:"creationDate"`
+	ClosedDate            time.Time         `json:"closedDate"`
+	Title                 string            `json:"title"`
+	Description           string            `json:"description"`
+	SourceRefName         string            `json:"sourceRefName"`
+	TargetRefName         string            `json:"targetRefName"`
+	MergeStatus           MergeStatus       `json:"mergeStatus"`
+	IsDraft               bool              `json:"isDraft"`
+	LastMergeSourceCommit *CommitRef        `json:"lastMergeSourceCommit,omitempty"`
+	LastMergeTargetCommit *CommitRef        `json:"lastMergeTargetCommit,omitempty"`
+	Reviewers             []Reviewer        `json:"reviewers"`
+
+	// Statuses are not part of the pull request API response, but loaded
+	// separately when syncing changesets.
+	Statuses []*Status `json:"statuses,omitempty"`
+}
+
+// WebURL returns the URL of the pull request in the Azure DevOps web UI.
+func (pr *PullRequest) WebURL() string {
+	if pr.Repository.WebURL == "" {
+		return ""
+	}
+	return pr.Repository.WebURL + "/pullrequest/" + strconv.FormatInt(pr.ID, 10)
+}
+
+// UpdatedAt returns the last time the pull request was updated, as far as
+// it can be determined: Azure DevOps only records when pull requests are
+// created and closed.
+func (pr *PullRequest) UpdatedAt() time.Time {
+	if pr.ClosedDate.After(pr.CreationDate) {
+		return pr.ClosedDate
+	}
+	return pr.CreationDate
+}
+
+// StatusState is the state of a pull request status.
+type StatusState string
+
+const (
+	StatusStateError         StatusState = "error"
+	StatusStateFailed        StatusState = "failed"
+	StatusStateNotApplicable StatusState = "notApplicable"
+	StatusStateNotSet        StatusState = "notSet"
+	StatusStatePending       StatusState = "pending"
+	StatusStateSucceeded     StatusState = "succeeded"
+)
+
+// Status is a status posted to a pull request, usually by a build.
+type Status struct {
+	ID          int64       `json:"id"`
+	State       StatusState `json:"state"`
+	Description string      `json:"description"`
+	Context     struct {
+		Name  string `json:"name"`
+		Genre string `json:"genre"`
+	} `json:"context"`
+	CreationDate time.Time `json:"creationDate"`
+	UpdatedDate  time.Time `json:"updatedDate"`
+}
+
+// Key identifies the service that posted the status. Services update their
+// state by posting new statuses with the same key.
+func (s *Status) Key() string {
+	return s.Context.Genre + "/" + s.Context.Name
+}
+
+// Comment is a comment in a pull request thread.
+type Comment struct {
+	ID              int64     `json:"id"`
+	Author          Identity  `json:"author"`
+	Content         string    `json:"content"`
+	CommentType     string    `json:"commentType"`
+	PublishedDate   time.Time `json:"publishedDate"`
+	LastUpdatedDate time.Time `json:"lastUpdatedDate"`
+}
@@ -0,0 +1,234 @@
+package bitbucketcloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	eventTypeHeader = "X-Event-Key"
+
+	// SignatureHeader is the header Bitbucket Cloud uses to send the HMAC
+	// signature of the payload when a webhook secret is configured.
+	SignatureHeader = "X-Hub-Signature"
+)
+
+// WebhookEventType returns the event key of the given webhook request.
+func WebhookEventType(r *http.Request) string {
+	return r.Header.Get(eventTypeHeader)
+}
+
+// ParseWebhookEvent parses the payload of a webhook request with the given
+// event key into one of the event types defined in this package.
+func ParseWebhookEvent(eventType string, payload []byte) (e interface{}, err error) {
+	switch eventType {
+	case "pullrequest:created", "pullrequest:updated":
+		e = &PullRequestUpdatedEvent{}
+	case "pullrequest:approved":
+		e = &PullRequestApprovedEvent{}
+	case "pullrequest:unapproved":
+		e = &PullRequestUnapprovedEvent{}
+	case "pullrequest:changes_request_created":
+		e = &PullRequestChangesRequestCreatedEvent{}
+	case "pullrequest:changes_request_removed":
+		e = &PullRequestChangesRequestRemovedEvent{}
+	case "pullrequest:comment_created", "pullrequest:comment_updated":
+		e = &PullRequestCommentEvent{}
+	case "pullrequest:fulfilled":
+		e = &PullRequestFulfilledEvent{}
+	case "pullrequest:rejected":
+		e = &PullRequestRejectedEvent{}
+	default:
+		return nil, errors.Errorf("unknown webhook event type: %q", eventType)
+	}
+
+	return e, json.Unmarshal(payload, e)
+}
+
+// Account is a Bitbucket Cloud user or team.
+type Account struct {
+	AccountID   string `json:"account_id"`
+	DisplayName string `json:"display_name"`
+	Nickname    string `json:"nickname"`
+	UUID        string `json:"uuid"`
+}
+
+// PullRequestState is the state of a pull request.
+type PullRequestState string
+
+const (
+	PullRequestStateOpen       PullRequestState = "OPEN"
+	PullRequestStateMerged     PullRequestState = "MERGED"
+	PullRequestStateDeclined   PullRequestState = "DECLINED"
+	PullRequestStateSuperseded PullRequestState = "SUPERSEDED"
+)
+
+// PullRequestEndpoint is the source or destination of a pull request.
+type PullRequestEndpoint struct {
+	Branch struct {
+		Name string `json:"name"`
+	} `json:"branch"`
+	Commit struct {
+		Hash string `json:"hash"`
+	} `json:"commit"`
+	Repository Repo `json:"repository"`
+}
+
+// PullRequest is a Bitbucket Cloud pull request, as returned by the API and
+// included in webhook payloads.
+type PullRequest struct {
+	ID           int64                    `json:"id"`
+	Title        string                   `json:"title"`
+	Description  string                   `json:"description"`
+	State        PullRequestState         `json:"state"`
+	Author       Account                  `json:"author"`
+	Source       PullRequestEndpoint      `json:"source"`
+	Destination  PullRequestEndpoint      `json:"destination"`
+	Participants []PullRequestParticipant `json:"participants"`
+	Links        struct {
+		HTML Link `json:"html"`
+	} `json:"links"`
+	CreatedOn time.Time `json:"created_on"`
+	UpdatedOn time.Time `json:"updated_on"`
+
+	// Statuses are the commit statuses of the source commit. They are not
+	// part of the pull request API responses and need to be loaded
+	// separately.
+	Statuses []*PullRequestStatus `json:"statuses,omitempty"`
+}
+
+// PullRequestParticipantState is the review state of a participant.
+type PullRequestParticipantState string
+
+const (
+	PullRequestParticipantStateApproved         PullRequestParticipantState = "approved"
+	PullRequestParticipantStateChangesRequested PullRequestParticipantState = "changes_requested"
+)
+
+// PullRequestParticipant is a reviewer of, or another participant in, a pull
+// request.
+type PullRequestParticipant struct {
+	User     Account                     `json:"user"`
+	Role     string                      `json:"role"`
+	Approved bool                        `json:"approved"`
+	State    PullRequestParticipantState `json:"state"`
+}
+
+// PullRequestStatusState is the state of a commit status.
+type PullRequestStatusState string
+
+const (
+	PullRequestStatusStateSuccessful PullRequestStatusState = "SUCCESSFUL"
+	PullRequestStatusStateFailed     PullRequestStatusState = "FAILED"
+	PullRequestStatusStateInProgress PullRequestStatusState = "INPROGRESS"
+	PullRequestStatusStateStopped    PullRequestStatusState = "STOPPED"
+)
+
+// PullRequestStatus is a commit status, such as the result of a build. There
+// is only one status per key, which is updated by the service reporting it.
+type PullRequestStatus struct {
+	Key       string                 `json:"key"`
+	Name      string                 `json:"name"`
+	URL       string                 `json:"url"`
+	State     PullRequestStatusState `json:"state"`
+	CreatedOn time.Time              `json:"created_on"`
+	UpdatedOn time.Time              `json:"updated_on"`
+}
+
+// PullRequestEvent contains the fields common to all pull request webhook
+// payloads.
+type PullRequestEvent struct {
+	Actor       Account     `json:"actor"`
+	PullRequest PullRequest `json:"pullrequest"`
+	Repository  Repo        `json:"repository"`
+}
+
+// Participant records an approval or change request made by a user.
+type Participant struct {
+	Date time.Time `json:"date"`
+	User Account   `json:"user"`
+}
+
+// Comment is a comment on a pull request.
+type Comment struct {
+	ID      int64 `json:"id"`
+	Content struct {
+		Raw string `json:"raw"`
+	} `json:"content"`
+	User      Account   `json:"user"`
+	CreatedOn time.Time `json:"created_on"`
+	UpdatedOn time.Time `json:"updated_on"`
+}
+
+type PullRequestUpdatedEvent struct {
+	PullRequestEvent
+}
+
+func (e *PullRequestUpdatedEvent) Key() string {
+	return fmt.Sprintf("updated:%d:%d", e.PullRequest.ID, e.PullRequest.UpdatedOn.UnixNano())
+}
+
+type PullRequestApprovedEvent struct {
+	PullRequestEvent
+	Approval Participant `json:"approval"`
+}
+
+func (e *PullRequestApprovedEvent) Key() string {
+	return fmt.Sprintf("approved:%s:%d", e.Approval.User.UUID, e.Approval.Date.UnixNano())
+}
+
+type PullRequestUnapprovedEvent struct {
+	PullRequestEvent
+	Approval Participant `json:"approval"`
+}
+
+func (e *PullRequestUnapprovedEvent) Key() string {
+	return fmt.Sprintf("unapproved:%s:%d", e.Approval.User.UUID, e.Approval.Date.UnixNano())
+}
+
+type PullRequestChangesRequestCreatedEvent struct {
+	PullRequestEvent
+	ChangesRequest Participant `json:"changes_request"`
+}
+
+func (e *PullRequestChangesRequestCreatedEvent) Key() string {
+	return fmt.Sprintf("changes_request_created:%s:%d", e.ChangesRequest.User.UUID, e.ChangesRequest.Date.UnixNano())
+}
+
+type PullRequestChangesRequestRemovedEvent struct {
+	PullRequestEvent
+	ChangesRequest Participant `json:"changes_request"`
+}
+
+func (e *PullRequestChangesRequestRemovedEvent) Key() string {
+	return fmt.Sprintf("changes_request_removed:%s:%d", e.ChangesRequest.User.UUID, e.ChangesRequest.Date.UnixNano())
+}
+
+type PullRequestCommentEvent struct {
+	PullRequestEvent
+	Comment Comment `json:"comment"`
+}
+
+func (e *PullRequestCommentEvent) Key() string {
+	return fmt.Sprintf("comment:%d", e.Comment.ID)
+}
+
+type PullRequestFulfilledEvent struct {
+	PullRequestEvent
+}
+
+func (e *PullRequestFulfilledEvent) Key() string {
+	return fmt.Sprintf("fulfilled:%d", e.PullRequest.ID)
+}
+
+type PullRequestRejectedEvent struct {
+	PullRequestEvent
+}
+
+func (e *PullRequestRejectedEvent) Key() string {
+	return fmt.Sprintf("rejected:%d", e.PullRequest.ID)
+}
@@ -0,0 +1,71 @@
+package bitbucketcloud
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseWebhookEvent(t *testing.T) {
+	approvedAt := time.Date(2021, 10, 12, 13, 14, 15, 0, time.UTC)
+
+	for name, tc := range map[string]struct {
+		eventType string
+		payload   string
+		want      interface{}
+		wantErr   bool
+	}{
+		"approved": {
+			eventType: "pullrequest:approved",
+			payload: `{
+				"pullrequest": {"id": 42, "state": "OPEN"},
+				"repository": {"uuid": "{repo}"},
+				"approval": {"date": "2021-10-12T13:14:15Z", "user": {"uuid": "{user}", "nickname": "alice"}}
+			}`,
+			want: &PullRequestApprovedEvent{
+				PullRequestEvent: PullRequestEvent{
+					PullRequest: PullRequest{ID: 42, State: PullRequestStateOpen},
+					Repository:  Repo{UUID: "{repo}"},
+				},
+				Approval: Participant{Date: approvedAt, User: Account{UUID: "{user}", Nickname: "alice"}},
+			},
+		},
+		"fulfilled": {
+			eventType: "pullrequest:fulfilled",
+			payload:   `{"pullrequest": {"id": 42, "state": "MERGED"}, "repository": {"uuid": "{repo}"}}`,
+			want: &PullRequestFulfilledEvent{
+				PullRequestEvent: PullRequestEvent{
+					PullRequest: PullRequest{ID: 42, State: PullRequestStateMerged},
+					Repository:  Repo{UUID: "{repo}"},
+				},
+			},
+		},
+		"unknown event type": {
+			eventType: "repo:push",
+			payload:   `{}`,
+			wantErr:   true,
+		},
+		"invalid payload": {
+			eventType: "pullrequest:rejected",
+			payload:   `{`,
+			wantErr:   true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			have, err := ParseWebhookEvent(tc.eventType, []byte(tc.payload))
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("unexpected nil error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, have); diff != "" {
+				t.Errorf("unexpected event (-want +have):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package bitbucketcloud
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+var (
+	// ErrPullRequestNotFound is returned when no pull request could be found.
+	ErrPullRequestNotFound = errors.New("pull request not found")
+
+	// ErrNotMergeable is returned when a pull request could not be merged.
+	ErrNotMergeable = errors.New("pull request is not mergeable")
+)
+
+// PullRequestInput contains the fields of a pull request to create or update.
+// Empty fields are left unchanged when updating a pull request.
+type PullRequestInput struct {
+	Title             string
+	Description       string
+	SourceBranch      string
+	DestinationBranch string
+}
+
+func (input *PullRequestInput) MarshalJSON() ([]byte, error) {
+	type branch struct {
+		Name string `json:"name"`
+	}
+	type endpoint struct {
+		Branch branch `json:"branch"`
+	}
+
+	var body struct {
+		Title       string    `json:"title,omitempty"`
+		Description string    `json:"description,omitempty"`
+		Source      *endpoint `json:"source,omitempty"`
+		Destination *endpoint `json:"destination,omitempty"`
+	}
+	body.Title = input.Title
+	body.Description = input.Description
+	if input.SourceBranch != "" {
+		body.Source = &endpoint{Branch: branch{Name: input.SourceBranch}}
+	}
+	if input.DestinationBranch != "" {
+		body.Destination = &endpoint{Branch: branch{Name: input.DestinationBranch}}
+	}
+	return json.Marshal(body)
+}
+
+// CreatePullRequest creates a pull request in the given repository.
+func (c *Client) CreatePullRequest(ctx context.Context, repo *Repo, input *PullRequestInput) (*PullRequest, error) {
+	req, err := newJSONRequest("POST", pullRequestsPath(repo), input)
+	if err != nil {
+		return nil, err
+	}
+
+	var pr PullRequest
+	if err := c.do(ctx, req, &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// OpenPullRequestByBranches returns the open pull request from the given
+// source branch to the given destination branch. If there is none,
+// ErrPullRequestNotFound is returned.
+func (c *Client) OpenPullRequestByBranches(ctx context.Context, repo *Repo, sourceBranch, destinationBranch string) (*PullRequest, error) {
+	qry := url.Values{"q": []string{fmt.Sprintf(
+		"source.branch.name = %q AND destination.branch.name = %q AND state = %q",
+		sourceBranch,
+		destinationBranch,
+		PullRequestStateOpen,
+	)}}
+
+	var prs []*PullRequest
+	if _, err := c.page(ctx, pullRequestsPath(repo), qry, nil, &prs); err != nil {
+		return nil, err
+	}
+	if len(prs) == 0 {
+		return nil, ErrPullRequestNotFound
+	}
+	return prs[0], nil
+}
+
+// PullRequest returns the pull request with the given ID. If it doesn't
+// exist, ErrPullRequestNotFound is returned.
+func (c *Client) PullRequest(ctx context.Context, repo *Repo, id int64) (*PullRequest, error) {
+	req, err := http.NewRequest("GET", pullRequestPath(repo, id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var pr PullRequest
+	if err := c.do(ctx, req, &pr); err != nil {
+		var e *httpError
+		if errors.As(err, &e) && e.NotFound() {
+			return nil, ErrPullRequestNotFound
+		}
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// UpdatePullRequest updates the pull request with the given ID.
+func (c *Client) UpdatePullRequest(ctx context.Context, repo *Repo, id int64, input *PullRequestInput) (*PullRequest, error) {
+	req, err := newJSONRequest("PUT", pullRequestPath(repo, id), input)
+	if err != nil {
+		return nil, err
+	}
+
+	var pr PullRequest
+	if err := c.do(ctx, req, &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// DeclinePullRequest declines the pull request with the given ID. Declined
+// pull requests cannot be reopened.
+func (c *Client) DeclinePullRequest(ctx context.Context, repo *Repo, id int64) (*PullRequest, error) {
+	req, err := http.NewRequest("POST", pullRequestPath(repo, id)+"/decline", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var pr PullRequest
+	if err := c.do(ctx, req, &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// MergePullRequest merges the pull request with the given ID. If squash is
+// true, the changes are squashed into a single commit. If Bitbucket Cloud
+// refuses to merge the pull request, ErrNotMergeable is returned.
+func (c *Client) MergePullRequest(ctx context.Context, repo *Repo, id int64, squash bool) (*PullRequest, error) {
+	strategy := "merge_commit"
+	if squash {
+		strategy = "squash"
+	}
+
+	req, err := newJSONRequest("POST", pullRequestPath(repo, id)+"/merge", map[string]string{
+		"merge_strategy": strategy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var pr PullRequest
+	if err := c.do(ctx, req, &pr); err != nil {
+		var e *httpError
+		if errors.As(err, &e) && (e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusConflict) {
+			return nil, errors.Wrap(ErrNotMergeable, err.Error())
+		}
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// CreatePullRequestComment posts a comment on the pull request with the
+// given ID.
+func (c *Client) CreatePullRequestComment(ctx context.Context, repo *Repo, id int64, text string) error {
+	var body struct {
+		Content struct {
+			Raw string `json:"raw"`
+		} `json:"content"`
+	}
+	body.Content.Raw = text
+
+	req, err := newJSONRequest("POST", pullRequestPath(repo, id)+"/comments", body)
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, req, nil)
+}
+
+// PullRequestStatuses returns the commit statuses of the source commit of
+// the pull request with the given ID.
+func (c *Client) PullRequestStatuses(ctx context.Context, repo *Repo, id int64) ([]*PullRequestStatus, error) {
+	var statuses []*PullRequestStatus
+	next, err := c.page(ctx, pullRequestPath(repo, id)+"/statuses", nil, nil, &statuses)
+	for err == nil && next.HasMore() {
+		var page []*PullRequestStatus
+		next, err = c.reqPage(ctx, next.Next, &page)
+		statuses = append(statuses, page...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
+// CurrentUser returns the user authenticated by the credentials of the
+// client.
+func (c *Client) CurrentUser(ctx context.Context) (*Account, error) {
+	req, err := http.NewRequest("GET", "/2.0/user", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var account Account
+	if err := c.do(ctx, req, &account); err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func newJSONRequest(method, path string, body interface{}) (*http.Request, error) {
+	bs, err := json.Marshal(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling request body")
+	}
+	return http.NewRequest(method, path, bytes.NewReader(bs))
+}
+
+func pullRequestsPath(repo *Repo) string {
+	parts := strings.SplitN(repo.FullName, "/", 2)
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return "/2.0/repositories/" + strings.Join(parts, "/") + "/pullrequests"
+}
+
+func pullRequestPath(repo *Repo, id int64) string {
+	return fmt.Sprintf("%s/%d", pullRequestsPath(repo), id)
+}
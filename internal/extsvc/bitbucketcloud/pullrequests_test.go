@@ -0,0 +1,190 @@
+package bitbucketcloud
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/time/rate"
+)
+
+func newPullRequestTestClient(t *testing.T, h http.Handler) *Client {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewClient(u, nil).WithCredentials("alice", "secret")
+	c.RateLimit = rate.NewLimiter(rate.Inf, 1)
+	return c
+}
+
+var testRepo = &Repo{Slug: "my-repo", FullName: "my-workspace/my-repo", UUID: "{repo-uuid}"}
+
+func TestClient_CreatePullRequest(t *testing.T) {
+	c := newPullRequestTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/2.0/repositories/my-workspace/my-repo/pullrequests" {
+			http.NotFound(w, r)
+			return
+		}
+		if user, pass, ok := r.BasicAuth(); !ok || user != "alice" || pass != "secret" {
+			t.Errorf("got basic auth %q:%q", user, pass)
+		}
+
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]interface{}{
+			"title":       "Title",
+			"source":      map[string]interface{}{"branch": map[string]interface{}{"name": "feature"}},
+			"destination": map[string]interface{}{"branch": map[string]interface{}{"name": "main"}},
+		}
+		if diff := cmp.Diff(want, body); diff != "" {
+			t.Errorf("unexpected request body (-want +got):\n%s", diff)
+		}
+
+		_, _ = w.Write([]byte(`{"id": 7, "title": "Title", "state": "OPEN", "source": {"branch": {"name": "feature"}}}`))
+	}))
+
+	pr, err := c.CreatePullRequest(context.Background(), testRepo, &PullRequestInput{
+		Title:             "Title",
+		SourceBranch:      "feature",
+		DestinationBranch: "main",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.ID != 7 || pr.State != PullRequestStateOpen || pr.Source.Branch.Name != "feature" {
+		t.Fatalf("unexpected pull request: %+v", pr)
+	}
+}
+
+func TestClient_OpenPullRequestByBranches(t *testing.T) {
+	c := newPullRequestTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/2.0/repositories/my-workspace/my-repo/pullrequests" {
+			http.NotFound(w, r)
+			return
+		}
+		want := `source.branch.name = "feature" AND destination.branch.name = "main" AND state = "OPEN"`
+		if have := r.URL.Query().Get("q"); have != want {
+			t.Errorf("unexpected query. want=%q, have=%q", want, have)
+		}
+		_, _ = w.Write([]byte(`{"values": [{"id": 3}]}`))
+	}))
+
+	pr, err := c.OpenPullRequestByBranches(context.Background(), testRepo, "feature", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.ID != 3 {
+		t.Fatalf("unexpected pull request: %+v", pr)
+	}
+}
+
+func TestClient_PullRequest(t *testing.T) {
+	c := newPullRequestTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/2.0/repositories/my-workspace/my-repo/pullrequests/1":
+			_, _ = w.Write([]byte(`{
+				"id": 1,
+				"participants": [{"user": {"nickname": "bob"}, "role": "REVIEWER", "approved": false, "state": "changes_requested"}],
+				"links": {"html": {"href": "https://bitbucket.org/my-workspace/my-repo/pull-requests/1"}}
+			}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	pr, err := c.PullRequest(context.Background(), testRepo, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []PullRequestParticipant{{
+		User:  Account{Nickname: "bob"},
+		Role:  "REVIEWER",
+		State: PullRequestParticipantStateChangesRequested,
+	}}
+	if diff := cmp.Diff(want, pr.Participants); diff != "" {
+		t.Errorf("unexpected participants (-want +got):\n%s", diff)
+	}
+	if have, want := pr.Links.HTML.Href, "https://bitbucket.org/my-workspace/my-repo/pull-requests/1"; have != want {
+		t.Errorf("unexpected link. want=%q, have=%q", want, have)
+	}
+
+	if _, err := c.PullRequest(context.Background(), testRepo, 2); err != ErrPullRequestNotFound {
+		t.Fatalf("unexpected error. want=%s, have=%v", ErrPullRequestNotFound, err)
+	}
+}
+
+func TestClient_MergePullRequest(t *testing.T) {
+	c := newPullRequestTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			MergeStrategy string `json:"merge_strategy"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+
+		switch r.URL.Path {
+		case "/2.0/repositories/my-workspace/my-repo/pullrequests/1/merge":
+			if body.MergeStrategy != "squash" {
+				t.Errorf("unexpected merge strategy %q", body.MergeStrategy)
+			}
+			_, _ = w.Write([]byte(`{"id": 1, "state": "MERGED"}`))
+		case "/2.0/repositories/my-workspace/my-repo/pullrequests/2/merge":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"type": "error", "error": {"message": "You can't merge until you resolve all merge conflicts."}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	pr, err := c.MergePullRequest(context.Background(), testRepo, 1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.State != PullRequestStateMerged {
+		t.Fatalf("unexpected state %q", pr.State)
+	}
+
+	if _, err := c.MergePullRequest(context.Background(), testRepo, 2, false); !errors.Is(err, ErrNotMergeable) {
+		t.Fatalf("unexpected error. want=%s, have=%v", ErrNotMergeable, err)
+	}
+}
+
+func TestClient_PullRequestStatuses(t *testing.T) {
+	var srvURL string
+	c := newPullRequestTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/2.0/repositories/my-workspace/my-repo/pullrequests/1/statuses" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("page") == "2" {
+			_, _ = w.Write([]byte(`{"values": [{"key": "lint", "state": "FAILED"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"values": [{"key": "build", "state": "SUCCESSFUL"}], "next": "` + srvURL + `/2.0/repositories/my-workspace/my-repo/pullrequests/1/statuses?page=2"}`))
+	}))
+	srvURL = c.URL.String()
+
+	statuses, err := c.PullRequestStatuses(context.Background(), testRepo, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*PullRequestStatus{
+		{Key: "build", State: PullRequestStatusStateSuccessful},
+		{Key: "lint", State: PullRequestStatusStateFailed},
+	}
+	if diff := cmp.Diff(want, statuses); diff != "" {
+		t.Fatalf("unexpected statuses (-want +got):\n%s", diff)
+	}
+}
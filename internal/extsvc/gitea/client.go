@@ -0,0 +1,262 @@
+// Package gitea implements a client for the Gitea REST API (v1).
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+	"github.com/opentracing-contrib/go-stdlib/nethttp"
+	"golang.org/x/time/rate"
+
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
+)
+
+var requestCounter = metrics.NewRequestMeter("gitea_requests_count", "Total number of requests sent to the Gitea API.")
+
+// These fields define the self-imposed Gitea rate limit (since Gitea does not have a concept of
+// rate limiting in HTTP response headers).
+//
+// See https://godoc.org/golang.org/x/time/rate#Limiter for an explanation of these fields.
+const (
+	rateLimitRequestsPerSecond = 2 // 120/min or 7200/hr
+	RateLimitMaxBurstRequests  = 500
+)
+
+// pageSize is the number of results requested per page of list API calls. Gitea caps it at the
+// instance's MAX_RESPONSE_ITEMS setting, which defaults to 50.
+const pageSize = 50
+
+// Client accesses a Gitea instance via the REST API v1.
+type Client struct {
+	// HTTP Client used to communicate with the API
+	httpClient httpcli.Doer
+
+	// URL is the base URL of the Gitea instance.
+	URL *url.URL
+
+	// Token is the access token used to authenticate API requests.
+	Token string
+
+	// Sudo is the username of the user to impersonate, if any. Impersonation requires the token
+	// to belong to a Gitea site admin.
+	Sudo string
+
+	// RateLimit is the self-imposed rate limiter (since Gitea does not have a concept of rate
+	// limiting in HTTP response headers).
+	RateLimit *rate.Limiter
+}
+
+// NewClient creates a new Gitea API client for the Gitea instance at baseURL, authenticated with
+// the given access token. If a nil httpClient is provided, httpcli.ExternalDoer will be used.
+func NewClient(baseURL *url.URL, token string, httpClient httpcli.Doer) *Client {
+	if httpClient == nil {
+		httpClient = httpcli.ExternalDoer
+	}
+
+	httpClient = requestCounter.Doer(httpClient, func(u *url.URL) string {
+		// The fourth component of the Path (/api/v1/{category}/...) mostly maps to the type of
+		// API request we are making.
+		var category string
+		if parts := strings.SplitN(u.Path, "/", 5); len(parts) > 3 {
+			category = parts[3]
+		}
+		return category
+	})
+
+	// Normally our registry will return a default infinite limiter when nothing has been
+	// synced from config. However, we always want to ensure there is at least some form of rate
+	// limiting for Gitea.
+	defaultLimiter := rate.NewLimiter(rateLimitRequestsPerSecond, RateLimitMaxBurstRequests)
+	l := ratelimit.DefaultRegistry.GetOrSet(baseURL.String(), defaultLimiter)
+
+	return &Client{
+		httpClient: httpClient,
+		URL:        baseURL,
+		Token:      token,
+		RateLimit:  l,
+	}
+}
+
+// WithSudo returns a copy of the client that impersonates the Gitea user with the given username.
+func (c *Client) WithSudo(username string) *Client {
+	c2 := *c
+	c2.Sudo = username
+	return &c2
+}
+
+// OrgRepos returns all repositories of the given organization that are visible to the
+// authenticated user.
+func (c *Client) OrgRepos(ctx context.Context, org string) ([]*Repo, error) {
+	return c.listRepos(ctx, "/api/v1/orgs/"+url.PathEscape(org)+"/repos")
+}
+
+// UserRepos returns all repositories that the authenticated (or impersonated) user has access
+// to.
+func (c *Client) UserRepos(ctx context.Context) ([]*Repo, error) {
+	return c.listRepos(ctx, "/api/v1/user/repos")
+}
+
+// Repo returns the repository with the given owner and name.
+func (c *Client) Repo(ctx context.Context, owner, name string) (*Repo, error) {
+	req, err := http.NewRequest("GET", "/api/v1/repos/"+url.PathEscape(owner)+"/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var repo Repo
+	if err := c.do(ctx, req, &repo); err != nil {
+		return nil, err
+	}
+	return &repo, nil
+}
+
+// User returns the user with the given username.
+func (c *Client) User(ctx context.Context, username string) (*User, error) {
+	return c.user(ctx, "/api/v1/users/"+url.PathEscape(username))
+}
+
+// CurrentUser returns the authenticated (or impersonated) user.
+func (c *Client) CurrentUser(ctx context.Context) (*User, error) {
+	return c.user(ctx, "/api/v1/user")
+}
+
+func (c *Client) user(ctx context.Context, path string) (*User, error) {
+	req, err := http.NewRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var user User
+	if err := c.do(ctx, req, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// listRepos requests all pages of the repository list at the given path.
+func (c *Client) listRepos(ctx context.Context, path string) ([]*Repo, error) {
+	var all []*Repo
+	for page := 1; ; page++ {
+		qry := url.Values{
+			"page":  []string{strconv.Itoa(page)},
+			"limit": []string{strconv.Itoa(pageSize)},
+		}
+		req, err := http.NewRequest("GET", (&url.URL{Path: path, RawQuery: qry.Encode()}).String(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var repos []*Repo
+		if err := c.do(ctx, req, &repos); err != nil {
+			return nil, err
+		}
+		all = append(all, repos...)
+
+		if len(repos) < pageSize {
+			return all, nil
+		}
+	}
+}
+
+func (c *Client) do(ctx context.Context, req *http.Request, result interface{}) error {
+	req.URL = c.URL.ResolveReference(req.URL)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "token "+c.Token)
+	if c.Sudo != "" {
+		req.Header.Set("Sudo", c.Sudo)
+	}
+
+	req, ht := nethttp.TraceRequest(ot.GetTracer(ctx),
+		req.WithContext(ctx),
+		nethttp.OperationName("Gitea"),
+		nethttp.ClientTrace(false))
+	defer ht.Finish()
+
+	startWait := time.Now()
+	if err := c.RateLimit.Wait(ctx); err != nil {
+		return err
+	}
+
+	if d := time.Since(startWait); d > 200*time.Millisecond {
+		log15.Warn("Gitea self-enforced API rate limit: request delayed longer than expected due to rate limit", "delay", d)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return errors.WithStack(&httpError{
+			URL:        req.URL,
+			StatusCode: resp.StatusCode,
+			Body:       bs,
+		})
+	}
+
+	if result != nil {
+		return json.Unmarshal(bs, result)
+	}
+
+	return nil
+}
+
+// Repo is a Gitea repository.
+type Repo struct {
+	ID          int64  `json:"id"`
+	Owner       User   `json:"owner"`
+	Name        string `json:"name"`
+	FullName    string `json:"full_name"`
+	Description string `json:"description"`
+	Empty       bool   `json:"empty"`
+	Private     bool   `json:"private"`
+	Fork        bool   `json:"fork"`
+	Archived    bool   `json:"archived"`
+	HTMLURL     string `json:"html_url"`
+	SSHURL      string `json:"ssh_url"`
+	CloneURL    string `json:"clone_url"`
+}
+
+// User is a Gitea user (or organization, when it is the owner of a repository).
+type User struct {
+	ID      int64  `json:"id"`
+	Login   string `json:"login"`
+	IsAdmin bool   `json:"is_admin"`
+}
+
+type httpError struct {
+	StatusCode int
+	URL        *url.URL
+	Body       []byte
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("Gitea API HTTP error: code=%d url=%q body=%q", e.StatusCode, e.URL, e.Body)
+}
+
+func (e *httpError) Unauthorized() bool {
+	return e.StatusCode == http.StatusUnauthorized
+}
+
+func (e *httpError) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,93 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/time/rate"
+)
+
+func newTestClient(t *testing.T, h http.Handler) *Client {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewClient(u, "secret", nil)
+	c.RateLimit = rate.NewLimiter(rate.Inf, 1)
+	return c
+}
+
+func TestClient_OrgRepos(t *testing.T) {
+	const total = pageSize + 3
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/orgs/myorg/repos" {
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.Header.Get("Authorization"); got != "token secret" {
+			t.Errorf("got Authorization header %q", got)
+		}
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		repos := []*Repo{}
+		for i := (page - 1) * limit; i < page*limit && i < total; i++ {
+			repos = append(repos, &Repo{ID: int64(i + 1), FullName: fmt.Sprintf("myorg/repo%d", i+1)})
+		}
+		_ = json.NewEncoder(w).Encode(repos)
+	}))
+
+	repos, err := c.OrgRepos(context.Background(), "myorg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != total {
+		t.Fatalf("got %d repos, want %d", len(repos), total)
+	}
+	if last := repos[total-1]; last.ID != total {
+		t.Errorf("got last repo ID %d, want %d", last.ID, total)
+	}
+}
+
+func TestClient_WithSudo(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/user" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&User{ID: 2, Login: r.Header.Get("Sudo")})
+	}))
+
+	user, err := c.WithSudo("alice").CurrentUser(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&User{ID: 2, Login: "alice"}, user); diff != "" {
+		t.Fatalf("unexpected user (-want +got):\n%s", diff)
+	}
+	if c.Sudo != "" {
+		t.Errorf("WithSudo modified the original client")
+	}
+}
+
+func TestClient_Repo_NotFound(t *testing.T) {
+	c := newTestClient(t, http.NotFoundHandler())
+
+	_, err := c.Repo(context.Background(), "myorg", "missing")
+	var e *httpError
+	if !errors.As(err, &e) || !e.NotFound() {
+		t.Fatalf("got error %v, want not found", err)
+	}
+}
@@ -0,0 +1,250 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// installationTokenRefreshWindow is how long before its expiry an installation
+// access token is replaced by a new one, so that requests in flight don't fail.
+const installationTokenRefreshWindow = 5 * time.Minute
+
+// appJWTLifetime is the lifetime of the JWTs used to authenticate as a GitHub
+// App. GitHub accepts at most 10 minutes.
+const appJWTLifetime = 9 * time.Minute
+
+// InstallationAuthenticator authenticates requests as an installation of a
+// GitHub App. It creates installation access tokens with a JWT signed by the
+// private key of the app and refreshes them before they expire. Tokens are
+// shared by all InstallationAuthenticators of the same installation.
+type InstallationAuthenticator struct {
+	apiURL         *url.URL
+	appID          int
+	installationID int
+	key            *rsa.PrivateKey
+	hash           string
+	cli            httpcli.Doer
+}
+
+var _ auth.Authenticator = &InstallationAuthenticator{}
+
+// NewInstallationAuthenticator returns an authenticator for the given
+// installation of a GitHub App. apiURL must point to the base URL of the
+// GitHub API, see APIRoot. privateKey is the PEM encoded private key of the
+// app. If cli is nil, httpcli.ExternalDoer is used to request tokens.
+func NewInstallationAuthenticator(apiURL *url.URL, appID, installationID int, privateKey string, cli httpcli.Doer) (*InstallationAuthenticator, error) {
+	key, err := ParseAppPrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if cli == nil {
+		cli = httpcli.ExternalDoer
+	}
+
+	h := sha256.Sum256([]byte(fmt.Sprintf("%s:%d:%d:%s", apiURL, appID, installationID, privateKey)))
+
+	return &InstallationAuthenticator{
+		apiURL:         apiURL,
+		appID:          appID,
+		installationID: installationID,
+		key:            key,
+		hash:           hex.EncodeToString(h[:]),
+		cli:            cli,
+	}, nil
+}
+
+// NewAuthenticatorFromConfig returns the authenticator of the given GitHub
+// connection: an InstallationAuthenticator if a GitHub App is configured, the
+// personal access token otherwise.
+func NewAuthenticatorFromConfig(apiURL *url.URL, c *schema.GitHubConnection, cli httpcli.Doer) (auth.Authenticator, error) {
+	if app := c.GitHubApp; app != nil {
+		return NewInstallationAuthenticator(apiURL, app.AppID, app.InstallationID, app.PrivateKey, cli)
+	}
+	return &auth.OAuthBearerToken{Token: c.Token}, nil
+}
+
+// ParseAppPrivateKey parses the PEM encoded RSA private key of a GitHub App.
+// GitHub issues keys in PKCS #1 form, but PKCS #8 is accepted too.
+func ParseAppPrivateKey(privateKey string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(privateKey))
+	if block == nil {
+		return nil, errors.New("GitHub App private key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing GitHub App private key")
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.Errorf("GitHub App private key is a %T, not an RSA key", parsed)
+	}
+	return key, nil
+}
+
+func (a *InstallationAuthenticator) Authenticate(req *http.Request) error {
+	token, err := a.Token(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+func (a *InstallationAuthenticator) Hash() string {
+	return a.hash
+}
+
+// Token returns an installation access token, creating a new one if there is
+// no cached token or it is about to expire.
+func (a *InstallationAuthenticator) Token(ctx context.Context) (string, error) {
+	t := installationTokens.get(a.hash)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token != "" && time.Until(t.expiresAt) > installationTokenRefreshWindow {
+		return t.token, nil
+	}
+
+	token, expiresAt, err := a.createToken(ctx)
+	if err != nil {
+		return "", errors.Wrapf(err, "creating access token for GitHub App installation %d", a.installationID)
+	}
+	t.token, t.expiresAt = token, expiresAt
+	return token, nil
+}
+
+func (a *InstallationAuthenticator) createToken(ctx context.Context) (string, time.Time, error) {
+	jwt, err := a.appJWT(time.Now())
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	u := *a.apiURL
+	u.Path = path.Join(u.Path, "app/installations", strconv.Itoa(a.installationID), "access_tokens")
+
+	req, err := http.NewRequestWithContext(ctx, "POST", u.String(), nil)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("Authorization", "Bearer "+jwt)
+
+	resp, err := a.cli.Do(req)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<13)) // 8kb
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	if resp.StatusCode != http.StatusCreated {
+		apiErr := APIError{URL: u.String(), Code: resp.StatusCode}
+		if err := json.Unmarshal(body, &apiErr); err != nil {
+			apiErr.Message = fmt.Sprintf("failed to decode error response from GitHub API: %v: %q", err, string(body))
+		}
+		return "", time.Time{}, &apiErr
+	}
+
+	var result struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", time.Time{}, err
+	}
+	return result.Token, result.ExpiresAt, nil
+}
+
+// appJWT returns a JWT authenticating as the GitHub App, signed with RS256 as
+// described in
+// https://docs.github.com/en/developers/apps/building-github-apps/authenticating-with-github-apps#authenticating-as-a-github-app.
+func (a *InstallationAuthenticator) appJWT(now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		// Backdate the token to allow for clock drift.
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(appJWTLifetime).Unix(),
+		"iss": strconv.Itoa(a.appID),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(base64.RawURLEncoding.EncodeToString(header))
+	buf.WriteByte('.')
+	buf.WriteString(base64.RawURLEncoding.EncodeToString(claims))
+
+	digest := sha256.Sum256(buf.Bytes())
+	sig, err := rsa.SignPKCS1v15(rand.Reader, a.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", errors.Wrap(err, "signing GitHub App JWT")
+	}
+
+	buf.WriteByte('.')
+	buf.WriteString(base64.RawURLEncoding.EncodeToString(sig))
+	return buf.String(), nil
+}
+
+// installationTokens caches installation access tokens by the hash of the
+// authenticator that created them, so that sources and clone URLs of the same
+// external service don't create a new token for every use.
+var installationTokens = &installationTokenCache{tokens: map[string]*installationToken{}}
+
+type installationTokenCache struct {
+	mu     sync.Mutex
+	tokens map[string]*installationToken
+}
+
+type installationToken struct {
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+func (c *installationTokenCache) get(hash string) *installationToken {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t, ok := c.tokens[hash]
+	if !ok {
+		t = &installationToken{}
+		c.tokens[hash] = t
+	}
+	return t
+}
@@ -0,0 +1,142 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInstallationAuthenticator(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKey := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+
+	var (
+		created   int
+		expiresIn time.Duration
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/api/v3/app/installations/99/access_tokens" {
+			http.NotFound(w, r)
+			return
+		}
+
+		jwt := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		parts := strings.Split(jwt, ".")
+		if len(parts) != 3 {
+			t.Fatalf("malformed JWT: %q", jwt)
+		}
+		sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+		if err != nil {
+			t.Fatal(err)
+		}
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], sig); err != nil {
+			t.Fatalf("invalid JWT signature: %v", err)
+		}
+		claims, err := base64.RawURLEncoding.DecodeString(parts[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		var c struct {
+			Iss string `json:"iss"`
+		}
+		if err := json.Unmarshal(claims, &c); err != nil {
+			t.Fatal(err)
+		}
+		if c.Iss != "42" {
+			t.Errorf("wrong issuer: %q", c.Iss)
+		}
+
+		created++
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"token":      fmt.Sprintf("token-%d", created),
+			"expires_at": time.Now().Add(expiresIn),
+		})
+	}))
+	defer srv.Close()
+
+	apiURL, _ := url.Parse(srv.URL + "/api/v3")
+	a, err := NewInstallationAuthenticator(apiURL, 42, 99, privateKey, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authenticate := func() string {
+		t.Helper()
+		req, _ := http.NewRequest("GET", srv.URL, nil)
+		if err := a.Authenticate(req); err != nil {
+			t.Fatal(err)
+		}
+		return req.Header.Get("Authorization")
+	}
+
+	expiresIn = time.Hour
+	if have, want := authenticate(), "Bearer token-1"; have != want {
+		t.Errorf("wrong authorization: have=%q want=%q", have, want)
+	}
+	if have, want := authenticate(), "Bearer token-1"; have != want {
+		t.Errorf("token was not reused: have=%q want=%q", have, want)
+	}
+
+	// Tokens of other authenticators for the same installation are shared.
+	other, err := NewInstallationAuthenticator(apiURL, 42, 99, privateKey, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token, err := other.Token(context.Background()); err != nil {
+		t.Fatal(err)
+	} else if token != "token-1" {
+		t.Errorf("token was not shared: %q", token)
+	}
+
+	// Tokens about to expire are refreshed.
+	installationTokens.get(a.Hash()).expiresAt = time.Now().Add(time.Minute)
+	if have, want := authenticate(), "Bearer token-2"; have != want {
+		t.Errorf("token was not refreshed: have=%q want=%q", have, want)
+	}
+}
+
+func TestParseAppPrivateKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tc := range map[string]struct {
+		key     string
+		wantErr bool
+	}{
+		"pkcs1":   {key: string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))},
+		"pkcs8":   {key: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}))},
+		"not pem": {key: "secret", wantErr: true},
+		"garbage": {key: string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: []byte("garbage")})), wantErr: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseAppPrivateKey(tc.key)
+			if have, want := err != nil, tc.wantErr; have != want {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -1870,6 +1870,12 @@ type UserEmail struct {
 	Visibility string `json:"visibility,omitempty"`
 }
 
+// PublicKey is an SSH public key added to the account of a GitHub user.
+type PublicKey struct {
+	ID  int64  `json:"id,omitempty"`
+	Key string `json:"key,omitempty"`
+}
+
 type Org struct {
 	Login string `json:"login,omitempty"`
 }
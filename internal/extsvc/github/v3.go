@@ -263,6 +263,24 @@ func (c *V3Client) GetAuthenticatedUserEmails(ctx context.Context) ([]*UserEmail
 	return emails, nil
 }
 
+var MockGetAuthenticatedUserPublicKeys func(ctx context.Context) ([]*PublicKey, error)
+
+// GetAuthenticatedUserPublicKeys returns the first 100 SSH public keys added to the
+// account of the currently authenticated user. The token requires the
+// read:public_key scope.
+func (c *V3Client) GetAuthenticatedUserPublicKeys(ctx context.Context) ([]*PublicKey, error) {
+	if MockGetAuthenticatedUserPublicKeys != nil {
+		return MockGetAuthenticatedUserPublicKeys(ctx)
+	}
+
+	var keys []*PublicKey
+	err := c.requestGet(ctx, "/user/keys?per_page=100", &keys)
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
 func (c *V3Client) getAuthenticatedUserOrgs(ctx context.Context, page int) (
 	orgs []*Org,
 	hasNextPage bool,
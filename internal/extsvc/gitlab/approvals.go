@@ -0,0 +1,88 @@
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// MergeRequestApprovals is the approval state of a merge request, as returned
+// by the merge request approvals endpoint.
+type MergeRequestApprovals struct {
+	Approved          bool                    `json:"approved"`
+	ApprovalsRequired int                     `json:"approvals_required"`
+	ApprovalsLeft     int                     `json:"approvals_left"`
+	ApprovedBy        []*MergeRequestApprover `json:"approved_by"`
+}
+
+// MergeRequestApprover is a user that approved a merge request.
+type MergeRequestApprover struct {
+	User User `json:"user"`
+}
+
+// GetMergeRequestApprovals retrieves the approval state of the given merge
+// request. GitLab instances that don't expose the approvals endpoint return a
+// nil state without an error.
+func (c *Client) GetMergeRequestApprovals(ctx context.Context, project *Project, iid ID) (*MergeRequestApprovals, error) {
+	if MockGetMergeRequestApprovals != nil {
+		return MockGetMergeRequestApprovals(c, ctx, project, iid)
+	}
+
+	time.Sleep(c.rateLimitMonitor.RecommendedWaitForBackgroundOp(1))
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("projects/%d/merge_requests/%d/approvals", project.ID, iid), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request to get merge request approvals")
+	}
+
+	resp := &MergeRequestApprovals{}
+	if _, _, err := c.do(ctx, req, resp); err != nil {
+		var e HTTPError
+		if errors.As(err, &e) && e.Code() == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "sending request to get merge request approvals")
+	}
+
+	return resp, nil
+}
+
+type CreateMergeRequestApprovalRuleOpts struct {
+	Name              string  `json:"name"`
+	ApprovalsRequired int     `json:"approvals_required"`
+	UserIDs           []int32 `json:"user_ids,omitempty"`
+}
+
+// CreateMergeRequestApprovalRule adds an approval rule to the given merge
+// request.
+func (c *Client) CreateMergeRequestApprovalRule(ctx context.Context, project *Project, mr *MergeRequest, opts CreateMergeRequestApprovalRuleOpts) error {
+	if MockCreateMergeRequestApprovalRule != nil {
+		return MockCreateMergeRequestApprovalRule(c, ctx, project, mr, opts)
+	}
+
+	data, err := json.Marshal(opts)
+	if err != nil {
+		return errors.Wrap(err, "marshalling options")
+	}
+
+	time.Sleep(c.rateLimitMonitor.RecommendedWaitForBackgroundOp(1))
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("projects/%d/merge_requests/%d/approval_rules", project.ID, mr.IID), bytes.NewBuffer(data))
+	if err != nil {
+		return errors.Wrap(err, "creating request to create a merge request approval rule")
+	}
+
+	var resp struct {
+		ID int32 `json:"id"`
+	}
+	if _, _, err := c.do(ctx, req, &resp); err != nil {
+		return errors.Wrap(err, "sending request to create a merge request approval rule")
+	}
+
+	return nil
+}
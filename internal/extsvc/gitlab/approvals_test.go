@@ -0,0 +1,116 @@
+package gitlab
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGetMergeRequestApprovals(t *testing.T) {
+	ctx := context.Background()
+	project := &Project{}
+
+	t.Run("not found", func(t *testing.T) {
+		client := newTestClient(t)
+		client.httpClient = &mockHTTPEmptyResponse{http.StatusNotFound}
+
+		approvals, err := client.GetMergeRequestApprovals(ctx, project, 1)
+		if approvals != nil {
+			t.Errorf("unexpected non-nil approvals: %+v", approvals)
+		}
+		if err != nil {
+			t.Errorf("unexpected non-nil error: %+v", err)
+		}
+	})
+
+	t.Run("error status code", func(t *testing.T) {
+		client := newTestClient(t)
+		client.httpClient = &mockHTTPEmptyResponse{http.StatusInternalServerError}
+
+		approvals, err := client.GetMergeRequestApprovals(ctx, project, 1)
+		if approvals != nil {
+			t.Errorf("unexpected non-nil approvals: %+v", approvals)
+		}
+		if err == nil {
+			t.Error("unexpected nil error")
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		client := newTestClient(t)
+		client.httpClient = &mockHTTPResponseBody{
+			responseBody: `{"approved":false,"approvals_required":2,"approvals_left":1,"approved_by":[{"user":{"id":5,"username":"alice"}}]}`,
+		}
+
+		approvals, err := client.GetMergeRequestApprovals(ctx, project, 1)
+		if err != nil {
+			t.Fatalf("unexpected non-nil error: %+v", err)
+		}
+		want := &MergeRequestApprovals{
+			ApprovalsRequired: 2,
+			ApprovalsLeft:     1,
+			ApprovedBy:        []*MergeRequestApprover{{User: User{ID: 5, Username: "alice"}}},
+		}
+		if diff := cmp.Diff(want, approvals); diff != "" {
+			t.Errorf("unexpected approvals: %s", diff)
+		}
+	})
+}
+
+func TestCreateMergeRequestApprovalRule(t *testing.T) {
+	ctx := context.Background()
+	project := &Project{}
+	mr := &MergeRequest{}
+
+	t.Run("error status code", func(t *testing.T) {
+		client := newTestClient(t)
+		client.httpClient = &mockHTTPEmptyResponse{http.StatusForbidden}
+
+		if err := client.CreateMergeRequestApprovalRule(ctx, project, mr, CreateMergeRequestApprovalRuleOpts{}); err == nil {
+			t.Error("unexpected nil error")
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		client := newTestClient(t)
+		client.httpClient = &mockHTTPResponseBody{
+			responseBody: `{"id":1}`,
+		}
+
+		if err := client.CreateMergeRequestApprovalRule(ctx, project, mr, CreateMergeRequestApprovalRuleOpts{Name: "security", ApprovalsRequired: 1}); err != nil {
+			t.Errorf("unexpected non-nil error: %+v", err)
+		}
+	})
+}
+
+func TestGetUserByUsername(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("not found", func(t *testing.T) {
+		client := newTestClient(t)
+		client.httpClient = &mockHTTPResponseBody{responseBody: `[]`}
+
+		user, err := client.GetUserByUsername(ctx, "alice")
+		if user != nil {
+			t.Errorf("unexpected non-nil user: %+v", user)
+		}
+		if err == nil {
+			t.Error("unexpected nil error")
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		client := newTestClient(t)
+		client.httpClient = &mockHTTPResponseBody{responseBody: `[{"id":5,"username":"alice"}]`}
+
+		user, err := client.GetUserByUsername(ctx, "alice")
+		if err != nil {
+			t.Fatalf("unexpected non-nil error: %+v", err)
+		}
+		if diff := cmp.Diff(&User{ID: 5, Username: "alice"}, user); diff != "" {
+			t.Errorf("unexpected user: %s", diff)
+		}
+	})
+}
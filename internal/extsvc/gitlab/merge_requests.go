@@ -41,7 +41,9 @@ type MergeRequest struct {
 	TargetBranch   string            `json:"target_branch"`
 	WebURL         string            `json:"web_url"`
 	WorkInProgress bool              `json:"work_in_progress"`
+	Draft          bool              `json:"draft"`
 	Author         User              `json:"author"`
+	Reviewers      []User            `json:"reviewers"`
 
 	DiffRefs DiffRefs `json:"diff_refs"`
 
@@ -52,6 +54,13 @@ type MergeRequest struct {
 	Notes               []*Note
 	Pipelines           []*Pipeline
 	ResourceStateEvents []*ResourceStateEvent
+	Approvals           *MergeRequestApprovals
+}
+
+// IsDraft returns true if the merge request is marked as a draft. GitLab 14.0
+// introduced the draft field, older versions only set work_in_progress.
+func (mr *MergeRequest) IsDraft() bool {
+	return mr.Draft || mr.WorkInProgress
 }
 
 // IsWIP returns true if the given title would result in GitLab rendering the MR as 'work in progress'.
@@ -85,10 +94,11 @@ var (
 )
 
 type CreateMergeRequestOpts struct {
-	SourceBranch string `json:"source_branch"`
-	TargetBranch string `json:"target_branch"`
-	Title        string `json:"title"`
-	Description  string `json:"description,omitempty"`
+	SourceBranch string  `json:"source_branch"`
+	TargetBranch string  `json:"target_branch"`
+	Title        string  `json:"title"`
+	Description  string  `json:"description,omitempty"`
+	ReviewerIDs  []int32 `json:"reviewer_ids,omitempty"`
 	// TODO: other fields at
 	// https://docs.gitlab.com/ee/api/merge_requests.html#create-mr as needed.
 }
@@ -11,6 +11,10 @@ var MockListUsers func(c *Client, ctx context.Context, urlStr string) (users []*
 // MockGetUser, if non-nil, will be called instead of Client.GetUser
 var MockGetUser func(c *Client, ctx context.Context, id string) (*User, error)
 
+// MockListAuthenticatedUserSSHKeys, if non-nil, will be called instead of
+// Client.ListAuthenticatedUserSSHKeys
+var MockListAuthenticatedUserSSHKeys func(c *Client, ctx context.Context) ([]*SSHKey, error)
+
 // MockGetProject, if non-nil, will be called instead of Client.GetProject
 var MockGetProject func(c *Client, ctx context.Context, op GetProjectOp) (*Project, error)
 
@@ -52,3 +56,15 @@ var MockMergeMergeRequest func(c *Client, ctx context.Context, project *Project,
 // MockCreateMergeRequestNote, if non-nil, will be called instead of
 // Client.CreateMergeRequestNote
 var MockCreateMergeRequestNote func(c *Client, ctx context.Context, project *Project, mr *MergeRequest, body string) error
+
+// MockGetMergeRequestApprovals, if non-nil, will be called instead of
+// Client.GetMergeRequestApprovals
+var MockGetMergeRequestApprovals func(c *Client, ctx context.Context, project *Project, iid ID) (*MergeRequestApprovals, error)
+
+// MockCreateMergeRequestApprovalRule, if non-nil, will be called instead of
+// Client.CreateMergeRequestApprovalRule
+var MockCreateMergeRequestApprovalRule func(c *Client, ctx context.Context, project *Project, mr *MergeRequest, opts CreateMergeRequestApprovalRuleOpts) error
+
+// MockGetUserByUsername, if non-nil, will be called instead of
+// Client.GetUserByUsername
+var MockGetUserByUsername func(c *Client, ctx context.Context, username string) (*User, error)
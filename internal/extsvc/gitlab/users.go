@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 
+	"github.com/cockroachdb/errors"
 	"github.com/peterhellberg/link"
 )
 
@@ -68,3 +70,56 @@ func (c *Client) GetUser(ctx context.Context, id string) (*User, error) {
 	}
 	return &usr, nil
 }
+
+// ErrUserNotFound is returned by GetUserByUsername when no user with the given
+// username exists.
+var ErrUserNotFound = errors.New("user not found")
+
+// GetUserByUsername looks up the user with the given username.
+func (c *Client) GetUserByUsername(ctx context.Context, username string) (*User, error) {
+	if MockGetUserByUsername != nil {
+		return MockGetUserByUsername(c, ctx, username)
+	}
+
+	q := make(url.Values)
+	q.Set("username", username)
+	req, err := http.NewRequest("GET", "users?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []*User
+	if _, _, err := c.do(ctx, req, &users); err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, errors.Wrap(ErrUserNotFound, username)
+	}
+	return users[0], nil
+}
+
+// SSHKey is an SSH public key added to the account of a GitLab user.
+type SSHKey struct {
+	ID    int32  `json:"id"`
+	Title string `json:"title"`
+	Key   string `json:"key"`
+}
+
+// ListAuthenticatedUserSSHKeys returns the first 100 SSH public keys added to the
+// account of the currently authenticated user.
+func (c *Client) ListAuthenticatedUserSSHKeys(ctx context.Context) ([]*SSHKey, error) {
+	if MockListAuthenticatedUserSSHKeys != nil {
+		return MockListAuthenticatedUserSSHKeys(c, ctx)
+	}
+
+	req, err := http.NewRequest("GET", "user/keys?per_page=100", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []*SSHKey
+	if _, _, err := c.do(ctx, req, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
@@ -75,10 +75,12 @@ const (
 	// in preference to the Type values below.
 
 	KindAWSCodeCommit   = "AWSCODECOMMIT"
+	KindAzureDevOps     = "AZUREDEVOPS"
 	KindBitbucketServer = "BITBUCKETSERVER"
 	KindBitbucketCloud  = "BITBUCKETCLOUD"
 	KindGitHub          = "GITHUB"
 	KindGitLab          = "GITLAB"
+	KindGitea           = "GITEA"
 	KindGitolite        = "GITOLITE"
 	KindPerforce        = "PERFORCE"
 	KindPhabricator     = "PHABRICATOR"
@@ -94,6 +96,10 @@ const (
 	// suffix (e.g., "arn:aws:codecommit:us-west-1:123456789:").
 	TypeAWSCodeCommit = "awscodecommit"
 
+	// TypeAzureDevOps is the (api.ExternalRepoSpec).ServiceType value for Azure DevOps repositories. The
+	// ServiceID value is the base URL of Azure DevOps Services (https://dev.azure.com/).
+	TypeAzureDevOps = "azuredevops"
+
 	// TypeBitbucketServer is the (api.ExternalRepoSpec).ServiceType value for Bitbucket Server projects. The
 	// ServiceID value is the base URL to the Bitbucket Server instance.
 	TypeBitbucketServer = "bitbucketServer"
@@ -110,6 +116,10 @@ const (
 	// value is the base URL to the GitLab instance (https://gitlab.com or self-hosted GitLab URL).
 	TypeGitLab = "gitlab"
 
+	// TypeGitea is the (api.ExternalRepoSpec).ServiceType value for Gitea repositories. The ServiceID
+	// value is the base URL to the Gitea instance (https://gitea.example.com/).
+	TypeGitea = "gitea"
+
 	// TypeGitolite is the (api.ExternalRepoSpec).ServiceType value for Gitolite projects.
 	TypeGitolite = "gitolite"
 
@@ -134,6 +144,8 @@ func KindToType(kind string) string {
 	switch kind {
 	case KindAWSCodeCommit:
 		return TypeAWSCodeCommit
+	case KindAzureDevOps:
+		return TypeAzureDevOps
 	case KindBitbucketServer:
 		return TypeBitbucketServer
 	case KindBitbucketCloud:
@@ -142,6 +154,8 @@ func KindToType(kind string) string {
 		return TypeGitHub
 	case KindGitLab:
 		return TypeGitLab
+	case KindGitea:
+		return TypeGitea
 	case KindGitolite:
 		return TypeGitolite
 	case KindPhabricator:
@@ -163,6 +177,8 @@ func TypeToKind(t string) string {
 	switch t {
 	case TypeAWSCodeCommit:
 		return KindAWSCodeCommit
+	case TypeAzureDevOps:
+		return KindAzureDevOps
 	case TypeBitbucketServer:
 		return KindBitbucketServer
 	case TypeBitbucketCloud:
@@ -171,6 +187,8 @@ func TypeToKind(t string) string {
 		return KindGitHub
 	case TypeGitLab:
 		return KindGitLab
+	case TypeGitea:
+		return KindGitea
 	case TypeGitolite:
 		return KindGitolite
 	case TypePerforce:
@@ -199,6 +217,8 @@ func ParseServiceType(s string) (string, bool) {
 	switch strings.ToLower(s) {
 	case TypeAWSCodeCommit:
 		return TypeAWSCodeCommit, true
+	case TypeAzureDevOps:
+		return TypeAzureDevOps, true
 	case bbsLower:
 		return TypeBitbucketServer, true
 	case bbcLower:
@@ -207,6 +227,8 @@ func ParseServiceType(s string) (string, bool) {
 		return TypeGitHub, true
 	case TypeGitLab:
 		return TypeGitLab, true
+	case TypeGitea:
+		return TypeGitea, true
 	case TypeGitolite:
 		return TypeGitolite, true
 	case TypePerforce:
@@ -228,6 +250,8 @@ func ParseServiceKind(s string) (string, bool) {
 	switch strings.ToUpper(s) {
 	case KindAWSCodeCommit:
 		return KindAWSCodeCommit, true
+	case KindAzureDevOps:
+		return KindAzureDevOps, true
 	case KindBitbucketServer:
 		return KindBitbucketServer, true
 	case KindBitbucketCloud:
@@ -236,6 +260,8 @@ func ParseServiceKind(s string) (string, bool) {
 		return KindGitHub, true
 	case KindGitLab:
 		return KindGitLab, true
+	case KindGitea:
+		return KindGitea, true
 	case KindGitolite:
 		return KindGitolite, true
 	case KindPerforce:
@@ -273,10 +299,14 @@ func ParseConfig(kind, config string) (cfg interface{}, _ error) {
 		cfg = &schema.BitbucketServerConnection{}
 	case KindBitbucketCloud:
 		cfg = &schema.BitbucketCloudConnection{}
+	case KindAzureDevOps:
+		cfg = &schema.AzureDevOpsConnection{}
 	case KindGitHub:
 		cfg = &schema.GitHubConnection{}
 	case KindGitLab:
 		cfg = &schema.GitLabConnection{}
+	case KindGitea:
+		cfg = &schema.GiteaConnection{}
 	case KindGitolite:
 		cfg = &schema.GitoliteConnection{}
 	case KindPerforce:
@@ -295,20 +325,17 @@ func ParseConfig(kind, config string) (cfg interface{}, _ error) {
 
 const IDParam = "externalServiceID"
 
+// WebhookURL returns the URL of the webhook router that the code host of the
+// external service should send webhooks to, or an empty string if webhooks aren't
+// supported for the kind of external service.
 func WebhookURL(kind string, externalServiceID int64, externalURL string) string {
-	var path string
 	switch strings.ToUpper(kind) {
-	case KindGitHub:
-		path = "github-webhooks"
-	case KindBitbucketServer:
-		path = "bitbucket-server-webhooks"
-	case KindGitLab:
-		path = "gitlab-webhooks"
+	case KindGitHub, KindBitbucketServer, KindBitbucketCloud, KindAzureDevOps, KindGitLab:
 	default:
 		return ""
 	}
-	// eg. https://example.com/.api/github-webhooks?externalServiceID=1
-	return fmt.Sprintf("%s/.api/%s?%s=%d", externalURL, path, IDParam, externalServiceID)
+	// eg. https://example.com/.api/webhooks?externalServiceID=1
+	return fmt.Sprintf("%s/.api/webhooks?%s=%d", externalURL, IDParam, externalServiceID)
 }
 
 // ExtractToken attempts to extract the token from the supplied args
@@ -322,6 +349,10 @@ func ExtractToken(config string, kind string) (string, error) {
 		return c.Token, nil
 	case *schema.GitLabConnection:
 		return c.Token, nil
+	case *schema.GiteaConnection:
+		return c.Token, nil
+	case *schema.AzureDevOpsConnection:
+		return c.Token, nil
 	case *schema.BitbucketServerConnection:
 		return c.Token, nil
 	case *schema.PhabricatorConnection:
@@ -354,6 +385,10 @@ type RateLimitConfig struct {
 	DisplayName string
 	Limit       rate.Limit
 	IsDefault   bool
+
+	// MaxConcurrentRequests is the maximum number of concurrent requests to
+	// the code host. Zero means unlimited.
+	MaxConcurrentRequests int
 }
 
 // GetLimitFromConfig gets RateLimitConfig from an already parsed config schema.
@@ -370,14 +405,23 @@ func GetLimitFromConfig(kind string, config interface{}) (rlc RateLimitConfig, e
 		rlc.Limit = rate.Limit(10)
 		if c != nil && c.RateLimit != nil {
 			rlc.Limit = limitOrInf(c.RateLimit.Enabled, c.RateLimit.RequestsPerHour)
+			rlc.MaxConcurrentRequests = maxConcurrentOrUnlimited(c.RateLimit.Enabled, c.RateLimit.MaxConcurrentRequests)
 			rlc.IsDefault = false
 		}
 		rlc.BaseURL = c.Url
 	case *schema.GitHubConnection:
 		// 5000 per hour is the default enforced by GitHub on their end
 		rlc.Limit = rate.Limit(5000.0 / 3600.0)
+		if c != nil && c.GitHubApp != nil {
+			// GitHub App installations get up to 12500 per hour, depending
+			// on the number of repositories and users. The rate limit
+			// monitor still backs off according to the actual remaining
+			// limit reported by GitHub.
+			rlc.Limit = rate.Limit(12500.0 / 3600.0)
+		}
 		if c != nil && c.RateLimit != nil {
 			rlc.Limit = limitOrInf(c.RateLimit.Enabled, c.RateLimit.RequestsPerHour)
+			rlc.MaxConcurrentRequests = maxConcurrentOrUnlimited(c.RateLimit.Enabled, c.RateLimit.MaxConcurrentRequests)
 			rlc.IsDefault = false
 		}
 		rlc.BaseURL = c.Url
@@ -386,6 +430,7 @@ func GetLimitFromConfig(kind string, config interface{}) (rlc RateLimitConfig, e
 		rlc.Limit = rate.Limit(8)
 		if c != nil && c.RateLimit != nil {
 			rlc.Limit = limitOrInf(c.RateLimit.Enabled, c.RateLimit.RequestsPerHour)
+			rlc.MaxConcurrentRequests = maxConcurrentOrUnlimited(c.RateLimit.Enabled, c.RateLimit.MaxConcurrentRequests)
 			rlc.IsDefault = false
 		}
 		rlc.BaseURL = c.Url
@@ -393,6 +438,23 @@ func GetLimitFromConfig(kind string, config interface{}) (rlc RateLimitConfig, e
 		rlc.Limit = defaultRateLimit
 		if c != nil && c.RateLimit != nil {
 			rlc.Limit = limitOrInf(c.RateLimit.Enabled, c.RateLimit.RequestsPerHour)
+			rlc.MaxConcurrentRequests = maxConcurrentOrUnlimited(c.RateLimit.Enabled, c.RateLimit.MaxConcurrentRequests)
+			rlc.IsDefault = false
+		}
+		rlc.BaseURL = c.Url
+	case *schema.GiteaConnection:
+		rlc.Limit = defaultRateLimit
+		if c != nil && c.RateLimit != nil {
+			rlc.Limit = limitOrInf(c.RateLimit.Enabled, c.RateLimit.RequestsPerHour)
+			rlc.MaxConcurrentRequests = maxConcurrentOrUnlimited(c.RateLimit.Enabled, c.RateLimit.MaxConcurrentRequests)
+			rlc.IsDefault = false
+		}
+		rlc.BaseURL = c.Url
+	case *schema.AzureDevOpsConnection:
+		rlc.Limit = defaultRateLimit
+		if c != nil && c.RateLimit != nil {
+			rlc.Limit = limitOrInf(c.RateLimit.Enabled, c.RateLimit.RequestsPerHour)
+			rlc.MaxConcurrentRequests = maxConcurrentOrUnlimited(c.RateLimit.Enabled, c.RateLimit.MaxConcurrentRequests)
 			rlc.IsDefault = false
 		}
 		rlc.BaseURL = c.Url
@@ -431,6 +493,13 @@ func limitOrInf(enabled bool, perHour float64) rate.Limit {
 	return rate.Inf
 }
 
+func maxConcurrentOrUnlimited(enabled bool, max int) int {
+	if enabled && max > 0 {
+		return max
+	}
+	return 0
+}
+
 type ErrRateLimitUnsupported struct {
 	codehostKind string
 }
@@ -492,6 +561,10 @@ func UniqueCodeHostIdentifier(kind, config string) (string, error) {
 		rawURL = c.Url
 	case *schema.BitbucketCloudConnection:
 		rawURL = c.Url
+	case *schema.GiteaConnection:
+		rawURL = c.Url
+	case *schema.AzureDevOpsConnection:
+		rawURL = c.Url
 	case *schema.PhabricatorConnection:
 		rawURL = c.Url
 	case *schema.OtherExternalServiceConnection:
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"golang.org/x/time/rate"
 )
 
 func TestExtractToken(t *testing.T) {
@@ -32,6 +33,16 @@ func TestExtractToken(t *testing.T) {
 			kind:   KindPhabricator,
 			want:   "deadbeef",
 		},
+		{
+			config: `{"token": "deadbeef"}`,
+			kind:   KindGitea,
+			want:   "deadbeef",
+		},
+		{
+			config: `{"token": "deadbeef"}`,
+			kind:   KindAzureDevOps,
+			want:   "deadbeef",
+		},
 	} {
 		t.Run(tc.kind, func(t *testing.T) {
 			have, err := ExtractToken(tc.config, tc.kind)
@@ -108,6 +119,42 @@ func TestExtractRateLimitConfig(t *testing.T) {
 				IsDefault:   true,
 			},
 		},
+		{
+			name:        "Gitea default",
+			config:      `{"url": "https://example.com/"}`,
+			kind:        KindGitea,
+			displayName: "Gitea 1",
+			want: RateLimitConfig{
+				BaseURL:     "https://example.com/",
+				DisplayName: "Gitea 1",
+				Limit:       2.0,
+				IsDefault:   true,
+			},
+		},
+		{
+			name:        "Gitea non-default",
+			config:      `{"url": "https://example.com/", "rateLimit": {"enabled": true, "requestsPerHour": 3600}}`,
+			kind:        KindGitea,
+			displayName: "Gitea 1",
+			want: RateLimitConfig{
+				BaseURL:     "https://example.com/",
+				DisplayName: "Gitea 1",
+				Limit:       1.0,
+				IsDefault:   false,
+			},
+		},
+		{
+			name:        "Azure DevOps default",
+			config:      `{"url": "https://dev.azure.com/"}`,
+			kind:        KindAzureDevOps,
+			displayName: "Azure DevOps 1",
+			want: RateLimitConfig{
+				BaseURL:     "https://dev.azure.com/",
+				DisplayName: "Azure DevOps 1",
+				Limit:       2.0,
+				IsDefault:   true,
+			},
+		},
 		{
 			name:        "GitLab non-default",
 			config:      `{"url": "https://example.com/", "rateLimit": {"enabled": true, "requestsPerHour": 3600}}`,
@@ -156,6 +203,43 @@ func TestExtractRateLimitConfig(t *testing.T) {
 				IsDefault:   false,
 			},
 		},
+		{
+			name:        "GitHub max concurrent requests",
+			config:      `{"url": "https://example.com/", "rateLimit": {"enabled": true, "requestsPerHour": 3600, "maxConcurrentRequests": 4}}`,
+			kind:        KindGitHub,
+			displayName: "GitHub 1",
+			want: RateLimitConfig{
+				BaseURL:               "https://example.com/",
+				DisplayName:           "GitHub 1",
+				Limit:                 1.0,
+				IsDefault:             false,
+				MaxConcurrentRequests: 4,
+			},
+		},
+		{
+			name:        "GitHub App default",
+			config:      `{"url": "https://example.com/", "gitHubApp": {"appID": 1, "installationID": 2, "privateKey": "key"}}`,
+			kind:        KindGitHub,
+			displayName: "GitHub 1",
+			want: RateLimitConfig{
+				BaseURL:     "https://example.com/",
+				DisplayName: "GitHub 1",
+				Limit:       3.4722222222222223,
+				IsDefault:   true,
+			},
+		},
+		{
+			name:        "GitLab max concurrent requests disabled",
+			config:      `{"url": "https://example.com/", "rateLimit": {"enabled": false, "requestsPerHour": 3600, "maxConcurrentRequests": 4}}`,
+			kind:        KindGitLab,
+			displayName: "GitLab 1",
+			want: RateLimitConfig{
+				BaseURL:     "https://example.com/",
+				DisplayName: "GitLab 1",
+				Limit:       rate.Inf,
+				IsDefault:   false,
+			},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			rlc, err := ExtractRateLimitConfig(tc.config, tc.kind, tc.displayName)
@@ -303,6 +387,16 @@ func TestUniqueCodeHostIdentifier(t *testing.T) {
 			config: `{"url": "https://bitbucket.org/"}`,
 			want:   "https://bitbucket.org/",
 		},
+		{
+			kind:   KindGitea,
+			config: `{"url": "https://gitea.example.com"}`,
+			want:   "https://gitea.example.com/",
+		},
+		{
+			kind:   KindAzureDevOps,
+			config: `{"url": "https://dev.azure.com"}`,
+			want:   "https://dev.azure.com/",
+		},
 
 		{
 			kind:   KindGitolite,
@@ -14,6 +14,10 @@ type FeatureFlag struct {
 	Bool    *FeatureFlagBool
 	Rollout *FeatureFlagRollout
 
+	// Rules target cohorts of users. The first rule matching a user decides the value of
+	// the flag for them, see EvaluateForUserAttributes. Rules only apply to signed-in users.
+	Rules []FeatureFlagRule
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt *time.Time
@@ -30,6 +34,27 @@ func (f *FeatureFlag) EvaluateForUser(userID int32) bool {
 	panic("one of Bool or Rollout must be set")
 }
 
+// EvaluateForUserAttributes evaluates the feature flag for the user with the given
+// attributes. The first rule matching the user decides the value, otherwise it is the
+// value of EvaluateForUser.
+func (f *FeatureFlag) EvaluateForUserAttributes(attrs *UserAttributes) *Evaluation {
+	for i, rule := range f.Rules {
+		if rule.Matches(attrs) {
+			return &Evaluation{
+				FlagName: f.Name,
+				Value:    hashUserAndFlag(attrs.UserID, f.Name)%10000 < uint32(rule.Rollout),
+				Source:   EvaluationSourceRule,
+				Rule:     i,
+			}
+		}
+	}
+	return &Evaluation{
+		FlagName: f.Name,
+		Value:    f.EvaluateForUser(attrs.UserID),
+		Source:   EvaluationSourceDefault,
+	}
+}
+
 func hashUserAndFlag(userID int32, flagName string) uint32 {
 	h := fnv.New32()
 	binary.Write(h, binary.LittleEndian, userID)
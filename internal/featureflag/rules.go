@@ -0,0 +1,126 @@
+package featureflag
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+// FeatureFlagRule targets a cohort of users. A user matches a rule if they match all of its
+// conditions, and a condition listing several values matches users matching any of them. A
+// matching user is then part of the rule's rollout, using the same deterministic hash as
+// FeatureFlagRollout, so that raising the rollout of a rule only ever adds users to it.
+type FeatureFlagRule struct {
+	// UserIDs matches the users with one of the given IDs.
+	UserIDs []int32 `json:"userIDs,omitempty"`
+	// OrgIDs matches the members of one of the given organizations.
+	OrgIDs []int32 `json:"orgIDs,omitempty"`
+	// EmailDomains matches the users with a verified email address at one of the given
+	// domains, e.g. "sourcegraph.com".
+	EmailDomains []string `json:"emailDomains,omitempty"`
+	// SiteAdmin matches the users who are, or are not, site admins.
+	SiteAdmin *bool `json:"siteAdmin,omitempty"`
+
+	// Rollout is an integer between 0 and 10000, representing the percent of matching
+	// users for which the feature flag evaluates to 'true' in increments of 0.01%.
+	Rollout int32 `json:"rollout"`
+}
+
+// UserAttributes are the attributes of a user that rules are evaluated against.
+type UserAttributes struct {
+	UserID    int32
+	SiteAdmin bool
+	OrgIDs    []int32
+	// EmailDomains are the lowercase domains of the verified email addresses of the user.
+	EmailDomains []string
+}
+
+// Validate returns an error if the rule can't be evaluated. A rule must have at least one
+// condition, since a rule without conditions is better expressed as the flag's rollout.
+func (r *FeatureFlagRule) Validate() error {
+	if len(r.UserIDs) == 0 && len(r.OrgIDs) == 0 && len(r.EmailDomains) == 0 && r.SiteAdmin == nil {
+		return errors.New("feature flag rule must have at least one condition")
+	}
+	if r.Rollout < 0 || r.Rollout > 10000 {
+		return errors.Newf("feature flag rule rollout must be between 0 and 10000, got %d", r.Rollout)
+	}
+	return nil
+}
+
+// Matches returns true if the user with the given attributes matches all of the rule's
+// conditions.
+func (r *FeatureFlagRule) Matches(attrs *UserAttributes) bool {
+	if len(r.UserIDs) > 0 && !containsInt32(r.UserIDs, attrs.UserID) {
+		return false
+	}
+	if len(r.OrgIDs) > 0 && !containsAnyInt32(r.OrgIDs, attrs.OrgIDs) {
+		return false
+	}
+	if len(r.EmailDomains) > 0 && !containsAnyDomain(r.EmailDomains, attrs.EmailDomains) {
+		return false
+	}
+	if r.SiteAdmin != nil && *r.SiteAdmin != attrs.SiteAdmin {
+		return false
+	}
+	return true
+}
+
+func containsInt32(values []int32, v int32) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAnyInt32(values, vs []int32) bool {
+	for _, v := range vs {
+		if containsInt32(values, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAnyDomain(domains, userDomains []string) bool {
+	for _, domain := range domains {
+		for _, userDomain := range userDomains {
+			if strings.EqualFold(domain, userDomain) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// EvaluationSource is what decided the value of a feature flag for a user.
+type EvaluationSource string
+
+const (
+	// EvaluationSourceDefault means the value is the flag's bool value or rollout.
+	EvaluationSourceDefault EvaluationSource = "default"
+	// EvaluationSourceRule means the value is the rollout of a rule of the flag.
+	EvaluationSourceRule EvaluationSource = "rule"
+	// EvaluationSourceOrgOverride means the value is an override of one of the user's
+	// organizations.
+	EvaluationSourceOrgOverride EvaluationSource = "org_override"
+	// EvaluationSourceUserOverride means the value is an override of the user.
+	EvaluationSourceUserOverride EvaluationSource = "user_override"
+)
+
+// Evaluation is the value of a feature flag for a user, along with what decided it.
+// Overrides of the user take precedence over overrides of their organizations, which take
+// precedence over the rules of the flag, in order, which take precedence over the flag's
+// bool value or rollout.
+type Evaluation struct {
+	FlagName string
+	Value    bool
+	Source   EvaluationSource
+	// Rule is the index of the rule that decided the value, if Source is
+	// EvaluationSourceRule.
+	Rule int
+	// Override is the override that decided the value, if Source is one of the override
+	// sources.
+	Override *Override
+}
@@ -0,0 +1,82 @@
+package featureflag
+
+import "testing"
+
+func TestFeatureFlagRuleMatches(t *testing.T) {
+	yes, no := true, false
+	attrs := &UserAttributes{
+		UserID:       1,
+		SiteAdmin:    false,
+		OrgIDs:       []int32{10, 11},
+		EmailDomains: []string{"sourcegraph.com"},
+	}
+
+	for _, tc := range []struct {
+		name string
+		rule FeatureFlagRule
+		want bool
+	}{
+		{"user", FeatureFlagRule{UserIDs: []int32{2, 1}}, true},
+		{"other user", FeatureFlagRule{UserIDs: []int32{2}}, false},
+		{"org", FeatureFlagRule{OrgIDs: []int32{11}}, true},
+		{"other org", FeatureFlagRule{OrgIDs: []int32{12}}, false},
+		{"email domain", FeatureFlagRule{EmailDomains: []string{"example.com", "SourceGraph.com"}}, true},
+		{"other email domain", FeatureFlagRule{EmailDomains: []string{"example.com"}}, false},
+		{"not site admin", FeatureFlagRule{SiteAdmin: &no}, true},
+		{"site admin", FeatureFlagRule{SiteAdmin: &yes}, false},
+		{"all conditions", FeatureFlagRule{OrgIDs: []int32{10}, EmailDomains: []string{"sourcegraph.com"}}, true},
+		{"one condition not matching", FeatureFlagRule{OrgIDs: []int32{10}, SiteAdmin: &yes}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if have := tc.rule.Matches(attrs); have != tc.want {
+				t.Errorf("unexpected match: have %v, want %v", have, tc.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateForUserAttributes(t *testing.T) {
+	flag := &FeatureFlag{
+		Name: "f",
+		Bool: &FeatureFlagBool{Value: true},
+		Rules: []FeatureFlagRule{
+			{OrgIDs: []int32{1}, Rollout: 0},
+			{OrgIDs: []int32{1, 2}, Rollout: 10000},
+		},
+	}
+
+	for _, tc := range []struct {
+		name   string
+		orgIDs []int32
+		want   Evaluation
+	}{
+		{"first matching rule", []int32{1}, Evaluation{FlagName: "f", Value: false, Source: EvaluationSourceRule, Rule: 0}},
+		{"second matching rule", []int32{2}, Evaluation{FlagName: "f", Value: true, Source: EvaluationSourceRule, Rule: 1}},
+		{"no matching rule", []int32{3}, Evaluation{FlagName: "f", Value: true, Source: EvaluationSourceDefault}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			have := flag.EvaluateForUserAttributes(&UserAttributes{UserID: 1, OrgIDs: tc.orgIDs})
+			if *have != tc.want {
+				t.Errorf("unexpected evaluation: have %+v, want %+v", *have, tc.want)
+			}
+		})
+	}
+
+	// The rollout of a rule is deterministic and consistent with the flag's rollout.
+	rollout := &FeatureFlag{Name: "r", Rollout: &FeatureFlagRollout{Rollout: 5000}, Rules: []FeatureFlagRule{{UserIDs: []int32{42}, Rollout: 5000}}}
+	if have, want := rollout.EvaluateForUserAttributes(&UserAttributes{UserID: 42}).Value, rollout.EvaluateForUser(42); have != want {
+		t.Errorf("rule rollout differs from flag rollout: have %v, want %v", have, want)
+	}
+}
+
+func TestFeatureFlagRuleValidate(t *testing.T) {
+	if err := (&FeatureFlagRule{Rollout: 100}).Validate(); err == nil {
+		t.Error("expected error for rule without conditions")
+	}
+	if err := (&FeatureFlagRule{UserIDs: []int32{1}, Rollout: 10001}).Validate(); err == nil {
+		t.Error("expected error for rule with invalid rollout")
+	}
+	if err := (&FeatureFlagRule{UserIDs: []int32{1}, Rollout: 10000}).Validate(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
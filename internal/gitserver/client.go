@@ -134,6 +134,59 @@ type ArchiveOptions struct {
 	Treeish string   // the tree or commit to produce an archive for
 	Format  string   // format of the resulting archive (usually "tar" or "zip")
 	Paths   []string // if nonempty, only include these paths
+
+	// Include and Exclude are glob patterns of paths, relative to the root of the
+	// repository, e.g. "docs/**/*.md". "*" doesn't match "/", "**/" matches any
+	// number of directories and a trailing "/**" matches everything inside a
+	// directory.
+	Include []string // if nonempty, only include paths matching these patterns (in addition to Paths)
+	Exclude []string // exclude paths matching these patterns
+
+	// MaxDepth, if positive, only includes files with at most this many path
+	// components, e.g. 1 only includes the files in the root directory.
+	MaxDepth int
+}
+
+// Pathspecs returns the git pathspecs selecting the paths to include in the
+// archive. An empty list selects all paths.
+func (o ArchiveOptions) Pathspecs() []string {
+	pathspecs := make([]string, 0, len(o.Paths)+len(o.Include)+len(o.Exclude)+1)
+	pathspecs = append(pathspecs, o.Paths...)
+	for _, pattern := range o.Include {
+		pathspecs = append(pathspecs, ":(glob)"+pattern)
+	}
+	for _, pattern := range o.Exclude {
+		pathspecs = append(pathspecs, ":(exclude,glob)"+pattern)
+	}
+	if o.MaxDepth > 0 {
+		// Excludes the contents of every directory at the maximum depth.
+		pathspecs = append(pathspecs, ":(exclude,glob)"+strings.Repeat("*/", o.MaxDepth)+"**")
+	}
+	return pathspecs
+}
+
+// ParseArchiveFilters sets Include, Exclude and MaxDepth of o from the
+// "include", "exclude" and "depth" query parameters, as encoded by ArchiveURL.
+func (o *ArchiveOptions) ParseArchiveFilters(q url.Values) error {
+	for _, pattern := range append(q["include"], q["exclude"]...) {
+		if pattern == "" {
+			return errors.New("empty path pattern")
+		}
+	}
+
+	var maxDepth int
+	if depth := q.Get("depth"); depth != "" {
+		var err error
+		maxDepth, err = strconv.Atoi(depth)
+		if err != nil || maxDepth < 0 {
+			return errors.Errorf("invalid depth %q", depth)
+		}
+	}
+
+	o.Include = q["include"]
+	o.Exclude = q["exclude"]
+	o.MaxDepth = maxDepth
+	return nil
 }
 
 // archiveReader wraps the StdoutReader yielded by gitserver's
@@ -173,6 +226,15 @@ func (c *Client) ArchiveURL(repo api.RepoName, opt ArchiveOptions) *url.URL {
 	for _, path := range opt.Paths {
 		q.Add("path", path)
 	}
+	for _, pattern := range opt.Include {
+		q.Add("include", pattern)
+	}
+	for _, pattern := range opt.Exclude {
+		q.Add("exclude", pattern)
+	}
+	if opt.MaxDepth > 0 {
+		q.Set("depth", strconv.Itoa(opt.MaxDepth))
+	}
 
 	return &url.URL{
 		Scheme:   "http",
@@ -1001,6 +1063,36 @@ func (c *Client) CreateCommitFromPatch(ctx context.Context, req protocol.CreateC
 	return res.Rev, nil
 }
 
+// CommitGraphSince returns the commits of the repository reachable from tips but not from any
+// commit of watermark, children first. Passing the tips of a previous call as the watermark only
+// returns the commits added since that call.
+func (c *Client) CommitGraphSince(ctx context.Context, repo api.RepoName, tips, watermark []api.CommitID) ([]protocol.CommitParents, error) {
+	req := protocol.CommitGraphRequest{
+		Repo:      repo,
+		Tips:      tips,
+		Watermark: watermark,
+	}
+	resp, err := c.httpPost(ctx, req.Repo, "commit-graph", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, &gitdomain.RepoNotExistError{Repo: repo}
+	}
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, &url.Error{URL: resp.Request.URL.String(), Op: "CommitGraphSince", Err: errors.Errorf("CommitGraphSince: http status %d: %s", resp.StatusCode, bytes.TrimSpace(data))}
+	}
+
+	var res protocol.CommitGraphResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return res.Commits, nil
+}
+
 // GetObject fetches git object data in the supplied repo
 func (c *Client) GetObject(ctx context.Context, repo api.RepoName, objectName string) (*gitdomain.GitObject, error) {
 	if ClientMocks.GetObject != nil {
@@ -65,19 +65,44 @@ func TestClient_Archive(t *testing.T) {
 	}
 	defer os.RemoveAll(root)
 
+	simple := createSimpleGitRepo(t, root)
+
 	tests := map[api.RepoName]struct {
 		remote string
+		opt    gitserver.ArchiveOptions
 		want   map[string]string
 		err    error
 	}{
 		"simple": {
-			remote: createSimpleGitRepo(t, root),
+			remote: simple,
 			want: map[string]string{
 				"dir1/":      "",
 				"dir1/file1": "infile1",
 				"file 2":     "infile2",
 			},
 		},
+		"simple-include": {
+			remote: simple,
+			opt:    gitserver.ArchiveOptions{Include: []string{"**/file1"}},
+			want: map[string]string{
+				"dir1/":      "",
+				"dir1/file1": "infile1",
+			},
+		},
+		"simple-exclude": {
+			remote: simple,
+			opt:    gitserver.ArchiveOptions{Exclude: []string{"dir1/**"}},
+			want: map[string]string{
+				"file 2": "infile2",
+			},
+		},
+		"simple-max-depth": {
+			remote: simple,
+			opt:    gitserver.ArchiveOptions{MaxDepth: 1},
+			want: map[string]string{
+				"file 2": "infile2",
+			},
+		},
 		"repo-with-dotgit-dir": {
 			remote: createRepoWithDotGitDir(t, root),
 			want:   map[string]string{"file1": "hello\n", ".git/mydir/file2": "milton\n", ".git/mydir/": "", ".git/": ""},
@@ -117,7 +142,9 @@ func TestClient_Archive(t *testing.T) {
 				}
 			}
 
-			rc, err := cli.Archive(ctx, name, gitserver.ArchiveOptions{Treeish: "HEAD", Format: "zip"})
+			opt := test.opt
+			opt.Treeish, opt.Format = "HEAD", "zip"
+			rc, err := cli.Archive(ctx, name, opt)
 			if have, want := fmt.Sprint(err), fmt.Sprint(test.err); have != want {
 				t.Errorf("archive: have err %v, want %v", have, want)
 			}
@@ -350,3 +350,24 @@ type GetObjectRequest struct {
 type GetObjectResponse struct {
 	Object gitdomain.GitObject
 }
+
+// CommitGraphRequest is a request for the commits reachable from Tips but not from any commit of
+// Watermark, which is usually the set of ref tips of a previous request.
+type CommitGraphRequest struct {
+	Repo      api.RepoName
+	Tips      []api.CommitID
+	Watermark []api.CommitID
+}
+
+// CommitGraphResponse is the response to a CommitGraphRequest.
+type CommitGraphResponse struct {
+	// Commits are the commits added since the watermark, in topological order with children
+	// before their parents.
+	Commits []CommitParents
+}
+
+// CommitParents is a commit and its parent commits.
+type CommitParents struct {
+	Commit  api.CommitID
+	Parents []api.CommitID
+}
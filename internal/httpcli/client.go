@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"io"
 	"math"
 	"math/rand"
 	"net"
@@ -13,6 +14,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/PuerkitoBio/rehttp"
@@ -28,6 +30,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
 	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
 	"github.com/sourcegraph/sourcegraph/internal/rcache"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 )
@@ -98,6 +101,8 @@ func NewExternalClientFactory() *Factory {
 	return NewFactory(
 		NewMiddleware(
 			ContextErrorMiddleware,
+			RequestCounterMiddleware,
+			ConcurrencyLimitMiddleware(ratelimit.DefaultConcurrencyRegistry),
 		),
 		NewTimeoutOpt(externalTimeout),
 		// ExternalTransportOpt needs to be before TracedTransportOpt and
@@ -239,6 +244,67 @@ func ContextErrorMiddleware(cli Doer) Doer {
 	})
 }
 
+// ConcurrencyLimitMiddleware returns a middleware that limits the number of
+// concurrent requests to the code hosts with a concurrency limit in the given
+// registry. A request counts against the limit until its response body is
+// closed.
+func ConcurrencyLimitMiddleware(registry *ratelimit.ConcurrencyRegistry) Middleware {
+	return func(cli Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			l, ok := registry.Lookup(req.URL)
+			if !ok {
+				return cli.Do(req)
+			}
+
+			if err := l.Acquire(req.Context()); err != nil {
+				return nil, err
+			}
+
+			resp, err := cli.Do(req)
+			if err != nil {
+				l.Release()
+				return resp, err
+			}
+
+			resp.Body = &releasingReadCloser{ReadCloser: resp.Body, release: l.Release}
+			return resp, nil
+		})
+	}
+}
+
+// releasingReadCloser calls release once when it's closed.
+type releasingReadCloser struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (r *releasingReadCloser) Close() error {
+	err := r.ReadCloser.Close()
+	r.once.Do(r.release)
+	return err
+}
+
+type requestCounterKey struct{}
+
+// WithRequestCounter returns a context which makes RequestCounterMiddleware
+// count the requests sent with it, or any context derived from it, in n.
+func WithRequestCounter(ctx context.Context, n *int64) context.Context {
+	return context.WithValue(ctx, requestCounterKey{}, n)
+}
+
+// RequestCounterMiddleware counts the requests sent with a context returned by
+// WithRequestCounter. This lets callers measure how many requests to a code
+// host an operation needed, without access to the clients it used.
+func RequestCounterMiddleware(cli Doer) Doer {
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		if n, ok := req.Context().Value(requestCounterKey{}).(*int64); ok {
+			atomic.AddInt64(n, 1)
+		}
+		return cli.Do(req)
+	})
+}
+
 // GitHubProxyRedirectMiddleware rewrites requests to the "github-proxy" host
 // to "https://api.github.com".
 func GitHubProxyRedirectMiddleware(cli Doer) Doer {
@@ -24,6 +24,8 @@ import (
 	"github.com/PuerkitoBio/rehttp"
 	"github.com/cockroachdb/errors"
 	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
 )
 
 func TestHeadersMiddleware(t *testing.T) {
@@ -134,6 +136,63 @@ func TestContextErrorMiddleware(t *testing.T) {
 	}
 }
 
+func TestConcurrencyLimitMiddleware(t *testing.T) {
+	registry := ratelimit.NewConcurrencyRegistry()
+	limiter := registry.Get("https://example.com")
+	limiter.SetLimit(1)
+
+	cli := ConcurrencyLimitMiddleware(registry)(newFakeClient(http.StatusOK, []byte("ok"), nil))
+
+	req, _ := http.NewRequest("GET", "https://example.com/api", nil)
+	resp, err := cli.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first request holds the only slot until its body is closed.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := cli.Do(req.WithContext(ctx)); err != context.DeadlineExceeded {
+		t.Fatalf("have error: %v\nwant error: %v", err, context.DeadlineExceeded)
+	}
+
+	// Requests to other hosts are not limited.
+	other, _ := http.NewRequest("GET", "https://example.org/api", nil)
+	if _, err := cli.Do(other.WithContext(ctx)); err != nil {
+		t.Fatalf("unexpected error for unlimited host: %v", err)
+	}
+
+	resp.Body.Close()
+	resp, err = cli.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+}
+
+func TestRequestCounterMiddleware(t *testing.T) {
+	cli := RequestCounterMiddleware(newFakeClient(http.StatusOK, []byte("ok"), nil))
+
+	var n int64
+	ctx := WithRequestCounter(context.Background(), &n)
+	for i := 0; i < 3; i++ {
+		req, _ := http.NewRequestWithContext(ctx, "GET", "https://example.com/api", nil)
+		if _, err := cli.Do(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Requests without a counter in their context are not counted.
+	req, _ := http.NewRequest("GET", "https://example.com/api", nil)
+	if _, err := cli.Do(req); err != nil {
+		t.Fatal(err)
+	}
+
+	if n != 3 {
+		t.Fatalf("have %d requests, want 3", n)
+	}
+}
+
 func genCert(subject string) (string, error) {
 	priv, err := rsa.GenerateKey(rand.Reader, 1024)
 	if err != nil {
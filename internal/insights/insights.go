@@ -86,62 +86,83 @@ func FilterSettingJson(settingJson string, prefix string) (map[string]json.RawMe
 // GetSearchInsights returns insights stored in user / org / global settings that match the extensions schema. This schema is planned for deprecation
 // and currently only exists to service pings.
 func GetSearchInsights(ctx context.Context, db dbutil.DB, filter SettingFilter) ([]SearchInsight, error) {
-	prefix := "searchInsights."
-	settings, err := GetSettings(ctx, db, filter, prefix)
+	settings, err := GetSettings(ctx, db, filter, searchInsightsPrefix)
 	if err != nil {
 		return []SearchInsight{}, err
 	}
 
-	var raw map[string]json.RawMessage
 	results := make([]SearchInsight, 0)
-
 	for _, setting := range settings {
-		raw, err = FilterSettingJson(setting.Contents, prefix)
+		insights, err := SearchInsightsFromSettings(setting)
 		if err != nil {
 			return []SearchInsight{}, err
 		}
+		results = append(results, insights...)
+	}
+	return results, nil
+}
 
-		var temp SearchInsight
+const searchInsightsPrefix = "searchInsights."
 
-		for id, body := range raw {
-			temp.ID = id
-			if err := json.Unmarshal(body, &temp); err != nil {
-				// a deprecated schema collides with this field name, so skip any deserialization errors
-				continue
-			}
-			results = append(results, temp)
+// SearchInsightsFromSettings returns the search insights that match the extensions schema in the
+// given settings. Insights are owned by the subject of the settings.
+func SearchInsightsFromSettings(setting *api.Settings) ([]SearchInsight, error) {
+	raw, err := FilterSettingJson(setting.Contents, searchInsightsPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]SearchInsight, 0, len(raw))
+	for id, body := range raw {
+		temp := SearchInsight{ID: id}
+		if err := json.Unmarshal(body, &temp); err != nil {
+			// a deprecated schema collides with this field name, so skip any deserialization errors
+			continue
 		}
+		temp.UserID = setting.Subject.User
+		temp.OrgID = setting.Subject.Org
+		results = append(results, temp)
 	}
 	return results, nil
 }
 
 func GetLangStatsInsights(ctx context.Context, db dbutil.DB, filter SettingFilter) ([]LangStatsInsight, error) {
-	prefix := "codeStatsInsights."
-
-	settings, err := GetSettings(ctx, db, filter, prefix)
+	settings, err := GetSettings(ctx, db, filter, langStatsInsightsPrefix)
 	if err != nil {
 		return []LangStatsInsight{}, err
 	}
 
-	var raw map[string]json.RawMessage
 	results := make([]LangStatsInsight, 0)
-
 	for _, setting := range settings {
-		raw, err = FilterSettingJson(setting.Contents, prefix)
+		insights, err := LangStatsInsightsFromSettings(setting)
 		if err != nil {
 			return []LangStatsInsight{}, err
 		}
+		results = append(results, insights...)
+	}
+	return results, nil
+}
 
-		var temp LangStatsInsight
+const langStatsInsightsPrefix = "codeStatsInsights."
+
+// LangStatsInsightsFromSettings returns the language statistics insights in the given settings.
+// Insights are owned by the subject of the settings.
+func LangStatsInsightsFromSettings(setting *api.Settings) ([]LangStatsInsight, error) {
+	raw, err := FilterSettingJson(setting.Contents, langStatsInsightsPrefix)
+	if err != nil {
+		return nil, err
+	}
 
-		for id, body := range raw {
-			temp.ID = id
-			if err := json.Unmarshal(body, &temp); err != nil {
-				// a deprecated schema collides with this field name, so skip any deserialization errors
-				continue
-			}
-			results = append(results, temp)
+	results := make([]LangStatsInsight, 0, len(raw))
+	for id, body := range raw {
+		temp := LangStatsInsight{ID: id}
+		if err := json.Unmarshal(body, &temp); err != nil {
+			// a deprecated schema collides with this field name, so skip any deserialization errors
+			continue
 		}
+		temp.UserID = setting.Subject.User
+		temp.OrgID = setting.Subject.Org
+		results = append(results, temp)
 	}
 	return results, nil
 }
@@ -152,9 +173,7 @@ func GetLangStatsInsights(ctx context.Context, db dbutil.DB, filter SettingFilte
 // fully to a persistent database. Any deserialization errors that occur during parsing will be logged as errors, but will not
 // cause any errors to surface.
 func GetIntegratedInsights(ctx context.Context, db dbutil.DB) ([]SearchInsight, error) {
-	prefix := "insights.allrepos"
-
-	settings, err := GetSettings(ctx, db, All, prefix)
+	settings, err := GetSettings(ctx, db, All, integratedInsightsPrefix)
 	if err != nil {
 		return []SearchInsight{}, err
 	}
@@ -163,27 +182,11 @@ func GetIntegratedInsights(ctx context.Context, db dbutil.DB) ([]SearchInsight,
 
 	results := make([]SearchInsight, 0)
 	for _, setting := range settings {
-		perms := permissionAssociations{
-			userID: setting.Subject.User,
-			orgID:  setting.Subject.Org,
-		}
-
-		var raw map[string]json.RawMessage
-		raw, err = FilterSettingJson(setting.Contents, prefix)
+		insights, err := IntegratedInsightsFromSettings(setting)
 		if err != nil {
 			multi = multierror.Append(multi, err)
-			continue
-		}
-
-		for _, val := range raw {
-			// iterate for each instance of the prefix key in the settings. This should never be len > 1, but it's technically a map.
-			temp, err := unmarshalIntegrated(val)
-			if err != nil {
-				// this isn't actually a total failure case, we could have partially parsed this dictionary.
-				multi = multierror.Append(multi, err)
-			}
-			results = append(results, temp.Insights(perms)...)
 		}
+		results = append(results, insights...)
 	}
 
 	if multi != nil {
@@ -193,6 +196,36 @@ func GetIntegratedInsights(ctx context.Context, db dbutil.DB) ([]SearchInsight,
 	return results, nil
 }
 
+const integratedInsightsPrefix = "insights.allrepos"
+
+// IntegratedInsightsFromSettings returns the integrated insights in the given settings. Insights
+// are owned by the subject of the settings. Deserialization errors are returned along with the
+// insights that could be parsed.
+func IntegratedInsightsFromSettings(setting *api.Settings) ([]SearchInsight, error) {
+	perms := permissionAssociations{
+		userID: setting.Subject.User,
+		orgID:  setting.Subject.Org,
+	}
+
+	raw, err := FilterSettingJson(setting.Contents, integratedInsightsPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var multi error
+	results := make([]SearchInsight, 0)
+	for _, val := range raw {
+		// iterate for each instance of the prefix key in the settings. This should never be len > 1, but it's technically a map.
+		temp, err := unmarshalIntegrated(val)
+		if err != nil {
+			// this isn't actually a total failure case, we could have partially parsed this dictionary.
+			multi = multierror.Append(multi, err)
+		}
+		results = append(results, temp.Insights(perms)...)
+	}
+	return results, multi
+}
+
 // IntegratedInsights represents a settings dictionary of valid insights that are integrated across the extensions API and the backend.
 type IntegratedInsights map[string]SearchInsight
 
@@ -272,6 +305,8 @@ type LangStatsInsight struct {
 	Title          string
 	Repository     string
 	OtherThreshold float32
+	OrgID          *int32
+	UserID         *int32
 }
 
 type SettingFilter string
@@ -291,8 +326,7 @@ type SettingDashboard struct {
 }
 
 func DiscoverDashboardsInSettings(ctx context.Context, db dbutil.DB) ([]SettingDashboard, error) {
-	prefix := "insights.dashboards"
-	settings, err := GetSettings(ctx, db, All, prefix)
+	settings, err := GetSettings(ctx, db, All, dashboardsPrefix)
 	if err != nil {
 		return []SettingDashboard{}, err
 	}
@@ -300,26 +334,11 @@ func DiscoverDashboardsInSettings(ctx context.Context, db dbutil.DB) ([]SettingD
 
 	results := make([]SettingDashboard, 0)
 	for _, setting := range settings {
-		perms := permissionAssociations{
-			userID: setting.Subject.User,
-			orgID:  setting.Subject.Org,
-		}
-
-		var raw map[string]json.RawMessage
-		raw, err := FilterSettingJson(setting.Contents, prefix)
+		dashboards, err := DashboardsFromSettings(setting)
 		if err != nil {
 			multi = multierror.Append(multi, err)
-			continue
-		}
-		for _, val := range raw {
-			// iterate for each instance of the prefix key in the settings. This should never be len > 1, but it's technically a map.
-			temp, err := unmarshalDashboard(val)
-			if err != nil {
-				// this isn't actually a total failure case, we could have partially parsed this dictionary.
-				multi = multierror.Append(multi, err)
-			}
-			results = append(results, temp.Dashboards(perms)...)
 		}
+		results = append(results, dashboards...)
 	}
 	if multi != nil {
 		log15.Error("insights: deserialization errors parsing integrated dashboards", "error", multi)
@@ -327,6 +346,36 @@ func DiscoverDashboardsInSettings(ctx context.Context, db dbutil.DB) ([]SettingD
 	return results, nil
 }
 
+const dashboardsPrefix = "insights.dashboards"
+
+// DashboardsFromSettings returns the dashboards in the given settings. Dashboards are owned by
+// the subject of the settings. Deserialization errors are returned along with the dashboards that
+// could be parsed.
+func DashboardsFromSettings(setting *api.Settings) ([]SettingDashboard, error) {
+	perms := permissionAssociations{
+		userID: setting.Subject.User,
+		orgID:  setting.Subject.Org,
+	}
+
+	raw, err := FilterSettingJson(setting.Contents, dashboardsPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var multi error
+	results := make([]SettingDashboard, 0)
+	for _, val := range raw {
+		// iterate for each instance of the prefix key in the settings. This should never be len > 1, but it's technically a map.
+		temp, err := unmarshalDashboard(val)
+		if err != nil {
+			// this isn't actually a total failure case, we could have partially parsed this dictionary.
+			multi = multierror.Append(multi, err)
+		}
+		results = append(results, temp.Dashboards(perms)...)
+	}
+	return results, multi
+}
+
 // Dashboards returns an array of contained dashboards.
 func (i IntegratedDashboards) Dashboards(perms permissionAssociations) []SettingDashboard {
 	results := make([]SettingDashboard, 0, len(i))
@@ -42,6 +42,7 @@ func TestGetSearchInsights(t *testing.T) {
 
 	weeks := 2
 
+	wantOrg := int32(1)
 	want := []SearchInsight{{
 		ID:           "searchInsights.insight.global.simple",
 		Title:        "my insight",
@@ -54,6 +55,7 @@ func TestGetSearchInsights(t *testing.T) {
 		Step: Interval{
 			Weeks: &weeks,
 		},
+		OrgID: &wantOrg,
 	}}
 
 	if diff := cmp.Diff(want, got); diff != "" {
@@ -0,0 +1,195 @@
+package ratelimit
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	concurrencyInFlight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "src_concurrency_limit_in_flight_requests",
+		Help: "Number of in flight requests to a code host with a concurrency limit.",
+	}, []string{"host"})
+
+	concurrencyWaiting = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "src_concurrency_limit_waiting_requests",
+		Help: "Number of requests to a code host waiting for the concurrency limit.",
+	}, []string{"host"})
+
+	concurrencyWaitDuration = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "src_concurrency_limit_wait_duration_seconds",
+		Help: "The amount of time spent waiting on the concurrency limit of a code host.",
+	}, []string{"host"})
+)
+
+// DefaultConcurrencyRegistry is the default global concurrency limit registry.
+// It will hold concurrency limits for each instance of our services.
+var DefaultConcurrencyRegistry = NewConcurrencyRegistry()
+
+// NewConcurrencyRegistry creates a new empty registry.
+func NewConcurrencyRegistry() *ConcurrencyRegistry {
+	return &ConcurrencyRegistry{
+		limiters: make(map[string]*ConcurrencyLimiter),
+	}
+}
+
+// ConcurrencyRegistry keeps a mapping of code host to *ConcurrencyLimiter.
+type ConcurrencyRegistry struct {
+	mu sync.Mutex
+	// Concurrency limiter per code host, keys are the lower cased host names of
+	// the code host base URLs.
+	limiters map[string]*ConcurrencyLimiter
+}
+
+// Get fetches the concurrency limiter associated with the given code host. If
+// none has been configured yet, an unlimited limiter is set and returned.
+func (r *ConcurrencyRegistry) Get(baseURL string) *ConcurrencyLimiter {
+	host := concurrencyHost(baseURL)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	l := r.limiters[host]
+	if l == nil {
+		l = &ConcurrencyLimiter{host: host}
+		r.limiters[host] = l
+	}
+	return l
+}
+
+// Lookup returns the concurrency limiter for the code host the given request
+// URL is sent to, if there is one. Requests to the "api." subdomain of a code
+// host, such as api.github.com for github.com, count against the limit of the
+// code host.
+func (r *ConcurrencyRegistry) Lookup(u *url.URL) (*ConcurrencyLimiter, bool) {
+	host := strings.ToLower(u.Hostname())
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if l, ok := r.limiters[host]; ok {
+		return l, true
+	}
+	if strings.HasPrefix(host, "api.") {
+		l, ok := r.limiters[strings.TrimPrefix(host, "api.")]
+		return l, ok
+	}
+	return nil, false
+}
+
+// Count returns the total number of concurrency limiters in the registry.
+func (r *ConcurrencyRegistry) Count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.limiters)
+}
+
+func concurrencyHost(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Host == "" {
+		return strings.ToLower(baseURL)
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// ConcurrencyLimiter is a semaphore limiting the number of concurrent requests
+// to a code host. Unlike a fixed size semaphore, its limit can be changed while
+// it's in use. The zero value is unlimited.
+type ConcurrencyLimiter struct {
+	host string
+
+	mu       sync.Mutex
+	limit    int // <= 0 means unlimited
+	inFlight int
+	waiters  []chan struct{}
+}
+
+// Limit returns the current limit. Zero means unlimited.
+func (l *ConcurrencyLimiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// SetLimit changes the limit. A limit <= 0 removes the limit. Raising the limit
+// immediately admits waiting requests; lowering it lets requests in flight
+// complete.
+func (l *ConcurrencyLimiter) SetLimit(limit int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if limit < 0 {
+		limit = 0
+	}
+	l.limit = limit
+	l.admitLocked()
+}
+
+// Acquire blocks until a request may be sent, or ctx is done. Every successful
+// call must be followed by a call to Release once the request completed.
+func (l *ConcurrencyLimiter) Acquire(ctx context.Context) error {
+	l.mu.Lock()
+	if l.availableLocked() {
+		l.inFlight++
+		concurrencyInFlight.WithLabelValues(l.host).Inc()
+		l.mu.Unlock()
+		return nil
+	}
+
+	ready := make(chan struct{})
+	l.waiters = append(l.waiters, ready)
+	l.mu.Unlock()
+
+	concurrencyWaiting.WithLabelValues(l.host).Inc()
+	start := time.Now()
+	defer func() {
+		concurrencyWaiting.WithLabelValues(l.host).Dec()
+		concurrencyWaitDuration.WithLabelValues(l.host).Add(time.Since(start).Seconds())
+	}()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		for i, w := range l.waiters {
+			if w == ready {
+				l.waiters = append(l.waiters[:i], l.waiters[i+1:]...)
+				l.mu.Unlock()
+				return ctx.Err()
+			}
+		}
+		l.mu.Unlock()
+
+		// We were admitted concurrently with the context being done, so we
+		// need to give back our slot.
+		l.Release()
+		return ctx.Err()
+	}
+}
+
+// Release marks a request acquired with Acquire as completed.
+func (l *ConcurrencyLimiter) Release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inFlight--
+	concurrencyInFlight.WithLabelValues(l.host).Dec()
+	l.admitLocked()
+}
+
+func (l *ConcurrencyLimiter) availableLocked() bool {
+	return l.limit <= 0 || l.inFlight < l.limit
+}
+
+// admitLocked admits waiting requests in FIFO order, as far as the limit
+// allows.
+func (l *ConcurrencyLimiter) admitLocked() {
+	for len(l.waiters) > 0 && l.availableLocked() {
+		w := l.waiters[0]
+		l.waiters = l.waiters[1:]
+		l.inFlight++
+		concurrencyInFlight.WithLabelValues(l.host).Inc()
+		close(w)
+	}
+}
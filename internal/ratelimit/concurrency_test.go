@@ -0,0 +1,85 @@
+package ratelimit
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestConcurrencyLimiter(t *testing.T) {
+	ctx := context.Background()
+	l := NewConcurrencyRegistry().Get("https://example.com/")
+	l.SetLimit(1)
+
+	if err := l.Acquire(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	acquired := make(chan error)
+	go func() { acquired <- l.Acquire(ctx) }()
+
+	select {
+	case <-acquired:
+		t.Fatal("acquired more than the limit")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	l.Release()
+	if err := <-acquired; err != nil {
+		t.Fatal(err)
+	}
+	l.Release()
+
+	t.Run("context done", func(t *testing.T) {
+		if err := l.Acquire(ctx); err != nil {
+			t.Fatal(err)
+		}
+		defer l.Release()
+
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		if err := l.Acquire(ctx); err != context.DeadlineExceeded {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("raising the limit admits waiters", func(t *testing.T) {
+		if err := l.Acquire(ctx); err != nil {
+			t.Fatal(err)
+		}
+		defer l.Release()
+
+		go func() { acquired <- l.Acquire(ctx) }()
+		time.Sleep(10 * time.Millisecond)
+
+		l.SetLimit(0)
+		if err := <-acquired; err != nil {
+			t.Fatal(err)
+		}
+		l.Release()
+	})
+}
+
+func TestConcurrencyRegistry_Lookup(t *testing.T) {
+	r := NewConcurrencyRegistry()
+	l := r.Get("https://GitHub.com/")
+
+	for rawURL, want := range map[string]bool{
+		"https://github.com/api/v3/repos": true,
+		"https://api.github.com/repos":    true,
+		"https://gitlab.com/api/v4":       false,
+	} {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		have, ok := r.Lookup(u)
+		if ok != want {
+			t.Errorf("%s: want found %t, have %t", rawURL, want, ok)
+		}
+		if ok && have != l {
+			t.Errorf("%s: unexpected limiter", rawURL)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package repos
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// An AzureDevOpsSource yields repositories from a single Azure DevOps connection configured in
+// Sourcegraph via the external services configuration.
+type AzureDevOpsSource struct {
+	svc     *types.ExternalService
+	config  *schema.AzureDevOpsConnection
+	baseURL *url.URL
+	exclude excludeFunc
+	client  *azuredevops.Client
+}
+
+// NewAzureDevOpsSource returns a new AzureDevOpsSource from the given external service.
+func NewAzureDevOpsSource(svc *types.ExternalService, cf *httpcli.Factory) (*AzureDevOpsSource, error) {
+	var c schema.AzureDevOpsConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	return newAzureDevOpsSource(svc, &c, cf)
+}
+
+func newAzureDevOpsSource(svc *types.ExternalService, c *schema.AzureDevOpsConnection, cf *httpcli.Factory) (*AzureDevOpsSource, error) {
+	baseURL, err := url.Parse(c.Url)
+	if err != nil {
+		return nil, err
+	}
+	baseURL = extsvc.NormalizeBaseURL(baseURL)
+
+	if cf == nil {
+		cf = httpcli.ExternalClientFactory
+	}
+
+	cli, err := cf.Doer()
+	if err != nil {
+		return nil, err
+	}
+
+	var eb excludeBuilder
+	for _, r := range c.Exclude {
+		eb.Exact(r.Name)
+		eb.Pattern(r.Pattern)
+	}
+	exclude, err := eb.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return &AzureDevOpsSource{
+		svc:     svc,
+		config:  c,
+		baseURL: baseURL,
+		exclude: exclude,
+		client:  azuredevops.NewClient(baseURL, &auth.BasicAuth{Username: c.Username, Password: c.Token}, cli),
+	}, nil
+}
+
+// ListRepos returns all Azure DevOps repositories of the configured organizations and projects.
+func (s AzureDevOpsSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	seen := make(map[string]bool)
+	emit := func(repo *azuredevops.Repository) {
+		if repo.IsDisabled || seen[repo.ID] || s.exclude(repo.NameWithOwner()) {
+			return
+		}
+		seen[repo.ID] = true
+		results <- SourceResult{Source: s, Repo: s.makeRepo(repo)}
+	}
+
+	for _, org := range s.config.Orgs {
+		repos, err := s.client.OrgRepos(ctx, org)
+		if err != nil {
+			results <- SourceResult{Source: s, Err: errors.Wrapf(err, "azuredevops.orgs: item=%q", org)}
+			continue
+		}
+		for _, repo := range repos {
+			emit(repo)
+		}
+	}
+
+	for _, orgProject := range s.config.Projects {
+		org, project, err := splitAzureDevOpsProject(orgProject)
+		if err != nil {
+			results <- SourceResult{Source: s, Err: err}
+			continue
+		}
+		repos, err := s.client.ProjectRepos(ctx, org, project)
+		if err != nil {
+			results <- SourceResult{Source: s, Err: errors.Wrapf(err, "azuredevops.projects: item=%q", orgProject)}
+			continue
+		}
+		for _, repo := range repos {
+			emit(repo)
+		}
+	}
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s AzureDevOpsSource) ExternalServices() types.ExternalServices {
+	return types.ExternalServices{s.svc}
+}
+
+func (s AzureDevOpsSource) makeRepo(r *azuredevops.Repository) *types.Repo {
+	urn := s.svc.URN()
+	return &types.Repo{
+		Name: reposource.AzureDevOpsRepoName(
+			s.config.RepositoryPathPattern,
+			s.baseURL.Hostname(),
+			r.Org,
+			r.Project.Name,
+			r.Name,
+		),
+		URI: string(reposource.AzureDevOpsRepoName(
+			"",
+			s.baseURL.Hostname(),
+			r.Org,
+			r.Project.Name,
+			r.Name,
+		)),
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          r.ID,
+			ServiceType: extsvc.TypeAzureDevOps,
+			ServiceID:   s.baseURL.String(),
+		},
+		Fork:    r.IsFork,
+		Private: r.Project.Visibility != "public",
+		Sources: map[string]*types.SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: s.remoteURL(r),
+			},
+		},
+		Metadata: r,
+	}
+}
+
+// remoteURL returns the repository's Git remote URL
+//
+// note: this used to contain credentials but that is no longer the case
+// if you need to get an authenticated clone url use repos.CloneURL
+func (s *AzureDevOpsSource) remoteURL(repo *azuredevops.Repository) string {
+	if s.config.GitURLType == "ssh" {
+		return repo.SSHURL
+	}
+	return repo.RemoteURL
+}
+
+func splitAzureDevOpsProject(orgProject string) (org, project string, err error) {
+	parts := strings.SplitN(orgProject, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.Errorf("invalid Azure DevOps project %q, expected \"org/project\"", orgProject)
+	}
+	return parts[0], parts[1], nil
+}
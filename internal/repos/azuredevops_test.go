@@ -0,0 +1,82 @@
+package repos
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestAzureDevOpsSource_ListRepos(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "alice" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var repos []*azuredevops.Repository
+		switch r.URL.Path {
+		case "/myorg/_apis/git/repositories":
+			repos = []*azuredevops.Repository{
+				{ID: "1", Name: "a", Project: azuredevops.Project{Name: "p"}},
+				{ID: "2", Name: "b", Project: azuredevops.Project{Name: "p"}},
+				{ID: "3", Name: "archived-c", Project: azuredevops.Project{Name: "p"}},
+				{ID: "4", Name: "disabled", Project: azuredevops.Project{Name: "p"}, IsDisabled: true},
+			}
+		case "/otherorg/q/_apis/git/repositories":
+			repos = []*azuredevops.Repository{
+				{ID: "5", Name: "d", Project: azuredevops.Project{Name: "q"}},
+			}
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"count": len(repos), "value": repos})
+	}))
+	defer srv.Close()
+
+	svc := &types.ExternalService{ID: 1, Kind: extsvc.KindAzureDevOps}
+	conf := &schema.AzureDevOpsConnection{
+		Url:      srv.URL,
+		Username: "alice",
+		Token:    "secret",
+		Orgs:     []string{"myorg"},
+		Projects: []string{"otherorg/q"},
+		Exclude: []*schema.ExcludedAzureDevOpsRepo{
+			{Name: "myorg/p/b"},
+			{Pattern: "^myorg/p/archived-.*"},
+		},
+	}
+	src, err := newAzureDevOpsSource(svc, conf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repos, err := listAll(context.Background(), src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	have := types.Repos(repos).Names()
+	sort.Strings(have)
+	host := src.baseURL.Hostname()
+	want := []string{host + "/myorg/p/a", host + "/otherorg/q/d"}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Errorf("Mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, r := range repos {
+		if r.ExternalRepo.ServiceType != extsvc.TypeAzureDevOps || r.ExternalRepo.ID == "" {
+			t.Errorf("unexpected external repo spec %+v", r.ExternalRepo)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package repos
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestGiteaSource_ListRepos(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var v interface{}
+		switch r.URL.Path {
+		case "/api/v1/orgs/myorg/repos":
+			v = []*gitea.Repo{
+				{ID: 1, FullName: "myorg/a", CloneURL: "https://gitea.example.com/myorg/a.git"},
+				{ID: 2, FullName: "myorg/b", CloneURL: "https://gitea.example.com/myorg/b.git"},
+				{ID: 3, FullName: "myorg/archived-c", CloneURL: "https://gitea.example.com/myorg/archived-c.git"},
+				{ID: 4, FullName: "myorg/empty", Empty: true},
+			}
+		case "/api/v1/repos/myorg/a":
+			v = &gitea.Repo{ID: 1, FullName: "myorg/a", CloneURL: "https://gitea.example.com/myorg/a.git"}
+		case "/api/v1/repos/alice/d":
+			v = &gitea.Repo{ID: 5, FullName: "alice/d", Private: true, CloneURL: "https://gitea.example.com/alice/d.git"}
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(v)
+	}))
+	defer srv.Close()
+
+	svc := &types.ExternalService{ID: 1, Kind: extsvc.KindGitea}
+	conf := &schema.GiteaConnection{
+		Url:   srv.URL,
+		Token: "secret",
+		Orgs:  []string{"myorg"},
+		Repos: []string{"myorg/a", "alice/d"},
+		Exclude: []*schema.ExcludedGiteaRepo{
+			{Id: 2},
+			{Pattern: "^myorg/archived-.*"},
+		},
+	}
+	src, err := newGiteaSource(svc, conf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repos, err := listAll(context.Background(), src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	have := types.Repos(repos).Names()
+	sort.Strings(have)
+	host := src.baseURL.Hostname()
+	want := []string{host + "/alice/d", host + "/myorg/a"}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Errorf("Mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, r := range repos {
+		if r.ExternalRepo.ServiceType != extsvc.TypeGitea || r.ExternalRepo.ID == "" {
+			t.Errorf("unexpected external repo spec %+v", r.ExternalRepo)
+		}
+	}
+}
@@ -127,7 +127,10 @@ func newGithubSource(svc *types.ExternalService, c *schema.GitHubConnection, cf
 	if err != nil {
 		return nil, err
 	}
-	token := &auth.OAuthBearerToken{Token: c.Token}
+	token, err := github.NewAuthenticatorFromConfig(apiURL, c, cli)
+	if err != nil {
+		return nil, err
+	}
 
 	var (
 		v3Client     = github.NewV3Client(apiURL, token, cli)
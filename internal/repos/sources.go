@@ -50,6 +50,10 @@ func NewSource(svc *types.ExternalService, cf *httpcli.Factory) (Source, error)
 		return NewBitbucketServerSource(svc, cf)
 	case extsvc.KindBitbucketCloud:
 		return NewBitbucketCloudSource(svc, cf)
+	case extsvc.KindGitea:
+		return NewGiteaSource(svc, cf)
+	case extsvc.KindAzureDevOps:
+		return NewAzureDevOpsSource(svc, cf)
 	case extsvc.KindGitolite:
 		return NewGitoliteSource(svc, cf)
 	case extsvc.KindPhabricator:
@@ -77,8 +81,9 @@ type Source interface {
 	ExternalServices() types.ExternalServices
 }
 
-// RepoGetter captures the optional GetRepo method of a Source. It's used only
-// on sourcegraph.com to lazily sync individual repos.
+// RepoGetter captures the optional GetRepo method of a Source. It's used on
+// sourcegraph.com to lazily sync individual repos and to sync single repos of
+// an external service on demand.
 type RepoGetter interface {
 	GetRepo(context.Context, string) (*types.Repo, error)
 }
@@ -573,7 +573,7 @@ WHERE NOT EXISTS (
 	JOIN external_services es ON es.id = j.external_service_id
 	WHERE j.external_service_id = %s
 	AND (
-		j.state IN ('queued', 'processing')
+		(j.state IN ('queued', 'processing') AND j.scope IS NULL)
 		OR es.cloud_default
 	)
 )
@@ -581,6 +581,25 @@ WHERE NOT EXISTS (
 	return s.Exec(ctx, q)
 }
 
+// EnqueueSingleRepoSyncJob enqueues a sync job for the given external service
+// that is scoped to the single repository with the given name. Nothing is
+// enqueued if a job for the same repository, or a sync of the whole external
+// service, is already queued.
+func (s *Store) EnqueueSingleRepoSyncJob(ctx context.Context, extSvcID int64, repo string) (err error) {
+	q := sqlf.Sprintf(`
+INSERT INTO external_service_sync_jobs (external_service_id, scope)
+SELECT %s, %s
+WHERE NOT EXISTS (
+	SELECT
+	FROM external_service_sync_jobs j
+	WHERE j.external_service_id = %s
+	AND j.state = 'queued'
+	AND (j.scope IS NULL OR j.scope = %s)
+)
+`, extSvcID, repo, extSvcID, repo)
+	return s.Exec(ctx, q)
+}
+
 // EnqueueSyncJobs enqueues sync jobs for all external services that are due.
 func (s *Store) EnqueueSyncJobs(ctx context.Context, isCloud bool) (err error) {
 	tr, ctx := s.trace(ctx, "Store.EnqueueSyncJobs")
@@ -615,8 +634,8 @@ WITH due AS (
 ),
 busy AS (
     SELECT DISTINCT external_service_id id FROM external_service_sync_jobs
-    WHERE state = 'queued'
-    OR state = 'processing'
+    WHERE (state = 'queued' OR state = 'processing')
+    AND scope IS NULL
 )
 INSERT INTO external_service_sync_jobs (external_service_id)
 SELECT id from due EXCEPT SELECT id from busy
@@ -636,7 +655,8 @@ func (s *Store) ListSyncJobs(ctx context.Context) ([]SyncJob, error) {
 			num_failures,
 			execution_logs,
 			external_service_id,
-			next_sync_at
+			next_sync_at,
+			scope
 		FROM external_service_sync_jobs_with_next_sync_at
 	`)
 	rows, err := s.Query(ctx, q)
@@ -668,6 +688,7 @@ func scanJobs(rows *sql.Rows) ([]SyncJob, error) {
 			&executionLogs,
 			&job.ExternalServiceID,
 			&job.NextSyncAt,
+			&job.Scope,
 		); err != nil {
 			return nil, err
 		}
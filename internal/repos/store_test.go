@@ -60,7 +60,7 @@ func testSyncRateLimiters(store *repos.Store) func(*testing.T) {
 			}
 
 			registry := ratelimit.NewRegistry()
-			syncer := repos.NewRateLimitSyncer(registry, tx.ExternalServiceStore)
+			syncer := repos.NewRateLimitSyncer(registry, ratelimit.NewConcurrencyRegistry(), tx.ExternalServiceStore)
 			err := syncer.SyncRateLimiters(ctx)
 			if err != nil {
 				t.Fatal(err)
@@ -278,6 +278,79 @@ func testStoreEnqueueSingleSyncJob(store *repos.Store) func(*testing.T) {
 	}
 }
 
+func testStoreEnqueueSingleRepoSyncJob(store *repos.Store) func(*testing.T) {
+	return func(t *testing.T) {
+		clock := timeutil.NewFakeClock(time.Now(), 0)
+		now := clock.Now()
+
+		ctx := context.Background()
+		t.Cleanup(func() {
+			if err := store.Exec(ctx, sqlf.Sprintf("DELETE FROM external_service_sync_jobs;DELETE FROM external_services")); err != nil {
+				t.Fatal(err)
+			}
+		})
+		service := types.ExternalService{
+			Kind:        extsvc.KindGitHub,
+			DisplayName: "Github - Test",
+			Config:      `{"url": "https://github.com", "repositoryQuery": ["none"], "token": "abc"}`,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+
+		confGet := func() *conf.Unified {
+			return &conf.Unified{}
+		}
+		err := database.ExternalServicesWith(store).Create(ctx, confGet, &service)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertCount := func(t *testing.T, want int) {
+			t.Helper()
+			var count int
+			if err := store.QueryRow(ctx, sqlf.Sprintf("SELECT COUNT(*) FROM external_service_sync_jobs")).Scan(&count); err != nil {
+				t.Fatal(err)
+			}
+			if count != want {
+				t.Fatalf("Expected %d rows, got %d", want, count)
+			}
+		}
+
+		for _, repo := range []string{"foo/bar", "foo/bar", "foo/baz"} {
+			if err = store.EnqueueSingleRepoSyncJob(ctx, service.ID, repo); err != nil {
+				t.Fatal(err)
+			}
+		}
+		// Only one job is queued per repo
+		assertCount(t, 2)
+
+		jobs, err := store.ListSyncJobs(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, j := range jobs {
+			if !j.Scope.Valid {
+				t.Fatalf("Expected job %d to be scoped to a single repo", j.ID)
+			}
+		}
+
+		// Queued single repo jobs don't block syncing the whole external service
+		if err = store.EnqueueSingleSyncJob(ctx, service.ID); err != nil {
+			t.Fatal(err)
+		}
+		assertCount(t, 3)
+
+		// A queued sync of the whole external service syncs the repo anyway
+		if err = store.Exec(ctx, sqlf.Sprintf("DELETE FROM external_service_sync_jobs WHERE scope IS NOT NULL")); err != nil {
+			t.Fatal(err)
+		}
+		if err = store.EnqueueSingleRepoSyncJob(ctx, service.ID, "foo/bar"); err != nil {
+			t.Fatal(err)
+		}
+		assertCount(t, 1)
+	}
+}
+
 func testStoreListExternalServiceUserIDsByRepoID(store *repos.Store) func(*testing.T) {
 	return func(t *testing.T) {
 		ctx := context.Background()
@@ -59,6 +59,7 @@ func NewSyncWorker(ctx context.Context, db dbutil.DB, handler workerutil.Handler
 		sqlf.Sprintf("execution_logs"),
 		sqlf.Sprintf("external_service_id"),
 		sqlf.Sprintf("next_sync_at"),
+		sqlf.Sprintf("scope"),
 	}
 
 	store := store.New(dbHandle, store.Options{
@@ -143,6 +144,17 @@ WHERE
 			log15.Error("error while running job cleaner", "err", err)
 		}
 
+		// Sync stats outlive their jobs, so that sync statistics can be
+		// aggregated over longer time windows.
+		_, err = db.ExecContext(ctx, `
+-- source: internal/repos/sync_worker.go:runJobCleaner
+DELETE FROM external_service_sync_stats
+WHERE finished_at < now() - INTERVAL '30 days'
+`)
+		if err != nil && err != context.Canceled {
+			log15.Error("error while cleaning up sync stats", "err", err)
+		}
+
 		select {
 		case <-ctx.Done():
 			return
@@ -176,6 +188,9 @@ type SyncJob struct {
 	NumFailures       int
 	ExternalServiceID int64
 	NextSyncAt        sql.NullTime
+	// Scope is the name of the single repository to sync. If it's not set,
+	// the whole external service is synced.
+	Scope sql.NullString
 }
 
 // RecordID implements workerutil.Record and indicates the queued item id
@@ -2,12 +2,15 @@ package repos
 
 import (
 	"context"
+	"fmt"
 	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/cockroachdb/errors"
+	"github.com/graph-gophers/graphql-go/relay"
 	"github.com/hashicorp/go-multierror"
 	"github.com/inconshreveable/log15"
 	"github.com/prometheus/client_golang/prometheus"
@@ -18,6 +21,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
@@ -50,6 +55,10 @@ type Syncer struct {
 	// If zero, we'll read from config instead.
 	UserReposMaxPerSite int
 
+	// RepoDeletionThresholdPercent can be used to override the value read from
+	// config. If zero, we'll read from config instead.
+	RepoDeletionThresholdPercent int
+
 	// Ensure that we only run one sync per repo at a time
 	syncGroup singleflight.Group
 }
@@ -120,6 +129,10 @@ func (s *syncHandler) Handle(ctx context.Context, record workerutil.Record) (err
 		return errors.Errorf("expected repos.SyncJob, got %T", record)
 	}
 
+	if sj.Scope.Valid {
+		return s.syncer.SyncExternalServiceRepo(ctx, sj.ExternalServiceID, sj.Scope.String)
+	}
+
 	return s.syncer.SyncExternalService(ctx, sj.ExternalServiceID, s.minSyncInterval())
 }
 
@@ -137,6 +150,13 @@ func (s *Syncer) TriggerExternalServiceSync(ctx context.Context, id int64) error
 	return s.Store.EnqueueSingleSyncJob(ctx, id)
 }
 
+// TriggerExternalServiceRepoSync will enqueue a sync job for the single
+// repository with the given path on the code host of the supplied external
+// service.
+func (s *Syncer) TriggerExternalServiceRepoSync(ctx context.Context, id int64, repo string) error {
+	return s.Store.EnqueueSingleRepoSyncJob(ctx, id, repo)
+}
+
 type externalServiceOwnerType string
 
 const (
@@ -414,6 +434,13 @@ func (s *Syncer) SyncExternalService(
 		return err
 	}
 
+	stats := types.ExternalServiceSyncStats{
+		ExternalServiceID: svc.ID,
+		StartedAt:         s.Now(),
+	}
+	var apiRequests int64
+	ctx = httpcli.WithRequestCounter(ctx, &apiRequests)
+
 	results := make(chan SourceResult)
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -440,6 +467,7 @@ func (s *Syncer) SyncExternalService(
 				"svc", svc.DisplayName, "id", svc.ID, "seen", len(seen), "error", err)
 
 			multierror.Append(errs, errors.Wrapf(err, "fetching from code host %s", svc.DisplayName))
+			stats.ReposErrored++
 
 			if fatal(err) {
 				// Delete all external service repos of this external service
@@ -459,9 +487,12 @@ func (s *Syncer) SyncExternalService(
 		if diff, err = s.sync(ctx, svc, sourced); err != nil {
 			s.log().Error("failed to sync, skipping", "repo", sourced.Name, "err", err)
 			multierror.Append(errs, err)
+			stats.ReposErrored++
 			continue
 		}
 
+		stats.ReposAdded += len(diff.Added)
+
 		for _, r := range diff.Repos() {
 			seen[r.ID] = struct{}{}
 		}
@@ -483,24 +514,32 @@ func (s *Syncer) SyncExternalService(
 		// spurious errors since that could cause lots of repos to be deleted, only to be
 		// added the next sync. We delete only if we had no errors or we had one of the
 		// fatal errors.
-		var deletedErr error
-		deleted, deletedErr = s.delete(ctx, svc, seen)
-		if deletedErr != nil {
-			s.log().Warn("syncer: failed to delete some repos",
-				"svc", svc.DisplayName, "id", svc.ID, "seen", len(seen), "error", deletedErr, "deleted", deleted)
+		if protectErr := s.checkDeletionThreshold(ctx, svc, len(seen)); protectErr != nil {
+			s.log().Warn("syncer: not deleting not seen repos",
+				"svc", svc.DisplayName, "id", svc.ID, "seen", len(seen), "error", protectErr)
 
-			multierror.Append(errs, errors.Wrap(deletedErr, "some repos couldn't be deleted"))
-		}
+			multierror.Append(errs, protectErr)
+		} else {
+			var deletedErr error
+			deleted, deletedErr = s.delete(ctx, svc, seen)
+			if deletedErr != nil {
+				s.log().Warn("syncer: failed to delete some repos",
+					"svc", svc.DisplayName, "id", svc.ID, "seen", len(seen), "error", deletedErr, "deleted", deleted)
 
-		if deleted > 0 {
-			s.log().Warn("syncer: deleted not seen repos",
-				"svc", svc.DisplayName, "id", svc.ID, "seen", len(seen), "deleted", deleted, "error", err)
+				multierror.Append(errs, errors.Wrap(deletedErr, "some repos couldn't be deleted"))
+			}
+
+			if deleted > 0 {
+				s.log().Warn("syncer: deleted not seen repos",
+					"svc", svc.DisplayName, "id", svc.ID, "seen", len(seen), "deleted", deleted, "error", err)
+			}
 		}
 	}
 
 	now := s.Now()
 	modified = modified || deleted > 0
 	interval := calcSyncInterval(now, svc.LastSyncAt, minSyncInterval, modified, errs.ErrorOrNil())
+	stats.BackoffReason = syncBackoffReason(svc.LastSyncAt, modified, errs.ErrorOrNil())
 
 	s.log().Debug("Synced external service", "id", externalServiceID, "backoff duration", interval)
 	svc.NextSyncAt = now.Add(interval)
@@ -511,9 +550,157 @@ func (s *Syncer) SyncExternalService(
 		multierror.Append(errs, errors.Wrap(err, "upserting external service"))
 	}
 
+	stats.FinishedAt = now
+	stats.ReposRemoved = deleted
+	stats.APIRequests = int(atomic.LoadInt64(&apiRequests))
+	stats.Errored = errs.ErrorOrNil() != nil
+	if err := s.Store.ExternalServiceStore.RecordSyncStats(ctx, &stats); err != nil {
+		// The stats are only used for monitoring, so they don't fail the sync.
+		s.log().Warn("syncer: failed to record sync stats", "svc", svc.DisplayName, "id", svc.ID, "error", err)
+	}
+
+	if err := errs.ErrorOrNil(); err != nil {
+		if notifyErr := s.notifySyncFailure(ctx, svc, err); notifyErr != nil {
+			s.log().Warn("syncer: failed to notify about sync failure", "svc", svc.DisplayName, "id", svc.ID, "error", notifyErr)
+		}
+	}
+
 	return errs.ErrorOrNil()
 }
 
+// notifySyncFailure notifies the owners of the external service that syncing it
+// failed: the user or the members of the organization owning it, or the site admins
+// for site-level external services. Owners are notified once until they read the
+// notification, not on every failed sync.
+func (s *Syncer) notifySyncFailure(ctx context.Context, svc *types.ExternalService, syncErr error) error {
+	notifications := database.NotificationsWith(s.Store)
+	n := database.Notification{
+		Kind:      database.NotificationKindExternalServiceSyncFailed,
+		Title:     fmt.Sprintf("Syncing code host connection %q failed", svc.DisplayName),
+		Body:      syncErr.Error(),
+		DedupeKey: "external-service-sync-failed:" + strconv.FormatInt(svc.ID, 10),
+	}
+	switch {
+	case svc.NamespaceUserID != 0:
+		user, err := database.UsersWith(s.Store).GetByID(ctx, svc.NamespaceUserID)
+		if err != nil {
+			return err
+		}
+		n.UserID = user.ID
+		n.URL = "/users/" + user.Username + "/settings/code-hosts"
+		return notifications.Create(ctx, &n)
+	case svc.NamespaceOrgID != 0:
+		org, err := database.OrgsWith(s.Store).GetByID(ctx, svc.NamespaceOrgID)
+		if err != nil {
+			return err
+		}
+		n.URL = "/organizations/" + org.Name + "/settings/code-hosts"
+		return notifications.CreateForOrgMembers(ctx, org.ID, n)
+	default:
+		n.URL = "/site-admin/external-services/" + string(relay.MarshalID("ExternalService", svc.ID))
+		return notifications.CreateForSiteAdmins(ctx, n)
+	}
+}
+
+// SyncExternalServiceRepo syncs the single repository with the given path on
+// the code host of the supplied external service, e.g. "owner/name" for GitHub.
+// Unlike SyncExternalService, it doesn't delete any repos nor does it change
+// when the external service is synced next.
+func (s *Syncer) SyncExternalServiceRepo(ctx context.Context, externalServiceID int64, path string) (err error) {
+	s.log().Info("Syncing external service repo", "serviceID", externalServiceID, "path", path)
+
+	var svc *types.ExternalService
+	ctx, save := s.observeSync(ctx, "Syncer.SyncExternalServiceRepo", path)
+	defer func() { save(svc, err) }()
+
+	svc, err = s.Store.ExternalServiceStore.GetByID(ctx, externalServiceID)
+	if err != nil {
+		return errors.Wrap(err, "fetching external services")
+	}
+
+	src, err := s.Sourcer(svc)
+	if err != nil {
+		return err
+	}
+
+	rg, ok := src.(RepoGetter)
+	if !ok {
+		return errors.Errorf("can't source repo %q from external service %s", path, svc.DisplayName)
+	}
+
+	sourced, err := rg.GetRepo(ctx, path)
+	if err != nil {
+		return errors.Wrapf(err, "fetching repo %q from code host %s", path, svc.DisplayName)
+	}
+
+	// Same as in SyncExternalService, user added external services only sync
+	// public code unless they are allowed to sync private code.
+	if svc.NamespaceUserID != 0 && sourced.Private {
+		if mode, err := database.UsersWith(s.Store).UserAllowedExternalServices(ctx, svc.NamespaceUserID); err != nil {
+			return errors.Wrap(err, "checking if user can add private code")
+		} else if mode != conf.ExternalServiceModeAll {
+			return &database.RepoNotFoundErr{Name: sourced.Name}
+		}
+	}
+
+	_, err = s.sync(ctx, svc, sourced)
+	return err
+}
+
+func (s *Syncer) repoDeletionThresholdPercent() int {
+	if s.RepoDeletionThresholdPercent != 0 {
+		return s.RepoDeletionThresholdPercent
+	}
+	return conf.Get().RepoDeletionThresholdPercent
+}
+
+// checkDeletionThreshold returns an error if deleting all but the seen repos of
+// the site-level external service svc would delete a larger share of its repos
+// than the deletion threshold allows, and a site admin hasn't confirmed the
+// deletion. This protects against wiping repos because of a token that was
+// replaced by one with less permissions.
+func (s *Syncer) checkDeletionThreshold(ctx context.Context, svc *types.ExternalService, seen int) error {
+	threshold := s.repoDeletionThresholdPercent()
+	if threshold <= 0 || threshold >= 100 || svc.NamespaceUserID != 0 || svc.NamespaceOrgID != 0 {
+		return nil
+	}
+
+	total, err := s.Store.ExternalServiceStore.RepoCount(ctx, svc.ID)
+	if err != nil {
+		return errors.Wrap(err, "counting repos for deletion threshold")
+	}
+
+	toDelete := int(total) - seen
+	if toDelete <= 0 || toDelete*100 <= threshold*int(total) {
+		return nil
+	}
+
+	confirmed, err := s.Store.ExternalServiceStore.ConsumeRepoDeletionConfirmation(ctx, svc.ID)
+	if err != nil {
+		return errors.Wrap(err, "checking repo deletion confirmation")
+	}
+	if confirmed {
+		return nil
+	}
+
+	return &ErrDeletionThresholdExceeded{Deleted: toDelete, Total: int(total), ThresholdPercent: threshold}
+}
+
+// ErrDeletionThresholdExceeded is returned by a sync that didn't delete any
+// repos because it would have deleted more than the deletion threshold allows.
+type ErrDeletionThresholdExceeded struct {
+	Deleted          int
+	Total            int
+	ThresholdPercent int
+}
+
+func (e *ErrDeletionThresholdExceeded) Error() string {
+	return fmt.Sprintf(
+		"sync would delete %d of %d repositories, more than the deletion threshold of %d%%: no repositories were deleted. A site admin must confirm the deletion for the next sync to delete them",
+		e.Deleted, e.Total, e.ThresholdPercent,
+	)
+}
+
 func (s *Syncer) userReposMaxPerSite() uint64 {
 	if n := uint64(s.UserReposMaxPerSite); n > 0 {
 		return n
@@ -583,6 +770,7 @@ func (s *Syncer) sync(ctx context.Context, svc *types.ExternalService, sourced *
 		stored = types.Repos{existing}
 		fallthrough
 	case 1: // Existing repo, update.
+		oldName := stored[0].Name
 		if !stored[0].Update(sourced) {
 			d.Unmodified = append(d.Unmodified, stored[0])
 			break
@@ -592,6 +780,13 @@ func (s *Syncer) sync(ctx context.Context, svc *types.ExternalService, sourced *
 			return Diff{}, errors.Wrap(err, "syncer: failed to update external service repo")
 		}
 
+		if oldName != stored[0].Name {
+			// Keep links to the old name working once the code host stops redirecting it.
+			if err = database.RedirectsWith(tx).Create(ctx, database.RedirectKindRepo, string(oldName), string(stored[0].Name), "renamed on the code host"); err != nil {
+				return Diff{}, errors.Wrap(err, "syncer: failed to record repo rename")
+			}
+		}
+
 		d.Modified = append(d.Modified, stored[0])
 	case 0: // New repo, create.
 		if svc.NamespaceUserID != 0 { // enforce user repo limits
@@ -706,6 +901,23 @@ func calcSyncInterval(
 	return interval
 }
 
+// syncBackoffReason returns why calcSyncInterval backs off from syncing an
+// external service, or an empty reason if it doesn't.
+func syncBackoffReason(lastSync time.Time, modified bool, err error) types.ExternalServiceSyncBackoffReason {
+	switch {
+	case err == nil && (lastSync.IsZero() || modified):
+		return ""
+	case err == nil:
+		return types.ExternalServiceSyncBackoffUnchanged
+	case github.IsRateLimitExceeded(err):
+		return types.ExternalServiceSyncBackoffRateLimited
+	case errcode.IsUnauthorized(err) || errcode.IsForbidden(err) || errcode.IsAccountSuspended(err):
+		return types.ExternalServiceSyncBackoffUnauthorized
+	default:
+		return types.ExternalServiceSyncBackoffError
+	}
+}
+
 func (s *Syncer) observeSync(
 	ctx context.Context,
 	family, title string,
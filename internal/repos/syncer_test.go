@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cockroachdb/errors"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/keegancsmith/sqlf"
@@ -1730,6 +1731,87 @@ func testDeleteExternalService(store *repos.Store) func(*testing.T) {
 	}
 }
 
+func testDeletionThreshold(store *repos.Store) func(*testing.T) {
+	return func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		now := time.Now()
+
+		svc := &types.ExternalService{
+			Kind:        extsvc.KindGitHub,
+			DisplayName: "Github - Test",
+			Config:      `{"url": "https://github.com"}`,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		if err := store.ExternalServiceStore.Upsert(ctx, svc); err != nil {
+			t.Fatal(err)
+		}
+
+		newRepo := func(name string) *types.Repo {
+			return &types.Repo{
+				Name:     api.RepoName("github.com/org/" + name),
+				Metadata: &github.Repository{},
+				ExternalRepo: api.ExternalRepoSpec{
+					ID:          name + "-external",
+					ServiceID:   "https://github.com/",
+					ServiceType: extsvc.TypeGitHub,
+				},
+			}
+		}
+
+		var sourced []*types.Repo
+		syncer := &repos.Syncer{
+			Sourcer: func(service *types.ExternalService) (repos.Source, error) {
+				return repos.NewFakeSource(svc, nil, sourced...), nil
+			},
+			Store:                        store,
+			Now:                          time.Now,
+			RepoDeletionThresholdPercent: 50,
+		}
+
+		sourced = []*types.Repo{newRepo("foo"), newRepo("bar"), newRepo("baz")}
+		if err := syncer.SyncExternalService(ctx, svc.ID, 10*time.Second); err != nil {
+			t.Fatal(err)
+		}
+
+		// Deleting one of three repos is below the threshold.
+		sourced = sourced[:2]
+		if err := syncer.SyncExternalService(ctx, svc.ID, 10*time.Second); err != nil {
+			t.Fatal(err)
+		}
+		assertSourceCount(ctx, t, store, 2)
+
+		// Deleting both remaining repos exceeds it.
+		sourced = nil
+		err := syncer.SyncExternalService(ctx, svc.ID, 10*time.Second)
+		var thresholdErr *repos.ErrDeletionThresholdExceeded
+		if !errors.As(err, &thresholdErr) {
+			t.Fatalf("expected deletion threshold error, got %v", err)
+		}
+		assertSourceCount(ctx, t, store, 2)
+
+		// Once confirmed, the next sync deletes them.
+		if err := store.ExternalServiceStore.ConfirmRepoDeletion(ctx, svc.ID); err != nil {
+			t.Fatal(err)
+		}
+		if err := syncer.SyncExternalService(ctx, svc.ID, 10*time.Second); err != nil {
+			t.Fatal(err)
+		}
+		assertSourceCount(ctx, t, store, 0)
+
+		// The confirmation only applies to a single sync.
+		confirmed, err := store.ExternalServiceStore.ConsumeRepoDeletionConfirmation(ctx, svc.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if confirmed {
+			t.Fatal("confirmation was not reset")
+		}
+	}
+}
+
 func assertSourceCount(ctx context.Context, t *testing.T, store *repos.Store, want int) {
 	t.Helper()
 	var rowCount int
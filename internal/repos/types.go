@@ -18,29 +18,34 @@ type externalServiceLister interface {
 	List(context.Context, database.ExternalServicesListOptions) ([]*types.ExternalService, error)
 }
 
-// RateLimitSyncer syncs rate limits based on external service configuration
+// RateLimitSyncer syncs rate limits and concurrency limits based on external
+// service configuration
 type RateLimitSyncer struct {
-	registry      *ratelimit.Registry
-	serviceLister externalServiceLister
+	registry            *ratelimit.Registry
+	concurrencyRegistry *ratelimit.ConcurrencyRegistry
+	serviceLister       externalServiceLister
 	// How many services to fetch in each DB call
 	limit int64
 }
 
 // NewRateLimitSyncer returns a new syncer
-func NewRateLimitSyncer(registry *ratelimit.Registry, serviceLister externalServiceLister) *RateLimitSyncer {
+func NewRateLimitSyncer(registry *ratelimit.Registry, concurrencyRegistry *ratelimit.ConcurrencyRegistry, serviceLister externalServiceLister) *RateLimitSyncer {
 	r := &RateLimitSyncer{
-		registry:      registry,
-		serviceLister: serviceLister,
-		limit:         500,
+		registry:            registry,
+		concurrencyRegistry: concurrencyRegistry,
+		serviceLister:       serviceLister,
+		limit:               500,
 	}
 	return r
 }
 
-// SyncRateLimiters syncs all rate limiters using current config.
+// SyncRateLimiters syncs all rate limiters and concurrency limiters using current config.
 // We sync them all as we need to pick the most restrictive configured limit per code host
 // and rate limits can be defined in multiple external services for the same host.
 func (r *RateLimitSyncer) SyncRateLimiters(ctx context.Context) error {
 	byURL := make(map[string]extsvc.RateLimitConfig)
+	// Most restrictive concurrency limit per code host, zero meaning unlimited.
+	maxConcurrent := make(map[string]int)
 
 	cursor := database.LimitOffset{
 		Limit: int(r.limit),
@@ -70,6 +75,12 @@ func (r *RateLimitSyncer) SyncRateLimiters(ctx context.Context) error {
 				return errors.Wrap(err, "getting rate limit configuration")
 			}
 
+			// Use the lowest concurrency limit, where zero means unlimited.
+			lowest, ok := maxConcurrent[rlc.BaseURL]
+			if n := rlc.MaxConcurrentRequests; !ok || (n > 0 && (lowest == 0 || n < lowest)) {
+				maxConcurrent[rlc.BaseURL] = n
+			}
+
 			current, ok := byURL[rlc.BaseURL]
 			if !ok || (ok && current.IsDefault) {
 				byURL[rlc.BaseURL] = rlc
@@ -92,6 +103,10 @@ func (r *RateLimitSyncer) SyncRateLimiters(ctx context.Context) error {
 		l.SetLimit(rl.Limit)
 	}
 
+	for u, n := range maxConcurrent {
+		r.concurrencyRegistry.Get(u).SetLimit(n)
+	}
+
 	return nil
 }
 
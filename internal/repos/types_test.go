@@ -61,9 +61,10 @@ func TestSyncRateLimiters(t *testing.T) {
 	baseURL := "http://gitlab.com/"
 
 	type limitOptions struct {
-		includeLimit bool
-		enabled      bool
-		perHour      float64
+		includeLimit  bool
+		enabled       bool
+		perHour       float64
+		maxConcurrent int
 	}
 
 	makeLister := func(options ...limitOptions) *MockExternalServicesLister {
@@ -82,8 +83,9 @@ func TestSyncRateLimiters(t *testing.T) {
 			}
 			if o.includeLimit {
 				config.RateLimit = &schema.GitLabRateLimit{
-					RequestsPerHour: o.perHour,
-					Enabled:         o.enabled,
+					RequestsPerHour:       o.perHour,
+					Enabled:               o.enabled,
+					MaxConcurrentRequests: o.maxConcurrent,
 				}
 			}
 			data, err := json.Marshal(config)
@@ -101,9 +103,10 @@ func TestSyncRateLimiters(t *testing.T) {
 	}
 
 	for _, tc := range []struct {
-		name    string
-		options []limitOptions
-		want    rate.Limit
+		name           string
+		options        []limitOptions
+		want           rate.Limit
+		wantConcurrent int
 	}{
 		{
 			name:    "No limiters defined",
@@ -211,13 +214,63 @@ func TestSyncRateLimiters(t *testing.T) {
 			},
 			want: rate.Limit(20),
 		},
+		{
+			name: "Concurrency limit",
+			options: []limitOptions{
+				{
+					includeLimit:  true,
+					enabled:       true,
+					perHour:       3600,
+					maxConcurrent: 4,
+				},
+			},
+			want:           rate.Limit(1),
+			wantConcurrent: 4,
+		},
+		{
+			name: "Two concurrency limits, one unlimited",
+			options: []limitOptions{
+				{
+					includeLimit: false,
+				},
+				{
+					includeLimit:  true,
+					enabled:       true,
+					perHour:       3600,
+					maxConcurrent: 8,
+				},
+				{
+					includeLimit:  true,
+					enabled:       true,
+					perHour:       3600,
+					maxConcurrent: 4,
+				},
+			},
+			want:           rate.Limit(1),
+			wantConcurrent: 4,
+		},
+		{
+			name: "Concurrency limit, disabled",
+			options: []limitOptions{
+				{
+					includeLimit:  true,
+					enabled:       false,
+					perHour:       3600,
+					maxConcurrent: 4,
+				},
+			},
+			want:           rate.Inf,
+			wantConcurrent: 0,
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			reg := ratelimit.NewRegistry()
+			concurrencyReg := ratelimit.NewConcurrencyRegistry()
 			r := &RateLimitSyncer{
-				registry:      reg,
-				serviceLister: makeLister(tc.options...),
-				limit:         10,
+				registry:            reg,
+				concurrencyRegistry: concurrencyReg,
+				serviceLister:       makeLister(tc.options...),
+				limit:               10,
 			}
 
 			err := r.SyncRateLimiters(ctx)
@@ -233,6 +286,10 @@ func TestSyncRateLimiters(t *testing.T) {
 			if l.Limit() != tc.want {
 				t.Fatalf("Expected limit %f, got %f", tc.want, l.Limit())
 			}
+
+			if have := concurrencyReg.Get(baseURL).Limit(); have != tc.wantConcurrent {
+				t.Fatalf("Expected concurrency limit %d, got %d", tc.wantConcurrent, have)
+			}
 		})
 	}
 }
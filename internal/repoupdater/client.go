@@ -270,6 +270,42 @@ func (c *Client) SyncExternalService(
 	return &result, nil
 }
 
+// MockSyncExternalServiceRepo mocks (*Client).SyncExternalServiceRepo for tests.
+var MockSyncExternalServiceRepo func(ctx context.Context, externalServiceID int64, repo string) error
+
+// SyncExternalServiceRepo requests the single repository with the given path on
+// the code host of the given external service to be synced. The sync happens
+// asynchronously, this only enqueues it.
+func (c *Client) SyncExternalServiceRepo(ctx context.Context, externalServiceID int64, repo string) error {
+	if MockSyncExternalServiceRepo != nil {
+		return MockSyncExternalServiceRepo(ctx, externalServiceID, repo)
+	}
+
+	req := protocol.ExternalServiceRepoSyncRequest{ExternalServiceID: externalServiceID, Repo: repo}
+	resp, err := c.httpPost(ctx, "sync-external-service-repo", req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read response body")
+	}
+
+	var res protocol.ExternalServiceRepoSyncResponse
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return errors.New(string(bs))
+	} else if err = json.Unmarshal(bs, &res); err != nil {
+		return err
+	}
+
+	if res.Error == "" {
+		return nil
+	}
+	return errors.New(res.Error)
+}
+
 // RepoExternalServices requests the external services associated with a
 // repository with the given id.
 func (c *Client) RepoExternalServices(ctx context.Context, id api.RepoID) ([]api.ExternalService, error) {
@@ -9,8 +9,10 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/types"
@@ -173,6 +175,33 @@ func NewRepoInfo(r *types.Repo) *RepoInfo {
 			Blob:   pathAppend(href, "/src/{rev}/{path}"),
 			Commit: pathAppend(href, "/commits/{commit}"),
 		}
+	case extsvc.TypeGitea:
+		repo := r.Metadata.(*gitea.Repo)
+		if repo.HTMLURL == "" {
+			break
+		}
+
+		// Gitea redirects /src/{rev} to the branch, tag or commit named rev.
+		info.Links = &RepoLinks{
+			Root:   repo.HTMLURL,
+			Tree:   pathAppend(repo.HTMLURL, "/src/{rev}/{path}"),
+			Blob:   pathAppend(repo.HTMLURL, "/src/{rev}/{path}"),
+			Commit: pathAppend(repo.HTMLURL, "/commit/{commit}"),
+		}
+	case extsvc.TypeAzureDevOps:
+		repo := r.Metadata.(*azuredevops.Repository)
+		if repo.WebURL == "" {
+			break
+		}
+
+		// Azure DevOps addresses branches with a "GB" version prefix. Links
+		// for commit revisions therefore only resolve on the default branch.
+		info.Links = &RepoLinks{
+			Root:   repo.WebURL,
+			Tree:   pathAppend(repo.WebURL, "?path=/{path}&version=GB{rev}"),
+			Blob:   pathAppend(repo.WebURL, "?path=/{path}&version=GB{rev}"),
+			Commit: pathAppend(repo.WebURL, "/commit/{commit}"),
+		}
 	case extsvc.TypeAWSCodeCommit:
 		repo := r.Metadata.(*awscodecommit.Repository)
 		if repo.ARN == "" {
@@ -277,3 +306,21 @@ type ExternalServiceSyncResult struct {
 	ExternalService api.ExternalService
 	Error           string
 }
+
+// ExternalServiceRepoSyncRequest is a request to sync a single repository of a
+// specific external service, rather than the whole external service.
+//
+// It's issued by site admins to quickly fix a repository that is missing or out
+// of date without waiting for the next sync of the whole external service.
+type ExternalServiceRepoSyncRequest struct {
+	ExternalServiceID int64
+	// Repo is the path of the repository on the code host, e.g. "owner/name"
+	// for GitHub.
+	Repo string
+}
+
+// ExternalServiceRepoSyncResponse is a response to sync a single repository of
+// an external service.
+type ExternalServiceRepoSyncResponse struct {
+	Error string
+}
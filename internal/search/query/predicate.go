@@ -31,6 +31,7 @@ var DefaultPredicateRegistry = PredicateRegistry{
 		"contains.file":         func() Predicate { return &RepoContainsFilePredicate{} },
 		"contains.content":      func() Predicate { return &RepoContainsContentPredicate{} },
 		"contains.commit.after": func() Predicate { return &RepoContainsCommitAfterPredicate{} },
+		"has.meta":              func() Predicate { return &RepoHasMetaPredicate{} },
 	},
 	FieldFile: {
 		"contains.content": func() Predicate { return &FileContainsContentPredicate{} },
@@ -261,6 +262,61 @@ func (f *RepoContainsCommitAfterPredicate) Plan(parent Basic) (Plan, error) {
 	return ToPlan(Dnf(nodes))
 }
 
+/* repo:has.meta(...) */
+
+// RepoKVPFilter matches the repositories that have the key-value pair with the
+// given key. If Value is non-nil, the value of the pair must be equal to it.
+type RepoKVPFilter struct {
+	Key   string
+	Value *string
+}
+
+// ParseRepoKVPFilter parses a filter of the form "key:value", or "key" to match
+// any value of the key.
+func ParseRepoKVPFilter(s string) RepoKVPFilter {
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) == 1 {
+		return RepoKVPFilter{Key: parts[0]}
+	}
+	return RepoKVPFilter{Key: parts[0], Value: &parts[1]}
+}
+
+func (f RepoKVPFilter) String() string {
+	if f.Value == nil {
+		return f.Key
+	}
+	return f.Key + ":" + *f.Value
+}
+
+type RepoHasMetaPredicate struct {
+	RepoKVPFilter
+}
+
+func (f *RepoHasMetaPredicate) ParseParams(params string) error {
+	filter := ParseRepoKVPFilter(strings.TrimSpace(params))
+	if filter.Key == "" {
+		return errors.New("repo:has.meta argument should be of the form key:value or key")
+	}
+	f.RepoKVPFilter = filter
+	return nil
+}
+
+func (f RepoHasMetaPredicate) Field() string { return FieldRepo }
+func (f RepoHasMetaPredicate) Name() string  { return "has.meta" }
+func (f *RepoHasMetaPredicate) Plan(parent Basic) (Plan, error) {
+	nodes := make([]Node, 0, 3)
+	nodes = append(nodes, Parameter{
+		Field: FieldCount,
+		Value: "99999",
+	}, Parameter{
+		Field: FieldRepoHasKVP,
+		Value: f.RepoKVPFilter.String(),
+	})
+
+	nodes = append(nodes, nonPredicateRepos(parent)...)
+	return ToPlan(Dnf(nodes))
+}
+
 type FileContainsContentPredicate struct {
 	Pattern string
 }
@@ -56,6 +56,48 @@ func TestRepoContainsPredicate(t *testing.T) {
 	})
 }
 
+func TestRepoHasMetaPredicate(t *testing.T) {
+	t.Run("ParseParams", func(t *testing.T) {
+		value := func(s string) *string { return &s }
+
+		valid := []struct {
+			name     string
+			params   string
+			expected *RepoHasMetaPredicate
+		}{
+			{`key and value`, `team:search`, &RepoHasMetaPredicate{RepoKVPFilter{Key: "team", Value: value("search")}}},
+			{`key only`, `team`, &RepoHasMetaPredicate{RepoKVPFilter{Key: "team"}}},
+			{`empty value`, `team:`, &RepoHasMetaPredicate{RepoKVPFilter{Key: "team", Value: value("")}}},
+			{`value with colon`, `url:https://example.com`, &RepoHasMetaPredicate{RepoKVPFilter{Key: "url", Value: value("https://example.com")}}},
+		}
+
+		for _, tc := range valid {
+			t.Run(tc.name, func(t *testing.T) {
+				p := &RepoHasMetaPredicate{}
+				if err := p.ParseParams(tc.params); err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				if !reflect.DeepEqual(tc.expected, p) {
+					t.Fatalf("expected %#v, got %#v", tc.expected, p)
+				}
+				if have := p.RepoKVPFilter.String(); have != tc.params {
+					t.Fatalf("expected filter to round-trip to %q, got %q", tc.params, have)
+				}
+			})
+		}
+
+		for _, params := range []string{``, `:search`} {
+			t.Run(params, func(t *testing.T) {
+				p := &RepoHasMetaPredicate{}
+				if err := p.ParseParams(params); err == nil {
+					t.Fatal("expected error but got none")
+				}
+			})
+		}
+	})
+}
+
 func TestParseAsPredicate(t *testing.T) {
 	tests := []struct {
 		input  string
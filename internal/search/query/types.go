@@ -337,6 +337,15 @@ func (q Q) Repositories() (repos []string, negatedRepos []string) {
 	return repos, negatedRepos
 }
 
+// RepoHasKVPs returns the key-value pair filters of the repohasmeta fields of
+// the query, as generated by the repo:has.meta() predicate.
+func (q Q) RepoHasKVPs() (filters []RepoKVPFilter) {
+	VisitField(q, FieldRepoHasKVP, func(value string, _ bool, _ Annotation) {
+		filters = append(filters, ParseRepoKVPFilter(value))
+	})
+	return filters
+}
+
 func parseRegexpOrPanic(field, value string) *regexp.Regexp {
 	r, err := regexp.Compile(value)
 	if err != nil {
@@ -398,6 +407,7 @@ func (q Q) valueToTypedValue(field, value string, label labels) []*Value {
 
 	case
 		FieldRepoHasCommitAfter,
+		FieldRepoHasKVP,
 		FieldBefore, "until",
 		FieldAfter, "since":
 		return []*Value{{String: &value}}
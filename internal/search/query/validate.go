@@ -323,6 +323,9 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 	case
 		FieldRepoHasCommitAfter:
 		return satisfies(isSingular, isNotNegated)
+	case
+		FieldRepoHasKVP:
+		return satisfies(isNotNegated)
 	case
 		FieldBefore,
 		FieldAfter:
@@ -40,6 +40,10 @@ type Resolved struct {
 	MissingRepoRevs []*search.RepositoryRevisions
 	ExcludedRepos   ExcludedRepos
 	OverLimit       bool
+
+	// Next is the first repository of the next page if the repositories were paginated and
+	// there are more repositories.
+	Next api.RepoName
 }
 
 func (r *Resolved) String() string {
@@ -71,6 +75,9 @@ func (r *Resolver) Resolve(ctx context.Context, op search.RepoOptions) (Resolved
 	if limit == 0 {
 		limit = search.SearchLimits(conf.Get()).MaxRepos
 	}
+	if op.Pagination != nil {
+		limit = op.Pagination.Limit
+	}
 
 	// If any repo groups are specified, take the intersection of the repo
 	// groups and the set of repos specified with repo:. (If none are specified
@@ -88,7 +95,7 @@ func (r *Resolver) Resolve(ctx context.Context, op search.RepoOptions) (Resolved
 
 		// Ensure we don't omit any repos explicitly included via a repo group. (Each explicitly
 		// listed repo generates at least one pattern.)
-		if numPatterns > limit {
+		if numPatterns > limit && op.Pagination == nil {
 			limit = numPatterns
 		}
 	}
@@ -107,7 +114,7 @@ func (r *Resolver) Resolve(ctx context.Context, op search.RepoOptions) (Resolved
 
 	var searchableRepos []types.RepoName
 
-	if envvar.SourcegraphDotComMode() && len(includePatterns) == 0 && !query.HasTypeRepo(op.Query) && searchcontexts.IsGlobalSearchContext(searchContext) {
+	if envvar.SourcegraphDotComMode() && len(includePatterns) == 0 && len(op.HasKVPs) == 0 && !query.HasTypeRepo(op.Query) && searchcontexts.IsGlobalSearchContext(searchContext) && op.Pagination == nil {
 		start := time.Now()
 		searchableRepos, err = searchableRepositories(ctx, r.SearchableReposFunc, excludePatterns)
 		if err != nil {
@@ -144,6 +151,10 @@ func (r *Resolver) Resolve(ctx context.Context, op search.RepoOptions) (Resolved
 			OnlyPrivate:  op.Visibility == query.Private,
 		}
 
+		for _, kvp := range op.HasKVPs {
+			options.KVPFilters = append(options.KVPFilters, database.RepoKVPFilter{Key: kvp.Key, Value: kvp.Value})
+		}
+
 		if searchContext.ID != 0 {
 			options.SearchContextID = searchContext.ID
 		} else if searchContext.NamespaceUserID != 0 {
@@ -151,7 +162,12 @@ func (r *Resolver) Resolve(ctx context.Context, op search.RepoOptions) (Resolved
 			options.IncludeUserPublicRepos = true
 		}
 
-		if op.Ranked {
+		if op.Pagination != nil {
+			options.OrderBy = database.RepoListOrderBy{{Field: database.RepoListName}}
+			options.CursorColumn = string(database.RepoListName)
+			options.CursorValue = string(op.Pagination.Start)
+			options.CursorDirection = "next"
+		} else if op.Ranked {
 			options.OrderBy = database.RepoListOrderBy{
 				{
 					Field:      database.RepoListStars,
@@ -178,6 +194,13 @@ func (r *Resolver) Resolve(ctx context.Context, op search.RepoOptions) (Resolved
 			return Resolved{}, err
 		}
 	}
+	var next api.RepoName
+	if op.Pagination != nil && len(repos) > limit {
+		// The extra repository is the first one of the next page, not a repository omitted
+		// because of the limit.
+		next = repos[limit].Name
+		repos = repos[:limit]
+	}
 	overLimit := len(repos) > limit
 	repoRevs := make([]*search.RepositoryRevisions, 0, len(repos))
 	var missingRepoRevs []*search.RepositoryRevisions
@@ -290,6 +313,7 @@ func (r *Resolver) Resolve(ctx context.Context, op search.RepoOptions) (Resolved
 		MissingRepoRevs: missingRepoRevs,
 		ExcludedRepos:   excluded,
 		OverLimit:       overLimit,
+		Next:            next,
 	}, err
 }
 
@@ -180,6 +180,52 @@ func TestRevisionValidation(t *testing.T) {
 	}
 }
 
+func TestResolvePagination(t *testing.T) {
+	all := []types.RepoName{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}, {ID: 3, Name: "c"}}
+	database.Mocks.Repos.ListRepoNames = func(ctx context.Context, opts database.ReposListOptions) ([]types.RepoName, error) {
+		if opts.CursorColumn != "name" || opts.CursorDirection != "next" {
+			t.Errorf("unexpected cursor options %+v", opts)
+		}
+		var repos []types.RepoName
+		for _, r := range all {
+			if string(r.Name) >= opts.CursorValue && len(repos) < opts.Limit {
+				repos = append(repos, r)
+			}
+		}
+		return repos, nil
+	}
+	defer func() { database.Mocks.Repos.ListRepoNames = nil }()
+
+	var pages [][]api.RepoName
+	pagination := &search.RepoPagination{Limit: 2}
+	for {
+		if len(pages) > 2 {
+			t.Fatal("too many pages")
+		}
+		resolved, err := (&Resolver{}).Resolve(context.Background(), search.RepoOptions{Pagination: pagination})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resolved.OverLimit {
+			t.Error("paginated repositories must not be over the limit")
+		}
+
+		var page []api.RepoName
+		for _, rev := range resolved.RepoRevs {
+			page = append(page, rev.Repo.Name)
+		}
+		pages = append(pages, page)
+		if resolved.Next == "" {
+			break
+		}
+		pagination = &search.RepoPagination{Start: resolved.Next, Limit: 2}
+	}
+
+	if diff := cmp.Diff([][]api.RepoName{{"a", "b"}, {"c"}}, pages); diff != "" {
+		t.Fatalf("unexpected pages (-want +got):\n%s", diff)
+	}
+}
+
 // TestSearchRevspecs tests a repository name against a list of
 // repository specs with optional revspecs, and determines whether
 // we get the expected error, list of matching rev specs, or list
@@ -58,6 +58,7 @@ func SearchRepositories(ctx context.Context, args *search.TextParameters, limit
 		query.FieldCase:               {},
 		query.FieldRepoHasFile:        {},
 		query.FieldRepoHasCommitAfter: {},
+		query.FieldRepoHasKVP:         {},
 		query.FieldPatternType:        {},
 		query.FieldSelect:             {},
 	}
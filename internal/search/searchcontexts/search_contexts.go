@@ -202,6 +202,9 @@ func UpdateSearchContextWithRepositoryRevisions(ctx context.Context, db dbutil.D
 	if IsGlobalSearchContext(searchContext) {
 		return nil, errors.New("cannot update global search context")
 	}
+	if searchContext.Managed {
+		return nil, errors.New("cannot update managed search context")
+	}
 
 	err := ValidateSearchContextWriteAccessForCurrentUser(ctx, db, searchContext.NamespaceUserID, searchContext.NamespaceOrgID, searchContext.Public)
 	if err != nil {
@@ -234,6 +237,9 @@ func DeleteSearchContext(ctx context.Context, db dbutil.DB, searchContext *types
 	if IsAutoDefinedSearchContext(searchContext) {
 		return errors.New("cannot delete auto-defined search context")
 	}
+	if searchContext.Managed {
+		return errors.New("cannot delete managed search context, it can be hidden instead")
+	}
 
 	err := ValidateSearchContextWriteAccessForCurrentUser(ctx, db, searchContext.NamespaceUserID, searchContext.NamespaceOrgID, searchContext.Public)
 	if err != nil {
@@ -243,6 +249,21 @@ func DeleteSearchContext(ctx context.Context, db dbutil.DB, searchContext *types
 	return database.SearchContexts(db).DeleteSearchContext(ctx, searchContext.ID)
 }
 
+// SetSearchContextHidden hides the search context from the search context selector, or shows it
+// again. Unlike other search contexts, managed search contexts can only be hidden.
+func SetSearchContextHidden(ctx context.Context, db dbutil.DB, searchContext *types.SearchContext, hidden bool) error {
+	if IsAutoDefinedSearchContext(searchContext) {
+		return errors.New("cannot hide auto-defined search context")
+	}
+
+	err := ValidateSearchContextWriteAccessForCurrentUser(ctx, db, searchContext.NamespaceUserID, searchContext.NamespaceOrgID, searchContext.Public)
+	if err != nil {
+		return err
+	}
+
+	return database.SearchContexts(db).SetSearchContextHidden(ctx, searchContext.ID, hidden)
+}
+
 func GetAutoDefinedSearchContexts(ctx context.Context, db dbutil.DB) ([]*types.SearchContext, error) {
 	searchContexts := []*types.SearchContext{GetGlobalSearchContext()}
 	a := actor.FromContext(ctx)
@@ -505,3 +505,19 @@ func TestDeletingAutoDefinedSearchContext(t *testing.T) {
 		t.Fatalf("wanted error containing %s, got %s", wantErr, err)
 	}
 }
+
+func TestDeletingManagedSearchContext(t *testing.T) {
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+	managedSearchContext := &types.SearchContext{ID: 1, Name: database.ManagedSearchContextName, NamespaceUserID: 1, Managed: true}
+
+	// The search context is rejected before the database is queried.
+	err := DeleteSearchContext(ctx, nil, managedSearchContext)
+
+	wantErr := "cannot delete managed search context"
+	if err == nil {
+		t.Fatalf("wanted error, got none")
+	}
+	if err != nil && !strings.Contains(err.Error(), wantErr) {
+		t.Fatalf("wanted error containing %s, got %s", wantErr, err)
+	}
+}
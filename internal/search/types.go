@@ -300,11 +300,25 @@ type RepoOptions struct {
 	NoArchived        bool
 	OnlyArchived      bool
 	CommitAfter       string
+	HasKVPs           []query.RepoKVPFilter
 	Visibility        query.RepoVisibility
 	Ranked            bool // Return results ordered by rank
 	Limit             int
 	CacheLookup       bool
 	Query             query.Q
+
+	// Pagination, if set, resolves a page of the repositories instead of all of them.
+	Pagination *RepoPagination
+}
+
+// RepoPagination selects a page of the repositories of a search. The repositories are ordered
+// by name, so that every page starts at the repository following the last one of the previous
+// page.
+type RepoPagination struct {
+	// Start is the name of the first repository of the page. It is empty for the first page.
+	Start api.RepoName
+	// Limit is the maximum number of repositories of the page.
+	Limit int
 }
 
 func (op *RepoOptions) String() string {
@@ -328,6 +342,9 @@ func (op *RepoOptions) String() string {
 	if op.CommitAfter != "" {
 		_, _ = fmt.Fprintf(&b, " CommitAfter=%q", op.CommitAfter)
 	}
+	for _, kvp := range op.HasKVPs {
+		_, _ = fmt.Fprintf(&b, " HasKVP=%q", kvp.String())
+	}
 
 	if op.NoForks {
 		b.WriteString(" NoForks")
@@ -344,6 +361,9 @@ func (op *RepoOptions) String() string {
 	if op.Visibility != query.Any {
 		b.WriteString(" Visibility" + string(op.Visibility))
 	}
+	if op.Pagination != nil {
+		_, _ = fmt.Fprintf(&b, " Start=%q Limit=%d", op.Pagination.Start, op.Pagination.Limit)
+	}
 
 	return b.String()
 }
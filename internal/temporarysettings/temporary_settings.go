@@ -1,5 +1,79 @@
 package temporarysettings
 
+import (
+	_ "embed"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/hashicorp/go-multierror"
+	"github.com/xeipuuv/gojsonschema"
+)
+
 type TemporarySettings struct {
 	Contents string
 }
+
+// MaxSize is the maximum size in bytes of the temporary settings of a user, so that
+// clients cannot use them as unbounded storage.
+const MaxSize = 16 * 1024
+
+// ErrQuotaExceeded is returned when writing temporary settings would exceed MaxSize.
+var ErrQuotaExceeded = errors.Newf("temporary settings exceed the maximum size of %d bytes", MaxSize)
+
+// Schema is the JSON schema of temporary settings.
+//
+//go:embed temporary_settings.schema.json
+var Schema string
+
+var (
+	compiledSchema *gojsonschema.Schema
+	knownKeys      []string
+)
+
+func init() {
+	var err error
+	compiledSchema, err = gojsonschema.NewSchemaLoader().Compile(gojsonschema.NewStringLoader(Schema))
+	if err != nil {
+		panic("compiling temporary settings schema: " + err.Error())
+	}
+
+	var schema struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal([]byte(Schema), &schema); err != nil {
+		panic("decoding temporary settings schema: " + err.Error())
+	}
+	for key := range schema.Properties {
+		knownKeys = append(knownKeys, key)
+	}
+	sort.Strings(knownKeys)
+}
+
+// KnownKeys returns the keys defined by the schema of temporary settings, in order.
+func KnownKeys() []string {
+	return append([]string(nil), knownKeys...)
+}
+
+// Validate returns an error if the given temporary settings, or edit of temporary
+// settings, exceed MaxSize or don't match the schema.
+func Validate(contents string) error {
+	if len(contents) > MaxSize {
+		return ErrQuotaExceeded
+	}
+
+	res, err := compiledSchema.Validate(gojsonschema.NewStringLoader(contents))
+	if err != nil {
+		return errors.Wrap(err, "invalid temporary settings")
+	}
+
+	var errs *multierror.Error
+	for _, err := range res.Errors() {
+		errs = multierror.Append(errs, errors.New(strings.TrimPrefix(err.String(), "(root): ")))
+	}
+	if err := errs.ErrorOrNil(); err != nil {
+		return errors.Wrap(err, "invalid temporary settings")
+	}
+	return nil
+}
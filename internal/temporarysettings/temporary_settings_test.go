@@ -0,0 +1,42 @@
+package temporarysettings
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		contents string
+		wantErr  string
+	}{
+		{name: "empty", contents: `{}`},
+		{name: "valid", contents: `{"search.collapsedSidebarSections": {"types": false}, "user.lastDayActive": null, "user.daysActiveCount": 3}`},
+		{name: "malformed", contents: `{"search.usedNonGlobalContext": tru`, wantErr: "invalid temporary settings"},
+		{name: "not an object", contents: `[]`, wantErr: "Invalid type"},
+		{name: "unknown key", contents: `{"unknown": true}`, wantErr: "Additional property unknown is not allowed"},
+		{name: "invalid value", contents: `{"user.daysActiveCount": "3"}`, wantErr: "Invalid type"},
+		{name: "too large", contents: `{"user.lastDayActive": "` + strings.Repeat("x", MaxSize) + `"}`, wantErr: "exceed the maximum size"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Validate(tc.contents)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("unexpected error: have %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestKnownKeys(t *testing.T) {
+	keys := KnownKeys()
+	if len(keys) == 0 || keys[0] != "insights.freeBetaAccepted" {
+		t.Fatalf("unexpected keys: %v", keys)
+	}
+}
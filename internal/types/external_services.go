@@ -10,6 +10,12 @@ type BitbucketServerConnection struct {
 	*schema.BitbucketServerConnection
 }
 
+type GiteaConnection struct {
+	// The unique resource identifier of the external service.
+	URN string
+	*schema.GiteaConnection
+}
+
 type GitHubConnection struct {
 	// The unique resource identifier of the external service.
 	URN string
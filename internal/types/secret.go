@@ -38,7 +38,15 @@ func (e *ExternalService) RedactConfigSecrets() (string, error) {
 	}
 	switch cfg := cfg.(type) {
 	case *schema.GitHubConnection:
-		newCfg, err = redactField(e.Config, []string{"token"})
+		// GitHub can have a token OR a GitHub App
+		var fields [][]string
+		if cfg.Token != "" {
+			fields = append(fields, []string{"token"})
+		}
+		if cfg.GitHubApp != nil {
+			fields = append(fields, []string{"gitHubApp", "privateKey"})
+		}
+		newCfg, err = redactField(e.Config, fields...)
 	case *schema.GitLabConnection:
 		newCfg, err = redactField(e.Config, []string{"token"})
 	case *schema.BitbucketServerConnection:
@@ -53,6 +61,10 @@ func (e *ExternalService) RedactConfigSecrets() (string, error) {
 		newCfg, err = redactField(e.Config, fields...)
 	case *schema.BitbucketCloudConnection:
 		newCfg, err = redactField(e.Config, []string{"appPassword"})
+	case *schema.GiteaConnection:
+		newCfg, err = redactField(e.Config, []string{"token"})
+	case *schema.AzureDevOpsConnection:
+		newCfg, err = redactField(e.Config, []string{"token"})
 	case *schema.AWSCodeCommitConnection:
 		newCfg, err = redactField(e.Config, []string{"secretAccessKey"}, []string{"gitCredentials", "password"})
 	case *schema.PhabricatorConnection:
@@ -114,7 +126,25 @@ func (e *ExternalService) UnredactConfig(old *ExternalService) error {
 	}
 	switch cfg := cfg.(type) {
 	case *schema.GitHubConnection:
-		unredacted, err = unredactField(old.Config, e.Config, &cfg, jsonStringField{[]string{"token"}, &cfg.Token})
+		// GitHub can have a token OR a GitHub App
+		var fields []jsonStringField
+		if cfg.Token != "" {
+			fields = append(fields, jsonStringField{[]string{"token"}, &cfg.Token})
+		}
+		if cfg.GitHubApp != nil {
+			// unredactField replaces cfg.GitHubApp when reading the old
+			// config, so we read the old private key ourselves.
+			var oldCfg schema.GitHubConnection
+			if err := unmarshalConfig(old.Config, &oldCfg); err != nil {
+				return err
+			}
+			var privateKey string
+			if oldCfg.GitHubApp != nil {
+				privateKey = oldCfg.GitHubApp.PrivateKey
+			}
+			fields = append(fields, jsonStringField{[]string{"gitHubApp", "privateKey"}, &privateKey})
+		}
+		unredacted, err = unredactField(old.Config, e.Config, &cfg, fields...)
 	case *schema.GitLabConnection:
 		unredacted, err = unredactField(old.Config, e.Config, &cfg, jsonStringField{[]string{"token"}, &cfg.Token})
 	case *schema.BitbucketServerConnection:
@@ -129,6 +159,10 @@ func (e *ExternalService) UnredactConfig(old *ExternalService) error {
 		unredacted, err = unredactField(old.Config, e.Config, &cfg, fields...)
 	case *schema.BitbucketCloudConnection:
 		unredacted, err = unredactField(old.Config, e.Config, &cfg, jsonStringField{[]string{"appPassword"}, &cfg.AppPassword})
+	case *schema.GiteaConnection:
+		unredacted, err = unredactField(old.Config, e.Config, &cfg, jsonStringField{[]string{"token"}, &cfg.Token})
+	case *schema.AzureDevOpsConnection:
+		unredacted, err = unredactField(old.Config, e.Config, &cfg, jsonStringField{[]string{"token"}, &cfg.Token})
 	case *schema.AWSCodeCommitConnection:
 		unredacted, err = unredactField(old.Config,
 			e.Config,
@@ -123,6 +123,7 @@ func (r *Repo) ExternalServiceIDs() []int64 {
 type BlockedRepoError struct {
 	Name   api.RepoName
 	Reason string
+	At     int64 // Unix timestamp
 }
 
 func (e BlockedRepoError) Error() string {
@@ -135,7 +136,7 @@ func (e BlockedRepoError) Blocked() bool { return true }
 // IsBlocked returns a non nil error if the repo has been blocked.
 func (r *Repo) IsBlocked() error {
 	if r.Blocked != nil {
-		return &BlockedRepoError{Name: r.Name, Reason: r.Blocked.Reason}
+		return &BlockedRepoError{Name: r.Name, Reason: r.Blocked.Reason, At: r.Blocked.At}
 	}
 	return nil
 }
@@ -478,6 +479,7 @@ type ExternalService struct {
 	NamespaceOrgID  int32
 	Unrestricted    bool // Whether access to repositories belong to this external service is unrestricted.
 	CloudDefault    bool // Whether this external service is our default public service on Cloud
+	TokenShared     bool // Whether the token of this organization external service grants all members of the organization read access to its repositories.
 }
 
 // ExternalServiceSyncJob represents an sync job for an external service
@@ -493,6 +495,74 @@ type ExternalServiceSyncJob struct {
 	NumFailures       int
 }
 
+// ExternalServiceSyncStats records the outcome of a single sync of an external
+// service.
+type ExternalServiceSyncStats struct {
+	ID                int64
+	ExternalServiceID int64
+	StartedAt         time.Time
+	FinishedAt        time.Time
+	ReposAdded        int
+	ReposRemoved      int
+	ReposErrored      int
+	// APIRequests is the number of requests sent to the code host during the
+	// sync, which for most code hosts is the API rate limit quota it consumed.
+	APIRequests int
+	Errored     bool
+	// BackoffReason is why the next sync was scheduled later than the minimum
+	// sync interval, empty if it was not.
+	BackoffReason ExternalServiceSyncBackoffReason
+}
+
+// ExternalServiceSyncBackoffReason is why the syncer backed off from syncing an
+// external service.
+type ExternalServiceSyncBackoffReason string
+
+const (
+	// ExternalServiceSyncBackoffUnchanged means the last sync didn't change any
+	// repositories.
+	ExternalServiceSyncBackoffUnchanged ExternalServiceSyncBackoffReason = "unchanged"
+	// ExternalServiceSyncBackoffRateLimited means the code host rate limited the
+	// last sync.
+	ExternalServiceSyncBackoffRateLimited ExternalServiceSyncBackoffReason = "rate_limited"
+	// ExternalServiceSyncBackoffUnauthorized means the code host rejected the
+	// credentials of the external service during the last sync.
+	ExternalServiceSyncBackoffUnauthorized ExternalServiceSyncBackoffReason = "unauthorized"
+	// ExternalServiceSyncBackoffError means the last sync failed for any other
+	// reason.
+	ExternalServiceSyncBackoffError ExternalServiceSyncBackoffReason = "error"
+)
+
+// ExternalServiceNextSyncInfo describes when an external service is synced next.
+type ExternalServiceNextSyncInfo struct {
+	ExternalServiceID int64
+	// NextSyncAt is when the external service is due to sync, zero if it is due
+	// now.
+	NextSyncAt time.Time
+	// SyncJobState is the state of the sync job of the external service if one is
+	// queued or processing, empty otherwise.
+	SyncJobState string
+	// BackoffReason is why the last sync scheduled the next one later than the
+	// minimum sync interval, empty if it did not.
+	BackoffReason ExternalServiceSyncBackoffReason
+}
+
+// ExternalServiceSyncStatistics aggregates the ExternalServiceSyncStats of the
+// syncs of an external service that finished within a time window.
+type ExternalServiceSyncStatistics struct {
+	Syncs           int
+	ErroredSyncs    int
+	AverageDuration time.Duration
+	MaxDuration     time.Duration
+	ReposAdded      int
+	ReposRemoved    int
+	ReposErrored    int
+	APIRequests     int
+	// LastErroredAt is the time the last errored sync in the window finished,
+	// zero if there was none.
+	LastErroredAt time.Time
+}
+
 // URN returns a unique resource identifier of this external service,
 // used as the key in a repo's Sources map as well as the SourceInfo ID.
 func (e *ExternalService) URN() string {
@@ -670,10 +740,39 @@ type OrgMembership struct {
 	ID        int32
 	OrgID     int32
 	UserID    int32
+	Role      OrgRole
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
+// OrgRole is the role of a user in an organization.
+type OrgRole string
+
+const (
+	// OrgRoleAdmin members can manage the organization: its members, settings and code host
+	// connections.
+	OrgRoleAdmin OrgRole = "admin"
+	// OrgRoleMember members can use the organization: read its settings and code host
+	// connections, and create batch changes in its namespace.
+	OrgRoleMember OrgRole = "member"
+)
+
+// Valid returns whether the role is a known role.
+func (r OrgRole) Valid() bool {
+	return r == OrgRoleAdmin || r == OrgRoleMember
+}
+
+// Includes returns whether the role grants the permissions of the required role. An admin has
+// all the permissions of a member.
+func (r OrgRole) Includes(required OrgRole) bool {
+	switch required {
+	case OrgRoleAdmin:
+		return r == OrgRoleAdmin
+	default:
+		return true
+	}
+}
+
 type PhabricatorRepo struct {
 	ID       int32
 	Name     api.RepoName
@@ -1195,6 +1294,11 @@ type SearchContext struct {
 	NamespaceUserID int32 // if non-zero, the owner is this user. NamespaceUserID/NamespaceOrgID are mutually exclusive.
 	NamespaceOrgID  int32 // if non-zero, the owner is this organization. NamespaceUserID/NamespaceOrgID are mutually exclusive.
 	UpdatedAt       time.Time
+	// Managed search contexts are maintained by Sourcegraph from the repositories of the code host
+	// connections of their namespace. They cannot be edited or deleted, but can be hidden.
+	Managed bool
+	// Hidden search contexts are not offered in the search context selector.
+	Hidden bool
 
 	// We cache namespace names to avoid separate database lookups when constructing the search context spec
 
@@ -0,0 +1,66 @@
+package urlsign
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSigner(t *testing.T) {
+	key := "test-key"
+	signer := Signer{Purpose: "raw", Key: func() string { return key }}
+
+	now := time.Now()
+	expires, signature := signer.Sign(now.Add(time.Minute), "github.com/foo/bar", "main")
+
+	tests := []struct {
+		name      string
+		signer    Signer
+		expires   string
+		signature string
+		now       time.Time
+		fields    []string
+		wantErr   error
+	}{
+		{name: "valid", signer: signer, expires: expires, signature: signature, now: now, fields: []string{"github.com/foo/bar", "main"}},
+		{name: "other field", signer: signer, expires: expires, signature: signature, now: now, fields: []string{"github.com/foo/baz", "main"}, wantErr: ErrInvalidSignature},
+		{name: "fields joined differently", signer: signer, expires: expires, signature: signature, now: now, fields: []string{"github.com/foo/barmain", ""}, wantErr: ErrInvalidSignature},
+		{name: "missing field", signer: signer, expires: expires, signature: signature, now: now, fields: []string{"github.com/foo/bar"}, wantErr: ErrInvalidSignature},
+		{name: "extended expiry", signer: signer, expires: "99999999999", signature: signature, now: now, fields: []string{"github.com/foo/bar", "main"}, wantErr: ErrInvalidSignature},
+		{name: "expired", signer: signer, expires: expires, signature: signature, now: now.Add(time.Minute), fields: []string{"github.com/foo/bar", "main"}, wantErr: ErrExpired},
+		{name: "unsigned", signer: signer, now: now, fields: []string{"github.com/foo/bar", "main"}, wantErr: ErrInvalidSignature},
+		{
+			name:      "other purpose",
+			signer:    Signer{Purpose: "artifact", Key: signer.Key},
+			expires:   expires,
+			signature: signature,
+			now:       now,
+			fields:    []string{"github.com/foo/bar", "main"},
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "other key",
+			signer:    Signer{Purpose: "raw", Key: func() string { return "other-key" }},
+			expires:   expires,
+			signature: signature,
+			now:       now,
+			fields:    []string{"github.com/foo/bar", "main"},
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "disabled",
+			signer:    Signer{Purpose: "raw", Key: func() string { return "" }},
+			expires:   expires,
+			signature: signature,
+			now:       now,
+			fields:    []string{"github.com/foo/bar", "main"},
+			wantErr:   ErrInvalidSignature,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.signer.Verify(test.expires, test.signature, test.now, test.fields...); err != test.wantErr {
+				t.Errorf("unexpected error. want=%v have=%v", test.wantErr, err)
+			}
+		})
+	}
+}
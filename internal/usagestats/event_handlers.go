@@ -235,5 +235,5 @@ func logLocalEvent(ctx context.Context, db dbutil.DB, name, url string, userID i
 		CohortID:        cohortID,
 		PublicArgument:  publicArgument,
 	}
-	return database.EventLogs(db).Insert(ctx, info)
+	return database.EventLogs(db).InsertAsync(ctx, info)
 }
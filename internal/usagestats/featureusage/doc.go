@@ -0,0 +1,7 @@
+// Package featureusage periodically aggregates the daily and weekly usage of product features
+// (batch changes, code insights and code intelligence) from the event logs into the
+// feature_usage_statistics table.
+//
+// Consumers such as the site admin analytics read the aggregated usage through
+// database.FeatureUsageStatistics instead of aggregating the event logs on demand.
+package featureusage
@@ -0,0 +1,116 @@
+package featureusage
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/worker/shared"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+// The names of the features under which their usage is aggregated.
+const (
+	BatchChanges     = "batch-changes"
+	CodeInsights     = "code-insights"
+	CodeIntelligence = "code-intelligence"
+)
+
+// features are the events that make up the usage of each feature.
+var features = []database.FeatureUsageEvents{
+	{
+		Feature: BatchChanges,
+		EventNames: []string{
+			"BatchSpecCreated",
+			"BatchChangeCreated",
+			"BatchChangeCreatedOrUpdated",
+			"BatchChangeClosed",
+			"BatchChangeDeleted",
+			"ViewBatchChangeApplyPage",
+			"ViewBatchChangeDetailsPageAfterCreate",
+			"ViewBatchChangeDetailsPageAfterUpdate",
+			"ViewBatchChangeDetailsPagePage",
+			"ViewBatchChangesListPage",
+		},
+	},
+	{
+		Feature:           CodeInsights,
+		EventNames:        []string{"ViewInsights"},
+		EventNamePrefixes: []string{"Insight"},
+	},
+	{
+		Feature: CodeIntelligence,
+		EventNames: []string{
+			"ViewCodeIntelUploadsPage",
+			"ViewCodeIntelUploadPage",
+			"ViewCodeIntelIndexesPage",
+			"ViewCodeIntelIndexPage",
+			"ViewCodeIntelConfigurationPage",
+			"ViewCodeIntelConfigurationPolicyPage",
+		},
+		EventNamePrefixes: []string{"codeintel."},
+	},
+}
+
+const aggregationInterval = time.Hour
+
+// aggregatedPeriods are the number of periods of each type that are re-aggregated on every run,
+// so that the current period is kept up to date and the previous one is completed.
+var aggregatedPeriods = map[database.PeriodType]int{
+	database.Daily:  2,
+	database.Weekly: 2,
+}
+
+// backfilledPeriods are the number of periods of each type that are aggregated on the first run
+// of the worker, so that the usage logged before the job ran (or while the worker was down) is
+// available too.
+var backfilledPeriods = map[database.PeriodType]int{
+	database.Daily:  30,
+	database.Weekly: 12,
+}
+
+func NewAggregationJob() shared.Job {
+	return &aggregationJob{}
+}
+
+type aggregationJob struct{}
+
+func (j *aggregationJob) Config() []env.Config {
+	return []env.Config{}
+}
+
+func (j *aggregationJob) Routines(_ context.Context) ([]goroutine.BackgroundRoutine, error) {
+	db, err := shared.InitDatabase()
+	if err != nil {
+		return nil, err
+	}
+
+	periods := backfilledPeriods
+	handler := goroutine.NewHandlerWithErrorMessage("aggregate feature usage statistics", func(ctx context.Context) error {
+		if err := aggregate(ctx, db, time.Now().UTC(), periods); err != nil {
+			return err
+		}
+		periods = aggregatedPeriods
+		return nil
+	})
+
+	return []goroutine.BackgroundRoutine{
+		// Pass a fresh context, see docs for shared.Job
+		goroutine.NewPeriodicGoroutine(context.Background(), aggregationInterval, handler),
+	}, nil
+}
+
+// aggregate aggregates the usage of all features over the given number of periods up to now.
+func aggregate(ctx context.Context, db dbutil.DB, now time.Time, periods map[database.PeriodType]int) error {
+	store := database.FeatureUsageStatistics(db)
+	for _, events := range features {
+		for _, periodType := range []database.PeriodType{database.Daily, database.Weekly} {
+			if err := store.Aggregate(ctx, events, periodType, now, periods[periodType]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package webauthn
+
+import (
+	"encoding/binary"
+
+	"github.com/cockroachdb/errors"
+)
+
+// maxCBORDepth limits the nesting of decoded CBOR data items, as they come from
+// untrusted clients.
+const maxCBORDepth = 16
+
+// decodeCBOR decodes the first CBOR data item in b and returns it along with the
+// bytes following it.
+//
+// It only supports the subset of CBOR used by WebAuthn authenticators, which
+// encode data items with definite lengths and without floating point numbers.
+// Integers are decoded as int64, byte strings as []byte, text strings as
+// string, arrays as []interface{} and maps as map[interface{}]interface{}.
+func decodeCBOR(b []byte) (v interface{}, rest []byte, err error) {
+	return decodeCBORItem(b, 0)
+}
+
+func decodeCBORItem(b []byte, depth int) (interface{}, []byte, error) {
+	if depth > maxCBORDepth {
+		return nil, nil, errors.New("cbor: data items nested too deeply")
+	}
+	if len(b) == 0 {
+		return nil, nil, errors.New("cbor: unexpected end of data")
+	}
+
+	major, info := b[0]>>5, b[0]&0x1f
+	b = b[1:]
+
+	// Simple values don't have an argument in the usual sense.
+	if major == 7 {
+		switch info {
+		case 20:
+			return false, b, nil
+		case 21:
+			return true, b, nil
+		case 22, 23:
+			return nil, b, nil
+		default:
+			return nil, nil, errors.Errorf("cbor: unsupported simple value %d", info)
+		}
+	}
+
+	var arg uint64
+	switch {
+	case info < 24:
+		arg = uint64(info)
+	case info <= 27:
+		n := 1 << (info - 24)
+		if len(b) < n {
+			return nil, nil, errors.New("cbor: unexpected end of data")
+		}
+		switch n {
+		case 1:
+			arg = uint64(b[0])
+		case 2:
+			arg = uint64(binary.BigEndian.Uint16(b))
+		case 4:
+			arg = uint64(binary.BigEndian.Uint32(b))
+		case 8:
+			arg = binary.BigEndian.Uint64(b)
+		}
+		b = b[n:]
+	case info == 31:
+		return nil, nil, errors.New("cbor: indefinite lengths are not supported")
+	default:
+		return nil, nil, errors.Errorf("cbor: invalid additional information %d", info)
+	}
+
+	switch major {
+	case 0:
+		if arg > 1<<63-1 {
+			return nil, nil, errors.New("cbor: integer overflows int64")
+		}
+		return int64(arg), b, nil
+
+	case 1:
+		if arg > 1<<63-1 {
+			return nil, nil, errors.New("cbor: integer overflows int64")
+		}
+		return -1 - int64(arg), b, nil
+
+	case 2, 3:
+		if uint64(len(b)) < arg {
+			return nil, nil, errors.New("cbor: unexpected end of data")
+		}
+		s := b[:arg]
+		if major == 3 {
+			return string(s), b[arg:], nil
+		}
+		return append([]byte(nil), s...), b[arg:], nil
+
+	case 4:
+		// Each element takes at least one byte, which bounds the allocation.
+		if uint64(len(b)) < arg {
+			return nil, nil, errors.New("cbor: unexpected end of data")
+		}
+		items := make([]interface{}, 0, arg)
+		for i := uint64(0); i < arg; i++ {
+			var (
+				item interface{}
+				err  error
+			)
+			item, b, err = decodeCBORItem(b, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			items = append(items, item)
+		}
+		return items, b, nil
+
+	case 5:
+		if uint64(len(b)) < 2*arg {
+			return nil, nil, errors.New("cbor: unexpected end of data")
+		}
+		m := make(map[interface{}]interface{}, arg)
+		for i := uint64(0); i < arg; i++ {
+			var (
+				k, v interface{}
+				err  error
+			)
+			k, b, err = decodeCBORItem(b, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			switch k.(type) {
+			case int64, string:
+			default:
+				return nil, nil, errors.Errorf("cbor: unsupported map key type %T", k)
+			}
+			v, b, err = decodeCBORItem(b, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			m[k] = v
+		}
+		return m, b, nil
+
+	default:
+		return nil, nil, errors.Errorf("cbor: unsupported major type %d", major)
+	}
+}
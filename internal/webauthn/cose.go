@@ -0,0 +1,114 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"math/big"
+
+	"github.com/cockroachdb/errors"
+)
+
+// COSE algorithm identifiers of the signature algorithms we support, see
+// https://www.iana.org/assignments/cose/cose.xhtml#algorithms.
+const (
+	algES256 = -7
+	algEdDSA = -8
+	algRS256 = -257
+)
+
+// supportedAlgorithms are the COSE algorithms we accept, in order of
+// preference.
+var supportedAlgorithms = []int{algES256, algEdDSA, algRS256}
+
+// COSE key types and labels, see RFC 8152.
+const (
+	coseKeyTypeOKP = 1
+	coseKeyTypeEC2 = 2
+	coseKeyTypeRSA = 3
+
+	coseLabelKeyType   = 1
+	coseLabelAlgorithm = 3
+
+	coseCurveP256    = 1
+	coseCurveEd25519 = 6
+)
+
+// publicKey is a credential public key decoded from its COSE encoding.
+type publicKey struct {
+	alg int64
+	key crypto.PublicKey
+}
+
+// parsePublicKey decodes a COSE encoded credential public key. Only keys of the
+// supported algorithms are accepted.
+func parsePublicKey(b []byte) (*publicKey, error) {
+	v, rest, err := decodeCBOR(b)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding public key")
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("trailing data after public key")
+	}
+	m, ok := v.(map[interface{}]interface{})
+	if !ok {
+		return nil, errors.New("public key is not a map")
+	}
+
+	kty, _ := m[int64(coseLabelKeyType)].(int64)
+	alg, _ := m[int64(coseLabelAlgorithm)].(int64)
+	bytesParam := func(label int64) []byte {
+		b, _ := m[label].([]byte)
+		return b
+	}
+
+	switch {
+	case kty == coseKeyTypeEC2 && alg == algES256:
+		crv, _ := m[int64(-1)].(int64)
+		x, y := bytesParam(-2), bytesParam(-3)
+		if crv != coseCurveP256 || len(x) != 32 || len(y) != 32 {
+			return nil, errors.New("invalid ES256 public key")
+		}
+		k := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !k.Curve.IsOnCurve(k.X, k.Y) {
+			return nil, errors.New("ES256 public key is not on the curve")
+		}
+		return &publicKey{alg: alg, key: k}, nil
+
+	case kty == coseKeyTypeOKP && alg == algEdDSA:
+		crv, _ := m[int64(-1)].(int64)
+		x := bytesParam(-2)
+		if crv != coseCurveEd25519 || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid EdDSA public key")
+		}
+		return &publicKey{alg: alg, key: ed25519.PublicKey(x)}, nil
+
+	case kty == coseKeyTypeRSA && alg == algRS256:
+		n, e := bytesParam(-1), bytesParam(-2)
+		if len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return nil, errors.New("invalid RS256 public key")
+		}
+		k := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		return &publicKey{alg: alg, key: k}, nil
+	}
+
+	return nil, errors.Errorf("unsupported public key type %d with algorithm %d", kty, alg)
+}
+
+// verify reports whether sig is a valid signature of data by the key.
+func (k *publicKey) verify(data, sig []byte) bool {
+	switch key := k.key.(type) {
+	case *ecdsa.PublicKey:
+		h := sha256.Sum256(data)
+		return ecdsa.VerifyASN1(key, h[:], sig)
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, data, sig)
+	case *rsa.PublicKey:
+		h := sha256.Sum256(data)
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, h[:], sig) == nil
+	}
+	return false
+}
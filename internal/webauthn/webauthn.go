@@ -0,0 +1,344 @@
+// Package webauthn implements the server side of the Web Authentication (WebAuthn) API, which
+// lets users register security keys and prove their possession as a second factor when signing
+// in. See https://www.w3.org/TR/webauthn-2/.
+//
+// Attestation statements are not verified, as we request "none" attestation: we trust security
+// keys to be what they claim, not any particular make or model.
+package webauthn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Timeout is how long (in milliseconds) clients should wait for the user to use their security key.
+const Timeout = 60000
+
+// Flags of the authenticator data.
+const (
+	flagUserPresent            = 0x01
+	flagAttestedCredentialData = 0x40
+)
+
+// Base64URL is a byte slice encoded as unpadded base64url in JSON, the way binary WebAuthn
+// values are commonly passed between the browser and the server.
+type Base64URL []byte
+
+func (b Base64URL) MarshalJSON() ([]byte, error) {
+	return json.Marshal(base64.RawURLEncoding.EncodeToString(b))
+}
+
+func (b *Base64URL) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	// Be lenient about padding, some client libraries add it.
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return err
+	}
+	*b = decoded
+	return nil
+}
+
+// RelyingParty is the WebAuthn relying party, i.e. the Sourcegraph instance security keys are
+// registered with.
+type RelyingParty struct {
+	// ID is the domain of the instance, which scopes the credentials of security keys.
+	ID string
+	// Name is the name of the instance shown to users by their browser.
+	Name string
+	// Origin is the origin (scheme, host and port) of the instance.
+	Origin string
+}
+
+// NewChallenge returns a new random challenge for a registration or sign-in ceremony.
+func NewChallenge() ([]byte, error) {
+	challenge := make([]byte, 32)
+	if _, err := rand.Read(challenge); err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
+// User is the user a security key is registered for.
+type User struct {
+	ID          []byte
+	Name        string
+	DisplayName string
+}
+
+// CredentialDescriptor identifies a credential of a security key.
+type CredentialDescriptor struct {
+	Type string    `json:"type"`
+	ID   Base64URL `json:"id"`
+}
+
+// CredentialParameters describes a type of credential to create.
+type CredentialParameters struct {
+	Type string `json:"type"`
+	Alg  int    `json:"alg"`
+}
+
+// CreationOptions are the options passed to navigator.credentials.create() in the browser to
+// register a security key.
+type CreationOptions struct {
+	Challenge Base64URL `json:"challenge"`
+	RP        struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"rp"`
+	User struct {
+		ID          Base64URL `json:"id"`
+		Name        string    `json:"name"`
+		DisplayName string    `json:"displayName"`
+	} `json:"user"`
+	PubKeyCredParams   []CredentialParameters `json:"pubKeyCredParams"`
+	ExcludeCredentials []CredentialDescriptor `json:"excludeCredentials,omitempty"`
+	Timeout            int                    `json:"timeout"`
+	Attestation        string                 `json:"attestation"`
+}
+
+// RequestOptions are the options passed to navigator.credentials.get() in the browser to sign in
+// with a security key.
+type RequestOptions struct {
+	Challenge        Base64URL              `json:"challenge"`
+	RPID             string                 `json:"rpId"`
+	AllowCredentials []CredentialDescriptor `json:"allowCredentials"`
+	Timeout          int                    `json:"timeout"`
+	UserVerification string                 `json:"userVerification"`
+}
+
+// CreationOptions returns the options to register a security key for the user. The credentials
+// with the IDs in exclude are already registered, so the browser refuses to register their
+// security keys again.
+func (rp *RelyingParty) CreationOptions(challenge []byte, user User, exclude [][]byte) *CreationOptions {
+	opts := &CreationOptions{
+		Challenge:   challenge,
+		Timeout:     Timeout,
+		Attestation: "none",
+	}
+	opts.RP.ID = rp.ID
+	opts.RP.Name = rp.Name
+	opts.User.ID = user.ID
+	opts.User.Name = user.Name
+	opts.User.DisplayName = user.DisplayName
+	for _, alg := range supportedAlgorithms {
+		opts.PubKeyCredParams = append(opts.PubKeyCredParams, CredentialParameters{Type: "public-key", Alg: alg})
+	}
+	for _, id := range exclude {
+		opts.ExcludeCredentials = append(opts.ExcludeCredentials, CredentialDescriptor{Type: "public-key", ID: id})
+	}
+	return opts
+}
+
+// RequestOptions returns the options to sign in with one of the security keys whose credential
+// IDs are given.
+func (rp *RelyingParty) RequestOptions(challenge []byte, allow [][]byte) *RequestOptions {
+	opts := &RequestOptions{
+		Challenge: challenge,
+		RPID:      rp.ID,
+		Timeout:   Timeout,
+		// Security keys are a second factor, the password is the first one.
+		UserVerification: "discouraged",
+		AllowCredentials: []CredentialDescriptor{},
+	}
+	for _, id := range allow {
+		opts.AllowCredentials = append(opts.AllowCredentials, CredentialDescriptor{Type: "public-key", ID: id})
+	}
+	return opts
+}
+
+// AttestationResponse is the PublicKeyCredential returned by navigator.credentials.create() in
+// the browser, with its binary values encoded as base64url.
+type AttestationResponse struct {
+	RawID    Base64URL `json:"rawId"`
+	Response struct {
+		ClientDataJSON    Base64URL `json:"clientDataJSON"`
+		AttestationObject Base64URL `json:"attestationObject"`
+	} `json:"response"`
+}
+
+// AssertionResponse is the PublicKeyCredential returned by navigator.credentials.get() in the
+// browser, with its binary values encoded as base64url.
+type AssertionResponse struct {
+	RawID    Base64URL `json:"rawId"`
+	Response struct {
+		ClientDataJSON    Base64URL `json:"clientDataJSON"`
+		AuthenticatorData Base64URL `json:"authenticatorData"`
+		Signature         Base64URL `json:"signature"`
+	} `json:"response"`
+}
+
+// Credential is the credential of a registered security key.
+type Credential struct {
+	// ID is the credential ID chosen by the security key.
+	ID []byte
+	// PublicKey is the COSE encoded public key of the credential.
+	PublicKey []byte
+	// SignCount is the signature counter of the security key, which increases with each use
+	// unless the security key doesn't implement it, in which case it's always 0.
+	SignCount uint32
+}
+
+// VerifyRegistration verifies the response of the browser to the creation options with the given
+// challenge and returns the credential of the registered security key.
+func (rp *RelyingParty) VerifyRegistration(challenge []byte, resp *AttestationResponse) (*Credential, error) {
+	if err := rp.verifyClientData(resp.Response.ClientDataJSON, "webauthn.create", challenge); err != nil {
+		return nil, err
+	}
+
+	v, _, err := decodeCBOR(resp.Response.AttestationObject)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding attestation object")
+	}
+	attestation, ok := v.(map[interface{}]interface{})
+	if !ok {
+		return nil, errors.New("attestation object is not a map")
+	}
+	rawAuthData, ok := attestation["authData"].([]byte)
+	if !ok {
+		return nil, errors.New("attestation object has no authenticator data")
+	}
+
+	authData, err := rp.parseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+	if authData.flags&flagAttestedCredentialData == 0 {
+		return nil, errors.New("authenticator data has no attested credential")
+	}
+	if len(resp.RawID) > 0 && !bytes.Equal(resp.RawID, authData.credentialID) {
+		return nil, errors.New("credential ID doesn't match the attested credential")
+	}
+	if _, err := parsePublicKey(authData.credentialPublicKey); err != nil {
+		return nil, err
+	}
+
+	return &Credential{
+		ID:        authData.credentialID,
+		PublicKey: authData.credentialPublicKey,
+		SignCount: authData.signCount,
+	}, nil
+}
+
+// ErrSignCount occurs when the signature counter of a security key didn't increase, which
+// indicates that the security key was cloned.
+var ErrSignCount = errors.New("signature counter of the security key didn't increase")
+
+// VerifySignIn verifies the response of the browser to the request options with the given
+// challenge, which must be signed by the security key of the given credential. It returns the new
+// signature counter of the security key.
+func (rp *RelyingParty) VerifySignIn(challenge []byte, resp *AssertionResponse, cred *Credential) (signCount uint32, err error) {
+	if !bytes.Equal(resp.RawID, cred.ID) {
+		return 0, errors.New("credential ID doesn't match")
+	}
+	if err := rp.verifyClientData(resp.Response.ClientDataJSON, "webauthn.get", challenge); err != nil {
+		return 0, err
+	}
+	authData, err := rp.parseAuthenticatorData(resp.Response.AuthenticatorData)
+	if err != nil {
+		return 0, err
+	}
+
+	key, err := parsePublicKey(cred.PublicKey)
+	if err != nil {
+		return 0, err
+	}
+	clientDataHash := sha256.Sum256(resp.Response.ClientDataJSON)
+	signed := append(append([]byte(nil), resp.Response.AuthenticatorData...), clientDataHash[:]...)
+	if !key.verify(signed, resp.Response.Signature) {
+		return 0, errors.New("invalid signature")
+	}
+
+	if (authData.signCount != 0 || cred.SignCount != 0) && authData.signCount <= cred.SignCount {
+		return 0, ErrSignCount
+	}
+	return authData.signCount, nil
+}
+
+type clientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+func (rp *RelyingParty) verifyClientData(raw []byte, typ string, challenge []byte) error {
+	var cd clientData
+	if err := json.Unmarshal(raw, &cd); err != nil {
+		return errors.Wrap(err, "decoding client data")
+	}
+	if cd.Type != typ {
+		return errors.Errorf("client data has type %q, want %q", cd.Type, typ)
+	}
+	got, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(cd.Challenge, "="))
+	if err != nil || len(challenge) == 0 || subtle.ConstantTimeCompare(got, challenge) != 1 {
+		return errors.New("client data challenge doesn't match")
+	}
+	if cd.Origin != rp.Origin {
+		return errors.Errorf("client data has origin %q, want %q", cd.Origin, rp.Origin)
+	}
+	return nil
+}
+
+type authenticatorData struct {
+	flags               byte
+	signCount           uint32
+	credentialID        []byte
+	credentialPublicKey []byte
+}
+
+// parseAuthenticatorData parses the authenticator data, which must be scoped to the relying party
+// and attest the presence of the user.
+func (rp *RelyingParty) parseAuthenticatorData(b []byte) (*authenticatorData, error) {
+	// The RP ID hash, flags and signature counter make up the first 37 bytes.
+	if len(b) < 37 {
+		return nil, errors.New("authenticator data is too short")
+	}
+	rpIDHash := sha256.Sum256([]byte(rp.ID))
+	if subtle.ConstantTimeCompare(b[:32], rpIDHash[:]) != 1 {
+		return nil, errors.New("authenticator data is for another relying party")
+	}
+
+	d := &authenticatorData{
+		flags:     b[32],
+		signCount: binary.BigEndian.Uint32(b[33:37]),
+	}
+	if d.flags&flagUserPresent == 0 {
+		return nil, errors.New("user was not present")
+	}
+	if d.flags&flagAttestedCredentialData == 0 {
+		return d, nil
+	}
+
+	// The attested credential data consists of the AAGUID of the authenticator, the length of
+	// the credential ID, the credential ID and the COSE encoded public key.
+	b = b[37:]
+	if len(b) < 18 {
+		return nil, errors.New("attested credential data is too short")
+	}
+	idLen := int(binary.BigEndian.Uint16(b[16:18]))
+	b = b[18:]
+	if len(b) < idLen {
+		return nil, errors.New("attested credential data is too short")
+	}
+	d.credentialID = append([]byte(nil), b[:idLen]...)
+	b = b[idLen:]
+
+	// Extensions may follow the public key, so we need to decode it to know where it ends.
+	_, rest, err := decodeCBOR(b)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding credential public key")
+	}
+	d.credentialPublicKey = append([]byte(nil), b[:len(b)-len(rest)]...)
+	return d, nil
+}
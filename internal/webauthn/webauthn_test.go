@@ -0,0 +1,353 @@
+package webauthn
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDecodeCBOR(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		in   []byte
+		want interface{}
+	}{
+		{name: "small integer", in: []byte{0x17}, want: int64(23)},
+		{name: "uint8 integer", in: []byte{0x18, 0xff}, want: int64(255)},
+		{name: "uint16 integer", in: []byte{0x19, 0x01, 0x00}, want: int64(256)},
+		{name: "negative integer", in: []byte{0x39, 0x01, 0x00}, want: int64(-257)},
+		{name: "byte string", in: []byte{0x42, 0x01, 0x02}, want: []byte{1, 2}},
+		{name: "text string", in: []byte{0x63, 'f', 'o', 'o'}, want: "foo"},
+		{name: "array", in: []byte{0x82, 0x01, 0xf5}, want: []interface{}{int64(1), true}},
+		{name: "map", in: []byte{0xa2, 0x01, 0x02, 0x61, 'a', 0xf6}, want: map[interface{}]interface{}{int64(1): int64(2), "a": nil}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			have, rest, err := decodeCBOR(append(tc.in, 0xff))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, have); diff != "" {
+				t.Fatalf("mismatch (-want +have):\n%s", diff)
+			}
+			if diff := cmp.Diff([]byte{0xff}, rest); diff != "" {
+				t.Fatalf("rest mismatch (-want +have):\n%s", diff)
+			}
+		})
+	}
+
+	for _, tc := range []struct {
+		name string
+		in   []byte
+	}{
+		{name: "empty", in: nil},
+		{name: "truncated byte string", in: []byte{0x45, 0x01}},
+		{name: "truncated argument", in: []byte{0x19, 0x01}},
+		{name: "huge array", in: []byte{0x9b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{name: "indefinite length", in: []byte{0x5f, 0x41, 0x01, 0xff}},
+		{name: "float", in: []byte{0xf9, 0x3c, 0x00}},
+		{name: "array map key", in: []byte{0xa1, 0x80, 0x01}},
+		{name: "deeply nested", in: append(bytesRepeat(0x81, maxCBORDepth+2), 0x01)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, _, err := decodeCBOR(tc.in); err == nil {
+				t.Fatal("want error, got nil")
+			}
+		})
+	}
+}
+
+func bytesRepeat(b byte, n int) []byte {
+	bs := make([]byte, n)
+	for i := range bs {
+		bs[i] = b
+	}
+	return bs
+}
+
+var testRP = &RelyingParty{ID: "sourcegraph.example.com", Name: "Sourcegraph", Origin: "https://sourcegraph.example.com"}
+
+func TestRegistrationAndSignIn(t *testing.T) {
+	for _, key := range []testKey{newTestES256Key(t), newTestEdDSAKey(t)} {
+		t.Run(key.name(), func(t *testing.T) {
+			credentialID := []byte("credential-id")
+
+			challenge, err := NewChallenge()
+			if err != nil {
+				t.Fatal(err)
+			}
+			cred, err := testRP.VerifyRegistration(challenge, testAttest(t, key, testRP, challenge, credentialID, "https://sourcegraph.example.com"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(credentialID, cred.ID); diff != "" {
+				t.Fatalf("credential ID mismatch (-want +have):\n%s", diff)
+			}
+
+			challenge, err = NewChallenge()
+			if err != nil {
+				t.Fatal(err)
+			}
+			signCount, err := testRP.VerifySignIn(challenge, testAssert(t, key, testRP, challenge, credentialID, 1), cred)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if signCount != 1 {
+				t.Fatalf("want sign count 1, got %d", signCount)
+			}
+		})
+	}
+}
+
+func TestVerifyRegistration_Errors(t *testing.T) {
+	key := newTestES256Key(t)
+	challenge := []byte("challenge")
+	credentialID := []byte("credential-id")
+
+	for _, tc := range []struct {
+		name string
+		resp func() *AttestationResponse
+	}{
+		{
+			name: "other challenge",
+			resp: func() *AttestationResponse {
+				return testAttest(t, key, testRP, []byte("other challenge"), credentialID, testRP.Origin)
+			},
+		},
+		{
+			name: "other origin",
+			resp: func() *AttestationResponse {
+				return testAttest(t, key, testRP, challenge, credentialID, "https://evil.example.com")
+			},
+		},
+		{
+			name: "other relying party",
+			resp: func() *AttestationResponse {
+				other := *testRP
+				other.ID = "evil.example.com"
+				return testAttest(t, key, &other, challenge, credentialID, testRP.Origin)
+			},
+		},
+		{
+			name: "other credential ID",
+			resp: func() *AttestationResponse {
+				resp := testAttest(t, key, testRP, challenge, credentialID, testRP.Origin)
+				resp.RawID = []byte("other")
+				return resp
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := testRP.VerifyRegistration(challenge, tc.resp()); err == nil {
+				t.Fatal("want error, got nil")
+			}
+		})
+	}
+}
+
+func TestVerifySignIn_Errors(t *testing.T) {
+	key := newTestES256Key(t)
+	challenge := []byte("challenge")
+	credentialID := []byte("credential-id")
+	cred := &Credential{ID: credentialID, PublicKey: key.coseKey(), SignCount: 5}
+
+	if _, err := testRP.VerifySignIn([]byte("other challenge"), testAssert(t, key, testRP, challenge, credentialID, 6), cred); err == nil {
+		t.Error("other challenge: want error, got nil")
+	}
+
+	resp := testAssert(t, key, testRP, challenge, credentialID, 6)
+	resp.Response.Signature[len(resp.Response.Signature)-1] ^= 0xff
+	if _, err := testRP.VerifySignIn(challenge, resp, cred); err == nil {
+		t.Error("invalid signature: want error, got nil")
+	}
+
+	other := newTestES256Key(t)
+	if _, err := testRP.VerifySignIn(challenge, testAssert(t, other, testRP, challenge, credentialID, 6), cred); err == nil {
+		t.Error("other key: want error, got nil")
+	}
+
+	if _, err := testRP.VerifySignIn(challenge, testAssert(t, key, testRP, challenge, credentialID, 5), cred); err != ErrSignCount {
+		t.Errorf("sign count: want %v, got %v", ErrSignCount, err)
+	}
+}
+
+func TestBase64URL(t *testing.T) {
+	data, err := json.Marshal(Base64URL{0xfb, 0xff})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := string(data), `"-_8"`; have != want {
+		t.Fatalf("want %s, got %s", want, have)
+	}
+
+	var b Base64URL
+	if err := json.Unmarshal([]byte(`"-_8="`), &b); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(Base64URL{0xfb, 0xff}, b); diff != "" {
+		t.Fatalf("mismatch (-want +have):\n%s", diff)
+	}
+}
+
+// testKey is a software security key.
+type testKey interface {
+	name() string
+	coseKey() []byte
+	sign(t *testing.T, data []byte) []byte
+}
+
+type testES256Key struct{ *ecdsa.PrivateKey }
+
+func newTestES256Key(t *testing.T) testES256Key {
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testES256Key{k}
+}
+
+func (k testES256Key) name() string { return "ES256" }
+
+func (k testES256Key) coseKey() []byte {
+	x, y := make([]byte, 32), make([]byte, 32)
+	k.X.FillBytes(x)
+	k.Y.FillBytes(y)
+	return encodeTestCBOR(map[int64]interface{}{1: int64(2), 3: int64(-7), -1: int64(1), -2: x, -3: y})
+}
+
+func (k testES256Key) sign(t *testing.T, data []byte) []byte {
+	h := sha256.Sum256(data)
+	sig, err := ecdsa.SignASN1(rand.Reader, k.PrivateKey, h[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sig
+}
+
+type testEdDSAKey struct{ ed25519.PrivateKey }
+
+func newTestEdDSAKey(t *testing.T) testEdDSAKey {
+	_, k, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testEdDSAKey{k}
+}
+
+func (k testEdDSAKey) name() string { return "EdDSA" }
+
+func (k testEdDSAKey) coseKey() []byte {
+	return encodeTestCBOR(map[int64]interface{}{1: int64(1), 3: int64(-8), -1: int64(6), -2: []byte(k.Public().(ed25519.PublicKey))})
+}
+
+func (k testEdDSAKey) sign(t *testing.T, data []byte) []byte {
+	return ed25519.Sign(k.PrivateKey, data)
+}
+
+// testAttest registers the key with the relying party, the way a security key does.
+func testAttest(t *testing.T, k testKey, rp *RelyingParty, challenge, credentialID []byte, origin string) *AttestationResponse {
+	authData := testAuthData(rp, flagUserPresent|flagAttestedCredentialData, 0)
+	authData = append(authData, make([]byte, 16)...) // AAGUID
+	authData = append(authData, byte(len(credentialID)>>8), byte(len(credentialID)))
+	authData = append(authData, credentialID...)
+	authData = append(authData, k.coseKey()...)
+
+	var resp AttestationResponse
+	resp.RawID = credentialID
+	resp.Response.ClientDataJSON = testClientData(t, "webauthn.create", challenge, origin)
+	resp.Response.AttestationObject = encodeTestCBOR(map[string]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[string]interface{}{},
+		"authData": authData,
+	})
+	return &resp
+}
+
+// testAssert signs in with the key, the way a security key does.
+func testAssert(t *testing.T, k testKey, rp *RelyingParty, challenge, credentialID []byte, signCount uint32) *AssertionResponse {
+	var resp AssertionResponse
+	resp.RawID = credentialID
+	resp.Response.ClientDataJSON = testClientData(t, "webauthn.get", challenge, rp.Origin)
+	resp.Response.AuthenticatorData = testAuthData(rp, flagUserPresent, signCount)
+	clientDataHash := sha256.Sum256(resp.Response.ClientDataJSON)
+	resp.Response.Signature = k.sign(t, append(append([]byte(nil), resp.Response.AuthenticatorData...), clientDataHash[:]...))
+	return &resp
+}
+
+func testAuthData(rp *RelyingParty, flags byte, signCount uint32) []byte {
+	rpIDHash := sha256.Sum256([]byte(rp.ID))
+	authData := append(rpIDHash[:], flags, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(authData[33:], signCount)
+	return authData
+}
+
+func testClientData(t *testing.T, typ string, challenge []byte, origin string) []byte {
+	data, err := json.Marshal(clientData{
+		Type:      typ,
+		Challenge: base64.RawURLEncoding.EncodeToString(challenge),
+		Origin:    origin,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// encodeTestCBOR encodes the subset of CBOR values used in tests.
+func encodeTestCBOR(v interface{}) []byte {
+	head := func(major byte, n uint64) []byte {
+		switch {
+		case n < 24:
+			return []byte{major<<5 | byte(n)}
+		case n < 1<<8:
+			return []byte{major<<5 | 24, byte(n)}
+		default:
+			return []byte{major<<5 | 25, byte(n >> 8), byte(n)}
+		}
+	}
+
+	switch v := v.(type) {
+	case int64:
+		if v < 0 {
+			return head(1, uint64(-1-v))
+		}
+		return head(0, uint64(v))
+	case []byte:
+		return append(head(2, uint64(len(v))), v...)
+	case string:
+		return append(head(3, uint64(len(v))), v...)
+	case map[int64]interface{}:
+		keys := make([]int64, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+		b := head(5, uint64(len(v)))
+		for _, k := range keys {
+			b = append(b, encodeTestCBOR(k)...)
+			b = append(b, encodeTestCBOR(v[k])...)
+		}
+		return b
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b := head(5, uint64(len(v)))
+		for _, k := range keys {
+			b = append(b, encodeTestCBOR(k)...)
+			b = append(b, encodeTestCBOR(v[k])...)
+		}
+		return b
+	}
+	panic("unsupported type")
+}
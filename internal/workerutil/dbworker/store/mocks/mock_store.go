@@ -48,6 +48,9 @@ type MockStore struct {
 	// ResetStalledFunc is an instance of a mock function object controlling
 	// the behavior of the method ResetStalled.
 	ResetStalledFunc *StoreResetStalledFunc
+	// ResetWorkersFunc is an instance of a mock function object controlling
+	// the behavior of the method ResetWorkers.
+	ResetWorkersFunc *StoreResetWorkersFunc
 	// UpdateExecutionLogEntryFunc is an instance of a mock function object
 	// controlling the behavior of the method UpdateExecutionLogEntry.
 	UpdateExecutionLogEntryFunc *StoreUpdateExecutionLogEntryFunc
@@ -110,6 +113,11 @@ func NewMockStore() *MockStore {
 				return nil, nil, nil
 			},
 		},
+		ResetWorkersFunc: &StoreResetWorkersFunc{
+			defaultHook: func(context.Context, []string) ([]int, []int, error) {
+				return nil, nil, nil
+			},
+		},
 		UpdateExecutionLogEntryFunc: &StoreUpdateExecutionLogEntryFunc{
 			defaultHook: func(context.Context, int, int, workerutil.ExecutionLogEntry, store.ExecutionLogEntryOptions) error {
 				return nil
@@ -157,6 +165,9 @@ func NewMockStoreFrom(i store.Store) *MockStore {
 		ResetStalledFunc: &StoreResetStalledFunc{
 			defaultHook: i.ResetStalled,
 		},
+		ResetWorkersFunc: &StoreResetWorkersFunc{
+			defaultHook: i.ResetWorkers,
+		},
 		UpdateExecutionLogEntryFunc: &StoreUpdateExecutionLogEntryFunc{
 			defaultHook: i.UpdateExecutionLogEntry,
 		},
@@ -1271,6 +1282,117 @@ func (c StoreResetStalledFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
+// StoreResetWorkersFunc describes the behavior when the ResetWorkers method
+// of the parent MockStore instance is invoked.
+type StoreResetWorkersFunc struct {
+	defaultHook func(context.Context, []string) ([]int, []int, error)
+	hooks       []func(context.Context, []string) ([]int, []int, error)
+	history     []StoreResetWorkersFuncCall
+	mutex       sync.Mutex
+}
+
+// ResetWorkers delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockStore) ResetWorkers(v0 context.Context, v1 []string) ([]int, []int, error) {
+	r0, r1, r2 := m.ResetWorkersFunc.nextHook()(v0, v1)
+	m.ResetWorkersFunc.appendCall(StoreResetWorkersFuncCall{v0, v1, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the ResetWorkers method
+// of the parent MockStore instance is invoked and the hook queue is empty.
+func (f *StoreResetWorkersFunc) SetDefaultHook(hook func(context.Context, []string) ([]int, []int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ResetWorkers method of the parent MockStore instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *StoreResetWorkersFunc) PushHook(hook func(context.Context, []string) ([]int, []int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *StoreResetWorkersFunc) SetDefaultReturn(r0 []int, r1 []int, r2 error) {
+	f.SetDefaultHook(func(context.Context, []string) ([]int, []int, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *StoreResetWorkersFunc) PushReturn(r0 []int, r1 []int, r2 error) {
+	f.PushHook(func(context.Context, []string) ([]int, []int, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *StoreResetWorkersFunc) nextHook() func(context.Context, []string) ([]int, []int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreResetWorkersFunc) appendCall(r0 StoreResetWorkersFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreResetWorkersFuncCall objects
+// describing the invocations of this function.
+func (f *StoreResetWorkersFunc) History() []StoreResetWorkersFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreResetWorkersFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreResetWorkersFuncCall is an object that describes an invocation of
+// method ResetWorkers on an instance of MockStore.
+type StoreResetWorkersFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 []string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 []int
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreResetWorkersFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreResetWorkersFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
 // StoreUpdateExecutionLogEntryFunc describes the behavior when the
 // UpdateExecutionLogEntry method of the parent MockStore instance is
 // invoked.
@@ -17,6 +17,7 @@ type operations struct {
 	markErrored             *observation.Operation
 	markFailed              *observation.Operation
 	resetStalled            *observation.Operation
+	resetWorkers            *observation.Operation
 	heartbeat               *observation.Operation
 }
 
@@ -46,6 +47,7 @@ func newOperations(storeName string, observationContext *observation.Context) *o
 		markErrored:             op("MarkErrored"),
 		markFailed:              op("MarkFailed"),
 		resetStalled:            op("ResetStalled"),
+		resetWorkers:            op("ResetWorkers"),
 		heartbeat:               op("Heartbeat"),
 	}
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/derision-test/glock"
 	"github.com/inconshreveable/log15"
 	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
 	"github.com/opentracing/opentracing-go/log"
 
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
@@ -125,6 +126,13 @@ type Store interface {
 	// identifiers the age of the record's last heartbeat timestamp for each record reset to queued and failed states,
 	// respectively.
 	ResetStalled(ctx context.Context) (resetLastHeartbeatsByIDs, failedLastHeartbeatsByIDs map[int]time.Duration, err error)
+
+	// ResetWorkers moves all processing records dequeued by one of the given workers back to the queued state,
+	// regardless of their last heartbeat. It's used to requeue the records of workers that are known to be dead
+	// before the records are considered stalled. Records that have been reset more than `MaxNumResets` times are
+	// marked as failed. This method returns the identifiers of the records reset to queued and failed states,
+	// respectively.
+	ResetWorkers(ctx context.Context, workerHostnames []string) (resetIDs, failedIDs []int, err error)
 }
 
 type ExecutionLogEntry workerutil.ExecutionLogEntry
@@ -902,6 +910,98 @@ WHERE {id} IN (SELECT {id} FROM stalled)
 RETURNING {id}, {last_heartbeat_at}
 `
 
+// ResetWorkers moves all processing records dequeued by one of the given workers back to the queued state,
+// regardless of their last heartbeat. It's used to requeue the records of workers that are known to be dead
+// before the records are considered stalled. Records that have been reset more than `MaxNumResets` times are
+// marked as failed. This method returns the identifiers of the records reset to queued and failed states,
+// respectively.
+func (s *store) ResetWorkers(ctx context.Context, workerHostnames []string) (resetIDs, failedIDs []int, err error) {
+	ctx, traceLog, endObservation := s.operations.resetWorkers.WithAndLogger(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("numWorkerHostnames", len(workerHostnames)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	if len(workerHostnames) == 0 {
+		return nil, nil, nil
+	}
+
+	resetIDs, err = basestore.ScanInts(s.Query(
+		ctx,
+		s.formatQuery(
+			resetWorkersQuery,
+			quote(s.options.TableName),
+			pq.Array(workerHostnames),
+			s.options.MaxNumResets,
+			quote(s.options.TableName),
+		),
+	))
+	if err != nil {
+		return resetIDs, failedIDs, err
+	}
+	traceLog(log.Int("numResetIDs", len(resetIDs)))
+
+	resetFailureMessage := s.options.ResetFailureMessage
+	if resetFailureMessage == "" {
+		resetFailureMessage = defaultResetFailureMessage
+	}
+
+	failedIDs, err = basestore.ScanInts(s.Query(
+		ctx,
+		s.formatQuery(
+			resetWorkersMaxResetsQuery,
+			quote(s.options.TableName),
+			pq.Array(workerHostnames),
+			s.options.MaxNumResets,
+			quote(s.options.TableName),
+			resetFailureMessage,
+		),
+	))
+	if err != nil {
+		return resetIDs, failedIDs, err
+	}
+	traceLog(log.Int("numErroredIDs", len(failedIDs)))
+
+	return resetIDs, failedIDs, nil
+}
+
+const resetWorkersQuery = `
+-- source: internal/workerutil/store.go:ResetWorkers
+WITH dead AS (
+	SELECT {id} FROM %s
+	WHERE
+		{state} = 'processing' AND
+		{worker_hostname} = ANY(%s) AND
+		{num_resets} < %s
+	FOR UPDATE SKIP LOCKED
+)
+UPDATE %s
+SET
+	{state} = 'queued',
+	{started_at} = null,
+	{num_resets} = {num_resets} + 1
+WHERE {id} IN (SELECT {id} FROM dead)
+RETURNING {id}
+`
+
+const resetWorkersMaxResetsQuery = `
+-- source: internal/workerutil/store.go:ResetWorkers
+WITH dead AS (
+	SELECT {id} FROM %s
+	WHERE
+		{state} = 'processing' AND
+		{worker_hostname} = ANY(%s) AND
+		{num_resets} >= %s
+	FOR UPDATE SKIP LOCKED
+)
+UPDATE %s
+SET
+	{state} = 'failed',
+	{finished_at} = clock_timestamp(),
+	{failure_message} = %s
+WHERE {id} IN (SELECT {id} FROM dead)
+RETURNING {id}
+`
+
 func (s *store) formatQuery(query string, args ...interface{}) *sqlf.Query {
 	return sqlf.Sprintf(s.columnReplacer.Replace(query), args...)
 }
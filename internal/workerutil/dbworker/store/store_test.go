@@ -953,6 +953,44 @@ func TestStoreResetStalled(t *testing.T) {
 	}
 }
 
+func TestStoreResetWorkers(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state, worker_hostname, last_heartbeat_at, num_resets)
+		VALUES
+			(1, 'processing', 'worker1', NOW(), 0),
+			(2, 'processing', 'worker2', NOW(), 0),
+			(3, 'completed', 'worker1', NOW(), 0),
+			(4, 'processing', 'worker3', NOW(), 1),
+			(5, 'processing', 'worker1', NOW(), 5)
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	resetIDs, failedIDs, err := testStore(db, defaultTestStoreOptions(nil)).ResetWorkers(context.Background(), []string{"worker1", "worker3"})
+	if err != nil {
+		t.Fatalf("unexpected error resetting records of workers: %s", err)
+	}
+	sort.Ints(resetIDs)
+	sort.Ints(failedIDs)
+
+	if diff := cmp.Diff([]int{1, 4}, resetIDs); diff != "" {
+		t.Errorf("unexpected reset ids (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int{5}, failedIDs); diff != "" {
+		t.Errorf("unexpected failed ids (-want +got):\n%s", diff)
+	}
+
+	states, err := basestore.ScanStrings(db.QueryContext(context.Background(), `SELECT state FROM workerutil_test ORDER BY id`))
+	if err != nil {
+		t.Fatalf("unexpected error querying records: %s", err)
+	}
+	if diff := cmp.Diff([]string{"queued", "processing", "completed", "queued", "failed"}, states); diff != "" {
+		t.Errorf("unexpected states (-want +got):\n%s", diff)
+	}
+}
+
 func TestStoreHeartbeat(t *testing.T) {
 	db := setupStoreTest(t)
 
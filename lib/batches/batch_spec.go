@@ -10,6 +10,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/lib/batches/env"
 	"github.com/sourcegraph/sourcegraph/lib/batches/overridable"
 	"github.com/sourcegraph/sourcegraph/lib/batches/schema"
+	"github.com/sourcegraph/sourcegraph/lib/batches/template"
 	"github.com/sourcegraph/sourcegraph/lib/batches/yaml"
 )
 
@@ -41,11 +42,28 @@ type BatchSpec struct {
 }
 
 type ChangesetTemplate struct {
-	Title     string                       `json:"title,omitempty" yaml:"title"`
-	Body      string                       `json:"body,omitempty" yaml:"body"`
-	Branch    string                       `json:"branch,omitempty" yaml:"branch"`
-	Commit    ExpandedGitCommitDescription `json:"commit,omitempty" yaml:"commit"`
-	Published *overridable.BoolOrString    `json:"published" yaml:"published"`
+	Title                string                       `json:"title,omitempty" yaml:"title"`
+	Body                 string                       `json:"body,omitempty" yaml:"body"`
+	Branch               string                       `json:"branch,omitempty" yaml:"branch"`
+	Commit               ExpandedGitCommitDescription `json:"commit,omitempty" yaml:"commit"`
+	Published            *overridable.BoolOrString    `json:"published" yaml:"published"`
+	AutoRebase           bool                         `json:"autoRebase,omitempty" yaml:"autoRebase"`
+	AutoArchiveAfterDays int                          `json:"autoArchiveAfterDays,omitempty" yaml:"autoArchiveAfterDays"`
+	GitLab               *GitLabChangesetTemplate     `json:"gitlab,omitempty" yaml:"gitlab,omitempty"`
+}
+
+// GitLabChangesetTemplate holds the options that only apply to merge requests
+// created on GitLab.
+type GitLabChangesetTemplate struct {
+	Reviewers               []string             `json:"reviewers,omitempty" yaml:"reviewers"`
+	ReviewersFromCodeOwners bool                 `json:"reviewersFromCodeOwners,omitempty" yaml:"reviewersFromCodeOwners"`
+	ApprovalRules           []GitLabApprovalRule `json:"approvalRules,omitempty" yaml:"approvalRules"`
+}
+
+type GitLabApprovalRule struct {
+	Name              string   `json:"name" yaml:"name"`
+	ApprovalsRequired int      `json:"approvalsRequired" yaml:"approvalsRequired"`
+	Users             []string `json:"users,omitempty" yaml:"users"`
 }
 
 type GitCommitAuthor struct {
@@ -162,6 +180,18 @@ func parseBatchSpec(schema string, data []byte, opts ParseBatchSpecOptions) (*Ba
 		errs = multierror.Append(errs, NewValidationError(errors.New("batch spec includes steps but no changesetTemplate")))
 	}
 
+	if spec.ChangesetTemplate != nil {
+		for _, field := range []struct{ name, tmpl string }{
+			{"title", spec.ChangesetTemplate.Title},
+			{"body", spec.ChangesetTemplate.Body},
+			{"branch", spec.ChangesetTemplate.Branch},
+		} {
+			if err := template.ValidateChangesetTemplateField(field.name, field.tmpl); err != nil {
+				errs = multierror.Append(errs, NewValidationError(errors.Wrapf(err, "changesetTemplate.%s is invalid", field.name)))
+			}
+		}
+	}
+
 	if spec.TransformChanges != nil && !opts.AllowTransformChanges {
 		errs = multierror.Append(errs, NewValidationError(errors.New("batch spec includes transformChanges, which is not supported in this Sourcegraph version")))
 	}
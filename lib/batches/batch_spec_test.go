@@ -49,6 +49,39 @@ steps:
 		wantErr := `1 error occurred:
 	* batch spec includes steps but no changesetTemplate
 
+`
+		haveErr := err.Error()
+		if haveErr != wantErr {
+			t.Fatalf("wrong error. want=%q, have=%q", wantErr, haveErr)
+		}
+	})
+
+	t.Run("unknown changesetTemplate variable", func(t *testing.T) {
+		const spec = `
+name: hello-world
+description: Add Hello World to READMEs
+on:
+  - repositoriesMatchingQuery: file:README.md
+steps:
+  - run: echo Hello World | tee -a $(find -name README.md)
+    container: alpine:3
+changesetTemplate:
+  title: Hello World for ${{ repository.language }}
+  body: Owned by ${{ join repository.owners ", " }} in ${{ repository.tags.team }}
+  branch: hello-world-${{ repository.owner }}
+  commit:
+    message: Append Hello World to all README.md files
+  published: false
+`
+
+		_, err := ParseBatchSpec([]byte(spec), ParseBatchSpecOptions{})
+		if err == nil {
+			t.Fatal("no error returned")
+		}
+
+		wantErr := `1 error occurred:
+	* changesetTemplate.branch is invalid: template "branch" references unknown variables: repository.owner
+
 `
 		haveErr := err.Error()
 		if haveErr != wantErr {
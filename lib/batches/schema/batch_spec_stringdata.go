@@ -339,6 +339,64 @@ const BatchSpecJSON = `{
               }
             }
           ]
+        },
+        "autoRebase": {
+          "description": "Whether to automatically rebase the changesets onto their base branch when it advances. The diff is reapplied on top of the new base branch head and force-pushed. Changesets whose diff no longer applies cleanly are marked as failed.",
+          "type": "boolean",
+          "default": false
+        },
+        "autoArchiveAfterDays": {
+          "description": "The number of days after which merged and closed changesets are automatically archived. The days are counted from the last update of the changeset on the code host. If omitted, changesets are not archived automatically.",
+          "type": "integer",
+          "minimum": 1
+        },
+        "gitlab": {
+          "title": "GitLabChangesetTemplate",
+          "type": "object",
+          "description": "Options that only apply to merge requests created on GitLab. They are set when the merge request is created and ignored for changesets on other code hosts.",
+          "additionalProperties": false,
+          "properties": {
+            "reviewers": {
+              "type": "array",
+              "description": "The usernames of the GitLab users that are requested to review the merge request.",
+              "items": {
+                "type": "string"
+              }
+            },
+            "reviewersFromCodeOwners": {
+              "type": "boolean",
+              "description": "Also request reviews from the code owners of the changed files, resolved from the uploaded ownership manifest or CODEOWNERS file of the repository. Only owners that are usernames are requested, teams and email addresses are skipped.",
+              "default": false
+            },
+            "approvalRules": {
+              "type": "array",
+              "description": "Approval rules to add to the merge request. Requires a GitLab edition that supports merge request approval rules.",
+              "items": {
+                "title": "GitLabApprovalRule",
+                "type": "object",
+                "additionalProperties": false,
+                "required": ["name", "approvalsRequired"],
+                "properties": {
+                  "name": {
+                    "type": "string",
+                    "description": "The name of the approval rule."
+                  },
+                  "approvalsRequired": {
+                    "type": "integer",
+                    "description": "The number of approvals required by the rule.",
+                    "minimum": 0
+                  },
+                  "users": {
+                    "type": "array",
+                    "description": "The usernames of the GitLab users that are eligible to approve under this rule.",
+                    "items": {
+                      "type": "string"
+                    }
+                  }
+                }
+              }
+            }
+          }
         }
       }
     }
@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strings"
 	"text/template"
+	"text/template/parse"
 
 	"github.com/cockroachdb/errors"
 	"github.com/gobwas/glob"
@@ -89,6 +90,15 @@ type BatchChangeAttributes struct {
 type Repository struct {
 	Name        string
 	FileMatches []string
+
+	// Tags are the key-value pairs of custom metadata of the repository. Tags
+	// without a value map to the empty string.
+	Tags map[string]string
+	// Owners are the owners of the workspace according to the ownership rules
+	// of the repository, such as its CODEOWNERS file.
+	Owners []string
+	// Language is the primary language of the repository.
+	Language string
 }
 
 func (r Repository) SearchResultPaths() (list fileMatchPathList) {
@@ -255,9 +265,21 @@ type ChangesetTemplateContext struct {
 func (tmplCtx *ChangesetTemplateContext) ToFuncMap() template.FuncMap {
 	return template.FuncMap{
 		"repository": func() map[string]interface{} {
+			tags := tmplCtx.Repository.Tags
+			if tags == nil {
+				tags = map[string]string{}
+			}
+			owners := tmplCtx.Repository.Owners
+			if owners == nil {
+				owners = []string{}
+			}
+
 			return map[string]interface{}{
 				"search_result_paths": tmplCtx.Repository.SearchResultPaths(),
 				"name":                tmplCtx.Repository.Name,
+				"tags":                tags,
+				"owners":              owners,
+				"language":            tmplCtx.Repository.Language,
 			}
 		},
 		"batch_change": func() map[string]interface{} {
@@ -299,3 +321,95 @@ func RenderChangesetTemplateField(name, tmpl string, tmplCtx *ChangesetTemplateC
 
 	return strings.TrimSpace(out.String()), nil
 }
+
+// changesetTemplateVariables are the fields available on the variables of a
+// ChangesetTemplateContext. A nil slice means that any field is accepted.
+var changesetTemplateVariables = map[string][]string{
+	"repository":   {"search_result_paths", "name", "tags", "owners", "language"},
+	"batch_change": {"name", "description"},
+	"outputs":      nil,
+	"steps":        {"modified_files", "added_files", "deleted_files", "renamed_files", "path"},
+}
+
+// ValidateChangesetTemplateField returns an error if the given template of a
+// field of the ChangesetTemplate does not parse or references a variable that
+// is not available in a ChangesetTemplateContext. Unknown variables would
+// otherwise silently render as "<no value>".
+func ValidateChangesetTemplateField(name, tmpl string) error {
+	t, err := template.New(name).Delims(startDelim, endDelim).Funcs(builtins).Funcs((&ChangesetTemplateContext{}).ToFuncMap()).Parse(tmpl)
+	if err != nil {
+		return err
+	}
+	if t.Tree == nil {
+		return nil
+	}
+
+	var unknown []string
+	walkTemplateNodes(t.Tree.Root, func(n parse.Node) {
+		chain, ok := n.(*parse.ChainNode)
+		if !ok || len(chain.Field) == 0 {
+			return
+		}
+		ident, ok := chain.Node.(*parse.IdentifierNode)
+		if !ok {
+			return
+		}
+		fields, ok := changesetTemplateVariables[ident.Ident]
+		if !ok || fields == nil {
+			return
+		}
+		for _, field := range fields {
+			if field == chain.Field[0] {
+				return
+			}
+		}
+		unknown = append(unknown, ident.Ident+"."+chain.Field[0])
+	})
+
+	if len(unknown) > 0 {
+		return errors.Errorf("template %q references unknown variables: %s", name, strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// walkTemplateNodes calls fn for n and all of the nodes nested in it.
+func walkTemplateNodes(n parse.Node, fn func(parse.Node)) {
+	fn(n)
+
+	switch n := n.(type) {
+	case *parse.ListNode:
+		for _, node := range n.Nodes {
+			walkTemplateNodes(node, fn)
+		}
+	case *parse.ActionNode:
+		walkTemplateNodes(n.Pipe, fn)
+	case *parse.PipeNode:
+		for _, cmd := range n.Cmds {
+			walkTemplateNodes(cmd, fn)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			walkTemplateNodes(arg, fn)
+		}
+	case *parse.ChainNode:
+		walkTemplateNodes(n.Node, fn)
+	case *parse.IfNode:
+		walkTemplateBranch(&n.BranchNode, fn)
+	case *parse.RangeNode:
+		walkTemplateBranch(&n.BranchNode, fn)
+	case *parse.WithNode:
+		walkTemplateBranch(&n.BranchNode, fn)
+	case *parse.TemplateNode:
+		if n.Pipe != nil {
+			walkTemplateNodes(n.Pipe, fn)
+		}
+	}
+}
+
+func walkTemplateBranch(n *parse.BranchNode, fn func(parse.Node)) {
+	walkTemplateNodes(n.Pipe, fn)
+	walkTemplateNodes(n.List, fn)
+	if n.ElseList != nil {
+		walkTemplateNodes(n.ElseList, fn)
+	}
+}
@@ -325,6 +325,28 @@ CGO_ENABLED=0
 [deleted-file.txt]
 [renamed-file.txt]
 infrastructure/sub-project`,
+		},
+		{
+			name: "repository metadata",
+			tmplCtx: &ChangesetTemplateContext{
+				Repository: Repository{
+					Name:     "github.com/sourcegraph/src-cli",
+					Tags:     map[string]string{"team": "batchers", "deprecated": ""},
+					Owners:   []string{"@sourcegraph/batchers", "@mrnugget"},
+					Language: "Go",
+				},
+			},
+			tmpl: `${{ repository.tags.team }}
+${{ index repository.tags "deprecated" }}
+${{ join repository.owners ", " }}
+${{ repository.language }}
+${{ if eq repository.language "Go" }}gopher${{ end }}
+`,
+			want: `batchers
+
+@sourcegraph/batchers, @mrnugget
+Go
+gopher`,
 		},
 		{
 			name:    "empty context",
@@ -360,3 +382,57 @@ ${{ steps.renamed_files }}
 		})
 	}
 }
+
+func TestValidateChangesetTemplateField(t *testing.T) {
+	tests := []struct {
+		name    string
+		tmpl    string
+		wantErr string
+	}{
+		{
+			name: "no variables",
+			tmpl: "Update dependencies",
+		},
+		{
+			name: "known variables",
+			tmpl: `${{ repository.name }} ${{ repository.tags.team }} ${{ join repository.owners " " }} ${{ repository.language }} ${{ batch_change.name }} ${{ steps.path }}`,
+		},
+		{
+			name: "any outputs",
+			tmpl: `${{ outputs.whatever.nested }}`,
+		},
+		{
+			name:    "nested in control structures",
+			tmpl:    `${{ if eq repository.language "Go" }}${{ range repository.owners }}${{ . }}${{ end }}${{ else }}${{ repository.maintainers }}${{ end }}`,
+			wantErr: `template "title" references unknown variables: repository.maintainers`,
+		},
+		{
+			name:    "unknown fields",
+			tmpl:    `${{ repository.tag.team }} ${{ batch_change.title }}`,
+			wantErr: `template "title" references unknown variables: repository.tag, batch_change.title`,
+		},
+		{
+			name:    "unknown function",
+			tmpl:    `${{ repo.name }}`,
+			wantErr: `template: title:1: function "repo" not defined`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateChangesetTemplateField("title", tc.tmpl)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error %q, got none", tc.wantErr)
+			}
+			if err.Error() != tc.wantErr {
+				t.Fatalf("wrong error:\n%s", cmp.Diff(tc.wantErr, err.Error()))
+			}
+		})
+	}
+}
@@ -18,6 +18,16 @@ type WorkspaceRepo struct {
 	// ID is the GraphQL ID of the repository.
 	ID   string `json:"id"`
 	Name string `json:"name"`
+
+	// Tags are the key-value pairs of custom metadata of the repository.
+	// Tags without a value map to the empty string.
+	Tags map[string]string `json:"tags,omitempty"`
+	// Owners are the owners of the workspace according to the CODEOWNERS
+	// file or ownership manifest of the repository.
+	Owners []string `json:"owners,omitempty"`
+	// Language is the primary language of the repository at the workspace
+	// commit.
+	Language string `json:"language,omitempty"`
 }
 
 type WorkspaceBranch struct {
@@ -0,0 +1,37 @@
+BEGIN;
+
+-- When the Postgres storage backend is used (i.e. the timescaledb extension is not installed),
+-- series_points is a regular table. Convert it into a table that is natively partitioned by time.
+-- Monthly partitions are created ahead of time by the code insights background workers, all other
+-- points end up in the default partition.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'timescaledb') THEN
+        ALTER TABLE series_points RENAME TO series_points_unpartitioned;
+
+        CREATE TABLE series_points (
+            LIKE series_points_unpartitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS INCLUDING COMMENTS
+        ) PARTITION BY RANGE (time);
+
+        CREATE TABLE series_points_default PARTITION OF series_points DEFAULT;
+
+        EXECUTE format('COMMENT ON TABLE series_points IS %L', obj_description('series_points_unpartitioned'::regclass, 'pg_class'));
+
+        INSERT INTO series_points SELECT * FROM series_points_unpartitioned;
+        DROP TABLE series_points_unpartitioned;
+
+        ALTER TABLE series_points ADD FOREIGN KEY (metadata_id) REFERENCES metadata(id) ON DELETE CASCADE DEFERRABLE;
+        ALTER TABLE series_points ADD FOREIGN KEY (repo_name_id) REFERENCES repo_names(id) ON DELETE CASCADE DEFERRABLE;
+        ALTER TABLE series_points ADD FOREIGN KEY (original_repo_name_id) REFERENCES repo_names(id) ON DELETE CASCADE DEFERRABLE;
+
+        CREATE INDEX series_points_time_idx ON series_points USING btree (time DESC);
+        CREATE INDEX series_points_repo_id_btree ON series_points USING btree (repo_id);
+        CREATE INDEX series_points_repo_name_id_btree ON series_points USING btree (repo_name_id);
+        CREATE INDEX series_points_original_repo_name_id_btree ON series_points USING btree (original_repo_name_id);
+        CREATE INDEX series_points_series_id_btree ON series_points USING btree (series_id);
+        CREATE INDEX series_points_series_id_repo_id_time_idx ON series_points (series_id, repo_id, time);
+    END IF;
+END
+$$;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE insight_series DROP COLUMN IF EXISTS backfill_estimated_cost;
+ALTER TABLE insight_series DROP COLUMN IF EXISTS backfill_spent_cost;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE insight_series ADD COLUMN IF NOT EXISTS backfill_estimated_cost INT;
+ALTER TABLE insight_series ADD COLUMN IF NOT EXISTS backfill_spent_cost INT NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN insight_series.backfill_estimated_cost IS 'The estimated number of historical search queries (repositories times frames) required to backfill this series. Null if not estimated yet.';
+COMMENT ON COLUMN insight_series.backfill_spent_cost IS 'The number of historical search queries that have been enqueued so far to backfill this series.';
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP INDEX IF EXISTS series_points_series_id_capture_idx;
+
+ALTER TABLE series_points_snapshots DROP COLUMN IF EXISTS capture;
+ALTER TABLE series_points DROP COLUMN IF EXISTS capture;
+ALTER TABLE insight_series DROP COLUMN IF EXISTS generated_from_capture_groups;
+
+COMMIT;
@@ -0,0 +1,17 @@
+BEGIN;
+
+ALTER TABLE insight_series ADD COLUMN IF NOT EXISTS generated_from_capture_groups BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN insight_series.generated_from_capture_groups IS 'Whether this series generates one data series per distinct value of the regexp capture group in its query.';
+
+-- series_points and series_points_snapshots are queried with a UNION of all columns, so both
+-- tables must keep the same column order.
+ALTER TABLE series_points ADD COLUMN IF NOT EXISTS capture TEXT;
+ALTER TABLE series_points_snapshots ADD COLUMN IF NOT EXISTS capture TEXT;
+
+COMMENT ON COLUMN series_points.capture IS 'The value of the regexp capture group this point was recorded for, if the series is generated from capture groups.';
+COMMENT ON COLUMN series_points_snapshots.capture IS 'The value of the regexp capture group this point was recorded for, if the series is generated from capture groups.';
+
+CREATE INDEX IF NOT EXISTS series_points_series_id_capture_idx ON series_points (series_id, capture) WHERE capture IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE IF EXISTS insight_series_alerts;
+
+COMMIT;
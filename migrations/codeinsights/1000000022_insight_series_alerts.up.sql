@@ -0,0 +1,30 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS insight_series_alerts (
+    id SERIAL PRIMARY KEY,
+    series_id TEXT NOT NULL,
+    user_id INT NOT NULL,
+    threshold DOUBLE PRECISION NOT NULL,
+    direction TEXT NOT NULL,
+    evaluation_window_days INT NOT NULL DEFAULT 0,
+    email BOOLEAN NOT NULL DEFAULT TRUE,
+    webhook_url TEXT,
+    triggered BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_evaluated_at TIMESTAMP,
+    last_triggered_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS insight_series_alerts_series_id_idx ON insight_series_alerts (series_id);
+CREATE INDEX IF NOT EXISTS insight_series_alerts_user_id_idx ON insight_series_alerts (user_id);
+
+COMMENT ON TABLE insight_series_alerts IS 'Alert definitions that notify a user when an insight series crosses a threshold.';
+COMMENT ON COLUMN insight_series_alerts.series_id IS 'The unique series ID (insight_series.series_id) the alert is evaluated for.';
+COMMENT ON COLUMN insight_series_alerts.user_id IS 'The user (in the main Sourcegraph database) that owns the alert. The series is evaluated with the repository permissions of this user.';
+COMMENT ON COLUMN insight_series_alerts.direction IS 'AT_LEAST or AT_MOST: whether the alert triggers when the evaluated value is greater than or equal to, or less than or equal to the threshold.';
+COMMENT ON COLUMN insight_series_alerts.evaluation_window_days IS 'If zero, the latest value of the series is evaluated. Otherwise, the change of the series over this number of days is evaluated.';
+COMMENT ON COLUMN insight_series_alerts.email IS 'Whether to notify the owner of the alert by email.';
+COMMENT ON COLUMN insight_series_alerts.webhook_url IS 'If set, a JSON payload is posted to this URL when the alert triggers.';
+COMMENT ON COLUMN insight_series_alerts.triggered IS 'Whether the alert condition held at the last evaluation. Notifications are only sent when this changes from false to true.';
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+-- Move the aggregated points back so that no data is lost.
+INSERT INTO series_points SELECT * FROM series_points_weekly;
+INSERT INTO series_points SELECT * FROM series_points_daily;
+
+DROP TABLE IF EXISTS series_points_weekly;
+DROP TABLE IF EXISTS series_points_daily;
+
+COMMIT;
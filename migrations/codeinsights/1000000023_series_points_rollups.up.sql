@@ -0,0 +1,30 @@
+BEGIN;
+
+-- The rollup tables have the same columns, in the same order, as series_points so that they can be
+-- queried together with a UNION.
+CREATE TABLE IF NOT EXISTS series_points_daily (
+    LIKE series_points INCLUDING DEFAULTS INCLUDING CONSTRAINTS
+);
+
+CREATE TABLE IF NOT EXISTS series_points_weekly (
+    LIKE series_points INCLUDING DEFAULTS INCLUDING CONSTRAINTS
+);
+
+CREATE INDEX IF NOT EXISTS series_points_daily_series_id_repo_id_time_idx ON series_points_daily (series_id, repo_id, time);
+CREATE INDEX IF NOT EXISTS series_points_daily_time_idx ON series_points_daily (time DESC);
+CREATE INDEX IF NOT EXISTS series_points_daily_repo_name_id_idx ON series_points_daily (repo_name_id);
+CREATE INDEX IF NOT EXISTS series_points_weekly_series_id_repo_id_time_idx ON series_points_weekly (series_id, repo_id, time);
+CREATE INDEX IF NOT EXISTS series_points_weekly_time_idx ON series_points_weekly (time DESC);
+CREATE INDEX IF NOT EXISTS series_points_weekly_repo_name_id_idx ON series_points_weekly (repo_name_id);
+
+ALTER TABLE series_points_daily ADD FOREIGN KEY (metadata_id) REFERENCES metadata(id) ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE series_points_daily ADD FOREIGN KEY (repo_name_id) REFERENCES repo_names(id) ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE series_points_daily ADD FOREIGN KEY (original_repo_name_id) REFERENCES repo_names(id) ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE series_points_weekly ADD FOREIGN KEY (metadata_id) REFERENCES metadata(id) ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE series_points_weekly ADD FOREIGN KEY (repo_name_id) REFERENCES repo_names(id) ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE series_points_weekly ADD FOREIGN KEY (original_repo_name_id) REFERENCES repo_names(id) ON DELETE CASCADE DEFERRABLE;
+
+COMMENT ON TABLE series_points_daily IS 'Daily aggregates of series_points older than the configured raw retention. There is one point per series, repository and capture group value per day, holding the maximum value recorded that day. The time is the start of the day (UTC).';
+COMMENT ON TABLE series_points_weekly IS 'Weekly aggregates of series_points_daily older than the configured daily retention. There is one point per series, repository and capture group value per week, holding the maximum value recorded that week. The time is the start of the week (Monday, UTC).';
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE insight_series DROP COLUMN IF EXISTS top_repositories_limit;
+
+COMMENT ON COLUMN insight_series.generation_method IS 'How the data of this series is generated: search for search queries, language-stats for the language statistics of its repositories.';
+
+COMMIT;
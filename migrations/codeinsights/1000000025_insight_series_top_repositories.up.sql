@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE insight_series ADD COLUMN IF NOT EXISTS top_repositories_limit INT NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN insight_series.generation_method IS 'How the data of this series is generated: search for search queries, language-stats for the language statistics of its repositories, top-repositories for the match counts of the repositories with the most matches.';
+COMMENT ON COLUMN insight_series.top_repositories_limit IS 'The number of repositories recorded at every recording time by top-repositories series.';
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS insight_series_repository_scopes;
+
+ALTER TABLE insight_series DROP COLUMN IF EXISTS repository_query;
+
+COMMIT;
@@ -0,0 +1,21 @@
+BEGIN;
+
+ALTER TABLE insight_series ADD COLUMN IF NOT EXISTS repository_query TEXT NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN insight_series.repository_query IS 'If set, a search query (such as repo:has.meta(team:payments)) whose matching repositories are the repository scope of this series. It is re-evaluated at every recording time.';
+
+CREATE TABLE IF NOT EXISTS insight_series_repository_scopes (
+    id SERIAL PRIMARY KEY,
+    series_id TEXT NOT NULL,
+    recorded_at TIMESTAMP NOT NULL,
+    repositories TEXT[] NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS insight_series_repository_scopes_series_id_recorded_at_idx ON insight_series_repository_scopes (series_id, recorded_at);
+
+COMMENT ON TABLE insight_series_repository_scopes IS 'The repositories that the repository query of a series resolved to at each of its recording times.';
+COMMENT ON COLUMN insight_series_repository_scopes.series_id IS 'The unique series ID (insight_series.series_id) the scope was resolved for.';
+COMMENT ON COLUMN insight_series_repository_scopes.recorded_at IS 'The recording time of the points recorded with this scope.';
+COMMENT ON COLUMN insight_series_repository_scopes.repositories IS 'The names of the repositories that matched the repository query, sorted.';
+
+COMMIT;
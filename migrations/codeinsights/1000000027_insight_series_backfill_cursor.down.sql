@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE insight_series DROP COLUMN IF EXISTS backfill_cursor_repo_id;
+ALTER TABLE insight_series DROP COLUMN IF EXISTS backfill_cursor_frame;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE insight_series ADD COLUMN IF NOT EXISTS backfill_cursor_repo_id INT NOT NULL DEFAULT 0;
+ALTER TABLE insight_series ADD COLUMN IF NOT EXISTS backfill_cursor_frame INT NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN insight_series.backfill_cursor_repo_id IS 'The ID of the repository the backfill of this series stopped at when its budget was exhausted. Repositories are backfilled in ID order, so the ones before it are done.';
+COMMENT ON COLUMN insight_series.backfill_cursor_frame IS 'The number of historical frames of backfill_cursor_repo_id that have been enqueued already.';
+
+COMMIT;
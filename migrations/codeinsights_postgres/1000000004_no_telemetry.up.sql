@@ -0,0 +1,4 @@
+-- Plain Postgres variant of codeinsights/1000000004_no_telemetry.up.sql, which is run instead of
+-- it when code insights use the Postgres storage backend. There is no TimescaleDB telemetry to
+-- disable, and the setting doesn't exist on plain Postgres.
+SELECT 1;
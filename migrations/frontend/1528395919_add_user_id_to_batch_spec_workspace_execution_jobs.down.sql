@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE batch_spec_workspace_execution_jobs
+  DROP COLUMN IF EXISTS user_id;
+
+COMMIT;
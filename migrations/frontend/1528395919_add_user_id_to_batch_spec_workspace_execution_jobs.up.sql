@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE batch_spec_workspace_execution_jobs
+  ADD COLUMN IF NOT EXISTS user_id integer REFERENCES users(id) ON DELETE CASCADE DEFERRABLE;
+
+COMMIT;
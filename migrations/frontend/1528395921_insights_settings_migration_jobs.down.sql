@@ -0,0 +1,7 @@
+BEGIN;
+
+-- Do not remove oob migration when downgrading
+
+DROP TABLE IF EXISTS insights_settings_migration_jobs;
+
+COMMIT;
@@ -0,0 +1,40 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS insights_settings_migration_jobs (
+    id                  SERIAL PRIMARY KEY,
+    user_id             INTEGER,
+    org_id              INTEGER,
+    global              BOOLEAN NOT NULL DEFAULT FALSE,
+    migrated_insights   INTEGER NOT NULL DEFAULT 0,
+    migrated_dashboards INTEGER NOT NULL DEFAULT 0,
+    runs                INTEGER NOT NULL DEFAULT 0,
+    error_message       TEXT,
+    completed_at        TIMESTAMP WITH TIME ZONE
+);
+
+COMMENT ON TABLE insights_settings_migration_jobs IS 'One row per settings subject whose code insights and dashboards are migrated from settings into the code insights database.';
+COMMENT ON COLUMN insights_settings_migration_jobs.migrated_insights IS 'The number of insights of the subject that exist in the code insights database.';
+COMMENT ON COLUMN insights_settings_migration_jobs.migrated_dashboards IS 'The number of dashboards of the subject that exist in the code insights database.';
+COMMENT ON COLUMN insights_settings_migration_jobs.runs IS 'The number of times the migration of the subject was attempted.';
+COMMENT ON COLUMN insights_settings_migration_jobs.error_message IS 'The errors of the last attempt to migrate the subject, if any.';
+
+-- Global settings are migrated first and user settings last, so that dashboards can reference the
+-- insights of the subjects they inherit settings from.
+INSERT INTO insights_settings_migration_jobs (global) VALUES (TRUE);
+INSERT INTO insights_settings_migration_jobs (org_id) SELECT DISTINCT org_id FROM settings WHERE org_id IS NOT NULL;
+INSERT INTO insights_settings_migration_jobs (user_id) SELECT DISTINCT user_id FROM settings WHERE user_id IS NOT NULL;
+
+-- Create the OOB migration according to doc/dev/background-information/oobmigrations.md
+INSERT INTO out_of_band_migrations (id, team, component, description, introduced_version_major, introduced_version_minor, non_destructive)
+VALUES (
+    13,                                              -- This must be consistent across all Sourcegraph instances
+    'code-insights',                                 -- Team owning migration
+    'frontend-db.insights_settings_migration_jobs',  -- Component being migrated
+    'Migrate insights and dashboards from settings', -- Description
+    3,                                               -- The next minor release (major version)
+    34,                                              -- The next minor release (minor version)
+    true                                             -- Can be read with previous version without down migration
+)
+ON CONFLICT DO NOTHING;
+
+COMMIT;
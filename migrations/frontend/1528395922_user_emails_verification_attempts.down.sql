@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE user_emails
+  DROP COLUMN IF EXISTS verification_attempts;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE user_emails
+  ADD COLUMN IF NOT EXISTS verification_attempts integer NOT NULL DEFAULT 0;
+
+COMMIT;
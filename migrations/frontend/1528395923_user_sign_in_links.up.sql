@@ -0,0 +1,14 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS user_sign_in_links (
+    id           bigserial PRIMARY KEY,
+    user_id      integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    value_sha256 bytea NOT NULL UNIQUE,
+    created_at   timestamp with time zone NOT NULL DEFAULT now(),
+    expires_at   timestamp with time zone NOT NULL,
+    used_at      timestamp with time zone
+);
+
+CREATE INDEX IF NOT EXISTS user_sign_in_links_user_id_created_at ON user_sign_in_links(user_id, created_at);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE IF EXISTS external_service_sync_stats;
+
+COMMIT;
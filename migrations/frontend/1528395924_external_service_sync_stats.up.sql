@@ -0,0 +1,17 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS external_service_sync_stats (
+    id                  bigserial PRIMARY KEY,
+    external_service_id bigint NOT NULL REFERENCES external_services(id) ON DELETE CASCADE DEFERRABLE,
+    started_at          timestamp with time zone NOT NULL,
+    finished_at         timestamp with time zone NOT NULL,
+    repos_added         integer NOT NULL DEFAULT 0,
+    repos_removed       integer NOT NULL DEFAULT 0,
+    repos_errored       integer NOT NULL DEFAULT 0,
+    api_requests        integer NOT NULL DEFAULT 0,
+    errored             boolean NOT NULL DEFAULT false
+);
+
+CREATE INDEX IF NOT EXISTS external_service_sync_stats_external_service_id_finished_at ON external_service_sync_stats(external_service_id, finished_at);
+
+COMMIT;
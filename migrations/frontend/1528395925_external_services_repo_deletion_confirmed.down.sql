@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE external_services DROP COLUMN IF EXISTS repo_deletion_confirmed;
+
+COMMIT;
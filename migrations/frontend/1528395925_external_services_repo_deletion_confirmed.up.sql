@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE external_services ADD COLUMN IF NOT EXISTS repo_deletion_confirmed boolean NOT NULL DEFAULT false;
+
+COMMIT;
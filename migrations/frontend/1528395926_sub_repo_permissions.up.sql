@@ -0,0 +1,16 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS sub_repo_permissions (
+    repo_id    integer NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    user_id    integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    path_rules text[] NOT NULL,
+    updated_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS sub_repo_permissions_repo_id_user_id_uindex ON sub_repo_permissions(repo_id, user_id);
+CREATE INDEX IF NOT EXISTS sub_repo_permissions_user_id_idx ON sub_repo_permissions(user_id);
+
+COMMENT ON TABLE sub_repo_permissions IS 'Responsible for storing permissions at a finer granularity than repo';
+COMMENT ON COLUMN sub_repo_permissions.path_rules IS 'Ordered glob patterns of the paths a user can read, relative to the repository root. Patterns prefixed with "-" exclude paths, later patterns take precedence.';
+
+COMMIT;
@@ -0,0 +1,23 @@
+BEGIN;
+
+ALTER TABLE external_service_sync_jobs ADD COLUMN IF NOT EXISTS scope text;
+
+COMMENT ON COLUMN external_service_sync_jobs.scope IS 'The name of the single repository to sync. NULL means the whole external service is synced.';
+
+CREATE OR REPLACE VIEW external_service_sync_jobs_with_next_sync_at AS
+ SELECT j.id,
+    j.state,
+    j.failure_message,
+    j.started_at,
+    j.finished_at,
+    j.process_after,
+    j.num_resets,
+    j.num_failures,
+    j.execution_logs,
+    j.external_service_id,
+    e.next_sync_at,
+    j.scope
+   FROM (external_services e
+     JOIN external_service_sync_jobs j ON ((e.id = j.external_service_id)));
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP INDEX IF EXISTS external_service_org_repos_idx;
+
+ALTER TABLE external_services DROP CONSTRAINT IF EXISTS external_services_token_shared_org_only;
+
+ALTER TABLE external_services DROP COLUMN IF EXISTS token_shared;
+
+COMMIT;
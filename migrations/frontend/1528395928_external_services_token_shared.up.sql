@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TABLE external_services ADD COLUMN IF NOT EXISTS token_shared boolean NOT NULL DEFAULT false;
+
+ALTER TABLE external_services ADD CONSTRAINT external_services_token_shared_org_only CHECK (NOT token_shared OR namespace_org_id IS NOT NULL);
+
+COMMENT ON COLUMN external_services.token_shared IS 'Whether the token of an organization external service is shared with all members of the organization, granting them read access to its repositories.';
+
+CREATE INDEX IF NOT EXISTS external_service_org_repos_idx ON external_service_repos USING btree (org_id, repo_id) WHERE org_id IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS user_security_keys (
+    id                bigserial PRIMARY KEY,
+    user_id           integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name              text NOT NULL,
+    credential_id     bytea NOT NULL UNIQUE,
+    public_key        text NOT NULL,
+    encryption_key_id text NOT NULL DEFAULT '',
+    sign_count        bigint NOT NULL DEFAULT 0,
+    created_at        timestamp with time zone NOT NULL DEFAULT now(),
+    last_used_at      timestamp with time zone
+);
+
+CREATE INDEX IF NOT EXISTS user_security_keys_user_id ON user_security_keys(user_id);
+
+COMMENT ON COLUMN user_security_keys.public_key IS 'The COSE encoded public key of the credential, base64 encoded and possibly encrypted.';
+
+COMMIT;
@@ -0,0 +1,18 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS perms_sync_states (
+    request_type text NOT NULL,
+    id           integer NOT NULL,
+    queued_at    timestamp with time zone,
+    started_at   timestamp with time zone,
+    finished_at  timestamp with time zone,
+    last_error   text,
+    PRIMARY KEY (request_type, id)
+);
+
+COMMENT ON TABLE perms_sync_states IS 'The progress of the permissions syncing of users and repositories, as reported by the permissions syncer.';
+COMMENT ON COLUMN perms_sync_states.request_type IS 'Whether the permissions syncing is user-centric ("user") or repository-centric ("repo").';
+COMMENT ON COLUMN perms_sync_states.id IS 'The ID of the user or repository.';
+COMMENT ON COLUMN perms_sync_states.last_error IS 'The error of the last finished sync, NULL if it succeeded.';
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE IF EXISTS user_credentials
+    DROP COLUMN IF EXISTS previous_credential,
+    DROP COLUMN IF EXISTS previous_credential_expires_at,
+    DROP COLUMN IF EXISTS last_used_at;
+
+COMMIT;
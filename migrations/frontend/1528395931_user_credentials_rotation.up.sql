@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TABLE IF EXISTS user_credentials
+    ADD COLUMN IF NOT EXISTS previous_credential bytea,
+    ADD COLUMN IF NOT EXISTS previous_credential_expires_at timestamp with time zone,
+    ADD COLUMN IF NOT EXISTS last_used_at timestamp with time zone;
+
+COMMENT ON COLUMN user_credentials.previous_credential IS 'The credential replaced by the last rotation, encrypted like credential. It remains valid until previous_credential_expires_at.';
+COMMENT ON COLUMN user_credentials.last_used_at IS 'When the credential was last loaded to authenticate against the code host.';
+
+COMMIT;
@@ -0,0 +1,27 @@
+BEGIN;
+
+ALTER TABLE event_logs RENAME TO event_logs_partitioned;
+
+CREATE TABLE event_logs (
+    LIKE event_logs_partitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS INCLUDING COMMENTS
+);
+
+INSERT INTO event_logs SELECT * FROM event_logs_partitioned;
+-- Events that were not moved to the partitioned table yet by the out-of-band migration.
+INSERT INTO event_logs SELECT * FROM event_logs_unpartitioned;
+DROP TABLE event_logs_unpartitioned;
+
+ALTER SEQUENCE event_logs_id_seq OWNED BY event_logs.id;
+-- Dropping the partitioned table drops all of its partitions.
+DROP TABLE event_logs_partitioned;
+
+ALTER TABLE event_logs ADD PRIMARY KEY (id);
+
+CREATE INDEX event_logs_anonymous_user_id ON event_logs USING btree (anonymous_user_id);
+CREATE INDEX event_logs_name ON event_logs USING btree (name);
+CREATE INDEX event_logs_source ON event_logs USING btree (source);
+CREATE INDEX event_logs_timestamp ON event_logs USING btree ("timestamp");
+CREATE INDEX event_logs_timestamp_at_utc ON event_logs USING btree (date(timezone('UTC'::text, "timestamp")));
+CREATE INDEX event_logs_user_id ON event_logs USING btree (user_id);
+
+COMMIT;
@@ -0,0 +1,72 @@
+BEGIN;
+
+-- Convert event_logs into a table that is natively partitioned by month, so that expired
+-- events can be pruned by dropping whole partitions rather than by deleting rows. Monthly
+-- partitions are created ahead of time by the frontend. All events outside of the created
+-- partitions end up in the default partition.
+-- The existing events are moved to the partitioned table in batches by an out-of-band
+-- migration, as copying them here would block the upgrade for a long time on large instances.
+ALTER TABLE event_logs RENAME TO event_logs_unpartitioned;
+
+-- The partitioned table reuses the names of the indexes. The out-of-band migration only reads
+-- the unpartitioned table by ID.
+ALTER TABLE event_logs_unpartitioned RENAME CONSTRAINT event_logs_pkey TO event_logs_unpartitioned_pkey;
+DROP INDEX event_logs_anonymous_user_id;
+DROP INDEX event_logs_name;
+DROP INDEX event_logs_source;
+DROP INDEX event_logs_timestamp;
+DROP INDEX event_logs_timestamp_at_utc;
+DROP INDEX event_logs_user_id;
+
+CREATE TABLE event_logs (
+    LIKE event_logs_unpartitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS INCLUDING COMMENTS
+) PARTITION BY RANGE ("timestamp");
+
+CREATE TABLE event_logs_default PARTITION OF event_logs DEFAULT;
+
+-- Create the partitions of the months covered by the retention period of event logs (93
+-- days), and of the upcoming months.
+DO $$
+DECLARE
+    -- Partitions are bounded by months in UTC, regardless of the time zone of the session.
+    partition_start timestamp := date_trunc('month', (now() - interval '93 days') AT TIME ZONE 'UTC');
+BEGIN
+    WHILE partition_start < date_trunc('month', now() AT TIME ZONE 'UTC') + interval '3 months' LOOP
+        EXECUTE format(
+            'CREATE TABLE %I PARTITION OF event_logs FOR VALUES FROM (%L) TO (%L)',
+            to_char(partition_start, '"event_logs_y"YYYY"m"MM'),
+            partition_start AT TIME ZONE 'UTC',
+            (partition_start + interval '1 month') AT TIME ZONE 'UTC'
+        );
+        partition_start := partition_start + interval '1 month';
+    END LOOP;
+END
+$$;
+
+-- The sequence of the IDs is owned by the unpartitioned table, and would be dropped with it.
+ALTER SEQUENCE event_logs_id_seq OWNED BY event_logs.id;
+
+-- The primary key of a partitioned table must include the partitioning column.
+ALTER TABLE event_logs ADD PRIMARY KEY (id, "timestamp");
+
+CREATE INDEX event_logs_anonymous_user_id ON event_logs USING btree (anonymous_user_id);
+CREATE INDEX event_logs_name ON event_logs USING btree (name);
+CREATE INDEX event_logs_source ON event_logs USING btree (source);
+CREATE INDEX event_logs_timestamp ON event_logs USING btree ("timestamp");
+CREATE INDEX event_logs_timestamp_at_utc ON event_logs USING btree (date(timezone('UTC'::text, "timestamp")));
+CREATE INDEX event_logs_user_id ON event_logs USING btree (user_id);
+
+-- Create the OOB migration according to doc/dev/background-information/oobmigrations.md
+INSERT INTO out_of_band_migrations (id, team, component, description, introduced_version_major, introduced_version_minor, non_destructive)
+VALUES (
+    14,                                                    -- This must be consistent across all Sourcegraph instances
+    'core-application',                                    -- Team owning migration
+    'frontend-db.event_logs',                              -- Component being migrated
+    'Move event logs to the partitioned event_logs table', -- Description
+    3,                                                     -- The next minor release (major version)
+    34,                                                    -- The next minor release (minor version)
+    true                                                   -- Can be read with previous version without down migration
+)
+ON CONFLICT DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE IF EXISTS feature_flags
+    DROP COLUMN IF EXISTS rules;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE IF EXISTS feature_flags
+    ADD COLUMN IF NOT EXISTS rules jsonb DEFAULT '[]'::jsonb NOT NULL;
+
+COMMENT ON COLUMN feature_flags.rules IS 'Rules targeting cohorts of users, evaluated in order before bool_value or rollout. The first rule matching a user decides the value of the flag for them.';
+
+COMMIT;
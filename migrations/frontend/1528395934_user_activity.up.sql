@@ -0,0 +1,15 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS user_activity (
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind text NOT NULL,
+    last_seen_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (user_id, kind)
+);
+
+CREATE INDEX IF NOT EXISTS user_activity_kind_last_seen_at ON user_activity (kind, last_seen_at);
+
+COMMENT ON TABLE user_activity IS 'The last time each user was seen using Sourcegraph, per kind of activity.';
+COMMENT ON COLUMN user_activity.kind IS 'The kind of activity, e.g. web, api or git.';
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE changeset_specs DROP COLUMN IF EXISTS diff_digest;
+
+COMMIT;
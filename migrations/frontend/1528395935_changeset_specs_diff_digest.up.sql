@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE changeset_specs ADD COLUMN IF NOT EXISTS diff_digest text;
+
+COMMENT ON COLUMN changeset_specs.diff_digest IS 'SHA-256 digest of the commits (diff, message and author) of the changeset spec. NULL for specs importing existing changesets and for specs created before the column was added.';
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS batch_spec_resolution_cache_entries;
+
+ALTER TABLE batch_specs DROP COLUMN IF EXISTS no_cache;
+
+COMMIT;
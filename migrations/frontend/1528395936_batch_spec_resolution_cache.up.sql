@@ -0,0 +1,22 @@
+BEGIN;
+
+ALTER TABLE batch_specs
+  ADD COLUMN IF NOT EXISTS no_cache BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS batch_spec_resolution_cache_entries (
+  id BIGSERIAL PRIMARY KEY,
+  query TEXT NOT NULL,
+  repo_id INTEGER NOT NULL REFERENCES repo(id) ON DELETE CASCADE DEFERRABLE,
+  commit TEXT NOT NULL,
+  file_matches TEXT[] NOT NULL DEFAULT '{}'::TEXT[],
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  CONSTRAINT batch_spec_resolution_cache_entries_query_repo_id_unique UNIQUE (query, repo_id)
+);
+
+CREATE INDEX IF NOT EXISTS batch_spec_resolution_cache_entries_created_at ON batch_spec_resolution_cache_entries (created_at);
+
+COMMENT ON TABLE batch_spec_resolution_cache_entries IS 'Caches the repositories matched by the repositoriesMatchingQuery clauses of batch specs, along with the default branch commit they were resolved at.';
+COMMENT ON COLUMN batch_spec_resolution_cache_entries.commit IS 'The commit of the default branch of the repository when the query was resolved. The cached entries of a query are invalidated once the default branch of one of its repositories has moved on.';
+COMMENT ON COLUMN batch_specs.no_cache IS 'Whether to bypass the workspace resolution cache when resolving the workspaces of the batch spec.';
+
+COMMIT;
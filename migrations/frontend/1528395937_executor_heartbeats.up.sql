@@ -0,0 +1,28 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS executor_heartbeats (
+  id SERIAL PRIMARY KEY,
+  name TEXT NOT NULL,
+  hostname TEXT NOT NULL,
+  queue_name TEXT NOT NULL,
+  os TEXT NOT NULL DEFAULT '',
+  architecture TEXT NOT NULL DEFAULT '',
+  executor_version TEXT NOT NULL DEFAULT '',
+  job_ids INTEGER[] NOT NULL DEFAULT '{}',
+  num_cpus INTEGER NOT NULL DEFAULT 0,
+  memory_total_bytes BIGINT NOT NULL DEFAULT 0,
+  memory_available_bytes BIGINT NOT NULL DEFAULT 0,
+  load_average DOUBLE PRECISION NOT NULL DEFAULT 0,
+  first_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  CONSTRAINT executor_heartbeats_name_key UNIQUE (name)
+);
+
+CREATE INDEX IF NOT EXISTS executor_heartbeats_last_seen_at ON executor_heartbeats (last_seen_at);
+
+COMMENT ON TABLE executor_heartbeats IS 'The latest heartbeat of each executor, used to list active executors and to requeue the jobs of executors that stopped sending heartbeats.';
+COMMENT ON COLUMN executor_heartbeats.name IS 'The unique name of the executor process, which is also the worker_hostname of the jobs it dequeued.';
+COMMENT ON COLUMN executor_heartbeats.job_ids IS 'The IDs of the jobs of the queue the executor was processing at its last heartbeat.';
+COMMENT ON COLUMN executor_heartbeats.load_average IS 'The one minute load average of the host of the executor.';
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS repo_kvps (
+  repo_id INTEGER NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+  key TEXT NOT NULL,
+  value TEXT,
+  PRIMARY KEY (repo_id, key)
+);
+
+CREATE INDEX IF NOT EXISTS repo_kvps_key_value ON repo_kvps (key, value);
+
+COMMENT ON TABLE repo_kvps IS 'Custom key-value metadata of repositories, e.g. the owning team of a repository. Repositories can be searched by their metadata with the repo:has.meta() predicate.';
+COMMENT ON COLUMN repo_kvps.value IS 'The value of the key, or NULL for a tag without a value.';
+
+COMMIT;
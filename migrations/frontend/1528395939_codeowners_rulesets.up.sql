@@ -0,0 +1,19 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS codeowners_rulesets (
+    repo_id integer NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    source text NOT NULL,
+    commit_id text,
+    path text,
+    rules jsonb NOT NULL DEFAULT '[]'::jsonb,
+    updated_at timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (repo_id, source),
+    CONSTRAINT codeowners_rulesets_source_check CHECK (source IN ('file', 'upload'))
+);
+
+COMMENT ON TABLE codeowners_rulesets IS 'The ownership rules of repositories, parsed from their CODEOWNERS file or from an uploaded ownership manifest.';
+COMMENT ON COLUMN codeowners_rulesets.source IS 'Either file, for the rules ingested from the CODEOWNERS file on the default branch, or upload, for an uploaded manifest. Uploaded rules take precedence.';
+COMMENT ON COLUMN codeowners_rulesets.commit_id IS 'The commit of the default branch the rules were ingested at. NULL for uploaded rules.';
+COMMENT ON COLUMN codeowners_rulesets.path IS 'The path of the CODEOWNERS file the rules were parsed from, or NULL if the repository has none.';
+
+COMMIT;
@@ -0,0 +1,26 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id bigserial PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE DEFERRABLE,
+    kind text NOT NULL,
+    title text NOT NULL,
+    body text NOT NULL DEFAULT '',
+    url text,
+    dedupe_key text,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    read_at timestamp with time zone,
+    expires_at timestamp with time zone NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS notifications_user_id_created_at ON notifications(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS notifications_expires_at ON notifications(expires_at);
+CREATE UNIQUE INDEX IF NOT EXISTS notifications_unread_dedupe_key ON notifications(user_id, dedupe_key) WHERE read_at IS NULL;
+
+COMMENT ON TABLE notifications IS 'In-product notifications about events that users should see, e.g. a finished batch spec execution or a failing code host sync.';
+COMMENT ON COLUMN notifications.kind IS 'The kind of event the notification is about, e.g. insight_alert.';
+COMMENT ON COLUMN notifications.dedupe_key IS 'Identifies the event the notification is about. A user has at most one unread notification with the same key, so that recurring events only notify them once.';
+COMMENT ON COLUMN notifications.read_at IS 'When the user marked the notification as read, or NULL if it is unread.';
+COMMENT ON COLUMN notifications.expires_at IS 'When the notification is deleted, whether it was read or not.';
+
+COMMIT;
@@ -0,0 +1,24 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS webhook_logs (
+    id bigserial PRIMARY KEY,
+    received_at timestamp with time zone NOT NULL DEFAULT now(),
+    external_service_id bigint REFERENCES external_services(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    kind text NOT NULL,
+    status_code integer NOT NULL,
+    request jsonb NOT NULL,
+    response jsonb NOT NULL,
+    replay_of bigint REFERENCES webhook_logs(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS webhook_logs_received_at_idx ON webhook_logs(received_at);
+CREATE INDEX IF NOT EXISTS webhook_logs_external_service_id_idx ON webhook_logs(external_service_id);
+CREATE INDEX IF NOT EXISTS webhook_logs_status_code_idx ON webhook_logs(status_code);
+
+COMMENT ON TABLE webhook_logs IS 'The webhooks received from code hosts, kept for debugging and replaying them.';
+COMMENT ON COLUMN webhook_logs.kind IS 'The kind of the external service the webhook was received for, e.g. GITHUB.';
+COMMENT ON COLUMN webhook_logs.request IS 'The method, URL, headers and body of the request. Headers carrying secrets are redacted.';
+COMMENT ON COLUMN webhook_logs.response IS 'The headers and body of the response of the handler of the webhook.';
+COMMENT ON COLUMN webhook_logs.replay_of IS 'The webhook log this webhook was replayed from, if any.';
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE external_service_sync_stats DROP COLUMN IF EXISTS backoff_reason;
+
+COMMIT;
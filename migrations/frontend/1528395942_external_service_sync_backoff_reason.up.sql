@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE external_service_sync_stats ADD COLUMN IF NOT EXISTS backoff_reason text NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN external_service_sync_stats.backoff_reason IS 'Why the next sync was scheduled later than the minimum sync interval: unchanged, rate_limited, unauthorized or error. Empty if it was not.';
+
+COMMIT;
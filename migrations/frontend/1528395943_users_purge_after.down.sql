@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP INDEX IF EXISTS users_purge_after_idx;
+ALTER TABLE users DROP COLUMN IF EXISTS purge_after;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS purge_after timestamp with time zone;
+
+COMMENT ON COLUMN users.purge_after IS 'When the soft-deleted user and the resources it owns are permanently deleted. NULL for users that are not deleted, and for users deleted before the grace period was introduced, which are kept.';
+
+CREATE INDEX IF NOT EXISTS users_purge_after_idx ON users(purge_after) WHERE purge_after IS NOT NULL;
+
+COMMIT;
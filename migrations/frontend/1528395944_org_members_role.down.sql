@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE org_members DROP CONSTRAINT IF EXISTS org_members_role_check;
+ALTER TABLE org_members DROP COLUMN IF EXISTS role;
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+ALTER TABLE org_members ADD COLUMN IF NOT EXISTS role text NOT NULL DEFAULT 'member';
+ALTER TABLE org_members DROP CONSTRAINT IF EXISTS org_members_role_check;
+ALTER TABLE org_members ADD CONSTRAINT org_members_role_check CHECK (role IN ('admin', 'member'));
+
+COMMENT ON COLUMN org_members.role IS 'The role of the user in the organization: admin or member. Only admins can manage the organization, its settings and its code host connections.';
+
+-- Every member could manage their organization before roles were introduced.
+UPDATE org_members SET role = 'admin';
+
+COMMIT;
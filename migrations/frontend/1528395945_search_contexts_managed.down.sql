@@ -0,0 +1,8 @@
+BEGIN;
+
+DELETE FROM search_contexts WHERE managed;
+
+ALTER TABLE search_contexts DROP COLUMN IF EXISTS managed;
+ALTER TABLE search_contexts DROP COLUMN IF EXISTS hidden;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE search_contexts ADD COLUMN IF NOT EXISTS managed boolean NOT NULL DEFAULT false;
+ALTER TABLE search_contexts ADD COLUMN IF NOT EXISTS hidden boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN search_contexts.managed IS 'Whether the search context is maintained by Sourcegraph from the repositories of the code host connections of its namespace. Managed search contexts cannot be edited or deleted.';
+COMMENT ON COLUMN search_contexts.hidden IS 'Whether the owner of the search context hid it from the search context selector.';
+
+COMMIT;
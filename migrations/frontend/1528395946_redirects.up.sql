@@ -0,0 +1,21 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS redirects (
+    id bigserial PRIMARY KEY,
+    kind text NOT NULL,
+    old_name citext NOT NULL,
+    new_name citext NOT NULL,
+    reason text NOT NULL DEFAULT '',
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    CONSTRAINT redirects_kind_check CHECK (kind IN ('repo', 'user')),
+    CONSTRAINT redirects_old_name_new_name_check CHECK (old_name <> new_name)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS redirects_kind_old_name_unique ON redirects (kind, old_name);
+CREATE INDEX IF NOT EXISTS redirects_kind_new_name_idx ON redirects (kind, new_name);
+
+COMMENT ON TABLE redirects IS 'Redirects from the old names of renamed repositories and users to their new names, so that links to the old names keep working.';
+COMMENT ON COLUMN redirects.kind IS 'The kind of the renamed resource: repo or user.';
+COMMENT ON COLUMN redirects.reason IS 'Why the resource was renamed, for example because it was renamed on the code host.';
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE lsif_dirty_repositories DROP COLUMN IF EXISTS watermark_commits;
+ALTER TABLE lsif_dirty_repositories DROP COLUMN IF EXISTS watermark_upload_ids;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE lsif_dirty_repositories ADD COLUMN IF NOT EXISTS watermark_commits text[];
+ALTER TABLE lsif_dirty_repositories ADD COLUMN IF NOT EXISTS watermark_upload_ids integer[];
+
+COMMENT ON COLUMN lsif_dirty_repositories.watermark_commits IS 'The tips of the branches and tags of the commit graph used by the last update. The next update only needs the commits added since then. Null if the next update must recalculate the whole commit graph.';
+COMMENT ON COLUMN lsif_dirty_repositories.watermark_upload_ids IS 'The identifiers of the completed uploads of the repository at the last update of the commit graph.';
+
+COMMIT;
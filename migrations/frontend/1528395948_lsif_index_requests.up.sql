@@ -0,0 +1,22 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS lsif_index_requests (
+    id serial PRIMARY KEY,
+    repository_id integer NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    commit text NOT NULL,
+    rev text NOT NULL,
+    requested_at timestamp with time zone NOT NULL DEFAULT NOW(),
+    inferred_at timestamp with time zone,
+    failure_message text,
+    index_ids integer[] NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX IF NOT EXISTS lsif_index_requests_repository_id ON lsif_index_requests(repository_id);
+
+COMMENT ON TABLE lsif_index_requests IS 'Tracks requests to index a repository at a commit on demand, so that the progress of the resulting index jobs can be reported.';
+COMMENT ON COLUMN lsif_index_requests.rev IS 'The revision given by the user, which resolved to commit.';
+COMMENT ON COLUMN lsif_index_requests.inferred_at IS 'The time the index jobs of the request were determined. Null while the index jobs are being inferred.';
+COMMENT ON COLUMN lsif_index_requests.failure_message IS 'The reason no index jobs could be queued for the request.';
+COMMENT ON COLUMN lsif_index_requests.index_ids IS 'The identifiers of the index jobs queued for the request.';
+
+COMMIT;
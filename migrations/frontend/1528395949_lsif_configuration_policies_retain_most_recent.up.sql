@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE lsif_configuration_policies ADD COLUMN IF NOT EXISTS retain_most_recent_uploads integer;
+
+COMMENT ON COLUMN lsif_configuration_policies.retain_most_recent_uploads IS 'The number of most recent completed uploads for each root and indexer of a repository to retain regardless of their age. Only uploads visible from a commit matching this policy are retained. Null if no uploads are retained by count.';
+
+COMMIT;
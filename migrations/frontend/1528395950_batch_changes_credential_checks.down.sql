@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TABLE IF EXISTS user_credentials
+    DROP COLUMN IF EXISTS checked_at,
+    DROP COLUMN IF EXISTS check_failure_message;
+
+ALTER TABLE IF EXISTS batch_changes_site_credentials
+    DROP COLUMN IF EXISTS checked_at,
+    DROP COLUMN IF EXISTS check_failure_message;
+
+COMMIT;
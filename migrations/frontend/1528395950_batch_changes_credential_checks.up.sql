@@ -0,0 +1,17 @@
+BEGIN;
+
+ALTER TABLE IF EXISTS user_credentials
+    ADD COLUMN IF NOT EXISTS checked_at timestamp with time zone,
+    ADD COLUMN IF NOT EXISTS check_failure_message text;
+
+COMMENT ON COLUMN user_credentials.checked_at IS 'When the credential was last checked against the code host.';
+COMMENT ON COLUMN user_credentials.check_failure_message IS 'Why the credential can''t be used to publish changesets, as found by the last check. NULL if the last check succeeded.';
+
+ALTER TABLE IF EXISTS batch_changes_site_credentials
+    ADD COLUMN IF NOT EXISTS checked_at timestamp with time zone,
+    ADD COLUMN IF NOT EXISTS check_failure_message text;
+
+COMMENT ON COLUMN batch_changes_site_credentials.checked_at IS 'When the credential was last checked against the code host.';
+COMMENT ON COLUMN batch_changes_site_credentials.check_failure_message IS 'Why the credential can''t be used to publish changesets, as found by the last check. NULL if the last check succeeded.';
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE IF EXISTS batch_changes
+    DROP COLUMN IF EXISTS auto_archive_after_days;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE IF EXISTS batch_changes
+    ADD COLUMN IF NOT EXISTS auto_archive_after_days integer NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN batch_changes.auto_archive_after_days IS 'The number of days after their last update on the code host after which merged and closed changesets are archived. 0 disables automatic archival.';
+
+COMMIT;
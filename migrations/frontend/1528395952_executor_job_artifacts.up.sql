@@ -0,0 +1,23 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS executor_job_artifacts (
+  id SERIAL PRIMARY KEY,
+  queue_name TEXT NOT NULL,
+  job_id INTEGER NOT NULL,
+  name TEXT NOT NULL,
+  object_key TEXT NOT NULL,
+  size BIGINT NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  CONSTRAINT executor_job_artifacts_queue_name_job_id_name_key UNIQUE (queue_name, job_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS executor_job_artifacts_expires_at ON executor_job_artifacts (expires_at);
+
+COMMENT ON TABLE executor_job_artifacts IS 'The artifacts (such as logs, patches or SARIF files) uploaded by executor jobs to the upload store.';
+COMMENT ON COLUMN executor_job_artifacts.job_id IS 'The ID of the record of the queue the artifact was uploaded for.';
+COMMENT ON COLUMN executor_job_artifacts.object_key IS 'The key of the artifact in the upload store.';
+COMMENT ON COLUMN executor_job_artifacts.size IS 'The size of the artifact in bytes.';
+COMMENT ON COLUMN executor_job_artifacts.expires_at IS 'The time after which the artifact is deleted from the upload store.';
+
+COMMIT;
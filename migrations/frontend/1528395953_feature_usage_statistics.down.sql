@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE IF EXISTS feature_usage_statistics;
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS feature_usage_statistics (
+  feature TEXT NOT NULL,
+  period TEXT NOT NULL,
+  period_start TIMESTAMP WITH TIME ZONE NOT NULL,
+  event_name TEXT NOT NULL,
+  event_count INTEGER NOT NULL,
+  user_count INTEGER NOT NULL,
+  aggregated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (feature, period, period_start, event_name)
+);
+
+COMMENT ON TABLE feature_usage_statistics IS 'Usage metrics of product features aggregated periodically from event_logs by the worker.';
+COMMENT ON COLUMN feature_usage_statistics.period IS 'The length of the aggregated period: daily or weekly.';
+COMMENT ON COLUMN feature_usage_statistics.event_name IS 'The name of the aggregated event. The empty string holds the totals over all events of the feature.';
+COMMENT ON COLUMN feature_usage_statistics.user_count IS 'The number of unique (registered or anonymous) users that logged the event in the period.';
+
+COMMIT;
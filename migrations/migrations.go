@@ -7,13 +7,18 @@ import (
 	"log"
 )
 
-//go:embed codeinsights/* codeintel/* frontend/*
+//go:embed codeinsights/* codeinsights_postgres/* codeintel/* frontend/*
 var content embed.FS
 
 var (
 	CodeInsights = mustSub("codeinsights")
 	CodeIntel    = mustSub("codeintel")
 	Frontend     = mustSub("frontend")
+
+	// CodeInsightsPostgres are the code insights migrations for the Postgres storage backend of
+	// code insights. The migrations in codeinsights_postgres replace the code insights migrations
+	// of the same name, which use TimescaleDB.
+	CodeInsightsPostgres = overlayFS{base: CodeInsights, overlay: mustSub("codeinsights_postgres")}
 )
 
 func mustSub(dir string) fs.FS {
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/golang-migrate/migrate/v4"
+	"github.com/google/go-cmp/cmp"
 
 	"github.com/sourcegraph/sourcegraph/internal/database/dbconn"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
@@ -28,6 +29,7 @@ func TestIDConstraints(t *testing.T) {
 		{Name: "frontend", FS: migrations.Frontend},
 		{Name: "codeintel", FS: migrations.CodeIntel},
 		{Name: "codeinsights", FS: migrations.CodeInsights},
+		{Name: "codeinsights_postgres", FS: migrations.CodeInsightsPostgres},
 	}
 
 	for _, c := range cases {
@@ -70,6 +72,50 @@ func TestIDConstraints(t *testing.T) {
 	}
 }
 
+func TestCodeInsightsPostgres(t *testing.T) {
+	names, err := fs.Glob(migrations.CodeInsights, "*.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	postgresNames, err := fs.Glob(migrations.CodeInsightsPostgres, "*.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(names, postgresNames); diff != "" {
+		t.Fatalf("the Postgres migrations must replace existing migrations (-want +got):\n%s", diff)
+	}
+
+	var replaced []string
+	for _, name := range names {
+		want, err := fs.ReadFile(migrations.CodeInsights, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := fs.ReadFile(migrations.CodeInsightsPostgres, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(want) {
+			replaced = append(replaced, name)
+		}
+
+		for _, timescale := range []string{"create_hypertable(", "drop_chunks(", "EXTENSION IF NOT EXISTS timescaledb", "timescaledb.telemetry_level"} {
+			if strings.Contains(string(got), timescale) {
+				t.Errorf("%s: uses TimescaleDB (%s)", name, timescale)
+			}
+		}
+	}
+
+	wantReplaced := []string{
+		"1000000001_initial_schema.up.sql",
+		"1000000004_no_telemetry.up.sql",
+		"1000000010_series_points_reset.up.sql",
+	}
+	if diff := cmp.Diff(wantReplaced, replaced); diff != "" {
+		t.Fatalf("unexpected replaced migrations (-want +got):\n%s", diff)
+	}
+}
+
 func TestMigrations(t *testing.T) {
 	if os.Getenv("SKIP_MIGRATION_TEST") != "" {
 		t.Skip()
@@ -0,0 +1,82 @@
+package migrations
+
+import (
+	"io"
+	"io/fs"
+	"sort"
+)
+
+// overlayFS is a flat directory with the files of base, except for those replaced by the files
+// of the same name in overlay.
+type overlayFS struct {
+	base    fs.FS
+	overlay fs.FS
+}
+
+func (o overlayFS) Open(name string) (fs.File, error) {
+	if name == "." {
+		f, err := o.base.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		entries, err := o.ReadDir(name)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		return &overlayDir{File: f, entries: entries}, nil
+	}
+
+	if f, err := o.overlay.Open(name); err == nil {
+		return f, nil
+	}
+	return o.base.Open(name)
+}
+
+func (o overlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	base, err := fs.ReadDir(o.base, name)
+	if err != nil {
+		return nil, err
+	}
+	overlay, err := fs.ReadDir(o.overlay, name)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]fs.DirEntry, len(base))
+	for _, e := range base {
+		byName[e.Name()] = e
+	}
+	for _, e := range overlay {
+		byName[e.Name()] = e
+	}
+	entries := make([]fs.DirEntry, 0, len(byName))
+	for _, e := range byName {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+// overlayDir is the root directory of an overlayFS, which lists the entries of both file systems.
+type overlayDir struct {
+	fs.File
+	entries []fs.DirEntry
+}
+
+func (d *overlayDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if n <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(d.entries) {
+		n = len(d.entries)
+	}
+	entries := d.entries[:n]
+	d.entries = d.entries[n:]
+	return entries, nil
+}
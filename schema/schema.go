@@ -56,10 +56,14 @@ type AWSKMSEncryptionKey struct {
 
 // ApiRatelimit description: Configuration for API rate limiting
 type ApiRatelimit struct {
+	// ApplyToGraphQL description: Whether the limits apply to requests of the GraphQL API. If not set, the limits are not enforced.
+	ApplyToGraphQL bool `json:"applyToGraphQL,omitempty"`
 	// Enabled description: Whether API rate limiting is enabled
 	Enabled bool `json:"enabled"`
 	// Overrides description: An array of rate limit overrides
 	Overrides []*Overrides `json:"overrides,omitempty"`
+	// PerAccessToken description: Limit granted per access token per hour. Requests authenticated with an access token count towards the limits of both the token and its user, so that a single integration cannot use up the limit of its user. If not set, requests authenticated with an access token only count towards the limit of the user.
+	PerAccessToken int `json:"perAccessToken,omitempty"`
 	// PerIP description: Limit granted per IP per hour, only applied to anonymous users
 	PerIP int `json:"perIP"`
 	// PerUser description: Limit granted per user per hour
@@ -131,6 +135,49 @@ func (v *AuthProviders) UnmarshalJSON(data []byte) error {
 	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "type", []string{"builtin", "saml", "openidconnect", "http-header", "github", "gitlab"})
 }
 
+// AzureDevOpsConnection description: Configuration for a connection to Azure DevOps.
+type AzureDevOpsConnection struct {
+	// Exclude description: A list of repositories to never mirror from Azure DevOps. Takes precedence over "orgs" and "projects".
+	//
+	// Supports excluding by name ({"name": "org/project/repo"}) or by pattern.
+	Exclude []*ExcludedAzureDevOpsRepo `json:"exclude,omitempty"`
+	// GitURLType description: The type of Git URLs to use for cloning and fetching Git repositories on Azure DevOps.
+	//
+	// If "http", Sourcegraph will access Azure DevOps repositories using Git URLs of the form https://dev.azure.com/myorg/myproject/_git/myrepo.
+	//
+	// If "ssh", Sourcegraph will access Azure DevOps repositories using Git URLs of the form git@ssh.dev.azure.com:v3/myorg/myproject/myrepo. See the documentation for how to provide SSH private keys and known_hosts: https://docs.sourcegraph.com/admin/repo/auth#repositories-that-need-http-s-or-ssh-authentication.
+	GitURLType string `json:"gitURLType,omitempty"`
+	// Orgs description: An array of Azure DevOps organization names whose repositories (across all of their projects) should be mirrored on Sourcegraph.
+	Orgs []string `json:"orgs,omitempty"`
+	// Projects description: An array of Azure DevOps projects (of the form "org/project") whose repositories should be mirrored on Sourcegraph.
+	Projects []string `json:"projects,omitempty"`
+	// RateLimit description: Rate limit applied when making background API requests to Azure DevOps.
+	RateLimit *AzureDevOpsRateLimit `json:"rateLimit,omitempty"`
+	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for an Azure DevOps repository. In the pattern, the variable "{host}" is replaced with the Azure DevOps URL's host (such as dev.azure.com), "{org}" with the organization name, "{project}" with the project name, and "{repo}" with the repository name.
+	//
+	// For example, if your Sourcegraph URL is https://src.example.com, then a repositoryPathPattern of "{host}/{org}/{project}/{repo}" would mean that the Azure DevOps repository https://dev.azure.com/myorg/myproject/_git/myrepo is available on Sourcegraph at https://src.example.com/dev.azure.com/myorg/myproject/myrepo.
+	//
+	// It is important that the Sourcegraph repository name generated with this pattern be unique to this code host. If different code hosts generate repository names that collide, Sourcegraph's behavior is undefined.
+	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
+	// Token description: A personal access token for the Azure DevOps user. The token needs the "Code (Read)" scope to mirror repositories, and the "Code (Read & write)" scope to create changesets with batch changes.
+	Token string `json:"token"`
+	// Url description: URL of Azure DevOps Services. This is almost always https://dev.azure.com.
+	Url string `json:"url"`
+	// Username description: The username of the Azure DevOps user that owns the personal access token.
+	Username string `json:"username"`
+	// WebhookSecret description: A shared secret used to authenticate incoming webhooks (minimum 12 characters). Configure the Azure DevOps "Web Hooks" service hook to use basic authentication with this secret as the password, and Sourcegraph rejects requests whose password does not match.
+	WebhookSecret string `json:"webhookSecret,omitempty"`
+}
+
+// AzureDevOpsRateLimit description: Rate limit applied when making background API requests to Azure DevOps.
+type AzureDevOpsRateLimit struct {
+	// Enabled description: true if rate limiting is enabled.
+	Enabled bool `json:"enabled"`
+	// MaxConcurrentRequests description: Maximum number of requests to Azure DevOps that are in flight at the same time, shared by all clients of the code host. Additional requests wait until a running request completes. Only applies if rate limiting is enabled. If not set, the number of concurrent requests is unlimited.
+	MaxConcurrentRequests int `json:"maxConcurrentRequests,omitempty"`
+	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second. Internally, the burst limit is set to 500, which implies that for a requests per hour limit as low as 1, users will continue to be able to send a maximum of 500 requests immediately, provided that the complexity cost of each request is 1.
+	RequestsPerHour float64 `json:"requestsPerHour"`
+}
 type BackendInsight struct {
 	// Description description: The description of this insight
 	Description string          `json:"description,omitempty"`
@@ -211,12 +258,16 @@ type BitbucketCloudConnection struct {
 	Url string `json:"url"`
 	// Username description: The username to use when authenticating to the Bitbucket Cloud. Also set the corresponding "appPassword" field.
 	Username string `json:"username"`
+	// WebhookSecret description: A shared secret used to authenticate incoming webhooks (minimum 12 characters). Bitbucket Cloud signs webhook payloads with this secret, and Sourcegraph rejects payloads whose signature does not match.
+	WebhookSecret string `json:"webhookSecret,omitempty"`
 }
 
 // BitbucketCloudRateLimit description: Rate limit applied when making background API requests to Bitbucket Cloud.
 type BitbucketCloudRateLimit struct {
 	// Enabled description: true if rate limiting is enabled.
 	Enabled bool `json:"enabled"`
+	// MaxConcurrentRequests description: Maximum number of requests to Bitbucket Cloud that are in flight at the same time, shared by all clients of the code host. Additional requests wait until a running request completes. Only applies if rate limiting is enabled. If not set, the number of concurrent requests is unlimited.
+	MaxConcurrentRequests int `json:"maxConcurrentRequests,omitempty"`
 	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second. Internally, the burst limit is set to 500, which implies that for a requests per hour limit as low as 1, users will continue to be able to send a maximum of 500 requests immediately, provided that the complexity cost of each request is 1.
 	RequestsPerHour float64 `json:"requestsPerHour"`
 }
@@ -335,6 +386,8 @@ type BitbucketServerPluginWebhooks struct {
 type BitbucketServerRateLimit struct {
 	// Enabled description: true if rate limiting is enabled.
 	Enabled bool `json:"enabled"`
+	// MaxConcurrentRequests description: Maximum number of requests to Bitbucket Server that are in flight at the same time, shared by all clients of the code host. Additional requests wait until a running request completes. Only applies if rate limiting is enabled. If not set, the number of concurrent requests is unlimited.
+	MaxConcurrentRequests int `json:"maxConcurrentRequests,omitempty"`
 	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second. Internally, the burst limit is set to 500, which implies that for a requests per hour limit as low as 1, users will continue to be able to send a maximum of 500 requests immediately, provided that the complexity cost of each request is 1.
 	RequestsPerHour float64 `json:"requestsPerHour"`
 }
@@ -384,11 +437,15 @@ type Branding struct {
 
 // BuiltinAuthProvider description: Configures the builtin username-password authentication provider.
 type BuiltinAuthProvider struct {
+	// AllowSignInLinks description: Allows users to sign in without a password by requesting a one-time sign-in link sent to their verified email address. Sign-in links expire after 15 minutes and can only be used once. Requires email sending to be configured (`email.smtp`).
+	AllowSignInLinks bool `json:"allowSignInLinks,omitempty"`
 	// AllowSignup description: Allows new visitors to sign up for accounts. The sign-up page will be enabled and accessible to all visitors.
 	//
 	// SECURITY: If the site has no users (i.e., during initial setup), it will always allow the first user to sign up and become site admin **without any approval** (first user to sign up becomes the admin).
-	AllowSignup bool   `json:"allowSignup,omitempty"`
-	Type        string `json:"type"`
+	AllowSignup bool `json:"allowSignup,omitempty"`
+	// SecurityKeys description: Configures security keys (WebAuthn) as a second factor when signing in with a password or sign-in link. Users can always register security keys, and must then use one of them to sign in.
+	SecurityKeys *SecurityKeys `json:"securityKeys,omitempty"`
+	Type         string        `json:"type"`
 }
 
 // ChangesetTemplate description: A template describing how to create (and update) changesets with the file changes produced by the command steps.
@@ -492,6 +549,7 @@ type EncryptionKeys struct {
 	EnableCache            bool           `json:"enableCache,omitempty"`
 	ExternalServiceKey     *EncryptionKey `json:"externalServiceKey,omitempty"`
 	UserExternalAccountKey *EncryptionKey `json:"userExternalAccountKey,omitempty"`
+	UserSecurityKeyKey     *EncryptionKey `json:"userSecurityKeyKey,omitempty"`
 }
 type ExcludedAWSCodeCommitRepo struct {
 	// Id description: The ID of an AWS Code Commit repository (as returned by the AWS API) to exclude from mirroring. Use this to exclude the repository, even if renamed, or to differentiate between repositories with the same name in multiple regions.
@@ -499,6 +557,12 @@ type ExcludedAWSCodeCommitRepo struct {
 	// Name description: The name of an AWS CodeCommit repository ("repo-name") to exclude from mirroring.
 	Name string `json:"name,omitempty"`
 }
+type ExcludedAzureDevOpsRepo struct {
+	// Name description: The name of an Azure DevOps repository ("org/project/repo") to exclude from mirroring.
+	Name string `json:"name,omitempty"`
+	// Pattern description: Regular expression which matches against the name of an Azure DevOps repository ("org/project/repo").
+	Pattern string `json:"pattern,omitempty"`
+}
 type ExcludedBitbucketCloudRepo struct {
 	// Name description: The name of a Bitbucket Cloud repo ("myorg/myrepo") to exclude from mirroring.
 	Name string `json:"name,omitempty"`
@@ -533,6 +597,14 @@ type ExcludedGitLabProject struct {
 	// Name description: The name of a GitLab project ("group/name") to exclude from mirroring.
 	Name string `json:"name,omitempty"`
 }
+type ExcludedGiteaRepo struct {
+	// Id description: The ID of a Gitea repository (as returned by the Gitea instance's API) to exclude from mirroring.
+	Id int `json:"id,omitempty"`
+	// Name description: The name of a Gitea repository ("owner/name") to exclude from mirroring.
+	Name string `json:"name,omitempty"`
+	// Pattern description: Regular expression which matches against the name of a Gitea repository ("owner/name").
+	Pattern string `json:"pattern,omitempty"`
+}
 type ExcludedGitoliteRepo struct {
 	// Name description: The name of a Gitolite repo ("my-repo") to exclude from mirroring.
 	Name string `json:"name,omitempty"`
@@ -651,6 +723,16 @@ type GitCommitDescription struct {
 	Message string `json:"message"`
 }
 
+// GitHubApp description: A GitHub App installation to authenticate as, instead of using a personal access token. Installation access tokens are created and refreshed automatically, and GitHub grants installations higher API rate limits than personal access tokens. See https://docs.sourcegraph.com/admin/external_service/github#github-app for how to create and install a GitHub App.
+type GitHubApp struct {
+	// AppID description: The ID of the GitHub App, as shown on the settings page of the app.
+	AppID int `json:"appID"`
+	// InstallationID description: The ID of the installation of the GitHub App in the organization or user account whose repositories are synced. It is the last path component of the installation's settings page URL.
+	InstallationID int `json:"installationID"`
+	// PrivateKey description: A PEM encoded private key of the GitHub App, used to sign requests for installation access tokens.
+	PrivateKey string `json:"privateKey"`
+}
+
 // GitHubAuthProvider description: Configures the GitHub (or GitHub Enterprise) OAuth authentication provider for SSO. In addition to specifying this configuration object, you must also create a OAuth App on your GitHub instance: https://developer.github.com/apps/building-oauth-apps/creating-an-oauth-app/. When a user signs into Sourcegraph or links their GitHub account to their existing Sourcegraph account, GitHub will prompt the user for the repo scope.
 type GitHubAuthProvider struct {
 	// AllowGroupsPermissionsSync description: Experimental: Allows sync of GitHub teams and organizations permissions across all external services associated with this provider to allow enabling of [repository permissions caching](https://docs.sourcegraph.com/admin/repo/permissions#permissions-caching).
@@ -691,6 +773,8 @@ type GitHubConnection struct {
 	//
 	// Note: ID is the GitHub GraphQL ID, not the GitHub database ID. eg: "curl https://api.github.com/repos/vuejs/vue | jq .node_id"
 	Exclude []*ExcludedGitHubRepo `json:"exclude,omitempty"`
+	// GitHubApp description: A GitHub App installation to authenticate as, instead of using a personal access token. Installation access tokens are created and refreshed automatically, and GitHub grants installations higher API rate limits than personal access tokens. See https://docs.sourcegraph.com/admin/external_service/github#github-app for how to create and install a GitHub App.
+	GitHubApp *GitHubApp `json:"gitHubApp,omitempty"`
 	// GitURLType description: The type of Git URLs to use for cloning and fetching Git repositories on this GitHub instance.
 	//
 	// If "http", Sourcegraph will access GitHub repositories using Git URLs of the form http(s)://github.com/myteam/myproject.git (using https: if the GitHub instance uses HTTPS).
@@ -729,7 +813,7 @@ type GitHubConnection struct {
 	// If you need to narrow the set of mirrored repositories further (and don't want to enumerate it with a list or query set as above), create a new bot/machine user on GitHub or GitHub Enterprise that is only affiliated with the desired repositories.
 	RepositoryQuery []string `json:"repositoryQuery,omitempty"`
 	// Token description: A GitHub personal access token. Create one for GitHub.com at https://github.com/settings/tokens/new?description=Sourcegraph (for GitHub Enterprise, replace github.com with your instance's hostname). See https://docs.sourcegraph.com/admin/external_service/github#github-api-token-and-access for which scopes are required for which use cases.
-	Token string `json:"token"`
+	Token string `json:"token,omitempty"`
 	// Url description: URL of a GitHub instance, such as https://github.com or https://github-enterprise.example.com.
 	Url string `json:"url"`
 	// Webhooks description: An array of configurations defining existing GitHub webhooks that send updates back to Sourcegraph.
@@ -740,6 +824,8 @@ type GitHubConnection struct {
 type GitHubRateLimit struct {
 	// Enabled description: true if rate limiting is enabled.
 	Enabled bool `json:"enabled"`
+	// MaxConcurrentRequests description: Maximum number of requests to GitHub that are in flight at the same time, shared by all clients of the code host. Additional requests wait until a running request completes. Only applies if rate limiting is enabled. If not set, the number of concurrent requests is unlimited.
+	MaxConcurrentRequests int `json:"maxConcurrentRequests,omitempty"`
 	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second. Internally, the burst limit is set to 100, which implies that for a requests per hour limit as low as 1, users will continue to be able to send a maximum of 100 requests immediately, provided that the complexity cost of each request is 1.
 	RequestsPerHour float64 `json:"requestsPerHour"`
 }
@@ -832,6 +918,8 @@ type GitLabProject struct {
 type GitLabRateLimit struct {
 	// Enabled description: true if rate limiting is enabled.
 	Enabled bool `json:"enabled"`
+	// MaxConcurrentRequests description: Maximum number of requests to GitLab that are in flight at the same time, shared by all clients of the code host. Additional requests wait until a running request completes. Only applies if rate limiting is enabled. If not set, the number of concurrent requests is unlimited.
+	MaxConcurrentRequests int `json:"maxConcurrentRequests,omitempty"`
 	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second. Internally the burst limit is set to 100, which implies that for a requests per hour limit as low as 1, users will continue to be able to send a maximum of 100 requests immediately, provided that the complexity cost of each request is 1.
 	RequestsPerHour float64 `json:"requestsPerHour"`
 }
@@ -840,6 +928,59 @@ type GitLabWebhook struct {
 	Secret string `json:"secret"`
 }
 
+// GiteaAuthorization description: If non-null, enforces Gitea repository permissions. This requires that the "token" belongs to a Gitea site admin, which is used to list the repositories of each user (with the Gitea "Sudo" API header).
+type GiteaAuthorization struct {
+	// IdentityProvider description: The source of identity to use when computing permissions. This defines how to compute the Gitea identity to use for a given Sourcegraph user. When 'username' is used, Sourcegraph assumes usernames are identical in Sourcegraph and Gitea accounts and `auth.enableUsernameChanges` must be set to false for security reasons.
+	IdentityProvider GiteaIdentityProvider `json:"identityProvider"`
+}
+
+// GiteaConnection description: Configuration for a connection to Gitea.
+type GiteaConnection struct {
+	// Authorization description: If non-null, enforces Gitea repository permissions. This requires that the "token" belongs to a Gitea site admin, which is used to list the repositories of each user (with the Gitea "Sudo" API header).
+	Authorization *GiteaAuthorization `json:"authorization,omitempty"`
+	// Exclude description: A list of repositories to never mirror from this Gitea instance. Takes precedence over "orgs" and "repos".
+	//
+	// Supports excluding by name ({"name": "owner/name"}) or by ID ({"id": 42}).
+	Exclude []*ExcludedGiteaRepo `json:"exclude,omitempty"`
+	// GitURLType description: The type of Git URLs to use for cloning and fetching Git repositories on this Gitea instance.
+	//
+	// If "http", Sourcegraph will access Gitea repositories using Git URLs of the form http(s)://gitea.example.com/myorg/myrepo.git (using https: if the Gitea instance uses HTTPS).
+	//
+	// If "ssh", Sourcegraph will access Gitea repositories using Git URLs of the form git@gitea.example.com:myorg/myrepo.git. See the documentation for how to provide SSH private keys and known_hosts: https://docs.sourcegraph.com/admin/repo/auth#repositories-that-need-http-s-or-ssh-authentication.
+	GitURLType string `json:"gitURLType,omitempty"`
+	// Orgs description: An array of Gitea organization names whose repositories should be mirrored on Sourcegraph.
+	Orgs []string `json:"orgs,omitempty"`
+	// RateLimit description: Rate limit applied when making background API requests to Gitea.
+	RateLimit *GiteaRateLimit `json:"rateLimit,omitempty"`
+	// Repos description: An array of repository "owner/name" strings specifying which Gitea repositories to mirror on Sourcegraph.
+	Repos []string `json:"repos,omitempty"`
+	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for a Gitea repository. In the pattern, the variable "{host}" is replaced with the Gitea URL's host (such as gitea.example.com), and "{nameWithOwner}" is replaced with the Gitea repository's "owner/name" (such as "myorg/myrepo").
+	//
+	// For example, if your Gitea URL is https://gitea.example.com and your Sourcegraph URL is https://src.example.com, then a repositoryPathPattern of "{host}/{nameWithOwner}" would mean that a Gitea repository at https://gitea.example.com/myorg/myrepo is available on Sourcegraph at https://src.example.com/gitea.example.com/myorg/myrepo.
+	//
+	// It is important that the Sourcegraph repository name generated with this pattern be unique to this code host. If different code hosts generate repository names that collide, Sourcegraph's behavior is undefined.
+	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
+	// Token description: An access token for a Gitea user with read access to the repositories to mirror. To enforce Gitea repository permissions (see "authorization"), the user must be a Gitea site admin.
+	Token string `json:"token"`
+	// Url description: URL of a Gitea instance, such as https://gitea.example.com.
+	Url string `json:"url"`
+}
+
+// GiteaIdentityProvider description: The source of identity to use when computing permissions. This defines how to compute the Gitea identity to use for a given Sourcegraph user. When 'username' is used, Sourcegraph assumes usernames are identical in Sourcegraph and Gitea accounts and `auth.enableUsernameChanges` must be set to false for security reasons.
+type GiteaIdentityProvider struct {
+	Type string `json:"type"`
+}
+
+// GiteaRateLimit description: Rate limit applied when making background API requests to Gitea.
+type GiteaRateLimit struct {
+	// Enabled description: true if rate limiting is enabled.
+	Enabled bool `json:"enabled"`
+	// MaxConcurrentRequests description: Maximum number of requests to Gitea that are in flight at the same time, shared by all clients of the code host. Additional requests wait until a running request completes. Only applies if rate limiting is enabled. If not set, the number of concurrent requests is unlimited.
+	MaxConcurrentRequests int `json:"maxConcurrentRequests,omitempty"`
+	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second. Internally, the burst limit is set to 500, which implies that for a requests per hour limit as low as 1, users will continue to be able to send a maximum of 500 requests immediately, provided that the complexity cost of each request is 1.
+	RequestsPerHour float64 `json:"requestsPerHour"`
+}
+
 // GitoliteConnection description: Configuration for a connection to Gitolite.
 type GitoliteConnection struct {
 	// Exclude description: A list of repositories to never mirror from this Gitolite instance. Supports excluding by exact name ({"name": "foo"}).
@@ -953,10 +1094,20 @@ type JVMPackagesConnection struct {
 
 // Log description: Configuration for logging and alerting, including to external services.
 type Log struct {
+	// SecurityEventLogs description: Configures the audit log of security events, such as sign-ins, password changes and site admin role changes. Setting it enables the audit log, which site admins can query with the GraphQL API.
+	SecurityEventLogs *SecurityEventLogs `json:"securityEventLogs,omitempty"`
 	// Sentry description: Configuration for Sentry
 	Sentry *Sentry `json:"sentry,omitempty"`
 }
 
+// MaintenanceMode description: Puts the site in maintenance mode, e.g. while operators migrate data. In maintenance mode, the site is read-only: browsing and searching keep working, but changes (e.g. GraphQL mutations, sign-ups, settings updates) are rejected with an error and a banner with the message is shown on every page.
+type MaintenanceMode struct {
+	// Enabled description: Whether the site is in maintenance mode.
+	Enabled bool `json:"enabled"`
+	// Message description: The message shown to users while the site is in maintenance mode.
+	Message string `json:"message,omitempty"`
+}
+
 // Maven description: Configuration for resolving from Maven repositories.
 type Maven struct {
 	// Credentials description: Contents of a coursier.credentials file needed for accessing the Maven repositories.
@@ -1187,6 +1338,8 @@ type ParentSourcegraph struct {
 
 // PerforceAuthorization description: If non-null, enforces Perforce depot permissions.
 type PerforceAuthorization struct {
+	// SubRepoPermissions description: EXPERIMENTAL: Sync the path-level rules of the protections table within depots as sub-repo permissions. Sub-repo permissions only narrow down the access to depots users can read, they never grant access to a depot.
+	SubRepoPermissions bool `json:"subRepoPermissions,omitempty"`
 }
 
 // PerforceConnection description: Configuration for a connection to Perforce Server.
@@ -1268,6 +1421,12 @@ type Ranking struct {
 	// RepoScores description: a map of URI directories to numeric scores for specifying search result importance, like {"github.com": 500, "github.com/sourcegraph": 300, "github.com/sourcegraph/sourcegraph": 100}. Would rank "github.com/sourcegraph/sourcegraph" as 500+300+100=900, and "github.com/other/foo" as 500.
 	RepoScores map[string]float64 `json:"repoScores,omitempty"`
 }
+type RepoAlias struct {
+	// Alias description: The alias of the repository name (or repository name prefix), e.g. `src/foo`.
+	Alias string `json:"alias"`
+	// Name description: The canonical repository name (or repository name prefix) the alias redirects to, e.g. `github.com/org/foo`.
+	Name string `json:"name"`
+}
 type Repos struct {
 	// Callsign description: The unique Phabricator identifier for the repository, like 'MUX'.
 	Callsign string `json:"callsign"`
@@ -1359,6 +1518,30 @@ type SearchScope struct {
 	Value string `json:"value"`
 }
 
+// SecurityEventLogs description: Configures the audit log of security events, such as sign-ins, password changes and site admin role changes. Setting it enables the audit log, which site admins can query with the GraphQL API.
+type SecurityEventLogs struct {
+	// Export description: Exports security events as they are logged, in addition to keeping them in the audit log.
+	Export *SecurityEventLogsExport `json:"export,omitempty"`
+	// RetentionDays description: The number of days security events are kept.
+	RetentionDays int `json:"retentionDays,omitempty"`
+}
+
+// SecurityEventLogsExport description: Exports security events as they are logged, in addition to keeping them in the audit log.
+type SecurityEventLogsExport struct {
+	// Address description: For syslog, the address of the syslog server, as udp://host:port or tcp://host:port.
+	Address string `json:"address,omitempty"`
+	// Path description: For jsonLines, the path of the file the events are appended to.
+	Path string `json:"path,omitempty"`
+	// Type description: The export format. "syslog" sends RFC 5424 messages to a syslog server, "jsonLines" appends one JSON object per event to a file.
+	Type string `json:"type"`
+}
+
+// SecurityKeys description: Configures security keys (WebAuthn) as a second factor when signing in with a password or sign-in link. Users can always register security keys, and must then use one of them to sign in.
+type SecurityKeys struct {
+	// Enforce description: Which users must register a security key before they can sign in. Users who have no security key yet are asked to register one when they next sign in.
+	Enforce string `json:"enforce,omitempty"`
+}
+
 // Sentry description: Configuration for Sentry
 type Sentry struct {
 	// BackendDSN description: Sentry Data Source Name (DSN) for backend errors. Per the Sentry docs (https://docs.sentry.io/quickstart/#about-the-dsn), it should match the following pattern: '{PROTOCOL}://{PUBLIC_KEY}@{HOST}/{PATH}{PROJECT_ID}'.
@@ -1520,6 +1703,8 @@ type SiteConfiguration struct {
 	//   ```
 	//
 	AuthSessionExpiry string `json:"auth.sessionExpiry,omitempty"`
+	// AuthUserDeletionGracePeriodDays description: The number of days a deleted user is kept before the user and the resources it owns (external services, batch changes, access tokens, settings, etc.) are permanently deleted. Users deleted with hard: true are permanently deleted right away, regardless of this setting.
+	AuthUserDeletionGracePeriodDays int `json:"auth.userDeletionGracePeriodDays,omitempty"`
 	// AuthUserOrgMap description: Ensure that matching users are members of the specified orgs (auto-joining users to the orgs if they are not already a member). Provide a JSON object of the form `{"*": ["org1", "org2"]}`, where org1 and org2 are orgs that all users are automatically joined to. Currently the only supported key is `"*"`.
 	AuthUserOrgMap map[string][]string `json:"auth.userOrgMap,omitempty"`
 	// AuthzEnforceForSiteAdmins description: When true, site admins will only be able to see private code they have access to via our authz system.
@@ -1598,6 +1783,8 @@ type SiteConfiguration struct {
 	HtmlHeadTop string `json:"htmlHeadTop,omitempty"`
 	// InsightsCommitIndexerInterval description: The interval (in minutes) at which the insights commit indexer will check for new commits.
 	InsightsCommitIndexerInterval int `json:"insights.commit.indexer.interval,omitempty"`
+	// InsightsHistoricalBudget description: Maximum number of historical Code Insights search queries that are enqueued per run of the historical backfiller (every 15 minutes). Series are backfilled oldest first, and the remaining work is picked up in the next run. Use this to limit the load on gitserver and searcher on large instances. Unlimited if not set or 0.
+	InsightsHistoricalBudget int `json:"insights.historical.budget,omitempty"`
 	// InsightsHistoricalFrameLength description: (debug) duration of historical insights timeframes, one point per repository will be recorded in each timeframe.
 	InsightsHistoricalFrameLength string `json:"insights.historical.frameLength,omitempty"`
 	// InsightsHistoricalFrames description: (debug) number of historical insights timeframes to populate
@@ -1606,16 +1793,24 @@ type SiteConfiguration struct {
 	InsightsHistoricalSpeedFactor *float64 `json:"insights.historical.speedFactor,omitempty"`
 	// InsightsHistoricalWorkerRateLimit description: Maximum number of historical Code Insights data frames that may be analyzed per second.
 	InsightsHistoricalWorkerRateLimit *float64 `json:"insights.historical.worker.rateLimit,omitempty"`
+	// InsightsJustInTimeMaxRepositories description: Maximum number of repositories in the repository scope of a Code Insights search series for its data to be computed when the insight is viewed, instead of waiting for the series to be recorded in the background. Computing a series just in time runs one search per repository and data point. Disabled if set to 0.
+	InsightsJustInTimeMaxRepositories *int `json:"insights.justInTime.maxRepositories,omitempty"`
 	// InsightsQueryWorkerConcurrency description: Number of concurrent executions of a code insight query on a worker node
 	InsightsQueryWorkerConcurrency int `json:"insights.query.worker.concurrency,omitempty"`
 	// InsightsQueryWorkerRateLimit description: Maximum number of Code Insights queries initiated per second on a worker node.
 	InsightsQueryWorkerRateLimit *float64 `json:"insights.query.worker.rateLimit,omitempty"`
+	// InsightsRetentionDailyDays description: Number of days daily Code Insights series points are kept. Older daily points are downsampled to one point per week. Should be greater than insights.retention.rawDays. Daily points are kept forever if not set or 0.
+	InsightsRetentionDailyDays int `json:"insights.retention.dailyDays,omitempty"`
+	// InsightsRetentionRawDays description: Number of days raw Code Insights series points are kept. Older points are downsampled to one point per day. Raw points are kept forever if not set or 0.
+	InsightsRetentionRawDays int `json:"insights.retention.rawDays,omitempty"`
 	// LicenseKey description: The license key associated with a Sourcegraph product subscription, which is necessary to activate Sourcegraph Enterprise functionality. To obtain this value, contact Sourcegraph to purchase a subscription. To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh.
 	LicenseKey string `json:"licenseKey,omitempty"`
 	// Log description: Configuration for logging and alerting, including to external services.
 	Log *Log `json:"log,omitempty"`
 	// LsifEnforceAuth description: Whether or not LSIF uploads will be blocked unless a valid LSIF upload token is provided.
 	LsifEnforceAuth bool `json:"lsifEnforceAuth,omitempty"`
+	// MaintenanceMode description: Puts the site in maintenance mode, e.g. while operators migrate data. In maintenance mode, the site is read-only: browsing and searching keep working, but changes (e.g. GraphQL mutations, sign-ups, settings updates) are rejected with an error and a banner with the message is shown on every page.
+	MaintenanceMode *MaintenanceMode `json:"maintenanceMode,omitempty"`
 	// MaxReposToSearch description: DEPRECATED: Configure maxRepos in search.limits. The maximum number of repositories to search across. The user is prompted to narrow their query if exceeded. Any value less than or equal to zero means unlimited.
 	MaxReposToSearch int `json:"maxReposToSearch,omitempty"`
 	// ObservabilityAlerts description: Configure notifications for Sourcegraph's built-in alerts.
@@ -1634,10 +1829,16 @@ type SiteConfiguration struct {
 	PermissionsUserMapping *PermissionsUserMapping `json:"permissions.userMapping,omitempty"`
 	// ProductResearchPageEnabled description: Enables users access to the product research page in their settings.
 	ProductResearchPageEnabled *bool `json:"productResearchPage.enabled,omitempty"`
+	// RepoAliases description: Aliases of repository names, e.g. to expose short internal names for repositories without renaming them on their code host. Visiting a repository through an alias redirects to its canonical name. An alias also matches the repositories below it, e.g. the alias `src` of `github.com/org` redirects `src/foo` to `github.com/org/foo`.
+	RepoAliases []*RepoAlias `json:"repoAliases,omitempty"`
 	// RepoConcurrentExternalServiceSyncers description: The number of concurrent external service syncers that can run.
 	RepoConcurrentExternalServiceSyncers int `json:"repoConcurrentExternalServiceSyncers,omitempty"`
+	// RepoDeletionThresholdPercent description: The maximum percentage of the repositories of a site-level external service that a single sync may delete. Syncs that would delete more repositories fail without deleting any until a site admin confirms the deletion. This protects against losing repositories to a token with too few permissions. 0 disables the protection.
+	RepoDeletionThresholdPercent int `json:"repoDeletionThresholdPercent,omitempty"`
 	// RepoListUpdateInterval description: Interval (in minutes) for checking code hosts (such as GitHub, Gitolite, etc.) for new repositories.
 	RepoListUpdateInterval int `json:"repoListUpdateInterval,omitempty"`
+	// ScimAuthToken description: The bearer token that identity providers must use to provision users and groups through the SCIM 2.0 API at /.api/scim/v2. SCIM provisioning is disabled if unset.
+	ScimAuthToken string `json:"scim.authToken,omitempty"`
 	// SearchIndexEnabled description: Whether indexed search is enabled. If unset Sourcegraph detects the environment to decide if indexed search is enabled. Indexed search is RAM heavy, and is disabled by default in the single docker image. All other environments will have it enabled by default. The size of all your repository working copies is the amount of additional RAM required.
 	SearchIndexEnabled *bool `json:"search.index.enabled,omitempty"`
 	// SearchIndexSymbolsEnabled description: Whether indexed symbol search is enabled. This is contingent on the indexed search configuration, and is true by default for instances with indexed search enabled. Enabling this will cause every repository to re-index, which is a time consuming (several hours) operation. Additionally, it requires more storage and ram to accommodate the added symbols information in the search index.
@@ -1646,6 +1847,12 @@ type SiteConfiguration struct {
 	SearchLargeFiles []string `json:"search.largeFiles,omitempty"`
 	// SearchLimits description: Limits that search applies for number of repositories searched and timeouts.
 	SearchLimits *SearchLimits `json:"search.limits,omitempty"`
+	// UiCacheControl description: Configures the HTTP caching (Cache-Control headers) of the pages and static assets of the web app. By default, pages are not cached.
+	UiCacheControl *UiCacheControl `json:"ui.cacheControl,omitempty"`
+	// UiErrorPages description: Customizes the help shown on error pages of the web app, e.g. to point users to your internal support channels.
+	UiErrorPages *UiErrorPages `json:"ui.errorPages,omitempty"`
+	// UiRatelimit description: Configuration for rate limiting anonymous requests to expensive pages and endpoints of the web app (file pages, raw files and archives, and search badges). Requests of signed-in users are never limited.
+	UiRatelimit *UiRatelimit `json:"ui.ratelimit,omitempty"`
 	// UpdateChannel description: The channel on which to automatically check for Sourcegraph updates.
 	UpdateChannel string `json:"update.channel,omitempty"`
 	// UseJaeger description: DEPRECATED. Use `"observability.tracing": { "sampling": "all" }`, instead. Enables Jaeger tracing.
@@ -1694,6 +1901,40 @@ type TransformChangesGroup struct {
 	// Repository description: Only apply this transformation in the repository with this name (as it is known to Sourcegraph).
 	Repository string `json:"repository,omitempty"`
 }
+
+// UiCacheControl description: Configures the HTTP caching (Cache-Control headers) of the pages and static assets of the web app. By default, pages are not cached.
+type UiCacheControl struct {
+	// AssetsMaxAge description: The number of seconds browsers and CDNs may cache the (immutable, versioned) static assets of the web app. Defaults to 2 days.
+	AssetsMaxAge int `json:"assetsMaxAge,omitempty"`
+	// AuthenticatedPagesNoStore description: Prevents browsers from storing pages served to signed-in users (`Cache-Control: no-store`), e.g. on shared computers.
+	AuthenticatedPagesNoStore bool `json:"authenticatedPagesNoStore,omitempty"`
+	// RepoPagesMaxAge description: The number of seconds browsers of anonymous users may cache repository, tree, blob and raw file pages. Pages served to signed-in users are never cached. If 0, these pages are not cached.
+	RepoPagesMaxAge int `json:"repoPagesMaxAge,omitempty"`
+}
+
+// UiErrorPages description: Customizes the help shown on error pages of the web app, e.g. to point users to your internal support channels.
+type UiErrorPages struct {
+	// CloneInProgress description: Troubleshooting text shown while a repository is being cloned.
+	CloneInProgress string `json:"cloneInProgress,omitempty"`
+	// NotFound description: Troubleshooting text shown when a page or repository is not found (404 Not Found), e.g. how to request that a repository is added.
+	NotFound string `json:"notFound,omitempty"`
+	// SupportURL description: The URL of the support link shown on error pages (e.g. a chat channel, issue tracker or `mailto:` link). Defaults to contacting Sourcegraph support.
+	SupportURL string `json:"supportURL,omitempty"`
+	// Unauthorized description: Troubleshooting text shown when the user is not authorized to view a page (401 Unauthorized), e.g. how to request access to a repository.
+	Unauthorized string `json:"unauthorized,omitempty"`
+}
+
+// UiRatelimit description: Configuration for rate limiting anonymous requests to expensive pages and endpoints of the web app (file pages, raw files and archives, and search badges). Requests of signed-in users are never limited.
+type UiRatelimit struct {
+	// Enabled description: Whether rate limiting of anonymous requests to the web app is enabled
+	Enabled bool `json:"enabled"`
+	// PerAnonymousUser description: Limit granted per anonymous user (identified by their anonymous user cookie) per hour, applied in addition to the limit per IP
+	PerAnonymousUser int `json:"perAnonymousUser"`
+	// PerIP description: Limit granted per IP per hour, applied to all anonymous requests
+	PerIP int `json:"perIP"`
+	// TrustedProxies description: The number of proxies in front of Sourcegraph that append the address of their client to the X-Forwarded-For header. The IP of a client is the address appended by the outermost trusted proxy, counting from the right.
+	TrustedProxies int `json:"trustedProxies,omitempty"`
+}
 type UpdateIntervalRule struct {
 	// Interval description: An integer representing the number of minutes to wait until the next update
 	Interval int `json:"interval"`
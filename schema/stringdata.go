@@ -6,6 +6,10 @@ import _ "embed"
 //go:embed aws_codecommit.schema.json
 var AWSCodeCommitSchemaJSON string
 
+// AzureDevOpsSchemaJSON is the content of the file "azuredevops.schema.json".
+//go:embed azuredevops.schema.json
+var AzureDevOpsSchemaJSON string
+
 // BatchSpecSchemaJSON is the content of the file "batch_spec.schema.json".
 //go:embed batch_spec.schema.json
 var BatchSpecSchemaJSON string
@@ -30,6 +34,10 @@ var GitHubSchemaJSON string
 //go:embed gitlab.schema.json
 var GitLabSchemaJSON string
 
+// GiteaSchemaJSON is the content of the file "gitea.schema.json".
+//go:embed gitea.schema.json
+var GiteaSchemaJSON string
+
 // GitoliteSchemaJSON is the content of the file "gitolite.schema.json".
 //go:embed gitolite.schema.json
 var GitoliteSchemaJSON string